@@ -19,6 +19,11 @@ const (
 	ExternTypeTable  ExternType = 0x01
 	ExternTypeMemory ExternType = 0x02
 	ExternTypeGlobal ExternType = 0x03
+
+	// ExternTypeTag is an exception tag, which requires the exception handling proposal.
+	//
+	// See https://github.com/WebAssembly/exception-handling/blob/main/proposals/exception-handling/Exceptions.md
+	ExternTypeTag ExternType = 0x04
 )
 
 // The below are exported to consolidate parsing behavior for external types.
@@ -31,6 +36,8 @@ const (
 	ExternTypeMemoryName = "memory"
 	// ExternTypeGlobalName is the name of the WebAssembly 1.0 (20191205) Text Format field for ExternTypeGlobal.
 	ExternTypeGlobalName = "global"
+	// ExternTypeTagName is the name of the exception handling proposal Text Format field for ExternTypeTag.
+	ExternTypeTagName = "tag"
 )
 
 // ExternTypeName returns the name of the WebAssembly 1.0 (20191205) Text Format field of the given type.
@@ -47,6 +54,8 @@ func ExternTypeName(et ExternType) string {
 		return ExternTypeMemoryName
 	case ExternTypeGlobal:
 		return ExternTypeGlobalName
+	case ExternTypeTag:
+		return ExternTypeTagName
 	}
 	return fmt.Sprintf("%#x", et)
 }
@@ -94,8 +103,17 @@ const (
 	//    "f": func(externref uintptr) (resultExternRef uintptr) { return },
 	//  })
 	//
+	// Note: Host functions can't use pointer types instead, as the Go garbage collector doesn't know about references
+	// kept by Wasm. The host is responsible for keeping the referenced objects alive, ex. in a map.
+	//
 	// Note: The usage of this type is toggled with WithFeatureBulkMemoryOperations.
 	ValueTypeExternref ValueType = 0x6f
+	// ValueTypeFuncref is a funcref type, which is a reference to a function.
+	//
+	// Note: in wazero, funcref values are opaque, except zero which is a null reference (ref.null func). Host functions
+	// defined in Go can accept and return funcref values as Function, where nil is a null reference.
+	// Note: The usage of this type is toggled with WithFeatureBulkMemoryOperations.
+	ValueTypeFuncref ValueType = 0x70
 )
 
 // ValueTypeName returns the type name of the given ValueType as a string.
@@ -114,6 +132,8 @@ func ValueTypeName(t ValueType) string {
 		return "f64"
 	case ValueTypeExternref:
 		return "externref"
+	case ValueTypeFuncref:
+		return "funcref"
 	}
 	return "unknown"
 }
@@ -135,7 +155,8 @@ type Module interface {
 	// ExportedFunction returns a function exported from this module or nil if it wasn't.
 	ExportedFunction(name string) Function
 
-	// TODO: Table
+	// ExportedTable returns a table exported from this module or nil if it wasn't.
+	ExportedTable(name string) Table
 
 	// ExportedMemory returns a memory exported from this module or nil if it wasn't.
 	//
@@ -147,6 +168,9 @@ type Module interface {
 	// ExportedGlobal a global exported from this module or nil if it wasn't.
 	ExportedGlobal(name string) Global
 
+	// ExportedTag returns an exception tag exported from this module or nil if it wasn't.
+	ExportedTag(name string) Tag
+
 	// CloseWithExitCode releases resources allocated for this Module. Use a non-zero exitCode parameter to indicate a
 	// failure to ExportedFunction callers.
 	//
@@ -197,6 +221,23 @@ type Function interface {
 	Call(ctx context.Context, params ...uint64) ([]uint64, error)
 }
 
+// GoModuleFunc is a host function implemented in Go, which reads its parameters from and writes its results to the
+// stack, encoded according to ValueType. Unlike functions exported via reflection, this is called without reflection
+// or allocation, so it is the fastest way to implement a host function.
+//
+// The stack is at least as long as the larger of the parameter and result count in uint64 values (ValueTypeV128 takes
+// two). Parameters begin at stack[0], and results must be written from stack[0], overwriting the parameters.
+//
+// Ex. This adds two i32 parameters and returns one i32 result:
+//
+//	add := func(ctx context.Context, m api.Module, stack []uint64) {
+//		x, y := uint32(stack[0]), uint32(stack[1])
+//		stack[0] = uint64(x + y)
+//	}
+//
+// Note: The stack is only valid during the call. Do not retain it.
+type GoModuleFunc func(ctx context.Context, mod Module, stack []uint64)
+
 // Global is a WebAssembly 1.0 (20191205) global exported from an instantiated module (wazero.Runtime InstantiateModule).
 //
 // Ex. If the value is not mutable, you can read it once:
@@ -238,6 +279,88 @@ type MutableGlobal interface {
 	Set(ctx context.Context, v uint64)
 }
 
+// Tag identifies a kind of WebAssembly exception, and declares the types of the values it carries.
+//
+// Tags are compared by identity: a catch clause only handles exceptions thrown with the very same Tag, even if another
+// one has the same ParamTypes. Use Module.ExportedTag to throw or recognize exceptions of a module from a host function.
+//
+// Note: This is an interface for decoupling, not third-party implementations. All implementations are in wazero.
+// See https://github.com/WebAssembly/exception-handling/blob/main/proposals/exception-handling/Exceptions.md
+type Tag interface {
+	// ParamTypes are the possibly empty sequence of value types of the values carried by exceptions of this tag.
+	// See ValueType documentation for encoding rules.
+	ParamTypes() []ValueType
+}
+
+// Exception is a WebAssembly exception, raised by the "throw" instruction.
+//
+// A host function throws an Exception to the calling WebAssembly code by panicking with it. Ex.
+//
+//	panic(&api.Exception{Tag: m.ExportedTag("error"), Values: []uint64{code}})
+//
+// An Exception not caught by any WebAssembly code escapes Function.Call as an error, which can be inspected with
+// errors.As.
+type Exception struct {
+	// Tag identifies which catch clauses handle this exception.
+	Tag Tag
+
+	// Values are the values carried by this exception, encoded according to Tag.ParamTypes.
+	Values []uint64
+}
+
+// Error implements error.
+func (e *Exception) Error() string {
+	return fmt.Sprintf("uncaught exception with values %v", e.Values)
+}
+
+// Table is a WebAssembly table of references, exported from an instantiated module (wazero.Runtime InstantiateModule).
+//
+// Ex. To register a callback the guest can call indirectly, such as via a function pointer in C:
+//
+//	table := module.ExportedTable("__indirect_function_table")
+//	if index, ok := table.Grow(ctx, 1); ok && table.SetFunction(ctx, index, callback) {
+//		// pass the index to the guest
+//	}
+//
+// Note: All functions accept a context.Context, which when nil, default to context.Background.
+// Note: This is an interface for decoupling, not third-party implementations. All implementations are in wazero.
+// See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#table-instances%E2%91%A0
+type Table interface {
+	// Type is the type of references in this table: ValueTypeFuncref or ValueTypeExternref.
+	Type() ValueType
+
+	// Size returns the count of elements in this table.
+	Size(context.Context) uint32
+
+	// Grow increases the table by the delta in elements, which are null references. The return val is the previous
+	// size in elements, or false if the delta was ignored as it exceeds the max elements of the table.
+	//
+	// Note: This is the same as the "table.grow" instruction with a null reference, except returns false instead of -1
+	// on failure.
+	Grow(ctx context.Context, delta uint32) (previousSize uint32, ok bool)
+
+	// Function returns the function referenced by the element at the index, or nil if it is a null reference. This
+	// returns false if the index is out of range or the Type isn't ValueTypeFuncref.
+	Function(ctx context.Context, index uint32) (Function, bool)
+
+	// SetFunction sets the element at the index to reference the function, or to a null reference if nil. This returns
+	// false if the index is out of range or the Type isn't ValueTypeFuncref.
+	//
+	// Note: The function must have been returned by a Module of the same wazero.Runtime, or by Function. Otherwise,
+	// this returns false.
+	SetFunction(ctx context.Context, index uint32, fn Function) bool
+
+	// Externref returns the element at the index, or zero if it is a null reference. This returns false if the index is
+	// out of range or the Type isn't ValueTypeExternref.
+	// See ValueTypeExternref for how to decode this value to a Go type.
+	Externref(ctx context.Context, index uint32) (uintptr, bool)
+
+	// SetExternref sets the element at the index, or to a null reference if zero. This returns false if the index is
+	// out of range or the Type isn't ValueTypeExternref.
+	// See ValueTypeExternref for how to encode this value from a Go type.
+	SetExternref(ctx context.Context, index uint32, ref uintptr) bool
+}
+
 // Memory allows restricted access to a module's memory. Notably, this does not allow growing.
 //
 // Note: All functions accept a context.Context, which when nil, default to context.Background.
@@ -333,6 +456,159 @@ type Memory interface {
 
 	// Write writes the slice to the underlying buffer at the offset or returns false if out of range.
 	Write(ctx context.Context, offset uint32, v []byte) bool
+
+	// Read64 is like Read, except the offset and byteCount are 64-bit, such as pointers into a memory indexed with i64
+	// (memory64). This returns false when the range is outside the memory, including when it overflows a 64-bit integer.
+	//
+	// See https://github.com/WebAssembly/memory64/blob/main/proposals/memory64/Overview.md
+	Read64(ctx context.Context, offset, byteCount uint64) ([]byte, bool)
+
+	// Write64 is like Write, except the offset is 64-bit, such as a pointer into a memory indexed with i64 (memory64).
+	//
+	// See https://github.com/WebAssembly/memory64/blob/main/proposals/memory64/Overview.md
+	Write64(ctx context.Context, offset uint64, v []byte) bool
+
+	// ReadByte64 is like ReadByte, except the offset is 64-bit. See Read64
+	ReadByte64(ctx context.Context, offset uint64) (byte, bool)
+
+	// ReadUint16Le64 is like ReadUint16Le, except the offset is 64-bit. See Read64
+	ReadUint16Le64(ctx context.Context, offset uint64) (uint16, bool)
+
+	// ReadUint32Le64 is like ReadUint32Le, except the offset is 64-bit. See Read64
+	ReadUint32Le64(ctx context.Context, offset uint64) (uint32, bool)
+
+	// ReadFloat32Le64 is like ReadFloat32Le, except the offset is 64-bit. See Read64
+	ReadFloat32Le64(ctx context.Context, offset uint64) (float32, bool)
+
+	// ReadUint64Le64 is like ReadUint64Le, except the offset is 64-bit. See Read64
+	ReadUint64Le64(ctx context.Context, offset uint64) (uint64, bool)
+
+	// ReadFloat64Le64 is like ReadFloat64Le, except the offset is 64-bit. See Read64
+	ReadFloat64Le64(ctx context.Context, offset uint64) (float64, bool)
+
+	// WriteByte64 is like WriteByte, except the offset is 64-bit. See Write64
+	WriteByte64(ctx context.Context, offset uint64, v byte) bool
+
+	// WriteUint16Le64 is like WriteUint16Le, except the offset is 64-bit. See Write64
+	WriteUint16Le64(ctx context.Context, offset uint64, v uint16) bool
+
+	// WriteUint32Le64 is like WriteUint32Le, except the offset is 64-bit. See Write64
+	WriteUint32Le64(ctx context.Context, offset uint64, v uint32) bool
+
+	// WriteFloat32Le64 is like WriteFloat32Le, except the offset is 64-bit. See Write64
+	WriteFloat32Le64(ctx context.Context, offset uint64, v float32) bool
+
+	// WriteUint64Le64 is like WriteUint64Le, except the offset is 64-bit. See Write64
+	WriteUint64Le64(ctx context.Context, offset uint64, v uint64) bool
+
+	// WriteFloat64Le64 is like WriteFloat64Le, except the offset is 64-bit. See Write64
+	WriteFloat64Le64(ctx context.Context, offset uint64, v float64) bool
+}
+
+// ImportDefinition describes an import of a module compiled by wazero.Runtime CompileModule, which must be satisfied
+// by an export of another module to instantiate it.
+//
+// Ex. To find which host modules a compiled module needs, before instantiating it:
+//
+//	for _, imp := range compiled.Imports() {
+//		if imp.Type == api.ExternTypeFunc {
+//			fmt.Println(imp.Module, imp.Name, imp.Func.ParamTypes, imp.Func.ResultTypes)
+//		}
+//	}
+type ImportDefinition struct {
+	// Module is the name of the module which exports the import. Ex. "wasi_snapshot_preview1"
+	Module string
+
+	// Name is the name of the export in Module. Ex. "fd_write"
+	Name string
+
+	ExternDefinition
+}
+
+// ExportDefinition describes an export of a module compiled by wazero.Runtime CompileModule.
+type ExportDefinition struct {
+	// Name is the name of the export, unique in the module. Ex. "_start"
+	Name string
+
+	ExternDefinition
+}
+
+// ExternDefinition is the type of an import or an export. Only the field corresponding to Type is set.
+//
+// See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#external-types%E2%91%A0
+type ExternDefinition struct {
+	// Type classifies the import or export, and determines which of the below fields is non-nil.
+	Type ExternType
+
+	// Func is the signature of a function, when Type is ExternTypeFunc.
+	Func *FunctionType
+
+	// Table is the type of table, when Type is ExternTypeTable.
+	Table *TableType
+
+	// Memory is the type of memory, when Type is ExternTypeMemory.
+	Memory *MemoryType
+
+	// Global is the type of global, when Type is ExternTypeGlobal.
+	Global *GlobalType
+
+	// Tag is the signature of an exception tag, when Type is ExternTypeTag. It never has results.
+	Tag *FunctionType
+}
+
+// FunctionType is the signature of a function, or of an exception tag.
+//
+// See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#function-types%E2%91%A0
+type FunctionType struct {
+	// ParamTypes are the possibly empty sequence of value types accepted by a function with this signature.
+	ParamTypes []ValueType
+
+	// ResultTypes are the possibly empty sequence of value types returned by a function with this signature.
+	ResultTypes []ValueType
+}
+
+// TableType is the type of table, in count of elements.
+//
+// See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#table-types%E2%91%A0
+type TableType struct {
+	// ElementType is the type of references in the table: ValueTypeFuncref or ValueTypeExternref.
+	ElementType ValueType
+
+	// Min is the initial count of elements.
+	Min uint32
+
+	// Max is the maximum count of elements, or nil when unbounded.
+	Max *uint32
+}
+
+// MemoryType is the type of memory, in pages (65536 bytes per page).
+//
+// See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#memory-types%E2%91%A0
+type MemoryType struct {
+	// Min is the initial count of pages.
+	Min uint32
+
+	// Max is the maximum count of pages, or nil when not defined in the module.
+	Max *uint32
+
+	// Shared is true if the memory is shared between threads.
+	Shared bool
+
+	// Memory64 is true if the memory is indexed with ValueTypeI64.
+	//
+	// See https://github.com/WebAssembly/memory64/blob/main/proposals/memory64/Overview.md
+	Memory64 bool
+}
+
+// GlobalType is the type of global.
+//
+// See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#global-types%E2%91%A0
+type GlobalType struct {
+	// ValueType is the type of the value of the global.
+	ValueType ValueType
+
+	// Mutable is true if the global is a MutableGlobal once instantiated.
+	Mutable bool
 }
 
 // EncodeExternref encodes the input as a ValueTypeExternref.
@@ -48,7 +48,8 @@ type ModuleBuilder interface {
 	// * goFunc - the `func` to export.
 	//
 	// Noting a context exception described later, all parameters or result types must match WebAssembly 1.0 (20191205) value
-	// types. This means uint32, uint64, float32 or float64. Up to one result can be returned.
+	// types. This means uint32, uint64, float32 or float64. Up to one result can be returned, unless
+	// RuntimeConfig.WithFeatureMultiValue is enabled.
 	//
 	// Ex. This is a valid host function:
 	//
@@ -56,6 +57,30 @@ type ModuleBuilder interface {
 	//		return x + y
 	//	}
 	//
+	// Parameters and results may also be references when RuntimeConfig.WithFeatureReferenceTypes is enabled: an
+	// externref is a uintptr, and a funcref is an api.Function, where nil is null.
+	//
+	// Ex. This returns multiple results, including the externref and funcref passed to it:
+	//
+	//	swap := func(h uintptr, fn api.Function) (api.Function, uintptr) {
+	//		return fn, h
+	//	}
+	//
+	// Parameters and results may also be a v128 when RuntimeConfig.WithFeatureSIMD is enabled: a [2]uint64 of the low
+	// then high 64 bits.
+	//
+	// Host functions may also have a trailing result of type error, which isn't a Wasm result. When it is non-nil,
+	// the calling function traps, and api.Function Call returns it with the Wasm stack trace appended. Use errors.Is
+	// or errors.As to unwrap it.
+	//
+	// Ex. This traps when the buffer is too small:
+	//
+	//	readAll := func(ctx context.Context, m api.Module, offset, byteCount uint32) (uint32, error) {
+	//		if byteCount < minBufSize {
+	//			return 0, ErrBufferTooSmall
+	//		}
+	//	--snip--
+	//
 	// Host functions may also have an initial parameter (param[0]) of type context.Context or api.Module.
 	//
 	// Ex. This uses a Go Context:
@@ -89,6 +114,25 @@ type ModuleBuilder interface {
 	// ExportFunctions is a convenience that calls ExportFunction for each key/value in the provided map.
 	ExportFunctions(nameToGoFunc map[string]interface{}) ModuleBuilder
 
+	// ExportGoModuleFunc is like ExportFunction, except the signature is declared explicitly and the function reads
+	// and writes values on a stack instead. This avoids the reflection and allocation that ExportFunction incurs on
+	// each call, so is best for functions that are called frequently.
+	//
+	// * name - the name to export. Ex "random_get"
+	// * fn - the function to export, which reads params from and writes results to the stack. See api.GoModuleFunc.
+	// * params - the possibly empty parameter types of fn.
+	// * results - the possibly empty result types of fn.
+	//
+	// Ex. This is the equivalent of the addInts example in ExportFunction:
+	//
+	//	builder.ExportGoModuleFunc("add_ints", func(ctx context.Context, m api.Module, stack []uint64) {
+	//		x, y := uint32(stack[0]), uint32(stack[1])
+	//		stack[0] = uint64(x + y)
+	//	}, []api.ValueType{api.ValueTypeI32, api.ValueTypeI32}, []api.ValueType{api.ValueTypeI32})
+	//
+	// Note: If a function is already exported with the same name, this overwrites it.
+	ExportGoModuleFunc(name string, fn api.GoModuleFunc, params, results []api.ValueType) ModuleBuilder
+
 	// ExportMemory adds linear memory, which a WebAssembly module can import and become available via api.Memory.
 	//
 	// * name - the name to export. Ex "memory" for wasi.ModuleSnapshotPreview1
@@ -204,6 +248,12 @@ func (b *moduleBuilder) ExportFunctions(nameToGoFunc map[string]interface{}) Mod
 	return b
 }
 
+// ExportGoModuleFunc implements ModuleBuilder.ExportGoModuleFunc
+func (b *moduleBuilder) ExportGoModuleFunc(name string, fn api.GoModuleFunc, params, results []api.ValueType) ModuleBuilder {
+	b.nameToGoFunc[name] = &wasm.HostFunc{ParamTypes: params, ResultTypes: results, Code: fn}
+	return b
+}
+
 // ExportMemory implements ModuleBuilder.ExportMemory
 func (b *moduleBuilder) ExportMemory(name string, minPages uint32) ModuleBuilder {
 	b.nameToMemory[name] = &wasm.Memory{Min: minPages}
@@ -1,6 +1,7 @@
 package wazero
 
 import (
+	"context"
 	"math"
 	"reflect"
 	"testing"
@@ -24,6 +25,8 @@ func TestNewModuleBuilder_Build(t *testing.T) {
 		return 0
 	}
 	fnUint64_uint32 := reflect.ValueOf(uint64_uint32)
+	goModuleFunc := api.GoModuleFunc(func(context.Context, api.Module, []uint64) {})
+	fnGoModuleFunc := reflect.ValueOf(goModuleFunc)
 
 	tests := []struct {
 		name     string
@@ -104,6 +107,25 @@ func TestNewModuleBuilder_Build(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "ExportGoModuleFunc",
+			input: func(r Runtime) ModuleBuilder {
+				return r.NewModuleBuilder("").ExportGoModuleFunc("1", goModuleFunc, []api.ValueType{i64}, []api.ValueType{i32})
+			},
+			expected: &wasm.Module{
+				TypeSection: []*wasm.FunctionType{
+					{Params: []api.ValueType{i64}, Results: []api.ValueType{i32}, ParamNumInUint64: 1, ResultNumInUint64: 1},
+				},
+				FunctionSection:     []wasm.Index{0},
+				HostFunctionSection: []*reflect.Value{&fnGoModuleFunc},
+				ExportSection: []*wasm.Export{
+					{Name: "1", Type: wasm.ExternTypeFunc, Index: 0},
+				},
+				NameSection: &wasm.NameSection{
+					FunctionNames: wasm.NameMap{{Index: 0, Name: "1"}},
+				},
+			},
+		},
 		{
 			name: "ExportFunctions",
 			input: func(r Runtime) ModuleBuilder {
@@ -159,7 +181,7 @@ func TestNewModuleBuilder_Build(t *testing.T) {
 				return r.NewModuleBuilder("").ExportMemory("memory", 1)
 			},
 			expected: &wasm.Module{
-				MemorySection: &wasm.Memory{Min: 1, Cap: 1, Max: wasm.MemoryLimitPages},
+				MemorySection: []*wasm.Memory{{Min: 1, Cap: 1, Max: wasm.MemoryLimitPages}},
 				ExportSection: []*wasm.Export{
 					{Name: "memory", Type: wasm.ExternTypeMemory, Index: 0},
 				},
@@ -171,7 +193,7 @@ func TestNewModuleBuilder_Build(t *testing.T) {
 				return r.NewModuleBuilder("").ExportMemory("memory", 1).ExportMemory("memory", 2)
 			},
 			expected: &wasm.Module{
-				MemorySection: &wasm.Memory{Min: 2, Cap: 2, Max: wasm.MemoryLimitPages},
+				MemorySection: []*wasm.Memory{{Min: 2, Cap: 2, Max: wasm.MemoryLimitPages}},
 				ExportSection: []*wasm.Export{
 					{Name: "memory", Type: wasm.ExternTypeMemory, Index: 0},
 				},
@@ -183,7 +205,7 @@ func TestNewModuleBuilder_Build(t *testing.T) {
 				return r.NewModuleBuilder("").ExportMemoryWithMax("memory", 1, 1)
 			},
 			expected: &wasm.Module{
-				MemorySection: &wasm.Memory{Min: 1, Cap: 1, Max: 1, IsMaxEncoded: true},
+				MemorySection: []*wasm.Memory{{Min: 1, Cap: 1, Max: 1, IsMaxEncoded: true}},
 				ExportSection: []*wasm.Export{
 					{Name: "memory", Type: wasm.ExternTypeMemory, Index: 0},
 				},
@@ -195,7 +217,7 @@ func TestNewModuleBuilder_Build(t *testing.T) {
 				return r.NewModuleBuilder("").ExportMemoryWithMax("memory", 1, 1).ExportMemoryWithMax("memory", 1, 2)
 			},
 			expected: &wasm.Module{
-				MemorySection: &wasm.Memory{Min: 1, Cap: 1, Max: 2, IsMaxEncoded: true},
+				MemorySection: []*wasm.Memory{{Min: 1, Cap: 1, Max: 2, IsMaxEncoded: true}},
 				ExportSection: []*wasm.Export{
 					{Name: "memory", Type: wasm.ExternTypeMemory, Index: 0},
 				},
@@ -6,6 +6,7 @@ import (
 	"io"
 	"io/fs"
 	"math"
+	"net"
 
 	"github.com/tetratelabs/wazero/api"
 	"github.com/tetratelabs/wazero/internal/engine/compiler"
@@ -111,6 +112,80 @@ type RuntimeConfig interface {
 	// See https://github.com/WebAssembly/spec/blob/main/proposals/simd/SIMD.md
 	WithFeatureSIMD(bool) RuntimeConfig
 
+	// WithFeatureThreads enables shared memories and atomic instructions ("threads"). This defaults to false as the
+	// feature was not in WebAssembly 1.0.
+	//
+	// Here are the notable effects:
+	// * Memories can be declared `shared`, in which case a maximum size is required, and the memory is allocated
+	//   up to that maximum so that it never moves when grown. The maximum is lowered to 16384 pages (1GiB), so a
+	//   shared memory can't grow past that, and CompileConfig.WithMemorySizer can lower it further.
+	// * A shared memory can be exported and imported into several module instances, which may run concurrently.
+	// * Adds atomic loads, stores and read-modify-write instructions as well as `memory.atomic.wait32`,
+	//   `memory.atomic.wait64`, `memory.atomic.notify` and `atomic.fence`.
+	//
+	// See https://github.com/WebAssembly/threads/blob/main/proposals/threads/Overview.md
+	WithFeatureThreads(bool) RuntimeConfig
+
+	// WithFeatureTailCall enables tail calls ("tail-call"). This defaults to false as the feature was not in
+	// WebAssembly 1.0.
+	//
+	// Here are the notable effects:
+	// * Adds instructions `return_call` and `return_call_indirect`, which replace the call frame of the caller with
+	//   the one of the callee. Hence, tail recursion doesn't exhaust the call stack.
+	//
+	// See https://github.com/WebAssembly/tail-call/blob/main/proposals/tail-call/Overview.md
+	WithFeatureTailCall(bool) RuntimeConfig
+
+	// WithFeatureExceptionHandling enables exception handling ("exception-handling"). This defaults to false as the
+	// feature was not in WebAssembly 1.0.
+	//
+	// Here are the notable effects:
+	// * Adds the tag section, and tags as a kind of import and export.
+	// * Adds instructions `try`, `catch`, `catch_all`, `delegate`, `throw` and `rethrow`.
+	// * Host functions can throw an api.Exception by panicking with it, and an uncaught one is returned as the error
+	//   of api.Function Call.
+	//
+	// See https://github.com/WebAssembly/exception-handling/blob/main/proposals/exception-handling/Exceptions.md
+	WithFeatureExceptionHandling(bool) RuntimeConfig
+
+	// WithFeatureMultiMemory enables multiple memories ("multi-memory"). This defaults to false as the feature was not
+	// in WebAssembly 1.0.
+	//
+	// Here are the notable effects:
+	// * A module can define and import more than one memory, and export each of them.
+	// * Memory instructions, such as loads, stores, `memory.size` and `memory.copy`, can access any memory by index.
+	// * Active data segments can initialize any memory.
+	//
+	// Note: api.Module Memory returns the memory at index zero. Use api.Module ExportedMemory to access the others.
+	//
+	// See https://github.com/WebAssembly/multi-memory/blob/main/proposals/multi-memory/Overview.md
+	WithFeatureMultiMemory(bool) RuntimeConfig
+
+	// WithFeatureMemory64 enables memories indexed with i64 ("memory64"). This defaults to false as the feature was not
+	// in WebAssembly 1.0.
+	//
+	// Here are the notable effects:
+	// * A memory can be declared with the i64 index type, which is what the wasm64 target of compilers emits.
+	// * Address operands of instructions on such a memory, such as loads and stores, are i64, as well as the results of
+	//   `memory.size` and `memory.grow`.
+	//
+	// Note: A memory is still limited to 65536 pages (4 GiB) even if it is indexed with i64, so out of range addresses
+	// trap. Hosts can access memory with i64 pointers via the api.Memory functions suffixed with 64, such as Read64.
+	//
+	// See https://github.com/WebAssembly/memory64/blob/main/proposals/memory64/Overview.md
+	WithFeatureMemory64(bool) RuntimeConfig
+
+	// WithFeatureExtendedConst enables arithmetic in constant expressions ("extended-const"). This defaults to false as
+	// the feature was not in WebAssembly 1.0.
+	//
+	// Here are the notable effects:
+	// * Global initializers and the offsets of active data and element segments can combine constants and imported
+	//   globals with `i32.add`, `i32.sub`, `i32.mul`, `i64.add`, `i64.sub` and `i64.mul`.
+	//
+	// Note: LLVM emits these for position-independent code, such as modules linked with `wasm-ld --experimental-pic`.
+	// See https://github.com/WebAssembly/extended-const/blob/main/proposals/extended-const/Overview.md
+	WithFeatureExtendedConst(bool) RuntimeConfig
+
 	// WithWasmCore1 enables features included in the WebAssembly Core Specification 1.0. Selecting this
 	// overwrites any currently accumulated features with only those included in this W3C recommendation.
 	//
@@ -133,16 +208,53 @@ type RuntimeConfig interface {
 	//
 	// See https://www.w3.org/TR/2022/WD-wasm-core-2-20220419/
 	WithWasmCore2() RuntimeConfig
+
+	// WithCallStackCeiling sets the maximum number of nested function calls, including host functions, in a call
+	// from the host. This defaults to 2000.
+	//
+	// Exceeding the ceiling raises an error instead of exhausting the host. Ex. Raise it for deeply recursive guests,
+	// such as parsers, or lower it for untrusted guests:
+	//	rConfig = wazero.NewRuntimeConfig().WithCallStackCeiling(100)
+	//
+	// Note: Zero is invalid and ignored.
+	// Note: The interpreter caps the ceiling at 100000, or 15000 on 32-bit platforms, as each function call nests Go
+	// calls, and a deeper stack would exceed the maximum size of a goroutine stack.
+	WithCallStackCeiling(uint32) RuntimeConfig
+
+	// WithValueStackCeiling sets the maximum size in bytes of the stack holding the parameters, locals and operands
+	// of the functions in a call from the host. This defaults to 64 MiB.
+	//
+	// Exceeding the ceiling raises an error instead of exhausting the host. Ex. Lower it for untrusted guests:
+	//	rConfig = wazero.NewRuntimeConfig().WithValueStackCeiling(1 << 20)
+	//
+	// Note: Each value takes 8 bytes, or 16 bytes for a vector. Hence, the ceiling is rounded down to a multiple of 8.
+	// Note: Zero is invalid and ignored.
+	WithValueStackCeiling(uint32) RuntimeConfig
+
+	// WithFuelCosts makes functions of modules compiled by the runtime consume experimental.Fuel, by the costs of the
+	// instructions they execute. The costs are keyed by the name of the instruction in the text format, such as
+	// "i32.add" or "memory.grow". Calls exceed their fuel with an error instead of executing indefinitely. Ex.
+	//	rConfig = wazero.NewRuntimeConfig().WithFuelCosts(func(string) uint32 { return 1 })
+	//
+	// Note: Calls without experimental.WithFuel have unlimited fuel.
+	// Note: A block consumes the cost of all of its instructions upon entry, even if it branches out early.
+	// Note: Host functions don't consume fuel.
+	// Note: Nil is invalid and ignored.
+	// See experimental.WithFuel
+	WithFuelCosts(costs func(instruction string) uint32) RuntimeConfig
 }
 
 type runtimeConfig struct {
 	enabledFeatures wasm.Features
-	newEngine       func(wasm.Features) wasm.Engine
+	stackLimits     wasm.StackLimits
+	fuelCosts       func(instruction string) uint32
+	newEngine       func(wasm.Features, wasm.StackLimits) wasm.Engine
 }
 
 // engineLessConfig helps avoid copy/pasting the wrong defaults.
 var engineLessConfig = &runtimeConfig{
 	enabledFeatures: wasm.Features20191205,
+	stackLimits:     wasm.DefaultStackLimits,
 }
 
 // NewRuntimeConfigCompiler compiles WebAssembly modules into
@@ -224,6 +336,48 @@ func (c *runtimeConfig) WithFeatureSIMD(enabled bool) RuntimeConfig {
 	return &ret
 }
 
+// WithFeatureThreads implements RuntimeConfig.WithFeatureThreads
+func (c *runtimeConfig) WithFeatureThreads(enabled bool) RuntimeConfig {
+	ret := *c // copy
+	ret.enabledFeatures = ret.enabledFeatures.Set(wasm.FeatureThreads, enabled)
+	return &ret
+}
+
+// WithFeatureTailCall implements RuntimeConfig.WithFeatureTailCall
+func (c *runtimeConfig) WithFeatureTailCall(enabled bool) RuntimeConfig {
+	ret := *c // copy
+	ret.enabledFeatures = ret.enabledFeatures.Set(wasm.FeatureTailCall, enabled)
+	return &ret
+}
+
+// WithFeatureExceptionHandling implements RuntimeConfig.WithFeatureExceptionHandling
+func (c *runtimeConfig) WithFeatureExceptionHandling(enabled bool) RuntimeConfig {
+	ret := *c // copy
+	ret.enabledFeatures = ret.enabledFeatures.Set(wasm.FeatureExceptionHandling, enabled)
+	return &ret
+}
+
+// WithFeatureMultiMemory implements RuntimeConfig.WithFeatureMultiMemory
+func (c *runtimeConfig) WithFeatureMultiMemory(enabled bool) RuntimeConfig {
+	ret := *c // copy
+	ret.enabledFeatures = ret.enabledFeatures.Set(wasm.FeatureMultiMemory, enabled)
+	return &ret
+}
+
+// WithFeatureMemory64 implements RuntimeConfig.WithFeatureMemory64
+func (c *runtimeConfig) WithFeatureMemory64(enabled bool) RuntimeConfig {
+	ret := *c // copy
+	ret.enabledFeatures = ret.enabledFeatures.Set(wasm.FeatureMemory64, enabled)
+	return &ret
+}
+
+// WithFeatureExtendedConst implements RuntimeConfig.WithFeatureExtendedConst
+func (c *runtimeConfig) WithFeatureExtendedConst(enabled bool) RuntimeConfig {
+	ret := *c // copy
+	ret.enabledFeatures = ret.enabledFeatures.Set(wasm.FeatureExtendedConst, enabled)
+	return &ret
+}
+
 // WithWasmCore1 implements RuntimeConfig.WithWasmCore1
 func (c *runtimeConfig) WithWasmCore1() RuntimeConfig {
 	ret := *c // copy
@@ -238,6 +392,36 @@ func (c *runtimeConfig) WithWasmCore2() RuntimeConfig {
 	return &ret
 }
 
+// WithCallStackCeiling implements RuntimeConfig.WithCallStackCeiling
+func (c *runtimeConfig) WithCallStackCeiling(ceiling uint32) RuntimeConfig {
+	if ceiling == 0 {
+		return c
+	}
+	ret := *c // copy
+	ret.stackLimits.CallStackCeiling = int(ceiling)
+	return &ret
+}
+
+// WithValueStackCeiling implements RuntimeConfig.WithValueStackCeiling
+func (c *runtimeConfig) WithValueStackCeiling(ceilingBytes uint32) RuntimeConfig {
+	if ceilingBytes == 0 {
+		return c
+	}
+	ret := *c // copy
+	ret.stackLimits.ValueStackCeiling = int(ceilingBytes / 8)
+	return &ret
+}
+
+// WithFuelCosts implements RuntimeConfig.WithFuelCosts
+func (c *runtimeConfig) WithFuelCosts(costs func(instruction string) uint32) RuntimeConfig {
+	if costs == nil {
+		return c
+	}
+	ret := *c // copy
+	ret.fuelCosts = costs
+	return &ret
+}
+
 // CompiledModule is a WebAssembly 1.0 module ready to be instantiated (Runtime.InstantiateModule) as an api.Module.
 //
 // Note: Closing the wazero.Runtime closes any CompiledModule it compiled.
@@ -245,6 +429,13 @@ func (c *runtimeConfig) WithWasmCore2() RuntimeConfig {
 // the name "Module" for both before and after instantiation as the name conflation has caused confusion.
 // See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#semantic-phases%E2%91%A0
 type CompiledModule interface {
+	// Imports returns the imports of this module in order, after any CompileConfig WithImportRenamer. Each must be
+	// satisfied by an export of another module in the runtime, such as a host module, to instantiate it.
+	Imports() []api.ImportDefinition
+
+	// Exports returns the exports of this module in order, which are available via api.Module once instantiated.
+	Exports() []api.ExportDefinition
+
 	// Close releases all the allocated resources for this CompiledModule.
 	//
 	// Note: It is safe to call Close while having outstanding calls from an api.Module instantiated from this.
@@ -257,6 +448,16 @@ type compiledCode struct {
 	compiledEngine wasm.Engine
 }
 
+// Imports implements CompiledModule.Imports
+func (c *compiledCode) Imports() []api.ImportDefinition {
+	return c.module.ImportDefinitions()
+}
+
+// Exports implements CompiledModule.Exports
+func (c *compiledCode) Exports() []api.ExportDefinition {
+	return c.module.ExportDefinitions()
+}
+
 // Close implements CompiledModule.Close
 func (c *compiledCode) Close(_ context.Context) error {
 	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
@@ -346,6 +547,13 @@ type ModuleConfig interface {
 	// See https://en.wikipedia.org/wiki/Null-terminated_string
 	WithArgs(...string) ModuleConfig
 
+	// WithConn adds a connected socket, which the module can use with functions like "sock_recv" and "sock_send" in
+	// "wasi_snapshot_preview1", as well as "fd_read" and "fd_write". Defaults to none.
+	//
+	// Note: The file descriptor is assigned the same way as WithListener.
+	// Note: The connection is shared the same way as WithListener.
+	WithConn(net.Conn) ModuleConfig
+
 	// WithEnv sets an environment variable visible to a Module that imports functions. Defaults to none.
 	//
 	// Validation is the same as os.Setenv on Linux and replaces any existing value. Unlike exec.Cmd Env, this does not
@@ -377,8 +585,42 @@ type ModuleConfig interface {
 	//	config := wazero.NewModuleConfig().WithFS(rooted)
 	//
 	// Note: This sets WithWorkDirFS to the same file-system unless already set.
+	// Note: Functions that mutate files, such as "path_create_directory" in "wasi_snapshot_preview1", fail unless the
+	// file system is an experimental.WriteFS, such as experimental.DirFS or experimental.MemFS.
 	WithFS(fs.FS) ModuleConfig
 
+	// WithFSMount assigns the file system to use for any paths beginning at the guest path, such as "/data". Each guest
+	// path is pre-opened with its own file descriptor, in the order it was first configured.
+	//
+	// Ex. This mounts a read-only input directory, a writable output directory and scratch space:
+	//
+	//	config := wazero.NewModuleConfig().
+	//		WithFSMount(os.DirFS("/jobs/123/in"), "/data").
+	//		WithFSMount(experimental.DirFS("/jobs/123/out"), "/out").
+	//		WithFSMount(experimental.MemFS(), "/tmp")
+	//
+	// Note: The guest path is cleaned, so "/data/" and "/data" are the same mount. Configuring the same path again
+	// replaces its file system, but keeps its file descriptor.
+	// Note: WithFS is the same as WithFSMount with the guest path "/", and WithWorkDirFS with the guest path ".".
+	WithFSMount(fs fs.FS, guestPath string) ModuleConfig
+
+	// WithListener adds a listening socket, which the module can accept connections from with functions like
+	// "sock_accept" in "wasi_snapshot_preview1". Defaults to none.
+	//
+	// Ex. This allows an HTTP server compiled to wasm32-wasi to serve requests from a socket the host opened:
+	//
+	//	ln, err := net.Listen("tcp", "127.0.0.1:8080")
+	//	require.NoError(t, err)
+	//
+	//	config := wazero.NewModuleConfig().WithListener(ln)
+	//
+	// Note: Sockets are assigned file descriptors in the order they were added, after any pre-opened directories, such
+	// as those added with WithFS. Pre-opened directories are first, as that's where guests look for them.
+	// Note: Every module instantiated with this config shares the listener, as it can't be duplicated. It is closed when
+	// the last of these modules closes it, for example with api.Module Close. Connections accepted from it belong to the
+	// module that accepted them.
+	WithListener(net.Listener) ModuleConfig
+
 	// WithName configures the module name. Defaults to what was decoded or overridden via CompileConfig.WithModuleName.
 	WithName(string) ModuleConfig
 
@@ -433,7 +675,7 @@ type ModuleConfig interface {
 	//	var rootFS embed.FS
 	//
 	//	// Files relative to this source under appA are available under "/" and files relative to "/work/appA" under ".".
-	//	config := wazero.NewModuleConfig().WithFS(rootFS).WithWorkDirFS(os.DirFS("/work/appA"))
+	//	config := wazero.NewModuleConfig().WithFS(rootFS).WithWorkDirFS(experimental.DirFS("/work/appA"))
 	//
 	// Note: os.DirFS documentation includes important notes about isolation, which also applies to fs.Sub. As of Go 1.18,
 	// the built-in file-systems are not jailed (chroot). See https://github.com/golang/go/issues/42322
@@ -473,6 +715,13 @@ func (c *moduleConfig) WithArgs(args ...string) ModuleConfig {
 	return &ret
 }
 
+// WithConn implements ModuleConfig.WithConn
+func (c *moduleConfig) WithConn(conn net.Conn) ModuleConfig {
+	ret := *c // copy
+	ret.fs = ret.fs.WithConn(conn)
+	return &ret
+}
+
 // WithEnv implements ModuleConfig.WithEnv
 func (c *moduleConfig) WithEnv(key, value string) ModuleConfig {
 	ret := *c // copy
@@ -493,6 +742,20 @@ func (c *moduleConfig) WithFS(fs fs.FS) ModuleConfig {
 	return &ret
 }
 
+// WithFSMount implements ModuleConfig.WithFSMount
+func (c *moduleConfig) WithFSMount(fs fs.FS, guestPath string) ModuleConfig {
+	ret := *c // copy
+	ret.fs = ret.fs.WithFSMount(fs, guestPath)
+	return &ret
+}
+
+// WithListener implements ModuleConfig.WithListener
+func (c *moduleConfig) WithListener(ln net.Listener) ModuleConfig {
+	ret := *c // copy
+	ret.fs = ret.fs.WithListener(ln)
+	return &ret
+}
+
 // WithName implements ModuleConfig.WithName
 func (c *moduleConfig) WithName(name string) ModuleConfig {
 	ret := *c // copy
@@ -561,10 +824,10 @@ func (c *moduleConfig) toSysContext() (sys *wasm.SysContext, err error) {
 		environ = append(environ, key+"="+value)
 	}
 
-	preopens, err := c.fs.Preopens()
+	openedFiles, err := c.fs.OpenedFiles()
 	if err != nil {
 		return nil, err
 	}
 
-	return wasm.NewSysContext(math.MaxUint32, c.args, environ, c.stdin, c.stdout, c.stderr, c.randSource, preopens)
+	return wasm.NewSysContext(math.MaxUint32, c.args, environ, c.stdin, c.stdout, c.stderr, c.randSource, openedFiles)
 }
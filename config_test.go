@@ -4,6 +4,7 @@ import (
 	"context"
 	"io"
 	"math"
+	"net"
 	"reflect"
 	"testing"
 	"testing/fstest"
@@ -101,6 +102,99 @@ func TestRuntimeConfig(t *testing.T) {
 				enabledFeatures: wasm.FeatureSIMD,
 			},
 		},
+		{
+			name: "threads",
+			with: func(c RuntimeConfig) RuntimeConfig {
+				return c.WithFeatureThreads(true)
+			},
+			expected: &runtimeConfig{
+				enabledFeatures: wasm.FeatureThreads,
+			},
+		},
+		{
+			name: "tail-call",
+			with: func(c RuntimeConfig) RuntimeConfig {
+				return c.WithFeatureTailCall(true)
+			},
+			expected: &runtimeConfig{
+				enabledFeatures: wasm.FeatureTailCall,
+			},
+		},
+		{
+			name: "exception-handling",
+			with: func(c RuntimeConfig) RuntimeConfig {
+				return c.WithFeatureExceptionHandling(true)
+			},
+			expected: &runtimeConfig{
+				enabledFeatures: wasm.FeatureExceptionHandling,
+			},
+		},
+		{
+			name: "multi-memory",
+			with: func(c RuntimeConfig) RuntimeConfig {
+				return c.WithFeatureMultiMemory(true)
+			},
+			expected: &runtimeConfig{
+				enabledFeatures: wasm.FeatureMultiMemory,
+			},
+		},
+		{
+			name: "memory64",
+			with: func(c RuntimeConfig) RuntimeConfig {
+				return c.WithFeatureMemory64(true)
+			},
+			expected: &runtimeConfig{
+				enabledFeatures: wasm.FeatureMemory64,
+			},
+		},
+		{
+			name: "extended-const",
+			with: func(c RuntimeConfig) RuntimeConfig {
+				return c.WithFeatureExtendedConst(true)
+			},
+			expected: &runtimeConfig{
+				enabledFeatures: wasm.FeatureExtendedConst,
+			},
+		},
+		{
+			name: "call stack ceiling",
+			with: func(c RuntimeConfig) RuntimeConfig {
+				return c.WithCallStackCeiling(100)
+			},
+			expected: &runtimeConfig{
+				stackLimits: wasm.StackLimits{CallStackCeiling: 100},
+			},
+		},
+		{
+			name: "call stack ceiling - zero ignored",
+			with: func(c RuntimeConfig) RuntimeConfig {
+				return c.WithCallStackCeiling(0)
+			},
+			expected: &runtimeConfig{},
+		},
+		{
+			name: "value stack ceiling",
+			with: func(c RuntimeConfig) RuntimeConfig {
+				return c.WithValueStackCeiling(1 << 20)
+			},
+			expected: &runtimeConfig{
+				stackLimits: wasm.StackLimits{ValueStackCeiling: 1 << 17},
+			},
+		},
+		{
+			name: "value stack ceiling - zero ignored",
+			with: func(c RuntimeConfig) RuntimeConfig {
+				return c.WithValueStackCeiling(0)
+			},
+			expected: &runtimeConfig{},
+		},
+		{
+			name: "fuel costs - nil ignored",
+			with: func(c RuntimeConfig) RuntimeConfig {
+				return c.WithFuelCosts(nil)
+			},
+			expected: &runtimeConfig{},
+		},
 	}
 	for _, tt := range tests {
 		tc := tt
@@ -115,6 +209,21 @@ func TestRuntimeConfig(t *testing.T) {
 	}
 }
 
+// TestRuntimeConfig_WithFuelCosts is separate from TestRuntimeConfig as functions can't be compared.
+func TestRuntimeConfig_WithFuelCosts(t *testing.T) {
+	input := &runtimeConfig{}
+	rc := input.WithFuelCosts(func(instruction string) uint32 {
+		if instruction == "call" {
+			return 10
+		}
+		return 1
+	}).(*runtimeConfig)
+	require.Equal(t, uint32(10), rc.fuelCosts("call"))
+	require.Equal(t, uint32(1), rc.fuelCosts("i32.add"))
+	// The source wasn't modified
+	require.Nil(t, input.fuelCosts)
+}
+
 func TestRuntimeConfig_FeatureToggle(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -295,6 +404,12 @@ func TestModuleConfig(t *testing.T) {
 func TestModuleConfig_toSysContext(t *testing.T) {
 	testFS := fstest.MapFS{}
 	testFS2 := fstest.MapFS{}
+	testListener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer testListener.Close()
+	testConn, testConnPeer := net.Pipe()
+	defer testConn.Close()
+	defer testConnPeer.Close()
 
 	tests := []struct {
 		name     string
@@ -441,7 +556,7 @@ func TestModuleConfig_toSysContext(t *testing.T) {
 				nil,            // randSource
 				map[uint32]*sys.FileEntry{ // openedFiles
 					3: {Path: "/", FS: testFS},
-					4: {Path: ".", FS: testFS},
+					4: {Path: ".", FS: testFS, Dev: 3},
 				},
 			),
 		},
@@ -458,7 +573,7 @@ func TestModuleConfig_toSysContext(t *testing.T) {
 				nil,            // randSource
 				map[uint32]*sys.FileEntry{ // openedFiles
 					3: {Path: "/", FS: testFS2},
-					4: {Path: ".", FS: testFS2},
+					4: {Path: ".", FS: testFS2, Dev: 3},
 				},
 			),
 		},
@@ -512,6 +627,73 @@ func TestModuleConfig_toSysContext(t *testing.T) {
 				},
 			),
 		},
+		{
+			name:  "WithFSMount",
+			input: NewModuleConfig().WithFSMount(testFS, "/data").WithFSMount(testFS2, "/tmp"),
+			expected: requireSysContext(t,
+				math.MaxUint32, // max
+				nil,            // args
+				nil,            // environ
+				nil,            // stdin
+				nil,            // stdout
+				nil,            // stderr
+				nil,            // randSource
+				map[uint32]*sys.FileEntry{ // openedFiles
+					3: {Path: "/data", FS: testFS},
+					4: {Path: "/tmp", FS: testFS2},
+				},
+			),
+		},
+		{
+			name:  "WithFSMount cleans path and overwrites",
+			input: NewModuleConfig().WithFSMount(testFS, "/data/").WithFSMount(testFS2, "/tmp").WithFSMount(testFS2, "/data"),
+			expected: requireSysContext(t,
+				math.MaxUint32, // max
+				nil,            // args
+				nil,            // environ
+				nil,            // stdin
+				nil,            // stdout
+				nil,            // stderr
+				nil,            // randSource
+				map[uint32]*sys.FileEntry{ // openedFiles
+					3: {Path: "/data", FS: testFS2},
+					4: {Path: "/tmp", FS: testFS2},
+				},
+			),
+		},
+		{
+			name:  "WithListener and WithConn",
+			input: NewModuleConfig().WithListener(testListener).WithConn(testConn).WithFSMount(testFS, "/data"),
+			expected: requireSysContext(t,
+				math.MaxUint32, // max
+				nil,            // args
+				nil,            // environ
+				nil,            // stdin
+				nil,            // stdout
+				nil,            // stderr
+				nil,            // randSource
+				// openedFiles are from an equivalent config, as sockets are reference counted.
+				requireOpenedFiles(t, sys.NewFSConfig().WithFSMount(testFS, "/data").WithListener(testListener).WithConn(testConn)),
+			),
+		},
+		{
+			name:  "WithFS and WithFSMount",
+			input: NewModuleConfig().WithFS(testFS).WithFSMount(testFS2, "/tmp"),
+			expected: requireSysContext(t,
+				math.MaxUint32, // max
+				nil,            // args
+				nil,            // environ
+				nil,            // stdin
+				nil,            // stdout
+				nil,            // stderr
+				nil,            // randSource
+				map[uint32]*sys.FileEntry{ // openedFiles
+					3: {Path: "/", FS: testFS},
+					4: {Path: "/tmp", FS: testFS2},
+					5: {Path: ".", FS: testFS, Dev: 3},
+				},
+			),
+		},
 	}
 	for _, tt := range tests {
 		tc := tt
@@ -565,6 +747,21 @@ func TestModuleConfig_toSysContext_Errors(t *testing.T) {
 			input:       NewModuleConfig().WithWorkDirFS(nil),
 			expectedErr: "FS for . is nil",
 		},
+		{
+			name:        "WithFSMount nil",
+			input:       NewModuleConfig().WithFSMount(nil, "/data"),
+			expectedErr: "FS for /data is nil",
+		},
+		{
+			name:        "WithListener nil",
+			input:       NewModuleConfig().WithListener(nil),
+			expectedErr: "socket[0] is nil",
+		},
+		{
+			name:        "WithConn nil",
+			input:       NewModuleConfig().WithConn(nil),
+			expectedErr: "socket[0] is nil",
+		},
 	}
 	for _, tt := range tests {
 		tc := tt
@@ -583,6 +780,12 @@ func requireSysContext(t *testing.T, max uint32, args, environ []string, stdin i
 	return sys
 }
 
+func requireOpenedFiles(t *testing.T, c *sys.FSConfig) map[uint32]*sys.FileEntry {
+	openedFiles, err := c.OpenedFiles()
+	require.NoError(t, err)
+	return openedFiles
+}
+
 func TestCompiledCode_Close(t *testing.T) {
 	for _, ctx := range []context.Context{nil, testCtx} { // Ensure it doesn't crash on nil!
 		e := &mockEngine{name: "1", cachedModules: map[*wasm.Module]struct{}{}}
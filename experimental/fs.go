@@ -13,7 +13,7 @@ import (
 // Note: This has the same effect as the same function name on wazero.ModuleConfig.
 func WithFS(ctx context.Context, fs fs.FS) (context.Context, api.Closer, error) {
 	fsConfig := internalfs.NewFSConfig().WithFS(fs)
-	preopens, err := fsConfig.Preopens()
+	preopens, err := fsConfig.OpenedFiles()
 	if err != nil {
 		return nil, nil, err
 	}
@@ -21,3 +21,20 @@ func WithFS(ctx context.Context, fs fs.FS) (context.Context, api.Closer, error)
 	fsCtx := internalfs.NewFSContext(preopens)
 	return context.WithValue(ctx, internalfs.FSKey{}, fsCtx), fsCtx, nil
 }
+
+// WriteFS is a file system that allows mutations, such as "path_create_directory" in "wasi_snapshot_preview1". Pass
+// one to WithFS or wazero.ModuleConfig WithFS. Functions that mutate files fail on other fs.FS, as they are read-only.
+type WriteFS = internalfs.WriteFS
+
+// DirFS returns a WriteFS for the tree of files rooted at the host directory dir.
+//
+// Note: Like os.DirFS, this doesn't prevent symbolic links that already exist inside dir from escaping it. However,
+// "path_symlink" refuses to create new ones that would.
+func DirFS(dir string) WriteFS {
+	return internalfs.NewDirFS(dir)
+}
+
+// MemFS returns an empty WriteFS backed by memory, for example to give a module a scratch directory.
+func MemFS() WriteFS {
+	return internalfs.NewMemFS()
+}
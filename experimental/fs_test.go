@@ -39,3 +39,17 @@ func TestWithFS(t *testing.T) {
 	require.Equal(t, ".", entry.Path)
 	require.Equal(t, mapfs, entry.FS)
 }
+
+func TestWithFS_WriteFS(t *testing.T) {
+	for _, wfs := range []experimental.WriteFS{experimental.DirFS(t.TempDir()), experimental.MemFS()} {
+		ctx, closer, err := experimental.WithFS(context.Background(), wfs)
+		require.NoError(t, err)
+		defer closer.Close(ctx)
+
+		fsCtx := ctx.Value(sys.FSKey{}).(*sys.FSContext)
+		entry, ok := fsCtx.OpenedFile(3)
+		require.True(t, ok)
+		_, ok = entry.FS.(sys.WriteFS)
+		require.True(t, ok)
+	}
+}
@@ -0,0 +1,34 @@
+package experimental
+
+import (
+	"context"
+
+	"github.com/tetratelabs/wazero/internal/wasmruntime"
+)
+
+// Fuel is the budget of calls to functions of modules compiled with wazero.RuntimeConfig WithFuelCosts, which
+// decreases by the costs of the instructions they execute. Pass it to calls with WithFuel.
+//
+// Ex. To bound a call, then read the remaining fuel:
+//	fuel := experimental.Fuel(10000)
+//	_, err := fn.Call(experimental.WithFuel(ctx, &fuel))
+//	if errors.Is(err, experimental.ErrFuelExhausted) {
+//		// The call ran out of fuel.
+//	}
+//	fmt.Println(fuel)
+type Fuel int64
+
+// FuelKey is a context.Context Value key. Its associated value should be a *Fuel.
+type FuelKey struct{}
+
+// WithFuel returns a context which makes calls consume the given fuel. Without it, the fuel of calls is unlimited.
+//
+// Note: The same Fuel can be passed to several calls, such as the ones made by host functions, to share a budget.
+// However, it must not be consumed by concurrent calls.
+func WithFuel(ctx context.Context, fuel *Fuel) context.Context {
+	return context.WithValue(ctx, FuelKey{}, fuel)
+}
+
+// ErrFuelExhausted is the error of a call which ran out of Fuel. The remaining Fuel is then less than the cost of the
+// instructions which couldn't execute.
+var ErrFuelExhausted = wasmruntime.ErrRuntimeFuelExhausted
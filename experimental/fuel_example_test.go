@@ -0,0 +1,47 @@
+package experimental_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/experimental"
+)
+
+// This is a basic example of bounding calls with WithFuel. The main goal is to show how it is configured.
+func Example_withFuel() {
+	ctx := context.Background()
+
+	// Each instruction costs one fuel.
+	r := wazero.NewRuntimeWithConfig(wazero.NewRuntimeConfig().
+		WithFuelCosts(func(instruction string) uint32 { return 1 }))
+	defer r.Close(ctx) // This closes everything this Runtime created.
+
+	mod, err := r.InstantiateModuleFromCode(ctx, []byte(`(module
+  (func $add (param i32 i32) (result i32) local.get 0 local.get 1 i32.add)
+  (export "add" (func $add))
+)`))
+	if err != nil {
+		log.Panicln(err)
+	}
+	add := mod.ExportedFunction("add")
+
+	// Call add in context of a budget, which is enough for its four instructions, including the implicit end.
+	fuel := experimental.Fuel(6)
+	results, err := add.Call(experimental.WithFuel(ctx, &fuel), 1, 2)
+	if err != nil {
+		log.Panicln(err)
+	}
+	fmt.Println(results[0], fuel)
+
+	// The remaining fuel isn't enough to call add again.
+	if _, err = add.Call(experimental.WithFuel(ctx, &fuel), 1, 2); errors.Is(err, experimental.ErrFuelExhausted) {
+		fmt.Println("fuel exhausted", fuel)
+	}
+
+	// Output:
+	// 3 2
+	// fuel exhausted 2
+}
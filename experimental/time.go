@@ -10,3 +10,11 @@ import (
 func WithTimeNowUnixNano(ctx context.Context, timeUnixNano func() uint64) context.Context {
 	return context.WithValue(ctx, sys.TimeNowUnixNanoKey{}, timeUnixNano)
 }
+
+// WithNanosleep allows you to control how functions such as WASI poll_oneoff wait, otherwise a time.Timer. This is
+// typically paired with WithTimeNowUnixNano, so that a fake clock advances when a guest sleeps.
+//
+// The nanosleep function blocks for ns nanoseconds or until ctx is done, whichever comes first.
+func WithNanosleep(ctx context.Context, nanosleep func(ctx context.Context, ns int64)) context.Context {
+	return context.WithValue(ctx, sys.NanosleepKey{}, nanosleep)
+}
@@ -319,6 +319,220 @@ const (
 	ADDPS
 	// ADDPD is the ADDPD instruction. https://www.felixcloutier.com/x86/addpd
 	ADDPD
+	// PSUBB is the PSUBB instruction. https://www.felixcloutier.com/x86/psubb:psubw:psubd
+	PSUBB
+	// PSUBW is the PSUBW instruction. https://www.felixcloutier.com/x86/psubb:psubw:psubd
+	PSUBW
+	// PSUBL is the PSUBD instruction. https://www.felixcloutier.com/x86/psubb:psubw:psubd
+	PSUBL
+	// PSUBQ is the PSUBQ instruction. https://www.felixcloutier.com/x86/psubq
+	PSUBQ
+	// SUBPS is the SUBPS instruction. https://www.felixcloutier.com/x86/subps
+	SUBPS
+	// SUBPD is the SUBPD instruction. https://www.felixcloutier.com/x86/subpd
+	SUBPD
+	// PAND is the PAND instruction. https://www.felixcloutier.com/x86/pand
+	PAND
+	// PANDN is the PANDN instruction. https://www.felixcloutier.com/x86/pandn
+	PANDN
+	// POR is the POR instruction. https://www.felixcloutier.com/x86/por
+	POR
+	// PXOR is the PXOR instruction. https://www.felixcloutier.com/x86/pxor
+	PXOR
+	// ANDNPS is the ANDNPS instruction. https://www.felixcloutier.com/x86/andnps
+	ANDNPS
+	// ANDNPD is the ANDNPD instruction. https://www.felixcloutier.com/x86/andnpd
+	ANDNPD
+	// PSHUFB is the PSHUFB instruction. https://www.felixcloutier.com/x86/pshufb
+	PSHUFB
+	// PSHUFD is the PSHUFD instruction. https://www.felixcloutier.com/x86/pshufd
+	PSHUFD
+	// SHUFPS is the SHUFPS instruction. https://www.felixcloutier.com/x86/shufps
+	SHUFPS
+	// UNPCKLPS is the UNPCKLPS instruction. https://www.felixcloutier.com/x86/unpcklps
+	UNPCKLPS
+	// PUNPCKLBW is the PUNPCKLBW instruction. https://www.felixcloutier.com/x86/punpcklbw:punpcklwd:punpckldq:punpcklqdq
+	PUNPCKLBW
+	// PUNPCKHBW is the PUNPCKHBW instruction. https://www.felixcloutier.com/x86/punpckhbw:punpckhwd:punpckhdq:punpckhqdq
+	PUNPCKHBW
+	// PINSRB is the PINSRB instruction. https://www.felixcloutier.com/x86/pinsrb:pinsrd:pinsrq
+	PINSRB
+	// PINSRW is the PINSRW instruction. https://www.felixcloutier.com/x86/pinsrw
+	PINSRW
+	// PINSRD is the PINSRD instruction. https://www.felixcloutier.com/x86/pinsrb:pinsrd:pinsrq
+	PINSRD
+	// PEXTRB is the PEXTRB instruction. https://www.felixcloutier.com/x86/pextrb:pextrd:pextrq
+	PEXTRB
+	// PEXTRW is the PEXTRW instruction. https://www.felixcloutier.com/x86/pextrw
+	PEXTRW
+	// PEXTRD is the PEXTRD instruction. https://www.felixcloutier.com/x86/pextrb:pextrd:pextrq
+	PEXTRD
+	// PEXTRQ is the PEXTRQ instruction. https://www.felixcloutier.com/x86/pextrb:pextrd:pextrq
+	PEXTRQ
+	// INSERTPS is the INSERTPS instruction. https://www.felixcloutier.com/x86/insertps
+	INSERTPS
+	// MOVSD is the MOVSD instruction. https://www.felixcloutier.com/x86/movsd
+	MOVSD
+	// MOVLHPS is the MOVLHPS instruction. https://www.felixcloutier.com/x86/movlhps
+	MOVLHPS
+	// PCMPEQB is the PCMPEQB instruction. https://www.felixcloutier.com/x86/pcmpeqb:pcmpeqw:pcmpeqd
+	PCMPEQB
+	// PCMPEQW is the PCMPEQW instruction. https://www.felixcloutier.com/x86/pcmpeqb:pcmpeqw:pcmpeqd
+	PCMPEQW
+	// PCMPEQD is the PCMPEQD instruction. https://www.felixcloutier.com/x86/pcmpeqb:pcmpeqw:pcmpeqd
+	PCMPEQD
+	// PCMPEQQ is the PCMPEQQ instruction. https://www.felixcloutier.com/x86/pcmpeqq
+	PCMPEQQ
+	// PCMPGTB is the PCMPGTB instruction. https://www.felixcloutier.com/x86/pcmpgtb:pcmpgtw:pcmpgtd
+	PCMPGTB
+	// PCMPGTW is the PCMPGTW instruction. https://www.felixcloutier.com/x86/pcmpgtb:pcmpgtw:pcmpgtd
+	PCMPGTW
+	// PCMPGTD is the PCMPGTD instruction. https://www.felixcloutier.com/x86/pcmpgtb:pcmpgtw:pcmpgtd
+	PCMPGTD
+	// PCMPGTQ is the PCMPGTQ instruction. https://www.felixcloutier.com/x86/pcmpgtq
+	PCMPGTQ
+	// PMINSB is the PMINSB instruction. https://www.felixcloutier.com/x86/pminsb:pminsw
+	PMINSB
+	// PMINSW is the PMINSW instruction. https://www.felixcloutier.com/x86/pminsb:pminsw
+	PMINSW
+	// PMINSD is the PMINSD instruction. https://www.felixcloutier.com/x86/pminsd:pminsq
+	PMINSD
+	// PMINUB is the PMINUB instruction. https://www.felixcloutier.com/x86/pminub:pminuw
+	PMINUB
+	// PMINUW is the PMINUW instruction. https://www.felixcloutier.com/x86/pminub:pminuw
+	PMINUW
+	// PMINUD is the PMINUD instruction. https://www.felixcloutier.com/x86/pminud:pminuq
+	PMINUD
+	// PMAXSB is the PMAXSB instruction. https://www.felixcloutier.com/x86/pmaxsb:pmaxsw:pmaxsd:pmaxsq
+	PMAXSB
+	// PMAXSW is the PMAXSW instruction. https://www.felixcloutier.com/x86/pmaxsb:pmaxsw:pmaxsd:pmaxsq
+	PMAXSW
+	// PMAXSD is the PMAXSD instruction. https://www.felixcloutier.com/x86/pmaxsb:pmaxsw:pmaxsd:pmaxsq
+	PMAXSD
+	// PMAXUB is the PMAXUB instruction. https://www.felixcloutier.com/x86/pmaxub:pmaxuw
+	PMAXUB
+	// PMAXUW is the PMAXUW instruction. https://www.felixcloutier.com/x86/pmaxub:pmaxuw
+	PMAXUW
+	// PMAXUD is the PMAXUD instruction. https://www.felixcloutier.com/x86/pmaxud:pmaxuq
+	PMAXUD
+	// CMPPS is the CMPPS instruction. https://www.felixcloutier.com/x86/cmpps
+	CMPPS
+	// CMPPD is the CMPPD instruction. https://www.felixcloutier.com/x86/cmppd
+	CMPPD
+	// PADDSB is the PADDSB instruction. https://www.felixcloutier.com/x86/paddsb:paddsw
+	PADDSB
+	// PADDSW is the PADDSW instruction. https://www.felixcloutier.com/x86/paddsb:paddsw
+	PADDSW
+	// PADDUSB is the PADDUSB instruction. https://www.felixcloutier.com/x86/paddusb:paddusw
+	PADDUSB
+	// PADDUSW is the PADDUSW instruction. https://www.felixcloutier.com/x86/paddusb:paddusw
+	PADDUSW
+	// PSUBSB is the PSUBSB instruction. https://www.felixcloutier.com/x86/psubsb:psubsw
+	PSUBSB
+	// PSUBSW is the PSUBSW instruction. https://www.felixcloutier.com/x86/psubsb:psubsw
+	PSUBSW
+	// PSUBUSB is the PSUBUSB instruction. https://www.felixcloutier.com/x86/psubusb:psubusw
+	PSUBUSB
+	// PSUBUSW is the PSUBUSW instruction. https://www.felixcloutier.com/x86/psubusb:psubusw
+	PSUBUSW
+	// PMULLW is the PMULLW instruction. https://www.felixcloutier.com/x86/pmullw
+	PMULLW
+	// PMULLD is the PMULLD instruction. https://www.felixcloutier.com/x86/pmulld:pmullq
+	PMULLD
+	// PMULUDQ is the PMULUDQ instruction. https://www.felixcloutier.com/x86/pmuludq
+	PMULUDQ
+	// PMULDQ is the PMULDQ instruction. https://www.felixcloutier.com/x86/pmuldq
+	PMULDQ
+	// PMULHRSW is the PMULHRSW instruction. https://www.felixcloutier.com/x86/pmulhrsw
+	PMULHRSW
+	// PMADDWD is the PMADDWD instruction. https://www.felixcloutier.com/x86/pmaddwd
+	PMADDWD
+	// PMADDUBSW is the PMADDUBSW instruction. https://www.felixcloutier.com/x86/pmaddubsw
+	PMADDUBSW
+	// MULPS is the MULPS instruction. https://www.felixcloutier.com/x86/mulps
+	MULPS
+	// MULPD is the MULPD instruction. https://www.felixcloutier.com/x86/mulpd
+	MULPD
+	// DIVPS is the DIVPS instruction. https://www.felixcloutier.com/x86/divps
+	DIVPS
+	// DIVPD is the DIVPD instruction. https://www.felixcloutier.com/x86/divpd
+	DIVPD
+	// SQRTPS is the SQRTPS instruction. https://www.felixcloutier.com/x86/sqrtps
+	SQRTPS
+	// SQRTPD is the SQRTPD instruction. https://www.felixcloutier.com/x86/sqrtpd
+	SQRTPD
+	// MINPS is the MINPS instruction. https://www.felixcloutier.com/x86/minps
+	MINPS
+	// MINPD is the MINPD instruction. https://www.felixcloutier.com/x86/minpd
+	MINPD
+	// MAXPS is the MAXPS instruction. https://www.felixcloutier.com/x86/maxps
+	MAXPS
+	// MAXPD is the MAXPD instruction. https://www.felixcloutier.com/x86/maxpd
+	MAXPD
+	// PABSB is the PABSB instruction. https://www.felixcloutier.com/x86/pabsb:pabsw:pabsd:pabsq
+	PABSB
+	// PABSW is the PABSW instruction. https://www.felixcloutier.com/x86/pabsb:pabsw:pabsd:pabsq
+	PABSW
+	// PABSD is the PABSD instruction. https://www.felixcloutier.com/x86/pabsb:pabsw:pabsd:pabsq
+	PABSD
+	// PAVGB is the PAVGB instruction. https://www.felixcloutier.com/x86/pavgb:pavgw
+	PAVGB
+	// PAVGW is the PAVGW instruction. https://www.felixcloutier.com/x86/pavgb:pavgw
+	PAVGW
+	// ROUNDPS is the ROUNDPS instruction. https://www.felixcloutier.com/x86/roundps
+	ROUNDPS
+	// ROUNDPD is the ROUNDPD instruction. https://www.felixcloutier.com/x86/roundpd
+	ROUNDPD
+	// PMOVSXBW is the PMOVSXBW instruction. https://www.felixcloutier.com/x86/pmovsx
+	PMOVSXBW
+	// PMOVSXWD is the PMOVSXWD instruction. https://www.felixcloutier.com/x86/pmovsx
+	PMOVSXWD
+	// PMOVSXDQ is the PMOVSXDQ instruction. https://www.felixcloutier.com/x86/pmovsx
+	PMOVSXDQ
+	// PMOVZXBW is the PMOVZXBW instruction. https://www.felixcloutier.com/x86/pmovzx
+	PMOVZXBW
+	// PMOVZXWD is the PMOVZXWD instruction. https://www.felixcloutier.com/x86/pmovzx
+	PMOVZXWD
+	// PMOVZXDQ is the PMOVZXDQ instruction. https://www.felixcloutier.com/x86/pmovzx
+	PMOVZXDQ
+	// CVTPS2PD is the CVTPS2PD instruction. https://www.felixcloutier.com/x86/cvtps2pd
+	CVTPS2PD
+	// CVTPD2PS is the CVTPD2PS instruction. https://www.felixcloutier.com/x86/cvtpd2ps
+	CVTPD2PS
+	// CVTDQ2PS is the CVTDQ2PS instruction. https://www.felixcloutier.com/x86/cvtdq2ps
+	CVTDQ2PS
+	// CVTDQ2PD is the CVTDQ2PD instruction. https://www.felixcloutier.com/x86/cvtdq2pd
+	CVTDQ2PD
+	// CVTTPS2DQ is the CVTTPS2DQ instruction. https://www.felixcloutier.com/x86/cvttps2dq
+	CVTTPS2DQ
+	// CVTTPD2DQ is the CVTTPD2DQ instruction. https://www.felixcloutier.com/x86/cvttpd2dq
+	CVTTPD2DQ
+	// PACKSSWB is the PACKSSWB instruction. https://www.felixcloutier.com/x86/packsswb:packssdw
+	PACKSSWB
+	// PACKSSDW is the PACKSSDW instruction. https://www.felixcloutier.com/x86/packsswb:packssdw
+	PACKSSDW
+	// PACKUSWB is the PACKUSWB instruction. https://www.felixcloutier.com/x86/packuswb
+	PACKUSWB
+	// PACKUSDW is the PACKUSDW instruction. https://www.felixcloutier.com/x86/packusdw
+	PACKUSDW
+	// PMOVMSKB is the PMOVMSKB instruction. https://www.felixcloutier.com/x86/pmovmskb
+	PMOVMSKB
+	// MOVMSKPS is the MOVMSKPS instruction. https://www.felixcloutier.com/x86/movmskps
+	MOVMSKPS
+	// MOVMSKPD is the MOVMSKPD instruction. https://www.felixcloutier.com/x86/movmskpd
+	MOVMSKPD
+	// PTEST is the PTEST instruction. https://www.felixcloutier.com/x86/ptest
+	PTEST
+	// PSLLW is the PSLLW instruction. https://www.felixcloutier.com/x86/psllw:pslld:psllq
+	PSLLW
+	// PSRLW is the PSRLW instruction. https://www.felixcloutier.com/x86/psrlw:psrld:psrlq
+	PSRLW
+	// PSRAW is the PSRAW instruction. https://www.felixcloutier.com/x86/psraw:psrad:psraq
+	PSRAW
+	// PSRAL is the PSRAD instruction. https://www.felixcloutier.com/x86/psraw:psrad:psraq
+	PSRAL
+	// PSRLDQ is the PSRLDQ instruction. https://www.felixcloutier.com/x86/psrldq
+	PSRLDQ
 )
 
 // InstructionName returns the name for an instruction
@@ -600,6 +814,220 @@ func InstructionName(instruction asm.Instruction) string {
 		return "ADDPS"
 	case ADDPD:
 		return "ADDPD"
+	case PSUBB:
+		return "PSUBB"
+	case PSUBW:
+		return "PSUBW"
+	case PSUBL:
+		return "PSUBL"
+	case PSUBQ:
+		return "PSUBQ"
+	case SUBPS:
+		return "SUBPS"
+	case SUBPD:
+		return "SUBPD"
+	case PAND:
+		return "PAND"
+	case PANDN:
+		return "PANDN"
+	case POR:
+		return "POR"
+	case PXOR:
+		return "PXOR"
+	case ANDNPS:
+		return "ANDNPS"
+	case ANDNPD:
+		return "ANDNPD"
+	case PSHUFB:
+		return "PSHUFB"
+	case PSHUFD:
+		return "PSHUFD"
+	case SHUFPS:
+		return "SHUFPS"
+	case UNPCKLPS:
+		return "UNPCKLPS"
+	case PUNPCKLBW:
+		return "PUNPCKLBW"
+	case PUNPCKHBW:
+		return "PUNPCKHBW"
+	case PINSRB:
+		return "PINSRB"
+	case PINSRW:
+		return "PINSRW"
+	case PINSRD:
+		return "PINSRD"
+	case PEXTRB:
+		return "PEXTRB"
+	case PEXTRW:
+		return "PEXTRW"
+	case PEXTRD:
+		return "PEXTRD"
+	case PEXTRQ:
+		return "PEXTRQ"
+	case INSERTPS:
+		return "INSERTPS"
+	case MOVSD:
+		return "MOVSD"
+	case MOVLHPS:
+		return "MOVLHPS"
+	case PCMPEQB:
+		return "PCMPEQB"
+	case PCMPEQW:
+		return "PCMPEQW"
+	case PCMPEQD:
+		return "PCMPEQD"
+	case PCMPEQQ:
+		return "PCMPEQQ"
+	case PCMPGTB:
+		return "PCMPGTB"
+	case PCMPGTW:
+		return "PCMPGTW"
+	case PCMPGTD:
+		return "PCMPGTD"
+	case PCMPGTQ:
+		return "PCMPGTQ"
+	case PMINSB:
+		return "PMINSB"
+	case PMINSW:
+		return "PMINSW"
+	case PMINSD:
+		return "PMINSD"
+	case PMINUB:
+		return "PMINUB"
+	case PMINUW:
+		return "PMINUW"
+	case PMINUD:
+		return "PMINUD"
+	case PMAXSB:
+		return "PMAXSB"
+	case PMAXSW:
+		return "PMAXSW"
+	case PMAXSD:
+		return "PMAXSD"
+	case PMAXUB:
+		return "PMAXUB"
+	case PMAXUW:
+		return "PMAXUW"
+	case PMAXUD:
+		return "PMAXUD"
+	case CMPPS:
+		return "CMPPS"
+	case CMPPD:
+		return "CMPPD"
+	case PADDSB:
+		return "PADDSB"
+	case PADDSW:
+		return "PADDSW"
+	case PADDUSB:
+		return "PADDUSB"
+	case PADDUSW:
+		return "PADDUSW"
+	case PSUBSB:
+		return "PSUBSB"
+	case PSUBSW:
+		return "PSUBSW"
+	case PSUBUSB:
+		return "PSUBUSB"
+	case PSUBUSW:
+		return "PSUBUSW"
+	case PMULLW:
+		return "PMULLW"
+	case PMULLD:
+		return "PMULLD"
+	case PMULUDQ:
+		return "PMULUDQ"
+	case PMULDQ:
+		return "PMULDQ"
+	case PMULHRSW:
+		return "PMULHRSW"
+	case PMADDWD:
+		return "PMADDWD"
+	case PMADDUBSW:
+		return "PMADDUBSW"
+	case MULPS:
+		return "MULPS"
+	case MULPD:
+		return "MULPD"
+	case DIVPS:
+		return "DIVPS"
+	case DIVPD:
+		return "DIVPD"
+	case SQRTPS:
+		return "SQRTPS"
+	case SQRTPD:
+		return "SQRTPD"
+	case MINPS:
+		return "MINPS"
+	case MINPD:
+		return "MINPD"
+	case MAXPS:
+		return "MAXPS"
+	case MAXPD:
+		return "MAXPD"
+	case PABSB:
+		return "PABSB"
+	case PABSW:
+		return "PABSW"
+	case PABSD:
+		return "PABSD"
+	case PAVGB:
+		return "PAVGB"
+	case PAVGW:
+		return "PAVGW"
+	case ROUNDPS:
+		return "ROUNDPS"
+	case ROUNDPD:
+		return "ROUNDPD"
+	case PMOVSXBW:
+		return "PMOVSXBW"
+	case PMOVSXWD:
+		return "PMOVSXWD"
+	case PMOVSXDQ:
+		return "PMOVSXDQ"
+	case PMOVZXBW:
+		return "PMOVZXBW"
+	case PMOVZXWD:
+		return "PMOVZXWD"
+	case PMOVZXDQ:
+		return "PMOVZXDQ"
+	case CVTPS2PD:
+		return "CVTPS2PD"
+	case CVTPD2PS:
+		return "CVTPD2PS"
+	case CVTDQ2PS:
+		return "CVTDQ2PS"
+	case CVTDQ2PD:
+		return "CVTDQ2PD"
+	case CVTTPS2DQ:
+		return "CVTTPS2DQ"
+	case CVTTPD2DQ:
+		return "CVTTPD2DQ"
+	case PACKSSWB:
+		return "PACKSSWB"
+	case PACKSSDW:
+		return "PACKSSDW"
+	case PACKUSWB:
+		return "PACKUSWB"
+	case PACKUSDW:
+		return "PACKUSDW"
+	case PMOVMSKB:
+		return "PMOVMSKB"
+	case MOVMSKPS:
+		return "MOVMSKPS"
+	case MOVMSKPD:
+		return "MOVMSKPD"
+	case PTEST:
+		return "PTEST"
+	case PSLLW:
+		return "PSLLW"
+	case PSRLW:
+		return "PSRLW"
+	case PSRAW:
+		return "PSRAW"
+	case PSRAL:
+		return "PSRAL"
+	case PSRLDQ:
+		return "PSRLDQ"
 	}
 	return "Unknown"
 }
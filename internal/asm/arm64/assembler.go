@@ -66,13 +66,39 @@ type Assembler interface {
 	// CompileConditionalRegisterSet adds an instruction to set 1 on dstReg if the condition satisfies,
 	// otherwise set 0.
 	CompileConditionalRegisterSet(cond asm.ConditionalRegisterState, dstReg asm.Register)
-	// CompileMemoryToVectorRegister TODO
+	// CompileMemoryToVectorRegister adds an instruction where source operand is the memory address specified by `srcOffsetReg`,
+	// and the destination is the vector register `dstReg` with the given arrangement.
 	CompileMemoryToVectorRegister(instruction asm.Instruction, srcOffsetReg, dstReg asm.Register, arrangement VectorArrangement)
-	// CompileVectorRegisterToMemory TODO
+
+	// CompileVectorRegisterToMemory adds an instruction where source operand is the vector register `srcReg` with the given
+	// arrangement, and the destination is the memory address specified by `dstOffsetReg`.
 	CompileVectorRegisterToMemory(instruction asm.Instruction, srcReg, dstOffsetReg asm.Register, arrangement VectorArrangement)
-	// CompileRegisterToVectorRegister TODO
+
+	// CompileRegisterToVectorRegister adds an instruction where source operand is the general purpose register `srcReg`,
+	// and the destination is the vector register `dstReg` with the arrangement. `index` is the element index of the
+	// destination, or VectorIndexNone if the instruction writes all the elements (e.g. VDUP).
 	CompileRegisterToVectorRegister(instruction asm.Instruction, srcReg, dstReg asm.Register,
 		arrangement VectorArrangement, index VectorIndex)
-	// CompileVectorRegisterToVectorRegister TODO
-	CompileVectorRegisterToVectorRegister(instruction asm.Instruction, srcReg, dstReg asm.Register, arrangement VectorArrangement)
+
+	// CompileVectorRegisterToRegister adds an instruction where source operand is the element at `index` of the vector
+	// register `srcReg`, and the destination is the general purpose register `dstReg`.
+	CompileVectorRegisterToRegister(instruction asm.Instruction, srcReg, dstReg asm.Register,
+		arrangement VectorArrangement, index VectorIndex)
+
+	// CompileVectorRegisterToVectorRegister adds an instruction where both source and destination operands are vector
+	// registers. `srcIndex` and `dstIndex` are the element indexes for the instructions which operate on a single element
+	// (e.g. VINS), and must be VectorIndexNone otherwise.
+	CompileVectorRegisterToVectorRegister(instruction asm.Instruction, srcReg, dstReg asm.Register,
+		arrangement VectorArrangement, srcIndex, dstIndex VectorIndex)
+
+	// CompileVectorRegisterToVectorRegisterWithConst is the same as CompileVectorRegisterToVectorRegister, but takes
+	// the constant operand `c` (e.g. the shift amount of VSSHR) instead of the element indexes.
+	CompileVectorRegisterToVectorRegisterWithConst(instruction asm.Instruction, srcReg, dstReg asm.Register,
+		arrangement VectorArrangement, c asm.ConstantValue)
+
+	// CompileTwoVectorRegistersToVectorRegister adds an instruction where source operands are the vector registers
+	// `srcReg` and `srcReg2`, and the destination is the vector register `dstReg`. For example, `VSUB` results in
+	// "dstReg = srcReg - srcReg2".
+	CompileTwoVectorRegistersToVectorRegister(instruction asm.Instruction, srcReg, srcReg2, dstReg asm.Register,
+		arrangement VectorArrangement)
 }
@@ -696,6 +696,146 @@ const (
 	VFADDS
 	// VFADDD is the FADD instruction, for double precision. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FADD--vector-
 	VFADDD
+	// VSUB is the SUB instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SUB--vector-
+	VSUB
+	// VSQADD is the SQADD instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SQADD--vector-
+	VSQADD
+	// VUQADD is the UQADD instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/UQADD--vector-
+	VUQADD
+	// VSQSUB is the SQSUB instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SQSUB--vector-
+	VSQSUB
+	// VUQSUB is the UQSUB instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/UQSUB--vector-
+	VUQSUB
+	// VCMEQ is the CMEQ (register) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/CMEQ--vector--register-
+	VCMEQ
+	// VCMEQZ is the CMEQ (zero) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/CMEQ--vector--zero-
+	VCMEQZ
+	// VCMGT is the CMGT (register) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/CMGT--vector--register-
+	VCMGT
+	// VCMHI is the CMHI (register) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/CMHI--vector--register-
+	VCMHI
+	// VCMGE is the CMGE (register) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/CMGE--vector--register-
+	VCMGE
+	// VCMHS is the CMHS (register) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/CMHS--vector--register-
+	VCMHS
+	// VSSHL is the SSHL instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SSHL--vector-
+	VSSHL
+	// VUSHL is the USHL instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/USHL--vector-
+	VUSHL
+	// VSMAX is the SMAX instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SMAX--vector-
+	VSMAX
+	// VUMAX is the UMAX instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/UMAX--vector-
+	VUMAX
+	// VSMIN is the SMIN instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SMIN--vector-
+	VSMIN
+	// VUMIN is the UMIN instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/UMIN--vector-
+	VUMIN
+	// VURHADD is the URHADD instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/URHADD--vector-
+	VURHADD
+	// VMUL is the MUL instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/MUL--vector-
+	VMUL
+	// VADDP is the ADDP instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/ADDP--vector-
+	VADDP
+	// VSQRDMULH is the SQRDMULH instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SQRDMULH--vector-
+	VSQRDMULH
+	// VAND is the AND instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/AND--vector-
+	VAND
+	// VBIC is the BIC (register) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/BIC--vector--register-
+	VBIC
+	// VORR is the ORR (register) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/ORR--vector--register-
+	VORR
+	// VEOR is the EOR instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/EOR--vector-
+	VEOR
+	// VNOT is the NOT instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/NOT--vector-
+	VNOT
+	// VNEG is the NEG instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/NEG--vector-
+	VNEG
+	// VABS is the ABS instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/ABS--vector-
+	VABS
+	// VSADDLP is the SADDLP instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SADDLP--vector-
+	VSADDLP
+	// VUADDLP is the UADDLP instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/UADDLP--vector-
+	VUADDLP
+	// VSQXTN is the SQXTN instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SQXTN--SQXTN2--vector-
+	VSQXTN
+	// VSQXTUN is the SQXTUN instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SQXTUN--SQXTUN2--vector-
+	VSQXTUN
+	// VUQXTN is the UQXTN instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/UQXTN--UQXTN2--vector-
+	VUQXTN
+	// VUMINV is the UMINV instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/UMINV--vector-
+	VUMINV
+	// VUMAXV is the UMAXV instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/UMAXV--vector-
+	VUMAXV
+	// VADDV is the ADDV instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/ADDV--vector-
+	VADDV
+	// VSSHR is the SSHR instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SSHR--vector-
+	VSSHR
+	// VUSHR is the USHR instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/USHR--vector-
+	VUSHR
+	// VSHL is the SHL instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SHL--vector-
+	VSHL
+	// VSSHLL is the SSHLL instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SSHLL--SSHLL2--vector-
+	VSSHLL
+	// VUSHLL is the USHLL instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/USHLL--USHLL2--vector-
+	VUSHLL
+	// VSMULL is the SMULL instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SMULL--SMULL2--vector-
+	VSMULL
+	// VUMULL is the UMULL instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/UMULL--UMULL2--vector-
+	VUMULL
+	// VDUP is the DUP instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/DUP--vector--general-
+	VDUP
+	// VDUPELEM is the DUP (element) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/DUP--vector--element-
+	VDUPELEM
+	// VINS is the INS (element) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/INS--vector--element-
+	VINS
+	// VUMOV is the UMOV instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/UMOV--vector-
+	VUMOV
+	// VSMOV is the SMOV instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SMOV--vector-
+	VSMOV
+	// VTBL is the TBL instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/TBL--vector-
+	VTBL
+	// VFSUB is the FSUB instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FSUB--vector-
+	VFSUB
+	// VFMUL is the FMUL instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FMUL--vector-
+	VFMUL
+	// VFDIV is the FDIV instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FDIV--vector-
+	VFDIV
+	// VFMAX is the FMAX instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FMAX--vector-
+	VFMAX
+	// VFMIN is the FMIN instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FMIN--vector-
+	VFMIN
+	// VFCMEQ is the FCMEQ (register) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FCMEQ--vector--register-
+	VFCMEQ
+	// VFCMGE is the FCMGE (register) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FCMGE--vector--register-
+	VFCMGE
+	// VFCMGT is the FCMGT (register) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FCMGT--vector--register-
+	VFCMGT
+	// VFABS is the FABS instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FABS--vector-
+	VFABS
+	// VFNEG is the FNEG instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FNEG--vector-
+	VFNEG
+	// VFSQRT is the FSQRT instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FSQRT--vector-
+	VFSQRT
+	// VFRINTN is the FRINTN instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FRINTN--vector-
+	VFRINTN
+	// VFRINTM is the FRINTM instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FRINTM--vector-
+	VFRINTM
+	// VFRINTP is the FRINTP instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FRINTP--vector-
+	VFRINTP
+	// VFRINTZ is the FRINTZ instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FRINTZ--vector-
+	VFRINTZ
+	// VSCVTF is the SCVTF (integer) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/SCVTF--vector--integer-
+	VSCVTF
+	// VUCVTF is the UCVTF (integer) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/UCVTF--vector--integer-
+	VUCVTF
+	// VFCVTZS is the FCVTZS (integer) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FCVTZS--vector--integer-
+	VFCVTZS
+	// VFCVTZU is the FCVTZU (integer) instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FCVTZU--vector--integer-
+	VFCVTZU
+	// VFCVTL is the FCVTL instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FCVTL--FCVTL2--vector-
+	VFCVTL
+	// VFCVTN is the FCVTN instruction. https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/FCVTN--FCVTN2--vector-
+	VFCVTN
 )
 
 // VectorArrangement is the arrangement of data within a vector register.
@@ -771,6 +911,9 @@ func (v VectorArrangement) String() (ret string) {
 // VectorIndex is the index of an element of a vector register
 type VectorIndex byte
 
+// VectorIndexNone is the VectorIndex which indicates that the instruction doesn't use the vector index.
+const VectorIndexNone = ^VectorIndex(0)
+
 // InstructionName returns the name of the given instruction
 func InstructionName(i asm.Instruction) string {
 	switch i {
@@ -1024,6 +1167,146 @@ func InstructionName(i asm.Instruction) string {
 		return "VFADDS"
 	case VFADDD:
 		return "VFADDD"
+	case VSUB:
+		return "VSUB"
+	case VSQADD:
+		return "VSQADD"
+	case VUQADD:
+		return "VUQADD"
+	case VSQSUB:
+		return "VSQSUB"
+	case VUQSUB:
+		return "VUQSUB"
+	case VCMEQ:
+		return "VCMEQ"
+	case VCMEQZ:
+		return "VCMEQZ"
+	case VCMGT:
+		return "VCMGT"
+	case VCMHI:
+		return "VCMHI"
+	case VCMGE:
+		return "VCMGE"
+	case VCMHS:
+		return "VCMHS"
+	case VSSHL:
+		return "VSSHL"
+	case VUSHL:
+		return "VUSHL"
+	case VSMAX:
+		return "VSMAX"
+	case VUMAX:
+		return "VUMAX"
+	case VSMIN:
+		return "VSMIN"
+	case VUMIN:
+		return "VUMIN"
+	case VURHADD:
+		return "VURHADD"
+	case VMUL:
+		return "VMUL"
+	case VADDP:
+		return "VADDP"
+	case VSQRDMULH:
+		return "VSQRDMULH"
+	case VAND:
+		return "VAND"
+	case VBIC:
+		return "VBIC"
+	case VORR:
+		return "VORR"
+	case VEOR:
+		return "VEOR"
+	case VNOT:
+		return "VNOT"
+	case VNEG:
+		return "VNEG"
+	case VABS:
+		return "VABS"
+	case VSADDLP:
+		return "VSADDLP"
+	case VUADDLP:
+		return "VUADDLP"
+	case VSQXTN:
+		return "VSQXTN"
+	case VSQXTUN:
+		return "VSQXTUN"
+	case VUQXTN:
+		return "VUQXTN"
+	case VUMINV:
+		return "VUMINV"
+	case VUMAXV:
+		return "VUMAXV"
+	case VADDV:
+		return "VADDV"
+	case VSSHR:
+		return "VSSHR"
+	case VUSHR:
+		return "VUSHR"
+	case VSHL:
+		return "VSHL"
+	case VSSHLL:
+		return "VSSHLL"
+	case VUSHLL:
+		return "VUSHLL"
+	case VSMULL:
+		return "VSMULL"
+	case VUMULL:
+		return "VUMULL"
+	case VDUP:
+		return "VDUP"
+	case VDUPELEM:
+		return "VDUPELEM"
+	case VINS:
+		return "VINS"
+	case VUMOV:
+		return "VUMOV"
+	case VSMOV:
+		return "VSMOV"
+	case VTBL:
+		return "VTBL"
+	case VFSUB:
+		return "VFSUB"
+	case VFMUL:
+		return "VFMUL"
+	case VFDIV:
+		return "VFDIV"
+	case VFMAX:
+		return "VFMAX"
+	case VFMIN:
+		return "VFMIN"
+	case VFCMEQ:
+		return "VFCMEQ"
+	case VFCMGE:
+		return "VFCMGE"
+	case VFCMGT:
+		return "VFCMGT"
+	case VFABS:
+		return "VFABS"
+	case VFNEG:
+		return "VFNEG"
+	case VFSQRT:
+		return "VFSQRT"
+	case VFRINTN:
+		return "VFRINTN"
+	case VFRINTM:
+		return "VFRINTM"
+	case VFRINTP:
+		return "VFRINTP"
+	case VFRINTZ:
+		return "VFRINTZ"
+	case VSCVTF:
+		return "VSCVTF"
+	case VUCVTF:
+		return "VUCVTF"
+	case VFCVTZS:
+		return "VFCVTZS"
+	case VFCVTZU:
+		return "VFCVTZU"
+	case VFCVTL:
+		return "VFCVTL"
+	case VFCVTN:
+		return "VFCVTN"
 	}
 	return "UNKNOWN"
 }
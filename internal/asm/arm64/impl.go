@@ -26,7 +26,9 @@ type NodeImpl struct {
 	SrcConst, DstConst               asm.ConstantValue
 
 	VectorArrangement VectorArrangement
-	VectorIndex       VectorIndex
+	// SrcVectorIndex and DstVectorIndex are the element indexes of the source and destination vector registers
+	// respectively, or VectorIndexNone if the instruction doesn't operate on a single element.
+	SrcVectorIndex, DstVectorIndex VectorIndex
 
 	// readInstructionAddressBeforeTargetInstruction holds the instruction right before the target of
 	// read instruction address instruction. See asm.assemblerBase.CompileReadInstructionAddress.
@@ -106,13 +108,31 @@ func (n *NodeImpl) String() (ret string) {
 	case OperandTypesTwoSIMDBytesToSIMDByteRegister:
 		ret = fmt.Sprintf("%s (%s.B8, %s.B8), %s.B8", instName, RegisterName(n.SrcReg), RegisterName(n.SrcReg2), RegisterName(n.DstReg))
 	case OperandTypesRegisterToVectorRegister:
-		ret = fmt.Sprintf("%s %s, %s.%s[%d]", instName, RegisterName(n.SrcReg), RegisterName(n.DstReg), n.VectorArrangement, n.VectorIndex)
+		if n.DstVectorIndex != VectorIndexNone {
+			ret = fmt.Sprintf("%s %s, %s.%s[%d]", instName, RegisterName(n.SrcReg), RegisterName(n.DstReg), n.VectorArrangement, n.DstVectorIndex)
+		} else {
+			ret = fmt.Sprintf("%s %s, %s.%s", instName, RegisterName(n.SrcReg), RegisterName(n.DstReg), n.VectorArrangement)
+		}
+	case OperandTypesVectorRegisterToRegister:
+		ret = fmt.Sprintf("%s %s.%s[%d], %s", instName, RegisterName(n.SrcReg), n.VectorArrangement, n.SrcVectorIndex, RegisterName(n.DstReg))
 	case OperandTypesVectorRegisterToMemory:
 		ret = fmt.Sprintf("%s %s.%s, [%s]", instName, RegisterName(n.SrcReg), n.VectorArrangement, RegisterName(n.DstReg))
 	case OperandTypesMemoryToVectorRegister:
 		ret = fmt.Sprintf("%s [%s], %s.%s", instName, RegisterName(n.SrcReg), RegisterName(n.DstReg), n.VectorArrangement)
 	case OperandTypesVectorRegisterToVectorRegister:
-		ret = fmt.Sprintf("%s %s.%[2]s, %s.%[2]s", instName, RegisterName(n.SrcReg), RegisterName(n.DstReg), n.VectorArrangement)
+		src, dst := fmt.Sprintf("%s.%s", RegisterName(n.SrcReg), n.VectorArrangement), fmt.Sprintf("%s.%s", RegisterName(n.DstReg), n.VectorArrangement)
+		if n.SrcVectorIndex != VectorIndexNone {
+			src += fmt.Sprintf("[%d]", n.SrcVectorIndex)
+		}
+		if n.DstVectorIndex != VectorIndexNone {
+			dst += fmt.Sprintf("[%d]", n.DstVectorIndex)
+		}
+		ret = fmt.Sprintf("%s %s, %s", instName, src, dst)
+		if n.SrcConst != 0 {
+			ret += fmt.Sprintf(", 0x%x", n.SrcConst)
+		}
+	case OperandTypesTwoVectorRegistersToVectorRegister:
+		ret = fmt.Sprintf("%[1]s (%[2]s.%[5]s, %[3]s.%[5]s), %[4]s.%[5]s", instName, RegisterName(n.SrcReg), RegisterName(n.SrcReg2), RegisterName(n.DstReg), n.VectorArrangement)
 	}
 	return
 }
@@ -134,6 +154,7 @@ const (
 	OperandTypeSIMDByte
 	OperandTypeTwoSIMDBytes
 	OperandTypeVectorRegister
+	OperandTypeTwoVectorRegisters
 )
 
 // String implements fmt.Stringer.
@@ -161,6 +182,8 @@ func (o OperandType) String() (ret string) {
 		ret = "two-simd-bytes"
 	case OperandTypeVectorRegister:
 		ret = "vector-register"
+	case OperandTypeTwoVectorRegisters:
+		ret = "two-vector-registers"
 	}
 	return
 }
@@ -189,6 +212,9 @@ var (
 	OperandTypesMemoryToVectorRegister         = OperandTypes{OperandTypeMemory, OperandTypeVectorRegister}
 	OperandTypesVectorRegisterToMemory         = OperandTypes{OperandTypeVectorRegister, OperandTypeMemory}
 	OperandTypesVectorRegisterToVectorRegister = OperandTypes{OperandTypeVectorRegister, OperandTypeVectorRegister}
+	OperandTypesVectorRegisterToRegister       = OperandTypes{OperandTypeVectorRegister, OperandTypeRegister}
+
+	OperandTypesTwoVectorRegistersToVectorRegister = OperandTypes{OperandTypeTwoVectorRegisters, OperandTypeVectorRegister}
 )
 
 // String implements fmt.Stringer
@@ -399,6 +425,10 @@ func (a *AssemblerImpl) EncodeNode(n *NodeImpl) (err error) {
 		err = a.EncodeVectorRegisterToMemory(n)
 	case OperandTypesVectorRegisterToVectorRegister:
 		err = a.EncodeVectorRegisterToVectorRegister(n)
+	case OperandTypesVectorRegisterToRegister:
+		err = a.EncodeVectorRegisterToRegister(n)
+	case OperandTypesTwoVectorRegistersToVectorRegister:
+		err = a.EncodeTwoVectorRegistersToVectorRegister(n)
 	default:
 		err = fmt.Errorf("encoder undefined for [%s] operand type", n.Types)
 	}
@@ -590,6 +620,7 @@ func (a *AssemblerImpl) CompileConditionalRegisterSet(cond asm.ConditionalRegist
 	n.DstReg = dstReg
 }
 
+// CompileMemoryToVectorRegister implements Assembler.CompileMemoryToVectorRegister
 func (a *AssemblerImpl) CompileMemoryToVectorRegister(
 	instruction asm.Instruction, srcOffsetReg, dstReg asm.Register, arrangement VectorArrangement) {
 	n := a.newNode(instruction, OperandTypesMemoryToVectorRegister)
@@ -598,6 +629,7 @@ func (a *AssemblerImpl) CompileMemoryToVectorRegister(
 	n.VectorArrangement = arrangement
 }
 
+// CompileVectorRegisterToMemory implements Assembler.CompileVectorRegisterToMemory
 func (a *AssemblerImpl) CompileVectorRegisterToMemory(
 	instruction asm.Instruction, srcReg, dstOffsetReg asm.Register, arrangement VectorArrangement) {
 	n := a.newNode(instruction, OperandTypesVectorRegisterToMemory)
@@ -606,21 +638,59 @@ func (a *AssemblerImpl) CompileVectorRegisterToMemory(
 	n.VectorArrangement = arrangement
 }
 
+// CompileRegisterToVectorRegister implements Assembler.CompileRegisterToVectorRegister
 func (a *AssemblerImpl) CompileRegisterToVectorRegister(
 	instruction asm.Instruction, srcReg, dstReg asm.Register, arrangement VectorArrangement, index VectorIndex) {
 	n := a.newNode(instruction, OperandTypesRegisterToVectorRegister)
 	n.SrcReg = srcReg
 	n.DstReg = dstReg
 	n.VectorArrangement = arrangement
-	n.VectorIndex = index
+	n.SrcVectorIndex = VectorIndexNone
+	n.DstVectorIndex = index
+}
+
+// CompileVectorRegisterToRegister implements Assembler.CompileVectorRegisterToRegister
+func (a *AssemblerImpl) CompileVectorRegisterToRegister(
+	instruction asm.Instruction, srcReg, dstReg asm.Register, arrangement VectorArrangement, index VectorIndex) {
+	n := a.newNode(instruction, OperandTypesVectorRegisterToRegister)
+	n.SrcReg = srcReg
+	n.DstReg = dstReg
+	n.VectorArrangement = arrangement
+	n.SrcVectorIndex = index
+	n.DstVectorIndex = VectorIndexNone
 }
 
+// CompileVectorRegisterToVectorRegister implements Assembler.CompileVectorRegisterToVectorRegister
 func (a *AssemblerImpl) CompileVectorRegisterToVectorRegister(
-	instruction asm.Instruction, srcReg, dstReg asm.Register, arrangement VectorArrangement) {
+	instruction asm.Instruction, srcReg, dstReg asm.Register, arrangement VectorArrangement, srcIndex, dstIndex VectorIndex) {
 	n := a.newNode(instruction, OperandTypesVectorRegisterToVectorRegister)
 	n.SrcReg = srcReg
 	n.DstReg = dstReg
 	n.VectorArrangement = arrangement
+	n.SrcVectorIndex = srcIndex
+	n.DstVectorIndex = dstIndex
+}
+
+// CompileVectorRegisterToVectorRegisterWithConst implements Assembler.CompileVectorRegisterToVectorRegisterWithConst
+func (a *AssemblerImpl) CompileVectorRegisterToVectorRegisterWithConst(
+	instruction asm.Instruction, srcReg, dstReg asm.Register, arrangement VectorArrangement, c asm.ConstantValue) {
+	n := a.newNode(instruction, OperandTypesVectorRegisterToVectorRegister)
+	n.SrcReg = srcReg
+	n.DstReg = dstReg
+	n.VectorArrangement = arrangement
+	n.SrcVectorIndex = VectorIndexNone
+	n.DstVectorIndex = VectorIndexNone
+	n.SrcConst = c
+}
+
+// CompileTwoVectorRegistersToVectorRegister implements Assembler.CompileTwoVectorRegistersToVectorRegister
+func (a *AssemblerImpl) CompileTwoVectorRegistersToVectorRegister(
+	instruction asm.Instruction, srcReg, srcReg2, dstReg asm.Register, arrangement VectorArrangement) {
+	n := a.newNode(instruction, OperandTypesTwoVectorRegistersToVectorRegister)
+	n.SrcReg = srcReg
+	n.SrcReg2 = srcReg2
+	n.DstReg = dstReg
+	n.VectorArrangement = arrangement
 }
 
 func errorEncodingUnsupported(n *NodeImpl) error {
@@ -2501,12 +2571,14 @@ func checkArrangementIndexPair(arr VectorArrangement, index VectorIndex) (err er
 }
 
 func (a *AssemblerImpl) EncodeRegisterToVectorRegister(n *NodeImpl) (err error) {
-	if n.Instruction != VMOV {
+	if n.Instruction != VMOV && n.Instruction != VDUP {
 		return errorEncodingUnsupported(n)
 	}
 
-	if err = checkArrangementIndexPair(n.VectorArrangement, n.VectorIndex); err != nil {
-		return
+	if n.DstVectorIndex != VectorIndexNone {
+		if err = checkArrangementIndexPair(n.VectorArrangement, n.DstVectorIndex); err != nil {
+			return
+		}
 	}
 
 	srcRegBits, err := intRegisterBits(n.SrcReg)
@@ -2524,21 +2596,8 @@ func (a *AssemblerImpl) EncodeRegisterToVectorRegister(n *NodeImpl) (err error)
 		// VMOV is translated as "INS(Vector, Element)"
 		// Description: https://developer.arm.com/documentation/dui0802/a/A64-Advanced-SIMD-Vector-Instructions/INS--vector---general-
 		// Encoding: https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/INS--general---Insert-vector-element-from-general-purpose-register-?lang=en
-		var imm5 byte
-		switch n.VectorArrangement {
-		case VectorArrangementB:
-			imm5 |= 0b1
-			imm5 |= byte(n.VectorIndex) << 1
-		case VectorArrangementH:
-			imm5 |= 0b10
-			imm5 |= byte(n.VectorIndex) << 2
-		case VectorArrangementS:
-			imm5 |= 0b100
-			imm5 |= byte(n.VectorIndex) << 3
-		case VectorArrangementD:
-			imm5 |= 0b1000
-			imm5 |= byte(n.VectorIndex) << 4
-		default:
+		imm5, err := vectorElementImm5(n.VectorArrangement, n.DstVectorIndex)
+		if err != nil {
 			return fmt.Errorf("unsupported arrangement for VMOV: %s", n.VectorArrangement)
 		}
 		a.Buf.Write([]byte{
@@ -2547,12 +2606,52 @@ func (a *AssemblerImpl) EncodeRegisterToVectorRegister(n *NodeImpl) (err error)
 			imm5,
 			0b01001110,
 		})
+	case VDUP:
+		// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/DUP--general---Duplicate-general-purpose-register-to-vector-?lang=en
+		var imm5 byte
+		_, q := arrangementSizeQ(n.VectorArrangement)
+		switch n.VectorArrangement {
+		case VectorArrangement8B, VectorArrangement16B:
+			imm5 = 0b1
+		case VectorArrangement4H, VectorArrangement8H:
+			imm5 = 0b10
+		case VectorArrangement2S, VectorArrangement4S:
+			imm5 = 0b100
+		case VectorArrangement2D:
+			imm5 = 0b1000
+		default:
+			return fmt.Errorf("unsupported arrangement for VDUP: %s", n.VectorArrangement)
+		}
+		a.Buf.Write([]byte{
+			(srcRegBits << 5) | dstVectorRegBits,
+			0b000011_00 | srcRegBits>>3,
+			imm5,
+			q<<6 | 0b00001110,
+		})
 	default:
 		return errorEncodingUnsupported(n)
 	}
 	return
 }
 
+// vectorElementImm5 returns the "imm5" field which encodes the size and index of an element for the SIMD copy instructions.
+// See https://developer.arm.com/documentation/ddi0596/2020-12/Index-by-Encoding/Data-Processing----Scalar-Floating-Point-and-Advanced-SIMD?lang=en#asimdins
+func vectorElementImm5(arr VectorArrangement, index VectorIndex) (imm5 byte, err error) {
+	switch arr {
+	case VectorArrangementB:
+		imm5 = 0b1 | byte(index)<<1
+	case VectorArrangementH:
+		imm5 = 0b10 | byte(index)<<2
+	case VectorArrangementS:
+		imm5 = 0b100 | byte(index)<<3
+	case VectorArrangementD:
+		imm5 = 0b1000 | byte(index)<<4
+	default:
+		err = fmt.Errorf("unsupported arrangement: %s", arr)
+	}
+	return
+}
+
 func (a *AssemblerImpl) EncodeMemoryToVectorRegister(n *NodeImpl) (err error) {
 	srcRegBits, err := intRegisterBits(n.SrcReg)
 	if err != nil {
@@ -2639,7 +2738,175 @@ func (a *AssemblerImpl) EncodeVectorRegisterToMemory(n *NodeImpl) (err error) {
 	return
 }
 
-func (a *AssemblerImpl) EncodeVectorRegisterToVectorRegister(n *NodeImpl) error {
+// vectorArrangementQAndSize is the pair of "Q" and "size" fields of vector instructions for an arrangement.
+type vectorArrangementQAndSize struct {
+	q, size byte
+}
+
+// defaultQAndSize is the "Q" and "size" fields for the arrangements of integer vector instructions.
+var defaultQAndSize = map[VectorArrangement]vectorArrangementQAndSize{
+	VectorArrangement8B:  {q: 0, size: 0b00},
+	VectorArrangement16B: {q: 1, size: 0b00},
+	VectorArrangement4H:  {q: 0, size: 0b01},
+	VectorArrangement8H:  {q: 1, size: 0b01},
+	VectorArrangement2S:  {q: 0, size: 0b10},
+	VectorArrangement4S:  {q: 1, size: 0b10},
+	VectorArrangement1D:  {q: 0, size: 0b11},
+	VectorArrangement2D:  {q: 1, size: 0b11},
+}
+
+// vectorEncoding holds the "U" and "opcode" fields, and the valid arrangements of a vector instruction.
+type vectorEncoding struct {
+	u, opcode byte
+	qAndSize  map[VectorArrangement]vectorArrangementQAndSize
+}
+
+// advancedSIMDTwoRegisterMisc holds the encodings of the instructions in the "Advanced SIMD two-register miscellaneous" class.
+// The arrangement of narrowing instructions (e.g. VSQXTN) is the one of the destination, and the lower or upper half of
+// the destination is written depending on the arrangement (e.g. "8B" for SQXTN, and "16B" for SQXTN2).
+// The arrangement of lengthening instructions (e.g. VSADDLP, VFCVTL) is the one of the source.
+// See https://developer.arm.com/documentation/ddi0596/2020-12/Index-by-Encoding/Data-Processing----Scalar-Floating-Point-and-Advanced-SIMD?lang=en#asimdmisc
+var advancedSIMDTwoRegisterMisc = map[asm.Instruction]vectorEncoding{
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/NOT--Bitwise-NOT--vector--?lang=en
+	VNOT: {u: 1, opcode: 0b00101, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement16B: {q: 1, size: 0b00},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/CNT--Population-Count-per-byte-?lang=en
+	VCNT: {u: 0, opcode: 0b00101, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement8B:  {q: 0, size: 0b00},
+		VectorArrangement16B: {q: 1, size: 0b00},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/NEG--vector---Negate--vector--?lang=en
+	VNEG: {u: 1, opcode: 0b01011, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/ABS--Absolute-value--vector--?lang=en
+	VABS: {u: 0, opcode: 0b01011, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/CMEQ--zero---Compare-bitwise-Equal-to-zero--vector--?lang=en
+	VCMEQZ: {u: 0, opcode: 0b01001, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SADDLP--Signed-Add-Long-Pairwise-?lang=en
+	VSADDLP: {u: 0, opcode: 0b00010, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/UADDLP--Unsigned-Add-Long-Pairwise-?lang=en
+	VUADDLP: {u: 1, opcode: 0b00010, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SQXTN--SQXTN2--Signed-saturating-extract-Narrow-?lang=en
+	VSQXTN: {u: 0, opcode: 0b10100, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SQXTUN--SQXTUN2--Signed-saturating-extract-Unsigned-Narrow-?lang=en
+	VSQXTUN: {u: 1, opcode: 0b10010, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/UQXTN--UQXTN2--Unsigned-saturating-extract-Narrow-?lang=en
+	VUQXTN: {u: 1, opcode: 0b10100, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FABS--vector---Floating-point-Absolute-value--vector--?lang=en
+	VFABS: {u: 0, opcode: 0b01111, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b10},
+		VectorArrangement2D: {q: 1, size: 0b11},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FNEG--vector---Floating-point-Negate--vector--?lang=en
+	VFNEG: {u: 1, opcode: 0b01111, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b10},
+		VectorArrangement2D: {q: 1, size: 0b11},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FSQRT--vector---Floating-point-Square-Root--vector--?lang=en
+	VFSQRT: {u: 1, opcode: 0b11111, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b10},
+		VectorArrangement2D: {q: 1, size: 0b11},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FRINTN--vector---Floating-point-Round-to-Integral--to-nearest-with-ties-to-even--vector--?lang=en
+	VFRINTN: {u: 0, opcode: 0b11000, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b00},
+		VectorArrangement2D: {q: 1, size: 0b01},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FRINTM--vector---Floating-point-Round-to-Integral--toward-Minus-infinity--vector--?lang=en
+	VFRINTM: {u: 0, opcode: 0b11001, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b00},
+		VectorArrangement2D: {q: 1, size: 0b01},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FRINTP--vector---Floating-point-Round-to-Integral--toward-Plus-infinity--vector--?lang=en
+	VFRINTP: {u: 0, opcode: 0b11000, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b10},
+		VectorArrangement2D: {q: 1, size: 0b11},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FRINTZ--vector---Floating-point-Round-to-Integral--toward-Zero--vector--?lang=en
+	VFRINTZ: {u: 0, opcode: 0b11001, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b10},
+		VectorArrangement2D: {q: 1, size: 0b11},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SCVTF--vector--integer---Signed-integer-Convert-to-Floating-point--vector--?lang=en
+	VSCVTF: {u: 0, opcode: 0b11101, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b00},
+		VectorArrangement2D: {q: 1, size: 0b01},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/UCVTF--vector--integer---Unsigned-integer-Convert-to-Floating-point--vector--?lang=en
+	VUCVTF: {u: 1, opcode: 0b11101, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b00},
+		VectorArrangement2D: {q: 1, size: 0b01},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FCVTZS--vector--integer---Floating-point-Convert-to-Signed-integer--rounding-toward-Zero--vector--?lang=en
+	VFCVTZS: {u: 0, opcode: 0b11011, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b10},
+		VectorArrangement2D: {q: 1, size: 0b11},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FCVTZU--vector--integer---Floating-point-Convert-to-Unsigned-integer--rounding-toward-Zero--vector--?lang=en
+	VFCVTZU: {u: 1, opcode: 0b11011, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b10},
+		VectorArrangement2D: {q: 1, size: 0b11},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FCVTL--FCVTL2--Floating-point-Convert-to-higher-precision-Long--vector--?lang=en
+	VFCVTL: {u: 0, opcode: 0b10111, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement2S: {q: 0, size: 0b01},
+		VectorArrangement4S: {q: 1, size: 0b01},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FCVTN--FCVTN2--Floating-point-Convert-to-lower-precision-Narrow--vector--?lang=en
+	VFCVTN: {u: 0, opcode: 0b10110, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement2S: {q: 0, size: 0b01},
+		VectorArrangement4S: {q: 1, size: 0b01},
+	}},
+}
+
+// advancedSIMDAcrossLanes holds the encodings of the instructions in the "Advanced SIMD across lanes" class.
+// See https://developer.arm.com/documentation/ddi0596/2020-12/Index-by-Encoding/Data-Processing----Scalar-Floating-Point-and-Advanced-SIMD?lang=en#asimdall
+var advancedSIMDAcrossLanes = map[asm.Instruction]vectorEncoding{
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/UMINV--Unsigned-Minimum-across-Vector-?lang=en
+	VUMINV: {u: 1, opcode: 0b11010, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement16B: {q: 1, size: 0b00},
+		VectorArrangement8H:  {q: 1, size: 0b01},
+		VectorArrangement4S:  {q: 1, size: 0b10},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/UMAXV--Unsigned-Maximum-across-Vector-?lang=en
+	VUMAXV: {u: 1, opcode: 0b01010, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement16B: {q: 1, size: 0b00},
+		VectorArrangement8H:  {q: 1, size: 0b01},
+		VectorArrangement4S:  {q: 1, size: 0b10},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/ADDV--Add-across-Vector-?lang=en
+	VADDV: {u: 0, opcode: 0b11011, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement8B:  {q: 0, size: 0b00},
+		VectorArrangement16B: {q: 1, size: 0b00},
+		VectorArrangement8H:  {q: 1, size: 0b01},
+		VectorArrangement4S:  {q: 1, size: 0b10},
+	}},
+}
+
+// advancedSIMDShiftByImmediate holds the encodings of the instructions in the "Advanced SIMD shift by immediate" class.
+// The arrangement of lengthening instructions (VSSHLL, VUSHLL) is the one of the source, and the lower or upper half of
+// the source is read depending on the arrangement (e.g. "8B" for SSHLL, and "16B" for SSHLL2).
+// See https://developer.arm.com/documentation/ddi0596/2020-12/Index-by-Encoding/Data-Processing----Scalar-Floating-Point-and-Advanced-SIMD?lang=en#asimdshf
+var advancedSIMDShiftByImmediate = map[asm.Instruction]struct {
+	u, opcode byte
+	// right is true if the shift amount is encoded as "2*element size - shift".
+	right bool
+}{
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SSHR--Signed-Shift-Right--immediate--?lang=en
+	VSSHR: {u: 0, opcode: 0b00000, right: true},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/USHR--Unsigned-Shift-Right--immediate--?lang=en
+	VUSHR: {u: 1, opcode: 0b00000, right: true},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SHL--Shift-Left--immediate--?lang=en
+	VSHL: {u: 0, opcode: 0b01010},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SSHLL--SSHLL2--Signed-Shift-Left-Long--immediate--?lang=en
+	VSSHLL: {u: 0, opcode: 0b10100},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/USHLL--USHLL2--Unsigned-Shift-Left-Long--immediate--?lang=en
+	VUSHLL: {u: 1, opcode: 0b10100},
+}
+
+// Exported for inter-op testing with golang-asm.
+// TODO: unexport after golang-asm complete removal.
+func (a *AssemblerImpl) EncodeVectorRegisterToVectorRegister(n *NodeImpl) (err error) {
 	srcVectorRegBits, err := vectorRegisterBits(n.SrcReg)
 	if err != nil {
 		return err
@@ -2650,6 +2917,73 @@ func (a *AssemblerImpl) EncodeVectorRegisterToVectorRegister(n *NodeImpl) error
 		return err
 	}
 
+	if enc, ok := advancedSIMDTwoRegisterMisc[n.Instruction]; ok {
+		qs, ok := enc.qAndSize[n.VectorArrangement]
+		if !ok {
+			return fmt.Errorf("unsupported arrangement for %s: %s", InstructionName(n.Instruction), n.VectorArrangement)
+		}
+		a.Buf.Write([]byte{
+			(srcVectorRegBits << 5) | dstVectorRegBits,
+			(enc.opcode&0b1111)<<4 | 0b10<<2 | srcVectorRegBits>>3,
+			qs.size<<6 | 0b10000<<1 | enc.opcode>>4,
+			qs.q<<6 | enc.u<<5 | 0b01110,
+		})
+		return nil
+	}
+
+	if enc, ok := advancedSIMDAcrossLanes[n.Instruction]; ok {
+		qs, ok := enc.qAndSize[n.VectorArrangement]
+		if !ok {
+			return fmt.Errorf("unsupported arrangement for %s: %s", InstructionName(n.Instruction), n.VectorArrangement)
+		}
+		a.Buf.Write([]byte{
+			(srcVectorRegBits << 5) | dstVectorRegBits,
+			(enc.opcode&0b1111)<<4 | 0b10<<2 | srcVectorRegBits>>3,
+			qs.size<<6 | 0b11000<<1 | enc.opcode>>4,
+			qs.q<<6 | enc.u<<5 | 0b01110,
+		})
+		return nil
+	}
+
+	if enc, ok := advancedSIMDShiftByImmediate[n.Instruction]; ok {
+		var elementSize int64
+		var q byte
+		switch n.VectorArrangement {
+		case VectorArrangement8B, VectorArrangement16B:
+			elementSize = 8
+		case VectorArrangement4H, VectorArrangement8H:
+			elementSize = 16
+		case VectorArrangement2S, VectorArrangement4S:
+			elementSize = 32
+		case VectorArrangement2D:
+			elementSize = 64
+		default:
+			return fmt.Errorf("unsupported arrangement for %s: %s", InstructionName(n.Instruction), n.VectorArrangement)
+		}
+		_, q = arrangementSizeQ(n.VectorArrangement)
+
+		shift := n.SrcConst
+		var immhImmb int64
+		if enc.right {
+			if shift <= 0 || shift > elementSize {
+				return fmt.Errorf("shift amount must be in [1, %d] for %s but got %d", elementSize, InstructionName(n.Instruction), shift)
+			}
+			immhImmb = 2*elementSize - shift
+		} else {
+			if shift < 0 || shift >= elementSize {
+				return fmt.Errorf("shift amount must be in [0, %d) for %s but got %d", elementSize, InstructionName(n.Instruction), shift)
+			}
+			immhImmb = elementSize + shift
+		}
+		a.Buf.Write([]byte{
+			(srcVectorRegBits << 5) | dstVectorRegBits,
+			enc.opcode<<3 | 0b1<<2 | srcVectorRegBits>>3,
+			byte(immhImmb),
+			q<<6 | enc.u<<5 | 0b01111,
+		})
+		return nil
+	}
+
 	switch n.Instruction {
 	case VMOV:
 		if n.VectorArrangement != VectorArrangement16B {
@@ -2689,11 +3023,295 @@ func (a *AssemblerImpl) EncodeVectorRegisterToVectorRegister(n *NodeImpl) error
 			sz<<6 | 0b1<<5 | dstVectorRegBits,
 			0b1<<6 | 0b1110,
 		})
+	case VDUPELEM:
+		// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/DUP--element---Duplicate-vector-element-to-vector-or-scalar-?lang=en
+		var elementArr VectorArrangement
+		switch n.VectorArrangement {
+		case VectorArrangement16B:
+			elementArr = VectorArrangementB
+		case VectorArrangement8H:
+			elementArr = VectorArrangementH
+		case VectorArrangement4S:
+			elementArr = VectorArrangementS
+		case VectorArrangement2D:
+			elementArr = VectorArrangementD
+		default:
+			return fmt.Errorf("unsupported arrangement for VDUPELEM: %s", n.VectorArrangement)
+		}
+		if err = checkArrangementIndexPair(elementArr, n.SrcVectorIndex); err != nil {
+			return
+		}
+		imm5, _ := vectorElementImm5(elementArr, n.SrcVectorIndex)
+		a.Buf.Write([]byte{
+			(srcVectorRegBits << 5) | dstVectorRegBits,
+			0b1<<2 | srcVectorRegBits>>3,
+			imm5,
+			0b01001110,
+		})
+	case VINS:
+		// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/INS--element---Insert-vector-element-from-another-vector-element-?lang=en
+		if err = checkArrangementIndexPair(n.VectorArrangement, n.SrcVectorIndex); err != nil {
+			return
+		}
+		if err = checkArrangementIndexPair(n.VectorArrangement, n.DstVectorIndex); err != nil {
+			return
+		}
+		imm5, err := vectorElementImm5(n.VectorArrangement, n.DstVectorIndex)
+		if err != nil {
+			return fmt.Errorf("unsupported arrangement for VINS: %s", n.VectorArrangement)
+		}
+		var imm4 byte
+		switch n.VectorArrangement {
+		case VectorArrangementB:
+			imm4 = byte(n.SrcVectorIndex)
+		case VectorArrangementH:
+			imm4 = byte(n.SrcVectorIndex) << 1
+		case VectorArrangementS:
+			imm4 = byte(n.SrcVectorIndex) << 2
+		case VectorArrangementD:
+			imm4 = byte(n.SrcVectorIndex) << 3
+		}
+		a.Buf.Write([]byte{
+			(srcVectorRegBits << 5) | dstVectorRegBits,
+			imm4<<3 | 0b1<<2 | srcVectorRegBits>>3,
+			imm5,
+			0b01101110,
+		})
 	default:
 		return errorEncodingUnsupported(n)
 	}
+	return
+}
 
-	return nil
+// Exported for inter-op testing with golang-asm.
+// TODO: unexport after golang-asm complete removal.
+func (a *AssemblerImpl) EncodeVectorRegisterToRegister(n *NodeImpl) (err error) {
+	srcVectorRegBits, err := vectorRegisterBits(n.SrcReg)
+	if err != nil {
+		return err
+	}
+
+	dstRegBits, err := intRegisterBits(n.DstReg)
+	if err != nil {
+		return err
+	}
+
+	if err = checkArrangementIndexPair(n.VectorArrangement, n.SrcVectorIndex); err != nil {
+		return
+	}
+
+	imm5, err := vectorElementImm5(n.VectorArrangement, n.SrcVectorIndex)
+	if err != nil {
+		return fmt.Errorf("unsupported arrangement for %s: %s", InstructionName(n.Instruction), n.VectorArrangement)
+	}
+
+	var q, imm4 byte
+	switch n.Instruction {
+	case VUMOV:
+		// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/UMOV--Unsigned-Move-vector-element-to-general-purpose-register-?lang=en
+		imm4 = 0b0111
+		if n.VectorArrangement == VectorArrangementD {
+			q = 1 // The destination is the 64-bit register.
+		}
+	case VSMOV:
+		// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SMOV--Signed-Move-vector-element-to-general-purpose-register-?lang=en
+		imm4 = 0b0101
+		if n.VectorArrangement != VectorArrangementB && n.VectorArrangement != VectorArrangementH {
+			return fmt.Errorf("unsupported arrangement for VSMOV: %s", n.VectorArrangement)
+		}
+		// Note: the destination is always the 32-bit register.
+	default:
+		return errorEncodingUnsupported(n)
+	}
+
+	a.Buf.Write([]byte{
+		(srcVectorRegBits << 5) | dstRegBits,
+		imm4<<3 | 0b1<<2 | srcVectorRegBits>>3,
+		imm5,
+		q<<6 | 0b00001110,
+	})
+	return
+}
+
+// advancedSIMDThreeSame holds the encodings of the instructions in the "Advanced SIMD three same" class.
+// See https://developer.arm.com/documentation/ddi0596/2020-12/Index-by-Encoding/Data-Processing----Scalar-Floating-Point-and-Advanced-SIMD?lang=en#asimdsame
+var advancedSIMDThreeSame = map[asm.Instruction]vectorEncoding{
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SUB--vector---Subtract--vector--?lang=en
+	VSUB: {u: 1, opcode: 0b10000, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SQADD--Signed-saturating-Add-?lang=en
+	VSQADD: {u: 0, opcode: 0b00001, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/UQADD--Unsigned-saturating-Add-?lang=en
+	VUQADD: {u: 1, opcode: 0b00001, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SQSUB--Signed-saturating-Subtract-?lang=en
+	VSQSUB: {u: 0, opcode: 0b00101, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/UQSUB--Unsigned-saturating-Subtract-?lang=en
+	VUQSUB: {u: 1, opcode: 0b00101, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/CMEQ--register---Compare-bitwise-Equal--vector--?lang=en
+	VCMEQ: {u: 1, opcode: 0b10001, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/CMGT--register---Compare-signed-Greater-than--vector--?lang=en
+	VCMGT: {u: 0, opcode: 0b00110, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/CMHI--register---Compare-unsigned-Higher--vector--?lang=en
+	VCMHI: {u: 1, opcode: 0b00110, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/CMGE--register---Compare-signed-Greater-than-or-Equal--vector--?lang=en
+	VCMGE: {u: 0, opcode: 0b00111, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/CMHS--register---Compare-unsigned-Higher-or-Same--vector--?lang=en
+	VCMHS: {u: 1, opcode: 0b00111, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SSHL--Signed-Shift-Left--register--?lang=en
+	VSSHL: {u: 0, opcode: 0b01000, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/USHL--Unsigned-Shift-Left--register--?lang=en
+	VUSHL: {u: 1, opcode: 0b01000, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SMAX--Signed-Maximum--vector--?lang=en
+	VSMAX: {u: 0, opcode: 0b01100, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/UMAX--Unsigned-Maximum--vector--?lang=en
+	VUMAX: {u: 1, opcode: 0b01100, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SMIN--Signed-Minimum--vector--?lang=en
+	VSMIN: {u: 0, opcode: 0b01101, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/UMIN--Unsigned-Minimum--vector--?lang=en
+	VUMIN: {u: 1, opcode: 0b01101, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/URHADD--Unsigned-Rounding-Halving-Add-?lang=en
+	VURHADD: {u: 1, opcode: 0b00010, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/MUL--vector---Multiply--vector--?lang=en
+	VMUL: {u: 0, opcode: 0b10011, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/ADDP--vector---Add-Pairwise--vector--?lang=en
+	VADDP: {u: 0, opcode: 0b10111, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SQRDMULH--vector---Signed-saturating-Rounding-Doubling-Multiply-returning-High-half-?lang=en
+	VSQRDMULH: {u: 1, opcode: 0b10110, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/AND--vector---Bitwise-AND--vector--?lang=en
+	VAND: {u: 0, opcode: 0b00011, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement16B: {q: 1, size: 0b00},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/BIC--vector--register---Bitwise-bit-Clear--vector--register--?lang=en
+	VBIC: {u: 0, opcode: 0b00011, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement16B: {q: 1, size: 0b01},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/ORR--vector--register---Bitwise-inclusive-OR--vector--register--?lang=en
+	VORR: {u: 0, opcode: 0b00011, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement16B: {q: 1, size: 0b10},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/EOR--vector---Bitwise-Exclusive-OR--vector--?lang=en
+	VEOR: {u: 1, opcode: 0b00011, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement16B: {q: 1, size: 0b00},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/BIT--Bitwise-Insert-if-True-?lang=en
+	VBIT: {u: 1, opcode: 0b00011, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement16B: {q: 1, size: 0b10},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FSUB--vector---Floating-point-Subtract--vector--?lang=en
+	VFSUB: {u: 0, opcode: 0b11010, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b10},
+		VectorArrangement2D: {q: 1, size: 0b11},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FMUL--vector---Floating-point-Multiply--vector--?lang=en
+	VFMUL: {u: 1, opcode: 0b11011, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b00},
+		VectorArrangement2D: {q: 1, size: 0b01},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FDIV--vector---Floating-point-Divide--vector--?lang=en
+	VFDIV: {u: 1, opcode: 0b11111, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b00},
+		VectorArrangement2D: {q: 1, size: 0b01},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FMAX--vector---Floating-point-Maximum--vector--?lang=en
+	VFMAX: {u: 0, opcode: 0b11110, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b00},
+		VectorArrangement2D: {q: 1, size: 0b01},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FMIN--vector---Floating-point-minimum--vector--?lang=en
+	VFMIN: {u: 0, opcode: 0b11110, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b10},
+		VectorArrangement2D: {q: 1, size: 0b11},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FCMEQ--register---Floating-point-Compare-Equal--vector--?lang=en
+	VFCMEQ: {u: 0, opcode: 0b11100, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b00},
+		VectorArrangement2D: {q: 1, size: 0b01},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FCMGE--register---Floating-point-Compare-Greater-than-or-Equal--vector--?lang=en
+	VFCMGE: {u: 1, opcode: 0b11100, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b00},
+		VectorArrangement2D: {q: 1, size: 0b01},
+	}},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/FCMGT--register---Floating-point-Compare-Greater-than--vector--?lang=en
+	VFCMGT: {u: 1, opcode: 0b11100, qAndSize: map[VectorArrangement]vectorArrangementQAndSize{
+		VectorArrangement4S: {q: 1, size: 0b10},
+		VectorArrangement2D: {q: 1, size: 0b11},
+	}},
+}
+
+// advancedSIMDThreeDifferent holds the encodings of the instructions in the "Advanced SIMD three different" class.
+// The arrangement is the one of the sources, and the lower or upper halves of the sources are read depending on the
+// arrangement (e.g. "8B" for SMULL, and "16B" for SMULL2).
+// See https://developer.arm.com/documentation/ddi0596/2020-12/Index-by-Encoding/Data-Processing----Scalar-Floating-Point-and-Advanced-SIMD?lang=en#asimddiff
+var advancedSIMDThreeDifferent = map[asm.Instruction]vectorEncoding{
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/SMULL--SMULL2--vector---Signed-Multiply-Long--vector--?lang=en
+	VSMULL: {u: 0, opcode: 0b1100, qAndSize: defaultQAndSize},
+	// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/UMULL--UMULL2--vector---Unsigned-Multiply-long--vector--?lang=en
+	VUMULL: {u: 1, opcode: 0b1100, qAndSize: defaultQAndSize},
+}
+
+// Exported for inter-op testing with golang-asm.
+// TODO: unexport after golang-asm complete removal.
+func (a *AssemblerImpl) EncodeTwoVectorRegistersToVectorRegister(n *NodeImpl) (err error) {
+	srcRegBits, err := vectorRegisterBits(n.SrcReg)
+	if err != nil {
+		return err
+	}
+
+	srcReg2Bits, err := vectorRegisterBits(n.SrcReg2)
+	if err != nil {
+		return err
+	}
+
+	dstRegBits, err := vectorRegisterBits(n.DstReg)
+	if err != nil {
+		return err
+	}
+
+	if enc, ok := advancedSIMDThreeSame[n.Instruction]; ok {
+		qs, ok := enc.qAndSize[n.VectorArrangement]
+		if !ok {
+			return fmt.Errorf("unsupported arrangement for %s: %s", InstructionName(n.Instruction), n.VectorArrangement)
+		}
+		a.Buf.Write([]byte{
+			(srcRegBits << 5) | dstRegBits,
+			enc.opcode<<3 | 0b1<<2 | srcRegBits>>3,
+			qs.size<<6 | 0b1<<5 | srcReg2Bits,
+			qs.q<<6 | enc.u<<5 | 0b01110,
+		})
+		return nil
+	}
+
+	if enc, ok := advancedSIMDThreeDifferent[n.Instruction]; ok {
+		qs, ok := enc.qAndSize[n.VectorArrangement]
+		if !ok || qs.size == 0b11 {
+			return fmt.Errorf("unsupported arrangement for %s: %s", InstructionName(n.Instruction), n.VectorArrangement)
+		}
+		a.Buf.Write([]byte{
+			(srcRegBits << 5) | dstRegBits,
+			enc.opcode<<4 | srcRegBits>>3,
+			qs.size<<6 | 0b1<<5 | srcReg2Bits,
+			qs.q<<6 | enc.u<<5 | 0b01110,
+		})
+		return nil
+	}
+
+	switch n.Instruction {
+	case VTBL:
+		if n.VectorArrangement != VectorArrangement16B {
+			return fmt.Errorf("unsupported arrangement for VTBL: %s", n.VectorArrangement)
+		}
+		// "TBL dst.16B, {src.16B}, src2.16B" where src is the table and src2 holds the indexes.
+		// https://developer.arm.com/documentation/ddi0596/2020-12/SIMD-FP-Instructions/TBL--Table-vector-Lookup-?lang=en
+		a.Buf.Write([]byte{
+			(srcRegBits << 5) | dstRegBits,
+			srcRegBits >> 3,
+			srcReg2Bits,
+			0b01001110,
+		})
+	default:
+		return errorEncodingUnsupported(n)
+	}
+	return
 }
 
 var zeroRegisterBits byte = 0b11111
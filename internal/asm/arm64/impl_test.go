@@ -118,13 +118,40 @@ func TestNodeImpl_String(t *testing.T) {
 		},
 		{
 			in: &NodeImpl{Instruction: VMOV, Types: OperandTypesRegisterToVectorRegister,
-				SrcReg: RegR1, DstReg: RegV29, VectorArrangement: VectorArrangement2D, VectorIndex: 1},
+				SrcReg: RegR1, DstReg: RegV29, VectorArrangement: VectorArrangement2D, DstVectorIndex: 1},
 			exp: "VMOV R1, V29.2D[1]",
 		},
+		{
+			in: &NodeImpl{Instruction: VDUP, Types: OperandTypesRegisterToVectorRegister,
+				SrcReg: RegR1, DstReg: RegV29, VectorArrangement: VectorArrangement4S, DstVectorIndex: VectorIndexNone},
+			exp: "VDUP R1, V29.4S",
+		},
+		{
+			in: &NodeImpl{Instruction: VUMOV, Types: OperandTypesVectorRegisterToRegister,
+				SrcReg: RegV29, DstReg: RegR1, VectorArrangement: VectorArrangementH, SrcVectorIndex: 7},
+			exp: "VUMOV V29.H[7], R1",
+		},
 		{
 			in: &NodeImpl{Instruction: VCNT, Types: OperandTypesVectorRegisterToVectorRegister,
-				SrcReg: RegV3, DstReg: RegV29, VectorArrangement: VectorArrangement2D, VectorIndex: 1},
-			exp: "VCNT V3.V3, V29.V3",
+				SrcReg: RegV3, DstReg: RegV29, VectorArrangement: VectorArrangement16B,
+				SrcVectorIndex: VectorIndexNone, DstVectorIndex: VectorIndexNone},
+			exp: "VCNT V3.16B, V29.16B",
+		},
+		{
+			in: &NodeImpl{Instruction: VINS, Types: OperandTypesVectorRegisterToVectorRegister,
+				SrcReg: RegV3, DstReg: RegV29, VectorArrangement: VectorArrangementS, SrcVectorIndex: 1, DstVectorIndex: 3},
+			exp: "VINS V3.S[1], V29.S[3]",
+		},
+		{
+			in: &NodeImpl{Instruction: VSSHR, Types: OperandTypesVectorRegisterToVectorRegister,
+				SrcReg: RegV3, DstReg: RegV29, VectorArrangement: VectorArrangement4S, SrcConst: 0x1f,
+				SrcVectorIndex: VectorIndexNone, DstVectorIndex: VectorIndexNone},
+			exp: "VSSHR V3.4S, V29.4S, 0x1f",
+		},
+		{
+			in: &NodeImpl{Instruction: VSUB, Types: OperandTypesTwoVectorRegistersToVectorRegister,
+				SrcReg: RegV3, SrcReg2: RegV4, DstReg: RegV29, VectorArrangement: VectorArrangement8H},
+			exp: "VSUB (V3.8H, V4.8H), V29.8H",
 		},
 	}
 
@@ -419,12 +446,25 @@ func Test_CompileRegisterToVectorRegister(t *testing.T) {
 	require.Equal(t, OperandTypeRegister, actualNode.Types.src)
 	require.Equal(t, OperandTypeVectorRegister, actualNode.Types.dst)
 	require.Equal(t, VectorArrangement1D, actualNode.VectorArrangement)
-	require.Equal(t, VectorIndex(10), actualNode.VectorIndex)
+	require.Equal(t, VectorIndex(10), actualNode.DstVectorIndex)
+}
+
+func Test_CompileVectorRegisterToRegister(t *testing.T) {
+	a := NewAssemblerImpl(RegR10)
+	a.CompileVectorRegisterToRegister(VUMOV, RegV3, RegR10, VectorArrangementS, 2)
+	actualNode := a.Current
+	require.Equal(t, VUMOV, actualNode.Instruction)
+	require.Equal(t, RegV3, actualNode.SrcReg)
+	require.Equal(t, RegR10, actualNode.DstReg)
+	require.Equal(t, OperandTypeVectorRegister, actualNode.Types.src)
+	require.Equal(t, OperandTypeRegister, actualNode.Types.dst)
+	require.Equal(t, VectorArrangementS, actualNode.VectorArrangement)
+	require.Equal(t, VectorIndex(2), actualNode.SrcVectorIndex)
 }
 
 func Test_CompileVectorRegisterToVectorRegister(t *testing.T) {
 	a := NewAssemblerImpl(RegR10)
-	a.CompileVectorRegisterToVectorRegister(VMOV, RegV3, RegV10, VectorArrangement1D)
+	a.CompileVectorRegisterToVectorRegister(VMOV, RegV3, RegV10, VectorArrangement1D, 1, 2)
 	actualNode := a.Current
 	require.Equal(t, VMOV, actualNode.Instruction)
 	require.Equal(t, RegV3, actualNode.SrcReg)
@@ -432,6 +472,34 @@ func Test_CompileVectorRegisterToVectorRegister(t *testing.T) {
 	require.Equal(t, OperandTypeVectorRegister, actualNode.Types.src)
 	require.Equal(t, OperandTypeVectorRegister, actualNode.Types.dst)
 	require.Equal(t, VectorArrangement1D, actualNode.VectorArrangement)
+	require.Equal(t, VectorIndex(1), actualNode.SrcVectorIndex)
+	require.Equal(t, VectorIndex(2), actualNode.DstVectorIndex)
+}
+
+func Test_CompileVectorRegisterToVectorRegisterWithConst(t *testing.T) {
+	a := NewAssemblerImpl(RegR10)
+	a.CompileVectorRegisterToVectorRegisterWithConst(VSSHR, RegV3, RegV10, VectorArrangement4S, 10)
+	actualNode := a.Current
+	require.Equal(t, VSSHR, actualNode.Instruction)
+	require.Equal(t, RegV3, actualNode.SrcReg)
+	require.Equal(t, RegV10, actualNode.DstReg)
+	require.Equal(t, OperandTypeVectorRegister, actualNode.Types.src)
+	require.Equal(t, OperandTypeVectorRegister, actualNode.Types.dst)
+	require.Equal(t, VectorArrangement4S, actualNode.VectorArrangement)
+	require.Equal(t, int64(10), actualNode.SrcConst)
+}
+
+func Test_CompileTwoVectorRegistersToVectorRegister(t *testing.T) {
+	a := NewAssemblerImpl(RegR10)
+	a.CompileTwoVectorRegistersToVectorRegister(VSUB, RegV3, RegV4, RegV10, VectorArrangement8H)
+	actualNode := a.Current
+	require.Equal(t, VSUB, actualNode.Instruction)
+	require.Equal(t, RegV3, actualNode.SrcReg)
+	require.Equal(t, RegV4, actualNode.SrcReg2)
+	require.Equal(t, RegV10, actualNode.DstReg)
+	require.Equal(t, OperandTypeTwoVectorRegisters, actualNode.Types.src)
+	require.Equal(t, OperandTypeVectorRegister, actualNode.Types.dst)
+	require.Equal(t, VectorArrangement8H, actualNode.VectorArrangement)
 }
 
 func Test_checkRegisterToRegisterType(t *testing.T) {
@@ -494,33 +562,249 @@ func Test_validateMemoryOffset(t *testing.T) {
 }
 
 func TestAssemblerImpl_EncodeVectorRegisterToVectorRegister(t *testing.T) {
-	x1, x2 := RegV2, RegV10
+	srcReg, dstReg := RegV2, RegV10
+	tests := []struct {
+		inst               asm.Instruction
+		arr                VectorArrangement
+		srcIndex, dstIndex VectorIndex
+		c                  asm.ConstantValue
+		exp                []byte
+	}{
+		// These are not supported in golang-asm, so test it here instead of integration tests.
+		{inst: VFADDD, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, exp: []byte{0x4a, 0xd4, 0x6a, 0x4e}},
+		{inst: VFADDS, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, exp: []byte{0x4a, 0xd4, 0x2a, 0x4e}},
+		{inst: VNOT, arr: VectorArrangement16B, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x58, 0x20, 0x6e}},    // mvn v10.16b, v2.16b
+		{inst: VCNT, arr: VectorArrangement16B, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x58, 0x20, 0x4e}},    // cnt v10.16b, v2.16b
+		{inst: VNEG, arr: VectorArrangement16B, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xb8, 0x20, 0x6e}},    // neg v10.16b, v2.16b
+		{inst: VNEG, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xb8, 0xe0, 0x6e}},     // neg v10.2d, v2.2d
+		{inst: VABS, arr: VectorArrangement8H, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xb8, 0x60, 0x4e}},     // abs v10.8h, v2.8h
+		{inst: VABS, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xb8, 0xe0, 0x4e}},     // abs v10.2d, v2.2d
+		{inst: VCMEQZ, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x98, 0xe0, 0x4e}},   // cmeq v10.2d, v2.2d, #0
+		{inst: VSADDLP, arr: VectorArrangement16B, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x28, 0x20, 0x4e}}, // saddlp v10.8h, v2.16b
+		{inst: VUADDLP, arr: VectorArrangement8H, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x28, 0x60, 0x6e}},  // uaddlp v10.4s, v2.8h
+		{inst: VSQXTN, arr: VectorArrangement8B, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x48, 0x21, 0x0e}},   // sqxtn v10.8b, v2.8h
+		{inst: VSQXTN, arr: VectorArrangement8H, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x48, 0x61, 0x4e}},   // sqxtn2 v10.8h, v2.4s
+		{inst: VSQXTUN, arr: VectorArrangement16B, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x28, 0x21, 0x6e}}, // sqxtun2 v10.16b, v2.8h
+		{inst: VUQXTN, arr: VectorArrangement2S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x48, 0xa1, 0x2e}},   // uqxtn v10.2s, v2.2d
+		{inst: VFABS, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xf8, 0xa0, 0x4e}},    // fabs v10.4s, v2.4s
+		{inst: VFABS, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xf8, 0xe0, 0x4e}},    // fabs v10.2d, v2.2d
+		{inst: VFNEG, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xf8, 0xa0, 0x6e}},    // fneg v10.4s, v2.4s
+		{inst: VFNEG, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xf8, 0xe0, 0x6e}},    // fneg v10.2d, v2.2d
+		{inst: VFSQRT, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xf8, 0xa1, 0x6e}},   // fsqrt v10.4s, v2.4s
+		{inst: VFSQRT, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xf8, 0xe1, 0x6e}},   // fsqrt v10.2d, v2.2d
+		{inst: VFRINTN, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x88, 0x21, 0x4e}},  // frintn v10.4s, v2.4s
+		{inst: VFRINTN, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x88, 0x61, 0x4e}},  // frintn v10.2d, v2.2d
+		{inst: VFRINTM, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x98, 0x21, 0x4e}},  // frintm v10.4s, v2.4s
+		{inst: VFRINTM, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x98, 0x61, 0x4e}},  // frintm v10.2d, v2.2d
+		{inst: VFRINTP, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x88, 0xa1, 0x4e}},  // frintp v10.4s, v2.4s
+		{inst: VFRINTP, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x88, 0xe1, 0x4e}},  // frintp v10.2d, v2.2d
+		{inst: VFRINTZ, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x98, 0xa1, 0x4e}},  // frintz v10.4s, v2.4s
+		{inst: VFRINTZ, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x98, 0xe1, 0x4e}},  // frintz v10.2d, v2.2d
+		{inst: VSCVTF, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xd8, 0x21, 0x4e}},   // scvtf v10.4s, v2.4s
+		{inst: VSCVTF, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xd8, 0x61, 0x4e}},   // scvtf v10.2d, v2.2d
+		{inst: VUCVTF, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xd8, 0x21, 0x6e}},   // ucvtf v10.4s, v2.4s
+		{inst: VUCVTF, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xd8, 0x61, 0x6e}},   // ucvtf v10.2d, v2.2d
+		{inst: VFCVTZS, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xb8, 0xa1, 0x4e}},  // fcvtzs v10.4s, v2.4s
+		{inst: VFCVTZS, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xb8, 0xe1, 0x4e}},  // fcvtzs v10.2d, v2.2d
+		{inst: VFCVTZU, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xb8, 0xa1, 0x6e}},  // fcvtzu v10.4s, v2.4s
+		{inst: VFCVTZU, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xb8, 0xe1, 0x6e}},  // fcvtzu v10.2d, v2.2d
+		{inst: VFCVTL, arr: VectorArrangement2S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x78, 0x61, 0x0e}},   // fcvtl v10.2d, v2.2s
+		{inst: VFCVTN, arr: VectorArrangement2S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x68, 0x61, 0x0e}},   // fcvtn v10.2s, v2.2d
+		{inst: VUMINV, arr: VectorArrangement16B, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xa8, 0x31, 0x6e}},  // uminv b10, v2.16b
+		{inst: VUMINV, arr: VectorArrangement8H, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xa8, 0x71, 0x6e}},   // uminv h10, v2.8h
+		{inst: VUMINV, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xa8, 0xb1, 0x6e}},   // uminv s10, v2.4s
+		{inst: VUMAXV, arr: VectorArrangement16B, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xa8, 0x30, 0x6e}},  // umaxv b10, v2.16b
+		{inst: VUMAXV, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xa8, 0xb0, 0x6e}},   // umaxv s10, v2.4s
+		{inst: VADDV, arr: VectorArrangement8H, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xb8, 0x71, 0x4e}},    // addv h10, v2.8h
+		{inst: VADDV, arr: VectorArrangement16B, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xb8, 0x31, 0x4e}},   // addv b10, v2.16b
+		{inst: VSSHR, arr: VectorArrangement16B, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 7, exp: []byte{0x4a, 0x04, 0x09, 0x4f}},   // sshr v10.16b, v2.16b, #7
+		{inst: VSSHR, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 31, exp: []byte{0x4a, 0x04, 0x21, 0x4f}},   // sshr v10.4s, v2.4s, #31
+		{inst: VSSHR, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 1, exp: []byte{0x4a, 0x04, 0x7f, 0x4f}},    // sshr v10.2d, v2.2d, #1
+		{inst: VUSHR, arr: VectorArrangement8H, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 15, exp: []byte{0x4a, 0x04, 0x11, 0x6f}},   // ushr v10.8h, v2.8h, #15
+		{inst: VSHL, arr: VectorArrangement2D, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 63, exp: []byte{0x4a, 0x54, 0x7f, 0x4f}},    // shl v10.2d, v2.2d, #63
+		{inst: VSHL, arr: VectorArrangement16B, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 3, exp: []byte{0x4a, 0x54, 0x0b, 0x4f}},    // shl v10.16b, v2.16b, #3
+		{inst: VSSHLL, arr: VectorArrangement8B, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xa4, 0x08, 0x0f}},   // sshll v10.8h, v2.8b, #0
+		{inst: VSSHLL, arr: VectorArrangement16B, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xa4, 0x08, 0x4f}},  // sshll2 v10.8h, v2.16b, #0
+		{inst: VUSHLL, arr: VectorArrangement4H, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xa4, 0x10, 0x2f}},   // ushll v10.4s, v2.4h, #0
+		{inst: VUSHLL, arr: VectorArrangement4S, srcIndex: VectorIndexNone, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0xa4, 0x20, 0x6f}},   // ushll2 v10.2d, v2.4s, #0
+		{inst: VDUPELEM, arr: VectorArrangement16B, srcIndex: 5, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x04, 0x0b, 0x4e}},              // dup v10.16b, v2.b[5]
+		{inst: VDUPELEM, arr: VectorArrangement4S, srcIndex: 3, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x04, 0x1c, 0x4e}},               // dup v10.4s, v2.s[3]
+		{inst: VDUPELEM, arr: VectorArrangement2D, srcIndex: 1, dstIndex: VectorIndexNone, c: 0, exp: []byte{0x4a, 0x04, 0x18, 0x4e}},               // dup v10.2d, v2.d[1]
+		{inst: VINS, arr: VectorArrangementB, srcIndex: 15, dstIndex: 0, c: 0, exp: []byte{0x4a, 0x7c, 0x01, 0x6e}},                                 // mov v10.b[0], v2.b[15]
+		{inst: VINS, arr: VectorArrangementH, srcIndex: 1, dstIndex: 7, c: 0, exp: []byte{0x4a, 0x14, 0x1e, 0x6e}},                                  // mov v10.h[7], v2.h[1]
+		{inst: VINS, arr: VectorArrangementS, srcIndex: 2, dstIndex: 1, c: 0, exp: []byte{0x4a, 0x44, 0x0c, 0x6e}},                                  // mov v10.s[1], v2.s[2]
+		{inst: VINS, arr: VectorArrangementD, srcIndex: 0, dstIndex: 1, c: 0, exp: []byte{0x4a, 0x04, 0x18, 0x6e}},                                  // mov v10.d[1], v2.d[0]
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		n := &NodeImpl{
+			Instruction:       tc.inst,
+			Types:             OperandTypesVectorRegisterToVectorRegister,
+			SrcReg:            srcReg,
+			DstReg:            dstReg,
+			VectorArrangement: tc.arr,
+			SrcVectorIndex:    tc.srcIndex,
+			DstVectorIndex:    tc.dstIndex,
+			SrcConst:          tc.c,
+		}
+		t.Run(n.String(), func(t *testing.T) {
+			a := NewAssemblerImpl(asm.NilRegister)
+			err := a.EncodeVectorRegisterToVectorRegister(n)
+			require.NoError(t, err)
+			actual := a.Buf.Bytes()
+			require.Equal(t, tc.exp, actual, hex.EncodeToString(actual))
+		})
+	}
+}
+
+func TestAssemblerImpl_EncodeTwoVectorRegistersToVectorRegister(t *testing.T) {
+	srcReg, srcReg2, dstReg := RegV2, RegV10, RegV30
 	tests := []struct {
 		inst asm.Instruction
+		arr  VectorArrangement
 		exp  []byte
 	}{
 		// These are not supported in golang-asm, so test it here instead of integration tests.
-		{inst: VFADDD, exp: []byte{
-			0x4a, 0xd4, 0x6a, 0x4e, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
-		}},
-		{inst: VFADDS, exp: []byte{
-			0x4a, 0xd4, 0x2a, 0x4e, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0,
-		}},
+		{inst: VSUB, arr: VectorArrangement16B, exp: []byte{0x5e, 0x84, 0x2a, 0x6e}},     // sub v30.16b, v2.16b, v10.16b
+		{inst: VSUB, arr: VectorArrangement2D, exp: []byte{0x5e, 0x84, 0xea, 0x6e}},      // sub v30.2d, v2.2d, v10.2d
+		{inst: VSQADD, arr: VectorArrangement16B, exp: []byte{0x5e, 0x0c, 0x2a, 0x4e}},   // sqadd v30.16b, v2.16b, v10.16b
+		{inst: VUQADD, arr: VectorArrangement8H, exp: []byte{0x5e, 0x0c, 0x6a, 0x6e}},    // uqadd v30.8h, v2.8h, v10.8h
+		{inst: VSQSUB, arr: VectorArrangement16B, exp: []byte{0x5e, 0x2c, 0x2a, 0x4e}},   // sqsub v30.16b, v2.16b, v10.16b
+		{inst: VUQSUB, arr: VectorArrangement8H, exp: []byte{0x5e, 0x2c, 0x6a, 0x6e}},    // uqsub v30.8h, v2.8h, v10.8h
+		{inst: VCMEQ, arr: VectorArrangement4S, exp: []byte{0x5e, 0x8c, 0xaa, 0x6e}},     // cmeq v30.4s, v2.4s, v10.4s
+		{inst: VCMGT, arr: VectorArrangement2D, exp: []byte{0x5e, 0x34, 0xea, 0x4e}},     // cmgt v30.2d, v2.2d, v10.2d
+		{inst: VCMHI, arr: VectorArrangement16B, exp: []byte{0x5e, 0x34, 0x2a, 0x6e}},    // cmhi v30.16b, v2.16b, v10.16b
+		{inst: VCMGE, arr: VectorArrangement8H, exp: []byte{0x5e, 0x3c, 0x6a, 0x4e}},     // cmge v30.8h, v2.8h, v10.8h
+		{inst: VCMHS, arr: VectorArrangement4S, exp: []byte{0x5e, 0x3c, 0xaa, 0x6e}},     // cmhs v30.4s, v2.4s, v10.4s
+		{inst: VSSHL, arr: VectorArrangement2D, exp: []byte{0x5e, 0x44, 0xea, 0x4e}},     // sshl v30.2d, v2.2d, v10.2d
+		{inst: VUSHL, arr: VectorArrangement16B, exp: []byte{0x5e, 0x44, 0x2a, 0x6e}},    // ushl v30.16b, v2.16b, v10.16b
+		{inst: VSMAX, arr: VectorArrangement4S, exp: []byte{0x5e, 0x64, 0xaa, 0x4e}},     // smax v30.4s, v2.4s, v10.4s
+		{inst: VUMAX, arr: VectorArrangement8H, exp: []byte{0x5e, 0x64, 0x6a, 0x6e}},     // umax v30.8h, v2.8h, v10.8h
+		{inst: VSMIN, arr: VectorArrangement16B, exp: []byte{0x5e, 0x6c, 0x2a, 0x4e}},    // smin v30.16b, v2.16b, v10.16b
+		{inst: VUMIN, arr: VectorArrangement4S, exp: []byte{0x5e, 0x6c, 0xaa, 0x6e}},     // umin v30.4s, v2.4s, v10.4s
+		{inst: VURHADD, arr: VectorArrangement8H, exp: []byte{0x5e, 0x14, 0x6a, 0x6e}},   // urhadd v30.8h, v2.8h, v10.8h
+		{inst: VMUL, arr: VectorArrangement4S, exp: []byte{0x5e, 0x9c, 0xaa, 0x4e}},      // mul v30.4s, v2.4s, v10.4s
+		{inst: VADDP, arr: VectorArrangement4S, exp: []byte{0x5e, 0xbc, 0xaa, 0x4e}},     // addp v30.4s, v2.4s, v10.4s
+		{inst: VSQRDMULH, arr: VectorArrangement8H, exp: []byte{0x5e, 0xb4, 0x6a, 0x6e}}, // sqrdmulh v30.8h, v2.8h, v10.8h
+		{inst: VAND, arr: VectorArrangement16B, exp: []byte{0x5e, 0x1c, 0x2a, 0x4e}},     // and v30.16b, v2.16b, v10.16b
+		{inst: VBIC, arr: VectorArrangement16B, exp: []byte{0x5e, 0x1c, 0x6a, 0x4e}},     // bic v30.16b, v2.16b, v10.16b
+		{inst: VORR, arr: VectorArrangement16B, exp: []byte{0x5e, 0x1c, 0xaa, 0x4e}},     // orr v30.16b, v2.16b, v10.16b
+		{inst: VEOR, arr: VectorArrangement16B, exp: []byte{0x5e, 0x1c, 0x2a, 0x6e}},     // eor v30.16b, v2.16b, v10.16b
+		{inst: VBIT, arr: VectorArrangement16B, exp: []byte{0x5e, 0x1c, 0xaa, 0x6e}},     // bit v30.16b, v2.16b, v10.16b
+		{inst: VFSUB, arr: VectorArrangement4S, exp: []byte{0x5e, 0xd4, 0xaa, 0x4e}},     // fsub v30.4s, v2.4s, v10.4s
+		{inst: VFSUB, arr: VectorArrangement2D, exp: []byte{0x5e, 0xd4, 0xea, 0x4e}},     // fsub v30.2d, v2.2d, v10.2d
+		{inst: VFMUL, arr: VectorArrangement4S, exp: []byte{0x5e, 0xdc, 0x2a, 0x6e}},     // fmul v30.4s, v2.4s, v10.4s
+		{inst: VFMUL, arr: VectorArrangement2D, exp: []byte{0x5e, 0xdc, 0x6a, 0x6e}},     // fmul v30.2d, v2.2d, v10.2d
+		{inst: VFDIV, arr: VectorArrangement4S, exp: []byte{0x5e, 0xfc, 0x2a, 0x6e}},     // fdiv v30.4s, v2.4s, v10.4s
+		{inst: VFDIV, arr: VectorArrangement2D, exp: []byte{0x5e, 0xfc, 0x6a, 0x6e}},     // fdiv v30.2d, v2.2d, v10.2d
+		{inst: VFMAX, arr: VectorArrangement4S, exp: []byte{0x5e, 0xf4, 0x2a, 0x4e}},     // fmax v30.4s, v2.4s, v10.4s
+		{inst: VFMAX, arr: VectorArrangement2D, exp: []byte{0x5e, 0xf4, 0x6a, 0x4e}},     // fmax v30.2d, v2.2d, v10.2d
+		{inst: VFMIN, arr: VectorArrangement4S, exp: []byte{0x5e, 0xf4, 0xaa, 0x4e}},     // fmin v30.4s, v2.4s, v10.4s
+		{inst: VFMIN, arr: VectorArrangement2D, exp: []byte{0x5e, 0xf4, 0xea, 0x4e}},     // fmin v30.2d, v2.2d, v10.2d
+		{inst: VFCMEQ, arr: VectorArrangement4S, exp: []byte{0x5e, 0xe4, 0x2a, 0x4e}},    // fcmeq v30.4s, v2.4s, v10.4s
+		{inst: VFCMEQ, arr: VectorArrangement2D, exp: []byte{0x5e, 0xe4, 0x6a, 0x4e}},    // fcmeq v30.2d, v2.2d, v10.2d
+		{inst: VFCMGE, arr: VectorArrangement4S, exp: []byte{0x5e, 0xe4, 0x2a, 0x6e}},    // fcmge v30.4s, v2.4s, v10.4s
+		{inst: VFCMGE, arr: VectorArrangement2D, exp: []byte{0x5e, 0xe4, 0x6a, 0x6e}},    // fcmge v30.2d, v2.2d, v10.2d
+		{inst: VFCMGT, arr: VectorArrangement4S, exp: []byte{0x5e, 0xe4, 0xaa, 0x6e}},    // fcmgt v30.4s, v2.4s, v10.4s
+		{inst: VFCMGT, arr: VectorArrangement2D, exp: []byte{0x5e, 0xe4, 0xea, 0x6e}},    // fcmgt v30.2d, v2.2d, v10.2d
+		{inst: VSMULL, arr: VectorArrangement8B, exp: []byte{0x5e, 0xc0, 0x2a, 0x0e}},    // smull v30.8h, v2.8b, v10.8b
+		{inst: VSMULL, arr: VectorArrangement8H, exp: []byte{0x5e, 0xc0, 0x6a, 0x4e}},    // smull2 v30.4s, v2.8h, v10.8h
+		{inst: VUMULL, arr: VectorArrangement2S, exp: []byte{0x5e, 0xc0, 0xaa, 0x2e}},    // umull v30.2d, v2.2s, v10.2s
+		{inst: VUMULL, arr: VectorArrangement16B, exp: []byte{0x5e, 0xc0, 0x2a, 0x6e}},   // umull2 v30.8h, v2.16b, v10.16b
+		{inst: VTBL, arr: VectorArrangement16B, exp: []byte{0x5e, 0x00, 0x0a, 0x4e}},     // tbl v30.16b, { v2.16b }, v10.16b
 	}
 
 	for _, tt := range tests {
 		tc := tt
-		t.Run(InstructionName(tc.inst), func(t *testing.T) {
+		n := &NodeImpl{
+			Instruction:       tc.inst,
+			Types:             OperandTypesTwoVectorRegistersToVectorRegister,
+			SrcReg:            srcReg,
+			SrcReg2:           srcReg2,
+			DstReg:            dstReg,
+			VectorArrangement: tc.arr,
+		}
+		t.Run(n.String(), func(t *testing.T) {
 			a := NewAssemblerImpl(asm.NilRegister)
-			err := a.EncodeVectorRegisterToVectorRegister(&NodeImpl{
-				Instruction: tc.inst,
-				SrcReg:      x1,
-				DstReg:      x2,
-			})
+			err := a.EncodeTwoVectorRegistersToVectorRegister(n)
 			require.NoError(t, err)
-			actual, err := a.Assemble()
+			actual := a.Buf.Bytes()
+			require.Equal(t, tc.exp, actual, hex.EncodeToString(actual))
+		})
+	}
+}
+
+func TestAssemblerImpl_EncodeVectorRegisterToRegister(t *testing.T) {
+	srcReg, dstReg := RegV2, RegR10
+	tests := []struct {
+		inst  asm.Instruction
+		arr   VectorArrangement
+		index VectorIndex
+		exp   []byte
+	}{
+		// These are not supported in golang-asm, so test it here instead of integration tests.
+		{inst: VUMOV, arr: VectorArrangementB, index: 15, exp: []byte{0x4a, 0x3c, 0x1f, 0x0e}}, // umov w10, v2.b[15]
+		{inst: VUMOV, arr: VectorArrangementH, index: 7, exp: []byte{0x4a, 0x3c, 0x1e, 0x0e}},  // umov w10, v2.h[7]
+		{inst: VUMOV, arr: VectorArrangementS, index: 3, exp: []byte{0x4a, 0x3c, 0x1c, 0x0e}},  // mov w10, v2.s[3]
+		{inst: VUMOV, arr: VectorArrangementD, index: 1, exp: []byte{0x4a, 0x3c, 0x18, 0x4e}},  // mov x10, v2.d[1]
+		{inst: VSMOV, arr: VectorArrangementB, index: 15, exp: []byte{0x4a, 0x2c, 0x1f, 0x0e}}, // smov w10, v2.b[15]
+		{inst: VSMOV, arr: VectorArrangementH, index: 7, exp: []byte{0x4a, 0x2c, 0x1e, 0x0e}},  // smov w10, v2.h[7]
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		n := &NodeImpl{
+			Instruction:       tc.inst,
+			Types:             OperandTypesVectorRegisterToRegister,
+			SrcReg:            srcReg,
+			DstReg:            dstReg,
+			VectorArrangement: tc.arr,
+			SrcVectorIndex:    tc.index,
+			DstVectorIndex:    VectorIndexNone,
+		}
+		t.Run(n.String(), func(t *testing.T) {
+			a := NewAssemblerImpl(asm.NilRegister)
+			err := a.EncodeVectorRegisterToRegister(n)
 			require.NoError(t, err)
+			actual := a.Buf.Bytes()
+			require.Equal(t, tc.exp, actual, hex.EncodeToString(actual))
+		})
+	}
+}
 
+func TestAssemblerImpl_EncodeRegisterToVectorRegister(t *testing.T) {
+	srcReg, dstReg := RegR10, RegV2
+	tests := []struct {
+		inst  asm.Instruction
+		arr   VectorArrangement
+		index VectorIndex
+		exp   []byte
+	}{
+		{inst: VDUP, arr: VectorArrangement16B, index: VectorIndexNone, exp: []byte{0x42, 0x0d, 0x01, 0x4e}}, // dup v2.16b, w10
+		{inst: VDUP, arr: VectorArrangement8H, index: VectorIndexNone, exp: []byte{0x42, 0x0d, 0x02, 0x4e}},  // dup v2.8h, w10
+		{inst: VDUP, arr: VectorArrangement4S, index: VectorIndexNone, exp: []byte{0x42, 0x0d, 0x04, 0x4e}},  // dup v2.4s, w10
+		{inst: VDUP, arr: VectorArrangement2D, index: VectorIndexNone, exp: []byte{0x42, 0x0d, 0x08, 0x4e}},  // dup v2.2d, x10
+		{inst: VMOV, arr: VectorArrangementB, index: 3, exp: []byte{0x42, 0x1d, 0x07, 0x4e}},                 // mov v2.b[3], w10
+		{inst: VMOV, arr: VectorArrangementS, index: 3, exp: []byte{0x42, 0x1d, 0x1c, 0x4e}},                 // mov v2.s[3], w10
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		n := &NodeImpl{
+			Instruction:       tc.inst,
+			Types:             OperandTypesRegisterToVectorRegister,
+			SrcReg:            srcReg,
+			DstReg:            dstReg,
+			VectorArrangement: tc.arr,
+			SrcVectorIndex:    VectorIndexNone,
+			DstVectorIndex:    tc.index,
+		}
+		t.Run(n.String(), func(t *testing.T) {
+			a := NewAssemblerImpl(asm.NilRegister)
+			err := a.EncodeRegisterToVectorRegister(n)
+			require.NoError(t, err)
+			actual := a.Buf.Bytes()
 			require.Equal(t, tc.exp, actual, hex.EncodeToString(actual))
 		})
 	}
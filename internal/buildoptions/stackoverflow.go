@@ -1,7 +1,15 @@
 package buildoptions
 
 // CallStackCeiling is the maximum WebAssembly call stack height. This allows wazero to raise
-// wasm.ErrCallStackOverflow instead of overflowing the Go runtime.
+// wasmruntime.ErrRuntimeCallStackOverflow instead of overflowing the Go runtime.
 //
-// The default value should suffice for most use cases. Those wishing to change this can via `go build -ldflags`.
+// The default value should suffice for most use cases. Those wishing to change this can via
+// wazero.RuntimeConfig WithCallStackCeiling.
 var CallStackCeiling = 2000
+
+// ValueStackCeiling is the maximum size in bytes of the WebAssembly value stack. This allows wazero to raise
+// wasmruntime.ErrRuntimeValueStackOverflow instead of growing the stack until the host runs out of memory.
+//
+// The default value should suffice for most use cases. Those wishing to change this can via
+// wazero.RuntimeConfig WithValueStackCeiling.
+var ValueStackCeiling = 64 << 20 // 64 MiB
@@ -0,0 +1,13 @@
+package compiler
+
+import (
+	"testing"
+	"unsafe"
+
+	"github.com/tetratelabs/wazero/internal/testing/require"
+)
+
+func TestFuelContextOffsetInAmd64Engine(t *testing.T) {
+	var ctx callEngine
+	require.Equal(t, int(unsafe.Offsetof(ctx.fuelAddress)), amd64CallEngineFuelContextFuelAddressOffset)
+}
@@ -71,13 +71,21 @@ type compiler interface {
 	//
 	// See wasm.CallIndirect
 	compileCallIndirect(o *wazeroir.OperationCallIndirect) error
+	// compileTailCall adds instructions to replace the current call frame with the one of the function of the given
+	// index, and jump into it. The function arguments must be the only values on the current frame's stack.
+	// See wasm.OpcodeReturnCall
+	compileTailCall(o *wazeroir.OperationTailCall) error
+	// compileTailCallIndirect is the same as compileTailCall except that the target function is chosen as in
+	// compileCallIndirect.
+	// See wasm.OpcodeReturnCallIndirect
+	compileTailCallIndirect(o *wazeroir.OperationTailCallIndirect) error
 	// compileDrop adds instructions to drop values within the given inclusive range from the value stack.
 	// See wazeroir.OperationDrop
 	compileDrop(o *wazeroir.OperationDrop) error
 	// compileSelect uses top three values on the stack. For example, if we have stack as [..., x1, x2, c]
 	// and the value "c" equals zero, then the stack results in [..., x1], otherwise, [..., x2].
 	// See wasm.OpcodeSelect
-	compileSelect() error
+	compileSelect(o *wazeroir.OperationSelect) error
 	// compilePick adds instructions to copy a value on the given location in the Wasm value stack,
 	// and push the copied value onto the top of the stack.
 	// See wazeroir.OperationPick
@@ -356,6 +364,23 @@ type compiler interface {
 	//
 	// https://www.w3.org/TR/2022/WD-wasm-core-2-20220419/appendix/changes.html#bulk-memory-and-table-instructions
 	compileMemoryFill() error
+	// compileSelectMemory adds instructions to make the subsequent memory instructions access the memory of the given
+	// index in wasm.FeatureMultiMemory, until the memory of index zero is selected again.
+	compileSelectMemory(index uint32) error
+	// compileReloadMemoryLength adds instructions to reload the length of the first memory of the current module into
+	// the module context. This is needed before accessing a shared memory in wasm.FeatureThreads, as other threads
+	// can grow it, while the module context is otherwise only updated when entering a function of another module.
+	compileReloadMemoryLength() error
+	// compileBuiltinMemoryCopy adds instructions to perform operations corresponding to the wasm.OpcodeMemoryCopyName
+	// instruction by calling a Go function. This is used between two different memories in wasm.FeatureMultiMemory, and
+	// on memories indexed with i64 in wasm.FeatureMemory64.
+	compileBuiltinMemoryCopy(*wazeroir.OperationMemoryCopy) error
+	// compileBuiltinMemoryFill adds instructions to perform operations corresponding to the wasm.OpcodeMemoryFillName
+	// instruction on a memory indexed with i64 in wasm.FeatureMemory64 by calling a Go function.
+	compileBuiltinMemoryFill() error
+	// compileBuiltinMemoryInit adds instructions to perform operations corresponding to the wasm.OpcodeMemoryInitName
+	// instruction on a memory indexed with i64 in wasm.FeatureMemory64 by calling a Go function.
+	compileBuiltinMemoryInit(*wazeroir.OperationMemoryInit) error
 	// compileTableInit adds instructions to perform operations corresponding to the wasm.OpcodeTableInit instruction in
 	// wasm.FeatureBulkMemoryOperations.
 	//
@@ -404,9 +429,202 @@ type compiler interface {
 	//
 	// https://www.w3.org/TR/2022/WD-wasm-core-2-20220419/valid/instructions.html#xref-syntax-instructions-syntax-instr-table-mathsf-table-fill-x
 	compileTableFill(*wazeroir.OperationTableFill) error
-	// compileConstV128 adds instructions to push a constant V128 value onto the stack.
+	// compileV128Const adds instructions to push a constant V128 value onto the stack.
 	// See wasm.OpcodeVecV128Const
-	compileConstV128(*wazeroir.OperationConstV128) error
-	// compileAddV128 adds instruction to add two vector values whose shape is specified as `o.Shape`.
-	compileAddV128(o *wazeroir.OperationAddV128) error
+	compileV128Const(*wazeroir.OperationV128Const) error
+	// compileV128Add adds instruction to add two vector values whose shape is specified as `o.Shape`.
+	compileV128Add(o *wazeroir.OperationV128Add) error
+	// compileV128Sub adds instructions to subtract two vector values whose shape is specified as `o.Shape`.
+	// See wazeroir.OperationV128Sub
+	compileV128Sub(o *wazeroir.OperationV128Sub) error
+	// compileV128Load adds instructions to load a vector value from the memory.
+	// See wazeroir.OperationV128Load
+	compileV128Load(o *wazeroir.OperationV128Load) error
+	// compileV128LoadLane adds instructions to load a lane of a vector value from the memory.
+	// See wazeroir.OperationV128LoadLane
+	compileV128LoadLane(o *wazeroir.OperationV128LoadLane) error
+	// compileV128Store adds instructions to store a vector value into the memory.
+	// See wazeroir.OperationV128Store
+	compileV128Store(o *wazeroir.OperationV128Store) error
+	// compileV128StoreLane adds instructions to store a lane of a vector value into the memory.
+	// See wazeroir.OperationV128StoreLane
+	compileV128StoreLane(o *wazeroir.OperationV128StoreLane) error
+	// compileV128ExtractLane adds instructions to extract a lane of a vector value as a scalar value.
+	// See wazeroir.OperationV128ExtractLane
+	compileV128ExtractLane(o *wazeroir.OperationV128ExtractLane) error
+	// compileV128ReplaceLane adds instructions to replace a lane of a vector value with a scalar value.
+	// See wazeroir.OperationV128ReplaceLane
+	compileV128ReplaceLane(o *wazeroir.OperationV128ReplaceLane) error
+	// compileV128Splat adds instructions to create a vector value whose lanes all have the given scalar value.
+	// See wazeroir.OperationV128Splat
+	compileV128Splat(o *wazeroir.OperationV128Splat) error
+	// compileV128Shuffle adds instructions to select lanes from two vector values with the constant lane indexes.
+	// See wazeroir.OperationV128Shuffle
+	compileV128Shuffle(o *wazeroir.OperationV128Shuffle) error
+	// compileV128Swizzle adds instructions to select lanes from a vector value with the lane indexes given as a vector value.
+	// See wazeroir.OperationV128Swizzle
+	compileV128Swizzle(o *wazeroir.OperationV128Swizzle) error
+	// compileV128AnyTrue adds instructions to check if any bit of a vector value is set.
+	// See wazeroir.OperationV128AnyTrue
+	compileV128AnyTrue(o *wazeroir.OperationV128AnyTrue) error
+	// compileV128AllTrue adds instructions to check if all lanes of a vector value are non-zero.
+	// See wazeroir.OperationV128AllTrue
+	compileV128AllTrue(o *wazeroir.OperationV128AllTrue) error
+	// compileV128BitMask adds instructions to extract the highest bit of each lane of a vector value.
+	// See wazeroir.OperationV128BitMask
+	compileV128BitMask(o *wazeroir.OperationV128BitMask) error
+	// compileV128And adds instructions to perform bitwise AND on two vector values.
+	// See wazeroir.OperationV128And
+	compileV128And(o *wazeroir.OperationV128And) error
+	// compileV128Not adds instructions to perform bitwise NOT on a vector value.
+	// See wazeroir.OperationV128Not
+	compileV128Not(o *wazeroir.OperationV128Not) error
+	// compileV128Or adds instructions to perform bitwise OR on two vector values.
+	// See wazeroir.OperationV128Or
+	compileV128Or(o *wazeroir.OperationV128Or) error
+	// compileV128Xor adds instructions to perform bitwise XOR on two vector values.
+	// See wazeroir.OperationV128Xor
+	compileV128Xor(o *wazeroir.OperationV128Xor) error
+	// compileV128Bitselect adds instructions to select bits from two vector values with a vector mask.
+	// See wazeroir.OperationV128Bitselect
+	compileV128Bitselect(o *wazeroir.OperationV128Bitselect) error
+	// compileV128AndNot adds instructions to perform bitwise AND of a vector value and the complement of another one.
+	// See wazeroir.OperationV128AndNot
+	compileV128AndNot(o *wazeroir.OperationV128AndNot) error
+	// compileV128Shl adds instructions to perform left shift on each lane of a vector value.
+	// See wazeroir.OperationV128Shl
+	compileV128Shl(o *wazeroir.OperationV128Shl) error
+	// compileV128Shr adds instructions to perform right shift on each lane of a vector value.
+	// See wazeroir.OperationV128Shr
+	compileV128Shr(o *wazeroir.OperationV128Shr) error
+	// compileV128Cmp adds instructions to compare lanes of two vector values.
+	// See wazeroir.OperationV128Cmp
+	compileV128Cmp(o *wazeroir.OperationV128Cmp) error
+	// compileV128AddSat adds instructions to add two vector values with saturation.
+	// See wazeroir.OperationV128AddSat
+	compileV128AddSat(o *wazeroir.OperationV128AddSat) error
+	// compileV128SubSat adds instructions to subtract two vector values with saturation.
+	// See wazeroir.OperationV128SubSat
+	compileV128SubSat(o *wazeroir.OperationV128SubSat) error
+	// compileV128Mul adds instructions to multiply two vector values.
+	// See wazeroir.OperationV128Mul
+	compileV128Mul(o *wazeroir.OperationV128Mul) error
+	// compileV128Div adds instructions to divide two vector values.
+	// See wazeroir.OperationV128Div
+	compileV128Div(o *wazeroir.OperationV128Div) error
+	// compileV128Neg adds instructions to negate each lane of a vector value.
+	// See wazeroir.OperationV128Neg
+	compileV128Neg(o *wazeroir.OperationV128Neg) error
+	// compileV128Sqrt adds instructions to take the square root of each lane of a vector value.
+	// See wazeroir.OperationV128Sqrt
+	compileV128Sqrt(o *wazeroir.OperationV128Sqrt) error
+	// compileV128Abs adds instructions to take the absolute value of each lane of a vector value.
+	// See wazeroir.OperationV128Abs
+	compileV128Abs(o *wazeroir.OperationV128Abs) error
+	// compileV128Popcnt adds instructions to count the set bits of each lane of a vector value.
+	// See wazeroir.OperationV128Popcnt
+	compileV128Popcnt(o *wazeroir.OperationV128Popcnt) error
+	// compileV128Min adds instructions to take the minimum of each lane of two vector values.
+	// See wazeroir.OperationV128Min
+	compileV128Min(o *wazeroir.OperationV128Min) error
+	// compileV128Max adds instructions to take the maximum of each lane of two vector values.
+	// See wazeroir.OperationV128Max
+	compileV128Max(o *wazeroir.OperationV128Max) error
+	// compileV128AvgrU adds instructions to take the unsigned rounding average of each lane of two vector values.
+	// See wazeroir.OperationV128AvgrU
+	compileV128AvgrU(o *wazeroir.OperationV128AvgrU) error
+	// compileV128Pmin adds instructions to take the pseudo-minimum of each lane of two vector values.
+	// See wazeroir.OperationV128Pmin
+	compileV128Pmin(o *wazeroir.OperationV128Pmin) error
+	// compileV128Pmax adds instructions to take the pseudo-maximum of each lane of two vector values.
+	// See wazeroir.OperationV128Pmax
+	compileV128Pmax(o *wazeroir.OperationV128Pmax) error
+	// compileV128Ceil adds instructions to round each lane of a vector value up to an integer.
+	// See wazeroir.OperationV128Ceil
+	compileV128Ceil(o *wazeroir.OperationV128Ceil) error
+	// compileV128Floor adds instructions to round each lane of a vector value down to an integer.
+	// See wazeroir.OperationV128Floor
+	compileV128Floor(o *wazeroir.OperationV128Floor) error
+	// compileV128Trunc adds instructions to round each lane of a vector value toward zero to an integer.
+	// See wazeroir.OperationV128Trunc
+	compileV128Trunc(o *wazeroir.OperationV128Trunc) error
+	// compileV128Nearest adds instructions to round each lane of a vector value to the nearest integer, ties to even.
+	// See wazeroir.OperationV128Nearest
+	compileV128Nearest(o *wazeroir.OperationV128Nearest) error
+	// compileV128Extend adds instructions to extend the half of lanes of a vector value into the double width.
+	// See wazeroir.OperationV128Extend
+	compileV128Extend(o *wazeroir.OperationV128Extend) error
+	// compileV128ExtMul adds instructions to multiply the half of lanes of two vector values into the double width.
+	// See wazeroir.OperationV128ExtMul
+	compileV128ExtMul(o *wazeroir.OperationV128ExtMul) error
+	// compileV128Q15mulrSatS adds instructions to perform the saturating, rounding Q-format multiplication on two vector values.
+	// See wazeroir.OperationV128Q15mulrSatS
+	compileV128Q15mulrSatS(o *wazeroir.OperationV128Q15mulrSatS) error
+	// compileV128ExtAddPairwise adds instructions to add the adjacent pairs of lanes of a vector value into the double width.
+	// See wazeroir.OperationV128ExtAddPairwise
+	compileV128ExtAddPairwise(o *wazeroir.OperationV128ExtAddPairwise) error
+	// compileV128FloatPromote adds instructions to promote the lower two f32 lanes of a vector value to f64 lanes.
+	// See wazeroir.OperationV128FloatPromote
+	compileV128FloatPromote(o *wazeroir.OperationV128FloatPromote) error
+	// compileV128FloatDemote adds instructions to demote the f64 lanes of a vector value to the lower two f32 lanes.
+	// See wazeroir.OperationV128FloatDemote
+	compileV128FloatDemote(o *wazeroir.OperationV128FloatDemote) error
+	// compileV128FConvertFromI adds instructions to convert the integer lanes of a vector value into float lanes.
+	// See wazeroir.OperationV128FConvertFromI
+	compileV128FConvertFromI(o *wazeroir.OperationV128FConvertFromI) error
+	// compileV128Dot adds instructions to perform the dot product of the signed 16-bit lanes of two vector values.
+	// See wazeroir.OperationV128Dot
+	compileV128Dot(o *wazeroir.OperationV128Dot) error
+	// compileV128Narrow adds instructions to narrow the lanes of two vector values into the half width with saturation.
+	// See wazeroir.OperationV128Narrow
+	compileV128Narrow(o *wazeroir.OperationV128Narrow) error
+	// compileV128ITruncSatFromF adds instructions to convert the float lanes of a vector value into integer lanes with saturation.
+	// See wazeroir.OperationV128ITruncSatFromF
+	compileV128ITruncSatFromF(o *wazeroir.OperationV128ITruncSatFromF) error
+	// compileAtomicLoad adds instructions to perform an atomic load.
+	// See wazeroir.OperationAtomicLoad
+	compileAtomicLoad(o *wazeroir.OperationAtomicLoad) error
+	// compileAtomicStore adds instructions to perform an atomic store.
+	// See wazeroir.OperationAtomicStore
+	compileAtomicStore(o *wazeroir.OperationAtomicStore) error
+	// compileAtomicRMW adds instructions to perform an atomic read-modify-write.
+	// See wazeroir.OperationAtomicRMW
+	compileAtomicRMW(o *wazeroir.OperationAtomicRMW) error
+	// compileAtomicRMWCmpxchg adds instructions to perform an atomic compare-and-exchange.
+	// See wazeroir.OperationAtomicRMWCmpxchg
+	compileAtomicRMWCmpxchg(o *wazeroir.OperationAtomicRMWCmpxchg) error
+	// compileAtomicMemoryWait adds instructions to wait for a notification on a shared memory address.
+	// See wazeroir.OperationAtomicMemoryWait
+	compileAtomicMemoryWait(o *wazeroir.OperationAtomicMemoryWait) error
+	// compileAtomicMemoryNotify adds instructions to notify the waiters on a shared memory address.
+	// See wazeroir.OperationAtomicMemoryNotify
+	compileAtomicMemoryNotify(o *wazeroir.OperationAtomicMemoryNotify) error
+	// compileAtomicFence adds instructions to order the memory accesses around it.
+	// See wazeroir.OperationAtomicFence
+	compileAtomicFence(o *wazeroir.OperationAtomicFence) error
+	// compileTry notifies compilers of the beginning of the protected region of a try block.
+	// See wazeroir.OperationTry
+	compileTry(o *wazeroir.OperationTry) error
+	// compileCatch notifies compilers of the beginning of a handler of a try block, which is entered by the engine
+	// with the values on the stack when catching an exception.
+	// Like compileLabel, this returns true if the compiler decided to skip the entire handler.
+	// See wazeroir.OperationCatch
+	compileCatch(o *wazeroir.OperationCatch) (skipThisHandler bool, err error)
+	// compileDelegate notifies compilers of the end of the protected region of a try block without handlers.
+	// See wazeroir.OperationDelegate
+	compileDelegate(o *wazeroir.OperationDelegate) error
+	// compileThrow adds instructions to raise an exception with the values on the stack, by making the builtin
+	// function call which unwinds the stack to the handler.
+	// See wazeroir.OperationThrow
+	compileThrow(o *wazeroir.OperationThrow) error
+	// compileRethrow is the same as compileThrow except that this raises the caught exception whose handle is on top
+	// of the stack.
+	// See wazeroir.OperationRethrow
+	compileRethrow() error
+	// compileConsumeFuel adds instructions to subtract the cost from the fuel of the call, or to exit with
+	// nativeCallStatusCodeFuelExhausted if the fuel is less than the cost.
+	// See wazeroir.OperationConsumeFuel
+	compileConsumeFuel(o *wazeroir.OperationConsumeFuel) error
+	// resolveTryBlocks returns the try blocks of the function, and must be called after compile.
+	resolveTryBlocks() []*tryBlock
 }
@@ -0,0 +1,150 @@
+package compiler
+
+import (
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/internal/asm"
+	"github.com/tetratelabs/wazero/internal/wasm"
+	"github.com/tetratelabs/wazero/internal/wazeroir"
+)
+
+// tryBlock is the protected region of a try block in code.codeSegment, compiled from wazeroir.OperationTry.
+type tryBlock struct {
+	// begin and end are the offsets in code.codeSegment which delimit the protected region. A call frame is in the
+	// region when its return address, relative to code.codeSegment, is in (begin, end].
+	begin, end uint64
+	// stackHeight is the same as wazeroir.OperationTry StackHeight.
+	stackHeight uint64
+	// catches are the handlers of this try block in the order of appearance.
+	catches []*catchHandler
+	// next is the try block which handles the exceptions not caught by catches, or nil if they propagate to the
+	// caller.
+	next *tryBlock
+}
+
+// catchHandler is a handler of tryBlock, compiled from wazeroir.OperationCatch.
+type catchHandler struct {
+	// offset is the offset in code.codeSegment where the handler begins.
+	offset   uint64
+	tagIndex uint32
+	catchAll bool
+}
+
+// findHandler returns the handler of exc for the call frame whose return address is at the given offset of
+// code.codeSegment, along with the try block of the handler. This returns nil if there's no such handler.
+func findHandler(tryBlocks []*tryBlock, offset uint64, tags []*wasm.TagInstance, exc *api.Exception) (*tryBlock, *catchHandler) {
+	var tb *tryBlock
+	// Nested try blocks come later than their enclosing ones, so search backwards.
+	for i := len(tryBlocks) - 1; i >= 0; i-- {
+		if b := tryBlocks[i]; b.begin < offset && offset <= b.end {
+			tb = b
+			break
+		}
+	}
+	for ; tb != nil; tb = tb.next {
+		for _, c := range tb.catches {
+			if c.catchAll || exc.Tag == api.Tag(tags[c.tagIndex]) {
+				return tb, c
+			}
+		}
+	}
+	return nil, nil
+}
+
+// tryBlockTable tracks the try blocks while compiling a function, and is shared by the architecture-specific
+// compilers.
+type tryBlockTable struct {
+	blocks []*tryBlock
+	// compiling holds the try blocks by wazeroir.OperationTry FrameID until the code is assembled.
+	compiling map[uint32]*compilingTryBlock
+}
+
+// compilingTryBlock holds the state of tryBlock only used during compilation.
+type compilingTryBlock struct {
+	block      *tryBlock
+	begin, end asm.Node
+	// catches are the beginning of the handlers, index-correlated with tryBlock.catches.
+	catches []asm.Node
+	// valueTypes are the types of the values below the try block, which are kept in the handlers.
+	valueTypes []runtimeValueType
+}
+
+// begin records the beginning of the try block of o at the node begin, below which the given values are.
+func (t *tryBlockTable) begin(o *wazeroir.OperationTry, begin asm.Node, stack *runtimeValueLocationStack) {
+	if t.compiling == nil {
+		t.compiling = map[uint32]*compilingTryBlock{}
+	}
+	tb := &compilingTryBlock{block: &tryBlock{stackHeight: uint64(o.StackHeight)}, begin: begin}
+	for i := 0; i < o.StackHeight; i++ {
+		tb.valueTypes = append(tb.valueTypes, stack.stack[i].valueType)
+	}
+	if o.Outer != nil { // The outer try block is compiled as well as it encloses this one.
+		tb.block.next = t.compiling[*o.Outer].block
+	}
+	t.compiling[o.FrameID] = tb
+	t.blocks = append(t.blocks, tb.block)
+}
+
+// compiled returns the try block of the given FrameID, or nil if it is never reached.
+func (t *tryBlockTable) compiled(frameID uint32) *compilingTryBlock {
+	return t.compiling[frameID]
+}
+
+// addCatch records the handler of o which begins at the node begin. The first handler also ends the protected region.
+func (t *tryBlockTable) addCatch(o *wazeroir.OperationCatch, begin asm.Node) {
+	tb := t.compiling[o.FrameID]
+	if tb.end == nil {
+		tb.end = begin
+	}
+	tb.catches = append(tb.catches, begin)
+	tb.block.catches = append(tb.block.catches, &catchHandler{tagIndex: o.TagIndex, catchAll: o.CatchAll})
+}
+
+// delegate records the end of the protected region of the try block of o at the node end.
+func (t *tryBlockTable) delegate(o *wazeroir.OperationDelegate, end asm.Node) {
+	tb := t.compiling[o.FrameID]
+	tb.end = end
+	tb.block.next = nil
+	if o.Target != nil {
+		// The target always encloses this try block, so it is compiled as well.
+		tb.block.next = t.compiling[*o.Target].block
+	}
+}
+
+// resolve fills the offsets of the try blocks once the code is assembled, and returns them.
+func (t *tryBlockTable) resolve() []*tryBlock {
+	for _, tb := range t.compiling {
+		tb.block.begin, tb.block.end = tb.begin.OffsetInBinary(), tb.end.OffsetInBinary()
+		for i, n := range tb.catches {
+			tb.block.catches[i].offset = n.OffsetInBinary()
+		}
+	}
+	return t.blocks
+}
+
+// pushHandlerValues pushes the locations of the values on entering the handler of the try block, which are all on the
+// stack: the values below the try block, followed by the i64 handle of the caught exception and, unless o.CatchAll,
+// the values of the exception.
+func (tb *compilingTryBlock) pushHandlerValues(stack *runtimeValueLocationStack, o *wazeroir.OperationCatch, tags []*wasm.FunctionType) {
+	for _, vt := range tb.valueTypes {
+		stack.pushRuntimeValueLocationOnStack().valueType = vt
+	}
+	stack.pushRuntimeValueLocationOnStack().valueType = runtimeValueTypeI64
+	if o.CatchAll {
+		return
+	}
+	for _, t := range tags[o.TagIndex].Params {
+		switch t {
+		case wasm.ValueTypeI32:
+			stack.pushRuntimeValueLocationOnStack().valueType = runtimeValueTypeI32
+		case wasm.ValueTypeI64, wasm.ValueTypeFuncref, wasm.ValueTypeExternref:
+			stack.pushRuntimeValueLocationOnStack().valueType = runtimeValueTypeI64
+		case wasm.ValueTypeF32:
+			stack.pushRuntimeValueLocationOnStack().valueType = runtimeValueTypeF32
+		case wasm.ValueTypeF64:
+			stack.pushRuntimeValueLocationOnStack().valueType = runtimeValueTypeF64
+		case wasm.ValueTypeV128:
+			stack.pushRuntimeValueLocationOnStack().valueType = runtimeValueTypeV128Lo
+			stack.pushRuntimeValueLocationOnStack().valueType = runtimeValueTypeV128Hi
+		}
+	}
+}
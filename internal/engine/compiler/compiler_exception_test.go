@@ -0,0 +1,71 @@
+package compiler
+
+import (
+	"testing"
+
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/internal/testing/require"
+	"github.com/tetratelabs/wazero/internal/wasm"
+)
+
+func TestFindHandler(t *testing.T) {
+	tags := []*wasm.TagInstance{{Type: &wasm.FunctionType{}}, {Type: &wasm.FunctionType{}}}
+
+	catchAll := &catchHandler{offset: 100, catchAll: true}
+	outer := &tryBlock{begin: 10, end: 90, catches: []*catchHandler{catchAll}}
+	catch1 := &catchHandler{offset: 80, tagIndex: 1}
+	inner := &tryBlock{begin: 20, end: 40, catches: []*catchHandler{catch1}, next: outer}
+	tryBlocks := []*tryBlock{outer, inner}
+
+	tests := []struct {
+		name            string
+		offset          uint64
+		tag             api.Tag
+		expectedBlock   *tryBlock
+		expectedHandler *catchHandler
+	}{
+		{name: "outside", offset: 5, tag: tags[1]},
+		{name: "begin is excluded", offset: 10, tag: tags[1]},
+		{name: "outer", offset: 15, tag: tags[1], expectedBlock: outer, expectedHandler: catchAll},
+		{name: "inner", offset: 40, tag: tags[1], expectedBlock: inner, expectedHandler: catch1},
+		{name: "inner to outer", offset: 30, tag: tags[0], expectedBlock: outer, expectedHandler: catchAll},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			tb, h := findHandler(tryBlocks, tc.offset, tags, &api.Exception{Tag: tc.tag})
+			require.Equal(t, tc.expectedBlock, tb)
+			require.Equal(t, tc.expectedHandler, h)
+		})
+	}
+}
+
+func TestCallEngine_PushExceptionHandle(t *testing.T) {
+	outer, inner, next := &api.Exception{}, &api.Exception{}, &api.Exception{}
+	me := &moduleEngine{stackLimits: wasm.DefaultStackLimits}
+	ce := me.newCallEngine()
+	ce.valueStackContext.stackBasePointer = 1
+	ce.valueStackContext.stackPointer = 1
+
+	ce.pushExceptionHandle(outer)
+	require.Equal(t, uint64(0), ce.valueStack[2])
+	require.Equal(t, uint64(2), ce.valueStackContext.stackPointer)
+
+	// A handler nested in the one of outer keeps outer.
+	ce.pushValue(3)
+	for i := 0; i < 3; i++ {
+		// Catching in a loop reuses the handle of the previous iteration, whose handler has ended.
+		ce.valueStackContext.stackPointer = 3
+		ce.pushExceptionHandle(inner)
+		require.Equal(t, uint64(1), ce.valueStack[4])
+		require.Equal(t, []caughtException{{exc: outer, stackPosition: 2}, {exc: inner, stackPosition: 4}}, ce.exceptions)
+	}
+
+	// Catching below the handlers ends them, even in another call frame.
+	ce.valueStackContext.stackBasePointer = 0
+	ce.valueStackContext.stackPointer = 1
+	ce.pushExceptionHandle(next)
+	require.Equal(t, uint64(0), ce.valueStack[1])
+	require.Equal(t, []caughtException{{exc: next, stackPosition: 1}}, ce.exceptions)
+}
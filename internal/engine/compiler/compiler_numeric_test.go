@@ -17,7 +17,7 @@ func TestCompiler_compileConsts(t *testing.T) {
 		wazeroir.OperationKindConstI64,
 		wazeroir.OperationKindConstF32,
 		wazeroir.OperationKindConstF64,
-		wazeroir.OperationKindConstV128,
+		wazeroir.OperationKindV128Const,
 	} {
 		op := op
 		t.Run(op.String(), func(t *testing.T) {
@@ -51,8 +51,8 @@ func TestCompiler_compileConsts(t *testing.T) {
 						err = compiler.compileConstF32(&wazeroir.OperationConstF32{Value: math.Float32frombits(uint32(val))})
 					case wazeroir.OperationKindConstF64:
 						err = compiler.compileConstF64(&wazeroir.OperationConstF64{Value: math.Float64frombits(val)})
-					case wazeroir.OperationKindConstV128:
-						err = compiler.compileConstV128(&wazeroir.OperationConstV128{Lo: val, Hi: ^val})
+					case wazeroir.OperationKindV128Const:
+						err = compiler.compileV128Const(&wazeroir.OperationV128Const{Lo: val, Hi: ^val})
 					}
 					require.NoError(t, err)
 
@@ -60,7 +60,7 @@ func TestCompiler_compileConsts(t *testing.T) {
 					loc := compiler.runtimeValueLocationStack().peek()
 					require.True(t, loc.onRegister())
 
-					if op == wazeroir.OperationKindConstV128 {
+					if op == wazeroir.OperationKindV128Const {
 						require.Equal(t, runtimeValueTypeV128Hi, loc.valueType)
 					}
 
@@ -76,7 +76,7 @@ func TestCompiler_compileConsts(t *testing.T) {
 
 					// Compiler status must be returned.
 					require.Equal(t, nativeCallStatusCodeReturned, env.compilerStatus())
-					if op == wazeroir.OperationKindConstV128 {
+					if op == wazeroir.OperationKindV128Const {
 						require.Equal(t, uint64(2), env.stackPointer()) // a vector value consists of two uint64.
 					} else {
 						require.Equal(t, uint64(1), env.stackPointer())
@@ -87,7 +87,7 @@ func TestCompiler_compileConsts(t *testing.T) {
 						require.Equal(t, uint32(val), env.stackTopAsUint32())
 					case wazeroir.OperationKindConstI64, wazeroir.OperationKindConstF64:
 						require.Equal(t, val, env.stackTopAsUint64())
-					case wazeroir.OperationKindConstV128:
+					case wazeroir.OperationKindV128Const:
 						lo, hi := env.stackTopAsV128()
 						require.Equal(t, val, lo)
 						require.Equal(t, ^val, hi)
@@ -843,7 +843,8 @@ func TestCompiler_compile_Min_Max_Copysign(t *testing.T) {
 				if math.IsNaN(float64(exp)) { // NaN cannot be compared with themselves, so we have to use IsNaN
 					require.True(t, math.IsNaN(float64(actual)))
 				} else {
-					require.Equal(t, exp, actual)
+					// Compare bits, so that the sign of zero is verified.
+					require.Equal(t, math.Float32bits(exp), math.Float32bits(actual))
 				}
 			},
 		},
@@ -860,7 +861,8 @@ func TestCompiler_compile_Min_Max_Copysign(t *testing.T) {
 				if math.IsNaN(exp) { // NaN cannot be compared with themselves, so we have to use IsNaN
 					require.True(t, math.IsNaN(actual))
 				} else {
-					require.Equal(t, exp, actual)
+					// Compare bits, so that the sign of zero is verified.
+					require.Equal(t, math.Float64bits(exp), math.Float64bits(actual))
 				}
 			},
 		},
@@ -877,7 +879,8 @@ func TestCompiler_compile_Min_Max_Copysign(t *testing.T) {
 				if math.IsNaN(float64(exp)) { // NaN cannot be compared with themselves, so we have to use IsNaN
 					require.True(t, math.IsNaN(float64(actual)))
 				} else {
-					require.Equal(t, exp, actual)
+					// Compare bits, so that the sign of zero is verified.
+					require.Equal(t, math.Float32bits(exp), math.Float32bits(actual))
 				}
 			},
 		},
@@ -894,7 +897,8 @@ func TestCompiler_compile_Min_Max_Copysign(t *testing.T) {
 				if math.IsNaN(exp) { // NaN cannot be compared with themselves, so we have to use IsNaN
 					require.True(t, math.IsNaN(actual))
 				} else {
-					require.Equal(t, exp, actual)
+					// Compare bits, so that the sign of zero is verified.
+					require.Equal(t, math.Float64bits(exp), math.Float64bits(actual))
 				}
 			},
 		},
@@ -938,7 +942,8 @@ func TestCompiler_compile_Min_Max_Copysign(t *testing.T) {
 		tc := tt
 		t.Run(tc.name, func(t *testing.T) {
 			for _, vs := range [][2]float64{
-				{100, -1.1}, {100, 0}, {0, 0}, {1, 1},
+				{100, -1.1}, {100, 0}, {0, 0}, {0, math.Copysign(0, -1)},
+				{math.Copysign(0, -1), 0}, {1, 1},
 				{-1, 100}, {100, 200}, {100.01234124, 100.01234124},
 				{100.01234124, -100.01234124}, {200.12315, 100},
 				{6.8719476736e+10 /* = 1 << 36 */, 100},
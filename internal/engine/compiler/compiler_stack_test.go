@@ -180,7 +180,7 @@ func TestCompiler_compilePick_v128(t *testing.T) {
 
 			// Set up the stack before picking.
 			if tc.isPickTargetOnRegister {
-				err = compiler.compileConstV128(&wazeroir.OperationConstV128{
+				err = compiler.compileV128Const(&wazeroir.OperationV128Const{
 					Lo: pickTargetLo, Hi: pickTargetHi,
 				})
 				require.NoError(t, err)
@@ -569,7 +569,7 @@ func TestCompiler_compileSelect(t *testing.T) {
 					}
 
 					// Now emit code for select.
-					err = compiler.compileSelect()
+					err = compiler.compileSelect(&wazeroir.OperationSelect{})
 					require.NoError(t, err)
 
 					// x1 should be top of the stack.
@@ -618,7 +618,7 @@ func TestCompiler_compileSwap_v128(t *testing.T) {
 			require.NoError(t, err)
 
 			if tc.x1OnRegister {
-				err = compiler.compileConstV128(&wazeroir.OperationConstV128{Lo: x1Lo, Hi: x1Hi})
+				err = compiler.compileV128Const(&wazeroir.OperationV128Const{Lo: x1Lo, Hi: x1Hi})
 				require.NoError(t, err)
 			} else {
 				lo := compiler.runtimeValueLocationStack().pushRuntimeValueLocationOnStack() // lo
@@ -632,7 +632,7 @@ func TestCompiler_compileSwap_v128(t *testing.T) {
 			_ = compiler.runtimeValueLocationStack().pushRuntimeValueLocationOnStack() // Dummy value!
 
 			if tc.x2OnRegister {
-				err = compiler.compileConstV128(&wazeroir.OperationConstV128{Lo: x2Lo, Hi: x2Hi})
+				err = compiler.compileV128Const(&wazeroir.OperationV128Const{Lo: x2Lo, Hi: x2Hi})
 				require.NoError(t, err)
 			} else {
 				lo := compiler.runtimeValueLocationStack().pushRuntimeValueLocationOnStack() // lo
@@ -668,6 +668,69 @@ func TestCompiler_compileSwap_v128(t *testing.T) {
 	}
 }
 
+func TestCompiler_compileSelect_v128(t *testing.T) {
+	const x1Lo, x1Hi uint64 = 100000, 200000
+	const x2Lo, x2Hi uint64 = 1, 2
+
+	for _, selectX1 := range []bool{false, true} {
+		selectX1 := selectX1
+		for _, onRegister := range []bool{false, true} {
+			onRegister := onRegister
+			t.Run(fmt.Sprintf("select_x1=%v,on_register=%v", selectX1, onRegister), func(t *testing.T) {
+				env := newCompilerEnvironment()
+				compiler := env.requireNewCompiler(t, newCompiler, nil)
+				err := compiler.compilePreamble()
+				require.NoError(t, err)
+
+				for _, v := range [][2]uint64{{x1Lo, x1Hi}, {x2Lo, x2Hi}} {
+					if onRegister {
+						err = compiler.compileV128Const(&wazeroir.OperationV128Const{Lo: v[0], Hi: v[1]})
+						require.NoError(t, err)
+					} else {
+						lo := compiler.runtimeValueLocationStack().pushRuntimeValueLocationOnStack() // lo
+						lo.valueType = runtimeValueTypeV128Lo
+						env.stack()[lo.stackPointer] = v[0]
+						hi := compiler.runtimeValueLocationStack().pushRuntimeValueLocationOnStack() // hi
+						hi.valueType = runtimeValueTypeV128Hi
+						env.stack()[hi.stackPointer] = v[1]
+					}
+				}
+
+				var c uint32
+				if selectX1 {
+					c = 1
+				}
+				err = compiler.compileConstI32(&wazeroir.OperationConstI32{Value: c})
+				require.NoError(t, err)
+
+				err = compiler.compileSelect(&wazeroir.OperationSelect{IsTargetVector: true})
+				require.NoError(t, err)
+
+				require.NoError(t, compiler.compileReturnFunction())
+
+				// Generate the code under test.
+				code, _, _, err := compiler.compile()
+				require.NoError(t, err)
+
+				// Run code.
+				env.exec(code)
+
+				require.Equal(t, nativeCallStatusCodeReturned, env.compilerStatus())
+				require.Equal(t, uint64(2), env.stackPointer())
+
+				st := env.stack()
+				if selectX1 {
+					require.Equal(t, x1Lo, st[0])
+					require.Equal(t, x1Hi, st[1])
+				} else {
+					require.Equal(t, x2Lo, st[0])
+					require.Equal(t, x2Hi, st[1])
+				}
+			})
+		}
+	}
+}
+
 func TestCompiler_compileSwap(t *testing.T) {
 	var x1Value, x2Value int64 = 100, 200
 	tests := []struct {
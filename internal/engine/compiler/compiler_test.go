@@ -179,13 +179,12 @@ type compilerImpl interface {
 	setRuntimeValueLocationStack(*runtimeValueLocationStack)
 	compileEnsureOnGeneralPurposeRegister(loc *runtimeValueLocation) error
 	compileModuleContextInitialization() error
-	compileNOP()
 }
 
 const defaultMemoryPageNumInTest = 1
 
 func newCompilerEnvironment() *compilerEnv {
-	me := &moduleEngine{}
+	me := &moduleEngine{stackLimits: wasm.DefaultStackLimits}
 	return &compilerEnv{
 		me: me,
 		moduleInstance: &wasm.ModuleInstance{
@@ -4,11 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"reflect"
 	"runtime"
 	"sync"
 	"unsafe"
 
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/experimental"
 	"github.com/tetratelabs/wazero/internal/buildoptions"
 	"github.com/tetratelabs/wazero/internal/wasm"
 	"github.com/tetratelabs/wazero/internal/wasmdebug"
@@ -20,6 +23,7 @@ type (
 	// engine is a Compiler implementation of wasm.Engine
 	engine struct {
 		enabledFeatures wasm.Features
+		stackLimits     wasm.StackLimits
 		codes           map[wasm.ModuleID][]*code // guarded by mutex.
 		mux             sync.RWMutex
 		// setFinalizer defaults to runtime.SetFinalizer, but overridable for tests.
@@ -38,6 +42,15 @@ type (
 		functions []*function
 
 		importedFunctionCount uint32
+
+		// stackLimits are the wasm.StackLimits of the parent engine.
+		stackLimits wasm.StackLimits
+
+		// consumesFuel is true if the module was compiled with wasm.Module FuelCosts.
+		consumesFuel bool
+
+		// parentEngine holds *engine from which this module engine is created from.
+		parentEngine *engine
 	}
 
 	// callEngine holds context per moduleEngine.Call, and shared across all the
@@ -51,6 +64,7 @@ type (
 		valueStackContext
 		exitContext
 		archContext
+		fuelContext
 
 		// The following fields are not accessed by compiled code directly.
 
@@ -62,6 +76,22 @@ type (
 		// The currently executed function call frame lives at callFrameStack[callFrameStackPointer-1]
 		// and that is equivalent to  engine.callFrameTop().
 		callFrameStack []callFrame
+
+		// exceptions holds the exceptions of the handlers being executed, indexed by the handles pushed on entering
+		// them.
+		exceptions []caughtException
+
+		// memoryIndex is the index of the memory currently referenced by moduleContext. This is non-zero only while the
+		// compiled code executes an instruction on another memory than the first one in wasm.FeatureMultiMemory.
+		memoryIndex uint64
+
+		// callStackCeiling and valueStackCeiling are the wasm.StackLimits which valueStack and callFrameStack never
+		// grow beyond.
+		callStackCeiling, valueStackCeiling uint64
+
+		// fuel is consumed by the compiled code via fuelContext, or nil if the module of the called function doesn't
+		// consume fuel. See wasm.FuelOf.
+		fuel *experimental.Fuel
 	}
 
 	// globalContext holds the data which is constant across multiple function calls.
@@ -90,9 +120,9 @@ type (
 	// This is subject to be manipulated from compiled native code whenever we make function calls.
 	moduleContext struct {
 		// moduleInstanceAddress is the address of module instance from which we initialize
-		// the following fields. This is set whenever we enter a function or return from function calls.
-		// This is only used by Compiler code so mark this as nolint.
-		moduleInstanceAddress uintptr //nolint
+		// the following fields. This is set whenever we enter a function or return from function calls, except host
+		// functions, so that calling them can read the module of their caller.
+		moduleInstanceAddress uintptr
 
 		// globalElement0Address is the address of the first element in the global slice,
 		// i.e. &ModuleInstance.Globals[0] as uintptr.
@@ -152,6 +182,13 @@ type (
 		builtinFunctionCallIndex wasm.Index
 	}
 
+	// fuelContext holds the data to consume the fuel of the call in the compiled code of wazeroir.OperationConsumeFuel.
+	// Note: This follows archContext, so its offset depends on the architecture.
+	fuelContext struct {
+		// fuelAddress is the address of callEngine.fuel.
+		fuelAddress uintptr
+	}
+
 	// callFrame holds the information to which the caller function can return.
 	// callFrame is created for currently executed function frame as well,
 	// so some of the fields are not yet set when native code is currently executing it.
@@ -195,6 +232,8 @@ type (
 		staticData codeStaticData
 		// stackPointerCeil is the max of the stack pointer this function can reach. Lazily applied via maybeGrowValueStack.
 		stackPointerCeil uint64
+		// tryBlocks are the try blocks in codeSegment, used to find the handlers of exceptions.
+		tryBlocks []*tryBlock
 
 		// indexInModule is the index of this function in the module. For logging purpose.
 		indexInModule wasm.Index
@@ -279,7 +318,7 @@ const (
 	tableInstanceTableLenOffset = 8
 
 	// Offsets for wasm.FunctionInstance.
-	functionInstanceTypeIDOffset = 96
+	functionInstanceTypeIDOffset = 104
 
 	// Offsets for wasm.MemoryInstance.
 	memoryInstanceBufferOffset    = 0
@@ -327,6 +366,8 @@ const (
 	nativeCallStatusCodeTypeMismatchOnIndirectCall
 	nativeCallStatusIntegerOverflow
 	nativeCallStatusIntegerDivisionByZero
+	// nativeCallStatusCodeFuelExhausted means the remaining fuel was less than the cost of wazeroir.OperationConsumeFuel.
+	nativeCallStatusCodeFuelExhausted
 )
 
 // causePanic causes a panic with the corresponding error to the status code.
@@ -347,6 +388,8 @@ func (s nativeCallStatusCode) causePanic() {
 		err = wasmruntime.ErrRuntimeInvalidTableAccess
 	case nativeCallStatusCodeTypeMismatchOnIndirectCall:
 		err = wasmruntime.ErrRuntimeIndirectCallTypeMismatch
+	case nativeCallStatusCodeFuelExhausted:
+		err = wasmruntime.ErrRuntimeFuelExhausted
 	}
 	panic(err)
 }
@@ -373,6 +416,8 @@ func (s nativeCallStatusCode) String() (ret string) {
 		ret = "integer overflow"
 	case nativeCallStatusIntegerDivisionByZero:
 		ret = "integer division by zero"
+	case nativeCallStatusCodeFuelExhausted:
+		ret = "fuel exhausted"
 	default:
 		panic("BUG")
 	}
@@ -465,6 +510,9 @@ func (e *engine) NewModuleEngine(name string, module *wasm.Module, importedFunct
 		name:                  name,
 		functions:             make([]*function, 0, imported+uint32(len(moduleFunctions))),
 		importedFunctionCount: imported,
+		stackLimits:           e.stackLimits,
+		consumesFuel:          module.FuelCosts != nil,
+		parentEngine:          e,
 	}
 
 	for _, f := range importedFunctions {
@@ -522,6 +570,11 @@ func (e *moduleEngine) Name() string {
 	return e.name
 }
 
+// Engine implements the same method as documented on wasm.ModuleEngine.
+func (e *moduleEngine) Engine() wasm.Engine {
+	return e.parentEngine
+}
+
 // CreateFuncElementInstance implements the same method as documented on wasm.ModuleEngine.
 func (e *moduleEngine) CreateFuncElementInstance(indexes []*wasm.Index) *wasm.ElementInstance {
 	refs := make([]wasm.Reference, len(indexes))
@@ -536,6 +589,32 @@ func (e *moduleEngine) CreateFuncElementInstance(indexes []*wasm.Index) *wasm.El
 	}
 }
 
+// FunctionInstanceReference implements the same method as documented on wasm.ModuleEngine.
+func (e *moduleEngine) FunctionInstanceReference(funcIndex wasm.Index) wasm.Reference {
+	return uintptr(unsafe.Pointer(e.functions[funcIndex]))
+}
+
+// LookupFunction implements the same method as documented on wasm.ModuleEngine.
+func (e *moduleEngine) LookupFunction(ref wasm.Reference) *wasm.FunctionInstance {
+	return functionFromUintptr(ref).source
+}
+
+// functionFromUintptr resurrects the original *function from the given uintptr which comes from either funcref table
+// or OpcodeRefFunc instruction.
+func functionFromUintptr(ptr uintptr) *function {
+	// Wraps ptrs as the double pointer in order to avoid the unsafe access as detected by race detector.
+	// See the same function in the interpreter package.
+	var wrapped *uintptr = &ptr
+	return *(**function)(unsafe.Pointer(wrapped))
+}
+
+// moduleInstanceFromUintptr resurrects the original *wasm.ModuleInstance from the given uintptr, like
+// functionFromUintptr.
+func moduleInstanceFromUintptr(ptr uintptr) *wasm.ModuleInstance {
+	var wrapped *uintptr = &ptr
+	return *(**wasm.ModuleInstance)(unsafe.Pointer(wrapped))
+}
+
 // InitializeFuncrefGlobals implements the same method as documented on wasm.InitializeFuncrefGlobals.
 func (e *moduleEngine) InitializeFuncrefGlobals(globals []*wasm.GlobalInstance) {
 	for _, g := range globals {
@@ -568,6 +647,10 @@ func (e *moduleEngine) Call(ctx context.Context, callCtx *wasm.CallContext, f *w
 	}
 
 	ce := e.newCallEngine()
+	if e.consumesFuel {
+		ce.fuel = wasm.FuelOf(ctx)
+		ce.fuelAddress = uintptr(unsafe.Pointer(ce.fuel))
+	}
 
 	// We ensure that this Call method never panics as
 	// this Call method is indirectly invoked by embedders via store.CallFunction,
@@ -596,6 +679,9 @@ func (e *moduleEngine) Call(ctx context.Context, callCtx *wasm.CallContext, f *w
 	}()
 
 	if f.Kind == wasm.FunctionKindWasm {
+		if uint64(paramCount) > ce.globalContext.valueStackLen {
+			ce.builtinFunctionGrowValueStack(uint64(paramCount))
+		}
 		for _, v := range params {
 			ce.pushValue(v)
 		}
@@ -607,13 +693,14 @@ func (e *moduleEngine) Call(ctx context.Context, callCtx *wasm.CallContext, f *w
 	return
 }
 
-func NewEngine(enabledFeatures wasm.Features) wasm.Engine {
-	return newEngine(enabledFeatures)
+func NewEngine(enabledFeatures wasm.Features, stackLimits wasm.StackLimits) wasm.Engine {
+	return newEngine(enabledFeatures, stackLimits)
 }
 
-func newEngine(enabledFeatures wasm.Features) *engine {
+func newEngine(enabledFeatures wasm.Features, stackLimits wasm.StackLimits) *engine {
 	return &engine{
 		enabledFeatures: enabledFeatures,
+		stackLimits:     stackLimits,
 		codes:           map[wasm.ModuleID][]*code{},
 		setFinalizer:    runtime.SetFinalizer,
 	}
@@ -652,10 +739,23 @@ var (
 )
 
 func (e *moduleEngine) newCallEngine() *callEngine {
+	callStackCeiling, valueStackCeiling := uint64(e.stackLimits.CallStackCeiling), uint64(e.stackLimits.ValueStackCeiling)
+
+	// The stacks are initially smaller than the ceilings, but they never grow beyond them.
+	valueStackSize, callFrameStackSize := uint64(initialValueStackSize), uint64(initialCallFrameStackSize)
+	if valueStackSize > valueStackCeiling {
+		valueStackSize = valueStackCeiling
+	}
+	if callFrameStackSize > callStackCeiling {
+		callFrameStackSize = callStackCeiling
+	}
+
 	ce := &callEngine{
-		valueStack:     make([]uint64, initialValueStackSize),
-		callFrameStack: make([]callFrame, initialCallFrameStackSize),
-		archContext:    newArchContext(),
+		valueStack:        make([]uint64, valueStackSize),
+		callFrameStack:    make([]callFrame, callFrameStackSize),
+		archContext:       newArchContext(),
+		callStackCeiling:  callStackCeiling,
+		valueStackCeiling: valueStackCeiling,
 	}
 
 	valueStackHeader := (*reflect.SliceHeader)(unsafe.Pointer(&ce.valueStack))
@@ -681,6 +781,20 @@ func (ce *callEngine) pushValue(v uint64) {
 	ce.valueStackContext.stackPointer++
 }
 
+// callGoModuleFunc calls the api.GoModuleFunc directly on the value stack, so the call neither reflects nor allocates.
+func (ce *callEngine) callGoModuleFunc(ctx context.Context, callCtx *wasm.CallContext, f *wasm.FunctionInstance) {
+	base := ce.valueStackTopIndex() - uint64(f.Type.ParamNumInUint64)
+	end := base + uint64(f.Type.ParamNumInUint64)
+	if r := base + uint64(f.Type.ResultNumInUint64); r > end {
+		end = r
+		if end > uint64(len(ce.valueStack)) {
+			ce.builtinFunctionGrowValueStack(end - ce.valueStackContext.stackBasePointer)
+		}
+	}
+	f.GoModuleFunc(ctx, callCtx, ce.valueStack[base:end])
+	ce.valueStackContext.stackPointer = base + uint64(f.Type.ResultNumInUint64) - ce.valueStackContext.stackBasePointer
+}
+
 func (ce *callEngine) callFrameTop() *callFrame {
 	return &ce.callFrameStack[ce.globalContext.callFrameStackPointer-1]
 }
@@ -699,6 +813,18 @@ const (
 	builtinFunctionIndexGrowValueStack
 	builtinFunctionIndexGrowCallFrameStack
 	builtinFunctionIndexTableGrow
+	builtinFunctionIndexAtomicLoad
+	builtinFunctionIndexAtomicStore
+	builtinFunctionIndexAtomicRMW
+	builtinFunctionIndexAtomicRMWCmpxchg
+	builtinFunctionIndexAtomicMemoryWait
+	builtinFunctionIndexAtomicMemoryNotify
+	builtinFunctionIndexThrow
+	builtinFunctionIndexRethrow
+	builtinFunctionIndexSelectMemory
+	builtinFunctionIndexMemoryCopy
+	builtinFunctionIndexMemoryFill
+	builtinFunctionIndexMemoryInit
 	// builtinFunctionIndexBreakPoint is internal (only for wazero developers). Disabled by default.
 	builtinFunctionIndexBreakPoint
 )
@@ -717,7 +843,9 @@ entry:
 		}
 
 		// Call into the native code.
-		nativecall(frame.returnAddress, uintptr(unsafe.Pointer(ce)), f.moduleInstanceAddress)
+		// Note: The module instance of the frame is needed when entering a handler of an exception, which
+		// initializes the module context as in the preamble of functions.
+		nativecall(frame.returnAddress, uintptr(unsafe.Pointer(ce)), frame.function.moduleInstanceAddress)
 
 		// Check the status code from Compiler code.
 		switch status := ce.exitContext.statusCode; status {
@@ -725,17 +853,30 @@ entry:
 			// Meaning that all the function frames above the previous call frame stack pointer are executed.
 		case nativeCallStatusCodeCallHostFunction:
 			calleeHostFunction := ce.callFrameTop().function
-			// Not "callFrameTop" but take the below of peek with "callFrameAt(1)" as the top frame is for host function,
-			// but when making host function calls, we need to pass the memory instance of host function caller.
-			callerFunction := ce.callFrameAt(1).function
-			params := wasm.PopGoFuncParams(calleeHostFunction.source, ce.popValue)
-			results := wasm.CallGoFunc(
-				ctx,
-				// Use the caller's memory, which might be different from the defining module on an imported function.
-				callCtx.WithMemory(callerFunction.source.Module.Memory),
-				calleeHostFunction.source,
-				params,
-			)
+			// Host functions don't initialize the module context, so it is still the one of the function which called
+			// the host function. Unlike "callFrameAt(1)", this is also the case when the caller tail-called the host
+			// function, which replaced the caller's frame.
+			callerModule := moduleInstanceFromUintptr(ce.moduleContext.moduleInstanceAddress)
+			// Use the caller's memory, which might be different from the defining module on an imported function.
+			hostCallCtx := callCtx.WithMemory(callerModule.Memory)
+			var results []uint64
+			if exc := catchException(func() {
+				if calleeHostFunction.source.Kind == wasm.FunctionKindGoModuleFunc {
+					ce.callGoModuleFunc(ctx, hostCallCtx, calleeHostFunction.source)
+				} else {
+					params := wasm.PopGoFuncParams(calleeHostFunction.source, ce.popValue)
+					results = wasm.CallGoFunc(ctx, hostCallCtx, calleeHostFunction.source, params)
+				}
+			}); exc != nil {
+				// The exception is handled from the caller, which saved its stack base pointer on the call.
+				top := int(ce.globalContext.callFrameStackPointer) - 2
+				var base uint64
+				if top >= 0 {
+					base = ce.callFrameStack[top].returnStackBasePointer
+				}
+				ce.handleException(exc, top, base)
+				goto entry
+			}
 			for _, v := range results {
 				ce.pushValue(v)
 			}
@@ -744,7 +885,7 @@ entry:
 			switch ce.exitContext.builtinFunctionCallIndex {
 			case builtinFunctionIndexMemoryGrow:
 				callerFunction := ce.callFrameTop().function
-				ce.builtinFunctionMemoryGrow(ctx, callerFunction.source.Module.Memory)
+				ce.builtinFunctionMemoryGrow(ctx, ce.memory(callerFunction.source.Module))
 			case builtinFunctionIndexGrowValueStack:
 				callerFunction := ce.callFrameTop().function
 				ce.builtinFunctionGrowValueStack(callerFunction.stackPointerCeil)
@@ -753,6 +894,43 @@ entry:
 			case builtinFunctionIndexTableGrow:
 				caller := ce.callFrameTop().function
 				ce.builtinFunctionTableGrow(ctx, caller.source.Module.Tables)
+			case builtinFunctionIndexAtomicLoad:
+				caller := ce.callFrameTop().function
+				ce.builtinFunctionAtomicLoad(ce.memory(caller.source.Module))
+			case builtinFunctionIndexAtomicStore:
+				caller := ce.callFrameTop().function
+				ce.builtinFunctionAtomicStore(ce.memory(caller.source.Module))
+			case builtinFunctionIndexAtomicRMW:
+				caller := ce.callFrameTop().function
+				ce.builtinFunctionAtomicRMW(ce.memory(caller.source.Module))
+			case builtinFunctionIndexAtomicRMWCmpxchg:
+				caller := ce.callFrameTop().function
+				ce.builtinFunctionAtomicRMWCmpxchg(ce.memory(caller.source.Module))
+			case builtinFunctionIndexAtomicMemoryWait:
+				caller := ce.callFrameTop().function
+				ce.builtinFunctionAtomicMemoryWait(ce.memory(caller.source.Module))
+			case builtinFunctionIndexAtomicMemoryNotify:
+				caller := ce.callFrameTop().function
+				ce.builtinFunctionAtomicMemoryNotify(ce.memory(caller.source.Module))
+			case builtinFunctionIndexThrow:
+				caller := ce.callFrameTop().function
+				exc := ce.builtinFunctionThrow(caller.source.Module.Tags)
+				ce.handleException(exc, int(ce.globalContext.callFrameStackPointer)-1, ce.valueStackContext.stackBasePointer)
+			case builtinFunctionIndexRethrow:
+				exc := ce.exceptions[ce.popValue()].exc
+				ce.handleException(exc, int(ce.globalContext.callFrameStackPointer)-1, ce.valueStackContext.stackBasePointer)
+			case builtinFunctionIndexSelectMemory:
+				caller := ce.callFrameTop().function
+				ce.builtinFunctionSelectMemory(caller.source.Module)
+			case builtinFunctionIndexMemoryCopy:
+				caller := ce.callFrameTop().function
+				ce.builtinFunctionMemoryCopy(caller.source.Module.Memories)
+			case builtinFunctionIndexMemoryFill:
+				caller := ce.callFrameTop().function
+				ce.builtinFunctionMemoryFill(ce.memory(caller.source.Module))
+			case builtinFunctionIndexMemoryInit:
+				caller := ce.callFrameTop().function
+				ce.builtinFunctionMemoryInit(caller.source.Module.DataInstances, ce.memory(caller.source.Module))
 			}
 			if buildoptions.IsDebugMode {
 				if ce.exitContext.builtinFunctionCallIndex == builtinFunctionIndexBreakPoint {
@@ -767,8 +945,15 @@ entry:
 }
 
 func (ce *callEngine) builtinFunctionGrowValueStack(stackPointerCeil uint64) {
-	// Extends the valueStack's length to currentLen*2+stackPointerCeil.
+	if ce.valueStackCeiling < ce.valueStackContext.stackBasePointer+stackPointerCeil {
+		panic(wasmruntime.ErrRuntimeValueStackOverflow)
+	}
+
+	// Extends the valueStack's length to currentLen*2+stackPointerCeil, but not beyond the ceiling.
 	newLen := ce.globalContext.valueStackLen*2 + (stackPointerCeil)
+	if newLen > ce.valueStackCeiling {
+		newLen = ce.valueStackCeiling
+	}
 	newStack := make([]uint64, newLen)
 	top := ce.valueStackContext.stackBasePointer + ce.valueStackContext.stackPointer
 	copy(newStack[:top], ce.valueStack[:top])
@@ -778,15 +963,16 @@ func (ce *callEngine) builtinFunctionGrowValueStack(stackPointerCeil uint64) {
 	ce.globalContext.valueStackLen = uint64(valueStackHeader.Len)
 }
 
-var callStackCeiling = uint64(buildoptions.CallStackCeiling)
-
 func (ce *callEngine) builtinFunctionGrowCallFrameStack() {
-	if callStackCeiling < uint64(len(ce.callFrameStack)+1) {
+	if ce.callStackCeiling <= uint64(len(ce.callFrameStack)) {
 		panic(wasmruntime.ErrRuntimeCallStackOverflow)
 	}
 
-	// Double the callstack slice length.
+	// Double the callstack slice length, but not beyond the ceiling.
 	newLen := uint64(ce.globalContext.callFrameStackLen) * 2
+	if newLen > ce.callStackCeiling {
+		newLen = ce.callStackCeiling
+	}
 	newStack := make([]callFrame, newLen)
 	copy(newStack, ce.callFrameStack)
 	ce.callFrameStack = newStack
@@ -800,18 +986,104 @@ func (ce *callEngine) builtinFunctionGrowCallFrameStack() {
 func (ce *callEngine) builtinFunctionMemoryGrow(ctx context.Context, mem *wasm.MemoryInstance) {
 	newPages := ce.popValue()
 
-	if res, ok := mem.Grow(ctx, uint32(newPages)); !ok {
+	if mem.Memory64 && newPages > math.MaxUint32 {
+		ce.pushValue(uint64(0xffffffff)) // The delta is 64-bit, and never fits in the memory.
+	} else if res, ok := mem.Grow(ctx, uint32(newPages)); !ok {
 		ce.pushValue(uint64(0xffffffff)) // = -1 in signed 32-bit integer.
 	} else {
 		ce.pushValue(uint64(res))
 	}
 
 	// Update the moduleContext fields as they become stale after the update ^^.
+	ce.updateMemoryContext(mem)
+}
+
+// memory returns the memory of the given module which is currently referenced by moduleContext.
+func (ce *callEngine) memory(m *wasm.ModuleInstance) *wasm.MemoryInstance {
+	if ce.memoryIndex == 0 {
+		return m.Memory
+	}
+	return m.Memories[ce.memoryIndex]
+}
+
+// updateMemoryContext makes moduleContext reference the buffer of the given memory.
+func (ce *callEngine) updateMemoryContext(mem *wasm.MemoryInstance) {
 	bufSliceHeader := (*reflect.SliceHeader)(unsafe.Pointer(&mem.Buffer))
 	ce.moduleContext.memorySliceLen = uint64(bufSliceHeader.Len)
 	ce.moduleContext.memoryElement0Address = bufSliceHeader.Data
 }
 
+// builtinFunctionSelectMemory makes moduleContext reference the memory of the index on the top of the stack, so that
+// the compiled code accesses it instead of the first memory. See compiler.compileSelectMemory.
+func (ce *callEngine) builtinFunctionSelectMemory(m *wasm.ModuleInstance) {
+	ce.memoryIndex = uint64(uint32(ce.popValue()))
+	ce.updateMemoryContext(ce.memory(m))
+}
+
+// popMemoryOperand pops an address or a size of a memory instruction, which is 64-bit only if the memory is indexed
+// with i64.
+func (ce *callEngine) popMemoryOperand(memory64 bool) uint64 {
+	v := ce.popValue()
+	if !memory64 {
+		v = uint64(uint32(v))
+	}
+	return v
+}
+
+// outOfRange returns true if the range of size from offset doesn't fit in length. Unlike comparing offset+size with
+// length, this doesn't overflow with 64-bit offsets and sizes of a memory indexed with i64.
+func outOfRange(offset, size, length uint64) bool {
+	return offset > length || size > length-offset
+}
+
+// builtinFunctionMemoryCopy performs memory.copy between two different memories, or on a memory indexed with i64. The
+// memory indexes are on the top of the stack followed by the usual operands of memory.copy.
+func (ce *callEngine) builtinFunctionMemoryCopy(memories []*wasm.MemoryInstance) {
+	dst, src := memories[uint32(ce.popValue())], memories[uint32(ce.popValue())]
+	copySize := ce.popMemoryOperand(dst.Memory64 && src.Memory64)
+	sourceOffset := ce.popMemoryOperand(src.Memory64)
+	destinationOffset := ce.popMemoryOperand(dst.Memory64)
+	if outOfRange(sourceOffset, copySize, uint64(len(src.Buffer))) ||
+		outOfRange(destinationOffset, copySize, uint64(len(dst.Buffer))) {
+		panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+	} else if copySize != 0 {
+		copy(dst.Buffer[destinationOffset:], src.Buffer[sourceOffset:sourceOffset+copySize])
+	}
+}
+
+// builtinFunctionMemoryFill performs memory.fill on a memory indexed with i64.
+func (ce *callEngine) builtinFunctionMemoryFill(mem *wasm.MemoryInstance) {
+	fillSize := ce.popMemoryOperand(mem.Memory64)
+	value := byte(ce.popValue())
+	offset := ce.popMemoryOperand(mem.Memory64)
+	if outOfRange(offset, fillSize, uint64(len(mem.Buffer))) {
+		panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+	} else if fillSize != 0 {
+		// Uses the copy trick for faster filling buffer.
+		// https://gist.github.com/taylorza/df2f89d5f9ab3ffd06865062a4cf015d
+		buf := mem.Buffer[offset : offset+fillSize]
+		buf[0] = value
+		for i := 1; i < len(buf); i *= 2 {
+			copy(buf[i:], buf[:i])
+		}
+	}
+}
+
+// builtinFunctionMemoryInit performs memory.init on a memory indexed with i64. The data index is on the top of the
+// stack followed by the usual operands of memory.init.
+func (ce *callEngine) builtinFunctionMemoryInit(dataInstances []wasm.DataInstance, mem *wasm.MemoryInstance) {
+	dataInstance := dataInstances[uint32(ce.popValue())]
+	copySize := ce.popMemoryOperand(false)
+	inDataOffset := ce.popMemoryOperand(false)
+	inMemoryOffset := ce.popMemoryOperand(mem.Memory64)
+	if outOfRange(inDataOffset, copySize, uint64(len(dataInstance))) ||
+		outOfRange(inMemoryOffset, copySize, uint64(len(mem.Buffer))) {
+		panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+	} else if copySize != 0 {
+		copy(mem.Buffer[inMemoryOffset:inMemoryOffset+copySize], dataInstance[inDataOffset:])
+	}
+}
+
 func (ce *callEngine) builtinFunctionTableGrow(ctx context.Context, tables []*wasm.TableInstance) {
 	tableIndex := ce.popValue()
 	table := tables[tableIndex] // verifed not to be out of range by the func validation at compilation phase.
@@ -821,6 +1093,180 @@ func (ce *callEngine) builtinFunctionTableGrow(ctx context.Context, tables []*wa
 	ce.pushValue(uint64(res))
 }
 
+// atomicImmediate packs the immediates of an atomic instruction into a single value, which is pushed onto the stack
+// before calling the atomic builtin functions: the static offset in the lower 32 bits, followed by the access size in
+// bytes and the arithmetic of read-modify-write.
+func atomicImmediate(arg *wazeroir.MemoryImmediate, sizeInBytes uint32, op wazeroir.AtomicArithmeticOp) uint64 {
+	return uint64(arg.Offset) | uint64(sizeInBytes)<<32 | uint64(op)<<40
+}
+
+// atomicResultType returns the runtimeValueType of the value pushed by the atomic builtin functions.
+func atomicResultType(t wazeroir.UnsignedInt) runtimeValueType {
+	if t == wazeroir.UnsignedInt64 {
+		return runtimeValueTypeI64
+	}
+	return runtimeValueTypeI32
+}
+
+// popAtomicImmediate pops the value pushed with atomicImmediate, which is on top of the operands of the instruction.
+func (ce *callEngine) popAtomicImmediate() (staticOffset uint64, sizeInBytes uint32, op wazeroir.AtomicArithmeticOp) {
+	imm := ce.popValue()
+	return uint64(uint32(imm)), uint32(byte(imm >> 32)), wazeroir.AtomicArithmeticOp(imm >> 40)
+}
+
+// popAtomicAddress pops the base address and returns the effective address. Unlike the other memory instructions, the
+// range is checked by wasm.MemoryInstance after the alignment.
+func (ce *callEngine) popAtomicAddress(mem *wasm.MemoryInstance, staticOffset uint64) uint64 {
+	base := ce.popMemoryOperand(mem.Memory64)
+	if addr := base + staticOffset; addr >= base {
+		return addr
+	}
+	// The base is 64-bit for a memory indexed with i64, and the sum overflowed. Saturate it to an aligned address
+	// which is out of range of any memory.
+	return math.MaxUint64 &^ 7
+}
+
+func (ce *callEngine) builtinFunctionAtomicLoad(mem *wasm.MemoryInstance) {
+	staticOffset, sizeInBytes, _ := ce.popAtomicImmediate()
+	v, err := mem.AtomicLoad(ce.popAtomicAddress(mem, staticOffset), sizeInBytes)
+	if err != nil {
+		panic(err)
+	}
+	ce.pushValue(v)
+}
+
+func (ce *callEngine) builtinFunctionAtomicStore(mem *wasm.MemoryInstance) {
+	staticOffset, sizeInBytes, _ := ce.popAtomicImmediate()
+	val := ce.popValue()
+	if err := mem.AtomicStore(ce.popAtomicAddress(mem, staticOffset), sizeInBytes, val); err != nil {
+		panic(err)
+	}
+}
+
+func (ce *callEngine) builtinFunctionAtomicRMW(mem *wasm.MemoryInstance) {
+	staticOffset, sizeInBytes, op := ce.popAtomicImmediate()
+	val := ce.popValue()
+	old, err := mem.AtomicRMW(ce.popAtomicAddress(mem, staticOffset), sizeInBytes, func(old uint64) uint64 {
+		return op.Apply(old, val)
+	})
+	if err != nil {
+		panic(err)
+	}
+	ce.pushValue(old)
+}
+
+func (ce *callEngine) builtinFunctionAtomicRMWCmpxchg(mem *wasm.MemoryInstance) {
+	staticOffset, sizeInBytes, _ := ce.popAtomicImmediate()
+	replacement, expected := ce.popValue(), ce.popValue()
+	old, err := mem.AtomicCompareExchange(ce.popAtomicAddress(mem, staticOffset), sizeInBytes, expected, replacement)
+	if err != nil {
+		panic(err)
+	}
+	ce.pushValue(old)
+}
+
+func (ce *callEngine) builtinFunctionAtomicMemoryWait(mem *wasm.MemoryInstance) {
+	staticOffset, sizeInBytes, _ := ce.popAtomicImmediate()
+	timeout, expected := int64(ce.popValue()), ce.popValue()
+	res, err := mem.Wait(ce.popAtomicAddress(mem, staticOffset), sizeInBytes, expected, timeout)
+	if err != nil {
+		panic(err)
+	}
+	ce.pushValue(res)
+}
+
+func (ce *callEngine) builtinFunctionAtomicMemoryNotify(mem *wasm.MemoryInstance) {
+	staticOffset, _, _ := ce.popAtomicImmediate()
+	count := uint32(ce.popValue())
+	res, err := mem.Notify(ce.popAtomicAddress(mem, staticOffset), count)
+	if err != nil {
+		panic(err)
+	}
+	ce.pushValue(uint64(res))
+}
+
+// builtinFunctionThrow pops the tag index and the values pushed by arm64Compiler.compileThrow or
+// amd64Compiler.compileThrow, and returns the exception to raise.
+func (ce *callEngine) builtinFunctionThrow(tags []*wasm.TagInstance) *api.Exception {
+	tag := tags[ce.popValue()]
+	values := make([]uint64, tag.Type.ParamNumInUint64)
+	for i := len(values) - 1; i >= 0; i-- {
+		values[i] = ce.popValue()
+	}
+	return &api.Exception{Tag: tag, Values: values}
+}
+
+// caughtException is an exception caught by a handler, whose handle is at stackPosition of callEngine.valueStack.
+type caughtException struct {
+	exc           *api.Exception
+	stackPosition uint64
+}
+
+// pushExceptionHandle pushes the handle of exc caught by the handler entered with the current value stack.
+//
+// The handles of the handlers being executed are below the current stack, as handlers are entered with the stack of
+// their try block which is nested in them or called by them. So, the exceptions whose handle is at or above it are
+// of handlers which have ended, and are discarded to bound ce.exceptions to the depth of the stack.
+func (ce *callEngine) pushExceptionHandle(exc *api.Exception) {
+	pos := ce.valueStackTopIndex()
+	i := len(ce.exceptions)
+	for i > 0 && ce.exceptions[i-1].stackPosition >= pos {
+		i--
+	}
+	ce.exceptions = append(ce.exceptions[:i], caughtException{exc: exc, stackPosition: pos})
+	ce.pushValue(uint64(i))
+}
+
+// handleException unwinds the call frames to the innermost handler of exc, beginning at callFrameStack[top] whose
+// stack base pointer is base, so that the handler is entered on the next nativecall. This panics with exc if there's
+// no handler, so that the exception escapes moduleEngine.Call.
+func (ce *callEngine) handleException(exc *api.Exception, top int, base uint64) {
+	for i := top; i >= 0; i-- {
+		frame := &ce.callFrameStack[i]
+		if i < top {
+			// The frames below the top have made function calls, which saved their stack base pointer.
+			base = frame.returnStackBasePointer
+		}
+
+		f := frame.function
+		tb, c := findHandler(f.parent.tryBlocks, uint64(frame.returnAddress-f.codeInitialAddress), f.source.Module.Tags, exc)
+		if c == nil {
+			continue
+		}
+
+		// Discard the frames above the handler, as well as the values pushed in the try block.
+		ce.globalContext.callFrameStackPointer = uint64(i) + 1
+		ce.valueStackContext.stackBasePointer = base
+		ce.valueStackContext.stackPointer = tb.stackHeight
+		ce.pushExceptionHandle(exc)
+		if !c.catchAll {
+			for _, v := range exc.Values {
+				ce.pushValue(v)
+			}
+		}
+		frame.returnAddress = f.codeInitialAddress + uintptr(c.offset)
+		return
+	}
+	panic(exc)
+}
+
+// catchException calls fn, and returns the exception raised by fn if any. Host functions raise exceptions by
+// panicking with them, possibly wrapped in another error. Other panics, such as traps, are propagated as is.
+func catchException(fn func()) (exc *api.Exception) {
+	defer func() {
+		if v := recover(); v != nil {
+			// Only allocate the target of errors.As on panic, as host function calls are otherwise allocation free.
+			var e *api.Exception
+			if err, ok := v.(error); !ok || !errors.As(err, &e) {
+				panic(v)
+			}
+			exc = e
+		}
+	}()
+	fn()
+	return
+}
+
 func compileHostFunction(sig *wasm.FunctionType) (*code, error) {
 	compiler, err := newCompiler(&wazeroir.CompilationResult{Signature: sig})
 	if err != nil {
@@ -854,8 +1300,22 @@ func compileWasmFunction(_ wasm.Features, ir *wazeroir.CompilationResult) (*code
 		// Compiler determines whether skip the entire label.
 		// For example, if the label doesn't have any caller,
 		// we don't need to generate native code at all as we never reach the region.
-		if op.Kind() == wazeroir.OperationKindLabel {
-			skip = compiler.compileLabel(op.(*wazeroir.OperationLabel))
+		switch o := op.(type) {
+		case *wazeroir.OperationLabel:
+			skip = compiler.compileLabel(o)
+		case *wazeroir.OperationCatch:
+			// Likewise, handlers are skipped if the try block is never reached.
+			var err error
+			if skip, err = compiler.compileCatch(o); err != nil {
+				return nil, fmt.Errorf("operation %s: %w", op.Kind().String(), err)
+			}
+			continue
+		case *wazeroir.OperationDelegate:
+			// The end of the protected region must be known even if it's never reached.
+			if err := compiler.compileDelegate(o); err != nil {
+				return nil, fmt.Errorf("operation %s: %w", op.Kind().String(), err)
+			}
+			continue
 		}
 		if skip {
 			continue
@@ -864,6 +1324,21 @@ func compileWasmFunction(_ wasm.Features, ir *wazeroir.CompilationResult) (*code
 		if buildoptions.IsDebugMode {
 			fmt.Printf("compiling op=%s: %s\n", op.Kind(), compiler)
 		}
+
+		// Instructions on another memory than the first one are executed while that memory is selected, which updates
+		// the module context with its current length. The first memory's length is only updated when entering a
+		// function of another module, so it's reloaded if shared, as other threads can grow it meanwhile.
+		memoryIndex, accessesMemory := memoryIndexOf(op)
+		if memoryIndex != 0 {
+			if err := compiler.compileSelectMemory(memoryIndex); err != nil {
+				return nil, fmt.Errorf("operation %s: %w", op.Kind().String(), err)
+			}
+		} else if accessesMemory && ir.Memories[0].IsShared {
+			if err := compiler.compileReloadMemoryLength(); err != nil {
+				return nil, fmt.Errorf("operation %s: %w", op.Kind().String(), err)
+			}
+		}
+
 		var err error
 		switch o := op.(type) {
 		case *wazeroir.OperationLabel:
@@ -880,10 +1355,14 @@ func compileWasmFunction(_ wasm.Features, ir *wazeroir.CompilationResult) (*code
 			err = compiler.compileCall(o)
 		case *wazeroir.OperationCallIndirect:
 			err = compiler.compileCallIndirect(o)
+		case *wazeroir.OperationTailCall:
+			err = compiler.compileTailCall(o)
+		case *wazeroir.OperationTailCallIndirect:
+			err = compiler.compileTailCallIndirect(o)
 		case *wazeroir.OperationDrop:
 			err = compiler.compileDrop(o)
 		case *wazeroir.OperationSelect:
-			err = compiler.compileSelect()
+			err = compiler.compileSelect(o)
 		case *wazeroir.OperationPick:
 			err = compiler.compilePick(o)
 		case *wazeroir.OperationSwap:
@@ -1017,11 +1496,26 @@ func compileWasmFunction(_ wasm.Features, ir *wazeroir.CompilationResult) (*code
 		case *wazeroir.OperationDataDrop:
 			err = compiler.compileDataDrop(o)
 		case *wazeroir.OperationMemoryInit:
-			err = compiler.compileMemoryInit(o)
+			// Bulk memory instructions on a memory indexed with i64 are performed by Go functions, which check the
+			// range of 64-bit operands without overflow.
+			if ir.Memories[o.MemoryIndex].IsMemory64 {
+				err = compiler.compileBuiltinMemoryInit(o)
+			} else {
+				err = compiler.compileMemoryInit(o)
+			}
 		case *wazeroir.OperationMemoryCopy:
-			err = compiler.compileMemoryCopy()
+			if o.SrcMemoryIndex != o.DstMemoryIndex ||
+				ir.Memories[o.SrcMemoryIndex].IsMemory64 || ir.Memories[o.DstMemoryIndex].IsMemory64 {
+				err = compiler.compileBuiltinMemoryCopy(o)
+			} else {
+				err = compiler.compileMemoryCopy()
+			}
 		case *wazeroir.OperationMemoryFill:
-			err = compiler.compileMemoryFill()
+			if ir.Memories[o.MemoryIndex].IsMemory64 {
+				err = compiler.compileBuiltinMemoryFill()
+			} else {
+				err = compiler.compileMemoryFill()
+			}
 		case *wazeroir.OperationTableInit:
 			err = compiler.compileTableInit(o)
 		case *wazeroir.OperationTableCopy:
@@ -1040,16 +1534,142 @@ func compileWasmFunction(_ wasm.Features, ir *wazeroir.CompilationResult) (*code
 			err = compiler.compileTableSize(o)
 		case *wazeroir.OperationTableFill:
 			err = compiler.compileTableFill(o)
-		case *wazeroir.OperationConstV128:
-			err = compiler.compileConstV128(o)
-		case *wazeroir.OperationAddV128:
-			err = compiler.compileAddV128(o)
+		case *wazeroir.OperationV128Const:
+			err = compiler.compileV128Const(o)
+		case *wazeroir.OperationV128Add:
+			err = compiler.compileV128Add(o)
+		case *wazeroir.OperationV128Sub:
+			err = compiler.compileV128Sub(o)
+		case *wazeroir.OperationV128Load:
+			err = compiler.compileV128Load(o)
+		case *wazeroir.OperationV128LoadLane:
+			err = compiler.compileV128LoadLane(o)
+		case *wazeroir.OperationV128Store:
+			err = compiler.compileV128Store(o)
+		case *wazeroir.OperationV128StoreLane:
+			err = compiler.compileV128StoreLane(o)
+		case *wazeroir.OperationV128ExtractLane:
+			err = compiler.compileV128ExtractLane(o)
+		case *wazeroir.OperationV128ReplaceLane:
+			err = compiler.compileV128ReplaceLane(o)
+		case *wazeroir.OperationV128Splat:
+			err = compiler.compileV128Splat(o)
+		case *wazeroir.OperationV128Shuffle:
+			err = compiler.compileV128Shuffle(o)
+		case *wazeroir.OperationV128Swizzle:
+			err = compiler.compileV128Swizzle(o)
+		case *wazeroir.OperationV128AnyTrue:
+			err = compiler.compileV128AnyTrue(o)
+		case *wazeroir.OperationV128AllTrue:
+			err = compiler.compileV128AllTrue(o)
+		case *wazeroir.OperationV128BitMask:
+			err = compiler.compileV128BitMask(o)
+		case *wazeroir.OperationV128And:
+			err = compiler.compileV128And(o)
+		case *wazeroir.OperationV128Not:
+			err = compiler.compileV128Not(o)
+		case *wazeroir.OperationV128Or:
+			err = compiler.compileV128Or(o)
+		case *wazeroir.OperationV128Xor:
+			err = compiler.compileV128Xor(o)
+		case *wazeroir.OperationV128Bitselect:
+			err = compiler.compileV128Bitselect(o)
+		case *wazeroir.OperationV128AndNot:
+			err = compiler.compileV128AndNot(o)
+		case *wazeroir.OperationV128Shl:
+			err = compiler.compileV128Shl(o)
+		case *wazeroir.OperationV128Shr:
+			err = compiler.compileV128Shr(o)
+		case *wazeroir.OperationV128Cmp:
+			err = compiler.compileV128Cmp(o)
+		case *wazeroir.OperationV128AddSat:
+			err = compiler.compileV128AddSat(o)
+		case *wazeroir.OperationV128SubSat:
+			err = compiler.compileV128SubSat(o)
+		case *wazeroir.OperationV128Mul:
+			err = compiler.compileV128Mul(o)
+		case *wazeroir.OperationV128Div:
+			err = compiler.compileV128Div(o)
+		case *wazeroir.OperationV128Neg:
+			err = compiler.compileV128Neg(o)
+		case *wazeroir.OperationV128Sqrt:
+			err = compiler.compileV128Sqrt(o)
+		case *wazeroir.OperationV128Abs:
+			err = compiler.compileV128Abs(o)
+		case *wazeroir.OperationV128Popcnt:
+			err = compiler.compileV128Popcnt(o)
+		case *wazeroir.OperationV128Min:
+			err = compiler.compileV128Min(o)
+		case *wazeroir.OperationV128Max:
+			err = compiler.compileV128Max(o)
+		case *wazeroir.OperationV128AvgrU:
+			err = compiler.compileV128AvgrU(o)
+		case *wazeroir.OperationV128Pmin:
+			err = compiler.compileV128Pmin(o)
+		case *wazeroir.OperationV128Pmax:
+			err = compiler.compileV128Pmax(o)
+		case *wazeroir.OperationV128Ceil:
+			err = compiler.compileV128Ceil(o)
+		case *wazeroir.OperationV128Floor:
+			err = compiler.compileV128Floor(o)
+		case *wazeroir.OperationV128Trunc:
+			err = compiler.compileV128Trunc(o)
+		case *wazeroir.OperationV128Nearest:
+			err = compiler.compileV128Nearest(o)
+		case *wazeroir.OperationV128Extend:
+			err = compiler.compileV128Extend(o)
+		case *wazeroir.OperationV128ExtMul:
+			err = compiler.compileV128ExtMul(o)
+		case *wazeroir.OperationV128Q15mulrSatS:
+			err = compiler.compileV128Q15mulrSatS(o)
+		case *wazeroir.OperationV128ExtAddPairwise:
+			err = compiler.compileV128ExtAddPairwise(o)
+		case *wazeroir.OperationV128FloatPromote:
+			err = compiler.compileV128FloatPromote(o)
+		case *wazeroir.OperationV128FloatDemote:
+			err = compiler.compileV128FloatDemote(o)
+		case *wazeroir.OperationV128FConvertFromI:
+			err = compiler.compileV128FConvertFromI(o)
+		case *wazeroir.OperationV128Dot:
+			err = compiler.compileV128Dot(o)
+		case *wazeroir.OperationV128Narrow:
+			err = compiler.compileV128Narrow(o)
+		case *wazeroir.OperationV128ITruncSatFromF:
+			err = compiler.compileV128ITruncSatFromF(o)
+		case *wazeroir.OperationAtomicLoad:
+			err = compiler.compileAtomicLoad(o)
+		case *wazeroir.OperationAtomicStore:
+			err = compiler.compileAtomicStore(o)
+		case *wazeroir.OperationAtomicRMW:
+			err = compiler.compileAtomicRMW(o)
+		case *wazeroir.OperationAtomicRMWCmpxchg:
+			err = compiler.compileAtomicRMWCmpxchg(o)
+		case *wazeroir.OperationAtomicMemoryWait:
+			err = compiler.compileAtomicMemoryWait(o)
+		case *wazeroir.OperationAtomicMemoryNotify:
+			err = compiler.compileAtomicMemoryNotify(o)
+		case *wazeroir.OperationAtomicFence:
+			err = compiler.compileAtomicFence(o)
+		case *wazeroir.OperationTry:
+			err = compiler.compileTry(o)
+		case *wazeroir.OperationThrow:
+			err = compiler.compileThrow(o)
+		case *wazeroir.OperationRethrow:
+			err = compiler.compileRethrow()
+		case *wazeroir.OperationConsumeFuel:
+			err = compiler.compileConsumeFuel(o)
 		default:
 			err = errors.New("unsupported")
 		}
 		if err != nil {
 			return nil, fmt.Errorf("operation %s: %w", op.Kind().String(), err)
 		}
+
+		if memoryIndex != 0 {
+			if err = compiler.compileSelectMemory(0); err != nil {
+				return nil, fmt.Errorf("operation %s: %w", op.Kind().String(), err)
+			}
+		}
 	}
 
 	c, staticData, stackPointerCeil, err := compiler.compile()
@@ -1057,5 +1677,61 @@ func compileWasmFunction(_ wasm.Features, ir *wazeroir.CompilationResult) (*code
 		return nil, fmt.Errorf("failed to compile: %w", err)
 	}
 
-	return &code{codeSegment: c, stackPointerCeil: stackPointerCeil, staticData: staticData}, nil
+	return &code{codeSegment: c, stackPointerCeil: stackPointerCeil, staticData: staticData, tryBlocks: compiler.resolveTryBlocks()}, nil
+}
+
+// memoryIndexOf returns the index of the memory which the given operation accesses. This returns false if the operation
+// doesn't access any memory or accesses two different memories, i.e. wazeroir.OperationMemoryCopy.
+func memoryIndexOf(op wazeroir.Operation) (uint32, bool) {
+	switch o := op.(type) {
+	case *wazeroir.OperationLoad:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationLoad8:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationLoad16:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationLoad32:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationStore:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationStore8:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationStore16:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationStore32:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationV128Load:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationV128LoadLane:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationV128Store:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationV128StoreLane:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationAtomicLoad:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationAtomicStore:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationAtomicRMW:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationAtomicRMWCmpxchg:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationAtomicMemoryWait:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationAtomicMemoryNotify:
+		return o.Arg.MemoryIndex, true
+	case *wazeroir.OperationMemorySize:
+		return o.MemoryIndex, true
+	case *wazeroir.OperationMemoryGrow:
+		return o.MemoryIndex, true
+	case *wazeroir.OperationMemoryInit:
+		return o.MemoryIndex, true
+	case *wazeroir.OperationMemoryFill:
+		return o.MemoryIndex, true
+	case *wazeroir.OperationMemoryCopy:
+		if o.SrcMemoryIndex == o.DstMemoryIndex {
+			return o.SrcMemoryIndex, true
+		}
+	}
+	return 0, false
 }
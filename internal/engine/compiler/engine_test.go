@@ -11,6 +11,7 @@ import (
 	"github.com/tetratelabs/wazero/internal/testing/enginetest"
 	"github.com/tetratelabs/wazero/internal/testing/require"
 	"github.com/tetratelabs/wazero/internal/wasm"
+	"github.com/tetratelabs/wazero/internal/wasmruntime"
 )
 
 // testCtx is an arbitrary, non-default context. Non-nil also prevents linter errors.
@@ -113,7 +114,7 @@ type engineTester struct{}
 
 // NewEngine implements enginetest.EngineTester NewEngine.
 func (e *engineTester) NewEngine(enabledFeatures wasm.Features) wasm.Engine {
-	return newEngine(enabledFeatures)
+	return newEngine(enabledFeatures, wasm.DefaultStackLimits)
 }
 
 // InitTables implements enginetest.EngineTester InitTables.
@@ -263,7 +264,7 @@ func TestCompiler_Releasecode_Panic(t *testing.T) {
 // See comments on initialValueStackSize and initialCallFrameStackSize.
 func TestCompiler_SliceAllocatedOnHeap(t *testing.T) {
 	enabledFeatures := wasm.Features20191205
-	e := newEngine(enabledFeatures)
+	e := newEngine(enabledFeatures, wasm.DefaultStackLimits)
 	store := wasm.NewStore(enabledFeatures, e)
 
 	const hostModuleName = "env"
@@ -359,9 +360,32 @@ func TestCompiler_SliceAllocatedOnHeap(t *testing.T) {
 	}
 }
 
+func TestCallEngine_StackCeilings(t *testing.T) {
+	me := &moduleEngine{stackLimits: wasm.StackLimits{CallStackCeiling: 20, ValueStackCeiling: 100}}
+	ce := me.newCallEngine()
+
+	// The call frame stack doubles, but not beyond the ceiling.
+	ce.builtinFunctionGrowCallFrameStack()
+	require.Equal(t, 20, len(ce.callFrameStack))
+	err := require.CapturePanic(ce.builtinFunctionGrowCallFrameStack)
+	require.Equal(t, wasmruntime.ErrRuntimeCallStackOverflow, err)
+
+	// The value stack grows as needed by the current function, but not beyond the ceiling.
+	ce.builtinFunctionGrowValueStack(70)
+	require.Equal(t, 100, len(ce.valueStack))
+	err = require.CapturePanic(func() { ce.builtinFunctionGrowValueStack(101) })
+	require.Equal(t, wasmruntime.ErrRuntimeValueStackOverflow, err)
+
+	// The initial stacks are not larger than the ceilings.
+	me.stackLimits = wasm.StackLimits{CallStackCeiling: 1, ValueStackCeiling: 1}
+	ce = me.newCallEngine()
+	require.Equal(t, 1, len(ce.callFrameStack))
+	require.Equal(t, 1, len(ce.valueStack))
+}
+
 // TODO: move most of this logic to enginetest.go so that there is less drift between interpreter and compiler
 func TestEngine_Cachedcodes(t *testing.T) {
-	e := newEngine(wasm.Features20191205)
+	e := newEngine(wasm.Features20191205, wasm.DefaultStackLimits)
 	exp := []*code{
 		{codeSegment: []byte{0x0}},
 		{codeSegment: []byte{0x0}},
@@ -60,6 +60,10 @@ var (
 	float64ForMaximumSigned64bitIntPlusOneAddress uintptr
 )
 
+// amd64CallEngineFuelContextFuelAddressOffset is the offset of fuelContext.fuelAddress in callEngine.
+// Note: this follows the empty archContext for amd64.
+const amd64CallEngineFuelContextFuelAddressOffset = 136
+
 func init() {
 	// TODO: what if these address exceed 32-bit address space?  Even though AMD says 2GB memory space
 	// should be enough for everyone, we might end up in these circum stances. We access these variables
@@ -140,6 +144,8 @@ type amd64Compiler struct {
 	// onStackPointerCeilDeterminedCallBack hold a callback which are called when the max stack pointer is determined BEFORE generating native code.
 	onStackPointerCeilDeterminedCallBack func(stackPointerCeil uint64)
 	staticData                           codeStaticData
+	// tryBlocks tracks the try blocks of this function.
+	tryBlocks tryBlockTable
 }
 
 func newAmd64Compiler(ir *wazeroir.CompilationResult) (compiler, error) {
@@ -757,27 +763,70 @@ func (c *amd64Compiler) compileCall(o *wazeroir.OperationCall) error {
 
 // compileCallIndirect implements compiler.compileCallIndirect for the amd64 architecture.
 func (c *amd64Compiler) compileCallIndirect(o *wazeroir.OperationCallIndirect) error {
+	targetFunctionAddressRegister, err := c.compileCallIndirectTarget(o.TypeIndex, o.TableIndex)
+	if err != nil {
+		return err
+	}
+
+	targetFunctionType := c.ir.Types[o.TypeIndex]
+	if err = c.compileCallFunctionImpl(0, targetFunctionAddressRegister, targetFunctionType); err != nil {
+		return nil
+	}
+
+	// The offset register should be marked as un-used as we consumed in the function call.
+	c.locationStack.markRegisterUnused(targetFunctionAddressRegister)
+
+	// We consumed the function parameters from the stack after call.
+	for i := 0; i < targetFunctionType.ParamNumInUint64; i++ {
+		c.locationStack.pop()
+	}
+
+	// Also, the function results were pushed by the call.
+	for _, t := range targetFunctionType.Results {
+		loc := c.locationStack.pushRuntimeValueLocationOnStack()
+		switch t {
+		case wasm.ValueTypeI32:
+			loc.valueType = runtimeValueTypeI32
+		case wasm.ValueTypeI64, wasm.ValueTypeFuncref, wasm.ValueTypeExternref:
+			loc.valueType = runtimeValueTypeI64
+		case wasm.ValueTypeF32:
+			loc.valueType = runtimeValueTypeF32
+		case wasm.ValueTypeF64:
+			loc.valueType = runtimeValueTypeF64
+		case wasm.ValueTypeV128:
+			loc.valueType = runtimeValueTypeV128Lo
+			hi := c.locationStack.pushRuntimeValueLocationOnStack()
+			hi.valueType = runtimeValueTypeV128Hi
+		}
+	}
+	return nil
+}
+
+// compileCallIndirectTarget adds instructions to pop the table offset from the stack, and load table[offset] into a
+// register after checking that it is in bounds, initialized and of the given type. The register holding the *function
+// is returned and must be marked unused by the caller once consumed.
+func (c *amd64Compiler) compileCallIndirectTarget(typeIndex, tableIndex wasm.Index) (asm.Register, error) {
 	offset := c.locationStack.pop()
 	if err := c.compileEnsureOnGeneralPurposeRegister(offset); err != nil {
-		return nil
+		return asm.NilRegister, err
 	}
 
 	tmp, err := c.allocateRegister(registerTypeGeneralPurpose)
 	if err != nil {
-		return err
+		return asm.NilRegister, err
 	}
 	c.locationStack.markRegisterUsed(tmp)
 
 	tmp2, err := c.allocateRegister(registerTypeGeneralPurpose)
 	if err != nil {
-		return err
+		return asm.NilRegister, err
 	}
 	c.locationStack.markRegisterUsed(tmp2)
 
 	// Load the address of the target table: tmp = &module.Tables[0]
 	c.assembler.CompileMemoryToRegister(amd64.MOVQ, amd64ReservedRegisterForCallEngine, callEngineModuleContextTablesElement0AddressOffset, tmp)
-	// tmp = &module.Tables[0] + Index*8 = &module.Tables[0] + sizeOf(*TableInstance)*index = module.Tables[o.TableIndex].
-	c.assembler.CompileMemoryToRegister(amd64.MOVQ, tmp, int64(o.TableIndex*8), tmp)
+	// tmp = &module.Tables[0] + Index*8 = &module.Tables[0] + sizeOf(*TableInstance)*index = module.Tables[tableIndex].
+	c.assembler.CompileMemoryToRegister(amd64.MOVQ, tmp, int64(tableIndex*8), tmp)
 
 	// Then, we need to check if the offset doesn't exceed the length of table.
 	c.assembler.CompileMemoryToRegister(amd64.CMPQ, tmp, tableInstanceTableLenOffset, offset.register)
@@ -822,7 +871,7 @@ func (c *amd64Compiler) compileCallIndirect(o *wazeroir.OperationCallIndirect) e
 	c.assembler.CompileMemoryToRegister(amd64.MOVQ,
 		amd64ReservedRegisterForCallEngine, callEngineModuleContextTypeIDsElement0AddressOffset,
 		tmp2)
-	c.assembler.CompileMemoryToRegister(amd64.MOVL, tmp2, int64(o.TypeIndex)*4, tmp2)
+	c.assembler.CompileMemoryToRegister(amd64.MOVL, tmp2, int64(typeIndex)*4, tmp2)
 
 	// Jump if the type matches.
 	c.assembler.CompileMemoryToRegister(amd64.CMPL, tmp, functionInstanceTypeIDOffset, tmp2)
@@ -832,36 +881,107 @@ func (c *amd64Compiler) compileCallIndirect(o *wazeroir.OperationCallIndirect) e
 	c.compileExitFromNativeCode(nativeCallStatusCodeTypeMismatchOnIndirectCall)
 
 	c.assembler.SetJumpTargetOnNext(jumpIfTypeMatch)
-	targetFunctionType := c.ir.Types[o.TypeIndex]
-	if err = c.compileCallFunctionImpl(0, offset.register, targetFunctionType); err != nil {
-		return nil
+
+	c.locationStack.markRegisterUnused(tmp, tmp2)
+	return offset.register, nil
+}
+
+// compileTailCall implements compiler.compileTailCall for the amd64 architecture.
+func (c *amd64Compiler) compileTailCall(o *wazeroir.OperationTailCall) error {
+	target := c.ir.Functions[o.FunctionIndex]
+	return c.compileTailCallFunctionImpl(o.FunctionIndex, asm.NilRegister, c.ir.Types[target])
+}
+
+// compileTailCallIndirect implements compiler.compileTailCallIndirect for the amd64 architecture.
+func (c *amd64Compiler) compileTailCallIndirect(o *wazeroir.OperationTailCallIndirect) error {
+	targetFunctionAddressRegister, err := c.compileCallIndirectTarget(o.TypeIndex, o.TableIndex)
+	if err != nil {
+		return err
 	}
 
-	// The offset register should be marked as un-used as we consumed in the function call.
-	c.locationStack.markRegisterUnused(offset.register, tmp, tmp2)
+	if err = c.compileTailCallFunctionImpl(0, targetFunctionAddressRegister, c.ir.Types[o.TypeIndex]); err != nil {
+		return err
+	}
+	c.locationStack.markRegisterUnused(targetFunctionAddressRegister)
+	return nil
+}
 
-	// We consumed the function parameters from the stack after call.
-	for i := 0; i < targetFunctionType.ParamNumInUint64; i++ {
-		c.locationStack.pop()
+// compileTailCallFunctionImpl adds instructions to replace the current call frame's function with the target one,
+// and jump into its initial address. The target is specified either by index or by the register holding the
+// *function (call_indirect case).
+//
+// Unlike compileCallFunctionImpl, this pushes no call frame and leaves the return address and the stack base pointer
+// untouched: the target function returns directly to our caller, and wazeroir already dropped all the values below
+// the function arguments, so the arguments sit at the current stack base pointer as if our caller called the target.
+func (c *amd64Compiler) compileTailCallFunctionImpl(index wasm.Index, functionAddressRegister asm.Register, functype *wasm.FunctionType) error {
+	// Release all the registers as our calling convention requires the caller-save.
+	c.compileReleaseAllRegistersToStack()
+
+	if !isNilRegister(functionAddressRegister) {
+		c.locationStack.markRegisterUsed(functionAddressRegister)
 	}
 
-	// Also, the function results were pushed by the call.
-	for _, t := range targetFunctionType.Results {
-		loc := c.locationStack.pushRuntimeValueLocationOnStack()
-		switch t {
-		case wasm.ValueTypeI32:
-			loc.valueType = runtimeValueTypeI32
-		case wasm.ValueTypeI64, wasm.ValueTypeFuncref, wasm.ValueTypeExternref:
-			loc.valueType = runtimeValueTypeI64
-		case wasm.ValueTypeF32:
-			loc.valueType = runtimeValueTypeF32
-		case wasm.ValueTypeF64:
-			loc.valueType = runtimeValueTypeF64
-		case wasm.ValueTypeV128:
-			loc.valueType = runtimeValueTypeV128Lo
-			hi := c.locationStack.pushRuntimeValueLocationOnStack()
-			hi.valueType = runtimeValueTypeV128Hi
-		}
+	freeRegs, found := c.locationStack.takeFreeRegisters(registerTypeGeneralPurpose, 2)
+	if !found {
+		// This in theory never happen as all the registers must be free except functionAddressRegister.
+		return fmt.Errorf("could not find enough free registers")
+	}
+	c.locationStack.markRegisterUsed(freeRegs...)
+
+	// Alias these free tmp registers for readability.
+	currentCallFrameAddressRegister, targetFunctionAddressRegister := freeRegs[0], freeRegs[1]
+
+	if isNilRegister(functionAddressRegister) {
+		// "targetFunctionAddressRegister = callEngine.functions[index]"
+		c.assembler.CompileMemoryToRegister(amd64.MOVQ, amd64ReservedRegisterForCallEngine,
+			callEngineModuleContextFunctionsElement0AddressOffset, targetFunctionAddressRegister)
+		c.assembler.CompileMemoryToRegister(amd64.MOVQ,
+			// Note: FunctionIndex is limited up to 2^27 so this offset never exceeds 32-bit integer.
+			targetFunctionAddressRegister, int64(index)*8,
+			targetFunctionAddressRegister,
+		)
+	} else {
+		targetFunctionAddressRegister = functionAddressRegister
+	}
+
+	// "currentCallFrameAddressRegister = &callEngine.callFrameStack[callEngine.callFrameStackPointer]", which is the
+	// address right after the current call frame.
+	c.assembler.CompileMemoryToRegister(amd64.MOVQ,
+		amd64ReservedRegisterForCallEngine, callEngineGlobalContextCallFrameStackPointerOffset,
+		currentCallFrameAddressRegister)
+	c.assembler.CompileConstToRegister(amd64.SHLQ, int64(callFrameDataSizeMostSignificantSetBit), currentCallFrameAddressRegister)
+	c.assembler.CompileMemoryToRegister(amd64.ADDQ,
+		amd64ReservedRegisterForCallEngine, callEngineGlobalContextCallFrameStackElement0AddressOffset,
+		currentCallFrameAddressRegister)
+
+	// Replace the function of the current call frame so that Go functions called from the target one (builtin or
+	// host functions) see the target function as the top call frame.
+	c.assembler.CompileRegisterToMemory(amd64.MOVQ, targetFunctionAddressRegister,
+		// The current call frame is BELOW the address. See the example in compileCallFunctionImpl for detail.
+		currentCallFrameAddressRegister, -(callFrameDataSize - callFrameFunctionOffset),
+	)
+
+	if amd64CallingConventionModuleInstanceAddressRegister == targetFunctionAddressRegister {
+		// This case we must move the value on targetFunctionAddressRegister to another register, otherwise
+		// the address (jump target below) will be modified and result in segfault.
+		// See #526.
+		c.assembler.CompileRegisterToRegister(amd64.MOVQ, targetFunctionAddressRegister, currentCallFrameAddressRegister)
+		targetFunctionAddressRegister = currentCallFrameAddressRegister
+	}
+
+	// Put the target function's *wasm.ModuleInstance into amd64CallingConventionModuleInstanceAddressRegister.
+	c.assembler.CompileMemoryToRegister(amd64.MOVQ, targetFunctionAddressRegister, functionModuleInstanceAddressOffset,
+		amd64CallingConventionModuleInstanceAddressRegister)
+
+	// And jump into the initial address of the target function, which never comes back here.
+	c.assembler.CompileJumpToMemory(amd64.JMP, targetFunctionAddressRegister, functionCodeInitialAddressOffset)
+
+	// All the registers used are temporary, so we mark them unused.
+	c.locationStack.markRegisterUnused(freeRegs...)
+
+	// The function arguments were consumed by the target function.
+	for i := 0; i < functype.ParamNumInUint64; i++ {
+		c.locationStack.pop()
 	}
 	return nil
 }
@@ -914,12 +1034,16 @@ func (c *amd64Compiler) emitDropRange(r *wazeroir.InclusiveRange) error {
 //
 // The emitted native code depends on whether the values are on
 // the physical registers or memory stack, or maybe conditional register.
-func (c *amd64Compiler) compileSelect() error {
+func (c *amd64Compiler) compileSelect(o *wazeroir.OperationSelect) error {
 	cv := c.locationStack.pop()
 	if err := c.compileEnsureOnGeneralPurposeRegister(cv); err != nil {
 		return err
 	}
 
+	if o.IsTargetVector {
+		return c.compileSelectV128Impl(cv)
+	}
+
 	x2 := c.locationStack.pop()
 	// We do not consume x1 here, but modify the value according to
 	// the conditional value "c" above.
@@ -964,6 +1088,35 @@ func (c *amd64Compiler) compileSelect() error {
 	return nil
 }
 
+// compileSelectV128Impl emits the instructions to select one of the two vectors on the stack top, depending on the
+// conditional value on the register of cv.
+func (c *amd64Compiler) compileSelectV128Impl(cv *runtimeValueLocation) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	// Compare the conditional value with zero.
+	c.assembler.CompileRegisterToConst(amd64.CMPQ, cv.register, 0)
+
+	// Set the jump if the top value is not zero, in which case x1 is the result as-is.
+	jmpIfNotZero := c.assembler.CompileJump(amd64.JNE)
+
+	// If the value is zero, we place the value of x2 onto the register of x1.
+	c.assembler.CompileRegisterToRegister(amd64.MOVDQU, x2.register, x1.register)
+
+	c.assembler.SetJumpTargetOnNext(jmpIfNotZero)
+
+	c.locationStack.markRegisterUnused(x2.register, cv.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
 // compilePick implements compiler.compilePick for the amd64 architecture.
 func (c *amd64Compiler) compilePick(o *wazeroir.OperationPick) error {
 	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
@@ -1922,7 +2075,7 @@ func (c *amd64Compiler) compileMinOrMax(is32Bit bool, minOrMaxInstruction asm.In
 	// Jump if two values are equal and NaN-free by checking the parity flag (PF).
 	// Here we use JPC to do the conditional jump when the parity flag is NOT set,
 	// and that is of 2).
-	equalExitJmp := c.assembler.CompileJump(amd64.JPC)
+	equalJmp := c.assembler.CompileJump(amd64.JPC)
 
 	// Start handling 3).
 
@@ -1936,13 +2089,28 @@ func (c *amd64Compiler) compileMinOrMax(is32Bit bool, minOrMaxInstruction asm.In
 	// Exit from the NaN case branch.
 	nanExitJmp := c.assembler.CompileJump(amd64.JMP)
 
+	// Start handling 2).
+	c.assembler.SetJumpTargetOnNext(equalJmp)
+
+	// Equal values have the same bits unless they are zeros of different signs. In that case, Wasm specifies
+	// min(0, -0) = -0 and max(0, -0) = 0, whereas native min/max returns the second operand, so we combine the bits
+	// with OR for min (which keeps the sign bit) and with AND for max (which clears it).
+	if minOrMaxInstruction == amd64.MINSS || minOrMaxInstruction == amd64.MINSD {
+		c.assembler.CompileRegisterToRegister(amd64.ORPS, x2.register, x1.register)
+	} else {
+		c.assembler.CompileRegisterToRegister(amd64.ANDPS, x2.register, x1.register)
+	}
+
+	// Exit from the equal values case branch.
+	equalExitJmp := c.assembler.CompileJump(amd64.JMP)
+
 	// Start handling 1).
 	c.assembler.SetJumpTargetOnNext(nanFreeOrDiffJump)
 
 	// Now handle the NaN-free and different values case.
 	c.assembler.CompileRegisterToRegister(minOrMaxInstruction, x2.register, x1.register)
 
-	// Set the jump target of 1) and 2) cases to the next instruction after 3) case.
+	// Set the jump target of 2) and 3) cases to the next instruction after 1) case.
 	c.assembler.SetJumpTargetOnNext(nanExitJmp, equalExitJmp)
 
 	// Record that we consumed the x2 and placed the minOrMax result in the x1's register.
@@ -3282,6 +3450,9 @@ func (c *amd64Compiler) compileMemoryAccessCeilSetup(offsetArg uint32, targetSiz
 		return result, nil
 	}
 
+	// The base is 64-bit for a memory indexed with i64, so jump to the exit if the addition above overflowed.
+	overflowJmp := c.assembler.CompileJump(amd64.JCS)
+
 	// Now we compare the value with the memory length which is held by callEngine.
 	c.assembler.CompileMemoryToRegister(amd64.CMPQ,
 		amd64ReservedRegisterForCallEngine, callEngineModuleContextMemorySliceLenOffset, result)
@@ -3290,6 +3461,7 @@ func (c *amd64Compiler) compileMemoryAccessCeilSetup(offsetArg uint32, targetSiz
 	okJmp := c.assembler.CompileJump(amd64.JCC)
 
 	// Otherwise, we exit the function with out of bounds status code.
+	c.assembler.SetJumpTargetOnNext(overflowJmp)
 	c.compileExitFromNativeCode(nativeCallStatusCodeMemoryOutOfBounds)
 
 	c.assembler.SetJumpTargetOnNext(okJmp)
@@ -3365,6 +3537,217 @@ func (c *amd64Compiler) compileMemoryGrow() error {
 	return nil
 }
 
+// compileAtomicLoad implements compiler.compileAtomicLoad for the amd64 architecture.
+func (c *amd64Compiler) compileAtomicLoad(o *wazeroir.OperationAtomicLoad) error {
+	if err := c.compileCallAtomicBuiltinFunction(builtinFunctionIndexAtomicLoad,
+		atomicImmediate(o.Arg, o.SizeInBytes, 0), 1); err != nil {
+		return err
+	}
+	c.locationStack.pushRuntimeValueLocationOnStack().valueType = atomicResultType(o.Type)
+	return nil
+}
+
+// compileAtomicStore implements compiler.compileAtomicStore for the amd64 architecture.
+func (c *amd64Compiler) compileAtomicStore(o *wazeroir.OperationAtomicStore) error {
+	return c.compileCallAtomicBuiltinFunction(builtinFunctionIndexAtomicStore,
+		atomicImmediate(o.Arg, o.SizeInBytes, 0), 2)
+}
+
+// compileAtomicRMW implements compiler.compileAtomicRMW for the amd64 architecture.
+func (c *amd64Compiler) compileAtomicRMW(o *wazeroir.OperationAtomicRMW) error {
+	if err := c.compileCallAtomicBuiltinFunction(builtinFunctionIndexAtomicRMW,
+		atomicImmediate(o.Arg, o.SizeInBytes, o.Op), 2); err != nil {
+		return err
+	}
+	c.locationStack.pushRuntimeValueLocationOnStack().valueType = atomicResultType(o.Type)
+	return nil
+}
+
+// compileAtomicRMWCmpxchg implements compiler.compileAtomicRMWCmpxchg for the amd64 architecture.
+func (c *amd64Compiler) compileAtomicRMWCmpxchg(o *wazeroir.OperationAtomicRMWCmpxchg) error {
+	if err := c.compileCallAtomicBuiltinFunction(builtinFunctionIndexAtomicRMWCmpxchg,
+		atomicImmediate(o.Arg, o.SizeInBytes, 0), 3); err != nil {
+		return err
+	}
+	c.locationStack.pushRuntimeValueLocationOnStack().valueType = atomicResultType(o.Type)
+	return nil
+}
+
+// compileAtomicMemoryWait implements compiler.compileAtomicMemoryWait for the amd64 architecture.
+func (c *amd64Compiler) compileAtomicMemoryWait(o *wazeroir.OperationAtomicMemoryWait) error {
+	sizeInBytes := uint32(4)
+	if o.Type == wazeroir.UnsignedInt64 {
+		sizeInBytes = 8
+	}
+	if err := c.compileCallAtomicBuiltinFunction(builtinFunctionIndexAtomicMemoryWait,
+		atomicImmediate(o.Arg, sizeInBytes, 0), 3); err != nil {
+		return err
+	}
+	c.locationStack.pushRuntimeValueLocationOnStack().valueType = runtimeValueTypeI32
+	return nil
+}
+
+// compileAtomicMemoryNotify implements compiler.compileAtomicMemoryNotify for the amd64 architecture.
+func (c *amd64Compiler) compileAtomicMemoryNotify(o *wazeroir.OperationAtomicMemoryNotify) error {
+	if err := c.compileCallAtomicBuiltinFunction(builtinFunctionIndexAtomicMemoryNotify,
+		atomicImmediate(o.Arg, 4, 0), 2); err != nil {
+		return err
+	}
+	c.locationStack.pushRuntimeValueLocationOnStack().valueType = runtimeValueTypeI32
+	return nil
+}
+
+// compileAtomicFence implements compiler.compileAtomicFence for the amd64 architecture.
+//
+// This emits nothing as every atomic instruction is implemented by a builtin function whose accesses are
+// sequentially consistent.
+func (c *amd64Compiler) compileAtomicFence(*wazeroir.OperationAtomicFence) error {
+	return nil
+}
+
+// compileCallAtomicBuiltinFunction calls the atomic builtin function of the given index with the immediate packed
+// by atomicImmediate. operandNum is the number of the operands of the instruction, which are consumed by the builtin
+// function together with the immediate. The caller is responsible for pushing the result, if any.
+func (c *amd64Compiler) compileCallAtomicBuiltinFunction(index wasm.Index, imm uint64, operandNum int) error {
+	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
+
+	// Pushes the immediate.
+	if err := c.compileConstI64(&wazeroir.OperationConstI64{Value: imm}); err != nil {
+		return err
+	}
+
+	// Atomic instructions are implemented in Go, so that both engines share the same implementation including
+	// wait and notify, which have to block the goroutine.
+	if err := c.compileCallBuiltinFunction(index); err != nil {
+		return err
+	}
+
+	for i := 0; i < operandNum+1; i++ {
+		c.locationStack.pop()
+	}
+
+	// After return, we re-initialize reserved registers just like preamble of functions.
+	c.compileReservedStackBasePointerInitialization()
+	c.compileReservedMemoryPointerInitialization()
+	return nil
+}
+
+// compileTry implements compiler.compileTry for the amd64 architecture.
+func (c *amd64Compiler) compileTry(o *wazeroir.OperationTry) error {
+	// We use NOP as the beginning of the protected region.
+	c.tryBlocks.begin(o, c.assembler.CompileStandAlone(amd64.NOP), c.locationStack)
+	return nil
+}
+
+// compileCatch implements compiler.compileCatch for the amd64 architecture.
+func (c *amd64Compiler) compileCatch(o *wazeroir.OperationCatch) (skipThisHandler bool, err error) {
+	tb := c.tryBlocks.compiled(o.FrameID)
+	if tb == nil { // The try block is never reached, and neither is this handler.
+		return true, nil
+	}
+
+	// We use NOP as the beginning of the handler, which the engine jumps into when catching an exception.
+	c.tryBlocks.addCatch(o, c.assembler.CompileStandAlone(amd64.NOP))
+
+	// The engine places all the values on the stack, and the handler might be in a different module from the one
+	// where the exception is raised. So we initialize the location stack and the reserved registers as in the
+	// preamble of functions.
+	c.setLocationStack(newRuntimeValueLocationStack())
+	tb.pushHandlerValues(c.locationStack, o, c.ir.Tags)
+	if err = c.compileModuleContextInitialization(); err != nil {
+		return
+	}
+	c.compileReservedStackBasePointerInitialization()
+	c.compileReservedMemoryPointerInitialization()
+	return
+}
+
+// compileDelegate implements compiler.compileDelegate for the amd64 architecture.
+func (c *amd64Compiler) compileDelegate(o *wazeroir.OperationDelegate) error {
+	if c.tryBlocks.compiled(o.FrameID) != nil {
+		// We use NOP as the end of the protected region.
+		c.tryBlocks.delegate(o, c.assembler.CompileStandAlone(amd64.NOP))
+	}
+	return nil
+}
+
+// compileThrow implements compiler.compileThrow for the amd64 architecture.
+func (c *amd64Compiler) compileThrow(o *wazeroir.OperationThrow) error {
+	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
+
+	// Pushes the tag index, so that the engine can pop the values of the tag.
+	if err := c.compileConstI32(&wazeroir.OperationConstI32{Value: o.TagIndex}); err != nil {
+		return err
+	}
+
+	if err := c.compileCallBuiltinFunction(builtinFunctionIndexThrow); err != nil {
+		return err
+	}
+	// The engine never returns here, but jumps into the handler or escapes the call with the exception.
+	return c.compileUnreachable()
+}
+
+// compileRethrow implements compiler.compileRethrow for the amd64 architecture.
+func (c *amd64Compiler) compileRethrow() error {
+	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
+
+	// Like compileThrow, the engine pops the handle of the exception and never returns here.
+	if err := c.compileCallBuiltinFunction(builtinFunctionIndexRethrow); err != nil {
+		return err
+	}
+	return c.compileUnreachable()
+}
+
+// compileConsumeFuel implements compiler.compileConsumeFuel for the amd64 architecture.
+func (c *amd64Compiler) compileConsumeFuel(o *wazeroir.OperationConsumeFuel) error {
+	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
+
+	fuelAddress, err := c.allocateRegister(registerTypeGeneralPurpose)
+	if err != nil {
+		return err
+	}
+	c.locationStack.markRegisterUsed(fuelAddress)
+
+	fuel, err := c.allocateRegister(registerTypeGeneralPurpose)
+	if err != nil {
+		return err
+	}
+	c.locationStack.markRegisterUsed(fuel)
+
+	c.assembler.CompileMemoryToRegister(amd64.MOVQ, amd64ReservedRegisterForCallEngine, amd64CallEngineFuelContextFuelAddressOffset, fuelAddress)
+	c.assembler.CompileMemoryToRegister(amd64.MOVQ, fuelAddress, 0, fuel)
+
+	if o.Cost <= math.MaxInt32 {
+		// ADDQ with the negated cost sets the same sign flag as subtracting it, as the fuel is never negative.
+		c.assembler.CompileConstToRegister(amd64.ADDQ, -int64(o.Cost), fuel)
+	} else {
+		// The cost doesn't fit in the 32-bit immediate, so we subtract it via a register.
+		cost, err := c.allocateRegister(registerTypeGeneralPurpose)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileConstToRegister(amd64.MOVQ, int64(o.Cost), cost)
+		c.assembler.CompileRegisterToRegister(amd64.SUBQ, cost, fuel)
+	}
+
+	// Jump if the remaining fuel is still non-negative.
+	okJmp := c.assembler.CompileJump(amd64.JGE)
+
+	// Otherwise, we exit without writing back the fuel, so that it never becomes negative.
+	c.compileExitFromNativeCode(nativeCallStatusCodeFuelExhausted)
+
+	c.assembler.SetJumpTargetOnNext(okJmp)
+	c.assembler.CompileRegisterToMemory(amd64.MOVQ, fuel, fuelAddress, 0)
+
+	c.locationStack.markRegisterUnused(fuelAddress, fuel)
+	return nil
+}
+
+// resolveTryBlocks implements compiler.resolveTryBlocks for the amd64 architecture.
+func (c *amd64Compiler) resolveTryBlocks() []*tryBlock {
+	return c.tryBlocks.resolve()
+}
+
 // compileMemorySize implements compiler.compileMemorySize for the amd64 architecture.
 func (c *amd64Compiler) compileMemorySize() error {
 	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
@@ -3838,6 +4221,92 @@ func (c *amd64Compiler) compileMemoryFill() error {
 	return c.compileFillImpl(false, 0)
 }
 
+// compileReloadMemoryLength implements compiler.compileReloadMemoryLength for the amd64 architecture.
+func (c *amd64Compiler) compileReloadMemoryLength() error {
+	tmp, err := c.allocateRegister(registerTypeGeneralPurpose)
+	if err != nil {
+		return err
+	}
+
+	// "tmp = len(ModuleInstance.Memory.Buffer)" of the module instance in the module context.
+	c.assembler.CompileMemoryToRegister(amd64.MOVQ,
+		amd64ReservedRegisterForCallEngine, callEngineModuleContextModuleInstanceAddressOffset, tmp)
+	c.assembler.CompileMemoryToRegister(amd64.MOVQ, tmp, moduleInstanceMemoryOffset, tmp)
+	c.assembler.CompileMemoryToRegister(amd64.MOVQ, tmp, memoryInstanceBufferLenOffset, tmp)
+
+	// The buffer of a shared memory never moves, so only the length needs to be updated.
+	c.assembler.CompileRegisterToMemory(amd64.MOVQ,
+		tmp, amd64ReservedRegisterForCallEngine, callEngineModuleContextMemorySliceLenOffset)
+	return nil
+}
+
+// compileSelectMemory implements compiler.compileSelectMemory for the amd64 architecture.
+func (c *amd64Compiler) compileSelectMemory(index uint32) error {
+	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
+
+	// Pushes the memory index.
+	if err := c.compileConstI32(&wazeroir.OperationConstI32{Value: index}); err != nil {
+		return err
+	}
+
+	// The buffer of the memory is only reachable from Go, so call out to the builtin function to update the module
+	// context with the memory.
+	if err := c.compileCallBuiltinFunction(builtinFunctionIndexSelectMemory); err != nil {
+		return err
+	}
+
+	// SelectMemory consumes the memory index.
+	c.locationStack.pop()
+
+	// After the function call, we have to initialize the stack base pointer and memory reserved registers.
+	c.compileReservedStackBasePointerInitialization()
+	c.compileReservedMemoryPointerInitialization()
+	return nil
+}
+
+// compileBuiltinMemoryCopy implements compiler.compileBuiltinMemoryCopy for the amd64 architecture.
+func (c *amd64Compiler) compileBuiltinMemoryCopy(o *wazeroir.OperationMemoryCopy) error {
+	// Consumes the memory indexes, size, source and destination offsets.
+	return c.compileCallBuiltinMemoryFunction(builtinFunctionIndexMemoryCopy, 3, o.SrcMemoryIndex, o.DstMemoryIndex)
+}
+
+// compileBuiltinMemoryFill implements compiler.compileBuiltinMemoryFill for the amd64 architecture.
+func (c *amd64Compiler) compileBuiltinMemoryFill() error {
+	// Consumes the size, value and offset.
+	return c.compileCallBuiltinMemoryFunction(builtinFunctionIndexMemoryFill, 3)
+}
+
+// compileBuiltinMemoryInit implements compiler.compileBuiltinMemoryInit for the amd64 architecture.
+func (c *amd64Compiler) compileBuiltinMemoryInit(o *wazeroir.OperationMemoryInit) error {
+	// Consumes the data index, size, offsets in the data and memory.
+	return c.compileCallBuiltinMemoryFunction(builtinFunctionIndexMemoryInit, 3, o.DataIndex)
+}
+
+// compileCallBuiltinMemoryFunction pushes the immediates onto the stack, and calls the builtin function of the given
+// index, which consumes them as well as the given number of operands below.
+func (c *amd64Compiler) compileCallBuiltinMemoryFunction(index wasm.Index, operands int, immediates ...uint32) error {
+	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
+
+	for _, imm := range immediates {
+		if err := c.compileConstI32(&wazeroir.OperationConstI32{Value: imm}); err != nil {
+			return err
+		}
+	}
+
+	if err := c.compileCallBuiltinFunction(index); err != nil {
+		return err
+	}
+
+	for i := 0; i < operands+len(immediates); i++ {
+		c.locationStack.pop()
+	}
+
+	// After the function call, we have to initialize the stack base pointer and memory reserved registers.
+	c.compileReservedStackBasePointerInitialization()
+	c.compileReservedMemoryPointerInitialization()
+	return nil
+}
+
 // compileTableInit implements compiler.compileTableInit for the amd64 architecture.
 func (c *amd64Compiler) compileTableInit(o *wazeroir.OperationTableInit) error {
 	return c.compileInitImpl(true, o.ElemIndex, o.TableIndex)
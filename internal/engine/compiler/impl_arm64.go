@@ -10,7 +10,6 @@
 package compiler
 
 import (
-	"errors"
 	"fmt"
 	"math"
 	"unsafe"
@@ -36,6 +35,8 @@ type arm64Compiler struct {
 	// codeStaticData holds br_table offset tables.
 	// See codeStaticData and arm64Compiler.compileBrTable.
 	staticData codeStaticData
+	// tryBlocks tracks the try blocks of this function.
+	tryBlocks tryBlockTable
 }
 
 func newArm64Compiler(ir *wazeroir.CompilationResult) (compiler, error) {
@@ -93,6 +94,8 @@ const (
 	arm64CallEngineArchContextMinimum32BitSignedIntOffset = 144
 	// arm64CallEngineArchContextMinimum64BitSignedIntOffset is the offset of archContext.minimum64BitSignedIntAddress in callEngine.
 	arm64CallEngineArchContextMinimum64BitSignedIntOffset = 152
+	// arm64CallEngineFuelContextFuelAddressOffset is the offset of fuelContext.fuelAddress in callEngine.
+	arm64CallEngineFuelContextFuelAddressOffset = 160
 )
 
 func isZeroRegister(r asm.Register) bool {
@@ -159,6 +162,12 @@ func (c *arm64Compiler) pushRuntimeValueLocationOnRegister(reg asm.Register, vt
 	return
 }
 
+func (c *arm64Compiler) pushVectorRuntimeValueLocationOnRegister(reg asm.Register) {
+	c.locationStack.pushRuntimeValueLocationOnRegister(reg, runtimeValueTypeV128Lo)
+	c.locationStack.pushRuntimeValueLocationOnRegister(reg, runtimeValueTypeV128Hi)
+	c.markRegisterUsed(reg)
+}
+
 func (c *arm64Compiler) markRegisterUsed(regs ...asm.Register) {
 	for _, reg := range regs {
 		if !isZeroRegister(reg) && reg != asm.NilRegister {
@@ -202,10 +211,6 @@ func (c *arm64Compiler) pushFunctionParams() {
 
 // compilePreamble implements compiler.compilePreamble for the arm64 architecture.
 func (c *arm64Compiler) compilePreamble() error {
-	// The assembler skips the first instruction so we intentionally add NOP here.
-	// TODO: delete after #233
-	c.assembler.CompileStandAlone(arm64.NOP)
-
 	c.pushFunctionParams()
 
 	// Check if it's necessary to grow the value stack before entering function body.
@@ -405,10 +410,6 @@ func (c *arm64Compiler) compileExitFromNativeCode(status nativeCallStatusCode) {
 
 // compileHostFunction implements compiler.compileHostFunction for the arm64 architecture.
 func (c *arm64Compiler) compileHostFunction() error {
-	// The assembler skips the first instruction so we intentionally add NOP here.
-	// TODO: delete after #233
-	c.assembler.CompileStandAlone(arm64.NOP)
-
 	// First we must update the location stack to reflect the number of host function inputs.
 	c.pushFunctionParams()
 
@@ -1136,15 +1137,34 @@ func (c *arm64Compiler) compileCalcCallFrameStackTopAddress(callFrameStackPointe
 
 // compileCallIndirect implements compiler.compileCallIndirect for the arm64 architecture.
 func (c *arm64Compiler) compileCallIndirect(o *wazeroir.OperationCallIndirect) error {
+	targetFunctionAddressRegister, err := c.compileCallIndirectTarget(o.TypeIndex, o.TableIndex)
+	if err != nil {
+		return err
+	}
+
+	targetFunctionType := c.ir.Types[o.TypeIndex]
+	if err := c.compileCallImpl(0, targetFunctionAddressRegister, targetFunctionType); err != nil {
+		return err
+	}
+
+	// The offset register should be marked as un-used as we consumed in the function call.
+	c.markRegisterUnused(targetFunctionAddressRegister)
+	return nil
+}
+
+// compileCallIndirectTarget adds instructions to pop the table offset from the stack, and load table[offset] into a
+// register after checking that it is in bounds, initialized and of the given type. The register holding the *function
+// is returned and must be marked unused by the caller once consumed.
+func (c *arm64Compiler) compileCallIndirectTarget(typeIndex, tableIndex wasm.Index) (asm.Register, error) {
 	offset := c.locationStack.pop()
 	if err := c.compileEnsureOnGeneralPurposeRegister(offset); err != nil {
-		return err
+		return asm.NilRegister, err
 	}
 
 	if isZeroRegister(offset.register) {
 		reg, err := c.allocateRegister(registerTypeGeneralPurpose)
 		if err != nil {
-			return err
+			return asm.NilRegister, err
 		}
 		offset.setRegister(reg)
 		c.markRegisterUsed(reg)
@@ -1155,13 +1175,13 @@ func (c *arm64Compiler) compileCallIndirect(o *wazeroir.OperationCallIndirect) e
 
 	tmp, err := c.allocateRegister(registerTypeGeneralPurpose)
 	if err != nil {
-		return err
+		return asm.NilRegister, err
 	}
 	c.markRegisterUsed(tmp)
 
 	tmp2, err := c.allocateRegister(registerTypeGeneralPurpose)
 	if err != nil {
-		return err
+		return asm.NilRegister, err
 	}
 	c.markRegisterUsed(tmp2)
 
@@ -1173,7 +1193,7 @@ func (c *arm64Compiler) compileCallIndirect(o *wazeroir.OperationCallIndirect) e
 	)
 	// tmp = [tmp + TableIndex*8] = [&Tables[0] + TableIndex*sizeOf(*tableInstance)] = Tables[tableIndex]
 	c.assembler.CompileMemoryToRegister(arm64.MOVD,
-		tmp, int64(o.TableIndex)*8,
+		tmp, int64(tableIndex)*8,
 		tmp,
 	)
 	// tmp2 = [tmp + tableInstanceTableLenOffset] = len(Tables[tableIndex])
@@ -1231,7 +1251,7 @@ func (c *arm64Compiler) compileCallIndirect(o *wazeroir.OperationCallIndirect) e
 	c.assembler.CompileMemoryToRegister(arm64.MOVD,
 		arm64ReservedRegisterForCallEngine, callEngineModuleContextTypeIDsElement0AddressOffset,
 		tmp2)
-	c.assembler.CompileMemoryToRegister(arm64.MOVWU, tmp2, int64(o.TypeIndex)*4, tmp2)
+	c.assembler.CompileMemoryToRegister(arm64.MOVWU, tmp2, int64(typeIndex)*4, tmp2)
 
 	// Compare these two values, and if they equal, we are ready to make function call.
 	c.assembler.CompileTwoRegistersToNone(arm64.CMPW, tmp, tmp2)
@@ -1240,13 +1260,105 @@ func (c *arm64Compiler) compileCallIndirect(o *wazeroir.OperationCallIndirect) e
 
 	c.assembler.SetJumpTargetOnNext(brIfTypeMatched)
 
-	targetFunctionType := c.ir.Types[o.TypeIndex]
-	if err := c.compileCallImpl(0, offset.register, targetFunctionType); err != nil {
+	c.markRegisterUnused(tmp, tmp2)
+	return offset.register, nil
+}
+
+// compileTailCall implements compiler.compileTailCall for the arm64 architecture.
+func (c *arm64Compiler) compileTailCall(o *wazeroir.OperationTailCall) error {
+	tp := c.ir.Types[c.ir.Functions[o.FunctionIndex]]
+	return c.compileTailCallImpl(o.FunctionIndex, asm.NilRegister, tp)
+}
+
+// compileTailCallIndirect implements compiler.compileTailCallIndirect for the arm64 architecture.
+func (c *arm64Compiler) compileTailCallIndirect(o *wazeroir.OperationTailCallIndirect) error {
+	targetFunctionAddressRegister, err := c.compileCallIndirectTarget(o.TypeIndex, o.TableIndex)
+	if err != nil {
 		return err
 	}
 
-	// The offset register should be marked as un-used as we consumed in the function call.
-	c.markRegisterUnused(offset.register, tmp, tmp2)
+	if err := c.compileTailCallImpl(0, targetFunctionAddressRegister, c.ir.Types[o.TypeIndex]); err != nil {
+		return err
+	}
+	c.markRegisterUnused(targetFunctionAddressRegister)
+	return nil
+}
+
+// compileTailCallImpl implements compiler.compileTailCall and compiler.compileTailCallIndirect for the arm64 architecture.
+//
+// Unlike compileCallImpl, this pushes no call frame and leaves the return address and the stack base pointer
+// untouched: the target function returns directly to our caller, and wazeroir already dropped all the values below
+// the function arguments, so the arguments sit at the current stack base pointer as if our caller called the target.
+func (c *arm64Compiler) compileTailCallImpl(index wasm.Index, targetFunctionAddressRegister asm.Register, functype *wasm.FunctionType) error {
+	// Release all the registers as our calling convention requires the caller-save.
+	if err := c.compileReleaseAllRegistersToStack(); err != nil {
+		return err
+	}
+
+	freeRegisters, found := c.locationStack.takeFreeRegisters(registerTypeGeneralPurpose, 3)
+	if !found {
+		return fmt.Errorf("BUG: all registers except indexReg should be free at this point")
+	}
+	c.markRegisterUsed(freeRegisters...)
+
+	// Alias for readability.
+	callFrameStackPointerRegister, callFrameStackTopAddressRegister, targetFunctionRegister :=
+		freeRegisters[0], freeRegisters[1], freeRegisters[2]
+
+	if isNilRegister(targetFunctionAddressRegister) {
+		// "targetFunctionRegister = ce.functions[index]"
+		c.assembler.CompileMemoryToRegister(arm64.MOVD,
+			arm64ReservedRegisterForCallEngine, callEngineModuleContextFunctionsElement0AddressOffset,
+			targetFunctionRegister)
+		c.assembler.CompileMemoryToRegister(
+			arm64.MOVD,
+			targetFunctionRegister, int64(index)*8, // * 8 because the size of *function equals 8 bytes.
+			targetFunctionRegister)
+	} else {
+		targetFunctionRegister = targetFunctionAddressRegister
+	}
+
+	// "callFrameStackTopAddressRegister = &ce.callFrameStack[ce.callFrameStackPointer]", which is the address right
+	// after the current call frame.
+	c.assembler.CompileMemoryToRegister(arm64.MOVD,
+		arm64ReservedRegisterForCallEngine, callEngineGlobalContextCallFrameStackPointerOffset,
+		callFrameStackPointerRegister)
+	c.compileCalcCallFrameStackTopAddress(callFrameStackPointerRegister, callFrameStackTopAddressRegister)
+
+	// Replace the function of the current call frame so that Go functions called from the target one (builtin or
+	// host functions) see the target function as the top call frame.
+	c.assembler.CompileRegisterToMemory(arm64.MOVD,
+		targetFunctionRegister,
+		// The current call frame is BELOW the top address. See the example in compileCallImpl for detail.
+		callFrameStackTopAddressRegister, -(callFrameDataSize - callFrameFunctionOffset))
+
+	if targetFunctionRegister == arm64CallingConventionModuleInstanceAddressRegister {
+		// This case we must move the value on targetFunctionAddressRegister to another register, otherwise
+		// the address (jump target below) will be modified and result in segfault.
+		// See #526.
+		c.assembler.CompileRegisterToRegister(arm64.MOVD, targetFunctionRegister, callFrameStackPointerRegister)
+		targetFunctionRegister = callFrameStackPointerRegister
+	}
+
+	// Put the code's moduleInstance address into arm64CallingConventionModuleInstanceAddressRegister.
+	c.assembler.CompileMemoryToRegister(arm64.MOVD,
+		targetFunctionRegister, functionModuleInstanceAddressOffset,
+		arm64CallingConventionModuleInstanceAddressRegister,
+	)
+
+	// Then, br into the target function's initial address, which never comes back here.
+	c.assembler.CompileMemoryToRegister(arm64.MOVD,
+		targetFunctionRegister, functionCodeInitialAddressOffset,
+		callFrameStackTopAddressRegister)
+	c.assembler.CompileJumpToMemory(arm64.B, callFrameStackTopAddressRegister)
+
+	// All the registers used are temporary so we mark them unused.
+	c.markRegisterUnused(freeRegisters...)
+
+	// The function arguments were consumed by the target function.
+	for i := 0; i < functype.ParamNumInUint64; i++ {
+		c.locationStack.pop()
+	}
 	return nil
 }
 
@@ -1301,7 +1413,7 @@ func (c *arm64Compiler) compileDropRange(r *wazeroir.InclusiveRange) error {
 }
 
 // compileSelect implements compiler.compileSelect for the arm64 architecture.
-func (c *arm64Compiler) compileSelect() error {
+func (c *arm64Compiler) compileSelect(o *wazeroir.OperationSelect) error {
 	cv, err := c.popValueOnRegister()
 	if err != nil {
 		return err
@@ -1309,6 +1421,10 @@ func (c *arm64Compiler) compileSelect() error {
 
 	c.markRegisterUsed(cv.register)
 
+	if o.IsTargetVector {
+		return c.compileSelectV128Impl(cv.register)
+	}
+
 	x1, x2, err := c.popTwoValuesOnRegisters()
 	if err != nil {
 		return err
@@ -1359,7 +1475,7 @@ func (c *arm64Compiler) compileSelect() error {
 	case runtimeValueTypeF64:
 		c.assembler.CompileRegisterToRegister(arm64.FMOVD, x2.register, x1.register)
 	default:
-		return errors.New("TODO: implement vector type select")
+		panic("BUG") // vectors are handled by compileSelectV128Impl.
 	}
 
 	c.pushRuntimeValueLocationOnRegister(x1.register, x1.valueType)
@@ -1372,6 +1488,34 @@ func (c *arm64Compiler) compileSelect() error {
 	return nil
 }
 
+// compileSelectV128Impl emits the instructions to select one of the two vectors on the stack top, depending on the
+// conditional value on the register cvReg.
+func (c *arm64Compiler) compileSelectV128Impl(cvReg asm.Register) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileTwoRegistersToNone(arm64.CMPW, arm64.RegRZR, cvReg)
+	brIfNotZero := c.assembler.CompileJump(arm64.BNE)
+
+	// If cv == 0, we move the value of x2 to the x1.register.
+	c.assembler.CompileVectorRegisterToVectorRegister(arm64.VMOV, x2.register, x1.register,
+		arm64.VectorArrangement16B, arm64.VectorIndexNone, arm64.VectorIndexNone)
+
+	// Otherwise, nothing to do for select.
+	c.assembler.SetJumpTargetOnNext(brIfNotZero)
+
+	c.markRegisterUnused(cvReg, x2.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
 // compilePick implements compiler.compilePick for the arm64 architecture.
 func (c *arm64Compiler) compilePick(o *wazeroir.OperationPick) error {
 	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
@@ -1395,7 +1539,7 @@ func (c *arm64Compiler) compilePick(o *wazeroir.OperationPick) error {
 			c.assembler.CompileRegisterToRegister(arm64.FMOVD, pickTarget.register, pickedRegister)
 		case runtimeValueTypeV128Lo:
 			c.assembler.CompileVectorRegisterToVectorRegister(arm64.VMOV,
-				pickTarget.register, pickedRegister, arm64.VectorArrangement16B)
+				pickTarget.register, pickedRegister, arm64.VectorArrangement16B, arm64.VectorIndexNone, arm64.VectorIndexNone)
 		case runtimeValueTypeV128Hi:
 			panic("BUG") // since pick target must point to the lower 64-bits of vectors.
 		}
@@ -2823,14 +2967,17 @@ func (c *arm64Compiler) compileMemoryAccessOffsetSetup(offsetArg uint32, targetS
 	}
 
 	if offsetConst := int64(offsetArg) + targetSizeInBytes; offsetConst <= math.MaxUint32 {
-		// "offsetRegister = base + offsetArg + targetSizeInBytes"
-		c.assembler.CompileConstToRegister(arm64.ADD, offsetConst, offsetRegister)
+		// "offsetRegister = base + offsetArg + targetSizeInBytes" with the carry flag set on overflow.
+		c.assembler.CompileConstToRegister(arm64.ADDS, offsetConst, offsetRegister)
 	} else {
 		// If the offset const is too large, we exit with nativeCallStatusCodeMemoryOutOfBounds.
 		c.compileExitFromNativeCode(nativeCallStatusCodeMemoryOutOfBounds)
 		return
 	}
 
+	// The base is 64-bit for a memory indexed with i64, so jump to the exit if the addition above overflowed.
+	overflow := c.assembler.CompileJump(arm64.BHS)
+
 	// "arm64ReservedRegisterForTemporary = len(memory.Buffer)"
 	c.assembler.CompileMemoryToRegister(arm64.MOVD,
 		arm64ReservedRegisterForCallEngine, callEngineModuleContextMemorySliceLenOffset,
@@ -2842,6 +2989,7 @@ func (c *arm64Compiler) compileMemoryAccessOffsetSetup(offsetArg uint32, targetS
 
 	// If offsetRegister(= base+offsetArg+targetSizeInBytes) exceeds the memory length,
 	//  we exit the function with nativeCallStatusCodeMemoryOutOfBounds.
+	c.assembler.SetJumpTargetOnNext(overflow)
 	c.compileExitFromNativeCode(nativeCallStatusCodeMemoryOutOfBounds)
 
 	// Otherwise, we subtract targetSizeInBytes from offsetRegister.
@@ -2864,6 +3012,205 @@ func (c *arm64Compiler) compileMemoryGrow() error {
 	return nil
 }
 
+// compileAtomicLoad implements compiler.compileAtomicLoad for the arm64 architecture.
+func (c *arm64Compiler) compileAtomicLoad(o *wazeroir.OperationAtomicLoad) error {
+	if err := c.compileCallAtomicBuiltinFunction(builtinFunctionIndexAtomicLoad,
+		atomicImmediate(o.Arg, o.SizeInBytes, 0), 1); err != nil {
+		return err
+	}
+	c.locationStack.pushRuntimeValueLocationOnStack().valueType = atomicResultType(o.Type)
+	return nil
+}
+
+// compileAtomicStore implements compiler.compileAtomicStore for the arm64 architecture.
+func (c *arm64Compiler) compileAtomicStore(o *wazeroir.OperationAtomicStore) error {
+	return c.compileCallAtomicBuiltinFunction(builtinFunctionIndexAtomicStore,
+		atomicImmediate(o.Arg, o.SizeInBytes, 0), 2)
+}
+
+// compileAtomicRMW implements compiler.compileAtomicRMW for the arm64 architecture.
+func (c *arm64Compiler) compileAtomicRMW(o *wazeroir.OperationAtomicRMW) error {
+	if err := c.compileCallAtomicBuiltinFunction(builtinFunctionIndexAtomicRMW,
+		atomicImmediate(o.Arg, o.SizeInBytes, o.Op), 2); err != nil {
+		return err
+	}
+	c.locationStack.pushRuntimeValueLocationOnStack().valueType = atomicResultType(o.Type)
+	return nil
+}
+
+// compileAtomicRMWCmpxchg implements compiler.compileAtomicRMWCmpxchg for the arm64 architecture.
+func (c *arm64Compiler) compileAtomicRMWCmpxchg(o *wazeroir.OperationAtomicRMWCmpxchg) error {
+	if err := c.compileCallAtomicBuiltinFunction(builtinFunctionIndexAtomicRMWCmpxchg,
+		atomicImmediate(o.Arg, o.SizeInBytes, 0), 3); err != nil {
+		return err
+	}
+	c.locationStack.pushRuntimeValueLocationOnStack().valueType = atomicResultType(o.Type)
+	return nil
+}
+
+// compileAtomicMemoryWait implements compiler.compileAtomicMemoryWait for the arm64 architecture.
+func (c *arm64Compiler) compileAtomicMemoryWait(o *wazeroir.OperationAtomicMemoryWait) error {
+	sizeInBytes := uint32(4)
+	if o.Type == wazeroir.UnsignedInt64 {
+		sizeInBytes = 8
+	}
+	if err := c.compileCallAtomicBuiltinFunction(builtinFunctionIndexAtomicMemoryWait,
+		atomicImmediate(o.Arg, sizeInBytes, 0), 3); err != nil {
+		return err
+	}
+	c.locationStack.pushRuntimeValueLocationOnStack().valueType = runtimeValueTypeI32
+	return nil
+}
+
+// compileAtomicMemoryNotify implements compiler.compileAtomicMemoryNotify for the arm64 architecture.
+func (c *arm64Compiler) compileAtomicMemoryNotify(o *wazeroir.OperationAtomicMemoryNotify) error {
+	if err := c.compileCallAtomicBuiltinFunction(builtinFunctionIndexAtomicMemoryNotify,
+		atomicImmediate(o.Arg, 4, 0), 2); err != nil {
+		return err
+	}
+	c.locationStack.pushRuntimeValueLocationOnStack().valueType = runtimeValueTypeI32
+	return nil
+}
+
+// compileAtomicFence implements compiler.compileAtomicFence for the arm64 architecture.
+//
+// This emits nothing as every atomic instruction is implemented by a builtin function whose accesses are
+// sequentially consistent.
+func (c *arm64Compiler) compileAtomicFence(*wazeroir.OperationAtomicFence) error {
+	return nil
+}
+
+// compileCallAtomicBuiltinFunction calls the atomic builtin function of the given index with the immediate packed
+// by atomicImmediate. operandNum is the number of the operands of the instruction, which are consumed by the builtin
+// function together with the immediate. The caller is responsible for pushing the result, if any.
+func (c *arm64Compiler) compileCallAtomicBuiltinFunction(index wasm.Index, imm uint64, operandNum int) error {
+	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
+
+	// Pushes the immediate.
+	if err := c.compileConstI64(&wazeroir.OperationConstI64{Value: imm}); err != nil {
+		return err
+	}
+
+	// Atomic instructions are implemented in Go, so that both engines share the same implementation including
+	// wait and notify, which have to block the goroutine.
+	if err := c.compileCallGoFunction(nativeCallStatusCodeCallBuiltInFunction, index); err != nil {
+		return err
+	}
+
+	for i := 0; i < operandNum+1; i++ {
+		c.locationStack.pop()
+	}
+
+	// After return, we re-initialize reserved registers just like preamble of functions.
+	c.compileReservedStackBasePointerRegisterInitialization()
+	c.compileReservedMemoryRegisterInitialization()
+	return nil
+}
+
+// compileTry implements compiler.compileTry for the arm64 architecture.
+func (c *arm64Compiler) compileTry(o *wazeroir.OperationTry) error {
+	// We use NOP as the beginning of the protected region.
+	c.tryBlocks.begin(o, c.assembler.CompileStandAlone(arm64.NOP), c.locationStack)
+	return nil
+}
+
+// compileCatch implements compiler.compileCatch for the arm64 architecture.
+func (c *arm64Compiler) compileCatch(o *wazeroir.OperationCatch) (skipThisHandler bool, err error) {
+	tb := c.tryBlocks.compiled(o.FrameID)
+	if tb == nil { // The try block is never reached, and neither is this handler.
+		return true, nil
+	}
+
+	// We use NOP as the beginning of the handler, which the engine jumps into when catching an exception.
+	c.tryBlocks.addCatch(o, c.assembler.CompileStandAlone(arm64.NOP))
+
+	// The engine places all the values on the stack, and the handler might be in a different module from the one
+	// where the exception is raised. So we initialize the location stack and the reserved registers as in the
+	// preamble of functions.
+	c.setLocationStack(newRuntimeValueLocationStack())
+	tb.pushHandlerValues(c.locationStack, o, c.ir.Tags)
+	if err = c.compileModuleContextInitialization(); err != nil {
+		return
+	}
+	c.compileReservedStackBasePointerRegisterInitialization()
+	c.compileReservedMemoryRegisterInitialization()
+	return
+}
+
+// compileDelegate implements compiler.compileDelegate for the arm64 architecture.
+func (c *arm64Compiler) compileDelegate(o *wazeroir.OperationDelegate) error {
+	if c.tryBlocks.compiled(o.FrameID) != nil {
+		// We use NOP as the end of the protected region.
+		c.tryBlocks.delegate(o, c.assembler.CompileStandAlone(arm64.NOP))
+	}
+	return nil
+}
+
+// compileThrow implements compiler.compileThrow for the arm64 architecture.
+func (c *arm64Compiler) compileThrow(o *wazeroir.OperationThrow) error {
+	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
+
+	// Pushes the tag index, so that the engine can pop the values of the tag.
+	if err := c.compileConstI32(&wazeroir.OperationConstI32{Value: o.TagIndex}); err != nil {
+		return err
+	}
+
+	if err := c.compileCallGoFunction(nativeCallStatusCodeCallBuiltInFunction, builtinFunctionIndexThrow); err != nil {
+		return err
+	}
+	// The engine never returns here, but jumps into the handler or escapes the call with the exception.
+	return c.compileUnreachable()
+}
+
+// compileRethrow implements compiler.compileRethrow for the arm64 architecture.
+func (c *arm64Compiler) compileRethrow() error {
+	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
+
+	// Like compileThrow, the engine pops the handle of the exception and never returns here.
+	if err := c.compileCallGoFunction(nativeCallStatusCodeCallBuiltInFunction, builtinFunctionIndexRethrow); err != nil {
+		return err
+	}
+	return c.compileUnreachable()
+}
+
+// compileConsumeFuel implements compiler.compileConsumeFuel for the arm64 architecture.
+func (c *arm64Compiler) compileConsumeFuel(o *wazeroir.OperationConsumeFuel) error {
+	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
+
+	fuelAddress, err := c.allocateRegister(registerTypeGeneralPurpose)
+	if err != nil {
+		return err
+	}
+	c.markRegisterUsed(fuelAddress)
+
+	fuel, err := c.allocateRegister(registerTypeGeneralPurpose)
+	if err != nil {
+		return err
+	}
+	c.markRegisterUsed(fuel)
+
+	c.assembler.CompileMemoryToRegister(arm64.MOVD, arm64ReservedRegisterForCallEngine, arm64CallEngineFuelContextFuelAddressOffset, fuelAddress)
+	c.assembler.CompileMemoryToRegister(arm64.MOVD, fuelAddress, 0, fuel)
+	c.assembler.CompileConstToRegister(arm64.SUBS, int64(o.Cost), fuel)
+
+	// Jump if the remaining fuel is still non-negative.
+	okJmp := c.assembler.CompileJump(arm64.BGE)
+
+	// Otherwise, we exit without writing back the fuel, so that it never becomes negative.
+	c.compileExitFromNativeCode(nativeCallStatusCodeFuelExhausted)
+
+	c.assembler.SetJumpTargetOnNext(okJmp)
+	c.assembler.CompileRegisterToMemory(arm64.MOVD, fuel, fuelAddress, 0)
+
+	c.markRegisterUnused(fuelAddress, fuel)
+	return nil
+}
+
+// resolveTryBlocks implements compiler.resolveTryBlocks for the arm64 architecture.
+func (c *arm64Compiler) resolveTryBlocks() []*tryBlock {
+	return c.tryBlocks.resolve()
+}
+
 // compileMemorySize implements compileMemorySize variants for arm64 architecture.
 func (c *arm64Compiler) compileMemorySize() error {
 	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
@@ -3536,6 +3883,92 @@ func (c *arm64Compiler) compileMemoryFill() error {
 	return c.compileFillImpl(false, 0)
 }
 
+// compileReloadMemoryLength implements compiler.compileReloadMemoryLength for the arm64 architecture.
+func (c *arm64Compiler) compileReloadMemoryLength() error {
+	tmp, err := c.allocateRegister(registerTypeGeneralPurpose)
+	if err != nil {
+		return err
+	}
+
+	// "tmp = len(ModuleInstance.Memory.Buffer)" of the module instance in the module context.
+	c.assembler.CompileMemoryToRegister(arm64.MOVD,
+		arm64ReservedRegisterForCallEngine, callEngineModuleContextModuleInstanceAddressOffset, tmp)
+	c.assembler.CompileMemoryToRegister(arm64.MOVD, tmp, moduleInstanceMemoryOffset, tmp)
+	c.assembler.CompileMemoryToRegister(arm64.MOVD, tmp, memoryInstanceBufferLenOffset, tmp)
+
+	// The buffer of a shared memory never moves, so only the length needs to be updated.
+	c.assembler.CompileRegisterToMemory(arm64.MOVD,
+		tmp, arm64ReservedRegisterForCallEngine, callEngineModuleContextMemorySliceLenOffset)
+	return nil
+}
+
+// compileSelectMemory implements compiler.compileSelectMemory for the arm64 architecture.
+func (c *arm64Compiler) compileSelectMemory(index uint32) error {
+	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
+
+	// Pushes the memory index.
+	if err := c.compileConstI32(&wazeroir.OperationConstI32{Value: index}); err != nil {
+		return err
+	}
+
+	// The buffer of the memory is only reachable from Go, so call out to the builtin function to update the module
+	// context with the memory.
+	if err := c.compileCallGoFunction(nativeCallStatusCodeCallBuiltInFunction, builtinFunctionIndexSelectMemory); err != nil {
+		return err
+	}
+
+	// SelectMemory consumes the memory index.
+	c.locationStack.pop()
+
+	// After return, we re-initialize reserved registers just like preamble of functions.
+	c.compileReservedStackBasePointerRegisterInitialization()
+	c.compileReservedMemoryRegisterInitialization()
+	return nil
+}
+
+// compileBuiltinMemoryCopy implements compiler.compileBuiltinMemoryCopy for the arm64 architecture.
+func (c *arm64Compiler) compileBuiltinMemoryCopy(o *wazeroir.OperationMemoryCopy) error {
+	// Consumes the memory indexes, size, source and destination offsets.
+	return c.compileCallBuiltinMemoryFunction(builtinFunctionIndexMemoryCopy, 3, o.SrcMemoryIndex, o.DstMemoryIndex)
+}
+
+// compileBuiltinMemoryFill implements compiler.compileBuiltinMemoryFill for the arm64 architecture.
+func (c *arm64Compiler) compileBuiltinMemoryFill() error {
+	// Consumes the size, value and offset.
+	return c.compileCallBuiltinMemoryFunction(builtinFunctionIndexMemoryFill, 3)
+}
+
+// compileBuiltinMemoryInit implements compiler.compileBuiltinMemoryInit for the arm64 architecture.
+func (c *arm64Compiler) compileBuiltinMemoryInit(o *wazeroir.OperationMemoryInit) error {
+	// Consumes the data index, size, offsets in the data and memory.
+	return c.compileCallBuiltinMemoryFunction(builtinFunctionIndexMemoryInit, 3, o.DataIndex)
+}
+
+// compileCallBuiltinMemoryFunction pushes the immediates onto the stack, and calls the builtin function of the given
+// index, which consumes them as well as the given number of operands below.
+func (c *arm64Compiler) compileCallBuiltinMemoryFunction(index wasm.Index, operands int, immediates ...uint32) error {
+	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
+
+	for _, imm := range immediates {
+		if err := c.compileConstI32(&wazeroir.OperationConstI32{Value: imm}); err != nil {
+			return err
+		}
+	}
+
+	if err := c.compileCallGoFunction(nativeCallStatusCodeCallBuiltInFunction, index); err != nil {
+		return err
+	}
+
+	for i := 0; i < operands+len(immediates); i++ {
+		c.locationStack.pop()
+	}
+
+	// After return, we re-initialize reserved registers just like preamble of functions.
+	c.compileReservedStackBasePointerRegisterInitialization()
+	c.compileReservedMemoryRegisterInitialization()
+	return nil
+}
+
 // compileFillImpl implements TableFill and MemoryFill.
 //
 // TODO: the compiled code in this function should be reused and compile at once as
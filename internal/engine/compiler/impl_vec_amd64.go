@@ -1,13 +1,15 @@
 package compiler
 
 import (
+	"unsafe"
+
 	"github.com/tetratelabs/wazero/internal/asm"
 	"github.com/tetratelabs/wazero/internal/asm/amd64"
 	"github.com/tetratelabs/wazero/internal/wazeroir"
 )
 
-// compileConstV128 implements compiler.compileConstV128 for amd64 architecture.
-func (c *amd64Compiler) compileConstV128(o *wazeroir.OperationConstV128) error {
+// compileV128Const implements compiler.compileV128Const for amd64 architecture.
+func (c *amd64Compiler) compileV128Const(o *wazeroir.OperationV128Const) error {
 	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
 
 	result, err := c.allocateRegister(registerTypeVector)
@@ -42,8 +44,8 @@ func (c *amd64Compiler) compileConstV128(o *wazeroir.OperationConstV128) error {
 	return nil
 }
 
-// compileAddV128 implements compiler.compileAddV128 for amd64 architecture.
-func (c *amd64Compiler) compileAddV128(o *wazeroir.OperationAddV128) error {
+// compileV128Add implements compiler.compileV128Add for amd64 architecture.
+func (c *amd64Compiler) compileV128Add(o *wazeroir.OperationV128Add) error {
 	c.locationStack.pop() // skip higher 64-bits.
 	x2 := c.locationStack.pop()
 	if err := c.compileEnsureOnGeneralPurposeRegister(x2); err != nil {
@@ -76,3 +78,2206 @@ func (c *amd64Compiler) compileAddV128(o *wazeroir.OperationAddV128) error {
 	c.locationStack.markRegisterUnused(x2.register)
 	return nil
 }
+
+// compileV128Sub implements compiler.compileV128Sub for amd64 architecture.
+func (c *amd64Compiler) compileV128Sub(o *wazeroir.OperationV128Sub) error {
+	var inst asm.Instruction
+	switch o.Shape {
+	case wazeroir.ShapeI8x16:
+		inst = amd64.PSUBB
+	case wazeroir.ShapeI16x8:
+		inst = amd64.PSUBW
+	case wazeroir.ShapeI32x4:
+		inst = amd64.PSUBL
+	case wazeroir.ShapeI64x2:
+		inst = amd64.PSUBQ
+	case wazeroir.ShapeF32x4:
+		inst = amd64.SUBPS
+	case wazeroir.ShapeF64x2:
+		inst = amd64.SUBPD
+	}
+	return c.compileV128x2BinOp(inst)
+}
+
+// compileV128x2BinOp performs `inst` on the two vectors on top of the stack, where the result is
+// placed on the register of the first operand.
+func (c *amd64Compiler) compileV128x2BinOp(inst asm.Instruction) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileRegisterToRegister(inst, x2.register, x1.register)
+
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	c.locationStack.markRegisterUnused(x2.register)
+	return nil
+}
+
+// popV128 pops a V128 value from the stack and ensures that it is placed on a vector register.
+func (c *amd64Compiler) popV128() (*runtimeValueLocation, error) {
+	c.locationStack.pop() // skip higher 64-bits.
+	v := c.locationStack.pop()
+	if err := c.compileEnsureOnGeneralPurposeRegister(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// compileLoadStaticV128ToRegister loads the 16-bytes `data` onto the vector register `dst`.
+// The data is kept alive as a static data of the function.
+func (c *amd64Compiler) compileLoadStaticV128ToRegister(data []byte, dst asm.Register) error {
+	c.addStaticData(data)
+
+	// Note: we cannot assume the address of data fits in 32-bit displacement, so we
+	// place the address on a general purpose register first.
+	tmp, err := c.allocateRegister(registerTypeGeneralPurpose)
+	if err != nil {
+		return err
+	}
+	c.assembler.CompileConstToRegister(amd64.MOVQ, int64(uintptr(unsafe.Pointer(&data[0]))), tmp)
+	c.assembler.CompileMemoryToRegister(amd64.MOVDQU, tmp, 0, dst)
+	return nil
+}
+
+// compileV128Load implements compiler.compileV128Load for amd64 architecture.
+func (c *amd64Compiler) compileV128Load(o *wazeroir.OperationV128Load) error {
+	var result asm.Register
+	switch o.Type {
+	case wazeroir.V128LoadType128:
+		r, err := c.compileV128LoadImpl(amd64.MOVDQU, o.Arg.Offset, 16)
+		if err != nil {
+			return err
+		}
+		result = r
+	case wazeroir.V128LoadType8x8s:
+		r, err := c.compileV128LoadImpl(amd64.MOVQ, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegister(amd64.PMOVSXBW, r, r)
+		result = r
+	case wazeroir.V128LoadType8x8u:
+		r, err := c.compileV128LoadImpl(amd64.MOVQ, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegister(amd64.PMOVZXBW, r, r)
+		result = r
+	case wazeroir.V128LoadType16x4s:
+		r, err := c.compileV128LoadImpl(amd64.MOVQ, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegister(amd64.PMOVSXWD, r, r)
+		result = r
+	case wazeroir.V128LoadType16x4u:
+		r, err := c.compileV128LoadImpl(amd64.MOVQ, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegister(amd64.PMOVZXWD, r, r)
+		result = r
+	case wazeroir.V128LoadType32x2s:
+		r, err := c.compileV128LoadImpl(amd64.MOVQ, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegister(amd64.PMOVSXDQ, r, r)
+		result = r
+	case wazeroir.V128LoadType32x2u:
+		r, err := c.compileV128LoadImpl(amd64.MOVQ, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegister(amd64.PMOVZXDQ, r, r)
+		result = r
+	case wazeroir.V128LoadType8Splat:
+		reg, err := c.compileMemoryAccessCeilSetup(o.Arg.Offset, 1)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileMemoryWithIndexToRegister(amd64.MOVBQZX, amd64ReservedRegisterForMemory, -1,
+			reg, 1, reg)
+		result, err = c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		c.locationStack.markRegisterUsed(result)
+		tmp, err := c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PINSRB, reg, result, 0)
+		// Shuffle the first byte into all lanes with all-zero indexes.
+		c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, tmp)
+		c.assembler.CompileRegisterToRegister(amd64.PSHUFB, tmp, result)
+	case wazeroir.V128LoadType16Splat:
+		reg, err := c.compileMemoryAccessCeilSetup(o.Arg.Offset, 2)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileMemoryWithIndexToRegister(amd64.MOVWQZX, amd64ReservedRegisterForMemory, -2,
+			reg, 1, reg)
+		result, err = c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		// Place the value on the first two 16-bit lanes, and then broadcast the first 32-bit lane.
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PINSRW, reg, result, 0)
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PINSRW, reg, result, 1)
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PSHUFD, result, result, 0)
+	case wazeroir.V128LoadType32Splat:
+		r, err := c.compileV128LoadImpl(amd64.MOVL, o.Arg.Offset, 4)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PSHUFD, r, r, 0)
+		result = r
+	case wazeroir.V128LoadType64Splat:
+		r, err := c.compileV128LoadImpl(amd64.MOVQ, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		// 0x44 = [0, 1, 0, 1] in terms of 32-bit lanes.
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PSHUFD, r, r, 0x44)
+		result = r
+	case wazeroir.V128LoadType32zero:
+		r, err := c.compileV128LoadImpl(amd64.MOVL, o.Arg.Offset, 4)
+		if err != nil {
+			return err
+		}
+		result = r
+	case wazeroir.V128LoadType64zero:
+		r, err := c.compileV128LoadImpl(amd64.MOVQ, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		result = r
+	}
+
+	c.pushVectorRuntimeValueLocationOnRegister(result)
+	return nil
+}
+
+// compileV128LoadImpl loads `targetSizeInBytes` bytes from the memory onto a vector register with `inst`.
+func (c *amd64Compiler) compileV128LoadImpl(inst asm.Instruction, offset uint32, targetSizeInBytes int64) (asm.Register, error) {
+	offsetReg, err := c.compileMemoryAccessCeilSetup(offset, targetSizeInBytes)
+	if err != nil {
+		return asm.NilRegister, err
+	}
+
+	vectorReg, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return asm.NilRegister, err
+	}
+
+	c.assembler.CompileMemoryWithIndexToRegister(inst, amd64ReservedRegisterForMemory, -targetSizeInBytes,
+		offsetReg, 1, vectorReg)
+	return vectorReg, nil
+}
+
+// compileV128LoadLane implements compiler.compileV128LoadLane for amd64.
+func (c *amd64Compiler) compileV128LoadLane(o *wazeroir.OperationV128LoadLane) error {
+	targetVector, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	var insertInst, loadInst asm.Instruction
+	switch o.LaneSize {
+	case 8:
+		loadInst, insertInst = amd64.MOVBQZX, amd64.PINSRB
+	case 16:
+		loadInst, insertInst = amd64.MOVWQZX, amd64.PINSRW
+	case 32:
+		loadInst, insertInst = amd64.MOVL, amd64.PINSRD
+	case 64:
+		loadInst, insertInst = amd64.MOVQ, amd64.PINSRQ
+	}
+
+	targetSizeInBytes := int64(o.LaneSize / 8)
+	offsetReg, err := c.compileMemoryAccessCeilSetup(o.Arg.Offset, targetSizeInBytes)
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileMemoryWithIndexToRegister(loadInst, amd64ReservedRegisterForMemory, -targetSizeInBytes,
+		offsetReg, 1, offsetReg)
+	c.assembler.CompileRegisterToRegisterWithArg(insertInst, offsetReg, targetVector.register, o.LaneIndex)
+
+	c.pushVectorRuntimeValueLocationOnRegister(targetVector.register)
+	return nil
+}
+
+// compileV128Store implements compiler.compileV128Store for amd64.
+func (c *amd64Compiler) compileV128Store(o *wazeroir.OperationV128Store) error {
+	val, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	const targetSizeInBytes = 16
+	offsetReg, err := c.compileMemoryAccessCeilSetup(o.Arg.Offset, targetSizeInBytes)
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileRegisterToMemoryWithIndex(amd64.MOVDQU, val.register,
+		amd64ReservedRegisterForMemory, -targetSizeInBytes, offsetReg, 1)
+
+	c.locationStack.markRegisterUnused(val.register, offsetReg)
+	return nil
+}
+
+// compileV128StoreLane implements compiler.compileV128StoreLane for amd64.
+func (c *amd64Compiler) compileV128StoreLane(o *wazeroir.OperationV128StoreLane) error {
+	val, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	var extractInst, storeInst asm.Instruction
+	switch o.LaneSize {
+	case 8:
+		extractInst, storeInst = amd64.PEXTRB, amd64.MOVB
+	case 16:
+		extractInst, storeInst = amd64.PEXTRW, amd64.MOVW
+	case 32:
+		extractInst, storeInst = amd64.PEXTRD, amd64.MOVL
+	case 64:
+		extractInst, storeInst = amd64.PEXTRQ, amd64.MOVQ
+	}
+
+	tmp, err := c.allocateRegister(registerTypeGeneralPurpose)
+	if err != nil {
+		return err
+	}
+	c.locationStack.markRegisterUsed(tmp)
+	c.assembler.CompileRegisterToRegisterWithArg(extractInst, val.register, tmp, o.LaneIndex)
+
+	targetSizeInBytes := int64(o.LaneSize / 8)
+	offsetReg, err := c.compileMemoryAccessCeilSetup(o.Arg.Offset, targetSizeInBytes)
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileRegisterToMemoryWithIndex(storeInst, tmp,
+		amd64ReservedRegisterForMemory, -targetSizeInBytes, offsetReg, 1)
+
+	c.locationStack.markRegisterUnused(val.register, tmp, offsetReg)
+	return nil
+}
+
+// compileV128ExtractLane implements compiler.compileV128ExtractLane for amd64.
+func (c *amd64Compiler) compileV128ExtractLane(o *wazeroir.OperationV128ExtractLane) error {
+	val, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	switch o.Shape {
+	case wazeroir.ShapeI8x16, wazeroir.ShapeI16x8, wazeroir.ShapeI32x4, wazeroir.ShapeI64x2:
+		result, err := c.allocateRegister(registerTypeGeneralPurpose)
+		if err != nil {
+			return err
+		}
+
+		vt := runtimeValueTypeI32
+		switch o.Shape {
+		case wazeroir.ShapeI8x16:
+			c.assembler.CompileRegisterToRegisterWithArg(amd64.PEXTRB, val.register, result, o.LaneIndex)
+			if o.Signed {
+				c.assembler.CompileRegisterToRegister(amd64.MOVBLSX, result, result)
+			}
+		case wazeroir.ShapeI16x8:
+			c.assembler.CompileRegisterToRegisterWithArg(amd64.PEXTRW, val.register, result, o.LaneIndex)
+			if o.Signed {
+				c.assembler.CompileRegisterToRegister(amd64.MOVWLSX, result, result)
+			}
+		case wazeroir.ShapeI32x4:
+			c.assembler.CompileRegisterToRegisterWithArg(amd64.PEXTRD, val.register, result, o.LaneIndex)
+		case wazeroir.ShapeI64x2:
+			c.assembler.CompileRegisterToRegisterWithArg(amd64.PEXTRQ, val.register, result, o.LaneIndex)
+			vt = runtimeValueTypeI64
+		}
+		c.locationStack.markRegisterUnused(val.register)
+		c.pushRuntimeValueLocationOnRegister(result, vt)
+	case wazeroir.ShapeF32x4:
+		// Move the lane into the first one, and zero-out the others.
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.INSERTPS, val.register, val.register,
+			o.LaneIndex<<6|0b1110)
+		c.pushRuntimeValueLocationOnRegister(val.register, runtimeValueTypeF32)
+	case wazeroir.ShapeF64x2:
+		if o.LaneIndex == 0 {
+			// Zero-out the higher 64-bits.
+			c.assembler.CompileRegisterToRegister(amd64.MOVQ, val.register, val.register)
+		} else {
+			// Shift the higher 64-bits into the lower ones.
+			c.assembler.CompileConstToRegister(amd64.PSRLDQ, 8, val.register)
+		}
+		c.pushRuntimeValueLocationOnRegister(val.register, runtimeValueTypeF64)
+	}
+	return nil
+}
+
+// compileV128ReplaceLane implements compiler.compileV128ReplaceLane for amd64.
+func (c *amd64Compiler) compileV128ReplaceLane(o *wazeroir.OperationV128ReplaceLane) error {
+	origin := c.locationStack.pop()
+	if err := c.compileEnsureOnGeneralPurposeRegister(origin); err != nil {
+		return err
+	}
+
+	vector, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	switch o.Shape {
+	case wazeroir.ShapeI8x16:
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PINSRB, origin.register, vector.register, o.LaneIndex)
+	case wazeroir.ShapeI16x8:
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PINSRW, origin.register, vector.register, o.LaneIndex)
+	case wazeroir.ShapeI32x4:
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PINSRD, origin.register, vector.register, o.LaneIndex)
+	case wazeroir.ShapeI64x2:
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PINSRQ, origin.register, vector.register, o.LaneIndex)
+	case wazeroir.ShapeF32x4:
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.INSERTPS, origin.register, vector.register, o.LaneIndex<<4)
+	case wazeroir.ShapeF64x2:
+		if o.LaneIndex == 0 {
+			c.assembler.CompileRegisterToRegister(amd64.MOVSD, origin.register, vector.register)
+		} else {
+			c.assembler.CompileRegisterToRegister(amd64.MOVLHPS, origin.register, vector.register)
+		}
+	}
+
+	c.pushVectorRuntimeValueLocationOnRegister(vector.register)
+	c.locationStack.markRegisterUnused(origin.register)
+	return nil
+}
+
+// compileV128Splat implements compiler.compileV128Splat for amd64.
+func (c *amd64Compiler) compileV128Splat(o *wazeroir.OperationV128Splat) (err error) {
+	origin := c.locationStack.pop()
+	if err = c.compileEnsureOnGeneralPurposeRegister(origin); err != nil {
+		return
+	}
+
+	var result asm.Register
+	switch o.Shape {
+	case wazeroir.ShapeI8x16:
+		result, err = c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		c.locationStack.markRegisterUsed(result)
+
+		tmp, err := c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PINSRB, origin.register, result, 0)
+		c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, tmp)
+		c.assembler.CompileRegisterToRegister(amd64.PSHUFB, tmp, result)
+	case wazeroir.ShapeI16x8:
+		result, err = c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PINSRW, origin.register, result, 0)
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PINSRW, origin.register, result, 1)
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PSHUFD, result, result, 0)
+	case wazeroir.ShapeI32x4:
+		result, err = c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PINSRD, origin.register, result, 0)
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PSHUFD, result, result, 0)
+	case wazeroir.ShapeI64x2:
+		result, err = c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PINSRQ, origin.register, result, 0)
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PINSRQ, origin.register, result, 1)
+	case wazeroir.ShapeF32x4:
+		result = origin.register
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PSHUFD, result, result, 0)
+	case wazeroir.ShapeF64x2:
+		result = origin.register
+		// 0x44 = [0, 1, 0, 1] in terms of 32-bit lanes.
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PSHUFD, result, result, 0x44)
+	}
+
+	c.locationStack.markRegisterUnused(origin.register)
+	c.pushVectorRuntimeValueLocationOnRegister(result)
+	return nil
+}
+
+// compileV128Shuffle implements compiler.compileV128Shuffle for amd64.
+func (c *amd64Compiler) compileV128Shuffle(o *wazeroir.OperationV128Shuffle) error {
+	w, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+	c.locationStack.markRegisterUsed(tmp)
+
+	// PSHUFB zeros out the lanes whose index has the highest bit set, so we select
+	// lanes from v and w separately, and then take the bitwise OR of them.
+	consts := [32]byte{}
+	for i, lane := range o.Lanes {
+		if lane < 16 {
+			consts[i+16] = 0x80
+			consts[i] = lane
+		} else {
+			consts[i+16] = lane - 16
+			consts[i] = 0x80
+		}
+	}
+
+	if err = c.compileLoadStaticV128ToRegister(consts[:16], tmp); err != nil {
+		return err
+	}
+	c.assembler.CompileRegisterToRegister(amd64.PSHUFB, tmp, v.register)
+	if err = c.compileLoadStaticV128ToRegister(consts[16:], tmp); err != nil {
+		return err
+	}
+	c.assembler.CompileRegisterToRegister(amd64.PSHUFB, tmp, w.register)
+	c.assembler.CompileRegisterToRegister(amd64.POR, w.register, v.register)
+
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	c.locationStack.markRegisterUnused(tmp, w.register)
+	return nil
+}
+
+// compileV128Swizzle implements compiler.compileV128Swizzle for amd64.
+func (c *amd64Compiler) compileV128Swizzle(*wazeroir.OperationV128Swizzle) error {
+	indexVec, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	baseVec, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	// Add 0x70 with saturation so that the indexes out of range have the highest bit set,
+	// which results in zero with PSHUFB.
+	if err = c.compileLoadStaticV128ToRegister(swizzleConst[:], tmp); err != nil {
+		return err
+	}
+	c.assembler.CompileRegisterToRegister(amd64.PADDUSB, tmp, indexVec.register)
+	c.assembler.CompileRegisterToRegister(amd64.PSHUFB, indexVec.register, baseVec.register)
+
+	c.pushVectorRuntimeValueLocationOnRegister(baseVec.register)
+	c.locationStack.markRegisterUnused(indexVec.register)
+	return nil
+}
+
+// compileV128AnyTrue implements compiler.compileV128AnyTrue for amd64.
+func (c *amd64Compiler) compileV128AnyTrue(*wazeroir.OperationV128AnyTrue) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileRegisterToRegister(amd64.PTEST, v.register, v.register)
+
+	c.locationStack.pushRuntimeValueLocationOnConditionalRegister(amd64.ConditionalRegisterStateNE)
+	c.locationStack.markRegisterUnused(v.register)
+	return nil
+}
+
+// compileV128AllTrue implements compiler.compileV128AllTrue for amd64.
+func (c *amd64Compiler) compileV128AllTrue(o *wazeroir.OperationV128AllTrue) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	var cmpInst asm.Instruction
+	switch o.Shape {
+	case wazeroir.ShapeI8x16:
+		cmpInst = amd64.PCMPEQB
+	case wazeroir.ShapeI16x8:
+		cmpInst = amd64.PCMPEQW
+	case wazeroir.ShapeI32x4:
+		cmpInst = amd64.PCMPEQD
+	case wazeroir.ShapeI64x2:
+		cmpInst = amd64.PCMPEQQ
+	}
+
+	// tmp has all bits set on the lanes which are zero in v.
+	c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, tmp)
+	c.assembler.CompileRegisterToRegister(cmpInst, v.register, tmp)
+	c.assembler.CompileRegisterToRegister(amd64.PTEST, tmp, tmp)
+
+	c.locationStack.pushRuntimeValueLocationOnConditionalRegister(amd64.ConditionalRegisterStateE)
+	c.locationStack.markRegisterUnused(v.register)
+	return nil
+}
+
+// compileV128BitMask implements compiler.compileV128BitMask for amd64.
+func (c *amd64Compiler) compileV128BitMask(o *wazeroir.OperationV128BitMask) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	result, err := c.allocateRegister(registerTypeGeneralPurpose)
+	if err != nil {
+		return err
+	}
+
+	switch o.Shape {
+	case wazeroir.ShapeI8x16:
+		c.assembler.CompileRegisterToRegister(amd64.PMOVMSKB, v.register, result)
+	case wazeroir.ShapeI16x8:
+		// Pack the 16-bit lanes into 8-bit lanes with signed saturation, which preserves
+		// the sign of each lane. The packed lanes are placed both on lower and higher 64-bits,
+		// so we take the higher 8 bits of the mask.
+		c.assembler.CompileRegisterToRegister(amd64.PACKSSWB, v.register, v.register)
+		c.assembler.CompileRegisterToRegister(amd64.PMOVMSKB, v.register, result)
+		c.assembler.CompileConstToRegister(amd64.SHRQ, 8, result)
+	case wazeroir.ShapeI32x4:
+		c.assembler.CompileRegisterToRegister(amd64.MOVMSKPS, v.register, result)
+	case wazeroir.ShapeI64x2:
+		c.assembler.CompileRegisterToRegister(amd64.MOVMSKPD, v.register, result)
+	}
+
+	c.locationStack.markRegisterUnused(v.register)
+	c.pushRuntimeValueLocationOnRegister(result, runtimeValueTypeI32)
+	return nil
+}
+
+// compileV128And implements compiler.compileV128And for amd64.
+func (c *amd64Compiler) compileV128And(*wazeroir.OperationV128And) error {
+	return c.compileV128x2BinOp(amd64.PAND)
+}
+
+// compileV128Not implements compiler.compileV128Not for amd64.
+func (c *amd64Compiler) compileV128Not(*wazeroir.OperationV128Not) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	if err = c.compileV128NotImpl(v.register); err != nil {
+		return err
+	}
+
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+// compileV128NotImpl flips all the bits of the vector register `v`.
+func (c *amd64Compiler) compileV128NotImpl(v asm.Register) error {
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	// Set all bits on tmp, and XOR it with v.
+	c.assembler.CompileRegisterToRegister(amd64.PCMPEQD, tmp, tmp)
+	c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, v)
+	return nil
+}
+
+// compileV128Or implements compiler.compileV128Or for amd64.
+func (c *amd64Compiler) compileV128Or(*wazeroir.OperationV128Or) error {
+	return c.compileV128x2BinOp(amd64.POR)
+}
+
+// compileV128Xor implements compiler.compileV128Xor for amd64.
+func (c *amd64Compiler) compileV128Xor(*wazeroir.OperationV128Xor) error {
+	return c.compileV128x2BinOp(amd64.PXOR)
+}
+
+// compileV128Bitselect implements compiler.compileV128Bitselect for amd64.
+func (c *amd64Compiler) compileV128Bitselect(*wazeroir.OperationV128Bitselect) error {
+	selector, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	// The following logic is equivalent to v128.or(v128.and(v1, selector), v128.and(v2, v128.not(selector)))
+	// See https://github.com/WebAssembly/spec/blob/main/proposals/simd/SIMD.md#bitwise-select
+	c.assembler.CompileRegisterToRegister(amd64.PAND, selector.register, x1.register)
+	c.assembler.CompileRegisterToRegister(amd64.PANDN, x2.register, selector.register)
+	c.assembler.CompileRegisterToRegister(amd64.POR, selector.register, x1.register)
+
+	c.locationStack.markRegisterUnused(x2.register, selector.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128AndNot implements compiler.compileV128AndNot for amd64.
+func (c *amd64Compiler) compileV128AndNot(*wazeroir.OperationV128AndNot) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	// PANDN computes the bitwise AND of the destination with the complement of the source,
+	// so we put x2 on the destination.
+	c.assembler.CompileRegisterToRegister(amd64.PANDN, x1.register, x2.register)
+
+	c.locationStack.markRegisterUnused(x1.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x2.register)
+	return nil
+}
+
+// compileV128Shr implements compiler.compileV128Shr for amd64.
+func (c *amd64Compiler) compileV128Shr(o *wazeroir.OperationV128Shr) error {
+	// https://stackoverflow.com/questions/35002937/sse-simd-shift-with-one-byte-element-size-granularity
+	if o.Shape == wazeroir.ShapeI8x16 {
+		return c.compileV128ShrI8x16Impl(o.Signed)
+	} else if o.Shape == wazeroir.ShapeI64x2 && o.Signed {
+		return c.compileV128ShrI64x2SignedImpl()
+	} else {
+		return c.compileV128ShiftImpl(o.Shape, false, o.Signed)
+	}
+}
+
+// compileV128Shl implements compiler.compileV128Shl for amd64.
+func (c *amd64Compiler) compileV128Shl(o *wazeroir.OperationV128Shl) error {
+	if o.Shape == wazeroir.ShapeI8x16 {
+		return c.compileV128ShlI8x16Impl()
+	} else {
+		return c.compileV128ShiftImpl(o.Shape, true, false)
+	}
+}
+
+// compileV128ShiftImpl implements the shifts on 16, 32 and 64-bit lanes except for the signed
+// right shift on 64-bit lanes, which has no corresponding instruction.
+func (c *amd64Compiler) compileV128ShiftImpl(shape wazeroir.Shape, shl, signed bool) error {
+	s := c.locationStack.pop()
+	if err := c.compileEnsureOnGeneralPurposeRegister(s); err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	vecTmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	var modulo int64
+	var shift asm.Instruction
+	switch shape {
+	case wazeroir.ShapeI16x8:
+		modulo = 0xf // modulo 16.
+		if shl {
+			shift = amd64.PSLLW
+		} else if signed {
+			shift = amd64.PSRAW
+		} else {
+			shift = amd64.PSRLW
+		}
+	case wazeroir.ShapeI32x4:
+		modulo = 0x1f // modulo 32.
+		if shl {
+			shift = amd64.PSLLL
+		} else if signed {
+			shift = amd64.PSRAL
+		} else {
+			shift = amd64.PSRLL
+		}
+	case wazeroir.ShapeI64x2:
+		modulo = 0x3f // modulo 64.
+		if shl {
+			shift = amd64.PSLLQ
+		} else {
+			shift = amd64.PSRLQ
+		}
+	}
+
+	c.assembler.CompileConstToRegister(amd64.ANDQ, modulo, s.register)
+	c.assembler.CompileRegisterToRegister(amd64.MOVL, s.register, vecTmp)
+	c.assembler.CompileRegisterToRegister(shift, vecTmp, x1.register)
+
+	c.locationStack.markRegisterUnused(s.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128ShrI64x2SignedImpl implements compiler.compileV128Shr for i64x2 signed (arithmetic) shift.
+// SSE has no arithmetic shift for 64-bit lanes, so we flip the bits of negative lanes, perform the
+// logical shift, and flip the bits back.
+func (c *amd64Compiler) compileV128ShrI64x2SignedImpl() error {
+	s := c.locationStack.pop()
+	if err := c.compileEnsureOnGeneralPurposeRegister(s); err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	signs, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+	c.locationStack.markRegisterUsed(signs)
+
+	shiftAmount, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileConstToRegister(amd64.ANDQ, 0x3f, s.register)
+	c.assembler.CompileRegisterToRegister(amd64.MOVL, s.register, shiftAmount)
+
+	// signs has all bits set on the negative lanes.
+	c.assembler.CompileRegisterToRegister(amd64.PXOR, signs, signs)
+	c.assembler.CompileRegisterToRegister(amd64.PCMPGTQ, x1.register, signs)
+
+	c.assembler.CompileRegisterToRegister(amd64.PXOR, signs, x1.register)
+	c.assembler.CompileRegisterToRegister(amd64.PSRLQ, shiftAmount, x1.register)
+	c.assembler.CompileRegisterToRegister(amd64.PXOR, signs, x1.register)
+
+	c.locationStack.markRegisterUnused(s.register, signs)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// i8x16LogicalSHRMaskTable is necessary for emulating non-existent packed bytes logical right shifts on amd64.
+// The mask is applied after performing packed word shifts on the value to clear out the unnecessary bits.
+var i8x16LogicalSHRMaskTable = [8 * 16]byte{ // (the number of possible shift amount 0, 1, ..., 7.) * 16 bytes.
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, // for 0 shift
+	0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, 0x7f, // for 1 shift
+	0x3f, 0x3f, 0x3f, 0x3f, 0x3f, 0x3f, 0x3f, 0x3f, 0x3f, 0x3f, 0x3f, 0x3f, 0x3f, 0x3f, 0x3f, 0x3f, // for 2 shift
+	0x1f, 0x1f, 0x1f, 0x1f, 0x1f, 0x1f, 0x1f, 0x1f, 0x1f, 0x1f, 0x1f, 0x1f, 0x1f, 0x1f, 0x1f, 0x1f, // for 3 shift
+	0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, // for 4 shift
+	0x07, 0x07, 0x07, 0x07, 0x07, 0x07, 0x07, 0x07, 0x07, 0x07, 0x07, 0x07, 0x07, 0x07, 0x07, 0x07, // for 5 shift
+	0x03, 0x03, 0x03, 0x03, 0x03, 0x03, 0x03, 0x03, 0x03, 0x03, 0x03, 0x03, 0x03, 0x03, 0x03, 0x03, // for 6 shift
+	0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, // for 7 shift
+}
+
+// i8x16SHLMaskTable is necessary for emulating non-existent packed bytes left shifts on amd64.
+// The mask is applied after performing packed word shifts on the value to clear out the unnecessary bits.
+var i8x16SHLMaskTable = [8 * 16]byte{ // (the number of possible shift amount 0, 1, ..., 7.) * 16 bytes.
+	0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, // for 0 shift
+	0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, // for 1 shift
+	0xfc, 0xfc, 0xfc, 0xfc, 0xfc, 0xfc, 0xfc, 0xfc, 0xfc, 0xfc, 0xfc, 0xfc, 0xfc, 0xfc, 0xfc, 0xfc, // for 2 shift
+	0xf8, 0xf8, 0xf8, 0xf8, 0xf8, 0xf8, 0xf8, 0xf8, 0xf8, 0xf8, 0xf8, 0xf8, 0xf8, 0xf8, 0xf8, 0xf8, // for 3 shift
+	0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, 0xf0, // for 4 shift
+	0xe0, 0xe0, 0xe0, 0xe0, 0xe0, 0xe0, 0xe0, 0xe0, 0xe0, 0xe0, 0xe0, 0xe0, 0xe0, 0xe0, 0xe0, 0xe0, // for 5 shift
+	0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, 0xc0, // for 6 shift
+	0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, // for 7 shift
+}
+
+// compileV128ShlI8x16Impl implements compiler.compileV128Shl for i8x16.
+func (c *amd64Compiler) compileV128ShlI8x16Impl() error {
+	return c.compileV128ShiftI8x16WithMaskImpl(amd64.PSLLW, i8x16SHLMaskTable[:])
+}
+
+// compileV128ShrI8x16Impl implements compiler.compileV128Shr for i8x16.
+func (c *amd64Compiler) compileV128ShrI8x16Impl(signed bool) error {
+	if signed {
+		return c.compileV128ShrI8x16SignedImpl()
+	}
+	return c.compileV128ShiftI8x16WithMaskImpl(amd64.PSRLW, i8x16LogicalSHRMaskTable[:])
+}
+
+// compileV128ShiftI8x16WithMaskImpl shifts the 16-bit lanes with `shift`, and then clears out the bits
+// which are shifted across the 8-bit lane boundaries with the mask chosen from `maskTable`.
+func (c *amd64Compiler) compileV128ShiftI8x16WithMaskImpl(shift asm.Instruction, maskTable []byte) error {
+	s := c.locationStack.pop()
+	if err := c.compileEnsureOnGeneralPurposeRegister(s); err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	vecTmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	gpTmp, err := c.allocateRegister(registerTypeGeneralPurpose)
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileConstToRegister(amd64.ANDQ, 0x7, s.register) // modulo 8.
+	c.assembler.CompileRegisterToRegister(amd64.MOVL, s.register, vecTmp)
+	c.assembler.CompileRegisterToRegister(shift, vecTmp, x1.register)
+
+	// Load the mask for the shift amount: maskTable[s*16:(s+1)*16].
+	c.addStaticData(maskTable)
+	c.assembler.CompileConstToRegister(amd64.MOVQ, int64(uintptr(unsafe.Pointer(&maskTable[0]))), gpTmp)
+	c.assembler.CompileConstToRegister(amd64.SHLQ, 4, s.register)
+	c.assembler.CompileMemoryWithIndexToRegister(amd64.MOVDQU, gpTmp, 0, s.register, 1, vecTmp)
+
+	c.assembler.CompileRegisterToRegister(amd64.PAND, vecTmp, x1.register)
+
+	c.locationStack.markRegisterUnused(s.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128ShrI8x16SignedImpl implements compiler.compileV128Shr for i8x16 signed (arithmetic) shift.
+// Each byte is extended to a 16-bit lane with the byte itself on the higher 8-bits, so that the arithmetic
+// 16-bit shift by (amount+8) results in the sign-extended shifted byte, which is then packed back.
+func (c *amd64Compiler) compileV128ShrI8x16SignedImpl() error {
+	s := c.locationStack.pop()
+	if err := c.compileEnsureOnGeneralPurposeRegister(s); err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	vecTmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+	c.locationStack.markRegisterUsed(vecTmp)
+
+	shiftAmount, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileConstToRegister(amd64.ANDQ, 0x7, s.register) // modulo 8.
+	c.assembler.CompileConstToRegister(amd64.ADDQ, 8, s.register)
+	c.assembler.CompileRegisterToRegister(amd64.MOVL, s.register, shiftAmount)
+
+	// Copy the value so that we can have both lower and higher 8 bytes extended.
+	c.assembler.CompileRegisterToRegister(amd64.MOVDQU, x1.register, vecTmp)
+	c.assembler.CompileRegisterToRegister(amd64.PUNPCKLBW, x1.register, x1.register)
+	c.assembler.CompileRegisterToRegister(amd64.PUNPCKHBW, vecTmp, vecTmp)
+	c.assembler.CompileRegisterToRegister(amd64.PSRAW, shiftAmount, x1.register)
+	c.assembler.CompileRegisterToRegister(amd64.PSRAW, shiftAmount, vecTmp)
+	c.assembler.CompileRegisterToRegister(amd64.PACKSSWB, vecTmp, x1.register)
+
+	c.locationStack.markRegisterUnused(s.register, vecTmp)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128Cmp implements compiler.compileV128Cmp for amd64.
+func (c *amd64Compiler) compileV128Cmp(o *wazeroir.OperationV128Cmp) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	const (
+		// See https://www.felixcloutier.com/x86/cmpps
+		//
+		// Note: if we want to emit "lt" and "le" with x2 and x1 swapped, we can
+		// use these predicates in order to compute "gt" and "ge" respectively.
+		floatEqualPredicate           = 0 // EQ_OQ
+		floatLessThanPredicate        = 1 // LT_OS
+		floatLessThanOrEqualPredicate = 2 // LE_OS
+		floatNotEqualPredicate        = 4 // NEQ_UQ
+	)
+
+	var result asm.Register
+	switch o.Type {
+	case wazeroir.V128CmpTypeF32x4Eq:
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPS, x2.register, x1.register, floatEqualPredicate)
+		result = x1.register
+	case wazeroir.V128CmpTypeF32x4Ne:
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPS, x2.register, x1.register, floatNotEqualPredicate)
+		result = x1.register
+	case wazeroir.V128CmpTypeF32x4Lt:
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPS, x2.register, x1.register, floatLessThanPredicate)
+		result = x1.register
+	case wazeroir.V128CmpTypeF32x4Gt:
+		// Without AVX, there's no float Gt instruction, so we swap the register and use Lt instead.
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPS, x1.register, x2.register, floatLessThanPredicate)
+		result = x2.register
+	case wazeroir.V128CmpTypeF32x4Le:
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPS, x2.register, x1.register, floatLessThanOrEqualPredicate)
+		result = x1.register
+	case wazeroir.V128CmpTypeF32x4Ge:
+		// Without AVX, there's no float Ge instruction, so we swap the register and use Le instead.
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPS, x1.register, x2.register, floatLessThanOrEqualPredicate)
+		result = x2.register
+	case wazeroir.V128CmpTypeF64x2Eq:
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPD, x2.register, x1.register, floatEqualPredicate)
+		result = x1.register
+	case wazeroir.V128CmpTypeF64x2Ne:
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPD, x2.register, x1.register, floatNotEqualPredicate)
+		result = x1.register
+	case wazeroir.V128CmpTypeF64x2Lt:
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPD, x2.register, x1.register, floatLessThanPredicate)
+		result = x1.register
+	case wazeroir.V128CmpTypeF64x2Gt:
+		// Without AVX, there's no float Gt instruction, so we swap the register and use Lt instead.
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPD, x1.register, x2.register, floatLessThanPredicate)
+		result = x2.register
+	case wazeroir.V128CmpTypeF64x2Le:
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPD, x2.register, x1.register, floatLessThanOrEqualPredicate)
+		result = x1.register
+	case wazeroir.V128CmpTypeF64x2Ge:
+		// Without AVX, there's no float Ge instruction, so we swap the register and use Le instead.
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPD, x1.register, x2.register, floatLessThanOrEqualPredicate)
+		result = x2.register
+	case wazeroir.V128CmpTypeI8x16Eq:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQB, x2.register, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI8x16Ne:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQB, x2.register, x1.register)
+		if err = c.compileV128NotImpl(x1.register); err != nil {
+			return err
+		}
+		result = x1.register
+	case wazeroir.V128CmpTypeI8x16LtS:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPGTB, x1.register, x2.register)
+		result = x2.register
+	case wazeroir.V128CmpTypeI8x16LtU, wazeroir.V128CmpTypeI8x16GtU:
+		// Take the unsigned min/max values on each lane on x1 and x2 onto x1.
+		if o.Type == wazeroir.V128CmpTypeI8x16LtU {
+			c.assembler.CompileRegisterToRegister(amd64.PMINUB, x2.register, x1.register)
+		} else {
+			c.assembler.CompileRegisterToRegister(amd64.PMAXUB, x2.register, x1.register)
+		}
+		// Then compare if x1 and x2 are the same, and flip the bits.
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQB, x2.register, x1.register)
+		if err = c.compileV128NotImpl(x1.register); err != nil {
+			return err
+		}
+		result = x1.register
+	case wazeroir.V128CmpTypeI8x16GtS:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPGTB, x2.register, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI8x16LeS, wazeroir.V128CmpTypeI8x16LeU:
+		tmp, err := c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		// Copy the value on the src to tmp.
+		c.assembler.CompileRegisterToRegister(amd64.MOVDQU, x1.register, tmp)
+		if o.Type == wazeroir.V128CmpTypeI8x16LeS {
+			c.assembler.CompileRegisterToRegister(amd64.PMINSB, x2.register, tmp)
+		} else {
+			c.assembler.CompileRegisterToRegister(amd64.PMINUB, x2.register, tmp)
+		}
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQB, tmp, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI8x16GeS, wazeroir.V128CmpTypeI8x16GeU:
+		tmp, err := c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegister(amd64.MOVDQU, x1.register, tmp)
+		if o.Type == wazeroir.V128CmpTypeI8x16GeS {
+			c.assembler.CompileRegisterToRegister(amd64.PMAXSB, x2.register, tmp)
+		} else {
+			c.assembler.CompileRegisterToRegister(amd64.PMAXUB, x2.register, tmp)
+		}
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQB, tmp, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI16x8Eq:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQW, x2.register, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI16x8Ne:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQW, x2.register, x1.register)
+		if err = c.compileV128NotImpl(x1.register); err != nil {
+			return err
+		}
+		result = x1.register
+	case wazeroir.V128CmpTypeI16x8LtS:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPGTW, x1.register, x2.register)
+		result = x2.register
+	case wazeroir.V128CmpTypeI16x8LtU, wazeroir.V128CmpTypeI16x8GtU:
+		// Take the unsigned min/max values on each lane on x1 and x2 onto x1.
+		if o.Type == wazeroir.V128CmpTypeI16x8LtU {
+			c.assembler.CompileRegisterToRegister(amd64.PMINUW, x2.register, x1.register)
+		} else {
+			c.assembler.CompileRegisterToRegister(amd64.PMAXUW, x2.register, x1.register)
+		}
+		// Then compare if x1 and x2 are the same, and flip the bits.
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQW, x2.register, x1.register)
+		if err = c.compileV128NotImpl(x1.register); err != nil {
+			return err
+		}
+		result = x1.register
+	case wazeroir.V128CmpTypeI16x8GtS:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPGTW, x2.register, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI16x8LeS, wazeroir.V128CmpTypeI16x8LeU:
+		tmp, err := c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		// Copy the value on the src to tmp.
+		c.assembler.CompileRegisterToRegister(amd64.MOVDQU, x1.register, tmp)
+		if o.Type == wazeroir.V128CmpTypeI16x8LeS {
+			c.assembler.CompileRegisterToRegister(amd64.PMINSW, x2.register, tmp)
+		} else {
+			c.assembler.CompileRegisterToRegister(amd64.PMINUW, x2.register, tmp)
+		}
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQW, tmp, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI16x8GeS, wazeroir.V128CmpTypeI16x8GeU:
+		tmp, err := c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegister(amd64.MOVDQU, x1.register, tmp)
+		if o.Type == wazeroir.V128CmpTypeI16x8GeS {
+			c.assembler.CompileRegisterToRegister(amd64.PMAXSW, x2.register, tmp)
+		} else {
+			c.assembler.CompileRegisterToRegister(amd64.PMAXUW, x2.register, tmp)
+		}
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQW, tmp, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI32x4Eq:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQD, x2.register, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI32x4Ne:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQD, x2.register, x1.register)
+		if err = c.compileV128NotImpl(x1.register); err != nil {
+			return err
+		}
+		result = x1.register
+	case wazeroir.V128CmpTypeI32x4LtS:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPGTD, x1.register, x2.register)
+		result = x2.register
+	case wazeroir.V128CmpTypeI32x4LtU, wazeroir.V128CmpTypeI32x4GtU:
+		// Take the unsigned min/max values on each lane on x1 and x2 onto x1.
+		if o.Type == wazeroir.V128CmpTypeI32x4LtU {
+			c.assembler.CompileRegisterToRegister(amd64.PMINUD, x2.register, x1.register)
+		} else {
+			c.assembler.CompileRegisterToRegister(amd64.PMAXUD, x2.register, x1.register)
+		}
+		// Then compare if x1 and x2 are the same, and flip the bits.
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQD, x2.register, x1.register)
+		if err = c.compileV128NotImpl(x1.register); err != nil {
+			return err
+		}
+		result = x1.register
+	case wazeroir.V128CmpTypeI32x4GtS:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPGTD, x2.register, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI32x4LeS, wazeroir.V128CmpTypeI32x4LeU:
+		tmp, err := c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		// Copy the value on the src to tmp.
+		c.assembler.CompileRegisterToRegister(amd64.MOVDQU, x1.register, tmp)
+		if o.Type == wazeroir.V128CmpTypeI32x4LeS {
+			c.assembler.CompileRegisterToRegister(amd64.PMINSD, x2.register, tmp)
+		} else {
+			c.assembler.CompileRegisterToRegister(amd64.PMINUD, x2.register, tmp)
+		}
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQD, tmp, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI32x4GeS, wazeroir.V128CmpTypeI32x4GeU:
+		tmp, err := c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegister(amd64.MOVDQU, x1.register, tmp)
+		if o.Type == wazeroir.V128CmpTypeI32x4GeS {
+			c.assembler.CompileRegisterToRegister(amd64.PMAXSD, x2.register, tmp)
+		} else {
+			c.assembler.CompileRegisterToRegister(amd64.PMAXUD, x2.register, tmp)
+		}
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQD, tmp, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI64x2Eq:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQQ, x2.register, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI64x2Ne:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQQ, x2.register, x1.register)
+		if err = c.compileV128NotImpl(x1.register); err != nil {
+			return err
+		}
+		result = x1.register
+	case wazeroir.V128CmpTypeI64x2LtS:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPGTQ, x1.register, x2.register)
+		result = x2.register
+	case wazeroir.V128CmpTypeI64x2GtS:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPGTQ, x2.register, x1.register)
+		result = x1.register
+	case wazeroir.V128CmpTypeI64x2LeS:
+		// x1 <= x2 is equivalent to !(x1 > x2).
+		c.assembler.CompileRegisterToRegister(amd64.PCMPGTQ, x2.register, x1.register)
+		if err = c.compileV128NotImpl(x1.register); err != nil {
+			return err
+		}
+		result = x1.register
+	case wazeroir.V128CmpTypeI64x2GeS:
+		// x1 >= x2 is equivalent to !(x2 > x1).
+		c.assembler.CompileRegisterToRegister(amd64.PCMPGTQ, x1.register, x2.register)
+		if err = c.compileV128NotImpl(x2.register); err != nil {
+			return err
+		}
+		result = x2.register
+	}
+
+	c.locationStack.markRegisterUnused(x1.register, x2.register)
+	c.pushVectorRuntimeValueLocationOnRegister(result)
+	return nil
+}
+
+// compileV128AddSat implements compiler.compileV128AddSat for amd64.
+func (c *amd64Compiler) compileV128AddSat(o *wazeroir.OperationV128AddSat) error {
+	var inst asm.Instruction
+	switch o.Shape {
+	case wazeroir.ShapeI8x16:
+		if o.Signed {
+			inst = amd64.PADDSB
+		} else {
+			inst = amd64.PADDUSB
+		}
+	case wazeroir.ShapeI16x8:
+		if o.Signed {
+			inst = amd64.PADDSW
+		} else {
+			inst = amd64.PADDUSW
+		}
+	}
+	return c.compileV128x2BinOp(inst)
+}
+
+// compileV128SubSat implements compiler.compileV128SubSat for amd64.
+func (c *amd64Compiler) compileV128SubSat(o *wazeroir.OperationV128SubSat) error {
+	var inst asm.Instruction
+	switch o.Shape {
+	case wazeroir.ShapeI8x16:
+		if o.Signed {
+			inst = amd64.PSUBSB
+		} else {
+			inst = amd64.PSUBUSB
+		}
+	case wazeroir.ShapeI16x8:
+		if o.Signed {
+			inst = amd64.PSUBSW
+		} else {
+			inst = amd64.PSUBUSW
+		}
+	}
+	return c.compileV128x2BinOp(inst)
+}
+
+// compileV128Mul implements compiler.compileV128Mul for amd64.
+func (c *amd64Compiler) compileV128Mul(o *wazeroir.OperationV128Mul) error {
+	var inst asm.Instruction
+	switch o.Shape {
+	case wazeroir.ShapeI16x8:
+		inst = amd64.PMULLW
+	case wazeroir.ShapeI32x4:
+		inst = amd64.PMULLD
+	case wazeroir.ShapeI64x2:
+		return c.compileV128MulI64x2()
+	case wazeroir.ShapeF32x4:
+		inst = amd64.MULPS
+	case wazeroir.ShapeF64x2:
+		inst = amd64.MULPD
+	}
+	return c.compileV128x2BinOp(inst)
+}
+
+// compileV128MulI64x2 implements V128Mul for i64x2. There's no instruction for multiplying 64-bit lanes
+// without AVX-512, so we compute it with 32-bit multiplications as
+//
+//	x1*x2 = x1Lo*x2Lo + ((x1Hi*x2Lo + x1Lo*x2Hi) << 32)
+//
+// where xLo and xHi are the lower and higher 32-bits of each 64-bit lane.
+func (c *amd64Compiler) compileV128MulI64x2() error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp1, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+	c.locationStack.markRegisterUsed(tmp1)
+
+	tmp2, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	// tmp1 = x1Hi*x2Lo.
+	c.assembler.CompileRegisterToRegister(amd64.MOVDQU, x1.register, tmp1)
+	c.assembler.CompileConstToRegister(amd64.PSRLQ, 32, tmp1)
+	c.assembler.CompileRegisterToRegister(amd64.PMULUDQ, x2.register, tmp1)
+
+	// tmp2 = x2Hi*x1Lo.
+	c.assembler.CompileRegisterToRegister(amd64.MOVDQU, x2.register, tmp2)
+	c.assembler.CompileConstToRegister(amd64.PSRLQ, 32, tmp2)
+	c.assembler.CompileRegisterToRegister(amd64.PMULUDQ, x1.register, tmp2)
+
+	// tmp1 = (tmp1 + tmp2) << 32.
+	c.assembler.CompileRegisterToRegister(amd64.PADDQ, tmp2, tmp1)
+	c.assembler.CompileConstToRegister(amd64.PSLLQ, 32, tmp1)
+
+	// x1 = x1Lo*x2Lo + tmp1.
+	c.assembler.CompileRegisterToRegister(amd64.PMULUDQ, x2.register, x1.register)
+	c.assembler.CompileRegisterToRegister(amd64.PADDQ, tmp1, x1.register)
+
+	c.locationStack.markRegisterUnused(x2.register, tmp1)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128Div implements compiler.compileV128Div for amd64.
+func (c *amd64Compiler) compileV128Div(o *wazeroir.OperationV128Div) error {
+	var inst asm.Instruction
+	switch o.Shape {
+	case wazeroir.ShapeF32x4:
+		inst = amd64.DIVPS
+	case wazeroir.ShapeF64x2:
+		inst = amd64.DIVPD
+	}
+	return c.compileV128x2BinOp(inst)
+}
+
+// compileV128Neg implements compiler.compileV128Neg for amd64.
+func (c *amd64Compiler) compileV128Neg(o *wazeroir.OperationV128Neg) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	var result asm.Register
+	switch o.Shape {
+	case wazeroir.ShapeI8x16, wazeroir.ShapeI16x8, wazeroir.ShapeI32x4, wazeroir.ShapeI64x2:
+		var sub asm.Instruction
+		switch o.Shape {
+		case wazeroir.ShapeI8x16:
+			sub = amd64.PSUBB
+		case wazeroir.ShapeI16x8:
+			sub = amd64.PSUBW
+		case wazeroir.ShapeI32x4:
+			sub = amd64.PSUBL
+		case wazeroir.ShapeI64x2:
+			sub = amd64.PSUBQ
+		}
+		// Subtract v from zero.
+		c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, tmp)
+		c.assembler.CompileRegisterToRegister(sub, v.register, tmp)
+		result = tmp
+	case wazeroir.ShapeF32x4:
+		// Flip the sign bit of each lane with the mask made from all-one bits.
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQD, tmp, tmp)
+		c.assembler.CompileConstToRegister(amd64.PSLLL, 31, tmp)
+		c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, v.register)
+		result = v.register
+	case wazeroir.ShapeF64x2:
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQD, tmp, tmp)
+		c.assembler.CompileConstToRegister(amd64.PSLLQ, 63, tmp)
+		c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, v.register)
+		result = v.register
+	}
+
+	c.locationStack.markRegisterUnused(v.register)
+	c.pushVectorRuntimeValueLocationOnRegister(result)
+	return nil
+}
+
+// compileV128Sqrt implements compiler.compileV128Sqrt for amd64.
+func (c *amd64Compiler) compileV128Sqrt(o *wazeroir.OperationV128Sqrt) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	var inst asm.Instruction
+	switch o.Shape {
+	case wazeroir.ShapeF64x2:
+		inst = amd64.SQRTPD
+	case wazeroir.ShapeF32x4:
+		inst = amd64.SQRTPS
+	}
+
+	c.assembler.CompileRegisterToRegister(inst, v.register, v.register)
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+// compileV128Abs implements compiler.compileV128Abs for amd64.
+func (c *amd64Compiler) compileV128Abs(o *wazeroir.OperationV128Abs) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	switch o.Shape {
+	case wazeroir.ShapeI8x16:
+		c.assembler.CompileRegisterToRegister(amd64.PABSB, v.register, v.register)
+	case wazeroir.ShapeI16x8:
+		c.assembler.CompileRegisterToRegister(amd64.PABSW, v.register, v.register)
+	case wazeroir.ShapeI32x4:
+		c.assembler.CompileRegisterToRegister(amd64.PABSD, v.register, v.register)
+	case wazeroir.ShapeI64x2:
+		tmp, err := c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		// tmp has all bits set on the negative lanes, and then we compute (v ^ tmp) - tmp,
+		// which negates only the negative lanes.
+		c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, tmp)
+		c.assembler.CompileRegisterToRegister(amd64.PCMPGTQ, v.register, tmp)
+		c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, v.register)
+		c.assembler.CompileRegisterToRegister(amd64.PSUBQ, tmp, v.register)
+	case wazeroir.ShapeF32x4:
+		tmp, err := c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		// Clear the sign bit of each lane with the mask made from all-one bits.
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQD, tmp, tmp)
+		c.assembler.CompileConstToRegister(amd64.PSRLL, 1, tmp)
+		c.assembler.CompileRegisterToRegister(amd64.PAND, tmp, v.register)
+	case wazeroir.ShapeF64x2:
+		tmp, err := c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileRegisterToRegister(amd64.PCMPEQD, tmp, tmp)
+		c.assembler.CompileConstToRegister(amd64.PSRLQ, 1, tmp)
+		c.assembler.CompileRegisterToRegister(amd64.PAND, tmp, v.register)
+	}
+
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+var (
+	// popcntMask is the mask to take the lower 4-bits of each byte.
+	popcntMask = [16]byte{
+		0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f,
+		0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f, 0x0f,
+	}
+	// popcntTable holds each index's Popcnt, for example popcntTable[5] holds popcnt(0x05).
+	popcntTable = [16]byte{
+		0x00, 0x01, 0x01, 0x02, 0x01, 0x02, 0x02, 0x03,
+		0x01, 0x02, 0x02, 0x03, 0x02, 0x03, 0x03, 0x04,
+	}
+)
+
+// compileV128Popcnt implements compiler.compileV128Popcnt for amd64.
+func (c *amd64Compiler) compileV128Popcnt(*wazeroir.OperationV128Popcnt) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp1, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+	c.locationStack.markRegisterUsed(tmp1)
+
+	tmp2, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+	c.locationStack.markRegisterUsed(tmp2)
+
+	// Split each byte into the higher and lower 4-bits on tmp2 and v respectively.
+	if err = c.compileLoadStaticV128ToRegister(popcntMask[:], tmp1); err != nil {
+		return err
+	}
+	c.assembler.CompileRegisterToRegister(amd64.MOVDQU, v.register, tmp2)
+	c.assembler.CompileConstToRegister(amd64.PSRLW, 4, tmp2)
+	c.assembler.CompileRegisterToRegister(amd64.PAND, tmp1, tmp2)
+	c.assembler.CompileRegisterToRegister(amd64.PAND, tmp1, v.register)
+
+	// Look up the Popcnt of each 4-bits with PSHUFB, and add them.
+	if err = c.compileLoadStaticV128ToRegister(popcntTable[:], tmp1); err != nil {
+		return err
+	}
+	c.assembler.CompileRegisterToRegister(amd64.PSHUFB, v.register, tmp1)
+	if err = c.compileLoadStaticV128ToRegister(popcntTable[:], v.register); err != nil {
+		return err
+	}
+	c.assembler.CompileRegisterToRegister(amd64.PSHUFB, tmp2, v.register)
+	c.assembler.CompileRegisterToRegister(amd64.PADDB, tmp1, v.register)
+
+	c.locationStack.markRegisterUnused(tmp1, tmp2)
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+// compileV128Min implements compiler.compileV128Min for amd64.
+func (c *amd64Compiler) compileV128Min(o *wazeroir.OperationV128Min) error {
+	var inst asm.Instruction
+	switch o.Shape {
+	case wazeroir.ShapeI8x16:
+		if o.Signed {
+			inst = amd64.PMINSB
+		} else {
+			inst = amd64.PMINUB
+		}
+	case wazeroir.ShapeI16x8:
+		if o.Signed {
+			inst = amd64.PMINSW
+		} else {
+			inst = amd64.PMINUW
+		}
+	case wazeroir.ShapeI32x4:
+		if o.Signed {
+			inst = amd64.PMINSD
+		} else {
+			inst = amd64.PMINUD
+		}
+	case wazeroir.ShapeF32x4:
+		return c.compileV128FloatMinImpl(true)
+	case wazeroir.ShapeF64x2:
+		return c.compileV128FloatMinImpl(false)
+	}
+	return c.compileV128x2BinOp(inst)
+}
+
+// compileV128FloatMinImpl implements compiler.compileV128Min for float lanes. MINPS and MINPD don't
+// follow the Wasm semantics on NaN and signed zeros, so we perform them in both orders and merge the results.
+// This is the same instruction sequence as V8: https://github.com/v8/v8/blob/10.2.154/src/codegen/shared-ia32-x64/macro-assembler-shared-ia32-x64.cc
+func (c *amd64Compiler) compileV128FloatMinImpl(is32bit bool) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	min, cmp, andn, or, srl /* shift right logical */ := amd64.MINPD, amd64.CMPPD, amd64.ANDNPD, amd64.ORPD, amd64.PSRLQ
+	// The shift amount to clear the payload of canonical NaN.
+	srlAmount := int64(13)
+	if is32bit {
+		min, cmp, andn, or, srl = amd64.MINPS, amd64.CMPPS, amd64.ANDNPS, amd64.ORPS, amd64.PSRLL
+		srlAmount = 10
+	}
+
+	// Perform min in both orders.
+	c.assembler.CompileRegisterToRegister(amd64.MOVDQU, x2.register, tmp)
+	c.assembler.CompileRegisterToRegister(min, x1.register, tmp)
+	c.assembler.CompileRegisterToRegister(min, x2.register, x1.register)
+	// Propagate -0 and NaN.
+	c.assembler.CompileRegisterToRegister(or, x1.register, tmp)
+	// Canonicalize NaN by quieting and clearing the payload.
+	c.assembler.CompileRegisterToRegisterWithArg(cmp, tmp, x1.register, 3) // UNORD_Q.
+	c.assembler.CompileRegisterToRegister(or, x1.register, tmp)
+	c.assembler.CompileConstToRegister(srl, srlAmount, x1.register)
+	c.assembler.CompileRegisterToRegister(andn, tmp, x1.register)
+
+	c.locationStack.markRegisterUnused(x2.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128Max implements compiler.compileV128Max for amd64.
+func (c *amd64Compiler) compileV128Max(o *wazeroir.OperationV128Max) error {
+	var inst asm.Instruction
+	switch o.Shape {
+	case wazeroir.ShapeI8x16:
+		if o.Signed {
+			inst = amd64.PMAXSB
+		} else {
+			inst = amd64.PMAXUB
+		}
+	case wazeroir.ShapeI16x8:
+		if o.Signed {
+			inst = amd64.PMAXSW
+		} else {
+			inst = amd64.PMAXUW
+		}
+	case wazeroir.ShapeI32x4:
+		if o.Signed {
+			inst = amd64.PMAXSD
+		} else {
+			inst = amd64.PMAXUD
+		}
+	case wazeroir.ShapeF32x4:
+		return c.compileV128FloatMaxImpl(true)
+	case wazeroir.ShapeF64x2:
+		return c.compileV128FloatMaxImpl(false)
+	}
+	return c.compileV128x2BinOp(inst)
+}
+
+// compileV128FloatMaxImpl implements compiler.compileV128Max for float lanes. MAXPS and MAXPD don't
+// follow the Wasm semantics on NaN and signed zeros, so we perform them in both orders and merge the results.
+// This is the same instruction sequence as V8: https://github.com/v8/v8/blob/10.2.154/src/codegen/shared-ia32-x64/macro-assembler-shared-ia32-x64.cc
+func (c *amd64Compiler) compileV128FloatMaxImpl(is32bit bool) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	max, cmp, andn, or, xor, sub, srl /* shift right logical */ := amd64.MAXPD, amd64.CMPPD, amd64.ANDNPD, amd64.ORPD, amd64.XORPD, amd64.SUBPD, amd64.PSRLQ
+	// The shift amount to clear the payload of canonical NaN.
+	srlAmount := int64(13)
+	if is32bit {
+		max, cmp, andn, or, xor, sub, srl = amd64.MAXPS, amd64.CMPPS, amd64.ANDNPS, amd64.ORPS, amd64.XORPS, amd64.SUBPS, amd64.PSRLL
+		srlAmount = 10
+	}
+
+	// Perform max in both orders.
+	c.assembler.CompileRegisterToRegister(amd64.MOVDQU, x2.register, tmp)
+	c.assembler.CompileRegisterToRegister(max, x1.register, tmp)
+	c.assembler.CompileRegisterToRegister(max, x2.register, x1.register)
+	// Find the discrepancies.
+	c.assembler.CompileRegisterToRegister(xor, tmp, x1.register)
+	// Propagate NaN.
+	c.assembler.CompileRegisterToRegister(or, x1.register, tmp)
+	// Propagate the sign discrepancy and quiet NaN.
+	c.assembler.CompileRegisterToRegister(sub, x1.register, tmp)
+	// Canonicalize NaN by clearing the payload.
+	c.assembler.CompileRegisterToRegisterWithArg(cmp, tmp, x1.register, 3) // UNORD_Q.
+	c.assembler.CompileConstToRegister(srl, srlAmount, x1.register)
+	c.assembler.CompileRegisterToRegister(andn, tmp, x1.register)
+
+	c.locationStack.markRegisterUnused(x2.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128AvgrU implements compiler.compileV128AvgrU for amd64.
+func (c *amd64Compiler) compileV128AvgrU(o *wazeroir.OperationV128AvgrU) error {
+	var inst asm.Instruction
+	switch o.Shape {
+	case wazeroir.ShapeI8x16:
+		inst = amd64.PAVGB
+	case wazeroir.ShapeI16x8:
+		inst = amd64.PAVGW
+	}
+	return c.compileV128x2BinOp(inst)
+}
+
+// compileV128Pmin implements compiler.compileV128Pmin for amd64.
+func (c *amd64Compiler) compileV128Pmin(o *wazeroir.OperationV128Pmin) error {
+	var inst asm.Instruction
+	if o.Shape == wazeroir.ShapeF32x4 {
+		inst = amd64.MINPS
+	} else {
+		inst = amd64.MINPD
+	}
+	return c.compileV128PseudoMinOrMaxImpl(inst)
+}
+
+// compileV128Pmax implements compiler.compileV128Pmax for amd64.
+func (c *amd64Compiler) compileV128Pmax(o *wazeroir.OperationV128Pmax) error {
+	var inst asm.Instruction
+	if o.Shape == wazeroir.ShapeF32x4 {
+		inst = amd64.MAXPS
+	} else {
+		inst = amd64.MAXPD
+	}
+	return c.compileV128PseudoMinOrMaxImpl(inst)
+}
+
+// compileV128PseudoMinOrMaxImpl implements Pmin and Pmax. MINPS(D) and MAXPS(D) return the source operand
+// when the values are unordered or both zeros, which exactly matches the pseudo min/max semantics if x1 is
+// the source and x2 is the destination.
+func (c *amd64Compiler) compileV128PseudoMinOrMaxImpl(inst asm.Instruction) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileRegisterToRegister(inst, x1.register, x2.register)
+
+	c.locationStack.markRegisterUnused(x1.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x2.register)
+	return nil
+}
+
+// compileV128Ceil implements compiler.compileV128Ceil for amd64.
+func (c *amd64Compiler) compileV128Ceil(o *wazeroir.OperationV128Ceil) error {
+	// See https://www.felixcloutier.com/x86/roundpd
+	const roundModeCeil = 0x2
+	return c.compileV128RoundImpl(o.Shape == wazeroir.ShapeF32x4, roundModeCeil)
+}
+
+// compileV128Floor implements compiler.compileV128Floor for amd64.
+func (c *amd64Compiler) compileV128Floor(o *wazeroir.OperationV128Floor) error {
+	// See https://www.felixcloutier.com/x86/roundpd
+	const roundModeFloor = 0x1
+	return c.compileV128RoundImpl(o.Shape == wazeroir.ShapeF32x4, roundModeFloor)
+}
+
+// compileV128Trunc implements compiler.compileV128Trunc for amd64.
+func (c *amd64Compiler) compileV128Trunc(o *wazeroir.OperationV128Trunc) error {
+	// See https://www.felixcloutier.com/x86/roundpd
+	const roundModeTrunc = 0x3
+	return c.compileV128RoundImpl(o.Shape == wazeroir.ShapeF32x4, roundModeTrunc)
+}
+
+// compileV128Nearest implements compiler.compileV128Nearest for amd64.
+func (c *amd64Compiler) compileV128Nearest(o *wazeroir.OperationV128Nearest) error {
+	// See https://www.felixcloutier.com/x86/roundpd
+	const roundModeNearest = 0x0
+	return c.compileV128RoundImpl(o.Shape == wazeroir.ShapeF32x4, roundModeNearest)
+}
+
+// compileV128RoundImpl implements compileV128Nearest compileV128Trunc compileV128Floor and compileV128Ceil
+// with ROUNDPS (32-bit lane) and ROUNDPD (64-bit lane).
+func (c *amd64Compiler) compileV128RoundImpl(is32bit bool, mode byte) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	var round asm.Instruction
+	if is32bit {
+		round = amd64.ROUNDPS
+	} else {
+		round = amd64.ROUNDPD
+	}
+
+	c.assembler.CompileRegisterToRegisterWithArg(round, v.register, v.register, mode)
+
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+// compileV128Extend implements compiler.compileV128Extend for amd64.
+func (c *amd64Compiler) compileV128Extend(o *wazeroir.OperationV128Extend) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	if !o.UseLow {
+		// We have to shift the higher 64-bits into the lower ones before the actual extending instruction.
+		// Shifting right by 0x8 * 8 = 64bits and concatenate itself.
+		// See https://www.felixcloutier.com/x86/psrldq
+		c.assembler.CompileConstToRegister(amd64.PSRLDQ, 8, v.register)
+	}
+
+	c.assembler.CompileRegisterToRegister(c.v128ExtendInstruction(o.OriginShape, o.Signed), v.register, v.register)
+
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+// v128ExtendInstruction returns the instruction to extend the lower 64-bits of lanes of `originShape`.
+func (c *amd64Compiler) v128ExtendInstruction(originShape wazeroir.Shape, signed bool) (inst asm.Instruction) {
+	switch originShape {
+	case wazeroir.ShapeI8x16:
+		if signed {
+			inst = amd64.PMOVSXBW
+		} else {
+			inst = amd64.PMOVZXBW
+		}
+	case wazeroir.ShapeI16x8:
+		if signed {
+			inst = amd64.PMOVSXWD
+		} else {
+			inst = amd64.PMOVZXWD
+		}
+	case wazeroir.ShapeI32x4:
+		if signed {
+			inst = amd64.PMOVSXDQ
+		} else {
+			inst = amd64.PMOVZXDQ
+		}
+	}
+	return
+}
+
+// compileV128ExtMul implements compiler.compileV128ExtMul for amd64.
+func (c *amd64Compiler) compileV128ExtMul(o *wazeroir.OperationV128ExtMul) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	switch o.OriginShape {
+	case wazeroir.ShapeI8x16, wazeroir.ShapeI16x8:
+		if !o.UseLow {
+			// Shift the higher 64-bits into the lower ones.
+			c.assembler.CompileConstToRegister(amd64.PSRLDQ, 8, x1.register)
+			c.assembler.CompileConstToRegister(amd64.PSRLDQ, 8, x2.register)
+		}
+		extend := c.v128ExtendInstruction(o.OriginShape, o.Signed)
+		c.assembler.CompileRegisterToRegister(extend, x1.register, x1.register)
+		c.assembler.CompileRegisterToRegister(extend, x2.register, x2.register)
+		if o.OriginShape == wazeroir.ShapeI8x16 {
+			c.assembler.CompileRegisterToRegister(amd64.PMULLW, x2.register, x1.register)
+		} else {
+			c.assembler.CompileRegisterToRegister(amd64.PMULLD, x2.register, x1.register)
+		}
+	case wazeroir.ShapeI32x4:
+		// Place the 32-bit lanes on the even 32-bit lanes, which PMULDQ and PMULUDQ multiply.
+		var shuffle byte
+		if o.UseLow {
+			shuffle = 0b01_01_00_00
+		} else {
+			shuffle = 0b11_11_10_10
+		}
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PSHUFD, x1.register, x1.register, shuffle)
+		c.assembler.CompileRegisterToRegisterWithArg(amd64.PSHUFD, x2.register, x2.register, shuffle)
+		if o.Signed {
+			c.assembler.CompileRegisterToRegister(amd64.PMULDQ, x2.register, x1.register)
+		} else {
+			c.assembler.CompileRegisterToRegister(amd64.PMULUDQ, x2.register, x1.register)
+		}
+	}
+
+	c.locationStack.markRegisterUnused(x2.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+var (
+	// i16x8Min holds the minimum signed 16-bit integer, 0x8000, on each 16-bit lane.
+	i16x8Min = [16]byte{
+		0x00, 0x80, 0x00, 0x80, 0x00, 0x80, 0x00, 0x80,
+		0x00, 0x80, 0x00, 0x80, 0x00, 0x80, 0x00, 0x80,
+	}
+	// i16x8One holds 1 on each 16-bit lane.
+	i16x8One = [16]byte{
+		0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00,
+		0x01, 0x00, 0x01, 0x00, 0x01, 0x00, 0x01, 0x00,
+	}
+	// i8x16One holds 1 on each 8-bit lane.
+	i8x16One = [16]byte{
+		0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+		0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01,
+	}
+	// i32x4x10000 holds 0x10000 on each 32-bit lane.
+	i32x4x10000 = [16]byte{
+		0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00,
+		0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00,
+	}
+	// swizzleConst is added to the indexes of swizzle with unsigned saturation, so that
+	// the out-of-range indexes have the highest bit set.
+	swizzleConst = [16]byte{
+		0x70, 0x70, 0x70, 0x70, 0x70, 0x70, 0x70, 0x70,
+		0x70, 0x70, 0x70, 0x70, 0x70, 0x70, 0x70, 0x70,
+	}
+)
+
+// compileV128Q15mulrSatS implements compiler.compileV128Q15mulrSatS for amd64.
+func (c *amd64Compiler) compileV128Q15mulrSatS(*wazeroir.OperationV128Q15mulrSatS) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	// PMULHRSW results in 0x8000 only when both lanes are 0x8000, where it should be saturated to 0x7fff.
+	// So we flip the bits of such lanes.
+	if err = c.compileLoadStaticV128ToRegister(i16x8Min[:], tmp); err != nil {
+		return err
+	}
+	c.assembler.CompileRegisterToRegister(amd64.PMULHRSW, x2.register, x1.register)
+	c.assembler.CompileRegisterToRegister(amd64.PCMPEQW, x1.register, tmp)
+	c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, x1.register)
+
+	c.locationStack.markRegisterUnused(x2.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128ExtAddPairwise implements compiler.compileV128ExtAddPairwise for amd64.
+func (c *amd64Compiler) compileV128ExtAddPairwise(o *wazeroir.OperationV128ExtAddPairwise) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	result := v.register
+	switch o.OriginShape {
+	case wazeroir.ShapeI8x16:
+		if err = c.compileLoadStaticV128ToRegister(i8x16One[:], tmp); err != nil {
+			return err
+		}
+		// PMADDUBSW multiplies the unsigned bytes on the destination with the signed bytes on the
+		// source, and adds the adjacent pairs.
+		if o.Signed {
+			c.assembler.CompileRegisterToRegister(amd64.PMADDUBSW, v.register, tmp)
+			result = tmp
+		} else {
+			c.assembler.CompileRegisterToRegister(amd64.PMADDUBSW, tmp, v.register)
+		}
+	case wazeroir.ShapeI16x8:
+		if o.Signed {
+			if err = c.compileLoadStaticV128ToRegister(i16x8One[:], tmp); err != nil {
+				return err
+			}
+			c.assembler.CompileRegisterToRegister(amd64.PMADDWD, tmp, v.register)
+		} else {
+			// Flip the sign bits so that the unsigned lanes are interpreted as signed ones
+			// subtracted by 0x8000, and then add the subtracted (0x8000*2) back after PMADDWD.
+			if err = c.compileLoadStaticV128ToRegister(i16x8Min[:], tmp); err != nil {
+				return err
+			}
+			c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, v.register)
+			if err = c.compileLoadStaticV128ToRegister(i16x8One[:], tmp); err != nil {
+				return err
+			}
+			c.assembler.CompileRegisterToRegister(amd64.PMADDWD, tmp, v.register)
+			if err = c.compileLoadStaticV128ToRegister(i32x4x10000[:], tmp); err != nil {
+				return err
+			}
+			c.assembler.CompileRegisterToRegister(amd64.PADDL, tmp, v.register)
+		}
+	}
+
+	if result != v.register {
+		c.locationStack.markRegisterUnused(v.register)
+	}
+	c.pushVectorRuntimeValueLocationOnRegister(result)
+	return nil
+}
+
+// compileV128FloatPromote implements compiler.compileV128FloatPromote for amd64.
+func (c *amd64Compiler) compileV128FloatPromote(*wazeroir.OperationV128FloatPromote) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileRegisterToRegister(amd64.CVTPS2PD, v.register, v.register)
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+// compileV128FloatDemote implements compiler.compileV128FloatDemote for amd64.
+func (c *amd64Compiler) compileV128FloatDemote(*wazeroir.OperationV128FloatDemote) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileRegisterToRegister(amd64.CVTPD2PS, v.register, v.register)
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+var (
+	// f64x2ConvertFromIMask holds 0x43300000 on each 32-bit lane. Interleaved with 32-bit unsigned
+	// integers, this results in the float64 values 2^52 + integer.
+	f64x2ConvertFromIMask = [16]byte{
+		0x00, 0x00, 0x30, 0x43, 0x00, 0x00, 0x30, 0x43,
+		0x00, 0x00, 0x30, 0x43, 0x00, 0x00, 0x30, 0x43,
+	}
+	// twop52 holds the float64 value 2^52 (0x4330000000000000) on each 64-bit lane.
+	twop52 = [16]byte{
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x43,
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x30, 0x43,
+	}
+)
+
+// compileV128FConvertFromI implements compiler.compileV128FConvertFromI for amd64.
+func (c *amd64Compiler) compileV128FConvertFromI(o *wazeroir.OperationV128FConvertFromI) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	switch o.DestinationShape {
+	case wazeroir.ShapeF32x4:
+		if o.Signed {
+			c.assembler.CompileRegisterToRegister(amd64.CVTDQ2PS, v.register, v.register)
+		} else {
+			tmp, err := c.allocateRegister(registerTypeVector)
+			if err != nil {
+				return err
+			}
+
+			// Take the lower 16-bits of each lane on tmp, and the higher ones on v.
+			c.assembler.CompileRegisterToRegister(amd64.MOVDQU, v.register, tmp)
+			c.assembler.CompileConstToRegister(amd64.PSLLL, 0x10, tmp)
+			c.assembler.CompileConstToRegister(amd64.PSRLL, 0x10, tmp)
+			c.assembler.CompileRegisterToRegister(amd64.PSUBL, tmp, v.register)
+
+			// Convert the lower 16-bits exactly.
+			c.assembler.CompileRegisterToRegister(amd64.CVTDQ2PS, tmp, tmp)
+
+			// Divide the higher bits by 2 to make them fit in the signed range, convert
+			// exactly, and then double them.
+			c.assembler.CompileConstToRegister(amd64.PSRLL, 1, v.register)
+			c.assembler.CompileRegisterToRegister(amd64.CVTDQ2PS, v.register, v.register)
+			c.assembler.CompileRegisterToRegister(amd64.ADDPS, v.register, v.register)
+
+			// Add the higher and lower parts, which may round.
+			c.assembler.CompileRegisterToRegister(amd64.ADDPS, tmp, v.register)
+		}
+	case wazeroir.ShapeF64x2:
+		if o.Signed {
+			c.assembler.CompileRegisterToRegister(amd64.CVTDQ2PD, v.register, v.register)
+		} else {
+			tmp, err := c.allocateRegister(registerTypeVector)
+			if err != nil {
+				return err
+			}
+
+			// v = [v0, 0x43300000, v1, 0x43300000] in terms of 32-bit lanes, which represents
+			// 2^52 + v0 and 2^52 + v1 as float64 lanes.
+			if err = c.compileLoadStaticV128ToRegister(f64x2ConvertFromIMask[:], tmp); err != nil {
+				return err
+			}
+			c.assembler.CompileRegisterToRegister(amd64.UNPCKLPS, tmp, v.register)
+
+			// Then subtract 2^52 from each lane.
+			if err = c.compileLoadStaticV128ToRegister(twop52[:], tmp); err != nil {
+				return err
+			}
+			c.assembler.CompileRegisterToRegister(amd64.SUBPD, tmp, v.register)
+		}
+	}
+
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+// compileV128Dot implements compiler.compileV128Dot for amd64.
+func (c *amd64Compiler) compileV128Dot(*wazeroir.OperationV128Dot) error {
+	return c.compileV128x2BinOp(amd64.PMADDWD)
+}
+
+// compileV128Narrow implements compiler.compileV128Narrow for amd64.
+func (c *amd64Compiler) compileV128Narrow(o *wazeroir.OperationV128Narrow) error {
+	var inst asm.Instruction
+	switch o.OriginShape {
+	case wazeroir.ShapeI16x8:
+		if o.Signed {
+			inst = amd64.PACKSSWB
+		} else {
+			inst = amd64.PACKUSWB
+		}
+	case wazeroir.ShapeI32x4:
+		if o.Signed {
+			inst = amd64.PACKSSDW
+		} else {
+			inst = amd64.PACKUSDW
+		}
+	}
+	return c.compileV128x2BinOp(inst)
+}
+
+var (
+	// f64x2Max32bitSigned holds the float64 value 2147483647.0 on each 64-bit lane.
+	f64x2Max32bitSigned = [16]byte{
+		0x00, 0x00, 0xc0, 0xff, 0xff, 0xff, 0xdf, 0x41,
+		0x00, 0x00, 0xc0, 0xff, 0xff, 0xff, 0xdf, 0x41,
+	}
+	// f64x2Max32bitUnsigned holds the float64 value 4294967295.0 on each 64-bit lane.
+	f64x2Max32bitUnsigned = [16]byte{
+		0x00, 0x00, 0xe0, 0xff, 0xff, 0xff, 0xef, 0x41,
+		0x00, 0x00, 0xe0, 0xff, 0xff, 0xff, 0xef, 0x41,
+	}
+)
+
+// compileV128ITruncSatFromF implements compiler.compileV128ITruncSatFromF for amd64.
+// The instruction sequences are the same as V8: https://github.com/v8/v8/blob/10.2.154/src/codegen/shared-ia32-x64/macro-assembler-shared-ia32-x64.h
+func (c *amd64Compiler) compileV128ITruncSatFromF(o *wazeroir.OperationV128ITruncSatFromF) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+	c.locationStack.markRegisterUsed(tmp)
+
+	switch o.OriginShape {
+	case wazeroir.ShapeF32x4:
+		if o.Signed {
+			// Zero out NaN lanes.
+			c.assembler.CompileRegisterToRegister(amd64.MOVDQU, v.register, tmp)
+			c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPS, tmp, tmp, 0) // EQ_OQ.
+			c.assembler.CompileRegisterToRegister(amd64.PAND, tmp, v.register)
+
+			// Set the highest bit of tmp on the positive lanes.
+			c.assembler.CompileRegisterToRegister(amd64.PXOR, v.register, tmp)
+
+			// Overflowing lanes result in 0x80000000.
+			c.assembler.CompileRegisterToRegister(amd64.CVTTPS2DQ, v.register, v.register)
+
+			// Flip the bits of the positive lanes which overflowed into 0x7fffffff.
+			c.assembler.CompileRegisterToRegister(amd64.PAND, v.register, tmp)
+			c.assembler.CompileConstToRegister(amd64.PSRAL, 0x1f, tmp)
+			c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, v.register)
+		} else {
+			tmp2, err := c.allocateRegister(registerTypeVector)
+			if err != nil {
+				return err
+			}
+
+			// Zero out NaN and negative lanes.
+			c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, tmp)
+			c.assembler.CompileRegisterToRegister(amd64.MAXPS, tmp, v.register)
+
+			// tmp = float32(0x7fffffff) on each lane.
+			c.assembler.CompileRegisterToRegister(amd64.PCMPEQD, tmp, tmp)
+			c.assembler.CompileConstToRegister(amd64.PSRLL, 0x1, tmp)
+			c.assembler.CompileRegisterToRegister(amd64.CVTDQ2PS, tmp, tmp)
+
+			// tmp2 = int32(v - tmp), where the positive overflow lanes are 0x7fffffff,
+			// and the negative ones are zero.
+			c.assembler.CompileRegisterToRegister(amd64.MOVDQU, v.register, tmp2)
+			c.assembler.CompileRegisterToRegister(amd64.SUBPS, tmp, tmp2)
+			c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPS, tmp2, tmp, 0x2) // LE_OS.
+			c.assembler.CompileRegisterToRegister(amd64.CVTTPS2DQ, tmp2, tmp2)
+			c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, tmp2)
+			c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, tmp)
+			c.assembler.CompileRegisterToRegister(amd64.PMAXSD, tmp, tmp2)
+
+			// Convert, where the lanes above 0x7fffffff result in 0x80000000,
+			// and add tmp2 for such lanes.
+			c.assembler.CompileRegisterToRegister(amd64.CVTTPS2DQ, v.register, v.register)
+			c.assembler.CompileRegisterToRegister(amd64.PADDL, tmp2, v.register)
+		}
+	case wazeroir.ShapeF64x2:
+		tmp2, err := c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+
+		if o.Signed {
+			// Zero out NaN lanes, and clamp the positive lanes with 2147483647.0.
+			c.assembler.CompileRegisterToRegister(amd64.MOVDQU, v.register, tmp)
+			c.assembler.CompileRegisterToRegisterWithArg(amd64.CMPPD, v.register, tmp, 0) // EQ_OQ.
+			if err = c.compileLoadStaticV128ToRegister(f64x2Max32bitSigned[:], tmp2); err != nil {
+				return err
+			}
+			c.assembler.CompileRegisterToRegister(amd64.ANDPS, tmp2, tmp)
+			c.assembler.CompileRegisterToRegister(amd64.MINPD, tmp, v.register)
+
+			// Overflowing negative lanes result in 0x80000000.
+			c.assembler.CompileRegisterToRegister(amd64.CVTTPD2DQ, v.register, v.register)
+		} else {
+			// Clamp the lanes into [0, 4294967295.0], where NaN becomes zero.
+			c.assembler.CompileRegisterToRegister(amd64.PXOR, tmp, tmp)
+			c.assembler.CompileRegisterToRegister(amd64.MAXPD, tmp, v.register)
+			if err = c.compileLoadStaticV128ToRegister(f64x2Max32bitUnsigned[:], tmp2); err != nil {
+				return err
+			}
+			c.assembler.CompileRegisterToRegister(amd64.MINPD, tmp2, v.register)
+
+			// Truncate the lanes, and add 2^52 so that the lower 32-bits of each 64-bit lane
+			// hold the integer.
+			c.assembler.CompileRegisterToRegisterWithArg(amd64.ROUNDPD, v.register, v.register, 0x3)
+			if err = c.compileLoadStaticV128ToRegister(twop52[:], tmp2); err != nil {
+				return err
+			}
+			c.assembler.CompileRegisterToRegister(amd64.ADDPD, tmp2, v.register)
+
+			// Gather the lower 32-bits of each 64-bit lane into the lower 64-bits, and zero out the higher ones.
+			c.assembler.CompileRegisterToRegisterWithArg(amd64.SHUFPS, tmp, v.register, 0b10_00_10_00)
+		}
+	}
+
+	c.locationStack.markRegisterUnused(tmp)
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
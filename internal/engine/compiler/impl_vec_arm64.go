@@ -1,12 +1,15 @@
 package compiler
 
 import (
+	"unsafe"
+
 	"github.com/tetratelabs/wazero/internal/asm"
 	"github.com/tetratelabs/wazero/internal/asm/arm64"
 	"github.com/tetratelabs/wazero/internal/wazeroir"
 )
 
-func (c *arm64Compiler) compileConstV128(o *wazeroir.OperationConstV128) error {
+// compileV128Const implements compiler.compileV128Const for arm64.
+func (c *arm64Compiler) compileV128Const(o *wazeroir.OperationV128Const) error {
 	c.maybeCompileMoveTopConditionalToFreeGeneralPurposeRegister()
 
 	result, err := c.allocateRegister(registerTypeVector)
@@ -33,20 +36,19 @@ func (c *arm64Compiler) compileConstV128(o *wazeroir.OperationConstV128) error {
 	// "ins Vn.D[1], intReg"
 	c.assembler.CompileRegisterToVectorRegister(arm64.VMOV, intReg, result, arm64.VectorArrangementD, 1)
 
-	c.pushRuntimeValueLocationOnRegister(result, runtimeValueTypeV128Lo)
-	c.pushRuntimeValueLocationOnRegister(result, runtimeValueTypeV128Hi)
+	c.pushVectorRuntimeValueLocationOnRegister(result)
 	return nil
 }
 
-func (c *arm64Compiler) compileAddV128(o *wazeroir.OperationAddV128) error {
-	c.locationStack.pop() // skip higher 64-bits.
-	x1Low := c.locationStack.pop()
-	if err := c.compileEnsureOnGeneralPurposeRegister(x1Low); err != nil {
+// compileV128Add implements compiler.compileV128Add for arm64.
+func (c *arm64Compiler) compileV128Add(o *wazeroir.OperationV128Add) error {
+	x2, err := c.popV128()
+	if err != nil {
 		return err
 	}
-	c.locationStack.pop() // skip higher 64-bits.
-	x2Low := c.locationStack.pop()
-	if err := c.compileEnsureOnGeneralPurposeRegister(x2Low); err != nil {
+
+	x1, err := c.popV128()
+	if err != nil {
 		return err
 	}
 
@@ -73,12 +75,1249 @@ func (c *arm64Compiler) compileAddV128(o *wazeroir.OperationAddV128) error {
 		arr = arm64.VectorArrangement2D
 	}
 
-	c.assembler.CompileVectorRegisterToVectorRegister(inst, x1Low.register, x2Low.register, arr)
+	c.assembler.CompileVectorRegisterToVectorRegister(inst, x1.register, x2.register, arr,
+		arm64.VectorIndexNone, arm64.VectorIndexNone)
+
+	c.pushVectorRuntimeValueLocationOnRegister(x2.register)
+	c.markRegisterUnused(x1.register)
+	return nil
+}
+
+// compileV128Sub implements compiler.compileV128Sub for arm64.
+func (c *arm64Compiler) compileV128Sub(o *wazeroir.OperationV128Sub) error {
+	var arr arm64.VectorArrangement
+	var inst asm.Instruction
+	switch o.Shape {
+	case wazeroir.ShapeI8x16:
+		inst, arr = arm64.VSUB, arm64.VectorArrangement16B
+	case wazeroir.ShapeI16x8:
+		inst, arr = arm64.VSUB, arm64.VectorArrangement8H
+	case wazeroir.ShapeI32x4:
+		inst, arr = arm64.VSUB, arm64.VectorArrangement4S
+	case wazeroir.ShapeI64x2:
+		inst, arr = arm64.VSUB, arm64.VectorArrangement2D
+	case wazeroir.ShapeF32x4:
+		inst, arr = arm64.VFSUB, arm64.VectorArrangement4S
+	case wazeroir.ShapeF64x2:
+		inst, arr = arm64.VFSUB, arm64.VectorArrangement2D
+	}
+	return c.compileV128x2BinOp(inst, arr)
+}
+
+// compileV128x2BinOp performs `inst` on the two vectors on top of the stack with the arrangement `arr`,
+// and the result is placed on the register of the first operand. In other words, "x1 = x1 `inst` x2".
+func (c *arm64Compiler) compileV128x2BinOp(inst asm.Instruction, arr arm64.VectorArrangement) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileTwoVectorRegistersToVectorRegister(inst, x1.register, x2.register, x1.register, arr)
+
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	c.markRegisterUnused(x2.register)
+	return nil
+}
+
+// compileV128UniOp performs `inst` on the vector on top of the stack with the arrangement `arr`,
+// and the result is placed on the same register.
+func (c *arm64Compiler) compileV128UniOp(inst asm.Instruction, arr arm64.VectorArrangement) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileVectorRegisterToVectorRegister(inst, v.register, v.register, arr,
+		arm64.VectorIndexNone, arm64.VectorIndexNone)
+
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+// popV128 pops a V128 value from the stack and ensures that it is placed on a vector register.
+// Note: unlike popValueOnRegister, the resulting register is still marked used.
+func (c *arm64Compiler) popV128() (*runtimeValueLocation, error) {
+	c.locationStack.pop() // skip higher 64-bits.
+	v := c.locationStack.pop()
+	if err := c.compileEnsureOnGeneralPurposeRegister(v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// compileLoadStaticV128ToRegister loads the 16-bytes `data` onto the vector register `dst`.
+// The data is kept alive as a static data of the function.
+func (c *arm64Compiler) compileLoadStaticV128ToRegister(data []byte, dst asm.Register) {
+	c.addStaticData(data)
+
+	// "arm64ReservedRegisterForTemporary = &data[0]"
+	c.assembler.CompileConstToRegister(arm64.MOVD, int64(uintptr(unsafe.Pointer(&data[0]))),
+		arm64ReservedRegisterForTemporary)
+	c.assembler.CompileMemoryToVectorRegister(arm64.VLD1, arm64ReservedRegisterForTemporary, dst,
+		arm64.VectorArrangement2D)
+}
+
+// compileV128Load implements compiler.compileV128Load for arm64.
+func (c *arm64Compiler) compileV128Load(o *wazeroir.OperationV128Load) error {
+	var result asm.Register
+	var err error
+	switch o.Type {
+	case wazeroir.V128LoadType128:
+		offsetReg, err := c.compileMemoryAccessOffsetSetup(o.Arg.Offset, 16)
+		if err != nil {
+			return err
+		}
+		// "offsetReg = &memory.Buffer[offset]"
+		c.assembler.CompileRegisterToRegister(arm64.ADD, arm64ReservedRegisterForMemory, offsetReg)
+
+		result, err = c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileMemoryToVectorRegister(arm64.VLD1, offsetReg, result, arm64.VectorArrangement2D)
+	case wazeroir.V128LoadType8x8s:
+		result, err = c.compileV128LoadImpl(arm64.FMOVD, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileVectorRegisterToVectorRegisterWithConst(arm64.VSSHLL, result, result,
+			arm64.VectorArrangement8B, 0)
+	case wazeroir.V128LoadType8x8u:
+		result, err = c.compileV128LoadImpl(arm64.FMOVD, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileVectorRegisterToVectorRegisterWithConst(arm64.VUSHLL, result, result,
+			arm64.VectorArrangement8B, 0)
+	case wazeroir.V128LoadType16x4s:
+		result, err = c.compileV128LoadImpl(arm64.FMOVD, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileVectorRegisterToVectorRegisterWithConst(arm64.VSSHLL, result, result,
+			arm64.VectorArrangement4H, 0)
+	case wazeroir.V128LoadType16x4u:
+		result, err = c.compileV128LoadImpl(arm64.FMOVD, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileVectorRegisterToVectorRegisterWithConst(arm64.VUSHLL, result, result,
+			arm64.VectorArrangement4H, 0)
+	case wazeroir.V128LoadType32x2s:
+		result, err = c.compileV128LoadImpl(arm64.FMOVD, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileVectorRegisterToVectorRegisterWithConst(arm64.VSSHLL, result, result,
+			arm64.VectorArrangement2S, 0)
+	case wazeroir.V128LoadType32x2u:
+		result, err = c.compileV128LoadImpl(arm64.FMOVD, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+		c.assembler.CompileVectorRegisterToVectorRegisterWithConst(arm64.VUSHLL, result, result,
+			arm64.VectorArrangement2S, 0)
+	case wazeroir.V128LoadType8Splat:
+		result, err = c.compileV128LoadSplatImpl(arm64.MOVBU, o.Arg.Offset, 1, arm64.VectorArrangement16B)
+		if err != nil {
+			return err
+		}
+	case wazeroir.V128LoadType16Splat:
+		result, err = c.compileV128LoadSplatImpl(arm64.MOVHU, o.Arg.Offset, 2, arm64.VectorArrangement8H)
+		if err != nil {
+			return err
+		}
+	case wazeroir.V128LoadType32Splat:
+		result, err = c.compileV128LoadSplatImpl(arm64.MOVWU, o.Arg.Offset, 4, arm64.VectorArrangement4S)
+		if err != nil {
+			return err
+		}
+	case wazeroir.V128LoadType64Splat:
+		result, err = c.compileV128LoadSplatImpl(arm64.MOVD, o.Arg.Offset, 8, arm64.VectorArrangement2D)
+		if err != nil {
+			return err
+		}
+	case wazeroir.V128LoadType32zero:
+		// Scalar loads clear the upper bits of the vector register.
+		result, err = c.compileV128LoadImpl(arm64.FMOVS, o.Arg.Offset, 4)
+		if err != nil {
+			return err
+		}
+	case wazeroir.V128LoadType64zero:
+		result, err = c.compileV128LoadImpl(arm64.FMOVD, o.Arg.Offset, 8)
+		if err != nil {
+			return err
+		}
+	}
+
+	c.pushVectorRuntimeValueLocationOnRegister(result)
+	return nil
+}
+
+// compileV128LoadImpl loads `targetSizeInBytes` bytes from the memory onto the lowest bits of a vector register
+// with the scalar load instruction `inst`. The other bits of the vector register are cleared.
+func (c *arm64Compiler) compileV128LoadImpl(inst asm.Instruction, offset uint32, targetSizeInBytes int64) (asm.Register, error) {
+	offsetReg, err := c.compileMemoryAccessOffsetSetup(offset, targetSizeInBytes)
+	if err != nil {
+		return asm.NilRegister, err
+	}
+
+	result, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return asm.NilRegister, err
+	}
+
+	c.assembler.CompileMemoryWithRegisterOffsetToRegister(inst, arm64ReservedRegisterForMemory, offsetReg, result)
+	return result, nil
+}
+
+// compileV128LoadSplatImpl loads `targetSizeInBytes` bytes from the memory onto a general purpose register with `inst`,
+// and then copies the value into all the lanes of a vector register with the arrangement `arr`.
+func (c *arm64Compiler) compileV128LoadSplatImpl(inst asm.Instruction, offset uint32, targetSizeInBytes int64,
+	arr arm64.VectorArrangement) (asm.Register, error) {
+	offsetReg, err := c.compileMemoryAccessOffsetSetup(offset, targetSizeInBytes)
+	if err != nil {
+		return asm.NilRegister, err
+	}
+
+	c.assembler.CompileMemoryWithRegisterOffsetToRegister(inst, arm64ReservedRegisterForMemory, offsetReg, offsetReg)
+
+	result, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return asm.NilRegister, err
+	}
+
+	c.assembler.CompileRegisterToVectorRegister(arm64.VDUP, offsetReg, result, arr, arm64.VectorIndexNone)
+	return result, nil
+}
+
+// laneSizeToLoadStoreInstructions returns the general purpose load and store instructions for a lane of `laneSize` bits,
+// and the arrangement which specifies the element size of the lane.
+func laneSizeToLoadStoreInstructions(laneSize byte) (loadInst, storeInst asm.Instruction, arr arm64.VectorArrangement) {
+	switch laneSize {
+	case 8:
+		loadInst, storeInst, arr = arm64.MOVBU, arm64.MOVB, arm64.VectorArrangementB
+	case 16:
+		loadInst, storeInst, arr = arm64.MOVHU, arm64.MOVH, arm64.VectorArrangementH
+	case 32:
+		loadInst, storeInst, arr = arm64.MOVWU, arm64.MOVW, arm64.VectorArrangementS
+	case 64:
+		loadInst, storeInst, arr = arm64.MOVD, arm64.MOVD, arm64.VectorArrangementD
+	}
+	return
+}
+
+// compileV128LoadLane implements compiler.compileV128LoadLane for arm64.
+func (c *arm64Compiler) compileV128LoadLane(o *wazeroir.OperationV128LoadLane) error {
+	targetVector, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	loadInst, _, arr := laneSizeToLoadStoreInstructions(o.LaneSize)
+
+	offsetReg, err := c.compileMemoryAccessOffsetSetup(o.Arg.Offset, int64(o.LaneSize/8))
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileMemoryWithRegisterOffsetToRegister(loadInst, arm64ReservedRegisterForMemory, offsetReg, offsetReg)
+	c.assembler.CompileRegisterToVectorRegister(arm64.VMOV, offsetReg, targetVector.register, arr,
+		arm64.VectorIndex(o.LaneIndex))
+
+	c.pushVectorRuntimeValueLocationOnRegister(targetVector.register)
+	return nil
+}
+
+// compileV128Store implements compiler.compileV128Store for arm64.
+func (c *arm64Compiler) compileV128Store(o *wazeroir.OperationV128Store) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
 
-	resultReg := x2Low.register
-	c.pushRuntimeValueLocationOnRegister(resultReg, runtimeValueTypeV128Lo)
-	c.pushRuntimeValueLocationOnRegister(resultReg, runtimeValueTypeV128Hi)
+	offsetReg, err := c.compileMemoryAccessOffsetSetup(o.Arg.Offset, 16)
+	if err != nil {
+		return err
+	}
+
+	// "offsetReg = &memory.Buffer[offset]"
+	c.assembler.CompileRegisterToRegister(arm64.ADD, arm64ReservedRegisterForMemory, offsetReg)
+	c.assembler.CompileVectorRegisterToMemory(arm64.VST1, v.register, offsetReg, arm64.VectorArrangement2D)
 
-	c.markRegisterUnused(x1Low.register)
+	c.markRegisterUnused(v.register)
 	return nil
 }
+
+// compileV128StoreLane implements compiler.compileV128StoreLane for arm64.
+func (c *arm64Compiler) compileV128StoreLane(o *wazeroir.OperationV128StoreLane) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	_, storeInst, arr := laneSizeToLoadStoreInstructions(o.LaneSize)
+
+	offsetReg, err := c.compileMemoryAccessOffsetSetup(o.Arg.Offset, int64(o.LaneSize/8))
+	if err != nil {
+		return err
+	}
+
+	c.assembler.CompileVectorRegisterToRegister(arm64.VUMOV, v.register, arm64ReservedRegisterForTemporary, arr,
+		arm64.VectorIndex(o.LaneIndex))
+	c.assembler.CompileRegisterToMemoryWithRegisterOffset(storeInst, arm64ReservedRegisterForTemporary,
+		arm64ReservedRegisterForMemory, offsetReg)
+
+	c.markRegisterUnused(v.register)
+	return nil
+}
+
+// compileV128ExtractLane implements compiler.compileV128ExtractLane for arm64.
+func (c *arm64Compiler) compileV128ExtractLane(o *wazeroir.OperationV128ExtractLane) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	switch o.Shape {
+	case wazeroir.ShapeI8x16, wazeroir.ShapeI16x8, wazeroir.ShapeI32x4, wazeroir.ShapeI64x2:
+		result, err := c.allocateRegister(registerTypeGeneralPurpose)
+		if err != nil {
+			return err
+		}
+
+		inst, arr, vt := asm.Instruction(arm64.VUMOV), arm64.VectorArrangementB, runtimeValueTypeI32
+		switch o.Shape {
+		case wazeroir.ShapeI8x16:
+			if o.Signed {
+				inst = arm64.VSMOV
+			}
+		case wazeroir.ShapeI16x8:
+			arr = arm64.VectorArrangementH
+			if o.Signed {
+				inst = arm64.VSMOV
+			}
+		case wazeroir.ShapeI32x4:
+			arr = arm64.VectorArrangementS
+		case wazeroir.ShapeI64x2:
+			arr, vt = arm64.VectorArrangementD, runtimeValueTypeI64
+		}
+
+		c.assembler.CompileVectorRegisterToRegister(inst, v.register, result, arr, arm64.VectorIndex(o.LaneIndex))
+		c.markRegisterUnused(v.register)
+		c.pushRuntimeValueLocationOnRegister(result, vt)
+	case wazeroir.ShapeF32x4:
+		// Move the lane via the general purpose register so that the other lanes are cleared.
+		c.assembler.CompileVectorRegisterToRegister(arm64.VUMOV, v.register, arm64ReservedRegisterForTemporary,
+			arm64.VectorArrangementS, arm64.VectorIndex(o.LaneIndex))
+		c.assembler.CompileRegisterToRegister(arm64.FMOVS, arm64ReservedRegisterForTemporary, v.register)
+		c.pushRuntimeValueLocationOnRegister(v.register, runtimeValueTypeF32)
+	case wazeroir.ShapeF64x2:
+		c.assembler.CompileVectorRegisterToRegister(arm64.VUMOV, v.register, arm64ReservedRegisterForTemporary,
+			arm64.VectorArrangementD, arm64.VectorIndex(o.LaneIndex))
+		c.assembler.CompileRegisterToRegister(arm64.FMOVD, arm64ReservedRegisterForTemporary, v.register)
+		c.pushRuntimeValueLocationOnRegister(v.register, runtimeValueTypeF64)
+	}
+	return nil
+}
+
+// compileV128ReplaceLane implements compiler.compileV128ReplaceLane for arm64.
+func (c *arm64Compiler) compileV128ReplaceLane(o *wazeroir.OperationV128ReplaceLane) error {
+	origin := c.locationStack.pop()
+	if err := c.compileEnsureOnGeneralPurposeRegister(origin); err != nil {
+		return err
+	}
+
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	switch o.Shape {
+	case wazeroir.ShapeI8x16:
+		c.assembler.CompileRegisterToVectorRegister(arm64.VMOV, origin.register, v.register,
+			arm64.VectorArrangementB, arm64.VectorIndex(o.LaneIndex))
+	case wazeroir.ShapeI16x8:
+		c.assembler.CompileRegisterToVectorRegister(arm64.VMOV, origin.register, v.register,
+			arm64.VectorArrangementH, arm64.VectorIndex(o.LaneIndex))
+	case wazeroir.ShapeI32x4:
+		c.assembler.CompileRegisterToVectorRegister(arm64.VMOV, origin.register, v.register,
+			arm64.VectorArrangementS, arm64.VectorIndex(o.LaneIndex))
+	case wazeroir.ShapeI64x2:
+		c.assembler.CompileRegisterToVectorRegister(arm64.VMOV, origin.register, v.register,
+			arm64.VectorArrangementD, arm64.VectorIndex(o.LaneIndex))
+	case wazeroir.ShapeF32x4:
+		c.assembler.CompileVectorRegisterToVectorRegister(arm64.VINS, origin.register, v.register,
+			arm64.VectorArrangementS, 0, arm64.VectorIndex(o.LaneIndex))
+	case wazeroir.ShapeF64x2:
+		c.assembler.CompileVectorRegisterToVectorRegister(arm64.VINS, origin.register, v.register,
+			arm64.VectorArrangementD, 0, arm64.VectorIndex(o.LaneIndex))
+	}
+
+	c.markRegisterUnused(origin.register)
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+// compileV128Splat implements compiler.compileV128Splat for arm64.
+func (c *arm64Compiler) compileV128Splat(o *wazeroir.OperationV128Splat) error {
+	origin := c.locationStack.pop()
+	if err := c.compileEnsureOnGeneralPurposeRegister(origin); err != nil {
+		return err
+	}
+
+	var result asm.Register
+	switch o.Shape {
+	case wazeroir.ShapeI8x16, wazeroir.ShapeI16x8, wazeroir.ShapeI32x4, wazeroir.ShapeI64x2:
+		var err error
+		result, err = c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+
+		var arr arm64.VectorArrangement
+		switch o.Shape {
+		case wazeroir.ShapeI8x16:
+			arr = arm64.VectorArrangement16B
+		case wazeroir.ShapeI16x8:
+			arr = arm64.VectorArrangement8H
+		case wazeroir.ShapeI32x4:
+			arr = arm64.VectorArrangement4S
+		case wazeroir.ShapeI64x2:
+			arr = arm64.VectorArrangement2D
+		}
+		c.assembler.CompileRegisterToVectorRegister(arm64.VDUP, origin.register, result, arr, arm64.VectorIndexNone)
+		c.markRegisterUnused(origin.register)
+	case wazeroir.ShapeF32x4:
+		result = origin.register
+		c.assembler.CompileVectorRegisterToVectorRegister(arm64.VDUPELEM, origin.register, result,
+			arm64.VectorArrangement4S, 0, arm64.VectorIndexNone)
+	case wazeroir.ShapeF64x2:
+		result = origin.register
+		c.assembler.CompileVectorRegisterToVectorRegister(arm64.VDUPELEM, origin.register, result,
+			arm64.VectorArrangement2D, 0, arm64.VectorIndexNone)
+	}
+
+	c.pushVectorRuntimeValueLocationOnRegister(result)
+	return nil
+}
+
+// compileV128Shuffle implements compiler.compileV128Shuffle for arm64.
+func (c *arm64Compiler) compileV128Shuffle(o *wazeroir.OperationV128Shuffle) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	// TBL with a single table register results in zero for the out of range (>= 16) indexes, so we
+	// look up the lanes from x1 and x2 separately, and take the bitwise OR of the two results.
+	x1Indexes, x2Indexes := make([]byte, 16), make([]byte, 16)
+	for i, l := range o.Lanes {
+		x1Indexes[i] = l
+		// Indexes for x1 (< 16) are mapped to out of range ones (>= 16).
+		x2Indexes[i] = l ^ 16
+	}
+
+	// "tmp = TBL(x1, x1Indexes)"
+	c.compileLoadStaticV128ToRegister(x1Indexes, tmp)
+	c.assembler.CompileTwoVectorRegistersToVectorRegister(arm64.VTBL, x1.register, tmp, tmp, arm64.VectorArrangement16B)
+
+	// "x1 = TBL(x2, x2Indexes)"
+	c.compileLoadStaticV128ToRegister(x2Indexes, x1.register)
+	c.assembler.CompileTwoVectorRegistersToVectorRegister(arm64.VTBL, x2.register, x1.register, x1.register,
+		arm64.VectorArrangement16B)
+
+	// "x1 = x1 | tmp"
+	c.assembler.CompileTwoVectorRegistersToVectorRegister(arm64.VORR, x1.register, tmp, x1.register,
+		arm64.VectorArrangement16B)
+
+	c.markRegisterUnused(x2.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128Swizzle implements compiler.compileV128Swizzle for arm64.
+func (c *arm64Compiler) compileV128Swizzle(*wazeroir.OperationV128Swizzle) error {
+	indexVec, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	baseVec, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	// TBL results in zero for the out of range indexes (>= 16) which matches the semantics of swizzle.
+	c.assembler.CompileTwoVectorRegistersToVectorRegister(arm64.VTBL, baseVec.register, indexVec.register,
+		baseVec.register, arm64.VectorArrangement16B)
+
+	c.markRegisterUnused(indexVec.register)
+	c.pushVectorRuntimeValueLocationOnRegister(baseVec.register)
+	return nil
+}
+
+// compileV128AnyTrue implements compiler.compileV128AnyTrue for arm64.
+func (c *arm64Compiler) compileV128AnyTrue(*wazeroir.OperationV128AnyTrue) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	// The maximum of unsigned 32-bit lanes is non-zero if and only if any bit is set.
+	c.assembler.CompileVectorRegisterToVectorRegister(arm64.VUMAXV, v.register, v.register,
+		arm64.VectorArrangement4S, arm64.VectorIndexNone, arm64.VectorIndexNone)
+	c.assembler.CompileVectorRegisterToRegister(arm64.VUMOV, v.register, arm64ReservedRegisterForTemporary,
+		arm64.VectorArrangementS, 0)
+	c.assembler.CompileTwoRegistersToNone(arm64.CMP, arm64.RegRZR, arm64ReservedRegisterForTemporary)
+
+	c.markRegisterUnused(v.register)
+	c.locationStack.pushRuntimeValueLocationOnConditionalRegister(arm64.CondNE)
+	return nil
+}
+
+// compileV128AllTrue implements compiler.compileV128AllTrue for arm64.
+func (c *arm64Compiler) compileV128AllTrue(o *wazeroir.OperationV128AllTrue) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	cond := arm64.CondNE
+	if o.Shape == wazeroir.ShapeI64x2 {
+		// There's no UMINV for 64-bit lanes, so we set all the bits on zero lanes,
+		// and check if the maximum of 32-bit lanes is zero.
+		c.assembler.CompileVectorRegisterToVectorRegister(arm64.VCMEQZ, v.register, v.register,
+			arm64.VectorArrangement2D, arm64.VectorIndexNone, arm64.VectorIndexNone)
+		c.assembler.CompileVectorRegisterToVectorRegister(arm64.VUMAXV, v.register, v.register,
+			arm64.VectorArrangement4S, arm64.VectorIndexNone, arm64.VectorIndexNone)
+		c.assembler.CompileVectorRegisterToRegister(arm64.VUMOV, v.register, arm64ReservedRegisterForTemporary,
+			arm64.VectorArrangementS, 0)
+		cond = arm64.CondEQ
+	} else {
+		var arr, elementArr arm64.VectorArrangement
+		switch o.Shape {
+		case wazeroir.ShapeI8x16:
+			arr, elementArr = arm64.VectorArrangement16B, arm64.VectorArrangementB
+		case wazeroir.ShapeI16x8:
+			arr, elementArr = arm64.VectorArrangement8H, arm64.VectorArrangementH
+		case wazeroir.ShapeI32x4:
+			arr, elementArr = arm64.VectorArrangement4S, arm64.VectorArrangementS
+		}
+
+		// The minimum of the lanes is non-zero if and only if all the lanes are non-zero.
+		c.assembler.CompileVectorRegisterToVectorRegister(arm64.VUMINV, v.register, v.register,
+			arr, arm64.VectorIndexNone, arm64.VectorIndexNone)
+		c.assembler.CompileVectorRegisterToRegister(arm64.VUMOV, v.register, arm64ReservedRegisterForTemporary,
+			elementArr, 0)
+	}
+	c.assembler.CompileTwoRegistersToNone(arm64.CMP, arm64.RegRZR, arm64ReservedRegisterForTemporary)
+
+	c.markRegisterUnused(v.register)
+	c.locationStack.pushRuntimeValueLocationOnConditionalRegister(cond)
+	return nil
+}
+
+var (
+	// i8x16BitMaskWeights holds the weight of each lane for compileV128BitMask on i8x16 shape.
+	i8x16BitMaskWeights = [16]byte{1, 2, 4, 8, 16, 32, 64, 128, 1, 2, 4, 8, 16, 32, 64, 128}
+	// i16x8BitMaskWeights holds the weight of each lane for compileV128BitMask on i16x8 shape.
+	i16x8BitMaskWeights = [16]byte{1, 0, 2, 0, 4, 0, 8, 0, 16, 0, 32, 0, 64, 0, 128, 0}
+	// i32x4BitMaskWeights holds the weight of each lane for compileV128BitMask on i32x4 shape.
+	i32x4BitMaskWeights = [16]byte{1, 0, 0, 0, 2, 0, 0, 0, 4, 0, 0, 0, 8, 0, 0, 0}
+)
+
+// compileV128BitMask implements compiler.compileV128BitMask for arm64.
+func (c *arm64Compiler) compileV128BitMask(o *wazeroir.OperationV128BitMask) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	result, err := c.allocateRegister(registerTypeGeneralPurpose)
+	if err != nil {
+		return err
+	}
+
+	if o.Shape == wazeroir.ShapeI64x2 {
+		// "result = (v[0] >> 63) + ((v[1] >> 63) << 1)"
+		c.assembler.CompileVectorRegisterToRegister(arm64.VUMOV, v.register, result, arm64.VectorArrangementD, 0)
+		c.assembler.CompileConstToRegister(arm64.LSR, 63, result)
+		c.assembler.CompileVectorRegisterToRegister(arm64.VUMOV, v.register, arm64ReservedRegisterForTemporary,
+			arm64.VectorArrangementD, 1)
+		c.assembler.CompileConstToRegister(arm64.LSR, 63, arm64ReservedRegisterForTemporary)
+		c.assembler.CompileLeftShiftedRegisterToRegister(arm64.ADD, arm64ReservedRegisterForTemporary, 1, result, result)
+	} else {
+		tmp, err := c.allocateRegister(registerTypeVector)
+		if err != nil {
+			return err
+		}
+
+		var arr, elementArr arm64.VectorArrangement
+		var shift int64
+		var weights []byte
+		switch o.Shape {
+		case wazeroir.ShapeI8x16:
+			arr, elementArr, shift, weights = arm64.VectorArrangement16B, arm64.VectorArrangementH, 7, i8x16BitMaskWeights[:]
+		case wazeroir.ShapeI16x8:
+			arr, elementArr, shift, weights = arm64.VectorArrangement8H, arm64.VectorArrangementH, 15, i16x8BitMaskWeights[:]
+		case wazeroir.ShapeI32x4:
+			arr, elementArr, shift, weights = arm64.VectorArrangement4S, arm64.VectorArrangementS, 31, i32x4BitMaskWeights[:]
+		}
+
+		// Set all the bits on each lane whose highest bit is set, and then take the bitwise AND with the weights
+		// so that each lane has its own bit of the mask.
+		c.assembler.CompileVectorRegisterToVectorRegisterWithConst(arm64.VSSHR, v.register, v.register, arr, shift)
+		c.compileLoadStaticV128ToRegister(weights, tmp)
+		c.assembler.CompileTwoVectorRegistersToVectorRegister(arm64.VAND, v.register, tmp, v.register,
+			arm64.VectorArrangement16B)
+
+		if o.Shape == wazeroir.ShapeI8x16 {
+			// Sum the lower and higher 8 lanes separately into the lowest two lanes with the pairwise additions,
+			// so that the lowest 16-bits hold the mask.
+			for i := 0; i < 3; i++ {
+				c.assembler.CompileTwoVectorRegistersToVectorRegister(arm64.VADDP, v.register, v.register, v.register,
+					arm64.VectorArrangement16B)
+			}
+		} else {
+			c.assembler.CompileVectorRegisterToVectorRegister(arm64.VADDV, v.register, v.register, arr,
+				arm64.VectorIndexNone, arm64.VectorIndexNone)
+		}
+		c.assembler.CompileVectorRegisterToRegister(arm64.VUMOV, v.register, result, elementArr, 0)
+	}
+
+	c.markRegisterUnused(v.register)
+	c.pushRuntimeValueLocationOnRegister(result, runtimeValueTypeI32)
+	return nil
+}
+
+// compileV128And implements compiler.compileV128And for arm64.
+func (c *arm64Compiler) compileV128And(*wazeroir.OperationV128And) error {
+	return c.compileV128x2BinOp(arm64.VAND, arm64.VectorArrangement16B)
+}
+
+// compileV128Not implements compiler.compileV128Not for arm64.
+func (c *arm64Compiler) compileV128Not(*wazeroir.OperationV128Not) error {
+	return c.compileV128UniOp(arm64.VNOT, arm64.VectorArrangement16B)
+}
+
+// compileV128Or implements compiler.compileV128Or for arm64.
+func (c *arm64Compiler) compileV128Or(*wazeroir.OperationV128Or) error {
+	return c.compileV128x2BinOp(arm64.VORR, arm64.VectorArrangement16B)
+}
+
+// compileV128Xor implements compiler.compileV128Xor for arm64.
+func (c *arm64Compiler) compileV128Xor(*wazeroir.OperationV128Xor) error {
+	return c.compileV128x2BinOp(arm64.VEOR, arm64.VectorArrangement16B)
+}
+
+// compileV128Bitselect implements compiler.compileV128Bitselect for arm64.
+func (c *arm64Compiler) compileV128Bitselect(*wazeroir.OperationV128Bitselect) error {
+	selector, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	// "x2 = (x2 & ^selector) | (x1 & selector)"
+	c.assembler.CompileTwoVectorRegistersToVectorRegister(arm64.VBIT, x1.register, selector.register, x2.register,
+		arm64.VectorArrangement16B)
+
+	c.markRegisterUnused(x1.register, selector.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x2.register)
+	return nil
+}
+
+// compileV128AndNot implements compiler.compileV128AndNot for arm64.
+func (c *arm64Compiler) compileV128AndNot(*wazeroir.OperationV128AndNot) error {
+	return c.compileV128x2BinOp(arm64.VBIC, arm64.VectorArrangement16B)
+}
+
+// compileV128Shl implements compiler.compileV128Shl for arm64.
+func (c *arm64Compiler) compileV128Shl(o *wazeroir.OperationV128Shl) error {
+	return c.compileV128ShiftImpl(o.Shape, arm64.VUSHL, false)
+}
+
+// compileV128Shr implements compiler.compileV128Shr for arm64.
+func (c *arm64Compiler) compileV128Shr(o *wazeroir.OperationV128Shr) error {
+	inst := asm.Instruction(arm64.VUSHL)
+	if o.Signed {
+		inst = arm64.VSSHL
+	}
+	return c.compileV128ShiftImpl(o.Shape, inst, true)
+}
+
+// compileV128ShiftImpl shifts each lane of the vector by the amount on top of the stack with `inst`.
+// As the vector shift instructions shift right with negative amounts, `rightShift` negates the amount.
+func (c *arm64Compiler) compileV128ShiftImpl(shape wazeroir.Shape, inst asm.Instruction, rightShift bool) error {
+	s, err := c.popValueOnRegister()
+	if err != nil {
+		return err
+	}
+
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	var modulo int64
+	var arr arm64.VectorArrangement
+	switch shape {
+	case wazeroir.ShapeI8x16:
+		modulo, arr = 0x7, arm64.VectorArrangement16B // modulo 8.
+	case wazeroir.ShapeI16x8:
+		modulo, arr = 0xf, arm64.VectorArrangement8H // modulo 16.
+	case wazeroir.ShapeI32x4:
+		modulo, arr = 0x1f, arm64.VectorArrangement4S // modulo 32.
+	case wazeroir.ShapeI64x2:
+		modulo, arr = 0x3f, arm64.VectorArrangement2D // modulo 64.
+	}
+
+	// "arm64ReservedRegisterForTemporary = s.register & modulo"
+	c.assembler.CompileConstToRegister(arm64.MOVD, modulo, arm64ReservedRegisterForTemporary)
+	c.assembler.CompileTwoRegistersToRegister(arm64.AND, s.register, arm64ReservedRegisterForTemporary,
+		arm64ReservedRegisterForTemporary)
+
+	if rightShift {
+		c.assembler.CompileRegisterToRegister(arm64.NEG, arm64ReservedRegisterForTemporary,
+			arm64ReservedRegisterForTemporary)
+	}
+
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	// Copy the shift amount into all the lanes, and shift each lane of v by the corresponding lane of tmp.
+	c.assembler.CompileRegisterToVectorRegister(arm64.VDUP, arm64ReservedRegisterForTemporary, tmp, arr,
+		arm64.VectorIndexNone)
+	c.assembler.CompileTwoVectorRegistersToVectorRegister(inst, v.register, tmp, v.register, arr)
+
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+// compileV128Cmp implements compiler.compileV128Cmp for arm64.
+func (c *arm64Compiler) compileV128Cmp(o *wazeroir.OperationV128Cmp) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	var arr arm64.VectorArrangement
+	switch {
+	case o.Type <= wazeroir.V128CmpTypeI8x16GeU:
+		arr = arm64.VectorArrangement16B
+	case o.Type <= wazeroir.V128CmpTypeI16x8GeU:
+		arr = arm64.VectorArrangement8H
+	case o.Type <= wazeroir.V128CmpTypeI32x4GeU:
+		arr = arm64.VectorArrangement4S
+	case o.Type <= wazeroir.V128CmpTypeI64x2GeS:
+		arr = arm64.VectorArrangement2D
+	case o.Type <= wazeroir.V128CmpTypeF32x4Ge:
+		arr = arm64.VectorArrangement4S
+	default:
+		arr = arm64.VectorArrangement2D
+	}
+
+	// "lt" and "le" are computed with "gt" and "ge" respectively by swapping the operands.
+	var inst asm.Instruction
+	var swap, not bool
+	switch o.Type {
+	case wazeroir.V128CmpTypeI8x16Eq, wazeroir.V128CmpTypeI16x8Eq, wazeroir.V128CmpTypeI32x4Eq, wazeroir.V128CmpTypeI64x2Eq:
+		inst = arm64.VCMEQ
+	case wazeroir.V128CmpTypeI8x16Ne, wazeroir.V128CmpTypeI16x8Ne, wazeroir.V128CmpTypeI32x4Ne, wazeroir.V128CmpTypeI64x2Ne:
+		inst, not = arm64.VCMEQ, true
+	case wazeroir.V128CmpTypeI8x16LtS, wazeroir.V128CmpTypeI16x8LtS, wazeroir.V128CmpTypeI32x4LtS, wazeroir.V128CmpTypeI64x2LtS:
+		inst, swap = arm64.VCMGT, true
+	case wazeroir.V128CmpTypeI8x16LtU, wazeroir.V128CmpTypeI16x8LtU, wazeroir.V128CmpTypeI32x4LtU:
+		inst, swap = arm64.VCMHI, true
+	case wazeroir.V128CmpTypeI8x16GtS, wazeroir.V128CmpTypeI16x8GtS, wazeroir.V128CmpTypeI32x4GtS, wazeroir.V128CmpTypeI64x2GtS:
+		inst = arm64.VCMGT
+	case wazeroir.V128CmpTypeI8x16GtU, wazeroir.V128CmpTypeI16x8GtU, wazeroir.V128CmpTypeI32x4GtU:
+		inst = arm64.VCMHI
+	case wazeroir.V128CmpTypeI8x16LeS, wazeroir.V128CmpTypeI16x8LeS, wazeroir.V128CmpTypeI32x4LeS, wazeroir.V128CmpTypeI64x2LeS:
+		inst, swap = arm64.VCMGE, true
+	case wazeroir.V128CmpTypeI8x16LeU, wazeroir.V128CmpTypeI16x8LeU, wazeroir.V128CmpTypeI32x4LeU:
+		inst, swap = arm64.VCMHS, true
+	case wazeroir.V128CmpTypeI8x16GeS, wazeroir.V128CmpTypeI16x8GeS, wazeroir.V128CmpTypeI32x4GeS, wazeroir.V128CmpTypeI64x2GeS:
+		inst = arm64.VCMGE
+	case wazeroir.V128CmpTypeI8x16GeU, wazeroir.V128CmpTypeI16x8GeU, wazeroir.V128CmpTypeI32x4GeU:
+		inst = arm64.VCMHS
+	case wazeroir.V128CmpTypeF32x4Eq, wazeroir.V128CmpTypeF64x2Eq:
+		inst = arm64.VFCMEQ
+	case wazeroir.V128CmpTypeF32x4Ne, wazeroir.V128CmpTypeF64x2Ne:
+		inst, not = arm64.VFCMEQ, true
+	case wazeroir.V128CmpTypeF32x4Lt, wazeroir.V128CmpTypeF64x2Lt:
+		inst, swap = arm64.VFCMGT, true
+	case wazeroir.V128CmpTypeF32x4Gt, wazeroir.V128CmpTypeF64x2Gt:
+		inst = arm64.VFCMGT
+	case wazeroir.V128CmpTypeF32x4Le, wazeroir.V128CmpTypeF64x2Le:
+		inst, swap = arm64.VFCMGE, true
+	case wazeroir.V128CmpTypeF32x4Ge, wazeroir.V128CmpTypeF64x2Ge:
+		inst = arm64.VFCMGE
+	}
+
+	if swap {
+		c.assembler.CompileTwoVectorRegistersToVectorRegister(inst, x2.register, x1.register, x1.register, arr)
+	} else {
+		c.assembler.CompileTwoVectorRegistersToVectorRegister(inst, x1.register, x2.register, x1.register, arr)
+	}
+
+	if not {
+		c.assembler.CompileVectorRegisterToVectorRegister(arm64.VNOT, x1.register, x1.register,
+			arm64.VectorArrangement16B, arm64.VectorIndexNone, arm64.VectorIndexNone)
+	}
+
+	c.markRegisterUnused(x2.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128AddSat implements compiler.compileV128AddSat for arm64.
+func (c *arm64Compiler) compileV128AddSat(o *wazeroir.OperationV128AddSat) error {
+	inst := asm.Instruction(arm64.VUQADD)
+	if o.Signed {
+		inst = arm64.VSQADD
+	}
+	return c.compileV128x2BinOp(inst, defaultArrangementForShape(o.Shape))
+}
+
+// compileV128SubSat implements compiler.compileV128SubSat for arm64.
+func (c *arm64Compiler) compileV128SubSat(o *wazeroir.OperationV128SubSat) error {
+	inst := asm.Instruction(arm64.VUQSUB)
+	if o.Signed {
+		inst = arm64.VSQSUB
+	}
+	return c.compileV128x2BinOp(inst, defaultArrangementForShape(o.Shape))
+}
+
+// compileV128Mul implements compiler.compileV128Mul for arm64.
+func (c *arm64Compiler) compileV128Mul(o *wazeroir.OperationV128Mul) error {
+	switch o.Shape {
+	case wazeroir.ShapeI8x16, wazeroir.ShapeI16x8, wazeroir.ShapeI32x4:
+		return c.compileV128x2BinOp(arm64.VMUL, defaultArrangementForShape(o.Shape))
+	case wazeroir.ShapeF32x4, wazeroir.ShapeF64x2:
+		return c.compileV128x2BinOp(arm64.VFMUL, defaultArrangementForShape(o.Shape))
+	default:
+		return c.compileV128MulI64x2()
+	}
+}
+
+// compileV128MulI64x2 multiplies the 64-bit lanes of the two vectors on top of the stack.
+// There's no vector instruction for that, so we multiply each lane on general purpose registers.
+func (c *arm64Compiler) compileV128MulI64x2() error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := c.allocateRegister(registerTypeGeneralPurpose)
+	if err != nil {
+		return err
+	}
+
+	for i := arm64.VectorIndex(0); i < 2; i++ {
+		// "x1[i] = x1[i] * x2[i]"
+		c.assembler.CompileVectorRegisterToRegister(arm64.VUMOV, x1.register, arm64ReservedRegisterForTemporary,
+			arm64.VectorArrangementD, i)
+		c.assembler.CompileVectorRegisterToRegister(arm64.VUMOV, x2.register, tmp, arm64.VectorArrangementD, i)
+		c.assembler.CompileRegisterToRegister(arm64.MUL, arm64ReservedRegisterForTemporary, tmp)
+		c.assembler.CompileRegisterToVectorRegister(arm64.VMOV, tmp, x1.register, arm64.VectorArrangementD, i)
+	}
+
+	c.markRegisterUnused(x2.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128Div implements compiler.compileV128Div for arm64.
+func (c *arm64Compiler) compileV128Div(o *wazeroir.OperationV128Div) error {
+	return c.compileV128x2BinOp(arm64.VFDIV, defaultArrangementForShape(o.Shape))
+}
+
+// compileV128Neg implements compiler.compileV128Neg for arm64.
+func (c *arm64Compiler) compileV128Neg(o *wazeroir.OperationV128Neg) error {
+	inst := asm.Instruction(arm64.VNEG)
+	if o.Shape == wazeroir.ShapeF32x4 || o.Shape == wazeroir.ShapeF64x2 {
+		inst = arm64.VFNEG
+	}
+	return c.compileV128UniOp(inst, defaultArrangementForShape(o.Shape))
+}
+
+// compileV128Sqrt implements compiler.compileV128Sqrt for arm64.
+func (c *arm64Compiler) compileV128Sqrt(o *wazeroir.OperationV128Sqrt) error {
+	return c.compileV128UniOp(arm64.VFSQRT, defaultArrangementForShape(o.Shape))
+}
+
+// compileV128Abs implements compiler.compileV128Abs for arm64.
+func (c *arm64Compiler) compileV128Abs(o *wazeroir.OperationV128Abs) error {
+	inst := asm.Instruction(arm64.VABS)
+	if o.Shape == wazeroir.ShapeF32x4 || o.Shape == wazeroir.ShapeF64x2 {
+		inst = arm64.VFABS
+	}
+	return c.compileV128UniOp(inst, defaultArrangementForShape(o.Shape))
+}
+
+// compileV128Popcnt implements compiler.compileV128Popcnt for arm64.
+func (c *arm64Compiler) compileV128Popcnt(*wazeroir.OperationV128Popcnt) error {
+	return c.compileV128UniOp(arm64.VCNT, arm64.VectorArrangement16B)
+}
+
+// compileV128Min implements compiler.compileV128Min for arm64.
+func (c *arm64Compiler) compileV128Min(o *wazeroir.OperationV128Min) error {
+	var inst asm.Instruction
+	switch o.Shape {
+	case wazeroir.ShapeI8x16, wazeroir.ShapeI16x8, wazeroir.ShapeI32x4:
+		if o.Signed {
+			inst = arm64.VSMIN
+		} else {
+			inst = arm64.VUMIN
+		}
+	case wazeroir.ShapeF32x4, wazeroir.ShapeF64x2:
+		// FMIN propagates NaN and treats -0 as less than +0, which matches the semantics of Wasm.
+		inst = arm64.VFMIN
+	}
+	return c.compileV128x2BinOp(inst, defaultArrangementForShape(o.Shape))
+}
+
+// compileV128Max implements compiler.compileV128Max for arm64.
+func (c *arm64Compiler) compileV128Max(o *wazeroir.OperationV128Max) error {
+	var inst asm.Instruction
+	switch o.Shape {
+	case wazeroir.ShapeI8x16, wazeroir.ShapeI16x8, wazeroir.ShapeI32x4:
+		if o.Signed {
+			inst = arm64.VSMAX
+		} else {
+			inst = arm64.VUMAX
+		}
+	case wazeroir.ShapeF32x4, wazeroir.ShapeF64x2:
+		// FMAX propagates NaN and treats +0 as greater than -0, which matches the semantics of Wasm.
+		inst = arm64.VFMAX
+	}
+	return c.compileV128x2BinOp(inst, defaultArrangementForShape(o.Shape))
+}
+
+// compileV128AvgrU implements compiler.compileV128AvgrU for arm64.
+func (c *arm64Compiler) compileV128AvgrU(o *wazeroir.OperationV128AvgrU) error {
+	return c.compileV128x2BinOp(arm64.VURHADD, defaultArrangementForShape(o.Shape))
+}
+
+// compileV128Pmin implements compiler.compileV128Pmin for arm64.
+func (c *arm64Compiler) compileV128Pmin(o *wazeroir.OperationV128Pmin) error {
+	return c.compileV128PseudoMinOrMax(defaultArrangementForShape(o.Shape), false)
+}
+
+// compileV128Pmax implements compiler.compileV128Pmax for arm64.
+func (c *arm64Compiler) compileV128Pmax(o *wazeroir.OperationV128Pmax) error {
+	return c.compileV128PseudoMinOrMax(defaultArrangementForShape(o.Shape), true)
+}
+
+// compileV128PseudoMinOrMax implements compileV128Pmax and compileV128Pmin.
+func (c *arm64Compiler) compileV128PseudoMinOrMax(arr arm64.VectorArrangement, max bool) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	// pmin: "x1 = (x2 < x1) ? x2 : x1", and pmax: "x1 = (x1 < x2) ? x2 : x1".
+	if max {
+		c.assembler.CompileTwoVectorRegistersToVectorRegister(arm64.VFCMGT, x2.register, x1.register, tmp, arr)
+	} else {
+		c.assembler.CompileTwoVectorRegistersToVectorRegister(arm64.VFCMGT, x1.register, x2.register, tmp, arr)
+	}
+	// "x1 = (x1 & ^tmp) | (x2 & tmp)"
+	c.assembler.CompileTwoVectorRegistersToVectorRegister(arm64.VBIT, x2.register, tmp, x1.register,
+		arm64.VectorArrangement16B)
+
+	c.markRegisterUnused(x2.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128Ceil implements compiler.compileV128Ceil for arm64.
+func (c *arm64Compiler) compileV128Ceil(o *wazeroir.OperationV128Ceil) error {
+	return c.compileV128UniOp(arm64.VFRINTP, defaultArrangementForShape(o.Shape))
+}
+
+// compileV128Floor implements compiler.compileV128Floor for arm64.
+func (c *arm64Compiler) compileV128Floor(o *wazeroir.OperationV128Floor) error {
+	return c.compileV128UniOp(arm64.VFRINTM, defaultArrangementForShape(o.Shape))
+}
+
+// compileV128Trunc implements compiler.compileV128Trunc for arm64.
+func (c *arm64Compiler) compileV128Trunc(o *wazeroir.OperationV128Trunc) error {
+	return c.compileV128UniOp(arm64.VFRINTZ, defaultArrangementForShape(o.Shape))
+}
+
+// compileV128Nearest implements compiler.compileV128Nearest for arm64.
+func (c *arm64Compiler) compileV128Nearest(o *wazeroir.OperationV128Nearest) error {
+	return c.compileV128UniOp(arm64.VFRINTN, defaultArrangementForShape(o.Shape))
+}
+
+// halfArrangementForShape returns the arrangement of the lower (useLow=true) or higher half of the lanes of `shape`,
+// which is used by the lengthening instructions such as SSHLL and SMULL.
+func halfArrangementForShape(shape wazeroir.Shape, useLow bool) (arr arm64.VectorArrangement) {
+	switch shape {
+	case wazeroir.ShapeI8x16:
+		arr = arm64.VectorArrangement16B
+		if useLow {
+			arr = arm64.VectorArrangement8B
+		}
+	case wazeroir.ShapeI16x8:
+		arr = arm64.VectorArrangement8H
+		if useLow {
+			arr = arm64.VectorArrangement4H
+		}
+	case wazeroir.ShapeI32x4:
+		arr = arm64.VectorArrangement4S
+		if useLow {
+			arr = arm64.VectorArrangement2S
+		}
+	}
+	return
+}
+
+// compileV128Extend implements compiler.compileV128Extend for arm64.
+func (c *arm64Compiler) compileV128Extend(o *wazeroir.OperationV128Extend) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	inst := asm.Instruction(arm64.VUSHLL)
+	if o.Signed {
+		inst = arm64.VSSHLL
+	}
+	// Lengthening shift by zero is the sign (or zero) extension of the lanes.
+	c.assembler.CompileVectorRegisterToVectorRegisterWithConst(inst, v.register, v.register,
+		halfArrangementForShape(o.OriginShape, o.UseLow), 0)
+
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+// compileV128ExtMul implements compiler.compileV128ExtMul for arm64.
+func (c *arm64Compiler) compileV128ExtMul(o *wazeroir.OperationV128ExtMul) error {
+	inst := asm.Instruction(arm64.VUMULL)
+	if o.Signed {
+		inst = arm64.VSMULL
+	}
+	return c.compileV128x2BinOp(inst, halfArrangementForShape(o.OriginShape, o.UseLow))
+}
+
+// compileV128Q15mulrSatS implements compiler.compileV128Q15mulrSatS for arm64.
+func (c *arm64Compiler) compileV128Q15mulrSatS(*wazeroir.OperationV128Q15mulrSatS) error {
+	return c.compileV128x2BinOp(arm64.VSQRDMULH, arm64.VectorArrangement8H)
+}
+
+// compileV128ExtAddPairwise implements compiler.compileV128ExtAddPairwise for arm64.
+func (c *arm64Compiler) compileV128ExtAddPairwise(o *wazeroir.OperationV128ExtAddPairwise) error {
+	inst := asm.Instruction(arm64.VUADDLP)
+	if o.Signed {
+		inst = arm64.VSADDLP
+	}
+	return c.compileV128UniOp(inst, defaultArrangementForShape(o.OriginShape))
+}
+
+// compileV128FloatPromote implements compiler.compileV128FloatPromote for arm64.
+func (c *arm64Compiler) compileV128FloatPromote(*wazeroir.OperationV128FloatPromote) error {
+	return c.compileV128UniOp(arm64.VFCVTL, arm64.VectorArrangement2S)
+}
+
+// compileV128FloatDemote implements compiler.compileV128FloatDemote for arm64.
+func (c *arm64Compiler) compileV128FloatDemote(*wazeroir.OperationV128FloatDemote) error {
+	// FCVTN (not FCVTN2) clears the higher 64-bits of the destination.
+	return c.compileV128UniOp(arm64.VFCVTN, arm64.VectorArrangement2S)
+}
+
+// compileV128FConvertFromI implements compiler.compileV128FConvertFromI for arm64.
+func (c *arm64Compiler) compileV128FConvertFromI(o *wazeroir.OperationV128FConvertFromI) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	inst := asm.Instruction(arm64.VUCVTF)
+	if o.Signed {
+		inst = arm64.VSCVTF
+	}
+
+	arr := arm64.VectorArrangement4S
+	if o.DestinationShape == wazeroir.ShapeF64x2 {
+		// Extend the lower two 32-bit lanes into 64-bit lanes first.
+		extend := asm.Instruction(arm64.VUSHLL)
+		if o.Signed {
+			extend = arm64.VSSHLL
+		}
+		c.assembler.CompileVectorRegisterToVectorRegisterWithConst(extend, v.register, v.register,
+			arm64.VectorArrangement2S, 0)
+		arr = arm64.VectorArrangement2D
+	}
+
+	c.assembler.CompileVectorRegisterToVectorRegister(inst, v.register, v.register, arr,
+		arm64.VectorIndexNone, arm64.VectorIndexNone)
+
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+// compileV128Dot implements compiler.compileV128Dot for arm64.
+func (c *arm64Compiler) compileV128Dot(*wazeroir.OperationV128Dot) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	tmp, err := c.allocateRegister(registerTypeVector)
+	if err != nil {
+		return err
+	}
+
+	// Multiply the lower and higher four lanes into 32-bit lanes, and then add the adjacent pairs.
+	c.assembler.CompileTwoVectorRegistersToVectorRegister(arm64.VSMULL, x1.register, x2.register, tmp,
+		arm64.VectorArrangement4H)
+	c.assembler.CompileTwoVectorRegistersToVectorRegister(arm64.VSMULL, x1.register, x2.register, x1.register,
+		arm64.VectorArrangement8H)
+	c.assembler.CompileTwoVectorRegistersToVectorRegister(arm64.VADDP, tmp, x1.register, x1.register,
+		arm64.VectorArrangement4S)
+
+	c.markRegisterUnused(x2.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128Narrow implements compiler.compileV128Narrow for arm64.
+func (c *arm64Compiler) compileV128Narrow(o *wazeroir.OperationV128Narrow) error {
+	x2, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	x1, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	// Wasm's unsigned narrowing saturates signed integers into unsigned ones.
+	inst := asm.Instruction(arm64.VSQXTUN)
+	if o.Signed {
+		inst = arm64.VSQXTN
+	}
+
+	var lo, hi arm64.VectorArrangement
+	switch o.OriginShape {
+	case wazeroir.ShapeI16x8:
+		lo, hi = arm64.VectorArrangement8B, arm64.VectorArrangement16B
+	case wazeroir.ShapeI32x4:
+		lo, hi = arm64.VectorArrangement4H, arm64.VectorArrangement8H
+	}
+
+	// Narrow x1 into the lower half of x1, and then x2 into the higher half of x1.
+	c.assembler.CompileVectorRegisterToVectorRegister(inst, x1.register, x1.register, lo,
+		arm64.VectorIndexNone, arm64.VectorIndexNone)
+	c.assembler.CompileVectorRegisterToVectorRegister(inst, x2.register, x1.register, hi,
+		arm64.VectorIndexNone, arm64.VectorIndexNone)
+
+	c.markRegisterUnused(x2.register)
+	c.pushVectorRuntimeValueLocationOnRegister(x1.register)
+	return nil
+}
+
+// compileV128ITruncSatFromF implements compiler.compileV128ITruncSatFromF for arm64.
+func (c *arm64Compiler) compileV128ITruncSatFromF(o *wazeroir.OperationV128ITruncSatFromF) error {
+	v, err := c.popV128()
+	if err != nil {
+		return err
+	}
+
+	// The conversion instructions saturate the out of range values, and convert NaN into zero.
+	inst := asm.Instruction(arm64.VFCVTZU)
+	if o.Signed {
+		inst = arm64.VFCVTZS
+	}
+	c.assembler.CompileVectorRegisterToVectorRegister(inst, v.register, v.register,
+		defaultArrangementForShape(o.OriginShape), arm64.VectorIndexNone, arm64.VectorIndexNone,
+	)
+
+	if o.OriginShape == wazeroir.ShapeF64x2 {
+		// Narrow the 64-bit lanes into the lower 32-bit lanes with saturation, and clear the higher ones.
+		narrow := asm.Instruction(arm64.VUQXTN)
+		if o.Signed {
+			narrow = arm64.VSQXTN
+		}
+		c.assembler.CompileVectorRegisterToVectorRegister(narrow, v.register, v.register,
+			arm64.VectorArrangement2S, arm64.VectorIndexNone, arm64.VectorIndexNone)
+	}
+
+	c.pushVectorRuntimeValueLocationOnRegister(v.register)
+	return nil
+}
+
+// defaultArrangementForShape returns the arrangement which has the same number and size of lanes as `shape`.
+func defaultArrangementForShape(shape wazeroir.Shape) (arr arm64.VectorArrangement) {
+	switch shape {
+	case wazeroir.ShapeI8x16:
+		arr = arm64.VectorArrangement16B
+	case wazeroir.ShapeI16x8:
+		arr = arm64.VectorArrangement8H
+	case wazeroir.ShapeI32x4:
+		arr = arm64.VectorArrangement4S
+	case wazeroir.ShapeI64x2:
+		arr = arm64.VectorArrangement2D
+	case wazeroir.ShapeF32x4:
+		arr = arm64.VectorArrangement4S
+	case wazeroir.ShapeF64x2:
+		arr = arm64.VectorArrangement2D
+	}
+	return
+}
@@ -2,16 +2,19 @@ package interpreter
 
 import (
 	"context"
+	"encoding/binary"
+	"errors"
 	"fmt"
 	"math"
 	"math/bits"
 	"reflect"
+	"strconv"
 	"strings"
 	"sync"
 	"unsafe"
 
+	"github.com/tetratelabs/wazero/api"
 	"github.com/tetratelabs/wazero/experimental"
-	"github.com/tetratelabs/wazero/internal/buildoptions"
 	"github.com/tetratelabs/wazero/internal/moremath"
 	"github.com/tetratelabs/wazero/internal/wasm"
 	"github.com/tetratelabs/wazero/internal/wasmdebug"
@@ -19,18 +22,27 @@ import (
 	"github.com/tetratelabs/wazero/internal/wazeroir"
 )
 
-var callStackCeiling = buildoptions.CallStackCeiling
+// MaxCallStackCeiling caps wasm.StackLimits CallStackCeiling. Each function call nests Go calls taking a few KiB of
+// the goroutine stack, which Go limits to 1 GB on 64-bit platforms and 250 MB on 32-bit ones. As Go doubles the stack
+// to grow it, only half of that is usable, which fits about 150000 and 25000 calls. A higher ceiling would crash the
+// process with "fatal error: stack overflow" instead of raising wasmruntime.ErrRuntimeCallStackOverflow.
+const MaxCallStackCeiling = 15_000 + (strconv.IntSize-32)/32*85_000
 
 // engine is an interpreter implementation of wasm.Engine
 type engine struct {
 	enabledFeatures wasm.Features
+	stackLimits     wasm.StackLimits
 	codes           map[wasm.ModuleID][]*code // guarded by mutex.
 	mux             sync.RWMutex
 }
 
-func NewEngine(enabledFeatures wasm.Features) wasm.Engine {
+func NewEngine(enabledFeatures wasm.Features, stackLimits wasm.StackLimits) wasm.Engine {
+	if stackLimits.CallStackCeiling > MaxCallStackCeiling {
+		stackLimits.CallStackCeiling = MaxCallStackCeiling
+	}
 	return &engine{
 		enabledFeatures: enabledFeatures,
+		stackLimits:     stackLimits,
 		codes:           map[wasm.ModuleID][]*code{},
 	}
 }
@@ -71,6 +83,9 @@ type moduleEngine struct {
 	// parentEngine holds *engine from which this module engine is created from.
 	parentEngine          *engine
 	importedFunctionCount uint32
+
+	// consumesFuel is true if the module was compiled with wasm.Module FuelCosts.
+	consumesFuel bool
 }
 
 // callEngine holds context per moduleEngine.Call, and shared across all the
@@ -82,12 +97,27 @@ type callEngine struct {
 
 	// frames are the function call stack.
 	frames []*callFrame
+
+	// exceptions holds the exceptions of the handlers being executed, indexed by the handles pushed on entering them.
+	exceptions []caughtException
+
+	// callStackCeiling and valueStackCeiling are the wasm.StackLimits of the parent engine.
+	callStackCeiling, valueStackCeiling int
+
+	// fuel is consumed by wazeroir.OperationConsumeFuel, or nil if the module of the called function doesn't consume
+	// fuel. See wasm.FuelOf.
+	fuel *experimental.Fuel
 }
 
 func (me *moduleEngine) newCallEngine() *callEngine {
-	return &callEngine{}
+	return &callEngine{
+		callStackCeiling:  me.parentEngine.stackLimits.CallStackCeiling,
+		valueStackCeiling: me.parentEngine.stackLimits.ValueStackCeiling,
+	}
 }
 
+// pushValue pushes the value without checking valueStackCeiling, which callNativeFunc checks for the whole function
+// on entering it.
 func (ce *callEngine) pushValue(v uint64) {
 	ce.stack = append(ce.stack, v)
 }
@@ -138,7 +168,7 @@ func (ce *callEngine) drop(r *wazeroir.InclusiveRange) {
 }
 
 func (ce *callEngine) pushFrame(frame *callFrame) {
-	if callStackCeiling <= len(ce.frames) {
+	if ce.callStackCeiling <= len(ce.frames) {
 		panic(wasmruntime.ErrRuntimeCallStackOverflow)
 	}
 	ce.frames = append(ce.frames, frame)
@@ -158,17 +188,59 @@ type callFrame struct {
 	pc uint64
 	// f is the compiled function used in this function frame.
 	f *function
+	// base is the index of the first param of this frame in callEngine.stack.
+	base int
 }
 
 type code struct {
-	body   []*interpreterOp
-	hostFn *reflect.Value
+	body      []*interpreterOp
+	tryBlocks []*tryBlock
+	hostFn    *reflect.Value
+	// maxStackHeight is the same as wazeroir.CompilationResult MaxStackHeight.
+	maxStackHeight int
 }
 
 type function struct {
-	source *wasm.FunctionInstance
-	body   []*interpreterOp
-	hostFn *reflect.Value
+	source    *wasm.FunctionInstance
+	body      []*interpreterOp
+	tryBlocks []*tryBlock
+	hostFn    *reflect.Value
+	// maxStackHeight is the same as code.maxStackHeight.
+	maxStackHeight int
+	// hostFrame is the call frame of a host function, which is never modified, so it is shared across calls.
+	hostFrame *callFrame
+}
+
+// tryBlock is the protected region of a try block in code.body, lowered from wazeroir.OperationTry.
+type tryBlock struct {
+	// begin and end are the inclusive and exclusive indexes of code.body protected by this try block.
+	begin, end uint64
+	// stackHeight is the same as wazeroir.OperationTry StackHeight.
+	stackHeight int
+	// catches are the handlers of this try block in the order of appearance.
+	catches []*catchHandler
+	// next is the try block which handles the exceptions not caught by catches, or nil if they propagate to the
+	// caller.
+	next *tryBlock
+}
+
+// catchHandler is a handler of tryBlock, lowered from wazeroir.OperationCatch.
+type catchHandler struct {
+	// pc is the index of code.body where the handler begins.
+	pc       uint64
+	tagIndex uint32
+	catchAll bool
+}
+
+// tryBlockAt returns the innermost try block protecting the given pc, or nil if there's none.
+func (f *function) tryBlockAt(pc uint64) *tryBlock {
+	// Nested try blocks come later than their enclosing ones, so search backwards.
+	for i := len(f.tryBlocks) - 1; i >= 0; i-- {
+		if tb := f.tryBlocks[i]; tb.begin <= pc && pc < tb.end {
+			return tb
+		}
+	}
+	return nil
 }
 
 // functionFromUintptr resurrects the original *function from the given uintptr
@@ -184,11 +256,17 @@ func functionFromUintptr(ptr uintptr) *function {
 }
 
 func (c *code) instantiate(f *wasm.FunctionInstance) *function {
-	return &function{
-		source: f,
-		body:   c.body,
-		hostFn: c.hostFn,
+	fn := &function{
+		source:         f,
+		body:           c.body,
+		tryBlocks:      c.tryBlocks,
+		hostFn:         c.hostFn,
+		maxStackHeight: c.maxStackHeight,
+	}
+	if c.hostFn != nil {
+		fn.hostFrame = &callFrame{f: fn}
 	}
+	return fn
 }
 
 // interpreterOp is the compilation (engine.lowerIR) result of a wazeroir.Operation.
@@ -246,6 +324,7 @@ func (e *engine) NewModuleEngine(name string, module *wasm.Module, importedFunct
 		name:                  name,
 		parentEngine:          e,
 		importedFunctionCount: imported,
+		consumesFuel:          module.FuelCosts != nil,
 	}
 
 	for _, f := range importedFunctions {
@@ -282,9 +361,12 @@ func (e *engine) NewModuleEngine(name string, module *wasm.Module, importedFunct
 // lowerIR lowers the wazeroir operations to engine friendly struct.
 func (e *engine) lowerIR(ir *wazeroir.CompilationResult) (*code, error) {
 	ops := ir.Operations
-	ret := &code{}
+	ret := &code{maxStackHeight: ir.MaxStackHeight}
 	labelAddress := map[string]uint64{}
 	onLabelAddressResolved := map[string][]func(addr uint64){}
+	tryBlocks := map[uint32]*tryBlock{}
+	// protectedRegions is the number of the try blocks whose protected regions contain the current operation.
+	protectedRegions := 0
 	for _, original := range ops {
 		op := &interpreterOp{kind: original.Kind()}
 		switch o := original.(type) {
@@ -374,14 +456,24 @@ func (e *engine) lowerIR(ir *wazeroir.CompilationResult) (*code, error) {
 		case *wazeroir.OperationCall:
 			op.us = make([]uint64, 1)
 			op.us = []uint64{uint64(o.FunctionIndex)}
+			// Exceptions raised by the callee must be handled in this frame.
+			op.b3 = protectedRegions > 0
 		case *wazeroir.OperationCallIndirect:
 			op.us = make([]uint64, 2)
 			op.us[0] = uint64(o.TypeIndex)
 			op.us[1] = uint64(o.TableIndex)
+			op.b3 = protectedRegions > 0
+		case *wazeroir.OperationTailCall:
+			op.us = []uint64{uint64(o.FunctionIndex)}
+		case *wazeroir.OperationTailCallIndirect:
+			op.us = make([]uint64, 2)
+			op.us[0] = uint64(o.TypeIndex)
+			op.us[1] = uint64(o.TableIndex)
 		case *wazeroir.OperationDrop:
 			op.rs = make([]*wazeroir.InclusiveRange, 1)
 			op.rs[0] = o.Depth
 		case *wazeroir.OperationSelect:
+			op.b3 = o.IsTargetVector
 		case *wazeroir.OperationPick:
 			op.us = make([]uint64, 1)
 			op.us[0] = uint64(o.Depth)
@@ -398,47 +490,59 @@ func (e *engine) lowerIR(ir *wazeroir.CompilationResult) (*code, error) {
 			op.us[0] = uint64(o.Index)
 		case *wazeroir.OperationLoad:
 			op.b1 = byte(o.Type)
-			op.us = make([]uint64, 2)
+			op.us = make([]uint64, 3)
 			op.us[0] = uint64(o.Arg.Alignment)
 			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
 		case *wazeroir.OperationLoad8:
 			op.b1 = byte(o.Type)
-			op.us = make([]uint64, 2)
+			op.us = make([]uint64, 3)
 			op.us[0] = uint64(o.Arg.Alignment)
 			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
 		case *wazeroir.OperationLoad16:
 			op.b1 = byte(o.Type)
-			op.us = make([]uint64, 2)
+			op.us = make([]uint64, 3)
 			op.us[0] = uint64(o.Arg.Alignment)
 			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
 		case *wazeroir.OperationLoad32:
 			if o.Signed {
 				op.b1 = 1
 			}
-			op.us = make([]uint64, 2)
+			op.us = make([]uint64, 3)
 			op.us[0] = uint64(o.Arg.Alignment)
 			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
 		case *wazeroir.OperationStore:
 			op.b1 = byte(o.Type)
-			op.us = make([]uint64, 2)
+			op.us = make([]uint64, 3)
 			op.us[0] = uint64(o.Arg.Alignment)
 			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
 		case *wazeroir.OperationStore8:
 			op.b1 = byte(o.Type)
-			op.us = make([]uint64, 2)
+			op.us = make([]uint64, 3)
 			op.us[0] = uint64(o.Arg.Alignment)
 			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
 		case *wazeroir.OperationStore16:
 			op.b1 = byte(o.Type)
-			op.us = make([]uint64, 2)
+			op.us = make([]uint64, 3)
 			op.us[0] = uint64(o.Arg.Alignment)
 			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
 		case *wazeroir.OperationStore32:
-			op.us = make([]uint64, 2)
+			op.us = make([]uint64, 3)
 			op.us[0] = uint64(o.Arg.Alignment)
 			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
 		case *wazeroir.OperationMemorySize:
+			op.us = make([]uint64, 1)
+			op.us[0] = uint64(o.MemoryIndex)
 		case *wazeroir.OperationMemoryGrow:
+			op.us = make([]uint64, 1)
+			op.us[0] = uint64(o.MemoryIndex)
 		case *wazeroir.OperationConstI32:
 			op.us = make([]uint64, 1)
 			op.us[0] = uint64(o.Value)
@@ -541,13 +645,19 @@ func (e *engine) lowerIR(ir *wazeroir.CompilationResult) (*code, error) {
 		case *wazeroir.OperationSignExtend32From8, *wazeroir.OperationSignExtend32From16, *wazeroir.OperationSignExtend64From8,
 			*wazeroir.OperationSignExtend64From16, *wazeroir.OperationSignExtend64From32:
 		case *wazeroir.OperationMemoryInit:
-			op.us = make([]uint64, 1)
+			op.us = make([]uint64, 2)
 			op.us[0] = uint64(o.DataIndex)
+			op.us[1] = uint64(o.MemoryIndex)
 		case *wazeroir.OperationDataDrop:
 			op.us = make([]uint64, 1)
 			op.us[0] = uint64(o.DataIndex)
 		case *wazeroir.OperationMemoryCopy:
+			op.us = make([]uint64, 2)
+			op.us[0] = uint64(o.SrcMemoryIndex)
+			op.us[1] = uint64(o.DstMemoryIndex)
 		case *wazeroir.OperationMemoryFill:
+			op.us = make([]uint64, 1)
+			op.us[0] = uint64(o.MemoryIndex)
 		case *wazeroir.OperationTableInit:
 			op.us = make([]uint64, 2)
 			op.us[0] = uint64(o.ElemIndex)
@@ -577,12 +687,212 @@ func (e *engine) lowerIR(ir *wazeroir.CompilationResult) (*code, error) {
 		case *wazeroir.OperationTableFill:
 			op.us = make([]uint64, 1)
 			op.us[0] = uint64(o.TableIndex)
-		case *wazeroir.OperationConstV128:
+		case *wazeroir.OperationV128Const:
 			op.us = make([]uint64, 2)
 			op.us[0] = o.Lo
 			op.us[1] = o.Hi
-		case *wazeroir.OperationAddV128:
-			op.b1 = byte(o.Shape)
+		case *wazeroir.OperationV128Add:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Sub:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Load:
+			op.b1 = o.Type
+			op.us = make([]uint64, 3)
+			op.us[0] = uint64(o.Arg.Alignment)
+			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
+		case *wazeroir.OperationV128LoadLane:
+			op.b1 = o.LaneSize
+			op.b2 = o.LaneIndex
+			op.us = make([]uint64, 3)
+			op.us[0] = uint64(o.Arg.Alignment)
+			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
+		case *wazeroir.OperationV128Store:
+			op.us = make([]uint64, 3)
+			op.us[0] = uint64(o.Arg.Alignment)
+			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
+		case *wazeroir.OperationV128StoreLane:
+			op.b1 = o.LaneSize
+			op.b2 = o.LaneIndex
+			op.us = make([]uint64, 3)
+			op.us[0] = uint64(o.Arg.Alignment)
+			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
+		case *wazeroir.OperationV128ExtractLane:
+			op.b1 = o.Shape
+			op.b2 = o.LaneIndex
+			op.b3 = o.Signed
+		case *wazeroir.OperationV128ReplaceLane:
+			op.b1 = o.Shape
+			op.b2 = o.LaneIndex
+		case *wazeroir.OperationV128Splat:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Shuffle:
+			op.us = make([]uint64, 16)
+			for i, l := range o.Lanes {
+				op.us[i] = uint64(l)
+			}
+		case *wazeroir.OperationV128Swizzle:
+		case *wazeroir.OperationV128AnyTrue:
+		case *wazeroir.OperationV128AllTrue:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128BitMask:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128And:
+		case *wazeroir.OperationV128Not:
+		case *wazeroir.OperationV128Or:
+		case *wazeroir.OperationV128Xor:
+		case *wazeroir.OperationV128Bitselect:
+		case *wazeroir.OperationV128AndNot:
+		case *wazeroir.OperationV128Shl:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Shr:
+			op.b1 = o.Shape
+			op.b3 = o.Signed
+		case *wazeroir.OperationV128Cmp:
+			op.b1 = o.Type
+		case *wazeroir.OperationV128AddSat:
+			op.b1 = o.Shape
+			op.b3 = o.Signed
+		case *wazeroir.OperationV128SubSat:
+			op.b1 = o.Shape
+			op.b3 = o.Signed
+		case *wazeroir.OperationV128Mul:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Div:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Neg:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Sqrt:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Abs:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Popcnt:
+		case *wazeroir.OperationV128Min:
+			op.b1 = o.Shape
+			op.b3 = o.Signed
+		case *wazeroir.OperationV128Max:
+			op.b1 = o.Shape
+			op.b3 = o.Signed
+		case *wazeroir.OperationV128AvgrU:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Pmin:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Pmax:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Ceil:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Floor:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Trunc:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Nearest:
+			op.b1 = o.Shape
+		case *wazeroir.OperationV128Extend:
+			op.b1 = o.OriginShape
+			if o.Signed {
+				op.b2 = 1
+			}
+			op.b3 = o.UseLow
+		case *wazeroir.OperationV128ExtMul:
+			op.b1 = o.OriginShape
+			if o.Signed {
+				op.b2 = 1
+			}
+			op.b3 = o.UseLow
+		case *wazeroir.OperationV128Q15mulrSatS:
+		case *wazeroir.OperationV128ExtAddPairwise:
+			op.b1 = o.OriginShape
+			op.b3 = o.Signed
+		case *wazeroir.OperationV128FloatPromote:
+		case *wazeroir.OperationV128FloatDemote:
+		case *wazeroir.OperationV128FConvertFromI:
+			op.b1 = o.DestinationShape
+			op.b3 = o.Signed
+		case *wazeroir.OperationV128Dot:
+		case *wazeroir.OperationV128Narrow:
+			op.b1 = o.OriginShape
+			op.b3 = o.Signed
+		case *wazeroir.OperationV128ITruncSatFromF:
+			op.b1 = o.OriginShape
+			op.b3 = o.Signed
+		case *wazeroir.OperationAtomicLoad:
+			op.b1 = byte(o.SizeInBytes)
+			op.us = make([]uint64, 3)
+			op.us[0] = uint64(o.Arg.Alignment)
+			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
+		case *wazeroir.OperationAtomicStore:
+			op.b1 = byte(o.SizeInBytes)
+			op.us = make([]uint64, 3)
+			op.us[0] = uint64(o.Arg.Alignment)
+			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
+		case *wazeroir.OperationAtomicRMW:
+			op.b1 = byte(o.SizeInBytes)
+			op.b2 = byte(o.Op)
+			op.us = make([]uint64, 3)
+			op.us[0] = uint64(o.Arg.Alignment)
+			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
+		case *wazeroir.OperationAtomicRMWCmpxchg:
+			op.b1 = byte(o.SizeInBytes)
+			op.us = make([]uint64, 3)
+			op.us[0] = uint64(o.Arg.Alignment)
+			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
+		case *wazeroir.OperationAtomicMemoryWait:
+			op.b1 = 4
+			if o.Type == wazeroir.UnsignedInt64 {
+				op.b1 = 8
+			}
+			op.us = make([]uint64, 3)
+			op.us[0] = uint64(o.Arg.Alignment)
+			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
+		case *wazeroir.OperationAtomicMemoryNotify:
+			op.us = make([]uint64, 3)
+			op.us[0] = uint64(o.Arg.Alignment)
+			op.us[1] = uint64(o.Arg.Offset)
+			op.us[2] = uint64(o.Arg.MemoryIndex)
+		case *wazeroir.OperationAtomicFence:
+		case *wazeroir.OperationTry:
+			tb := &tryBlock{begin: uint64(len(ret.body)), stackHeight: o.StackHeight}
+			if o.Outer != nil {
+				tb.next = tryBlocks[*o.Outer]
+			}
+			tryBlocks[o.FrameID] = tb
+			ret.tryBlocks = append(ret.tryBlocks, tb)
+			protectedRegions++
+			// Like labels, try blocks are only recorded in the table.
+			continue
+		case *wazeroir.OperationCatch:
+			tb := tryBlocks[o.FrameID]
+			if len(tb.catches) == 0 { // The first handler ends the protected region.
+				tb.end = uint64(len(ret.body))
+				protectedRegions--
+			}
+			tb.catches = append(tb.catches, &catchHandler{pc: uint64(len(ret.body)), tagIndex: o.TagIndex, catchAll: o.CatchAll})
+			continue
+		case *wazeroir.OperationDelegate:
+			tb := tryBlocks[o.FrameID]
+			tb.end = uint64(len(ret.body))
+			tb.next = nil
+			if o.Target != nil {
+				tb.next = tryBlocks[*o.Target]
+			}
+			protectedRegions--
+			continue
+		case *wazeroir.OperationThrow:
+			op.us = make([]uint64, 2)
+			op.us[0] = uint64(o.TagIndex)
+			op.us[1] = uint64(ir.Tags[o.TagIndex].ParamNumInUint64)
+		case *wazeroir.OperationRethrow:
+		case *wazeroir.OperationConsumeFuel:
+			op.us = make([]uint64, 1)
+			op.us[0] = o.Cost
 		default:
 			return nil, fmt.Errorf("unreachable: a bug in wazeroir engine")
 		}
@@ -604,6 +914,11 @@ func (me *moduleEngine) Name() string {
 	return me.name
 }
 
+// Engine implements the same method as documented on wasm.ModuleEngine.
+func (me *moduleEngine) Engine() wasm.Engine {
+	return me.parentEngine
+}
+
 // CreateFuncElementInstance implements the same method as documented on wasm.ModuleEngine.
 func (me *moduleEngine) CreateFuncElementInstance(indexes []*wasm.Index) *wasm.ElementInstance {
 	refs := make([]wasm.Reference, len(indexes))
@@ -618,6 +933,16 @@ func (me *moduleEngine) CreateFuncElementInstance(indexes []*wasm.Index) *wasm.E
 	}
 }
 
+// FunctionInstanceReference implements the same method as documented on wasm.ModuleEngine.
+func (me *moduleEngine) FunctionInstanceReference(funcIndex wasm.Index) wasm.Reference {
+	return uintptr(unsafe.Pointer(me.functions[funcIndex]))
+}
+
+// LookupFunction implements the same method as documented on wasm.ModuleEngine.
+func (me *moduleEngine) LookupFunction(ref wasm.Reference) *wasm.FunctionInstance {
+	return functionFromUintptr(ref).source
+}
+
 // InitializeFuncrefGlobals implements the same method as documented on wasm.InitializeFuncrefGlobals.
 func (me *moduleEngine) InitializeFuncrefGlobals(globals []*wasm.GlobalInstance) {
 	for _, g := range globals {
@@ -651,6 +976,9 @@ func (me *moduleEngine) Call(ctx context.Context, m *wasm.CallContext, f *wasm.F
 	}
 
 	ce := me.newCallEngine()
+	if me.consumesFuel {
+		ce.fuel = wasm.FuelOf(ctx)
+	}
 	defer func() {
 		// If the module closed during the call, and the call didn't err for another reason, set an ExitError.
 		if err == nil {
@@ -709,17 +1037,22 @@ func (ce *callEngine) callGoFunc(ctx context.Context, callCtx *wasm.CallContext,
 }
 
 func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallContext, f *function) {
-	frame := &callFrame{f: f}
-	moduleInst := f.source.Module
-	memoryInst := moduleInst.Memory
+	frame := &callFrame{f: f, base: len(ce.stack) - f.source.Type.ParamNumInUint64}
+	ce.pushFrame(frame)
+
+	// Tail calls jump back here after replacing frame.f with the callee.
+entry:
+	if ce.valueStackCeiling < frame.base+frame.f.maxStackHeight {
+		panic(wasmruntime.ErrRuntimeValueStackOverflow)
+	}
+	moduleInst := frame.f.source.Module
 	globals := moduleInst.Globals
 	tables := moduleInst.Tables
-	typeIDs := f.source.Module.TypeIDs
-	functions := f.source.Module.Engine.(*moduleEngine).functions
-	dataInstances := f.source.Module.DataInstances
-	elementInstances := f.source.Module.ElementInstances
-	listener := f.source.FunctionListener
-	ce.pushFrame(frame)
+	typeIDs := moduleInst.TypeIDs
+	functions := moduleInst.Engine.(*moduleEngine).functions
+	dataInstances := moduleInst.DataInstances
+	elementInstances := moduleInst.ElementInstances
+	listener := frame.f.source.FunctionListener
 	bodyLen := uint64(len(frame.f.body))
 	for frame.pc < bodyLen {
 		op := frame.f.body[frame.pc]
@@ -750,7 +1083,12 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 			}
 		case wazeroir.OperationKindCall:
 			f := functions[op.us[0]]
-			if f.hostFn != nil {
+			if op.b3 {
+				if exc := ce.callInTry(func() { ctx = ce.call(ctx, callCtx, f, listener) }); exc != nil {
+					ce.throw(frame, exc)
+					continue
+				}
+			} else if f.hostFn != nil {
 				ce.callGoFuncWithStack(ctx, callCtx, f)
 			} else if listener != nil {
 				ctx = ce.callNativeFuncWithListener(ctx, callCtx, f, listener)
@@ -775,7 +1113,12 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 			}
 
 			// Call in.
-			if tf.hostFn != nil {
+			if op.b3 {
+				if exc := ce.callInTry(func() { ctx = ce.call(ctx, callCtx, tf, listener) }); exc != nil {
+					ce.throw(frame, exc)
+					continue
+				}
+			} else if tf.hostFn != nil {
 				ce.callGoFuncWithStack(ctx, callCtx, tf)
 			} else if listener != nil {
 				ctx = ce.callNativeFuncWithListener(ctx, callCtx, f, listener)
@@ -783,15 +1126,68 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 				ce.callNativeFunc(ctx, callCtx, tf)
 			}
 			frame.pc++
+		case wazeroir.OperationKindTailCall:
+			f := functions[op.us[0]]
+			if f.hostFn != nil || listener != nil {
+				// Host functions don't run on this frame, and listeners have to observe each call, so these are
+				// called as usual and then return.
+				if f.hostFn != nil {
+					ce.callGoFuncWithStack(ctx, callCtx, f)
+				} else {
+					ctx = ce.callNativeFuncWithListener(ctx, callCtx, f, listener)
+				}
+				frame.pc = bodyLen // return
+				continue
+			}
+			// Only the arguments remain in the current frame, so the callee can reuse it.
+			frame.f, frame.pc = f, 0
+			goto entry
+		case wazeroir.OperationKindTailCallIndirect:
+			offset := ce.popValue()
+			table := tables[op.us[1]]
+			if offset >= uint64(len(table.References)) {
+				panic(wasmruntime.ErrRuntimeInvalidTableAccess)
+			}
+			rawPtr := table.References[offset]
+			if rawPtr == 0 {
+				panic(wasmruntime.ErrRuntimeInvalidTableAccess)
+			}
+
+			tf := functionFromUintptr(rawPtr)
+			if tf.source.TypeID != typeIDs[op.us[0]] {
+				panic(wasmruntime.ErrRuntimeIndirectCallTypeMismatch)
+			}
+
+			if tf.hostFn != nil || listener != nil {
+				// See OperationKindTailCall.
+				if tf.hostFn != nil {
+					ce.callGoFuncWithStack(ctx, callCtx, tf)
+				} else {
+					ctx = ce.callNativeFuncWithListener(ctx, callCtx, tf, listener)
+				}
+				frame.pc = bodyLen // return
+				continue
+			}
+			frame.f, frame.pc = tf, 0
+			goto entry
 		case wazeroir.OperationKindDrop:
 			ce.drop(op.rs[0])
 			frame.pc++
 		case wazeroir.OperationKindSelect:
 			c := ce.popValue()
-			v2 := ce.popValue()
-			if c == 0 {
-				_ = ce.popValue()
-				ce.pushValue(v2)
+			if op.b3 { // Target is vector.
+				x2Hi, x2Lo := ce.popValue(), ce.popValue()
+				if c == 0 {
+					_, _ = ce.popValue(), ce.popValue() // discard the x1's lo and hi bits.
+					ce.pushValue(x2Lo)
+					ce.pushValue(x2Hi)
+				}
+			} else {
+				v2 := ce.popValue()
+				if c == 0 {
+					_ = ce.popValue()
+					ce.pushValue(v2)
+				}
 			}
 			frame.pc++
 		case wazeroir.OperationKindPick:
@@ -826,6 +1222,7 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 			g.Val = ce.popValue()
 			frame.pc++
 		case wazeroir.OperationKindLoad:
+			memoryInst := memoryAt(moduleInst, op.us[2])
 			offset := ce.popMemoryOffset(op)
 			switch wazeroir.UnsignedType(op.b1) {
 			case wazeroir.UnsignedTypeI32, wazeroir.UnsignedTypeF32:
@@ -843,6 +1240,7 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 			}
 			frame.pc++
 		case wazeroir.OperationKindLoad8:
+			memoryInst := memoryAt(moduleInst, op.us[2])
 			val, ok := memoryInst.ReadByte(ctx, ce.popMemoryOffset(op))
 			if !ok {
 				panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
@@ -856,6 +1254,7 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 			}
 			frame.pc++
 		case wazeroir.OperationKindLoad16:
+			memoryInst := memoryAt(moduleInst, op.us[2])
 			val, ok := memoryInst.ReadUint16Le(ctx, ce.popMemoryOffset(op))
 			if !ok {
 				panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
@@ -869,6 +1268,7 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 			}
 			frame.pc++
 		case wazeroir.OperationKindLoad32:
+			memoryInst := memoryAt(moduleInst, op.us[2])
 			val, ok := memoryInst.ReadUint32Le(ctx, ce.popMemoryOffset(op))
 			if !ok {
 				panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
@@ -881,6 +1281,7 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 			}
 			frame.pc++
 		case wazeroir.OperationKindStore:
+			memoryInst := memoryAt(moduleInst, op.us[2])
 			val := ce.popValue()
 			offset := ce.popMemoryOffset(op)
 			switch wazeroir.UnsignedType(op.b1) {
@@ -895,6 +1296,7 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 			}
 			frame.pc++
 		case wazeroir.OperationKindStore8:
+			memoryInst := memoryAt(moduleInst, op.us[2])
 			val := byte(ce.popValue())
 			offset := ce.popMemoryOffset(op)
 			if !memoryInst.WriteByte(ctx, offset, val) {
@@ -902,6 +1304,7 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 			}
 			frame.pc++
 		case wazeroir.OperationKindStore16:
+			memoryInst := memoryAt(moduleInst, op.us[2])
 			val := uint16(ce.popValue())
 			offset := ce.popMemoryOffset(op)
 			if !memoryInst.WriteUint16Le(ctx, offset, val) {
@@ -909,6 +1312,7 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 			}
 			frame.pc++
 		case wazeroir.OperationKindStore32:
+			memoryInst := memoryAt(moduleInst, op.us[2])
 			val := uint32(ce.popValue())
 			offset := ce.popMemoryOffset(op)
 			if !memoryInst.WriteUint32Le(ctx, offset, val) {
@@ -916,11 +1320,15 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 			}
 			frame.pc++
 		case wazeroir.OperationKindMemorySize:
+			memoryInst := memoryAt(moduleInst, op.us[0])
 			ce.pushValue(uint64(memoryInst.PageSize(ctx)))
 			frame.pc++
 		case wazeroir.OperationKindMemoryGrow:
+			memoryInst := memoryAt(moduleInst, op.us[0])
 			n := ce.popValue()
-			if res, ok := memoryInst.Grow(ctx, uint32(n)); !ok {
+			if memoryInst.Memory64 && n > math.MaxUint32 {
+				ce.pushValue(uint64(0xffffffff)) // The delta is 64-bit, and never fits in the memory.
+			} else if res, ok := memoryInst.Grow(ctx, uint32(n)); !ok {
 				ce.pushValue(uint64(0xffffffff)) // = -1 in signed 32-bit integer.
 			} else {
 				ce.pushValue(uint64(res))
@@ -1695,12 +2103,13 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 			ce.pushValue(uint64(v))
 			frame.pc++
 		case wazeroir.OperationKindMemoryInit:
+			memoryInst := memoryAt(moduleInst, op.us[1])
 			dataInstance := dataInstances[op.us[0]]
 			copySize := ce.popValue()
 			inDataOffset := ce.popValue()
 			inMemoryOffset := ce.popValue()
-			if inDataOffset+copySize > uint64(len(dataInstance)) ||
-				inMemoryOffset+copySize > uint64(len(memoryInst.Buffer)) {
+			if outOfRange(inDataOffset, copySize, uint64(len(dataInstance))) ||
+				outOfRange(inMemoryOffset, copySize, uint64(len(memoryInst.Buffer))) {
 				panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
 			} else if copySize != 0 {
 				copy(memoryInst.Buffer[inMemoryOffset:inMemoryOffset+copySize], dataInstance[inDataOffset:])
@@ -1710,22 +2119,24 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 			dataInstances[op.us[0]] = nil
 			frame.pc++
 		case wazeroir.OperationKindMemoryCopy:
-			memLen := uint64(len(memoryInst.Buffer))
+			src, dst := memoryAt(moduleInst, op.us[0]), memoryAt(moduleInst, op.us[1])
 			copySize := ce.popValue()
 			sourceOffset := ce.popValue()
 			destinationOffset := ce.popValue()
-			if sourceOffset+copySize > memLen || destinationOffset+copySize > memLen {
+			if outOfRange(sourceOffset, copySize, uint64(len(src.Buffer))) ||
+				outOfRange(destinationOffset, copySize, uint64(len(dst.Buffer))) {
 				panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
 			} else if copySize != 0 {
-				copy(memoryInst.Buffer[destinationOffset:],
-					memoryInst.Buffer[sourceOffset:sourceOffset+copySize])
+				copy(dst.Buffer[destinationOffset:],
+					src.Buffer[sourceOffset:sourceOffset+copySize])
 			}
 			frame.pc++
 		case wazeroir.OperationKindMemoryFill:
+			memoryInst := memoryAt(moduleInst, op.us[0])
 			fillSize := ce.popValue()
 			value := byte(ce.popValue())
 			offset := ce.popValue()
-			if fillSize+offset > uint64(len(memoryInst.Buffer)) {
+			if outOfRange(offset, fillSize, uint64(len(memoryInst.Buffer))) {
 				panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
 			} else if fillSize != 0 {
 				// Uses the copy trick for faster filling buffer.
@@ -1815,53 +2226,1660 @@ func (ce *callEngine) callNativeFunc(ctx context.Context, callCtx *wasm.CallCont
 				}
 			}
 			frame.pc++
-		case wazeroir.OperationKindConstV128:
+		case wazeroir.OperationKindV128Const:
 			lo, hi := op.us[0], op.us[1]
 			ce.pushValue(lo)
 			ce.pushValue(hi)
 			frame.pc++
 		case wazeroir.OperationKindV128Add:
+			yHigh, yLow := ce.popValue(), ce.popValue()
+			xHigh, xLow := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
 			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				for i := 0; i < 64; i += 8 {
+					retLo |= uint64(uint8(xLow>>i)+uint8(yLow>>i)) << i
+					retHi |= uint64(uint8(xHigh>>i)+uint8(yHigh>>i)) << i
+				}
+			case wazeroir.ShapeI16x8:
+				for i := 0; i < 64; i += 16 {
+					retLo |= uint64(uint16(xLow>>i)+uint16(yLow>>i)) << i
+					retHi |= uint64(uint16(xHigh>>i)+uint16(yHigh>>i)) << i
+				}
 			case wazeroir.ShapeI32x4:
-				xHigh, xLow := ce.popValue(), ce.popValue()
-				yHigh, yLow := ce.popValue(), ce.popValue()
-				ce.pushValue(uint64(uint32(xLow>>32+yLow>>32))<<32 + uint64(uint32(xLow)+uint32(yLow)))
-				ce.pushValue(uint64(uint32(xHigh>>32+yHigh>>32))<<32 + uint64(uint32(xHigh)+uint32(yHigh)))
+				for i := 0; i < 64; i += 32 {
+					retLo |= uint64(uint32(xLow>>i)+uint32(yLow>>i)) << i
+					retHi |= uint64(uint32(xHigh>>i)+uint32(yHigh>>i)) << i
+				}
 			case wazeroir.ShapeI64x2:
-				xHigh, xLow := ce.popValue(), ce.popValue()
-				yHigh, yLow := ce.popValue(), ce.popValue()
-				ce.pushValue(xLow + yLow)
-				ce.pushValue(xHigh + yHigh)
+				retLo, retHi = xLow+yLow, xHigh+yHigh
+			case wazeroir.ShapeF32x4:
+				for i := 0; i < 64; i += 32 {
+					retLo |= uint64(math.Float32bits(math.Float32frombits(uint32(xLow>>i))+math.Float32frombits(uint32(yLow>>i)))) << i
+					retHi |= uint64(math.Float32bits(math.Float32frombits(uint32(xHigh>>i))+math.Float32frombits(uint32(yHigh>>i)))) << i
+				}
+			case wazeroir.ShapeF64x2:
+				retLo = math.Float64bits(math.Float64frombits(xLow) + math.Float64frombits(yLow))
+				retHi = math.Float64bits(math.Float64frombits(xHigh) + math.Float64frombits(yHigh))
 			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
 			frame.pc++
-		}
-	}
-	ce.popFrame()
-}
-
-func (ce *callEngine) callNativeFuncWithListener(ctx context.Context, callCtx *wasm.CallContext, f *function, fnl experimental.FunctionListener) context.Context {
-	ctx = fnl.Before(ctx, ce.peekValues(len(f.source.Type.Params)))
-	ce.callNativeFunc(ctx, callCtx, f)
-	// TODO: This doesn't get the error due to use of panic to propagate them.
-	fnl.After(ctx, nil, ce.peekValues(len(f.source.Type.Results)))
-	return ctx
-}
-
-// popMemoryOffset takes a memory offset off the stack for use in load and store instructions.
-// As the top of stack value is 64-bit, this ensures it is in range before returning it.
-func (ce *callEngine) popMemoryOffset(op *interpreterOp) uint32 {
-	// TODO: Document what 'us' is and why we expect to look at value 1.
-	offset := op.us[1] + ce.popValue()
-	if offset > math.MaxUint32 {
-		panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
-	}
-	return uint32(offset)
-}
-
-func (ce *callEngine) callGoFuncWithStack(ctx context.Context, callCtx *wasm.CallContext, f *function) {
-	params := wasm.PopGoFuncParams(f.source, ce.popValue)
-	results := ce.callGoFunc(ctx, callCtx, f, params)
-	for _, v := range results {
-		ce.pushValue(v)
+		case wazeroir.OperationKindV128Sub:
+			yHigh, yLow := ce.popValue(), ce.popValue()
+			xHigh, xLow := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				for i := 0; i < 64; i += 8 {
+					retLo |= uint64(uint8(xLow>>i)-uint8(yLow>>i)) << i
+					retHi |= uint64(uint8(xHigh>>i)-uint8(yHigh>>i)) << i
+				}
+			case wazeroir.ShapeI16x8:
+				for i := 0; i < 64; i += 16 {
+					retLo |= uint64(uint16(xLow>>i)-uint16(yLow>>i)) << i
+					retHi |= uint64(uint16(xHigh>>i)-uint16(yHigh>>i)) << i
+				}
+			case wazeroir.ShapeI32x4:
+				for i := 0; i < 64; i += 32 {
+					retLo |= uint64(uint32(xLow>>i)-uint32(yLow>>i)) << i
+					retHi |= uint64(uint32(xHigh>>i)-uint32(yHigh>>i)) << i
+				}
+			case wazeroir.ShapeI64x2:
+				retLo, retHi = xLow-yLow, xHigh-yHigh
+			case wazeroir.ShapeF32x4:
+				for i := 0; i < 64; i += 32 {
+					retLo |= uint64(math.Float32bits(math.Float32frombits(uint32(xLow>>i))-math.Float32frombits(uint32(yLow>>i)))) << i
+					retHi |= uint64(math.Float32bits(math.Float32frombits(uint32(xHigh>>i))-math.Float32frombits(uint32(yHigh>>i)))) << i
+				}
+			case wazeroir.ShapeF64x2:
+				retLo = math.Float64bits(math.Float64frombits(xLow) - math.Float64frombits(yLow))
+				retHi = math.Float64bits(math.Float64frombits(xHigh) - math.Float64frombits(yHigh))
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128Load:
+			memoryInst := memoryAt(moduleInst, op.us[2])
+			offset := ce.popMemoryOffset(op)
+			switch op.b1 {
+			case wazeroir.V128LoadType128:
+				buf, ok := memoryInst.Read(ctx, offset, 16)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				ce.pushValue(binary.LittleEndian.Uint64(buf))
+				ce.pushValue(binary.LittleEndian.Uint64(buf[8:]))
+			case wazeroir.V128LoadType8x8s:
+				buf, ok := memoryInst.Read(ctx, offset, 8)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				ce.pushValue(
+					uint64(uint16(int8(buf[3])))<<48 | uint64(uint16(int8(buf[2])))<<32 | uint64(uint16(int8(buf[1])))<<16 | uint64(uint16(int8(buf[0]))),
+				)
+				ce.pushValue(
+					uint64(uint16(int8(buf[7])))<<48 | uint64(uint16(int8(buf[6])))<<32 | uint64(uint16(int8(buf[5])))<<16 | uint64(uint16(int8(buf[4]))),
+				)
+			case wazeroir.V128LoadType8x8u:
+				buf, ok := memoryInst.Read(ctx, offset, 8)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				ce.pushValue(
+					uint64(buf[3])<<48 | uint64(buf[2])<<32 | uint64(buf[1])<<16 | uint64(buf[0]),
+				)
+				ce.pushValue(
+					uint64(buf[7])<<48 | uint64(buf[6])<<32 | uint64(buf[5])<<16 | uint64(buf[4]),
+				)
+			case wazeroir.V128LoadType16x4s:
+				buf, ok := memoryInst.Read(ctx, offset, 8)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				ce.pushValue(
+					uint64(uint32(int16(binary.LittleEndian.Uint16(buf[2:]))))<<32 |
+						uint64(uint32(int16(binary.LittleEndian.Uint16(buf)))),
+				)
+				ce.pushValue(
+					uint64(uint32(int16(binary.LittleEndian.Uint16(buf[6:]))))<<32 |
+						uint64(uint32(int16(binary.LittleEndian.Uint16(buf[4:])))),
+				)
+			case wazeroir.V128LoadType16x4u:
+				buf, ok := memoryInst.Read(ctx, offset, 8)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				ce.pushValue(
+					uint64(binary.LittleEndian.Uint16(buf[2:]))<<32 | uint64(binary.LittleEndian.Uint16(buf)),
+				)
+				ce.pushValue(
+					uint64(binary.LittleEndian.Uint16(buf[6:]))<<32 | uint64(binary.LittleEndian.Uint16(buf[4:])),
+				)
+			case wazeroir.V128LoadType32x2s:
+				buf, ok := memoryInst.Read(ctx, offset, 8)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				ce.pushValue(uint64(int32(binary.LittleEndian.Uint32(buf))))
+				ce.pushValue(uint64(int32(binary.LittleEndian.Uint32(buf[4:]))))
+			case wazeroir.V128LoadType32x2u:
+				buf, ok := memoryInst.Read(ctx, offset, 8)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				ce.pushValue(uint64(binary.LittleEndian.Uint32(buf)))
+				ce.pushValue(uint64(binary.LittleEndian.Uint32(buf[4:])))
+			case wazeroir.V128LoadType8Splat:
+				v, ok := memoryInst.ReadByte(ctx, offset)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				v8 := uint64(v)<<56 | uint64(v)<<48 | uint64(v)<<40 | uint64(v)<<32 |
+					uint64(v)<<24 | uint64(v)<<16 | uint64(v)<<8 | uint64(v)
+				ce.pushValue(v8)
+				ce.pushValue(v8)
+			case wazeroir.V128LoadType16Splat:
+				v, ok := memoryInst.ReadUint16Le(ctx, offset)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				v4 := uint64(v)<<48 | uint64(v)<<32 | uint64(v)<<16 | uint64(v)
+				ce.pushValue(v4)
+				ce.pushValue(v4)
+			case wazeroir.V128LoadType32Splat:
+				v, ok := memoryInst.ReadUint32Le(ctx, offset)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				vv := uint64(v)<<32 | uint64(v)
+				ce.pushValue(vv)
+				ce.pushValue(vv)
+			case wazeroir.V128LoadType64Splat:
+				v, ok := memoryInst.ReadUint64Le(ctx, offset)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				ce.pushValue(v)
+				ce.pushValue(v)
+			case wazeroir.V128LoadType32zero:
+				v, ok := memoryInst.ReadUint32Le(ctx, offset)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				ce.pushValue(uint64(v))
+				ce.pushValue(0)
+			case wazeroir.V128LoadType64zero:
+				v, ok := memoryInst.ReadUint64Le(ctx, offset)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				ce.pushValue(v)
+				ce.pushValue(0)
+			}
+			frame.pc++
+		case wazeroir.OperationKindV128LoadLane:
+			memoryInst := memoryAt(moduleInst, op.us[2])
+			hi, lo := ce.popValue(), ce.popValue()
+			offset := ce.popMemoryOffset(op)
+			switch op.b1 {
+			case 8:
+				b, ok := memoryInst.ReadByte(ctx, offset)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				if op.b2 < 8 {
+					s := op.b2 << 3
+					lo = (lo & ^(0xff << s)) | uint64(b)<<s
+				} else {
+					s := (op.b2 - 8) << 3
+					hi = (hi & ^(0xff << s)) | uint64(b)<<s
+				}
+			case 16:
+				b, ok := memoryInst.ReadUint16Le(ctx, offset)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				if op.b2 < 4 {
+					s := op.b2 << 4
+					lo = (lo & ^(0xff_ff << s)) | uint64(b)<<s
+				} else {
+					s := (op.b2 - 4) << 4
+					hi = (hi & ^(0xff_ff << s)) | uint64(b)<<s
+				}
+			case 32:
+				b, ok := memoryInst.ReadUint32Le(ctx, offset)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				if op.b2 < 2 {
+					s := op.b2 << 5
+					lo = (lo & ^(0xff_ff_ff_ff << s)) | uint64(b)<<s
+				} else {
+					s := (op.b2 - 2) << 5
+					hi = (hi & ^(0xff_ff_ff_ff << s)) | uint64(b)<<s
+				}
+			case 64:
+				b, ok := memoryInst.ReadUint64Le(ctx, offset)
+				if !ok {
+					panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+				}
+				if op.b2 == 0 {
+					lo = b
+				} else {
+					hi = b
+				}
+			}
+			ce.pushValue(lo)
+			ce.pushValue(hi)
+			frame.pc++
+		case wazeroir.OperationKindV128Store:
+			memoryInst := memoryAt(moduleInst, op.us[2])
+			hi, lo := ce.popValue(), ce.popValue()
+			offset := ce.popMemoryOffset(op)
+			buf, ok := memoryInst.Read(ctx, offset, 16)
+			if !ok {
+				panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+			}
+			binary.LittleEndian.PutUint64(buf, lo)
+			binary.LittleEndian.PutUint64(buf[8:], hi)
+			frame.pc++
+		case wazeroir.OperationKindV128StoreLane:
+			memoryInst := memoryAt(moduleInst, op.us[2])
+			hi, lo := ce.popValue(), ce.popValue()
+			offset := ce.popMemoryOffset(op)
+			var ok bool
+			switch op.b1 {
+			case 8:
+				if op.b2 < 8 {
+					ok = memoryInst.WriteByte(ctx, offset, byte(lo>>(op.b2*8)))
+				} else {
+					ok = memoryInst.WriteByte(ctx, offset, byte(hi>>((op.b2-8)*8)))
+				}
+			case 16:
+				if op.b2 < 4 {
+					ok = memoryInst.WriteUint16Le(ctx, offset, uint16(lo>>(op.b2*16)))
+				} else {
+					ok = memoryInst.WriteUint16Le(ctx, offset, uint16(hi>>((op.b2-4)*16)))
+				}
+			case 32:
+				if op.b2 < 2 {
+					ok = memoryInst.WriteUint32Le(ctx, offset, uint32(lo>>(op.b2*32)))
+				} else {
+					ok = memoryInst.WriteUint32Le(ctx, offset, uint32(hi>>((op.b2-2)*32)))
+				}
+			case 64:
+				if op.b2 == 0 {
+					ok = memoryInst.WriteUint64Le(ctx, offset, lo)
+				} else {
+					ok = memoryInst.WriteUint64Le(ctx, offset, hi)
+				}
+			}
+			if !ok {
+				panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+			}
+			frame.pc++
+		case wazeroir.OperationKindV128ReplaceLane:
+			v := ce.popValue()
+			hi, lo := ce.popValue(), ce.popValue()
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				if op.b2 < 8 {
+					s := op.b2 << 3
+					lo = (lo & ^(0xff << s)) | uint64(byte(v))<<s
+				} else {
+					s := (op.b2 - 8) << 3
+					hi = (hi & ^(0xff << s)) | uint64(byte(v))<<s
+				}
+			case wazeroir.ShapeI16x8:
+				if op.b2 < 4 {
+					s := op.b2 << 4
+					lo = (lo & ^(0xff_ff << s)) | uint64(uint16(v))<<s
+				} else {
+					s := (op.b2 - 4) << 4
+					hi = (hi & ^(0xff_ff << s)) | uint64(uint16(v))<<s
+				}
+			case wazeroir.ShapeI32x4, wazeroir.ShapeF32x4:
+				if op.b2 < 2 {
+					s := op.b2 << 5
+					lo = (lo & ^(0xff_ff_ff_ff << s)) | uint64(uint32(v))<<s
+				} else {
+					s := (op.b2 - 2) << 5
+					hi = (hi & ^(0xff_ff_ff_ff << s)) | uint64(uint32(v))<<s
+				}
+			case wazeroir.ShapeI64x2, wazeroir.ShapeF64x2:
+				if op.b2 == 0 {
+					lo = v
+				} else {
+					hi = v
+				}
+			}
+			ce.pushValue(lo)
+			ce.pushValue(hi)
+			frame.pc++
+		case wazeroir.OperationKindV128ExtractLane:
+			hi, lo := ce.popValue(), ce.popValue()
+			var v uint64
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				var u8 byte
+				if op.b2 < 8 {
+					u8 = byte(lo >> (op.b2 * 8))
+				} else {
+					u8 = byte(hi >> ((op.b2 - 8) * 8))
+				}
+				if op.b3 {
+					// sign-extend.
+					v = uint64(uint32(int8(u8)))
+				} else {
+					v = uint64(u8)
+				}
+			case wazeroir.ShapeI16x8:
+				var u16 uint16
+				if op.b2 < 4 {
+					u16 = uint16(lo >> (op.b2 * 16))
+				} else {
+					u16 = uint16(hi >> ((op.b2 - 4) * 16))
+				}
+				if op.b3 {
+					// sign-extend.
+					v = uint64(uint32(int16(u16)))
+				} else {
+					v = uint64(u16)
+				}
+			case wazeroir.ShapeI32x4, wazeroir.ShapeF32x4:
+				if op.b2 < 2 {
+					v = uint64(uint32(lo >> (op.b2 * 32)))
+				} else {
+					v = uint64(uint32(hi >> ((op.b2 - 2) * 32)))
+				}
+			case wazeroir.ShapeI64x2, wazeroir.ShapeF64x2:
+				if op.b2 == 0 {
+					v = lo
+				} else {
+					v = hi
+				}
+			}
+			ce.pushValue(v)
+			frame.pc++
+		case wazeroir.OperationKindV128Splat:
+			v := ce.popValue()
+			var hi, lo uint64
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				v8 := uint64(byte(v))<<56 | uint64(byte(v))<<48 | uint64(byte(v))<<40 | uint64(byte(v))<<32 |
+					uint64(byte(v))<<24 | uint64(byte(v))<<16 | uint64(byte(v))<<8 | uint64(byte(v))
+				hi, lo = v8, v8
+			case wazeroir.ShapeI16x8:
+				v4 := uint64(uint16(v))<<48 | uint64(uint16(v))<<32 | uint64(uint16(v))<<16 | uint64(uint16(v))
+				hi, lo = v4, v4
+			case wazeroir.ShapeI32x4, wazeroir.ShapeF32x4:
+				v2 := uint64(uint32(v))<<32 | uint64(uint32(v))
+				lo, hi = v2, v2
+			case wazeroir.ShapeI64x2, wazeroir.ShapeF64x2:
+				lo, hi = v, v
+			}
+			ce.pushValue(lo)
+			ce.pushValue(hi)
+			frame.pc++
+		case wazeroir.OperationKindV128Swizzle:
+			idxHi, idxLo := ce.popValue(), ce.popValue()
+			baseHi, baseLo := ce.popValue(), ce.popValue()
+			var newVal [16]byte
+			for i := 0; i < 16; i++ {
+				var id byte
+				if i < 8 {
+					id = byte(idxLo >> (i * 8))
+				} else {
+					id = byte(idxHi >> ((i - 8) * 8))
+				}
+				if id < 8 {
+					newVal[i] = byte(baseLo >> (id * 8))
+				} else if id < 16 {
+					newVal[i] = byte(baseHi >> ((id - 8) * 8))
+				}
+			}
+			ce.pushValue(binary.LittleEndian.Uint64(newVal[:8]))
+			ce.pushValue(binary.LittleEndian.Uint64(newVal[8:]))
+			frame.pc++
+		case wazeroir.OperationKindV128Shuffle:
+			x2Hi, x2Lo, x1Hi, x1Lo := ce.popValue(), ce.popValue(), ce.popValue(), ce.popValue()
+			var newVal [16]byte
+			for i, l := range op.us {
+				if l < 8 {
+					newVal[i] = byte(x1Lo >> (l * 8))
+				} else if l < 16 {
+					newVal[i] = byte(x1Hi >> ((l - 8) * 8))
+				} else if l < 24 {
+					newVal[i] = byte(x2Lo >> ((l - 16) * 8))
+				} else if l < 32 {
+					newVal[i] = byte(x2Hi >> ((l - 24) * 8))
+				}
+			}
+			ce.pushValue(binary.LittleEndian.Uint64(newVal[:8]))
+			ce.pushValue(binary.LittleEndian.Uint64(newVal[8:]))
+			frame.pc++
+		case wazeroir.OperationKindV128AnyTrue:
+			hi, lo := ce.popValue(), ce.popValue()
+			if hi != 0 || lo != 0 {
+				ce.pushValue(1)
+			} else {
+				ce.pushValue(0)
+			}
+			frame.pc++
+		case wazeroir.OperationKindV128AllTrue:
+			hi, lo := ce.popValue(), ce.popValue()
+			var ret bool
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				ret = (uint8(lo) != 0) && (uint8(lo>>8) != 0) && (uint8(lo>>16) != 0) && (uint8(lo>>24) != 0) &&
+					(uint8(lo>>32) != 0) && (uint8(lo>>40) != 0) && (uint8(lo>>48) != 0) && (uint8(lo>>56) != 0) &&
+					(uint8(hi) != 0) && (uint8(hi>>8) != 0) && (uint8(hi>>16) != 0) && (uint8(hi>>24) != 0) &&
+					(uint8(hi>>32) != 0) && (uint8(hi>>40) != 0) && (uint8(hi>>48) != 0) && (uint8(hi>>56) != 0)
+			case wazeroir.ShapeI16x8:
+				ret = (uint16(lo) != 0) && (uint16(lo>>16) != 0) && (uint16(lo>>32) != 0) && (uint16(lo>>48) != 0) &&
+					(uint16(hi) != 0) && (uint16(hi>>16) != 0) && (uint16(hi>>32) != 0) && (uint16(hi>>48) != 0)
+			case wazeroir.ShapeI32x4:
+				ret = (uint32(lo) != 0) && (uint32(lo>>32) != 0) &&
+					(uint32(hi) != 0) && (uint32(hi>>32) != 0)
+			case wazeroir.ShapeI64x2:
+				ret = (lo != 0) &&
+					(hi != 0)
+			}
+			if ret {
+				ce.pushValue(1)
+			} else {
+				ce.pushValue(0)
+			}
+			frame.pc++
+		case wazeroir.OperationKindV128BitMask:
+			// https://github.com/WebAssembly/spec/blob/main/proposals/simd/SIMD.md#bitmask-extraction
+			hi, lo := ce.popValue(), ce.popValue()
+			var res uint64
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				for i := 0; i < 8; i++ {
+					if int8(lo>>(i*8)) < 0 {
+						res |= 1 << i
+					}
+				}
+				for i := 0; i < 8; i++ {
+					if int8(hi>>(i*8)) < 0 {
+						res |= 1 << (i + 8)
+					}
+				}
+			case wazeroir.ShapeI16x8:
+				for i := 0; i < 4; i++ {
+					if int16(lo>>(i*16)) < 0 {
+						res |= 1 << i
+					}
+				}
+				for i := 0; i < 4; i++ {
+					if int16(hi>>(i*16)) < 0 {
+						res |= 1 << (i + 4)
+					}
+				}
+			case wazeroir.ShapeI32x4:
+				for i := 0; i < 2; i++ {
+					if int32(lo>>(i*32)) < 0 {
+						res |= 1 << i
+					}
+				}
+				for i := 0; i < 2; i++ {
+					if int32(hi>>(i*32)) < 0 {
+						res |= 1 << (i + 2)
+					}
+				}
+			case wazeroir.ShapeI64x2:
+				if int64(lo) < 0 {
+					res |= 0b01
+				}
+				if int64(hi) < 0 {
+					res |= 0b10
+				}
+			}
+			ce.pushValue(res)
+			frame.pc++
+		case wazeroir.OperationKindV128And:
+			x2Hi, x2Lo := ce.popValue(), ce.popValue()
+			x1Hi, x1Lo := ce.popValue(), ce.popValue()
+			ce.pushValue(x1Lo & x2Lo)
+			ce.pushValue(x1Hi & x2Hi)
+			frame.pc++
+		case wazeroir.OperationKindV128Not:
+			hi, lo := ce.popValue(), ce.popValue()
+			ce.pushValue(^lo)
+			ce.pushValue(^hi)
+			frame.pc++
+		case wazeroir.OperationKindV128Or:
+			x2Hi, x2Lo := ce.popValue(), ce.popValue()
+			x1Hi, x1Lo := ce.popValue(), ce.popValue()
+			ce.pushValue(x1Lo | x2Lo)
+			ce.pushValue(x1Hi | x2Hi)
+			frame.pc++
+		case wazeroir.OperationKindV128Xor:
+			x2Hi, x2Lo := ce.popValue(), ce.popValue()
+			x1Hi, x1Lo := ce.popValue(), ce.popValue()
+			ce.pushValue(x1Lo ^ x2Lo)
+			ce.pushValue(x1Hi ^ x2Hi)
+			frame.pc++
+		case wazeroir.OperationKindV128Bitselect:
+			// https://github.com/WebAssembly/spec/blob/main/proposals/simd/SIMD.md#bitwise-select
+			cHi, cLo := ce.popValue(), ce.popValue()
+			x2Hi, x2Lo := ce.popValue(), ce.popValue()
+			x1Hi, x1Lo := ce.popValue(), ce.popValue()
+			// v128.or(v128.and(v1, c), v128.and(v2, v128.not(c)))
+			ce.pushValue((x1Lo & cLo) | (x2Lo & (^cLo)))
+			ce.pushValue((x1Hi & cHi) | (x2Hi & (^cHi)))
+			frame.pc++
+		case wazeroir.OperationKindV128AndNot:
+			x2Hi, x2Lo := ce.popValue(), ce.popValue()
+			x1Hi, x1Lo := ce.popValue(), ce.popValue()
+			ce.pushValue(x1Lo & (^x2Lo))
+			ce.pushValue(x1Hi & (^x2Hi))
+			frame.pc++
+		case wazeroir.OperationKindV128Shl:
+			s := ce.popValue()
+			hi, lo := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				s = s % 8
+				for i := 0; i < 64; i += 8 {
+					retLo |= uint64(uint8(lo>>i)<<s) << i
+					retHi |= uint64(uint8(hi>>i)<<s) << i
+				}
+			case wazeroir.ShapeI16x8:
+				s = s % 16
+				for i := 0; i < 64; i += 16 {
+					retLo |= uint64(uint16(lo>>i)<<s) << i
+					retHi |= uint64(uint16(hi>>i)<<s) << i
+				}
+			case wazeroir.ShapeI32x4:
+				s = s % 32
+				for i := 0; i < 64; i += 32 {
+					retLo |= uint64(uint32(lo>>i)<<s) << i
+					retHi |= uint64(uint32(hi>>i)<<s) << i
+				}
+			case wazeroir.ShapeI64x2:
+				s = s % 64
+				retLo, retHi = lo<<s, hi<<s
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128Shr:
+			s := ce.popValue()
+			hi, lo := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				s = s % 8
+				for i := 0; i < 64; i += 8 {
+					if op.b3 { // signed
+						retLo |= uint64(uint8(int8(lo>>i)>>s)) << i
+						retHi |= uint64(uint8(int8(hi>>i)>>s)) << i
+					} else {
+						retLo |= uint64(uint8(lo>>i)>>s) << i
+						retHi |= uint64(uint8(hi>>i)>>s) << i
+					}
+				}
+			case wazeroir.ShapeI16x8:
+				s = s % 16
+				for i := 0; i < 64; i += 16 {
+					if op.b3 { // signed
+						retLo |= uint64(uint16(int16(lo>>i)>>s)) << i
+						retHi |= uint64(uint16(int16(hi>>i)>>s)) << i
+					} else {
+						retLo |= uint64(uint16(lo>>i)>>s) << i
+						retHi |= uint64(uint16(hi>>i)>>s) << i
+					}
+				}
+			case wazeroir.ShapeI32x4:
+				s = s % 32
+				for i := 0; i < 64; i += 32 {
+					if op.b3 { // signed
+						retLo |= uint64(uint32(int32(lo>>i)>>s)) << i
+						retHi |= uint64(uint32(int32(hi>>i)>>s)) << i
+					} else {
+						retLo |= uint64(uint32(lo>>i)>>s) << i
+						retHi |= uint64(uint32(hi>>i)>>s) << i
+					}
+				}
+			case wazeroir.ShapeI64x2:
+				s = s % 64
+				if op.b3 { // signed
+					retLo, retHi = uint64(int64(lo)>>s), uint64(int64(hi)>>s)
+				} else {
+					retLo, retHi = lo>>s, hi>>s
+				}
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128Cmp:
+			x2Hi, x2Lo := ce.popValue(), ce.popValue()
+			x1Hi, x1Lo := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
+			switch t := op.b1; {
+			case t <= wazeroir.V128CmpTypeI8x16GeU:
+				kind := t - wazeroir.V128CmpTypeI8x16Eq
+				for i := 0; i < 64; i += 8 {
+					if v128IntCmp(kind, int64(int8(x1Lo>>i)), int64(int8(x2Lo>>i)), uint64(uint8(x1Lo>>i)), uint64(uint8(x2Lo>>i))) {
+						retLo |= 0xff << i
+					}
+					if v128IntCmp(kind, int64(int8(x1Hi>>i)), int64(int8(x2Hi>>i)), uint64(uint8(x1Hi>>i)), uint64(uint8(x2Hi>>i))) {
+						retHi |= 0xff << i
+					}
+				}
+			case t <= wazeroir.V128CmpTypeI16x8GeU:
+				kind := t - wazeroir.V128CmpTypeI16x8Eq
+				for i := 0; i < 64; i += 16 {
+					if v128IntCmp(kind, int64(int16(x1Lo>>i)), int64(int16(x2Lo>>i)), uint64(uint16(x1Lo>>i)), uint64(uint16(x2Lo>>i))) {
+						retLo |= 0xff_ff << i
+					}
+					if v128IntCmp(kind, int64(int16(x1Hi>>i)), int64(int16(x2Hi>>i)), uint64(uint16(x1Hi>>i)), uint64(uint16(x2Hi>>i))) {
+						retHi |= 0xff_ff << i
+					}
+				}
+			case t <= wazeroir.V128CmpTypeI32x4GeU:
+				kind := t - wazeroir.V128CmpTypeI32x4Eq
+				for i := 0; i < 64; i += 32 {
+					if v128IntCmp(kind, int64(int32(x1Lo>>i)), int64(int32(x2Lo>>i)), uint64(uint32(x1Lo>>i)), uint64(uint32(x2Lo>>i))) {
+						retLo |= 0xff_ff_ff_ff << i
+					}
+					if v128IntCmp(kind, int64(int32(x1Hi>>i)), int64(int32(x2Hi>>i)), uint64(uint32(x1Hi>>i)), uint64(uint32(x2Hi>>i))) {
+						retHi |= 0xff_ff_ff_ff << i
+					}
+				}
+			case t <= wazeroir.V128CmpTypeI64x2GeS:
+				// i64x2 only has eq, ne and the signed comparisons, so convert them to the kinds of v128IntCmp.
+				kind := [...]byte{0, 1, 2, 4, 6, 8}[t-wazeroir.V128CmpTypeI64x2Eq]
+				if v128IntCmp(kind, int64(x1Lo), int64(x2Lo), x1Lo, x2Lo) {
+					retLo = math.MaxUint64
+				}
+				if v128IntCmp(kind, int64(x1Hi), int64(x2Hi), x1Hi, x2Hi) {
+					retHi = math.MaxUint64
+				}
+			case t <= wazeroir.V128CmpTypeF32x4Ge:
+				kind := t - wazeroir.V128CmpTypeF32x4Eq
+				for i := 0; i < 64; i += 32 {
+					if v128FloatCmp(kind, float64(math.Float32frombits(uint32(x1Lo>>i))), float64(math.Float32frombits(uint32(x2Lo>>i)))) {
+						retLo |= 0xff_ff_ff_ff << i
+					}
+					if v128FloatCmp(kind, float64(math.Float32frombits(uint32(x1Hi>>i))), float64(math.Float32frombits(uint32(x2Hi>>i)))) {
+						retHi |= 0xff_ff_ff_ff << i
+					}
+				}
+			default:
+				kind := t - wazeroir.V128CmpTypeF64x2Eq
+				if v128FloatCmp(kind, math.Float64frombits(x1Lo), math.Float64frombits(x2Lo)) {
+					retLo = math.MaxUint64
+				}
+				if v128FloatCmp(kind, math.Float64frombits(x1Hi), math.Float64frombits(x2Hi)) {
+					retHi = math.MaxUint64
+				}
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128AddSat, wazeroir.OperationKindV128SubSat:
+			x2hi, x2Lo := ce.popValue(), ce.popValue()
+			x1hi, x1Lo := ce.popValue(), ce.popValue()
+			isAdd := op.kind == wazeroir.OperationKindV128AddSat
+			var retLo, retHi uint64
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				for i := 0; i < 64; i += 8 {
+					retLo |= uint64(v128SatI8(isAdd, op.b3, byte(x1Lo>>i), byte(x2Lo>>i))) << i
+					retHi |= uint64(v128SatI8(isAdd, op.b3, byte(x1hi>>i), byte(x2hi>>i))) << i
+				}
+			case wazeroir.ShapeI16x8:
+				for i := 0; i < 64; i += 16 {
+					retLo |= uint64(v128SatI16(isAdd, op.b3, uint16(x1Lo>>i), uint16(x2Lo>>i))) << i
+					retHi |= uint64(v128SatI16(isAdd, op.b3, uint16(x1hi>>i), uint16(x2hi>>i))) << i
+				}
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128Mul:
+			x2hi, x2lo := ce.popValue(), ce.popValue()
+			x1hi, x1lo := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
+			switch op.b1 {
+			case wazeroir.ShapeI16x8:
+				for i := 0; i < 64; i += 16 {
+					retLo |= uint64(uint16(x1lo>>i)*uint16(x2lo>>i)) << i
+					retHi |= uint64(uint16(x1hi>>i)*uint16(x2hi>>i)) << i
+				}
+			case wazeroir.ShapeI32x4:
+				for i := 0; i < 64; i += 32 {
+					retLo |= uint64(uint32(x1lo>>i)*uint32(x2lo>>i)) << i
+					retHi |= uint64(uint32(x1hi>>i)*uint32(x2hi>>i)) << i
+				}
+			case wazeroir.ShapeI64x2:
+				retLo, retHi = x1lo*x2lo, x1hi*x2hi
+			case wazeroir.ShapeF32x4:
+				for i := 0; i < 64; i += 32 {
+					retLo |= uint64(math.Float32bits(math.Float32frombits(uint32(x1lo>>i))*math.Float32frombits(uint32(x2lo>>i)))) << i
+					retHi |= uint64(math.Float32bits(math.Float32frombits(uint32(x1hi>>i))*math.Float32frombits(uint32(x2hi>>i)))) << i
+				}
+			case wazeroir.ShapeF64x2:
+				retLo = math.Float64bits(math.Float64frombits(x1lo) * math.Float64frombits(x2lo))
+				retHi = math.Float64bits(math.Float64frombits(x1hi) * math.Float64frombits(x2hi))
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128Div:
+			x2hi, x2lo := ce.popValue(), ce.popValue()
+			x1hi, x1lo := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
+			if op.b1 == wazeroir.ShapeF64x2 {
+				retLo = math.Float64bits(math.Float64frombits(x1lo) / math.Float64frombits(x2lo))
+				retHi = math.Float64bits(math.Float64frombits(x1hi) / math.Float64frombits(x2hi))
+			} else {
+				for i := 0; i < 64; i += 32 {
+					retLo |= uint64(math.Float32bits(math.Float32frombits(uint32(x1lo>>i))/math.Float32frombits(uint32(x2lo>>i)))) << i
+					retHi |= uint64(math.Float32bits(math.Float32frombits(uint32(x1hi>>i))/math.Float32frombits(uint32(x2hi>>i)))) << i
+				}
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128Neg:
+			hi, lo := ce.popValue(), ce.popValue()
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				var retLo, retHi uint64
+				for i := 0; i < 64; i += 8 {
+					retLo |= uint64(-uint8(lo>>i)) << i
+					retHi |= uint64(-uint8(hi>>i)) << i
+				}
+				lo, hi = retLo, retHi
+			case wazeroir.ShapeI16x8:
+				var retLo, retHi uint64
+				for i := 0; i < 64; i += 16 {
+					retLo |= uint64(-uint16(lo>>i)) << i
+					retHi |= uint64(-uint16(hi>>i)) << i
+				}
+				lo, hi = retLo, retHi
+			case wazeroir.ShapeI32x4:
+				lo = uint64(-uint32(lo>>32))<<32 | uint64(-uint32(lo))
+				hi = uint64(-uint32(hi>>32))<<32 | uint64(-uint32(hi))
+			case wazeroir.ShapeI64x2:
+				lo, hi = -lo, -hi
+			case wazeroir.ShapeF32x4:
+				// Negation of floats only flips the sign bits, so that NaN payloads are preserved.
+				lo, hi = lo^(1<<31|1<<63), hi^(1<<31|1<<63)
+			case wazeroir.ShapeF64x2:
+				lo, hi = lo^(1<<63), hi^(1<<63)
+			}
+			ce.pushValue(lo)
+			ce.pushValue(hi)
+			frame.pc++
+		case wazeroir.OperationKindV128Sqrt:
+			hi, lo := ce.popValue(), ce.popValue()
+			if op.b1 == wazeroir.ShapeF64x2 {
+				lo = math.Float64bits(math.Sqrt(math.Float64frombits(lo)))
+				hi = math.Float64bits(math.Sqrt(math.Float64frombits(hi)))
+			} else {
+				lo = uint64(math.Float32bits(float32(math.Sqrt(float64(math.Float32frombits(uint32(lo>>32)))))))<<32 |
+					uint64(math.Float32bits(float32(math.Sqrt(float64(math.Float32frombits(uint32(lo)))))))
+				hi = uint64(math.Float32bits(float32(math.Sqrt(float64(math.Float32frombits(uint32(hi>>32)))))))<<32 |
+					uint64(math.Float32bits(float32(math.Sqrt(float64(math.Float32frombits(uint32(hi)))))))
+			}
+			ce.pushValue(lo)
+			ce.pushValue(hi)
+			frame.pc++
+		case wazeroir.OperationKindV128Abs:
+			hi, lo := ce.popValue(), ce.popValue()
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				var retLo, retHi uint64
+				for i := 0; i < 64; i += 8 {
+					retLo |= uint64(uint8(v128AbsI64(int64(int8(lo>>i))))) << i
+					retHi |= uint64(uint8(v128AbsI64(int64(int8(hi>>i))))) << i
+				}
+				lo, hi = retLo, retHi
+			case wazeroir.ShapeI16x8:
+				var retLo, retHi uint64
+				for i := 0; i < 64; i += 16 {
+					retLo |= uint64(uint16(v128AbsI64(int64(int16(lo>>i))))) << i
+					retHi |= uint64(uint16(v128AbsI64(int64(int16(hi>>i))))) << i
+				}
+				lo, hi = retLo, retHi
+			case wazeroir.ShapeI32x4:
+				lo = uint64(uint32(v128AbsI64(int64(int32(lo>>32)))))<<32 | uint64(uint32(v128AbsI64(int64(int32(lo)))))
+				hi = uint64(uint32(v128AbsI64(int64(int32(hi>>32)))))<<32 | uint64(uint32(v128AbsI64(int64(int32(hi)))))
+			case wazeroir.ShapeI64x2:
+				lo, hi = uint64(v128AbsI64(int64(lo))), uint64(v128AbsI64(int64(hi)))
+			case wazeroir.ShapeF32x4:
+				// Absolute values of floats only clear the sign bits, so that NaN payloads are preserved.
+				lo, hi = lo&^(1<<31|1<<63), hi&^(1<<31|1<<63)
+			case wazeroir.ShapeF64x2:
+				lo, hi = lo&^(1<<63), hi&^(1<<63)
+			}
+			ce.pushValue(lo)
+			ce.pushValue(hi)
+			frame.pc++
+		case wazeroir.OperationKindV128Popcnt:
+			hi, lo := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
+			for i := 0; i < 64; i += 8 {
+				retLo |= uint64(bits.OnesCount8(uint8(lo>>i))) << i
+				retHi |= uint64(bits.OnesCount8(uint8(hi>>i))) << i
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128Min, wazeroir.OperationKindV128Max:
+			x2hi, x2lo := ce.popValue(), ce.popValue()
+			x1hi, x1lo := ce.popValue(), ce.popValue()
+			isMin := op.kind == wazeroir.OperationKindV128Min
+			var retLo, retHi uint64
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				for i := 0; i < 64; i += 8 {
+					if op.b3 { // signed
+						retLo |= uint64(uint8(v128MinMaxI64(isMin, int64(int8(x1lo>>i)), int64(int8(x2lo>>i))))) << i
+						retHi |= uint64(uint8(v128MinMaxI64(isMin, int64(int8(x1hi>>i)), int64(int8(x2hi>>i))))) << i
+					} else {
+						retLo |= uint64(uint8(v128MinMaxI64(isMin, int64(uint8(x1lo>>i)), int64(uint8(x2lo>>i))))) << i
+						retHi |= uint64(uint8(v128MinMaxI64(isMin, int64(uint8(x1hi>>i)), int64(uint8(x2hi>>i))))) << i
+					}
+				}
+			case wazeroir.ShapeI16x8:
+				for i := 0; i < 64; i += 16 {
+					if op.b3 { // signed
+						retLo |= uint64(uint16(v128MinMaxI64(isMin, int64(int16(x1lo>>i)), int64(int16(x2lo>>i))))) << i
+						retHi |= uint64(uint16(v128MinMaxI64(isMin, int64(int16(x1hi>>i)), int64(int16(x2hi>>i))))) << i
+					} else {
+						retLo |= uint64(uint16(v128MinMaxI64(isMin, int64(uint16(x1lo>>i)), int64(uint16(x2lo>>i))))) << i
+						retHi |= uint64(uint16(v128MinMaxI64(isMin, int64(uint16(x1hi>>i)), int64(uint16(x2hi>>i))))) << i
+					}
+				}
+			case wazeroir.ShapeI32x4:
+				for i := 0; i < 64; i += 32 {
+					if op.b3 { // signed
+						retLo |= uint64(uint32(v128MinMaxI64(isMin, int64(int32(x1lo>>i)), int64(int32(x2lo>>i))))) << i
+						retHi |= uint64(uint32(v128MinMaxI64(isMin, int64(int32(x1hi>>i)), int64(int32(x2hi>>i))))) << i
+					} else {
+						retLo |= uint64(uint32(v128MinMaxI64(isMin, int64(uint32(x1lo>>i)), int64(uint32(x2lo>>i))))) << i
+						retHi |= uint64(uint32(v128MinMaxI64(isMin, int64(uint32(x1hi>>i)), int64(uint32(x2hi>>i))))) << i
+					}
+				}
+			case wazeroir.ShapeF32x4:
+				for i := 0; i < 64; i += 32 {
+					retLo |= uint64(math.Float32bits(float32(v128MinMaxF64(isMin,
+						float64(math.Float32frombits(uint32(x1lo>>i))), float64(math.Float32frombits(uint32(x2lo>>i))))))) << i
+					retHi |= uint64(math.Float32bits(float32(v128MinMaxF64(isMin,
+						float64(math.Float32frombits(uint32(x1hi>>i))), float64(math.Float32frombits(uint32(x2hi>>i))))))) << i
+				}
+			case wazeroir.ShapeF64x2:
+				retLo = math.Float64bits(v128MinMaxF64(isMin, math.Float64frombits(x1lo), math.Float64frombits(x2lo)))
+				retHi = math.Float64bits(v128MinMaxF64(isMin, math.Float64frombits(x1hi), math.Float64frombits(x2hi)))
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128AvgrU:
+			x2hi, x2lo := ce.popValue(), ce.popValue()
+			x1hi, x1lo := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				for i := 0; i < 64; i += 8 {
+					retLo |= uint64((uint16(uint8(x1lo>>i))+uint16(uint8(x2lo>>i))+1)>>1) << i
+					retHi |= uint64((uint16(uint8(x1hi>>i))+uint16(uint8(x2hi>>i))+1)>>1) << i
+				}
+			case wazeroir.ShapeI16x8:
+				for i := 0; i < 64; i += 16 {
+					retLo |= uint64((uint32(uint16(x1lo>>i))+uint32(uint16(x2lo>>i))+1)>>1) << i
+					retHi |= uint64((uint32(uint16(x1hi>>i))+uint32(uint16(x2hi>>i))+1)>>1) << i
+				}
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128Pmin, wazeroir.OperationKindV128Pmax:
+			x2hi, x2lo := ce.popValue(), ce.popValue()
+			x1hi, x1lo := ce.popValue(), ce.popValue()
+			isMin := op.kind == wazeroir.OperationKindV128Pmin
+			var retLo, retHi uint64
+			if op.b1 == wazeroir.ShapeF32x4 {
+				for i := 0; i < 64; i += 32 {
+					retLo |= v128PminPmax(isMin, uint64(uint32(x1lo>>i)), uint64(uint32(x2lo>>i)),
+						float64(math.Float32frombits(uint32(x1lo>>i))), float64(math.Float32frombits(uint32(x2lo>>i)))) << i
+					retHi |= v128PminPmax(isMin, uint64(uint32(x1hi>>i)), uint64(uint32(x2hi>>i)),
+						float64(math.Float32frombits(uint32(x1hi>>i))), float64(math.Float32frombits(uint32(x2hi>>i)))) << i
+				}
+			} else {
+				retLo = v128PminPmax(isMin, x1lo, x2lo, math.Float64frombits(x1lo), math.Float64frombits(x2lo))
+				retHi = v128PminPmax(isMin, x1hi, x2hi, math.Float64frombits(x1hi), math.Float64frombits(x2hi))
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128Ceil, wazeroir.OperationKindV128Floor,
+			wazeroir.OperationKindV128Trunc, wazeroir.OperationKindV128Nearest:
+			hi, lo := ce.popValue(), ce.popValue()
+			var round func(float64) float64
+			switch op.kind {
+			case wazeroir.OperationKindV128Ceil:
+				round = math.Ceil
+			case wazeroir.OperationKindV128Floor:
+				round = math.Floor
+			case wazeroir.OperationKindV128Trunc:
+				round = math.Trunc
+			case wazeroir.OperationKindV128Nearest:
+				round = moremath.WasmCompatNearestF64
+			}
+			if op.b1 == wazeroir.ShapeF64x2 {
+				lo = math.Float64bits(v128QuietNaNF64(round(math.Float64frombits(lo))))
+				hi = math.Float64bits(v128QuietNaNF64(round(math.Float64frombits(hi))))
+			} else {
+				// Rounding float32 values via float64 is exact, as the result is representable in float32.
+				lo = uint64(math.Float32bits(float32(round(float64(math.Float32frombits(uint32(lo>>32)))))))<<32 |
+					uint64(math.Float32bits(float32(round(float64(math.Float32frombits(uint32(lo)))))))
+				hi = uint64(math.Float32bits(float32(round(float64(math.Float32frombits(uint32(hi>>32)))))))<<32 |
+					uint64(math.Float32bits(float32(round(float64(math.Float32frombits(uint32(hi)))))))
+			}
+			ce.pushValue(lo)
+			ce.pushValue(hi)
+			frame.pc++
+		case wazeroir.OperationKindV128Extend:
+			hi, lo := ce.popValue(), ce.popValue()
+			signed := op.b2 == 1
+			v := hi
+			if op.b3 { // use lower 64 bits
+				v = lo
+			}
+			var retLo, retHi uint64
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				for i := 0; i < 32; i += 8 {
+					if signed {
+						retLo |= uint64(uint16(int8(v>>i))) << (i * 2)
+						retHi |= uint64(uint16(int8(v>>(i+32)))) << (i * 2)
+					} else {
+						retLo |= uint64(uint8(v>>i)) << (i * 2)
+						retHi |= uint64(uint8(v>>(i+32))) << (i * 2)
+					}
+				}
+			case wazeroir.ShapeI16x8:
+				if signed {
+					retLo = uint64(uint32(int16(v>>16)))<<32 | uint64(uint32(int16(v)))
+					retHi = uint64(uint32(int16(v>>48)))<<32 | uint64(uint32(int16(v>>32)))
+				} else {
+					retLo = uint64(uint16(v>>16))<<32 | uint64(uint16(v))
+					retHi = uint64(uint16(v>>48))<<32 | uint64(uint16(v>>32))
+				}
+			case wazeroir.ShapeI32x4:
+				if signed {
+					retLo, retHi = uint64(int32(v)), uint64(int32(v>>32))
+				} else {
+					retLo, retHi = uint64(uint32(v)), uint64(uint32(v>>32))
+				}
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128ExtMul:
+			x2Hi, x2Lo := ce.popValue(), ce.popValue()
+			x1Hi, x1Lo := ce.popValue(), ce.popValue()
+			signed := op.b2 == 1
+			x1, x2 := x1Hi, x2Hi
+			if op.b3 { // use lower 64 bits
+				x1, x2 = x1Lo, x2Lo
+			}
+			var retLo, retHi uint64
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				for i := 0; i < 32; i += 8 {
+					if signed {
+						retLo |= uint64(uint16(int16(int8(x1>>i))*int16(int8(x2>>i)))) << (i * 2)
+						retHi |= uint64(uint16(int16(int8(x1>>(i+32)))*int16(int8(x2>>(i+32))))) << (i * 2)
+					} else {
+						retLo |= uint64(uint16(uint8(x1>>i))*uint16(uint8(x2>>i))) << (i * 2)
+						retHi |= uint64(uint16(uint8(x1>>(i+32)))*uint16(uint8(x2>>(i+32)))) << (i * 2)
+					}
+				}
+			case wazeroir.ShapeI16x8:
+				for i := 0; i < 32; i += 16 {
+					if signed {
+						retLo |= uint64(uint32(int32(int16(x1>>i))*int32(int16(x2>>i)))) << (i * 2)
+						retHi |= uint64(uint32(int32(int16(x1>>(i+32)))*int32(int16(x2>>(i+32))))) << (i * 2)
+					} else {
+						retLo |= uint64(uint32(uint16(x1>>i))*uint32(uint16(x2>>i))) << (i * 2)
+						retHi |= uint64(uint32(uint16(x1>>(i+32)))*uint32(uint16(x2>>(i+32)))) << (i * 2)
+					}
+				}
+			case wazeroir.ShapeI32x4:
+				if signed {
+					retLo = uint64(int64(int32(x1)) * int64(int32(x2)))
+					retHi = uint64(int64(int32(x1>>32)) * int64(int32(x2>>32)))
+				} else {
+					retLo = uint64(uint32(x1)) * uint64(uint32(x2))
+					retHi = uint64(uint32(x1>>32)) * uint64(uint32(x2>>32))
+				}
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128Q15mulrSatS:
+			x2hi, x2Lo := ce.popValue(), ce.popValue()
+			x1hi, x1Lo := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
+			for i := 0; i < 64; i += 16 {
+				retLo |= uint64(v128Q15mulrSatS(int16(x1Lo>>i), int16(x2Lo>>i))) << i
+				retHi |= uint64(v128Q15mulrSatS(int16(x1hi>>i), int16(x2hi>>i))) << i
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128ExtAddPairwise:
+			hi, lo := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
+			switch op.b1 {
+			case wazeroir.ShapeI8x16:
+				for i := 0; i < 64; i += 16 {
+					if op.b3 { // signed
+						retLo |= uint64(uint16(int16(int8(lo>>i))+int16(int8(lo>>(i+8))))) << i
+						retHi |= uint64(uint16(int16(int8(hi>>i))+int16(int8(hi>>(i+8))))) << i
+					} else {
+						retLo |= uint64(uint16(uint8(lo>>i))+uint16(uint8(lo>>(i+8)))) << i
+						retHi |= uint64(uint16(uint8(hi>>i))+uint16(uint8(hi>>(i+8)))) << i
+					}
+				}
+			case wazeroir.ShapeI16x8:
+				if op.b3 { // signed
+					retLo = uint64(uint32(int32(int16(lo))+int32(int16(lo>>16)))) |
+						uint64(uint32(int32(int16(lo>>32))+int32(int16(lo>>48))))<<32
+					retHi = uint64(uint32(int32(int16(hi))+int32(int16(hi>>16)))) |
+						uint64(uint32(int32(int16(hi>>32))+int32(int16(hi>>48))))<<32
+				} else {
+					retLo = uint64(uint32(uint16(lo))+uint32(uint16(lo>>16))) |
+						uint64(uint32(uint16(lo>>32))+uint32(uint16(lo>>48)))<<32
+					retHi = uint64(uint32(uint16(hi))+uint32(uint16(hi>>16))) |
+						uint64(uint32(uint16(hi>>32))+uint32(uint16(hi>>48)))<<32
+				}
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128FloatPromote:
+			_, toPromote := ce.popValue(), ce.popValue()
+			ce.pushValue(math.Float64bits(float64(math.Float32frombits(uint32(toPromote)))))
+			ce.pushValue(math.Float64bits(float64(math.Float32frombits(uint32(toPromote >> 32)))))
+			frame.pc++
+		case wazeroir.OperationKindV128FloatDemote:
+			hi, lo := ce.popValue(), ce.popValue()
+			ce.pushValue(
+				uint64(math.Float32bits(float32(math.Float64frombits(lo)))) |
+					(uint64(math.Float32bits(float32(math.Float64frombits(hi)))) << 32),
+			)
+			ce.pushValue(0)
+			frame.pc++
+		case wazeroir.OperationKindV128FConvertFromI:
+			hi, lo := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
+			switch op.b1 { // Destination shape.
+			case wazeroir.ShapeF32x4:
+				for i := 0; i < 64; i += 32 {
+					if op.b3 { // signed
+						retLo |= uint64(math.Float32bits(float32(int32(lo>>i)))) << i
+						retHi |= uint64(math.Float32bits(float32(int32(hi>>i)))) << i
+					} else {
+						retLo |= uint64(math.Float32bits(float32(uint32(lo>>i)))) << i
+						retHi |= uint64(math.Float32bits(float32(uint32(hi>>i)))) << i
+					}
+				}
+			case wazeroir.ShapeF64x2:
+				if op.b3 { // signed
+					retLo, retHi = math.Float64bits(float64(int32(lo))), math.Float64bits(float64(int32(lo>>32)))
+				} else {
+					retLo, retHi = math.Float64bits(float64(uint32(lo))), math.Float64bits(float64(uint32(lo>>32)))
+				}
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128Dot:
+			x2Hi, x2Lo := ce.popValue(), ce.popValue()
+			x1Hi, x1Lo := ce.popValue(), ce.popValue()
+			ce.pushValue(
+				uint64(uint32(int32(int16(x1Lo>>0))*int32(int16(x2Lo>>0))+int32(int16(x1Lo>>16))*int32(int16(x2Lo>>16)))) |
+					(uint64(uint32(int32(int16(x1Lo>>32))*int32(int16(x2Lo>>32))+int32(int16(x1Lo>>48))*int32(int16(x2Lo>>48)))) << 32),
+			)
+			ce.pushValue(
+				uint64(uint32(int32(int16(x1Hi>>0))*int32(int16(x2Hi>>0))+int32(int16(x1Hi>>16))*int32(int16(x2Hi>>16)))) |
+					(uint64(uint32(int32(int16(x1Hi>>32))*int32(int16(x2Hi>>32))+int32(int16(x1Hi>>48))*int32(int16(x2Hi>>48)))) << 32),
+			)
+			frame.pc++
+		case wazeroir.OperationKindV128Narrow:
+			x2Hi, x2Lo := ce.popValue(), ce.popValue()
+			x1Hi, x1Lo := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
+			switch op.b1 { // Original shape.
+			case wazeroir.ShapeI16x8:
+				for i := 0; i < 64; i += 16 {
+					retLo |= uint64(v128NarrowI16(op.b3, int16(x1Lo>>i))) << (i / 2)
+					retLo |= uint64(v128NarrowI16(op.b3, int16(x1Hi>>i))) << (i/2 + 32)
+					retHi |= uint64(v128NarrowI16(op.b3, int16(x2Lo>>i))) << (i / 2)
+					retHi |= uint64(v128NarrowI16(op.b3, int16(x2Hi>>i))) << (i/2 + 32)
+				}
+			case wazeroir.ShapeI32x4:
+				for i := 0; i < 64; i += 32 {
+					retLo |= uint64(v128NarrowI32(op.b3, int32(x1Lo>>i))) << (i / 2)
+					retLo |= uint64(v128NarrowI32(op.b3, int32(x1Hi>>i))) << (i/2 + 32)
+					retHi |= uint64(v128NarrowI32(op.b3, int32(x2Lo>>i))) << (i / 2)
+					retHi |= uint64(v128NarrowI32(op.b3, int32(x2Hi>>i))) << (i/2 + 32)
+				}
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindV128ITruncSatFromF:
+			hi, lo := ce.popValue(), ce.popValue()
+			var retLo, retHi uint64
+			switch op.b1 { // Original shape.
+			case wazeroir.ShapeF32x4:
+				for i := 0; i < 64; i += 32 {
+					retLo |= uint64(v128TruncSatToI32(op.b3, float64(math.Float32frombits(uint32(lo>>i))))) << i
+					retHi |= uint64(v128TruncSatToI32(op.b3, float64(math.Float32frombits(uint32(hi>>i))))) << i
+				}
+			case wazeroir.ShapeF64x2:
+				// The upper two lanes are zero.
+				retLo = uint64(v128TruncSatToI32(op.b3, math.Float64frombits(lo))) |
+					uint64(v128TruncSatToI32(op.b3, math.Float64frombits(hi)))<<32
+			}
+			ce.pushValue(retLo)
+			ce.pushValue(retHi)
+			frame.pc++
+		case wazeroir.OperationKindAtomicLoad:
+			memoryInst := memoryAt(moduleInst, op.us[2])
+			offset := ce.popAtomicOffset(op)
+			v, err := memoryInst.AtomicLoad(offset, uint32(op.b1))
+			if err != nil {
+				panic(err)
+			}
+			ce.pushValue(v)
+			frame.pc++
+		case wazeroir.OperationKindAtomicStore:
+			memoryInst := memoryAt(moduleInst, op.us[2])
+			val := ce.popValue()
+			offset := ce.popAtomicOffset(op)
+			if err := memoryInst.AtomicStore(offset, uint32(op.b1), val); err != nil {
+				panic(err)
+			}
+			frame.pc++
+		case wazeroir.OperationKindAtomicRMW:
+			memoryInst := memoryAt(moduleInst, op.us[2])
+			val := ce.popValue()
+			offset := ce.popAtomicOffset(op)
+			arithmetic := wazeroir.AtomicArithmeticOp(op.b2)
+			old, err := memoryInst.AtomicRMW(offset, uint32(op.b1), func(old uint64) uint64 {
+				return arithmetic.Apply(old, val)
+			})
+			if err != nil {
+				panic(err)
+			}
+			ce.pushValue(old)
+			frame.pc++
+		case wazeroir.OperationKindAtomicRMWCmpxchg:
+			memoryInst := memoryAt(moduleInst, op.us[2])
+			replacement, expected := ce.popValue(), ce.popValue()
+			offset := ce.popAtomicOffset(op)
+			old, err := memoryInst.AtomicCompareExchange(offset, uint32(op.b1), expected, replacement)
+			if err != nil {
+				panic(err)
+			}
+			ce.pushValue(old)
+			frame.pc++
+		case wazeroir.OperationKindAtomicMemoryWait:
+			memoryInst := memoryAt(moduleInst, op.us[2])
+			timeout, expected := int64(ce.popValue()), ce.popValue()
+			offset := ce.popAtomicOffset(op)
+			res, err := memoryInst.Wait(offset, uint32(op.b1), expected, timeout)
+			if err != nil {
+				panic(err)
+			}
+			ce.pushValue(res)
+			frame.pc++
+		case wazeroir.OperationKindAtomicMemoryNotify:
+			memoryInst := memoryAt(moduleInst, op.us[2])
+			count := uint32(ce.popValue())
+			offset := ce.popAtomicOffset(op)
+			res, err := memoryInst.Notify(offset, count)
+			if err != nil {
+				panic(err)
+			}
+			ce.pushValue(uint64(res))
+			frame.pc++
+		case wazeroir.OperationKindAtomicFence:
+			// Every atomic operation is sequentially consistent, so there's nothing to order here.
+			frame.pc++
+		case wazeroir.OperationKindThrow:
+			values := make([]uint64, op.us[1])
+			for i := len(values) - 1; i >= 0; i-- {
+				values[i] = ce.popValue()
+			}
+			ce.throw(frame, &api.Exception{Tag: moduleInst.Tags[op.us[0]], Values: values})
+		case wazeroir.OperationKindRethrow:
+			ce.throw(frame, ce.exceptions[ce.popValue()].exc)
+		case wazeroir.OperationKindConsumeFuel:
+			cost := experimental.Fuel(op.us[0])
+			if *ce.fuel < cost {
+				panic(wasmruntime.ErrRuntimeFuelExhausted)
+			}
+			*ce.fuel -= cost
+			frame.pc++
+		}
+	}
+	ce.popFrame()
+}
+
+// call calls f from a frame of which function listener is the given one.
+func (ce *callEngine) call(ctx context.Context, callCtx *wasm.CallContext, f *function, listener experimental.FunctionListener) context.Context {
+	if f.hostFn != nil {
+		ce.callGoFuncWithStack(ctx, callCtx, f)
+	} else if listener != nil {
+		ctx = ce.callNativeFuncWithListener(ctx, callCtx, f, listener)
+	} else {
+		ce.callNativeFunc(ctx, callCtx, f)
+	}
+	return ctx
+}
+
+// callInTry invokes the given call, and returns the exception raised by the callee if any. Other panics, such as
+// traps, are propagated as is.
+func (ce *callEngine) callInTry(call func()) (exc *api.Exception) {
+	frameCount := len(ce.frames)
+	defer func() {
+		if v := recover(); v != nil {
+			if exc = asException(v); exc == nil {
+				panic(v)
+			}
+			// Unwind the frames of the callee.
+			ce.frames = ce.frames[:frameCount]
+		}
+	}()
+	call()
+	return
+}
+
+// asException returns the *api.Exception in the recovered value, or nil if it is not an exception. Host functions
+// raise exceptions by panicking with them, possibly wrapped in another error.
+func asException(recovered interface{}) (exc *api.Exception) {
+	if err, ok := recovered.(error); ok {
+		errors.As(err, &exc)
+	}
+	return
+}
+
+// caughtException is an exception caught by a handler, whose handle is at stackPosition of callEngine.stack.
+type caughtException struct {
+	exc           *api.Exception
+	stackPosition int
+}
+
+// pushExceptionHandle pushes the handle of exc caught by the handler entered with the current stack.
+//
+// The handles of the handlers being executed are below the current stack, as handlers are entered with the stack of
+// their try block which is nested in them or called by them. So, the exceptions whose handle is at or above it are
+// of handlers which have ended, and are discarded to bound ce.exceptions to the depth of the stack.
+func (ce *callEngine) pushExceptionHandle(exc *api.Exception) {
+	pos := len(ce.stack)
+	i := len(ce.exceptions)
+	for i > 0 && ce.exceptions[i-1].stackPosition >= pos {
+		i--
+	}
+	ce.exceptions = append(ce.exceptions[:i], caughtException{exc: exc, stackPosition: pos})
+	ce.pushValue(uint64(i))
+}
+
+// throw enters the handler of exc in the given frame. If there's no such handler, this panics with exc, so that the
+// callers can handle it.
+func (ce *callEngine) throw(frame *callFrame, exc *api.Exception) {
+	tags := frame.f.source.Module.Tags
+	for tb := frame.f.tryBlockAt(frame.pc); tb != nil; tb = tb.next {
+		for _, c := range tb.catches {
+			if !c.catchAll && exc.Tag != api.Tag(tags[c.tagIndex]) {
+				continue
+			}
+			ce.stack = ce.stack[:frame.base+tb.stackHeight]
+			ce.pushExceptionHandle(exc)
+			if !c.catchAll {
+				for _, v := range exc.Values {
+					ce.pushValue(v)
+				}
+			}
+			frame.pc = c.pc
+			return
+		}
+	}
+	panic(exc)
+}
+
+func (ce *callEngine) callNativeFuncWithListener(ctx context.Context, callCtx *wasm.CallContext, f *function, fnl experimental.FunctionListener) context.Context {
+	ctx = fnl.Before(ctx, ce.peekValues(len(f.source.Type.Params)))
+	ce.callNativeFunc(ctx, callCtx, f)
+	// TODO: This doesn't get the error due to use of panic to propagate them.
+	fnl.After(ctx, nil, ce.peekValues(len(f.source.Type.Results)))
+	return ctx
+}
+
+// memoryAt returns the memory of moduleInst at the given index, which is ModuleInstance.Memory for index zero.
+func memoryAt(moduleInst *wasm.ModuleInstance, index uint64) *wasm.MemoryInstance {
+	if index == 0 {
+		return moduleInst.Memory
+	}
+	return moduleInst.Memories[index]
+}
+
+// popMemoryOffset takes a memory offset off the stack for use in load and store instructions.
+// As the top of stack value is 64-bit, this ensures it is in range before returning it.
+func (ce *callEngine) popMemoryOffset(op *interpreterOp) uint32 {
+	// TODO: Document what 'us' is and why we expect to look at value 1.
+	base := ce.popValue()
+	// The base is 64-bit for a memory indexed with i64, so check it before adding to prevent overflow.
+	if base > math.MaxUint32 {
+		panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+	}
+	offset := op.us[1] + base
+	if offset > math.MaxUint32 {
+		panic(wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess)
+	}
+	return uint32(offset)
+}
+
+// popAtomicOffset takes a memory offset off the stack for use in atomic instructions. Unlike popMemoryOffset, this
+// doesn't check the range as wasm.MemoryInstance checks the alignment first and then the range.
+func (ce *callEngine) popAtomicOffset(op *interpreterOp) uint64 {
+	base := ce.popValue()
+	if offset := op.us[1] + base; offset >= base {
+		return offset
+	}
+	// The base is 64-bit for a memory indexed with i64, and the sum overflowed. Saturate it to an aligned address
+	// which is out of range of any memory.
+	return math.MaxUint64 &^ 7
+}
+
+// outOfRange returns true if the range of size from offset doesn't fit in length. Unlike comparing offset+size with
+// length, this doesn't overflow with 64-bit offsets and sizes of a memory indexed with i64.
+func outOfRange(offset, size, length uint64) bool {
+	return offset > length || size > length-offset
+}
+
+func (ce *callEngine) callGoFuncWithStack(ctx context.Context, callCtx *wasm.CallContext, f *function) {
+	if f.source.Kind == wasm.FunctionKindGoModuleFunc {
+		ce.callGoModuleFuncWithStack(ctx, callCtx, f)
+		return
+	}
+	params := wasm.PopGoFuncParams(f.source, ce.popValue)
+	results := ce.callGoFunc(ctx, callCtx, f, params)
+	for _, v := range results {
+		ce.pushValue(v)
+	}
+}
+
+// callGoModuleFuncWithStack calls the api.GoModuleFunc directly on the value stack, so unlike callGoFuncWithStack,
+// the call neither reflects nor allocates.
+func (ce *callEngine) callGoModuleFuncWithStack(ctx context.Context, callCtx *wasm.CallContext, f *function) {
+	ft := f.source.Type
+	base := len(ce.stack) - ft.ParamNumInUint64
+	for i := ft.ParamNumInUint64; i < ft.ResultNumInUint64; i++ {
+		ce.pushValue(0) // Reserve the space for results.
+	}
+	if len(ce.frames) > 0 {
+		// Use the caller's memory, which might be different from the defining module on an imported function.
+		callCtx = callCtx.WithMemory(ce.frames[len(ce.frames)-1].f.source.Module.Memory)
+	}
+	listener := f.source.FunctionListener
+	if listener != nil {
+		ctx = listener.Before(ctx, ce.stack[base:base+ft.ParamNumInUint64])
+	}
+	ce.pushFrame(f.hostFrame)
+	f.source.GoModuleFunc(ctx, callCtx, ce.stack[base:])
+	ce.popFrame()
+	ce.stack = ce.stack[:base+ft.ResultNumInUint64]
+	if listener != nil {
+		// TODO: This doesn't get the error due to use of panic to propagate them.
+		listener.After(ctx, nil, ce.stack[base:])
+	}
+}
+
+// v128IntCmp returns the result of the integer lane comparison, where kind is one of eq, ne, lt_s, lt_u, gt_s, gt_u,
+// le_s, le_u, ge_s and ge_u in this order. x1s and x2s are the sign-extended lanes, and x1u and x2u are zero-extended.
+func v128IntCmp(kind byte, x1s, x2s int64, x1u, x2u uint64) bool {
+	switch kind {
+	case 0: // eq
+		return x1u == x2u
+	case 1: // ne
+		return x1u != x2u
+	case 2: // lt_s
+		return x1s < x2s
+	case 3: // lt_u
+		return x1u < x2u
+	case 4: // gt_s
+		return x1s > x2s
+	case 5: // gt_u
+		return x1u > x2u
+	case 6: // le_s
+		return x1s <= x2s
+	case 7: // le_u
+		return x1u <= x2u
+	case 8: // ge_s
+		return x1s >= x2s
+	default: // ge_u
+		return x1u >= x2u
+	}
+}
+
+// v128FloatCmp returns the result of the float lane comparison, where kind is one of eq, ne, lt, gt, le and ge in this
+// order.
+func v128FloatCmp(kind byte, x1, x2 float64) bool {
+	switch kind {
+	case 0: // eq
+		return x1 == x2
+	case 1: // ne
+		return x1 != x2
+	case 2: // lt
+		return x1 < x2
+	case 3: // gt
+		return x1 > x2
+	case 4: // le
+		return x1 <= x2
+	default: // ge
+		return x1 >= x2
+	}
+}
+
+// v128SatI8 returns the saturated addition (isAdd) or subtraction of the 8-bit lanes.
+func v128SatI8(isAdd, signed bool, x1, x2 byte) byte {
+	var v int64
+	if signed {
+		if isAdd {
+			v = int64(int8(x1)) + int64(int8(x2))
+		} else {
+			v = int64(int8(x1)) - int64(int8(x2))
+		}
+		if v < math.MinInt8 {
+			v = math.MinInt8
+		} else if v > math.MaxInt8 {
+			v = math.MaxInt8
+		}
+		return byte(int8(v))
+	}
+	if isAdd {
+		v = int64(x1) + int64(x2)
+	} else {
+		v = int64(x1) - int64(x2)
+	}
+	if v < 0 {
+		v = 0
+	} else if v > math.MaxUint8 {
+		v = math.MaxUint8
+	}
+	return byte(v)
+}
+
+// v128SatI16 returns the saturated addition (isAdd) or subtraction of the 16-bit lanes.
+func v128SatI16(isAdd, signed bool, x1, x2 uint16) uint16 {
+	var v int64
+	if signed {
+		if isAdd {
+			v = int64(int16(x1)) + int64(int16(x2))
+		} else {
+			v = int64(int16(x1)) - int64(int16(x2))
+		}
+		if v < math.MinInt16 {
+			v = math.MinInt16
+		} else if v > math.MaxInt16 {
+			v = math.MaxInt16
+		}
+		return uint16(int16(v))
+	}
+	if isAdd {
+		v = int64(x1) + int64(x2)
+	} else {
+		v = int64(x1) - int64(x2)
+	}
+	if v < 0 {
+		v = 0
+	} else if v > math.MaxUint16 {
+		v = math.MaxUint16
+	}
+	return uint16(v)
+}
+
+// v128AbsI64 returns the absolute value of v, which wraps around for the minimum value of the lane type.
+func v128AbsI64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// v128MinMaxI64 returns the minimum (isMin) or maximum of the extended integer lanes.
+func v128MinMaxI64(isMin bool, x1, x2 int64) int64 {
+	if (x1 < x2) == isMin {
+		return x1
+	}
+	return x2
+}
+
+// v128MinMaxF64 returns the minimum (isMin) or maximum of the float lanes following the Wasm semantics, where NaN
+// results are the canonical NaN.
+func v128MinMaxF64(isMin bool, x1, x2 float64) (ret float64) {
+	if isMin {
+		ret = moremath.WasmCompatMin(x1, x2)
+	} else {
+		ret = moremath.WasmCompatMax(x1, x2)
+	}
+	if math.IsNaN(ret) { // math.NaN isn't the canonical NaN, as its payload has the least significant bit set.
+		ret = math.Float64frombits(v128CanonicalNaNF64)
+	}
+	return
+}
+
+// v128CanonicalNaNF64 is the bits of the canonical NaN of float64, which only has the most significant bit of the payload
+// set. Converting it to float32 results in the canonical NaN of float32.
+const v128CanonicalNaNF64 = 0x7ff8_0000_0000_0000
+
+// v128QuietNaNF64 sets the most significant bit of the payload of v if it is a NaN, so that a signaling NaN operand
+// results in an arithmetic NaN, like the floating-point instructions of hardware.
+func v128QuietNaNF64(v float64) float64 {
+	if math.IsNaN(v) {
+		return math.Float64frombits(math.Float64bits(v) | v128CanonicalNaNF64)
+	}
+	return v
+}
+
+// v128PminPmax returns the pseudo-minimum (isMin) or pseudo-maximum of the float lanes, where x1Bits and x2Bits are the
+// original bits of x1 and x2. Unlike v128MinMaxF64, NaN in x2 is not propagated and the result is one of the operands.
+//
+// See https://github.com/WebAssembly/simd/pull/122
+func v128PminPmax(isMin bool, x1Bits, x2Bits uint64, x1, x2 float64) uint64 {
+	if isMin {
+		if x2 < x1 {
+			return x2Bits
+		}
+		return x1Bits
+	}
+	if x1 < x2 {
+		return x2Bits
+	}
+	return x1Bits
+}
+
+// v128Q15mulrSatS returns the saturating, rounding Q-format multiplication of the 16-bit lanes.
+func v128Q15mulrSatS(x1, x2 int16) uint16 {
+	v := (int32(x1)*int32(x2) + 0x4000) >> 15
+	if v > math.MaxInt16 {
+		v = math.MaxInt16
+	} else if v < math.MinInt16 {
+		v = math.MinInt16
+	}
+	return uint16(int16(v))
+}
+
+// v128NarrowI16 narrows the 16-bit signed lane into 8-bit with the signed or unsigned saturation.
+func v128NarrowI16(signed bool, v int16) byte {
+	if signed {
+		if v < math.MinInt8 {
+			v = math.MinInt8
+		} else if v > math.MaxInt8 {
+			v = math.MaxInt8
+		}
+		return byte(int8(v))
+	}
+	if v < 0 {
+		v = 0
+	} else if v > math.MaxUint8 {
+		v = math.MaxUint8
+	}
+	return byte(v)
+}
+
+// v128NarrowI32 narrows the 32-bit signed lane into 16-bit with the signed or unsigned saturation.
+func v128NarrowI32(signed bool, v int32) uint16 {
+	if signed {
+		if v < math.MinInt16 {
+			v = math.MinInt16
+		} else if v > math.MaxInt16 {
+			v = math.MaxInt16
+		}
+		return uint16(int16(v))
+	}
+	if v < 0 {
+		v = 0
+	} else if v > math.MaxUint16 {
+		v = math.MaxUint16
+	}
+	return uint16(v)
+}
+
+// v128TruncSatToI32 truncates the float into the 32-bit integer lane with the saturation, and NaN becomes zero.
+func v128TruncSatToI32(signed bool, v float64) uint32 {
+	if math.IsNaN(v) {
+		return 0
+	}
+	v = math.Trunc(v)
+	if signed {
+		if v < math.MinInt32 {
+			return uint32(1 << 31) // math.MinInt32
+		} else if v > math.MaxInt32 {
+			return math.MaxInt32
+		}
+		return uint32(int32(v))
+	}
+	if v < 0 {
+		return 0
+	} else if v > math.MaxUint32 {
+		return math.MaxUint32
 	}
+	return uint32(v)
 }
@@ -8,7 +8,7 @@ import (
 	"testing"
 	"unsafe"
 
-	"github.com/tetratelabs/wazero/internal/buildoptions"
+	"github.com/tetratelabs/wazero/api"
 	"github.com/tetratelabs/wazero/internal/testing/enginetest"
 	"github.com/tetratelabs/wazero/internal/testing/require"
 	"github.com/tetratelabs/wazero/internal/wasm"
@@ -22,7 +22,7 @@ func TestInterpreter_CallEngine_PushFrame(t *testing.T) {
 	f1 := &callFrame{}
 	f2 := &callFrame{}
 
-	ce := callEngine{}
+	ce := callEngine{callStackCeiling: wasm.DefaultStackLimits.CallStackCeiling}
 	require.Equal(t, 0, len(ce.frames), "expected no frames")
 
 	ce.pushFrame(f1)
@@ -33,16 +33,12 @@ func TestInterpreter_CallEngine_PushFrame(t *testing.T) {
 }
 
 func TestInterpreter_CallEngine_PushFrame_StackOverflow(t *testing.T) {
-	defer func() { callStackCeiling = buildoptions.CallStackCeiling }()
-
-	callStackCeiling = 3
-
 	f1 := &callFrame{}
 	f2 := &callFrame{}
 	f3 := &callFrame{}
 	f4 := &callFrame{}
 
-	vm := callEngine{}
+	vm := callEngine{callStackCeiling: 3}
 	vm.pushFrame(f1)
 	vm.pushFrame(f2)
 	vm.pushFrame(f3)
@@ -51,6 +47,58 @@ func TestInterpreter_CallEngine_PushFrame_StackOverflow(t *testing.T) {
 	require.EqualError(t, captured, "callstack overflow")
 }
 
+func TestInterpreter_CallEngine_callNativeFunc_StackOverflow(t *testing.T) {
+	// The function has one param, and can grow the stack to three values, including it.
+	f := &function{
+		source: &wasm.FunctionInstance{
+			Type:   &wasm.FunctionType{Params: []wasm.ValueType{wasm.ValueTypeI32}, ParamNumInUint64: 1},
+			Module: &wasm.ModuleInstance{Engine: &moduleEngine{}},
+		},
+		body:           []*interpreterOp{{kind: wazeroir.OperationKindBr, us: []uint64{math.MaxUint64}}},
+		maxStackHeight: 3,
+	}
+
+	ce := &callEngine{callStackCeiling: wasm.DefaultStackLimits.CallStackCeiling, valueStackCeiling: 3, stack: []uint64{1}}
+	ce.callNativeFunc(testCtx, &wasm.CallContext{}, f)
+
+	// The stack can't grow enough when the param is above another value, even if the function doesn't push any.
+	ce = &callEngine{callStackCeiling: wasm.DefaultStackLimits.CallStackCeiling, valueStackCeiling: 3, stack: []uint64{1, 2}}
+	captured := require.CapturePanic(func() { ce.callNativeFunc(testCtx, &wasm.CallContext{}, f) })
+	require.EqualError(t, captured, "value stack overflow")
+}
+
+func TestInterpreter_NewEngine_MaxCallStackCeiling(t *testing.T) {
+	e := NewEngine(wasm.Features20191205, wasm.StackLimits{CallStackCeiling: MaxCallStackCeiling + 1}).(*engine)
+	require.Equal(t, MaxCallStackCeiling, e.stackLimits.CallStackCeiling)
+
+	e = NewEngine(wasm.Features20191205, wasm.StackLimits{CallStackCeiling: 100}).(*engine)
+	require.Equal(t, 100, e.stackLimits.CallStackCeiling)
+}
+
+func TestInterpreter_CallEngine_PushExceptionHandle(t *testing.T) {
+	outer, inner, next := &api.Exception{}, &api.Exception{}, &api.Exception{}
+	ce := callEngine{valueStackCeiling: wasm.DefaultStackLimits.ValueStackCeiling, stack: []uint64{1, 2}}
+
+	ce.pushExceptionHandle(outer)
+	require.Equal(t, []uint64{1, 2, 0}, ce.stack)
+
+	// A handler nested in the one of outer keeps outer.
+	ce.pushValue(3)
+	for i := 0; i < 3; i++ {
+		// Catching in a loop reuses the handle of the previous iteration, whose handler has ended.
+		ce.stack = ce.stack[:4]
+		ce.pushExceptionHandle(inner)
+		require.Equal(t, []uint64{1, 2, 0, 3, 1}, ce.stack)
+		require.Equal(t, []caughtException{{exc: outer, stackPosition: 2}, {exc: inner, stackPosition: 4}}, ce.exceptions)
+	}
+
+	// Catching below the handlers ends them.
+	ce.stack = ce.stack[:1]
+	ce.pushExceptionHandle(next)
+	require.Equal(t, []uint64{1, 0}, ce.stack)
+	require.Equal(t, []caughtException{{exc: next, stackPosition: 1}}, ce.exceptions)
+}
+
 // et is used for tests defined in the enginetest package.
 var et = &engineTester{}
 
@@ -59,7 +107,7 @@ type engineTester struct{}
 
 // NewEngine implements enginetest.EngineTester NewEngine.
 func (e engineTester) NewEngine(enabledFeatures wasm.Features) wasm.Engine {
-	return NewEngine(enabledFeatures)
+	return NewEngine(enabledFeatures, wasm.DefaultStackLimits)
 }
 
 // InitTables implements enginetest.EngineTester InitTables.
@@ -291,9 +339,9 @@ func TestInterpreter_NonTrappingFloatToIntConversion(t *testing.T) {
 						&interpreterOp{kind: wazeroir.OperationKindBr, us: []uint64{math.MaxUint64}},
 					)
 
-					ce := &callEngine{}
+					ce := &callEngine{callStackCeiling: wasm.DefaultStackLimits.CallStackCeiling, valueStackCeiling: wasm.DefaultStackLimits.ValueStackCeiling}
 					f := &function{
-						source: &wasm.FunctionInstance{Module: &wasm.ModuleInstance{Engine: &moduleEngine{}}},
+						source: &wasm.FunctionInstance{Type: &wasm.FunctionType{}, Module: &wasm.ModuleInstance{Engine: &moduleEngine{}}},
 						body:   body,
 					}
 					ce.callNativeFunc(testCtx, &wasm.CallContext{}, f)
@@ -354,9 +402,9 @@ func TestInterpreter_CallEngine_callNativeFunc_signExtend(t *testing.T) {
 		for _, tt := range tests {
 			tc := tt
 			t.Run(fmt.Sprintf("%s(i32.const(0x%x))", wasm.InstructionName(tc.opcode), tc.in), func(t *testing.T) {
-				ce := &callEngine{}
+				ce := &callEngine{callStackCeiling: wasm.DefaultStackLimits.CallStackCeiling, valueStackCeiling: wasm.DefaultStackLimits.ValueStackCeiling}
 				f := &function{
-					source: &wasm.FunctionInstance{Module: &wasm.ModuleInstance{Engine: &moduleEngine{}}},
+					source: &wasm.FunctionInstance{Type: &wasm.FunctionType{}, Module: &wasm.ModuleInstance{Engine: &moduleEngine{}}},
 					body: []*interpreterOp{
 						{kind: wazeroir.OperationKindConstI32, us: []uint64{uint64(uint32(tc.in))}},
 						{kind: translateToIROperationKind(tc.opcode)},
@@ -408,9 +456,9 @@ func TestInterpreter_CallEngine_callNativeFunc_signExtend(t *testing.T) {
 		for _, tt := range tests {
 			tc := tt
 			t.Run(fmt.Sprintf("%s(i64.const(0x%x))", wasm.InstructionName(tc.opcode), tc.in), func(t *testing.T) {
-				ce := &callEngine{}
+				ce := &callEngine{callStackCeiling: wasm.DefaultStackLimits.CallStackCeiling, valueStackCeiling: wasm.DefaultStackLimits.ValueStackCeiling}
 				f := &function{
-					source: &wasm.FunctionInstance{Module: &wasm.ModuleInstance{Engine: &moduleEngine{}}},
+					source: &wasm.FunctionInstance{Type: &wasm.FunctionType{}, Module: &wasm.ModuleInstance{Engine: &moduleEngine{}}},
 					body: []*interpreterOp{
 						{kind: wazeroir.OperationKindConstI64, us: []uint64{uint64(tc.in)}},
 						{kind: translateToIROperationKind(tc.opcode)},
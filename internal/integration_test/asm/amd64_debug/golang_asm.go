@@ -263,6 +263,17 @@ func (a *assemblerGoAsmImpl) CompileRegisterToRegisterWithArg(
 ) {
 	p := a.NewProg()
 	p.As = castAsGolangAsmInstruction[inst]
+	if inst == amd64.CMPPS || inst == amd64.CMPPD {
+		// In Go assembler, the predicate of CMPPS and CMPPD comes last.
+		p.From.Type = obj.TYPE_REG
+		p.From.Reg = castAsGolangAsmRegister[from]
+		p.To.Type = obj.TYPE_CONST
+		p.To.Offset = int64(arg)
+		p.RestArgs = append(p.RestArgs,
+			obj.Addr{Reg: castAsGolangAsmRegister[to], Type: obj.TYPE_REG})
+		a.AddInstruction(p)
+		return
+	}
 	p.From.Type = obj.TYPE_CONST
 	p.From.Offset = int64(arg)
 	p.To.Type = obj.TYPE_REG
@@ -504,4 +515,111 @@ var castAsGolangAsmInstruction = [...]obj.As{
 	amd64.PADDQ:     x86.APADDQ,
 	amd64.ADDPS:     x86.AADDPS,
 	amd64.ADDPD:     x86.AADDPD,
+	amd64.PSUBB:     x86.APSUBB,
+	amd64.PSUBW:     x86.APSUBW,
+	amd64.PSUBL:     x86.APSUBL,
+	amd64.PSUBQ:     x86.APSUBQ,
+	amd64.SUBPS:     x86.ASUBPS,
+	amd64.SUBPD:     x86.ASUBPD,
+	amd64.PAND:      x86.APAND,
+	amd64.PANDN:     x86.APANDN,
+	amd64.POR:       x86.APOR,
+	amd64.PXOR:      x86.APXOR,
+	amd64.ANDNPS:    x86.AANDNPS,
+	amd64.ANDNPD:    x86.AANDNPD,
+	amd64.PSHUFB:    x86.APSHUFB,
+	amd64.PSHUFD:    x86.APSHUFD,
+	amd64.SHUFPS:    x86.ASHUFPS,
+	amd64.UNPCKLPS:  x86.AUNPCKLPS,
+	amd64.PUNPCKLBW: x86.APUNPCKLBW,
+	amd64.PUNPCKHBW: x86.APUNPCKHBW,
+	amd64.PINSRB:    x86.APINSRB,
+	amd64.PINSRW:    x86.APINSRW,
+	amd64.PINSRD:    x86.APINSRD,
+	amd64.PEXTRB:    x86.APEXTRB,
+	amd64.PEXTRW:    x86.APEXTRW,
+	amd64.PEXTRD:    x86.APEXTRD,
+	amd64.PEXTRQ:    x86.APEXTRQ,
+	amd64.INSERTPS:  x86.AINSERTPS,
+	amd64.MOVSD:     x86.AMOVSD,
+	amd64.MOVLHPS:   x86.AMOVLHPS,
+	amd64.PCMPEQB:   x86.APCMPEQB,
+	amd64.PCMPEQW:   x86.APCMPEQW,
+	amd64.PCMPEQD:   x86.APCMPEQL,
+	amd64.PCMPEQQ:   x86.APCMPEQQ,
+	amd64.PCMPGTB:   x86.APCMPGTB,
+	amd64.PCMPGTW:   x86.APCMPGTW,
+	amd64.PCMPGTD:   x86.APCMPGTL,
+	amd64.PCMPGTQ:   x86.APCMPGTQ,
+	amd64.PMINSB:    x86.APMINSB,
+	amd64.PMINSW:    x86.APMINSW,
+	amd64.PMINSD:    x86.APMINSD,
+	amd64.PMINUB:    x86.APMINUB,
+	amd64.PMINUW:    x86.APMINUW,
+	amd64.PMINUD:    x86.APMINUD,
+	amd64.PMAXSB:    x86.APMAXSB,
+	amd64.PMAXSW:    x86.APMAXSW,
+	amd64.PMAXSD:    x86.APMAXSD,
+	amd64.PMAXUB:    x86.APMAXUB,
+	amd64.PMAXUW:    x86.APMAXUW,
+	amd64.PMAXUD:    x86.APMAXUD,
+	amd64.CMPPS:     x86.ACMPPS,
+	amd64.CMPPD:     x86.ACMPPD,
+	amd64.PADDSB:    x86.APADDSB,
+	amd64.PADDSW:    x86.APADDSW,
+	amd64.PADDUSB:   x86.APADDUSB,
+	amd64.PADDUSW:   x86.APADDUSW,
+	amd64.PSUBSB:    x86.APSUBSB,
+	amd64.PSUBSW:    x86.APSUBSW,
+	amd64.PSUBUSB:   x86.APSUBUSB,
+	amd64.PSUBUSW:   x86.APSUBUSW,
+	amd64.PMULLW:    x86.APMULLW,
+	amd64.PMULLD:    x86.APMULLD,
+	amd64.PMULUDQ:   x86.APMULULQ,
+	amd64.PMULDQ:    x86.APMULDQ,
+	amd64.PMULHRSW:  x86.APMULHRSW,
+	amd64.PMADDWD:   x86.APMADDWL,
+	amd64.PMADDUBSW: x86.APMADDUBSW,
+	amd64.MULPS:     x86.AMULPS,
+	amd64.MULPD:     x86.AMULPD,
+	amd64.DIVPS:     x86.ADIVPS,
+	amd64.DIVPD:     x86.ADIVPD,
+	amd64.SQRTPS:    x86.ASQRTPS,
+	amd64.SQRTPD:    x86.ASQRTPD,
+	amd64.MINPS:     x86.AMINPS,
+	amd64.MINPD:     x86.AMINPD,
+	amd64.MAXPS:     x86.AMAXPS,
+	amd64.MAXPD:     x86.AMAXPD,
+	amd64.PABSB:     x86.APABSB,
+	amd64.PABSW:     x86.APABSW,
+	amd64.PABSD:     x86.APABSD,
+	amd64.PAVGB:     x86.APAVGB,
+	amd64.PAVGW:     x86.APAVGW,
+	amd64.ROUNDPS:   x86.AROUNDPS,
+	amd64.ROUNDPD:   x86.AROUNDPD,
+	amd64.PMOVSXBW:  x86.APMOVSXBW,
+	amd64.PMOVSXWD:  x86.APMOVSXWD,
+	amd64.PMOVSXDQ:  x86.APMOVSXDQ,
+	amd64.PMOVZXBW:  x86.APMOVZXBW,
+	amd64.PMOVZXWD:  x86.APMOVZXWD,
+	amd64.PMOVZXDQ:  x86.APMOVZXDQ,
+	amd64.CVTPS2PD:  x86.ACVTPS2PD,
+	amd64.CVTPD2PS:  x86.ACVTPD2PS,
+	amd64.CVTDQ2PS:  x86.ACVTPL2PS,
+	amd64.CVTDQ2PD:  x86.ACVTPL2PD,
+	amd64.CVTTPS2DQ: x86.ACVTTPS2PL,
+	amd64.CVTTPD2DQ: x86.ACVTTPD2PL,
+	amd64.PACKSSWB:  x86.APACKSSWB,
+	amd64.PACKSSDW:  x86.APACKSSLW,
+	amd64.PACKUSWB:  x86.APACKUSWB,
+	amd64.PACKUSDW:  x86.APACKUSDW,
+	amd64.PMOVMSKB:  x86.APMOVMSKB,
+	amd64.MOVMSKPS:  x86.AMOVMSKPS,
+	amd64.MOVMSKPD:  x86.AMOVMSKPD,
+	amd64.PTEST:     x86.APTEST,
+	amd64.PSLLW:     x86.APSLLW,
+	amd64.PSRLW:     x86.APSRLW,
+	amd64.PSRAW:     x86.APSRAW,
+	amd64.PSRAL:     x86.APSRAL,
+	amd64.PSRLDQ:    x86.APSRLO,
 }
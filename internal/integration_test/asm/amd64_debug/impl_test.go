@@ -905,6 +905,130 @@ func TestAssemblerImpl_EncodeRegisterToRegister(t *testing.T) {
 		{instruction: amd64.XORPD, srcRegs: floatRegisters, DstRegs: floatRegisters},
 		{instruction: amd64.XORPS, srcRegs: floatRegisters, DstRegs: floatRegisters},
 		{instruction: amd64.XORQ, srcRegs: intRegisters, DstRegs: intRegisters},
+		{instruction: amd64.PSUBB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSUBW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSUBL, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSUBQ, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.SUBPS, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.SUBPD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PAND, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PANDN, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.POR, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PXOR, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.ANDNPS, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.ANDNPD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSHUFB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSHUFD, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 0},
+		{instruction: amd64.PSHUFD, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 1},
+		{instruction: amd64.PSHUFD, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 27},
+		{instruction: amd64.SHUFPS, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 0},
+		{instruction: amd64.SHUFPS, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 1},
+		{instruction: amd64.SHUFPS, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 27},
+		{instruction: amd64.UNPCKLPS, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PUNPCKLBW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PUNPCKHBW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PINSRB, srcRegs: intRegisters, DstRegs: floatRegisters, arg: 1},
+		{instruction: amd64.PINSRW, srcRegs: intRegisters, DstRegs: floatRegisters, arg: 1},
+		{instruction: amd64.PINSRD, srcRegs: intRegisters, DstRegs: floatRegisters, arg: 1},
+		{instruction: amd64.PEXTRB, srcRegs: floatRegisters, DstRegs: intRegisters, arg: 1},
+		{instruction: amd64.PEXTRW, srcRegs: floatRegisters, DstRegs: intRegisters, arg: 1},
+		{instruction: amd64.PEXTRD, srcRegs: floatRegisters, DstRegs: intRegisters, arg: 1},
+		{instruction: amd64.PEXTRQ, srcRegs: floatRegisters, DstRegs: intRegisters, arg: 1},
+		{instruction: amd64.INSERTPS, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 0},
+		{instruction: amd64.INSERTPS, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 1},
+		{instruction: amd64.INSERTPS, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 27},
+		{instruction: amd64.MOVSD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.MOVLHPS, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PCMPEQB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PCMPEQW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PCMPEQD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PCMPEQQ, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PCMPGTB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PCMPGTW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PCMPGTD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PCMPGTQ, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMINSB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMINSW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMINSD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMINUB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMINUW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMINUD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMAXSB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMAXSW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMAXSD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMAXUB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMAXUW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMAXUD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.CMPPS, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 0},
+		{instruction: amd64.CMPPS, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 1},
+		{instruction: amd64.CMPPS, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 27},
+		{instruction: amd64.CMPPD, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 0},
+		{instruction: amd64.CMPPD, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 1},
+		{instruction: amd64.CMPPD, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 27},
+		{instruction: amd64.PADDSB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PADDSW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PADDUSB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PADDUSW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSUBSB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSUBSW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSUBUSB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSUBUSW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMULLW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMULLD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMULUDQ, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMULDQ, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMULHRSW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMADDWD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMADDUBSW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.MULPS, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.MULPD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.DIVPS, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.DIVPD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.SQRTPS, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.SQRTPD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.MINPS, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.MINPD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.MAXPS, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.MAXPD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PABSB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PABSW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PABSD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PAVGB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PAVGW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.ROUNDPS, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 0},
+		{instruction: amd64.ROUNDPS, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 1},
+		{instruction: amd64.ROUNDPS, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 27},
+		{instruction: amd64.ROUNDPD, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 0},
+		{instruction: amd64.ROUNDPD, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 1},
+		{instruction: amd64.ROUNDPD, srcRegs: floatRegisters, DstRegs: floatRegisters, arg: 27},
+		{instruction: amd64.PMOVSXBW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMOVSXWD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMOVSXDQ, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMOVZXBW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMOVZXWD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMOVZXDQ, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.CVTPS2PD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.CVTPD2PS, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.CVTDQ2PS, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.CVTDQ2PD, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.CVTTPS2DQ, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.CVTTPD2DQ, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PACKSSWB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PACKSSDW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PACKUSWB, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PACKUSDW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PMOVMSKB, srcRegs: floatRegisters, DstRegs: intRegisters},
+		{instruction: amd64.MOVMSKPS, srcRegs: floatRegisters, DstRegs: intRegisters},
+		{instruction: amd64.MOVMSKPD, srcRegs: floatRegisters, DstRegs: intRegisters},
+		{instruction: amd64.PTEST, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSLLW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSRLW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSRAW, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSRAL, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSLLL, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSLLQ, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSRLL, srcRegs: floatRegisters, DstRegs: floatRegisters},
+		{instruction: amd64.PSRLQ, srcRegs: floatRegisters, DstRegs: floatRegisters},
 	}
 
 	for _, tt := range tests {
@@ -961,9 +1085,12 @@ func TestAssemblerImpl_EncodeRegisterToRegister(t *testing.T) {
 							// TODO: remove golang-asm dependency in tests.
 							goasm, err := newGolangAsmAssembler()
 							require.NoError(t, err)
-							if tc.instruction == amd64.ROUNDSD || tc.instruction == amd64.ROUNDSS || tc.instruction == amd64.PINSRQ {
+							switch tc.instruction {
+							case amd64.ROUNDSD, amd64.ROUNDSS, amd64.ROUNDPS, amd64.ROUNDPD, amd64.CMPPS, amd64.CMPPD,
+								amd64.PSHUFD, amd64.SHUFPS, amd64.INSERTPS, amd64.PINSRB, amd64.PINSRW, amd64.PINSRD, amd64.PINSRQ,
+								amd64.PEXTRB, amd64.PEXTRW, amd64.PEXTRD, amd64.PEXTRQ:
 								goasm.CompileRegisterToRegisterWithArg(tc.instruction, srcReg, DstReg, tc.arg)
-							} else {
+							default:
 								goasm.CompileRegisterToRegister(tc.instruction, srcReg, DstReg)
 							}
 							bs, err := goasm.Assemble()
@@ -1431,7 +1558,7 @@ func TestAssemblerImpl_encodeConstToRegister(t *testing.T) {
 		}
 	})
 	t.Run("float instructions", func(t *testing.T) {
-		for _, inst := range []asm.Instruction{amd64.PSLLL, amd64.PSLLQ, amd64.PSRLL, amd64.PSRLQ} {
+		for _, inst := range []asm.Instruction{amd64.PSLLL, amd64.PSLLQ, amd64.PSRLL, amd64.PSRLQ, amd64.PSRLW, amd64.PSRAL, amd64.PSRLDQ} {
 			inst := inst
 			t.Run(amd64.InstructionName(inst), func(t *testing.T) {
 				for _, reg := range floatRegisters {
@@ -273,8 +273,26 @@ func (ta *testAssembler) CompileRegisterToVectorRegister(instruction asm.Instruc
 	ta.a.CompileRegisterToVectorRegister(instruction, srcReg, dstReg, arrangement, index)
 }
 
+func (ta *testAssembler) CompileVectorRegisterToRegister(instruction asm.Instruction, srcReg, dstReg asm.Register,
+	arrangement arm64.VectorArrangement, index arm64.VectorIndex) {
+	ta.goasm.CompileVectorRegisterToRegister(instruction, srcReg, dstReg, arrangement, index)
+	ta.a.CompileVectorRegisterToRegister(instruction, srcReg, dstReg, arrangement, index)
+}
+
 func (ta *testAssembler) CompileVectorRegisterToVectorRegister(instruction asm.Instruction, srcReg, dstReg asm.Register,
+	arrangement arm64.VectorArrangement, srcIndex, dstIndex arm64.VectorIndex) {
+	ta.goasm.CompileVectorRegisterToVectorRegister(instruction, srcReg, dstReg, arrangement, srcIndex, dstIndex)
+	ta.a.CompileVectorRegisterToVectorRegister(instruction, srcReg, dstReg, arrangement, srcIndex, dstIndex)
+}
+
+func (ta *testAssembler) CompileVectorRegisterToVectorRegisterWithConst(instruction asm.Instruction, srcReg, dstReg asm.Register,
+	arrangement arm64.VectorArrangement, c asm.ConstantValue) {
+	ta.goasm.CompileVectorRegisterToVectorRegisterWithConst(instruction, srcReg, dstReg, arrangement, c)
+	ta.a.CompileVectorRegisterToVectorRegisterWithConst(instruction, srcReg, dstReg, arrangement, c)
+}
+
+func (ta *testAssembler) CompileTwoVectorRegistersToVectorRegister(instruction asm.Instruction, srcReg, srcReg2, dstReg asm.Register,
 	arrangement arm64.VectorArrangement) {
-	ta.goasm.CompileVectorRegisterToVectorRegister(instruction, srcReg, dstReg, arrangement)
-	ta.a.CompileVectorRegisterToVectorRegister(instruction, srcReg, dstReg, arrangement)
+	ta.goasm.CompileTwoVectorRegistersToVectorRegister(instruction, srcReg, srcReg2, dstReg, arrangement)
+	ta.a.CompileTwoVectorRegistersToVectorRegister(instruction, srcReg, srcReg2, dstReg, arrangement)
 }
@@ -15,7 +15,16 @@ import (
 // newAssembler implements asm.NewAssembler by golang-asm.
 func newAssembler(temporaryRegister asm.Register) (*assemblerGoAsmImpl, error) {
 	g, err := golang_asm.NewGolangAsmBaseAssembler("arm64")
-	return &assemblerGoAsmImpl{GolangAsmBaseAssembler: g, temporaryRegister: temporaryRegister}, err
+	if err != nil {
+		return nil, err
+	}
+
+	// golang-asm treats the first instruction of arm64 programs as the function symbol and never encodes it,
+	// so we add a placeholder here instead of having callers (e.g. the arm64 compiler) emit one.
+	text := g.NewProg()
+	text.As = obj.ANOP
+	g.AddInstruction(text)
+	return &assemblerGoAsmImpl{GolangAsmBaseAssembler: g, temporaryRegister: temporaryRegister}, nil
 }
 
 // assemblerGoAsmImpl implements asm_arm64.Assembler for golang-asm library.
@@ -401,7 +410,23 @@ func (a *assemblerGoAsmImpl) CompileRegisterToVectorRegister(instruction asm.Ins
 	inst.From.Reg = castAsGolangAsmRegister[srcReg]
 	a.AddInstruction(inst)
 }
-func (a *assemblerGoAsmImpl) CompileVectorRegisterToVectorRegister(instruction asm.Instruction, srcReg, dstReg asm.Register, arrangement asm_arm64.VectorArrangement) {
+func (a *assemblerGoAsmImpl) CompileVectorRegisterToRegister(asm.Instruction, asm.Register, asm.Register,
+	asm_arm64.VectorArrangement, asm_arm64.VectorIndex) {
+	panic("Unsupported in golang-asm")
+}
+
+func (a *assemblerGoAsmImpl) CompileVectorRegisterToVectorRegisterWithConst(asm.Instruction, asm.Register, asm.Register,
+	asm_arm64.VectorArrangement, asm.ConstantValue) {
+	panic("Unsupported in golang-asm")
+}
+
+func (a *assemblerGoAsmImpl) CompileTwoVectorRegistersToVectorRegister(asm.Instruction, asm.Register, asm.Register, asm.Register,
+	asm_arm64.VectorArrangement) {
+	panic("Unsupported in golang-asm")
+}
+
+func (a *assemblerGoAsmImpl) CompileVectorRegisterToVectorRegister(instruction asm.Instruction, srcReg, dstReg asm.Register,
+	arrangement asm_arm64.VectorArrangement, _, _ asm_arm64.VectorIndex) {
 	inst := a.NewProg()
 	inst.As = castAsGolangAsmInstruction[instruction]
 
@@ -421,9 +446,7 @@ func (a *assemblerGoAsmImpl) CompileVectorRegisterToVectorRegister(instruction a
 		inst.From.Type = obj.TYPE_REG
 		inst.From.Reg = castAsGolangAsVectorRegister[dstReg]&31 + arm64.REG_ARNG + (castAsGolangAsmArrangement[arrangement]&15)<<5
 		a.AddInstruction(inst)
-	case asm_arm64.VFADDD:
-		panic("Unsupported in golang-asm")
-	case asm_arm64.VFADDS:
+	default:
 		panic("Unsupported in golang-asm")
 	}
 }
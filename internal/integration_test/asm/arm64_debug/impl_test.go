@@ -15,7 +15,6 @@ import (
 func newGoasmAssembler(t *testing.T, _ asm.Register) arm64.Assembler {
 	a, err := newAssembler(asm.NilRegister)
 	require.NoError(t, err)
-	a.CompileStandAlone(arm64.NOP)
 	return a
 }
 
@@ -1204,6 +1203,38 @@ func TestAssemblerImpl_EncodeRelativeJump(t *testing.T) {
 	}
 }
 
+// TestAssemblerImpl_vectorSelect ensures that the instructions emitted by the compiler for the select of vectors,
+// including the branch over the move, are encoded as Go's assembler does.
+func TestAssemblerImpl_vectorSelect(t *testing.T) {
+	for _, cvReg := range []asm.Register{arm64.RegR0, arm64.RegR10, arm64.RegR30} {
+		for _, x1Reg := range []asm.Register{arm64.RegV0, arm64.RegV31} {
+			for _, x2Reg := range []asm.Register{arm64.RegV1, arm64.RegV30} {
+				cvReg, x1Reg, x2Reg := cvReg, x1Reg, x2Reg
+				t.Run(fmt.Sprintf("cv=%s,x1=%s,x2=%s",
+					arm64.RegisterName(cvReg), arm64.RegisterName(x1Reg), arm64.RegisterName(x2Reg)), func(t *testing.T) {
+					goasm := newGoasmAssembler(t, asm.NilRegister)
+					a := arm64.NewAssemblerImpl(asm.NilRegister)
+
+					for _, assembler := range []arm64.Assembler{a, goasm} {
+						assembler.CompileTwoRegistersToNone(arm64.CMPW, arm64.RegRZR, cvReg)
+						brIfNotZero := assembler.CompileJump(arm64.BNE)
+						assembler.CompileVectorRegisterToVectorRegister(arm64.VMOV, x2Reg, x1Reg,
+							arm64.VectorArrangement16B, arm64.VectorIndexNone, arm64.VectorIndexNone)
+						assembler.SetJumpTargetOnNext(brIfNotZero)
+						assembler.CompileConstToRegister(arm64.MOVD, 1000, arm64.RegR10)
+					}
+
+					actual, err := a.Assemble()
+					require.NoError(t, err)
+					expected, err := goasm.Assemble()
+					require.NoError(t, err)
+					require.Equal(t, expected, actual, hex.EncodeToString(expected))
+				})
+			}
+		}
+	}
+}
+
 // TestAssemblerImpl_multipleLargeOffest ensures that the const pool flushing strategy matches
 // the one of Go's assembler.
 func TestAssemblerImpl_multipleLargeOffest(t *testing.T) {
@@ -1344,7 +1375,7 @@ func TestAssemblerImpl_EncodeVectorRegisterToVectorRegister(t *testing.T) {
 						a := arm64.NewAssemblerImpl(asm.NilRegister)
 
 						for _, assembler := range []arm64.Assembler{goasm, a} {
-							assembler.CompileVectorRegisterToVectorRegister(tc.inst, src, dst, tc.arr)
+							assembler.CompileVectorRegisterToVectorRegister(tc.inst, src, dst, tc.arr, arm64.VectorIndexNone, arm64.VectorIndexNone)
 						}
 
 						expected, err := goasm.Assemble()
@@ -1523,17 +1554,17 @@ func TestAssemblerImpl_EncodeRegisterToVectorRegister(t *testing.T) {
 			},
 			{
 				n: &arm64.NodeImpl{Instruction: arm64.VMOV,
-					Types:       arm64.OperandTypesRegisterToVectorRegister,
-					VectorIndex: 100, VectorArrangement: arm64.VectorArrangement1D,
+					Types:          arm64.OperandTypesRegisterToVectorRegister,
+					DstVectorIndex: 100, VectorArrangement: arm64.VectorArrangement1D,
 				},
 				expErr: "invalid arrangement and index pair: 1D[100]",
 			},
 			{
 				n: &arm64.NodeImpl{Instruction: arm64.VMOV,
-					Types:       arm64.OperandTypesRegisterToVectorRegister,
-					SrcReg:      arm64.RegR0,
-					DstReg:      arm64.RegV3,
-					VectorIndex: 0, VectorArrangement: arm64.VectorArrangement1D,
+					Types:          arm64.OperandTypesRegisterToVectorRegister,
+					SrcReg:         arm64.RegR0,
+					DstReg:         arm64.RegV3,
+					DstVectorIndex: 0, VectorArrangement: arm64.VectorArrangement1D,
 				},
 				expErr: "unsupported arrangement for VMOV: 1D",
 			},
@@ -3,16 +3,22 @@ package adhoc
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"math"
+	"runtime"
 	"strconv"
+	"sync"
 	"testing"
 	"unsafe"
 
 	"github.com/tetratelabs/wazero"
 	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/experimental"
+	"github.com/tetratelabs/wazero/internal/engine/interpreter"
 	"github.com/tetratelabs/wazero/internal/testing/require"
 	"github.com/tetratelabs/wazero/internal/wasm"
+	"github.com/tetratelabs/wazero/internal/wasmruntime"
 	"github.com/tetratelabs/wazero/sys"
 )
 
@@ -34,15 +40,28 @@ var moduleConfig = wazero.NewModuleConfig()
 var tests = map[string]func(t *testing.T, r wazero.Runtime){
 	"huge stack":                                        testHugeStack,
 	"unreachable":                                       testUnreachable,
+	"host function returning an error":                  testHostFunctionError,
 	"recursive entry":                                   testRecursiveEntry,
 	"imported-and-exported func":                        testImportedAndExportedFunc,
 	"host function with context parameter":              testHostFunctionContextParameter,
 	"host function with nested context":                 testNestedGoContext,
 	"host function with numeric parameter":              testHostFunctionNumericParameter,
+	"host function without reflection":                  testGoModuleFunc,
+	"host function with multiple and reference results": testHostFunctionResults,
+	"host function with v128 params and results":        testHostFunctionV128,
 	"close module with in-flight calls":                 testCloseInFlight,
 	"multiple instantiation from same source":           testMultipleInstantiation,
 	"exported function that grows memory":               testMemOps,
 	"import functions with reference type in signature": testReftypeImports,
+	"atomic instructions on shared memory":              testAtomics,
+	"shared memory grown by another thread":             testSharedMemoryGrow,
+	"tail calls":                                        testTailCalls,
+	"exception handling":                                testExceptions,
+	"multiple memories":                                 testMultiMemory,
+	"64-bit memory":                                     testMemory64,
+	"extended constant expressions":                     testExtendedConst,
+	"select and drop vectors":                           testVectorSelect,
+	"exported tables":                                   testTables,
 }
 
 func TestEngineCompiler(t *testing.T) {
@@ -56,8 +75,62 @@ func TestEngineInterpreter(t *testing.T) {
 	runAllTests(t, tests, wazero.NewRuntimeConfigInterpreter())
 }
 
+func TestEngineCompiler_StackLimits(t *testing.T) {
+	if !wazero.CompilerSupported {
+		t.Skip()
+	}
+	testStackLimits(t, wazero.NewRuntimeConfigCompiler())
+}
+
+func TestEngineInterpreter_StackLimits(t *testing.T) {
+	testStackLimits(t, wazero.NewRuntimeConfigInterpreter())
+
+	// A ceiling above the cap would exhaust the goroutine stack, crashing the process instead of raising an error.
+	t.Run("call stack cap", func(t *testing.T) {
+		r := wazero.NewRuntimeWithConfig(wazero.NewRuntimeConfigInterpreter().
+			WithCallStackCeiling(2 * interpreter.MaxCallStackCeiling))
+		defer r.Close(testCtx)
+
+		module, err := r.InstantiateModuleFromCode(testCtx, recursionWasm)
+		require.NoError(t, err)
+
+		recurse := module.ExportedFunction("recurse")
+		_, err = recurse.Call(testCtx, interpreter.MaxCallStackCeiling-1)
+		require.NoError(t, err)
+
+		for _, depth := range []uint64{interpreter.MaxCallStackCeiling, 2 * interpreter.MaxCallStackCeiling} {
+			_, err = recurse.Call(testCtx, depth)
+			require.ErrorIs(t, err, wasmruntime.ErrRuntimeCallStackOverflow)
+		}
+	})
+}
+
+func TestEngineCompiler_Fuel(t *testing.T) {
+	if !wazero.CompilerSupported {
+		t.Skip()
+	}
+	testFuel(t, wazero.NewRuntimeConfigCompiler())
+}
+
+func TestEngineInterpreter_Fuel(t *testing.T) {
+	testFuel(t, wazero.NewRuntimeConfigInterpreter())
+}
+
+func TestEngineCompiler_GoModuleFuncAllocs(t *testing.T) {
+	if !wazero.CompilerSupported {
+		t.Skip()
+	}
+	testGoModuleFuncAllocs(t, wazero.NewRuntimeConfigCompiler())
+}
+
+func TestEngineInterpreter_GoModuleFuncAllocs(t *testing.T) {
+	testGoModuleFuncAllocs(t, wazero.NewRuntimeConfigInterpreter())
+}
+
 func runAllTests(t *testing.T, tests map[string]func(t *testing.T, r wazero.Runtime), config wazero.RuntimeConfig) {
-	config = config.WithFeatureReferenceTypes(true)
+	config = config.WithWasmCore2().WithFeatureThreads(true).WithFeatureTailCall(true).
+		WithFeatureExceptionHandling(true).WithFeatureMultiMemory(true).
+		WithFeatureMemory64(true).WithFeatureExtendedConst(true)
 	for name, testf := range tests {
 		name := name   // pin
 		testf := testf // pin
@@ -77,6 +150,36 @@ var (
 	hugestackWasm []byte
 	//go:embed testdata/reftype_imports.wasm
 	reftypeImportsWasm []byte
+	//go:embed testdata/host_results.wasm
+	hostResultsWasm []byte
+	//go:embed testdata/host_v128.wasm
+	hostV128Wasm []byte
+	//go:embed testdata/host_loop.wasm
+	hostLoopWasm []byte
+	//go:embed testdata/atomics.wasm
+	atomicsWasm []byte
+	//go:embed testdata/atomics_import.wasm
+	atomicsImportWasm []byte
+	//go:embed testdata/shared_grow.wasm
+	sharedGrowWasm []byte
+	//go:embed testdata/tail_call.wasm
+	tailCallWasm []byte
+	//go:embed testdata/exceptions.wasm
+	exceptionsWasm []byte
+	//go:embed testdata/multi_memory.wasm
+	multiMemoryWasm []byte
+	//go:embed testdata/memory64.wasm
+	memory64Wasm []byte
+	//go:embed testdata/extended_const.wasm
+	extendedConstWasm []byte
+	//go:embed testdata/vector_select.wasm
+	vectorSelectWasm []byte
+	//go:embed testdata/recursion.wasm
+	recursionWasm []byte
+	//go:embed testdata/spin.wasm
+	spinWasm []byte
+	//go:embed testdata/tables.wasm
+	tablesWasm []byte
 )
 
 func testReftypeImports(t *testing.T, r wazero.Runtime) {
@@ -118,6 +221,203 @@ func testHugeStack(t *testing.T, r wazero.Runtime) {
 	require.NoError(t, err)
 }
 
+// testStackLimits ensures the stack ceilings of the runtime are enforced, as opposed to the defaults.
+func testStackLimits(t *testing.T, config wazero.RuntimeConfig) {
+	t.Run("call stack", func(t *testing.T) {
+		r := wazero.NewRuntimeWithConfig(config.WithCallStackCeiling(100))
+		defer r.Close(testCtx)
+
+		module, err := r.InstantiateModuleFromCode(testCtx, recursionWasm)
+		require.NoError(t, err)
+
+		recurse := module.ExportedFunction("recurse")
+		_, err = recurse.Call(testCtx, 99)
+		require.NoError(t, err)
+
+		_, err = recurse.Call(testCtx, 100)
+		require.ErrorIs(t, err, wasmruntime.ErrRuntimeCallStackOverflow)
+
+		// A ceiling above the default allows deeper recursion.
+		r = wazero.NewRuntimeWithConfig(config.WithCallStackCeiling(10000))
+		defer r.Close(testCtx)
+
+		module, err = r.InstantiateModuleFromCode(testCtx, recursionWasm)
+		require.NoError(t, err)
+
+		_, err = module.ExportedFunction("recurse").Call(testCtx, 5000)
+		require.NoError(t, err)
+	})
+	t.Run("value stack", func(t *testing.T) {
+		// hugestack.wasm pushes 100 values.
+		r := wazero.NewRuntimeWithConfig(config.WithValueStackCeiling(128 * 8))
+		defer r.Close(testCtx)
+
+		module, err := r.InstantiateModuleFromCode(testCtx, hugestackWasm)
+		require.NoError(t, err)
+
+		_, err = module.ExportedFunction("main").Call(testCtx)
+		require.NoError(t, err)
+
+		r = wazero.NewRuntimeWithConfig(config.WithValueStackCeiling(64 * 8))
+		defer r.Close(testCtx)
+
+		module, err = r.InstantiateModuleFromCode(testCtx, hugestackWasm)
+		require.NoError(t, err)
+
+		_, err = module.ExportedFunction("main").Call(testCtx)
+		require.ErrorIs(t, err, wasmruntime.ErrRuntimeValueStackOverflow)
+	})
+}
+
+// testFuel ensures calls consume the same fuel on every engine, and are interrupted when it is exhausted.
+func testFuel(t *testing.T, config wazero.RuntimeConfig) {
+	r := wazero.NewRuntimeWithConfig(config.WithFuelCosts(func(instruction string) uint32 {
+		if instruction == "call" {
+			return 10
+		}
+		return 1
+	}))
+	defer r.Close(testCtx)
+
+	t.Run("exhausted", func(t *testing.T) {
+		module, err := r.InstantiateModuleFromCode(testCtx, spinWasm)
+		require.NoError(t, err)
+		defer module.Close(testCtx)
+
+		fuel := experimental.Fuel(1000)
+		_, err = module.ExportedFunction("spin").Call(experimental.WithFuel(testCtx, &fuel))
+		require.ErrorIs(t, err, experimental.ErrFuelExhausted)
+		// Each iteration of the loop consumes 1 fuel for br, so it stops when none is left.
+		require.Equal(t, experimental.Fuel(0), fuel)
+	})
+	t.Run("consumed", func(t *testing.T) {
+		module, err := r.InstantiateModuleFromCode(testCtx, recursionWasm)
+		require.NoError(t, err)
+		defer module.Close(testCtx)
+		recurse := module.ExportedFunction("recurse")
+
+		// Each call but the innermost consumes 17 fuel: 2 for the entry, 14 for the then block including the call,
+		// and 1 for the end. The innermost consumes 3.
+		fuel := experimental.Fuel(200)
+		_, err = recurse.Call(experimental.WithFuel(testCtx, &fuel), 10)
+		require.NoError(t, err)
+		require.Equal(t, experimental.Fuel(200-173), fuel)
+
+		// The remaining fuel is less than the cost of the block which couldn't execute: 27-2-14-2 = 9 < 14.
+		_, err = recurse.Call(experimental.WithFuel(testCtx, &fuel), 10)
+		require.ErrorIs(t, err, experimental.ErrFuelExhausted)
+		require.Equal(t, experimental.Fuel(9), fuel)
+
+		// Without a budget, the fuel is unlimited.
+		_, err = recurse.Call(testCtx, 1000)
+		require.NoError(t, err)
+	})
+	t.Run("called by host function", func(t *testing.T) {
+		module, err := r.InstantiateModuleFromCode(testCtx, recursionWasm)
+		require.NoError(t, err)
+		defer module.Close(testCtx)
+		recurse := module.ExportedFunction("recurse")
+
+		// Host functions don't consume fuel, but the calls they make with their context do.
+		host, err := r.NewModuleBuilder("host").
+			ExportFunction("recurse", func(ctx context.Context, depth uint32) error {
+				_, err := recurse.Call(ctx, uint64(depth))
+				return err
+			}).
+			Instantiate(testCtx)
+		require.NoError(t, err)
+		defer host.Close(testCtx)
+
+		fuel := experimental.Fuel(200)
+		_, err = host.ExportedFunction("recurse").Call(experimental.WithFuel(testCtx, &fuel), 10)
+		require.NoError(t, err)
+		require.Equal(t, experimental.Fuel(200-173), fuel)
+	})
+}
+
+func testTables(t *testing.T, r wazero.Runtime) {
+	host, err := r.NewModuleBuilder("host").
+		ExportFunction("forty_two", func() uint32 { return 42 }).
+		Instantiate(testCtx)
+	require.NoError(t, err)
+	defer host.Close(testCtx)
+
+	module, err := r.InstantiateModuleFromCode(testCtx, tablesWasm)
+	require.NoError(t, err)
+	defer module.Close(testCtx)
+
+	call := module.ExportedFunction("call")
+	requireCall := func(index, expected uint64) {
+		results, err := call.Call(testCtx, index)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{expected}, results)
+	}
+
+	t.Run("funcref", func(t *testing.T) {
+		table := module.ExportedTable("funcs")
+		require.Equal(t, api.ValueTypeFuncref, table.Type())
+		require.Equal(t, uint32(2), table.Size(testCtx))
+
+		// The element segment initialized the first element.
+		one, ok := table.Function(testCtx, 0)
+		require.True(t, ok)
+		results, err := one.Call(testCtx)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{1}, results)
+
+		// The second element is a null reference.
+		fn, ok := table.Function(testCtx, 1)
+		require.True(t, ok)
+		require.Nil(t, fn)
+		_, err = call.Call(testCtx, 1)
+		require.ErrorIs(t, err, wasmruntime.ErrRuntimeInvalidTableAccess)
+
+		// The guest can call functions set by the host, whether defined in wasm or in Go.
+		require.True(t, table.SetFunction(testCtx, 1, module.ExportedFunction("two")))
+		requireCall(1, 2)
+
+		previousSize, ok := table.Grow(testCtx, 1)
+		require.True(t, ok)
+		require.Equal(t, uint32(2), previousSize)
+		require.True(t, table.SetFunction(testCtx, 2, host.ExportedFunction("forty_two")))
+		requireCall(2, 42)
+
+		fn, ok = table.Function(testCtx, 2)
+		require.True(t, ok)
+		results, err = fn.Call(testCtx)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{42}, results)
+
+		// Out of range.
+		require.False(t, table.SetFunction(testCtx, 3, one))
+
+		// Functions of another runtime are rejected.
+		other := wazero.NewRuntime()
+		defer other.Close(testCtx)
+		otherHost, err := other.NewModuleBuilder("host").
+			ExportFunction("forty_two", func() uint32 { return 42 }).
+			Instantiate(testCtx)
+		require.NoError(t, err)
+		require.False(t, table.SetFunction(testCtx, 2, otherHost.ExportedFunction("forty_two")))
+	})
+
+	t.Run("externref", func(t *testing.T) {
+		table := module.ExportedTable("externs")
+		require.Equal(t, api.ValueTypeExternref, table.Type())
+
+		require.True(t, table.SetExternref(testCtx, 0, 0xfeed))
+		results, err := module.ExportedFunction("get_externref").Call(testCtx, 0)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{0xfeed}, results)
+
+		ref, ok := table.Externref(testCtx, 0)
+		require.True(t, ok)
+		require.Equal(t, uintptr(0xfeed), ref)
+	})
+
+	require.Nil(t, module.ExportedTable("call"))
+}
+
 func testUnreachable(t *testing.T, r wazero.Runtime) {
 	callUnreachable := func(nil api.Module) {
 		panic("panic in host function")
@@ -140,6 +440,41 @@ wasm stack trace:
 	require.Equal(t, exp, err.Error())
 }
 
+type hostError struct {
+	code uint32
+}
+
+func (e *hostError) Error() string {
+	return fmt.Sprintf("host error %d", e.code)
+}
+
+func testHostFunctionError(t *testing.T, r wazero.Runtime) {
+	expectedErr := &hostError{code: 42}
+	_, err := r.NewModuleBuilder("host").ExportFunction("cause_unreachable", func() error {
+		return fmt.Errorf("wrapped: %w", expectedErr)
+	}).Instantiate(testCtx)
+	require.NoError(t, err)
+
+	module, err := r.InstantiateModuleFromCode(testCtx, unreachableWasm)
+	require.NoError(t, err)
+	defer module.Close(testCtx)
+
+	_, err = module.ExportedFunction("main").Call(testCtx)
+	exp := `wrapped: host error 42
+wasm stack trace:
+	host.cause_unreachable()
+	.two()
+	.one()
+	.main()`
+	require.EqualError(t, err, exp)
+
+	// The error returned by the host function can be unwrapped, so callers can tell it apart from a runtime bug.
+	require.True(t, errors.Is(err, expectedErr))
+	var actualErr *hostError
+	require.True(t, errors.As(err, &actualErr))
+	require.Equal(t, uint32(42), actualErr.code)
+}
+
 func testRecursiveEntry(t *testing.T, r wazero.Runtime) {
 	hostfunc := func(mod api.Module) {
 		_, err := mod.ExportedFunction("called_by_host_func").Call(testCtx)
@@ -352,6 +687,140 @@ func testHostFunctionNumericParameter(t *testing.T, r wazero.Runtime) {
 	}
 }
 
+// testHostFunctionResults ensures host functions can return multiple results, and pass externref and funcref values
+// in both directions.
+func testHostFunctionResults(t *testing.T, r wazero.Runtime) {
+	type dog struct {
+		name string
+	}
+
+	hostObj := &dog{name: "hello"}
+	ref := uintptr(unsafe.Pointer(hostObj))
+	host, err := r.NewModuleBuilder("host").
+		ExportFunction("divmod", func(x, y uint32) (uint32, uint32) {
+			return x / y, x % y
+		}).
+		ExportFunction("refs", func(ctx context.Context, d uintptr, f api.Function) (uintptr, api.Function) {
+			require.Equal(t, ref, d)
+			results, err := f.Call(ctx, 21)
+			require.NoError(t, err)
+			require.Equal(t, []uint64{42}, results)
+			return d, f
+		}).Instantiate(testCtx)
+	require.NoError(t, err)
+	defer host.Close(testCtx)
+
+	module, err := r.InstantiateModuleFromCode(testCtx, hostResultsWasm)
+	require.NoError(t, err)
+	defer module.Close(testCtx)
+
+	results, err := module.ExportedFunction("divmod").Call(testCtx, 17, 5)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{3, 2}, results)
+
+	results, err = module.ExportedFunction("round_trip").Call(testCtx, api.EncodeExternref(ref), 5)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{api.EncodeExternref(ref), 10}, results)
+}
+
+// testHostFunctionV128 ensures host functions exported via reflection pass v128 values as [2]uint64 in both
+// directions, with the low lane first.
+func testHostFunctionV128(t *testing.T, r wazero.Runtime) {
+	host, err := r.NewModuleBuilder("host").
+		ExportFunction("swap_halves", func(v [2]uint64) [2]uint64 {
+			return [2]uint64{v[1], v[0]}
+		}).Instantiate(testCtx)
+	require.NoError(t, err)
+	defer host.Close(testCtx)
+
+	module, err := r.InstantiateModuleFromCode(testCtx, hostV128Wasm)
+	require.NoError(t, err)
+	defer module.Close(testCtx)
+
+	results, err := module.ExportedFunction("swap_halves").Call(testCtx, 1, math.MaxUint64)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{math.MaxUint64, 1}, results)
+}
+
+// testGoModuleFunc ensures host functions exported via ExportGoModuleFunc see their params and the caller, and that
+// results are read back correctly when there are more results than params.
+func testGoModuleFunc(t *testing.T, r wazero.Runtime) {
+	importedName := t.Name() + "-imported"
+	importingName := t.Name() + "-importing"
+
+	var importing api.Module
+	i32, i64 := api.ValueTypeI32, api.ValueTypeI64
+	imported, err := r.NewModuleBuilder(importedName).
+		ExportGoModuleFunc("add", func(ctx context.Context, m api.Module, stack []uint64) {
+			require.Equal(t, testCtx, ctx)
+			require.Equal(t, importing, m)
+			stack[0] = uint64(uint32(stack[0]) + uint32(stack[1]))
+		}, []api.ValueType{i32, i32}, []api.ValueType{i32}).
+		ExportGoModuleFunc("split", func(ctx context.Context, m api.Module, stack []uint64) {
+			v := stack[0]
+			stack[0], stack[1] = uint64(uint32(v)), v>>32
+		}, []api.ValueType{i64}, []api.ValueType{i32, i32}).
+		Instantiate(testCtx)
+	require.NoError(t, err)
+	defer imported.Close(testCtx)
+
+	importing, err = r.InstantiateModuleFromCode(testCtx, []byte(fmt.Sprintf(`(module $%[1]s
+	(import "%[2]s" "add" (func $add (param i32 i32) (result i32)))
+	(import "%[2]s" "split" (func $split (param i64) (result i32 i32)))
+	(func $sum_halves (param i64 i32) (result i32)
+		local.get 1
+		local.get 0
+		call $split
+		call $add
+		i32.add
+	)
+	(export "sum_halves" (func $sum_halves))
+)`, importingName, importedName)))
+	require.NoError(t, err)
+	defer importing.Close(testCtx)
+
+	results, err := importing.ExportedFunction("sum_halves").Call(testCtx, 0x00000003_00000002, 10)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{15}, results)
+
+	// Calling the host function directly from Go must not reuse the caller's params as the stack.
+	params := []uint64{0x00000003_00000002}
+	results, err = imported.ExportedFunction("split").Call(testCtx, params...)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{2, 3}, results)
+	require.Equal(t, []uint64{0x00000003_00000002}, params)
+}
+
+// testGoModuleFuncAllocs ensures calling a host function exported via ExportGoModuleFunc doesn't allocate: the
+// allocations of a Call are the same regardless of how many times the guest calls the host function.
+//
+// Note: This isn't parallel with other tests, as testing.AllocsPerRun counts allocations of the whole process.
+func testGoModuleFuncAllocs(t *testing.T, config wazero.RuntimeConfig) {
+	r := wazero.NewRuntimeWithConfig(config)
+	defer r.Close(testCtx)
+
+	i64 := api.ValueTypeI64
+	_, err := r.NewModuleBuilder("host").
+		ExportGoModuleFunc("inc", func(ctx context.Context, m api.Module, stack []uint64) {
+			stack[0]++
+		}, []api.ValueType{i64}, []api.ValueType{i64}).
+		Instantiate(testCtx)
+	require.NoError(t, err)
+
+	module, err := r.InstantiateModuleFromCode(testCtx, hostLoopWasm)
+	require.NoError(t, err)
+	incN := module.ExportedFunction("inc_n")
+
+	allocsPerCall := func(n uint64) float64 {
+		return testing.AllocsPerRun(100, func() {
+			results, err := incN.Call(testCtx, n)
+			require.NoError(t, err)
+			require.Equal(t, []uint64{n}, results)
+		})
+	}
+	require.Equal(t, allocsPerCall(1), allocsPerCall(1000))
+}
+
 func callReturnImportSource(importedModule, importingModule string) []byte {
 	return []byte(fmt.Sprintf(`(module $%[1]s
 	;; test an imported function by re-exporting it
@@ -529,6 +998,128 @@ func testMemOps(t *testing.T, r wazero.Runtime) {
 	require.NoError(t, err)
 }
 
+func testAtomics(t *testing.T, r wazero.Runtime) {
+	compiled, err := r.CompileModule(testCtx, atomicsWasm, compileConfig)
+	require.NoError(t, err)
+	module, err := r.InstantiateModule(testCtx, compiled, moduleConfig.WithName("atomics"))
+	require.NoError(t, err)
+	defer module.Close(testCtx)
+
+	// The shared memory is imported into another module instance, which increments the same address.
+	importing, err := r.InstantiateModuleFromCode(testCtx, atomicsImportWasm)
+	require.NoError(t, err)
+	defer importing.Close(testCtx)
+
+	adds := []api.Function{module.ExportedFunction("add"), importing.ExportedFunction("add")}
+	load := module.ExportedFunction("load")
+
+	// Concurrent increments must not be lost.
+	const goroutines, increments = 4, 100
+	var wg sync.WaitGroup
+	wg.Add(goroutines)
+	for i := 0; i < goroutines; i++ {
+		add := adds[i%len(adds)]
+		go func() {
+			defer wg.Done()
+			for j := 0; j < increments; j++ {
+				_, err := add.Call(testCtx, 1)
+				require.NoError(t, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	results, err := load.Call(testCtx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(goroutines*increments), results[0])
+
+	// cmpxchg only replaces the expected value, and returns the previous one either way.
+	cmpxchg := module.ExportedFunction("cmpxchg")
+	results, err = cmpxchg.Call(testCtx, 1, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(goroutines*increments), results[0])
+	results, err = cmpxchg.Call(testCtx, goroutines*increments, 2)
+	require.NoError(t, err)
+	require.Equal(t, uint64(goroutines*increments), results[0])
+	results, err = load.Call(testCtx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), results[0])
+
+	// The narrow read-modify-write only touches the lowest 16 bits, and returns the previous value zero-extended.
+	xchg16 := module.ExportedFunction("xchg16")
+	_, err = xchg16.Call(testCtx, 0xffff_ffff)
+	require.NoError(t, err)
+	results, err = xchg16.Call(testCtx, 1)
+	require.NoError(t, err)
+	require.Equal(t, uint64(0xffff), results[0])
+
+	wait, notify := module.ExportedFunction("wait"), module.ExportedFunction("notify")
+
+	// The value at the address isn't the expected one.
+	results, err = wait.Call(testCtx, 1, math.MaxUint64 /* -1: no timeout */)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), results[0]) // not-equal
+
+	results, err = wait.Call(testCtx, 0, 1000 /* nanoseconds */)
+	require.NoError(t, err)
+	require.Equal(t, uint64(2), results[0]) // timed-out
+
+	// Notify until the waiting goroutine is woken up.
+	woken := make(chan uint64)
+	go func() {
+		results, err := wait.Call(testCtx, 0, math.MaxUint64 /* -1: no timeout */)
+		require.NoError(t, err)
+		woken <- results[0]
+	}()
+	for {
+		results, err = notify.Call(testCtx, 1)
+		require.NoError(t, err)
+		if results[0] == 1 {
+			break
+		}
+		runtime.Gosched()
+	}
+	require.Equal(t, uint64(0), <-woken) // ok
+
+	_, err = module.ExportedFunction("load_unaligned").Call(testCtx)
+	require.EqualError(t, err, `wasm error: unaligned atomic
+wasm stack trace:
+	atomics.[6]() i32`)
+}
+
+// testSharedMemoryGrow ensures a call sees a shared memory grown by another thread while it's running, instead of only
+// the length the memory had when the call started.
+func testSharedMemoryGrow(t *testing.T, r wazero.Runtime) {
+	module, err := r.InstantiateModuleFromCode(testCtx, sharedGrowWasm)
+	require.NoError(t, err)
+	defer module.Close(testCtx)
+
+	type accessResult struct {
+		results []uint64
+		err     error
+	}
+	accessed := make(chan accessResult)
+	go func() {
+		results, err := module.ExportedFunction("access").Call(testCtx)
+		accessed <- accessResult{results, err}
+	}()
+
+	// Grow only after "access" started, so that it must see the length change.
+	for {
+		if started, ok := module.Memory().ReadUint32Le(testCtx, 4); ok && started == 1 {
+			break
+		}
+		runtime.Gosched()
+	}
+	results, err := module.ExportedFunction("grow").Call(testCtx)
+	require.NoError(t, err)
+	require.Equal(t, uint64(1), results[0])
+
+	res := <-accessed
+	require.NoError(t, res.err)
+	require.Equal(t, []uint64{2, 42}, res.results) // memory.size and the byte stored at the end of the second page.
+}
+
 func testMultipleInstantiation(t *testing.T, r wazero.Runtime) {
 	compiled, err := r.CompileModule(testCtx, []byte(`(module $test
 		(memory 1)
@@ -566,3 +1157,344 @@ func testMultipleInstantiation(t *testing.T, r wazero.Runtime) {
 		require.Equal(t, uint64(1000), after)
 	}
 }
+
+func testTailCalls(t *testing.T, r wazero.Runtime) {
+	host, err := r.NewModuleBuilder("host").
+		ExportFunction("double", func(v uint64) uint64 { return v * 2 }).
+		Instantiate(testCtx)
+	require.NoError(t, err)
+	defer host.Close(testCtx)
+
+	module, err := r.InstantiateModuleFromCode(testCtx, tailCallWasm)
+	require.NoError(t, err)
+	defer module.Close(testCtx)
+
+	// The recursion is far deeper than the call stack ceiling, so this only succeeds when tail calls reuse the frame.
+	const n = 1_000_000
+	for _, name := range []string{"sum", "sum_indirect"} {
+		results, err := module.ExportedFunction(name).Call(testCtx, n, 0)
+		require.NoError(t, err)
+		require.Equal(t, uint64(n*(n+1)/2), results[0])
+	}
+
+	// Tail calls between functions of different numbers of parameters, and results. As n is even, the function called
+	// returns, which is 1, 2 for more and 3, 4 for fewer.
+	for _, tc := range []struct {
+		name   string
+		params []uint64
+		last   []uint64
+	}{
+		{name: "more", params: []uint64{n, 0}, last: []uint64{1, 2}},
+		{name: "fewer", params: []uint64{n, 0, 0, 0, 0}, last: []uint64{3, 4}},
+		{name: "more_indirect", params: []uint64{n, 0}, last: []uint64{1, 2}},
+		{name: "fewer_indirect", params: []uint64{n, 0, 0, 0, 0}, last: []uint64{3, 4}},
+	} {
+		results, err := module.ExportedFunction(tc.name).Call(testCtx, tc.params...)
+		require.NoError(t, err)
+		require.Equal(t, append([]uint64{n * (n + 1) / 2}, tc.last...), results, tc.name)
+	}
+
+	// Host functions can be tail-called, from the entry function or not.
+	for _, name := range []string{"double_tail", "double_nested"} {
+		results, err := module.ExportedFunction(name).Call(testCtx, 21)
+		require.NoError(t, err)
+		require.Equal(t, uint64(42), results[0])
+	}
+
+	_, err = module.ExportedFunction("type_mismatch").Call(testCtx)
+	require.Contains(t, err.Error(), "indirect call type mismatch")
+
+	t.Run("host function sees the memory of the tail caller", func(t *testing.T) {
+		host, err := r.NewModuleBuilder("memory_host").
+			ExportFunction("memory_size", func(ctx context.Context, m api.Module) uint32 { return m.Memory().Size(ctx) }).
+			Instantiate(testCtx)
+		require.NoError(t, err)
+		defer host.Close(testCtx)
+
+		tailCaller, err := r.InstantiateModuleFromCode(testCtx, []byte(`(module $tail_caller
+	(import "memory_host" "memory_size" (func $memory_size (result i32)))
+	(memory 2)
+	(func $tail_call (result i32) return_call $memory_size)
+	(export "memory_size" (func $tail_call))
+)`))
+		require.NoError(t, err)
+		defer tailCaller.Close(testCtx)
+
+		caller, err := r.InstantiateModuleFromCode(testCtx, []byte(`(module $caller
+	(import "tail_caller" "memory_size" (func $memory_size (result i32)))
+	(memory 1)
+	(func $call (result i32) call $memory_size)
+	(export "memory_size" (func $call))
+)`))
+		require.NoError(t, err)
+		defer caller.Close(testCtx)
+
+		results, err := caller.ExportedFunction("memory_size").Call(testCtx)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2*wasm.MemoryPageSize), results[0])
+	})
+
+	t.Run("function imported from another module", func(t *testing.T) {
+		callee, err := r.InstantiateModuleFromCode(testCtx, []byte(`(module $callee
+	(memory 3)
+	(func $sum (param i32 i32 i32) (result i32)
+		local.get 0
+		local.get 1
+		i32.add
+		local.get 2
+		i32.add
+		memory.size
+		i32.add
+	)
+	(export "sum" (func $sum))
+)`))
+		require.NoError(t, err)
+		defer callee.Close(testCtx)
+
+		// The callee has more parameters than the tail caller, and adds the size of its own memory. The caller of the
+		// tail caller adds the size of its memory after the return, so these ensure the module is switched both ways.
+		tailCaller, err := r.InstantiateModuleFromCode(testCtx, []byte(`(module $imported_tail_caller
+	(import "callee" "sum" (func $sum (param i32 i32 i32) (result i32)))
+	(memory 1)
+	(func $tail_call (param i32) (result i32)
+		local.get 0
+		local.get 0
+		local.get 0
+		return_call $sum
+	)
+	(func $call (param i32) (result i32)
+		local.get 0
+		call $tail_call
+		memory.size
+		i32.add
+	)
+	(export "tail_call" (func $tail_call))
+	(export "call" (func $call))
+)`))
+		require.NoError(t, err)
+		defer tailCaller.Close(testCtx)
+
+		results, err := tailCaller.ExportedFunction("tail_call").Call(testCtx, 1)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1+1+1+3), results[0])
+
+		results, err = tailCaller.ExportedFunction("call").Call(testCtx, 1)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1+1+1+3+1), results[0])
+	})
+}
+
+func testExceptions(t *testing.T, r wazero.Runtime) {
+	host, err := r.NewModuleBuilder("host").
+		ExportFunction("throw", func(ctx context.Context, m api.Module, v uint32) {
+			panic(&api.Exception{Tag: m.ExportedTag("e"), Values: []uint64{uint64(v)}})
+		}).
+		Instantiate(testCtx)
+	require.NoError(t, err)
+	defer host.Close(testCtx)
+
+	module, err := r.InstantiateModuleFromCode(testCtx, exceptionsWasm)
+	require.NoError(t, err)
+	defer module.Close(testCtx)
+
+	for _, tc := range []struct {
+		name     string
+		params   []uint64
+		expected uint64
+	}{
+		{name: "catch", params: []uint64{37}, expected: 42},
+		{name: "catch_local", params: []uint64{23}, expected: 123},
+		{name: "catch_all", expected: 2},
+		{name: "rethrow", params: []uint64{32}, expected: 42},
+		{name: "delegate", params: []uint64{41}, expected: 42},
+		{name: "host", params: []uint64{42}, expected: 42},
+		{name: "catch_deep", params: []uint64{100}, expected: 1010},
+	} {
+		results, err := module.ExportedFunction(tc.name).Call(testCtx, tc.params...)
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expected, results[0], tc.name)
+	}
+
+	// An uncaught exception is returned as an error.
+	_, err = module.ExportedFunction("throw").Call(testCtx, 42)
+	var exc *api.Exception
+	require.True(t, errors.As(err, &exc))
+	require.Equal(t, module.ExportedTag("e"), exc.Tag)
+	require.Equal(t, []uint64{42}, exc.Values)
+
+	// Traps are not exceptions.
+	_, err = module.ExportedFunction("trap").Call(testCtx)
+	require.Contains(t, err.Error(), "unreachable")
+}
+
+func testMultiMemory(t *testing.T, r wazero.Runtime) {
+	module, err := r.InstantiateModuleFromCode(testCtx, multiMemoryWasm)
+	require.NoError(t, err)
+	defer module.Close(testCtx)
+
+	memory0, memory1 := module.ExportedMemory("memory0"), module.ExportedMemory("memory1")
+	require.Equal(t, memory0, module.Memory())
+
+	call := func(name string, params ...uint64) []uint64 {
+		results, err := module.ExportedFunction(name).Call(testCtx, params...)
+		require.NoError(t, err, name)
+		return results
+	}
+
+	// The active data segment is applied to the second memory.
+	require.Equal(t, []uint64{42}, call("load", 0))
+	require.Equal(t, []uint64{0}, call("load0", 0))
+
+	call("store", 4, 0x01020304)
+	v, ok := memory1.ReadUint32Le(testCtx, 4)
+	require.True(t, ok)
+	require.Equal(t, uint32(0x01020304), v)
+	v, ok = memory0.ReadUint32Le(testCtx, 4)
+	require.True(t, ok)
+	require.Equal(t, uint32(0), v)
+
+	call("copy", 16, 4, 4)
+	v, ok = memory0.ReadUint32Le(testCtx, 16)
+	require.True(t, ok)
+	require.Equal(t, uint32(0x01020304), v)
+
+	require.Equal(t, []uint64{1}, call("size"))
+	require.Equal(t, []uint64{1}, call("grow", 1))
+	require.Equal(t, []uint64{2}, call("size"))
+	require.Equal(t, []uint64{0xffffffff}, call("grow", 1)) // Exceeds the maximum of the second memory.
+	require.Equal(t, uint32(1), memory0.Size(testCtx)/65536)
+
+	// The grown second memory is accessible while the first one isn't.
+	call("fill", 70000, 0x55, 3)
+	require.Equal(t, []uint64{0x55}, call("load", 70002))
+	_, err = module.ExportedFunction("load0").Call(testCtx, 70002)
+	require.Contains(t, err.Error(), "out of bounds memory access")
+	_, err = module.ExportedFunction("copy").Call(testCtx, 70000, 0, 1)
+	require.Contains(t, err.Error(), "out of bounds memory access")
+}
+
+func testMemory64(t *testing.T, r wazero.Runtime) {
+	module, err := r.InstantiateModuleFromCode(testCtx, memory64Wasm)
+	require.NoError(t, err)
+	defer module.Close(testCtx)
+
+	memory := module.ExportedMemory("memory")
+
+	call := func(name string, params ...uint64) []uint64 {
+		results, err := module.ExportedFunction(name).Call(testCtx, params...)
+		require.NoError(t, err, name)
+		return results
+	}
+	requireOutOfBounds := func(name string, params ...uint64) {
+		_, err := module.ExportedFunction(name).Call(testCtx, params...)
+		require.Error(t, err, name)
+		require.Contains(t, err.Error(), "out of bounds memory access", name)
+	}
+
+	require.Equal(t, []uint64{42}, call("load", 0))
+
+	// Addresses and offsets beyond 32 bits are out of bounds rather than wrapping around.
+	requireOutOfBounds("load", 1<<32)
+	requireOutOfBounds("load", 1<<40)
+	requireOutOfBounds("load_far", 0)
+
+	call("store", 8, 0x0102030405060708)
+	buf, ok := memory.Read64(testCtx, 8, 8)
+	require.True(t, ok)
+	require.Equal(t, []byte{8, 7, 6, 5, 4, 3, 2, 1}, buf)
+	_, ok = memory.Read64(testCtx, 1<<32, 1)
+	require.False(t, ok)
+	v64, ok := memory.ReadUint64Le64(testCtx, 8)
+	require.True(t, ok)
+	require.Equal(t, uint64(0x0102030405060708), v64)
+	require.True(t, memory.WriteUint32Le64(testCtx, 48, 0x090a0b0c))
+	require.Equal(t, []uint64{0x0c}, call("load", 48))
+	_, ok = memory.ReadUint64Le64(testCtx, 1<<32)
+	require.False(t, ok)
+
+	require.Equal(t, []uint64{1}, call("size"))
+	require.Equal(t, []uint64{1}, call("grow", 1))
+	require.Equal(t, []uint64{2}, call("size"))
+	require.Equal(t, []uint64{0xffffffffffffffff}, call("grow", 1)) // Exceeds the maximum.
+	require.Equal(t, []uint64{0xffffffffffffffff}, call("grow", 1<<33))
+
+	call("fill", 70000, 0x55, 3)
+	require.Equal(t, []uint64{0x55}, call("load", 70002))
+	requireOutOfBounds("fill", 1<<32, 0x55, 1)
+	requireOutOfBounds("fill", 0, 0x55, 1<<32)
+
+	call("copy", 16, 8, 8)
+	buf, ok = memory.Read64(testCtx, 16, 8)
+	require.True(t, ok)
+	require.Equal(t, []byte{8, 7, 6, 5, 4, 3, 2, 1}, buf)
+	requireOutOfBounds("copy", 0, 1<<32, 1)
+	requireOutOfBounds("copy", 0, 0, 1<<40)
+
+	call("init", 32, 1, 3)
+	buf, ok = memory.Read64(testCtx, 32, 3)
+	require.True(t, ok)
+	require.Equal(t, []byte("ell"), buf)
+	requireOutOfBounds("init", 1<<32, 0, 1)
+
+	require.Equal(t, []uint64{0x05060708}, call("atomic_add", 8, 1))
+	v, ok := memory.ReadUint32Le(testCtx, 8)
+	require.True(t, ok)
+	require.Equal(t, uint32(0x05060709), v)
+	requireOutOfBounds("atomic_add", 1<<32, 1)
+}
+
+func testExtendedConst(t *testing.T, r wazero.Runtime) {
+	env, err := r.NewModuleBuilder("env").
+		ExportGlobalI32("__memory_base", 1024).
+		ExportGlobalI32("__table_base", 2).
+		ExportMemory("memory", 1).
+		Instantiate(testCtx)
+	require.NoError(t, err)
+	defer env.Close(testCtx)
+
+	module, err := r.InstantiateModuleFromCode(testCtx, extendedConstWasm)
+	require.NoError(t, err)
+	defer module.Close(testCtx)
+
+	call := func(name string, params ...uint64) []uint64 {
+		results, err := module.ExportedFunction(name).Call(testCtx, params...)
+		require.NoError(t, err, name)
+		return results
+	}
+
+	// The global and the data segment are relocated by __memory_base.
+	require.Equal(t, []uint64{1040}, call("data_ptr"))
+	require.Equal(t, []uint64{'h'}, call("load", 1040))
+	buf, ok := env.Memory().Read(testCtx, 1040, 5)
+	require.True(t, ok)
+	require.Equal(t, "hello", string(buf))
+
+	// The element segment is relocated by __table_base.
+	require.Equal(t, []uint64{42}, call("call", 3))
+	_, err = module.ExportedFunction("call").Call(testCtx, 1)
+	require.Error(t, err)
+
+	require.Equal(t, uint64(3<<40), module.ExportedGlobal("wide").Get(testCtx))
+}
+
+func testVectorSelect(t *testing.T, r wazero.Runtime) {
+	module, err := r.InstantiateModuleFromCode(testCtx, vectorSelectWasm)
+	require.NoError(t, err)
+	defer module.Close(testCtx)
+
+	for _, name := range []string{"select", "typed_select"} {
+		fn := module.ExportedFunction(name)
+
+		results, err := fn.Call(testCtx, 1)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{1, 2}, results, name)
+
+		results, err = fn.Call(testCtx, 0)
+		require.NoError(t, err)
+		require.Equal(t, []uint64{3, 4}, results, name)
+	}
+
+	results, err := module.ExportedFunction("drop").Call(testCtx)
+	require.NoError(t, err)
+	require.Equal(t, []uint64{7}, results)
+}
@@ -0,0 +1,678 @@
+package adhoc
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/internal/leb128"
+	"github.com/tetratelabs/wazero/internal/testing/require"
+	"github.com/tetratelabs/wazero/internal/wasm"
+	"github.com/tetratelabs/wazero/internal/wasm/binary"
+)
+
+// TestEngineCompiler_Features20220419Operations ensures the compiler returns the same results and traps as the
+// interpreter for each operation finished in WebAssembly 2.0 (20220419) which can be called with arbitrary operands.
+//
+// The interpreter doesn't depend on the architecture, so this running on both amd64 and arm64 guarantees the compiler
+// backends behave the same way. Operations left out (control flow, variables, tables and the rest of bulk memory
+// operations) are covered by the spectests.
+func TestEngineCompiler_Features20220419Operations(t *testing.T) {
+	if !wazero.CompilerSupported {
+		t.Skip()
+	}
+
+	ops := features20220419Operations(t)
+	bin := operationsModule(ops)
+
+	compiled := instantiateOperations(t, wazero.NewRuntimeConfigCompiler(), bin)
+	interpreted := instantiateOperations(t, wazero.NewRuntimeConfigInterpreter(), bin)
+
+	for i, op := range ops {
+		name := strconv.Itoa(i)
+		op := op
+		t.Run(op.name, func(t *testing.T) {
+			for _, params := range op.inputs() {
+				expected, expectedErr := interpreted.ExportedFunction(name).Call(testCtx, params...)
+				actual, err := compiled.ExportedFunction(name).Call(testCtx, params...)
+				if expectedErr != nil {
+					require.Error(t, err, "params: %#x", params)
+					require.Equal(t, expectedErr.Error(), err.Error(), "params: %#x", params)
+					continue
+				}
+				require.NoError(t, err, "params: %#x", params)
+				require.True(t, op.equal(expected, actual), "params: %#x, expected %#x, but was %#x", params, expected, actual)
+			}
+		})
+	}
+}
+
+// operation is a function which applies a single instruction to its params.
+type operation struct {
+	name   string
+	params []wasm.ValueType
+	result wasm.ValueType
+	// body is the function body after all params are pushed onto the stack, excluding the trailing OpcodeEnd.
+	body []byte
+	// nanLaneSize is non-zero if the result is floating point, in which case results are equal if both are NaN
+	// regardless of their bits. This is the size in bytes of each floating point lane, e.g. 4 for f32 and f32x4.
+	nanLaneSize int
+}
+
+func instantiateOperations(t *testing.T, config wazero.RuntimeConfig, bin []byte) api.Module {
+	r := wazero.NewRuntimeWithConfig(config.WithWasmCore2())
+	m, err := r.InstantiateModuleFromCode(testCtx, bin)
+	require.NoError(t, err)
+	t.Cleanup(func() { m.Close(testCtx) })
+	return m
+}
+
+// operationsModule returns a module exporting each operation as its index, with one page of memory whose edges are
+// initialized with non-zero data.
+func operationsModule(ops []operation) []byte {
+	m := &wasm.Module{
+		MemorySection: []*wasm.Memory{{Min: 1, Cap: 1, Max: 1, IsMaxEncoded: true}},
+	}
+	for i, op := range ops {
+		m.TypeSection = append(m.TypeSection, &wasm.FunctionType{Params: op.params, Results: []wasm.ValueType{op.result}})
+		m.FunctionSection = append(m.FunctionSection, wasm.Index(i))
+
+		var body []byte
+		for j := range op.params {
+			body = append(body, wasm.OpcodeLocalGet, byte(j))
+		}
+		body = append(append(body, op.body...), wasm.OpcodeEnd)
+		m.CodeSection = append(m.CodeSection, &wasm.Code{Body: body})
+		m.ExportSection = append(m.ExportSection, &wasm.Export{Type: wasm.ExternTypeFunc, Name: strconv.Itoa(i), Index: wasm.Index(i)})
+	}
+
+	data := make([]byte, 64)
+	for i := range data {
+		data[i] = byte(i*37 + 11)
+	}
+	for _, offset := range []int32{0, int32(wasm.MemoryPageSize) - 64} {
+		m.DataSection = append(m.DataSection, &wasm.DataSegment{
+			OffsetExpression: &wasm.ConstantExpression{Opcode: wasm.OpcodeI32Const, Data: leb128.EncodeInt32(offset)},
+			Init:             data,
+		})
+	}
+	return binary.EncodeModule(m)
+}
+
+// inputs returns the params to call the operation with. With more than one param, this covers all combinations of
+// the first two params' values, choosing the rest of them from those combinations.
+func (op *operation) inputs() (ret [][]uint64) {
+	if len(op.params) == 0 {
+		return [][]uint64{nil}
+	}
+
+	pools := make([][][]uint64, len(op.params))
+	for i, vt := range op.params {
+		pools[i] = operandPool(vt)
+	}
+
+	n := len(pools[0])
+	if len(pools) > 1 {
+		n *= len(pools[1])
+	}
+	for k := 0; k < n; k++ {
+		var params []uint64
+		for i, pool := range pools {
+			var v []uint64
+			switch i {
+			case 0:
+				v = pool[k%len(pool)]
+			case 1:
+				v = pool[k/len(pools[0])]
+			default:
+				v = pool[(k*7+i)%len(pool)]
+			}
+			params = append(params, v...)
+		}
+		ret = append(ret, params)
+	}
+	return
+}
+
+func (op *operation) equal(expected, actual []uint64) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	for i := range expected {
+		e, a := expected[i], actual[i]
+		if op.result == i32 || op.result == f32 {
+			// Only the lower 32 bits are defined for 32-bit results, e.g. api.DecodeI32 ignores the rest.
+			e, a = uint64(uint32(e)), uint64(uint32(a))
+		}
+		switch op.nanLaneSize {
+		case 4:
+			if !equalIgnoringNaN32(uint32(e), uint32(a)) || !equalIgnoringNaN32(uint32(e>>32), uint32(a>>32)) {
+				return false
+			}
+		case 8:
+			if e != a && !(math.IsNaN(math.Float64frombits(e)) && math.IsNaN(math.Float64frombits(a))) {
+				return false
+			}
+		default:
+			if e != a {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func equalIgnoringNaN32(e, a uint32) bool {
+	return e == a || (isNaN32(e) && isNaN32(a))
+}
+
+func isNaN32(v uint32) bool {
+	f := math.Float32frombits(v)
+	return f != f
+}
+
+var (
+	i32Operands = []uint32{
+		0, 1, 2, 7, 31, 32, 0x7fffffff, 0x80000000, 0xffffffff, 0x12345678, 0xfffffff0,
+		wasm.MemoryPageSize - 16, wasm.MemoryPageSize - 1, wasm.MemoryPageSize,
+	}
+	i64Operands = []uint64{
+		0, 1, 63, 64, math.MaxInt64, 1 << 63, math.MaxUint64, 0x0123456789abcdef, 0xfedcba9876543210,
+		1 << 32, math.MaxUint32,
+	}
+	f32Operands = []uint32{
+		0, 0x80000000, math.Float32bits(1), math.Float32bits(-1.5), math.Float32bits(2.5), math.Float32bits(0.5),
+		math.Float32bits(-math.Pi), 0x7fc00000, 0xffc00001, 0x7fa00000, 0x7f800000, 0xff800000, 0x7f7fffff, 1,
+		math.Float32bits(math.MaxInt32 + 1), math.Float32bits(math.MinInt32), math.Float32bits(math.MaxUint32 + 1),
+		math.Float32bits(math.MaxUint64),
+	}
+	f64Operands = []uint64{
+		0, 1 << 63, math.Float64bits(1), math.Float64bits(-1.5), math.Float64bits(2.5), math.Float64bits(0.5),
+		math.Float64bits(-math.Pi), 0x7ff8000000000000, 0xfff8000000000001, 0x7ff4000000000000, 0x7ff0000000000000,
+		0xfff0000000000000, math.Float64bits(math.MaxFloat64), 1, math.Float64bits(math.MaxInt32 + 0.5),
+		math.Float64bits(math.MinInt32 - 1), math.Float64bits(math.MaxInt64), math.Float64bits(math.MaxUint64),
+	}
+	// v128Operands are the lower and higher 64 bits of each vector.
+	v128Operands = [][2]uint64{
+		{0, 0},
+		{math.MaxUint64, math.MaxUint64},
+		{0x8080808080808080, 0x8080808080808080},
+		{0x0706050403020100, 0x0f0e0d0c0b0a0908},
+		{0x000100017fff8000, 0x80011234ff0000ff},
+		{0x800000007fffffff, 0xffffffff00000001},
+		{0x7fc000003fc00000, 0xff80000080000000},
+		{0xc02000004f000000, 0x3f0000004f800000},
+		{0x43e0000000000000, 0xbff8000000000000},
+		{0x7ff8000000000000, 0x41f0000000800000},
+		{0x8000000000000000, 0x7ff0000000000000},
+		{0x9e3779b97f4a7c15, 0xbf58476d1ce4e5b9},
+	}
+)
+
+func operandPool(vt wasm.ValueType) (ret [][]uint64) {
+	switch vt {
+	case wasm.ValueTypeI32:
+		for _, v := range i32Operands {
+			ret = append(ret, []uint64{uint64(v)})
+		}
+	case wasm.ValueTypeI64:
+		for _, v := range i64Operands {
+			ret = append(ret, []uint64{v})
+		}
+	case wasm.ValueTypeF32:
+		for _, v := range f32Operands {
+			ret = append(ret, []uint64{uint64(v)})
+		}
+	case wasm.ValueTypeF64:
+		for _, v := range f64Operands {
+			ret = append(ret, []uint64{v})
+		}
+	case wasm.ValueTypeV128:
+		for _, v := range v128Operands {
+			ret = append(ret, []uint64{v[0], v[1]})
+		}
+	}
+	return
+}
+
+const (
+	i32  = wasm.ValueTypeI32
+	i64  = wasm.ValueTypeI64
+	f32  = wasm.ValueTypeF32
+	f64  = wasm.ValueTypeF64
+	v128 = wasm.ValueTypeV128
+)
+
+// features20220419Operations returns the operations to test. This fails if an instruction isn't known to be either
+// tested or left out, so that new instructions are not silently missed.
+func features20220419Operations(t *testing.T) (ops []operation) {
+	for op := 0; op < 256; op++ {
+		oc := wasm.Opcode(op)
+		name := wasm.InstructionName(oc)
+		if name == "" {
+			continue
+		}
+		added, ok := numericOperations(oc)
+		if !ok {
+			added, ok = memoryOperations(oc)
+		}
+		if !ok {
+			ok = isUntestedInstruction(oc)
+		}
+		require.True(t, ok, "%s is neither tested nor left out", name)
+		ops = append(ops, added...)
+	}
+	for op := 0; op < 256; op++ {
+		oc := wasm.OpcodeMisc(op)
+		name := wasm.MiscInstructionName(oc)
+		if name == "" {
+			continue
+		}
+		added, ok := miscOperations(oc)
+		require.True(t, ok, "%s is neither tested nor left out", name)
+		ops = append(ops, added...)
+	}
+	for op := 0; op < 256; op++ {
+		oc := wasm.OpcodeVec(op)
+		name := wasm.VectorInstructionName(oc)
+		if name == "" {
+			continue
+		}
+		added, ok := vectorOperations(oc)
+		require.True(t, ok, "%s is neither tested nor left out", name)
+		ops = append(ops, added...)
+	}
+	return
+}
+
+// isUntestedInstruction returns true if the instruction is left out as it can't be called with arbitrary operands,
+// or isn't part of WebAssembly 2.0 (20220419).
+func isUntestedInstruction(oc wasm.Opcode) bool {
+	switch oc {
+	case wasm.OpcodeUnreachable, wasm.OpcodeNop, wasm.OpcodeBlock, wasm.OpcodeLoop, wasm.OpcodeIf, wasm.OpcodeElse,
+		wasm.OpcodeEnd, wasm.OpcodeBr, wasm.OpcodeBrIf, wasm.OpcodeBrTable, wasm.OpcodeReturn, wasm.OpcodeCall,
+		wasm.OpcodeCallIndirect, wasm.OpcodeReturnCall, wasm.OpcodeReturnCallIndirect, wasm.OpcodeTry,
+		wasm.OpcodeCatch, wasm.OpcodeThrow, wasm.OpcodeRethrow, wasm.OpcodeDelegate, wasm.OpcodeCatchAll,
+		wasm.OpcodeDrop, wasm.OpcodeLocalGet, wasm.OpcodeLocalSet, wasm.OpcodeLocalTee, wasm.OpcodeGlobalGet,
+		wasm.OpcodeGlobalSet, wasm.OpcodeTableGet, wasm.OpcodeTableSet, wasm.OpcodeMemoryGrow,
+		wasm.OpcodeI32Const, wasm.OpcodeI64Const, wasm.OpcodeF32Const, wasm.OpcodeF64Const,
+		wasm.OpcodeRefNull, wasm.OpcodeRefIsNull, wasm.OpcodeRefFunc,
+		wasm.OpcodeMiscPrefix, wasm.OpcodeVecPrefix, wasm.OpcodeAtomicPrefix:
+		return true
+	}
+	return false
+}
+
+func numericOperations(oc wasm.Opcode) ([]operation, bool) {
+	name := wasm.InstructionName(oc)
+	body := []byte{oc}
+	switch {
+	case oc == wasm.OpcodeSelect:
+		return []operation{{name: name, params: []wasm.ValueType{i64, i64, i32}, result: i64, body: body}}, true
+	case oc == wasm.OpcodeTypedSelect:
+		var ops []operation
+		for _, vt := range []wasm.ValueType{i32, i64, f32, f64, v128} {
+			ops = append(ops, operation{
+				name:   fmt.Sprintf("%s %s", name, wasm.ValueTypeName(vt)),
+				params: []wasm.ValueType{vt, vt, i32},
+				result: vt,
+				body:   []byte{oc, 1, vt},
+			})
+		}
+		return ops, true
+	case oc < wasm.OpcodeI32Eqz:
+		return nil, false
+	case oc == wasm.OpcodeI32Eqz:
+		return unaryOperation(name, i32, i32, body), true
+	case oc <= wasm.OpcodeI32GeU:
+		return binaryOperation(name, i32, i32, body), true
+	case oc == wasm.OpcodeI64Eqz:
+		return unaryOperation(name, i64, i32, body), true
+	case oc <= wasm.OpcodeI64GeU:
+		return binaryOperation(name, i64, i32, body), true
+	case oc <= wasm.OpcodeF32Ge:
+		return binaryOperation(name, f32, i32, body), true
+	case oc <= wasm.OpcodeF64Ge:
+		return binaryOperation(name, f64, i32, body), true
+	case oc <= wasm.OpcodeI32Popcnt:
+		return unaryOperation(name, i32, i32, body), true
+	case oc <= wasm.OpcodeI32Rotr:
+		return binaryOperation(name, i32, i32, body), true
+	case oc <= wasm.OpcodeI64Popcnt:
+		return unaryOperation(name, i64, i64, body), true
+	case oc <= wasm.OpcodeI64Rotr:
+		return binaryOperation(name, i64, i64, body), true
+	case oc <= wasm.OpcodeF32Sqrt:
+		return unaryOperation(name, f32, f32, body), true
+	case oc <= wasm.OpcodeF32Copysign:
+		return binaryOperation(name, f32, f32, body), true
+	case oc <= wasm.OpcodeF64Sqrt:
+		return unaryOperation(name, f64, f64, body), true
+	case oc <= wasm.OpcodeF64Copysign:
+		return binaryOperation(name, f64, f64, body), true
+	}
+
+	var in, out wasm.ValueType
+	switch oc {
+	case wasm.OpcodeI32WrapI64:
+		in, out = i64, i32
+	case wasm.OpcodeI32TruncF32S, wasm.OpcodeI32TruncF32U, wasm.OpcodeI32ReinterpretF32:
+		in, out = f32, i32
+	case wasm.OpcodeI32TruncF64S, wasm.OpcodeI32TruncF64U:
+		in, out = f64, i32
+	case wasm.OpcodeI64ExtendI32S, wasm.OpcodeI64ExtendI32U:
+		in, out = i32, i64
+	case wasm.OpcodeI64TruncF32S, wasm.OpcodeI64TruncF32U:
+		in, out = f32, i64
+	case wasm.OpcodeI64TruncF64S, wasm.OpcodeI64TruncF64U, wasm.OpcodeI64ReinterpretF64:
+		in, out = f64, i64
+	case wasm.OpcodeF32ConvertI32s, wasm.OpcodeF32ConvertI32U, wasm.OpcodeF32ReinterpretI32:
+		in, out = i32, f32
+	case wasm.OpcodeF32ConvertI64S, wasm.OpcodeF32ConvertI64U:
+		in, out = i64, f32
+	case wasm.OpcodeF32DemoteF64:
+		in, out = f64, f32
+	case wasm.OpcodeF64ConvertI32S, wasm.OpcodeF64ConvertI32U:
+		in, out = i32, f64
+	case wasm.OpcodeF64ConvertI64S, wasm.OpcodeF64ConvertI64U, wasm.OpcodeF64ReinterpretI64:
+		in, out = i64, f64
+	case wasm.OpcodeF64PromoteF32:
+		in, out = f32, f64
+	case wasm.OpcodeI32Extend8S, wasm.OpcodeI32Extend16S:
+		in, out = i32, i32
+	case wasm.OpcodeI64Extend8S, wasm.OpcodeI64Extend16S, wasm.OpcodeI64Extend32S:
+		in, out = i64, i64
+	default:
+		return nil, false
+	}
+	return unaryOperation(name, in, out, body), true
+}
+
+func memoryOperations(oc wasm.Opcode) ([]operation, bool) {
+	name := wasm.InstructionName(oc)
+	var vt wasm.ValueType
+	switch oc {
+	case wasm.OpcodeMemorySize:
+		return []operation{{name: name, result: i32, body: []byte{oc, 0}}}, true
+	case wasm.OpcodeI32Load, wasm.OpcodeI32Load8S, wasm.OpcodeI32Load8U, wasm.OpcodeI32Load16S, wasm.OpcodeI32Load16U:
+		vt = i32
+	case wasm.OpcodeI64Load, wasm.OpcodeI64Load8S, wasm.OpcodeI64Load8U, wasm.OpcodeI64Load16S,
+		wasm.OpcodeI64Load16U, wasm.OpcodeI64Load32S, wasm.OpcodeI64Load32U:
+		vt = i64
+	case wasm.OpcodeF32Load:
+		vt = f32
+	case wasm.OpcodeF64Load:
+		vt = f64
+	case wasm.OpcodeI32Store, wasm.OpcodeI32Store8, wasm.OpcodeI32Store16:
+		return storeOperations(name, i32, []byte{oc}), true
+	case wasm.OpcodeI64Store, wasm.OpcodeI64Store8, wasm.OpcodeI64Store16, wasm.OpcodeI64Store32:
+		return storeOperations(name, i64, []byte{oc}), true
+	case wasm.OpcodeF32Store:
+		return storeOperations(name, f32, []byte{oc}), true
+	case wasm.OpcodeF64Store:
+		return storeOperations(name, f64, []byte{oc}), true
+	default:
+		return nil, false
+	}
+	var ops []operation
+	for _, offset := range memoryOffsets {
+		ops = append(ops, operation{
+			name:   fmt.Sprintf("%s offset=%d", name, offset),
+			params: []wasm.ValueType{i32},
+			result: vt,
+			body:   concat([]byte{oc}, memarg(offset)),
+		})
+	}
+	return ops, true
+}
+
+func miscOperations(oc wasm.OpcodeMisc) ([]operation, bool) {
+	name := wasm.MiscInstructionName(oc)
+	body := []byte{wasm.OpcodeMiscPrefix, oc}
+	switch oc {
+	case wasm.OpcodeMiscI32TruncSatF32S, wasm.OpcodeMiscI32TruncSatF32U:
+		return unaryOperation(name, f32, i32, body), true
+	case wasm.OpcodeMiscI32TruncSatF64S, wasm.OpcodeMiscI32TruncSatF64U:
+		return unaryOperation(name, f64, i32, body), true
+	case wasm.OpcodeMiscI64TruncSatF32S, wasm.OpcodeMiscI64TruncSatF32U:
+		return unaryOperation(name, f32, i64, body), true
+	case wasm.OpcodeMiscI64TruncSatF64S, wasm.OpcodeMiscI64TruncSatF64U:
+		return unaryOperation(name, f64, i64, body), true
+	case wasm.OpcodeMiscMemoryCopy, wasm.OpcodeMiscMemoryFill:
+		if oc == wasm.OpcodeMiscMemoryCopy {
+			body = append(body, 0, 0)
+		} else {
+			body = append(body, 0)
+		}
+		// Read back the destination to see what was written.
+		body = append(body, reloadV128(0)...)
+		return []operation{{name: name, params: []wasm.ValueType{i32, i32, i32}, result: v128, body: body}}, true
+	case wasm.OpcodeMiscMemoryInit, wasm.OpcodeMiscDataDrop, wasm.OpcodeMiscTableInit, wasm.OpcodeMiscElemDrop,
+		wasm.OpcodeMiscTableCopy, wasm.OpcodeMiscTableGrow, wasm.OpcodeMiscTableSize, wasm.OpcodeMiscTableFill:
+		return nil, true
+	}
+	return nil, false
+}
+
+func vectorOperations(oc wasm.OpcodeVec) ([]operation, bool) {
+	name := wasm.VectorInstructionName(oc)
+	body := append([]byte{wasm.OpcodeVecPrefix}, leb128.EncodeUint32(uint32(oc))...)
+
+	floatLaneSize := 0
+	if shape := strings.SplitN(name, ".", 2); len(shape) == 2 {
+		switch op := shape[1]; op {
+		case "eq", "ne", "lt", "gt", "le", "ge":
+		default:
+			if shape[0] == "f32x4" || strings.HasPrefix(op, "convert_i32x4") || strings.HasPrefix(op, "demote") {
+				floatLaneSize = 4
+			} else if shape[0] == "f64x2" {
+				floatLaneSize = 8
+			}
+		}
+	}
+
+	var ops []operation
+	switch oc {
+	case wasm.OpcodeVecV128Load, wasm.OpcodeVecV128Load8x8S, wasm.OpcodeVecV128Load8x8U,
+		wasm.OpcodeVecV128Load16x4S, wasm.OpcodeVecV128Load16x4U, wasm.OpcodeVecV128Load32x2S,
+		wasm.OpcodeVecV128Load32x2U, wasm.OpcodeVecV128Load8Splat, wasm.OpcodeVecV128Load16Splat,
+		wasm.OpcodeVecV128Load32Splat, wasm.OpcodeVecV128Load64Splat, wasm.OpcodeVecV128Load32zero,
+		wasm.OpcodeVecV128Load64zero:
+		for _, offset := range memoryOffsets {
+			ops = append(ops, operation{
+				name:   fmt.Sprintf("%s offset=%d", name, offset),
+				params: []wasm.ValueType{i32},
+				result: v128,
+				body:   concat(body, memarg(offset)),
+			})
+		}
+	case wasm.OpcodeVecV128Store:
+		ops = storeOperations(name, v128, body)
+	case wasm.OpcodeVecV128Load8Lane, wasm.OpcodeVecV128Load16Lane, wasm.OpcodeVecV128Load32Lane,
+		wasm.OpcodeVecV128Load64Lane, wasm.OpcodeVecV128Store8Lane, wasm.OpcodeVecV128Store16Lane,
+		wasm.OpcodeVecV128Store32Lane, wasm.OpcodeVecV128Store64Lane:
+		laneCount := map[wasm.OpcodeVec]byte{
+			wasm.OpcodeVecV128Load8Lane: 16, wasm.OpcodeVecV128Load16Lane: 8, wasm.OpcodeVecV128Load32Lane: 4,
+			wasm.OpcodeVecV128Load64Lane: 2, wasm.OpcodeVecV128Store8Lane: 16, wasm.OpcodeVecV128Store16Lane: 8,
+			wasm.OpcodeVecV128Store32Lane: 4, wasm.OpcodeVecV128Store64Lane: 2,
+		}[oc]
+		isStore := oc >= wasm.OpcodeVecV128Store8Lane
+		for _, lane := range []byte{0, laneCount - 1} {
+			op := operation{
+				name:   fmt.Sprintf("%s lane=%d", name, lane),
+				params: []wasm.ValueType{i32, v128},
+				result: v128,
+				body:   concat(body, memarg(0), []byte{lane}),
+			}
+			if isStore {
+				op.body = concat(op.body, reloadV128(0))
+			}
+			ops = append(ops, op)
+		}
+	case wasm.OpcodeVecV128Const:
+		imm := make([]byte, 16)
+		for i := range imm {
+			imm[i] = byte(i*29 + 3)
+		}
+		ops = []operation{{name: name, result: v128, body: concat(body, imm)}}
+	case wasm.OpcodeVecV128i8x16Shuffle:
+		for _, lanes := range [][]byte{
+			{0, 17, 2, 19, 4, 21, 6, 23, 8, 25, 10, 27, 12, 29, 14, 31},
+			{31, 0, 15, 16, 1, 30, 14, 17, 7, 7, 7, 7, 24, 8, 23, 9},
+		} {
+			ops = append(ops, operation{
+				name:   fmt.Sprintf("%s %v", name, lanes),
+				params: []wasm.ValueType{v128, v128},
+				result: v128,
+				body:   concat(body, lanes),
+			})
+		}
+	case wasm.OpcodeVecI8x16ExtractLaneS, wasm.OpcodeVecI8x16ExtractLaneU:
+		ops = laneOperations(name, 16, []wasm.ValueType{v128}, i32, body)
+	case wasm.OpcodeVecI16x8ExtractLaneS, wasm.OpcodeVecI16x8ExtractLaneU:
+		ops = laneOperations(name, 8, []wasm.ValueType{v128}, i32, body)
+	case wasm.OpcodeVecI32x4ExtractLane:
+		ops = laneOperations(name, 4, []wasm.ValueType{v128}, i32, body)
+	case wasm.OpcodeVecI64x2ExtractLane:
+		ops = laneOperations(name, 2, []wasm.ValueType{v128}, i64, body)
+	case wasm.OpcodeVecF32x4ExtractLane:
+		ops = laneOperations(name, 4, []wasm.ValueType{v128}, f32, body)
+	case wasm.OpcodeVecF64x2ExtractLane:
+		ops = laneOperations(name, 2, []wasm.ValueType{v128}, f64, body)
+	case wasm.OpcodeVecI8x16ReplaceLane:
+		ops = laneOperations(name, 16, []wasm.ValueType{v128, i32}, v128, body)
+	case wasm.OpcodeVecI16x8ReplaceLane:
+		ops = laneOperations(name, 8, []wasm.ValueType{v128, i32}, v128, body)
+	case wasm.OpcodeVecI32x4ReplaceLane:
+		ops = laneOperations(name, 4, []wasm.ValueType{v128, i32}, v128, body)
+	case wasm.OpcodeVecI64x2ReplaceLane:
+		ops = laneOperations(name, 2, []wasm.ValueType{v128, i64}, v128, body)
+	case wasm.OpcodeVecF32x4ReplaceLane:
+		ops = laneOperations(name, 4, []wasm.ValueType{v128, f32}, v128, body)
+	case wasm.OpcodeVecF64x2ReplaceLane:
+		ops = laneOperations(name, 2, []wasm.ValueType{v128, f64}, v128, body)
+	case wasm.OpcodeVecI8x16Splat, wasm.OpcodeVecI16x8Splat, wasm.OpcodeVecI32x4Splat:
+		ops = unaryOperation(name, i32, v128, body)
+	case wasm.OpcodeVecI64x2Splat:
+		ops = unaryOperation(name, i64, v128, body)
+	case wasm.OpcodeVecF32x4Splat:
+		ops = unaryOperation(name, f32, v128, body)
+	case wasm.OpcodeVecF64x2Splat:
+		ops = unaryOperation(name, f64, v128, body)
+	case wasm.OpcodeVecV128Bitselect:
+		ops = []operation{{name: name, params: []wasm.ValueType{v128, v128, v128}, result: v128, body: body}}
+	case wasm.OpcodeVecV128AnyTrue, wasm.OpcodeVecI8x16AllTrue, wasm.OpcodeVecI16x8AllTrue,
+		wasm.OpcodeVecI32x4AllTrue, wasm.OpcodeVecI64x2AllTrue, wasm.OpcodeVecI8x16BitMask,
+		wasm.OpcodeVecI16x8BitMask, wasm.OpcodeVecI32x4BitMask, wasm.OpcodeVecI64x2BitMask:
+		ops = unaryOperation(name, v128, i32, body)
+	case wasm.OpcodeVecI8x16Shl, wasm.OpcodeVecI8x16ShrS, wasm.OpcodeVecI8x16ShrU,
+		wasm.OpcodeVecI16x8Shl, wasm.OpcodeVecI16x8ShrS, wasm.OpcodeVecI16x8ShrU,
+		wasm.OpcodeVecI32x4Shl, wasm.OpcodeVecI32x4ShrS, wasm.OpcodeVecI32x4ShrU,
+		wasm.OpcodeVecI64x2Shl, wasm.OpcodeVecI64x2ShrS, wasm.OpcodeVecI64x2ShrU:
+		ops = []operation{{name: name, params: []wasm.ValueType{v128, i32}, result: v128, body: body}}
+	case wasm.OpcodeVecV128Not,
+		wasm.OpcodeVecI8x16Abs, wasm.OpcodeVecI8x16Neg, wasm.OpcodeVecI8x16Popcnt,
+		wasm.OpcodeVecI16x8Abs, wasm.OpcodeVecI16x8Neg, wasm.OpcodeVecI32x4Abs, wasm.OpcodeVecI32x4Neg,
+		wasm.OpcodeVecI64x2Abs, wasm.OpcodeVecI64x2Neg,
+		wasm.OpcodeVecI16x8ExtaddPairwiseI8x16S, wasm.OpcodeVecI16x8ExtaddPairwiseI8x16U,
+		wasm.OpcodeVecI32x4ExtaddPairwiseI16x8S, wasm.OpcodeVecI32x4ExtaddPairwiseI16x8U,
+		wasm.OpcodeVecI16x8ExtendLowI8x16S, wasm.OpcodeVecI16x8ExtendHighI8x16S,
+		wasm.OpcodeVecI16x8ExtendLowI8x16U, wasm.OpcodeVecI16x8ExtendHighI8x16U,
+		wasm.OpcodeVecI32x4ExtendLowI16x8S, wasm.OpcodeVecI32x4ExtendHighI16x8S,
+		wasm.OpcodeVecI32x4ExtendLowI16x8U, wasm.OpcodeVecI32x4ExtendHighI16x8U,
+		wasm.OpcodeVecI64x2ExtendLowI32x4S, wasm.OpcodeVecI64x2ExtendHighI32x4S,
+		wasm.OpcodeVecI64x2ExtendLowI32x4U, wasm.OpcodeVecI64x2ExtendHighI32x4U,
+		wasm.OpcodeVecF32x4Ceil, wasm.OpcodeVecF32x4Floor, wasm.OpcodeVecF32x4Trunc, wasm.OpcodeVecF32x4Nearest,
+		wasm.OpcodeVecF32x4Abs, wasm.OpcodeVecF32x4Neg, wasm.OpcodeVecF32x4Sqrt,
+		wasm.OpcodeVecF64x2Ceil, wasm.OpcodeVecF64x2Floor, wasm.OpcodeVecF64x2Trunc, wasm.OpcodeVecF64x2Nearest,
+		wasm.OpcodeVecF64x2Abs, wasm.OpcodeVecF64x2Neg, wasm.OpcodeVecF64x2Sqrt,
+		wasm.OpcodeVecI32x4TruncSatF32x4S, wasm.OpcodeVecI32x4TruncSatF32x4U,
+		wasm.OpcodeVecF32x4ConvertI32x4S, wasm.OpcodeVecF32x4ConvertI32x4U,
+		wasm.OpcodeVecI32x4TruncSatF64x2SZero, wasm.OpcodeVecI32x4TruncSatF64x2UZero,
+		wasm.OpcodeVecF64x2ConvertLowI32x4S, wasm.OpcodeVecF64x2ConvertLowI32x4U,
+		wasm.OpcodeVecF32x4DemoteF64x2Zero, wasm.OpcodeVecF64x2PromoteLowF32x4Zero:
+		ops = unaryOperation(name, v128, v128, body)
+	default:
+		// All the other vector instructions take two vectors.
+		ops = binaryOperation(name, v128, v128, body)
+	}
+	for i := range ops {
+		if ops[i].result == v128 {
+			ops[i].nanLaneSize = floatLaneSize
+		}
+	}
+	return ops, true
+}
+
+// memoryOffsets are the static offsets of memory instructions, where the latter makes all accesses but one operand
+// out of bounds.
+var memoryOffsets = []uint32{0, wasm.MemoryPageSize - 17}
+
+// reloadV128 reads back the 16-byte aligned vector at the address in the first param plus the offset, after it is
+// written. This doesn't overflow as the address plus the offset was in bounds for the write.
+func reloadV128(offset uint32) []byte {
+	return concat([]byte{wasm.OpcodeLocalGet, 0, wasm.OpcodeI32Const}, leb128.EncodeInt32(int32(offset)),
+		[]byte{wasm.OpcodeI32Add, wasm.OpcodeI32Const, 0x70, wasm.OpcodeI32And}, // 0x70 is -16 in signed LEB128.
+		[]byte{wasm.OpcodeVecPrefix, byte(wasm.OpcodeVecV128Load)}, memarg(0))
+}
+
+// concat returns a new slice with the contents of all the given slices.
+func concat(bs ...[]byte) (ret []byte) {
+	for _, b := range bs {
+		ret = append(ret, b...)
+	}
+	return
+}
+
+func memarg(offset uint32) []byte {
+	return append([]byte{0}, leb128.EncodeUint32(offset)...)
+}
+
+func unaryOperation(name string, in, out wasm.ValueType, body []byte) []operation {
+	return []operation{{name: name, params: []wasm.ValueType{in}, result: out, body: body, nanLaneSize: nanLaneSize(out)}}
+}
+
+func binaryOperation(name string, in, out wasm.ValueType, body []byte) []operation {
+	return []operation{{name: name, params: []wasm.ValueType{in, in}, result: out, body: body, nanLaneSize: nanLaneSize(out)}}
+}
+
+// storeOperations returns operations storing the second param at the address of the first param, and reads it back.
+func storeOperations(name string, vt wasm.ValueType, body []byte) (ops []operation) {
+	for _, offset := range memoryOffsets {
+		ops = append(ops, operation{
+			name:   fmt.Sprintf("%s offset=%d", name, offset),
+			params: []wasm.ValueType{i32, vt},
+			result: v128,
+			body:   concat(body, memarg(offset), reloadV128(offset)),
+		})
+	}
+	return
+}
+
+// laneOperations returns operations for the first and last lane of an instruction with a lane index immediate.
+func laneOperations(name string, laneCount byte, params []wasm.ValueType, result wasm.ValueType, body []byte) (ops []operation) {
+	for _, lane := range []byte{0, laneCount - 1} {
+		ops = append(ops, operation{
+			name:        fmt.Sprintf("%s lane=%d", name, lane),
+			params:      params,
+			result:      result,
+			body:        concat(body, []byte{lane}),
+			nanLaneSize: nanLaneSize(result),
+		})
+	}
+	return
+}
+
+func nanLaneSize(vt wasm.ValueType) int {
+	switch vt {
+	case f32:
+		return 4
+	case f64:
+		return 8
+	}
+	return 0
+}
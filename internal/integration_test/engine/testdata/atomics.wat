@@ -0,0 +1,39 @@
+(module
+	(memory (export "memory") 1 1 shared)
+
+	(func (export "add") (param $delta i32) (result (;previous;) i32)
+		i32.const 0
+		local.get $delta
+		i32.atomic.rmw.add
+	)
+	(func (export "load") (result i32)
+		i32.const 0
+		i32.atomic.load
+	)
+	(func (export "cmpxchg") (param $expected i32) (param $replacement i32) (result (;previous;) i32)
+		i32.const 0
+		local.get $expected
+		local.get $replacement
+		i32.atomic.rmw.cmpxchg
+	)
+	(func (export "xchg16") (param $v i64) (result (;previous;) i64)
+		i32.const 16
+		local.get $v
+		i64.atomic.rmw16.xchg_u
+	)
+	(func (export "wait") (param $expected i32) (param $timeout i64) (result i32)
+		i32.const 8
+		local.get $expected
+		local.get $timeout
+		memory.atomic.wait32
+	)
+	(func (export "notify") (param $count i32) (result (;woken;) i32)
+		i32.const 8
+		local.get $count
+		memory.atomic.notify
+	)
+	(func (export "load_unaligned") (result i32)
+		i32.const 1
+		i32.atomic.load
+	)
+)
@@ -0,0 +1,9 @@
+(module
+	(import "atomics" "memory" (memory 1 1 shared))
+
+	(func (export "add") (param $delta i32) (result (;previous;) i32)
+		i32.const 0
+		local.get $delta
+		i32.atomic.rmw.add
+	)
+)
@@ -0,0 +1,137 @@
+;; exceptions.wasm is hand-encoded from this as the text format doesn't support the exception handling proposal yet.
+(module
+	(import "host" "throw" (func $host_throw (param i32)))
+
+	(tag $e (export "e") (param i32))
+	(tag $other (param i64))
+
+	(func $throw (export "throw") (param i32)
+		local.get 0
+		throw $e
+	)
+
+	;; catch returns the thrown value plus a local set before the try block.
+	(func (export "catch") (param i32) (result i32)
+		(local i32)
+		i32.const 5
+		local.set 1
+		try (result i32)
+			local.get 0
+			call $throw
+			i32.const -1
+		catch $e
+			local.get 1
+			i32.add
+		end
+	)
+
+	;; catch_local discards the values pushed in the try block, but not the ones below it.
+	(func (export "catch_local") (param i32) (result i32)
+		i32.const 100
+		try (result i32)
+			i32.const 1
+			i32.const 2
+			local.get 0
+			throw $e
+		catch $e
+		end
+		i32.add
+	)
+
+	(func (export "catch_all") (result i32)
+		try (result i32)
+			i64.const 1
+			throw $other
+		catch $e
+			drop
+			i32.const 1
+		catch_all
+			i32.const 2
+		end
+	)
+
+	(func (export "rethrow") (param i32) (result i32)
+		try (result i32)
+			try (result i32)
+				local.get 0
+				throw $e
+			catch $e
+				drop
+				block
+					rethrow 1
+				end
+				i32.const 0
+			end
+		catch $e
+			i32.const 10
+			i32.add
+		end
+	)
+
+	;; delegate skips the handler of the middle try block.
+	(func (export "delegate") (param i32) (result i32)
+		try (result i32)
+			try (result i32)
+				try (result i32)
+					local.get 0
+					call $throw
+					i32.const 0
+				delegate 1
+			catch $e
+				drop
+				i32.const 2
+			end
+		catch $e
+			i32.const 1
+			i32.add
+		end
+	)
+
+	(func (export "host") (param i32) (result i32)
+		try (result i32)
+			local.get 0
+			call $host_throw
+			i32.const 0
+		catch $e
+		end
+	)
+
+	;; trap shows traps are never caught.
+	(func (export "trap") (result i32)
+		try (result i32)
+			unreachable
+		catch_all
+			i32.const 0
+		end
+	)
+
+	;; deep throws 7 after recursing n times.
+	(func $deep (param i32)
+		local.get 0
+		i32.eqz
+		if
+			i32.const 7
+			throw $e
+		end
+		local.get 0
+		i32.const 1
+		i32.sub
+		call $deep
+	)
+
+	(func (export "catch_deep") (param i32) (result i32)
+		(local i32)
+		i32.const 3
+		local.set 1
+		i32.const 1000
+		try (result i32)
+			local.get 0
+			call $deep
+			i32.const 0
+		catch $e
+			local.get 1
+			i32.add
+		end
+		i32.add
+	)
+)
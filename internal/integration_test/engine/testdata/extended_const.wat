@@ -0,0 +1,33 @@
+;; extended_const.wasm is hand-encoded from this as the text format doesn't support the extended-const proposal yet.
+;; This is similar to what wasm-ld --experimental-pic emits: segments are relocated by imported globals.
+(module
+	(import "env" "__memory_base" (global $__memory_base i32))
+	(import "env" "__table_base" (global $__table_base i32))
+	(import "env" "memory" (memory 1))
+
+	(table 4 funcref)
+
+	(global $ptr i32 (i32.add (global.get $__memory_base) (i32.const 16)))
+	(global $wide (export "wide") i64 (i64.mul (i64.const 0x10000000000) (i64.const 3)))
+
+	(elem (i32.add (global.get $__table_base) (i32.const 1)) $answer)
+	(data (i32.add (global.get $__memory_base) (i32.const 16)) "hello")
+
+	(func $answer (result i32)
+		i32.const 42
+	)
+
+	(func (export "data_ptr") (result i32)
+		global.get $ptr
+	)
+
+	(func (export "call") (param i32) (result i32)
+		local.get 0
+		call_indirect (result i32)
+	)
+
+	(func (export "load") (param i32) (result i32)
+		local.get 0
+		i32.load8_u
+	)
+)
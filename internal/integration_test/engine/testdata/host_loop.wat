@@ -0,0 +1,20 @@
+;; host_loop.wasm is hand-encoded from this.
+(module
+	(import "host" "inc" (func $inc (param i64) (result i64)))
+
+	;; inc_n calls $inc param[0] times, starting from zero, and returns the result.
+	(func (export "inc_n") (param i32) (result i64)
+		(local i64)
+		(loop
+			local.get 1
+			call $inc
+			local.set 1
+			local.get 0
+			i32.const 1
+			i32.sub
+			local.tee 0
+			br_if 0
+		)
+		local.get 1
+	)
+)
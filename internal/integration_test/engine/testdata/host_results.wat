@@ -0,0 +1,25 @@
+;; host_results.wasm is hand-encoded from this.
+(module
+	(type $i32_i32 (func (param i32) (result i32)))
+	(import "host" "divmod" (func $divmod (param i32 i32) (result i32 i32)))
+	(import "host" "refs" (func $refs (param externref funcref) (result externref funcref)))
+	(table $funcs 2 funcref)
+	(elem (table $funcs) (i32.const 0) func $double)
+
+	(func $double (type $i32_i32) (i32.add (local.get 0) (local.get 0)))
+
+	;; divmod returns both results of the host function.
+	(func (export "divmod") (param i32 i32) (result i32 i32)
+		(call $divmod (local.get 0) (local.get 1))
+	)
+
+	;; round_trip passes the externref and $double through the host function, then calls the returned function with
+	;; the i32 param.
+	(func (export "round_trip") (param externref i32) (result externref i32)
+		(local $f funcref)
+		(call $refs (local.get 0) (table.get $funcs (i32.const 0)))
+		(local.set $f)
+		(table.set $funcs (i32.const 1) (local.get $f))
+		(call_indirect $funcs (type $i32_i32) (local.get 1) (i32.const 1))
+	)
+)
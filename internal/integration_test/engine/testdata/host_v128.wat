@@ -0,0 +1,18 @@
+;; host_v128.wasm is hand-encoded from this.
+(module
+	(import "host" "swap_halves" (func $swap_halves (param v128) (result v128)))
+
+	;; swap_halves passes a vector of the i64 params through the host function, and returns its lanes.
+	(func (export "swap_halves") (param i64 i64) (result i64 i64)
+		(local v128)
+		local.get 0
+		i64x2.splat
+		local.get 1
+		i64x2.replace_lane 1
+		call $swap_halves
+		local.tee 2
+		i64x2.extract_lane 0
+		local.get 2
+		i64x2.extract_lane 1
+	)
+)
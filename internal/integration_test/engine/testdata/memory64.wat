@@ -0,0 +1,60 @@
+;; memory64.wasm is hand-encoded from this as the text format doesn't support the memory64 proposal yet.
+(module
+	(memory (export "memory") i64 1 2)
+
+	(data (i64.const 0) "\2a")
+	(data $hello "hello")
+
+	(func (export "load") (param i64) (result i32)
+		local.get 0
+		i32.load8_u
+	)
+
+	;; load_far uses a static offset which doesn't fit in 32 bits.
+	(func (export "load_far") (param i64) (result i32)
+		local.get 0
+		i32.load offset=0x100000000
+	)
+
+	(func (export "store") (param i64 i64)
+		local.get 0
+		local.get 1
+		i64.store
+	)
+
+	(func (export "size") (result i64)
+		memory.size
+	)
+
+	(func (export "grow") (param i64) (result i64)
+		local.get 0
+		memory.grow
+	)
+
+	(func (export "fill") (param i64 i32 i64)
+		local.get 0
+		local.get 1
+		local.get 2
+		memory.fill
+	)
+
+	(func (export "copy") (param i64 i64 i64)
+		local.get 0
+		local.get 1
+		local.get 2
+		memory.copy
+	)
+
+	(func (export "init") (param i64 i32 i32)
+		local.get 0
+		local.get 1
+		local.get 2
+		memory.init $hello
+	)
+
+	(func (export "atomic_add") (param i64 i32) (result i32)
+		local.get 0
+		local.get 1
+		i32.atomic.rmw.add
+	)
+)
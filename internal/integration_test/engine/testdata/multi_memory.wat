@@ -0,0 +1,47 @@
+;; multi_memory.wasm is hand-encoded from this as the text format doesn't support the multi-memory proposal yet.
+(module
+	(memory $m0 (export "memory0") 1)
+	(memory $m1 (export "memory1") 1 2)
+
+	(data (memory $m1) (i32.const 0) "\2a")
+
+	(func (export "load") (param i32) (result i32)
+		local.get 0
+		i32.load8_u $m1
+	)
+
+	(func (export "store") (param i32 i32)
+		local.get 0
+		local.get 1
+		i32.store $m1
+	)
+
+	(func (export "size") (result i32)
+		memory.size $m1
+	)
+
+	(func (export "grow") (param i32) (result i32)
+		local.get 0
+		memory.grow $m1
+	)
+
+	;; copy copies the bytes of $m1 into $m0.
+	(func (export "copy") (param i32 i32 i32)
+		local.get 0
+		local.get 1
+		local.get 2
+		memory.copy $m0 $m1
+	)
+
+	(func (export "fill") (param i32 i32 i32)
+		local.get 0
+		local.get 1
+		local.get 2
+		memory.fill $m1
+	)
+
+	(func (export "load0") (param i32) (result i32)
+		local.get 0
+		i32.load8_u
+	)
+)
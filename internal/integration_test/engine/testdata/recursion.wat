@@ -0,0 +1,9 @@
+;; recursion.wasm is hand-encoded from this.
+(module
+	;; recurse calls itself until its parameter is zero, so that it uses one frame more than the parameter.
+	(func $recurse (export "recurse") (param i32)
+		(if (local.get 0)
+			(then (call $recurse (i32.sub (local.get 0) (i32.const 1))))
+		)
+	)
+)
@@ -0,0 +1,33 @@
+(module
+	(memory (export "memory") 1 2 shared)
+
+	;; grow adds the second page, then sets the flag at address 0 for "access".
+	(func (export "grow") (result (;previous;) i32) (local $previous i32)
+		i32.const 1
+		memory.grow
+		local.set $previous
+		i32.const 0
+		i32.const 1
+		i32.atomic.store
+		local.get $previous
+	)
+	;; access sets the flag at address 4, so that the memory is grown after this call started. Then, it waits for the
+	;; flag of "grow", and accesses the last byte of the second page.
+	(func (export "access") (result (;size;) i32 (;stored;) i32)
+		i32.const 4
+		i32.const 1
+		i32.atomic.store
+		(loop $wait
+			i32.const 0
+			i32.atomic.load
+			i32.eqz
+			br_if $wait
+		)
+		i32.const 131071 ;; = 2 pages - 1
+		i32.const 42
+		i32.store8
+		memory.size
+		i32.const 131071
+		i32.load8_u
+	)
+)
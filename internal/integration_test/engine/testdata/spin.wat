@@ -0,0 +1,7 @@
+;; spin.wasm is hand-encoded from this.
+(module
+	;; spin never returns, unless it runs out of fuel.
+	(func (export "spin")
+		(loop (br 0))
+	)
+)
@@ -0,0 +1,20 @@
+;; tables.wasm is hand-encoded from this.
+(module
+	(type $v_i32 (func (result i32)))
+	(table $funcs (export "funcs") 2 funcref)
+	(table $externs (export "externs") 1 externref)
+	(elem (table $funcs) (i32.const 0) func $one)
+
+	(func $one (type $v_i32) (i32.const 1))
+	(func $two (export "two") (type $v_i32) (i32.const 2))
+
+	;; call calls the function at the given index of $funcs.
+	(func (export "call") (param i32) (result i32)
+		(call_indirect $funcs (type $v_i32) (local.get 0))
+	)
+
+	;; get_externref returns the element at the given index of $externs.
+	(func (export "get_externref") (param i32) (result externref)
+		(table.get $externs (local.get 0))
+	)
+)
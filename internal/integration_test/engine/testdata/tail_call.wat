@@ -0,0 +1,147 @@
+(module
+	(import "host" "double" (func $double (param i64) (result i64)))
+
+	(type $sum_t (func (param i64 i64) (result i64)))
+	(type $nullary_t (func (result i64)))
+	(type $more_t (func (param i64 i64) (result i64 i64 i64)))
+	(type $fewer_t (func (param i64 i64 i64 i64 i64) (result i64 i64 i64)))
+
+	(table 4 funcref)
+	(elem (i32.const 0) $sum $sum_indirect $more_indirect $fewer_indirect)
+
+	;; sum returns acc + n + (n-1) + ... + 1 without growing the call stack.
+	(func $sum (export "sum") (param $n i64) (param $acc i64) (result i64)
+		(local f32) ;; dropped by the tail call along with the parameters.
+		local.get $n
+		i64.eqz
+		if (result i64)
+			local.get $acc
+		else
+			local.get $n
+			i64.const 1
+			i64.sub
+			local.get $acc
+			local.get $n
+			i64.add
+			return_call $sum
+		end
+	)
+
+	(func $sum_indirect (export "sum_indirect") (param $n i64) (param $acc i64) (result i64)
+		(local f32)
+		local.get $n
+		i64.eqz
+		if (result i64)
+			local.get $acc
+		else
+			local.get $n
+			i64.const 1
+			i64.sub
+			local.get $acc
+			local.get $n
+			i64.add
+			i32.const 1 ;; $sum_indirect
+			return_call_indirect (type $sum_t)
+		end
+	)
+
+	;; double_tail tail-calls the host function from the entry function.
+	(func $double_tail (export "double_tail") (param i64) (result i64)
+		local.get 0
+		return_call $double
+	)
+
+	(func (export "double_nested") (param i64) (result i64)
+		local.get 0
+		call $double_tail
+	)
+
+	(func (export "type_mismatch") (result i64)
+		i32.const 0 ;; $sum
+		return_call_indirect (type $nullary_t)
+	)
+
+	;; more and fewer return the same as sum, along with which of them returned, by tail-calling each other. So each
+	;; tail call replaces a frame of more or fewer parameters than the callee's, neither matching the results.
+	(func $more (export "more") (param $n i64) (param $acc i64) (result i64 i64 i64)
+		local.get $n
+		i64.eqz
+		if (result i64 i64 i64)
+			local.get $acc
+			i64.const 1
+			i64.const 2
+		else
+			local.get $n
+			i64.const 1
+			i64.sub
+			local.get $acc
+			local.get $n
+			i64.add
+			i64.const 0
+			i64.const 0
+			i64.const 0
+			return_call $fewer
+		end
+	)
+
+	(func $fewer (export "fewer") (param $n i64) (param $acc i64) (param i64 i64 i64) (result i64 i64 i64)
+		(local f32)
+		local.get $n
+		i64.eqz
+		if (result i64 i64 i64)
+			local.get $acc
+			i64.const 3
+			i64.const 4
+		else
+			local.get $n
+			i64.const 1
+			i64.sub
+			local.get $acc
+			local.get $n
+			i64.add
+			return_call $more
+		end
+	)
+
+	(func $more_indirect (export "more_indirect") (param $n i64) (param $acc i64) (result i64 i64 i64)
+		local.get $n
+		i64.eqz
+		if (result i64 i64 i64)
+			local.get $acc
+			i64.const 1
+			i64.const 2
+		else
+			local.get $n
+			i64.const 1
+			i64.sub
+			local.get $acc
+			local.get $n
+			i64.add
+			i64.const 0
+			i64.const 0
+			i64.const 0
+			i32.const 3 ;; $fewer_indirect
+			return_call_indirect (type $fewer_t)
+		end
+	)
+
+	(func $fewer_indirect (export "fewer_indirect") (param $n i64) (param $acc i64) (param i64 i64 i64) (result i64 i64 i64)
+		(local f32)
+		local.get $n
+		i64.eqz
+		if (result i64 i64 i64)
+			local.get $acc
+			i64.const 3
+			i64.const 4
+		else
+			local.get $n
+			i64.const 1
+			i64.sub
+			local.get $acc
+			local.get $n
+			i64.add
+			i32.const 2 ;; $more_indirect
+			return_call_indirect (type $more_t)
+		end
+	)
+)
@@ -0,0 +1,33 @@
+;; vector_select.wasm is hand-encoded from this.
+(module
+	(func (export "select") (param i32) (result i64 i64)
+		(local v128)
+		v128.const i64x2 1 2
+		v128.const i64x2 3 4
+		local.get 0
+		select
+		local.tee 1
+		i64x2.extract_lane 0
+		local.get 1
+		i64x2.extract_lane 1
+	)
+
+	(func (export "typed_select") (param i32) (result i64 i64)
+		(local v128)
+		v128.const i64x2 1 2
+		v128.const i64x2 3 4
+		local.get 0
+		select (result v128)
+		local.tee 1
+		i64x2.extract_lane 0
+		local.get 1
+		i64x2.extract_lane 1
+	)
+
+	;; drop must remove both halves of the vector from the stack.
+	(func (export "drop") (result i32)
+		i32.const 7
+		v128.const i64x2 5 6
+		drop
+	)
+)
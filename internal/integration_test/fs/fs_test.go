@@ -49,8 +49,8 @@ func (fs *wasiFs) Open(name string) (fs.File, error) {
 	dirflags := uint32(0) // arbitrary dirflags
 	pathLen := len(pathBytes)
 	oflags := uint32(0) // arbitrary oflags
-	// rights are ignored per https://github.com/WebAssembly/WASI/issues/469#issuecomment-1045251844
-	fsRightsBase, fsRightsInheriting := uint64(1), uint64(2)
+	// rights to fd_read (=2), fd_seek (=4) and fd_tell (=32), as rights are enforced
+	fsRightsBase, fsRightsInheriting := uint64(2|4|32), uint64(0)
 	fdflags := uint32(0) // arbitrary fdflags
 	res, err := fs.pathOpen.Call(
 		testCtx,
@@ -188,18 +188,10 @@ func (c commandActionVal) toUint64s() (ret []uint64) {
 			panic("BUG")
 		}
 		for i := 0; i < valNum/2; i++ {
-			v, err := strconv.ParseUint(strValues[i].(string), 10, width)
-			if err != nil {
-				panic(err)
-			}
-			low |= (v << (i * width))
+			low |= parseLane(strValues[i].(string), c.LaneType, width) << (i * width)
 		}
 		for i := valNum / 2; i < valNum; i++ {
-			v, err := strconv.ParseUint(strValues[i].(string), 10, width)
-			if err != nil {
-				panic(err)
-			}
-			high |= (v << ((i - valNum/2) * width))
+			high |= parseLane(strValues[i].(string), c.LaneType, width) << ((i - valNum/2) * width)
 		}
 		return []uint64{low, high}
 	} else {
@@ -207,6 +199,21 @@ func (c commandActionVal) toUint64s() (ret []uint64) {
 	}
 }
 
+// parseLane parses the lane value of v128, which can be "nan:canonical" or "nan:arithmetic" for float lanes.
+func parseLane(strValue, laneType string, width int) uint64 {
+	if strings.Contains(strValue, "nan") {
+		if laneType == "f32" {
+			return uint64(math.Float32bits(float32(math.NaN())))
+		}
+		return math.Float64bits(math.NaN())
+	}
+	v, err := strconv.ParseUint(strValue, 10, width)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
 func (c commandActionVal) toUint64() (ret uint64) {
 	strValue := c.Value.(string)
 	if strings.Contains(strValue, "nan") {
@@ -345,9 +352,9 @@ func addSpectestModule(t *testing.T, store *wasm.Store) {
 	require.NoError(t, err)
 }
 
-// maybeSetMemoryCap assigns wasm.Memory Cap to Min, which is what wazero.CompileModule would do.
+// maybeSetMemoryCap assigns each wasm.Memory Cap to Min, which is what wazero.CompileModule would do.
 func maybeSetMemoryCap(mod *wasm.Module) {
-	if mem := mod.MemorySection; mem != nil {
+	for _, mem := range mod.MemorySection {
 		mem.Cap = mem.Min
 	}
 }
@@ -357,7 +364,7 @@ func maybeSetMemoryCap(mod *wasm.Module) {
 //
 // filter is a callback which is called with the target json file name and should return true if the engine wants to run tests against it, false otherwise.
 // TODO: remove filter after SIMD completion.
-func Run(t *testing.T, testDataFS embed.FS, newEngine func(wasm.Features) wasm.Engine, enabledFeatures wasm.Features, filter func(jsonname string) bool) {
+func Run(t *testing.T, testDataFS embed.FS, newEngine func(wasm.Features, wasm.StackLimits) wasm.Engine, enabledFeatures wasm.Features, filter func(jsonname string) bool) {
 	files, err := testDataFS.ReadDir("testdata")
 	require.NoError(t, err)
 
@@ -386,7 +393,7 @@ func Run(t *testing.T, testDataFS embed.FS, newEngine func(wasm.Features) wasm.E
 		wastName := basename(base.SourceFile)
 
 		t.Run(wastName, func(t *testing.T) {
-			store := wasm.NewStore(enabledFeatures, newEngine(enabledFeatures))
+			store := wasm.NewStore(enabledFeatures, newEngine(enabledFeatures, wasm.DefaultStackLimits))
 			addSpectestModule(t, store)
 
 			var lastInstantiatedModuleName string
@@ -437,7 +444,13 @@ func Run(t *testing.T, testDataFS embed.FS, newEngine func(wasm.Features) wasm.E
 							vals, types, err := callFunction(store, moduleName, c.Action.Field, args...)
 							require.NoError(t, err, msg)
 							require.Equal(t, len(exps), len(vals), msg)
-							requireValuesEq(t, vals, exps, types, msg)
+							vectorExps := map[int]commandActionVal{}
+							for i, expV := range c.Exps {
+								if expV.ValType == "v128" {
+									vectorExps[i] = expV
+								}
+							}
+							requireValuesEq(t, vals, exps, types, vectorExps, msg)
 						case "get":
 							_, exps := c.getAssertReturnArgsExps()
 							require.Equal(t, 1, len(exps))
@@ -593,21 +606,77 @@ func testdataPath(filename string) string {
 	return fmt.Sprintf("testdata/%s", filename)
 }
 
-func requireValuesEq(t *testing.T, actual, exps []uint64, valTypes []wasm.ValueType, msg string) {
-	var expectedTypesVectorFlattend []wasm.ValueType
-	for _, tp := range valTypes {
+// requireValuesEq ensures that the actual values equal the expected ones. vectorExps are the expectations of v128 results
+// keyed by the index in valTypes, so that "nan:canonical" and "nan:arithmetic" lanes are checked by their bits.
+func requireValuesEq(t *testing.T, actual, exps []uint64, valTypes []wasm.ValueType, vectorExps map[int]commandActionVal, msg string) {
+	result := fmt.Sprintf("\thave (%v)\n\twant (%v)", actual, exps)
+	var i int
+	for index, tp := range valTypes {
 		if tp != wasm.ValueTypeV128 {
-			expectedTypesVectorFlattend = append(expectedTypesVectorFlattend, tp)
-		} else {
-			expectedTypesVectorFlattend = append(expectedTypesVectorFlattend, wasm.ValueTypeI64)
-			expectedTypesVectorFlattend = append(expectedTypesVectorFlattend, wasm.ValueTypeI64)
+			requireValueEq(t, actual[i], exps[i], tp, msg+"\n"+result)
+			i++
+			continue
 		}
+		requireVectorValueEq(t, actual[i], actual[i+1], exps[i], exps[i+1], vectorExps[index], msg+"\n"+result)
+		i += 2
 	}
+}
 
-	result := fmt.Sprintf("\thave (%v)\n\twant (%v)", actual, exps)
-	for i := range exps {
-		requireValueEq(t, actual[i], exps[i], expectedTypesVectorFlattend[i], msg+"\n"+result)
+// requireVectorValueEq ensures that the actual v128 value (lo, hi) equals the expected one, comparing float lanes
+// one by one as NaN cannot be compared with themselves.
+func requireVectorValueEq(t *testing.T, actualLo, actualHi, expLo, expHi uint64, exp commandActionVal, msg string) {
+	var width int
+	var valType wasm.ValueType
+	switch exp.LaneType {
+	case "f32":
+		width, valType = 32, wasm.ValueTypeF32
+	case "f64":
+		width, valType = 64, wasm.ValueTypeF64
+	default:
+		require.Equal(t, expLo, actualLo, msg)
+		require.Equal(t, expHi, actualHi, msg)
+		return
+	}
+
+	lanes := exp.Value.([]interface{})
+	lanesPerHalf := len(lanes) / 2
+	for i, lane := range lanes {
+		actual, expected := actualLo, expLo
+		if i >= lanesPerHalf {
+			actual, expected = actualHi, expHi
+		}
+		shift := (i % lanesPerHalf) * width
+		actual, expected = (actual>>shift)&(math.MaxUint64>>(64-width)), (expected>>shift)&(math.MaxUint64>>(64-width))
+
+		switch lane.(string) {
+		case "nan:canonical":
+			require.True(t, isCanonicalNaN(actual, width), "lane %d: %#x is not a canonical NaN\n%s", i, actual, msg)
+		case "nan:arithmetic":
+			require.True(t, isArithmeticNaN(actual, width), "lane %d: %#x is not an arithmetic NaN\n%s", i, actual, msg)
+		default:
+			requireValueEq(t, actual, expected, valType, msg)
+		}
+	}
+}
+
+// isCanonicalNaN returns true if the bits of the float of the width are a canonical NaN, which only has the most
+// significant bit of the payload set, with either sign.
+// See https://www.w3.org/TR/2022/WD-wasm-core-2-20220419/syntax/values.html#canonical-nan
+func isCanonicalNaN(bits uint64, width int) bool {
+	if width == 32 {
+		return bits&0x7fff_ffff == 0x7fc0_0000
+	}
+	return bits&0x7fff_ffff_ffff_ffff == 0x7ff8_0000_0000_0000
+}
+
+// isArithmeticNaN returns true if the bits of the float of the width are an arithmetic NaN, which has the most
+// significant bit of the payload set, with any other payload bits and either sign.
+// See https://www.w3.org/TR/2022/WD-wasm-core-2-20220419/syntax/values.html#arithmetic-nan
+func isArithmeticNaN(bits uint64, width int) bool {
+	if width == 32 {
+		return bits&0x7fc0_0000 == 0x7fc0_0000
 	}
+	return bits&0x7ff8_0000_0000_0000 == 0x7ff8_0000_0000_0000
 }
 
 func requireValueEq(t *testing.T, actual, expected uint64, valType wasm.ValueType, msg string) {
@@ -25,8 +25,9 @@ func TestCompiler(t *testing.T) {
 	}
 
 	spectest.Run(t, testcases, compiler.NewEngine, enabledFeatures, func(jsonname string) bool {
-		// TODO: remove after SIMD proposal
-		if strings.Contains(jsonname, "simd") {
+		// TODO: remove after the simd suites pass with the arm64 compiler under qemu or on arm64 hardware, as the NEON
+		// lowering hasn't been run on either.
+		if runtime.GOARCH == "arm64" && strings.Contains(jsonname, "simd") {
 			return path.Base(jsonname) == "simd_const.json"
 		}
 		return true
@@ -34,11 +35,5 @@ func TestCompiler(t *testing.T) {
 }
 
 func TestInterpreter(t *testing.T) {
-	spectest.Run(t, testcases, interpreter.NewEngine, enabledFeatures, func(jsonname string) bool {
-		// TODO: remove after SIMD proposal
-		if strings.Contains(jsonname, "simd") {
-			return path.Base(jsonname) == "simd_const.json"
-		}
-		return true
-	})
+	spectest.Run(t, testcases, interpreter.NewEngine, enabledFeatures, func(string) bool { return true })
 }
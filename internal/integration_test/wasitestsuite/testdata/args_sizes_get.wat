@@ -0,0 +1,15 @@
+;; args_sizes_get exits with the count of arguments, including the program name.
+(module
+  (import "wasi_snapshot_preview1" "args_sizes_get"
+    (func $args_sizes_get (param i32 i32) (result i32)))
+  (import "wasi_snapshot_preview1" "proc_exit"
+    (func $proc_exit (param i32)))
+
+  (memory (export "memory") 1)
+
+  (func (export "_start")
+    (drop (call $args_sizes_get
+      (i32.const 0)   ;; result.argc
+      (i32.const 4))) ;; result.argv_buf_size
+    (call $proc_exit (i32.load (i32.const 0))))
+)
@@ -0,0 +1,15 @@
+;; environ_sizes_get exits with the count of environment variables.
+(module
+  (import "wasi_snapshot_preview1" "environ_sizes_get"
+    (func $environ_sizes_get (param i32 i32) (result i32)))
+  (import "wasi_snapshot_preview1" "proc_exit"
+    (func $proc_exit (param i32)))
+
+  (memory (export "memory") 1)
+
+  (func (export "_start")
+    (drop (call $environ_sizes_get
+      (i32.const 0)   ;; result.environc
+      (i32.const 4))) ;; result.environv_buf_size
+    (call $proc_exit (i32.load (i32.const 0))))
+)
@@ -0,0 +1,18 @@
+;; fd_prestat_dir_name writes the name of the first pre-opened directory to stdout.
+(module
+  (import "wasi_snapshot_preview1" "fd_prestat_get"
+    (func $fd_prestat_get (param i32 i32) (result i32)))
+  (import "wasi_snapshot_preview1" "fd_prestat_dir_name"
+    (func $fd_prestat_dir_name (param i32 i32 i32) (result i32)))
+  (import "wasi_snapshot_preview1" "fd_write"
+    (func $fd_write (param i32 i32 i32 i32) (result i32)))
+
+  (memory (export "memory") 1)
+
+  (func (export "_start")
+    (drop (call $fd_prestat_get (i32.const 3) (i32.const 0))) ;; prestat.pr_name_len is at offset 4
+    (drop (call $fd_prestat_dir_name (i32.const 3) (i32.const 16) (i32.load (i32.const 4))))
+    (i32.store (i32.const 8) (i32.const 16))            ;; iovs[0].offset
+    (i32.store (i32.const 12) (i32.load (i32.const 4))) ;; iovs[0].length
+    (drop (call $fd_write (i32.const 1) (i32.const 8) (i32.const 1) (i32.const 0))))
+)
@@ -0,0 +1,33 @@
+;; fd_read-write creates a file, writes to it, then reads it back and writes what it read to stdout.
+(module
+  (import "wasi_snapshot_preview1" "path_open"
+    (func $path_open (param i32 i32 i32 i32 i32 i64 i64 i32 i32) (result i32)))
+  (import "wasi_snapshot_preview1" "fd_write"
+    (func $fd_write (param i32 i32 i32 i32) (result i32)))
+  (import "wasi_snapshot_preview1" "fd_seek"
+    (func $fd_seek (param i32 i64 i32 i32) (result i32)))
+  (import "wasi_snapshot_preview1" "fd_read"
+    (func $fd_read (param i32 i32 i32 i32) (result i32)))
+
+  (memory (export "memory") 1)
+  (data (i32.const 0) "out")
+  (data (i32.const 16) "\20\00\00\00\06\00\00\00") ;; write iovs[0] = {offset: 32, length: 6}
+  (data (i32.const 32) "wazero")
+  (data (i32.const 48) "\40\00\00\00\06\00\00\00") ;; read iovs[0] = {offset: 64, length: 6}
+
+  (func (export "_start")
+    (drop (call $path_open
+      (i32.const 3)     ;; fs-tests.dir
+      (i32.const 0)     ;; dirflags
+      (i32.const 0)     ;; path
+      (i32.const 3)     ;; path_len
+      (i32.const 9)     ;; oflags = creat|trunc
+      (i64.const 0x46)  ;; fs_rights_base = fd_read|fd_seek|fd_write
+      (i64.const 0)     ;; fs_rights_inheriting
+      (i32.const 0)     ;; fdflags
+      (i32.const 8)))   ;; result.opened_fd
+    (drop (call $fd_write (i32.load (i32.const 8)) (i32.const 16) (i32.const 1) (i32.const 12)))
+    (drop (call $fd_seek (i32.load (i32.const 8)) (i64.const 0) (i32.const 0) (i32.const 24)))
+    (drop (call $fd_read (i32.load (i32.const 8)) (i32.const 48) (i32.const 1) (i32.const 12)))
+    (drop (call $fd_write (i32.const 1) (i32.const 48) (i32.const 1) (i32.const 12))))
+)
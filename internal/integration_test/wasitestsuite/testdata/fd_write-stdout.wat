@@ -0,0 +1,16 @@
+;; fd_write-stdout writes a message to stdout.
+(module
+  (import "wasi_snapshot_preview1" "fd_write"
+    (func $fd_write (param i32 i32 i32 i32) (result i32)))
+
+  (memory (export "memory") 1)
+  (data (i32.const 0) "\10\00\00\00\0b\00\00\00") ;; iovs[0] = {offset: 16, length: 11}
+  (data (i32.const 16) "hello wasi\n")
+
+  (func (export "_start")
+    (drop (call $fd_write
+      (i32.const 1)    ;; stdout
+      (i32.const 0)    ;; iovs
+      (i32.const 1)    ;; iovs_len
+      (i32.const 8)))) ;; result.nwritten
+)
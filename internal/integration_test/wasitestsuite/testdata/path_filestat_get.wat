@@ -0,0 +1,19 @@
+;; path_filestat_get exits with the filetype of a regular file: 4 (regular_file).
+(module
+  (import "wasi_snapshot_preview1" "path_filestat_get"
+    (func $path_filestat_get (param i32 i32 i32 i32 i32) (result i32)))
+  (import "wasi_snapshot_preview1" "proc_exit"
+    (func $proc_exit (param i32)))
+
+  (memory (export "memory") 1)
+  (data (i32.const 0) "file")
+
+  (func (export "_start")
+    (drop (call $path_filestat_get
+      (i32.const 3)    ;; fs-tests.dir
+      (i32.const 1)    ;; flags = symlink_follow
+      (i32.const 0)    ;; path
+      (i32.const 4)    ;; path_len
+      (i32.const 16))) ;; result.buf
+    (call $proc_exit (i32.load8_u (i32.const 32)))) ;; filestat.filetype
+)
@@ -0,0 +1,25 @@
+;; path_open-creat-excl creates a file, then exits with the errno of creating it again: 20 (exist).
+(module
+  (import "wasi_snapshot_preview1" "path_open"
+    (func $path_open (param i32 i32 i32 i32 i32 i64 i64 i32 i32) (result i32)))
+  (import "wasi_snapshot_preview1" "proc_exit"
+    (func $proc_exit (param i32)))
+
+  (memory (export "memory") 1)
+  (data (i32.const 0) "new")
+
+  (func (export "_start")
+    (drop (call $path_open
+      (i32.const 3)     ;; fs-tests.dir
+      (i32.const 0)     ;; dirflags
+      (i32.const 0)     ;; path
+      (i32.const 3)     ;; path_len
+      (i32.const 5)     ;; oflags = creat|excl
+      (i64.const 0x42)  ;; fs_rights_base = fd_read|fd_write
+      (i64.const 0)     ;; fs_rights_inheriting
+      (i32.const 0)     ;; fdflags
+      (i32.const 8)))   ;; result.opened_fd
+    (call $proc_exit (call $path_open
+      (i32.const 3) (i32.const 0) (i32.const 0) (i32.const 3) (i32.const 5)
+      (i64.const 0x42) (i64.const 0) (i32.const 0) (i32.const 8))))
+)
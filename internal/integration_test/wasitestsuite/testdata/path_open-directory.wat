@@ -0,0 +1,22 @@
+;; path_open-directory exits with the errno of opening a regular file as a directory: 54 (notdir).
+(module
+  (import "wasi_snapshot_preview1" "path_open"
+    (func $path_open (param i32 i32 i32 i32 i32 i64 i64 i32 i32) (result i32)))
+  (import "wasi_snapshot_preview1" "proc_exit"
+    (func $proc_exit (param i32)))
+
+  (memory (export "memory") 1)
+  (data (i32.const 0) "file")
+
+  (func (export "_start")
+    (call $proc_exit (call $path_open
+      (i32.const 3)    ;; fs-tests.dir
+      (i32.const 0)    ;; dirflags
+      (i32.const 0)    ;; path
+      (i32.const 4)    ;; path_len
+      (i32.const 2)    ;; oflags = directory
+      (i64.const 0x2)  ;; fs_rights_base = fd_read
+      (i64.const 0)    ;; fs_rights_inheriting
+      (i32.const 0)    ;; fdflags
+      (i32.const 8)))) ;; result.opened_fd
+)
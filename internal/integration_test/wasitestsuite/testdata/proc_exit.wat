@@ -0,0 +1,10 @@
+;; proc_exit exits with a code that isn't zero.
+(module
+  (import "wasi_snapshot_preview1" "proc_exit"
+    (func $proc_exit (param i32)))
+
+  (memory (export "memory") 1)
+
+  (func (export "_start")
+    (call $proc_exit (i32.const 42)))
+)
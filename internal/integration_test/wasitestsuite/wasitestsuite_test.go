@@ -0,0 +1,197 @@
+package wasitestsuite
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/tetratelabs/wazero"
+	"github.com/tetratelabs/wazero/experimental"
+	"github.com/tetratelabs/wazero/internal/testing/require"
+	"github.com/tetratelabs/wazero/sys"
+	"github.com/tetratelabs/wazero/wasi"
+)
+
+// testCtx is an arbitrary, non-default context. Non-nil also prevents linter errors.
+var testCtx = context.WithValue(context.Background(), struct{}{}, "arbitrary")
+
+// testdataDir contains %.wasm files and their expectations in %.json, in the layout of wasi-testsuite.
+// Sub-directories, such as "rust" copied from "tests/rust/testsuite" by `make build.wasi_testsuite`, are also run.
+const testdataDir = "testdata"
+
+// upstreamLangs are the sub-directories of testdataDir vendored from wasi-testsuite by `make build.wasi_testsuite`.
+var upstreamLangs = []string{"assemblyscript", "c", "rust"}
+
+// stubbed are functions in "wasi_snapshot_preview1" which return wasi.ErrnoNosys, per #271. Tests importing any of
+// them are skipped, as they can't pass until the function is implemented. Remove an entry when implementing it.
+var stubbed = map[string]struct{}{
+	"fd_advise":     {},
+	"fd_renumber":   {},
+	"path_readlink": {},
+	"proc_raise":    {},
+	"sched_yield":   {},
+}
+
+// expectation is the JSON file named the same as the %.wasm file. All fields are optional.
+// See https://github.com/WebAssembly/wasi-testsuite/blob/main/doc/specification.md
+type expectation struct {
+	// Args are passed after the program name, which is the name of the %.wasm file.
+	Args []string `json:"args"`
+	// Dirs are directories relative to the %.wasm file, which are pre-opened with the same name.
+	Dirs []string `json:"dirs"`
+	// Env are the environment variables.
+	Env map[string]string `json:"env"`
+	// ExitCode is the expected exit code. This defaults to zero.
+	ExitCode uint32 `json:"exit_code"`
+	// Stderr is the expected content of stderr, or nil to not check it.
+	Stderr *string `json:"stderr"`
+	// Stdout is the expected content of stdout, or nil to not check it.
+	Stdout *string `json:"stdout"`
+}
+
+func TestCompiler(t *testing.T) {
+	if runtime.GOARCH != "amd64" && runtime.GOARCH != "arm64" {
+		t.Skip()
+	}
+	runTestsuite(t, wazero.NewRuntimeConfigCompiler())
+}
+
+func TestInterpreter(t *testing.T) {
+	runTestsuite(t, wazero.NewRuntimeConfigInterpreter())
+}
+
+// runTestsuite runs each %.wasm file in testdataDir as a subtest, so that `go test -v` reports each pass or failure.
+func runTestsuite(t *testing.T, config wazero.RuntimeConfig) {
+	var wasmFiles []string
+	err := filepath.WalkDir(testdataDir, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() && filepath.Ext(p) == ".wasm" {
+			wasmFiles = append(wasmFiles, p)
+		}
+		return err
+	})
+	require.NoError(t, err)
+	require.NotEqual(t, 0, len(wasmFiles))
+
+	// Fail when upstream tests aren't vendored, instead of passing without them.
+	for _, l := range upstreamLangs {
+		lang := l
+		t.Run(lang, func(t *testing.T) {
+			_, err := os.Stat(filepath.Join(testdataDir, lang))
+			require.NoError(t, err, "%s tests aren't vendored: run `make build.wasi_testsuite`", lang)
+		})
+	}
+
+	for _, f := range wasmFiles {
+		wasmFile := f
+		name, err := filepath.Rel(testdataDir, strings.TrimSuffix(wasmFile, ".wasm"))
+		require.NoError(t, err)
+		name = filepath.ToSlash(name)
+
+		t.Run(name, func(t *testing.T) {
+			runTest(t, config, wasmFile)
+		})
+	}
+}
+
+// runTest runs the wasmFile as a command and ensures it exits with the expected code and output.
+func runTest(t *testing.T, config wazero.RuntimeConfig, wasmFile string) {
+	exp := readExpectation(t, strings.TrimSuffix(wasmFile, ".wasm")+".json")
+
+	source, err := os.ReadFile(wasmFile)
+	require.NoError(t, err)
+
+	r := wazero.NewRuntimeWithConfig(config)
+	defer r.Close(testCtx)
+
+	_, err = wasi.InstantiateSnapshotPreview1(testCtx, r)
+	require.NoError(t, err)
+
+	compiled, err := r.CompileModule(testCtx, source, wazero.NewCompileConfig())
+	require.NoError(t, err)
+
+	for _, imp := range compiled.Imports() {
+		if _, ok := stubbed[imp.Name]; ok && imp.Module == wasi.ModuleSnapshotPreview1 {
+			t.Skipf("%s is stubbed with ENOSYS", imp.Name)
+		}
+	}
+
+	var stdout, stderr bytes.Buffer
+	mConfig := wazero.NewModuleConfig().
+		WithArgs(append([]string{filepath.Base(wasmFile)}, exp.Args...)...).
+		WithStdout(&stdout).
+		WithStderr(&stderr)
+
+	// Add the environment in a consistent order, as the guest can see it.
+	keys := make([]string, 0, len(exp.Env))
+	for k := range exp.Env {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		mConfig = mConfig.WithEnv(k, exp.Env[k])
+	}
+
+	// Copy directories, so that tests which write files don't change testdata or each other.
+	for _, dir := range exp.Dirs {
+		tmpDir := t.TempDir()
+		copyDir(t, filepath.Join(filepath.Dir(wasmFile), dir), tmpDir)
+		mConfig = mConfig.WithFSMount(experimental.DirFS(tmpDir), dir)
+	}
+
+	var exitCode uint32
+	if _, err = r.InstantiateModule(testCtx, compiled, mConfig); err != nil {
+		exitErr := &sys.ExitError{}
+		require.True(t, errors.As(err, &exitErr), "unexpected error: %v", err)
+		exitCode = exitErr.ExitCode()
+	}
+
+	require.Equal(t, exp.ExitCode, exitCode, "stderr: %s", stderr.String())
+	if exp.Stdout != nil {
+		require.Equal(t, *exp.Stdout, stdout.String())
+	}
+	if exp.Stderr != nil {
+		require.Equal(t, *exp.Stderr, stderr.String())
+	}
+}
+
+// readExpectation reads the JSON file, or returns the defaults if it doesn't exist.
+func readExpectation(t *testing.T, jsonFile string) *expectation {
+	exp := &expectation{}
+	b, err := os.ReadFile(jsonFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		return exp
+	}
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal(b, exp))
+	return exp
+}
+
+// copyDir copies the files in the src directory into the dst directory.
+func copyDir(t *testing.T, src, dst string) {
+	err := filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.MkdirAll(filepath.Join(dst, rel), 0o700)
+		}
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(dst, rel), b, 0o600)
+	})
+	require.NoError(t, err)
+}
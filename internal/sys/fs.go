@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io/fs"
 	"math"
+	"net"
+	"path"
 	"sync/atomic"
 )
 
@@ -21,6 +23,25 @@ type FileEntry struct {
 	FS   fs.FS
 	// File when nil this is a mount like "." or "/".
 	File fs.File
+	// Fdflags are the WASI fdflags of this file descriptor, such as append. Zero means none.
+	Fdflags uint16
+	// Rights are the WASI rights of this file descriptor, or nil for all rights. Files opened by the host, such as
+	// pre-opened directories, have all rights.
+	Rights *Rights
+	// Dev identifies the file system of this file in WASI filestat, so that files in different mounts don't collide.
+	// When zero, NewFSContext defaults it to the file descriptor.
+	Dev uint64
+	// DirEntries are the sorted entries of this directory, cached by WASI fd_readdir so that reading it in chunks
+	// doesn't re-read it. This is nil until read, and re-read when reading restarts from the first entry.
+	DirEntries []fs.DirEntry
+}
+
+// Rights are the WASI rights of a file descriptor, which limit the operations allowed using it.
+type Rights struct {
+	// Base are the rights of operations using this file descriptor.
+	Base uint64
+	// Inheriting are the maximum rights of file descriptors opened relative to this one.
+	Inheriting uint64
 }
 
 type FSContext struct {
@@ -30,6 +51,9 @@ type FSContext struct {
 
 	// lastFD is not meant to be read directly. Rather by nextFD.
 	lastFD uint32
+
+	// stdioFdflags are the WASI fdflags of stdin, stdout and stderr, which aren't in openedFiles.
+	stdioFdflags [3]uint16
 }
 
 func NewFSContext(openedFiles map[uint32]*FileEntry) *FSContext {
@@ -40,10 +64,13 @@ func NewFSContext(openedFiles map[uint32]*FileEntry) *FSContext {
 	} else {
 		fsCtx.openedFiles = openedFiles
 		fsCtx.lastFD = 2 // STDERR
-		for fd := range openedFiles {
+		for fd, entry := range openedFiles {
 			if fd > fsCtx.lastFD {
 				fsCtx.lastFD = fd
 			}
+			if entry.Dev == 0 {
+				entry.Dev = uint64(fd)
+			}
 		}
 	}
 	return &fsCtx
@@ -96,6 +123,23 @@ func (c *FSContext) OpenedFile(fd uint32) (*FileEntry, bool) {
 	return f, ok
 }
 
+// StdioFdflags returns the WASI fdflags of stdin, stdout or stderr, or zero if fd is not one of them.
+func (c *FSContext) StdioFdflags(fd uint32) uint16 {
+	if fd >= uint32(len(c.stdioFdflags)) {
+		return 0
+	}
+	return c.stdioFdflags[fd]
+}
+
+// SetStdioFdflags sets the WASI fdflags of stdin, stdout or stderr, or returns false if fd is not one of them.
+func (c *FSContext) SetStdioFdflags(fd uint32, fdflags uint16) bool {
+	if fd >= uint32(len(c.stdioFdflags)) {
+		return false
+	}
+	c.stdioFdflags[fd] = fdflags
+	return true
+}
+
 // OpenFile returns the file descriptor of the new file or false if we ran out of file descriptors
 func (c *FSContext) OpenFile(f *FileEntry) (uint32, bool) {
 	newFD := c.nextFD()
@@ -113,6 +157,8 @@ type FSConfig struct {
 	preopens map[uint32]*FileEntry
 	// preopenPaths allow overwriting of existing paths.
 	preopenPaths map[string]uint32
+	// sockets are opened after the pre-opened directories, in the order they were added.
+	sockets []*FileEntry
 }
 
 func NewFSConfig() *FSConfig {
@@ -123,37 +169,87 @@ func NewFSConfig() *FSConfig {
 	}
 }
 
-// setFS maps a path to a file-system. This is only used for base paths: "/" and ".".
-func (c *FSConfig) setFS(path string, fs fs.FS) {
+// clone makes a deep copy of this FS config, so that changes aren't visible to prior instances.
+func (c *FSConfig) clone() *FSConfig {
+	ret := *c // copy
+	ret.preopens = make(map[uint32]*FileEntry, len(c.preopens))
+	for fd, entry := range c.preopens {
+		ret.preopens[fd] = entry
+	}
+	ret.preopenPaths = make(map[string]uint32, len(c.preopenPaths))
+	for p, fd := range c.preopenPaths {
+		ret.preopenPaths[p] = fd
+	}
+	ret.sockets = append([]*FileEntry(nil), c.sockets...)
+	return &ret
+}
+
+// setFS maps a path to a file-system. The path is cleaned, so "/data/" and "/data" are the same mount.
+func (c *FSConfig) setFS(guestPath string, fs fs.FS) {
+	guestPath = path.Clean(guestPath)
 	// Check to see if this key already exists and update it.
-	entry := &FileEntry{Path: path, FS: fs}
-	if fd, ok := c.preopenPaths[path]; ok {
+	entry := &FileEntry{Path: guestPath, FS: fs}
+	if fd, ok := c.preopenPaths[guestPath]; ok {
 		c.preopens[fd] = entry
 	} else {
 		c.preopens[c.preopenFD] = entry
-		c.preopenPaths[path] = c.preopenFD
+		c.preopenPaths[guestPath] = c.preopenFD
 		c.preopenFD++
 	}
 }
 
 func (c *FSConfig) WithFS(fs fs.FS) *FSConfig {
-	ret := *c // copy
+	ret := c.clone()
 	ret.setFS("/", fs)
-	return &ret
+	return ret
 }
 
 func (c *FSConfig) WithWorkDirFS(fs fs.FS) *FSConfig {
-	ret := *c // copy
+	ret := c.clone()
 	ret.setFS(".", fs)
-	return &ret
+	return ret
+}
+
+// WithFSMount maps the guest path, such as "/tmp", to a file-system. Each path is pre-opened in the order it was first
+// added, with its own file descriptor.
+func (c *FSConfig) WithFSMount(fs fs.FS, guestPath string) *FSConfig {
+	ret := c.clone()
+	ret.setFS(guestPath, fs)
+	return ret
+}
+
+// WithListener adds a listening socket, which is opened after the pre-opened directories.
+func (c *FSConfig) WithListener(ln net.Listener) *FSConfig {
+	ret := c.clone()
+	entry := &FileEntry{} // File is nil when ln is, which is an error in OpenedFiles.
+	if ln != nil {
+		entry.Path, entry.File = ln.Addr().String(), &ListenerFile{Listener: ln}
+	}
+	ret.sockets = append(ret.sockets, entry)
+	return ret
+}
+
+// WithConn adds a connected socket, which is opened after the pre-opened directories.
+func (c *FSConfig) WithConn(conn net.Conn) *FSConfig {
+	ret := c.clone()
+	entry := &FileEntry{} // File is nil when conn is, which is an error in OpenedFiles.
+	if conn != nil {
+		entry.Path, entry.File = conn.RemoteAddr().String(), &ConnFile{Conn: conn}
+	}
+	ret.sockets = append(ret.sockets, entry)
+	return ret
 }
 
-func (c *FSConfig) Preopens() (map[uint32]*FileEntry, error) {
+// OpenedFiles returns the files to open in a new FSContext: pre-opened directories, followed by any sockets.
+//
+// Note: Sockets are after pre-opened directories because guests, such as those compiled with wasi-libc, stop looking
+// for pre-opened directories at the first file descriptor that isn't one.
+func (c *FSConfig) OpenedFiles() (map[uint32]*FileEntry, error) {
 	// Ensure no-one set a nil FD. We do this here instead of at the call site to allow chaining as nil is unexpected.
 	rootFD := uint32(0) // zero is invalid
 	setWorkDirFS := false
-	preopens := c.preopens
-	for fd, entry := range preopens {
+	preopens := make(map[uint32]*FileEntry, len(c.preopens)+1)
+	for fd, entry := range c.preopens {
 		if entry.FS == nil {
 			return nil, fmt.Errorf("FS for %s is nil", entry.Path)
 		} else if entry.Path == "/" {
@@ -161,12 +257,25 @@ func (c *FSConfig) Preopens() (map[uint32]*FileEntry, error) {
 		} else if entry.Path == "." {
 			setWorkDirFS = true
 		}
+		e := *entry // copy, as a module can change its flags and rights
+		preopens[fd] = &e
 	}
 
 	// Default the working directory to the root FS if it exists.
+	nextFD := c.preopenFD
 	if rootFD != 0 && !setWorkDirFS {
-		preopens[c.preopenFD] = &FileEntry{Path: ".", FS: preopens[rootFD].FS}
+		preopens[nextFD] = &FileEntry{Path: ".", FS: preopens[rootFD].FS, Dev: uint64(rootFD)} // same file system
+		nextFD++
 	}
 
+	for i, entry := range c.sockets {
+		if entry.File == nil {
+			return nil, fmt.Errorf("socket[%d] is nil", i)
+		}
+		e := *entry // copy, though the socket is shared with other modules
+		acquire(e.File)
+		preopens[nextFD] = &e
+		nextFD++
+	}
 	return preopens, nil
 }
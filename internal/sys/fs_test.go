@@ -3,6 +3,7 @@ package sys
 import (
 	"context"
 	"io/fs"
+	"net"
 	"os"
 	"path"
 	"testing"
@@ -47,3 +48,67 @@ func createWriteableFile(t *testing.T, tmpDir string, pathName string, data []by
 	require.NoError(t, err)
 	return f, os.DirFS(tmpDir)
 }
+
+func TestFSConfig_WithFSMount(t *testing.T) {
+	dataFS, tmpFS := os.DirFS("data"), os.DirFS("tmp")
+
+	base := NewFSConfig().WithFSMount(dataFS, "/data")
+	withTmp := base.WithFSMount(tmpFS, "/tmp/")
+
+	// Ensure the prior config is unchanged
+	preopens, err := base.OpenedFiles()
+	require.NoError(t, err)
+	require.Equal(t, map[uint32]*FileEntry{3: {Path: "/data", FS: dataFS}}, preopens)
+
+	preopens, err = withTmp.OpenedFiles()
+	require.NoError(t, err)
+	require.Equal(t, map[uint32]*FileEntry{
+		3: {Path: "/data", FS: dataFS},
+		4: {Path: "/tmp", FS: tmpFS},
+	}, preopens)
+}
+
+func TestFSConfig_WithListener(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
+	guest, host := net.Pipe()
+	defer guest.Close()
+	defer host.Close()
+
+	rootFS := os.DirFS("root")
+	c := NewFSConfig().WithListener(ln).WithFS(rootFS).WithConn(guest)
+
+	// Sockets are after the pre-opened directories, including the default working directory.
+	openedFiles, err := c.OpenedFiles()
+	require.NoError(t, err)
+	require.Equal(t, map[uint32]*FileEntry{
+		3: {Path: "/", FS: rootFS},
+		4: {Path: ".", FS: rootFS, Dev: 3},
+		5: {Path: ln.Addr().String(), File: &ListenerFile{Listener: ln, refs: 1}},
+		6: {Path: guest.RemoteAddr().String(), File: &ConnFile{Conn: guest, refs: 1}},
+	}, openedFiles)
+}
+
+func TestFSConfig_WithListener_Shared(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	c := NewFSConfig().WithListener(ln)
+
+	// Each module shares the listener, so it is only closed with the last reference.
+	openedFiles1, err := c.OpenedFiles()
+	require.NoError(t, err)
+	openedFiles2, err := c.OpenedFiles()
+	require.NoError(t, err)
+	require.Same(t, openedFiles1[3].File, openedFiles2[3].File)
+
+	fsc1, fsc2 := NewFSContext(openedFiles1), NewFSContext(openedFiles2)
+	require.NoError(t, fsc1.Close(testCtx))
+	conn, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	require.NoError(t, fsc2.Close(testCtx))
+	_, err = net.Dial("tcp", ln.Addr().String())
+	require.Error(t, err)
+}
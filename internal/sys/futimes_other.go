@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || freebsd)
+
+package sys
+
+import (
+	"os"
+	"time"
+)
+
+// futimes changes the times of the file by the name it was opened with, as this platform has no portable way to change
+// them by handle.
+func futimes(f *os.File, atime, mtime time.Time) error {
+	return os.Chtimes(f.Name(), atime, mtime)
+}
@@ -0,0 +1,30 @@
+//go:build linux || darwin || freebsd
+
+package sys
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// futimes changes the times of the open file, with microsecond precision.
+func futimes(f *os.File, atime, mtime time.Time) error {
+	tv := []syscall.Timeval{
+		syscall.NsecToTimeval(atime.UnixNano()),
+		syscall.NsecToTimeval(mtime.UnixNano()),
+	}
+	// SyscallConn avoids Fd, which would put the file into blocking mode.
+	rc, err := f.SyscallConn()
+	if err != nil {
+		return err
+	}
+	var errno error
+	if err = rc.Control(func(fd uintptr) { errno = syscall.Futimes(int(fd), tv) }); err != nil {
+		return err
+	}
+	if errno != nil {
+		return &os.PathError{Op: "futimes", Path: f.Name(), Err: errno}
+	}
+	return nil
+}
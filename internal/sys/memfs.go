@@ -0,0 +1,610 @@
+package sys
+
+import (
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// maxSymlinkHops is the count of symbolic links followed before failing with syscall.ELOOP, same as Linux.
+const maxSymlinkHops = 40
+
+// NewMemFS returns an empty WriteFS backed by memory.
+//
+// Note: Files are discarded when garbage collected. Modes are retained, but not enforced.
+func NewMemFS() WriteFS {
+	return &memFS{root: &memNode{mode: fs.ModeDir | 0o755, modTime: time.Now(), entries: map[string]*memNode{}}}
+}
+
+// memFS implements WriteFS with a tree of memNode. All reads and writes are guarded by mux.
+type memFS struct {
+	mux  sync.Mutex
+	root *memNode
+}
+
+// memNode is a file, directory or symbolic link.
+type memNode struct {
+	mode    fs.FileMode
+	modTime time.Time
+	// data is the contents of a file or the target of a symbolic link.
+	data []byte
+	// entries are the children of a directory, keyed by name.
+	entries map[string]*memNode
+}
+
+func (n *memNode) isDir() bool {
+	return n.mode.IsDir()
+}
+
+func (n *memNode) isSymlink() bool {
+	return n.mode&fs.ModeSymlink != 0
+}
+
+// lookupParent returns the parent directory and base name of a path that may not exist yet. Symbolic links are followed
+// in all but the last path segment. Callers must hold mux.
+func (m *memFS) lookupParent(op, name string, hops int) (*memNode, string, error) {
+	if !fs.ValidPath(name) {
+		return nil, "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return nil, ".", nil
+	}
+	dir, base := path.Split(name)
+	dir = strings.TrimSuffix(dir, "/")
+	if dir == "" {
+		return m.root, base, nil
+	}
+	parent, err := m.lookup(op, dir, true, hops)
+	if err != nil {
+		return nil, "", err
+	}
+	if !parent.isDir() {
+		return nil, "", &fs.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+	}
+	return parent, base, nil
+}
+
+// lookup returns the node at the name, following a symbolic link in the last segment if follow is true. Callers must
+// hold mux.
+func (m *memFS) lookup(op, name string, follow bool, hops int) (*memNode, error) {
+	parent, base, err := m.lookupParent(op, name, hops)
+	if err != nil {
+		return nil, err
+	}
+	if parent == nil { // "."
+		return m.root, nil
+	}
+	n, ok := parent.entries[base]
+	if !ok {
+		return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrNotExist}
+	}
+	if follow && n.isSymlink() {
+		if hops >= maxSymlinkHops {
+			return nil, &fs.PathError{Op: op, Path: name, Err: syscall.ELOOP}
+		}
+		target := string(n.data)
+		if path.IsAbs(target) {
+			return nil, &fs.PathError{Op: op, Path: name, Err: fs.ErrPermission}
+		}
+		return m.lookup(op, path.Join(path.Dir(name), target), true, hops+1)
+	}
+	return n, nil
+}
+
+// Open implements fs.FS Open
+func (m *memFS) Open(name string) (fs.File, error) {
+	return m.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// OpenFile implements WriteFS.OpenFile
+func (m *memFS) OpenFile(name string, flag int, perm fs.FileMode) (fs.File, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	writable := flag&(os.O_WRONLY|os.O_RDWR) != 0
+	n, err := m.lookup("open", name, true, 0)
+	switch {
+	case err == nil:
+		if flag&(os.O_CREATE|os.O_EXCL) == os.O_CREATE|os.O_EXCL {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+		}
+	case flag&os.O_CREATE != 0 && isNotExist(err):
+		parent, base, err := m.lookupParent("open", name, 0)
+		if err != nil {
+			return nil, err
+		}
+		if parent == nil { // "."
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+		}
+		if _, ok := parent.entries[base]; ok { // dangling symbolic link
+			return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrExist}
+		}
+		n = &memNode{mode: perm.Perm(), modTime: time.Now()}
+		parent.entries[base] = n
+		parent.modTime = n.modTime
+	default:
+		return nil, err
+	}
+
+	if n.isDir() {
+		if writable {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: syscall.EISDIR}
+		}
+		return &memDir{fs: m, name: name, node: n}, nil
+	}
+
+	if writable && flag&os.O_TRUNC != 0 {
+		n.data = n.data[:0]
+		n.modTime = time.Now()
+	}
+	return &memFile{fs: m, name: name, node: n, flag: flag}, nil
+}
+
+// Lstat implements WriteFS.Lstat
+func (m *memFS) Lstat(name string) (fs.FileInfo, error) {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	n, err := m.lookup("lstat", name, false, 0)
+	if err != nil {
+		return nil, err
+	}
+	return newMemFileInfo(name, n), nil
+}
+
+// Mkdir implements WriteFS.Mkdir
+func (m *memFS) Mkdir(name string, perm fs.FileMode) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	parent, base, err := m.lookupParent("mkdir", name, 0)
+	if err != nil {
+		return err
+	}
+	if parent == nil { // "."
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	if _, ok := parent.entries[base]; ok {
+		return &fs.PathError{Op: "mkdir", Path: name, Err: fs.ErrExist}
+	}
+	now := time.Now()
+	parent.entries[base] = &memNode{mode: fs.ModeDir | perm.Perm(), modTime: now, entries: map[string]*memNode{}}
+	parent.modTime = now
+	return nil
+}
+
+// Remove implements WriteFS.Remove
+func (m *memFS) Remove(name string) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	parent, base, err := m.lookupParent("remove", name, 0)
+	if err != nil {
+		return err
+	}
+	if parent == nil { // "."
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrInvalid}
+	}
+	n, ok := parent.entries[base]
+	if !ok {
+		return &fs.PathError{Op: "remove", Path: name, Err: fs.ErrNotExist}
+	}
+	if n.isDir() && len(n.entries) > 0 {
+		return &fs.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+	}
+	delete(parent.entries, base)
+	parent.modTime = time.Now()
+	return nil
+}
+
+// Rename implements WriteFS.Rename
+func (m *memFS) Rename(oldName, newName string) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	oldParent, oldBase, err := m.lookupParent("rename", oldName, 0)
+	if err != nil {
+		return err
+	}
+	newParent, newBase, err := m.lookupParent("rename", newName, 0)
+	if err != nil {
+		return err
+	}
+	if oldParent == nil || newParent == nil { // "."
+		return &fs.PathError{Op: "rename", Path: oldName, Err: syscall.EBUSY}
+	}
+	n, ok := oldParent.entries[oldBase]
+	if !ok {
+		return &fs.PathError{Op: "rename", Path: oldName, Err: fs.ErrNotExist}
+	}
+	if n.isDir() && (newName == oldName || strings.HasPrefix(newName, oldName+"/")) {
+		if newName == oldName {
+			return nil
+		}
+		return &fs.PathError{Op: "rename", Path: oldName, Err: syscall.EINVAL}
+	}
+	if existing, ok := newParent.entries[newBase]; ok {
+		switch {
+		case existing == n:
+			return nil
+		case n.isDir() && !existing.isDir():
+			return &fs.PathError{Op: "rename", Path: newName, Err: syscall.ENOTDIR}
+		case !n.isDir() && existing.isDir():
+			return &fs.PathError{Op: "rename", Path: newName, Err: syscall.EISDIR}
+		case existing.isDir() && len(existing.entries) > 0:
+			return &fs.PathError{Op: "rename", Path: newName, Err: syscall.ENOTEMPTY}
+		}
+	}
+	delete(oldParent.entries, oldBase)
+	newParent.entries[newBase] = n
+	now := time.Now()
+	oldParent.modTime, newParent.modTime = now, now
+	return nil
+}
+
+// Link implements WriteFS.Link
+func (m *memFS) Link(oldName, newName string) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	n, err := m.lookup("link", oldName, false, 0)
+	if err != nil {
+		return err
+	}
+	if n.isDir() {
+		return &fs.PathError{Op: "link", Path: oldName, Err: fs.ErrPermission}
+	}
+	return m.addEntry("link", newName, n)
+}
+
+// Symlink implements WriteFS.Symlink
+func (m *memFS) Symlink(oldName, newName string) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	if err := validateSymlink(oldName, newName); err != nil {
+		return err
+	}
+	n := &memNode{mode: fs.ModeSymlink | 0o777, modTime: time.Now(), data: []byte(oldName)}
+	return m.addEntry("symlink", newName, n)
+}
+
+// addEntry adds the node to the parent of name, failing if it already exists. Callers must hold mux.
+func (m *memFS) addEntry(op, name string, n *memNode) error {
+	parent, base, err := m.lookupParent(op, name, 0)
+	if err != nil {
+		return err
+	}
+	if parent == nil { // "."
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrExist}
+	}
+	if _, ok := parent.entries[base]; ok {
+		return &fs.PathError{Op: op, Path: name, Err: fs.ErrExist}
+	}
+	parent.entries[base] = n
+	parent.modTime = time.Now()
+	return nil
+}
+
+// Truncate implements WriteFS.Truncate
+func (m *memFS) Truncate(name string, size int64) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	n, err := m.lookup("truncate", name, true, 0)
+	if err != nil {
+		return err
+	}
+	if n.isDir() {
+		return &fs.PathError{Op: "truncate", Path: name, Err: syscall.EISDIR}
+	}
+	if size < 0 {
+		return &fs.PathError{Op: "truncate", Path: name, Err: syscall.EINVAL}
+	}
+	n.truncate(size)
+	return nil
+}
+
+// truncate sets the length of data, padding with zeros as needed. Callers must hold mux.
+func (n *memNode) truncate(size int64) {
+	if size <= int64(len(n.data)) {
+		n.data = n.data[:size]
+	} else {
+		n.data = append(n.data, make([]byte, size-int64(len(n.data)))...)
+	}
+	n.modTime = time.Now()
+}
+
+// Chtimes implements WriteFS.Chtimes
+//
+// Note: Only the modification time is retained as access times aren't tracked.
+func (m *memFS) Chtimes(name string, _, mtime time.Time) error {
+	m.mux.Lock()
+	defer m.mux.Unlock()
+
+	n, err := m.lookup("chtimes", name, true, 0)
+	if err != nil {
+		return err
+	}
+	n.modTime = mtime
+	return nil
+}
+
+func isNotExist(err error) bool {
+	pe, ok := err.(*fs.PathError)
+	return ok && pe.Err == fs.ErrNotExist
+}
+
+// memFileInfo implements fs.FileInfo and fs.DirEntry from a snapshot of a memNode.
+type memFileInfo struct {
+	name    string
+	size    int64
+	mode    fs.FileMode
+	modTime time.Time
+}
+
+func newMemFileInfo(name string, n *memNode) *memFileInfo {
+	return &memFileInfo{name: path.Base(name), size: int64(len(n.data)), mode: n.mode, modTime: n.modTime}
+}
+
+// Name implements fs.FileInfo Name
+func (i *memFileInfo) Name() string { return i.name }
+
+// Size implements fs.FileInfo Size
+func (i *memFileInfo) Size() int64 { return i.size }
+
+// Mode implements fs.FileInfo Mode
+func (i *memFileInfo) Mode() fs.FileMode { return i.mode }
+
+// ModTime implements fs.FileInfo ModTime
+func (i *memFileInfo) ModTime() time.Time { return i.modTime }
+
+// IsDir implements fs.FileInfo IsDir
+func (i *memFileInfo) IsDir() bool { return i.mode.IsDir() }
+
+// Sys implements fs.FileInfo Sys
+func (i *memFileInfo) Sys() interface{} { return nil }
+
+// Type implements fs.DirEntry Type
+func (i *memFileInfo) Type() fs.FileMode { return i.mode.Type() }
+
+// Info implements fs.DirEntry Info
+func (i *memFileInfo) Info() (fs.FileInfo, error) { return i, nil }
+
+// memFile is an open regular file in a memFS. It implements io.Writer, io.Seeker, io.ReaderAt and io.WriterAt, like
+// os.File.
+type memFile struct {
+	fs     *memFS
+	name   string
+	node   *memNode
+	flag   int
+	offset int64
+	closed bool
+}
+
+// Stat implements fs.File Stat
+func (f *memFile) Stat() (fs.FileInfo, error) {
+	f.fs.mux.Lock()
+	defer f.fs.mux.Unlock()
+	if f.closed {
+		return nil, &fs.PathError{Op: "stat", Path: f.name, Err: fs.ErrClosed}
+	}
+	return newMemFileInfo(f.name, f.node), nil
+}
+
+// Read implements fs.File Read
+func (f *memFile) Read(b []byte) (int, error) {
+	f.fs.mux.Lock()
+	defer f.fs.mux.Unlock()
+	n, err := f.readAt("read", b, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+// ReadAt implements io.ReaderAt
+func (f *memFile) ReadAt(b []byte, off int64) (int, error) {
+	f.fs.mux.Lock()
+	defer f.fs.mux.Unlock()
+	n, err := f.readAt("read", b, off)
+	if err == nil && n < len(b) {
+		err = io.EOF // io.ReaderAt must return an error when n < len(b)
+	}
+	return n, err
+}
+
+// readAt reads from the offset without updating it. Callers must hold mux.
+func (f *memFile) readAt(op string, b []byte, off int64) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: op, Path: f.name, Err: fs.ErrClosed}
+	}
+	if f.flag&os.O_WRONLY != 0 {
+		return 0, &fs.PathError{Op: op, Path: f.name, Err: syscall.EBADF}
+	}
+	if off < 0 {
+		return 0, &fs.PathError{Op: op, Path: f.name, Err: syscall.EINVAL}
+	}
+	if off >= int64(len(f.node.data)) {
+		if len(b) == 0 {
+			return 0, nil
+		}
+		return 0, io.EOF
+	}
+	return copy(b, f.node.data[off:]), nil
+}
+
+// Write implements io.Writer
+func (f *memFile) Write(b []byte) (int, error) {
+	f.fs.mux.Lock()
+	defer f.fs.mux.Unlock()
+	if f.flag&os.O_APPEND != 0 {
+		f.offset = int64(len(f.node.data))
+	}
+	n, err := f.writeAt("write", b, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+// WriteAt implements io.WriterAt
+func (f *memFile) WriteAt(b []byte, off int64) (int, error) {
+	f.fs.mux.Lock()
+	defer f.fs.mux.Unlock()
+	if f.flag&os.O_APPEND != 0 { // same as os.File
+		return 0, &fs.PathError{Op: "writeat", Path: f.name, Err: syscall.EINVAL}
+	}
+	return f.writeAt("writeat", b, off)
+}
+
+// writeAt writes at the offset without updating it, growing the file as needed. Callers must hold mux.
+func (f *memFile) writeAt(op string, b []byte, off int64) (int, error) {
+	if f.closed {
+		return 0, &fs.PathError{Op: op, Path: f.name, Err: fs.ErrClosed}
+	}
+	if f.flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return 0, &fs.PathError{Op: op, Path: f.name, Err: syscall.EBADF}
+	}
+	if off < 0 {
+		return 0, &fs.PathError{Op: op, Path: f.name, Err: syscall.EINVAL}
+	}
+	if end := off + int64(len(b)); end > int64(len(f.node.data)) {
+		f.node.truncate(end)
+	}
+	copy(f.node.data[off:], b)
+	f.node.modTime = time.Now()
+	return len(b), nil
+}
+
+// Truncate is like os.File Truncate
+func (f *memFile) Truncate(size int64) error {
+	f.fs.mux.Lock()
+	defer f.fs.mux.Unlock()
+	if f.closed {
+		return &fs.PathError{Op: "truncate", Path: f.name, Err: fs.ErrClosed}
+	}
+	if f.flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		return &fs.PathError{Op: "truncate", Path: f.name, Err: syscall.EBADF}
+	}
+	if size < 0 {
+		return &fs.PathError{Op: "truncate", Path: f.name, Err: syscall.EINVAL}
+	}
+	f.node.truncate(size)
+	return nil
+}
+
+// Chtimes is like memFS.Chtimes, except it changes the open file.
+func (f *memFile) Chtimes(_, mtime time.Time) error {
+	f.fs.mux.Lock()
+	defer f.fs.mux.Unlock()
+	if f.closed {
+		return &fs.PathError{Op: "chtimes", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.node.modTime = mtime
+	return nil
+}
+
+// Seek implements io.Seeker
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	f.fs.mux.Lock()
+	defer f.fs.mux.Unlock()
+	if f.closed {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: fs.ErrClosed}
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += int64(len(f.node.data))
+	default:
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: syscall.EINVAL}
+	}
+	if offset < 0 {
+		return 0, &fs.PathError{Op: "seek", Path: f.name, Err: syscall.EINVAL}
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+// Close implements fs.File Close
+func (f *memFile) Close() error {
+	f.fs.mux.Lock()
+	defer f.fs.mux.Unlock()
+	if f.closed {
+		return &fs.PathError{Op: "close", Path: f.name, Err: fs.ErrClosed}
+	}
+	f.closed = true
+	return nil
+}
+
+// memDir is an open directory in a memFS. It implements fs.ReadDirFile.
+type memDir struct {
+	fs   *memFS
+	name string
+	node *memNode
+	// entries are sorted by name on the first call to ReadDir.
+	entries []fs.DirEntry
+	offset  int
+	closed  bool
+}
+
+// Stat implements fs.File Stat
+func (d *memDir) Stat() (fs.FileInfo, error) {
+	d.fs.mux.Lock()
+	defer d.fs.mux.Unlock()
+	if d.closed {
+		return nil, &fs.PathError{Op: "stat", Path: d.name, Err: fs.ErrClosed}
+	}
+	return newMemFileInfo(d.name, d.node), nil
+}
+
+// Read implements fs.File Read
+func (d *memDir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.name, Err: syscall.EISDIR}
+}
+
+// ReadDir implements fs.ReadDirFile ReadDir
+func (d *memDir) ReadDir(count int) ([]fs.DirEntry, error) {
+	d.fs.mux.Lock()
+	defer d.fs.mux.Unlock()
+	if d.closed {
+		return nil, &fs.PathError{Op: "readdir", Path: d.name, Err: fs.ErrClosed}
+	}
+
+	if d.entries == nil {
+		d.entries = make([]fs.DirEntry, 0, len(d.node.entries))
+		for name, n := range d.node.entries {
+			d.entries = append(d.entries, newMemFileInfo(name, n))
+		}
+		sort.Slice(d.entries, func(i, j int) bool { return d.entries[i].Name() < d.entries[j].Name() })
+	}
+
+	remaining := d.entries[d.offset:]
+	if count <= 0 {
+		d.offset = len(d.entries)
+		return remaining, nil
+	}
+	if len(remaining) == 0 {
+		return nil, io.EOF
+	}
+	if count > len(remaining) {
+		count = len(remaining)
+	}
+	d.offset += count
+	return remaining[:count], nil
+}
+
+// Close implements fs.File Close
+func (d *memDir) Close() error {
+	d.fs.mux.Lock()
+	defer d.fs.mux.Unlock()
+	if d.closed {
+		return &fs.PathError{Op: "close", Path: d.name, Err: fs.ErrClosed}
+	}
+	d.closed = true
+	return nil
+}
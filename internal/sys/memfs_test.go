@@ -0,0 +1,80 @@
+package sys
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+	"testing/fstest"
+
+	"github.com/tetratelabs/wazero/internal/testing/require"
+)
+
+func TestMemFS_TestFS(t *testing.T) {
+	memFS := NewMemFS()
+	require.NoError(t, memFS.Mkdir("dir", 0o700))
+	for _, name := range []string{"a.txt", "dir/b.txt"} {
+		f, err := memFS.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o600)
+		require.NoError(t, err)
+		_, err = f.(io.Writer).Write([]byte(name))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+
+	require.NoError(t, fstest.TestFS(memFS, "a.txt", "dir", "dir/b.txt"))
+}
+
+func TestMemFS_SymlinkLoop(t *testing.T) {
+	memFS := NewMemFS()
+	require.NoError(t, memFS.Symlink("b", "a"))
+	require.NoError(t, memFS.Symlink("a", "b"))
+
+	_, err := memFS.Open("a")
+	require.True(t, errors.Is(err, syscall.ELOOP))
+}
+
+func TestMemFile_ReadWriteAt(t *testing.T) {
+	memFS := NewMemFS()
+	f, err := memFS.OpenFile("file", os.O_RDWR|os.O_CREATE, 0o600)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = f.(io.WriterAt).WriteAt([]byte("zero"), 2)
+	require.NoError(t, err)
+
+	b := make([]byte, 6)
+	n, err := f.(io.ReaderAt).ReadAt(b, 0)
+	require.NoError(t, err)
+	require.Equal(t, 6, n)
+	require.Equal(t, []byte{0, 0, 'z', 'e', 'r', 'o'}, b)
+
+	n, err = f.(io.ReaderAt).ReadAt(b, 4)
+	require.Equal(t, io.EOF, err)
+	require.Equal(t, 2, n)
+
+	// The offset was not affected by the positional reads and writes.
+	offset, err := f.(io.Seeker).Seek(0, io.SeekCurrent)
+	require.NoError(t, err)
+	require.Zero(t, offset)
+}
+
+func TestMemFile_ReadOnly(t *testing.T) {
+	memFS := NewMemFS()
+	f, err := memFS.OpenFile("file", os.O_RDONLY|os.O_CREATE, 0o600)
+	require.NoError(t, err)
+	defer f.Close()
+
+	_, err = f.(io.Writer).Write([]byte("wazero"))
+	require.True(t, errors.Is(err, syscall.EBADF))
+
+	_, err = memFS.OpenFile(".", os.O_RDWR, 0)
+	require.True(t, errors.Is(err, syscall.EISDIR))
+
+	_, err = f.Stat()
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	_, err = f.Stat()
+	require.True(t, errors.Is(err, fs.ErrClosed))
+}
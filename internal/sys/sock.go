@@ -0,0 +1,123 @@
+package sys
+
+import (
+	"io/fs"
+	"net"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+// ListenerFile adapts a net.Listener to fs.File, so that it can be an entry in FSContext. Functions such as
+// "sock_accept" in "wasi_snapshot_preview1" detect this type to accept connections.
+//
+// Note: A ListenerFile added to FSConfig is shared by every module instantiated with that config, as a listener can't
+// be duplicated. Each FSConfig.OpenedFiles references it, and Close only closes the Listener when the last reference
+// is closed.
+type ListenerFile struct {
+	Listener net.Listener
+	refs     int32
+}
+
+// Stat implements fs.File Stat
+func (f *ListenerFile) Stat() (fs.FileInfo, error) {
+	return &sockInfo{name: f.Listener.Addr().String()}, nil
+}
+
+// Read implements fs.File Read by returning syscall.ENOTCONN, as a listener has no data to read.
+func (f *ListenerFile) Read([]byte) (int, error) {
+	return 0, syscall.ENOTCONN
+}
+
+// Close implements fs.File Close
+func (f *ListenerFile) Close() error {
+	if release(&f.refs) {
+		return f.Listener.Close()
+	}
+	return nil
+}
+
+// ConnFile adapts a net.Conn to fs.File, so that it can be an entry in FSContext. This allows functions such as
+// "fd_read" in "wasi_snapshot_preview1" to use the connection, in addition to socket functions like "sock_recv".
+//
+// Note: This is shared and reference counted the same way as ListenerFile.
+type ConnFile struct {
+	Conn net.Conn
+	refs int32
+}
+
+// Stat implements fs.File Stat
+func (f *ConnFile) Stat() (fs.FileInfo, error) {
+	return &sockInfo{name: f.Conn.RemoteAddr().String()}, nil
+}
+
+// Read implements fs.File Read
+func (f *ConnFile) Read(p []byte) (int, error) {
+	return f.Conn.Read(p)
+}
+
+// SetReadDeadline implements the same function on net.Conn, which allows non-blocking reads.
+func (f *ConnFile) SetReadDeadline(t time.Time) error {
+	return f.Conn.SetReadDeadline(t)
+}
+
+// SyscallConn implements syscall.Conn when Conn does, such as net.TCPConn, which allows reading without waiting for
+// data.
+func (f *ConnFile) SyscallConn() (syscall.RawConn, error) {
+	if sc, ok := f.Conn.(syscall.Conn); ok {
+		return sc.SyscallConn()
+	}
+	return nil, syscall.ENOTSUP
+}
+
+// Write implements io.Writer
+func (f *ConnFile) Write(p []byte) (int, error) {
+	return f.Conn.Write(p)
+}
+
+// Close implements fs.File Close
+func (f *ConnFile) Close() error {
+	if release(&f.refs) {
+		return f.Conn.Close()
+	}
+	return nil
+}
+
+// acquire adds a reference to a socket file, which is released by its Close.
+func acquire(f fs.File) {
+	switch f := f.(type) {
+	case *ListenerFile:
+		atomic.AddInt32(&f.refs, 1)
+	case *ConnFile:
+		atomic.AddInt32(&f.refs, 1)
+	}
+}
+
+// release returns true when the last reference was released, or there were none, as is the case for a connection
+// accepted by a module.
+func release(refs *int32) bool {
+	return atomic.AddInt32(refs, -1) <= 0
+}
+
+// sockInfo implements fs.FileInfo for ListenerFile and ConnFile.
+type sockInfo struct {
+	name string
+}
+
+// Name implements fs.FileInfo Name
+func (i *sockInfo) Name() string { return i.name }
+
+// Size implements fs.FileInfo Size
+func (i *sockInfo) Size() int64 { return 0 }
+
+// Mode implements fs.FileInfo Mode
+func (i *sockInfo) Mode() fs.FileMode { return fs.ModeSocket | 0o600 }
+
+// ModTime implements fs.FileInfo ModTime
+func (i *sockInfo) ModTime() time.Time { return time.Time{} }
+
+// IsDir implements fs.FileInfo IsDir
+func (i *sockInfo) IsDir() bool { return false }
+
+// Sys implements fs.FileInfo Sys
+func (i *sockInfo) Sys() interface{} { return nil }
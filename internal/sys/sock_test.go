@@ -0,0 +1,59 @@
+package sys
+
+import (
+	"io"
+	"io/fs"
+	"net"
+	"syscall"
+	"testing"
+
+	"github.com/tetratelabs/wazero/internal/testing/require"
+)
+
+func TestListenerFile(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	f := &ListenerFile{Listener: ln}
+
+	st, err := f.Stat()
+	require.NoError(t, err)
+	require.Equal(t, ln.Addr().String(), st.Name())
+	require.Equal(t, fs.ModeSocket, st.Mode().Type())
+
+	_, err = f.Read(make([]byte, 1))
+	require.Equal(t, syscall.ENOTCONN, err)
+
+	require.NoError(t, f.Close())
+	_, err = ln.Accept()
+	require.Error(t, err)
+}
+
+func TestConnFile(t *testing.T) {
+	guest, host := net.Pipe()
+	defer host.Close()
+	f := &ConnFile{Conn: guest}
+
+	st, err := f.Stat()
+	require.NoError(t, err)
+	require.Equal(t, guest.RemoteAddr().String(), st.Name())
+	require.Equal(t, fs.ModeSocket, st.Mode().Type())
+
+	go func() {
+		_, _ = host.Write([]byte("wazero"))
+	}()
+	b := make([]byte, 6)
+	_, err = io.ReadFull(f, b)
+	require.NoError(t, err)
+	require.Equal(t, "wazero", string(b))
+
+	go func() {
+		_, _ = io.ReadFull(host, b)
+	}()
+	n, err := f.Write([]byte("orezaw"))
+	require.NoError(t, err)
+	require.Equal(t, 6, n)
+
+	require.NoError(t, f.Close())
+	_, err = host.Read(b)
+	require.Equal(t, io.EOF, err)
+}
@@ -4,3 +4,7 @@ package sys
 //
 // See https://github.com/tetratelabs/wazero/issues/491
 type TimeNowUnixNanoKey struct{}
+
+// NanosleepKey is a context.Context Value key. Its associated value should be a
+// func(ctx context.Context, ns int64), which blocks for ns nanoseconds or until ctx is done.
+type NanosleepKey struct{}
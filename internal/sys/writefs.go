@@ -0,0 +1,200 @@
+package sys
+
+import (
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// WriteFS is a file system that supports mutations needed by functions such as "path_create_directory" in
+// "wasi_snapshot_preview1". Functions that only read use the embedded fs.FS.
+//
+// Like fs.FS, names are unrooted, slash-separated paths, validated with fs.ValidPath. Errors should wrap fs.ErrNotExist,
+// fs.ErrExist or a syscall.Errno, such as syscall.ENOTEMPTY, so that callers can map them to an error code.
+//
+// Note: This is an interface as a writable file system is detected from fs.FS, similar to how io.Writer is detected
+// from fs.File.
+type WriteFS interface {
+	fs.FS
+
+	// OpenFile is like os.OpenFile, except the name is relative to this file system.
+	OpenFile(name string, flag int, perm fs.FileMode) (fs.File, error)
+
+	// Lstat is like os.Lstat, except the name is relative to this file system.
+	Lstat(name string) (fs.FileInfo, error)
+
+	// Mkdir is like os.Mkdir, except the name is relative to this file system.
+	Mkdir(name string, perm fs.FileMode) error
+
+	// Remove is like os.Remove, except the name is relative to this file system.
+	Remove(name string) error
+
+	// Rename is like os.Rename, except the names are relative to this file system.
+	Rename(oldName, newName string) error
+
+	// Link is like os.Link, except the names are relative to this file system.
+	Link(oldName, newName string) error
+
+	// Symlink is like os.Symlink, except newName is relative to this file system. oldName is not resolved, but it fails
+	// with fs.ErrPermission if oldName is absolute or would resolve outside this file system.
+	Symlink(oldName, newName string) error
+
+	// Truncate is like os.Truncate, except the name is relative to this file system.
+	Truncate(name string, size int64) error
+
+	// Chtimes is like os.Chtimes, except the name is relative to this file system.
+	Chtimes(name string, atime, mtime time.Time) error
+}
+
+// NewDirFS returns a WriteFS for the tree of files rooted at the host directory dir.
+//
+// Note: Like os.DirFS, this doesn't prevent symbolic links that already exist inside dir from escaping it. Symlink
+// refuses to create new ones that would.
+func NewDirFS(dir string) WriteFS {
+	return dirFS(dir)
+}
+
+// dirFS implements WriteFS with the os package.
+type dirFS string
+
+// join returns the host path of name or an error if it is not valid per fs.ValidPath.
+func (d dirFS) join(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+	return filepath.Join(string(d), filepath.FromSlash(name)), nil
+}
+
+// Open implements fs.FS Open
+func (d dirFS) Open(name string) (fs.File, error) {
+	return d.OpenFile(name, os.O_RDONLY, 0)
+}
+
+// OpenFile implements WriteFS.OpenFile
+func (d dirFS) OpenFile(name string, flag int, perm fs.FileMode) (fs.File, error) {
+	p, err := d.join("open", name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(p, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return f, nil // Don't return a typed nil *os.File
+}
+
+// Lstat implements WriteFS.Lstat
+func (d dirFS) Lstat(name string) (fs.FileInfo, error) {
+	p, err := d.join("lstat", name)
+	if err != nil {
+		return nil, err
+	}
+	return os.Lstat(p)
+}
+
+// Mkdir implements WriteFS.Mkdir
+func (d dirFS) Mkdir(name string, perm fs.FileMode) error {
+	p, err := d.join("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return os.Mkdir(p, perm)
+}
+
+// Remove implements WriteFS.Remove
+func (d dirFS) Remove(name string) error {
+	p, err := d.join("remove", name)
+	if err != nil {
+		return err
+	}
+	return os.Remove(p)
+}
+
+// Rename implements WriteFS.Rename
+func (d dirFS) Rename(oldName, newName string) error {
+	from, err := d.join("rename", oldName)
+	if err != nil {
+		return err
+	}
+	to, err := d.join("rename", newName)
+	if err != nil {
+		return err
+	}
+	return os.Rename(from, to)
+}
+
+// Link implements WriteFS.Link
+func (d dirFS) Link(oldName, newName string) error {
+	from, err := d.join("link", oldName)
+	if err != nil {
+		return err
+	}
+	to, err := d.join("link", newName)
+	if err != nil {
+		return err
+	}
+	return os.Link(from, to)
+}
+
+// Symlink implements WriteFS.Symlink
+func (d dirFS) Symlink(oldName, newName string) error {
+	to, err := d.join("symlink", newName)
+	if err != nil {
+		return err
+	}
+	if err = validateSymlink(oldName, newName); err != nil {
+		return err
+	}
+	return os.Symlink(filepath.FromSlash(oldName), to)
+}
+
+// Truncate implements WriteFS.Truncate
+func (d dirFS) Truncate(name string, size int64) error {
+	p, err := d.join("truncate", name)
+	if err != nil {
+		return err
+	}
+	return os.Truncate(p, size)
+}
+
+// Chtimes implements WriteFS.Chtimes
+func (d dirFS) Chtimes(name string, atime, mtime time.Time) error {
+	p, err := d.join("chtimes", name)
+	if err != nil {
+		return err
+	}
+	return os.Chtimes(p, atime, mtime)
+}
+
+// validateSymlink returns fs.ErrPermission if the target of a symbolic link at newName would resolve outside the file
+// system, ex. "/etc/passwd" or "../../etc/passwd".
+func validateSymlink(oldName, newName string) error {
+	if path.IsAbs(oldName) || !fs.ValidPath(path.Join(path.Dir(newName), oldName)) {
+		return &fs.PathError{Op: "symlink", Path: newName, Err: fs.ErrPermission}
+	}
+	return nil
+}
+
+// TruncateFile is like os.File Truncate. Unlike WriteFS.Truncate, this resizes the open file even if it was renamed or
+// unlinked since it was opened.
+func TruncateFile(f fs.File, size int64) error {
+	if t, ok := f.(interface{ Truncate(int64) error }); ok {
+		return t.Truncate(size)
+	}
+	return syscall.EBADF
+}
+
+// ChtimesFile is like WriteFS.Chtimes, except it changes the times of the open file even if it was renamed or unlinked
+// since it was opened.
+func ChtimesFile(f fs.File, atime, mtime time.Time) error {
+	switch f := f.(type) {
+	case *memFile:
+		return f.Chtimes(atime, mtime)
+	case *os.File:
+		return futimes(f, atime, mtime)
+	}
+	return syscall.EBADF
+}
@@ -0,0 +1,191 @@
+package sys
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/tetratelabs/wazero/internal/testing/require"
+)
+
+func TestWriteFS(t *testing.T) {
+	tests := []struct {
+		name    string
+		newFS   func(t *testing.T) WriteFS
+		symlink bool
+	}{
+		{name: "NewDirFS", newFS: func(t *testing.T) WriteFS { return NewDirFS(t.TempDir()) }},
+		{name: "NewMemFS", newFS: func(*testing.T) WriteFS { return NewMemFS() }},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			t.Run("OpenFile", func(t *testing.T) {
+				wfs := tc.newFS(t)
+
+				_, err := wfs.OpenFile("file", os.O_RDWR, 0o600)
+				require.True(t, errors.Is(err, fs.ErrNotExist))
+
+				f, err := wfs.OpenFile("file", os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+				require.NoError(t, err)
+				_, err = f.(io.Writer).Write([]byte("wazero"))
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+
+				_, err = wfs.OpenFile("file", os.O_RDWR|os.O_CREATE|os.O_EXCL, 0o600)
+				require.True(t, errors.Is(err, fs.ErrExist))
+
+				b, err := fs.ReadFile(wfs, "file")
+				require.NoError(t, err)
+				require.Equal(t, "wazero", string(b))
+
+				f, err = wfs.OpenFile("file", os.O_WRONLY|os.O_APPEND, 0)
+				require.NoError(t, err)
+				_, err = f.(io.Writer).Write([]byte("!"))
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+
+				b, err = fs.ReadFile(wfs, "file")
+				require.NoError(t, err)
+				require.Equal(t, "wazero!", string(b))
+
+				f, err = wfs.OpenFile("file", os.O_RDWR|os.O_TRUNC, 0)
+				require.NoError(t, err)
+				st, err := f.Stat()
+				require.NoError(t, err)
+				require.Zero(t, st.Size())
+				require.NoError(t, f.Close())
+
+				_, err = wfs.OpenFile("../file", os.O_RDONLY, 0)
+				require.True(t, errors.Is(err, fs.ErrInvalid))
+			})
+
+			t.Run("Mkdir and Remove", func(t *testing.T) {
+				wfs := tc.newFS(t)
+
+				require.NoError(t, wfs.Mkdir("dir", 0o700))
+				require.True(t, errors.Is(wfs.Mkdir("dir", 0o700), fs.ErrExist))
+				require.True(t, errors.Is(wfs.Mkdir("missing/dir", 0o700), fs.ErrNotExist))
+
+				f, err := wfs.OpenFile("dir/file", os.O_RDWR|os.O_CREATE, 0o600)
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+
+				st, err := fs.Stat(wfs, "dir")
+				require.NoError(t, err)
+				require.True(t, st.IsDir())
+
+				require.True(t, errors.Is(wfs.Remove("dir"), syscall.ENOTEMPTY))
+				require.NoError(t, wfs.Remove("dir/file"))
+				require.NoError(t, wfs.Remove("dir"))
+				require.True(t, errors.Is(wfs.Remove("dir"), fs.ErrNotExist))
+			})
+
+			t.Run("Rename", func(t *testing.T) {
+				wfs := tc.newFS(t)
+
+				require.NoError(t, wfs.Mkdir("dir", 0o700))
+				f, err := wfs.OpenFile("file", os.O_RDWR|os.O_CREATE, 0o600)
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+
+				require.NoError(t, wfs.Rename("file", "dir/file"))
+				_, err = fs.Stat(wfs, "file")
+				require.True(t, errors.Is(err, fs.ErrNotExist))
+				_, err = fs.Stat(wfs, "dir/file")
+				require.NoError(t, err)
+
+				require.True(t, errors.Is(wfs.Rename("missing", "file"), fs.ErrNotExist))
+			})
+
+			t.Run("Link and Symlink", func(t *testing.T) {
+				wfs := tc.newFS(t)
+
+				f, err := wfs.OpenFile("file", os.O_RDWR|os.O_CREATE, 0o600)
+				require.NoError(t, err)
+				_, err = f.(io.Writer).Write([]byte("wazero"))
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+
+				require.NoError(t, wfs.Link("file", "hardlink"))
+				b, err := fs.ReadFile(wfs, "hardlink")
+				require.NoError(t, err)
+				require.Equal(t, "wazero", string(b))
+
+				require.NoError(t, wfs.Symlink("file", "symlink"))
+				b, err = fs.ReadFile(wfs, "symlink")
+				require.NoError(t, err)
+				require.Equal(t, "wazero", string(b))
+
+				st, err := wfs.Lstat("symlink")
+				require.NoError(t, err)
+				require.Equal(t, fs.ModeSymlink, st.Mode().Type())
+
+				require.True(t, errors.Is(wfs.Symlink("file", "symlink"), fs.ErrExist))
+
+				require.NoError(t, wfs.Mkdir("dir", 0o700))
+				require.NoError(t, wfs.Symlink("../file", "dir/symlink"))
+				for _, target := range []string{"/etc/passwd", "../file", "dir/../../file"} {
+					err = wfs.Symlink(target, "escape")
+					require.True(t, errors.Is(err, fs.ErrPermission), target)
+				}
+				_, err = wfs.Lstat("escape")
+				require.True(t, errors.Is(err, fs.ErrNotExist))
+			})
+
+			t.Run("Truncate and Chtimes", func(t *testing.T) {
+				wfs := tc.newFS(t)
+
+				f, err := wfs.OpenFile("file", os.O_RDWR|os.O_CREATE, 0o600)
+				require.NoError(t, err)
+				require.NoError(t, f.Close())
+
+				require.NoError(t, wfs.Truncate("file", 10))
+				b, err := fs.ReadFile(wfs, "file")
+				require.NoError(t, err)
+				require.Equal(t, make([]byte, 10), b)
+
+				mtime := time.Unix(1640995200, 0)
+				require.NoError(t, wfs.Chtimes("file", mtime, mtime))
+				st, err := fs.Stat(wfs, "file")
+				require.NoError(t, err)
+				require.Equal(t, mtime.UnixNano(), st.ModTime().UnixNano())
+
+				require.True(t, errors.Is(wfs.Truncate("missing", 10), fs.ErrNotExist))
+			})
+
+			t.Run("TruncateFile and ChtimesFile", func(t *testing.T) {
+				wfs := tc.newFS(t)
+
+				f, err := wfs.OpenFile("file", os.O_RDWR|os.O_CREATE, 0o600)
+				require.NoError(t, err)
+				defer f.Close()
+
+				// The open file is changed, not whatever is at its original name.
+				require.NoError(t, wfs.Rename("file", "renamed"))
+				other, err := wfs.OpenFile("file", os.O_RDWR|os.O_CREATE, 0o600)
+				require.NoError(t, err)
+				require.NoError(t, other.Close())
+
+				require.NoError(t, TruncateFile(f, 10))
+				mtime := time.Unix(1640995200, 0)
+				require.NoError(t, ChtimesFile(f, mtime, mtime))
+
+				st, err := fs.Stat(wfs, "renamed")
+				require.NoError(t, err)
+				require.Equal(t, int64(10), st.Size())
+				require.Equal(t, mtime.UnixNano(), st.ModTime().UnixNano())
+
+				st, err = fs.Stat(wfs, "file")
+				require.NoError(t, err)
+				require.Zero(t, st.Size())
+				require.NotEqual(t, mtime.UnixNano(), st.ModTime().UnixNano())
+			})
+		})
+	}
+}
@@ -10,6 +10,7 @@ import (
 )
 
 func decodeConstantExpression(r *bytes.Reader, enabledFeatures wasm.Features) (*wasm.ConstantExpression, error) {
+	offsetAtOpcode := r.Size() - int64(r.Len())
 	b, err := r.ReadByte()
 	if err != nil {
 		return nil, fmt.Errorf("read opcode: %v", err)
@@ -78,12 +79,49 @@ func decodeConstantExpression(r *bytes.Reader, enabledFeatures wasm.Features) (*
 		return nil, fmt.Errorf("read value: %v", err)
 	}
 
-	if b, err = r.ReadByte(); err != nil {
-		return nil, fmt.Errorf("look for end opcode: %v", err)
+	// With FeatureExtendedConst, more instructions can follow until the end opcode.
+	offsetAtLastOpcode := int64(-1)
+	for {
+		offsetAtNextOpcode := r.Size() - int64(r.Len())
+		if b, err = r.ReadByte(); err != nil {
+			return nil, fmt.Errorf("look for end opcode: %v", err)
+		}
+
+		if b == wasm.OpcodeEnd {
+			break
+		} else if !enabledFeatures.Get(wasm.FeatureExtendedConst) {
+			return nil, fmt.Errorf("constant expression has been not terminated")
+		}
+
+		switch b {
+		case wasm.OpcodeI32Const:
+			_, _, err = leb128.DecodeInt32(r)
+		case wasm.OpcodeI64Const:
+			_, _, err = leb128.DecodeInt64(r)
+		case wasm.OpcodeGlobalGet:
+			_, _, err = leb128.DecodeUint32(r)
+		case wasm.OpcodeI32Add, wasm.OpcodeI32Sub, wasm.OpcodeI32Mul, wasm.OpcodeI64Add, wasm.OpcodeI64Sub, wasm.OpcodeI64Mul:
+		default:
+			return nil, fmt.Errorf("%v for const expression opt code: %#x", ErrInvalidByte, b)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read value: %v", err)
+		}
+		opcode, offsetAtLastOpcode = b, offsetAtNextOpcode
 	}
 
-	if b != wasm.OpcodeEnd {
-		return nil, fmt.Errorf("constant expression has been not terminated")
+	if offsetAtLastOpcode >= 0 {
+		expr := &wasm.ConstantExpression{Opcode: opcode}
+		if !expr.IsExtended() {
+			return nil, fmt.Errorf("constant expression must end with an arithmetic instruction, but was %s",
+				wasm.InstructionName(opcode))
+		}
+		// The instructions before the last one are kept as the data. See wasm.ConstantExpression.IsExtended
+		expr.Data = make([]byte, offsetAtLastOpcode-offsetAtOpcode)
+		if _, err := r.ReadAt(expr.Data, offsetAtOpcode); err != nil {
+			return nil, fmt.Errorf("error re-buffering ConstantExpression.Data")
+		}
+		return expr, nil
 	}
 
 	data := make([]byte, remainingBeforeData-int64(r.Len())-1)
@@ -95,8 +133,14 @@ func decodeConstantExpression(r *bytes.Reader, enabledFeatures wasm.Features) (*
 }
 
 func encodeConstantExpression(expr *wasm.ConstantExpression) (ret []byte) {
-	ret = append(ret, expr.Opcode)
-	ret = append(ret, expr.Data...)
+	if expr.IsExtended() {
+		// The data holds the instructions before the last one. See wasm.ConstantExpression.IsExtended
+		ret = append(ret, expr.Data...)
+		ret = append(ret, expr.Opcode)
+	} else {
+		ret = append(ret, expr.Opcode)
+		ret = append(ret, expr.Data...)
+	}
 	ret = append(ret, wasm.OpcodeEnd)
 	return
 }
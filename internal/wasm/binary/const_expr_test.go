@@ -91,6 +91,33 @@ func TestDecodeConstantExpression(t *testing.T) {
 	}
 }
 
+func TestDecodeConstantExpression_ExtendedConst(t *testing.T) {
+	// global.get 0 + i32.const 16 * i32.const 2
+	in := []byte{
+		wasm.OpcodeGlobalGet, 0,
+		wasm.OpcodeI32Const, 16,
+		wasm.OpcodeI32Const, 2,
+		wasm.OpcodeI32Mul,
+		wasm.OpcodeI32Add,
+		wasm.OpcodeEnd,
+	}
+	exp := &wasm.ConstantExpression{
+		Opcode: wasm.OpcodeI32Add,
+		Data: []byte{
+			wasm.OpcodeGlobalGet, 0,
+			wasm.OpcodeI32Const, 16,
+			wasm.OpcodeI32Const, 2,
+			wasm.OpcodeI32Mul,
+		},
+	}
+
+	actual, err := decodeConstantExpression(bytes.NewReader(in), wasm.FeatureExtendedConst)
+	require.NoError(t, err)
+	require.Equal(t, exp, actual)
+	require.True(t, actual.IsExtended())
+	require.Equal(t, in, encodeConstantExpression(actual))
+}
+
 func TestDecodeConstantExpression_errors(t *testing.T) {
 	tests := []struct {
 		in          []byte
@@ -176,6 +203,46 @@ func TestDecodeConstantExpression_errors(t *testing.T) {
 			expectedErr: "read vector const instruction immediates: needs 16 bytes but was 8 bytes",
 			features:    wasm.FeatureSIMD,
 		},
+		{
+			in: []byte{
+				wasm.OpcodeI32Const, 1,
+				wasm.OpcodeI32Const, 2,
+				wasm.OpcodeI32Add,
+				wasm.OpcodeEnd,
+			},
+			expectedErr: "constant expression has been not terminated",
+			features:    wasm.Features20220419,
+		},
+		{
+			in: []byte{
+				wasm.OpcodeI32Const, 1,
+				wasm.OpcodeI32Const, 2,
+				wasm.OpcodeI32DivS,
+				wasm.OpcodeEnd,
+			},
+			expectedErr: "invalid byte for const expression opt code: 0x6d",
+			features:    wasm.FeatureExtendedConst,
+		},
+		{
+			in: []byte{
+				wasm.OpcodeI32Const, 1,
+				wasm.OpcodeI32Const, 2,
+				wasm.OpcodeI32Add,
+				wasm.OpcodeI32Const, 3,
+				wasm.OpcodeEnd,
+			},
+			expectedErr: "constant expression must end with an arithmetic instruction, but was i32.const",
+			features:    wasm.FeatureExtendedConst,
+		},
+		{
+			in: []byte{
+				wasm.OpcodeI32Const, 1,
+				wasm.OpcodeI32Const, 2,
+				wasm.OpcodeI32Add,
+			},
+			expectedErr: "look for end opcode: EOF",
+			features:    wasm.FeatureExtendedConst,
+		},
 	}
 
 	for _, tt := range tests {
@@ -127,3 +127,13 @@ func Test_decodeDataSegment(t *testing.T) {
 		})
 	}
 }
+
+func Test_encodeDataSegment_Passive(t *testing.T) {
+	d := &wasm.DataSegment{Init: []byte{0xf, 0xf}}
+	bin := encodeDataSegment(d)
+	require.Equal(t, []byte{0x1, 0x2, 0xf, 0xf}, bin)
+
+	actual, err := decodeDataSegment(bytes.NewReader(bin), wasm.FeatureBulkMemoryOperations)
+	require.NoError(t, err)
+	require.Equal(t, d, actual)
+}
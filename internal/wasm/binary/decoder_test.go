@@ -71,7 +71,7 @@ func TestDecodeModule(t *testing.T) {
 			name: "table and memory section",
 			input: &wasm.Module{
 				TableSection:  []*wasm.Table{{Min: 3, Type: wasm.RefTypeFuncref}},
-				MemorySection: &wasm.Memory{Min: 1, Cap: 1, Max: 1, IsMaxEncoded: true},
+				MemorySection: []*wasm.Memory{{Min: 1, Cap: 1, Max: 1, IsMaxEncoded: true}},
 			},
 		},
 		{
@@ -128,6 +128,25 @@ func TestDecodeModule(t *testing.T) {
 		_, e := DecodeModule(input, wasm.Features20191205, wasm.MemorySizer)
 		require.EqualError(t, e, `data count section not supported as feature "bulk-memory-operations" is disabled`)
 	})
+	t.Run("tag section", func(t *testing.T) {
+		input := &wasm.Module{
+			TypeSection: []*wasm.FunctionType{
+				{Params: []wasm.ValueType{i32}, ParamNumInUint64: 1},
+			},
+			ImportSection: []*wasm.Import{{Module: "env", Name: "error", Type: wasm.ExternTypeTag, DescTag: 0}},
+			TagSection:    []wasm.Index{0, 0},
+			ExportSection: []*wasm.Export{{Name: "mine", Type: wasm.ExternTypeTag, Index: 1}},
+		}
+		m, e := DecodeModule(EncodeModule(input), wasm.Features20191205|wasm.FeatureExceptionHandling, wasm.MemorySizer)
+		require.NoError(t, e)
+		require.Equal(t, input, m)
+	})
+	t.Run("tag section disabled", func(t *testing.T) {
+		input := append(append(Magic, version...),
+			wasm.SectionIDTag, 1, 0)
+		_, e := DecodeModule(input, wasm.Features20191205, wasm.MemorySizer)
+		require.EqualError(t, e, `tag section not supported as feature "exception-handling" is disabled`)
+	})
 }
 
 func TestDecodeModule_Errors(t *testing.T) {
@@ -169,13 +188,19 @@ func TestDecodeModule_Errors(t *testing.T) {
 				subsectionIDModuleName, 0x02, 0x01, 'x'),
 			expectedErr: "section custom: redundant custom section name",
 		},
+		{
+			name: "invalid tag attribute",
+			input: append(append(Magic, version...),
+				wasm.SectionIDTag, 3, 1, 0x01, 0),
+			expectedErr: "section tag: tag[0]: invalid byte: invalid tag attribute: 0x1",
+		},
 	}
 
 	for _, tt := range tests {
 		tc := tt
 
 		t.Run(tc.name, func(t *testing.T) {
-			_, e := DecodeModule(tc.input, wasm.Features20191205, wasm.MemorySizer)
+			_, e := DecodeModule(tc.input, wasm.Features20191205|wasm.FeatureExceptionHandling, wasm.MemorySizer)
 			require.EqualError(t, e, tc.expectedErr)
 		})
 	}
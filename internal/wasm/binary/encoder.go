@@ -30,6 +30,11 @@ func EncodeModule(m *wasm.Module) (bytes []byte) {
 	if m.SectionElementCount(wasm.SectionIDMemory) > 0 {
 		bytes = append(bytes, encodeMemorySection(m.MemorySection)...)
 	}
+	if m.SectionElementCount(wasm.SectionIDTag) > 0 {
+		// >> The tag section comes after the memory section and before the global section.
+		// See https://github.com/WebAssembly/exception-handling/blob/main/proposals/exception-handling/Exceptions.md#tag-section
+		bytes = append(bytes, encodeTagSection(m.TagSection)...)
+	}
 	if m.SectionElementCount(wasm.SectionIDGlobal) > 0 {
 		bytes = append(bytes, encodeGlobalSection(m.GlobalSection)...)
 	}
@@ -42,6 +47,11 @@ func EncodeModule(m *wasm.Module) (bytes []byte) {
 	if m.SectionElementCount(wasm.SectionIDElement) > 0 {
 		bytes = append(bytes, encodeElementSection(m.ElementSection)...)
 	}
+	if m.DataCountSection != nil {
+		// >> The data count section comes after the element section and before the code section.
+		// See https://www.w3.org/TR/2022/WD-wasm-core-2-20220419/binary/modules.html#data-count-section
+		bytes = append(bytes, encodeDataCountSection(*m.DataCountSection)...)
+	}
 	if m.SectionElementCount(wasm.SectionIDCode) > 0 {
 		bytes = append(bytes, encodeCodeSection(m.CodeSection)...)
 	}
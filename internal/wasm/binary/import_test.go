@@ -102,6 +102,22 @@ func TestEncodeImport(t *testing.T) {
 				wasm.ValueTypeF64, 0x01, // 1 == var
 			},
 		},
+		{
+			name: "tag",
+			input: &wasm.Import{ // Ex. (import "env" "error" (tag (type 1)))
+				Type:    wasm.ExternTypeTag,
+				Module:  "env",
+				Name:    "error",
+				DescTag: 1,
+			},
+			expected: []byte{
+				0x03, 'e', 'n', 'v',
+				0x05, 'e', 'r', 'r', 'o', 'r',
+				wasm.ExternTypeTag,
+				0x00, // exception attribute
+				0x01,
+			},
+		},
 		{
 			name: "table",
 			input: &wasm.Import{
@@ -3,50 +3,74 @@ package binary
 import (
 	"bytes"
 	"fmt"
+	"math"
 
 	"github.com/tetratelabs/wazero/internal/leb128"
 )
 
 // decodeLimitsType returns the `limitsType` (min, max) decoded with the WebAssembly 1.0 (20191205) Binary Format.
+// shared is true when the limits are flagged as shared, which is only valid for memories with FeatureThreads.
+// is64 is true when the limits are of a memory indexed with i64, which is only valid with FeatureMemory64. Such limits
+// are encoded as 64-bit, but saturate to math.MaxUint32 when decoded as that's beyond any limit of wazero.
 //
 // See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#limits%E2%91%A6
-func decodeLimitsType(r *bytes.Reader) (min uint32, max *uint32, err error) {
+// See https://github.com/WebAssembly/threads/blob/main/proposals/threads/Overview.md#spec-changes
+// See https://github.com/WebAssembly/memory64/blob/main/proposals/memory64/Overview.md#binary-format
+func decodeLimitsType(r *bytes.Reader) (min uint32, max *uint32, shared, is64 bool, err error) {
 	var flag byte
 	if flag, err = r.ReadByte(); err != nil {
 		err = fmt.Errorf("read leading byte: %v", err)
 		return
 	}
 
-	switch flag {
-	case 0x00:
-		min, _, err = leb128.DecodeUint32(r)
-		if err != nil {
-			err = fmt.Errorf("read min of limit: %v", err)
-		}
-	case 0x01:
-		min, _, err = leb128.DecodeUint32(r)
-		if err != nil {
-			err = fmt.Errorf("read min of limit: %v", err)
-			return
-		}
+	if flag > 0x07 {
+		err = fmt.Errorf("%v for limits: %#x not in (0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07)", ErrInvalidByte, flag)
+		return
+	}
+	shared = flag&0x02 != 0
+	is64 = flag&0x04 != 0
+
+	if min, err = decodeLimit(r, is64); err != nil {
+		err = fmt.Errorf("read min of limit: %v", err)
+		return
+	}
+	if flag&0x01 != 0 {
 		var m uint32
-		if m, _, err = leb128.DecodeUint32(r); err != nil {
+		if m, err = decodeLimit(r, is64); err != nil {
 			err = fmt.Errorf("read max of limit: %v", err)
 		} else {
 			max = &m
 		}
-	default:
-		err = fmt.Errorf("%v for limits: %#x != 0x00 or 0x01", ErrInvalidByte, flag)
 	}
 	return
 }
 
+// decodeLimit decodes a single limit, saturating it to math.MaxUint32 when is64.
+func decodeLimit(r *bytes.Reader, is64 bool) (uint32, error) {
+	if !is64 {
+		v, _, err := leb128.DecodeUint32(r)
+		return v, err
+	}
+	v, _, err := leb128.DecodeUint64(r)
+	if v > math.MaxUint32 {
+		v = math.MaxUint32
+	}
+	return uint32(v), err
+}
+
 // encodeLimitsType returns the `limitsType` (min, max) encoded in WebAssembly 1.0 (20191205) Binary Format.
 //
 // See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#limits%E2%91%A6
-func encodeLimitsType(min uint32, max *uint32) []byte {
+func encodeLimitsType(min uint32, max *uint32, shared, is64 bool) []byte {
+	var flag uint32
+	if shared {
+		flag = 0x02
+	}
+	if is64 {
+		flag |= 0x04
+	}
 	if max == nil {
-		return append(leb128.EncodeUint32(0x00), leb128.EncodeUint32(min)...)
+		return append(leb128.EncodeUint32(flag), leb128.EncodeUint32(min)...)
 	}
-	return append(leb128.EncodeUint32(0x01), append(leb128.EncodeUint32(min), leb128.EncodeUint32(*max)...)...)
+	return append(leb128.EncodeUint32(flag|0x01), append(leb128.EncodeUint32(min), leb128.EncodeUint32(*max)...)...)
 }
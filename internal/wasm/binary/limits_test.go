@@ -17,6 +17,8 @@ func TestLimitsType(t *testing.T) {
 		name     string
 		min      uint32
 		max      *uint32
+		shared   bool
+		is64     bool
 		expected []byte
 	}{
 		{
@@ -44,21 +46,67 @@ func TestLimitsType(t *testing.T) {
 			max:      &largest,
 			expected: []byte{0x1, 0xff, 0xff, 0xff, 0xff, 0xf, 0xff, 0xff, 0xff, 0xff, 0xf},
 		},
+		{
+			name:     "shared min 0",
+			shared:   true,
+			expected: []byte{0x2, 0},
+		},
+		{
+			name:     "shared min 0, max 0",
+			max:      &zero,
+			shared:   true,
+			expected: []byte{0x3, 0, 0},
+		},
+		{
+			name:     "i64 min 0",
+			is64:     true,
+			expected: []byte{0x4, 0},
+		},
+		{
+			name:     "i64 min 0, max largest",
+			max:      &largest,
+			is64:     true,
+			expected: []byte{0x5, 0, 0xff, 0xff, 0xff, 0xff, 0xf},
+		},
+		{
+			name:     "shared i64 min 0, max 0",
+			max:      &zero,
+			shared:   true,
+			is64:     true,
+			expected: []byte{0x7, 0, 0},
+		},
 	}
 
 	for _, tt := range tests {
 		tc := tt
 
-		b := encodeLimitsType(tc.min, tc.max)
+		b := encodeLimitsType(tc.min, tc.max, tc.shared, tc.is64)
 		t.Run(fmt.Sprintf("encode - %s", tc.name), func(t *testing.T) {
 			require.Equal(t, tc.expected, b)
 		})
 
 		t.Run(fmt.Sprintf("decode - %s", tc.name), func(t *testing.T) {
-			min, max, err := decodeLimitsType(bytes.NewReader(b))
+			min, max, shared, is64, err := decodeLimitsType(bytes.NewReader(b))
 			require.NoError(t, err)
 			require.Equal(t, min, tc.min)
 			require.Equal(t, max, tc.max)
+			require.Equal(t, shared, tc.shared)
+			require.Equal(t, is64, tc.is64)
 		})
 	}
 }
+
+func TestDecodeLimitsType_Memory64Saturates(t *testing.T) {
+	// i64 limits can be larger than math.MaxUint32, which is beyond any limit in wazero.
+	b := []byte{0x5, 0x80, 0x80, 0x80, 0x80, 0x10, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x1}
+	min, max, _, is64, err := decodeLimitsType(bytes.NewReader(b))
+	require.NoError(t, err)
+	require.True(t, is64)
+	require.Equal(t, uint32(math.MaxUint32), min)
+	require.Equal(t, uint32(math.MaxUint32), *max)
+}
+
+func TestDecodeLimitsType_Errors(t *testing.T) {
+	_, _, _, _, err := decodeLimitsType(bytes.NewReader([]byte{0x8, 0}))
+	require.EqualError(t, err, "invalid byte for limits: 0x8 not in (0x00, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07)")
+}
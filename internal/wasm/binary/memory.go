@@ -2,6 +2,7 @@ package binary
 
 import (
 	"bytes"
+	"fmt"
 
 	"github.com/tetratelabs/wazero/internal/wasm"
 )
@@ -12,14 +13,48 @@ import (
 func decodeMemory(
 	r *bytes.Reader,
 	memorySizer func(minPages uint32, maxPages *uint32) (min, capacity, max uint32),
+	enabledFeatures wasm.Features,
 ) (*wasm.Memory, error) {
-	min, maxP, err := decodeLimitsType(r)
+	min, maxP, shared, is64, err := decodeLimitsType(r)
 	if err != nil {
 		return nil, err
 	}
 
+	if is64 {
+		if err = enabledFeatures.Require(wasm.FeatureMemory64); err != nil {
+			return nil, fmt.Errorf("memory indexed with i64 is invalid as %v", err)
+		}
+		// The max of a memory indexed with i64 can be far beyond what wazero supports. As the memory would never
+		// grow that much anyway, lower it to the implementation limit instead of rejecting the module.
+		if maxP != nil && *maxP > wasm.MemoryLimitPages && min <= wasm.MemoryLimitPages {
+			limit := wasm.MemoryLimitPages
+			maxP = &limit
+		}
+	}
+
+	if shared {
+		if err = enabledFeatures.Require(wasm.FeatureThreads); err != nil {
+			return nil, fmt.Errorf("shared memory is invalid as %v", err)
+		} else if maxP == nil {
+			return nil, fmt.Errorf("shared memory must have max")
+		}
+	}
+
 	min, capacity, max := memorySizer(min, maxP)
-	mem := &wasm.Memory{Min: min, Cap: capacity, Max: max, IsMaxEncoded: maxP != nil}
+	if shared {
+		if min > wasm.MemoryLimitPagesShared {
+			return nil, fmt.Errorf("shared memory min %d pages (%s) over limit of %d pages (%s)", min,
+				wasm.PagesToUnitOfBytes(min), wasm.MemoryLimitPagesShared, wasm.PagesToUnitOfBytes(wasm.MemoryLimitPagesShared))
+		}
+		// Shared memory is allocated up to its max, so that growing it never moves the buffer which might be in use
+		// by other threads. Lower the max to the limit, so that the allocation is bounded, like memory.grow failing
+		// past it.
+		if max > wasm.MemoryLimitPagesShared {
+			max = wasm.MemoryLimitPagesShared
+		}
+		capacity = max
+	}
+	mem := &wasm.Memory{Min: min, Cap: capacity, Max: max, IsMaxEncoded: maxP != nil, IsShared: shared, IsMemory64: is64}
 
 	return mem, mem.Validate()
 }
@@ -32,5 +67,5 @@ func encodeMemory(i *wasm.Memory) []byte {
 	if !i.IsMaxEncoded {
 		maxPtr = nil
 	}
-	return encodeLimitsType(i.Min, maxPtr)
+	return encodeLimitsType(i.Min, maxPtr, i.IsShared, i.IsMemory64)
 }
@@ -48,6 +48,21 @@ func TestMemoryType(t *testing.T) {
 			input:    &wasm.Memory{Min: max, Cap: max, Max: max, IsMaxEncoded: true},
 			expected: []byte{0x1, 0x80, 0x80, 0x4, 0x80, 0x80, 0x4},
 		},
+		{
+			name:     "shared",
+			input:    &wasm.Memory{Min: 1, Cap: 2, Max: 2, IsMaxEncoded: true, IsShared: true},
+			expected: []byte{0x3, 1, 2},
+		},
+		{
+			name:     "memory64",
+			input:    &wasm.Memory{Min: 1, Cap: 1, Max: wasm.MemoryLimitPages, IsMemory64: true},
+			expected: []byte{0x4, 1},
+		},
+		{
+			name:     "memory64 max",
+			input:    &wasm.Memory{Min: 1, Cap: 1, Max: 2, IsMaxEncoded: true, IsMemory64: true},
+			expected: []byte{0x5, 1, 2},
+		},
 	}
 
 	for _, tt := range tests {
@@ -59,17 +74,38 @@ func TestMemoryType(t *testing.T) {
 		})
 
 		t.Run(fmt.Sprintf("decode %s", tc.name), func(t *testing.T) {
-			binary, err := decodeMemory(bytes.NewReader(b), wasm.MemorySizer)
+			binary, err := decodeMemory(bytes.NewReader(b), wasm.MemorySizer, wasm.FeatureThreads|wasm.FeatureMemory64)
 			require.NoError(t, err)
 			require.Equal(t, binary, tc.input)
 		})
 	}
 }
 
+func TestDecodeMemoryType_Memory64MaxOverLimit(t *testing.T) {
+	// max of 2^48 pages, which is the largest for a memory indexed with i64, is lowered to the limit of wazero.
+	mem, err := decodeMemory(bytes.NewReader([]byte{0x5, 1, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x40}), wasm.MemorySizer, wasm.FeatureMemory64)
+	require.NoError(t, err)
+	require.Equal(t, &wasm.Memory{Min: 1, Cap: 1, Max: wasm.MemoryLimitPages, IsMaxEncoded: true, IsMemory64: true}, mem)
+}
+
+func TestDecodeMemoryType_SharedMaxOverLimit(t *testing.T) {
+	// Shared memory is allocated up to its max, so the max of 65536 pages is lowered to the limit of shared memory.
+	mem, err := decodeMemory(bytes.NewReader([]byte{0x3, 1, 0x80, 0x80, 0x4}), wasm.MemorySizer, wasm.FeatureThreads)
+	require.NoError(t, err)
+	require.Equal(t, &wasm.Memory{
+		Min:          1,
+		Cap:          wasm.MemoryLimitPagesShared,
+		Max:          wasm.MemoryLimitPagesShared,
+		IsMaxEncoded: true,
+		IsShared:     true,
+	}, mem)
+}
+
 func TestDecodeMemoryType_Errors(t *testing.T) {
 	tests := []struct {
 		name        string
 		input       []byte
+		features    wasm.Features
 		expectedErr string
 	}{
 		{
@@ -87,13 +123,41 @@ func TestDecodeMemoryType_Errors(t *testing.T) {
 			input:       []byte{0x1, 0, 0xff, 0xff, 0xff, 0xff, 0xf},
 			expectedErr: "max 4294967295 pages (3 Ti) over limit of 65536 pages (4 Gi)",
 		},
+		{
+			name:        "shared without max",
+			input:       []byte{0x2, 0},
+			features:    wasm.FeatureThreads,
+			expectedErr: "shared memory must have max",
+		},
+		{
+			name:        "shared min > limit",
+			input:       []byte{0x3, 0x81, 0x80, 0x1, 0x80, 0x80, 0x4},
+			features:    wasm.FeatureThreads,
+			expectedErr: "shared memory min 16385 pages (1 Gi) over limit of 16384 pages (1 Gi)",
+		},
+		{
+			name:        "shared without threads",
+			input:       []byte{0x3, 0, 1},
+			expectedErr: `shared memory is invalid as feature "threads" is disabled`,
+		},
+		{
+			name:        "memory64 without memory64",
+			input:       []byte{0x4, 0},
+			expectedErr: `memory indexed with i64 is invalid as feature "memory64" is disabled`,
+		},
+		{
+			name:        "memory64 min > limit",
+			input:       []byte{0x4, 0x81, 0x80, 0x4},
+			features:    wasm.FeatureMemory64,
+			expectedErr: "min 65537 pages (4 Gi) over limit of 65536 pages (4 Gi)",
+		},
 	}
 
 	for _, tt := range tests {
 		tc := tt
 
 		t.Run(tc.name, func(t *testing.T) {
-			_, err := decodeMemory(bytes.NewReader(tc.input), wasm.MemorySizer)
+			_, err := decodeMemory(bytes.NewReader(tc.input), wasm.MemorySizer, tc.features)
 			require.EqualError(t, err, tc.expectedErr)
 		})
 	}
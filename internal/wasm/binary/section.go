@@ -83,16 +83,25 @@ func decodeTableSection(r *bytes.Reader, enabledFeatures wasm.Features) ([]*wasm
 func decodeMemorySection(
 	r *bytes.Reader,
 	memorySizer func(minPages uint32, maxPages *uint32) (min, capacity, max uint32),
-) (*wasm.Memory, error) {
+	enabledFeatures wasm.Features,
+) ([]*wasm.Memory, error) {
 	vs, _, err := leb128.DecodeUint32(r)
 	if err != nil {
 		return nil, fmt.Errorf("error reading size")
 	}
 	if vs > 1 {
-		return nil, fmt.Errorf("at most one memory allowed in module, but read %d", vs)
+		if !enabledFeatures.Get(wasm.FeatureMultiMemory) {
+			return nil, fmt.Errorf("at most one memory allowed in module, but read %d", vs)
+		}
 	}
 
-	return decodeMemory(r, memorySizer)
+	ret := make([]*wasm.Memory, vs)
+	for i := range ret {
+		if ret[i], err = decodeMemory(r, memorySizer, enabledFeatures); err != nil {
+			return nil, err
+		}
+	}
+	return ret, nil
 }
 
 func decodeGlobalSection(r *bytes.Reader, enabledFeatures wasm.Features) ([]*wasm.Global, error) {
@@ -110,6 +119,21 @@ func decodeGlobalSection(r *bytes.Reader, enabledFeatures wasm.Features) ([]*was
 	return result, nil
 }
 
+func decodeTagSection(r *bytes.Reader) ([]wasm.Index, error) {
+	vs, _, err := leb128.DecodeUint32(r)
+	if err != nil {
+		return nil, fmt.Errorf("get size of vector: %w", err)
+	}
+
+	result := make([]wasm.Index, vs)
+	for i := uint32(0); i < vs; i++ {
+		if result[i], err = decodeTag(r); err != nil {
+			return nil, fmt.Errorf("tag[%d]: %w", i, err)
+		}
+	}
+	return result, nil
+}
+
 func decodeExportSection(r *bytes.Reader) ([]*wasm.Export, error) {
 	vs, _, sizeErr := leb128.DecodeUint32(r)
 	if sizeErr != nil {
@@ -265,13 +289,16 @@ func encodeTableSection(tables []*wasm.Table) []byte {
 	return encodeSection(wasm.SectionIDTable, contents)
 }
 
-// encodeMemorySection encodes a wasm.SectionIDMemory for the module-defined function in WebAssembly 1.0
+// encodeMemorySection encodes a wasm.SectionIDMemory for the module-defined memories in WebAssembly 1.0
 // (20191205) Binary Format.
 //
 // See encodeMemory
 // See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#memory-section%E2%91%A0
-func encodeMemorySection(memory *wasm.Memory) []byte {
-	contents := append([]byte{1}, encodeMemory(memory)...)
+func encodeMemorySection(memories []*wasm.Memory) []byte {
+	contents := leb128.EncodeUint32(uint32(len(memories)))
+	for _, m := range memories {
+		contents = append(contents, encodeMemory(m)...)
+	}
 	return encodeSection(wasm.SectionIDMemory, contents)
 }
 
@@ -288,6 +315,19 @@ func encodeGlobalSection(globals []*wasm.Global) []byte {
 	return encodeSection(wasm.SectionIDGlobal, contents)
 }
 
+// encodeTagSection encodes a wasm.SectionIDTag for the given type indices in the exception handling proposal Binary
+// Format.
+//
+// See encodeTag
+// See https://github.com/WebAssembly/exception-handling/blob/main/proposals/exception-handling/Exceptions.md#tag-section
+func encodeTagSection(typeIndices []wasm.Index) []byte {
+	contents := leb128.EncodeUint32(uint32(len(typeIndices)))
+	for _, typeIdx := range typeIndices {
+		contents = append(contents, encodeTag(typeIdx)...)
+	}
+	return encodeSection(wasm.SectionIDTag, contents)
+}
+
 // encodeExportSection encodes a wasm.SectionIDExport for the given exports in WebAssembly 1.0 (20191205) Binary
 // Format.
 //
@@ -309,6 +349,13 @@ func encodeStartSection(funcidx wasm.Index) []byte {
 	return encodeSection(wasm.SectionIDStart, leb128.EncodeUint32(funcidx))
 }
 
+// encodeDataCountSection encodes a wasm.SectionIDDataCount for the given count in WebAssembly 2.0 Binary Format.
+//
+// See https://www.w3.org/TR/2022/WD-wasm-core-2-20220419/binary/modules.html#data-count-section
+func encodeDataCountSection(count uint32) []byte {
+	return encodeSection(wasm.SectionIDDataCount, leb128.EncodeUint32(count))
+}
+
 // encodeEelementSection encodes a wasm.SectionIDElement for the elements in WebAssembly 1.0 (20191205)
 // Binary Format.
 //
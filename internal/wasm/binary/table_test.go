@@ -90,6 +90,18 @@ func TestDecodeTableType_Errors(t *testing.T) {
 			expectedErr: "table min must be at most 134217728",
 			features:    wasm.FeatureReferenceTypes,
 		},
+		{
+			name:        "shared",
+			input:       []byte{wasm.RefTypeFuncref, 0x3, 0, 1},
+			expectedErr: "tables cannot be shared",
+			features:    wasm.FeatureThreads,
+		},
+		{
+			name:        "i64",
+			input:       []byte{wasm.RefTypeFuncref, 0x5, 0, 1},
+			expectedErr: "tables cannot be indexed with i64",
+			features:    wasm.FeatureMemory64,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,39 @@
+package binary
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tetratelabs/wazero/internal/leb128"
+	"github.com/tetratelabs/wazero/internal/wasm"
+)
+
+// tagAttributeException is the only tag attribute defined by the exception handling proposal.
+const tagAttributeException = 0x00
+
+// decodeTag returns the wasm.SectionIDType index of a tag decoded with the exception handling proposal Binary Format.
+//
+// See https://github.com/WebAssembly/exception-handling/blob/main/proposals/exception-handling/Exceptions.md#tag-section
+func decodeTag(r *bytes.Reader) (wasm.Index, error) {
+	b, err := r.ReadByte()
+	if err != nil {
+		return 0, fmt.Errorf("read attribute: %w", err)
+	}
+	if b != tagAttributeException {
+		return 0, fmt.Errorf("%w: invalid tag attribute: %#x", ErrInvalidByte, b)
+	}
+
+	typeIdx, _, err := leb128.DecodeUint32(r)
+	if err != nil {
+		return 0, fmt.Errorf("read type index: %w", err)
+	}
+	return typeIdx, nil
+}
+
+// encodeTag returns the tag of the given wasm.SectionIDType index encoded in the exception handling proposal Binary
+// Format.
+//
+// See https://github.com/WebAssembly/exception-handling/blob/main/proposals/exception-handling/Exceptions.md#tag-section
+func encodeTag(typeIdx wasm.Index) []byte {
+	return append([]byte{tagAttributeException}, leb128.EncodeUint32(typeIdx)...)
+}
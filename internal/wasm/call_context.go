@@ -105,7 +105,7 @@ func (m *CallContext) close(ctx context.Context, exitCode uint32) (c bool, err e
 
 // Memory implements the same method as documented on api.Module.
 func (m *CallContext) Memory() api.Memory {
-	return m.module.Memory
+	return m.memory
 }
 
 // ExportedMemory implements the same method as documented on api.Module.
@@ -175,6 +175,15 @@ func (f *FunctionInstance) Call(ctx context.Context, params ...uint64) (ret []ui
 	return
 }
 
+// ExportedTable implements the same method as documented on api.Module.
+func (m *CallContext) ExportedTable(name string) api.Table {
+	exp, err := m.module.getExport(name, ExternTypeTable)
+	if err != nil {
+		return nil
+	}
+	return &exportedTable{table: exp.Table, engine: m.module.Engine}
+}
+
 // ExportedGlobal implements the same method as documented on api.Module.
 func (m *CallContext) ExportedGlobal(name string) api.Global {
 	exp, err := m.module.getExport(name, ExternTypeGlobal)
@@ -198,3 +207,12 @@ func (m *CallContext) ExportedGlobal(name string) api.Global {
 		panic(fmt.Errorf("BUG: unknown value type %X", valType))
 	}
 }
+
+// ExportedTag implements the same method as documented on api.Module.
+func (m *CallContext) ExportedTag(name string) api.Tag {
+	exp, err := m.module.getExport(name, ExternTypeTag)
+	if err != nil {
+		return nil
+	}
+	return exp.Tag
+}
@@ -17,7 +17,7 @@ func (m *Module) ImportTableCount() uint32 {
 // ImportMemoryCount returns the possibly empty count of imported memories. This plus SectionElementCount of
 // SectionIDMemory is the size of the memory index namespace.
 func (m *Module) ImportMemoryCount() uint32 {
-	return m.importCount(ExternTypeMemory) // TODO: once validation happens on decode, this is zero or one.
+	return m.importCount(ExternTypeMemory)
 }
 
 // ImportGlobalCount returns the possibly empty count of imported globals. This plus SectionElementCount of
@@ -26,6 +26,12 @@ func (m *Module) ImportGlobalCount() uint32 {
 	return m.importCount(ExternTypeGlobal)
 }
 
+// ImportTagCount returns the possibly empty count of imported tags. This plus SectionElementCount of SectionIDTag is
+// the size of the tag index namespace.
+func (m *Module) ImportTagCount() uint32 {
+	return m.importCount(ExternTypeTag)
+}
+
 // importCount returns the count of a specific type of import. This is important because it is easy to mistake the
 // length of the import section with the count of a specific kind of import.
 func (m *Module) importCount(et ExternType) (res uint32) {
@@ -60,10 +66,7 @@ func (m *Module) SectionElementCount(sectionID SectionID) uint32 { // element as
 	case SectionIDTable:
 		return uint32(len(m.TableSection))
 	case SectionIDMemory:
-		if m.MemorySection != nil {
-			return 1
-		}
-		return 0
+		return uint32(len(m.MemorySection))
 	case SectionIDGlobal:
 		return uint32(len(m.GlobalSection))
 	case SectionIDExport:
@@ -81,6 +84,8 @@ func (m *Module) SectionElementCount(sectionID SectionID) uint32 { // element as
 		return uint32(len(m.DataSection))
 	case SectionIDHostFunction:
 		return uint32(len(m.HostFunctionSection))
+	case SectionIDTag:
+		return uint32(len(m.TagSection))
 	default:
 		panic(fmt.Errorf("BUG: unknown section: %d", sectionID))
 	}
@@ -3,6 +3,10 @@ package wasm
 import (
 	"context"
 	"errors"
+	"math"
+
+	"github.com/tetratelabs/wazero/experimental"
+	"github.com/tetratelabs/wazero/internal/buildoptions"
 )
 
 // Engine is a Store-scoped mechanism to compile functions declared or imported by a module.
@@ -41,6 +45,9 @@ type ModuleEngine interface {
 	// Name returns the name of the module this engine was compiled for.
 	Name() string
 
+	// Engine returns the Engine which created this. A Reference is only valid in ModuleEngines of the same Engine.
+	Engine() Engine
+
 	// Call invokes a function instance f with given parameters.
 	Call(ctx context.Context, m *CallContext, f *FunctionInstance, params ...uint64) (results []uint64, err error)
 
@@ -50,6 +57,45 @@ type ModuleEngine interface {
 
 	// InitializeFuncrefGlobals initializes the globals of Funcref type as the opaque pointer values of engine specific compiled functions.
 	InitializeFuncrefGlobals(globals []*GlobalInstance)
+
+	// FunctionInstanceReference returns the Reference of the function at the given index in the function index
+	// namespace, which is the same engine-specific function pointer as in CreateFuncElementInstance.
+	FunctionInstanceReference(funcIndex Index) Reference
+
+	// LookupFunction returns the FunctionInstance of the given non-null Reference, which must come from a ModuleEngine
+	// of the same Engine, Ex. a TableInstance element.
+	LookupFunction(ref Reference) *FunctionInstance
+}
+
+// StackLimits are the limits an Engine enforces on the stacks of each ModuleEngine.Call, so that a guest raises an error
+// instead of exhausting the memory of the host.
+type StackLimits struct {
+	// CallStackCeiling is the maximum number of function frames, including the ones of host functions. Exceeding it
+	// raises wasmruntime.ErrRuntimeCallStackOverflow.
+	CallStackCeiling int
+
+	// ValueStackCeiling is the maximum number of uint64 values on the stack holding the parameters, locals and
+	// operands of all frames. Exceeding it raises wasmruntime.ErrRuntimeValueStackOverflow.
+	ValueStackCeiling int
+}
+
+// DefaultStackLimits are the StackLimits used unless configured otherwise.
+var DefaultStackLimits = StackLimits{
+	CallStackCeiling:  buildoptions.CallStackCeiling,
+	ValueStackCeiling: buildoptions.ValueStackCeiling / 8,
+}
+
+// FuelOf returns the experimental.Fuel which calls with the given context consume, or an unlimited one if it has none.
+//
+// Note: This allocates the unlimited one, so engines only call this for functions of a Module with FuelCosts.
+func FuelOf(ctx context.Context) *experimental.Fuel {
+	if ctx != nil {
+		if fuel, ok := ctx.Value(experimental.FuelKey{}).(*experimental.Fuel); ok && fuel != nil {
+			return fuel
+		}
+	}
+	unlimited := experimental.Fuel(math.MaxInt64)
+	return &unlimited
 }
 
 // TableInitEntry is normalized element segment used for initializing tables by engines.
@@ -102,6 +102,52 @@ const (
 	//
 	// See https://github.com/WebAssembly/spec/blob/main/proposals/simd/SIMD.md
 	FeatureSIMD
+
+	// FeatureThreads enables shared memories and the atomic instructions prefixed by OpcodeAtomicPrefix.
+	//
+	// See https://github.com/WebAssembly/threads/blob/main/proposals/threads/Overview.md
+	FeatureThreads
+
+	// FeatureTailCall decides if parsing should succeed on the following instructions:
+	//
+	// * OpcodeReturnCall
+	// * OpcodeReturnCallIndirect
+	//
+	// See https://github.com/WebAssembly/tail-call/blob/main/proposals/tail-call/Overview.md
+	FeatureTailCall
+
+	// FeatureExceptionHandling decides if parsing should succeed on SectionIDTag, ExternTypeTag and the following
+	// instructions:
+	//
+	// * OpcodeTry
+	// * OpcodeCatch
+	// * OpcodeCatchAll
+	// * OpcodeDelegate
+	// * OpcodeThrow
+	// * OpcodeRethrow
+	//
+	// See https://github.com/WebAssembly/exception-handling/blob/main/proposals/exception-handling/Exceptions.md
+	FeatureExceptionHandling
+
+	// FeatureMultiMemory decides if a module can define or import more than one memory, and if memory instructions can
+	// access them by non-zero memory index. For example, the memarg of load and store instructions can be followed by
+	// the memory index, and the reserved bytes of OpcodeMemorySize and OpcodeMemoryGrow are memory indexes instead.
+	//
+	// See https://github.com/WebAssembly/multi-memory/blob/main/proposals/multi-memory/Overview.md
+	FeatureMultiMemory
+
+	// FeatureMemory64 decides if a memory can be indexed with i64 instead of i32. For example, the address operands of
+	// load and store instructions of such a memory are i64, and so are the offsets of their memarg.
+	//
+	// Note: Memories indexed with i64 are still limited to MemoryLimitPages in wazero.
+	// See https://github.com/WebAssembly/memory64/blob/main/proposals/memory64/Overview.md
+	FeatureMemory64
+
+	// FeatureExtendedConst decides if constant expressions, such as global initializers and segment offsets, can use
+	// the i32 and i64 add, sub and mul instructions in addition to a single constant or global.get.
+	//
+	// See https://github.com/WebAssembly/extended-const/blob/main/proposals/extended-const/Overview.md
+	FeatureExtendedConst
 )
 
 // Set assigns the value for the given feature.
@@ -165,6 +211,24 @@ func featureName(f Features) string {
 	case FeatureSIMD:
 		// match https://github.com/WebAssembly/spec/blob/main/proposals/simd/SIMD.md
 		return "simd"
+	case FeatureThreads:
+		// match https://github.com/WebAssembly/threads/blob/main/proposals/threads/Overview.md
+		return "threads"
+	case FeatureTailCall:
+		// match https://github.com/WebAssembly/tail-call/blob/main/proposals/tail-call/Overview.md
+		return "tail-call"
+	case FeatureExceptionHandling:
+		// match https://github.com/WebAssembly/exception-handling/blob/main/proposals/exception-handling/Exceptions.md
+		return "exception-handling"
+	case FeatureMultiMemory:
+		// match https://github.com/WebAssembly/multi-memory/blob/main/proposals/multi-memory/Overview.md
+		return "multi-memory"
+	case FeatureMemory64:
+		// match https://github.com/WebAssembly/memory64/blob/main/proposals/memory64/Overview.md
+		return "memory64"
+	case FeatureExtendedConst:
+		// match https://github.com/WebAssembly/extended-const/blob/main/proposals/extended-const/Overview.md
+		return "extended-const"
 	}
 	return ""
 }
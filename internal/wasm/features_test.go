@@ -61,6 +61,12 @@ func TestFeatures_String(t *testing.T) {
 		{name: "sign-extension-ops", feature: FeatureSignExtensionOps, expected: "sign-extension-ops"},
 		{name: "multi-value", feature: FeatureMultiValue, expected: "multi-value"},
 		{name: "simd", feature: FeatureSIMD, expected: "simd"},
+		{name: "threads", feature: FeatureThreads, expected: "threads"},
+		{name: "tail-call", feature: FeatureTailCall, expected: "tail-call"},
+		{name: "exception-handling", feature: FeatureExceptionHandling, expected: "exception-handling"},
+		{name: "multi-memory", feature: FeatureMultiMemory, expected: "multi-memory"},
+		{name: "memory64", feature: FeatureMemory64, expected: "memory64"},
+		{name: "extended-const", feature: FeatureExtendedConst, expected: "extended-const"},
 		{name: "features", feature: FeatureMutableGlobal | FeatureMultiValue, expected: "multi-value|mutable-global"},
 		{name: "undefined", feature: 1 << 63, expected: ""},
 		{name: "2.0", feature: Features20220419,
@@ -20,15 +20,15 @@ const maximumValuesOnStack = 1 << 27
 // * idx is the index in the FunctionSection
 // * functions are the function index namespace, which is prefixed by imports. The value is the TypeSection index.
 // * globals are the global index namespace, which is prefixed by imports.
-// * memory is the potentially imported memory and can be nil.
+// * memories are the memory index namespace, which is prefixed by imports.
 // * table is the potentially imported table and can be nil.
 // * declaredFunctionIndexes is the set of function indexes declared by declarative element segments which can be acceed by OpcodeRefFunc instruction.
 //
 // Returns an error if the instruction sequence is not valid,
 // or potentially it can exceed the maximum number of values on the stack.
 func (m *Module) validateFunction(enabledFeatures Features, idx Index, functions []Index,
-	globals []*GlobalType, memory *Memory, tables []*Table, declaredFunctionIndexes map[Index]struct{}) error {
-	return m.validateFunctionWithMaxStackValues(enabledFeatures, idx, functions, globals, memory, tables, maximumValuesOnStack, declaredFunctionIndexes)
+	globals []*GlobalType, memories []*Memory, tables []*Table, declaredFunctionIndexes map[Index]struct{}) error {
+	return m.validateFunctionWithMaxStackValues(enabledFeatures, idx, functions, globals, memories, tables, maximumValuesOnStack, declaredFunctionIndexes)
 }
 
 // validateFunctionWithMaxStackValues is like validateFunction, but allows overriding maxStackValues for testing.
@@ -39,7 +39,7 @@ func (m *Module) validateFunctionWithMaxStackValues(
 	idx Index,
 	functions []Index,
 	globals []*GlobalType,
-	memory *Memory,
+	memories []*Memory,
 	tables []*Table,
 	maxStackValues int,
 	declaredFunctionIndexes map[Index]struct{},
@@ -59,20 +59,22 @@ func (m *Module) validateFunctionWithMaxStackValues(
 	for pc := uint64(0); pc < uint64(len(body)); pc++ {
 		op := body[pc]
 		if OpcodeI32Load <= op && op <= OpcodeI64Store32 {
-			if memory == nil {
-				return fmt.Errorf("unknown memory access")
-			}
 			pc++
-			align, num, err := leb128.DecodeUint32(bytes.NewReader(body[pc:]))
+			align, memoryIndex, num, err := DecodeMemArgAlignment(bytes.NewReader(body[pc:]), enabledFeatures)
 			if err != nil {
 				return fmt.Errorf("read memory align: %v", err)
 			}
+			if memoryIndex >= uint32(len(memories)) {
+				return fmt.Errorf("unknown memory access")
+			}
+			// The address is i64 instead of i32 when the memory is indexed with i64.
+			indexType := memories[memoryIndex].IndexType()
 			switch op {
 			case OpcodeI32Load:
 				if 1<<align > 32/8 {
 					return fmt.Errorf("invalid memory alignment")
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 				valueTypeStack.push(ValueTypeI32)
@@ -80,7 +82,7 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if 1<<align > 32/8 {
 					return fmt.Errorf("invalid memory alignment")
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 				valueTypeStack.push(ValueTypeF32)
@@ -91,7 +93,7 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
 					return err
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 			case OpcodeF32Store:
@@ -101,14 +103,14 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if err := valueTypeStack.popAndVerifyType(ValueTypeF32); err != nil {
 					return err
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 			case OpcodeI64Load:
 				if 1<<align > 64/8 {
 					return fmt.Errorf("invalid memory alignment")
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 				valueTypeStack.push(ValueTypeI64)
@@ -116,7 +118,7 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if 1<<align > 64/8 {
 					return fmt.Errorf("invalid memory alignment")
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 				valueTypeStack.push(ValueTypeF64)
@@ -127,7 +129,7 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if err := valueTypeStack.popAndVerifyType(ValueTypeI64); err != nil {
 					return err
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 			case OpcodeF64Store:
@@ -137,14 +139,14 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if err := valueTypeStack.popAndVerifyType(ValueTypeF64); err != nil {
 					return err
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 			case OpcodeI32Load8S:
 				if 1<<align > 1 {
 					return fmt.Errorf("invalid memory alignment")
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 				valueTypeStack.push(ValueTypeI32)
@@ -152,7 +154,7 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if 1<<align > 1 {
 					return fmt.Errorf("invalid memory alignment")
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 				valueTypeStack.push(ValueTypeI32)
@@ -160,7 +162,7 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if 1<<align > 1 {
 					return fmt.Errorf("invalid memory alignment")
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 				valueTypeStack.push(ValueTypeI64)
@@ -171,7 +173,7 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
 					return err
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 			case OpcodeI64Store8:
@@ -181,14 +183,14 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if err := valueTypeStack.popAndVerifyType(ValueTypeI64); err != nil {
 					return err
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 			case OpcodeI32Load16S, OpcodeI32Load16U:
 				if 1<<align > 16/8 {
 					return fmt.Errorf("invalid memory alignment")
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 				valueTypeStack.push(ValueTypeI32)
@@ -196,7 +198,7 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if 1<<align > 16/8 {
 					return fmt.Errorf("invalid memory alignment")
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 				valueTypeStack.push(ValueTypeI64)
@@ -207,7 +209,7 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
 					return err
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 			case OpcodeI64Store16:
@@ -217,14 +219,14 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if err := valueTypeStack.popAndVerifyType(ValueTypeI64); err != nil {
 					return err
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 			case OpcodeI64Load32S, OpcodeI64Load32U:
 				if 1<<align > 32/8 {
 					return fmt.Errorf("invalid memory alignment")
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 				valueTypeStack.push(ValueTypeI64)
@@ -235,37 +237,39 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				if err := valueTypeStack.popAndVerifyType(ValueTypeI64); err != nil {
 					return err
 				}
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
 			}
 			pc += num
 			// offset
-			_, num, err = leb128.DecodeUint32(bytes.NewReader(body[pc:]))
+			_, num, err = DecodeMemArgOffset(bytes.NewReader(body[pc:]), memories[memoryIndex].IsMemory64)
 			if err != nil {
 				return fmt.Errorf("read memory offset: %v", err)
 			}
 			pc += num - 1
 		} else if OpcodeMemorySize <= op && op <= OpcodeMemoryGrow {
-			if memory == nil {
-				return fmt.Errorf("unknown memory access")
-			}
 			pc++
 			val, num, err := leb128.DecodeUint32(bytes.NewReader(body[pc:]))
 			if err != nil {
 				return fmt.Errorf("read immediate: %v", err)
 			}
-			if val != 0 || num != 1 {
+			if !enabledFeatures.Get(FeatureMultiMemory) && (val != 0 || num != 1) {
 				return fmt.Errorf("memory instruction reserved bytes not zero with 1 byte")
 			}
+			if val >= uint32(len(memories)) {
+				return fmt.Errorf("unknown memory access")
+			}
+			// The number of pages is i64 instead of i32 when the memory is indexed with i64.
+			indexType := memories[val].IndexType()
 			switch Opcode(op) {
 			case OpcodeMemoryGrow:
-				if err := valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
+				if err := valueTypeStack.popAndVerifyType(indexType); err != nil {
 					return err
 				}
-				valueTypeStack.push(ValueTypeI32)
+				valueTypeStack.push(indexType)
 			case OpcodeMemorySize:
-				valueTypeStack.push(ValueTypeI32)
+				valueTypeStack.push(indexType)
 			}
 			pc += num - 1
 		} else if OpcodeI32Const <= op && op <= OpcodeF64Const {
@@ -493,7 +497,12 @@ func (m *Module) validateFunctionWithMaxStackValues(
 
 			// br_table instruction is stack-polymorphic.
 			valueTypeStack.unreachable()
-		} else if op == OpcodeCall {
+		} else if op == OpcodeCall || op == OpcodeReturnCall {
+			if op == OpcodeReturnCall {
+				if err := enabledFeatures.Require(FeatureTailCall); err != nil {
+					return fmt.Errorf("%s invalid as %v", OpcodeReturnCallName, err)
+				}
+			}
 			pc++
 			index, num, err := leb128.DecodeUint32(bytes.NewReader(body[pc:]))
 			if err != nil {
@@ -506,13 +515,27 @@ func (m *Module) validateFunctionWithMaxStackValues(
 			funcType := types[functions[index]]
 			for i := 0; i < len(funcType.Params); i++ {
 				if err := valueTypeStack.popAndVerifyType(funcType.Params[len(funcType.Params)-1-i]); err != nil {
-					return fmt.Errorf("type mismatch on %s operation param type: %v", OpcodeCallName, err)
+					return fmt.Errorf("type mismatch on %s operation param type: %v", InstructionName(op), err)
+				}
+			}
+			if op == OpcodeReturnCall {
+				if err := verifyTailCallResults(funcType, functionType); err != nil {
+					return fmt.Errorf("type mismatch on %s operation result type: %v", OpcodeReturnCallName, err)
 				}
+				// return_call instruction is stack-polymorphic.
+				valueTypeStack.unreachable()
+				continue
 			}
 			for _, exp := range funcType.Results {
 				valueTypeStack.push(exp)
 			}
-		} else if op == OpcodeCallIndirect {
+		} else if op == OpcodeCallIndirect || op == OpcodeReturnCallIndirect {
+			if op == OpcodeReturnCallIndirect {
+				if err := enabledFeatures.Require(FeatureTailCall); err != nil {
+					return fmt.Errorf("%s invalid as %v", OpcodeReturnCallIndirectName, err)
+				}
+			}
+			opName := InstructionName(op)
 			pc++
 			typeIndex, num, err := leb128.DecodeUint32(bytes.NewReader(body[pc:]))
 			if err != nil {
@@ -521,7 +544,7 @@ func (m *Module) validateFunctionWithMaxStackValues(
 			pc += num
 
 			if int(typeIndex) >= len(types) {
-				return fmt.Errorf("invalid type index at %s: %d", OpcodeCallIndirectName, typeIndex)
+				return fmt.Errorf("invalid type index at %s: %d", opName, typeIndex)
 			}
 
 			tableIndex, num, err := leb128.DecodeUint32(bytes.NewReader(body[pc:]))
@@ -541,19 +564,27 @@ func (m *Module) validateFunctionWithMaxStackValues(
 
 			table := tables[tableIndex]
 			if table == nil {
-				return fmt.Errorf("table not given while having %s", OpcodeCallIndirectName)
+				return fmt.Errorf("table not given while having %s", opName)
 			} else if table.Type != RefTypeFuncref {
-				return fmt.Errorf("table is not funcref type but was %s for %s", RefTypeName(table.Type), OpcodeCallIndirectName)
+				return fmt.Errorf("table is not funcref type but was %s for %s", RefTypeName(table.Type), opName)
 			}
 
 			if err = valueTypeStack.popAndVerifyType(ValueTypeI32); err != nil {
-				return fmt.Errorf("cannot pop the offset in table for %s", OpcodeCallIndirectName)
+				return fmt.Errorf("cannot pop the offset in table for %s", opName)
 			}
 			funcType := types[typeIndex]
 			for i := 0; i < len(funcType.Params); i++ {
 				if err = valueTypeStack.popAndVerifyType(funcType.Params[len(funcType.Params)-1-i]); err != nil {
-					return fmt.Errorf("type mismatch on %s operation input type", OpcodeCallIndirectName)
+					return fmt.Errorf("type mismatch on %s operation input type", opName)
+				}
+			}
+			if op == OpcodeReturnCallIndirect {
+				if err := verifyTailCallResults(funcType, functionType); err != nil {
+					return fmt.Errorf("type mismatch on %s operation result type: %v", opName, err)
 				}
+				// return_call_indirect instruction is stack-polymorphic.
+				valueTypeStack.unreachable()
+				continue
 			}
 			for _, exp := range funcType.Results {
 				valueTypeStack.push(exp)
@@ -881,11 +912,9 @@ func (m *Module) validateFunctionWithMaxStackValues(
 					}
 					pc += num - 1
 				case OpcodeMiscMemoryInit, OpcodeMiscMemoryCopy, OpcodeMiscMemoryFill:
-					if memory == nil {
+					if len(memories) == 0 {
 						return fmt.Errorf("memory must exist for %s", MiscInstructionName(miscOpcode))
 					}
-					params = []ValueType{ValueTypeI32, ValueTypeI32, ValueTypeI32}
-
 					if miscOpcode == OpcodeMiscMemoryInit {
 						if m.DataCountSection == nil {
 							return fmt.Errorf("%s requires data count section", MiscInstructionName(miscOpcode))
@@ -903,24 +932,43 @@ func (m *Module) validateFunctionWithMaxStackValues(
 						pc += num - 1
 					}
 
-					pc++
-					val, num, err := leb128.DecodeUint32(bytes.NewReader(body[pc:]))
-					if err != nil {
-						return fmt.Errorf("failed to read memory index for %s: %v", MiscInstructionName(miscOpcode), err)
-					}
-					if val != 0 || num != 1 {
-						return fmt.Errorf("%s reserved byte must be zero encoded with 1 byte", MiscInstructionName(miscOpcode))
-					}
+					// memory.copy needs two memory indexes, which are reserved as zero unless FeatureMultiMemory is enabled.
+					memoryIndexCount := 1
 					if miscOpcode == OpcodeMiscMemoryCopy {
+						memoryIndexCount = 2
+					}
+					var memoryIndexes [2]uint32
+					for i := 0; i < memoryIndexCount; i++ {
 						pc++
-						// memory.copy needs two memory index which are reserved as zero.
 						val, num, err := leb128.DecodeUint32(bytes.NewReader(body[pc:]))
 						if err != nil {
 							return fmt.Errorf("failed to read memory index for %s: %v", MiscInstructionName(miscOpcode), err)
 						}
-						if val != 0 || num != 1 {
+						if !enabledFeatures.Get(FeatureMultiMemory) && (val != 0 || num != 1) {
 							return fmt.Errorf("%s reserved byte must be zero encoded with 1 byte", MiscInstructionName(miscOpcode))
 						}
+						if val >= uint32(len(memories)) {
+							return fmt.Errorf("unknown memory %d for %s", val, MiscInstructionName(miscOpcode))
+						}
+						memoryIndexes[i] = val
+						pc += num - 1
+					}
+
+					// The operands are (destination, source or value, size), and addresses and sizes into a memory are
+					// i64 instead of i32 when it is indexed with i64.
+					dstType := memories[memoryIndexes[0]].IndexType()
+					switch miscOpcode {
+					case OpcodeMiscMemoryInit:
+						params = []ValueType{dstType, ValueTypeI32, ValueTypeI32}
+					case OpcodeMiscMemoryCopy:
+						srcType := memories[memoryIndexes[1]].IndexType()
+						sizeType := ValueTypeI32
+						if dstType == ValueTypeI64 && srcType == ValueTypeI64 {
+							sizeType = ValueTypeI64
+						}
+						params = []ValueType{dstType, srcType, sizeType}
+					case OpcodeMiscMemoryFill:
+						params = []ValueType{dstType, ValueTypeI32, dstType}
 					}
 
 				case OpcodeMiscTableInit:
@@ -1002,8 +1050,8 @@ func (m *Module) validateFunctionWithMaxStackValues(
 
 					pc += num - 1
 				}
-				for _, p := range params {
-					if err := valueTypeStack.popAndVerifyType(p); err != nil {
+				for i := len(params) - 1; i >= 0; i-- {
+					if err := valueTypeStack.popAndVerifyType(params[i]); err != nil {
 						return fmt.Errorf("cannot pop the operand for %s: %v", miscInstructionNames[miscOpcode], err)
 					}
 				}
@@ -1045,38 +1093,289 @@ func (m *Module) validateFunctionWithMaxStackValues(
 		} else if op == OpcodeVecPrefix {
 			pc++
 			// Vector instructions come with two bytes where the first byte is always OpcodeVecPrefix,
-			// and the second byte determines the actual instruction.
-			vecOpcode := body[pc]
+			// and the second is the actual instruction encoded as LEB128 uint32.
+			vecOpcode32, num, err := leb128.DecodeUint32(bytes.NewReader(body[pc:]))
+			if err != nil {
+				return fmt.Errorf("read vector instruction: %v", err)
+			}
+			vecOpcode, ok := OpcodeVec(vecOpcode32), vecOpcode32 <= 0xff
+			if !ok || vectorInstructionName[vecOpcode] == "" {
+				return fmt.Errorf("invalid vector instruction: %#x", vecOpcode32)
+			}
+			pc += num - 1
+			vecName := vectorInstructionName[vecOpcode]
 			if err := enabledFeatures.Require(FeatureSIMD); err != nil {
-				return fmt.Errorf("%s invalid as %v", vectorInstructionName[vecOpcode], err)
+				return fmt.Errorf("%s invalid as %v", vecName, err)
 			}
 
+			// maxAlign is the natural alignment of memory instructions in bytes, and
+			// laneCount is the number of lanes for instructions with a lane index immediate.
+			var maxAlign, laneCount byte
+			var params, results []ValueType
 			switch vecOpcode {
 			case OpcodeVecV128Const:
 				// Read 128-bit = 16 bytes constants
 				if int(pc+16) >= len(body) {
-					return fmt.Errorf("cannot read constant vector value for %s", vectorInstructionName[vecOpcode])
+					return fmt.Errorf("cannot read constant vector value for %s", vecName)
 				}
 				pc += 16
-				valueTypeStack.push(ValueTypeV128)
-			case OpcodeVecI32x4Add:
-				for i := 0; i < 2; i++ {
-					if err := valueTypeStack.popAndVerifyType(ValueTypeV128); err != nil {
-						return fmt.Errorf("cannot pop the operand for %s: %v", OpcodeVecI32x4AddName, err)
-					}
+				results = []ValueType{ValueTypeV128}
+			case OpcodeVecV128i8x16Shuffle:
+				// Read 16 lane indexes, each of which must be less than 32.
+				if int(pc+16) >= len(body) {
+					return fmt.Errorf("cannot read lane indexes for %s", vecName)
 				}
-				valueTypeStack.push(ValueTypeV128)
-			case OpcodeVecI64x2Add:
-				for i := 0; i < 2; i++ {
-					if err := valueTypeStack.popAndVerifyType(ValueTypeV128); err != nil {
-						return fmt.Errorf("cannot pop the operand for %s: %v", OpcodeVecI64x2AddName, err)
+				for _, l := range body[pc+1 : pc+17] {
+					if l >= 32 {
+						return fmt.Errorf("invalid lane index %d >= 32 for %s", l, vecName)
 					}
 				}
-				valueTypeStack.push(ValueTypeV128)
+				pc += 16
+				params = []ValueType{ValueTypeV128, ValueTypeV128}
+				results = []ValueType{ValueTypeV128}
+			case OpcodeVecV128Load:
+				maxAlign = 16
+				params, results = []ValueType{ValueTypeI32}, []ValueType{ValueTypeV128}
+			case OpcodeVecV128Load8x8S, OpcodeVecV128Load8x8U, OpcodeVecV128Load16x4S, OpcodeVecV128Load16x4U,
+				OpcodeVecV128Load32x2S, OpcodeVecV128Load32x2U, OpcodeVecV128Load64Splat, OpcodeVecV128Load64zero:
+				maxAlign = 8
+				params, results = []ValueType{ValueTypeI32}, []ValueType{ValueTypeV128}
+			case OpcodeVecV128Load8Splat:
+				maxAlign = 1
+				params, results = []ValueType{ValueTypeI32}, []ValueType{ValueTypeV128}
+			case OpcodeVecV128Load16Splat:
+				maxAlign = 2
+				params, results = []ValueType{ValueTypeI32}, []ValueType{ValueTypeV128}
+			case OpcodeVecV128Load32Splat, OpcodeVecV128Load32zero:
+				maxAlign = 4
+				params, results = []ValueType{ValueTypeI32}, []ValueType{ValueTypeV128}
+			case OpcodeVecV128Store:
+				maxAlign = 16
+				params = []ValueType{ValueTypeI32, ValueTypeV128}
+			case OpcodeVecV128Load8Lane, OpcodeVecV128Load16Lane, OpcodeVecV128Load32Lane, OpcodeVecV128Load64Lane:
+				maxAlign = 1 << (vecOpcode - OpcodeVecV128Load8Lane)
+				laneCount = 16 / maxAlign
+				params, results = []ValueType{ValueTypeI32, ValueTypeV128}, []ValueType{ValueTypeV128}
+			case OpcodeVecV128Store8Lane, OpcodeVecV128Store16Lane, OpcodeVecV128Store32Lane, OpcodeVecV128Store64Lane:
+				maxAlign = 1 << (vecOpcode - OpcodeVecV128Store8Lane)
+				laneCount = 16 / maxAlign
+				params = []ValueType{ValueTypeI32, ValueTypeV128}
+			case OpcodeVecI8x16ExtractLaneS, OpcodeVecI8x16ExtractLaneU:
+				laneCount = 16
+				params, results = []ValueType{ValueTypeV128}, []ValueType{ValueTypeI32}
+			case OpcodeVecI16x8ExtractLaneS, OpcodeVecI16x8ExtractLaneU:
+				laneCount = 8
+				params, results = []ValueType{ValueTypeV128}, []ValueType{ValueTypeI32}
+			case OpcodeVecI32x4ExtractLane:
+				laneCount = 4
+				params, results = []ValueType{ValueTypeV128}, []ValueType{ValueTypeI32}
+			case OpcodeVecI64x2ExtractLane:
+				laneCount = 2
+				params, results = []ValueType{ValueTypeV128}, []ValueType{ValueTypeI64}
+			case OpcodeVecF32x4ExtractLane:
+				laneCount = 4
+				params, results = []ValueType{ValueTypeV128}, []ValueType{ValueTypeF32}
+			case OpcodeVecF64x2ExtractLane:
+				laneCount = 2
+				params, results = []ValueType{ValueTypeV128}, []ValueType{ValueTypeF64}
+			case OpcodeVecI8x16ReplaceLane:
+				laneCount = 16
+				params, results = []ValueType{ValueTypeV128, ValueTypeI32}, []ValueType{ValueTypeV128}
+			case OpcodeVecI16x8ReplaceLane:
+				laneCount = 8
+				params, results = []ValueType{ValueTypeV128, ValueTypeI32}, []ValueType{ValueTypeV128}
+			case OpcodeVecI32x4ReplaceLane:
+				laneCount = 4
+				params, results = []ValueType{ValueTypeV128, ValueTypeI32}, []ValueType{ValueTypeV128}
+			case OpcodeVecI64x2ReplaceLane:
+				laneCount = 2
+				params, results = []ValueType{ValueTypeV128, ValueTypeI64}, []ValueType{ValueTypeV128}
+			case OpcodeVecF32x4ReplaceLane:
+				laneCount = 4
+				params, results = []ValueType{ValueTypeV128, ValueTypeF32}, []ValueType{ValueTypeV128}
+			case OpcodeVecF64x2ReplaceLane:
+				laneCount = 2
+				params, results = []ValueType{ValueTypeV128, ValueTypeF64}, []ValueType{ValueTypeV128}
+			case OpcodeVecI8x16Splat, OpcodeVecI16x8Splat, OpcodeVecI32x4Splat:
+				params, results = []ValueType{ValueTypeI32}, []ValueType{ValueTypeV128}
+			case OpcodeVecI64x2Splat:
+				params, results = []ValueType{ValueTypeI64}, []ValueType{ValueTypeV128}
+			case OpcodeVecF32x4Splat:
+				params, results = []ValueType{ValueTypeF32}, []ValueType{ValueTypeV128}
+			case OpcodeVecF64x2Splat:
+				params, results = []ValueType{ValueTypeF64}, []ValueType{ValueTypeV128}
+			case OpcodeVecV128AnyTrue, OpcodeVecI8x16AllTrue, OpcodeVecI16x8AllTrue, OpcodeVecI32x4AllTrue, OpcodeVecI64x2AllTrue,
+				OpcodeVecI8x16BitMask, OpcodeVecI16x8BitMask, OpcodeVecI32x4BitMask, OpcodeVecI64x2BitMask:
+				params, results = []ValueType{ValueTypeV128}, []ValueType{ValueTypeI32}
+			case OpcodeVecI8x16Shl, OpcodeVecI8x16ShrS, OpcodeVecI8x16ShrU, OpcodeVecI16x8Shl, OpcodeVecI16x8ShrS, OpcodeVecI16x8ShrU,
+				OpcodeVecI32x4Shl, OpcodeVecI32x4ShrS, OpcodeVecI32x4ShrU, OpcodeVecI64x2Shl, OpcodeVecI64x2ShrS, OpcodeVecI64x2ShrU:
+				params, results = []ValueType{ValueTypeV128, ValueTypeI32}, []ValueType{ValueTypeV128}
+			case OpcodeVecV128Bitselect:
+				params = []ValueType{ValueTypeV128, ValueTypeV128, ValueTypeV128}
+				results = []ValueType{ValueTypeV128}
+			case OpcodeVecV128Not, OpcodeVecI8x16Abs, OpcodeVecI8x16Neg, OpcodeVecI8x16Popcnt,
+				OpcodeVecI16x8ExtaddPairwiseI8x16S, OpcodeVecI16x8ExtaddPairwiseI8x16U, OpcodeVecI16x8Abs, OpcodeVecI16x8Neg,
+				OpcodeVecI16x8ExtendLowI8x16S, OpcodeVecI16x8ExtendHighI8x16S, OpcodeVecI16x8ExtendLowI8x16U, OpcodeVecI16x8ExtendHighI8x16U,
+				OpcodeVecI32x4ExtaddPairwiseI16x8S, OpcodeVecI32x4ExtaddPairwiseI16x8U, OpcodeVecI32x4Abs, OpcodeVecI32x4Neg,
+				OpcodeVecI32x4ExtendLowI16x8S, OpcodeVecI32x4ExtendHighI16x8S, OpcodeVecI32x4ExtendLowI16x8U, OpcodeVecI32x4ExtendHighI16x8U,
+				OpcodeVecI64x2Abs, OpcodeVecI64x2Neg,
+				OpcodeVecI64x2ExtendLowI32x4S, OpcodeVecI64x2ExtendHighI32x4S, OpcodeVecI64x2ExtendLowI32x4U, OpcodeVecI64x2ExtendHighI32x4U,
+				OpcodeVecF32x4Ceil, OpcodeVecF32x4Floor, OpcodeVecF32x4Trunc, OpcodeVecF32x4Nearest,
+				OpcodeVecF32x4Abs, OpcodeVecF32x4Neg, OpcodeVecF32x4Sqrt,
+				OpcodeVecF64x2Ceil, OpcodeVecF64x2Floor, OpcodeVecF64x2Trunc, OpcodeVecF64x2Nearest,
+				OpcodeVecF64x2Abs, OpcodeVecF64x2Neg, OpcodeVecF64x2Sqrt,
+				OpcodeVecI32x4TruncSatF32x4S, OpcodeVecI32x4TruncSatF32x4U, OpcodeVecF32x4ConvertI32x4S, OpcodeVecF32x4ConvertI32x4U,
+				OpcodeVecI32x4TruncSatF64x2SZero, OpcodeVecI32x4TruncSatF64x2UZero, OpcodeVecF64x2ConvertLowI32x4S, OpcodeVecF64x2ConvertLowI32x4U,
+				OpcodeVecF32x4DemoteF64x2Zero, OpcodeVecF64x2PromoteLowF32x4Zero:
+				params, results = []ValueType{ValueTypeV128}, []ValueType{ValueTypeV128}
 			default:
-				return fmt.Errorf("TODO: SIMD instruction %s will be implemented in #506", vectorInstructionName[vecOpcode])
+				// The remaining instructions are binary operations on two vectors.
+				params = []ValueType{ValueTypeV128, ValueTypeV128}
+				results = []ValueType{ValueTypeV128}
+			}
+
+			if maxAlign != 0 {
+				pc++
+				align, memoryIndex, num, err := DecodeMemArgAlignment(bytes.NewReader(body[pc:]), enabledFeatures)
+				if err != nil {
+					return fmt.Errorf("read memory align for %s: %v", vecName, err)
+				}
+				if memoryIndex >= uint32(len(memories)) {
+					return fmt.Errorf("memory must exist for %s", vecName)
+				}
+				if align >= 32 || 1<<align > uint32(maxAlign) {
+					return fmt.Errorf("invalid memory alignment %d for %s", align, vecName)
+				}
+				// The address is always the first operand.
+				params[0] = memories[memoryIndex].IndexType()
+				pc += num
+				_, num, err = DecodeMemArgOffset(bytes.NewReader(body[pc:]), memories[memoryIndex].IsMemory64)
+				if err != nil {
+					return fmt.Errorf("read memory offset for %s: %v", vecName, err)
+				}
+				pc += num - 1
+			}
+			if laneCount != 0 {
+				pc++
+				if int(pc) >= len(body) {
+					return fmt.Errorf("cannot read lane index for %s", vecName)
+				}
+				if lane := body[pc]; lane >= laneCount {
+					return fmt.Errorf("invalid lane index %d >= %d for %s", lane, laneCount, vecName)
+				}
+			}
+
+			for i := len(params) - 1; i >= 0; i-- {
+				if err := valueTypeStack.popAndVerifyType(params[i]); err != nil {
+					return fmt.Errorf("cannot pop the operand for %s: %v", vecName, err)
+				}
+			}
+			for _, r := range results {
+				valueTypeStack.push(r)
+			}
+		} else if op == OpcodeAtomicPrefix {
+			pc++
+			// Atomic instructions come with two bytes where the first byte is always OpcodeAtomicPrefix,
+			// and the second is the actual instruction encoded as LEB128 uint32.
+			atomicOpcode32, num, err := leb128.DecodeUint32(bytes.NewReader(body[pc:]))
+			if err != nil {
+				return fmt.Errorf("read atomic instruction: %v", err)
+			}
+			atomicOpcode, ok := OpcodeAtomic(atomicOpcode32), atomicOpcode32 <= 0xff
+			if !ok || atomicInstructionName[atomicOpcode] == "" {
+				return fmt.Errorf("invalid atomic instruction: %#x", atomicOpcode32)
+			}
+			pc += num - 1
+			atomicName := atomicInstructionName[atomicOpcode]
+			if err := enabledFeatures.Require(FeatureThreads); err != nil {
+				return fmt.Errorf("%s invalid as %v", atomicName, err)
+			}
+
+			if atomicOpcode == OpcodeAtomicFence {
+				// The fence has a reserved byte which must be zero, and doesn't have any operand.
+				pc++
+				if int(pc) >= len(body) || body[pc] != 0x00 {
+					return fmt.Errorf("invalid reserved byte for %s", atomicName)
+				}
+				continue
+			}
+
+			// naturalAlign is the size of the accessed memory in bytes, which must equal the alignment.
+			var naturalAlign uint32
+			var params, results []ValueType
+			switch atomicOpcode {
+			case OpcodeAtomicMemoryNotify:
+				naturalAlign = 4
+				params, results = []ValueType{ValueTypeI32, ValueTypeI32}, []ValueType{ValueTypeI32}
+			case OpcodeAtomicMemoryWait32:
+				naturalAlign = 4
+				params, results = []ValueType{ValueTypeI32, ValueTypeI32, ValueTypeI64}, []ValueType{ValueTypeI32}
+			case OpcodeAtomicMemoryWait64:
+				naturalAlign = 8
+				params, results = []ValueType{ValueTypeI32, ValueTypeI64, ValueTypeI64}, []ValueType{ValueTypeI32}
+			default:
+				// The remaining loads, stores and read-modify-write instructions are grouped by seven opcodes,
+				// and each group has the same order of the accessed sizes and value types.
+				var group uint32
+				if atomicOpcode >= OpcodeAtomicI32RmwAdd {
+					group = uint32(atomicOpcode-OpcodeAtomicI32RmwAdd) % 7
+				} else if atomicOpcode >= OpcodeAtomicI32Store {
+					group = uint32(atomicOpcode - OpcodeAtomicI32Store)
+				} else {
+					group = uint32(atomicOpcode - OpcodeAtomicI32Load)
+				}
+				naturalAlign = [7]uint32{4, 8, 1, 2, 1, 2, 4}[group]
+				t := [7]ValueType{ValueTypeI32, ValueTypeI64, ValueTypeI32, ValueTypeI32, ValueTypeI64, ValueTypeI64, ValueTypeI64}[group]
+
+				switch {
+				case atomicOpcode <= OpcodeAtomicI64Load32U:
+					params, results = []ValueType{ValueTypeI32}, []ValueType{t}
+				case atomicOpcode <= OpcodeAtomicI64Store32:
+					params = []ValueType{ValueTypeI32, t}
+				case atomicOpcode >= OpcodeAtomicI32RmwCmpxchg:
+					params, results = []ValueType{ValueTypeI32, t, t}, []ValueType{t}
+				default:
+					params, results = []ValueType{ValueTypeI32, t}, []ValueType{t}
+				}
+			}
+
+			pc++
+			align, memoryIndex, num, err := DecodeMemArgAlignment(bytes.NewReader(body[pc:]), enabledFeatures)
+			if err != nil {
+				return fmt.Errorf("read memory align for %s: %v", atomicName, err)
+			}
+			if memoryIndex >= uint32(len(memories)) {
+				return fmt.Errorf("memory must exist for %s", atomicName)
+			}
+			// Unlike the other memory instructions, atomic ones require the exact natural alignment.
+			if align >= 32 || 1<<align != naturalAlign {
+				return fmt.Errorf("invalid memory alignment %d for %s", align, atomicName)
+			}
+			// The address is always the first operand.
+			params[0] = memories[memoryIndex].IndexType()
+			pc += num
+			_, num, err = DecodeMemArgOffset(bytes.NewReader(body[pc:]), memories[memoryIndex].IsMemory64)
+			if err != nil {
+				return fmt.Errorf("read memory offset for %s: %v", atomicName, err)
+			}
+			pc += num - 1
+
+			for i := len(params) - 1; i >= 0; i-- {
+				if err := valueTypeStack.popAndVerifyType(params[i]); err != nil {
+					return fmt.Errorf("cannot pop the operand for %s: %v", atomicName, err)
+				}
+			}
+			for _, r := range results {
+				valueTypeStack.push(r)
+			}
+		} else if op == OpcodeBlock || op == OpcodeTry {
+			var blockOp Opcode
+			if op == OpcodeTry {
+				if err := enabledFeatures.Require(FeatureExceptionHandling); err != nil {
+					return fmt.Errorf("%s invalid as %v", OpcodeTryName, err)
+				}
+				blockOp = op
 			}
-		} else if op == OpcodeBlock {
 			bt, num, err := DecodeBlockType(types, bytes.NewReader(body[pc+1:]), enabledFeatures)
 			if err != nil {
 				return fmt.Errorf("read block: %w", err)
@@ -1085,6 +1384,7 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				startAt:        pc,
 				blockType:      bt,
 				blockTypeBytes: num,
+				op:             blockOp,
 			})
 			if err = valueTypeStack.popParams(op, bt.Params, false); err != nil {
 				return err
@@ -1151,11 +1451,97 @@ func (m *Module) validateFunctionWithMaxStackValues(
 			for _, p := range bl.blockType.Params {
 				valueTypeStack.push(p)
 			}
-		} else if op == OpcodeEnd {
+		} else if op == OpcodeCatch || op == OpcodeCatchAll {
+			if err := enabledFeatures.Require(FeatureExceptionHandling); err != nil {
+				return fmt.Errorf("%s invalid as %v", InstructionName(op), err)
+			}
 			bl := controlBlockStack[len(controlBlockStack)-1]
+			if bl.op != OpcodeTry && bl.op != OpcodeCatch {
+				return fmt.Errorf("%s must follow %s or %s", InstructionName(op), OpcodeTryName, OpcodeCatchName)
+			}
+			// Check the type soundness of the instructions *before* entering this handler.
+			if err := valueTypeStack.popResults(bl.op, bl.blockType.Results, true); err != nil {
+				return err
+			}
+			// Handlers begin with the stack as it was before the try block, as the try block params are consumed.
+			valueTypeStack.resetAtStackLimit()
+			if op == OpcodeCatch {
+				pc++
+				index, num, err := leb128.DecodeUint32(bytes.NewReader(body[pc:]))
+				if err != nil {
+					return fmt.Errorf("read immediate: %v", err)
+				}
+				pc += num - 1
+				tagType := m.TypeOfTag(index)
+				if tagType == nil {
+					return fmt.Errorf("unknown tag %d for %s", index, OpcodeCatchName)
+				}
+				// The exception values are pushed for the handler.
+				for _, p := range tagType.Params {
+					valueTypeStack.push(p)
+				}
+			}
+			bl.op = op
+		} else if op == OpcodeThrow {
+			if err := enabledFeatures.Require(FeatureExceptionHandling); err != nil {
+				return fmt.Errorf("%s invalid as %v", OpcodeThrowName, err)
+			}
+			pc++
+			index, num, err := leb128.DecodeUint32(bytes.NewReader(body[pc:]))
+			if err != nil {
+				return fmt.Errorf("read immediate: %v", err)
+			}
+			pc += num - 1
+			tagType := m.TypeOfTag(index)
+			if tagType == nil {
+				return fmt.Errorf("unknown tag %d for %s", index, OpcodeThrowName)
+			}
+			if err = valueTypeStack.popParams(op, tagType.Params, false); err != nil {
+				return err
+			}
+			// throw instruction is stack-polymorphic.
+			valueTypeStack.unreachable()
+		} else if op == OpcodeRethrow {
+			if err := enabledFeatures.Require(FeatureExceptionHandling); err != nil {
+				return fmt.Errorf("%s invalid as %v", OpcodeRethrowName, err)
+			}
+			pc++
+			index, num, err := leb128.DecodeUint32(bytes.NewReader(body[pc:]))
+			if err != nil {
+				return fmt.Errorf("read immediate: %v", err)
+			} else if int(index) >= len(controlBlockStack) {
+				return fmt.Errorf("invalid %s operation: index out of range", OpcodeRethrowName)
+			}
+			pc += num - 1
+			if target := controlBlockStack[len(controlBlockStack)-int(index)-1]; target.op != OpcodeCatch && target.op != OpcodeCatchAll {
+				return fmt.Errorf("invalid %s operation: label %d is not a %s or %s", OpcodeRethrowName, index, OpcodeCatchName, OpcodeCatchAllName)
+			}
+			// rethrow instruction is stack-polymorphic.
+			valueTypeStack.unreachable()
+		} else if op == OpcodeEnd || op == OpcodeDelegate {
+			bl := controlBlockStack[len(controlBlockStack)-1]
+			if op == OpcodeDelegate {
+				if err := enabledFeatures.Require(FeatureExceptionHandling); err != nil {
+					return fmt.Errorf("%s invalid as %v", OpcodeDelegateName, err)
+				} else if bl.op != OpcodeTry {
+					return fmt.Errorf("%s must end a %s block without handlers", OpcodeDelegateName, OpcodeTryName)
+				}
+			}
 			bl.endAt = pc
 			controlBlockStack = controlBlockStack[:len(controlBlockStack)-1]
 
+			if op == OpcodeDelegate {
+				// The label is relative to the blocks enclosing the try block.
+				pc++
+				index, num, err := leb128.DecodeUint32(bytes.NewReader(body[pc:]))
+				if err != nil {
+					return fmt.Errorf("read immediate: %v", err)
+				} else if int(index) >= len(controlBlockStack) {
+					return fmt.Errorf("invalid %s operation: index out of range", OpcodeDelegateName)
+				}
+				pc += num - 1
+			}
+
 			// OpcodeEnd can end a block or the function itself. Check to see what it is:
 
 			ifMissingElse := bl.op == OpcodeIf && bl.elseAt <= bl.startAt
@@ -1226,8 +1612,13 @@ func (m *Module) validateFunctionWithMaxStackValues(
 				}
 				pc++
 				tp := body[pc]
-				if tp != ValueTypeI32 && tp != ValueTypeI64 && tp != ValueTypeF32 && tp != ValueTypeF64 &&
-					tp != api.ValueTypeExternref && tp != ValueTypeFuncref {
+				switch tp {
+				case ValueTypeI32, ValueTypeI64, ValueTypeF32, ValueTypeF64, api.ValueTypeExternref, ValueTypeFuncref:
+				case ValueTypeV128:
+					if err := enabledFeatures.Require(FeatureSIMD); err != nil {
+						return fmt.Errorf("invalid type %s for %s as %w", ValueTypeName(tp), OpcodeTypedSelectName, err)
+					}
+				default:
 					return fmt.Errorf("invalid type %s for %s", ValueTypeName(tp), OpcodeTypedSelectName)
 				}
 			} else if isReferenceValueType(v1) || isReferenceValueType(v2) {
@@ -1445,6 +1836,21 @@ func typeCountError(isParam bool, context string, have []ValueType, want []Value
 	return errors.New(ret.String())
 }
 
+// verifyTailCallResults returns an error unless the callee of a tail call returns the same results as the caller, as
+// its results are returned from the caller.
+func verifyTailCallResults(callee, caller *FunctionType) error {
+	if bytes.Equal(callee.Results, caller.Results) {
+		return nil
+	}
+	var ret strings.Builder
+	ret.WriteString("callee results (")
+	writeValueTypes(callee.Results, &ret)
+	ret.WriteString(") != function results (")
+	writeValueTypes(caller.Results, &ret)
+	ret.WriteByte(')')
+	return errors.New(ret.String())
+}
+
 func writeValueTypes(vts []ValueType, ret *strings.Builder) {
 	switch len(vts) {
 	case 0:
@@ -1491,6 +1897,42 @@ type controlBlock struct {
 	op Opcode
 }
 
+// memArgMemoryIndexFlag is set in the alignment of a memarg when the memory index follows it.
+//
+// See https://github.com/WebAssembly/multi-memory/blob/main/proposals/multi-memory/Overview.md#binary-format
+const memArgMemoryIndexFlag = 1 << 6
+
+// DecodeMemArgAlignment decodes the alignment of a memarg, followed by the memory index when `enabledFeatures` include
+// FeatureMultiMemory and the alignment has memArgMemoryIndexFlag. Otherwise, the memory index is zero. The returned
+// alignment excludes the flag, and the returned number of bytes read excludes the offset following them.
+func DecodeMemArgAlignment(r *bytes.Reader, enabledFeatures Features) (align, memoryIndex uint32, num uint64, err error) {
+	if align, num, err = leb128.DecodeUint32(r); err != nil {
+		return
+	}
+	if align&memArgMemoryIndexFlag == 0 || !enabledFeatures.Get(FeatureMultiMemory) {
+		return
+	}
+
+	var n uint64
+	if memoryIndex, n, err = leb128.DecodeUint32(r); err != nil {
+		err = fmt.Errorf("read memory index: %w", err)
+		return
+	}
+	return align &^ memArgMemoryIndexFlag, memoryIndex, num + n, nil
+}
+
+// DecodeMemArgOffset decodes the offset of a memarg, which is encoded as 64-bit when the memory is indexed with i64.
+//
+// See https://github.com/WebAssembly/memory64/blob/main/proposals/memory64/Overview.md#binary-format
+func DecodeMemArgOffset(r *bytes.Reader, memory64 bool) (offset, num uint64, err error) {
+	if memory64 {
+		return leb128.DecodeUint64(r)
+	}
+	var o uint32
+	o, num, err = leb128.DecodeUint32(r)
+	return uint64(o), num, err
+}
+
 // DecodeBlockType decodes the type index from a positive 33-bit signed integer. Negative numbers indicate up to one
 // WebAssembly 1.0 (20191205) compatible result type. Positive numbers are decoded when `enabledFeatures` include
 // FeatureMultiValue and include an index in the Module.TypeSection.
@@ -287,7 +287,7 @@ func TestModule_ValidateFunction_BulkMemoryOperations(t *testing.T) {
 					ElementSection:   []*ElementSegment{{}},
 					DataCountSection: &c,
 				}
-				err := m.validateFunction(FeatureBulkMemoryOperations, 0, []Index{0}, nil, &Memory{}, []*Table{{}, {}}, nil)
+				err := m.validateFunction(FeatureBulkMemoryOperations, 0, []Index{0}, nil, []*Memory{{}}, []*Table{{}, {}}, nil)
 				require.NoError(t, err)
 			})
 		}
@@ -298,7 +298,7 @@ func TestModule_ValidateFunction_BulkMemoryOperations(t *testing.T) {
 			dataSection         []*DataSegment
 			elementSection      []*ElementSegment
 			dataCountSectionNil bool
-			memory              *Memory
+			memories            []*Memory
 			tables              []*Table
 			flag                Features
 			expectedErr         string
@@ -307,7 +307,7 @@ func TestModule_ValidateFunction_BulkMemoryOperations(t *testing.T) {
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryInit},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      nil,
+				memories:    nil,
 				expectedErr: "memory must exist for memory.init",
 			},
 			{
@@ -324,48 +324,48 @@ func TestModule_ValidateFunction_BulkMemoryOperations(t *testing.T) {
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryInit},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				expectedErr: "failed to read data segment index for memory.init: EOF",
 			},
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryInit, 100 /* data section out of range */},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				dataSection: []*DataSegment{{}},
 				expectedErr: "index 100 out of range of data section(len=1)",
 			},
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryInit, 0},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				dataSection: []*DataSegment{{}},
 				expectedErr: "failed to read memory index for memory.init: EOF",
 			},
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryInit, 0, 1},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				dataSection: []*DataSegment{{}},
 				expectedErr: "memory.init reserved byte must be zero encoded with 1 byte",
 			},
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryInit, 0, 0},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				dataSection: []*DataSegment{{}},
 				expectedErr: "cannot pop the operand for memory.init: i32 missing",
 			},
 			{
 				body:        []byte{OpcodeI32Const, 0, OpcodeMiscPrefix, OpcodeMiscMemoryInit, 0, 0},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				dataSection: []*DataSegment{{}},
 				expectedErr: "cannot pop the operand for memory.init: i32 missing",
 			},
 			{
 				body:        []byte{OpcodeI32Const, 0, OpcodeI32Const, 0, OpcodeMiscPrefix, OpcodeMiscMemoryInit, 0, 0},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				dataSection: []*DataSegment{{}},
 				expectedErr: "cannot pop the operand for memory.init: i32 missing",
 			},
@@ -378,20 +378,20 @@ func TestModule_ValidateFunction_BulkMemoryOperations(t *testing.T) {
 			{
 				body:                []byte{OpcodeMiscPrefix, OpcodeMiscDataDrop},
 				dataCountSectionNil: true,
-				memory:              &Memory{},
+				memories:            []*Memory{{}},
 				flag:                FeatureBulkMemoryOperations,
 				expectedErr:         `data.drop requires data count section`,
 			},
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscDataDrop},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				expectedErr: "failed to read data segment index for data.drop: EOF",
 			},
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscDataDrop, 100 /* data section out of range */},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				dataSection: []*DataSegment{{}},
 				expectedErr: "index 100 out of range of data section(len=1)",
 			},
@@ -399,7 +399,7 @@ func TestModule_ValidateFunction_BulkMemoryOperations(t *testing.T) {
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryCopy},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      nil,
+				memories:    nil,
 				expectedErr: "memory must exist for memory.copy",
 			},
 			{
@@ -410,44 +410,44 @@ func TestModule_ValidateFunction_BulkMemoryOperations(t *testing.T) {
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryCopy},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				expectedErr: `failed to read memory index for memory.copy: EOF`,
 			},
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryCopy, 0},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				expectedErr: "failed to read memory index for memory.copy: EOF",
 			},
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryCopy, 0, 1},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				expectedErr: "memory.copy reserved byte must be zero encoded with 1 byte",
 			},
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryCopy, 0, 0},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				expectedErr: "cannot pop the operand for memory.copy: i32 missing",
 			},
 			{
 				body:        []byte{OpcodeI32Const, 0, OpcodeMiscPrefix, OpcodeMiscMemoryCopy, 0, 0},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				expectedErr: "cannot pop the operand for memory.copy: i32 missing",
 			},
 			{
 				body:        []byte{OpcodeI32Const, 0, OpcodeI32Const, 0, OpcodeMiscPrefix, OpcodeMiscMemoryCopy, 0, 0},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				expectedErr: "cannot pop the operand for memory.copy: i32 missing",
 			},
 			// memory.fill
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryFill},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      nil,
+				memories:    nil,
 				expectedErr: "memory must exist for memory.fill",
 			},
 			{
@@ -458,31 +458,31 @@ func TestModule_ValidateFunction_BulkMemoryOperations(t *testing.T) {
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryFill},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				expectedErr: `failed to read memory index for memory.fill: EOF`,
 			},
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryFill, 1},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				expectedErr: `memory.fill reserved byte must be zero encoded with 1 byte`,
 			},
 			{
 				body:        []byte{OpcodeMiscPrefix, OpcodeMiscMemoryFill, 0},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				expectedErr: "cannot pop the operand for memory.fill: i32 missing",
 			},
 			{
 				body:        []byte{OpcodeI32Const, 0, OpcodeMiscPrefix, OpcodeMiscMemoryFill, 0},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				expectedErr: "cannot pop the operand for memory.fill: i32 missing",
 			},
 			{
 				body:        []byte{OpcodeI32Const, 0, OpcodeI32Const, 0, OpcodeMiscPrefix, OpcodeMiscMemoryFill, 0},
 				flag:        FeatureBulkMemoryOperations,
-				memory:      &Memory{},
+				memories:    []*Memory{{}},
 				expectedErr: "cannot pop the operand for memory.fill: i32 missing",
 			},
 			// table.init
@@ -651,7 +651,7 @@ func TestModule_ValidateFunction_BulkMemoryOperations(t *testing.T) {
 					c := uint32(0)
 					m.DataCountSection = &c
 				}
-				err := m.validateFunction(tc.flag, 0, []Index{0}, nil, tc.memory, tc.tables, nil)
+				err := m.validateFunction(tc.flag, 0, []Index{0}, nil, tc.memories, tc.tables, nil)
 				require.EqualError(t, err, tc.expectedErr)
 			})
 		}
@@ -2196,7 +2196,7 @@ func TestModule_funcValidation_CallIndirect(t *testing.T) {
 				OpcodeEnd,
 			}}},
 		}
-		err := m.validateFunction(FeatureReferenceTypes, 0, []Index{0}, nil, &Memory{}, []*Table{{Type: RefTypeFuncref}}, nil)
+		err := m.validateFunction(FeatureReferenceTypes, 0, []Index{0}, nil, []*Memory{{}}, []*Table{{Type: RefTypeFuncref}}, nil)
 		require.NoError(t, err)
 	})
 	t.Run("non zero table index", func(t *testing.T) {
@@ -2210,11 +2210,11 @@ func TestModule_funcValidation_CallIndirect(t *testing.T) {
 			}}},
 		}
 		t.Run("disabled", func(t *testing.T) {
-			err := m.validateFunction(Features20191205, 0, []Index{0}, nil, &Memory{}, []*Table{{}, {}}, nil)
+			err := m.validateFunction(Features20191205, 0, []Index{0}, nil, []*Memory{{}}, []*Table{{}, {}}, nil)
 			require.EqualError(t, err, "table index must be zero but was 100: feature \"reference-types\" is disabled")
 		})
 		t.Run("enabled but out of range", func(t *testing.T) {
-			err := m.validateFunction(FeatureReferenceTypes, 0, []Index{0}, nil, &Memory{}, []*Table{{}, {}}, nil)
+			err := m.validateFunction(FeatureReferenceTypes, 0, []Index{0}, nil, []*Memory{{}}, []*Table{{}, {}}, nil)
 			require.EqualError(t, err, "unknown table index: 100")
 		})
 	})
@@ -2228,7 +2228,7 @@ func TestModule_funcValidation_CallIndirect(t *testing.T) {
 				OpcodeEnd,
 			}}},
 		}
-		err := m.validateFunction(FeatureReferenceTypes, 0, []Index{0}, nil, &Memory{}, []*Table{{Type: RefTypeExternref}}, nil)
+		err := m.validateFunction(FeatureReferenceTypes, 0, []Index{0}, nil, []*Memory{{}}, []*Table{{Type: RefTypeExternref}}, nil)
 		require.EqualError(t, err, "table is not funcref type but was externref for call_indirect")
 	})
 }
@@ -2650,6 +2650,16 @@ func TestModule_funcValidation_Select_error(t *testing.T) {
 			flag:        FeatureReferenceTypes,
 			expectedErr: `invalid type unknown for typed_select`,
 		},
+		{
+			name: "typed_select (v128 without simd)",
+			body: []byte{
+				OpcodeI32Const, 0, OpcodeI32Const, 0, OpcodeI32Const, 0,
+				OpcodeTypedSelect, 1, ValueTypeV128,
+				OpcodeEnd,
+			},
+			flag:        FeatureReferenceTypes,
+			expectedErr: `invalid type v128 for typed_select as feature "simd" is disabled`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -2695,7 +2705,7 @@ func TestModule_funcValidation_SIMD(t *testing.T) {
 				1, 1, 1, 1, 1, 1, 1, 1,
 				1, 1, 1, 1, 1, 1, 1, 1,
 				OpcodeVecPrefix,
-				OpcodeVecI32x4Add,
+				OpcodeVecI32x4Add, 0x01, // LEB128 encoding of 0xae
 				OpcodeDrop,
 				OpcodeEnd,
 			},
@@ -2712,7 +2722,108 @@ func TestModule_funcValidation_SIMD(t *testing.T) {
 				1, 1, 1, 1, 1, 1, 1, 1,
 				1, 1, 1, 1, 1, 1, 1, 1,
 				OpcodeVecPrefix,
-				OpcodeVecI64x2Add,
+				OpcodeVecI64x2Add, 0x01, // LEB128 encoding of 0xce
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "v128.load and v128.store",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeI32Const, 0,
+				OpcodeVecPrefix,
+				OpcodeVecV128Load, 4, 0,
+				OpcodeVecPrefix,
+				OpcodeVecV128Store, 4, 0,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "v128.load8_lane",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeVecPrefix,
+				OpcodeVecV128Const,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				OpcodeVecPrefix,
+				OpcodeVecV128Load8Lane, 0, 0, 15,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "i8x16.shuffle",
+			body: []byte{
+				OpcodeVecPrefix,
+				OpcodeVecV128Const,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				OpcodeVecPrefix,
+				OpcodeVecV128Const,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				OpcodeVecPrefix,
+				OpcodeVecV128i8x16Shuffle,
+				0, 1, 2, 3, 4, 5, 6, 7,
+				24, 25, 26, 27, 28, 29, 30, 31,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "f64x2.replace_lane",
+			body: []byte{
+				OpcodeVecPrefix,
+				OpcodeVecV128Const,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				OpcodeF64Const, 0, 0, 0, 0, 0, 0, 0, 0,
+				OpcodeVecPrefix,
+				OpcodeVecF64x2ReplaceLane, 1,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "i64x2.splat and i64x2.extract_lane",
+			body: []byte{
+				OpcodeI64Const, 1,
+				OpcodeVecPrefix,
+				OpcodeVecI64x2Splat,
+				OpcodeVecPrefix,
+				OpcodeVecI64x2ExtractLane, 1,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "i32x4.all_true",
+			body: []byte{
+				OpcodeVecPrefix,
+				OpcodeVecV128Const,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				OpcodeVecPrefix,
+				OpcodeVecI32x4AllTrue, 0x01, // LEB128 encoding of 0xa3
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "typed_select v128",
+			body: []byte{
+				OpcodeVecPrefix,
+				OpcodeVecV128Const,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				OpcodeVecPrefix,
+				OpcodeVecV128Const,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				OpcodeI32Const, 0,
+				OpcodeTypedSelect, 1, ValueTypeV128,
 				OpcodeDrop,
 				OpcodeEnd,
 			},
@@ -2727,7 +2838,7 @@ func TestModule_funcValidation_SIMD(t *testing.T) {
 				FunctionSection: []Index{0},
 				CodeSection:     []*Code{{Body: tc.body}},
 			}
-			err := m.validateFunction(FeatureSIMD, 0, []Index{0}, nil, nil, nil, nil)
+			err := m.validateFunction(FeatureSIMD|FeatureReferenceTypes, 0, []Index{0}, nil, []*Memory{{}}, nil, nil)
 			require.NoError(t, err)
 		})
 	}
@@ -2744,7 +2855,7 @@ func TestModule_funcValidation_SIMD_error(t *testing.T) {
 			name: "simd disabled",
 			body: []byte{
 				OpcodeVecPrefix,
-				OpcodeVecF32x4Abs,
+				OpcodeVecF32x4Abs, 0x01, // LEB128 encoding of 0xe0
 			},
 			flag:        Features20191205,
 			expectedErr: "f32x4.abs invalid as feature \"simd\" is disabled",
@@ -2768,7 +2879,7 @@ func TestModule_funcValidation_SIMD_error(t *testing.T) {
 				1, 1, 1, 1, 1, 1, 1, 1,
 				1, 1, 1, 1, 1, 1, 1, 1,
 				OpcodeVecPrefix,
-				OpcodeVecI32x4Add,
+				OpcodeVecI32x4Add, 0x01, // LEB128 encoding of 0xae
 				OpcodeDrop,
 				OpcodeEnd,
 			},
@@ -2783,7 +2894,7 @@ func TestModule_funcValidation_SIMD_error(t *testing.T) {
 				1, 1, 1, 1, 1, 1, 1, 1,
 				1, 1, 1, 1, 1, 1, 1, 1,
 				OpcodeVecPrefix,
-				OpcodeVecI64x2Add,
+				OpcodeVecI64x2Add, 0x01, // LEB128 encoding of 0xce
 				OpcodeDrop,
 				OpcodeEnd,
 			},
@@ -2791,14 +2902,86 @@ func TestModule_funcValidation_SIMD_error(t *testing.T) {
 			expectedErr: "cannot pop the operand for i64x2.add: v128 missing",
 		},
 		{
-			// TODO delete this case after SIMD impl completion.
-			name: "unimplemented",
+			name: "invalid opcode",
+			body: []byte{
+				OpcodeVecPrefix,
+				0x9a, 0x01, // 0x9a is not assigned to any instruction.
+			},
+			flag:        FeatureSIMD,
+			expectedErr: "invalid vector instruction: 0x9a",
+		},
+		{
+			name: "i8x16.extract_lane_s lane index",
+			body: []byte{
+				OpcodeVecPrefix,
+				OpcodeVecV128Const,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				OpcodeVecPrefix,
+				OpcodeVecI8x16ExtractLaneS, 16,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+			flag:        FeatureSIMD,
+			expectedErr: "invalid lane index 16 >= 16 for i8x16.extract_lane_s",
+		},
+		{
+			name: "i8x16.shuffle lane index",
+			body: []byte{
+				OpcodeVecPrefix,
+				OpcodeVecV128i8x16Shuffle,
+				0, 1, 2, 3, 4, 5, 6, 7,
+				8, 9, 10, 11, 12, 13, 14, 32,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+			flag:        FeatureSIMD,
+			expectedErr: "invalid lane index 32 >= 32 for i8x16.shuffle",
+		},
+		{
+			name: "v128.load32_splat alignment",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeVecPrefix,
+				OpcodeVecV128Load32Splat, 3, 0,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+			flag:        FeatureSIMD,
+			expectedErr: "invalid memory alignment 3 for v128.load32_splat",
+		},
+		{
+			name: "v128.store operand",
+			body: []byte{
+				OpcodeVecPrefix,
+				OpcodeVecV128Const,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				OpcodeVecPrefix,
+				OpcodeVecV128Store, 4, 0,
+				OpcodeEnd,
+			},
+			flag:        FeatureSIMD,
+			expectedErr: "cannot pop the operand for v128.store: i32 missing",
+		},
+		{
+			name: "i32x4.shl operand",
 			body: []byte{
 				OpcodeVecPrefix,
-				OpcodeVecF32x4DemoteF64x2Zero,
+				OpcodeVecV128Const,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				OpcodeVecPrefix,
+				OpcodeVecV128Const,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				1, 1, 1, 1, 1, 1, 1, 1,
+				OpcodeVecPrefix,
+				OpcodeVecI32x4Shl, 0x01, // LEB128 encoding of 0xab
+				OpcodeDrop,
+				OpcodeEnd,
 			},
 			flag:        FeatureSIMD,
-			expectedErr: "TODO: SIMD instruction f32x4.demote_f64x2_zero will be implemented in #506",
+			expectedErr: "cannot pop the operand for i32x4.shl: type mismatch: expected i32, but was v128",
 		},
 	}
 
@@ -2810,7 +2993,688 @@ func TestModule_funcValidation_SIMD_error(t *testing.T) {
 				FunctionSection: []Index{0},
 				CodeSection:     []*Code{{Body: tc.body}},
 			}
-			err := m.validateFunction(tc.flag, 0, []Index{0}, nil, nil, nil, nil)
+			err := m.validateFunction(tc.flag, 0, []Index{0}, nil, []*Memory{{}}, nil, nil)
+			require.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestModule_funcValidation_Atomic(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        []byte
+		expectedErr string
+	}{
+		{
+			name: "i32.atomic.load",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeAtomicPrefix, OpcodeAtomicI32Load, 0x2, 0x8, // alignment=2 (natural alignment) offset=8
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "i64.atomic.store32",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeI64Const, 1,
+				OpcodeAtomicPrefix, OpcodeAtomicI64Store32, 0x2, 0x0,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "i32.atomic.rmw8.add_u",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeI32Const, 1,
+				OpcodeAtomicPrefix, OpcodeAtomicI32Rmw8AddU, 0x0, 0x0,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "i64.atomic.rmw.cmpxchg",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeI64Const, 1,
+				OpcodeI64Const, 2,
+				OpcodeAtomicPrefix, OpcodeAtomicI64RmwCmpxchg, 0x3, 0x0,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "memory.atomic.wait64",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeI64Const, 1,
+				OpcodeI64Const, 2,
+				OpcodeAtomicPrefix, OpcodeAtomicMemoryWait64, 0x3, 0x0,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "memory.atomic.notify",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeI32Const, 1,
+				OpcodeAtomicPrefix, OpcodeAtomicMemoryNotify, 0x2, 0x0,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "atomic.fence",
+			body: []byte{
+				OpcodeAtomicPrefix, OpcodeAtomicFence, 0x0,
+				OpcodeEnd,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Module{
+				TypeSection:     []*FunctionType{v_v},
+				FunctionSection: []Index{0},
+				CodeSection:     []*Code{{Body: tc.body}},
+			}
+			err := m.validateFunction(FeatureThreads, 0, []Index{0}, nil, []*Memory{{}}, nil, nil)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestModule_funcValidation_Atomic_error(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        []byte
+		flag        Features
+		memories    []*Memory
+		expectedErr string
+	}{
+		{
+			name: "threads disabled",
+			body: []byte{
+				OpcodeAtomicPrefix, OpcodeAtomicFence, 0x0,
+			},
+			flag:        Features20191205,
+			memories:    []*Memory{{}},
+			expectedErr: "atomic.fence invalid as feature \"threads\" is disabled",
+		},
+		{
+			name: "invalid opcode",
+			body: []byte{
+				OpcodeAtomicPrefix, 0x04,
+			},
+			flag:        FeatureThreads,
+			memories:    []*Memory{{}},
+			expectedErr: "invalid atomic instruction: 0x4",
+		},
+		{
+			name: "atomic.fence reserved byte",
+			body: []byte{
+				OpcodeAtomicPrefix, OpcodeAtomicFence, 0x1,
+			},
+			flag:        FeatureThreads,
+			memories:    []*Memory{{}},
+			expectedErr: "invalid reserved byte for atomic.fence",
+		},
+		{
+			name: "no memory",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeAtomicPrefix, OpcodeAtomicI32Load, 0x2, 0x0,
+			},
+			flag:        FeatureThreads,
+			expectedErr: "memory must exist for i32.atomic.load",
+		},
+		{
+			name: "alignment less than natural",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeAtomicPrefix, OpcodeAtomicI64Load, 0x2, 0x0,
+			},
+			flag:        FeatureThreads,
+			memories:    []*Memory{{}},
+			expectedErr: "invalid memory alignment 2 for i64.atomic.load",
+		},
+		{
+			name: "operand type",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeI32Const, 0,
+				OpcodeAtomicPrefix, OpcodeAtomicI64Rmw16AddU, 0x1, 0x0,
+			},
+			flag:        FeatureThreads,
+			memories:    []*Memory{{}},
+			expectedErr: "cannot pop the operand for i64.atomic.rmw16.add_u: type mismatch: expected i64, but was i32",
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Module{
+				TypeSection:     []*FunctionType{v_v},
+				FunctionSection: []Index{0},
+				CodeSection:     []*Code{{Body: tc.body}},
+			}
+			err := m.validateFunction(tc.flag, 0, []Index{0}, nil, tc.memories, nil, nil)
+			require.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestModule_funcValidation_TailCall(t *testing.T) {
+	tests := []struct {
+		name string
+		body []byte
+	}{
+		{
+			name: "return_call",
+			body: []byte{
+				OpcodeLocalGet, 0,
+				OpcodeReturnCall, 0,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "return_call_indirect",
+			body: []byte{
+				OpcodeLocalGet, 0,
+				OpcodeI32Const, 0,
+				OpcodeReturnCallIndirect, 0, 0,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "stack-polymorphic",
+			body: []byte{
+				OpcodeBlock, 0x40, // (block
+				OpcodeLocalGet, 0,
+				OpcodeReturnCall, 0,
+				OpcodeI64Const, 0, // unreachable, so not a type mismatch
+				OpcodeDrop,
+				OpcodeEnd, // )
+				OpcodeLocalGet, 0,
+				OpcodeEnd,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Module{
+				TypeSection:     []*FunctionType{i32_i32},
+				FunctionSection: []Index{0},
+				CodeSection:     []*Code{{Body: tc.body}},
+			}
+			err := m.validateFunction(FeatureTailCall, 0, []Index{0}, nil, nil, []*Table{{Type: RefTypeFuncref}}, nil)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestModule_funcValidation_TailCall_error(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        []byte
+		flag        Features
+		expectedErr string
+	}{
+		{
+			name: "return_call disabled",
+			body: []byte{
+				OpcodeLocalGet, 0,
+				OpcodeReturnCall, 0,
+				OpcodeEnd,
+			},
+			flag:        Features20220419,
+			expectedErr: "return_call invalid as feature \"tail-call\" is disabled",
+		},
+		{
+			name: "return_call_indirect disabled",
+			body: []byte{
+				OpcodeLocalGet, 0,
+				OpcodeI32Const, 0,
+				OpcodeReturnCallIndirect, 0, 0,
+				OpcodeEnd,
+			},
+			flag:        Features20220419,
+			expectedErr: "return_call_indirect invalid as feature \"tail-call\" is disabled",
+		},
+		{
+			name: "return_call result mismatch",
+			body: []byte{
+				OpcodeLocalGet, 0,
+				OpcodeReturnCall, 1,
+				OpcodeEnd,
+			},
+			flag:        FeatureTailCall,
+			expectedErr: "type mismatch on return_call operation result type: callee results () != function results (i32)",
+		},
+		{
+			name: "return_call_indirect result mismatch",
+			body: []byte{
+				OpcodeLocalGet, 0,
+				OpcodeI32Const, 0,
+				OpcodeReturnCallIndirect, 1, 0,
+				OpcodeEnd,
+			},
+			flag:        FeatureTailCall,
+			expectedErr: "type mismatch on return_call_indirect operation result type: callee results () != function results (i32)",
+		},
+		{
+			name: "return_call_indirect param mismatch",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeReturnCallIndirect, 0, 0,
+				OpcodeEnd,
+			},
+			flag:        FeatureTailCall,
+			expectedErr: "type mismatch on return_call_indirect operation input type",
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Module{
+				TypeSection:     []*FunctionType{i32_i32, i32_v},
+				FunctionSection: []Index{0, 1},
+				CodeSection:     []*Code{{Body: tc.body}, {Body: []byte{OpcodeEnd}}},
+			}
+			err := m.validateFunction(tc.flag, 0, []Index{0, 1}, nil, nil, []*Table{{Type: RefTypeFuncref}}, nil)
+			require.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestModule_funcValidation_ExceptionHandling(t *testing.T) {
+	tests := []struct {
+		name string
+		body []byte
+	}{
+		{
+			name: "try catch",
+			body: []byte{
+				OpcodeTry, ValueTypeI32, // (try (result i32)
+				OpcodeLocalGet, 0,
+				OpcodeThrow, 0,
+				OpcodeCatch, 0, // the tag's i32 is the result
+				OpcodeEnd, // )
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "try catch catch_all",
+			body: []byte{
+				OpcodeTry, ValueTypeI32, // (try (result i32)
+				OpcodeLocalGet, 0,
+				OpcodeCatch, 0, // the tag's i32 is the result
+				OpcodeCatchAll,
+				OpcodeI32Const, 1,
+				OpcodeEnd, // )
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "rethrow",
+			body: []byte{
+				OpcodeTry, 0x40, // (try
+				OpcodeCatchAll,
+				OpcodeBlock, 0x40, // (block
+				OpcodeRethrow, 1,
+				OpcodeEnd, // )
+				OpcodeEnd, // )
+				OpcodeLocalGet, 0,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "delegate",
+			body: []byte{
+				OpcodeTry, 0x40, // (try
+				OpcodeTry, 0x40, // (try
+				OpcodeLocalGet, 0,
+				OpcodeThrow, 0,
+				OpcodeDelegate, 0, // ) to the outer try
+				OpcodeCatch, 0,
+				OpcodeDrop,
+				OpcodeEnd,       // )
+				OpcodeTry, 0x40, // (try
+				OpcodeDelegate, 0, // ) to the caller
+				OpcodeLocalGet, 0,
+				OpcodeEnd,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Module{
+				TypeSection:     []*FunctionType{i32_i32, i32_v},
+				FunctionSection: []Index{0},
+				TagSection:      []Index{1},
+				CodeSection:     []*Code{{Body: tc.body}},
+			}
+			err := m.validateFunction(FeatureExceptionHandling, 0, []Index{0}, nil, nil, nil, nil)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestModule_funcValidation_ExceptionHandling_error(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        []byte
+		flag        Features
+		expectedErr string
+	}{
+		{
+			name: "try disabled",
+			body: []byte{
+				OpcodeTry, 0x40,
+				OpcodeEnd,
+				OpcodeLocalGet, 0,
+				OpcodeEnd,
+			},
+			flag:        Features20220419,
+			expectedErr: "try invalid as feature \"exception-handling\" is disabled",
+		},
+		{
+			name: "throw unknown tag",
+			body: []byte{
+				OpcodeLocalGet, 0,
+				OpcodeThrow, 1,
+				OpcodeEnd,
+			},
+			flag:        FeatureExceptionHandling,
+			expectedErr: "unknown tag 1 for throw",
+		},
+		{
+			name: "throw param mismatch",
+			body: []byte{
+				OpcodeI64Const, 0,
+				OpcodeThrow, 0,
+				OpcodeEnd,
+			},
+			flag:        FeatureExceptionHandling,
+			expectedErr: "cannot use i64 in throw block as param[0] type i32",
+		},
+		{
+			name: "catch outside try",
+			body: []byte{
+				OpcodeBlock, 0x40,
+				OpcodeCatchAll,
+				OpcodeEnd,
+				OpcodeLocalGet, 0,
+				OpcodeEnd,
+			},
+			flag:        FeatureExceptionHandling,
+			expectedErr: "catch_all must follow try or catch",
+		},
+		{
+			name: "catch after catch_all",
+			body: []byte{
+				OpcodeTry, 0x40,
+				OpcodeCatchAll,
+				OpcodeCatch, 0,
+				OpcodeDrop,
+				OpcodeEnd,
+				OpcodeLocalGet, 0,
+				OpcodeEnd,
+			},
+			flag:        FeatureExceptionHandling,
+			expectedErr: "catch must follow try or catch",
+		},
+		{
+			name: "catch result mismatch",
+			body: []byte{
+				OpcodeTry, ValueTypeI32,
+				OpcodeLocalGet, 0,
+				OpcodeCatchAll,
+				OpcodeEnd,
+				OpcodeEnd,
+			},
+			flag:        FeatureExceptionHandling,
+			expectedErr: "not enough results in catch_all block\n\thave ()\n\twant (i32)",
+		},
+		{
+			name: "rethrow outside catch",
+			body: []byte{
+				OpcodeTry, 0x40,
+				OpcodeRethrow, 0,
+				OpcodeEnd,
+				OpcodeLocalGet, 0,
+				OpcodeEnd,
+			},
+			flag:        FeatureExceptionHandling,
+			expectedErr: "invalid rethrow operation: label 0 is not a catch or catch_all",
+		},
+		{
+			name: "delegate after catch",
+			body: []byte{
+				OpcodeTry, 0x40,
+				OpcodeCatchAll,
+				OpcodeDelegate, 0,
+				OpcodeLocalGet, 0,
+				OpcodeEnd,
+			},
+			flag:        FeatureExceptionHandling,
+			expectedErr: "delegate must end a try block without handlers",
+		},
+		{
+			name: "delegate label out of range",
+			body: []byte{
+				OpcodeTry, 0x40,
+				OpcodeDelegate, 1,
+				OpcodeLocalGet, 0,
+				OpcodeEnd,
+			},
+			flag:        FeatureExceptionHandling,
+			expectedErr: "invalid delegate operation: index out of range",
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Module{
+				TypeSection:     []*FunctionType{i32_i32, i32_v},
+				FunctionSection: []Index{0},
+				TagSection:      []Index{1},
+				CodeSection:     []*Code{{Body: tc.body}},
+			}
+			err := m.validateFunction(tc.flag, 0, []Index{0}, nil, nil, nil, nil)
+			require.EqualError(t, err, tc.expectedErr)
+		})
+	}
+}
+
+func TestModule_funcValidation_Memory64(t *testing.T) {
+	tests := []struct {
+		name string
+		body []byte
+	}{
+		{
+			name: "i32.load",
+			body: []byte{
+				OpcodeI64Const, 0,
+				OpcodeI32Load, 0x2, 0x80, 0x80, 0x80, 0x80, 0x10, // offset=1<<32
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "i64.store",
+			body: []byte{
+				OpcodeI64Const, 0,
+				OpcodeI64Const, 1,
+				OpcodeI64Store, 0x3, 0x0,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "memory.size and memory.grow",
+			body: []byte{
+				OpcodeMemorySize, 0,
+				OpcodeMemoryGrow, 0,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "memory.fill",
+			body: []byte{
+				OpcodeI64Const, 0,
+				OpcodeI32Const, 1,
+				OpcodeI64Const, 2,
+				OpcodeMiscPrefix, OpcodeMiscMemoryFill, 0,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "memory.copy",
+			body: []byte{
+				OpcodeI64Const, 0,
+				OpcodeI64Const, 1,
+				OpcodeI64Const, 2,
+				OpcodeMiscPrefix, OpcodeMiscMemoryCopy, 0, 0,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "memory.init",
+			body: []byte{
+				OpcodeI64Const, 0,
+				OpcodeI32Const, 1,
+				OpcodeI32Const, 2,
+				OpcodeMiscPrefix, OpcodeMiscMemoryInit, 0, 0,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "v128.load",
+			body: []byte{
+				OpcodeI64Const, 0,
+				OpcodeVecPrefix, OpcodeVecV128Load, 0x4, 0x0,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+		{
+			name: "i32.atomic.rmw.add",
+			body: []byte{
+				OpcodeI64Const, 0,
+				OpcodeI32Const, 1,
+				OpcodeAtomicPrefix, OpcodeAtomicI32RmwAdd, 0x2, 0x0,
+				OpcodeDrop,
+				OpcodeEnd,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Module{
+				TypeSection:      []*FunctionType{v_v},
+				FunctionSection:  []Index{0},
+				CodeSection:      []*Code{{Body: tc.body}},
+				DataSection:      []*DataSegment{{}},
+				DataCountSection: uint32Ptr(1),
+			}
+			err := m.validateFunction(Features20220419|FeatureThreads|FeatureMemory64, 0, []Index{0}, nil,
+				[]*Memory{{IsMemory64: true}}, nil, nil)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestModule_funcValidation_Memory64_error(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        []byte
+		memories    []*Memory
+		expectedErr string
+	}{
+		{
+			name: "i32 address into memory64",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeI32Load, 0x2, 0x0,
+			},
+			memories:    []*Memory{{IsMemory64: true}},
+			expectedErr: "type mismatch: expected i64, but was i32",
+		},
+		{
+			name: "i64 address into memory32",
+			body: []byte{
+				OpcodeI64Const, 0,
+				OpcodeI32Load, 0x2, 0x0,
+			},
+			memories:    []*Memory{{}},
+			expectedErr: "type mismatch: expected i32, but was i64",
+		},
+		{
+			name: "64-bit offset into memory32",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeI32Load, 0x2, 0x80, 0x80, 0x80, 0x80, 0x10,
+			},
+			memories:    []*Memory{{}},
+			expectedErr: "read memory offset: overflows a 32-bit integer",
+		},
+		{
+			name: "memory.grow i32 delta",
+			body: []byte{
+				OpcodeI32Const, 0,
+				OpcodeMemoryGrow, 0,
+			},
+			memories:    []*Memory{{IsMemory64: true}},
+			expectedErr: "type mismatch: expected i64, but was i32",
+		},
+		{
+			name: "memory.fill i32 size",
+			body: []byte{
+				OpcodeI64Const, 0,
+				OpcodeI32Const, 1,
+				OpcodeI32Const, 2,
+				OpcodeMiscPrefix, OpcodeMiscMemoryFill, 0,
+			},
+			memories:    []*Memory{{IsMemory64: true}},
+			expectedErr: "cannot pop the operand for memory.fill: type mismatch: expected i64, but was i32",
+		},
+		{
+			name: "memory.copy from memory32 into memory64 with i64 size",
+			body: []byte{
+				OpcodeI64Const, 0,
+				OpcodeI32Const, 1,
+				OpcodeI64Const, 2,
+				OpcodeMiscPrefix, OpcodeMiscMemoryCopy, 0, 1,
+			},
+			memories:    []*Memory{{IsMemory64: true}, {}},
+			expectedErr: "cannot pop the operand for memory.copy: type mismatch: expected i32, but was i64",
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			m := &Module{
+				TypeSection:     []*FunctionType{v_v},
+				FunctionSection: []Index{0},
+				CodeSection:     []*Code{{Body: tc.body}},
+			}
+			err := m.validateFunction(Features20220419|FeatureMultiMemory|FeatureMemory64, 0, []Index{0}, nil,
+				tc.memories, nil, nil)
 			require.EqualError(t, err, tc.expectedErr)
 		})
 	}
@@ -2,11 +2,13 @@ package wasm
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math"
 	"reflect"
 
 	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/internal/wasmruntime"
 )
 
 // FunctionKind identifies the type of function that can be called.
@@ -26,13 +28,28 @@ const (
 	// FunctionKindGoContextModule is a function implemented in Go, with a signature matching FunctionType, except arg
 	// zero is a context.Context and arg one is an api.Module.
 	FunctionKindGoContextModule
+	// FunctionKindGoModuleFunc is a function implemented in Go as an api.GoModuleFunc, with a FunctionType defined by
+	// a HostFunc instead of its signature. This is called without reflection.
+	FunctionKindGoModuleFunc
 )
 
+// HostFunc is a host function which declares its FunctionType explicitly, as it is called without reflection.
+type HostFunc struct {
+	// ParamTypes and ResultTypes are the signature of Code.
+	ParamTypes, ResultTypes []ValueType
+
+	// Code reads the ParamTypes from and writes the ResultTypes to the stack.
+	Code api.GoModuleFunc
+}
+
 // Below are reflection code to get the interface type used to parse functions and set values.
 
 var moduleType = reflect.TypeOf((*api.Module)(nil)).Elem()
 var goContextType = reflect.TypeOf((*context.Context)(nil)).Elem()
 var errorType = reflect.TypeOf((*error)(nil)).Elem()
+var goModuleFuncType = reflect.TypeOf(api.GoModuleFunc(nil))
+var functionType = reflect.TypeOf((*api.Function)(nil)).Elem()
+var v128Type = reflect.TypeOf([2]uint64{})
 
 // PopGoFuncParams pops the correct number of parameters off the stack into a parameter slice for use in CallGoFunc
 //
@@ -40,17 +57,8 @@ var errorType = reflect.TypeOf((*error)(nil)).Elem()
 // the stack is [..., A, B], then the function is called as F(A, B) where A and B are interpreted
 // as uint32 and float32 respectively.
 func PopGoFuncParams(f *FunctionInstance, popParam func() uint64) []uint64 {
-	// First, determine how many values we need to pop
-	paramCount := f.GoFunc.Type().NumIn()
-	switch f.Kind {
-	case FunctionKindGoNoContext:
-	case FunctionKindGoContextModule:
-		paramCount -= 2
-	default:
-		paramCount--
-	}
-
-	return PopValues(paramCount, popParam)
+	// ParamNumInUint64 excludes any context params and counts a ValueTypeV128 as two values.
+	return PopValues(f.Type.ParamNumInUint64, popParam)
 }
 
 // PopValues pops api.ValueType values from the stack and returns them in reverse order.
@@ -72,8 +80,15 @@ func PopValues(count int, popper func() uint64) []uint64 {
 //
 // * callCtx is passed to the host function as a first argument.
 //
+// When the last result of the function is a non-nil error, this panics with it wrapped in a
+// wasmruntime.HostFunctionError, which traps the calling Wasm function.
+//
 // Note: ctx must use the caller's memory, which might be different from the defining module on an imported function.
 func CallGoFunc(ctx context.Context, callCtx *CallContext, f *FunctionInstance, params []uint64) []uint64 {
+	if f.Kind == FunctionKindGoModuleFunc {
+		return CallGoModuleFunc(ctx, callCtx, f, params)
+	}
+
 	tp := f.GoFunc.Type()
 
 	var in []reflect.Value
@@ -94,7 +109,8 @@ func CallGoFunc(ctx context.Context, callCtx *CallContext, f *FunctionInstance,
 			i = 2
 		}
 
-		for _, raw := range params {
+		for j := 0; j < len(params); j++ {
+			raw := params[j]
 			val := reflect.New(tp.In(i)).Elem()
 			k := tp.In(i).Kind()
 			switch k {
@@ -106,6 +122,14 @@ func CallGoFunc(ctx context.Context, callCtx *CallContext, f *FunctionInstance,
 				val.SetUint(raw)
 			case reflect.Int32, reflect.Int64:
 				val.SetInt(int64(raw))
+			case reflect.Array: // [2]uint64, the low then high bits of a v128
+				val.Index(0).SetUint(raw)
+				j++
+				val.Index(1).SetUint(params[j])
+			case reflect.Interface: // api.Function
+				if raw != 0 {
+					val.Set(reflect.ValueOf(f.Module.Engine.LookupFunction(Reference(raw))))
+				}
 			default:
 				panic(fmt.Errorf("BUG: param[%d] has an invalid type: %v", i, k))
 			}
@@ -119,7 +143,14 @@ func CallGoFunc(ctx context.Context, callCtx *CallContext, f *FunctionInstance,
 	if tp.NumOut() > 0 {
 		results = make([]uint64, 0, tp.NumOut())
 	}
-	for i, ret := range f.GoFunc.Call(in) {
+	out := f.GoFunc.Call(in)
+	if last := len(out) - 1; last >= 0 && tp.Out(last) == errorType {
+		if err := out[last]; !err.IsNil() {
+			panic(&wasmruntime.HostFunctionError{Err: err.Interface().(error)})
+		}
+		out = out[:last]
+	}
+	for i, ret := range out {
 		switch ret.Kind() {
 		case reflect.Float32:
 			results = append(results, uint64(math.Float32bits(float32(ret.Float()))))
@@ -129,6 +160,17 @@ func CallGoFunc(ctx context.Context, callCtx *CallContext, f *FunctionInstance,
 			results = append(results, ret.Uint())
 		case reflect.Int32, reflect.Int64:
 			results = append(results, uint64(ret.Int()))
+		case reflect.Array: // [2]uint64, the low then high bits of a v128
+			results = append(results, ret.Index(0).Uint(), ret.Index(1).Uint())
+		case reflect.Interface: // api.Function
+			var ref Reference
+			if !ret.IsNil() {
+				var ok bool
+				if ref, ok = functionReference(ret.Interface().(api.Function), f.Module.Engine); !ok {
+					panic(fmt.Errorf("result[%d] is not a function of this runtime", i))
+				}
+			}
+			results = append(results, uint64(ref))
 		default:
 			panic(fmt.Errorf("BUG: result[%d] has an invalid type: %v", i, ret.Kind()))
 		}
@@ -136,6 +178,22 @@ func CallGoFunc(ctx context.Context, callCtx *CallContext, f *FunctionInstance,
 	return results
 }
 
+// CallGoModuleFunc executes the FunctionInstance.GoModuleFunc on a new stack initialized with params, and returns the
+// results from it. Engines avoid this allocation by calling FunctionInstance.GoModuleFunc on their own stack.
+func CallGoModuleFunc(ctx context.Context, callCtx *CallContext, f *FunctionInstance, params []uint64) []uint64 {
+	stackLen := f.Type.ParamNumInUint64
+	if stackLen < f.Type.ResultNumInUint64 {
+		stackLen = f.Type.ResultNumInUint64
+	}
+	var stack []uint64
+	if stackLen > 0 {
+		stack = make([]uint64, stackLen)
+		copy(stack, params)
+	}
+	f.GoModuleFunc(ctx, callCtx, stack)
+	return stack[:f.Type.ResultNumInUint64]
+}
+
 func newContextVal(ctx context.Context) reflect.Value {
 	val := reflect.New(goContextType).Elem()
 	val.Set(reflect.ValueOf(ctx))
@@ -160,6 +218,9 @@ func getFunctionType(fn *reflect.Value, enabledFeatures Features) (fk FunctionKi
 	fk = kind(p)
 	pOffset := 0
 	switch fk {
+	case FunctionKindGoModuleFunc:
+		err = errors.New("api.GoModuleFunc must be exported with its param and result types")
+		return
 	case FunctionKindGoNoContext:
 	case FunctionKindGoContextModule:
 		pOffset = 2
@@ -168,6 +229,9 @@ func getFunctionType(fn *reflect.Value, enabledFeatures Features) (fk FunctionKi
 	}
 
 	rCount := p.NumOut()
+	if rCount > 0 && p.Out(rCount-1) == errorType {
+		rCount-- // A trailing error traps when non-nil, so isn't a Wasm result.
+	}
 
 	if rCount > 1 {
 		// Guard >1.0 feature multi-value
@@ -178,11 +242,14 @@ func getFunctionType(fn *reflect.Value, enabledFeatures Features) (fk FunctionKi
 	}
 
 	ft = &FunctionType{Params: make([]ValueType, p.NumIn()-pOffset), Results: make([]ValueType, rCount)}
-	ft.CacheNumInUint64()
 
 	for i := 0; i < len(ft.Params); i++ {
 		pI := p.In(i + pOffset)
-		if t, ok := getTypeOf(pI.Kind()); ok {
+		if t, ok := getTypeOf(pI); ok {
+			if err = requireGoFuncValueType(t, enabledFeatures); err != nil {
+				err = fmt.Errorf("param[%d] %w", i+pOffset, err)
+				return
+			}
 			ft.Params[i] = t
 			continue
 		}
@@ -205,23 +272,43 @@ func getFunctionType(fn *reflect.Value, enabledFeatures Features) (fk FunctionKi
 
 	for i := 0; i < len(ft.Results); i++ {
 		rI := p.Out(i)
-		if t, ok := getTypeOf(rI.Kind()); ok {
+		if t, ok := getTypeOf(rI); ok {
+			if err = requireGoFuncValueType(t, enabledFeatures); err != nil {
+				err = fmt.Errorf("result[%d] %w", i, err)
+				return
+			}
 			ft.Results[i] = t
 			continue
 		}
 
 		// Now, we will definitely err, decide which message is best
 		if rI.Implements(errorType) {
-			err = fmt.Errorf("result[%d] is an error, which is unsupported", i)
+			err = fmt.Errorf("result[%d] is an error, which is only supported as the last result", i)
 		} else {
 			err = fmt.Errorf("result[%d] is unsupported: %s", i, rI.Kind())
 		}
 		return
 	}
+	ft.CacheNumInUint64()
 	return
 }
 
+// requireGoFuncValueType errs if the value type of a reflective host function requires a disabled feature, the same
+// as validateHostFuncValueType does for a HostFunc.
+//
+// Note: ValueTypeExternref (uintptr) isn't gated, as it was supported before FeatureReferenceTypes.
+func requireGoFuncValueType(t ValueType, enabledFeatures Features) error {
+	switch t {
+	case ValueTypeFuncref, ValueTypeV128:
+		return validateHostFuncValueType(t, enabledFeatures)
+	}
+	return nil
+}
+
 func kind(p reflect.Type) FunctionKind {
+	if p == goModuleFuncType {
+		return FunctionKindGoModuleFunc
+	}
 	pCount := p.NumIn()
 	if pCount > 0 && p.In(0).Kind() == reflect.Interface {
 		p0 := p.In(0)
@@ -237,8 +324,8 @@ func kind(p reflect.Type) FunctionKind {
 	return FunctionKindGoNoContext
 }
 
-func getTypeOf(kind reflect.Kind) (ValueType, bool) {
-	switch kind {
+func getTypeOf(t reflect.Type) (ValueType, bool) {
+	switch t.Kind() {
 	case reflect.Float64:
 		return ValueTypeF64, true
 	case reflect.Float32:
@@ -249,7 +336,67 @@ func getTypeOf(kind reflect.Kind) (ValueType, bool) {
 		return ValueTypeI64, true
 	case reflect.Uintptr:
 		return ValueTypeExternref, true
+	case reflect.Array:
+		if t == v128Type {
+			return ValueTypeV128, true
+		}
+		return 0x00, false
+	case reflect.Interface:
+		if t == functionType {
+			return ValueTypeFuncref, true
+		}
+		return 0x00, false
 	default:
 		return 0x00, false
 	}
 }
+
+// getHostFuncType returns the function type declared by the HostFunc or errs if invalid.
+func getHostFuncType(hf *HostFunc, enabledFeatures Features) (*FunctionType, error) {
+	if hf.Code == nil {
+		return nil, errors.New("code is nil")
+	}
+
+	if len(hf.ResultTypes) > 1 {
+		// Guard >1.0 feature multi-value
+		if err := enabledFeatures.Require(FeatureMultiValue); err != nil {
+			return nil, fmt.Errorf("multiple result types invalid as %v", err)
+		}
+	}
+
+	ft := &FunctionType{
+		Params:  append([]ValueType{}, hf.ParamTypes...),
+		Results: append([]ValueType{}, hf.ResultTypes...),
+	}
+	for i, t := range ft.Params {
+		if err := validateHostFuncValueType(t, enabledFeatures); err != nil {
+			return nil, fmt.Errorf("param[%d] %w", i, err)
+		}
+	}
+	for i, t := range ft.Results {
+		if err := validateHostFuncValueType(t, enabledFeatures); err != nil {
+			return nil, fmt.Errorf("result[%d] %w", i, err)
+		}
+	}
+	ft.CacheNumInUint64()
+	return ft, nil
+}
+
+func validateHostFuncValueType(t ValueType, enabledFeatures Features) error {
+	switch t {
+	case ValueTypeI32, ValueTypeI64, ValueTypeF32, ValueTypeF64:
+		return nil
+	case ValueTypeExternref, ValueTypeFuncref:
+		if err := enabledFeatures.Require(FeatureReferenceTypes); err != nil {
+			return fmt.Errorf("%s invalid as %v", ValueTypeName(t), err)
+		}
+		return nil
+	case ValueTypeV128:
+		if err := enabledFeatures.Require(FeatureSIMD); err != nil {
+			return fmt.Errorf("%s invalid as %v", ValueTypeName(t), err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("is unsupported: 0x%x", t)
+	}
+}
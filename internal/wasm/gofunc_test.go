@@ -2,6 +2,7 @@ package wasm
 
 import (
 	"context"
+	"errors"
 	"math"
 	"reflect"
 	"testing"
@@ -9,6 +10,7 @@ import (
 
 	"github.com/tetratelabs/wazero/api"
 	"github.com/tetratelabs/wazero/internal/testing/require"
+	"github.com/tetratelabs/wazero/internal/wasmruntime"
 )
 
 // testCtx is an arbitrary, non-default context. Non-nil also prevents linter errors.
@@ -63,6 +65,42 @@ func TestGetFunctionType(t *testing.T) {
 				ParamNumInUint64: 5, ResultNumInUint64: 5,
 			},
 		},
+		{
+			name: "reference types",
+			inputFunc: func(uintptr, api.Function) (api.Function, uintptr) {
+				return nil, 0
+			},
+			expectedKind: FunctionKindGoNoContext,
+			expectedType: &FunctionType{
+				Params:           []ValueType{externref, ValueTypeFuncref},
+				Results:          []ValueType{ValueTypeFuncref, externref},
+				ParamNumInUint64: 2, ResultNumInUint64: 2,
+			},
+		},
+		{
+			name: "v128",
+			inputFunc: func(uint32, [2]uint64) [2]uint64 {
+				return [2]uint64{}
+			},
+			expectedKind: FunctionKindGoNoContext,
+			expectedType: &FunctionType{
+				Params:           []ValueType{i32, ValueTypeV128},
+				Results:          []ValueType{ValueTypeV128},
+				ParamNumInUint64: 3, ResultNumInUint64: 2,
+			},
+		},
+		{
+			name:         "error result",
+			inputFunc:    func() error { return nil },
+			expectedKind: FunctionKindGoNoContext,
+			expectedType: &FunctionType{Params: []ValueType{}, Results: []ValueType{}},
+		},
+		{
+			name:         "i32 result and error",
+			inputFunc:    func(uint32) (uint32, error) { return 0, nil },
+			expectedKind: FunctionKindGoNoContext,
+			expectedType: &FunctionType{Params: []ValueType{i32}, Results: []ValueType{i32}, ParamNumInUint64: 1, ResultNumInUint64: 1},
+		},
 		{
 			name:         "all supported params and i32 result - wasm.Module",
 			inputFunc:    func(api.Module, uint32, uint64, float32, float64, uintptr) uint32 { return 0 },
@@ -87,7 +125,7 @@ func TestGetFunctionType(t *testing.T) {
 
 		t.Run(tc.name, func(t *testing.T) {
 			rVal := reflect.ValueOf(tc.inputFunc)
-			fk, ft, err := getFunctionType(&rVal, Features20191205|FeatureMultiValue)
+			fk, ft, err := getFunctionType(&rVal, Features20220419)
 			require.NoError(t, err)
 			require.Equal(t, tc.expectedKind, fk)
 			require.Equal(t, tc.expectedType, ft)
@@ -118,15 +156,60 @@ func TestGetFunctionTypeErrors(t *testing.T) {
 			expectedErr: "result[0] is unsupported: string",
 		},
 		{
-			name:        "error result",
-			input:       func() error { return nil },
-			expectedErr: "result[0] is an error, which is unsupported",
+			name:        "pointer param",
+			input:       func(*string) {},
+			expectedErr: "param[0] is unsupported: ptr",
+		},
+		{
+			name:        "unsafe.Pointer result",
+			input:       func() unsafe.Pointer { return nil },
+			expectedErr: "result[0] is unsupported: unsafe.Pointer",
+		},
+		{
+			name:        "unsupported interface param",
+			input:       func(api.Memory) {},
+			expectedErr: "param[0] is unsupported: interface",
+		},
+		{
+			name:        "error result not last",
+			input:       func() (error, error) { return nil, nil },
+			expectedErr: "result[0] is an error, which is only supported as the last result",
+		},
+		{
+			name:        "multiple results and error - multi-value not enabled",
+			input:       func() (uint64, uint32, error) { return 0, 0, nil },
+			expectedErr: "multiple result types invalid as feature \"multi-value\" is disabled",
 		},
 		{
 			name:        "multiple results - multi-value not enabled",
 			input:       func() (uint64, uint32) { return 0, 0 },
 			expectedErr: "multiple result types invalid as feature \"multi-value\" is disabled",
 		},
+		{
+			name:        "funcref param - reference-types not enabled",
+			input:       func(uint32, api.Function) {},
+			expectedErr: "param[1] funcref invalid as feature \"reference-types\" is disabled",
+		},
+		{
+			name:        "funcref result - reference-types not enabled",
+			input:       func() api.Function { return nil },
+			expectedErr: "result[0] funcref invalid as feature \"reference-types\" is disabled",
+		},
+		{
+			name:        "v128 param - simd not enabled",
+			input:       func(context.Context, [2]uint64) {},
+			expectedErr: "param[1] v128 invalid as feature \"simd\" is disabled",
+		},
+		{
+			name:        "v128 result - simd not enabled",
+			input:       func() [2]uint64 { return [2]uint64{} },
+			expectedErr: "result[0] v128 invalid as feature \"simd\" is disabled",
+		},
+		{
+			name:        "array param other than v128",
+			input:       func([4]uint32) {},
+			expectedErr: "param[0] is unsupported: array",
+		},
 		{
 			name:        "multiple context types",
 			input:       func(api.Module, context.Context) error { return nil },
@@ -142,6 +225,11 @@ func TestGetFunctionTypeErrors(t *testing.T) {
 			input:       func(api.Module, uint64, api.Module) error { return nil },
 			expectedErr: "param[2] is a api.Module, which may be defined only once as param[0]",
 		},
+		{
+			name:        "api.GoModuleFunc",
+			input:       api.GoModuleFunc(func(context.Context, api.Module, []uint64) {}),
+			expectedErr: "api.GoModuleFunc must be exported with its param and result types",
+		},
 	}
 
 	for _, tt := range tests {
@@ -247,6 +335,11 @@ func TestPopGoFuncParams(t *testing.T) {
 			inputFunc: func(context.Context, api.Module, uint32, uint64, float32, float64, uintptr) {},
 			expected:  []uint64{3, 4, 5, 6, 7},
 		},
+		{
+			name:      "v128 param",
+			inputFunc: func(context.Context, uint32, [2]uint64) {},
+			expected:  []uint64{5, 6, 7},
+		},
 	}
 
 	for _, tt := range tests {
@@ -254,10 +347,10 @@ func TestPopGoFuncParams(t *testing.T) {
 
 		t.Run(tc.name, func(t *testing.T) {
 			goFunc := reflect.ValueOf(tc.inputFunc)
-			fk, _, err := getFunctionType(&goFunc, Features20220419)
+			fk, ft, err := getFunctionType(&goFunc, Features20220419)
 			require.NoError(t, err)
 
-			vals := PopGoFuncParams(&FunctionInstance{Kind: fk, GoFunc: &goFunc}, (&stack{stackVals}).pop)
+			vals := PopGoFuncParams(&FunctionInstance{Kind: fk, Type: ft, GoFunc: &goFunc}, (&stack{stackVals}).pop)
 			require.Equal(t, tc.expected, vals)
 		})
 	}
@@ -340,6 +433,36 @@ func TestCallGoFunc(t *testing.T) {
 				api.EncodeF64(400),
 			},
 		},
+		{
+			name: "nil error result",
+			inputFunc: func(x uint32) (uint32, error) {
+				return x + 1, nil
+			},
+			inputParams:     []uint64{1},
+			expectedResults: []uint64{2},
+		},
+		{
+			name: "externref and null funcref params and results",
+			inputFunc: func(v uintptr, w uintptr, x api.Function) (api.Function, uintptr, uintptr) {
+				require.Equal(t, tPtr, v)
+				require.Equal(t, callCtxPtr, w)
+				require.Nil(t, x)
+				return nil, v, w
+			},
+			inputParams:     []uint64{api.EncodeExternref(tPtr), api.EncodeExternref(callCtxPtr), 0},
+			expectedResults: []uint64{0, api.EncodeExternref(tPtr), api.EncodeExternref(callCtxPtr)},
+		},
+		{
+			name: "v128 params and results",
+			inputFunc: func(x uint32, v [2]uint64, w [2]uint64) ([2]uint64, uint32, [2]uint64) {
+				require.Equal(t, uint32(1), x)
+				require.Equal(t, [2]uint64{2, 3}, v)
+				require.Equal(t, [2]uint64{4, math.MaxUint64}, w)
+				return w, x, v
+			},
+			inputParams:     []uint64{1, 2, 3, 4, math.MaxUint64},
+			expectedResults: []uint64{4, math.MaxUint64, 1, 2, 3},
+		},
 		{
 			name: "all supported params and i32 result - wasm.Module",
 			inputFunc: func(m api.Module, v uintptr, w uint32, x uint64, y float32, z float64) uint32 {
@@ -415,3 +538,91 @@ func TestCallGoFunc(t *testing.T) {
 		})
 	}
 }
+
+func TestCallGoModuleFunc(t *testing.T) {
+	callCtx := &CallContext{}
+
+	tests := []struct {
+		name                         string
+		params, results              []ValueType
+		inputParams, expectedResults []uint64
+	}{
+		{
+			name: "nullary",
+		},
+		{
+			name:            "more params than results",
+			params:          []ValueType{ValueTypeI32, ValueTypeI32},
+			results:         []ValueType{ValueTypeI32},
+			inputParams:     []uint64{1, 2},
+			expectedResults: []uint64{3},
+		},
+		{
+			name:            "more results than params",
+			params:          []ValueType{ValueTypeI32},
+			results:         []ValueType{ValueTypeI32, ValueTypeI64, ValueTypeV128},
+			inputParams:     []uint64{1},
+			expectedResults: []uint64{1, 2, 3, 4},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			ft := &FunctionType{Params: tc.params, Results: tc.results}
+			ft.CacheNumInUint64()
+			f := &FunctionInstance{
+				Kind: FunctionKindGoModuleFunc,
+				Type: ft,
+				GoModuleFunc: func(ctx context.Context, m api.Module, stack []uint64) {
+					require.Equal(t, testCtx, ctx)
+					require.Equal(t, callCtx, m)
+					require.Equal(t, tc.inputParams, stack[:len(tc.inputParams)])
+					sum := uint64(0)
+					for _, p := range tc.inputParams {
+						sum += p
+					}
+					for i := range tc.expectedResults {
+						stack[i] = sum + uint64(i)
+					}
+				},
+			}
+
+			// CallGoFunc delegates to CallGoModuleFunc, which must not write over the params.
+			params := append([]uint64(nil), tc.inputParams...)
+			results := CallGoFunc(testCtx, callCtx, f, params)
+			require.Equal(t, tc.expectedResults, results)
+			require.Equal(t, tc.inputParams, params)
+		})
+	}
+}
+
+func TestCallGoFunc_Error(t *testing.T) {
+	expectedErr := errors.New("ice cream")
+	goFunc := reflect.ValueOf(func(x uint32) (uint32, error) {
+		return x, expectedErr
+	})
+	fk, _, err := getFunctionType(&goFunc, Features20220419)
+	require.NoError(t, err)
+
+	defer func() {
+		// The error is wrapped so that it isn't formatted as if the host function panicked.
+		require.Equal(t, &wasmruntime.HostFunctionError{Err: expectedErr}, recover())
+	}()
+	CallGoFunc(testCtx, &CallContext{}, &FunctionInstance{Kind: fk, GoFunc: &goFunc}, []uint64{1})
+	t.Fatal("expected a panic")
+}
+
+func TestCallGoFunc_FunctionOfAnotherEngine(t *testing.T) {
+	other := &FunctionInstance{Module: &ModuleInstance{Engine: &mockModuleEngine{engine: &mockEngine{}}}}
+	goFunc := reflect.ValueOf(func() api.Function {
+		return other
+	})
+	fk, _, err := getFunctionType(&goFunc, Features20220419)
+	require.NoError(t, err)
+
+	f := &FunctionInstance{Kind: fk, GoFunc: &goFunc, Module: &ModuleInstance{Engine: &mockModuleEngine{engine: &mockEngine{}}}}
+	err = require.CapturePanic(func() { CallGoFunc(testCtx, &CallContext{}, f, nil) })
+	require.EqualError(t, err, "result[0] is not a function of this runtime")
+}
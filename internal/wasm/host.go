@@ -6,6 +6,7 @@ import (
 	"sort"
 	"strings"
 
+	"github.com/tetratelabs/wazero/api"
 	"github.com/tetratelabs/wazero/experimental"
 	"github.com/tetratelabs/wazero/internal/wasmdebug"
 )
@@ -54,7 +55,7 @@ func NewHostModule(
 	}
 
 	if memoryCount > 0 {
-		if err = addMemory(m, nameToMemory); err != nil {
+		if err = addMemory(m, nameToMemory, enabledFeatures); err != nil {
 			return
 		}
 	}
@@ -94,8 +95,16 @@ func addFuncs(m *Module, nameToGoFunc map[string]interface{}, enabledFeatures Fe
 
 	for idx := Index(0); idx < funcCount; idx++ {
 		name := funcNames[idx]
-		fn := reflect.ValueOf(nameToGoFunc[name])
-		_, functionType, err := getFunctionType(&fn, enabledFeatures)
+		var fn reflect.Value
+		var functionType *FunctionType
+		var err error
+		if hf, ok := nameToGoFunc[name].(*HostFunc); ok {
+			fn = reflect.ValueOf(hf.Code)
+			functionType, err = getHostFuncType(hf, enabledFeatures)
+		} else {
+			fn = reflect.ValueOf(nameToGoFunc[name])
+			_, functionType, err = getFunctionType(&fn, enabledFeatures)
+		}
 		if err != nil {
 			return fmt.Errorf("func[%s] %w", name, err)
 		}
@@ -108,30 +117,30 @@ func addFuncs(m *Module, nameToGoFunc map[string]interface{}, enabledFeatures Fe
 	return nil
 }
 
-func addMemory(m *Module, nameToMemory map[string]*Memory) error {
+func addMemory(m *Module, nameToMemory map[string]*Memory, enabledFeatures Features) error {
 	memoryCount := uint32(len(nameToMemory))
 
-	// Only one memory can be defined or imported
-	if memoryCount > 1 {
-		memoryNames := make([]string, 0, memoryCount)
-		for k := range nameToMemory {
-			memoryNames = append(memoryNames, k)
-		}
-		sort.Strings(memoryNames) // For consistent error messages
+	// Sort names for consistent iteration
+	memoryNames := make([]string, 0, memoryCount)
+	for k := range nameToMemory {
+		memoryNames = append(memoryNames, k)
+	}
+	sort.Strings(memoryNames)
+
+	// Only one memory can be defined or imported unless FeatureMultiMemory is enabled.
+	if memoryCount > 1 && !enabledFeatures.Get(FeatureMultiMemory) {
 		return fmt.Errorf("only one memory is allowed, but configured: %s", strings.Join(memoryNames, ", "))
 	}
 
-	// Find the memory name to export.
-	var name string
-	for k, v := range nameToMemory {
-		name = k
+	m.MemorySection = make([]*Memory, 0, memoryCount)
+	for idx, name := range memoryNames {
+		v := nameToMemory[name]
 		if v.Min > v.Max {
 			return fmt.Errorf("memory[%s] min %d pages (%s) > max %d pages (%s)", name, v.Min, PagesToUnitOfBytes(v.Min), v.Max, PagesToUnitOfBytes(v.Max))
 		}
-		m.MemorySection = v
+		m.MemorySection = append(m.MemorySection, v)
+		m.ExportSection = append(m.ExportSection, &Export{Type: ExternTypeMemory, Name: name, Index: Index(idx)})
 	}
-
-	m.ExportSection = append(m.ExportSection, &Export{Type: ExternTypeMemory, Name: name, Index: 0})
 	return nil
 }
 
@@ -179,6 +188,9 @@ func (m *Module) buildHostFunctions(
 			GoFunc: fn,
 			Idx:    Index(idx),
 		}
+		if f.Kind == FunctionKindGoModuleFunc {
+			f.GoModuleFunc = fn.Interface().(api.GoModuleFunc)
+		}
 		name := functionNames[f.Idx].Name
 		f.moduleName = moduleName
 		f.DebugName = wasmdebug.FuncName(moduleName, name, f.Idx)
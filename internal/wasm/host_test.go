@@ -1,6 +1,7 @@
 package wasm
 
 import (
+	"context"
 	"reflect"
 	"testing"
 
@@ -30,7 +31,7 @@ func swap(x, y uint32) (uint32, uint32) {
 }
 
 func TestNewHostModule(t *testing.T) {
-	i32 := ValueTypeI32
+	i32, i64 := ValueTypeI32, ValueTypeI64
 
 	a := wasiAPI{}
 	functionArgsSizesGet := "args_sizes_get"
@@ -39,6 +40,11 @@ func TestNewHostModule(t *testing.T) {
 	fnFdWrite := reflect.ValueOf(a.FdWrite)
 	functionSwap := "swap"
 	fnSwap := reflect.ValueOf(swap)
+	functionAdd := "add"
+	add := api.GoModuleFunc(func(ctx context.Context, m api.Module, stack []uint64) {
+		stack[0] += stack[1]
+	})
+	fnAdd := reflect.ValueOf(add)
 
 	tests := []struct {
 		name, moduleName string
@@ -97,11 +103,25 @@ func TestNewHostModule(t *testing.T) {
 				NameSection:         &NameSection{ModuleName: "swapper", FunctionNames: NameMap{{Index: 0, Name: "swap"}}},
 			},
 		},
+		{
+			name:       "api.GoModuleFunc",
+			moduleName: "adder",
+			nameToGoFunc: map[string]interface{}{
+				functionAdd: &HostFunc{ParamTypes: []ValueType{i64, i64}, ResultTypes: []ValueType{i64}, Code: add},
+			},
+			expected: &Module{
+				TypeSection:         []*FunctionType{{Params: []ValueType{i64, i64}, Results: []ValueType{i64}, ParamNumInUint64: 2, ResultNumInUint64: 1}},
+				FunctionSection:     []Index{0},
+				HostFunctionSection: []*reflect.Value{&fnAdd},
+				ExportSection:       []*Export{{Name: "add", Type: ExternTypeFunc, Index: 0}},
+				NameSection:         &NameSection{ModuleName: "adder", FunctionNames: NameMap{{Index: 0, Name: "add"}}},
+			},
+		},
 		{
 			name:         "memory",
 			nameToMemory: map[string]*Memory{"memory": {Min: 1, Max: 2}},
 			expected: &Module{
-				MemorySection: &Memory{Min: 1, Max: 2},
+				MemorySection: []*Memory{{Min: 1, Max: 2}},
 				ExportSection: []*Export{{Name: "memory", Type: ExternTypeMemory, Index: 0}},
 			},
 		},
@@ -161,7 +181,7 @@ func TestNewHostModule(t *testing.T) {
 						Init: &ConstantExpression{Opcode: OpcodeI32Const, Data: const1},
 					},
 				},
-				MemorySection: &Memory{Min: 1, Max: 1},
+				MemorySection: []*Memory{{Min: 1, Max: 1}},
 				ExportSection: []*Export{
 					{Name: "args_sizes_get", Type: ExternTypeFunc, Index: 0},
 					{Name: "memory", Type: ExternTypeMemory, Index: 0},
@@ -235,6 +255,35 @@ func TestNewHostModule_Errors(t *testing.T) {
 			nameToMemory: map[string]*Memory{"mem": {Min: 1, Max: 1}},
 			expectedErr:  "func[fn] multiple result types invalid as feature \"multi-value\" is disabled",
 		},
+		{
+			name: "api.GoModuleFunc has multiple results",
+			nameToGoFunc: map[string]interface{}{"fn": &HostFunc{
+				ResultTypes: []ValueType{ValueTypeI32, ValueTypeI32},
+				Code:        func(context.Context, api.Module, []uint64) {},
+			}},
+			expectedErr: "func[fn] multiple result types invalid as feature \"multi-value\" is disabled",
+		},
+		{
+			name: "api.GoModuleFunc has disabled param type",
+			nameToGoFunc: map[string]interface{}{"fn": &HostFunc{
+				ParamTypes: []ValueType{ValueTypeI32, ValueTypeV128},
+				Code:       func(context.Context, api.Module, []uint64) {},
+			}},
+			expectedErr: "func[fn] param[1] v128 invalid as feature \"simd\" is disabled",
+		},
+		{
+			name: "api.GoModuleFunc has unsupported result type",
+			nameToGoFunc: map[string]interface{}{"fn": &HostFunc{
+				ResultTypes: []ValueType{0x10},
+				Code:        func(context.Context, api.Module, []uint64) {},
+			}},
+			expectedErr: "func[fn] result[0] is unsupported: 0x10",
+		},
+		{
+			name:         "api.GoModuleFunc is nil",
+			nameToGoFunc: map[string]interface{}{"fn": &HostFunc{}},
+			expectedErr:  "func[fn] code is nil",
+		},
 		{
 			name:         "func collides on memory name",
 			nameToGoFunc: map[string]interface{}{"fn": ArgsSizesGet},
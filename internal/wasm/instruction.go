@@ -43,6 +43,27 @@ const (
 	OpcodeCall         Opcode = 0x10
 	OpcodeCallIndirect Opcode = 0x11
 
+	// Below are toggled with FeatureTailCall
+
+	OpcodeReturnCall         Opcode = 0x12
+	OpcodeReturnCallIndirect Opcode = 0x13
+
+	// Below are toggled with FeatureExceptionHandling
+
+	// OpcodeTry brackets a sequence of instructions like OpcodeBlock, whose exceptions are handled by the following
+	// OpcodeCatch or OpcodeCatchAll, or delegated to an outer block by OpcodeDelegate.
+	OpcodeTry Opcode = 0x06
+	// OpcodeCatch starts the handler of exceptions of the given tag, which are pushed onto the stack.
+	OpcodeCatch Opcode = 0x07
+	// OpcodeThrow raises an exception of the given tag with the values popped from the stack.
+	OpcodeThrow Opcode = 0x08
+	// OpcodeRethrow raises the exception caught by the enclosing OpcodeCatch or OpcodeCatchAll of the given label.
+	OpcodeRethrow Opcode = 0x09
+	// OpcodeDelegate terminates an OpcodeTry without handlers, and hands its exceptions to the block of the given label.
+	OpcodeDelegate Opcode = 0x18
+	// OpcodeCatchAll starts the handler of exceptions of any tag.
+	OpcodeCatchAll Opcode = 0x19
+
 	// parametric instructions
 
 	OpcodeDrop        Opcode = 0x1a
@@ -281,6 +302,10 @@ const (
 	// OpcodeVecPrefix is the prefix of all vector isntructions introduced in
 	// FeatureSIMD.
 	OpcodeVecPrefix Opcode = 0xfd
+
+	// OpcodeAtomicPrefix is the prefix of all atomic instructions introduced in
+	// FeatureThreads.
+	OpcodeAtomicPrefix Opcode = 0xfe
 )
 
 // OpcodeMisc represents opcodes of the miscellaneous operations.
@@ -330,12 +355,12 @@ const (
 	// Loads and stores.
 
 	OpcodeVecV128Load        OpcodeVec = 0x00
-	OpcodeVecV128Load8x8_s   OpcodeVec = 0x01
-	OpcodeVecV128Load8x8_u   OpcodeVec = 0x02
-	OpcodeVecV128Load16x4_s  OpcodeVec = 0x03
-	OpcodeVecV128Load16x4_u  OpcodeVec = 0x04
-	OpcodeVecV128Load32x2_s  OpcodeVec = 0x05
-	OpcodeVecV128Load32x2_u  OpcodeVec = 0x06
+	OpcodeVecV128Load8x8S    OpcodeVec = 0x01
+	OpcodeVecV128Load8x8U    OpcodeVec = 0x02
+	OpcodeVecV128Load16x4S   OpcodeVec = 0x03
+	OpcodeVecV128Load16x4U   OpcodeVec = 0x04
+	OpcodeVecV128Load32x2S   OpcodeVec = 0x05
+	OpcodeVecV128Load32x2U   OpcodeVec = 0x06
 	OpcodeVecV128Load8Splat  OpcodeVec = 0x07
 	OpcodeVecV128Load16Splat OpcodeVec = 0x08
 	OpcodeVecV128Load32Splat OpcodeVec = 0x09
@@ -487,7 +512,7 @@ const (
 	OpcodeVecI8x16MinU    OpcodeVec = 0x77
 	OpcodeVecI8x16MaxS    OpcodeVec = 0x78
 	OpcodeVecI8x16MaxU    OpcodeVec = 0x79
-	OpcodeVecI8x16ArgrU   OpcodeVec = 0x7b
+	OpcodeVecI8x16AvgrU   OpcodeVec = 0x7b
 
 	// i16 misc.
 
@@ -495,7 +520,7 @@ const (
 	OpcodeVecI16x8ExtaddPairwiseI8x16U OpcodeVec = 0x7d
 	OpcodeVecI16x8Abs                  OpcodeVec = 0x80
 	OpcodeVecI16x8Neg                  OpcodeVec = 0x81
-	OpcodeVecI16x8Q16mulrSatS          OpcodeVec = 0x82
+	OpcodeVecI16x8Q15mulrSatS          OpcodeVec = 0x82
 	OpcodeVecI16x8AllTrue              OpcodeVec = 0x83
 	OpcodeVecI16x8BitMask              OpcodeVec = 0x84
 	OpcodeVecI16x8NarrowI32x4S         OpcodeVec = 0x85
@@ -518,7 +543,7 @@ const (
 	OpcodeVecI16x8MinU                 OpcodeVec = 0x97
 	OpcodeVecI16x8MaxS                 OpcodeVec = 0x98
 	OpcodeVecI16x8MaxU                 OpcodeVec = 0x99
-	OpcodeVecI16x8ArgrU                OpcodeVec = 0x9b
+	OpcodeVecI16x8AvgrU                OpcodeVec = 0x9b
 	OpcodeVecI16x8ExtMulLowI8x16S      OpcodeVec = 0x9c
 	OpcodeVecI16x8ExtMulHighI8x16S     OpcodeVec = 0x9d
 	OpcodeVecI16x8ExtMulLowI8x16U      OpcodeVec = 0x9e
@@ -593,21 +618,21 @@ const (
 
 	// f64 misc.
 
-	OpcodeVecF64x4Ceil    OpcodeVec = 0x74
-	OpcodeVecF64x4Floor   OpcodeVec = 0x75
-	OpcodeVecF64x4Trunc   OpcodeVec = 0x7a
-	OpcodeVecF64x4Nearest OpcodeVec = 0x94
-	OpcodeVecF64x4Abs     OpcodeVec = 0xec
-	OpcodeVecF64x4Neg     OpcodeVec = 0xed
-	OpcodeVecF64x4Sqrt    OpcodeVec = 0xef
-	OpcodeVecF64x4Add     OpcodeVec = 0xf0
-	OpcodeVecF64x4Sub     OpcodeVec = 0xf1
-	OpcodeVecF64x4Mul     OpcodeVec = 0xf2
-	OpcodeVecF64x4Div     OpcodeVec = 0xf3
-	OpcodeVecF64x4Min     OpcodeVec = 0xf4
-	OpcodeVecF64x4Max     OpcodeVec = 0xf5
-	OpcodeVecF64x4Pmin    OpcodeVec = 0xf6
-	OpcodeVecF64x4Pmax    OpcodeVec = 0xf7
+	OpcodeVecF64x2Ceil    OpcodeVec = 0x74
+	OpcodeVecF64x2Floor   OpcodeVec = 0x75
+	OpcodeVecF64x2Trunc   OpcodeVec = 0x7a
+	OpcodeVecF64x2Nearest OpcodeVec = 0x94
+	OpcodeVecF64x2Abs     OpcodeVec = 0xec
+	OpcodeVecF64x2Neg     OpcodeVec = 0xed
+	OpcodeVecF64x2Sqrt    OpcodeVec = 0xef
+	OpcodeVecF64x2Add     OpcodeVec = 0xf0
+	OpcodeVecF64x2Sub     OpcodeVec = 0xf1
+	OpcodeVecF64x2Mul     OpcodeVec = 0xf2
+	OpcodeVecF64x2Div     OpcodeVec = 0xf3
+	OpcodeVecF64x2Min     OpcodeVec = 0xf4
+	OpcodeVecF64x2Max     OpcodeVec = 0xf5
+	OpcodeVecF64x2Pmin    OpcodeVec = 0xf6
+	OpcodeVecF64x2Pmax    OpcodeVec = 0xf7
 
 	// conversions.
 
@@ -617,8 +642,8 @@ const (
 	OpcodeVecF32x4ConvertI32x4U       OpcodeVec = 0xfb
 	OpcodeVecI32x4TruncSatF64x2SZero  OpcodeVec = 0xfc
 	OpcodeVecI32x4TruncSatF64x2UZero  OpcodeVec = 0xfd
-	OpcodeVecF64x2ConvertI32x4S       OpcodeVec = 0xfe
-	OpcodeVecF64x2ConvertI32x4U       OpcodeVec = 0xff
+	OpcodeVecF64x2ConvertLowI32x4S    OpcodeVec = 0xfe
+	OpcodeVecF64x2ConvertLowI32x4U    OpcodeVec = 0xff
 	OpcodeVecF32x4DemoteF64x2Zero     OpcodeVec = 0x5e
 	OpcodeVecF64x2PromoteLowF32x4Zero OpcodeVec = 0x5f
 )
@@ -805,6 +830,20 @@ const (
 	OpcodeTableGetName = "table.get"
 	OpcodeTableSetName = "table.set"
 
+	// Below are toggled with FeatureTailCall
+
+	OpcodeReturnCallName         = "return_call"
+	OpcodeReturnCallIndirectName = "return_call_indirect"
+
+	// Below are toggled with FeatureExceptionHandling
+
+	OpcodeTryName      = "try"
+	OpcodeCatchName    = "catch"
+	OpcodeThrowName    = "throw"
+	OpcodeRethrowName  = "rethrow"
+	OpcodeDelegateName = "delegate"
+	OpcodeCatchAllName = "catch_all"
+
 	// Below are toggled with FeatureSignExtensionOps
 
 	OpcodeI32Extend8SName  = "i32.extend8_s"
@@ -813,8 +852,9 @@ const (
 	OpcodeI64Extend16SName = "i64.extend16_s"
 	OpcodeI64Extend32SName = "i64.extend32_s"
 
-	OpcodeMiscPrefixName = "misc_prefix"
-	OpcodeVecPrefixName  = "vector_prefix"
+	OpcodeMiscPrefixName   = "misc_prefix"
+	OpcodeVecPrefixName    = "vector_prefix"
+	OpcodeAtomicPrefixName = "atomic_prefix"
 )
 
 var instructionNames = [256]string{
@@ -999,6 +1039,20 @@ var instructionNames = [256]string{
 	OpcodeTableGet: OpcodeTableGetName,
 	OpcodeTableSet: OpcodeTableSetName,
 
+	// Below are toggled with FeatureTailCall
+
+	OpcodeReturnCall:         OpcodeReturnCallName,
+	OpcodeReturnCallIndirect: OpcodeReturnCallIndirectName,
+
+	// Below are toggled with FeatureExceptionHandling
+
+	OpcodeTry:      OpcodeTryName,
+	OpcodeCatch:    OpcodeCatchName,
+	OpcodeThrow:    OpcodeThrowName,
+	OpcodeRethrow:  OpcodeRethrowName,
+	OpcodeDelegate: OpcodeDelegateName,
+	OpcodeCatchAll: OpcodeCatchAllName,
+
 	// Below are toggled with FeatureSignExtensionOps
 
 	OpcodeI32Extend8S:  OpcodeI32Extend8SName,
@@ -1007,8 +1061,9 @@ var instructionNames = [256]string{
 	OpcodeI64Extend16S: OpcodeI64Extend16SName,
 	OpcodeI64Extend32S: OpcodeI64Extend32SName,
 
-	OpcodeMiscPrefix: OpcodeMiscPrefixName,
-	OpcodeVecPrefix:  OpcodeVecPrefixName,
+	OpcodeMiscPrefix:   OpcodeMiscPrefixName,
+	OpcodeVecPrefix:    OpcodeVecPrefixName,
+	OpcodeAtomicPrefix: OpcodeAtomicPrefixName,
 }
 
 // InstructionName returns the instruction corresponding to this binary Opcode.
@@ -1068,12 +1123,12 @@ func MiscInstructionName(oc OpcodeMisc) string {
 
 const (
 	OpcodeVecV128LoadName                  = "v128.load"
-	OpcodeVecV128Load8x8_sName             = "v128.load8x8_s"
-	OpcodeVecV128Load8x8_uName             = "v128.load8x8_u"
-	OpcodeVecV128Load16x4_sName            = "v128.load16x4_s"
-	OpcodeVecV128Load16x4_uName            = "v128.load16x4_u"
-	OpcodeVecV128Load32x2_sName            = "v128.load32x2_s"
-	OpcodeVecV128Load32x2_uName            = "v128.load32x2_u"
+	OpcodeVecV128Load8x8SName              = "v128.load8x8_s"
+	OpcodeVecV128Load8x8UName              = "v128.load8x8_u"
+	OpcodeVecV128Load16x4SName             = "v128.load16x4_s"
+	OpcodeVecV128Load16x4UName             = "v128.load16x4_u"
+	OpcodeVecV128Load32x2SName             = "v128.load32x2_s"
+	OpcodeVecV128Load32x2UName             = "v128.load32x2_u"
 	OpcodeVecV128Load8SplatName            = "v128.load8_splat"
 	OpcodeVecV128Load16SplatName           = "v128.load16_splat"
 	OpcodeVecV128Load32SplatName           = "v128.load32_splat"
@@ -1090,13 +1145,13 @@ const (
 	OpcodeVecV128Store32LaneName           = "v128.store32_lane"
 	OpcodeVecV128Store64LaneName           = "v128.store64_lane"
 	OpcodeVecV128ConstName                 = "v128.const"
-	OpcodeVecV128i8x16ShuffleName          = "v128.shuffle"
+	OpcodeVecV128i8x16ShuffleName          = "i8x16.shuffle"
 	OpcodeVecI8x16ExtractLaneSName         = "i8x16.extract_lane_s"
 	OpcodeVecI8x16ExtractLaneUName         = "i8x16.extract_lane_u"
 	OpcodeVecI8x16ReplaceLaneName          = "i8x16.replace_lane"
-	OpcodeVecI16x8ExtractLaneSName         = "i16x4.extract_lane_s"
-	OpcodeVecI16x8ExtractLaneUName         = "i16x4.extract_lane_u"
-	OpcodeVecI16x8ReplaceLaneName          = "i16x4.replace"
+	OpcodeVecI16x8ExtractLaneSName         = "i16x8.extract_lane_s"
+	OpcodeVecI16x8ExtractLaneUName         = "i16x8.extract_lane_u"
+	OpcodeVecI16x8ReplaceLaneName          = "i16x8.replace_lane"
 	OpcodeVecI32x4ExtractLaneName          = "i32x4.extract_lane"
 	OpcodeVecI32x4ReplaceLaneName          = "i32x4.replace_lane"
 	OpcodeVecI64x2ExtractLaneName          = "i64x2.extract_lane"
@@ -1144,10 +1199,10 @@ const (
 	OpcodeVecI32x4GeUName                  = "i32x4.ge_u"
 	OpcodeVecI64x2EqName                   = "i64x2.eq"
 	OpcodeVecI64x2NeName                   = "i64x2.ne"
-	OpcodeVecI64x2LtSName                  = "i64x2.lt"
-	OpcodeVecI64x2GtSName                  = "i64x2.gt"
-	OpcodeVecI64x2LeSName                  = "i64x2.le"
-	OpcodeVecI64x2GeSName                  = "i64x2.ge"
+	OpcodeVecI64x2LtSName                  = "i64x2.lt_s"
+	OpcodeVecI64x2GtSName                  = "i64x2.gt_s"
+	OpcodeVecI64x2LeSName                  = "i64x2.le_s"
+	OpcodeVecI64x2GeSName                  = "i64x2.ge_s"
 	OpcodeVecF32x4EqName                   = "f32x4.eq"
 	OpcodeVecF32x4NeName                   = "f32x4.ne"
 	OpcodeVecF32x4LtName                   = "f32x4.lt"
@@ -1181,18 +1236,18 @@ const (
 	OpcodeVecI8x16AddSatSName              = "i8x16.add_sat_s"
 	OpcodeVecI8x16AddSatUName              = "i8x16.add_sat_u"
 	OpcodeVecI8x16SubName                  = "i8x16.sub"
-	OpcodeVecI8x16SubSatSName              = "i8x16.sub_s"
-	OpcodeVecI8x16SubSatUName              = "i8x16.sub_u"
+	OpcodeVecI8x16SubSatSName              = "i8x16.sub_sat_s"
+	OpcodeVecI8x16SubSatUName              = "i8x16.sub_sat_u"
 	OpcodeVecI8x16MinSName                 = "i8x16.min_s"
 	OpcodeVecI8x16MinUName                 = "i8x16.min_u"
 	OpcodeVecI8x16MaxSName                 = "i8x16.max_s"
 	OpcodeVecI8x16MaxUName                 = "i8x16.max_u"
-	OpcodeVecI8x16ArgrUName                = "i8x16.argr_u"
+	OpcodeVecI8x16AvgrUName                = "i8x16.avgr_u"
 	OpcodeVecI16x8ExtaddPairwiseI8x16SName = "i16x8.extadd_pairwise_i8x16_s"
 	OpcodeVecI16x8ExtaddPairwiseI8x16UName = "i16x8.extadd_pairwise_i8x16_u"
 	OpcodeVecI16x8AbsName                  = "i16x8.abs"
 	OpcodeVecI16x8NegName                  = "i16x8.neg"
-	OpcodeVecI16x8Q16mulrSatSName          = "i16x8.q15mulr_sat_s"
+	OpcodeVecI16x8Q15mulrSatSName          = "i16x8.q15mulr_sat_s"
 	OpcodeVecI16x8AllTrueName              = "i16x8.all_true"
 	OpcodeVecI16x8BitMaskName              = "i16x8.bitmask"
 	OpcodeVecI16x8NarrowI32x4SName         = "i16x8.narrow_i32x4_s"
@@ -1215,7 +1270,7 @@ const (
 	OpcodeVecI16x8MinUName                 = "i16x8.min_u"
 	OpcodeVecI16x8MaxSName                 = "i16x8.max_s"
 	OpcodeVecI16x8MaxUName                 = "i16x8.max_u"
-	OpcodeVecI16x8ArgrUName                = "i16x8.argr_u"
+	OpcodeVecI16x8AvgrUName                = "i16x8.avgr_u"
 	OpcodeVecI16x8ExtMulLowI8x16SName      = "i16x8.extmul_low_i8x16_s"
 	OpcodeVecI16x8ExtMulHighI8x16SName     = "i16x8.extmul_high_i8x16_s"
 	OpcodeVecI16x8ExtMulLowI8x16UName      = "i16x8.extmul_low_i8x16_u"
@@ -1278,41 +1333,41 @@ const (
 	OpcodeVecF32x4MaxName                  = "f32x4.max"
 	OpcodeVecF32x4PminName                 = "f32x4.pmin"
 	OpcodeVecF32x4PmaxName                 = "f32x4.pmax"
-	OpcodeVecF64x4CeilName                 = "f64x2.ceil"
-	OpcodeVecF64x4FloorName                = "f64x2.floor"
-	OpcodeVecF64x4TruncName                = "f64x2.trunc"
-	OpcodeVecF64x4NearestName              = "f64x2.nearest"
-	OpcodeVecF64x4AbsName                  = "f64x2.abs"
-	OpcodeVecF64x4NegName                  = "f64x2.neg"
-	OpcodeVecF64x4SqrtName                 = "f64x2.sqrt"
-	OpcodeVecF64x4AddName                  = "f64x2.add"
-	OpcodeVecF64x4SubName                  = "f64x2.sub"
-	OpcodeVecF64x4MulName                  = "f64x2.mul"
-	OpcodeVecF64x4DivName                  = "f64x2.div"
-	OpcodeVecF64x4MinName                  = "f64x2.min"
-	OpcodeVecF64x4MaxName                  = "f64x2.max"
-	OpcodeVecF64x4PminName                 = "f64x2.pmin"
-	OpcodeVecF64x4PmaxName                 = "f64x2.pmax"
+	OpcodeVecF64x2CeilName                 = "f64x2.ceil"
+	OpcodeVecF64x2FloorName                = "f64x2.floor"
+	OpcodeVecF64x2TruncName                = "f64x2.trunc"
+	OpcodeVecF64x2NearestName              = "f64x2.nearest"
+	OpcodeVecF64x2AbsName                  = "f64x2.abs"
+	OpcodeVecF64x2NegName                  = "f64x2.neg"
+	OpcodeVecF64x2SqrtName                 = "f64x2.sqrt"
+	OpcodeVecF64x2AddName                  = "f64x2.add"
+	OpcodeVecF64x2SubName                  = "f64x2.sub"
+	OpcodeVecF64x2MulName                  = "f64x2.mul"
+	OpcodeVecF64x2DivName                  = "f64x2.div"
+	OpcodeVecF64x2MinName                  = "f64x2.min"
+	OpcodeVecF64x2MaxName                  = "f64x2.max"
+	OpcodeVecF64x2PminName                 = "f64x2.pmin"
+	OpcodeVecF64x2PmaxName                 = "f64x2.pmax"
 	OpcodeVecI32x4TruncSatF32x4SName       = "i32x4.trunc_sat_f32x4_s"
 	OpcodeVecI32x4TruncSatF32x4UName       = "i32x4.trunc_sat_f32x4_u"
 	OpcodeVecF32x4ConvertI32x4SName        = "f32x4.convert_i32x4_s"
 	OpcodeVecF32x4ConvertI32x4UName        = "f32x4.convert_i32x4_u"
 	OpcodeVecI32x4TruncSatF64x2SZeroName   = "i32x4.trunc_sat_f64x2_s_zero"
 	OpcodeVecI32x4TruncSatF64x2UZeroName   = "i32x4.trunc_sat_f64x2_u_zero"
-	OpcodeVecF64x2ConvertI32x4SName        = "f64x2.convert_low_i32x4_s"
-	OpcodeVecF64x2ConvertI32x4UName        = "f64x2.convert_low_i32x4_u"
+	OpcodeVecF64x2ConvertLowI32x4SName     = "f64x2.convert_low_i32x4_s"
+	OpcodeVecF64x2ConvertLowI32x4UName     = "f64x2.convert_low_i32x4_u"
 	OpcodeVecF32x4DemoteF64x2ZeroName      = "f32x4.demote_f64x2_zero"
 	OpcodeVecF64x2PromoteLowF32x4ZeroName  = "f64x2.promote_low_f32x4"
 )
 
 var vectorInstructionName = map[OpcodeVec]string{
 	OpcodeVecV128Load:                  OpcodeVecV128LoadName,
-	OpcodeVecV128Load8x8_s:             OpcodeVecV128Load8x8_sName,
-	OpcodeVecV128Load8x8_u:             OpcodeVecV128Load8x8_uName,
-	OpcodeVecV128Load16x4_s:            OpcodeVecV128Load16x4_sName,
-	OpcodeVecV128Load16x4_u:            OpcodeVecV128Load16x4_uName,
-	OpcodeVecV128Load32x2_s:            OpcodeVecV128Load32x2_sName,
-	OpcodeVecV128Load32x2_u:            OpcodeVecV128Load32x2_uName,
+	OpcodeVecV128Load8x8S:              OpcodeVecV128Load8x8SName,
+	OpcodeVecV128Load8x8U:              OpcodeVecV128Load8x8UName,
+	OpcodeVecV128Load16x4S:             OpcodeVecV128Load16x4SName,
+	OpcodeVecV128Load16x4U:             OpcodeVecV128Load16x4UName,
+	OpcodeVecV128Load32x2S:             OpcodeVecV128Load32x2SName,
+	OpcodeVecV128Load32x2U:             OpcodeVecV128Load32x2UName,
 	OpcodeVecV128Load8Splat:            OpcodeVecV128Load8SplatName,
 	OpcodeVecV128Load16Splat:           OpcodeVecV128Load16SplatName,
 	OpcodeVecV128Load32Splat:           OpcodeVecV128Load32SplatName,
@@ -1426,12 +1481,12 @@ var vectorInstructionName = map[OpcodeVec]string{
 	OpcodeVecI8x16MinU:                 OpcodeVecI8x16MinUName,
 	OpcodeVecI8x16MaxS:                 OpcodeVecI8x16MaxSName,
 	OpcodeVecI8x16MaxU:                 OpcodeVecI8x16MaxUName,
-	OpcodeVecI8x16ArgrU:                OpcodeVecI8x16ArgrUName,
+	OpcodeVecI8x16AvgrU:                OpcodeVecI8x16AvgrUName,
 	OpcodeVecI16x8ExtaddPairwiseI8x16S: OpcodeVecI16x8ExtaddPairwiseI8x16SName,
 	OpcodeVecI16x8ExtaddPairwiseI8x16U: OpcodeVecI16x8ExtaddPairwiseI8x16UName,
 	OpcodeVecI16x8Abs:                  OpcodeVecI16x8AbsName,
 	OpcodeVecI16x8Neg:                  OpcodeVecI16x8NegName,
-	OpcodeVecI16x8Q16mulrSatS:          OpcodeVecI16x8Q16mulrSatSName,
+	OpcodeVecI16x8Q15mulrSatS:          OpcodeVecI16x8Q15mulrSatSName,
 	OpcodeVecI16x8AllTrue:              OpcodeVecI16x8AllTrueName,
 	OpcodeVecI16x8BitMask:              OpcodeVecI16x8BitMaskName,
 	OpcodeVecI16x8NarrowI32x4S:         OpcodeVecI16x8NarrowI32x4SName,
@@ -1454,7 +1509,7 @@ var vectorInstructionName = map[OpcodeVec]string{
 	OpcodeVecI16x8MinU:                 OpcodeVecI16x8MinUName,
 	OpcodeVecI16x8MaxS:                 OpcodeVecI16x8MaxSName,
 	OpcodeVecI16x8MaxU:                 OpcodeVecI16x8MaxUName,
-	OpcodeVecI16x8ArgrU:                OpcodeVecI16x8ArgrUName,
+	OpcodeVecI16x8AvgrU:                OpcodeVecI16x8AvgrUName,
 	OpcodeVecI16x8ExtMulLowI8x16S:      OpcodeVecI16x8ExtMulLowI8x16SName,
 	OpcodeVecI16x8ExtMulHighI8x16S:     OpcodeVecI16x8ExtMulHighI8x16SName,
 	OpcodeVecI16x8ExtMulLowI8x16U:      OpcodeVecI16x8ExtMulLowI8x16UName,
@@ -1517,29 +1572,29 @@ var vectorInstructionName = map[OpcodeVec]string{
 	OpcodeVecF32x4Max:                  OpcodeVecF32x4MaxName,
 	OpcodeVecF32x4Pmin:                 OpcodeVecF32x4PminName,
 	OpcodeVecF32x4Pmax:                 OpcodeVecF32x4PmaxName,
-	OpcodeVecF64x4Ceil:                 OpcodeVecF64x4CeilName,
-	OpcodeVecF64x4Floor:                OpcodeVecF64x4FloorName,
-	OpcodeVecF64x4Trunc:                OpcodeVecF64x4TruncName,
-	OpcodeVecF64x4Nearest:              OpcodeVecF64x4NearestName,
-	OpcodeVecF64x4Abs:                  OpcodeVecF64x4AbsName,
-	OpcodeVecF64x4Neg:                  OpcodeVecF64x4NegName,
-	OpcodeVecF64x4Sqrt:                 OpcodeVecF64x4SqrtName,
-	OpcodeVecF64x4Add:                  OpcodeVecF64x4AddName,
-	OpcodeVecF64x4Sub:                  OpcodeVecF64x4SubName,
-	OpcodeVecF64x4Mul:                  OpcodeVecF64x4MulName,
-	OpcodeVecF64x4Div:                  OpcodeVecF64x4DivName,
-	OpcodeVecF64x4Min:                  OpcodeVecF64x4MinName,
-	OpcodeVecF64x4Max:                  OpcodeVecF64x4MaxName,
-	OpcodeVecF64x4Pmin:                 OpcodeVecF64x4PminName,
-	OpcodeVecF64x4Pmax:                 OpcodeVecF64x4PmaxName,
+	OpcodeVecF64x2Ceil:                 OpcodeVecF64x2CeilName,
+	OpcodeVecF64x2Floor:                OpcodeVecF64x2FloorName,
+	OpcodeVecF64x2Trunc:                OpcodeVecF64x2TruncName,
+	OpcodeVecF64x2Nearest:              OpcodeVecF64x2NearestName,
+	OpcodeVecF64x2Abs:                  OpcodeVecF64x2AbsName,
+	OpcodeVecF64x2Neg:                  OpcodeVecF64x2NegName,
+	OpcodeVecF64x2Sqrt:                 OpcodeVecF64x2SqrtName,
+	OpcodeVecF64x2Add:                  OpcodeVecF64x2AddName,
+	OpcodeVecF64x2Sub:                  OpcodeVecF64x2SubName,
+	OpcodeVecF64x2Mul:                  OpcodeVecF64x2MulName,
+	OpcodeVecF64x2Div:                  OpcodeVecF64x2DivName,
+	OpcodeVecF64x2Min:                  OpcodeVecF64x2MinName,
+	OpcodeVecF64x2Max:                  OpcodeVecF64x2MaxName,
+	OpcodeVecF64x2Pmin:                 OpcodeVecF64x2PminName,
+	OpcodeVecF64x2Pmax:                 OpcodeVecF64x2PmaxName,
 	OpcodeVecI32x4TruncSatF32x4S:       OpcodeVecI32x4TruncSatF32x4SName,
 	OpcodeVecI32x4TruncSatF32x4U:       OpcodeVecI32x4TruncSatF32x4UName,
 	OpcodeVecF32x4ConvertI32x4S:        OpcodeVecF32x4ConvertI32x4SName,
 	OpcodeVecF32x4ConvertI32x4U:        OpcodeVecF32x4ConvertI32x4UName,
 	OpcodeVecI32x4TruncSatF64x2SZero:   OpcodeVecI32x4TruncSatF64x2SZeroName,
 	OpcodeVecI32x4TruncSatF64x2UZero:   OpcodeVecI32x4TruncSatF64x2UZeroName,
-	OpcodeVecF64x2ConvertI32x4S:        OpcodeVecF64x2ConvertI32x4SName,
-	OpcodeVecF64x2ConvertI32x4U:        OpcodeVecF64x2ConvertI32x4UName,
+	OpcodeVecF64x2ConvertLowI32x4S:     OpcodeVecF64x2ConvertLowI32x4SName,
+	OpcodeVecF64x2ConvertLowI32x4U:     OpcodeVecF64x2ConvertLowI32x4UName,
 	OpcodeVecF32x4DemoteF64x2Zero:      OpcodeVecF32x4DemoteF64x2ZeroName,
 	OpcodeVecF64x2PromoteLowF32x4Zero:  OpcodeVecF64x2PromoteLowF32x4ZeroName,
 }
@@ -1548,3 +1603,232 @@ var vectorInstructionName = map[OpcodeVec]string{
 func VectorInstructionName(oc OpcodeVec) (ret string) {
 	return vectorInstructionName[oc]
 }
+
+// OpcodeAtomic represents an opcode of atomic instructions which has
+// multi-byte encoding and is prefixed by OpcodeAtomicPrefix.
+//
+// These opcodes are toggled with FeatureThreads.
+type OpcodeAtomic = byte
+
+const (
+	// Wait and notify, and the fence.
+
+	OpcodeAtomicMemoryNotify OpcodeAtomic = 0x00
+	OpcodeAtomicMemoryWait32 OpcodeAtomic = 0x01
+	OpcodeAtomicMemoryWait64 OpcodeAtomic = 0x02
+	OpcodeAtomicFence        OpcodeAtomic = 0x03
+
+	// Loads and stores.
+
+	OpcodeAtomicI32Load    OpcodeAtomic = 0x10
+	OpcodeAtomicI64Load    OpcodeAtomic = 0x11
+	OpcodeAtomicI32Load8U  OpcodeAtomic = 0x12
+	OpcodeAtomicI32Load16U OpcodeAtomic = 0x13
+	OpcodeAtomicI64Load8U  OpcodeAtomic = 0x14
+	OpcodeAtomicI64Load16U OpcodeAtomic = 0x15
+	OpcodeAtomicI64Load32U OpcodeAtomic = 0x16
+	OpcodeAtomicI32Store   OpcodeAtomic = 0x17
+	OpcodeAtomicI64Store   OpcodeAtomic = 0x18
+	OpcodeAtomicI32Store8  OpcodeAtomic = 0x19
+	OpcodeAtomicI32Store16 OpcodeAtomic = 0x1a
+	OpcodeAtomicI64Store8  OpcodeAtomic = 0x1b
+	OpcodeAtomicI64Store16 OpcodeAtomic = 0x1c
+	OpcodeAtomicI64Store32 OpcodeAtomic = 0x1d
+
+	// Read-modify-write operations which return the old value.
+
+	OpcodeAtomicI32RmwAdd        OpcodeAtomic = 0x1e
+	OpcodeAtomicI64RmwAdd        OpcodeAtomic = 0x1f
+	OpcodeAtomicI32Rmw8AddU      OpcodeAtomic = 0x20
+	OpcodeAtomicI32Rmw16AddU     OpcodeAtomic = 0x21
+	OpcodeAtomicI64Rmw8AddU      OpcodeAtomic = 0x22
+	OpcodeAtomicI64Rmw16AddU     OpcodeAtomic = 0x23
+	OpcodeAtomicI64Rmw32AddU     OpcodeAtomic = 0x24
+	OpcodeAtomicI32RmwSub        OpcodeAtomic = 0x25
+	OpcodeAtomicI64RmwSub        OpcodeAtomic = 0x26
+	OpcodeAtomicI32Rmw8SubU      OpcodeAtomic = 0x27
+	OpcodeAtomicI32Rmw16SubU     OpcodeAtomic = 0x28
+	OpcodeAtomicI64Rmw8SubU      OpcodeAtomic = 0x29
+	OpcodeAtomicI64Rmw16SubU     OpcodeAtomic = 0x2a
+	OpcodeAtomicI64Rmw32SubU     OpcodeAtomic = 0x2b
+	OpcodeAtomicI32RmwAnd        OpcodeAtomic = 0x2c
+	OpcodeAtomicI64RmwAnd        OpcodeAtomic = 0x2d
+	OpcodeAtomicI32Rmw8AndU      OpcodeAtomic = 0x2e
+	OpcodeAtomicI32Rmw16AndU     OpcodeAtomic = 0x2f
+	OpcodeAtomicI64Rmw8AndU      OpcodeAtomic = 0x30
+	OpcodeAtomicI64Rmw16AndU     OpcodeAtomic = 0x31
+	OpcodeAtomicI64Rmw32AndU     OpcodeAtomic = 0x32
+	OpcodeAtomicI32RmwOr         OpcodeAtomic = 0x33
+	OpcodeAtomicI64RmwOr         OpcodeAtomic = 0x34
+	OpcodeAtomicI32Rmw8OrU       OpcodeAtomic = 0x35
+	OpcodeAtomicI32Rmw16OrU      OpcodeAtomic = 0x36
+	OpcodeAtomicI64Rmw8OrU       OpcodeAtomic = 0x37
+	OpcodeAtomicI64Rmw16OrU      OpcodeAtomic = 0x38
+	OpcodeAtomicI64Rmw32OrU      OpcodeAtomic = 0x39
+	OpcodeAtomicI32RmwXor        OpcodeAtomic = 0x3a
+	OpcodeAtomicI64RmwXor        OpcodeAtomic = 0x3b
+	OpcodeAtomicI32Rmw8XorU      OpcodeAtomic = 0x3c
+	OpcodeAtomicI32Rmw16XorU     OpcodeAtomic = 0x3d
+	OpcodeAtomicI64Rmw8XorU      OpcodeAtomic = 0x3e
+	OpcodeAtomicI64Rmw16XorU     OpcodeAtomic = 0x3f
+	OpcodeAtomicI64Rmw32XorU     OpcodeAtomic = 0x40
+	OpcodeAtomicI32RmwXchg       OpcodeAtomic = 0x41
+	OpcodeAtomicI64RmwXchg       OpcodeAtomic = 0x42
+	OpcodeAtomicI32Rmw8XchgU     OpcodeAtomic = 0x43
+	OpcodeAtomicI32Rmw16XchgU    OpcodeAtomic = 0x44
+	OpcodeAtomicI64Rmw8XchgU     OpcodeAtomic = 0x45
+	OpcodeAtomicI64Rmw16XchgU    OpcodeAtomic = 0x46
+	OpcodeAtomicI64Rmw32XchgU    OpcodeAtomic = 0x47
+	OpcodeAtomicI32RmwCmpxchg    OpcodeAtomic = 0x48
+	OpcodeAtomicI64RmwCmpxchg    OpcodeAtomic = 0x49
+	OpcodeAtomicI32Rmw8CmpxchgU  OpcodeAtomic = 0x4a
+	OpcodeAtomicI32Rmw16CmpxchgU OpcodeAtomic = 0x4b
+	OpcodeAtomicI64Rmw8CmpxchgU  OpcodeAtomic = 0x4c
+	OpcodeAtomicI64Rmw16CmpxchgU OpcodeAtomic = 0x4d
+	OpcodeAtomicI64Rmw32CmpxchgU OpcodeAtomic = 0x4e
+)
+
+const (
+	OpcodeAtomicMemoryNotifyName     = "memory.atomic.notify"
+	OpcodeAtomicMemoryWait32Name     = "memory.atomic.wait32"
+	OpcodeAtomicMemoryWait64Name     = "memory.atomic.wait64"
+	OpcodeAtomicFenceName            = "atomic.fence"
+	OpcodeAtomicI32LoadName          = "i32.atomic.load"
+	OpcodeAtomicI64LoadName          = "i64.atomic.load"
+	OpcodeAtomicI32Load8UName        = "i32.atomic.load8_u"
+	OpcodeAtomicI32Load16UName       = "i32.atomic.load16_u"
+	OpcodeAtomicI64Load8UName        = "i64.atomic.load8_u"
+	OpcodeAtomicI64Load16UName       = "i64.atomic.load16_u"
+	OpcodeAtomicI64Load32UName       = "i64.atomic.load32_u"
+	OpcodeAtomicI32StoreName         = "i32.atomic.store"
+	OpcodeAtomicI64StoreName         = "i64.atomic.store"
+	OpcodeAtomicI32Store8Name        = "i32.atomic.store8"
+	OpcodeAtomicI32Store16Name       = "i32.atomic.store16"
+	OpcodeAtomicI64Store8Name        = "i64.atomic.store8"
+	OpcodeAtomicI64Store16Name       = "i64.atomic.store16"
+	OpcodeAtomicI64Store32Name       = "i64.atomic.store32"
+	OpcodeAtomicI32RmwAddName        = "i32.atomic.rmw.add"
+	OpcodeAtomicI64RmwAddName        = "i64.atomic.rmw.add"
+	OpcodeAtomicI32Rmw8AddUName      = "i32.atomic.rmw8.add_u"
+	OpcodeAtomicI32Rmw16AddUName     = "i32.atomic.rmw16.add_u"
+	OpcodeAtomicI64Rmw8AddUName      = "i64.atomic.rmw8.add_u"
+	OpcodeAtomicI64Rmw16AddUName     = "i64.atomic.rmw16.add_u"
+	OpcodeAtomicI64Rmw32AddUName     = "i64.atomic.rmw32.add_u"
+	OpcodeAtomicI32RmwSubName        = "i32.atomic.rmw.sub"
+	OpcodeAtomicI64RmwSubName        = "i64.atomic.rmw.sub"
+	OpcodeAtomicI32Rmw8SubUName      = "i32.atomic.rmw8.sub_u"
+	OpcodeAtomicI32Rmw16SubUName     = "i32.atomic.rmw16.sub_u"
+	OpcodeAtomicI64Rmw8SubUName      = "i64.atomic.rmw8.sub_u"
+	OpcodeAtomicI64Rmw16SubUName     = "i64.atomic.rmw16.sub_u"
+	OpcodeAtomicI64Rmw32SubUName     = "i64.atomic.rmw32.sub_u"
+	OpcodeAtomicI32RmwAndName        = "i32.atomic.rmw.and"
+	OpcodeAtomicI64RmwAndName        = "i64.atomic.rmw.and"
+	OpcodeAtomicI32Rmw8AndUName      = "i32.atomic.rmw8.and_u"
+	OpcodeAtomicI32Rmw16AndUName     = "i32.atomic.rmw16.and_u"
+	OpcodeAtomicI64Rmw8AndUName      = "i64.atomic.rmw8.and_u"
+	OpcodeAtomicI64Rmw16AndUName     = "i64.atomic.rmw16.and_u"
+	OpcodeAtomicI64Rmw32AndUName     = "i64.atomic.rmw32.and_u"
+	OpcodeAtomicI32RmwOrName         = "i32.atomic.rmw.or"
+	OpcodeAtomicI64RmwOrName         = "i64.atomic.rmw.or"
+	OpcodeAtomicI32Rmw8OrUName       = "i32.atomic.rmw8.or_u"
+	OpcodeAtomicI32Rmw16OrUName      = "i32.atomic.rmw16.or_u"
+	OpcodeAtomicI64Rmw8OrUName       = "i64.atomic.rmw8.or_u"
+	OpcodeAtomicI64Rmw16OrUName      = "i64.atomic.rmw16.or_u"
+	OpcodeAtomicI64Rmw32OrUName      = "i64.atomic.rmw32.or_u"
+	OpcodeAtomicI32RmwXorName        = "i32.atomic.rmw.xor"
+	OpcodeAtomicI64RmwXorName        = "i64.atomic.rmw.xor"
+	OpcodeAtomicI32Rmw8XorUName      = "i32.atomic.rmw8.xor_u"
+	OpcodeAtomicI32Rmw16XorUName     = "i32.atomic.rmw16.xor_u"
+	OpcodeAtomicI64Rmw8XorUName      = "i64.atomic.rmw8.xor_u"
+	OpcodeAtomicI64Rmw16XorUName     = "i64.atomic.rmw16.xor_u"
+	OpcodeAtomicI64Rmw32XorUName     = "i64.atomic.rmw32.xor_u"
+	OpcodeAtomicI32RmwXchgName       = "i32.atomic.rmw.xchg"
+	OpcodeAtomicI64RmwXchgName       = "i64.atomic.rmw.xchg"
+	OpcodeAtomicI32Rmw8XchgUName     = "i32.atomic.rmw8.xchg_u"
+	OpcodeAtomicI32Rmw16XchgUName    = "i32.atomic.rmw16.xchg_u"
+	OpcodeAtomicI64Rmw8XchgUName     = "i64.atomic.rmw8.xchg_u"
+	OpcodeAtomicI64Rmw16XchgUName    = "i64.atomic.rmw16.xchg_u"
+	OpcodeAtomicI64Rmw32XchgUName    = "i64.atomic.rmw32.xchg_u"
+	OpcodeAtomicI32RmwCmpxchgName    = "i32.atomic.rmw.cmpxchg"
+	OpcodeAtomicI64RmwCmpxchgName    = "i64.atomic.rmw.cmpxchg"
+	OpcodeAtomicI32Rmw8CmpxchgUName  = "i32.atomic.rmw8.cmpxchg_u"
+	OpcodeAtomicI32Rmw16CmpxchgUName = "i32.atomic.rmw16.cmpxchg_u"
+	OpcodeAtomicI64Rmw8CmpxchgUName  = "i64.atomic.rmw8.cmpxchg_u"
+	OpcodeAtomicI64Rmw16CmpxchgUName = "i64.atomic.rmw16.cmpxchg_u"
+	OpcodeAtomicI64Rmw32CmpxchgUName = "i64.atomic.rmw32.cmpxchg_u"
+)
+
+var atomicInstructionName = map[OpcodeAtomic]string{
+	OpcodeAtomicMemoryNotify:     OpcodeAtomicMemoryNotifyName,
+	OpcodeAtomicMemoryWait32:     OpcodeAtomicMemoryWait32Name,
+	OpcodeAtomicMemoryWait64:     OpcodeAtomicMemoryWait64Name,
+	OpcodeAtomicFence:            OpcodeAtomicFenceName,
+	OpcodeAtomicI32Load:          OpcodeAtomicI32LoadName,
+	OpcodeAtomicI64Load:          OpcodeAtomicI64LoadName,
+	OpcodeAtomicI32Load8U:        OpcodeAtomicI32Load8UName,
+	OpcodeAtomicI32Load16U:       OpcodeAtomicI32Load16UName,
+	OpcodeAtomicI64Load8U:        OpcodeAtomicI64Load8UName,
+	OpcodeAtomicI64Load16U:       OpcodeAtomicI64Load16UName,
+	OpcodeAtomicI64Load32U:       OpcodeAtomicI64Load32UName,
+	OpcodeAtomicI32Store:         OpcodeAtomicI32StoreName,
+	OpcodeAtomicI64Store:         OpcodeAtomicI64StoreName,
+	OpcodeAtomicI32Store8:        OpcodeAtomicI32Store8Name,
+	OpcodeAtomicI32Store16:       OpcodeAtomicI32Store16Name,
+	OpcodeAtomicI64Store8:        OpcodeAtomicI64Store8Name,
+	OpcodeAtomicI64Store16:       OpcodeAtomicI64Store16Name,
+	OpcodeAtomicI64Store32:       OpcodeAtomicI64Store32Name,
+	OpcodeAtomicI32RmwAdd:        OpcodeAtomicI32RmwAddName,
+	OpcodeAtomicI64RmwAdd:        OpcodeAtomicI64RmwAddName,
+	OpcodeAtomicI32Rmw8AddU:      OpcodeAtomicI32Rmw8AddUName,
+	OpcodeAtomicI32Rmw16AddU:     OpcodeAtomicI32Rmw16AddUName,
+	OpcodeAtomicI64Rmw8AddU:      OpcodeAtomicI64Rmw8AddUName,
+	OpcodeAtomicI64Rmw16AddU:     OpcodeAtomicI64Rmw16AddUName,
+	OpcodeAtomicI64Rmw32AddU:     OpcodeAtomicI64Rmw32AddUName,
+	OpcodeAtomicI32RmwSub:        OpcodeAtomicI32RmwSubName,
+	OpcodeAtomicI64RmwSub:        OpcodeAtomicI64RmwSubName,
+	OpcodeAtomicI32Rmw8SubU:      OpcodeAtomicI32Rmw8SubUName,
+	OpcodeAtomicI32Rmw16SubU:     OpcodeAtomicI32Rmw16SubUName,
+	OpcodeAtomicI64Rmw8SubU:      OpcodeAtomicI64Rmw8SubUName,
+	OpcodeAtomicI64Rmw16SubU:     OpcodeAtomicI64Rmw16SubUName,
+	OpcodeAtomicI64Rmw32SubU:     OpcodeAtomicI64Rmw32SubUName,
+	OpcodeAtomicI32RmwAnd:        OpcodeAtomicI32RmwAndName,
+	OpcodeAtomicI64RmwAnd:        OpcodeAtomicI64RmwAndName,
+	OpcodeAtomicI32Rmw8AndU:      OpcodeAtomicI32Rmw8AndUName,
+	OpcodeAtomicI32Rmw16AndU:     OpcodeAtomicI32Rmw16AndUName,
+	OpcodeAtomicI64Rmw8AndU:      OpcodeAtomicI64Rmw8AndUName,
+	OpcodeAtomicI64Rmw16AndU:     OpcodeAtomicI64Rmw16AndUName,
+	OpcodeAtomicI64Rmw32AndU:     OpcodeAtomicI64Rmw32AndUName,
+	OpcodeAtomicI32RmwOr:         OpcodeAtomicI32RmwOrName,
+	OpcodeAtomicI64RmwOr:         OpcodeAtomicI64RmwOrName,
+	OpcodeAtomicI32Rmw8OrU:       OpcodeAtomicI32Rmw8OrUName,
+	OpcodeAtomicI32Rmw16OrU:      OpcodeAtomicI32Rmw16OrUName,
+	OpcodeAtomicI64Rmw8OrU:       OpcodeAtomicI64Rmw8OrUName,
+	OpcodeAtomicI64Rmw16OrU:      OpcodeAtomicI64Rmw16OrUName,
+	OpcodeAtomicI64Rmw32OrU:      OpcodeAtomicI64Rmw32OrUName,
+	OpcodeAtomicI32RmwXor:        OpcodeAtomicI32RmwXorName,
+	OpcodeAtomicI64RmwXor:        OpcodeAtomicI64RmwXorName,
+	OpcodeAtomicI32Rmw8XorU:      OpcodeAtomicI32Rmw8XorUName,
+	OpcodeAtomicI32Rmw16XorU:     OpcodeAtomicI32Rmw16XorUName,
+	OpcodeAtomicI64Rmw8XorU:      OpcodeAtomicI64Rmw8XorUName,
+	OpcodeAtomicI64Rmw16XorU:     OpcodeAtomicI64Rmw16XorUName,
+	OpcodeAtomicI64Rmw32XorU:     OpcodeAtomicI64Rmw32XorUName,
+	OpcodeAtomicI32RmwXchg:       OpcodeAtomicI32RmwXchgName,
+	OpcodeAtomicI64RmwXchg:       OpcodeAtomicI64RmwXchgName,
+	OpcodeAtomicI32Rmw8XchgU:     OpcodeAtomicI32Rmw8XchgUName,
+	OpcodeAtomicI32Rmw16XchgU:    OpcodeAtomicI32Rmw16XchgUName,
+	OpcodeAtomicI64Rmw8XchgU:     OpcodeAtomicI64Rmw8XchgUName,
+	OpcodeAtomicI64Rmw16XchgU:    OpcodeAtomicI64Rmw16XchgUName,
+	OpcodeAtomicI64Rmw32XchgU:    OpcodeAtomicI64Rmw32XchgUName,
+	OpcodeAtomicI32RmwCmpxchg:    OpcodeAtomicI32RmwCmpxchgName,
+	OpcodeAtomicI64RmwCmpxchg:    OpcodeAtomicI64RmwCmpxchgName,
+	OpcodeAtomicI32Rmw8CmpxchgU:  OpcodeAtomicI32Rmw8CmpxchgUName,
+	OpcodeAtomicI32Rmw16CmpxchgU: OpcodeAtomicI32Rmw16CmpxchgUName,
+	OpcodeAtomicI64Rmw8CmpxchgU:  OpcodeAtomicI64Rmw8CmpxchgUName,
+	OpcodeAtomicI64Rmw16CmpxchgU: OpcodeAtomicI64Rmw16CmpxchgUName,
+	OpcodeAtomicI64Rmw32CmpxchgU: OpcodeAtomicI64Rmw32CmpxchgUName,
+}
+
+// AtomicInstructionName returns the instruction name corresponding to the atomic Opcode.
+func AtomicInstructionName(oc OpcodeAtomic) (ret string) {
+	return atomicInstructionName[oc]
+}
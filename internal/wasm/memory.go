@@ -6,11 +6,15 @@ import (
 	"encoding/binary"
 	"fmt"
 	"math"
+	"math/bits"
 	"reflect"
 	"sync"
+	"sync/atomic"
+	"time"
 	"unsafe"
 
 	"github.com/tetratelabs/wazero/api"
+	"github.com/tetratelabs/wazero/internal/wasmruntime"
 )
 
 const (
@@ -23,6 +27,9 @@ const (
 	MemoryLimitPages = uint32(65536)
 	// MemoryPageSizeInBits satisfies the relation: "1 << MemoryPageSizeInBits == MemoryPageSize".
 	MemoryPageSizeInBits = 16
+	// MemoryLimitPagesShared is maximum number of pages of a shared memory (2^14), which is allocated up to its max
+	// when instantiated. This bounds that allocation to 1GiB, instead of 4GiB for any module declaring the maximum.
+	MemoryLimitPagesShared = uint32(16384)
 )
 
 // MemorySizer is the default function that derives min, capacity and max pages from decoded source. The capacity
@@ -45,8 +52,18 @@ var _ api.Memory = &MemoryInstance{}
 type MemoryInstance struct {
 	Buffer        []byte
 	Min, Cap, Max uint32
-	// mux is used to prevent overlapping calls to Grow.
+	// Shared is true if this memory can be accessed by multiple threads. See Memory.IsShared.
+	Shared bool
+	// Memory64 is true if this memory is indexed with i64. See Memory.IsMemory64.
+	Memory64 bool
+	// mux is used to prevent overlapping calls to Grow, and to prevent atomic instructions on a shared memory from
+	// reading Buffer while Grow changes it.
 	mux sync.RWMutex
+
+	// waiters are the threads blocked on memory.atomic.wait32 or memory.atomic.wait64 keyed by the address.
+	waiters map[uint64][]chan struct{}
+	// waitersMux guards waiters, and is held while checking the expected value on Wait.
+	waitersMux sync.Mutex
 }
 
 // NewMemoryInstance creates a new instance based on the parameters in the SectionIDMemory.
@@ -54,10 +71,12 @@ func NewMemoryInstance(memSec *Memory) *MemoryInstance {
 	min := MemoryPagesToBytesNum(memSec.Min)
 	capacity := MemoryPagesToBytesNum(memSec.Cap)
 	return &MemoryInstance{
-		Buffer: make([]byte, min, capacity),
-		Min:    memSec.Min,
-		Cap:    memSec.Cap,
-		Max:    memSec.Max,
+		Buffer:   make([]byte, min, capacity),
+		Min:      memSec.Min,
+		Cap:      memSec.Cap,
+		Max:      memSec.Max,
+		Shared:   memSec.IsShared,
+		Memory64: memSec.IsMemory64,
 	}
 }
 
@@ -209,6 +228,133 @@ func (m *MemoryInstance) Write(_ context.Context, offset uint32, val []byte) boo
 	return true
 }
 
+// Read64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) Read64(_ context.Context, offset, byteCount uint64) ([]byte, bool) {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	if !m.hasSize64(offset, byteCount) {
+		return nil, false
+	}
+	return m.Buffer[offset : offset+byteCount : offset+byteCount], true
+}
+
+// Write64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) Write64(_ context.Context, offset uint64, val []byte) bool {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	if !m.hasSize64(offset, uint64(len(val))) {
+		return false
+	}
+	copy(m.Buffer[offset:], val)
+	return true
+}
+
+// ReadByte64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) ReadByte64(_ context.Context, offset uint64) (byte, bool) {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	if !m.hasSize64(offset, 1) {
+		return 0, false
+	}
+	return m.Buffer[offset], true
+}
+
+// ReadUint16Le64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) ReadUint16Le64(_ context.Context, offset uint64) (uint16, bool) {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	if !m.hasSize64(offset, 2) {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint16(m.Buffer[offset : offset+2]), true
+}
+
+// ReadUint32Le64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) ReadUint32Le64(_ context.Context, offset uint64) (uint32, bool) {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	return m.readUint32Le64(offset)
+}
+
+// ReadFloat32Le64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) ReadFloat32Le64(_ context.Context, offset uint64) (float32, bool) {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	v, ok := m.readUint32Le64(offset)
+	if !ok {
+		return 0, false
+	}
+	return math.Float32frombits(v), true
+}
+
+// ReadUint64Le64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) ReadUint64Le64(_ context.Context, offset uint64) (uint64, bool) {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	return m.readUint64Le64(offset)
+}
+
+// ReadFloat64Le64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) ReadFloat64Le64(_ context.Context, offset uint64) (float64, bool) {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	v, ok := m.readUint64Le64(offset)
+	if !ok {
+		return 0, false
+	}
+	return math.Float64frombits(v), true
+}
+
+// WriteByte64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) WriteByte64(_ context.Context, offset uint64, v byte) bool {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	if !m.hasSize64(offset, 1) {
+		return false
+	}
+	m.Buffer[offset] = v
+	return true
+}
+
+// WriteUint16Le64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) WriteUint16Le64(_ context.Context, offset uint64, v uint16) bool {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	if !m.hasSize64(offset, 2) {
+		return false
+	}
+	binary.LittleEndian.PutUint16(m.Buffer[offset:], v)
+	return true
+}
+
+// WriteUint32Le64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) WriteUint32Le64(_ context.Context, offset uint64, v uint32) bool {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	return m.writeUint32Le64(offset, v)
+}
+
+// WriteFloat32Le64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) WriteFloat32Le64(_ context.Context, offset uint64, v float32) bool {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	return m.writeUint32Le64(offset, math.Float32bits(v))
+}
+
+// WriteUint64Le64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) WriteUint64Le64(_ context.Context, offset uint64, v uint64) bool {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	return m.writeUint64Le64(offset, v)
+}
+
+// WriteFloat64Le64 implements the same method as documented on api.Memory.
+func (m *MemoryInstance) WriteFloat64Le64(_ context.Context, offset uint64, v float64) bool {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	return m.writeUint64Le64(offset, math.Float64bits(v))
+}
+
 // MemoryPagesToBytesNum converts the given pages into the number of bytes contained in these pages.
 func MemoryPagesToBytesNum(pages uint32) (bytesNum uint64) {
 	return uint64(pages) << MemoryPageSizeInBits
@@ -232,6 +378,9 @@ func (m *MemoryInstance) Grow(_ context.Context, delta uint32) (result uint32, o
 	if newPages > m.Max {
 		return 0, false
 	} else if newPages > m.Cap { // grow the memory.
+		if m.Shared { // Other threads might be using the buffer, so it can't move.
+			return 0, false
+		}
 		m.Buffer = append(m.Buffer, make([]byte, MemoryPagesToBytesNum(delta))...)
 		m.Cap = newPages
 		return currentPages, true
@@ -285,6 +434,20 @@ func (m *MemoryInstance) hasSize(offset uint32, sizeInBytes uint32) bool {
 	return uint64(offset)+uint64(sizeInBytes) <= uint64(len(m.Buffer)) // uint64 prevents overflow on add
 }
 
+// indexType returns the type of addresses into this memory. See Memory.IndexType.
+func (m *MemoryInstance) indexType() ValueType {
+	if m.Memory64 {
+		return ValueTypeI64
+	}
+	return ValueTypeI32
+}
+
+// hasSize64 is like hasSize, but for 64-bit values, which can overflow on add.
+func (m *MemoryInstance) hasSize64(offset uint64, sizeInBytes uint64) bool {
+	size := uint64(len(m.Buffer))
+	return offset <= size && sizeInBytes <= size-offset
+}
+
 // readUint32Le implements ReadUint32Le without using a context. This is extracted as both ints and floats are stored in
 // memory as uint32le.
 func (m *MemoryInstance) readUint32Le(offset uint32) (uint32, bool) {
@@ -322,3 +485,288 @@ func (m *MemoryInstance) writeUint64Le(offset uint32, v uint64) bool {
 	binary.LittleEndian.PutUint64(m.Buffer[offset:], v)
 	return true
 }
+
+// readUint32Le64 is like readUint32Le, except the offset is 64-bit.
+func (m *MemoryInstance) readUint32Le64(offset uint64) (uint32, bool) {
+	if !m.hasSize64(offset, 4) {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint32(m.Buffer[offset : offset+4]), true
+}
+
+// readUint64Le64 is like readUint64Le, except the offset is 64-bit.
+func (m *MemoryInstance) readUint64Le64(offset uint64) (uint64, bool) {
+	if !m.hasSize64(offset, 8) {
+		return 0, false
+	}
+	return binary.LittleEndian.Uint64(m.Buffer[offset : offset+8]), true
+}
+
+// writeUint32Le64 is like writeUint32Le, except the offset is 64-bit.
+func (m *MemoryInstance) writeUint32Le64(offset uint64, v uint32) bool {
+	if !m.hasSize64(offset, 4) {
+		return false
+	}
+	binary.LittleEndian.PutUint32(m.Buffer[offset:], v)
+	return true
+}
+
+// writeUint64Le64 is like writeUint64Le, except the offset is 64-bit.
+func (m *MemoryInstance) writeUint64Le64(offset uint64, v uint64) bool {
+	if !m.hasSize64(offset, 8) {
+		return false
+	}
+	binary.LittleEndian.PutUint64(m.Buffer[offset:], v)
+	return true
+}
+
+// Below are functions used by engines to implement the atomic instructions of FeatureThreads. offset is the effective
+// address of the instruction and sizeInBytes is the accessed size, which is 1, 2, 4 or 8.
+//
+// The values are read and written with sync/atomic, so they are atomic with regard to each other as well as between
+// engines sharing the same memory. 8-bit and 16-bit values are updated with a compare-and-swap on the 32-bit word
+// which contains them.
+
+// isLittleEndian is true if the host stores integers in little-endian, which is the byte order of memory.
+var isLittleEndian = func() bool {
+	v := uint16(1)
+	return *(*byte)(unsafe.Pointer(&v)) == 1
+}()
+
+// checkAtomicAccess returns an error if the access of sizeInBytes at offset is not aligned or out of bounds.
+func (m *MemoryInstance) checkAtomicAccess(offset uint64, sizeInBytes uint32) error {
+	if offset%uint64(sizeInBytes) != 0 {
+		return wasmruntime.ErrRuntimeUnalignedAtomic
+	} else if offset+uint64(sizeInBytes) > uint64(len(m.Buffer)) {
+		return wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess
+	}
+	return nil
+}
+
+// AtomicLoad atomically reads the little-endian value of sizeInBytes at offset.
+func (m *MemoryInstance) AtomicLoad(offset uint64, sizeInBytes uint32) (uint64, error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	if err := m.checkAtomicAccess(offset, sizeInBytes); err != nil {
+		return 0, err
+	}
+	return m.atomicLoad(offset, sizeInBytes), nil
+}
+
+// AtomicStore atomically writes the lowest sizeInBytes of v in little-endian at offset.
+func (m *MemoryInstance) AtomicStore(offset uint64, sizeInBytes uint32, v uint64) error {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	if err := m.checkAtomicAccess(offset, sizeInBytes); err != nil {
+		return err
+	}
+	switch sizeInBytes {
+	case 4:
+		atomic.StoreUint32(m.uint32At(offset), toMemoryOrder32(uint32(v)))
+	case 8:
+		atomic.StoreUint64(m.uint64At(offset), toMemoryOrder64(v))
+	default:
+		m.atomicUpdateSubWord(offset, sizeInBytes, func(uint64) uint64 { return v })
+	}
+	return nil
+}
+
+// AtomicRMW atomically replaces the value of sizeInBytes at offset with the result of update, and returns the old
+// value. The result of update is truncated to sizeInBytes.
+func (m *MemoryInstance) AtomicRMW(offset uint64, sizeInBytes uint32, update func(old uint64) uint64) (old uint64, err error) {
+	m.mux.RLock()
+	defer m.mux.RUnlock()
+
+	if err = m.checkAtomicAccess(offset, sizeInBytes); err != nil {
+		return
+	}
+	switch sizeInBytes {
+	case 4:
+		p := m.uint32At(offset)
+		for {
+			raw := atomic.LoadUint32(p)
+			old = uint64(toMemoryOrder32(raw))
+			if atomic.CompareAndSwapUint32(p, raw, toMemoryOrder32(uint32(update(old)))) {
+				return
+			}
+		}
+	case 8:
+		p := m.uint64At(offset)
+		for {
+			raw := atomic.LoadUint64(p)
+			old = toMemoryOrder64(raw)
+			if atomic.CompareAndSwapUint64(p, raw, toMemoryOrder64(update(old))) {
+				return
+			}
+		}
+	default:
+		old = m.atomicUpdateSubWord(offset, sizeInBytes, update)
+		return
+	}
+}
+
+// AtomicCompareExchange atomically replaces the value of sizeInBytes at offset with replacement only if it equals to
+// expected, and returns the old value. expected is compared after truncated to sizeInBytes.
+func (m *MemoryInstance) AtomicCompareExchange(offset uint64, sizeInBytes uint32, expected, replacement uint64) (uint64, error) {
+	expected &= sizeMask(sizeInBytes)
+	return m.AtomicRMW(offset, sizeInBytes, func(old uint64) uint64 {
+		if old == expected {
+			return replacement
+		}
+		return old
+	})
+}
+
+// Wait blocks the current goroutine while the value of sizeInBytes (4 or 8) at offset equals to expected, until woken
+// up by Notify or the timeout in nanoseconds elapses. A negative timeout means no timeout.
+//
+// The result is 0 if woken up by Notify, 1 if the value didn't equal to expected and 2 on timeout.
+// See https://github.com/WebAssembly/threads/blob/main/proposals/threads/Overview.md#wait
+func (m *MemoryInstance) Wait(offset uint64, sizeInBytes uint32, expected uint64, timeout int64) (uint64, error) {
+	m.mux.RLock()
+	if err := m.checkAtomicAccess(offset, sizeInBytes); err != nil {
+		m.mux.RUnlock()
+		return 0, err
+	} else if !m.Shared {
+		m.mux.RUnlock()
+		return 0, wasmruntime.ErrRuntimeExpectedSharedMemory
+	}
+
+	m.waitersMux.Lock()
+	current := m.atomicLoad(offset, sizeInBytes)
+	m.mux.RUnlock() // Don't block Grow while waiting.
+	if current != expected&sizeMask(sizeInBytes) {
+		m.waitersMux.Unlock()
+		return 1, nil
+	}
+	if m.waiters == nil {
+		m.waiters = map[uint64][]chan struct{}{}
+	}
+	woken := make(chan struct{})
+	m.waiters[offset] = append(m.waiters[offset], woken)
+	m.waitersMux.Unlock()
+
+	if timeout < 0 {
+		<-woken
+		return 0, nil
+	}
+
+	timer := time.NewTimer(time.Duration(timeout))
+	defer timer.Stop()
+	select {
+	case <-woken:
+		return 0, nil
+	case <-timer.C:
+	}
+
+	m.waitersMux.Lock()
+	defer m.waitersMux.Unlock()
+	waiters := m.waiters[offset]
+	for i, w := range waiters {
+		if w == woken {
+			m.waiters[offset] = append(waiters[:i:i], waiters[i+1:]...)
+			return 2, nil
+		}
+	}
+	// Notify has already removed and woken up this waiter concurrently with the timeout.
+	return 0, nil
+}
+
+// Notify wakes up at most count goroutines blocked by Wait on offset in the order they started waiting, and returns
+// the number of woken ones. This always returns zero on the memory which is not shared.
+//
+// See https://github.com/WebAssembly/threads/blob/main/proposals/threads/Overview.md#wake
+func (m *MemoryInstance) Notify(offset uint64, count uint32) (uint32, error) {
+	m.mux.RLock()
+	err := m.checkAtomicAccess(offset, 4)
+	m.mux.RUnlock()
+	if err != nil {
+		return 0, err
+	} else if !m.Shared {
+		return 0, nil
+	}
+
+	m.waitersMux.Lock()
+	defer m.waitersMux.Unlock()
+	waiters := m.waiters[offset]
+	n := uint32(len(waiters))
+	if count < n {
+		n = count
+	}
+	for _, w := range waiters[:n] {
+		close(w)
+	}
+	if rest := waiters[n:]; len(rest) == 0 {
+		delete(m.waiters, offset)
+	} else {
+		m.waiters[offset] = rest
+	}
+	return n, nil
+}
+
+// atomicLoad implements AtomicLoad without the access checks.
+func (m *MemoryInstance) atomicLoad(offset uint64, sizeInBytes uint32) uint64 {
+	switch sizeInBytes {
+	case 4:
+		return uint64(toMemoryOrder32(atomic.LoadUint32(m.uint32At(offset))))
+	case 8:
+		return toMemoryOrder64(atomic.LoadUint64(m.uint64At(offset)))
+	default:
+		word := uint64(toMemoryOrder32(atomic.LoadUint32(m.uint32At(offset &^ 3))))
+		return (word >> ((offset & 3) * 8)) & sizeMask(sizeInBytes)
+	}
+}
+
+// atomicUpdateSubWord atomically replaces the 8-bit or 16-bit value at offset with the result of update via the
+// compare-and-swap on the containing 32-bit word, and returns the old value.
+func (m *MemoryInstance) atomicUpdateSubWord(offset uint64, sizeInBytes uint32, update func(old uint64) uint64) uint64 {
+	// The containing word never exceeds the buffer as its length is a multiple of the page size.
+	p := m.uint32At(offset &^ 3)
+	shift := (offset & 3) * 8
+	mask := sizeMask(sizeInBytes)
+	for {
+		raw := atomic.LoadUint32(p)
+		word := uint64(toMemoryOrder32(raw))
+		old := (word >> shift) & mask
+		word = (word &^ (mask << shift)) | ((update(old) & mask) << shift)
+		if atomic.CompareAndSwapUint32(p, raw, toMemoryOrder32(uint32(word))) {
+			return old
+		}
+	}
+}
+
+// uint32At returns the pointer to the 32-bit word at the aligned offset.
+func (m *MemoryInstance) uint32At(offset uint64) *uint32 {
+	return (*uint32)(unsafe.Pointer(&m.Buffer[offset]))
+}
+
+// uint64At returns the pointer to the 64-bit word at the aligned offset.
+func (m *MemoryInstance) uint64At(offset uint64) *uint64 {
+	return (*uint64)(unsafe.Pointer(&m.Buffer[offset]))
+}
+
+// sizeMask returns the mask of the lowest sizeInBytes bytes.
+func sizeMask(sizeInBytes uint32) uint64 {
+	if sizeInBytes == 8 {
+		return math.MaxUint64
+	}
+	return 1<<(sizeInBytes*8) - 1
+}
+
+// toMemoryOrder32 converts the 32-bit value between the host byte order and the little-endian byte order of memory.
+func toMemoryOrder32(v uint32) uint32 {
+	if isLittleEndian {
+		return v
+	}
+	return bits.ReverseBytes32(v)
+}
+
+// toMemoryOrder64 converts the 64-bit value between the host byte order and the little-endian byte order of memory.
+func toMemoryOrder64(v uint64) uint64 {
+	if isLittleEndian {
+		return v
+	}
+	return bits.ReverseBytes64(v)
+}
@@ -4,8 +4,10 @@ import (
 	"context"
 	"math"
 	"testing"
+	"time"
 
 	"github.com/tetratelabs/wazero/internal/testing/require"
+	"github.com/tetratelabs/wazero/internal/wasmruntime"
 )
 
 func TestMemoryPageConsts(t *testing.T) {
@@ -776,3 +778,282 @@ func TestMemoryInstance_WriteFloat64Le(t *testing.T) {
 		})
 	}
 }
+
+func TestMemoryInstance_Read64_Write64(t *testing.T) {
+	memory := &MemoryInstance{Buffer: make([]byte, 100), Memory64: true}
+	v := []byte{1, 2, 3, 4}
+
+	tests := []struct {
+		name       string
+		offset     uint64
+		expectedOk bool
+	}{
+		{
+			name:       "valid offset",
+			offset:     0, // arbitrary valid offset.
+			expectedOk: true,
+		},
+		{
+			name:       "maximum boundary valid offset",
+			offset:     uint64(memory.Size(testCtx)) - 4, // 4 is the length of v
+			expectedOk: true,
+		},
+		{
+			name:   "offset exceeds the maximum valid offset by 1",
+			offset: uint64(memory.Size(testCtx)) - 4 + 1, // 4 is the length of v
+		},
+		{
+			name:   "offset larger than 32-bit",
+			offset: 1 << 32,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expectedOk, memory.Write64(testCtx, tc.offset, v))
+
+			buf, ok := memory.Read64(testCtx, tc.offset, uint64(len(v)))
+			require.Equal(t, tc.expectedOk, ok)
+			if tc.expectedOk {
+				require.Equal(t, v, buf)
+			}
+		})
+	}
+
+	t.Run("byteCount overflows on add", func(t *testing.T) {
+		_, ok := memory.Read64(testCtx, 4, math.MaxUint64-1)
+		require.False(t, ok)
+	})
+}
+
+func TestMemoryInstance_Typed64(t *testing.T) {
+	memory := &MemoryInstance{Buffer: make([]byte, 100), Memory64: true}
+	size := uint64(memory.Size(testCtx))
+
+	tests := []struct {
+		name        string
+		sizeInBytes uint64
+		write       func(offset uint64) bool
+		read        func(offset uint64) (interface{}, bool)
+		expected    interface{}
+	}{
+		{
+			name:        "byte",
+			sizeInBytes: 1,
+			write:       func(offset uint64) bool { return memory.WriteByte64(testCtx, offset, 0xfe) },
+			read:        func(offset uint64) (interface{}, bool) { return memory.ReadByte64(testCtx, offset) },
+			expected:    byte(0xfe),
+		},
+		{
+			name:        "uint16",
+			sizeInBytes: 2,
+			write:       func(offset uint64) bool { return memory.WriteUint16Le64(testCtx, offset, 0xfffe) },
+			read:        func(offset uint64) (interface{}, bool) { return memory.ReadUint16Le64(testCtx, offset) },
+			expected:    uint16(0xfffe),
+		},
+		{
+			name:        "uint32",
+			sizeInBytes: 4,
+			write:       func(offset uint64) bool { return memory.WriteUint32Le64(testCtx, offset, 0xfffffffe) },
+			read:        func(offset uint64) (interface{}, bool) { return memory.ReadUint32Le64(testCtx, offset) },
+			expected:    uint32(0xfffffffe),
+		},
+		{
+			name:        "float32",
+			sizeInBytes: 4,
+			write:       func(offset uint64) bool { return memory.WriteFloat32Le64(testCtx, offset, math.MaxFloat32) },
+			read:        func(offset uint64) (interface{}, bool) { return memory.ReadFloat32Le64(testCtx, offset) },
+			expected:    float32(math.MaxFloat32),
+		},
+		{
+			name:        "uint64",
+			sizeInBytes: 8,
+			write:       func(offset uint64) bool { return memory.WriteUint64Le64(testCtx, offset, math.MaxUint64-1) },
+			read:        func(offset uint64) (interface{}, bool) { return memory.ReadUint64Le64(testCtx, offset) },
+			expected:    uint64(math.MaxUint64 - 1),
+		},
+		{
+			name:        "float64",
+			sizeInBytes: 8,
+			write:       func(offset uint64) bool { return memory.WriteFloat64Le64(testCtx, offset, math.MaxFloat64) },
+			read:        func(offset uint64) (interface{}, bool) { return memory.ReadFloat64Le64(testCtx, offset) },
+			expected:    math.MaxFloat64,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			// The maximum boundary valid offset.
+			offset := size - tc.sizeInBytes
+			require.True(t, tc.write(offset))
+			v, ok := tc.read(offset)
+			require.True(t, ok)
+			require.Equal(t, tc.expected, v)
+
+			// The offset exceeds the maximum valid offset by 1, or is larger than 32-bit.
+			for _, offset := range []uint64{size - tc.sizeInBytes + 1, 1 << 32, math.MaxUint64} {
+				require.False(t, tc.write(offset))
+				_, ok = tc.read(offset)
+				require.False(t, ok)
+			}
+		})
+	}
+}
+
+func TestMemoryInstance_Atomic(t *testing.T) {
+	mem := &MemoryInstance{Buffer: make([]byte, 16)}
+
+	t.Run("store and load", func(t *testing.T) {
+		require.NoError(t, mem.AtomicStore(0, 8, 0x0102030405060708))
+		require.Equal(t, []byte{8, 7, 6, 5, 4, 3, 2, 1}, mem.Buffer[:8])
+		require.NoError(t, mem.AtomicStore(1, 1, 0xffaa))
+		require.NoError(t, mem.AtomicStore(2, 2, 0xffffbbcc))
+		require.Equal(t, []byte{8, 0xaa, 0xcc, 0xbb, 4, 3, 2, 1}, mem.Buffer[:8])
+
+		for _, tc := range []struct {
+			offset   uint64
+			size     uint32
+			expected uint64
+		}{
+			{offset: 0, size: 8, expected: 0x01020304bbccaa08},
+			{offset: 4, size: 4, expected: 0x01020304},
+			{offset: 2, size: 2, expected: 0xbbcc},
+			{offset: 1, size: 1, expected: 0xaa},
+		} {
+			v, err := mem.AtomicLoad(tc.offset, tc.size)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, v)
+		}
+	})
+
+	t.Run("rmw", func(t *testing.T) {
+		require.NoError(t, mem.AtomicStore(8, 8, 0))
+		old, err := mem.AtomicRMW(9, 1, func(old uint64) uint64 { return old - 1 })
+		require.NoError(t, err)
+		require.Equal(t, uint64(0), old)
+		old, err = mem.AtomicRMW(8, 4, func(old uint64) uint64 { return old + 1 })
+		require.NoError(t, err)
+		require.Equal(t, uint64(0xff00), old)
+		require.Equal(t, []byte{1, 0xff, 0, 0, 0, 0, 0, 0}, mem.Buffer[8:])
+	})
+
+	t.Run("compare exchange", func(t *testing.T) {
+		require.NoError(t, mem.AtomicStore(8, 8, 1))
+		// The expected value is truncated to the accessed size.
+		old, err := mem.AtomicCompareExchange(8, 2, 0xff0001, 2)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), old)
+		old, err = mem.AtomicCompareExchange(8, 2, 1, 3)
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), old)
+		require.Equal(t, byte(2), mem.Buffer[8])
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		_, err := mem.AtomicLoad(4, 8)
+		require.Equal(t, wasmruntime.ErrRuntimeUnalignedAtomic, err)
+		_, err = mem.AtomicRMW(16, 4, func(old uint64) uint64 { return old })
+		require.Equal(t, wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess, err)
+		err = mem.AtomicStore(math.MaxUint32+1, 1, 0)
+		require.Equal(t, wasmruntime.ErrRuntimeOutOfBoundsMemoryAccess, err)
+		_, err = mem.Wait(0, 4, 0, 0)
+		require.Equal(t, wasmruntime.ErrRuntimeExpectedSharedMemory, err)
+		n, err := mem.Notify(0, 1)
+		require.NoError(t, err)
+		require.Equal(t, uint32(0), n)
+	})
+}
+
+func TestMemoryInstance_WaitNotify(t *testing.T) {
+	mem := &MemoryInstance{Buffer: make([]byte, 16), Shared: true}
+
+	t.Run("not equal", func(t *testing.T) {
+		res, err := mem.Wait(8, 8, 1, -1)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), res)
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		res, err := mem.Wait(0, 4, 0, int64(time.Millisecond))
+		require.NoError(t, err)
+		require.Equal(t, uint64(2), res)
+		require.Equal(t, 0, len(mem.waiters[0]))
+	})
+
+	t.Run("notify", func(t *testing.T) {
+		const waiters = 3
+		results := make(chan uint64, waiters)
+		for i := 0; i < waiters; i++ {
+			go func() {
+				res, err := mem.Wait(4, 4, 0, -1)
+				require.NoError(t, err)
+				results <- res
+			}()
+		}
+
+		// Wait until all goroutines start waiting.
+		for {
+			mem.waitersMux.Lock()
+			n := len(mem.waiters[4])
+			mem.waitersMux.Unlock()
+			if n == waiters {
+				break
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		n, err := mem.Notify(4, 2)
+		require.NoError(t, err)
+		require.Equal(t, uint32(2), n)
+		require.Equal(t, uint64(0), <-results)
+		require.Equal(t, uint64(0), <-results)
+
+		n, err = mem.Notify(4, math.MaxUint32)
+		require.NoError(t, err)
+		require.Equal(t, uint32(1), n)
+		require.Equal(t, uint64(0), <-results)
+
+		n, err = mem.Notify(4, 1)
+		require.NoError(t, err)
+		require.Equal(t, uint32(0), n)
+	})
+}
+
+func TestMemoryInstance_Grow_Shared(t *testing.T) {
+	mem := NewMemoryInstance(&Memory{Min: 1, Cap: 3, Max: 3, IsShared: true})
+	buf := &mem.Buffer[0]
+
+	t.Run("concurrent atomics", func(t *testing.T) {
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			for i := 0; i < 1000; i++ {
+				_, err := mem.AtomicRMW(0, 4, func(old uint64) uint64 { return old + 1 })
+				require.NoError(t, err)
+			}
+		}()
+		_, ok := mem.Grow(testCtx, 1)
+		require.True(t, ok)
+		<-done
+
+		v, err := mem.AtomicLoad(0, 4)
+		require.NoError(t, err)
+		require.Equal(t, uint64(1000), v)
+	})
+
+	t.Run("buffer doesn't move", func(t *testing.T) {
+		_, ok := mem.Grow(testCtx, 1)
+		require.True(t, ok)
+		require.Equal(t, buf, &mem.Buffer[0])
+	})
+
+	t.Run("capacity less than max", func(t *testing.T) {
+		mem := NewMemoryInstance(&Memory{Min: 1, Cap: 1, Max: 2, IsShared: true})
+		_, ok := mem.Grow(testCtx, 1)
+		require.False(t, ok)
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/sha256"
 	"errors"
 	"fmt"
+	"io"
 	"reflect"
 	"sort"
 	"strings"
@@ -92,16 +93,17 @@ type Module struct {
 	// MemorySection contains each memory defined in this module.
 	//
 	// Note: The memory Index namespace begins with imported memories and ends with those defined in this module.
-	// For example, if there are two imported memories and one defined in this module, the memory Index 3 is defined in
-	// this module at TableSection[0].
+	// For example, if there are two imported memories and one defined in this module, the memory Index 2 is defined in
+	// this module at MemorySection[0].
 	//
 	// Note: Version 1.0 (20191205) of the WebAssembly spec allows at most one memory definition per module, so the
-	// length of the MemorySection can be zero or one, and can only be one if there is no imported memory.
+	// length of the MemorySection can be zero or one, and can only be one if there is no imported memory. Multiple
+	// memories require FeatureMultiMemory.
 	//
 	// Note: In the Binary Format, this is SectionIDMemory.
 	//
 	// See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#memory-section%E2%91%A0
-	MemorySection *Memory
+	MemorySection []*Memory
 
 	// GlobalSection contains each global defined in this module.
 	//
@@ -114,6 +116,16 @@ type Module struct {
 	// See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#global-section%E2%91%A0
 	GlobalSection []*Global
 
+	// TagSection contains the index in TypeSection of each exception tag defined in this module.
+	//
+	// Tag indexes are offset by any imported tags because the tag index space begins with imports, followed by ones
+	// defined in this module. The referenced function type must not have results.
+	//
+	// Note: In the Binary Format, this is SectionIDTag.
+	//
+	// See https://github.com/WebAssembly/exception-handling/blob/main/proposals/exception-handling/Exceptions.md#tag-section
+	TagSection []Index
+
 	// ExportSection contains each export defined in this module.
 	//
 	// Note: In the Binary Format, this is SectionIDExport.
@@ -175,6 +187,12 @@ type Module struct {
 	// See https://www.w3.org/TR/2022/WD-wasm-core-2-20220419/appendix/changes.html#bulk-memory-and-table-instructions
 	DataCountSection *uint32
 
+	// FuelCosts returns the fuel consumed by the instruction of the given name, such as "i32.add", or is nil when the
+	// functions of this module don't consume fuel.
+	//
+	// Note: This has no serialization format, and is set by wazero.RuntimeConfig WithFuelCosts.
+	FuelCosts func(instruction string) uint32
+
 	// ID is the sha256 value of the source code (text/binary) and is used for caching.
 	ID ModuleID
 }
@@ -226,6 +244,33 @@ func (m *Module) TypeOfFunction(funcIdx Index) *FunctionType {
 	return m.TypeSection[typeIdx]
 }
 
+// TypeOfTag returns the wasm.SectionIDType entry for the given tag namespace index or nil.
+// Note: The tag index namespace is preceded by imported tags.
+func (m *Module) TypeOfTag(tagIdx Index) *FunctionType {
+	typeSectionLength := uint32(len(m.TypeSection))
+	tagImportCount := Index(0)
+	for _, im := range m.ImportSection {
+		if im.Type == ExternTypeTag {
+			if tagIdx == tagImportCount {
+				if im.DescTag >= typeSectionLength {
+					return nil
+				}
+				return m.TypeSection[im.DescTag]
+			}
+			tagImportCount++
+		}
+	}
+	tagSectionIdx := tagIdx - tagImportCount
+	if tagSectionIdx >= uint32(len(m.TagSection)) {
+		return nil
+	}
+	typeIdx := m.TagSection[tagSectionIdx]
+	if typeIdx >= typeSectionLength {
+		return nil
+	}
+	return m.TypeSection[typeIdx]
+}
+
 func (m *Module) Validate(enabledFeatures Features) error {
 	if err := m.validateStartSection(); err != nil {
 		return err
@@ -235,7 +280,7 @@ func (m *Module) Validate(enabledFeatures Features) error {
 		return errors.New("cannot mix functions and host functions in the same module")
 	}
 
-	functions, globals, memory, tables, err := m.AllDeclarations()
+	functions, globals, memories, tables, err := m.AllDeclarations()
 	if err != nil {
 		return err
 	}
@@ -244,20 +289,24 @@ func (m *Module) Validate(enabledFeatures Features) error {
 		return err
 	}
 
+	if err = m.validateTags(enabledFeatures); err != nil {
+		return err
+	}
+
 	if err = m.validateGlobals(globals, uint32(len(functions)), MaximumGlobals); err != nil {
 		return err
 	}
 
-	if err = m.validateMemory(memory, globals, enabledFeatures); err != nil {
+	if err = m.validateMemory(memories, globals, enabledFeatures); err != nil {
 		return err
 	}
 
-	if err = m.validateExports(enabledFeatures, functions, globals, memory, tables); err != nil {
+	if err = m.validateExports(enabledFeatures, functions, globals, memories, tables); err != nil {
 		return err
 	}
 
 	if m.CodeSection != nil {
-		if err = m.validateFunctions(enabledFeatures, functions, globals, memory, tables, MaximumFunctionIndex); err != nil {
+		if err = m.validateFunctions(enabledFeatures, functions, globals, memories, tables, MaximumFunctionIndex); err != nil {
 			return err
 		}
 	} // No need to validate host functions as NewHostModule validates
@@ -304,7 +353,7 @@ func (m *Module) validateGlobals(globals []*GlobalType, numFuncts, maxGlobals ui
 	return nil
 }
 
-func (m *Module) validateFunctions(enabledFeatures Features, functions []Index, globals []*GlobalType, memory *Memory, tables []*Table, maximumFunctionIndex uint32) error {
+func (m *Module) validateFunctions(enabledFeatures Features, functions []Index, globals []*GlobalType, memories []*Memory, tables []*Table, maximumFunctionIndex uint32) error {
 	if uint32(len(functions)) > maximumFunctionIndex {
 		return fmt.Errorf("too many functions in a store")
 	}
@@ -330,7 +379,7 @@ func (m *Module) validateFunctions(enabledFeatures Features, functions []Index,
 			return fmt.Errorf("invalid %s: type section index %d out of range", m.funcDesc(SectionIDFunction, Index(idx)), typeIndex)
 		}
 
-		if err := m.validateFunction(enabledFeatures, Index(idx), functions, globals, memory, tables, declaredFuncIndexes); err != nil {
+		if err := m.validateFunction(enabledFeatures, Index(idx), functions, globals, memories, tables, declaredFuncIndexes); err != nil {
 			return fmt.Errorf("invalid %s: %w", m.funcDesc(SectionIDFunction, Index(idx)), err)
 		}
 	}
@@ -404,20 +453,23 @@ func (m *Module) funcDesc(sectionID SectionID, sectionIndex Index) string {
 	return fmt.Sprintf("%s[%d] export[%s]", sectionIDName, sectionIndex, strings.Join(exportNames, ","))
 }
 
-func (m *Module) validateMemory(memory *Memory, globals []*GlobalType, enabledFeatures Features) error {
-	var activeElementCount int
-	for _, sec := range m.DataSection {
-		if !sec.IsPassive() {
-			activeElementCount++
+func (m *Module) validateMemory(memories []*Memory, globals []*GlobalType, enabledFeatures Features) error {
+	if len(memories) > 1 {
+		if err := enabledFeatures.Require(FeatureMultiMemory); err != nil {
+			return fmt.Errorf("at most one memory allowed in module as %w", err)
 		}
 	}
-	if activeElementCount > 0 && memory == nil {
-		return fmt.Errorf("unknown memory")
-	}
 
 	for _, d := range m.DataSection {
 		if !d.IsPassive() {
-			if err := validateConstExpression(globals, 0, d.OffsetExpression, ValueTypeI32); err != nil {
+			if d.MemoryIndex >= uint32(len(memories)) {
+				if d.MemoryIndex == 0 {
+					return fmt.Errorf("unknown memory")
+				}
+				return fmt.Errorf("unknown memory %d as active data target", d.MemoryIndex)
+			}
+			// The offset is i64 when the memory is indexed with i64.
+			if err := validateConstExpression(globals, 0, d.OffsetExpression, memories[d.MemoryIndex].IndexType()); err != nil {
 				return fmt.Errorf("calculate offset: %w", err)
 			}
 		}
@@ -435,12 +487,36 @@ func (m *Module) validateImports(enabledFeatures Features) error {
 			if err := enabledFeatures.Require(FeatureMutableGlobal); err != nil {
 				return fmt.Errorf("invalid import[%q.%q] global: %w", i.Module, i.Name, err)
 			}
+		case ExternTypeTag:
+			if err := enabledFeatures.Require(FeatureExceptionHandling); err != nil {
+				return fmt.Errorf("invalid import[%q.%q] tag: %w", i.Module, i.Name, err)
+			}
 		}
 	}
 	return nil
 }
 
-func (m *Module) validateExports(enabledFeatures Features, functions []Index, globals []*GlobalType, memory *Memory, tables []*Table) error {
+// validateTags ensures each tag, imported or defined, references a function type without results.
+func (m *Module) validateTags(enabledFeatures Features) error {
+	if len(m.TagSection) > 0 {
+		if err := enabledFeatures.Require(FeatureExceptionHandling); err != nil {
+			return fmt.Errorf("%s section: %w", SectionIDName(SectionIDTag), err)
+		}
+	}
+	tagCount := m.ImportTagCount() + m.SectionElementCount(SectionIDTag)
+	for i := Index(0); i < tagCount; i++ {
+		ft := m.TypeOfTag(i)
+		if ft == nil {
+			return fmt.Errorf("invalid tag[%d]: type index out of range", i)
+		}
+		if len(ft.Results) > 0 {
+			return fmt.Errorf("invalid tag[%d]: must not have results: %s", i, ft)
+		}
+	}
+	return nil
+}
+
+func (m *Module) validateExports(enabledFeatures Features, functions []Index, globals []*GlobalType, memories []*Memory, tables []*Table) error {
 	for _, exp := range m.ExportSection {
 		index := exp.Index
 		switch exp.Type {
@@ -459,13 +535,17 @@ func (m *Module) validateExports(enabledFeatures Features, functions []Index, gl
 				return fmt.Errorf("invalid export[%q] global[%d]: %w", exp.Name, index, err)
 			}
 		case ExternTypeMemory:
-			if index > 0 || memory == nil {
+			if index >= uint32(len(memories)) {
 				return fmt.Errorf("memory for export[%q] out of range", exp.Name)
 			}
 		case ExternTypeTable:
 			if index >= uint32(len(tables)) {
 				return fmt.Errorf("table for export[%q] out of range", exp.Name)
 			}
+		case ExternTypeTag:
+			if index >= m.ImportTagCount()+m.SectionElementCount(SectionIDTag) {
+				return fmt.Errorf("unknown tag for export[%q]", exp.Name)
+			}
 		}
 	}
 	return nil
@@ -531,6 +611,10 @@ func validateConstExpression(globals []*GlobalType, numFuncs uint32, expr *Const
 			return fmt.Errorf("%s needs 16 bytes but was %d bytes", OpcodeVecV128ConstName, len(expr.Data))
 		}
 		actualType = ValueTypeV128
+	case OpcodeI32Add, OpcodeI32Sub, OpcodeI32Mul, OpcodeI64Add, OpcodeI64Sub, OpcodeI64Mul:
+		if actualType, err = validateExtendedConstExpression(globals, expr); err != nil {
+			return err
+		}
 	default:
 		return fmt.Errorf("invalid opcode for const expression: 0x%x", expr.Opcode)
 	}
@@ -542,6 +626,63 @@ func validateConstExpression(globals []*GlobalType, numFuncs uint32, expr *Const
 	return nil
 }
 
+// validateExtendedConstExpression type-checks the instructions of the ConstantExpression.IsExtended expression, and
+// returns the type of the only value it leaves on the stack.
+func validateExtendedConstExpression(globals []*GlobalType, expr *ConstantExpression) (ValueType, error) {
+	r := bytes.NewReader(expr.Data)
+	var stack []ValueType
+	for {
+		op, err := r.ReadByte()
+		if err == io.EOF {
+			op = expr.Opcode // The last instruction isn't in the data.
+		} else if err != nil {
+			return 0, err
+		}
+
+		switch op {
+		case OpcodeI32Const:
+			if _, _, err = leb128.DecodeInt32(r); err != nil {
+				return 0, fmt.Errorf("read i32: %w", err)
+			}
+			stack = append(stack, ValueTypeI32)
+		case OpcodeI64Const:
+			if _, _, err = leb128.DecodeInt64(r); err != nil {
+				return 0, fmt.Errorf("read i64: %w", err)
+			}
+			stack = append(stack, ValueTypeI64)
+		case OpcodeGlobalGet:
+			id, _, err := leb128.DecodeUint32(r)
+			if err != nil {
+				return 0, fmt.Errorf("read index of global: %w", err)
+			}
+			if uint32(len(globals)) <= id {
+				return 0, fmt.Errorf("global index out of range")
+			}
+			stack = append(stack, globals[id].ValType)
+		case OpcodeI32Add, OpcodeI32Sub, OpcodeI32Mul, OpcodeI64Add, OpcodeI64Sub, OpcodeI64Mul:
+			t := ValueTypeI32
+			if op >= OpcodeI64Add {
+				t = ValueTypeI64
+			}
+			if l := len(stack); l < 2 || stack[l-1] != t || stack[l-2] != t {
+				return 0, fmt.Errorf("type mismatch on %s in const expression", InstructionName(op))
+			}
+			stack = stack[:len(stack)-1]
+		default:
+			return 0, fmt.Errorf("invalid opcode for const expression: 0x%x", op)
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	if len(stack) != 1 {
+		return 0, fmt.Errorf("const expression must result in one value, but was %d", len(stack))
+	}
+	return stack[0], nil
+}
+
 func (m *Module) validateDataCountSection() (err error) {
 	if m.DataCountSection != nil && int(*m.DataCountSection) != len(m.DataSection) {
 		err = fmt.Errorf("data count section (%d) doesn't match the length of data section (%d)",
@@ -642,10 +783,9 @@ func paramNames(localNames IndirectNameMap, funcIdx uint32, paramLen int) []stri
 	return nil
 }
 
-func (m *Module) buildMemory() (mem *MemoryInstance) {
-	memSec := m.MemorySection
-	if memSec != nil {
-		mem = NewMemoryInstance(memSec)
+func (m *Module) buildMemories() (memories []*MemoryInstance) {
+	for _, memSec := range m.MemorySection {
+		memories = append(memories, NewMemoryInstance(memSec))
 	}
 	return
 }
@@ -747,6 +887,8 @@ type Import struct {
 	DescMem *Memory
 	// DescGlobal is the inlined GlobalType when Type equals ExternTypeGlobal
 	DescGlobal *GlobalType
+	// DescTag is the index in Module.TypeSection when Type equals ExternTypeTag
+	DescTag Index
 }
 
 // Memory describes the limits of pages (64KB) in a memory.
@@ -754,6 +896,18 @@ type Memory struct {
 	Min, Cap, Max uint32
 	// IsMaxEncoded true if the Max is encoded in the original source (binary or text).
 	IsMaxEncoded bool
+	// IsShared is true if the memory is shared between threads, which requires FeatureThreads.
+	IsShared bool
+	// IsMemory64 is true if the memory is indexed with i64 instead of i32, which requires FeatureMemory64.
+	IsMemory64 bool
+}
+
+// IndexType returns the type of addresses into this memory: ValueTypeI64 if IsMemory64, otherwise ValueTypeI32.
+func (m *Memory) IndexType() ValueType {
+	if m.IsMemory64 {
+		return ValueTypeI64
+	}
+	return ValueTypeI32
 }
 
 // Validate ensures values assigned to Min, Cap and Max are within valid thresholds.
@@ -789,11 +943,25 @@ type Global struct {
 	Init *ConstantExpression
 }
 
+// ConstantExpression is a single instruction, Opcode, followed by its immediates in Data.
+//
+// Note: When FeatureExtendedConst is used, an expression of several instructions is represented by its last one, which
+// is an arithmetic instruction without immediates. In that case, Data holds the encoded instructions before it.
+// See IsExtended
 type ConstantExpression struct {
 	Opcode Opcode
 	Data   []byte
 }
 
+// IsExtended returns true if this expression consists of several instructions as allowed by FeatureExtendedConst.
+func (e *ConstantExpression) IsExtended() bool {
+	switch e.Opcode {
+	case OpcodeI32Add, OpcodeI32Sub, OpcodeI32Mul, OpcodeI64Add, OpcodeI64Sub, OpcodeI64Mul:
+		return true
+	}
+	return false
+}
+
 // Export is the binary representation of an export indicated by Type
 // See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#binary-export
 type Export struct {
@@ -822,6 +990,10 @@ type Code struct {
 type DataSegment struct {
 	OffsetExpression *ConstantExpression
 	Init             []byte
+
+	// MemoryIndex is the index of the memory to which this data segment is applied.
+	// Note: This is used if and only if the data segment is active, and can be non-zero only with FeatureMultiMemory.
+	MemoryIndex Index
 }
 
 // IsPassive returns true if this data segment is "passive" in the sense that memory offset and
@@ -895,7 +1067,7 @@ type NameMapAssoc struct {
 }
 
 // AllDeclarations returns all declarations for functions, globals, memories and tables in a module including imported ones.
-func (m *Module) AllDeclarations() (functions []Index, globals []*GlobalType, memory *Memory, tables []*Table, err error) {
+func (m *Module) AllDeclarations() (functions []Index, globals []*GlobalType, memories []*Memory, tables []*Table, err error) {
 	for _, imp := range m.ImportSection {
 		switch imp.Type {
 		case ExternTypeFunc:
@@ -903,7 +1075,7 @@ func (m *Module) AllDeclarations() (functions []Index, globals []*GlobalType, me
 		case ExternTypeGlobal:
 			globals = append(globals, imp.DescGlobal)
 		case ExternTypeMemory:
-			memory = imp.DescMem
+			memories = append(memories, imp.DescMem)
 		case ExternTypeTable:
 			tables = append(tables, imp.DescTable)
 		}
@@ -913,19 +1085,90 @@ func (m *Module) AllDeclarations() (functions []Index, globals []*GlobalType, me
 	for _, g := range m.GlobalSection {
 		globals = append(globals, g.Type)
 	}
-	if m.MemorySection != nil {
-		if memory != nil { // shouldn't be possible due to Validate
-			err = errors.New("at most one table allowed in module")
-			return
-		}
-		memory = m.MemorySection
-	}
+	memories = append(memories, m.MemorySection...)
 	if m.TableSection != nil {
 		tables = append(tables, m.TableSection...)
 	}
 	return
 }
 
+// ImportDefinitions returns the api.ImportDefinition of each import in the import section, in order.
+func (m *Module) ImportDefinitions() []api.ImportDefinition {
+	ret := make([]api.ImportDefinition, 0, len(m.ImportSection))
+	for _, imp := range m.ImportSection {
+		d := api.ImportDefinition{Module: imp.Module, Name: imp.Name}
+		d.Type = imp.Type
+		switch imp.Type {
+		case ExternTypeFunc:
+			d.Func = functionTypeDefinition(m.TypeSection[imp.DescFunc])
+		case ExternTypeTable:
+			d.Table = tableTypeDefinition(imp.DescTable)
+		case ExternTypeMemory:
+			d.Memory = memoryTypeDefinition(imp.DescMem)
+		case ExternTypeGlobal:
+			d.Global = &api.GlobalType{ValueType: imp.DescGlobal.ValType, Mutable: imp.DescGlobal.Mutable}
+		case ExternTypeTag:
+			d.Tag = functionTypeDefinition(m.TypeSection[imp.DescTag])
+		}
+		ret = append(ret, d)
+	}
+	return ret
+}
+
+// ExportDefinitions returns the api.ExportDefinition of each export in the export section, in order.
+//
+// Note: This must be called on a validated module, as export indexes aren't checked.
+func (m *Module) ExportDefinitions() []api.ExportDefinition {
+	functions, globals, memories, tables, _ := m.AllDeclarations() // never errs
+	ret := make([]api.ExportDefinition, 0, len(m.ExportSection))
+	for _, exp := range m.ExportSection {
+		d := api.ExportDefinition{Name: exp.Name}
+		d.Type = exp.Type
+		switch exp.Type {
+		case ExternTypeFunc:
+			d.Func = functionTypeDefinition(m.TypeSection[functions[exp.Index]])
+		case ExternTypeTable:
+			d.Table = tableTypeDefinition(tables[exp.Index])
+		case ExternTypeMemory:
+			d.Memory = memoryTypeDefinition(memories[exp.Index])
+		case ExternTypeGlobal:
+			g := globals[exp.Index]
+			d.Global = &api.GlobalType{ValueType: g.ValType, Mutable: g.Mutable}
+		case ExternTypeTag:
+			d.Tag = functionTypeDefinition(m.TypeOfTag(exp.Index))
+		}
+		ret = append(ret, d)
+	}
+	return ret
+}
+
+func functionTypeDefinition(ft *FunctionType) *api.FunctionType {
+	// Copy the value types, so that callers can't modify the module.
+	return &api.FunctionType{
+		ParamTypes:  append([]ValueType{}, ft.Params...),
+		ResultTypes: append([]ValueType{}, ft.Results...),
+	}
+}
+
+func tableTypeDefinition(t *Table) *api.TableType {
+	ret := &api.TableType{ElementType: t.Type, Min: t.Min}
+	if t.Max != nil {
+		max := *t.Max
+		ret.Max = &max
+	}
+	return ret
+}
+
+func memoryTypeDefinition(mem *Memory) *api.MemoryType {
+	ret := &api.MemoryType{Min: mem.Min, Shared: mem.IsShared, Memory64: mem.IsMemory64}
+	// Max is otherwise set by the api.MemorySizer, which isn't part of the type.
+	if mem.IsMaxEncoded {
+		max := mem.Max
+		ret.Max = &max
+	}
+	return ret
+}
+
 // SectionID identifies the sections of a Module in the WebAssembly 1.0 (20191205) Binary Format.
 //
 // Note: these are defined in the wasm package, instead of the binary package, as a key per section is needed regardless
@@ -954,6 +1197,11 @@ const (
 	// See https://www.w3.org/TR/2022/WD-wasm-core-2-20220419/binary/modules.html#data-count-section
 	// See https://www.w3.org/TR/2022/WD-wasm-core-2-20220419/appendix/changes.html#bulk-memory-and-table-instructions
 	SectionIDDataCount
+
+	// SectionIDTag may exist in WebAssembly 1.0 with FeatureExceptionHandling enabled.
+	//
+	// See https://github.com/WebAssembly/exception-handling/blob/main/proposals/exception-handling/Exceptions.md#tag-section
+	SectionIDTag
 )
 
 // SectionIDHostFunction is a pseudo-section ID for host functions.
@@ -993,6 +1241,8 @@ func SectionIDName(sectionID SectionID) string {
 		return "host_function"
 	case SectionIDDataCount:
 		return "data_count"
+	case SectionIDTag:
+		return "tag"
 	}
 	return "unknown"
 }
@@ -1006,17 +1256,14 @@ const (
 	ValueTypeF32 = api.ValueTypeF32
 	ValueTypeF64 = api.ValueTypeF64
 	// TODO: ValueTypeV128 is not exposed in the api pkg yet.
-	ValueTypeV128 = 0x7b
-	// TODO: ValueTypeFuncref is not exposed in the api pkg yet.
-	ValueTypeFuncref   ValueType = 0x70
-	ValueTypeExternref           = api.ValueTypeExternref
+	ValueTypeV128      = 0x7b
+	ValueTypeFuncref   = api.ValueTypeFuncref
+	ValueTypeExternref = api.ValueTypeExternref
 )
 
 // ValueTypeName is an alias of api.ValueTypeName defined to simplify imports.
 func ValueTypeName(t ValueType) string {
-	if t == ValueTypeFuncref {
-		return "funcref"
-	} else if t == ValueTypeV128 {
+	if t == ValueTypeV128 {
 		return "v128"
 	}
 	return api.ValueTypeName(t)
@@ -1038,6 +1285,8 @@ const (
 	ExternTypeMemoryName = api.ExternTypeMemoryName
 	ExternTypeGlobal     = api.ExternTypeGlobal
 	ExternTypeGlobalName = api.ExternTypeGlobalName
+	ExternTypeTag        = api.ExternTypeTag
+	ExternTypeTagName    = api.ExternTypeTagName
 )
 
 // ExternTypeName is an alias of api.ExternTypeName defined to simplify imports.
@@ -58,6 +58,7 @@ func TestSectionIDName(t *testing.T) {
 		{"code", SectionIDCode, "code"},
 		{"data", SectionIDData, "data"},
 		{"host_function", SectionIDHostFunction, "host_function"},
+		{"tag", SectionIDTag, "tag"},
 		{"unknown", 100, "unknown"},
 	}
 
@@ -126,7 +127,7 @@ func TestModule_allDeclarations(t *testing.T) {
 		module            *Module
 		expectedFunctions []Index
 		expectedGlobals   []*GlobalType
-		expectedMemory    *Memory
+		expectedMemories  []*Memory
 		expectedTables    []*Table
 	}{
 		// Functions.
@@ -174,13 +175,20 @@ func TestModule_allDeclarations(t *testing.T) {
 			module: &Module{
 				ImportSection: []*Import{{Type: ExternTypeMemory, DescMem: &Memory{Min: 1, Max: 10}}},
 			},
-			expectedMemory: &Memory{Min: 1, Max: 10},
+			expectedMemories: []*Memory{{Min: 1, Max: 10}},
 		},
 		{
 			module: &Module{
-				MemorySection: &Memory{Min: 100},
+				MemorySection: []*Memory{{Min: 100}},
 			},
-			expectedMemory: &Memory{Min: 100},
+			expectedMemories: []*Memory{{Min: 100}},
+		},
+		{
+			module: &Module{
+				ImportSection: []*Import{{Type: ExternTypeMemory, DescMem: &Memory{Min: 1, Max: 10}}},
+				MemorySection: []*Memory{{Min: 100}},
+			},
+			expectedMemories: []*Memory{{Min: 1, Max: 10}, {Min: 100}},
 		},
 		// Tables.
 		{
@@ -200,16 +208,117 @@ func TestModule_allDeclarations(t *testing.T) {
 	for i, tt := range tests {
 		tc := tt
 		t.Run(fmt.Sprintf("%d", i), func(t *testing.T) {
-			functions, globals, memory, tables, err := tc.module.AllDeclarations()
+			functions, globals, memories, tables, err := tc.module.AllDeclarations()
 			require.NoError(t, err)
 			require.Equal(t, tc.expectedFunctions, functions)
 			require.Equal(t, tc.expectedGlobals, globals)
 			require.Equal(t, tc.expectedTables, tables)
-			require.Equal(t, tc.expectedMemory, memory)
+			require.Equal(t, tc.expectedMemories, memories)
 		})
 	}
 }
 
+func TestModule_ImportDefinitions(t *testing.T) {
+	max := uint32(20)
+	m := &Module{
+		TypeSection: []*FunctionType{v_v, {Params: []ValueType{i32}, Results: []ValueType{i64}}},
+		ImportSection: []*Import{
+			{Type: ExternTypeFunc, Module: "env", Name: "f", DescFunc: 1},
+			{Type: ExternTypeTable, Module: "env", Name: "t", DescTable: &Table{Min: 10, Max: &max, Type: RefTypeExternref}},
+			{Type: ExternTypeMemory, Module: "env", Name: "m1", DescMem: &Memory{Min: 1, Cap: 1, Max: MemoryLimitPages}},
+			{Type: ExternTypeMemory, Module: "env", Name: "m2", DescMem: &Memory{Min: 1, Cap: 2, Max: 2, IsMaxEncoded: true, IsShared: true}},
+			{Type: ExternTypeGlobal, Module: "env", Name: "g", DescGlobal: &GlobalType{ValType: ValueTypeF64, Mutable: true}},
+			{Type: ExternTypeTag, Module: "env", Name: "e", DescTag: 0},
+		},
+	}
+
+	memoryMax := uint32(2)
+	require.Equal(t, []api.ImportDefinition{
+		{Module: "env", Name: "f", ExternDefinition: api.ExternDefinition{
+			Type: ExternTypeFunc,
+			Func: &api.FunctionType{ParamTypes: []ValueType{i32}, ResultTypes: []ValueType{i64}},
+		}},
+		{Module: "env", Name: "t", ExternDefinition: api.ExternDefinition{
+			Type:  ExternTypeTable,
+			Table: &api.TableType{ElementType: ValueTypeExternref, Min: 10, Max: &max},
+		}},
+		{Module: "env", Name: "m1", ExternDefinition: api.ExternDefinition{
+			Type:   ExternTypeMemory,
+			Memory: &api.MemoryType{Min: 1}, // Max wasn't encoded
+		}},
+		{Module: "env", Name: "m2", ExternDefinition: api.ExternDefinition{
+			Type:   ExternTypeMemory,
+			Memory: &api.MemoryType{Min: 1, Max: &memoryMax, Shared: true},
+		}},
+		{Module: "env", Name: "g", ExternDefinition: api.ExternDefinition{
+			Type:   ExternTypeGlobal,
+			Global: &api.GlobalType{ValueType: ValueTypeF64, Mutable: true},
+		}},
+		{Module: "env", Name: "e", ExternDefinition: api.ExternDefinition{
+			Type: ExternTypeTag,
+			Tag:  &api.FunctionType{ParamTypes: []ValueType{}, ResultTypes: []ValueType{}},
+		}},
+	}, m.ImportDefinitions())
+
+	// Modifying the result doesn't modify the module.
+	m.ImportDefinitions()[0].Func.ParamTypes[0] = ValueTypeF32
+	require.Equal(t, []ValueType{i32}, m.TypeSection[1].Params)
+
+	require.Equal(t, []api.ImportDefinition{}, (&Module{}).ImportDefinitions())
+}
+
+func TestModule_ExportDefinitions(t *testing.T) {
+	m := &Module{
+		TypeSection: []*FunctionType{v_v, {Params: []ValueType{i32}, Results: []ValueType{i64}}},
+		ImportSection: []*Import{
+			{Type: ExternTypeFunc, DescFunc: 0},
+			{Type: ExternTypeGlobal, DescGlobal: &GlobalType{ValType: ValueTypeI32}},
+		},
+		FunctionSection: []Index{1},
+		TableSection:    []*Table{{Min: 1, Type: RefTypeFuncref}},
+		MemorySection:   []*Memory{{Min: 1, Cap: 1, Max: 1, IsMemory64: true}},
+		GlobalSection:   []*Global{{Type: &GlobalType{ValType: ValueTypeI64, Mutable: true}}},
+		TagSection:      []Index{1},
+		ExportSection: []*Export{
+			{Type: ExternTypeFunc, Name: "imported", Index: 0},
+			{Type: ExternTypeFunc, Name: "f", Index: 1},
+			{Type: ExternTypeTable, Name: "t", Index: 0},
+			{Type: ExternTypeMemory, Name: "m", Index: 0},
+			{Type: ExternTypeGlobal, Name: "g", Index: 1},
+			{Type: ExternTypeTag, Name: "e", Index: 0},
+		},
+	}
+
+	require.Equal(t, []api.ExportDefinition{
+		{Name: "imported", ExternDefinition: api.ExternDefinition{
+			Type: ExternTypeFunc,
+			Func: &api.FunctionType{ParamTypes: []ValueType{}, ResultTypes: []ValueType{}},
+		}},
+		{Name: "f", ExternDefinition: api.ExternDefinition{
+			Type: ExternTypeFunc,
+			Func: &api.FunctionType{ParamTypes: []ValueType{i32}, ResultTypes: []ValueType{i64}},
+		}},
+		{Name: "t", ExternDefinition: api.ExternDefinition{
+			Type:  ExternTypeTable,
+			Table: &api.TableType{ElementType: ValueTypeFuncref, Min: 1},
+		}},
+		{Name: "m", ExternDefinition: api.ExternDefinition{
+			Type:   ExternTypeMemory,
+			Memory: &api.MemoryType{Min: 1, Memory64: true},
+		}},
+		{Name: "g", ExternDefinition: api.ExternDefinition{
+			Type:   ExternTypeGlobal,
+			Global: &api.GlobalType{ValueType: ValueTypeI64, Mutable: true},
+		}},
+		{Name: "e", ExternDefinition: api.ExternDefinition{
+			Type: ExternTypeTag,
+			Tag:  &api.FunctionType{ParamTypes: []ValueType{i32}, ResultTypes: []ValueType{i64}},
+		}},
+	}, m.ExportDefinitions())
+
+	require.Equal(t, []api.ExportDefinition{}, (&Module{}).ExportDefinitions())
+}
+
 func TestValidateConstExpression(t *testing.T) {
 	t.Run("invalid opcode", func(t *testing.T) {
 		expr := &ConstantExpression{Opcode: OpcodeNop}
@@ -323,6 +432,83 @@ func TestValidateConstExpression(t *testing.T) {
 			}
 		})
 	})
+	t.Run("extended", func(t *testing.T) {
+		globals := []*GlobalType{{ValType: ValueTypeI32}, {ValType: ValueTypeI64}}
+		tests := []struct {
+			name        string
+			expr        *ConstantExpression
+			expected    ValueType
+			expectedErr string
+		}{
+			{
+				name: "i32",
+				// global.get 0 + i32.const 16 * i32.const 2
+				expr: &ConstantExpression{Opcode: OpcodeI32Add, Data: []byte{
+					OpcodeGlobalGet, 0, OpcodeI32Const, 16, OpcodeI32Const, 2, OpcodeI32Mul,
+				}},
+				expected: ValueTypeI32,
+			},
+			{
+				name: "i64",
+				expr: &ConstantExpression{Opcode: OpcodeI64Sub, Data: []byte{
+					OpcodeGlobalGet, 1, OpcodeI64Const, 1,
+				}},
+				expected: ValueTypeI64,
+			},
+			{
+				name: "type mismatch",
+				expr: &ConstantExpression{Opcode: OpcodeI32Add, Data: []byte{
+					OpcodeGlobalGet, 1, OpcodeI32Const, 1,
+				}},
+				expected:    ValueTypeI32,
+				expectedErr: "type mismatch on i32.add in const expression",
+			},
+			{
+				name:        "result type mismatch",
+				expr:        &ConstantExpression{Opcode: OpcodeI64Add, Data: []byte{OpcodeI64Const, 1, OpcodeI64Const, 1}},
+				expected:    ValueTypeI32,
+				expectedErr: "const expression type mismatch expected i32 but got i64",
+			},
+			{
+				name:        "stack underflow",
+				expr:        &ConstantExpression{Opcode: OpcodeI32Add, Data: []byte{OpcodeI32Const, 1}},
+				expected:    ValueTypeI32,
+				expectedErr: "type mismatch on i32.add in const expression",
+			},
+			{
+				name: "too many values",
+				expr: &ConstantExpression{Opcode: OpcodeI32Add, Data: []byte{
+					OpcodeI32Const, 1, OpcodeI32Const, 1, OpcodeI32Const, 1,
+				}},
+				expected:    ValueTypeI32,
+				expectedErr: "const expression must result in one value, but was 2",
+			},
+			{
+				name:        "global index out of range",
+				expr:        &ConstantExpression{Opcode: OpcodeI32Add, Data: []byte{OpcodeGlobalGet, 2, OpcodeI32Const, 1}},
+				expected:    ValueTypeI32,
+				expectedErr: "global index out of range",
+			},
+			{
+				name:        "invalid opcode",
+				expr:        &ConstantExpression{Opcode: OpcodeI32Add, Data: []byte{OpcodeF32Const, 0, 0, 0, 0}},
+				expected:    ValueTypeI32,
+				expectedErr: "invalid opcode for const expression: 0x43",
+			},
+		}
+
+		for _, tt := range tests {
+			tc := tt
+			t.Run(tc.name, func(t *testing.T) {
+				err := validateConstExpression(globals, 0, tc.expr, tc.expected)
+				if tc.expectedErr == "" {
+					require.NoError(t, err)
+				} else {
+					require.EqualError(t, err, tc.expectedErr)
+				}
+			})
+		}
+	})
 }
 
 func TestModule_Validate_Errors(t *testing.T) {
@@ -562,7 +748,7 @@ func TestModule_validateMemory(t *testing.T) {
 				Opcode: OpcodeUnreachable, // Invalid!
 			},
 		}}}
-		err := m.validateMemory(&Memory{}, nil, Features20191205)
+		err := m.validateMemory([]*Memory{{}}, nil, Features20191205)
 		require.EqualError(t, err, "calculate offset: invalid opcode for const expression: 0x0")
 	})
 	t.Run("ok", func(t *testing.T) {
@@ -573,9 +759,41 @@ func TestModule_validateMemory(t *testing.T) {
 				Data:   leb128.EncodeInt32(1),
 			},
 		}}}
-		err := m.validateMemory(&Memory{}, nil, Features20191205)
+		err := m.validateMemory([]*Memory{{}}, nil, Features20191205)
 		require.NoError(t, err)
 	})
+	t.Run("multiple memories", func(t *testing.T) {
+		m := Module{}
+		err := m.validateMemory([]*Memory{{}, {}}, nil, Features20191205)
+		require.EqualError(t, err, `at most one memory allowed in module as feature "multi-memory" is disabled`)
+
+		err = m.validateMemory([]*Memory{{}, {}}, nil, FeatureMultiMemory)
+		require.NoError(t, err)
+	})
+	t.Run("data segment memory index out of range", func(t *testing.T) {
+		m := Module{DataSection: []*DataSegment{{
+			OffsetExpression: &ConstantExpression{
+				Opcode: OpcodeI32Const,
+				Data:   leb128.EncodeInt32(1),
+			},
+			MemoryIndex: 2,
+		}}}
+		err := m.validateMemory([]*Memory{{}, {}}, nil, FeatureMultiMemory)
+		require.EqualError(t, err, "unknown memory 2 as active data target")
+	})
+	t.Run("memory64 data segment offset", func(t *testing.T) {
+		m := Module{DataSection: []*DataSegment{{
+			OffsetExpression: &ConstantExpression{
+				Opcode: OpcodeI64Const,
+				Data:   leb128.EncodeInt64(1),
+			},
+		}}}
+		err := m.validateMemory([]*Memory{{IsMemory64: true}}, nil, FeatureMemory64)
+		require.NoError(t, err)
+
+		err = m.validateMemory([]*Memory{{}}, nil, FeatureMemory64)
+		require.EqualError(t, err, "calculate offset: const expression type mismatch expected i32 but got i64")
+	})
 }
 
 func TestModule_validateImports(t *testing.T) {
@@ -622,6 +840,12 @@ func TestModule_validateImports(t *testing.T) {
 				DescMem: &Memory{Min: 1},
 			},
 		},
+		{
+			name:            "tag disabled",
+			enabledFeatures: Features20220419,
+			i:               &Import{Module: "m", Name: "n", Type: ExternTypeTag, DescTag: 0},
+			expectedErr:     `invalid import["m"."n"] tag: feature "exception-handling" is disabled`,
+		},
 	}
 
 	for _, tt := range tests {
@@ -641,6 +865,59 @@ func TestModule_validateImports(t *testing.T) {
 	}
 }
 
+func TestModule_validateTags(t *testing.T) {
+	tests := []struct {
+		name            string
+		enabledFeatures Features
+		m               *Module
+		expectedErr     string
+	}{
+		{name: "no tags", enabledFeatures: Features20220419, m: &Module{}},
+		{
+			name:            "imported and defined",
+			enabledFeatures: FeatureExceptionHandling,
+			m: &Module{
+				TypeSection:   []*FunctionType{v_v, i32_v},
+				ImportSection: []*Import{{Type: ExternTypeTag, DescTag: 1}},
+				TagSection:    []Index{0},
+			},
+		},
+		{
+			name:            "disabled",
+			enabledFeatures: Features20220419,
+			m:               &Module{TypeSection: []*FunctionType{v_v}, TagSection: []Index{0}},
+			expectedErr:     `tag section: feature "exception-handling" is disabled`,
+		},
+		{
+			name:            "type out of range",
+			enabledFeatures: FeatureExceptionHandling,
+			m:               &Module{TypeSection: []*FunctionType{v_v}, TagSection: []Index{1}},
+			expectedErr:     "invalid tag[0]: type index out of range",
+		},
+		{
+			name:            "results",
+			enabledFeatures: FeatureExceptionHandling,
+			m: &Module{
+				TypeSection:   []*FunctionType{v_v, i32_i32},
+				ImportSection: []*Import{{Type: ExternTypeTag, DescTag: 1}},
+			},
+			expectedErr: "invalid tag[0]: must not have results: i32_i32",
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.m.validateTags(tc.enabledFeatures)
+			if tc.expectedErr != "" {
+				require.EqualError(t, err, tc.expectedErr)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
 func TestModule_validateExports(t *testing.T) {
 	tests := []struct {
 		name            string
@@ -648,7 +925,7 @@ func TestModule_validateExports(t *testing.T) {
 		exportSection   []*Export
 		functions       []Index
 		globals         []*GlobalType
-		memory          *Memory
+		memories        []*Memory
 		tables          []*Table
 		expectedErr     string
 	}{
@@ -715,7 +992,13 @@ func TestModule_validateExports(t *testing.T) {
 			name:            "memory",
 			enabledFeatures: Features20191205,
 			exportSection:   []*Export{{Type: ExternTypeMemory, Index: 0}},
-			memory:          &Memory{},
+			memories:        []*Memory{{}},
+		},
+		{
+			name:            "memory index one",
+			enabledFeatures: FeatureMultiMemory,
+			exportSection:   []*Export{{Type: ExternTypeMemory, Index: 1}},
+			memories:        []*Memory{{}, {}},
 		},
 		{
 			name:            "memory out of range",
@@ -730,7 +1013,7 @@ func TestModule_validateExports(t *testing.T) {
 		tc := tt
 		t.Run(tc.name, func(t *testing.T) {
 			m := Module{ExportSection: tc.exportSection}
-			err := m.validateExports(tc.enabledFeatures, tc.functions, tc.globals, tc.memory, tc.tables)
+			err := m.validateExports(tc.enabledFeatures, tc.functions, tc.globals, tc.memories, tc.tables)
 			if tc.expectedErr != "" {
 				require.EqualError(t, err, tc.expectedErr)
 			} else {
@@ -797,19 +1080,21 @@ func TestModule_buildFunctions(t *testing.T) {
 	}
 }
 
-func TestModule_buildMemoryInstance(t *testing.T) {
+func TestModule_buildMemories(t *testing.T) {
 	t.Run("nil", func(t *testing.T) {
 		m := Module{}
-		mem := m.buildMemory()
-		require.Nil(t, mem)
+		memories := m.buildMemories()
+		require.Nil(t, memories)
 	})
 	t.Run("non-nil", func(t *testing.T) {
 		min := uint32(1)
 		max := uint32(10)
-		m := Module{MemorySection: &Memory{Min: min, Cap: min, Max: max}}
-		mem := m.buildMemory()
-		require.Equal(t, min, mem.Min)
-		require.Equal(t, max, mem.Max)
+		m := Module{MemorySection: []*Memory{{Min: min, Cap: min, Max: max}, {Max: max}}}
+		memories := m.buildMemories()
+		require.Equal(t, 2, len(memories))
+		require.Equal(t, min, memories[0].Min)
+		require.Equal(t, max, memories[0].Max)
+		require.Equal(t, uint32(0), memories[1].Min)
 	})
 }
 
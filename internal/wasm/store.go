@@ -63,6 +63,7 @@ type (
 		Functions []*FunctionInstance
 		Globals   []*GlobalInstance
 		// Memory is set when Module.MemorySection had a memory, regardless of whether it was exported.
+		// Note: This is the memory at index zero, which is also Memories[0].
 		Memory *MemoryInstance
 		Tables []*TableInstance
 		Types  []*FunctionType
@@ -86,6 +87,13 @@ type (
 		// ElementInstances holds the element instance, and each holds the references to either functions
 		// or external objects (unimplemented).
 		ElementInstances []ElementInstance
+
+		// Tags holds the exception tags in the tag index namespace, beginning with imports.
+		Tags []*TagInstance
+
+		// Memories holds the memories in the memory index namespace, beginning with imports. This has more than one
+		// memory only with FeatureMultiMemory.
+		Memories []*MemoryInstance
 	}
 
 	// DataInstance holds bytes corresponding to the data segment in a module.
@@ -104,6 +112,7 @@ type (
 		Global   *GlobalInstance
 		Memory   *MemoryInstance
 		Table    *TableInstance
+		Tag      *TagInstance
 	}
 
 	// FunctionInstance represents a function instance in a Store.
@@ -129,6 +138,9 @@ type (
 		// specific to Wasm functions.
 		GoFunc *reflect.Value
 
+		// GoModuleFunc is set when Kind == FunctionKindGoModuleFunc, so that engines can call it without reflection.
+		GoModuleFunc api.GoModuleFunc
+
 		// Fields above here are settable prior to instantiation. Below are set by the Store during instantiation.
 
 		// ModuleInstance holds the pointer to the module instance to which this function belongs.
@@ -169,12 +181,26 @@ type (
 		// ^^ TODO: this should be guarded with atomics when mutable
 	}
 
+	// TagInstance represents an exception tag in a store. Tags are compared by identity: an exception thrown with one
+	// instance is only caught by handlers of that same instance, even when another tag has an equal type.
+	//
+	// See https://github.com/WebAssembly/exception-handling/blob/main/proposals/exception-handling/Exceptions.md#tags
+	TagInstance struct {
+		// Type is the signature of the tag, which never has results.
+		Type *FunctionType
+	}
+
 	// FunctionTypeID is a uniquely assigned integer for a function type.
 	// This is wazero specific runtime object and specific to a store,
 	// and used at runtime to do type-checks on indirect function calls.
 	FunctionTypeID uint32
 )
 
+// ParamTypes implements the same method as documented on api.Tag.
+func (t *TagInstance) ParamTypes() []ValueType {
+	return t.Type.Params
+}
+
 // Index implements the same method as documented on experimental.FunctionDefinition.
 func (f *FunctionInstance) Index() uint32 {
 	return f.Idx
@@ -207,8 +233,8 @@ const (
 
 // addSections adds section elements to the ModuleInstance
 func (m *ModuleInstance) addSections(module *Module, importedFunctions, functions []*FunctionInstance,
-	importedGlobals, globals []*GlobalInstance, tables []*TableInstance, memory, importedMemory *MemoryInstance,
-	types []*FunctionType, typeIDs []FunctionTypeID) {
+	importedGlobals, globals []*GlobalInstance, tables []*TableInstance, importedMemories, memories []*MemoryInstance,
+	importedTags []*TagInstance, types []*FunctionType, typeIDs []FunctionTypeID) {
 
 	m.Types = types
 	m.TypeIDs = typeIDs
@@ -226,10 +252,15 @@ func (m *ModuleInstance) addSections(module *Module, importedFunctions, function
 
 	m.Tables = tables
 
-	if importedMemory != nil {
-		m.Memory = importedMemory
-	} else {
-		m.Memory = memory
+	m.Tags = append(m.Tags, importedTags...)
+	for _, typeIdx := range module.TagSection {
+		m.Tags = append(m.Tags, &TagInstance{Type: types[typeIdx]})
+	}
+
+	m.Memories = append(m.Memories, importedMemories...)
+	m.Memories = append(m.Memories, memories...)
+	if len(m.Memories) > 0 {
+		m.Memory = m.Memories[0]
 	}
 
 	m.buildExports(module.ExportSection)
@@ -264,9 +295,11 @@ func (m *ModuleInstance) buildExports(exports []*Export) {
 		case ExternTypeGlobal:
 			ei = &ExportInstance{Type: exp.Type, Global: m.Globals[index]}
 		case ExternTypeMemory:
-			ei = &ExportInstance{Type: exp.Type, Memory: m.Memory}
+			ei = &ExportInstance{Type: exp.Type, Memory: m.Memories[index]}
 		case ExternTypeTable:
 			ei = &ExportInstance{Type: exp.Type, Table: m.Tables[index]}
+		case ExternTypeTag:
+			ei = &ExportInstance{Type: exp.Type, Tag: m.Tags[index]}
 		}
 
 		// We already validated the duplicates during module validation phase.
@@ -277,9 +310,8 @@ func (m *ModuleInstance) buildExports(exports []*Export) {
 func (m *ModuleInstance) validateData(data []*DataSegment) (err error) {
 	for i, d := range data {
 		if !d.IsPassive() {
-			offset := int(executeConstExpression(m.Globals, d.OffsetExpression).(int32))
-			ceil := offset + len(d.Init)
-			if offset < 0 || ceil > len(m.Memory.Buffer) {
+			offset := dataOffset(m.Globals, d)
+			if offset < 0 || offset > int64(len(m.Memories[d.MemoryIndex].Buffer)-len(d.Init)) {
 				return fmt.Errorf("%s[%d] out of bounds memory access", SectionIDName(SectionIDElement), i)
 			}
 		}
@@ -290,16 +322,27 @@ func (m *ModuleInstance) validateData(data []*DataSegment) (err error) {
 func (m *ModuleInstance) applyData(data []*DataSegment) error {
 	for i, d := range data {
 		if !d.IsPassive() {
-			offset := executeConstExpression(m.Globals, d.OffsetExpression).(int32)
-			if offset < 0 || int(offset)+len(d.Init) > len(m.Memory.Buffer) {
+			offset := dataOffset(m.Globals, d)
+			mem := m.Memories[d.MemoryIndex]
+			if offset < 0 || offset > int64(len(mem.Buffer)-len(d.Init)) {
 				return fmt.Errorf("%s[%d] out of bounds memory access", SectionIDName(SectionIDElement), i)
 			}
-			copy(m.Memory.Buffer[offset:], d.Init)
+			copy(mem.Buffer[offset:], d.Init)
 		}
 	}
 	return nil
 }
 
+// dataOffset returns the offset of the active data segment into the memory, which is i64 instead of i32 when the memory
+// is indexed with i64.
+func dataOffset(globals []*GlobalInstance, d *DataSegment) int64 {
+	v := executeConstExpression(globals, d.OffsetExpression)
+	if offset, ok := v.(int32); ok {
+		return int64(offset)
+	}
+	return v.(int64)
+}
+
 // GetExport returns an export of the given name and type or errs if not exported or the wrong type.
 func (m *ModuleInstance) getExport(name string, et ExternType) (*ExportInstance, error) {
 	exp, ok := m.Exports[name]
@@ -352,7 +395,7 @@ func (s *Store) Instantiate(
 		return nil, err
 	}
 
-	importedFunctions, importedGlobals, importedTables, importedMemory, err := s.resolveImports(module)
+	importedFunctions, importedGlobals, importedTables, importedMemories, importedTags, err := s.resolveImports(module)
 	if err != nil {
 		s.deleteModule(name)
 		return nil, err
@@ -365,7 +408,7 @@ func (s *Store) Instantiate(
 		s.deleteModule(name)
 		return nil, err
 	}
-	globals, memory := module.buildGlobals(importedGlobals), module.buildMemory()
+	globals, memories := module.buildGlobals(importedGlobals), module.buildMemories()
 
 	// If there are no module-defined functions, assume this is a host module.
 	var functions []*FunctionInstance
@@ -380,7 +423,7 @@ func (s *Store) Instantiate(
 
 	// Now we have all instances from imports and local ones, so ready to create a new ModuleInstance.
 	m := &ModuleInstance{Name: name}
-	m.addSections(module, importedFunctions, functions, importedGlobals, globals, tables, importedMemory, memory, module.TypeSection, typeIDs)
+	m.addSections(module, importedFunctions, functions, importedGlobals, globals, tables, importedMemories, memories, importedTags, module.TypeSection, typeIDs)
 
 	// As of reference types proposal, data segment validation must happen after instantiation,
 	// and the side effect must persist even if there's out of bounds error after instantiation.
@@ -480,7 +523,7 @@ func (s *Store) module(moduleName string) *ModuleInstance {
 
 func (s *Store) resolveImports(module *Module) (
 	importedFunctions []*FunctionInstance, importedGlobals []*GlobalInstance,
-	importedTables []*TableInstance, importedMemory *MemoryInstance,
+	importedTables []*TableInstance, importedMemories []*MemoryInstance, importedTags []*TagInstance,
 	err error,
 ) {
 	s.mux.RLock()
@@ -543,7 +586,7 @@ func (s *Store) resolveImports(module *Module) (
 			importedTables = append(importedTables, importedTable)
 		case ExternTypeMemory:
 			expected := i.DescMem
-			importedMemory = imported.Memory
+			importedMemory := imported.Memory
 
 			if expected.Min > memoryBytesNumToPages(uint64(len(importedMemory.Buffer))) {
 				err = errorMinSizeMismatch(i, idx, expected.Min, importedMemory.Min)
@@ -554,6 +597,19 @@ func (s *Store) resolveImports(module *Module) (
 				err = errorMaxSizeMismatch(i, idx, expected.Max, importedMemory.Max)
 				return
 			}
+
+			if expected.IsShared != importedMemory.Shared {
+				err = errorInvalidImport(i, idx, fmt.Errorf("shared mismatch: %t != %t",
+					expected.IsShared, importedMemory.Shared))
+				return
+			}
+
+			if expected.IsMemory64 != importedMemory.Memory64 {
+				err = errorInvalidImport(i, idx, fmt.Errorf("index type mismatch: %s != %s",
+					ValueTypeName(expected.IndexType()), ValueTypeName(importedMemory.indexType())))
+				return
+			}
+			importedMemories = append(importedMemories, importedMemory)
 		case ExternTypeGlobal:
 			expected := i.DescGlobal
 			importedGlobal := imported.Global
@@ -570,6 +626,21 @@ func (s *Store) resolveImports(module *Module) (
 				return
 			}
 			importedGlobals = append(importedGlobals, importedGlobal)
+		case ExternTypeTag:
+			typeIndex := i.DescTag
+			if int(typeIndex) >= len(module.TypeSection) {
+				err = errorInvalidImport(i, idx, fmt.Errorf("tag type out of range"))
+				return
+			}
+			expectedType := module.TypeSection[typeIndex]
+			importedTag := imported.Tag
+
+			actualType := importedTag.Type
+			if !expectedType.EqualsSignature(actualType.Params, actualType.Results) {
+				err = errorInvalidImport(i, idx, fmt.Errorf("signature mismatch: %s != %s", expectedType, actualType))
+				return
+			}
+			importedTags = append(importedTags, importedTag)
 		}
 	}
 	return
@@ -637,10 +708,58 @@ func executeConstExpression(importedGlobals []*GlobalInstance, expr *ConstantExp
 		v, _, _ = leb128.DecodeInt32(r)
 	case OpcodeVecV128Const:
 		v = [2]uint64{binary.LittleEndian.Uint64(expr.Data[0:8]), binary.LittleEndian.Uint64(expr.Data[8:16])}
+	case OpcodeI32Add, OpcodeI32Sub, OpcodeI32Mul, OpcodeI64Add, OpcodeI64Sub, OpcodeI64Mul:
+		v = executeExtendedConstExpression(importedGlobals, expr)
 	}
 	return
 }
 
+// executeExtendedConstExpression evaluates the ConstantExpression.IsExtended expression which is already validated.
+// The result is int32 or int64 depending on the type of the last instruction.
+func executeExtendedConstExpression(importedGlobals []*GlobalInstance, expr *ConstantExpression) interface{} {
+	r := bytes.NewReader(expr.Data)
+	var stack []uint64
+	for {
+		op, err := r.ReadByte()
+		if err != nil {
+			op = expr.Opcode // The last instruction isn't in the data.
+		}
+
+		switch op {
+		case OpcodeI32Const:
+			v, _, _ := leb128.DecodeInt32(r)
+			stack = append(stack, uint64(uint32(v)))
+		case OpcodeI64Const:
+			v, _, _ := leb128.DecodeInt64(r)
+			stack = append(stack, uint64(v))
+		case OpcodeGlobalGet:
+			id, _, _ := leb128.DecodeUint32(r)
+			stack = append(stack, importedGlobals[id].Val)
+		default:
+			x1, x2 := stack[len(stack)-2], stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			switch op {
+			case OpcodeI32Add, OpcodeI64Add:
+				x1 += x2
+			case OpcodeI32Sub, OpcodeI64Sub:
+				x1 -= x2
+			case OpcodeI32Mul, OpcodeI64Mul:
+				x1 *= x2
+			}
+			stack[len(stack)-1] = x1
+		}
+
+		if err != nil {
+			break
+		}
+	}
+
+	if expr.Opcode >= OpcodeI64Add {
+		return int64(stack[0])
+	}
+	return int32(stack[0])
+}
+
 // GlobalInstanceNullFuncRefValue is the temporary value for ValueTypeFuncref globals which are initialized via ref.null.
 const GlobalInstanceNullFuncRefValue int64 = -1
 
@@ -28,23 +28,23 @@ func TestModuleInstance_Memory(t *testing.T) {
 		},
 		{
 			name:  "memory not exported",
-			input: &Module{MemorySection: &Memory{Min: 1, Cap: 1}},
+			input: &Module{MemorySection: []*Memory{{Min: 1, Cap: 1}}},
 		},
 		{
 			name:  "memory not exported, one page",
-			input: &Module{MemorySection: &Memory{Min: 1, Cap: 1}},
+			input: &Module{MemorySection: []*Memory{{Min: 1, Cap: 1}}},
 		},
 		{
 			name: "memory exported, different name",
 			input: &Module{
-				MemorySection: &Memory{Min: 1, Cap: 1},
+				MemorySection: []*Memory{{Min: 1, Cap: 1}},
 				ExportSection: []*Export{{Type: ExternTypeMemory, Name: "momory", Index: 0}},
 			},
 		},
 		{
 			name: "memory exported, but zero length",
 			input: &Module{
-				MemorySection: &Memory{},
+				MemorySection: []*Memory{{}},
 				ExportSection: []*Export{{Type: ExternTypeMemory, Name: "memory", Index: 0}},
 			},
 			expected: true,
@@ -52,7 +52,7 @@ func TestModuleInstance_Memory(t *testing.T) {
 		{
 			name: "memory exported, one page",
 			input: &Module{
-				MemorySection: &Memory{Min: 1, Cap: 1},
+				MemorySection: []*Memory{{Min: 1, Cap: 1}},
 				ExportSection: []*Export{{Type: ExternTypeMemory, Name: "memory", Index: 0}},
 			},
 			expected:    true,
@@ -61,7 +61,7 @@ func TestModuleInstance_Memory(t *testing.T) {
 		{
 			name: "memory exported, two pages",
 			input: &Module{
-				MemorySection: &Memory{Min: 2, Cap: 2},
+				MemorySection: []*Memory{{Min: 2, Cap: 2}},
 				ExportSection: []*Export{{Type: ExternTypeMemory, Name: "memory", Index: 0}},
 			},
 			expected:    true,
@@ -158,7 +158,7 @@ func TestStore_CloseModule(t *testing.T) {
 			_, err := s.Instantiate(testCtx, &Module{
 				TypeSection:   []*FunctionType{{}},
 				ImportSection: []*Import{{Type: ExternTypeFunc, Module: importedModuleName, Name: "fn", DescFunc: 0}},
-				MemorySection: &Memory{Min: 1, Cap: 1},
+				MemorySection: []*Memory{{Min: 1, Cap: 1}},
 				GlobalSection: []*Global{{Type: &GlobalType{}, Init: &ConstantExpression{Opcode: OpcodeI32Const, Data: const1}}},
 				TableSection:  []*Table{{Min: 10}},
 			}, importingModuleName, nil, nil)
@@ -218,7 +218,7 @@ func TestStore_CloseStore(t *testing.T) {
 			m2, err := s.Instantiate(testCtx, &Module{
 				TypeSection:   []*FunctionType{{}},
 				ImportSection: []*Import{{Type: ExternTypeFunc, Module: importedModuleName, Name: "fn", DescFunc: 0}},
-				MemorySection: &Memory{Min: 1, Cap: 1},
+				MemorySection: []*Memory{{Min: 1, Cap: 1}},
 				GlobalSection: []*Global{{Type: &GlobalType{}, Init: &ConstantExpression{Opcode: OpcodeI32Const, Data: const1}}},
 				TableSection:  []*Table{{Min: 10}},
 			}, importingModuleName, nil, nil)
@@ -263,7 +263,7 @@ func TestStore_hammer(t *testing.T) {
 		TypeSection:     []*FunctionType{{}},
 		FunctionSection: []uint32{0},
 		CodeSection:     []*Code{{Body: []byte{OpcodeEnd}}},
-		MemorySection:   &Memory{Min: 1, Cap: 1},
+		MemorySection:   []*Memory{{Min: 1, Cap: 1}},
 		GlobalSection:   []*Global{{Type: &GlobalType{}, Init: &ConstantExpression{Opcode: OpcodeI32Const, Data: const1}}},
 		TableSection:    []*Table{{Min: 10}},
 		ImportSection: []*Import{
@@ -410,7 +410,7 @@ func TestCallContext_ExportedFunction(t *testing.T) {
 		importing, err := s.Instantiate(testCtx, &Module{
 			TypeSection:   []*FunctionType{{}},
 			ImportSection: []*Import{{Type: ExternTypeFunc, Module: "host", Name: "host_fn", DescFunc: 0}},
-			MemorySection: &Memory{Min: 1, Cap: 1},
+			MemorySection: []*Memory{{Min: 1, Cap: 1}},
 			ExportSection: []*Export{{Type: ExternTypeFunc, Name: "host.fn", Index: 0}},
 		}, "test", nil, nil)
 		require.NoError(t, err)
@@ -432,6 +432,7 @@ type mockEngine struct {
 type mockModuleEngine struct {
 	name          string
 	callFailIndex int
+	engine        *mockEngine
 }
 
 func newStore() *Store {
@@ -443,7 +444,7 @@ func (e *mockEngine) NewModuleEngine(_ string, _ *Module, _, _ []*FunctionInstan
 	if e.shouldCompileFail {
 		return nil, fmt.Errorf("some compilation error")
 	}
-	return &mockModuleEngine{callFailIndex: e.callFailIndex}, nil
+	return &mockModuleEngine{callFailIndex: e.callFailIndex, engine: e}, nil
 }
 
 // DeleteCompiledModule implements the same method as documented on wasm.Engine.
@@ -460,11 +461,26 @@ func (me *mockModuleEngine) CreateFuncElementInstance([]*Index) *ElementInstance
 // InitializeFuncrefGlobals implements the same method as documented on wasm.ModuleEngine.
 func (e *mockModuleEngine) InitializeFuncrefGlobals(globals []*GlobalInstance) {}
 
+// FunctionInstanceReference implements the same method as documented on wasm.ModuleEngine.
+func (e *mockModuleEngine) FunctionInstanceReference(funcIndex Index) Reference {
+	return 0
+}
+
+// LookupFunction implements the same method as documented on wasm.ModuleEngine.
+func (e *mockModuleEngine) LookupFunction(Reference) *FunctionInstance {
+	return nil
+}
+
 // Name implements the same method as documented on wasm.ModuleEngine.
 func (e *mockModuleEngine) Name() string {
 	return e.name
 }
 
+// Engine implements the same method as documented on wasm.ModuleEngine.
+func (e *mockModuleEngine) Engine() Engine {
+	return e.engine
+}
+
 // Call implements the same method as documented on wasm.ModuleEngine.
 func (e *mockModuleEngine) Call(ctx context.Context, callCtx *CallContext, f *FunctionInstance, _ ...uint64) (results []uint64, err error) {
 	if e.callFailIndex >= 0 && f.Idx == Index(e.callFailIndex) {
@@ -658,6 +674,56 @@ func TestExecuteConstExpression(t *testing.T) {
 		require.Equal(t, uint64(0x1), vector[0])
 		require.Equal(t, uint64(0x2), vector[1])
 	})
+
+	t.Run("extended", func(t *testing.T) {
+		globals := []*GlobalInstance{
+			{Val: 0xffffffff_fffffff0, Type: &GlobalType{ValType: ValueTypeI32}}, // int32(-16)
+			{Val: 1 << 40, Type: &GlobalType{ValType: ValueTypeI64}},
+		}
+		tests := []struct {
+			name string
+			expr *ConstantExpression
+			exp  interface{}
+		}{
+			{
+				name: "i32",
+				// global.get 0 + i32.const 16 * i32.const 2
+				expr: &ConstantExpression{Opcode: OpcodeI32Add, Data: []byte{
+					OpcodeGlobalGet, 0, OpcodeI32Const, 16, OpcodeI32Const, 2, OpcodeI32Mul,
+				}},
+				exp: int32(16),
+			},
+			{
+				name: "i32 wraps around",
+				// i32.const 0x7fffffff + i32.const 1
+				expr: &ConstantExpression{Opcode: OpcodeI32Add, Data: []byte{
+					OpcodeI32Const, 0xff, 0xff, 0xff, 0xff, 0x07, OpcodeI32Const, 1,
+				}},
+				exp: int32(math.MinInt32),
+			},
+			{
+				name: "i32 sub",
+				expr: &ConstantExpression{Opcode: OpcodeI32Sub, Data: []byte{OpcodeI32Const, 1, OpcodeI32Const, 2}},
+				exp:  int32(-1),
+			},
+			{
+				name: "i64",
+				// global.get 1 - i64.const 1 * i64.const 3
+				expr: &ConstantExpression{Opcode: OpcodeI64Mul, Data: []byte{
+					OpcodeGlobalGet, 1, OpcodeI64Const, 1, OpcodeI64Sub, OpcodeI64Const, 3,
+				}},
+				exp: int64((1<<40 - 1) * 3),
+			},
+		}
+
+		for _, tt := range tests {
+			tc := tt
+			t.Run(tc.name, func(t *testing.T) {
+				val := executeConstExpression(globals, tc.expr)
+				require.Equal(t, tc.exp, val)
+			})
+		}
+	})
 }
 
 func TestStore_resolveImports(t *testing.T) {
@@ -666,13 +732,13 @@ func TestStore_resolveImports(t *testing.T) {
 
 	t.Run("module not instantiated", func(t *testing.T) {
 		s := newStore()
-		_, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: "unknown", Name: "unknown"}}})
+		_, _, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: "unknown", Name: "unknown"}}})
 		require.EqualError(t, err, "module[unknown] not instantiated")
 	})
 	t.Run("export instance not found", func(t *testing.T) {
 		s := newStore()
 		s.modules[moduleName] = &ModuleInstance{Exports: map[string]*ExportInstance{}, Name: moduleName}
-		_, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: "unknown"}}})
+		_, _, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: "unknown"}}})
 		require.EqualError(t, err, "\"unknown\" is not exported in module \"test\"")
 	})
 	t.Run("func", func(t *testing.T) {
@@ -694,7 +760,7 @@ func TestStore_resolveImports(t *testing.T) {
 					{Module: moduleName, Name: "", Type: ExternTypeFunc, DescFunc: 1},
 				},
 			}
-			functions, _, _, _, err := s.resolveImports(m)
+			functions, _, _, _, _, err := s.resolveImports(m)
 			require.NoError(t, err)
 			require.True(t, functionsContain(functions, f), "expected to find %v in %v", f, functions)
 			require.True(t, functionsContain(functions, g), "expected to find %v in %v", g, functions)
@@ -702,7 +768,7 @@ func TestStore_resolveImports(t *testing.T) {
 		t.Run("type out of range", func(t *testing.T) {
 			s := newStore()
 			s.modules[moduleName] = &ModuleInstance{Exports: map[string]*ExportInstance{name: {}}, Name: moduleName}
-			_, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeFunc, DescFunc: 100}}})
+			_, _, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeFunc, DescFunc: 100}}})
 			require.EqualError(t, err, "import[0] func[test.target]: function type out of range")
 		})
 		t.Run("signature mismatch", func(t *testing.T) {
@@ -714,7 +780,7 @@ func TestStore_resolveImports(t *testing.T) {
 				TypeSection:   []*FunctionType{{Results: []ValueType{ValueTypeF32}}},
 				ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeFunc, DescFunc: 0}},
 			}
-			_, _, _, _, err := s.resolveImports(m)
+			_, _, _, _, _, err := s.resolveImports(m)
 			require.EqualError(t, err, "import[0] func[test.target]: signature mismatch: v_f32 != v_v")
 		})
 	})
@@ -723,7 +789,7 @@ func TestStore_resolveImports(t *testing.T) {
 			s := newStore()
 			g := &GlobalInstance{Type: &GlobalType{ValType: ValueTypeI32}}
 			s.modules[moduleName] = &ModuleInstance{Exports: map[string]*ExportInstance{name: {Type: ExternTypeGlobal, Global: g}}, Name: moduleName}
-			_, globals, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeGlobal, DescGlobal: g.Type}}})
+			_, globals, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeGlobal, DescGlobal: g.Type}}})
 			require.NoError(t, err)
 			require.True(t, globalsContain(globals, g), "expected to find %v in %v", g, globals)
 		})
@@ -733,7 +799,7 @@ func TestStore_resolveImports(t *testing.T) {
 				Type:   ExternTypeGlobal,
 				Global: &GlobalInstance{Type: &GlobalType{Mutable: false}},
 			}}, Name: moduleName}
-			_, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeGlobal, DescGlobal: &GlobalType{Mutable: true}}}})
+			_, _, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeGlobal, DescGlobal: &GlobalType{Mutable: true}}}})
 			require.EqualError(t, err, "import[0] global[test.target]: mutability mismatch: true != false")
 		})
 		t.Run("type mismatch", func(t *testing.T) {
@@ -742,7 +808,7 @@ func TestStore_resolveImports(t *testing.T) {
 				Type:   ExternTypeGlobal,
 				Global: &GlobalInstance{Type: &GlobalType{ValType: ValueTypeI32}},
 			}}, Name: moduleName}
-			_, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeGlobal, DescGlobal: &GlobalType{ValType: ValueTypeF64}}}})
+			_, _, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeGlobal, DescGlobal: &GlobalType{ValType: ValueTypeF64}}}})
 			require.EqualError(t, err, "import[0] global[test.target]: value type mismatch: f64 != i32")
 		})
 	})
@@ -755,9 +821,9 @@ func TestStore_resolveImports(t *testing.T) {
 				Type:   ExternTypeMemory,
 				Memory: memoryInst,
 			}}, Name: moduleName}
-			_, _, _, memory, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeMemory, DescMem: &Memory{Max: max}}}})
+			_, _, _, memories, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeMemory, DescMem: &Memory{Max: max}}}})
 			require.NoError(t, err)
-			require.Equal(t, memory, memoryInst)
+			require.Equal(t, []*MemoryInstance{memoryInst}, memories)
 		})
 		t.Run("minimum size mismatch", func(t *testing.T) {
 			s := newStore()
@@ -766,7 +832,7 @@ func TestStore_resolveImports(t *testing.T) {
 				Type:   ExternTypeMemory,
 				Memory: &MemoryInstance{Min: importMemoryType.Min - 1, Cap: 2},
 			}}, Name: moduleName}
-			_, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeMemory, DescMem: importMemoryType}}})
+			_, _, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeMemory, DescMem: importMemoryType}}})
 			require.EqualError(t, err, "import[0] memory[test.target]: minimum size mismatch: 2 > 1")
 		})
 		t.Run("maximum size mismatch", func(t *testing.T) {
@@ -777,14 +843,36 @@ func TestStore_resolveImports(t *testing.T) {
 				Type:   ExternTypeMemory,
 				Memory: &MemoryInstance{Max: MemoryLimitPages},
 			}}, Name: moduleName}
-			_, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeMemory, DescMem: importMemoryType}}})
+			_, _, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeMemory, DescMem: importMemoryType}}})
 			require.EqualError(t, err, "import[0] memory[test.target]: maximum size mismatch: 10 < 65536")
 		})
+		t.Run("shared mismatch", func(t *testing.T) {
+			s := newStore()
+			max := uint32(10)
+			importMemoryType := &Memory{Max: max, IsShared: true}
+			s.modules[moduleName] = &ModuleInstance{Exports: map[string]*ExportInstance{name: {
+				Type:   ExternTypeMemory,
+				Memory: &MemoryInstance{Max: max},
+			}}, Name: moduleName}
+			_, _, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeMemory, DescMem: importMemoryType}}})
+			require.EqualError(t, err, "import[0] memory[test.target]: shared mismatch: true != false")
+		})
+		t.Run("index type mismatch", func(t *testing.T) {
+			s := newStore()
+			max := uint32(10)
+			importMemoryType := &Memory{Max: max, IsMemory64: true}
+			s.modules[moduleName] = &ModuleInstance{Exports: map[string]*ExportInstance{name: {
+				Type:   ExternTypeMemory,
+				Memory: &MemoryInstance{Max: max},
+			}}, Name: moduleName}
+			_, _, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeMemory, DescMem: importMemoryType}}})
+			require.EqualError(t, err, "import[0] memory[test.target]: index type mismatch: i64 != i32")
+		})
 	})
 }
 
 func TestModuleInstance_validateData(t *testing.T) {
-	m := &ModuleInstance{Memory: &MemoryInstance{Buffer: make([]byte, 5)}}
+	m := &ModuleInstance{Memories: []*MemoryInstance{{Buffer: make([]byte, 5)}}}
 	tests := []struct {
 		name   string
 		data   []*DataSegment
@@ -827,13 +915,15 @@ func TestModuleInstance_validateData(t *testing.T) {
 }
 
 func TestModuleInstance_applyData(t *testing.T) {
-	m := &ModuleInstance{Memory: &MemoryInstance{Buffer: make([]byte, 10)}}
+	m := &ModuleInstance{Memories: []*MemoryInstance{{Buffer: make([]byte, 10)}, {Buffer: make([]byte, 3)}}}
 	err := m.applyData([]*DataSegment{
 		{OffsetExpression: &ConstantExpression{Opcode: OpcodeI32Const, Data: const0}, Init: []byte{0xa, 0xf}},
 		{OffsetExpression: &ConstantExpression{Opcode: OpcodeI32Const, Data: leb128.EncodeUint32(8)}, Init: []byte{0x1, 0x5}},
+		{OffsetExpression: &ConstantExpression{Opcode: OpcodeI32Const, Data: const1}, Init: []byte{0x7}, MemoryIndex: 1},
 	})
 	require.NoError(t, err)
-	require.Equal(t, []byte{0xa, 0xf, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1, 0x5}, m.Memory.Buffer)
+	require.Equal(t, []byte{0xa, 0xf, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x1, 0x5}, m.Memories[0].Buffer)
+	require.Equal(t, []byte{0x0, 0x7, 0x0}, m.Memories[1].Buffer)
 }
 
 func globalsContain(globals []*GlobalInstance, want *GlobalInstance) bool {
@@ -7,6 +7,7 @@ import (
 	"math"
 	"sync"
 
+	"github.com/tetratelabs/wazero/api"
 	"github.com/tetratelabs/wazero/internal/leb128"
 )
 
@@ -106,6 +107,113 @@ type TableInstance struct {
 	mux sync.RWMutex
 }
 
+// exportedTable implements api.Table for a TableInstance.
+type exportedTable struct {
+	table *TableInstance
+	// engine is the ModuleEngine of the module which exported the table, used to convert function references.
+	engine ModuleEngine
+}
+
+// Type implements the same method as documented on api.Table.
+func (t *exportedTable) Type() api.ValueType {
+	return t.table.Type
+}
+
+// Size implements the same method as documented on api.Table.
+func (t *exportedTable) Size(_ context.Context) uint32 {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	t.table.mux.RLock()
+	defer t.table.mux.RUnlock()
+	return uint32(len(t.table.References))
+}
+
+// Grow implements the same method as documented on api.Table.
+func (t *exportedTable) Grow(ctx context.Context, delta uint32) (previousSize uint32, ok bool) {
+	if previousSize = t.table.Grow(ctx, delta, 0); previousSize == 0xffffffff {
+		return 0, false
+	}
+	return previousSize, true
+}
+
+// Function implements the same method as documented on api.Table.
+func (t *exportedTable) Function(_ context.Context, index uint32) (api.Function, bool) {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	ref, ok := t.get(RefTypeFuncref, index)
+	if !ok {
+		return nil, false
+	} else if ref == 0 {
+		return nil, true
+	}
+	return t.engine.LookupFunction(ref), true
+}
+
+// SetFunction implements the same method as documented on api.Table.
+func (t *exportedTable) SetFunction(_ context.Context, index uint32, fn api.Function) bool {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	var ref Reference
+	if fn != nil {
+		var ok bool
+		if ref, ok = functionReference(fn, t.engine); !ok {
+			return false
+		}
+	}
+	return t.set(RefTypeFuncref, index, ref)
+}
+
+// functionReference returns the funcref value of the non-nil fn for the given ModuleEngine, or false if fn is not
+// implemented by this package or is a function of another Engine, such as one of another wazero.Runtime.
+func functionReference(fn api.Function, me ModuleEngine) (Reference, bool) {
+	var f *FunctionInstance
+	switch fn := fn.(type) {
+	case *FunctionInstance:
+		f = fn
+	case *importedFn:
+		f = fn.importedFn
+	default:
+		return 0, false
+	}
+	if f.Module.Engine.Engine() != me.Engine() {
+		return 0, false
+	}
+	return f.Module.Engine.FunctionInstanceReference(f.Idx), true
+}
+
+// Externref implements the same method as documented on api.Table.
+func (t *exportedTable) Externref(_ context.Context, index uint32) (uintptr, bool) {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	return t.get(RefTypeExternref, index)
+}
+
+// SetExternref implements the same method as documented on api.Table.
+func (t *exportedTable) SetExternref(_ context.Context, index uint32, ref uintptr) bool {
+	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
+
+	return t.set(RefTypeExternref, index, ref)
+}
+
+func (t *exportedTable) get(refType RefType, index uint32) (Reference, bool) {
+	t.table.mux.RLock()
+	defer t.table.mux.RUnlock()
+	if t.table.Type != refType || index >= uint32(len(t.table.References)) {
+		return 0, false
+	}
+	return t.table.References[index], true
+}
+
+func (t *exportedTable) set(refType RefType, index uint32, ref Reference) bool {
+	t.table.mux.RLock() // Grow replaces References, but setting an element doesn't.
+	defer t.table.mux.RUnlock()
+	if t.table.Type != refType || index >= uint32(len(t.table.References)) {
+		return false
+	}
+	t.table.References[index] = ref
+	return true
+}
+
 // ElementInstance represents an element instance in a module.
 //
 // See https://www.w3.org/TR/2022/WD-wasm-core-2-20220419/exec/runtime.html#element-instances
@@ -123,7 +231,7 @@ type Reference = uintptr
 //
 // Note: The global imported at globalIdx may have an offset value that is out-of-bounds for the corresponding table.
 type validatedActiveElementSegment struct {
-	// opcode is OpcodeGlobalGet or OpcodeI32Const
+	// opcode is OpcodeGlobalGet, OpcodeI32Const or the last instruction of an extended constant expression.
 	opcode Opcode
 
 	// arg is the only argument to opcode, which when applied results in the offset to add to init indices.
@@ -131,6 +239,9 @@ type validatedActiveElementSegment struct {
 	//  * OpcodeI32Const: a constant ValueTypeI32 offset.
 	arg uint32
 
+	// expr is the offset expression when it is ConstantExpression.IsExtended, which is evaluated on instantiation.
+	expr *ConstantExpression
+
 	// init are a range of table elements whose values are positions in the function index namespace. This range
 	// replaces any values in TableInstance.Table at an offset arg which is a constant if opcode == OpcodeI32Const or
 	// derived from a globalIdx if opcode == OpcodeGlobalGet
@@ -158,6 +269,9 @@ func (m *Module) validateTable(enabledFeatures Features, tables []*Table, maximu
 	// Create bounds checks as these can err prior to instantiation
 	funcCount := m.importCount(ExternTypeFunc) + m.SectionElementCount(SectionIDFunction)
 
+	// importedGlobals are the types of the imported globals, which are only needed by extended const expressions.
+	var importedGlobals []*GlobalType
+
 	// Now, we have to figure out which table elements can be resolved before instantiation and also fail early if there
 	// are any imported globals that are known to be invalid by their declarations.
 	for i, elem := range m.ElementSection {
@@ -220,6 +334,24 @@ func (m *Module) validateTable(enabledFeatures Features, tables []*Table, maximu
 				}
 
 				ret = append(ret, &validatedActiveElementSegment{opcode: oc, arg: offset, init: elem.Init, tableIndex: elem.TableIndex})
+			} else if elem.OffsetExpr.IsExtended() {
+				if importedGlobals == nil {
+					_, globals, _, _, err := m.AllDeclarations()
+					if err != nil {
+						return nil, err
+					}
+					importedGlobals = globals[:m.ImportGlobalCount()]
+				}
+				// The offset depends on imported globals, so the bounds are checked on instantiation.
+				if err := validateConstExpression(importedGlobals, 0, elem.OffsetExpr, ValueTypeI32); err != nil {
+					return nil, fmt.Errorf("%s[%d] has an invalid const expression: %w", SectionIDName(SectionIDElement), idx, err)
+				}
+
+				if initCount == 0 {
+					continue
+				}
+
+				ret = append(ret, &validatedActiveElementSegment{opcode: oc, expr: elem.OffsetExpr, init: elem.Init, tableIndex: elem.TableIndex})
 			} else {
 				return nil, fmt.Errorf("%s[%d] has an invalid const expression: %s", SectionIDName(SectionIDElement), idx, InstructionName(oc))
 			}
@@ -259,6 +391,8 @@ func (m *Module) buildTables(importedTables []*TableInstance, importedGlobals []
 		if elem.opcode == OpcodeGlobalGet {
 			global := importedGlobals[elem.arg]
 			offset = uint32(global.Val)
+		} else if elem.expr != nil {
+			offset = uint32(executeConstExpression(importedGlobals, elem.expr).(int32))
 		} else {
 			offset = elem.arg // constant
 		}
@@ -4,6 +4,7 @@ import (
 	"math"
 	"testing"
 
+	"github.com/tetratelabs/wazero/api"
 	"github.com/tetratelabs/wazero/internal/leb128"
 	"github.com/tetratelabs/wazero/internal/testing/require"
 )
@@ -24,7 +25,7 @@ func TestStore_resolveImports_table(t *testing.T) {
 			Type:  ExternTypeTable,
 			Table: tableInst,
 		}}, Name: moduleName}
-		_, _, tables, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeTable, DescTable: &Table{Max: &max}}}})
+		_, _, tables, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeTable, DescTable: &Table{Max: &max}}}})
 		require.NoError(t, err)
 		require.Equal(t, 1, len(tables))
 		require.Equal(t, tables[0], tableInst)
@@ -36,7 +37,7 @@ func TestStore_resolveImports_table(t *testing.T) {
 			Type:  ExternTypeTable,
 			Table: &TableInstance{Min: importTableType.Min - 1},
 		}}, Name: moduleName}
-		_, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeTable, DescTable: importTableType}}})
+		_, _, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeTable, DescTable: importTableType}}})
 		require.EqualError(t, err, "import[0] table[test.target]: minimum size mismatch: 2 > 1")
 	})
 	t.Run("maximum size mismatch", func(t *testing.T) {
@@ -47,7 +48,7 @@ func TestStore_resolveImports_table(t *testing.T) {
 			Type:  ExternTypeTable,
 			Table: &TableInstance{Min: importTableType.Min - 1},
 		}}, Name: moduleName}
-		_, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeTable, DescTable: importTableType}}})
+		_, _, _, _, _, err := s.resolveImports(&Module{ImportSection: []*Import{{Module: moduleName, Name: name, Type: ExternTypeTable, DescTable: importTableType}}})
 		require.EqualError(t, err, "import[0] table[test.target]: maximum size mismatch: 10, but actual has no max")
 	})
 }
@@ -309,6 +310,29 @@ func TestModule_validateTable(t *testing.T) {
 				{opcode: OpcodeGlobalGet, arg: 1, init: []*Index{uint32Ptr(1), uint32Ptr(2)}},
 			},
 		},
+		{
+			name: "extended const offset",
+			input: &Module{
+				TypeSection: []*FunctionType{{}},
+				ImportSection: []*Import{
+					{Type: ExternTypeGlobal, DescGlobal: &GlobalType{ValType: ValueTypeI64}},
+					{Type: ExternTypeGlobal, DescGlobal: &GlobalType{ValType: ValueTypeI32}},
+				},
+				TableSection:    []*Table{{Min: 3}},
+				FunctionSection: []Index{0},
+				CodeSection:     []*Code{codeEnd},
+				ElementSection: []*ElementSegment{
+					{
+						OffsetExpr: &ConstantExpression{Opcode: OpcodeI32Add, Data: []byte{OpcodeGlobalGet, 0x1, OpcodeI32Const, 1}},
+						Init:       []*Index{uint32Ptr(0)},
+						Type:       RefTypeFuncref,
+					},
+				},
+			},
+			expected: []*validatedActiveElementSegment{
+				{opcode: OpcodeI32Add, expr: &ConstantExpression{Opcode: OpcodeI32Add, Data: []byte{OpcodeGlobalGet, 0x1, OpcodeI32Const, 1}}, init: []*Index{uint32Ptr(0)}},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -584,6 +608,26 @@ func TestModule_validateTable_Errors(t *testing.T) {
 			},
 			expectedErr: "element[0] (global.get 0): out of range of imported globals",
 		},
+		{
+			name: "extended const offset - type mismatch",
+			input: &Module{
+				TypeSection: []*FunctionType{{}},
+				ImportSection: []*Import{
+					{Type: ExternTypeGlobal, DescGlobal: &GlobalType{ValType: ValueTypeI64}},
+				},
+				TableSection:    []*Table{{}},
+				FunctionSection: []Index{0},
+				CodeSection:     []*Code{codeEnd},
+				ElementSection: []*ElementSegment{
+					{
+						OffsetExpr: &ConstantExpression{Opcode: OpcodeI64Add, Data: []byte{OpcodeGlobalGet, 0x0, OpcodeI64Const, 1}},
+						Init:       []*Index{uint32Ptr(0)},
+						Type:       RefTypeFuncref,
+					},
+				},
+			},
+			expectedErr: "element[0] has an invalid const expression: const expression type mismatch expected i32 but got i64",
+		},
 	}
 
 	for _, tt := range tests {
@@ -839,6 +883,23 @@ func TestModule_buildTables(t *testing.T) {
 				{TableIndex: 0, Offset: 1, FunctionIndexes: []*Index{uint32Ptr(1), uint32Ptr(2)}},
 			},
 		},
+		{
+			name: "extended const offset",
+			module: &Module{
+				TableSection: []*Table{{Min: 3}},
+				validatedActiveElementSegments: []*validatedActiveElementSegment{
+					{opcode: OpcodeI32Add, expr: &ConstantExpression{Opcode: OpcodeI32Add, Data: []byte{OpcodeGlobalGet, 0x1, OpcodeI32Const, 1}}, init: []*Index{uint32Ptr(0)}},
+				},
+			},
+			importedGlobals: []*GlobalInstance{
+				{Type: &GlobalType{ValType: ValueTypeI64}, Val: 3},
+				{Type: &GlobalType{ValType: ValueTypeI32}, Val: 1},
+			},
+			expectedTables: []*TableInstance{{References: make([]Reference, 3), Min: 3}},
+			expectedInit: []TableInitEntry{
+				{TableIndex: 0, Offset: 2, FunctionIndexes: []*Index{uint32Ptr(0)}},
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -1002,3 +1063,72 @@ func TestTableInstance_Grow(t *testing.T) {
 		})
 	}
 }
+
+func TestExportedTable(t *testing.T) {
+	t.Run("externref", func(t *testing.T) {
+		table := &exportedTable{
+			table:  &TableInstance{References: make([]Reference, 2), Max: uint32Ptr(3), Type: RefTypeExternref},
+			engine: &mockModuleEngine{},
+		}
+		require.Equal(t, api.ValueTypeExternref, table.Type())
+		require.Equal(t, uint32(2), table.Size(testCtx))
+
+		require.True(t, table.SetExternref(testCtx, 1, 0xfeed))
+		ref, ok := table.Externref(testCtx, 1)
+		require.True(t, ok)
+		require.Equal(t, uintptr(0xfeed), ref)
+
+		// Out of range.
+		require.False(t, table.SetExternref(testCtx, 2, 0xfeed))
+		_, ok = table.Externref(testCtx, 2)
+		require.False(t, ok)
+
+		previousSize, ok := table.Grow(testCtx, 1)
+		require.True(t, ok)
+		require.Equal(t, uint32(2), previousSize)
+		require.Equal(t, uint32(3), table.Size(testCtx))
+
+		// The new element is a null reference.
+		ref, ok = table.Externref(testCtx, 2)
+		require.True(t, ok)
+		require.Zero(t, ref)
+
+		// Beyond max.
+		_, ok = table.Grow(testCtx, 1)
+		require.False(t, ok)
+
+		// Wrong type.
+		require.False(t, table.SetFunction(testCtx, 0, nil))
+		_, ok = table.Function(testCtx, 0)
+		require.False(t, ok)
+	})
+
+	t.Run("funcref", func(t *testing.T) {
+		e := &mockEngine{}
+		table := &exportedTable{
+			table:  &TableInstance{References: []Reference{0xfeed}, Type: RefTypeFuncref},
+			engine: &mockModuleEngine{engine: e},
+		}
+		require.Equal(t, api.ValueTypeFuncref, table.Type())
+
+		// A nil function is a null reference.
+		require.True(t, table.SetFunction(testCtx, 0, nil))
+		fn, ok := table.Function(testCtx, 0)
+		require.True(t, ok)
+		require.Nil(t, fn)
+
+		// Functions of another engine, such as one of another runtime, are rejected.
+		require.True(t, table.SetFunction(testCtx, 0, &FunctionInstance{Module: &ModuleInstance{Engine: &mockModuleEngine{engine: e}}}))
+		require.False(t, table.SetFunction(testCtx, 0, &FunctionInstance{Module: &ModuleInstance{Engine: &mockModuleEngine{engine: &mockEngine{}}}}))
+
+		// Out of range.
+		require.False(t, table.SetFunction(testCtx, 1, nil))
+		_, ok = table.Function(testCtx, 1)
+		require.False(t, ok)
+
+		// Wrong type.
+		require.False(t, table.SetExternref(testCtx, 0, 0xfeed))
+		_, ok = table.Externref(testCtx, 0)
+		require.False(t, ok)
+	})
+}
@@ -463,7 +463,7 @@ func (p *moduleParser) endFunc(typeIdx wasm.Index, code *wasm.Code, name string,
 // endMemory adds the limits for the current memory, and increments memoryNamespace as it is shared across imported and
 // module-defined memories. Finally, this returns parseModule to prepare for the next field.
 func (p *moduleParser) endMemory(mem *wasm.Memory) tokenParser {
-	p.module.MemorySection = mem
+	p.module.MemorySection = append(p.module.MemorySection, mem)
 	p.pos = positionModule
 	return p.parseModule
 }
@@ -1226,14 +1226,14 @@ func TestDecodeModule(t *testing.T) {
 			name:  "memory",
 			input: "(module (memory 1))",
 			expected: &wasm.Module{
-				MemorySection: &wasm.Memory{Min: 1, Cap: 1, Max: wasm.MemoryLimitPages},
+				MemorySection: []*wasm.Memory{{Min: 1, Cap: 1, Max: wasm.MemoryLimitPages}},
 			},
 		},
 		{
 			name:  "memory ID",
 			input: "(module (memory $mem 1))",
 			expected: &wasm.Module{
-				MemorySection: &wasm.Memory{Min: 1, Cap: 1, Max: wasm.MemoryLimitPages},
+				MemorySection: []*wasm.Memory{{Min: 1, Cap: 1, Max: wasm.MemoryLimitPages}},
 			},
 		},
 		{
@@ -1420,7 +1420,7 @@ func TestDecodeModule(t *testing.T) {
 	(export "foo" (memory 0))
 )`,
 			expected: &wasm.Module{
-				MemorySection: &wasm.Memory{Min: 0, Max: wasm.MemoryLimitPages},
+				MemorySection: []*wasm.Memory{{Min: 0, Max: wasm.MemoryLimitPages}},
 				ExportSection: []*wasm.Export{
 					{Name: "foo", Type: wasm.ExternTypeMemory, Index: 0},
 				},
@@ -1433,7 +1433,7 @@ func TestDecodeModule(t *testing.T) {
 	(memory 0)
 )`,
 			expected: &wasm.Module{
-				MemorySection: &wasm.Memory{Min: 0, Max: wasm.MemoryLimitPages},
+				MemorySection: []*wasm.Memory{{Min: 0, Max: wasm.MemoryLimitPages}},
 				ExportSection: []*wasm.Export{
 					{Name: "foo", Type: wasm.ExternTypeMemory, Index: 0},
 				},
@@ -1465,7 +1465,7 @@ func TestDecodeModule(t *testing.T) {
     (export "memory" (memory $mem))
 )`,
 			expected: &wasm.Module{
-				MemorySection: &wasm.Memory{Min: 1, Cap: 1, Max: wasm.MemoryLimitPages},
+				MemorySection: []*wasm.Memory{{Min: 1, Cap: 1, Max: wasm.MemoryLimitPages}},
 				ExportSection: []*wasm.Export{
 					{Name: "memory", Type: wasm.ExternTypeMemory, Index: 0},
 				},
@@ -148,6 +148,12 @@ func (p *funcParser) beginInstruction(tokenBytes []byte) (next tokenParser, err
 	case wasm.OpcodeCallName: // See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#-hrefsyntax-instr-controlmathsfcallx
 		opCode = wasm.OpcodeCall
 		next = p.parseFuncIndex
+	case wasm.OpcodeReturnCallName: // See https://github.com/WebAssembly/tail-call/blob/main/proposals/tail-call/Overview.md
+		if err = p.enabledFeatures.Require(wasm.FeatureTailCall); err != nil {
+			return nil, fmt.Errorf("%s invalid as %v", tokenBytes, err)
+		}
+		opCode = wasm.OpcodeReturnCall
+		next = p.parseFuncIndex
 	case wasm.OpcodeDropName: // See https://www.w3.org/TR/2019/REC-wasm-core-1-20191205/#-hrefsyntax-instr-parametricmathsfdrop
 		opCode = wasm.OpcodeDrop
 		next = p.beginFieldOrInstruction
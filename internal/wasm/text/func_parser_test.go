@@ -318,6 +318,17 @@ func TestFuncParser_Call_Unresolved(t *testing.T) {
 				line:       1, col: 12,
 			},
 		},
+		{
+			name:         "return_call ID",
+			source:       "(func return_call $main)",
+			expectedCode: &wasm.Code{Body: []byte{wasm.OpcodeReturnCall, 0x00, wasm.OpcodeEnd}},
+			expectedUnresolvedIndex: &unresolvedIndex{
+				section:    wasm.SectionIDCode,
+				bodyOffset: 1, // second byte is the position Code.Body
+				targetID:   "main",
+				line:       1, col: 19,
+			},
+		},
 	}
 	for _, tt := range tests {
 		tc := tt
@@ -330,7 +341,7 @@ func TestFuncParser_Call_Unresolved(t *testing.T) {
 			}
 
 			module := &wasm.Module{}
-			fp := newFuncParser(wasm.Features20191205, &typeUseParser{module: module}, newIndexNamespace(module.SectionElementCount), setFunc)
+			fp := newFuncParser(wasm.Features20191205|wasm.FeatureTailCall, &typeUseParser{module: module}, newIndexNamespace(module.SectionElementCount), setFunc)
 			require.NoError(t, parseFunc(fp, tc.source))
 			require.Equal(t, tc.expectedCode, parsedCode)
 			require.Equal(t, []*unresolvedIndex{tc.expectedUnresolvedIndex}, fp.funcNamespace.unresolvedIndices)
@@ -462,6 +473,11 @@ func TestFuncParser_Errors(t *testing.T) {
 			source:      "(func (result i32) (result i32))",
 			expectedErr: "1:21: multiple result types invalid as feature \"multi-value\" is disabled",
 		},
+		{
+			name:        "return_call disabled",
+			source:      "(func return_call 0)",
+			expectedErr: "1:7: return_call invalid as feature \"tail-call\" is disabled",
+		},
 		{
 			name:        "i32.extend8_s disabled",
 			source:      "(func (param i32) local.get 0 i32.extend8_s)",
@@ -122,6 +122,16 @@ func (s *stackTrace) FromRecovered(recovered interface{}) error {
 		return fmt.Errorf("wasm error: %w\nwasm stack trace:\n\t%s", wasmErr, stack)
 	}
 
+	// Likewise, an uncaught exception was raised intentionally by the "throw" instruction.
+	if exc, ok := recovered.(*api.Exception); ok {
+		return fmt.Errorf("wasm error: %w\nwasm stack trace:\n\t%s", exc, stack)
+	}
+
+	// An error returned by a host function is also intentional, so return it unwrapped from HostFunctionError.
+	if hostErr, ok := recovered.(*wasmruntime.HostFunctionError); ok {
+		return fmt.Errorf("%w\nwasm stack trace:\n\t%s", hostErr.Err, stack)
+	}
+
 	// If we have a runtime.Error, something severe happened which should include the stack trace. This could be
 	// a nil pointer from wazero or a user-defined function from ModuleBuilder.
 	if runtimeErr, ok := recovered.(runtime.Error); ok {
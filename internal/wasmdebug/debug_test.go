@@ -67,6 +67,8 @@ func TestAddSignature(t *testing.T) {
 func TestErrorBuilder(t *testing.T) {
 	argErr := errors.New("invalid argument")
 	rteErr := testRuntimeErr("index out of bounds")
+	exc := &api.Exception{Values: []uint64{1, 2}}
+	hostErr := &wasmruntime.HostFunctionError{Err: argErr}
 	i32 := api.ValueTypeI32
 	i32i32i32i32 := []api.ValueType{i32, i32, i32, i32}
 
@@ -126,6 +128,28 @@ wasm stack trace:
 	x.y()`,
 			expectUnwrap: wasmruntime.ErrRuntimeCallStackOverflow,
 		},
+		{
+			name: "api.Exception",
+			build: func(builder ErrorBuilder) error {
+				builder.AddFrame("x.y", nil, nil)
+				return builder.FromRecovered(exc)
+			},
+			expectedErr: `wasm error: uncaught exception with values [1 2]
+wasm stack trace:
+	x.y()`,
+			expectUnwrap: exc,
+		},
+		{
+			name: "wasmruntime.HostFunctionError",
+			build: func(builder ErrorBuilder) error {
+				builder.AddFrame("x.y", nil, nil)
+				return builder.FromRecovered(hostErr)
+			},
+			expectedErr: `invalid argument
+wasm stack trace:
+	x.y()`,
+			expectUnwrap: argErr,
+		},
 	}
 
 	for _, tt := range tests {
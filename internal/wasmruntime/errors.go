@@ -7,6 +7,12 @@ var (
 	// ErrRuntimeCallStackOverflow indicates that there are too many function calls,
 	// and the Engine terminated the execution.
 	ErrRuntimeCallStackOverflow = New("callstack overflow")
+	// ErrRuntimeValueStackOverflow indicates that the values of the function calls exceed the value stack,
+	// and the Engine terminated the execution.
+	ErrRuntimeValueStackOverflow = New("value stack overflow")
+	// ErrRuntimeFuelExhausted indicates that the remaining fuel was less than the cost of the instructions to
+	// execute, and the Engine terminated the execution.
+	ErrRuntimeFuelExhausted = New("fuel exhausted")
 	// ErrRuntimeInvalidConversionToInteger indicates the Wasm function tries to
 	// convert NaN floating point value to integers during trunc variant instructions.
 	ErrRuntimeInvalidConversionToInteger = New("invalid conversion to integer")
@@ -27,6 +33,11 @@ var (
 	ErrRuntimeInvalidTableAccess = New("invalid table access")
 	// ErrRuntimeIndirectCallTypeMismatch indicates that the type check failed during call_indirect.
 	ErrRuntimeIndirectCallTypeMismatch = New("indirect call type mismatch")
+	// ErrRuntimeUnalignedAtomic indicates that the program tried to execute an atomic instruction
+	// on an address which is not aligned to the size of the accessed value.
+	ErrRuntimeUnalignedAtomic = New("unaligned atomic")
+	// ErrRuntimeExpectedSharedMemory indicates that the program tried to wait on a memory which is not shared.
+	ErrRuntimeExpectedSharedMemory = New("expected shared memory")
 )
 
 // Error is returned by a wasm.Engine during the execution of Wasm functions, and they indicate that the Wasm runtime
@@ -42,3 +53,17 @@ func New(text string) *Error {
 func (e *Error) Error() string {
 	return e.s
 }
+
+// HostFunctionError wraps a non-nil error returned by a host function, which traps the calling Wasm function.
+// Unlike a panic in a host function, this is intentional, so the error is returned from the call as is.
+type HostFunctionError struct {
+	Err error
+}
+
+func (e *HostFunctionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *HostFunctionError) Unwrap() error {
+	return e.Err
+}
@@ -23,6 +23,10 @@ const (
 	controlFrameKindLoop
 	controlFrameKindIfWithElse
 	controlFrameKindIfWithoutElse
+	// controlFrameKindTry is a try block before its first handler.
+	controlFrameKindTry
+	// controlFrameKindCatch is a try block after its first handler.
+	controlFrameKindCatch
 )
 
 type (
@@ -59,6 +63,9 @@ func (c *controlFrame) asBranchTarget() *BranchTarget {
 	case controlFrameKindIfWithElse,
 		controlFrameKindIfWithoutElse:
 		return &BranchTarget{Label: &Label{FrameID: c.frameID, Kind: LabelKindContinuation}}
+	case controlFrameKindTry,
+		controlFrameKindCatch:
+		return &BranchTarget{Label: &Label{FrameID: c.frameID, Kind: LabelKindContinuation}}
 	}
 	panic(fmt.Sprintf("unreachable: a bug in wazeroir implementation: %v", c.kind))
 }
@@ -87,6 +94,18 @@ func (c *controlFrames) top() *controlFrame {
 	return c.frames[len(c.frames)-1]
 }
 
+// innermostTry returns the frame ID of the innermost try block at the given depth or below, whose handlers are not
+// entered yet. This returns nil if there's no such try block, meaning that exceptions propagate to the caller.
+func (c *controlFrames) innermostTry(depth int) *uint32 {
+	for i := len(c.frames) - depth - 1; i >= 0; i-- {
+		if frame := c.frames[i]; frame.kind == controlFrameKindTry {
+			id := frame.frameID
+			return &id
+		}
+	}
+	return nil
+}
+
 func (c *controlFrames) empty() bool {
 	return len(c.frames) == 0
 }
@@ -139,6 +158,9 @@ type compiler struct {
 	pc     uint64
 	result CompilationResult
 
+	// stackHeightInUint64 is stackLenInUint64(len(stack)), which is tracked for CompilationResult.MaxStackHeight.
+	stackHeightInUint64 int
+
 	// body holds the code for the function's body where Wasm instructions are stored.
 	body []byte
 	// sig is the function type of the target function.
@@ -155,6 +177,22 @@ type compiler struct {
 	funcs []uint32
 	// globals holds the global types for all declard globas in the module where the targe function exists.
 	globals []*wasm.GlobalType
+	// tags holds the types of all tags in the module where the target function exists, including imported ones.
+	tags []*wasm.FunctionType
+	// memories holds the types of all memories in the module where the target function exists, including imported ones.
+	memories []*wasm.Memory
+	// hasMemory64 is true if any of memories is indexed with i64.
+	hasMemory64 bool
+
+	// fuelCosts is wasm.Module FuelCosts, and fuel is the OperationConsumeFuel of the current label, to which the
+	// costs of instructions are added. Both are nil unless fuel metering is enabled.
+	fuelCosts func(instruction string) uint32
+	fuel      *OperationConsumeFuel
+}
+
+// isMemory64 returns true if the memory of the given index is indexed with i64.
+func (c *compiler) isMemory64(memoryIndex uint32) bool {
+	return c.hasMemory64 && c.memories[memoryIndex].IsMemory64
 }
 
 // For debugging only.
@@ -200,8 +238,13 @@ type CompilationResult struct {
 	Functions []wasm.Index
 	// Types holds all the types in the module from which this function is compiled.
 	Types []*wasm.FunctionType
+	// Tags holds the types of all the tags in the module from which this function is compiled, including imported ones.
+	Tags []*wasm.FunctionType
 	// TableTypes holds all the reference types of all tables declared in the module.
 	TableTypes []wasm.ValueType
+	// Memories holds all the declarations of memories in the module from which this function is compiled, including
+	// imported ones.
+	Memories []*wasm.Memory
 	// HasMemory is true if the module from which this function is compiled has memory declaration.
 	HasMemory bool
 	// HasTable is true if the module from which this function is compiled has table declaration.
@@ -210,39 +253,49 @@ type CompilationResult struct {
 	NeedsAccessToDataInstances bool
 	// NeedsAccessToDataInstances is true if the function needs access to element instances via table.init or elem.drop instructions.
 	NeedsAccessToElementInstances bool
+	// MaxStackHeight is the maximum height in uint64 of the stack of the function, including its params and locals.
+	// A vector takes two. This allows an engine to check the stack can grow enough once, on entering the function.
+	MaxStackHeight int
 }
 
 func CompileFunctions(_ context.Context, enabledFeatures wasm.Features, module *wasm.Module) ([]*CompilationResult, error) {
 	// Note: If you use the context.Context param, don't forget to coerce nil to context.Background()!
 
-	functions, globals, mem, tables, err := module.AllDeclarations()
+	functions, globals, memories, tables, err := module.AllDeclarations()
 	if err != nil {
 		return nil, err
 	}
 
-	hasMemory, hasTable := mem != nil, len(tables) > 0
+	hasMemory, hasTable := len(memories) > 0, len(tables) > 0
 
 	tableTypes := make([]wasm.ValueType, len(tables))
 	for i := range tableTypes {
 		tableTypes[i] = tables[i].Type
 	}
 
+	var tags []*wasm.FunctionType
+	for i := wasm.Index(0); i < module.ImportTagCount()+module.SectionElementCount(wasm.SectionIDTag); i++ {
+		tags = append(tags, module.TypeOfTag(i))
+	}
+
 	var ret []*CompilationResult
 	for funcIndex := range module.FunctionSection {
 		typeID := module.FunctionSection[funcIndex]
 		sig := module.TypeSection[typeID]
 		code := module.CodeSection[funcIndex]
-		r, err := compile(enabledFeatures, sig, code.Body, code.LocalTypes, module.TypeSection, functions, globals)
+		r, err := compile(enabledFeatures, sig, code.Body, code.LocalTypes, module.TypeSection, functions, globals, tags, memories, module.FuelCosts)
 		if err != nil {
 			return nil, fmt.Errorf("failed to lower func[%d/%d] to wazeroir: %w", funcIndex, len(functions)-1, err)
 		}
 		r.Globals = globals
 		r.Functions = functions
 		r.Types = module.TypeSection
+		r.Tags = tags
 		r.HasMemory = hasMemory
 		r.HasTable = hasTable
 		r.Signature = sig
 		r.TableTypes = tableTypes
+		r.Memories = memories
 		ret = append(ret, r)
 	}
 	return ret, nil
@@ -257,6 +310,9 @@ func compile(enabledFeatures wasm.Features,
 	localTypes []wasm.ValueType,
 	types []*wasm.FunctionType,
 	functions []uint32, globals []*wasm.GlobalType,
+	tags []*wasm.FunctionType,
+	memories []*wasm.Memory,
+	fuelCosts func(instruction string) uint32,
 ) (*CompilationResult, error) {
 	c := compiler{
 		enabledFeatures: enabledFeatures,
@@ -268,10 +324,23 @@ func compile(enabledFeatures wasm.Features,
 		globals:         globals,
 		funcs:           functions,
 		types:           types,
+		tags:            tags,
+		memories:        memories,
+		fuelCosts:       fuelCosts,
+	}
+	for _, m := range memories {
+		if m.IsMemory64 {
+			c.hasMemory64 = true
+		}
 	}
 
 	c.calcLocalIndexToStackHeight()
 
+	if fuelCosts != nil {
+		c.fuel = &OperationConsumeFuel{}
+		c.emit(c.fuel)
+	}
+
 	// Push function arguments.
 	for _, t := range sig.Params {
 		c.stackPush(wasmValueTypeToUnsignedType(t)...)
@@ -297,6 +366,18 @@ func compile(enabledFeatures wasm.Features,
 			return nil, fmt.Errorf("handling instruction: %w", err)
 		}
 	}
+
+	if fuelCosts != nil {
+		// Remove the fuel consumption of labels without instructions, such as the one at the end of the function.
+		ops := c.result.Operations[:0]
+		for _, op := range c.result.Operations {
+			if o, ok := op.(*OperationConsumeFuel); ok && o.Cost == 0 {
+				continue
+			}
+			ops = append(ops, op)
+		}
+		c.result.Operations = ops
+	}
 	return &c.result, nil
 }
 
@@ -311,6 +392,14 @@ func (c *compiler) handleInstruction() error {
 		)
 	}
 
+	if c.fuelCosts != nil && !c.unreachableState.on {
+		c.fuel.Cost += uint64(c.fuelCosts(c.instructionName(op)))
+	}
+
+	// The value dropped by wasm.OpcodeDrop is popped in applyToStack, so peek here whether it is a vector which spans
+	// two uint64 values on the stack.
+	isDropTargetVector := op == wasm.OpcodeDrop && !c.unreachableState.on && c.stack[len(c.stack)-1] == UnsignedTypeV128
+
 	// Modify the stack according the current instruction.
 	// Note that some instructions will read "index" in
 	// applyToStack and advance c.pc inside the function.
@@ -439,7 +528,7 @@ operatorSwitch:
 			// If it is currently in unreachable, and the non-nested if,
 			// reset the stack so we can correctly handle the else block.
 			top := c.controlFrames.top()
-			c.stack = c.stack[:top.originalStackLenWithoutParam]
+			c.stackTruncate(top.originalStackLenWithoutParam)
 			top.kind = controlFrameKindIfWithElse
 
 			// Re-push the parameters to the if block so that else block can use them.
@@ -468,7 +557,7 @@ operatorSwitch:
 
 		// Reset the stack manipulated by the then block, and re-push the block param types to the stack.
 
-		c.stack = c.stack[:frame.originalStackLenWithoutParam]
+		c.stackTruncate(frame.originalStackLenWithoutParam)
 		for _, t := range frame.blockType.Params {
 			c.stackPush(wasmValueTypeToUnsignedType(t)...)
 		}
@@ -487,7 +576,18 @@ operatorSwitch:
 			// Initiate the else block.
 			&OperationLabel{Label: elseLabel},
 		)
-	case wasm.OpcodeEnd:
+	case wasm.OpcodeEnd, wasm.OpcodeDelegate:
+		// delegate ends a try block like end, but its handlers are of the try block at the given label.
+		var delegateDepth uint32
+		if op == wasm.OpcodeDelegate {
+			v, n, err := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc+1:]))
+			if err != nil {
+				return fmt.Errorf("read the label for delegate: %w", err)
+			}
+			c.pc += n
+			delegateDepth = v
+		}
+
 		if c.unreachableState.on && c.unreachableState.depth > 0 {
 			c.unreachableState.depth--
 			break operatorSwitch
@@ -498,8 +598,9 @@ operatorSwitch:
 			if c.controlFrames.empty() {
 				return nil
 			}
+			c.emitDelegateIfTry(frame, delegateDepth)
 
-			c.stack = c.stack[:frame.originalStackLenWithoutParam]
+			c.stackTruncate(frame.originalStackLenWithoutParam)
 			for _, t := range frame.blockType.Results {
 				c.stackPush(wasmValueTypeToUnsignedType(t)...)
 			}
@@ -524,11 +625,12 @@ operatorSwitch:
 		}
 
 		frame := c.controlFrames.pop()
+		c.emitDelegateIfTry(frame, delegateDepth)
 
 		// We need to reset the stack so that
 		// the values pushed inside the block.
 		dropOp := &OperationDrop{Depth: c.getFrameDropRange(frame, true)}
-		c.stack = c.stack[:frame.originalStackLenWithoutParam]
+		c.stackTruncate(frame.originalStackLenWithoutParam)
 
 		// Push the result types onto the stack.
 		for _, t := range frame.blockType.Results {
@@ -563,7 +665,9 @@ operatorSwitch:
 				&OperationLabel{Label: continuationLabel},
 			)
 		case controlFrameKindBlockWithContinuationLabel,
-			controlFrameKindIfWithElse:
+			controlFrameKindIfWithElse,
+			controlFrameKindTry,
+			controlFrameKindCatch:
 			continuationLabel := &Label{Kind: LabelKindContinuation, FrameID: frame.frameID}
 			c.result.LabelCallers[continuationLabel.String()]++
 			c.emit(
@@ -706,20 +810,160 @@ operatorSwitch:
 		c.emit(
 			&OperationCallIndirect{TypeIndex: *index, TableIndex: tableIndex},
 		)
+	case wasm.OpcodeReturnCall:
+		if index == nil {
+			return fmt.Errorf("index does not exist for function tail call")
+		}
+		paramNum := c.types[c.funcs[*index]].ParamNumInUint64
+		// Drop everything in the current frame except for the arguments, so that the callee can reuse it.
+		c.emit(
+			&OperationDrop{Depth: c.getTailCallDropRange(paramNum)},
+			&OperationTailCall{FunctionIndex: *index},
+		)
+		// Tail call is stack-polymorphic just like return.
+		c.markUnreachable()
+	case wasm.OpcodeReturnCallIndirect:
+		if index == nil {
+			return fmt.Errorf("index does not exist for indirect function tail call")
+		}
+		tableIndex, n, err := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc+1:]))
+		if err != nil {
+			return fmt.Errorf("read target for return_call_indirect: %w", err)
+		}
+		c.pc += n
+		// Drop everything in the current frame except for the arguments and the offset in the table, so that the
+		// callee can reuse it.
+		paramNum := c.types[*index].ParamNumInUint64 + 1
+		c.emit(
+			&OperationDrop{Depth: c.getTailCallDropRange(paramNum)},
+			&OperationTailCallIndirect{TypeIndex: *index, TableIndex: tableIndex},
+		)
+		// Tail call is stack-polymorphic just like return.
+		c.markUnreachable()
+	case wasm.OpcodeTry:
+		bt, num, err := wasm.DecodeBlockType(c.types, bytes.NewReader(c.body[c.pc+1:]), c.enabledFeatures)
+		if err != nil {
+			return fmt.Errorf("reading block type for try instruction: %w", err)
+		}
+		c.pc += num
+
+		if c.unreachableState.on {
+			// If it is currently in unreachable,
+			// just remove the entire block.
+			c.unreachableState.depth++
+			break operatorSwitch
+		}
+
+		// Create a new frame -- entering try. Note this will be set to controlFrameKindCatch when the first handler
+		// is found later.
+		frame := &controlFrame{
+			frameID:                      c.nextID(),
+			originalStackLenWithoutParam: len(c.stack) - len(bt.Params),
+			kind:                         controlFrameKindTry,
+			blockType:                    bt,
+		}
+		outer := c.controlFrames.innermostTry(0)
+		c.controlFrames.push(frame)
+
+		c.emit(
+			&OperationTry{
+				FrameID:     frame.frameID,
+				Outer:       outer,
+				StackHeight: c.stackLenInUint64(frame.originalStackLenWithoutParam),
+			},
+		)
+	case wasm.OpcodeCatch, wasm.OpcodeCatchAll:
+		var tagIndex uint32
+		if op == wasm.OpcodeCatch {
+			v, n, err := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc+1:]))
+			if err != nil {
+				return fmt.Errorf("read the tag for catch: %w", err)
+			}
+			c.pc += n
+			tagIndex = v
+		}
+
+		if c.unreachableState.on && c.unreachableState.depth > 0 {
+			// If it is currently in unreachable, and the nested try,
+			// just remove the entire handler.
+			break operatorSwitch
+		}
+
+		frame := c.controlFrames.top()
+		continuationLabel := &Label{FrameID: frame.frameID, Kind: LabelKindContinuation}
+		if c.unreachableState.on {
+			// We are no longer unreachable in the handler as it is entered by exceptions.
+			c.resetUnreachable()
+		} else {
+			// Exit the try block or the previous handler by jumping to the continuation of this try block.
+			c.result.LabelCallers[continuationLabel.String()]++
+			c.emit(
+				&OperationDrop{Depth: c.getFrameDropRange(frame, true)},
+				&OperationBr{Target: continuationLabel.asBranchTarget()},
+			)
+		}
+		frame.kind = controlFrameKindCatch
+
+		// Handlers start with the stack below the try block params, followed by the handle of the caught exception
+		// and its values.
+		c.stackTruncate(frame.originalStackLenWithoutParam)
+		c.stackPush(UnsignedTypeI64)
+		if op == wasm.OpcodeCatch {
+			for _, t := range c.tags[tagIndex].Params {
+				c.stackPush(wasmValueTypeToUnsignedType(t)...)
+			}
+		}
+		c.emit(
+			&OperationCatch{FrameID: frame.frameID, TagIndex: tagIndex, CatchAll: op == wasm.OpcodeCatchAll},
+		)
+	case wasm.OpcodeThrow:
+		if index == nil {
+			return fmt.Errorf("index does not exist for throw")
+		}
+		c.emit(
+			&OperationThrow{TagIndex: *index},
+		)
+		// Throw operation is stack-polymorphic, and mark the state as unreachable.
+		c.markUnreachable()
+	case wasm.OpcodeRethrow:
+		targetIndex, n, err := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc+1:]))
+		if err != nil {
+			return fmt.Errorf("read the label for rethrow: %w", err)
+		}
+		c.pc += n
+
+		if c.unreachableState.on {
+			break operatorSwitch
+		}
+
+		// The handle of the exception is at the bottom of the handler's stack.
+		targetFrame := c.controlFrames.get(int(targetIndex))
+		depth := c.stackLenInUint64(len(c.stack)) - 1 - c.stackLenInUint64(targetFrame.originalStackLenWithoutParam)
+		c.emit(
+			&OperationPick{Depth: depth},
+			&OperationRethrow{},
+		)
+		// Rethrow operation is stack-polymorphic, and mark the state as unreachable.
+		c.markUnreachable()
 	case wasm.OpcodeDrop:
+		r := &InclusiveRange{Start: 0, End: 0}
+		if isDropTargetVector {
+			r.End = 1
+		}
 		c.emit(
-			&OperationDrop{Depth: &InclusiveRange{Start: 0, End: 0}},
+			&OperationDrop{Depth: r},
 		)
 	case wasm.OpcodeSelect:
 		c.emit(
-			&OperationSelect{},
+			// The result is pushed by applyToStack with the type of the selected values.
+			&OperationSelect{IsTargetVector: !c.unreachableState.on && c.stack[len(c.stack)-1] == UnsignedTypeV128},
 		)
 	case wasm.OpcodeTypedSelect:
 		// Skips two bytes: vector size fixed to 1, and the value type for select.
 		c.pc += 2
 		// Typed select is semantically equivalent to select at runtime.
 		c.emit(
-			&OperationSelect{},
+			&OperationSelect{IsTargetVector: !c.unreachableState.on && c.stack[len(c.stack)-1] == UnsignedTypeV128},
 		)
 	case wasm.OpcodeLocalGet:
 		if index == nil {
@@ -982,15 +1226,36 @@ operatorSwitch:
 			&OperationStore32{Arg: imm},
 		)
 	case wasm.OpcodeMemorySize:
-		c.pc++ // Skip the reserved one byte.
+		memoryIndex, num, err := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc+1:]))
+		if err != nil {
+			return fmt.Errorf("reading memory index for memory.size: %w", err)
+		}
+		c.pc += num
 		c.emit(
-			&OperationMemorySize{},
+			&OperationMemorySize{MemoryIndex: memoryIndex},
 		)
+		if c.isMemory64(memoryIndex) {
+			// The number of pages is always within 32-bit in wazero.
+			c.emit(
+				&OperationExtend{Signed: false},
+			)
+		}
 	case wasm.OpcodeMemoryGrow:
-		c.pc++ // Skip the reserved one byte.
+		memoryIndex, num, err := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc+1:]))
+		if err != nil {
+			return fmt.Errorf("reading memory index for memory.grow: %w", err)
+		}
+		c.pc += num
 		c.emit(
-			&OperationMemoryGrow{},
+			&OperationMemoryGrow{MemoryIndex: memoryIndex},
 		)
+		if c.isMemory64(memoryIndex) {
+			// The engines grow a memory indexed with i64 by the 64-bit delta, and push the result as i32, which is
+			// always within 32-bit in wazero or -1 on failure.
+			c.emit(
+				&OperationExtend{Signed: true},
+			)
+		}
 	case wasm.OpcodeI32Const:
 		val, num, err := leb128.DecodeInt32(bytes.NewReader(c.body[c.pc+1:]))
 		if err != nil {
@@ -1609,13 +1874,18 @@ operatorSwitch:
 				&OperationITruncFromF{InputType: Float64, OutputType: SignedUint64, NonTrapping: true},
 			)
 		case wasm.OpcodeMiscMemoryInit:
-			dataIndex, num, err := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc+1:]))
+			r := bytes.NewReader(c.body[c.pc+1:])
+			dataIndex, num, err := leb128.DecodeUint32(r)
 			if err != nil {
 				return fmt.Errorf("reading i32.const value: %v", err)
 			}
-			c.pc += num + 1 // +1 to skip the memory index which is fixed to zero.
+			memoryIndex, num2, err := leb128.DecodeUint32(r)
+			if err != nil {
+				return fmt.Errorf("reading memory index for memory.init: %w", err)
+			}
+			c.pc += num + num2
 			c.emit(
-				&OperationMemoryInit{DataIndex: dataIndex},
+				&OperationMemoryInit{DataIndex: dataIndex, MemoryIndex: memoryIndex},
 			)
 			c.result.NeedsAccessToDataInstances = true
 		case wasm.OpcodeMiscDataDrop:
@@ -1629,14 +1899,27 @@ operatorSwitch:
 			)
 			c.result.NeedsAccessToDataInstances = true
 		case wasm.OpcodeMiscMemoryCopy:
-			c.pc += 2 // +2 to skip two memory indexes which are fixed to zero.
+			r := bytes.NewReader(c.body[c.pc+1:])
+			dstMemoryIndex, num, err := leb128.DecodeUint32(r)
+			if err != nil {
+				return fmt.Errorf("reading destination memory index for memory.copy: %w", err)
+			}
+			srcMemoryIndex, num2, err := leb128.DecodeUint32(r)
+			if err != nil {
+				return fmt.Errorf("reading source memory index for memory.copy: %w", err)
+			}
+			c.pc += num + num2
 			c.emit(
-				&OperationMemoryCopy{},
+				&OperationMemoryCopy{SrcMemoryIndex: srcMemoryIndex, DstMemoryIndex: dstMemoryIndex},
 			)
 		case wasm.OpcodeMiscMemoryFill:
-			c.pc += 1 // +1 to skip the memory index which is fixed to zero.
+			memoryIndex, num, err := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc+1:]))
+			if err != nil {
+				return fmt.Errorf("reading memory index for memory.fill: %w", err)
+			}
+			c.pc += num
 			c.emit(
-				&OperationMemoryFill{},
+				&OperationMemoryFill{MemoryIndex: memoryIndex},
 			)
 		case wasm.OpcodeMiscTableInit:
 			elemIndex, num, err := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc+1:]))
@@ -1720,147 +2003,1322 @@ operatorSwitch:
 		}
 	case wasm.OpcodeVecPrefix:
 		c.pc++
-		switch miscOp := c.body[c.pc]; miscOp {
+		vecOp, num, err := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc:]))
+		if err != nil {
+			return fmt.Errorf("reading vector instruction: %w", err)
+		}
+		c.pc += num - 1
+		switch wasm.OpcodeVec(vecOp) {
 		case wasm.OpcodeVecV128Const:
 			c.pc++
 			lo := binary.LittleEndian.Uint64(c.body[c.pc : c.pc+8])
 			c.pc += 8
 			hi := binary.LittleEndian.Uint64(c.body[c.pc : c.pc+8])
 			c.emit(
-				&OperationConstV128{Lo: lo, Hi: hi},
+				&OperationV128Const{Lo: lo, Hi: hi},
 			)
 			c.pc += 7
-		case wasm.OpcodeVecI32x4Add:
+		case wasm.OpcodeVecV128Load:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128LoadName)
+			if err != nil {
+				return err
+			}
 			c.emit(
-				&OperationAddV128{Shape: ShapeI32x4},
+				&OperationV128Load{Type: V128LoadType128, Arg: imm},
 			)
-		case wasm.OpcodeVecI64x2Add:
+		case wasm.OpcodeVecV128Load8x8S:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load8x8SName)
+			if err != nil {
+				return err
+			}
 			c.emit(
-				&OperationAddV128{Shape: ShapeI64x2},
+				&OperationV128Load{Type: V128LoadType8x8s, Arg: imm},
 			)
-		default:
-			return fmt.Errorf("unsupported vector instruction in wazeroir: 0x%x", op)
-		}
-	default:
-		return fmt.Errorf("unsupported instruction in wazeroir: 0x%x", op)
-	}
-
-	// Move the program counter to point to the next instruction.
-	c.pc++
-	return nil
-}
-
-func (c *compiler) nextID() (id uint32) {
-	id = c.currentID + 1
-	c.currentID++
-	return
-}
-
-func (c *compiler) applyToStack(opcode wasm.Opcode) (*uint32, error) {
-	var index uint32
-	var ptr *uint32
-	switch opcode {
-	case
-		// These are the opcodes that is coupled with "index"　immediate
-		// and it DOES affect the signature of opcode.
-		wasm.OpcodeCall,
-		wasm.OpcodeCallIndirect,
-		wasm.OpcodeLocalGet,
-		wasm.OpcodeLocalSet,
-		wasm.OpcodeLocalTee,
-		wasm.OpcodeGlobalGet,
-		wasm.OpcodeGlobalSet:
-		// Assumes that we are at the opcode now so skip it before read immediates.
-		v, num, err := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc+1:]))
-		if err != nil {
-			return nil, fmt.Errorf("reading immediates: %w", err)
-		}
-		c.pc += num
-		index = v
-		ptr = &index
-	default:
-		// Note that other opcodes are free of index
-		// as it doesn't affect the signature of opt code.
-		// In other words, the "index" argument of wasmOpcodeSignature
-		// is ignored there.
-	}
-
-	if c.unreachableState.on {
-		return ptr, nil
-	}
-
-	// Retrieve the signature of the opcode.
-	s, err := c.wasmOpcodeSignature(opcode, index)
-	if err != nil {
-		return nil, err
-	}
-
-	// Manipulate the stack according to the signature.
-	// Note that the following algorithm assumes that
-	// the unknown type is unique in the signature,
-	// and is determined by the actual type on the stack.
-	// The determined type is stored in this typeParam.
-	var typeParam *UnsignedType
-	for i := range s.in {
-		want := s.in[len(s.in)-1-i]
-		actual := c.stackPop()
-		if want == UnsignedTypeUnknown && typeParam != nil {
-			want = *typeParam
-		} else if want == UnsignedTypeUnknown {
-			want = actual
-			typeParam = &actual
-		}
-		if want != actual {
-			return nil, fmt.Errorf("input signature mismatch: want %s but have %s", want, actual)
-		}
-	}
-
-	for _, target := range s.out {
-		if target == UnsignedTypeUnknown && typeParam == nil {
-			return nil, fmt.Errorf("cannot determine type of unknown result")
-		} else if target == UnsignedTypeUnknown {
-			c.stackPush(*typeParam)
-		} else {
-			c.stackPush(target)
-		}
-	}
-
-	return ptr, nil
-}
-
-func (c *compiler) stackPop() (ret UnsignedType) {
-	// No need to check stack bound
-	// as we can assume that all the operations
-	// are valid thanks to validateFunction
-	// at module validation phase.
-	ret = c.stack[len(c.stack)-1]
-	c.stack = c.stack[:len(c.stack)-1]
-	return
-}
-
-func (c *compiler) stackPush(ts ...UnsignedType) {
-	c.stack = append(c.stack, ts...)
-}
-
-// emit adds the operations into the result.
-func (c *compiler) emit(ops ...Operation) {
-	if !c.unreachableState.on {
-		for _, op := range ops {
-			switch o := op.(type) {
-			case *OperationDrop:
-				// If the drop range is nil,
-				// we could remove such operations.
-				// That happens when drop operation is unnecessary.
-				// i.e. when there's no need to adjust stack before jmp.
-				if o.Depth == nil {
-					continue
-				}
+		case wasm.OpcodeVecV128Load8x8U:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load8x8UName)
+			if err != nil {
+				return err
+			}
+			c.emit(
+				&OperationV128Load{Type: V128LoadType8x8u, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load16x4S:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load16x4SName)
+			if err != nil {
+				return err
+			}
+			c.emit(
+				&OperationV128Load{Type: V128LoadType16x4s, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load16x4U:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load16x4UName)
+			if err != nil {
+				return err
+			}
+			c.emit(
+				&OperationV128Load{Type: V128LoadType16x4u, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load32x2S:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load32x2SName)
+			if err != nil {
+				return err
+			}
+			c.emit(
+				&OperationV128Load{Type: V128LoadType32x2s, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load32x2U:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load32x2UName)
+			if err != nil {
+				return err
+			}
+			c.emit(
+				&OperationV128Load{Type: V128LoadType32x2u, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load8Splat:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load8SplatName)
+			if err != nil {
+				return err
+			}
+			c.emit(
+				&OperationV128Load{Type: V128LoadType8Splat, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load16Splat:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load16SplatName)
+			if err != nil {
+				return err
+			}
+			c.emit(
+				&OperationV128Load{Type: V128LoadType16Splat, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load32Splat:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load32SplatName)
+			if err != nil {
+				return err
+			}
+			c.emit(
+				&OperationV128Load{Type: V128LoadType32Splat, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load64Splat:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load64SplatName)
+			if err != nil {
+				return err
+			}
+			c.emit(
+				&OperationV128Load{Type: V128LoadType64Splat, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load32zero:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load32zeroName)
+			if err != nil {
+				return err
+			}
+			c.emit(
+				&OperationV128Load{Type: V128LoadType32zero, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load64zero:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load64zeroName)
+			if err != nil {
+				return err
+			}
+			c.emit(
+				&OperationV128Load{Type: V128LoadType64zero, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load8Lane:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load8LaneName)
+			if err != nil {
+				return err
+			}
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128LoadLane{LaneIndex: laneIndex, LaneSize: 8, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load16Lane:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load16LaneName)
+			if err != nil {
+				return err
+			}
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128LoadLane{LaneIndex: laneIndex, LaneSize: 16, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load32Lane:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load32LaneName)
+			if err != nil {
+				return err
+			}
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128LoadLane{LaneIndex: laneIndex, LaneSize: 32, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Load64Lane:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Load64LaneName)
+			if err != nil {
+				return err
+			}
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128LoadLane{LaneIndex: laneIndex, LaneSize: 64, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Store:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128StoreName)
+			if err != nil {
+				return err
+			}
+			c.emit(
+				&OperationV128Store{Arg: imm},
+			)
+		case wasm.OpcodeVecV128Store8Lane:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Store8LaneName)
+			if err != nil {
+				return err
+			}
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128StoreLane{LaneIndex: laneIndex, LaneSize: 8, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Store16Lane:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Store16LaneName)
+			if err != nil {
+				return err
+			}
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128StoreLane{LaneIndex: laneIndex, LaneSize: 16, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Store32Lane:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Store32LaneName)
+			if err != nil {
+				return err
+			}
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128StoreLane{LaneIndex: laneIndex, LaneSize: 32, Arg: imm},
+			)
+		case wasm.OpcodeVecV128Store64Lane:
+			imm, err := c.readMemoryImmediate(wasm.OpcodeVecV128Store64LaneName)
+			if err != nil {
+				return err
+			}
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128StoreLane{LaneIndex: laneIndex, LaneSize: 64, Arg: imm},
+			)
+		case wasm.OpcodeVecI8x16ExtractLaneS:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ExtractLane{LaneIndex: laneIndex, Signed: true, Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI8x16ExtractLaneU:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ExtractLane{LaneIndex: laneIndex, Signed: false, Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI8x16ReplaceLane:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ReplaceLane{LaneIndex: laneIndex, Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI8x16Splat:
+			c.emit(
+				&OperationV128Splat{Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI16x8ExtractLaneS:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ExtractLane{LaneIndex: laneIndex, Signed: true, Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI16x8ExtractLaneU:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ExtractLane{LaneIndex: laneIndex, Signed: false, Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI16x8ReplaceLane:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ReplaceLane{LaneIndex: laneIndex, Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI16x8Splat:
+			c.emit(
+				&OperationV128Splat{Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI32x4ExtractLane:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ExtractLane{LaneIndex: laneIndex, Shape: ShapeI32x4},
+			)
+		case wasm.OpcodeVecI32x4ReplaceLane:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ReplaceLane{LaneIndex: laneIndex, Shape: ShapeI32x4},
+			)
+		case wasm.OpcodeVecI32x4Splat:
+			c.emit(
+				&OperationV128Splat{Shape: ShapeI32x4},
+			)
+		case wasm.OpcodeVecI64x2ExtractLane:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ExtractLane{LaneIndex: laneIndex, Shape: ShapeI64x2},
+			)
+		case wasm.OpcodeVecI64x2ReplaceLane:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ReplaceLane{LaneIndex: laneIndex, Shape: ShapeI64x2},
+			)
+		case wasm.OpcodeVecI64x2Splat:
+			c.emit(
+				&OperationV128Splat{Shape: ShapeI64x2},
+			)
+		case wasm.OpcodeVecF32x4ExtractLane:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ExtractLane{LaneIndex: laneIndex, Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF32x4ReplaceLane:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ReplaceLane{LaneIndex: laneIndex, Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF32x4Splat:
+			c.emit(
+				&OperationV128Splat{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF64x2ExtractLane:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ExtractLane{LaneIndex: laneIndex, Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF64x2ReplaceLane:
+			c.pc++
+			laneIndex := c.body[c.pc]
+			c.emit(
+				&OperationV128ReplaceLane{LaneIndex: laneIndex, Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF64x2Splat:
+			c.emit(
+				&OperationV128Splat{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecV128i8x16Shuffle:
+			c.pc++
+			op := &OperationV128Shuffle{}
+			copy(op.Lanes[:], c.body[c.pc:c.pc+16])
+			c.emit(op)
+			c.pc += 15
+		case wasm.OpcodeVecI8x16Swizzle:
+			c.emit(
+				&OperationV128Swizzle{},
+			)
+		case wasm.OpcodeVecV128AnyTrue:
+			c.emit(
+				&OperationV128AnyTrue{},
+			)
+		case wasm.OpcodeVecI8x16AllTrue:
+			c.emit(
+				&OperationV128AllTrue{Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI8x16BitMask:
+			c.emit(
+				&OperationV128BitMask{Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI16x8AllTrue:
+			c.emit(
+				&OperationV128AllTrue{Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI16x8BitMask:
+			c.emit(
+				&OperationV128BitMask{Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI32x4AllTrue:
+			c.emit(
+				&OperationV128AllTrue{Shape: ShapeI32x4},
+			)
+		case wasm.OpcodeVecI32x4BitMask:
+			c.emit(
+				&OperationV128BitMask{Shape: ShapeI32x4},
+			)
+		case wasm.OpcodeVecI64x2AllTrue:
+			c.emit(
+				&OperationV128AllTrue{Shape: ShapeI64x2},
+			)
+		case wasm.OpcodeVecI64x2BitMask:
+			c.emit(
+				&OperationV128BitMask{Shape: ShapeI64x2},
+			)
+		case wasm.OpcodeVecV128And:
+			c.emit(
+				&OperationV128And{},
+			)
+		case wasm.OpcodeVecV128Not:
+			c.emit(
+				&OperationV128Not{},
+			)
+		case wasm.OpcodeVecV128Or:
+			c.emit(
+				&OperationV128Or{},
+			)
+		case wasm.OpcodeVecV128Xor:
+			c.emit(
+				&OperationV128Xor{},
+			)
+		case wasm.OpcodeVecV128Bitselect:
+			c.emit(
+				&OperationV128Bitselect{},
+			)
+		case wasm.OpcodeVecV128AndNot:
+			c.emit(
+				&OperationV128AndNot{},
+			)
+		case wasm.OpcodeVecI8x16Shl:
+			c.emit(
+				&OperationV128Shl{Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI8x16ShrS:
+			c.emit(
+				&OperationV128Shr{Shape: ShapeI8x16, Signed: true},
+			)
+		case wasm.OpcodeVecI8x16ShrU:
+			c.emit(
+				&OperationV128Shr{Shape: ShapeI8x16, Signed: false},
+			)
+		case wasm.OpcodeVecI16x8Shl:
+			c.emit(
+				&OperationV128Shl{Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI16x8ShrS:
+			c.emit(
+				&OperationV128Shr{Shape: ShapeI16x8, Signed: true},
+			)
+		case wasm.OpcodeVecI16x8ShrU:
+			c.emit(
+				&OperationV128Shr{Shape: ShapeI16x8, Signed: false},
+			)
+		case wasm.OpcodeVecI32x4Shl:
+			c.emit(
+				&OperationV128Shl{Shape: ShapeI32x4},
+			)
+		case wasm.OpcodeVecI32x4ShrS:
+			c.emit(
+				&OperationV128Shr{Shape: ShapeI32x4, Signed: true},
+			)
+		case wasm.OpcodeVecI32x4ShrU:
+			c.emit(
+				&OperationV128Shr{Shape: ShapeI32x4, Signed: false},
+			)
+		case wasm.OpcodeVecI64x2Shl:
+			c.emit(
+				&OperationV128Shl{Shape: ShapeI64x2},
+			)
+		case wasm.OpcodeVecI64x2ShrS:
+			c.emit(
+				&OperationV128Shr{Shape: ShapeI64x2, Signed: true},
+			)
+		case wasm.OpcodeVecI64x2ShrU:
+			c.emit(
+				&OperationV128Shr{Shape: ShapeI64x2, Signed: false},
+			)
+		case wasm.OpcodeVecI8x16Eq:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI8x16Eq},
+			)
+		case wasm.OpcodeVecI8x16Ne:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI8x16Ne},
+			)
+		case wasm.OpcodeVecI8x16LtS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI8x16LtS},
+			)
+		case wasm.OpcodeVecI8x16LtU:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI8x16LtU},
+			)
+		case wasm.OpcodeVecI8x16GtS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI8x16GtS},
+			)
+		case wasm.OpcodeVecI8x16GtU:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI8x16GtU},
+			)
+		case wasm.OpcodeVecI8x16LeS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI8x16LeS},
+			)
+		case wasm.OpcodeVecI8x16LeU:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI8x16LeU},
+			)
+		case wasm.OpcodeVecI8x16GeS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI8x16GeS},
+			)
+		case wasm.OpcodeVecI8x16GeU:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI8x16GeU},
+			)
+		case wasm.OpcodeVecI16x8Eq:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI16x8Eq},
+			)
+		case wasm.OpcodeVecI16x8Ne:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI16x8Ne},
+			)
+		case wasm.OpcodeVecI16x8LtS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI16x8LtS},
+			)
+		case wasm.OpcodeVecI16x8LtU:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI16x8LtU},
+			)
+		case wasm.OpcodeVecI16x8GtS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI16x8GtS},
+			)
+		case wasm.OpcodeVecI16x8GtU:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI16x8GtU},
+			)
+		case wasm.OpcodeVecI16x8LeS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI16x8LeS},
+			)
+		case wasm.OpcodeVecI16x8LeU:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI16x8LeU},
+			)
+		case wasm.OpcodeVecI16x8GeS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI16x8GeS},
+			)
+		case wasm.OpcodeVecI16x8GeU:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI16x8GeU},
+			)
+		case wasm.OpcodeVecI32x4Eq:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI32x4Eq},
+			)
+		case wasm.OpcodeVecI32x4Ne:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI32x4Ne},
+			)
+		case wasm.OpcodeVecI32x4LtS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI32x4LtS},
+			)
+		case wasm.OpcodeVecI32x4LtU:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI32x4LtU},
+			)
+		case wasm.OpcodeVecI32x4GtS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI32x4GtS},
+			)
+		case wasm.OpcodeVecI32x4GtU:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI32x4GtU},
+			)
+		case wasm.OpcodeVecI32x4LeS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI32x4LeS},
+			)
+		case wasm.OpcodeVecI32x4LeU:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI32x4LeU},
+			)
+		case wasm.OpcodeVecI32x4GeS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI32x4GeS},
+			)
+		case wasm.OpcodeVecI32x4GeU:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI32x4GeU},
+			)
+		case wasm.OpcodeVecI64x2Eq:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI64x2Eq},
+			)
+		case wasm.OpcodeVecI64x2Ne:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI64x2Ne},
+			)
+		case wasm.OpcodeVecI64x2LtS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI64x2LtS},
+			)
+		case wasm.OpcodeVecI64x2GtS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI64x2GtS},
+			)
+		case wasm.OpcodeVecI64x2LeS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI64x2LeS},
+			)
+		case wasm.OpcodeVecI64x2GeS:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeI64x2GeS},
+			)
+		case wasm.OpcodeVecF32x4Eq:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeF32x4Eq},
+			)
+		case wasm.OpcodeVecF32x4Ne:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeF32x4Ne},
+			)
+		case wasm.OpcodeVecF32x4Lt:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeF32x4Lt},
+			)
+		case wasm.OpcodeVecF32x4Gt:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeF32x4Gt},
+			)
+		case wasm.OpcodeVecF32x4Le:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeF32x4Le},
+			)
+		case wasm.OpcodeVecF32x4Ge:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeF32x4Ge},
+			)
+		case wasm.OpcodeVecF64x2Eq:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeF64x2Eq},
+			)
+		case wasm.OpcodeVecF64x2Ne:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeF64x2Ne},
+			)
+		case wasm.OpcodeVecF64x2Lt:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeF64x2Lt},
+			)
+		case wasm.OpcodeVecF64x2Gt:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeF64x2Gt},
+			)
+		case wasm.OpcodeVecF64x2Le:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeF64x2Le},
+			)
+		case wasm.OpcodeVecF64x2Ge:
+			c.emit(
+				&OperationV128Cmp{Type: V128CmpTypeF64x2Ge},
+			)
+		case wasm.OpcodeVecI8x16Add:
+			c.emit(
+				&OperationV128Add{Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI8x16Sub:
+			c.emit(
+				&OperationV128Sub{Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI16x8Add:
+			c.emit(
+				&OperationV128Add{Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI16x8Sub:
+			c.emit(
+				&OperationV128Sub{Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI32x4Add:
+			c.emit(
+				&OperationV128Add{Shape: ShapeI32x4},
+			)
+		case wasm.OpcodeVecI32x4Sub:
+			c.emit(
+				&OperationV128Sub{Shape: ShapeI32x4},
+			)
+		case wasm.OpcodeVecI64x2Add:
+			c.emit(
+				&OperationV128Add{Shape: ShapeI64x2},
+			)
+		case wasm.OpcodeVecI64x2Sub:
+			c.emit(
+				&OperationV128Sub{Shape: ShapeI64x2},
+			)
+		case wasm.OpcodeVecF32x4Add:
+			c.emit(
+				&OperationV128Add{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF32x4Sub:
+			c.emit(
+				&OperationV128Sub{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF64x2Add:
+			c.emit(
+				&OperationV128Add{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF64x2Sub:
+			c.emit(
+				&OperationV128Sub{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecI8x16AddSatS:
+			c.emit(
+				&OperationV128AddSat{Shape: ShapeI8x16, Signed: true},
+			)
+		case wasm.OpcodeVecI8x16AddSatU:
+			c.emit(
+				&OperationV128AddSat{Shape: ShapeI8x16, Signed: false},
+			)
+		case wasm.OpcodeVecI8x16SubSatS:
+			c.emit(
+				&OperationV128SubSat{Shape: ShapeI8x16, Signed: true},
+			)
+		case wasm.OpcodeVecI8x16SubSatU:
+			c.emit(
+				&OperationV128SubSat{Shape: ShapeI8x16, Signed: false},
+			)
+		case wasm.OpcodeVecI16x8AddSatS:
+			c.emit(
+				&OperationV128AddSat{Shape: ShapeI16x8, Signed: true},
+			)
+		case wasm.OpcodeVecI16x8AddSatU:
+			c.emit(
+				&OperationV128AddSat{Shape: ShapeI16x8, Signed: false},
+			)
+		case wasm.OpcodeVecI16x8SubSatS:
+			c.emit(
+				&OperationV128SubSat{Shape: ShapeI16x8, Signed: true},
+			)
+		case wasm.OpcodeVecI16x8SubSatU:
+			c.emit(
+				&OperationV128SubSat{Shape: ShapeI16x8, Signed: false},
+			)
+		case wasm.OpcodeVecI16x8Mul:
+			c.emit(
+				&OperationV128Mul{Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI32x4Mul:
+			c.emit(
+				&OperationV128Mul{Shape: ShapeI32x4},
+			)
+		case wasm.OpcodeVecI64x2Mul:
+			c.emit(
+				&OperationV128Mul{Shape: ShapeI64x2},
+			)
+		case wasm.OpcodeVecF32x4Mul:
+			c.emit(
+				&OperationV128Mul{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF64x2Mul:
+			c.emit(
+				&OperationV128Mul{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF32x4Div:
+			c.emit(
+				&OperationV128Div{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF32x4Sqrt:
+			c.emit(
+				&OperationV128Sqrt{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF32x4Min:
+			c.emit(
+				&OperationV128Min{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF32x4Max:
+			c.emit(
+				&OperationV128Max{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF32x4Pmin:
+			c.emit(
+				&OperationV128Pmin{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF32x4Pmax:
+			c.emit(
+				&OperationV128Pmax{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF32x4Ceil:
+			c.emit(
+				&OperationV128Ceil{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF32x4Floor:
+			c.emit(
+				&OperationV128Floor{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF32x4Trunc:
+			c.emit(
+				&OperationV128Trunc{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF32x4Nearest:
+			c.emit(
+				&OperationV128Nearest{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF64x2Div:
+			c.emit(
+				&OperationV128Div{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF64x2Sqrt:
+			c.emit(
+				&OperationV128Sqrt{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF64x2Min:
+			c.emit(
+				&OperationV128Min{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF64x2Max:
+			c.emit(
+				&OperationV128Max{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF64x2Pmin:
+			c.emit(
+				&OperationV128Pmin{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF64x2Pmax:
+			c.emit(
+				&OperationV128Pmax{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF64x2Ceil:
+			c.emit(
+				&OperationV128Ceil{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF64x2Floor:
+			c.emit(
+				&OperationV128Floor{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF64x2Trunc:
+			c.emit(
+				&OperationV128Trunc{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF64x2Nearest:
+			c.emit(
+				&OperationV128Nearest{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecI8x16Neg:
+			c.emit(
+				&OperationV128Neg{Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI8x16Abs:
+			c.emit(
+				&OperationV128Abs{Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI16x8Neg:
+			c.emit(
+				&OperationV128Neg{Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI16x8Abs:
+			c.emit(
+				&OperationV128Abs{Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI32x4Neg:
+			c.emit(
+				&OperationV128Neg{Shape: ShapeI32x4},
+			)
+		case wasm.OpcodeVecI32x4Abs:
+			c.emit(
+				&OperationV128Abs{Shape: ShapeI32x4},
+			)
+		case wasm.OpcodeVecI64x2Neg:
+			c.emit(
+				&OperationV128Neg{Shape: ShapeI64x2},
+			)
+		case wasm.OpcodeVecI64x2Abs:
+			c.emit(
+				&OperationV128Abs{Shape: ShapeI64x2},
+			)
+		case wasm.OpcodeVecF32x4Neg:
+			c.emit(
+				&OperationV128Neg{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF32x4Abs:
+			c.emit(
+				&OperationV128Abs{Shape: ShapeF32x4},
+			)
+		case wasm.OpcodeVecF64x2Neg:
+			c.emit(
+				&OperationV128Neg{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecF64x2Abs:
+			c.emit(
+				&OperationV128Abs{Shape: ShapeF64x2},
+			)
+		case wasm.OpcodeVecI8x16Popcnt:
+			c.emit(
+				&OperationV128Popcnt{},
+			)
+		case wasm.OpcodeVecI8x16MinS:
+			c.emit(
+				&OperationV128Min{Shape: ShapeI8x16, Signed: true},
+			)
+		case wasm.OpcodeVecI8x16MinU:
+			c.emit(
+				&OperationV128Min{Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI8x16MaxS:
+			c.emit(
+				&OperationV128Max{Shape: ShapeI8x16, Signed: true},
+			)
+		case wasm.OpcodeVecI8x16MaxU:
+			c.emit(
+				&OperationV128Max{Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI16x8MinS:
+			c.emit(
+				&OperationV128Min{Shape: ShapeI16x8, Signed: true},
+			)
+		case wasm.OpcodeVecI16x8MinU:
+			c.emit(
+				&OperationV128Min{Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI16x8MaxS:
+			c.emit(
+				&OperationV128Max{Shape: ShapeI16x8, Signed: true},
+			)
+		case wasm.OpcodeVecI16x8MaxU:
+			c.emit(
+				&OperationV128Max{Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI32x4MinS:
+			c.emit(
+				&OperationV128Min{Shape: ShapeI32x4, Signed: true},
+			)
+		case wasm.OpcodeVecI32x4MinU:
+			c.emit(
+				&OperationV128Min{Shape: ShapeI32x4},
+			)
+		case wasm.OpcodeVecI32x4MaxS:
+			c.emit(
+				&OperationV128Max{Shape: ShapeI32x4, Signed: true},
+			)
+		case wasm.OpcodeVecI32x4MaxU:
+			c.emit(
+				&OperationV128Max{Shape: ShapeI32x4},
+			)
+		case wasm.OpcodeVecI8x16AvgrU:
+			c.emit(
+				&OperationV128AvgrU{Shape: ShapeI8x16},
+			)
+		case wasm.OpcodeVecI16x8AvgrU:
+			c.emit(
+				&OperationV128AvgrU{Shape: ShapeI16x8},
+			)
+		case wasm.OpcodeVecI16x8ExtendLowI8x16S:
+			c.emit(
+				&OperationV128Extend{OriginShape: ShapeI8x16, Signed: true, UseLow: true},
+			)
+		case wasm.OpcodeVecI16x8ExtMulLowI8x16S:
+			c.emit(
+				&OperationV128ExtMul{OriginShape: ShapeI8x16, Signed: true, UseLow: true},
+			)
+		case wasm.OpcodeVecI16x8ExtendLowI8x16U:
+			c.emit(
+				&OperationV128Extend{OriginShape: ShapeI8x16, Signed: false, UseLow: true},
+			)
+		case wasm.OpcodeVecI16x8ExtMulLowI8x16U:
+			c.emit(
+				&OperationV128ExtMul{OriginShape: ShapeI8x16, Signed: false, UseLow: true},
+			)
+		case wasm.OpcodeVecI16x8ExtendHighI8x16S:
+			c.emit(
+				&OperationV128Extend{OriginShape: ShapeI8x16, Signed: true, UseLow: false},
+			)
+		case wasm.OpcodeVecI16x8ExtMulHighI8x16S:
+			c.emit(
+				&OperationV128ExtMul{OriginShape: ShapeI8x16, Signed: true, UseLow: false},
+			)
+		case wasm.OpcodeVecI16x8ExtendHighI8x16U:
+			c.emit(
+				&OperationV128Extend{OriginShape: ShapeI8x16, Signed: false, UseLow: false},
+			)
+		case wasm.OpcodeVecI16x8ExtMulHighI8x16U:
+			c.emit(
+				&OperationV128ExtMul{OriginShape: ShapeI8x16, Signed: false, UseLow: false},
+			)
+		case wasm.OpcodeVecI32x4ExtendLowI16x8S:
+			c.emit(
+				&OperationV128Extend{OriginShape: ShapeI16x8, Signed: true, UseLow: true},
+			)
+		case wasm.OpcodeVecI32x4ExtMulLowI16x8S:
+			c.emit(
+				&OperationV128ExtMul{OriginShape: ShapeI16x8, Signed: true, UseLow: true},
+			)
+		case wasm.OpcodeVecI32x4ExtendLowI16x8U:
+			c.emit(
+				&OperationV128Extend{OriginShape: ShapeI16x8, Signed: false, UseLow: true},
+			)
+		case wasm.OpcodeVecI32x4ExtMulLowI16x8U:
+			c.emit(
+				&OperationV128ExtMul{OriginShape: ShapeI16x8, Signed: false, UseLow: true},
+			)
+		case wasm.OpcodeVecI32x4ExtendHighI16x8S:
+			c.emit(
+				&OperationV128Extend{OriginShape: ShapeI16x8, Signed: true, UseLow: false},
+			)
+		case wasm.OpcodeVecI32x4ExtMulHighI16x8S:
+			c.emit(
+				&OperationV128ExtMul{OriginShape: ShapeI16x8, Signed: true, UseLow: false},
+			)
+		case wasm.OpcodeVecI32x4ExtendHighI16x8U:
+			c.emit(
+				&OperationV128Extend{OriginShape: ShapeI16x8, Signed: false, UseLow: false},
+			)
+		case wasm.OpcodeVecI32x4ExtMulHighI16x8U:
+			c.emit(
+				&OperationV128ExtMul{OriginShape: ShapeI16x8, Signed: false, UseLow: false},
+			)
+		case wasm.OpcodeVecI64x2ExtendLowI32x4S:
+			c.emit(
+				&OperationV128Extend{OriginShape: ShapeI32x4, Signed: true, UseLow: true},
+			)
+		case wasm.OpcodeVecI64x2ExtMulLowI32x4S:
+			c.emit(
+				&OperationV128ExtMul{OriginShape: ShapeI32x4, Signed: true, UseLow: true},
+			)
+		case wasm.OpcodeVecI64x2ExtendLowI32x4U:
+			c.emit(
+				&OperationV128Extend{OriginShape: ShapeI32x4, Signed: false, UseLow: true},
+			)
+		case wasm.OpcodeVecI64x2ExtMulLowI32x4U:
+			c.emit(
+				&OperationV128ExtMul{OriginShape: ShapeI32x4, Signed: false, UseLow: true},
+			)
+		case wasm.OpcodeVecI64x2ExtendHighI32x4S:
+			c.emit(
+				&OperationV128Extend{OriginShape: ShapeI32x4, Signed: true, UseLow: false},
+			)
+		case wasm.OpcodeVecI64x2ExtMulHighI32x4S:
+			c.emit(
+				&OperationV128ExtMul{OriginShape: ShapeI32x4, Signed: true, UseLow: false},
+			)
+		case wasm.OpcodeVecI64x2ExtendHighI32x4U:
+			c.emit(
+				&OperationV128Extend{OriginShape: ShapeI32x4, Signed: false, UseLow: false},
+			)
+		case wasm.OpcodeVecI64x2ExtMulHighI32x4U:
+			c.emit(
+				&OperationV128ExtMul{OriginShape: ShapeI32x4, Signed: false, UseLow: false},
+			)
+		case wasm.OpcodeVecI16x8Q15mulrSatS:
+			c.emit(
+				&OperationV128Q15mulrSatS{},
+			)
+		case wasm.OpcodeVecI16x8ExtaddPairwiseI8x16S:
+			c.emit(
+				&OperationV128ExtAddPairwise{OriginShape: ShapeI8x16, Signed: true},
+			)
+		case wasm.OpcodeVecI16x8ExtaddPairwiseI8x16U:
+			c.emit(
+				&OperationV128ExtAddPairwise{OriginShape: ShapeI8x16, Signed: false},
+			)
+		case wasm.OpcodeVecI32x4ExtaddPairwiseI16x8S:
+			c.emit(
+				&OperationV128ExtAddPairwise{OriginShape: ShapeI16x8, Signed: true},
+			)
+		case wasm.OpcodeVecI32x4ExtaddPairwiseI16x8U:
+			c.emit(
+				&OperationV128ExtAddPairwise{OriginShape: ShapeI16x8, Signed: false},
+			)
+		case wasm.OpcodeVecF64x2PromoteLowF32x4Zero:
+			c.emit(
+				&OperationV128FloatPromote{},
+			)
+		case wasm.OpcodeVecF32x4DemoteF64x2Zero:
+			c.emit(
+				&OperationV128FloatDemote{},
+			)
+		case wasm.OpcodeVecF32x4ConvertI32x4S:
+			c.emit(
+				&OperationV128FConvertFromI{DestinationShape: ShapeF32x4, Signed: true},
+			)
+		case wasm.OpcodeVecF64x2ConvertLowI32x4S:
+			c.emit(
+				&OperationV128FConvertFromI{DestinationShape: ShapeF64x2, Signed: true},
+			)
+		case wasm.OpcodeVecI8x16NarrowI16x8S:
+			c.emit(
+				&OperationV128Narrow{OriginShape: ShapeI16x8, Signed: true},
+			)
+		case wasm.OpcodeVecI16x8NarrowI32x4S:
+			c.emit(
+				&OperationV128Narrow{OriginShape: ShapeI32x4, Signed: true},
+			)
+		case wasm.OpcodeVecI32x4TruncSatF32x4S:
+			c.emit(
+				&OperationV128ITruncSatFromF{OriginShape: ShapeF32x4, Signed: true},
+			)
+		case wasm.OpcodeVecI32x4TruncSatF64x2SZero:
+			c.emit(
+				&OperationV128ITruncSatFromF{OriginShape: ShapeF64x2, Signed: true},
+			)
+		case wasm.OpcodeVecF32x4ConvertI32x4U:
+			c.emit(
+				&OperationV128FConvertFromI{DestinationShape: ShapeF32x4, Signed: false},
+			)
+		case wasm.OpcodeVecF64x2ConvertLowI32x4U:
+			c.emit(
+				&OperationV128FConvertFromI{DestinationShape: ShapeF64x2, Signed: false},
+			)
+		case wasm.OpcodeVecI8x16NarrowI16x8U:
+			c.emit(
+				&OperationV128Narrow{OriginShape: ShapeI16x8, Signed: false},
+			)
+		case wasm.OpcodeVecI16x8NarrowI32x4U:
+			c.emit(
+				&OperationV128Narrow{OriginShape: ShapeI32x4, Signed: false},
+			)
+		case wasm.OpcodeVecI32x4TruncSatF32x4U:
+			c.emit(
+				&OperationV128ITruncSatFromF{OriginShape: ShapeF32x4, Signed: false},
+			)
+		case wasm.OpcodeVecI32x4TruncSatF64x2UZero:
+			c.emit(
+				&OperationV128ITruncSatFromF{OriginShape: ShapeF64x2, Signed: false},
+			)
+		case wasm.OpcodeVecI32x4DotI16x8S:
+			c.emit(
+				&OperationV128Dot{},
+			)
+		default:
+			return fmt.Errorf("unsupported vector instruction in wazeroir: %s", wasm.VectorInstructionName(wasm.OpcodeVec(vecOp)))
+		}
+	case wasm.OpcodeAtomicPrefix:
+		c.pc++
+		atomicOp := c.body[c.pc]
+		name := wasm.AtomicInstructionName(atomicOp)
+		if atomicOp == wasm.OpcodeAtomicFence {
+			c.pc++ // Skip the reserved one byte.
+			c.emit(
+				&OperationAtomicFence{},
+			)
+			break
+		}
+
+		imm, err := c.readMemoryImmediate(name)
+		if err != nil {
+			return err
+		}
+		switch {
+		case atomicOp == wasm.OpcodeAtomicMemoryNotify:
+			c.emit(
+				&OperationAtomicMemoryNotify{Arg: imm},
+			)
+		case atomicOp == wasm.OpcodeAtomicMemoryWait32:
+			c.emit(
+				&OperationAtomicMemoryWait{Type: UnsignedInt32, Arg: imm},
+			)
+		case atomicOp == wasm.OpcodeAtomicMemoryWait64:
+			c.emit(
+				&OperationAtomicMemoryWait{Type: UnsignedInt64, Arg: imm},
+			)
+		case atomicOp >= wasm.OpcodeAtomicI32Load && atomicOp <= wasm.OpcodeAtomicI64Load32U:
+			sizeInBytes, isI64 := atomicAccessSize(atomicOp - wasm.OpcodeAtomicI32Load)
+			c.emit(
+				&OperationAtomicLoad{Type: atomicUnsignedInt(isI64), SizeInBytes: sizeInBytes, Arg: imm},
+			)
+		case atomicOp >= wasm.OpcodeAtomicI32Store && atomicOp <= wasm.OpcodeAtomicI64Store32:
+			sizeInBytes, isI64 := atomicAccessSize(atomicOp - wasm.OpcodeAtomicI32Store)
+			c.emit(
+				&OperationAtomicStore{Type: atomicUnsignedInt(isI64), SizeInBytes: sizeInBytes, Arg: imm},
+			)
+		case atomicOp >= wasm.OpcodeAtomicI32RmwCmpxchg && atomicOp <= wasm.OpcodeAtomicI64Rmw32CmpxchgU:
+			sizeInBytes, isI64 := atomicAccessSize(atomicOp - wasm.OpcodeAtomicI32RmwCmpxchg)
+			c.emit(
+				&OperationAtomicRMWCmpxchg{Type: atomicUnsignedInt(isI64), SizeInBytes: sizeInBytes, Arg: imm},
+			)
+		case atomicOp >= wasm.OpcodeAtomicI32RmwAdd && atomicOp < wasm.OpcodeAtomicI32RmwCmpxchg:
+			arithmetic, sizeInBytes, isI64 := atomicRMWOperands(atomicOp)
+			c.emit(
+				&OperationAtomicRMW{Type: atomicUnsignedInt(isI64), SizeInBytes: sizeInBytes, Op: arithmetic, Arg: imm},
+			)
+		default:
+			return fmt.Errorf("unsupported atomic instruction in wazeroir: 0x%x", atomicOp)
+		}
+	default:
+		return fmt.Errorf("unsupported instruction in wazeroir: 0x%x", op)
+	}
+
+	// Move the program counter to point to the next instruction.
+	c.pc++
+	return nil
+}
+
+// instructionName returns the name of the instruction at c.pc, whose first byte is op.
+func (c *compiler) instructionName(op wasm.Opcode) string {
+	switch op {
+	case wasm.OpcodeMiscPrefix:
+		return wasm.MiscInstructionName(c.body[c.pc+1])
+	case wasm.OpcodeVecPrefix:
+		vecOp, _, _ := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc+1:]))
+		return wasm.VectorInstructionName(wasm.OpcodeVec(vecOp))
+	case wasm.OpcodeAtomicPrefix:
+		return wasm.AtomicInstructionName(c.body[c.pc+1])
+	}
+	return wasm.InstructionName(op)
+}
+
+func (c *compiler) nextID() (id uint32) {
+	id = c.currentID + 1
+	c.currentID++
+	return
+}
+
+func (c *compiler) applyToStack(opcode wasm.Opcode) (*uint32, error) {
+	var index uint32
+	var ptr *uint32
+	switch opcode {
+	case
+		// These are the opcodes that is coupled with "index"　immediate
+		// and it DOES affect the signature of opcode.
+		wasm.OpcodeCall,
+		wasm.OpcodeCallIndirect,
+		wasm.OpcodeReturnCall,
+		wasm.OpcodeReturnCallIndirect,
+		wasm.OpcodeThrow,
+		wasm.OpcodeLocalGet,
+		wasm.OpcodeLocalSet,
+		wasm.OpcodeLocalTee,
+		wasm.OpcodeGlobalGet,
+		wasm.OpcodeGlobalSet:
+		// Assumes that we are at the opcode now so skip it before read immediates.
+		v, num, err := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("reading immediates: %w", err)
+		}
+		c.pc += num
+		index = v
+		ptr = &index
+	default:
+		// Note that other opcodes are free of index
+		// as it doesn't affect the signature of opt code.
+		// In other words, the "index" argument of wasmOpcodeSignature
+		// is ignored there.
+	}
+
+	if c.unreachableState.on {
+		return ptr, nil
+	}
+
+	// Retrieve the signature of the opcode.
+	s, err := c.wasmOpcodeSignature(opcode, index)
+	if err != nil {
+		return nil, err
+	}
+	if c.hasMemory64 {
+		if s, err = c.memory64Signature(opcode, s); err != nil {
+			return nil, err
+		}
+	}
+
+	// Manipulate the stack according to the signature.
+	// Note that the following algorithm assumes that
+	// the unknown type is unique in the signature,
+	// and is determined by the actual type on the stack.
+	// The determined type is stored in this typeParam.
+	var typeParam *UnsignedType
+	for i := range s.in {
+		want := s.in[len(s.in)-1-i]
+		actual := c.stackPop()
+		if want == UnsignedTypeUnknown && typeParam != nil {
+			want = *typeParam
+		} else if want == UnsignedTypeUnknown {
+			want = actual
+			typeParam = &actual
+		}
+		if want != actual {
+			return nil, fmt.Errorf("input signature mismatch: want %s but have %s", want, actual)
+		}
+	}
+
+	for _, target := range s.out {
+		if target == UnsignedTypeUnknown && typeParam == nil {
+			return nil, fmt.Errorf("cannot determine type of unknown result")
+		} else if target == UnsignedTypeUnknown {
+			c.stackPush(*typeParam)
+		} else {
+			c.stackPush(target)
+		}
+	}
+
+	return ptr, nil
+}
+
+func (c *compiler) stackPop() (ret UnsignedType) {
+	// No need to check stack bound
+	// as we can assume that all the operations
+	// are valid thanks to validateFunction
+	// at module validation phase.
+	ret = c.stack[len(c.stack)-1]
+	c.stack = c.stack[:len(c.stack)-1]
+	c.stackHeightInUint64 -= uint64Len(ret)
+	return
+}
+
+func (c *compiler) stackPush(ts ...UnsignedType) {
+	c.stack = append(c.stack, ts...)
+	for _, t := range ts {
+		c.stackHeightInUint64 += uint64Len(t)
+	}
+	if c.stackHeightInUint64 > c.result.MaxStackHeight {
+		c.result.MaxStackHeight = c.stackHeightInUint64
+	}
+}
+
+// stackTruncate removes the values above the given length of the stack.
+func (c *compiler) stackTruncate(stackLen int) {
+	c.stack = c.stack[:stackLen]
+	c.stackHeightInUint64 = c.stackLenInUint64(stackLen)
+}
+
+// uint64Len returns the number of uint64 taken by a value of the given type on the stack.
+func uint64Len(t UnsignedType) int {
+	if t == UnsignedTypeV128 {
+		return 2
+	}
+	return 1
+}
+
+// emit adds the operations into the result.
+func (c *compiler) emit(ops ...Operation) {
+	if !c.unreachableState.on {
+		for _, op := range ops {
+			switch o := op.(type) {
+			case *OperationDrop:
+				// If the drop range is nil,
+				// we could remove such operations.
+				// That happens when drop operation is unnecessary.
+				// i.e. when there's no need to adjust stack before jmp.
+				if o.Depth == nil {
+					continue
+				}
 			}
 			c.result.Operations = append(c.result.Operations, op)
 			if buildoptions.IsDebugMode {
 				fmt.Printf("emitting ")
 				formatOperation(os.Stdout, op)
 			}
+
+			switch op.(type) {
+			case *OperationLabel, *OperationCatch:
+				// Instructions after a label, or a handler, are reached from elsewhere, so they consume fuel separately.
+				if c.fuelCosts != nil {
+					c.fuel = &OperationConsumeFuel{}
+					c.result.Operations = append(c.result.Operations, c.fuel)
+				}
+			}
 		}
 	}
 }
@@ -1882,7 +3340,7 @@ func (c *compiler) emitDefaultValue(t wasm.ValueType) {
 		c.emit(&OperationConstF64{Value: 0})
 	case wasm.ValueTypeV128:
 		c.stackPush(UnsignedTypeV128)
-		c.emit(&OperationConstV128{Hi: 0, Lo: 0})
+		c.emit(&OperationV128Const{Hi: 0, Lo: 0})
 	}
 }
 
@@ -1934,6 +3392,28 @@ func (c *compiler) getFrameDropRange(frame *controlFrame, isEnd bool) *Inclusive
 	return nil
 }
 
+// emitDelegateIfTry ends the protected region of the given frame popped by wasm.OpcodeEnd or wasm.OpcodeDelegate if
+// it is a try block without handlers. depth is the label of wasm.OpcodeDelegate, and zero for wasm.OpcodeEnd.
+func (c *compiler) emitDelegateIfTry(frame *controlFrame, depth uint32) {
+	if frame.kind == controlFrameKindTry {
+		c.emit(
+			&OperationDelegate{FrameID: frame.frameID, Target: c.controlFrames.innermostTry(int(depth))},
+		)
+	}
+}
+
+// getTailCallDropRange returns the range of the current function frame below the operands of a tail call, where
+// operandNum is the number of the operands in uint64. The operands are already popped from c.stack at this point.
+func (c *compiler) getTailCallDropRange(operandNum int) *InclusiveRange {
+	if c.unreachableState.on {
+		return nil
+	}
+	if below := c.stackLenInUint64(len(c.stack)); below > 0 {
+		return &InclusiveRange{Start: operandNum, End: operandNum + below - 1}
+	}
+	return nil
+}
+
 func (c *compiler) stackLenInUint64(ceil int) (ret int) {
 	for i := 0; i < ceil; i++ {
 		if c.stack[i] == UnsignedTypeV128 {
@@ -1945,17 +3425,59 @@ func (c *compiler) stackLenInUint64(ceil int) (ret int) {
 	return
 }
 
+// atomicAccessSize returns the access size of the i-th instruction in a group of atomic loads, stores or
+// read-modify-write instructions, which are all ordered as i32, i64, i32 8-bit, i32 16-bit, i64 8-bit, i64 16-bit
+// and i64 32-bit.
+func atomicAccessSize(i wasm.OpcodeAtomic) (sizeInBytes uint32, isI64 bool) {
+	switch i {
+	case 0:
+		return 4, false
+	case 1:
+		return 8, true
+	case 2:
+		return 1, false
+	case 3:
+		return 2, false
+	case 4:
+		return 1, true
+	case 5:
+		return 2, true
+	default:
+		return 4, true
+	}
+}
+
+// atomicRMWOperands decodes the arithmetic and the access size of an atomic read-modify-write instruction.
+func atomicRMWOperands(op wasm.OpcodeAtomic) (arithmetic AtomicArithmeticOp, sizeInBytes uint32, isI64 bool) {
+	i := op - wasm.OpcodeAtomicI32RmwAdd
+	arithmetic = AtomicArithmeticOp(i / 7)
+	sizeInBytes, isI64 = atomicAccessSize(i % 7)
+	return
+}
+
+func atomicUnsignedInt(isI64 bool) UnsignedInt {
+	if isI64 {
+		return UnsignedInt64
+	}
+	return UnsignedInt32
+}
+
 func (c *compiler) readMemoryImmediate(tag string) (*MemoryImmediate, error) {
 	r := bytes.NewReader(c.body[c.pc+1:])
-	alignment, num, err := leb128.DecodeUint32(r)
+	alignment, memoryIndex, num, err := wasm.DecodeMemArgAlignment(r, c.enabledFeatures)
 	if err != nil {
 		return nil, fmt.Errorf("reading alignment for %s: %w", tag, err)
 	}
 	c.pc += num
-	offset, num, err := leb128.DecodeUint32(r)
+	offset, num, err := wasm.DecodeMemArgOffset(r, c.isMemory64(memoryIndex))
 	if err != nil {
 		return nil, fmt.Errorf("reading offset for %s: %w", tag, err)
 	}
 	c.pc += num
-	return &MemoryImmediate{Offset: offset, Alignment: alignment}, nil
+	if offset > math.MaxUint32 {
+		// The offset into a memory indexed with i64 can be 64-bit, but memories are at most 4GiB in wazero, so the
+		// saturated offset is out of bounds in the engines as well.
+		offset = math.MaxUint32
+	}
+	return &MemoryImmediate{Offset: uint32(offset), Alignment: alignment, MemoryIndex: memoryIndex}, nil
 }
@@ -29,6 +29,10 @@ var (
 		ParamNumInUint64:  2,
 		ResultNumInUint64: 1,
 	}
+	i64_i64 = &wasm.FunctionType{Params: []wasm.ValueType{wasm.ValueTypeI64}, Results: []wasm.ValueType{wasm.ValueTypeI64},
+		ParamNumInUint64:  1,
+		ResultNumInUint64: 1,
+	}
 	v_v      = &wasm.FunctionType{}
 	v_f64f64 = &wasm.FunctionType{Results: []wasm.ValueType{f64, f64}, ResultNumInUint64: 2}
 )
@@ -77,7 +81,8 @@ func TestCompile(t *testing.T) {
 					ParamNumInUint64:  1,
 					ResultNumInUint64: 1,
 				},
-				TableTypes: []wasm.RefType{},
+				TableTypes:     []wasm.RefType{},
+				MaxStackHeight: 2,
 			},
 		},
 		{
@@ -104,7 +109,8 @@ func TestCompile(t *testing.T) {
 					ParamNumInUint64:  1,
 					ResultNumInUint64: 1,
 				},
-				TableTypes: []wasm.RefType{},
+				TableTypes:     []wasm.RefType{},
+				MaxStackHeight: 2,
 			},
 		},
 	}
@@ -206,7 +212,7 @@ func TestCompile_BulkMemoryOperations(t *testing.T) {
 	module := &wasm.Module{
 		TypeSection:     []*wasm.FunctionType{v_v},
 		FunctionSection: []wasm.Index{0},
-		MemorySection:   &wasm.Memory{Min: 1},
+		MemorySection:   []*wasm.Memory{{Min: 1}},
 		DataSection: []*wasm.DataSegment{
 			{
 				OffsetExpression: &wasm.ConstantExpression{
@@ -235,7 +241,7 @@ func TestCompile_BulkMemoryOperations(t *testing.T) {
 			&OperationConstI32{16},                // [16]
 			&OperationConstI32{0},                 // [16, 0]
 			&OperationConstI32{7},                 // [16, 0, 7]
-			&OperationMemoryInit{1},               // []
+			&OperationMemoryInit{DataIndex: 1},    // []
 			&OperationDataDrop{1},                 // []
 			&OperationBr{Target: &BranchTarget{}}, // return!
 		},
@@ -246,6 +252,8 @@ func TestCompile_BulkMemoryOperations(t *testing.T) {
 		Functions:                  []wasm.Index{0},
 		Types:                      []*wasm.FunctionType{v_v},
 		TableTypes:                 []wasm.RefType{},
+		Memories:                   module.MemorySection,
+		MaxStackHeight:             3,
 	}
 
 	res, err := CompileFunctions(ctx, wasm.FeatureBulkMemoryOperations, module)
@@ -285,11 +293,12 @@ func TestCompile_MultiValue(t *testing.T) {
 					&OperationDrop{Depth: &InclusiveRange{Start: 2, End: 3}}, // [$y, $x]
 					&OperationBr{Target: &BranchTarget{}},                    // return!
 				},
-				LabelCallers: map[string]uint32{},
-				Signature:    i32i32_i32i32,
-				Functions:    []wasm.Index{0},
-				Types:        []*wasm.FunctionType{i32i32_i32i32},
-				TableTypes:   []wasm.RefType{},
+				LabelCallers:   map[string]uint32{},
+				Signature:      i32i32_i32i32,
+				Functions:      []wasm.Index{0},
+				Types:          []*wasm.FunctionType{i32i32_i32i32},
+				TableTypes:     []wasm.RefType{},
+				MaxStackHeight: 4,
 			},
 		},
 		{
@@ -330,11 +339,12 @@ func TestCompile_MultiValue(t *testing.T) {
 				},
 				// Note: f64.add comes after br 0 so is unreachable. This is why neither the add, nor its other operand
 				// are in the above compilation result.
-				LabelCallers: map[string]uint32{".L2_cont": 1}, // arbitrary label
-				Signature:    v_f64f64,
-				Functions:    []wasm.Index{0},
-				Types:        []*wasm.FunctionType{v_f64f64},
-				TableTypes:   []wasm.RefType{},
+				LabelCallers:   map[string]uint32{".L2_cont": 1}, // arbitrary label
+				Signature:      v_f64f64,
+				Functions:      []wasm.Index{0},
+				Types:          []*wasm.FunctionType{v_f64f64},
+				TableTypes:     []wasm.RefType{},
+				MaxStackHeight: 2,
 			},
 		},
 		{
@@ -349,11 +359,12 @@ func TestCompile_MultiValue(t *testing.T) {
 					&OperationConstI64{Value: 356},        // [306, 356]
 					&OperationBr{Target: &BranchTarget{}}, // return!
 				},
-				LabelCallers: map[string]uint32{},
-				Signature:    _i32i64,
-				Functions:    []wasm.Index{0},
-				Types:        []*wasm.FunctionType{_i32i64},
-				TableTypes:   []wasm.RefType{},
+				LabelCallers:   map[string]uint32{},
+				Signature:      _i32i64,
+				Functions:      []wasm.Index{0},
+				Types:          []*wasm.FunctionType{_i32i64},
+				TableTypes:     []wasm.RefType{},
+				MaxStackHeight: 2,
 			},
 		},
 		{
@@ -403,10 +414,11 @@ func TestCompile_MultiValue(t *testing.T) {
 					".L2_cont": 2,
 					".L2_else": 1,
 				},
-				Signature:  i32_i32,
-				Functions:  []wasm.Index{0},
-				Types:      []*wasm.FunctionType{i32_i32},
-				TableTypes: []wasm.RefType{},
+				Signature:      i32_i32,
+				Functions:      []wasm.Index{0},
+				Types:          []*wasm.FunctionType{i32_i32},
+				TableTypes:     []wasm.RefType{},
+				MaxStackHeight: 3,
 			},
 		},
 		{
@@ -460,10 +472,11 @@ func TestCompile_MultiValue(t *testing.T) {
 					".L2_cont": 2,
 					".L2_else": 1,
 				},
-				Signature:  i32_i32,
-				Functions:  []wasm.Index{0},
-				Types:      []*wasm.FunctionType{i32_i32, i32i32_i32},
-				TableTypes: []wasm.RefType{},
+				Signature:      i32_i32,
+				Functions:      []wasm.Index{0},
+				Types:          []*wasm.FunctionType{i32_i32, i32i32_i32},
+				TableTypes:     []wasm.RefType{},
+				MaxStackHeight: 4,
 			},
 		},
 		{
@@ -517,10 +530,11 @@ func TestCompile_MultiValue(t *testing.T) {
 					".L2_cont": 2,
 					".L2_else": 1,
 				},
-				Signature:  i32_i32,
-				Functions:  []wasm.Index{0},
-				Types:      []*wasm.FunctionType{i32_i32, i32i32_i32},
-				TableTypes: []wasm.RefType{},
+				Signature:      i32_i32,
+				Functions:      []wasm.Index{0},
+				Types:          []*wasm.FunctionType{i32_i32, i32i32_i32},
+				TableTypes:     []wasm.RefType{},
+				MaxStackHeight: 4,
 			},
 		},
 	}
@@ -557,11 +571,12 @@ func TestCompile_NonTrappingFloatToIntConversion(t *testing.T) {
 			&OperationDrop{Depth: &InclusiveRange{Start: 1, End: 1}}, // [i32.trunc_sat_f32_s($0)]
 			&OperationBr{Target: &BranchTarget{}},                    // return!
 		},
-		LabelCallers: map[string]uint32{},
-		Signature:    f32_i32,
-		Functions:    []wasm.Index{0},
-		Types:        []*wasm.FunctionType{f32_i32},
-		TableTypes:   []wasm.RefType{},
+		LabelCallers:   map[string]uint32{},
+		Signature:      f32_i32,
+		Functions:      []wasm.Index{0},
+		Types:          []*wasm.FunctionType{f32_i32},
+		TableTypes:     []wasm.RefType{},
+		MaxStackHeight: 2,
 	}
 
 	res, err := CompileFunctions(ctx, wasm.FeatureNonTrappingFloatToIntConversion, module)
@@ -582,11 +597,12 @@ func TestCompile_SignExtensionOps(t *testing.T) {
 			&OperationDrop{Depth: &InclusiveRange{Start: 1, End: 1}}, // [i32.extend8_s($0)]
 			&OperationBr{Target: &BranchTarget{}},                    // return!
 		},
-		LabelCallers: map[string]uint32{},
-		Signature:    i32_i32,
-		Functions:    []wasm.Index{0},
-		Types:        []*wasm.FunctionType{i32_i32},
-		TableTypes:   []wasm.RefType{},
+		LabelCallers:   map[string]uint32{},
+		Signature:      i32_i32,
+		Functions:      []wasm.Index{0},
+		Types:          []*wasm.FunctionType{i32_i32},
+		TableTypes:     []wasm.RefType{},
+		MaxStackHeight: 2,
 	}
 
 	res, err := CompileFunctions(ctx, wasm.FeatureSignExtensionOps, module)
@@ -640,7 +656,8 @@ func TestCompile_CallIndirectNonZeroTableIndex(t *testing.T) {
 		TableTypes: []wasm.RefType{
 			wasm.RefTypeExternref, wasm.RefTypeFuncref, wasm.RefTypeFuncref, wasm.RefTypeFuncref, wasm.RefTypeFuncref, wasm.RefTypeFuncref,
 		},
-		Types: []*wasm.FunctionType{v_v, v_v, v_v},
+		Types:          []*wasm.FunctionType{v_v, v_v, v_v},
+		MaxStackHeight: 1,
 	}
 
 	res, err := CompileFunctions(ctx, wasm.FeatureBulkMemoryOperations, module)
@@ -648,6 +665,28 @@ func TestCompile_CallIndirectNonZeroTableIndex(t *testing.T) {
 	require.Equal(t, expected, res[0])
 }
 
+func TestCompile_MaxStackHeight(t *testing.T) {
+	v128_i32 := &wasm.FunctionType{Params: []wasm.ValueType{wasm.ValueTypeV128}, Results: []wasm.ValueType{i32}}
+	module := &wasm.Module{
+		TypeSection:     []*wasm.FunctionType{v128_i32},
+		FunctionSection: []wasm.Index{0},
+		CodeSection: []*wasm.Code{{Body: []byte{ // begin with params: [$0] = 2
+			wasm.OpcodeBlock, 0x7f, // (block (result i32)
+			wasm.OpcodeI32Const, 1, // [$0, 1] = 3
+			wasm.OpcodeI32Const, 2, // [$0, 1, 2] = 4
+			wasm.OpcodeI32Add,      // [$0, 3] = 3
+			wasm.OpcodeEnd,         // ) [$0, 3] = 3
+			wasm.OpcodeLocalGet, 0, // [$0, 3, $0] = 5
+			wasm.OpcodeDrop, // [$0, 3] = 3
+			wasm.OpcodeEnd,
+		}}},
+	}
+
+	res, err := CompileFunctions(ctx, wasm.Features20220419, module)
+	require.NoError(t, err)
+	require.Equal(t, 5, res[0].MaxStackHeight)
+}
+
 func TestCompile_Refs(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -931,7 +970,7 @@ func TestCompile_Locals(t *testing.T) {
 				}},
 			},
 			expected: []Operation{
-				&OperationConstV128{Lo: 0, Hi: 0},
+				&OperationV128Const{Lo: 0, Hi: 0},
 				&OperationPick{Depth: 1, IsTargetVector: true}, // [p[0].low, p[0].high] -> [p[0].low, p[0].high, p[0].low, p[0].high]
 				&OperationDrop{Depth: &InclusiveRange{Start: 0, End: 3}},
 				&OperationBr{Target: &BranchTarget{}}, // return!
@@ -952,7 +991,7 @@ func TestCompile_Locals(t *testing.T) {
 			},
 			expected: []Operation{
 				// [p[0].lo, p[1].hi] -> [p[0].lo, p[1].hi, 0x01, 0x02]
-				&OperationConstV128{Lo: 0x01, Hi: 0x02},
+				&OperationV128Const{Lo: 0x01, Hi: 0x02},
 				// [p[0].lo, p[1].hi, 0x01, 0x02] -> [0x01, 0x02, p[0].lo, p[1].hi]
 				&OperationSwap{Depth: 3, IsTargetVector: true},
 				// [0x01, 0x02, p[0].lo, p[1].hi] -> [0x02, 0x01]
@@ -997,9 +1036,9 @@ func TestCompile_Locals(t *testing.T) {
 				}},
 			},
 			expected: []Operation{
-				&OperationConstV128{Lo: 0, Hi: 0},
+				&OperationV128Const{Lo: 0, Hi: 0},
 				// [p[0].lo, p[1].hi] -> [p[0].lo, p[1].hi, 0x01, 0x02]
-				&OperationConstV128{Lo: 0x01, Hi: 0x02},
+				&OperationV128Const{Lo: 0x01, Hi: 0x02},
 				// [p[0].lo, p[1].hi, 0x01, 0x02] -> [0x01, 0x02, p[0].lo, p[1].hi]
 				&OperationSwap{Depth: 3, IsTargetVector: true},
 				// [p[0].lo, 0x02, 0x01, p[1].hi] -> [0x02, 0x01]
@@ -1023,7 +1062,7 @@ func TestCompile_Locals(t *testing.T) {
 			},
 			expected: []Operation{
 				// [p[0].lo, p[1].hi] -> [p[0].lo, p[1].hi, 0x01, 0x02]
-				&OperationConstV128{Lo: 0x01, Hi: 0x02},
+				&OperationV128Const{Lo: 0x01, Hi: 0x02},
 				// [p[0].lo, p[1].hi, 0x01, 0x02] -> [p[0].lo, p[1].hi, 0x01, 0x02, 0x01, 0x02]
 				&OperationPick{Depth: 1, IsTargetVector: true},
 				// [p[0].lo, p[1].hi, 0x01, 0x02, 0x01, 0x02] -> [0x01, 0x02, 0x01, 0x02, p[0].lo, p[1].hi]
@@ -1071,9 +1110,9 @@ func TestCompile_Locals(t *testing.T) {
 				}},
 			},
 			expected: []Operation{
-				&OperationConstV128{Lo: 0, Hi: 0},
+				&OperationV128Const{Lo: 0, Hi: 0},
 				// [p[0].lo, p[1].hi] -> [p[0].lo, p[1].hi, 0x01, 0x02]
-				&OperationConstV128{Lo: 0x01, Hi: 0x02},
+				&OperationV128Const{Lo: 0x01, Hi: 0x02},
 				// [p[0].lo, p[1].hi, 0x01, 0x02] -> [p[0].lo, p[1].hi, 0x01, 0x02, 0x01, 0x02]
 				&OperationPick{Depth: 1, IsTargetVector: true},
 				// [p[0].lo, p[1].hi, 0x01, 0x02, 0x01, 0x2] -> [0x01, 0x02, 0x01, 0x02, p[0].lo, p[1].hi]
@@ -1096,3 +1135,522 @@ func TestCompile_Locals(t *testing.T) {
 		})
 	}
 }
+
+func TestCompile_VecSelectDrop(t *testing.T) {
+	v128Const := func(lo byte) []byte {
+		return []byte{wasm.OpcodeVecPrefix, wasm.OpcodeVecV128Const, lo, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
+	}
+	var body []byte
+	body = append(body, v128Const(1)...)
+	body = append(body, v128Const(2)...)
+	body = append(body, wasm.OpcodeLocalGet, 0, wasm.OpcodeSelect)
+	body = append(body, v128Const(3)...)
+	body = append(body, v128Const(4)...)
+	body = append(body, wasm.OpcodeLocalGet, 0, wasm.OpcodeTypedSelect, 1, wasm.ValueTypeV128)
+	body = append(body, wasm.OpcodeDrop, wasm.OpcodeEnd)
+
+	mod := &wasm.Module{
+		TypeSection: []*wasm.FunctionType{{
+			Params: []wasm.ValueType{i32}, Results: []wasm.ValueType{wasm.ValueTypeV128},
+			ParamNumInUint64: 1, ResultNumInUint64: 2,
+		}},
+		FunctionSection: []wasm.Index{0},
+		CodeSection:     []*wasm.Code{{Body: body}},
+	}
+	expected := []Operation{
+		&OperationV128Const{Lo: 1},                               // [$0, 1.lo, 1.hi]
+		&OperationV128Const{Lo: 2},                               // [$0, 1.lo, 1.hi, 2.lo, 2.hi]
+		&OperationPick{Depth: 4},                                 // [$0, 1.lo, 1.hi, 2.lo, 2.hi, $0]
+		&OperationSelect{IsTargetVector: true},                   // [$0, x.lo, x.hi]
+		&OperationV128Const{Lo: 3},                               // [$0, x.lo, x.hi, 3.lo, 3.hi]
+		&OperationV128Const{Lo: 4},                               // [$0, x.lo, x.hi, 3.lo, 3.hi, 4.lo, 4.hi]
+		&OperationPick{Depth: 6},                                 // [$0, x.lo, x.hi, 3.lo, 3.hi, 4.lo, 4.hi, $0]
+		&OperationSelect{IsTargetVector: true},                   // [$0, x.lo, x.hi, y.lo, y.hi]
+		&OperationDrop{Depth: &InclusiveRange{Start: 0, End: 1}}, // [$0, x.lo, x.hi]
+		&OperationDrop{Depth: &InclusiveRange{Start: 2, End: 2}}, // [x.lo, x.hi]
+		&OperationBr{Target: &BranchTarget{}},                    // return!
+	}
+
+	res, err := CompileFunctions(ctx, wasm.Features20220419, mod)
+	require.NoError(t, err)
+	msg := fmt.Sprintf("\nhave:\n\t%s\nwant:\n\t%s", Format(res[0].Operations), Format(expected))
+	require.Equal(t, expected, res[0].Operations, msg)
+}
+
+func TestCompile_Atomic(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     []byte
+		expected []Operation
+	}{
+		{
+			name: "i64.atomic.load32_u",
+			body: []byte{
+				wasm.OpcodeI32Const, 0,
+				wasm.OpcodeAtomicPrefix, wasm.OpcodeAtomicI64Load32U, 0x2, 0x8, // alignment=2 (natural alignment) staticOffset=8
+				wasm.OpcodeDrop,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{
+				&OperationConstI32{Value: 0},
+				&OperationAtomicLoad{Type: UnsignedInt64, SizeInBytes: 4, Arg: &MemoryImmediate{Alignment: 2, Offset: 8}},
+				&OperationDrop{Depth: &InclusiveRange{Start: 0, End: 0}},
+				&OperationBr{Target: &BranchTarget{}}, // return!
+			},
+		},
+		{
+			name: "i32.atomic.store16",
+			body: []byte{
+				wasm.OpcodeI32Const, 0,
+				wasm.OpcodeI32Const, 1,
+				wasm.OpcodeAtomicPrefix, wasm.OpcodeAtomicI32Store16, 0x1, 0x0,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{
+				&OperationConstI32{Value: 0},
+				&OperationConstI32{Value: 1},
+				&OperationAtomicStore{Type: UnsignedInt32, SizeInBytes: 2, Arg: &MemoryImmediate{Alignment: 1}},
+				&OperationBr{Target: &BranchTarget{}}, // return!
+			},
+		},
+		{
+			name: "i64.atomic.rmw8.xor_u",
+			body: []byte{
+				wasm.OpcodeI32Const, 0,
+				wasm.OpcodeI64Const, 1,
+				wasm.OpcodeAtomicPrefix, wasm.OpcodeAtomicI64Rmw8XorU, 0x0, 0x0,
+				wasm.OpcodeDrop,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{
+				&OperationConstI32{Value: 0},
+				&OperationConstI64{Value: 1},
+				&OperationAtomicRMW{Type: UnsignedInt64, SizeInBytes: 1, Op: AtomicArithmeticOpXor, Arg: &MemoryImmediate{}},
+				&OperationDrop{Depth: &InclusiveRange{Start: 0, End: 0}},
+				&OperationBr{Target: &BranchTarget{}}, // return!
+			},
+		},
+		{
+			name: "i32.atomic.rmw.cmpxchg",
+			body: []byte{
+				wasm.OpcodeI32Const, 0,
+				wasm.OpcodeI32Const, 1,
+				wasm.OpcodeI32Const, 2,
+				wasm.OpcodeAtomicPrefix, wasm.OpcodeAtomicI32RmwCmpxchg, 0x2, 0x0,
+				wasm.OpcodeDrop,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{
+				&OperationConstI32{Value: 0},
+				&OperationConstI32{Value: 1},
+				&OperationConstI32{Value: 2},
+				&OperationAtomicRMWCmpxchg{Type: UnsignedInt32, SizeInBytes: 4, Arg: &MemoryImmediate{Alignment: 2}},
+				&OperationDrop{Depth: &InclusiveRange{Start: 0, End: 0}},
+				&OperationBr{Target: &BranchTarget{}}, // return!
+			},
+		},
+		{
+			name: "memory.atomic.wait64",
+			body: []byte{
+				wasm.OpcodeI32Const, 0,
+				wasm.OpcodeI64Const, 0,
+				wasm.OpcodeI64Const, 0,
+				wasm.OpcodeAtomicPrefix, wasm.OpcodeAtomicMemoryWait64, 0x3, 0x0,
+				wasm.OpcodeDrop,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{
+				&OperationConstI32{Value: 0},
+				&OperationConstI64{Value: 0},
+				&OperationConstI64{Value: 0},
+				&OperationAtomicMemoryWait{Type: UnsignedInt64, Arg: &MemoryImmediate{Alignment: 3}},
+				&OperationDrop{Depth: &InclusiveRange{Start: 0, End: 0}},
+				&OperationBr{Target: &BranchTarget{}}, // return!
+			},
+		},
+		{
+			name: "memory.atomic.notify",
+			body: []byte{
+				wasm.OpcodeI32Const, 0,
+				wasm.OpcodeI32Const, 1,
+				wasm.OpcodeAtomicPrefix, wasm.OpcodeAtomicMemoryNotify, 0x2, 0x0,
+				wasm.OpcodeDrop,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{
+				&OperationConstI32{Value: 0},
+				&OperationConstI32{Value: 1},
+				&OperationAtomicMemoryNotify{Arg: &MemoryImmediate{Alignment: 2}},
+				&OperationDrop{Depth: &InclusiveRange{Start: 0, End: 0}},
+				&OperationBr{Target: &BranchTarget{}}, // return!
+			},
+		},
+		{
+			name: "atomic.fence",
+			body: []byte{
+				wasm.OpcodeAtomicPrefix, wasm.OpcodeAtomicFence, 0x0,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{
+				&OperationAtomicFence{},
+				&OperationBr{Target: &BranchTarget{}}, // return!
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			max := uint32(1)
+			module := &wasm.Module{
+				TypeSection:     []*wasm.FunctionType{{}},
+				FunctionSection: []wasm.Index{0},
+				CodeSection:     []*wasm.Code{{Body: tc.body}},
+				MemorySection:   []*wasm.Memory{{Min: 1, Cap: 1, Max: max, IsShared: true}},
+			}
+			res, err := CompileFunctions(ctx, wasm.Features20220419|wasm.FeatureThreads, module)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res[0].Operations)
+		})
+	}
+}
+
+func TestCompile_TailCall(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     []byte
+		expected []Operation
+	}{
+		{
+			name: "return_call",
+			body: []byte{
+				wasm.OpcodeLocalGet, 1,
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeReturnCall, 0,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{ // begin with params: [$0, $1]
+				&OperationPick{Depth: 0},                                 // [$0, $1, $1]
+				&OperationPick{Depth: 2},                                 // [$0, $1, $1, $0]
+				&OperationDrop{Depth: &InclusiveRange{Start: 2, End: 3}}, // [$1, $0]
+				&OperationTailCall{FunctionIndex: 0},
+			},
+		},
+		{
+			name: "return_call_indirect",
+			body: []byte{
+				wasm.OpcodeLocalGet, 1,
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeI32Const, 0,
+				wasm.OpcodeReturnCallIndirect, 0, 0,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{ // begin with params: [$0, $1]
+				&OperationPick{Depth: 0},                                 // [$0, $1, $1]
+				&OperationPick{Depth: 2},                                 // [$0, $1, $1, $0]
+				&OperationConstI32{Value: 0},                             // [$0, $1, $1, $0, 0]
+				&OperationDrop{Depth: &InclusiveRange{Start: 3, End: 4}}, // [$1, $0, 0]
+				&OperationTailCallIndirect{TypeIndex: 0, TableIndex: 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			module := &wasm.Module{
+				TypeSection:     []*wasm.FunctionType{i32i32_i32},
+				FunctionSection: []wasm.Index{0},
+				CodeSection:     []*wasm.Code{{Body: tc.body}},
+				TableSection:    []*wasm.Table{{}},
+			}
+			res, err := CompileFunctions(ctx, wasm.Features20220419|wasm.FeatureTailCall, module)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res[0].Operations)
+		})
+	}
+}
+
+func TestCompile_ExceptionHandling(t *testing.T) {
+	// The function frame is 1, so the outermost try block is 2.
+	outerTry := uint32(2)
+	outerContinuation := &Label{FrameID: outerTry, Kind: LabelKindContinuation}
+	tests := []struct {
+		name     string
+		body     []byte
+		expected []Operation
+	}{
+		{
+			name: "throw and catch",
+			body: []byte{
+				wasm.OpcodeTry, wasm.ValueTypeI32,
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeThrow, 0,
+				wasm.OpcodeCatch, 0,
+				wasm.OpcodeEnd,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{ // begin with params: [$0]
+				&OperationTry{FrameID: outerTry, StackHeight: 1},
+				&OperationPick{Depth: 0},                                 // [$0, $0]
+				&OperationThrow{TagIndex: 0},                             // [$0]
+				&OperationCatch{FrameID: outerTry, TagIndex: 0},          // [$0, handle, value]
+				&OperationDrop{Depth: &InclusiveRange{Start: 1, End: 1}}, // [$0, value]
+				&OperationBr{Target: outerContinuation.asBranchTarget()},
+				&OperationLabel{Label: outerContinuation},
+				&OperationDrop{Depth: &InclusiveRange{Start: 1, End: 1}}, // [value]
+				&OperationBr{Target: &BranchTarget{}},                    // return!
+			},
+		},
+		{
+			name: "delegate and rethrow",
+			body: []byte{
+				wasm.OpcodeTry, 0x40,
+				wasm.OpcodeTry, 0x40,
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeThrow, 0,
+				wasm.OpcodeDelegate, 0,
+				wasm.OpcodeCatchAll,
+				wasm.OpcodeRethrow, 0,
+				wasm.OpcodeEnd,
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{ // begin with params: [$0]
+				&OperationTry{FrameID: outerTry, StackHeight: 1},
+				&OperationTry{FrameID: 3, Outer: &outerTry, StackHeight: 1},
+				&OperationPick{Depth: 0},     // [$0, $0]
+				&OperationThrow{TagIndex: 0}, // [$0]
+				&OperationDelegate{FrameID: 3, Target: &outerTry},
+				&OperationLabel{Label: &Label{FrameID: 3, Kind: LabelKindContinuation}},
+				&OperationBr{Target: outerContinuation.asBranchTarget()},
+				&OperationCatch{FrameID: outerTry, CatchAll: true},       // [$0, handle]
+				&OperationPick{Depth: 0},                                 // [$0, handle, handle]
+				&OperationRethrow{},                                      // [$0, handle]
+				&OperationLabel{Label: outerContinuation},                // [$0]
+				&OperationPick{Depth: 0},                                 // [$0, $0]
+				&OperationDrop{Depth: &InclusiveRange{Start: 1, End: 1}}, // [$0]
+				&OperationBr{Target: &BranchTarget{}},                    // return!
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			module := &wasm.Module{
+				TypeSection:     []*wasm.FunctionType{i32_i32, {Params: []wasm.ValueType{i32}, ParamNumInUint64: 1}},
+				FunctionSection: []wasm.Index{0},
+				TagSection:      []wasm.Index{1},
+				CodeSection:     []*wasm.Code{{Body: tc.body}},
+			}
+			res, err := CompileFunctions(ctx, wasm.Features20220419|wasm.FeatureExceptionHandling, module)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res[0].Operations)
+		})
+	}
+}
+
+func TestCompile_MultiMemory(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     []byte
+		expected []Operation
+	}{
+		{
+			name: "i32.load",
+			body: []byte{
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeI32Load, 0x42, 1, 8, // align=2, memory 1, offset=8
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{ // begin with params: [$0]
+				&OperationPick{Depth: 0}, // [$0, $0]
+				&OperationLoad{Type: UnsignedTypeI32, Arg: &MemoryImmediate{Alignment: 2, Offset: 8, MemoryIndex: 1}}, // [$0, x]
+				&OperationDrop{Depth: &InclusiveRange{Start: 1, End: 1}},                                              // [x]
+				&OperationBr{Target: &BranchTarget{}},                                                                 // return!
+			},
+		},
+		{
+			name: "memory.grow",
+			body: []byte{
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeMemoryGrow, 1,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{ // begin with params: [$0]
+				&OperationPick{Depth: 0},                                 // [$0, $0]
+				&OperationMemoryGrow{MemoryIndex: 1},                     // [$0, $old_size]
+				&OperationDrop{Depth: &InclusiveRange{Start: 1, End: 1}}, // [$old_size]
+				&OperationBr{Target: &BranchTarget{}},                    // return!
+			},
+		},
+		{
+			name: "memory.copy",
+			body: []byte{
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeMiscPrefix, wasm.OpcodeMiscMemoryCopy, 1, 0, // memory 1 from memory 0
+				wasm.OpcodeI32Const, 0,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{ // begin with params: [$0]
+				&OperationPick{Depth: 0},                                   // [$0, $0]
+				&OperationPick{Depth: 1},                                   // [$0, $0, $0]
+				&OperationPick{Depth: 2},                                   // [$0, $0, $0, $0]
+				&OperationMemoryCopy{SrcMemoryIndex: 0, DstMemoryIndex: 1}, // [$0]
+				&OperationConstI32{Value: 0},                               // [$0, 0]
+				&OperationDrop{Depth: &InclusiveRange{Start: 1, End: 1}},   // [0]
+				&OperationBr{Target: &BranchTarget{}},                      // return!
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			module := &wasm.Module{
+				TypeSection:     []*wasm.FunctionType{i32_i32},
+				FunctionSection: []wasm.Index{0},
+				MemorySection:   []*wasm.Memory{{}, {}},
+				CodeSection:     []*wasm.Code{{Body: tc.body}},
+			}
+			res, err := CompileFunctions(ctx, wasm.Features20220419|wasm.FeatureMultiMemory, module)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res[0].Operations)
+		})
+	}
+}
+
+func TestCompile_Memory64(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     []byte
+		expected []Operation
+	}{
+		{
+			name: "i32.load",
+			body: []byte{
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeI32Load, 0x2, 0x80, 0x80, 0x80, 0x80, 0x10, // align=2, offset=1<<32
+				wasm.OpcodeDrop,
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{ // begin with params: [$0]
+				&OperationPick{Depth: 0}, // [$0, $0]
+				&OperationLoad{Type: UnsignedTypeI32, Arg: &MemoryImmediate{Alignment: 2, Offset: math.MaxUint32}}, // [$0, x]
+				&OperationDrop{Depth: &InclusiveRange{Start: 0, End: 0}},                                           // [$0]
+				&OperationPick{Depth: 0},                                 // [$0, $0]
+				&OperationDrop{Depth: &InclusiveRange{Start: 1, End: 1}}, // [$0]
+				&OperationBr{Target: &BranchTarget{}},                    // return!
+			},
+		},
+		{
+			name: "memory.size",
+			body: []byte{
+				wasm.OpcodeMemorySize, 0,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{ // begin with params: [$0]
+				&OperationMemorySize{},                                   // [$0, $size]
+				&OperationExtend{Signed: false},                          // [$0, $size]
+				&OperationDrop{Depth: &InclusiveRange{Start: 1, End: 1}}, // [$size]
+				&OperationBr{Target: &BranchTarget{}},                    // return!
+			},
+		},
+		{
+			name: "memory.grow",
+			body: []byte{
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeMemoryGrow, 0,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{ // begin with params: [$0]
+				&OperationPick{Depth: 0},                                 // [$0, $0]
+				&OperationMemoryGrow{},                                   // [$0, $old_size]
+				&OperationExtend{Signed: true},                           // [$0, $old_size]
+				&OperationDrop{Depth: &InclusiveRange{Start: 1, End: 1}}, // [$old_size]
+				&OperationBr{Target: &BranchTarget{}},                    // return!
+			},
+		},
+		{
+			name: "memory.fill",
+			body: []byte{
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeI32Const, 1,
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeMiscPrefix, wasm.OpcodeMiscMemoryFill, 0,
+				wasm.OpcodeLocalGet, 0,
+				wasm.OpcodeEnd,
+			},
+			expected: []Operation{ // begin with params: [$0]
+				&OperationPick{Depth: 0},                                 // [$0, $0]
+				&OperationConstI32{Value: 1},                             // [$0, $0, 1]
+				&OperationPick{Depth: 2},                                 // [$0, $0, 1, $0]
+				&OperationMemoryFill{},                                   // [$0]
+				&OperationPick{Depth: 0},                                 // [$0, $0]
+				&OperationDrop{Depth: &InclusiveRange{Start: 1, End: 1}}, // [$0]
+				&OperationBr{Target: &BranchTarget{}},                    // return!
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			module := &wasm.Module{
+				TypeSection:     []*wasm.FunctionType{i64_i64},
+				FunctionSection: []wasm.Index{0},
+				MemorySection:   []*wasm.Memory{{IsMemory64: true}},
+				CodeSection:     []*wasm.Code{{Body: tc.body}},
+			}
+			res, err := CompileFunctions(ctx, wasm.Features20220419|wasm.FeatureMemory64, module)
+			require.NoError(t, err)
+			require.Equal(t, tc.expected, res[0].Operations)
+		})
+	}
+}
+
+func TestCompile_FuelCosts(t *testing.T) {
+	module := &wasm.Module{
+		TypeSection:     []*wasm.FunctionType{i32_i32},
+		FunctionSection: []wasm.Index{0},
+		CodeSection: []*wasm.Code{{Body: []byte{
+			wasm.OpcodeLoop, 0x40,
+			wasm.OpcodeLocalGet, 0,
+			wasm.OpcodeBrIf, 0,
+			wasm.OpcodeEnd,
+			wasm.OpcodeLocalGet, 0,
+			wasm.OpcodeEnd,
+		}}},
+		FuelCosts: func(instruction string) uint32 {
+			if instruction == "br_if" {
+				return 10
+			}
+			return 1
+		},
+	}
+	// Above set manually until the text compiler supports this:
+	// (func (param i32) (result i32) (loop (br_if 0 (local.get 0))) (local.get 0))
+
+	res, err := CompileFunctions(ctx, wasm.Features20220419, module)
+	require.NoError(t, err)
+	require.Equal(t, []Operation{ // begin with params: [$0]
+		&OperationConsumeFuel{Cost: 1}, // loop
+		&OperationBr{Target: &BranchTarget{Label: &Label{FrameID: 2, Kind: LabelKindHeader}}},
+		&OperationLabel{Label: &Label{FrameID: 2, Kind: LabelKindHeader}},
+		&OperationConsumeFuel{Cost: 11}, // local.get, br_if
+		&OperationPick{Depth: 0},        // [$0, $0]
+		&OperationBrIf{ // [$0]
+			Then: &BranchTargetDrop{Target: &BranchTarget{Label: &Label{FrameID: 2, Kind: LabelKindHeader}}},
+			Else: &BranchTargetDrop{Target: &BranchTarget{Label: &Label{FrameID: 3, Kind: LabelKindHeader}}},
+		},
+		&OperationLabel{Label: &Label{FrameID: 3, Kind: LabelKindHeader}},
+		&OperationConsumeFuel{Cost: 3},                           // end, local.get, end
+		&OperationPick{Depth: 0},                                 // [$0, $0]
+		&OperationDrop{Depth: &InclusiveRange{Start: 1, End: 1}}, // [$0]
+		&OperationBr{Target: &BranchTarget{}},                    // return!
+	}, res[0].Operations)
+}
@@ -43,10 +43,14 @@ func formatOperation(w io.StringWriter, b Operation) {
 		str = fmt.Sprintf("call %d", o.FunctionIndex)
 	case *OperationCallIndirect:
 		str = fmt.Sprintf("call_indirect: type=%d, table=%d", o.TypeIndex, o.TableIndex)
+	case *OperationTailCall:
+		str = fmt.Sprintf("tail_call %d", o.FunctionIndex)
+	case *OperationTailCallIndirect:
+		str = fmt.Sprintf("tail_call_indirect: type=%d, table=%d", o.TypeIndex, o.TableIndex)
 	case *OperationDrop:
 		str = fmt.Sprintf("drop %d..%d", o.Depth.Start, o.Depth.End)
 	case *OperationSelect:
-		str = "select"
+		str = fmt.Sprintf("select (is_vector=%v)", o.IsTargetVector)
 	case *OperationPick:
 		str = fmt.Sprintf("pick %d (is_vector=%v)", o.Depth, o.IsTargetVector)
 	case *OperationSwap:
@@ -181,10 +185,138 @@ func formatOperation(w io.StringWriter, b Operation) {
 			out = "u64"
 		}
 		str = fmt.Sprintf("%s.extend_from.%s", out, in)
-	case *OperationConstV128:
+	case *OperationV128Const:
 		str = fmt.Sprintf("v128.const [%#x, %#x]", o.Lo, o.Hi)
-	case *OperationAddV128:
+	case *OperationV128Add:
 		str = fmt.Sprintf("v128.add (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Sub:
+		str = fmt.Sprintf("v128.sub (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Load:
+		str = fmt.Sprintf("v128.load (type=%d, align=%d, offset=%d)", o.Type, o.Arg.Alignment, o.Arg.Offset)
+	case *OperationV128LoadLane:
+		str = fmt.Sprintf("v128.load%d_lane %d (align=%d, offset=%d)", o.LaneSize, o.LaneIndex, o.Arg.Alignment, o.Arg.Offset)
+	case *OperationV128Store:
+		str = fmt.Sprintf("v128.store (align=%d, offset=%d)", o.Arg.Alignment, o.Arg.Offset)
+	case *OperationV128StoreLane:
+		str = fmt.Sprintf("v128.store%d_lane %d (align=%d, offset=%d)", o.LaneSize, o.LaneIndex, o.Arg.Alignment, o.Arg.Offset)
+	case *OperationV128ExtractLane:
+		str = fmt.Sprintf("v128.extract_lane %d (shape=%s, signed=%v)", o.LaneIndex, shapeName(o.Shape), o.Signed)
+	case *OperationV128ReplaceLane:
+		str = fmt.Sprintf("v128.replace_lane %d (shape=%s)", o.LaneIndex, shapeName(o.Shape))
+	case *OperationV128Splat:
+		str = fmt.Sprintf("v128.splat (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Shuffle:
+		str = fmt.Sprintf("v128.shuffle %v", o.Lanes)
+	case *OperationV128Swizzle:
+		str = "v128.swizzle"
+	case *OperationV128AnyTrue:
+		str = "v128.any_true"
+	case *OperationV128AllTrue:
+		str = fmt.Sprintf("v128.all_true (shape=%s)", shapeName(o.Shape))
+	case *OperationV128BitMask:
+		str = fmt.Sprintf("v128.bitmask (shape=%s)", shapeName(o.Shape))
+	case *OperationV128And:
+		str = "v128.and"
+	case *OperationV128Not:
+		str = "v128.not"
+	case *OperationV128Or:
+		str = "v128.or"
+	case *OperationV128Xor:
+		str = "v128.xor"
+	case *OperationV128Bitselect:
+		str = "v128.bitselect"
+	case *OperationV128AndNot:
+		str = "v128.andnot"
+	case *OperationV128Shl:
+		str = fmt.Sprintf("v128.shl (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Shr:
+		str = fmt.Sprintf("v128.shr (shape=%s, signed=%v)", shapeName(o.Shape), o.Signed)
+	case *OperationV128Cmp:
+		str = fmt.Sprintf("v128.cmp (type=%d)", o.Type)
+	case *OperationV128AddSat:
+		str = fmt.Sprintf("v128.add_sat (shape=%s, signed=%v)", shapeName(o.Shape), o.Signed)
+	case *OperationV128SubSat:
+		str = fmt.Sprintf("v128.sub_sat (shape=%s, signed=%v)", shapeName(o.Shape), o.Signed)
+	case *OperationV128Mul:
+		str = fmt.Sprintf("v128.mul (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Div:
+		str = fmt.Sprintf("v128.div (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Neg:
+		str = fmt.Sprintf("v128.neg (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Sqrt:
+		str = fmt.Sprintf("v128.sqrt (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Abs:
+		str = fmt.Sprintf("v128.abs (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Popcnt:
+		str = "v128.popcnt"
+	case *OperationV128Min:
+		str = fmt.Sprintf("v128.min (shape=%s, signed=%v)", shapeName(o.Shape), o.Signed)
+	case *OperationV128Max:
+		str = fmt.Sprintf("v128.max (shape=%s, signed=%v)", shapeName(o.Shape), o.Signed)
+	case *OperationV128AvgrU:
+		str = fmt.Sprintf("v128.avgr_u (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Pmin:
+		str = fmt.Sprintf("v128.pmin (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Pmax:
+		str = fmt.Sprintf("v128.pmax (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Ceil:
+		str = fmt.Sprintf("v128.ceil (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Floor:
+		str = fmt.Sprintf("v128.floor (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Trunc:
+		str = fmt.Sprintf("v128.trunc (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Nearest:
+		str = fmt.Sprintf("v128.nearest (shape=%s)", shapeName(o.Shape))
+	case *OperationV128Extend:
+		str = fmt.Sprintf("v128.extend (origin_shape=%s, signed=%v, use_low=%v)", shapeName(o.OriginShape), o.Signed, o.UseLow)
+	case *OperationV128ExtMul:
+		str = fmt.Sprintf("v128.extmul (origin_shape=%s, signed=%v, use_low=%v)", shapeName(o.OriginShape), o.Signed, o.UseLow)
+	case *OperationV128Q15mulrSatS:
+		str = "v128.q15mulr_sat_s"
+	case *OperationV128ExtAddPairwise:
+		str = fmt.Sprintf("v128.extadd_pairwise (origin_shape=%s, signed=%v)", shapeName(o.OriginShape), o.Signed)
+	case *OperationV128FloatPromote:
+		str = "v128.float_promote"
+	case *OperationV128FloatDemote:
+		str = "v128.float_demote"
+	case *OperationV128FConvertFromI:
+		str = fmt.Sprintf("v128.fconvert_from_i (destination_shape=%s, signed=%v)", shapeName(o.DestinationShape), o.Signed)
+	case *OperationV128Dot:
+		str = "v128.dot"
+	case *OperationV128Narrow:
+		str = fmt.Sprintf("v128.narrow (origin_shape=%s, signed=%v)", shapeName(o.OriginShape), o.Signed)
+	case *OperationV128ITruncSatFromF:
+		str = fmt.Sprintf("v128.itrunc_sat_from_f (origin_shape=%s, signed=%v)", shapeName(o.OriginShape), o.Signed)
+	case *OperationAtomicLoad:
+		str = fmt.Sprintf("%s.atomic.load (size=%d, align=%d, offset=%d)", o.Type, o.SizeInBytes, o.Arg.Alignment, o.Arg.Offset)
+	case *OperationAtomicStore:
+		str = fmt.Sprintf("%s.atomic.store (size=%d, align=%d, offset=%d)", o.Type, o.SizeInBytes, o.Arg.Alignment, o.Arg.Offset)
+	case *OperationAtomicRMW:
+		str = fmt.Sprintf("%s.atomic.rmw.%s (size=%d, align=%d, offset=%d)", o.Type, o.Op, o.SizeInBytes, o.Arg.Alignment, o.Arg.Offset)
+	case *OperationAtomicRMWCmpxchg:
+		str = fmt.Sprintf("%s.atomic.rmw.cmpxchg (size=%d, align=%d, offset=%d)", o.Type, o.SizeInBytes, o.Arg.Alignment, o.Arg.Offset)
+	case *OperationAtomicMemoryWait:
+		str = fmt.Sprintf("memory.atomic.wait (type=%s, align=%d, offset=%d)", o.Type, o.Arg.Alignment, o.Arg.Offset)
+	case *OperationAtomicMemoryNotify:
+		str = fmt.Sprintf("memory.atomic.notify (align=%d, offset=%d)", o.Arg.Alignment, o.Arg.Offset)
+	case *OperationAtomicFence:
+		str = "atomic.fence"
+	case *OperationTry:
+		str = fmt.Sprintf("try %d (outer=%s, height=%d)", o.FrameID, frameIDString(o.Outer), o.StackHeight)
+	case *OperationCatch:
+		if o.CatchAll {
+			str = fmt.Sprintf("catch_all %d", o.FrameID)
+		} else {
+			str = fmt.Sprintf("catch %d: tag=%d", o.FrameID, o.TagIndex)
+		}
+	case *OperationDelegate:
+		str = fmt.Sprintf("delegate %d (target=%s)", o.FrameID, frameIDString(o.Target))
+	case *OperationThrow:
+		str = fmt.Sprintf("throw %d", o.TagIndex)
+	case *OperationRethrow:
+		str = "rethrow"
+	case *OperationConsumeFuel:
+		str = fmt.Sprintf("consume_fuel %d", o.Cost)
 	default:
 		panic("unreachable: a bug in wazeroir implementation")
 	}
@@ -196,3 +328,11 @@ func formatOperation(w io.StringWriter, b Operation) {
 
 	_, _ = w.WriteString(str + "\n")
 }
+
+// frameIDString formats the optional frame ID of a try block, where nil means the caller.
+func frameIDString(id *uint32) string {
+	if id == nil {
+		return "caller"
+	}
+	return fmt.Sprintf("%d", *id)
+}
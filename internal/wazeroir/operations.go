@@ -139,6 +139,10 @@ func (o OperationKind) String() (ret string) {
 		ret = "Call"
 	case OperationKindCallIndirect:
 		ret = "CallIndirect"
+	case OperationKindTailCall:
+		ret = "TailCall"
+	case OperationKindTailCallIndirect:
+		ret = "TailCallIndirect"
 	case OperationKindDrop:
 		ret = "Drop"
 	case OperationKindSelect:
@@ -290,10 +294,134 @@ func (o OperationKind) String() (ret string) {
 		ret = "TableGrow"
 	case OperationKindTableFill:
 		ret = "TableFill"
-	case OperationKindConstV128:
-		ret = "ConstV128"
+	case OperationKindV128Const:
+		ret = "V128Const"
 	case OperationKindV128Add:
 		ret = "V128Add"
+	case OperationKindV128Sub:
+		ret = "V128Sub"
+	case OperationKindV128Load:
+		ret = "V128Load"
+	case OperationKindV128LoadLane:
+		ret = "V128LoadLane"
+	case OperationKindV128Store:
+		ret = "V128Store"
+	case OperationKindV128StoreLane:
+		ret = "V128StoreLane"
+	case OperationKindV128ExtractLane:
+		ret = "V128ExtractLane"
+	case OperationKindV128ReplaceLane:
+		ret = "V128ReplaceLane"
+	case OperationKindV128Splat:
+		ret = "V128Splat"
+	case OperationKindV128Shuffle:
+		ret = "V128Shuffle"
+	case OperationKindV128Swizzle:
+		ret = "V128Swizzle"
+	case OperationKindV128AnyTrue:
+		ret = "V128AnyTrue"
+	case OperationKindV128AllTrue:
+		ret = "V128AllTrue"
+	case OperationKindV128BitMask:
+		ret = "V128BitMask"
+	case OperationKindV128And:
+		ret = "V128And"
+	case OperationKindV128Not:
+		ret = "V128Not"
+	case OperationKindV128Or:
+		ret = "V128Or"
+	case OperationKindV128Xor:
+		ret = "V128Xor"
+	case OperationKindV128Bitselect:
+		ret = "V128Bitselect"
+	case OperationKindV128AndNot:
+		ret = "V128AndNot"
+	case OperationKindV128Shl:
+		ret = "V128Shl"
+	case OperationKindV128Shr:
+		ret = "V128Shr"
+	case OperationKindV128Cmp:
+		ret = "V128Cmp"
+	case OperationKindV128AddSat:
+		ret = "V128AddSat"
+	case OperationKindV128SubSat:
+		ret = "V128SubSat"
+	case OperationKindV128Mul:
+		ret = "V128Mul"
+	case OperationKindV128Div:
+		ret = "V128Div"
+	case OperationKindV128Neg:
+		ret = "V128Neg"
+	case OperationKindV128Sqrt:
+		ret = "V128Sqrt"
+	case OperationKindV128Abs:
+		ret = "V128Abs"
+	case OperationKindV128Popcnt:
+		ret = "V128Popcnt"
+	case OperationKindV128Min:
+		ret = "V128Min"
+	case OperationKindV128Max:
+		ret = "V128Max"
+	case OperationKindV128AvgrU:
+		ret = "V128AvgrU"
+	case OperationKindV128Pmin:
+		ret = "V128Pmin"
+	case OperationKindV128Pmax:
+		ret = "V128Pmax"
+	case OperationKindV128Ceil:
+		ret = "V128Ceil"
+	case OperationKindV128Floor:
+		ret = "V128Floor"
+	case OperationKindV128Trunc:
+		ret = "V128Trunc"
+	case OperationKindV128Nearest:
+		ret = "V128Nearest"
+	case OperationKindV128Extend:
+		ret = "V128Extend"
+	case OperationKindV128ExtMul:
+		ret = "V128ExtMul"
+	case OperationKindV128Q15mulrSatS:
+		ret = "V128Q15mulrSatS"
+	case OperationKindV128ExtAddPairwise:
+		ret = "V128ExtAddPairwise"
+	case OperationKindV128FloatPromote:
+		ret = "V128FloatPromote"
+	case OperationKindV128FloatDemote:
+		ret = "V128FloatDemote"
+	case OperationKindV128FConvertFromI:
+		ret = "V128FConvertFromI"
+	case OperationKindV128Dot:
+		ret = "V128Dot"
+	case OperationKindV128Narrow:
+		ret = "V128Narrow"
+	case OperationKindV128ITruncSatFromF:
+		ret = "V128ITruncSatFromF"
+	case OperationKindAtomicLoad:
+		ret = "AtomicLoad"
+	case OperationKindAtomicStore:
+		ret = "AtomicStore"
+	case OperationKindAtomicRMW:
+		ret = "AtomicRMW"
+	case OperationKindAtomicRMWCmpxchg:
+		ret = "AtomicRMWCmpxchg"
+	case OperationKindAtomicMemoryWait:
+		ret = "AtomicMemoryWait"
+	case OperationKindAtomicMemoryNotify:
+		ret = "AtomicMemoryNotify"
+	case OperationKindAtomicFence:
+		ret = "AtomicFence"
+	case OperationKindTry:
+		ret = "Try"
+	case OperationKindCatch:
+		ret = "Catch"
+	case OperationKindDelegate:
+		ret = "Delegate"
+	case OperationKindThrow:
+		ret = "Throw"
+	case OperationKindRethrow:
+		ret = "Rethrow"
+	case OperationKindConsumeFuel:
+		ret = "ConsumeFuel"
 	default:
 		panic("BUG")
 	}
@@ -308,6 +436,8 @@ const (
 	OperationKindBrTable
 	OperationKindCall
 	OperationKindCallIndirect
+	OperationKindTailCall
+	OperationKindTailCallIndirect
 	OperationKindDrop
 	OperationKindSelect
 	OperationKindPick
@@ -388,8 +518,76 @@ const (
 	OperationKindTableSize
 	OperationKindTableGrow
 	OperationKindTableFill
-	OperationKindConstV128
+	OperationKindV128Const
 	OperationKindV128Add
+	OperationKindV128Sub
+	OperationKindV128Load
+	OperationKindV128LoadLane
+	OperationKindV128Store
+	OperationKindV128StoreLane
+	OperationKindV128ExtractLane
+	OperationKindV128ReplaceLane
+	OperationKindV128Splat
+	OperationKindV128Shuffle
+	OperationKindV128Swizzle
+	OperationKindV128AnyTrue
+	OperationKindV128AllTrue
+	OperationKindV128BitMask
+	OperationKindV128And
+	OperationKindV128Not
+	OperationKindV128Or
+	OperationKindV128Xor
+	OperationKindV128Bitselect
+	OperationKindV128AndNot
+	OperationKindV128Shl
+	OperationKindV128Shr
+	OperationKindV128Cmp
+	OperationKindV128AddSat
+	OperationKindV128SubSat
+	OperationKindV128Mul
+	OperationKindV128Div
+	OperationKindV128Neg
+	OperationKindV128Sqrt
+	OperationKindV128Abs
+	OperationKindV128Popcnt
+	OperationKindV128Min
+	OperationKindV128Max
+	OperationKindV128AvgrU
+	OperationKindV128Pmin
+	OperationKindV128Pmax
+	OperationKindV128Ceil
+	OperationKindV128Floor
+	OperationKindV128Trunc
+	OperationKindV128Nearest
+	OperationKindV128Extend
+	OperationKindV128ExtMul
+	OperationKindV128Q15mulrSatS
+	OperationKindV128ExtAddPairwise
+	OperationKindV128FloatPromote
+	OperationKindV128FloatDemote
+	OperationKindV128FConvertFromI
+	OperationKindV128Dot
+	OperationKindV128Narrow
+	OperationKindV128ITruncSatFromF
+	OperationKindAtomicLoad
+	OperationKindAtomicStore
+	OperationKindAtomicRMW
+	OperationKindAtomicRMWCmpxchg
+	OperationKindAtomicMemoryWait
+	OperationKindAtomicMemoryNotify
+	OperationKindAtomicFence
+
+	// Below are toggled with wasm.FeatureExceptionHandling
+
+	OperationKindTry
+	OperationKindCatch
+	OperationKindDelegate
+	OperationKindThrow
+	OperationKindRethrow
+
+	// Below are only emitted when wasm.Module FuelCosts are set.
+
+	OperationKindConsumeFuel
 )
 
 type Label struct {
@@ -520,6 +718,33 @@ func (o *OperationCallIndirect) Kind() OperationKind {
 	return OperationKindCallIndirect
 }
 
+// OperationTailCall implements Operation.
+//
+// This corresponds to wasm.OpcodeReturnCallName, and is emitted after the values below the arguments are dropped.
+// The callee reuses the call frame of the current function, and its results are returned to the caller.
+type OperationTailCall struct {
+	FunctionIndex uint32
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationTailCall) Kind() OperationKind {
+	return OperationKindTailCall
+}
+
+// OperationTailCallIndirect implements Operation.
+//
+// This corresponds to wasm.OpcodeReturnCallIndirectName, and is emitted after the values below the arguments and
+// the table offset are dropped. The callee reuses the call frame of the current function, and its results are
+// returned to the caller.
+type OperationTailCallIndirect struct {
+	TypeIndex, TableIndex uint32
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationTailCallIndirect) Kind() OperationKind {
+	return OperationKindTailCallIndirect
+}
+
 type OperationDrop struct {
 	// Depths spans across the uint64 value stack at runtime to be dopped by this operation.
 	Depth *InclusiveRange
@@ -529,7 +754,10 @@ func (o *OperationDrop) Kind() OperationKind {
 	return OperationKindDrop
 }
 
-type OperationSelect struct{}
+type OperationSelect struct {
+	// IsTargetVector true if the selection is between vectors, which span two uint64 values on the stack.
+	IsTargetVector bool
+}
 
 func (o *OperationSelect) Kind() OperationKind {
 	return OperationKindSelect
@@ -582,6 +810,9 @@ type MemoryImmediate struct {
 	// Offset is the address offset added to the instruction's dynamic address operand, yielding a 33-bit effective
 	// address that is the zero-based index at which the memory is accessed. Default to zero.
 	Offset uint32
+	// MemoryIndex is the index of the memory in ModuleInstance.Memories. This is non-zero only with
+	// wasm.FeatureMultiMemory.
+	MemoryIndex uint32
 }
 
 type OperationLoad struct {
@@ -658,14 +889,21 @@ func (o *OperationStore32) Kind() OperationKind {
 	return OperationKindStore32
 }
 
-type OperationMemorySize struct{}
+type OperationMemorySize struct {
+	// MemoryIndex is the index of the memory in ModuleInstance.Memories.
+	MemoryIndex uint32
+}
 
 // Kind implements Operation.Kind.
 func (o *OperationMemorySize) Kind() OperationKind {
 	return OperationKindMemorySize
 }
 
-type OperationMemoryGrow struct{ Alignment uint64 }
+type OperationMemoryGrow struct {
+	Alignment uint64
+	// MemoryIndex is the index of the memory in ModuleInstance.Memories.
+	MemoryIndex uint32
+}
 
 // Kind implements Operation.Kind.
 func (o *OperationMemoryGrow) Kind() OperationKind {
@@ -1041,6 +1279,8 @@ type OperationMemoryInit struct {
 	// DataIndex is the index of the data instance in ModuleInstance.DataInstances
 	// by which this operation instantiates a part of the memory.
 	DataIndex uint32
+	// MemoryIndex is the index of the memory in ModuleInstance.Memories.
+	MemoryIndex uint32
 }
 
 // Kind implements Operation.Kind.
@@ -1059,14 +1299,19 @@ func (o *OperationDataDrop) Kind() OperationKind {
 	return OperationKindDataDrop
 }
 
-type OperationMemoryCopy struct{}
+type OperationMemoryCopy struct {
+	SrcMemoryIndex, DstMemoryIndex uint32
+}
 
 // Kind implements Operation.Kind.
 func (o *OperationMemoryCopy) Kind() OperationKind {
 	return OperationKindMemoryCopy
 }
 
-type OperationMemoryFill struct{}
+type OperationMemoryFill struct {
+	// MemoryIndex is the index of the memory in ModuleInstance.Memories.
+	MemoryIndex uint32
+}
 
 // Kind implements Operation.Kind.
 func (o *OperationMemoryFill) Kind() OperationKind {
@@ -1167,14 +1412,14 @@ func (o *OperationTableFill) Kind() OperationKind {
 	return OperationKindTableFill
 }
 
-// OperationConstV128 implements Operation.
-type OperationConstV128 struct {
+// OperationV128Const implements Operation.
+type OperationV128Const struct {
 	Lo, Hi uint64
 }
 
 // Kind implements Operation.Kind.
-func (o *OperationConstV128) Kind() OperationKind {
-	return OperationKindConstV128
+func (o *OperationV128Const) Kind() OperationKind {
+	return OperationKindV128Const
 }
 
 // Shape corresponds to a shape of v128 values.
@@ -1208,12 +1453,1085 @@ func shapeName(s Shape) (ret string) {
 	return
 }
 
-// OperationAddV128 implements Operation.
-type OperationAddV128 struct {
+// OperationV128Add implements Operation.
+type OperationV128Add struct {
 	Shape Shape
 }
 
 // Kind implements Operation.Kind.
-func (o *OperationAddV128) Kind() OperationKind {
+func (o *OperationV128Add) Kind() OperationKind {
 	return OperationKindV128Add
 }
+
+// OperationV128Sub implements Operation.
+//
+// This corresponds to wasm.OpcodeVecI8x16SubName wasm.OpcodeVecI16x8SubName wasm.OpcodeVecI32x4SubName
+// wasm.OpcodeVecI64x2SubName wasm.OpcodeVecF32x4SubName wasm.OpcodeVecF64x2SubName
+type OperationV128Sub struct {
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Sub) Kind() OperationKind {
+	return OperationKindV128Sub
+}
+
+// V128LoadType represents a type of wasm.OpcodeVecV128Load* instructions.
+type V128LoadType = byte
+
+const (
+	// V128LoadType128 corresponds to wasm.OpcodeVecV128LoadName.
+	V128LoadType128 V128LoadType = iota
+	// V128LoadType8x8s corresponds to wasm.OpcodeVecV128Load8x8SName.
+	V128LoadType8x8s
+	// V128LoadType8x8u corresponds to wasm.OpcodeVecV128Load8x8UName.
+	V128LoadType8x8u
+	// V128LoadType16x4s corresponds to wasm.OpcodeVecV128Load16x4SName
+	V128LoadType16x4s
+	// V128LoadType16x4u corresponds to wasm.OpcodeVecV128Load16x4UName
+	V128LoadType16x4u
+	// V128LoadType32x2s corresponds to wasm.OpcodeVecV128Load32x2SName
+	V128LoadType32x2s
+	// V128LoadType32x2u corresponds to wasm.OpcodeVecV128Load32x2UName
+	V128LoadType32x2u
+	// V128LoadType8Splat corresponds to wasm.OpcodeVecV128Load8SplatName
+	V128LoadType8Splat
+	// V128LoadType16Splat corresponds to wasm.OpcodeVecV128Load16SplatName
+	V128LoadType16Splat
+	// V128LoadType32Splat corresponds to wasm.OpcodeVecV128Load32SplatName
+	V128LoadType32Splat
+	// V128LoadType64Splat corresponds to wasm.OpcodeVecV128Load64SplatName
+	V128LoadType64Splat
+	// V128LoadType32zero corresponds to wasm.OpcodeVecV128Load32zeroName
+	V128LoadType32zero
+	// V128LoadType64zero corresponds to wasm.OpcodeVecV128Load64zeroName
+	V128LoadType64zero
+)
+
+// OperationV128Load implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecV128LoadName wasm.OpcodeVecV128Load8x8SName wasm.OpcodeVecV128Load8x8UName
+//	wasm.OpcodeVecV128Load16x4SName wasm.OpcodeVecV128Load16x4UName wasm.OpcodeVecV128Load32x2SName
+//	wasm.OpcodeVecV128Load32x2UName wasm.OpcodeVecV128Load8SplatName wasm.OpcodeVecV128Load16SplatName
+//	wasm.OpcodeVecV128Load32SplatName wasm.OpcodeVecV128Load64SplatName wasm.OpcodeVecV128Load32zeroName
+//	wasm.OpcodeVecV128Load64zeroName
+type OperationV128Load struct {
+	Type V128LoadType
+	Arg  *MemoryImmediate
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Load) Kind() OperationKind {
+	return OperationKindV128Load
+}
+
+// OperationV128LoadLane implements Operation.
+//
+// This corresponds to wasm.OpcodeVecV128Load8LaneName wasm.OpcodeVecV128Load16LaneName
+// wasm.OpcodeVecV128Load32LaneName wasm.OpcodeVecV128Load64LaneName.
+type OperationV128LoadLane struct {
+	// LaneIndex is >=0 && <(128/LaneSize).
+	LaneIndex byte
+	// LaneSize is either 8, 16, 32, or 64.
+	LaneSize byte
+	Arg      *MemoryImmediate
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128LoadLane) Kind() OperationKind {
+	return OperationKindV128LoadLane
+}
+
+// OperationV128Store implements Operation.
+//
+// This corresponds to wasm.OpcodeVecV128StoreName.
+type OperationV128Store struct {
+	Arg *MemoryImmediate
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Store) Kind() OperationKind {
+	return OperationKindV128Store
+}
+
+// OperationV128StoreLane implements Operation.
+//
+// This corresponds to wasm.OpcodeVecV128Store8LaneName wasm.OpcodeVecV128Store16LaneName
+// wasm.OpcodeVecV128Store32LaneName wasm.OpcodeVecV128Store64LaneName.
+type OperationV128StoreLane struct {
+	// LaneIndex is >=0 && <(128/LaneSize).
+	LaneIndex byte
+	// LaneSize is either 8, 16, 32, or 64.
+	LaneSize byte
+	Arg      *MemoryImmediate
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128StoreLane) Kind() OperationKind {
+	return OperationKindV128StoreLane
+}
+
+// OperationV128ExtractLane implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI8x16ExtractLaneSName wasm.OpcodeVecI8x16ExtractLaneUName
+//	wasm.OpcodeVecI16x8ExtractLaneSName wasm.OpcodeVecI16x8ExtractLaneUName
+//	wasm.OpcodeVecI32x4ExtractLaneName wasm.OpcodeVecI64x2ExtractLaneName
+//	wasm.OpcodeVecF32x4ExtractLaneName wasm.OpcodeVecF64x2ExtractLaneName.
+type OperationV128ExtractLane struct {
+	// LaneIndex is >=0 && <M where shape = NxM.
+	LaneIndex byte
+	// Signed is used when shape is either i8x16 or i16x8 to specify whether to sign-extend or not.
+	Signed bool
+	Shape  Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128ExtractLane) Kind() OperationKind {
+	return OperationKindV128ExtractLane
+}
+
+// OperationV128ReplaceLane implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI8x16ReplaceLaneName wasm.OpcodeVecI16x8ReplaceLaneName
+//	wasm.OpcodeVecI32x4ReplaceLaneName wasm.OpcodeVecI64x2ReplaceLaneName
+//	wasm.OpcodeVecF32x4ReplaceLaneName wasm.OpcodeVecF64x2ReplaceLaneName.
+type OperationV128ReplaceLane struct {
+	// LaneIndex is >=0 && <M where shape = NxM.
+	LaneIndex byte
+	Shape     Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128ReplaceLane) Kind() OperationKind {
+	return OperationKindV128ReplaceLane
+}
+
+// OperationV128Splat implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI8x16SplatName wasm.OpcodeVecI16x8SplatName
+//	wasm.OpcodeVecI32x4SplatName wasm.OpcodeVecI64x2SplatName
+//	wasm.OpcodeVecF32x4SplatName wasm.OpcodeVecF64x2SplatName.
+type OperationV128Splat struct {
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Splat) Kind() OperationKind {
+	return OperationKindV128Splat
+}
+
+// OperationV128Shuffle implements Operation.
+//
+// This corresponds to wasm.OpcodeVecV128i8x16ShuffleName.
+type OperationV128Shuffle struct {
+	// Lanes are the lane indexes in [0, 32) where [0, 16) selects the first vector and [16, 32) the second.
+	Lanes [16]byte
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Shuffle) Kind() OperationKind {
+	return OperationKindV128Shuffle
+}
+
+// OperationV128Swizzle implements Operation.
+//
+// This corresponds to wasm.OpcodeVecI8x16SwizzleName.
+type OperationV128Swizzle struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Swizzle) Kind() OperationKind {
+	return OperationKindV128Swizzle
+}
+
+// OperationV128AnyTrue implements Operation.
+//
+// This corresponds to wasm.OpcodeVecV128AnyTrueName.
+type OperationV128AnyTrue struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128AnyTrue) Kind() OperationKind {
+	return OperationKindV128AnyTrue
+}
+
+// OperationV128AllTrue implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI8x16AllTrueName wasm.OpcodeVecI16x8AllTrueName
+//	wasm.OpcodeVecI32x4AllTrueName wasm.OpcodeVecI64x2AllTrueName.
+type OperationV128AllTrue struct {
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128AllTrue) Kind() OperationKind {
+	return OperationKindV128AllTrue
+}
+
+// OperationV128BitMask implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI8x16BitMaskName wasm.OpcodeVecI16x8BitMaskName
+//	wasm.OpcodeVecI32x4BitMaskName wasm.OpcodeVecI64x2BitMaskName.
+type OperationV128BitMask struct {
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128BitMask) Kind() OperationKind {
+	return OperationKindV128BitMask
+}
+
+// OperationV128And implements Operation.
+//
+// This corresponds to wasm.OpcodeVecV128And.
+type OperationV128And struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128And) Kind() OperationKind {
+	return OperationKindV128And
+}
+
+// OperationV128Not implements Operation.
+//
+// This corresponds to wasm.OpcodeVecV128Not.
+type OperationV128Not struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Not) Kind() OperationKind {
+	return OperationKindV128Not
+}
+
+// OperationV128Or implements Operation.
+//
+// This corresponds to wasm.OpcodeVecV128Or.
+type OperationV128Or struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Or) Kind() OperationKind {
+	return OperationKindV128Or
+}
+
+// OperationV128Xor implements Operation.
+//
+// This corresponds to wasm.OpcodeVecV128Xor.
+type OperationV128Xor struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Xor) Kind() OperationKind {
+	return OperationKindV128Xor
+}
+
+// OperationV128Bitselect implements Operation.
+//
+// This corresponds to wasm.OpcodeVecV128Bitselect.
+type OperationV128Bitselect struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Bitselect) Kind() OperationKind {
+	return OperationKindV128Bitselect
+}
+
+// OperationV128AndNot implements Operation.
+//
+// This corresponds to wasm.OpcodeVecV128AndNot.
+type OperationV128AndNot struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128AndNot) Kind() OperationKind {
+	return OperationKindV128AndNot
+}
+
+// OperationV128Shl implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI8x16ShlName wasm.OpcodeVecI16x8ShlName
+//	wasm.OpcodeVecI32x4ShlName wasm.OpcodeVecI64x2ShlName
+type OperationV128Shl struct {
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Shl) Kind() OperationKind {
+	return OperationKindV128Shl
+}
+
+// OperationV128Shr implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI8x16ShrSName wasm.OpcodeVecI8x16ShrUName wasm.OpcodeVecI16x8ShrSName
+//	wasm.OpcodeVecI16x8ShrUName wasm.OpcodeVecI32x4ShrSName wasm.OpcodeVecI32x4ShrUName
+//	wasm.OpcodeVecI64x2ShrSName wasm.OpcodeVecI64x2ShrUName.
+type OperationV128Shr struct {
+	Shape  Shape
+	Signed bool
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Shr) Kind() OperationKind {
+	return OperationKindV128Shr
+}
+
+// V128CmpType represents a type of vector comparison operation.
+type V128CmpType = byte
+
+const (
+	// V128CmpTypeI8x16Eq corresponds to wasm.OpcodeVecI8x16EqName.
+	V128CmpTypeI8x16Eq V128CmpType = iota
+	// V128CmpTypeI8x16Ne corresponds to wasm.OpcodeVecI8x16NeName.
+	V128CmpTypeI8x16Ne
+	// V128CmpTypeI8x16LtS corresponds to wasm.OpcodeVecI8x16LtSName.
+	V128CmpTypeI8x16LtS
+	// V128CmpTypeI8x16LtU corresponds to wasm.OpcodeVecI8x16LtUName.
+	V128CmpTypeI8x16LtU
+	// V128CmpTypeI8x16GtS corresponds to wasm.OpcodeVecI8x16GtSName.
+	V128CmpTypeI8x16GtS
+	// V128CmpTypeI8x16GtU corresponds to wasm.OpcodeVecI8x16GtUName.
+	V128CmpTypeI8x16GtU
+	// V128CmpTypeI8x16LeS corresponds to wasm.OpcodeVecI8x16LeSName.
+	V128CmpTypeI8x16LeS
+	// V128CmpTypeI8x16LeU corresponds to wasm.OpcodeVecI8x16LeUName.
+	V128CmpTypeI8x16LeU
+	// V128CmpTypeI8x16GeS corresponds to wasm.OpcodeVecI8x16GeSName.
+	V128CmpTypeI8x16GeS
+	// V128CmpTypeI8x16GeU corresponds to wasm.OpcodeVecI8x16GeUName.
+	V128CmpTypeI8x16GeU
+	// V128CmpTypeI16x8Eq corresponds to wasm.OpcodeVecI16x8EqName.
+	V128CmpTypeI16x8Eq
+	// V128CmpTypeI16x8Ne corresponds to wasm.OpcodeVecI16x8NeName.
+	V128CmpTypeI16x8Ne
+	// V128CmpTypeI16x8LtS corresponds to wasm.OpcodeVecI16x8LtSName.
+	V128CmpTypeI16x8LtS
+	// V128CmpTypeI16x8LtU corresponds to wasm.OpcodeVecI16x8LtUName.
+	V128CmpTypeI16x8LtU
+	// V128CmpTypeI16x8GtS corresponds to wasm.OpcodeVecI16x8GtSName.
+	V128CmpTypeI16x8GtS
+	// V128CmpTypeI16x8GtU corresponds to wasm.OpcodeVecI16x8GtUName.
+	V128CmpTypeI16x8GtU
+	// V128CmpTypeI16x8LeS corresponds to wasm.OpcodeVecI16x8LeSName.
+	V128CmpTypeI16x8LeS
+	// V128CmpTypeI16x8LeU corresponds to wasm.OpcodeVecI16x8LeUName.
+	V128CmpTypeI16x8LeU
+	// V128CmpTypeI16x8GeS corresponds to wasm.OpcodeVecI16x8GeSName.
+	V128CmpTypeI16x8GeS
+	// V128CmpTypeI16x8GeU corresponds to wasm.OpcodeVecI16x8GeUName.
+	V128CmpTypeI16x8GeU
+	// V128CmpTypeI32x4Eq corresponds to wasm.OpcodeVecI32x4EqName.
+	V128CmpTypeI32x4Eq
+	// V128CmpTypeI32x4Ne corresponds to wasm.OpcodeVecI32x4NeName.
+	V128CmpTypeI32x4Ne
+	// V128CmpTypeI32x4LtS corresponds to wasm.OpcodeVecI32x4LtSName.
+	V128CmpTypeI32x4LtS
+	// V128CmpTypeI32x4LtU corresponds to wasm.OpcodeVecI32x4LtUName.
+	V128CmpTypeI32x4LtU
+	// V128CmpTypeI32x4GtS corresponds to wasm.OpcodeVecI32x4GtSName.
+	V128CmpTypeI32x4GtS
+	// V128CmpTypeI32x4GtU corresponds to wasm.OpcodeVecI32x4GtUName.
+	V128CmpTypeI32x4GtU
+	// V128CmpTypeI32x4LeS corresponds to wasm.OpcodeVecI32x4LeSName.
+	V128CmpTypeI32x4LeS
+	// V128CmpTypeI32x4LeU corresponds to wasm.OpcodeVecI32x4LeUName.
+	V128CmpTypeI32x4LeU
+	// V128CmpTypeI32x4GeS corresponds to wasm.OpcodeVecI32x4GeSName.
+	V128CmpTypeI32x4GeS
+	// V128CmpTypeI32x4GeU corresponds to wasm.OpcodeVecI32x4GeUName.
+	V128CmpTypeI32x4GeU
+	// V128CmpTypeI64x2Eq corresponds to wasm.OpcodeVecI64x2EqName.
+	V128CmpTypeI64x2Eq
+	// V128CmpTypeI64x2Ne corresponds to wasm.OpcodeVecI64x2NeName.
+	V128CmpTypeI64x2Ne
+	// V128CmpTypeI64x2LtS corresponds to wasm.OpcodeVecI64x2LtSName.
+	V128CmpTypeI64x2LtS
+	// V128CmpTypeI64x2GtS corresponds to wasm.OpcodeVecI64x2GtSName.
+	V128CmpTypeI64x2GtS
+	// V128CmpTypeI64x2LeS corresponds to wasm.OpcodeVecI64x2LeSName.
+	V128CmpTypeI64x2LeS
+	// V128CmpTypeI64x2GeS corresponds to wasm.OpcodeVecI64x2GeSName.
+	V128CmpTypeI64x2GeS
+	// V128CmpTypeF32x4Eq corresponds to wasm.OpcodeVecF32x4EqName.
+	V128CmpTypeF32x4Eq
+	// V128CmpTypeF32x4Ne corresponds to wasm.OpcodeVecF32x4NeName.
+	V128CmpTypeF32x4Ne
+	// V128CmpTypeF32x4Lt corresponds to wasm.OpcodeVecF32x4LtName.
+	V128CmpTypeF32x4Lt
+	// V128CmpTypeF32x4Gt corresponds to wasm.OpcodeVecF32x4GtName.
+	V128CmpTypeF32x4Gt
+	// V128CmpTypeF32x4Le corresponds to wasm.OpcodeVecF32x4LeName.
+	V128CmpTypeF32x4Le
+	// V128CmpTypeF32x4Ge corresponds to wasm.OpcodeVecF32x4GeName.
+	V128CmpTypeF32x4Ge
+	// V128CmpTypeF64x2Eq corresponds to wasm.OpcodeVecF64x2EqName.
+	V128CmpTypeF64x2Eq
+	// V128CmpTypeF64x2Ne corresponds to wasm.OpcodeVecF64x2NeName.
+	V128CmpTypeF64x2Ne
+	// V128CmpTypeF64x2Lt corresponds to wasm.OpcodeVecF64x2LtName.
+	V128CmpTypeF64x2Lt
+	// V128CmpTypeF64x2Gt corresponds to wasm.OpcodeVecF64x2GtName.
+	V128CmpTypeF64x2Gt
+	// V128CmpTypeF64x2Le corresponds to wasm.OpcodeVecF64x2LeName.
+	V128CmpTypeF64x2Le
+	// V128CmpTypeF64x2Ge corresponds to wasm.OpcodeVecF64x2GeName.
+	V128CmpTypeF64x2Ge
+)
+
+// OperationV128Cmp implements Operation.
+//
+// This corresponds to the wasm.OpcodeVec*Eq, wasm.OpcodeVec*Ne, wasm.OpcodeVec*Lt*, wasm.OpcodeVec*Gt*,
+// wasm.OpcodeVec*Le* and wasm.OpcodeVec*Ge* instructions.
+type OperationV128Cmp struct {
+	Type V128CmpType
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Cmp) Kind() OperationKind {
+	return OperationKindV128Cmp
+}
+
+// OperationV128AddSat implements Operation.
+//
+// This corresponds to wasm.OpcodeVecI8x16AddSatUName wasm.OpcodeVecI8x16AddSatSName
+// wasm.OpcodeVecI16x8AddSatUName wasm.OpcodeVecI16x8AddSatSName
+type OperationV128AddSat struct {
+	// Shape is either ShapeI8x16 or ShapeI16x8.
+	Shape  Shape
+	Signed bool
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128AddSat) Kind() OperationKind {
+	return OperationKindV128AddSat
+}
+
+// OperationV128SubSat implements Operation.
+//
+// This corresponds to wasm.OpcodeVecI8x16SubSatUName wasm.OpcodeVecI8x16SubSatSName
+// wasm.OpcodeVecI16x8SubSatUName wasm.OpcodeVecI16x8SubSatSName
+type OperationV128SubSat struct {
+	// Shape is either ShapeI8x16 or ShapeI16x8.
+	Shape  Shape
+	Signed bool
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128SubSat) Kind() OperationKind {
+	return OperationKindV128SubSat
+}
+
+// OperationV128Mul implements Operation.
+//
+// This corresponds to wasm.OpcodeVecF32x4MulName wasm.OpcodeVecF64x2MulName
+// wasm.OpcodeVecI16x8MulName wasm.OpcodeVecI32x4MulName wasm.OpcodeVecI64x2MulName.
+type OperationV128Mul struct {
+	// Shape is either ShapeI16x8, ShapeI32x4, ShapeI64x2, ShapeF32x4 or ShapeF64x2.
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Mul) Kind() OperationKind {
+	return OperationKindV128Mul
+}
+
+// OperationV128Div implements Operation.
+//
+// This corresponds to wasm.OpcodeVecF32x4DivName wasm.OpcodeVecF64x2DivName.
+type OperationV128Div struct {
+	// Shape is either ShapeF32x4 or ShapeF64x2.
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Div) Kind() OperationKind {
+	return OperationKindV128Div
+}
+
+// OperationV128Neg implements Operation.
+//
+// This corresponds to wasm.OpcodeVecI8x16NegName wasm.OpcodeVecI16x8NegName wasm.OpcodeVecI32x4NegName
+// wasm.OpcodeVecI64x2NegName wasm.OpcodeVecF32x4NegName wasm.OpcodeVecF64x2NegName.
+type OperationV128Neg struct {
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Neg) Kind() OperationKind {
+	return OperationKindV128Neg
+}
+
+// OperationV128Sqrt implements Operation.
+//
+// This corresponds to wasm.OpcodeVecF32x4SqrtName wasm.OpcodeVecF64x2SqrtName.
+type OperationV128Sqrt struct {
+	// Shape is either ShapeF32x4 or ShapeF64x2.
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Sqrt) Kind() OperationKind {
+	return OperationKindV128Sqrt
+}
+
+// OperationV128Abs implements Operation.
+//
+// This corresponds to wasm.OpcodeVecI8x16AbsName wasm.OpcodeVecI16x8AbsName wasm.OpcodeVecI32x4AbsName
+// wasm.OpcodeVecI64x2AbsName wasm.OpcodeVecF32x4AbsName wasm.OpcodeVecF64x2AbsName.
+type OperationV128Abs struct {
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Abs) Kind() OperationKind {
+	return OperationKindV128Abs
+}
+
+// OperationV128Popcnt implements Operation.
+//
+// This corresponds to wasm.OpcodeVecI8x16PopcntName.
+type OperationV128Popcnt struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Popcnt) Kind() OperationKind {
+	return OperationKindV128Popcnt
+}
+
+// OperationV128Min implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI8x16MinSName wasm.OpcodeVecI8x16MinUName wasm.OpcodeVecI16x8MinSName wasm.OpcodeVecI16x8MinUName
+//	wasm.OpcodeVecI32x4MinSName wasm.OpcodeVecI32x4MinUName
+//	wasm.OpcodeVecF32x4MinName wasm.OpcodeVecF64x2MinName
+type OperationV128Min struct {
+	Shape Shape
+	// Signed is only used for integer shapes.
+	Signed bool
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Min) Kind() OperationKind {
+	return OperationKindV128Min
+}
+
+// OperationV128Max implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI8x16MaxSName wasm.OpcodeVecI8x16MaxUName wasm.OpcodeVecI16x8MaxSName wasm.OpcodeVecI16x8MaxUName
+//	wasm.OpcodeVecI32x4MaxSName wasm.OpcodeVecI32x4MaxUName
+//	wasm.OpcodeVecF32x4MaxName wasm.OpcodeVecF64x2MaxName.
+type OperationV128Max struct {
+	Shape Shape
+	// Signed is only used for integer shapes.
+	Signed bool
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Max) Kind() OperationKind {
+	return OperationKindV128Max
+}
+
+// OperationV128AvgrU implements Operation.
+//
+// This corresponds to wasm.OpcodeVecI8x16AvgrUName wasm.OpcodeVecI16x8AvgrUName.
+type OperationV128AvgrU struct {
+	// Shape is either ShapeI8x16 or ShapeI16x8.
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128AvgrU) Kind() OperationKind {
+	return OperationKindV128AvgrU
+}
+
+// OperationV128Pmin implements Operation.
+//
+// This corresponds to wasm.OpcodeVecF32x4PminName wasm.OpcodeVecF64x2PminName.
+type OperationV128Pmin struct {
+	// Shape is either ShapeF32x4 or ShapeF64x2.
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Pmin) Kind() OperationKind {
+	return OperationKindV128Pmin
+}
+
+// OperationV128Pmax implements Operation.
+//
+// This corresponds to wasm.OpcodeVecF32x4PmaxName wasm.OpcodeVecF64x2PmaxName.
+type OperationV128Pmax struct {
+	// Shape is either ShapeF32x4 or ShapeF64x2.
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Pmax) Kind() OperationKind {
+	return OperationKindV128Pmax
+}
+
+// OperationV128Ceil implements Operation.
+//
+// This corresponds to wasm.OpcodeVecF32x4CeilName wasm.OpcodeVecF64x2CeilName
+type OperationV128Ceil struct {
+	// Shape is either ShapeF32x4 or ShapeF64x2.
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Ceil) Kind() OperationKind {
+	return OperationKindV128Ceil
+}
+
+// OperationV128Floor implements Operation.
+//
+// This corresponds to wasm.OpcodeVecF32x4FloorName wasm.OpcodeVecF64x2FloorName
+type OperationV128Floor struct {
+	// Shape is either ShapeF32x4 or ShapeF64x2.
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Floor) Kind() OperationKind {
+	return OperationKindV128Floor
+}
+
+// OperationV128Trunc implements Operation.
+//
+// This corresponds to wasm.OpcodeVecF32x4TruncName wasm.OpcodeVecF64x2TruncName
+type OperationV128Trunc struct {
+	// Shape is either ShapeF32x4 or ShapeF64x2.
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Trunc) Kind() OperationKind {
+	return OperationKindV128Trunc
+}
+
+// OperationV128Nearest implements Operation.
+//
+// This corresponds to wasm.OpcodeVecF32x4NearestName wasm.OpcodeVecF64x2NearestName
+type OperationV128Nearest struct {
+	// Shape is either ShapeF32x4 or ShapeF64x2.
+	Shape Shape
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Nearest) Kind() OperationKind {
+	return OperationKindV128Nearest
+}
+
+// OperationV128Extend implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI16x8ExtendLowI8x16SName wasm.OpcodeVecI16x8ExtendHighI8x16SName
+//	wasm.OpcodeVecI16x8ExtendLowI8x16UName wasm.OpcodeVecI16x8ExtendHighI8x16UName
+//	wasm.OpcodeVecI32x4ExtendLowI16x8SName wasm.OpcodeVecI32x4ExtendHighI16x8SName
+//	wasm.OpcodeVecI32x4ExtendLowI16x8UName wasm.OpcodeVecI32x4ExtendHighI16x8UName
+//	wasm.OpcodeVecI64x2ExtendLowI32x4SName wasm.OpcodeVecI64x2ExtendHighI32x4SName
+//	wasm.OpcodeVecI64x2ExtendLowI32x4UName wasm.OpcodeVecI64x2ExtendHighI32x4UName
+type OperationV128Extend struct {
+	// OriginShape is the shape of the original lanes for extension which is
+	// either ShapeI8x16, ShapeI16x8, or ShapeI32x4.
+	OriginShape Shape
+	Signed      bool
+	// UseLow true if it uses the lower half of vector for extension.
+	UseLow bool
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Extend) Kind() OperationKind {
+	return OperationKindV128Extend
+}
+
+// OperationV128ExtMul implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI16x8ExtMulLowI8x16SName wasm.OpcodeVecI16x8ExtMulLowI8x16UName
+//	wasm.OpcodeVecI16x8ExtMulHighI8x16SName wasm.OpcodeVecI16x8ExtMulHighI8x16UName
+//	wasm.OpcodeVecI32x4ExtMulLowI16x8SName wasm.OpcodeVecI32x4ExtMulLowI16x8UName
+//	wasm.OpcodeVecI32x4ExtMulHighI16x8SName wasm.OpcodeVecI32x4ExtMulHighI16x8UName
+//	wasm.OpcodeVecI64x2ExtMulLowI32x4SName wasm.OpcodeVecI64x2ExtMulLowI32x4UName
+//	wasm.OpcodeVecI64x2ExtMulHighI32x4SName wasm.OpcodeVecI64x2ExtMulHighI32x4UName.
+type OperationV128ExtMul struct {
+	// OriginShape is the shape of the original lanes for extension which is
+	// either ShapeI8x16, ShapeI16x8, or ShapeI32x4.
+	OriginShape Shape
+	Signed      bool
+	// UseLow true if it uses the lower half of vector for extension.
+	UseLow bool
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128ExtMul) Kind() OperationKind {
+	return OperationKindV128ExtMul
+}
+
+// OperationV128Q15mulrSatS implements Operation.
+//
+// This corresponds to wasm.OpcodeVecI16x8Q15mulrSatSName
+type OperationV128Q15mulrSatS struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Q15mulrSatS) Kind() OperationKind {
+	return OperationKindV128Q15mulrSatS
+}
+
+// OperationV128ExtAddPairwise implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI16x8ExtaddPairwiseI8x16SName wasm.OpcodeVecI16x8ExtaddPairwiseI8x16UName
+//	wasm.OpcodeVecI32x4ExtaddPairwiseI16x8SName wasm.OpcodeVecI32x4ExtaddPairwiseI16x8UName.
+type OperationV128ExtAddPairwise struct {
+	// OriginShape is the shape of the original lanes for extension which is
+	// either ShapeI8x16, or ShapeI16x8.
+	OriginShape Shape
+	Signed      bool
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128ExtAddPairwise) Kind() OperationKind {
+	return OperationKindV128ExtAddPairwise
+}
+
+// OperationV128FloatPromote implements Operation.
+//
+// This corresponds to wasm.OpcodeVecF64x2PromoteLowF32x4ZeroName
+// This discards the higher 64-bit of a vector, and promotes two
+// 32-bit floats in the lower 64-bit as two 64-bit floats.
+type OperationV128FloatPromote struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128FloatPromote) Kind() OperationKind {
+	return OperationKindV128FloatPromote
+}
+
+// OperationV128FloatDemote implements Operation.
+//
+// This corresponds to wasm.OpcodeVecF32x4DemoteF64x2ZeroName.
+type OperationV128FloatDemote struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128FloatDemote) Kind() OperationKind {
+	return OperationKindV128FloatDemote
+}
+
+// OperationV128FConvertFromI implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecF32x4ConvertI32x4SName wasm.OpcodeVecF32x4ConvertI32x4UName
+//	wasm.OpcodeVecF64x2ConvertLowI32x4SName wasm.OpcodeVecF64x2ConvertLowI32x4UName.
+type OperationV128FConvertFromI struct {
+	// DestinationShape is either ShapeF32x4 or ShapeF64x2.
+	DestinationShape Shape
+	Signed           bool
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128FConvertFromI) Kind() OperationKind {
+	return OperationKindV128FConvertFromI
+}
+
+// OperationV128Dot implements Operation.
+//
+// This corresponds to wasm.OpcodeVecI32x4DotI16x8SName
+type OperationV128Dot struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Dot) Kind() OperationKind {
+	return OperationKindV128Dot
+}
+
+// OperationV128Narrow implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI8x16NarrowI16x8SName wasm.OpcodeVecI8x16NarrowI16x8UName
+//	wasm.OpcodeVecI16x8NarrowI32x4SName wasm.OpcodeVecI16x8NarrowI32x4UName.
+type OperationV128Narrow struct {
+	// OriginShape is the shape of the original lanes for narrowing which is
+	// either ShapeI16x8, or ShapeI32x4.
+	OriginShape Shape
+	Signed      bool
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128Narrow) Kind() OperationKind {
+	return OperationKindV128Narrow
+}
+
+// OperationV128ITruncSatFromF implements Operation.
+//
+// This corresponds to
+//
+//	wasm.OpcodeVecI32x4TruncSatF64x2UZeroName wasm.OpcodeVecI32x4TruncSatF64x2SZeroName
+//	wasm.OpcodeVecI32x4TruncSatF32x4UName wasm.OpcodeVecI32x4TruncSatF32x4SName.
+type OperationV128ITruncSatFromF struct {
+	// OriginShape is either ShapeF32x4 or ShapeF64x2.
+	OriginShape Shape
+	Signed      bool
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationV128ITruncSatFromF) Kind() OperationKind {
+	return OperationKindV128ITruncSatFromF
+}
+
+// AtomicArithmeticOp is the arithmetic of OperationAtomicRMW, ordered as the opcodes.
+type AtomicArithmeticOp byte
+
+const (
+	AtomicArithmeticOpAdd AtomicArithmeticOp = iota
+	AtomicArithmeticOpSub
+	AtomicArithmeticOpAnd
+	AtomicArithmeticOpOr
+	AtomicArithmeticOpXor
+	// AtomicArithmeticOpXchg replaces the value with the operand.
+	AtomicArithmeticOpXchg
+)
+
+func (a AtomicArithmeticOp) String() (ret string) {
+	switch a {
+	case AtomicArithmeticOpAdd:
+		ret = "add"
+	case AtomicArithmeticOpSub:
+		ret = "sub"
+	case AtomicArithmeticOpAnd:
+		ret = "and"
+	case AtomicArithmeticOpOr:
+		ret = "or"
+	case AtomicArithmeticOpXor:
+		ret = "xor"
+	case AtomicArithmeticOpXchg:
+		ret = "xchg"
+	}
+	return
+}
+
+// Apply returns the value to replace old with when the operand of the instruction is v.
+func (a AtomicArithmeticOp) Apply(old, v uint64) uint64 {
+	switch a {
+	case AtomicArithmeticOpAdd:
+		return old + v
+	case AtomicArithmeticOpSub:
+		return old - v
+	case AtomicArithmeticOpAnd:
+		return old & v
+	case AtomicArithmeticOpOr:
+		return old | v
+	case AtomicArithmeticOpXor:
+		return old ^ v
+	default: // AtomicArithmeticOpXchg
+		return v
+	}
+}
+
+// OperationAtomicLoad implements Operation.
+//
+// This corresponds to wasm.OpcodeAtomicI32LoadName wasm.OpcodeAtomicI64LoadName and their narrower variants which
+// zero-extend the loaded value.
+type OperationAtomicLoad struct {
+	// Type is the type of the pushed value.
+	Type UnsignedInt
+	// SizeInBytes is the size of the loaded value which is 1, 2, 4 or 8.
+	SizeInBytes uint32
+	Arg         *MemoryImmediate
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationAtomicLoad) Kind() OperationKind {
+	return OperationKindAtomicLoad
+}
+
+// OperationAtomicStore implements Operation.
+//
+// This corresponds to wasm.OpcodeAtomicI32StoreName wasm.OpcodeAtomicI64StoreName and their narrower variants which
+// store the lowest bits of the value.
+type OperationAtomicStore struct {
+	// Type is the type of the popped value.
+	Type UnsignedInt
+	// SizeInBytes is the size of the stored value which is 1, 2, 4 or 8.
+	SizeInBytes uint32
+	Arg         *MemoryImmediate
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationAtomicStore) Kind() OperationKind {
+	return OperationKindAtomicStore
+}
+
+// OperationAtomicRMW implements Operation.
+//
+// This corresponds to the atomic read-modify-write instructions except for cmpxchg, for example
+// wasm.OpcodeAtomicI32RmwAddName or wasm.OpcodeAtomicI64Rmw8XchgUName. The old value is pushed zero-extended.
+type OperationAtomicRMW struct {
+	// Type is the type of the operand and the pushed value.
+	Type UnsignedInt
+	// SizeInBytes is the size of the modified value which is 1, 2, 4 or 8.
+	SizeInBytes uint32
+	Op          AtomicArithmeticOp
+	Arg         *MemoryImmediate
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationAtomicRMW) Kind() OperationKind {
+	return OperationKindAtomicRMW
+}
+
+// OperationAtomicRMWCmpxchg implements Operation.
+//
+// This corresponds to wasm.OpcodeAtomicI32RmwCmpxchgName wasm.OpcodeAtomicI64RmwCmpxchgName and their narrower
+// variants. The old value is pushed zero-extended.
+type OperationAtomicRMWCmpxchg struct {
+	// Type is the type of the operands and the pushed value.
+	Type UnsignedInt
+	// SizeInBytes is the size of the modified value which is 1, 2, 4 or 8.
+	SizeInBytes uint32
+	Arg         *MemoryImmediate
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationAtomicRMWCmpxchg) Kind() OperationKind {
+	return OperationKindAtomicRMWCmpxchg
+}
+
+// OperationAtomicMemoryWait implements Operation.
+//
+// This corresponds to wasm.OpcodeAtomicMemoryWait32Name wasm.OpcodeAtomicMemoryWait64Name.
+type OperationAtomicMemoryWait struct {
+	// Type is the type of the expected value.
+	Type UnsignedInt
+	Arg  *MemoryImmediate
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationAtomicMemoryWait) Kind() OperationKind {
+	return OperationKindAtomicMemoryWait
+}
+
+// OperationAtomicMemoryNotify implements Operation.
+//
+// This corresponds to wasm.OpcodeAtomicMemoryNotifyName.
+type OperationAtomicMemoryNotify struct {
+	Arg *MemoryImmediate
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationAtomicMemoryNotify) Kind() OperationKind {
+	return OperationKindAtomicMemoryNotify
+}
+
+// OperationAtomicFence implements Operation.
+//
+// This corresponds to wasm.OpcodeAtomicFenceName.
+type OperationAtomicFence struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationAtomicFence) Kind() OperationKind {
+	return OperationKindAtomicFence
+}
+
+// OperationTry implements Operation.
+//
+// This corresponds to wasm.OpcodeTryName, and begins the protected region of the try block identified by FrameID.
+// Exceptions raised in the region, including those raised by callees, are handled by the try block until the
+// region is ended by OperationCatch or OperationDelegate of the same FrameID.
+type OperationTry struct {
+	FrameID uint32
+	// Outer is the FrameID of the innermost enclosing try block whose protected region contains this one, or nil
+	// when exceptions not caught by this try block propagate to the caller.
+	Outer *uint32
+	// StackHeight is the height of the value stack in uint64 below the params of the try block. Handlers are
+	// entered with the value stack truncated to this height.
+	StackHeight int
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationTry) Kind() OperationKind {
+	return OperationKindTry
+}
+
+// OperationCatch implements Operation.
+//
+// This corresponds to wasm.OpcodeCatchName and wasm.OpcodeCatchAllName, and begins a handler of the try block
+// identified by FrameID. The first handler of a try block also ends its protected region.
+//
+// Handlers are never reached by branches. Instead, the engine enters them when unwinding an exception with the value
+// stack truncated to OperationTry.StackHeight, followed by the i64 handle of the caught exception and, unless
+// CatchAll, the values of the exception.
+type OperationCatch struct {
+	FrameID, TagIndex uint32
+	CatchAll          bool
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationCatch) Kind() OperationKind {
+	return OperationKindCatch
+}
+
+// OperationDelegate implements Operation.
+//
+// This corresponds to wasm.OpcodeDelegateName, and ends the protected region of the try block identified by FrameID
+// which has no handlers. This is also emitted for a try block without handlers which ends with wasm.OpcodeEndName.
+type OperationDelegate struct {
+	FrameID uint32
+	// Target is the FrameID of the try block which handles the exceptions raised in the region, or nil when they
+	// propagate to the caller.
+	Target *uint32
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationDelegate) Kind() OperationKind {
+	return OperationKindDelegate
+}
+
+// OperationThrow implements Operation.
+//
+// This corresponds to wasm.OpcodeThrowName, and pops the values of the tag at TagIndex to raise an exception.
+type OperationThrow struct {
+	TagIndex uint32
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationThrow) Kind() OperationKind {
+	return OperationKindThrow
+}
+
+// OperationRethrow implements Operation.
+//
+// This corresponds to wasm.OpcodeRethrowName, and pops the handle of a caught exception to raise it again. The
+// handle is copied to the top of the stack with OperationPick beforehand.
+type OperationRethrow struct{}
+
+// Kind implements Operation.Kind.
+func (o *OperationRethrow) Kind() OperationKind {
+	return OperationKindRethrow
+}
+
+// OperationConsumeFuel implements Operation.
+//
+// This is emitted at the beginning of the function and of each label when wasm.Module FuelCosts are set, and consumes
+// Cost, which is the sum of the costs of the Wasm instructions until the next label. If the remaining fuel is less
+// than Cost, the engine raises wasmruntime.ErrRuntimeFuelExhausted instead.
+type OperationConsumeFuel struct {
+	Cost uint64
+}
+
+// Kind implements Operation.Kind.
+func (o *OperationConsumeFuel) Kind() OperationKind {
+	return OperationKindConsumeFuel
+}
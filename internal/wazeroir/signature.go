@@ -1,8 +1,10 @@
 package wazeroir
 
 import (
+	"bytes"
 	"fmt"
 
+	"github.com/tetratelabs/wazero/internal/leb128"
 	"github.com/tetratelabs/wazero/internal/wasm"
 )
 
@@ -150,6 +152,26 @@ var (
 	signature_I32I64I32_None = &signature{
 		in: []UnsignedType{UnsignedTypeI32, UnsignedTypeI64, UnsignedTypeI32},
 	}
+	signature_I32I64_I64 = &signature{
+		in:  []UnsignedType{UnsignedTypeI32, UnsignedTypeI64},
+		out: []UnsignedType{UnsignedTypeI64},
+	}
+	signature_I32I32I32_I32 = &signature{
+		in:  []UnsignedType{UnsignedTypeI32, UnsignedTypeI32, UnsignedTypeI32},
+		out: []UnsignedType{UnsignedTypeI32},
+	}
+	signature_I32I64I64_I64 = &signature{
+		in:  []UnsignedType{UnsignedTypeI32, UnsignedTypeI64, UnsignedTypeI64},
+		out: []UnsignedType{UnsignedTypeI64},
+	}
+	signature_I32I32I64_I32 = &signature{
+		in:  []UnsignedType{UnsignedTypeI32, UnsignedTypeI32, UnsignedTypeI64},
+		out: []UnsignedType{UnsignedTypeI32},
+	}
+	signature_I32I64I64_I32 = &signature{
+		in:  []UnsignedType{UnsignedTypeI32, UnsignedTypeI64, UnsignedTypeI64},
+		out: []UnsignedType{UnsignedTypeI32},
+	}
 	signature_UnknownUnknownI32_Unknown = &signature{
 		in:  []UnsignedType{UnsignedTypeUnknown, UnsignedTypeUnknown, UnsignedTypeI32},
 		out: []UnsignedType{UnsignedTypeUnknown},
@@ -158,6 +180,69 @@ var (
 		in:  []UnsignedType{UnsignedTypeV128, UnsignedTypeV128},
 		out: []UnsignedType{UnsignedTypeV128},
 	}
+	signature_V128V128V128_V128 = &signature{
+		in:  []UnsignedType{UnsignedTypeV128, UnsignedTypeV128, UnsignedTypeV128},
+		out: []UnsignedType{UnsignedTypeV128},
+	}
+	signature_I32_V128 = &signature{
+		in:  []UnsignedType{UnsignedTypeI32},
+		out: []UnsignedType{UnsignedTypeV128},
+	}
+	signature_I32V128_V128 = &signature{
+		in:  []UnsignedType{UnsignedTypeI32, UnsignedTypeV128},
+		out: []UnsignedType{UnsignedTypeV128},
+	}
+	signature_I32V128_None = &signature{
+		in: []UnsignedType{UnsignedTypeI32, UnsignedTypeV128},
+	}
+	signature_I64_V128 = &signature{
+		in:  []UnsignedType{UnsignedTypeI64},
+		out: []UnsignedType{UnsignedTypeV128},
+	}
+	signature_F32_V128 = &signature{
+		in:  []UnsignedType{UnsignedTypeF32},
+		out: []UnsignedType{UnsignedTypeV128},
+	}
+	signature_F64_V128 = &signature{
+		in:  []UnsignedType{UnsignedTypeF64},
+		out: []UnsignedType{UnsignedTypeV128},
+	}
+	signature_V128_V128 = &signature{
+		in:  []UnsignedType{UnsignedTypeV128},
+		out: []UnsignedType{UnsignedTypeV128},
+	}
+	signature_V128_I32 = &signature{
+		in:  []UnsignedType{UnsignedTypeV128},
+		out: []UnsignedType{UnsignedTypeI32},
+	}
+	signature_V128_I64 = &signature{
+		in:  []UnsignedType{UnsignedTypeV128},
+		out: []UnsignedType{UnsignedTypeI64},
+	}
+	signature_V128_F32 = &signature{
+		in:  []UnsignedType{UnsignedTypeV128},
+		out: []UnsignedType{UnsignedTypeF32},
+	}
+	signature_V128_F64 = &signature{
+		in:  []UnsignedType{UnsignedTypeV128},
+		out: []UnsignedType{UnsignedTypeF64},
+	}
+	signature_V128I32_V128 = &signature{
+		in:  []UnsignedType{UnsignedTypeV128, UnsignedTypeI32},
+		out: []UnsignedType{UnsignedTypeV128},
+	}
+	signature_V128I64_V128 = &signature{
+		in:  []UnsignedType{UnsignedTypeV128, UnsignedTypeI64},
+		out: []UnsignedType{UnsignedTypeV128},
+	}
+	signature_V128F32_V128 = &signature{
+		in:  []UnsignedType{UnsignedTypeV128, UnsignedTypeF32},
+		out: []UnsignedType{UnsignedTypeV128},
+	}
+	signature_V128F64_V128 = &signature{
+		in:  []UnsignedType{UnsignedTypeV128, UnsignedTypeF64},
+		out: []UnsignedType{UnsignedTypeV128},
+	}
 )
 
 // wasmOpcodeSignature returns the signature of given Wasm opcode.
@@ -173,6 +258,11 @@ func (c *compiler) wasmOpcodeSignature(op wasm.Opcode, index uint32) (*signature
 		return signature_I32_None, nil
 	case wasm.OpcodeElse, wasm.OpcodeEnd, wasm.OpcodeBr:
 		return signature_None_None, nil
+	case wasm.OpcodeTry, wasm.OpcodeCatch, wasm.OpcodeCatchAll, wasm.OpcodeDelegate, wasm.OpcodeRethrow:
+		// The stack of handlers is manipulated when handling the instructions.
+		return signature_None_None, nil
+	case wasm.OpcodeThrow:
+		return &signature{in: funcTypeToSignature(c.tags[index]).in}, nil
 	case wasm.OpcodeBrIf, wasm.OpcodeBrTable:
 		return signature_I32_None, nil
 	case wasm.OpcodeReturn:
@@ -183,6 +273,13 @@ func (c *compiler) wasmOpcodeSignature(op wasm.Opcode, index uint32) (*signature
 		ret := funcTypeToSignature(c.types[index])
 		ret.in = append(ret.in, UnsignedTypeI32)
 		return ret, nil
+	case wasm.OpcodeReturnCall:
+		// The results are returned from the current function, so nothing is pushed.
+		return &signature{in: funcTypeToSignature(c.types[c.funcs[index]]).in}, nil
+	case wasm.OpcodeReturnCallIndirect:
+		ret := &signature{in: funcTypeToSignature(c.types[index]).in}
+		ret.in = append(ret.in, UnsignedTypeI32)
+		return ret, nil
 	case wasm.OpcodeDrop:
 		return signature_Unknown_None, nil
 	case wasm.OpcodeSelect, wasm.OpcodeTypedSelect:
@@ -408,19 +505,267 @@ func (c *compiler) wasmOpcodeSignature(op wasm.Opcode, index uint32) (*signature
 			return nil, fmt.Errorf("unsupported misc instruction in wazeroir: 0x%x", op)
 		}
 	case wasm.OpcodeVecPrefix:
-		switch vecOp := c.body[c.pc+1]; vecOp {
+		vecOp, _, err := leb128.DecodeUint32(bytes.NewReader(c.body[c.pc+1:]))
+		if err != nil {
+			return nil, fmt.Errorf("reading vector instruction: %w", err)
+		}
+		switch wasm.OpcodeVec(vecOp) {
 		case wasm.OpcodeVecV128Const:
 			return signature_None_V128, nil
-		case wasm.OpcodeVecI32x4Add, wasm.OpcodeVecI64x2Add:
+		case wasm.OpcodeVecV128Load, wasm.OpcodeVecV128Load8x8S, wasm.OpcodeVecV128Load8x8U,
+			wasm.OpcodeVecV128Load16x4S, wasm.OpcodeVecV128Load16x4U, wasm.OpcodeVecV128Load32x2S,
+			wasm.OpcodeVecV128Load32x2U, wasm.OpcodeVecV128Load8Splat, wasm.OpcodeVecV128Load16Splat,
+			wasm.OpcodeVecV128Load32Splat, wasm.OpcodeVecV128Load64Splat, wasm.OpcodeVecV128Load32zero,
+			wasm.OpcodeVecV128Load64zero:
+			return signature_I32_V128, nil
+		case wasm.OpcodeVecV128Load8Lane, wasm.OpcodeVecV128Load16Lane,
+			wasm.OpcodeVecV128Load32Lane, wasm.OpcodeVecV128Load64Lane:
+			return signature_I32V128_V128, nil
+		case wasm.OpcodeVecV128Store,
+			wasm.OpcodeVecV128Store8Lane,
+			wasm.OpcodeVecV128Store16Lane,
+			wasm.OpcodeVecV128Store32Lane,
+			wasm.OpcodeVecV128Store64Lane:
+			return signature_I32V128_None, nil
+		case wasm.OpcodeVecI8x16ExtractLaneS,
+			wasm.OpcodeVecI8x16ExtractLaneU,
+			wasm.OpcodeVecI16x8ExtractLaneS,
+			wasm.OpcodeVecI16x8ExtractLaneU,
+			wasm.OpcodeVecI32x4ExtractLane:
+			return signature_V128_I32, nil
+		case wasm.OpcodeVecI64x2ExtractLane:
+			return signature_V128_I64, nil
+		case wasm.OpcodeVecF32x4ExtractLane:
+			return signature_V128_F32, nil
+		case wasm.OpcodeVecF64x2ExtractLane:
+			return signature_V128_F64, nil
+		case wasm.OpcodeVecI8x16ReplaceLane, wasm.OpcodeVecI16x8ReplaceLane, wasm.OpcodeVecI32x4ReplaceLane,
+			wasm.OpcodeVecI8x16Shl, wasm.OpcodeVecI8x16ShrS, wasm.OpcodeVecI8x16ShrU,
+			wasm.OpcodeVecI16x8Shl, wasm.OpcodeVecI16x8ShrS, wasm.OpcodeVecI16x8ShrU,
+			wasm.OpcodeVecI32x4Shl, wasm.OpcodeVecI32x4ShrS, wasm.OpcodeVecI32x4ShrU,
+			wasm.OpcodeVecI64x2Shl, wasm.OpcodeVecI64x2ShrS, wasm.OpcodeVecI64x2ShrU:
+			return signature_V128I32_V128, nil
+		case wasm.OpcodeVecI64x2ReplaceLane:
+			return signature_V128I64_V128, nil
+		case wasm.OpcodeVecF32x4ReplaceLane:
+			return signature_V128F32_V128, nil
+		case wasm.OpcodeVecF64x2ReplaceLane:
+			return signature_V128F64_V128, nil
+		case wasm.OpcodeVecI8x16Splat,
+			wasm.OpcodeVecI16x8Splat,
+			wasm.OpcodeVecI32x4Splat:
+			return signature_I32_V128, nil
+		case wasm.OpcodeVecI64x2Splat:
+			return signature_I64_V128, nil
+		case wasm.OpcodeVecF32x4Splat:
+			return signature_F32_V128, nil
+		case wasm.OpcodeVecF64x2Splat:
+			return signature_F64_V128, nil
+		case wasm.OpcodeVecV128AnyTrue, wasm.OpcodeVecI8x16AllTrue, wasm.OpcodeVecI16x8AllTrue,
+			wasm.OpcodeVecI32x4AllTrue, wasm.OpcodeVecI64x2AllTrue,
+			wasm.OpcodeVecI8x16BitMask, wasm.OpcodeVecI16x8BitMask,
+			wasm.OpcodeVecI32x4BitMask, wasm.OpcodeVecI64x2BitMask:
+			return signature_V128_I32, nil
+		case wasm.OpcodeVecV128Bitselect:
+			return signature_V128V128V128_V128, nil
+		case wasm.OpcodeVecV128Not, wasm.OpcodeVecI8x16Abs, wasm.OpcodeVecI8x16Neg, wasm.OpcodeVecI8x16Popcnt,
+			wasm.OpcodeVecI16x8ExtaddPairwiseI8x16S, wasm.OpcodeVecI16x8ExtaddPairwiseI8x16U,
+			wasm.OpcodeVecI16x8Abs, wasm.OpcodeVecI16x8Neg,
+			wasm.OpcodeVecI16x8ExtendLowI8x16S, wasm.OpcodeVecI16x8ExtendHighI8x16S,
+			wasm.OpcodeVecI16x8ExtendLowI8x16U, wasm.OpcodeVecI16x8ExtendHighI8x16U,
+			wasm.OpcodeVecI32x4ExtaddPairwiseI16x8S, wasm.OpcodeVecI32x4ExtaddPairwiseI16x8U,
+			wasm.OpcodeVecI32x4Abs, wasm.OpcodeVecI32x4Neg,
+			wasm.OpcodeVecI32x4ExtendLowI16x8S, wasm.OpcodeVecI32x4ExtendHighI16x8S,
+			wasm.OpcodeVecI32x4ExtendLowI16x8U, wasm.OpcodeVecI32x4ExtendHighI16x8U,
+			wasm.OpcodeVecI64x2Abs, wasm.OpcodeVecI64x2Neg,
+			wasm.OpcodeVecI64x2ExtendLowI32x4S, wasm.OpcodeVecI64x2ExtendHighI32x4S,
+			wasm.OpcodeVecI64x2ExtendLowI32x4U, wasm.OpcodeVecI64x2ExtendHighI32x4U,
+			wasm.OpcodeVecF32x4Ceil, wasm.OpcodeVecF32x4Floor, wasm.OpcodeVecF32x4Trunc, wasm.OpcodeVecF32x4Nearest,
+			wasm.OpcodeVecF32x4Abs, wasm.OpcodeVecF32x4Neg, wasm.OpcodeVecF32x4Sqrt,
+			wasm.OpcodeVecF64x2Ceil, wasm.OpcodeVecF64x2Floor, wasm.OpcodeVecF64x2Trunc, wasm.OpcodeVecF64x2Nearest,
+			wasm.OpcodeVecF64x2Abs, wasm.OpcodeVecF64x2Neg, wasm.OpcodeVecF64x2Sqrt,
+			wasm.OpcodeVecI32x4TruncSatF32x4S, wasm.OpcodeVecI32x4TruncSatF32x4U,
+			wasm.OpcodeVecF32x4ConvertI32x4S, wasm.OpcodeVecF32x4ConvertI32x4U,
+			wasm.OpcodeVecI32x4TruncSatF64x2SZero, wasm.OpcodeVecI32x4TruncSatF64x2UZero,
+			wasm.OpcodeVecF64x2ConvertLowI32x4S, wasm.OpcodeVecF64x2ConvertLowI32x4U,
+			wasm.OpcodeVecF32x4DemoteF64x2Zero, wasm.OpcodeVecF64x2PromoteLowF32x4Zero:
+			return signature_V128_V128, nil
+		case wasm.OpcodeVecV128And, wasm.OpcodeVecV128Or, wasm.OpcodeVecV128Xor, wasm.OpcodeVecV128AndNot,
+			wasm.OpcodeVecV128i8x16Shuffle, wasm.OpcodeVecI8x16Swizzle,
+			wasm.OpcodeVecI8x16Eq, wasm.OpcodeVecI8x16Ne, wasm.OpcodeVecI8x16LtS, wasm.OpcodeVecI8x16LtU,
+			wasm.OpcodeVecI8x16GtS, wasm.OpcodeVecI8x16GtU, wasm.OpcodeVecI8x16LeS, wasm.OpcodeVecI8x16LeU,
+			wasm.OpcodeVecI8x16GeS, wasm.OpcodeVecI8x16GeU,
+			wasm.OpcodeVecI16x8Eq, wasm.OpcodeVecI16x8Ne, wasm.OpcodeVecI16x8LtS, wasm.OpcodeVecI16x8LtU,
+			wasm.OpcodeVecI16x8GtS, wasm.OpcodeVecI16x8GtU, wasm.OpcodeVecI16x8LeS, wasm.OpcodeVecI16x8LeU,
+			wasm.OpcodeVecI16x8GeS, wasm.OpcodeVecI16x8GeU,
+			wasm.OpcodeVecI32x4Eq, wasm.OpcodeVecI32x4Ne, wasm.OpcodeVecI32x4LtS, wasm.OpcodeVecI32x4LtU,
+			wasm.OpcodeVecI32x4GtS, wasm.OpcodeVecI32x4GtU, wasm.OpcodeVecI32x4LeS, wasm.OpcodeVecI32x4LeU,
+			wasm.OpcodeVecI32x4GeS, wasm.OpcodeVecI32x4GeU,
+			wasm.OpcodeVecI64x2Eq, wasm.OpcodeVecI64x2Ne, wasm.OpcodeVecI64x2LtS,
+			wasm.OpcodeVecI64x2GtS, wasm.OpcodeVecI64x2LeS, wasm.OpcodeVecI64x2GeS,
+			wasm.OpcodeVecF32x4Eq, wasm.OpcodeVecF32x4Ne, wasm.OpcodeVecF32x4Lt, wasm.OpcodeVecF32x4Gt,
+			wasm.OpcodeVecF32x4Le, wasm.OpcodeVecF32x4Ge,
+			wasm.OpcodeVecF64x2Eq, wasm.OpcodeVecF64x2Ne, wasm.OpcodeVecF64x2Lt, wasm.OpcodeVecF64x2Gt,
+			wasm.OpcodeVecF64x2Le, wasm.OpcodeVecF64x2Ge,
+			wasm.OpcodeVecI8x16Add, wasm.OpcodeVecI8x16AddSatS, wasm.OpcodeVecI8x16AddSatU, wasm.OpcodeVecI8x16Sub,
+			wasm.OpcodeVecI8x16SubSatS, wasm.OpcodeVecI8x16SubSatU,
+			wasm.OpcodeVecI16x8Add, wasm.OpcodeVecI16x8AddSatS, wasm.OpcodeVecI16x8AddSatU, wasm.OpcodeVecI16x8Sub,
+			wasm.OpcodeVecI16x8SubSatS, wasm.OpcodeVecI16x8SubSatU, wasm.OpcodeVecI16x8Mul,
+			wasm.OpcodeVecI32x4Add, wasm.OpcodeVecI32x4Sub, wasm.OpcodeVecI32x4Mul,
+			wasm.OpcodeVecI64x2Add, wasm.OpcodeVecI64x2Sub, wasm.OpcodeVecI64x2Mul,
+			wasm.OpcodeVecF32x4Add, wasm.OpcodeVecF32x4Sub, wasm.OpcodeVecF32x4Mul, wasm.OpcodeVecF32x4Div,
+			wasm.OpcodeVecF64x2Add, wasm.OpcodeVecF64x2Sub, wasm.OpcodeVecF64x2Mul, wasm.OpcodeVecF64x2Div,
+			wasm.OpcodeVecI8x16MinS, wasm.OpcodeVecI8x16MinU, wasm.OpcodeVecI8x16MaxS, wasm.OpcodeVecI8x16MaxU,
+			wasm.OpcodeVecI8x16AvgrU,
+			wasm.OpcodeVecI16x8MinS, wasm.OpcodeVecI16x8MinU, wasm.OpcodeVecI16x8MaxS, wasm.OpcodeVecI16x8MaxU,
+			wasm.OpcodeVecI16x8AvgrU,
+			wasm.OpcodeVecI32x4MinS, wasm.OpcodeVecI32x4MinU, wasm.OpcodeVecI32x4MaxS, wasm.OpcodeVecI32x4MaxU,
+			wasm.OpcodeVecF32x4Min, wasm.OpcodeVecF32x4Max, wasm.OpcodeVecF64x2Min, wasm.OpcodeVecF64x2Max,
+			wasm.OpcodeVecF32x4Pmin, wasm.OpcodeVecF32x4Pmax, wasm.OpcodeVecF64x2Pmin, wasm.OpcodeVecF64x2Pmax,
+			wasm.OpcodeVecI16x8Q15mulrSatS,
+			wasm.OpcodeVecI16x8ExtMulLowI8x16S,
+			wasm.OpcodeVecI16x8ExtMulHighI8x16S,
+			wasm.OpcodeVecI16x8ExtMulLowI8x16U,
+			wasm.OpcodeVecI16x8ExtMulHighI8x16U,
+			wasm.OpcodeVecI32x4ExtMulLowI16x8S,
+			wasm.OpcodeVecI32x4ExtMulHighI16x8S,
+			wasm.OpcodeVecI32x4ExtMulLowI16x8U,
+			wasm.OpcodeVecI32x4ExtMulHighI16x8U,
+			wasm.OpcodeVecI64x2ExtMulLowI32x4S,
+			wasm.OpcodeVecI64x2ExtMulHighI32x4S,
+			wasm.OpcodeVecI64x2ExtMulLowI32x4U,
+			wasm.OpcodeVecI64x2ExtMulHighI32x4U,
+			wasm.OpcodeVecI32x4DotI16x8S,
+			wasm.OpcodeVecI8x16NarrowI16x8S, wasm.OpcodeVecI8x16NarrowI16x8U,
+			wasm.OpcodeVecI16x8NarrowI32x4S, wasm.OpcodeVecI16x8NarrowI32x4U:
 			return signature_V128V128_V128, nil
 		default:
-			return nil, fmt.Errorf("unsupported vector instruction in wazeroir: 0x%x", op)
+			return nil, fmt.Errorf("unsupported vector instruction in wazeroir: %s", wasm.VectorInstructionName(wasm.OpcodeVec(vecOp)))
+		}
+	case wasm.OpcodeAtomicPrefix:
+		switch atomicOp := c.body[c.pc+1]; atomicOp {
+		case wasm.OpcodeAtomicMemoryNotify:
+			return signature_I32I32_I32, nil
+		case wasm.OpcodeAtomicMemoryWait32:
+			return signature_I32I32I64_I32, nil
+		case wasm.OpcodeAtomicMemoryWait64:
+			return signature_I32I64I64_I32, nil
+		case wasm.OpcodeAtomicFence:
+			return signature_None_None, nil
+		case wasm.OpcodeAtomicI32Load, wasm.OpcodeAtomicI32Load8U, wasm.OpcodeAtomicI32Load16U:
+			return signature_I32_I32, nil
+		case wasm.OpcodeAtomicI64Load, wasm.OpcodeAtomicI64Load8U, wasm.OpcodeAtomicI64Load16U,
+			wasm.OpcodeAtomicI64Load32U:
+			return signature_I32_I64, nil
+		case wasm.OpcodeAtomicI32Store, wasm.OpcodeAtomicI32Store8, wasm.OpcodeAtomicI32Store16:
+			return signature_I32I32_None, nil
+		case wasm.OpcodeAtomicI64Store, wasm.OpcodeAtomicI64Store8, wasm.OpcodeAtomicI64Store16,
+			wasm.OpcodeAtomicI64Store32:
+			return signature_I32I64_None, nil
+		case wasm.OpcodeAtomicI32RmwCmpxchg, wasm.OpcodeAtomicI32Rmw8CmpxchgU, wasm.OpcodeAtomicI32Rmw16CmpxchgU:
+			return signature_I32I32I32_I32, nil
+		case wasm.OpcodeAtomicI64RmwCmpxchg, wasm.OpcodeAtomicI64Rmw8CmpxchgU, wasm.OpcodeAtomicI64Rmw16CmpxchgU,
+			wasm.OpcodeAtomicI64Rmw32CmpxchgU:
+			return signature_I32I64I64_I64, nil
+		default:
+			if atomicOp < wasm.OpcodeAtomicI32RmwAdd || atomicOp > wasm.OpcodeAtomicI64Rmw32CmpxchgU {
+				return nil, fmt.Errorf("unsupported atomic instruction in wazeroir: 0x%x", atomicOp)
+			}
+			if _, _, isI64 := atomicRMWOperands(atomicOp); isI64 {
+				return signature_I32I64_I64, nil
+			}
+			return signature_I32I32_I32, nil
 		}
 	default:
 		return nil, fmt.Errorf("unsupported instruction in wazeroir: 0x%x", op)
 	}
 }
 
+// memory64Signature returns the signature of the memory instruction at the current pc with the addresses and sizes
+// turned into i64 when the instruction is on a memory indexed with i64. Otherwise, this returns the given signature.
+func (c *compiler) memory64Signature(op wasm.Opcode, s *signature) (*signature, error) {
+	r := bytes.NewReader(c.body[c.pc+1:])
+	// i64Operands are the indexes of the addresses and sizes in the signature, where the negative value means results.
+	var memoryIndex uint32
+	var i64Operands []int
+	var err error
+	switch {
+	case wasm.OpcodeI32Load <= op && op <= wasm.OpcodeI64Store32:
+		_, memoryIndex, _, err = wasm.DecodeMemArgAlignment(r, c.enabledFeatures)
+		i64Operands = []int{0}
+	case op == wasm.OpcodeMemorySize:
+		memoryIndex, _, err = leb128.DecodeUint32(r)
+		i64Operands = []int{-1}
+	case op == wasm.OpcodeMemoryGrow:
+		memoryIndex, _, err = leb128.DecodeUint32(r)
+		i64Operands = []int{0, -1}
+	case op == wasm.OpcodeVecPrefix:
+		var vecOp uint32
+		if vecOp, _, err = leb128.DecodeUint32(r); err != nil {
+			break
+		}
+		if vecOp <= uint32(wasm.OpcodeVecV128Store) ||
+			(uint32(wasm.OpcodeVecV128Load8Lane) <= vecOp && vecOp <= uint32(wasm.OpcodeVecV128Load64zero)) {
+			_, memoryIndex, _, err = wasm.DecodeMemArgAlignment(r, c.enabledFeatures)
+			i64Operands = []int{0}
+		}
+	case op == wasm.OpcodeAtomicPrefix:
+		if atomicOp, _ := r.ReadByte(); atomicOp != wasm.OpcodeAtomicFence {
+			_, memoryIndex, _, err = wasm.DecodeMemArgAlignment(r, c.enabledFeatures)
+			i64Operands = []int{0}
+		}
+	case op == wasm.OpcodeMiscPrefix:
+		switch miscOp, _ := r.ReadByte(); miscOp {
+		case wasm.OpcodeMiscMemoryInit:
+			if _, _, err = leb128.DecodeUint32(r); err == nil { // data index
+				memoryIndex, _, err = leb128.DecodeUint32(r)
+				i64Operands = []int{0}
+			}
+		case wasm.OpcodeMiscMemoryFill:
+			memoryIndex, _, err = leb128.DecodeUint32(r)
+			i64Operands = []int{0, 2}
+		case wasm.OpcodeMiscMemoryCopy:
+			// memory.copy is on two memories, so handle it separately.
+			var dst, src uint32
+			if dst, _, err = leb128.DecodeUint32(r); err != nil {
+				break
+			}
+			if src, _, err = leb128.DecodeUint32(r); err != nil {
+				break
+			}
+			dst64, src64 := c.isMemory64(dst), c.isMemory64(src)
+			ret := &signature{in: []UnsignedType{UnsignedTypeI32, UnsignedTypeI32, UnsignedTypeI32}}
+			if dst64 {
+				ret.in[0] = UnsignedTypeI64
+			}
+			if src64 {
+				ret.in[1] = UnsignedTypeI64
+			}
+			if dst64 && src64 {
+				ret.in[2] = UnsignedTypeI64
+			}
+			return ret, nil
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading memory index: %w", err)
+	}
+
+	if len(i64Operands) == 0 || !c.isMemory64(memoryIndex) {
+		return s, nil
+	}
+	// Copy the signature as it is shared.
+	ret := &signature{in: append([]UnsignedType{}, s.in...), out: append([]UnsignedType{}, s.out...)}
+	for _, i := range i64Operands {
+		if i < 0 {
+			ret.out[-1-i] = UnsignedTypeI64
+		} else {
+			ret.in[i] = UnsignedTypeI64
+		}
+	}
+	return ret, nil
+}
+
 func funcTypeToSignature(tps *wasm.FunctionType) *signature {
 	ret := &signature{}
 	for _, vt := range tps.Params {
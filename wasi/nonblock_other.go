@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd)
+
+package wasi
+
+import "io"
+
+// rawNonblockReader returns false as this platform doesn't portably read a file descriptor without waiting.
+func rawNonblockReader(io.Reader) (io.Reader, bool) {
+	return nil, false
+}
@@ -0,0 +1,51 @@
+//go:build linux || darwin || freebsd
+
+package wasi
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// rawNonblockReader returns a reader that reads from the file descriptor of r once, without waiting for it to be
+// readable, or false if r doesn't expose one, such as net.Pipe.
+//
+// Note: A file descriptor the Go runtime doesn't poll, such as os.Stdin of a terminal, is in blocking mode, so reads
+// can still wait for data.
+func rawNonblockReader(r io.Reader) (io.Reader, bool) {
+	sc, ok := r.(syscall.Conn)
+	if !ok {
+		return nil, false
+	}
+	rc, err := sc.SyscallConn()
+	if err != nil {
+		return nil, false
+	}
+	return &rawConnReader{rc: rc}, true
+}
+
+// rawConnReader implements io.Reader by reading from syscall.RawConn without waiting for data, returning
+// os.ErrDeadlineExceeded when there is none, like a read past its deadline.
+type rawConnReader struct {
+	rc syscall.RawConn
+}
+
+// Read implements io.Reader
+func (r *rawConnReader) Read(b []byte) (n int, err error) {
+	if rerr := r.rc.Read(func(fd uintptr) bool {
+		n, err = syscall.Read(int(fd), b)
+		return true // read once, instead of waiting for fd to be readable
+	}); rerr != nil {
+		return 0, rerr
+	}
+	switch {
+	case err == syscall.EAGAIN:
+		return 0, os.ErrDeadlineExceeded
+	case err != nil:
+		return 0, err
+	case n == 0 && len(b) > 0:
+		return 0, io.EOF
+	}
+	return n, nil
+}
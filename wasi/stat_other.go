@@ -0,0 +1,10 @@
+//go:build !(linux || darwin || freebsd)
+
+package wasi
+
+import "io/fs"
+
+// hostStat returns false as this platform doesn't portably expose the device and serial number of a file.
+func hostStat(fs.FileInfo) (dev, ino, nlink uint64, ok bool) {
+	return 0, 0, 0, false
+}
@@ -0,0 +1,16 @@
+//go:build linux || darwin || freebsd
+
+package wasi
+
+import (
+	"io/fs"
+	"syscall"
+)
+
+// hostStat returns the device, serial number and link count of a file in a host file system, such as sys.NewDirFS.
+func hostStat(st fs.FileInfo) (dev, ino, nlink uint64, ok bool) {
+	if s, ok := st.Sys().(*syscall.Stat_t); ok {
+		return uint64(s.Dev), uint64(s.Ino), uint64(s.Nlink), true
+	}
+	return 0, 0, 0, false
+}
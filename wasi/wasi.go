@@ -6,11 +6,18 @@ package wasi
 
 import (
 	"context"
+	"encoding/binary"
 	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"io/fs"
+	"math"
+	"net"
+	"os"
 	"path"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/tetratelabs/wazero"
@@ -338,7 +345,7 @@ const (
 	importPathUnlinkFile = `(import "wasi_snapshot_preview1" "path_unlink_file"
     (func $wasi.path_unlink_file (param $fd i32) (param $path i32) (param $path_len i32) (result (;errno;) i32)))`
 
-	// functionPollOneoff unlinks a file.
+	// functionPollOneoff concurrently polls for the occurrence of a set of events.
 	// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-poll_oneoffin-constpointersubscription-out-pointerevent-nsubscriptions-size---errno-size
 	functionPollOneoff = "poll_oneoff"
 
@@ -383,6 +390,14 @@ const (
 	importRandomGet = `(import "wasi_snapshot_preview1" "random_get"
     (func $wasi.random_get (param $buf i32) (param $buf_len i32) (result (;errno;) i32)))`
 
+	// functionSockAccept accepts a new incoming connection.
+	// See: https://github.com/WebAssembly/WASI/blob/main/phases/snapshot/docs.md#-sock_acceptfd-fd-flags-fdflags---resultfd-errno
+	functionSockAccept = "sock_accept"
+
+	// importSockAccept is the WebAssembly 1.0 (20191205) Text format import of functionSockAccept.
+	importSockAccept = `(import "wasi_snapshot_preview1" "sock_accept"
+    (func $wasi.sock_accept (param $fd i32) (param $flags i32) (param $result.fd i32) (result (;errno;) i32)))`
+
 	// functionSockRecv receives a message from a socket.
 	// See: https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-sock_recvfd-fd-ri_data-iovec_array-ri_flags-riflags---errno-size-roflags
 	functionSockRecv = "sock_recv"
@@ -488,6 +503,7 @@ func snapshotPreview1Functions(ctx context.Context) (a *snapshotPreview1, nameTo
 		functionProcRaise:            a.ProcRaise,
 		functionSchedYield:           a.SchedYield,
 		functionRandomGet:            a.RandomGet,
+		functionSockAccept:           a.SockAccept,
 		functionSockRecv:             a.SockRecv,
 		functionSockSend:             a.SockSend,
 		functionSockShutdown:         a.SockShutdown,
@@ -686,15 +702,7 @@ func (a *snapshotPreview1) ClockResGet(ctx context.Context, m api.Module, id uin
 // See https://linux.die.net/man/3/clock_gettime
 func (a *snapshotPreview1) ClockTimeGet(ctx context.Context, m api.Module, id uint32, precision uint64, resultTimestamp uint32) Errno {
 	// TODO: id and precision are currently ignored.
-	// Override Context when it is passed via context
-	clock := timeNowUnixNano
-	if clockVal := ctx.Value(sys.TimeNowUnixNanoKey{}); clockVal != nil {
-		clockCtx, ok := clockVal.(func() uint64)
-		if !ok {
-			panic(fmt.Errorf("unsupported clock key: %v", clockVal))
-		}
-		clock = clockCtx
-	}
+	clock := timeNowUnixNanoFunc(ctx)
 	if !m.Memory().WriteUint64Le(ctx, resultTimestamp, clock()) {
 		return ErrnoFault
 	}
@@ -706,9 +714,49 @@ func (a *snapshotPreview1) FdAdvise(ctx context.Context, m api.Module, fd uint32
 	return ErrnoNosys // stubbed for GrainLang per #271
 }
 
-// FdAllocate is the WASI function named functionFdAllocate and is stubbed for GrainLang per #271
+// FdAllocate is the WASI function named functionFdAllocate which forces the allocation of space in a file.
+//
+// * fd - the file descriptor of a file opened from a sys.WriteFS
+// * offset - the offset at which to start the allocation
+// * len - the length of the area that is allocated
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid or not a file
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to allocate space
+// * wasi.ErrnoInval - if `offset` + `len` overflows
+// * wasi.ErrnoFbig - if `offset` + `len` is larger than the maximum file size
+// * wasi.ErrnoRofs - if the file system of `fd` is read-only
+// * wasi.ErrnoIo - if the file couldn't be extended
+//
+// Note: importFdAllocate shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `posix_fallocate` in POSIX, except the file is extended with zeros, not reserved.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fd_allocatefd-fd-offset-filesize-len-filesize---errno
+// See https://linux.die.net/man/3/posix_fallocate
 func (a *snapshotPreview1) FdAllocate(ctx context.Context, m api.Module, fd uint32, offset, len uint64) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	f, _, errno := openedWriteFile(fsc, fd, rightFdAllocate)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+
+	size := offset + len
+	if size < offset {
+		return ErrnoInval
+	} else if size > math.MaxInt64 {
+		return ErrnoFbig
+	}
+
+	st, err := f.File.Stat()
+	if err != nil {
+		return toErrno(err)
+	}
+	if int64(size) > st.Size() {
+		if err = sys.TruncateFile(f.File, int64(size)); err != nil {
+			return toErrno(err)
+		}
+	}
+	return ErrnoSuccess
 }
 
 // FdClose is the WASI function to close a file descriptor. This returns ErrnoBadf if the fd is invalid.
@@ -731,9 +779,16 @@ func (a *snapshotPreview1) FdClose(ctx context.Context, m api.Module, fd uint32)
 	return ErrnoSuccess
 }
 
-// FdDatasync is the WASI function named functionFdDatasync and is stubbed for GrainLang per #271
+// FdDatasync is the WASI function named functionFdDatasync which synchronizes the data of a file to disk.
+//
+// This is the same as FdSync, as fs.File doesn't declare a way to synchronize only data.
+//
+// Note: importFdDatasync shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `fdatasync` in POSIX.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fd_datasyncfd-fd---errno
+// See https://linux.die.net/man/2/fdatasync
 func (a *snapshotPreview1) FdDatasync(ctx context.Context, m api.Module, fd uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	return fdSync(ctx, m, fd, rightFdDatasync)
 }
 
 // FdFdstatGet is the WASI function to return the attributes of a file descriptor.
@@ -744,6 +799,7 @@ func (a *snapshotPreview1) FdDatasync(ctx context.Context, m api.Module, fd uint
 // The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
 // * wasi.ErrnoBadf - if `fd` is invalid
 // * wasi.ErrnoFault - if `resultFdstat` contains an invalid offset due to the memory constraint
+// * wasi.ErrnoIo - if an IO related error happens reading the file type
 //
 // fdstat byte layout is 24-byte size, which as the following elements in order
 // * fs_filetype 1 byte, to indicate the file type
@@ -752,27 +808,58 @@ func (a *snapshotPreview1) FdDatasync(ctx context.Context, m api.Module, fd uint
 // * fs_right_base 8 bytes, to indicate the current rights of the fd
 // * fs_right_inheriting 8 bytes, to indicate the maximum rights of the fd
 //
-// For example, with a file corresponding with `fd` was a directory (=3) opened with `fd_read` right (=1) and no fs_flags (=0),
+// For example, with a file corresponding with `fd` was a directory (=3) opened with `fd_read` right (=2) and no fs_flags (=0),
 //    parameter resultFdstat=1, this function writes the below to `m.Memory`:
 //
 //                   uint16le   padding            uint64le                uint64le
 //          uint8 --+  +--+  +-----------+  +--------------------+  +--------------------+
 //                  |  |  |  |           |  |                    |  |                    |
-//        []byte{?, 3, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0}
+//        []byte{?, 3, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0, 2, 0, 0, 0, 0, 0, 0, 0}
 //   resultFdstat --^  ^-- fs_flags         ^-- fs_right_base       ^-- fs_right_inheriting
 //                  |
 //                  +-- fs_filetype
 //
 // Note: importFdFdstatGet shows this signature in the WebAssembly 1.0 (20191205) Text Format.
 // Note: FdFdstatGet returns similar flags to `fsync(fd, F_GETFL)` in POSIX, as well as additional fields.
+// Note: Standard input, output and error are character devices. They and files opened by the host, such as
+// pre-opened directories, have all rights.
 // See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#fdstat
 // See https://github.com/WebAssembly/WASI/blob/main/phases/snapshot/docs.md#fd_fdstat_get
 // See https://linux.die.net/man/3/fsync
 func (a *snapshotPreview1) FdFdstatGet(ctx context.Context, m api.Module, fd uint32, resultStat uint32) Errno {
 	_, fsc := sysFSCtx(ctx, m)
 
-	if _, ok := fsc.OpenedFile(fd); !ok {
-		return ErrnoBadf
+	var ft byte
+	var fdflags uint16
+	rights := sys.Rights{Base: rightsAll, Inheriting: rightsAll}
+	switch fd {
+	case fdStdin, fdStdout, fdStderr:
+		ft, fdflags = filetypeCharacterDevice, fsc.StdioFdflags(fd)
+	default:
+		f, ok := fsc.OpenedFile(fd)
+		if !ok {
+			return ErrnoBadf
+		}
+		if f.File == nil { // pre-opened directory
+			ft = filetypeDirectory
+		} else if st, err := f.File.Stat(); err != nil {
+			return toErrno(err)
+		} else {
+			ft = filetype(st.Mode().Type())
+		}
+		fdflags = f.Fdflags
+		if f.Rights != nil {
+			rights = *f.Rights
+		}
+	}
+
+	fdstat := make([]byte, 24)
+	fdstat[0] = ft
+	binary.LittleEndian.PutUint16(fdstat[2:], fdflags)
+	binary.LittleEndian.PutUint64(fdstat[8:], rights.Base)
+	binary.LittleEndian.PutUint64(fdstat[16:], rights.Inheriting)
+	if !m.Memory().Write(ctx, resultStat, fdstat) {
+		return ErrnoFault
 	}
 	return ErrnoSuccess
 }
@@ -808,7 +895,7 @@ func (a *snapshotPreview1) FdPrestatGet(ctx context.Context, m api.Module, fd ui
 	_, fsc := sysFSCtx(ctx, m)
 
 	entry, ok := fsc.OpenedFile(fd)
-	if !ok {
+	if !ok || entry.File != nil { // File is nil for a pre-opened directory
 		return ErrnoBadf
 	}
 
@@ -824,35 +911,274 @@ func (a *snapshotPreview1) FdPrestatGet(ctx context.Context, m api.Module, fd ui
 	return ErrnoSuccess
 }
 
-// FdFdstatSetFlags is the WASI function named functionFdFdstatSetFlags and is stubbed for GrainLang per #271
+// FdFdstatSetFlags is the WASI function named functionFdFdstatSetFlags which replaces the fdflags of a file
+// descriptor.
+//
+// * fd - the file descriptor to set the flags of
+// * flags - fdflags, a combination of fdflagsAppend, fdflagsDsync, fdflagsNonblock, fdflagsRsync and fdflagsSync
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoInval - if `flags` includes undefined flags
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to set its flags
+//
+// The flags have the following effects:
+// * fdflagsAppend - "fd_write" writes at the end of the file, if it is an io.Seeker
+// * fdflagsDsync, fdflagsSync - "fd_write" synchronizes the file after writing, if it implements `Sync() error`
+// * fdflagsNonblock - "fd_read" returns wasi.ErrnoAgain instead of waiting for data, if the file implements
+//   `SetReadDeadline(time.Time) error`, such as os.Stdin or a socket.
+// * fdflagsRsync - no effect, as reads are never cached
+//
+// Note: importFdFdstatSetFlags shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `fcntl(fd, F_SETFL, flags)` in POSIX.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fd_fdstat_set_flagsfd-fd-flags-fdflags---errno
+// See https://linux.die.net/man/3/fcntl
 func (a *snapshotPreview1) FdFdstatSetFlags(ctx context.Context, m api.Module, fd uint32, flags uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	if flags&^(fdflagsAppend|fdflagsDsync|fdflagsNonblock|fdflagsRsync|fdflagsSync) != 0 {
+		return ErrnoInval
+	}
+
+	if fsc.SetStdioFdflags(fd, uint16(flags)) {
+		return ErrnoSuccess
+	}
+
+	f, ok := fsc.OpenedFile(fd)
+	if !ok {
+		return ErrnoBadf
+	} else if !hasRights(f, rightFdFdstatSetFlags) {
+		return ErrnoNotcapable
+	}
+	f.Fdflags = uint16(flags)
+	return ErrnoSuccess
 }
 
-// FdFdstatSetRights implements snapshotPreview1.FdFdstatSetRights
-// Note: This will never be implemented per https://github.com/WebAssembly/WASI/issues/469#issuecomment-1045251844
+// FdFdstatSetRights is the WASI function named functionFdFdstatSetRights which removes rights from a file descriptor.
+//
+// * fd - the file descriptor to set the rights of
+// * fsRightsBase - the rights of operations using `fd`
+// * fsRightsInheriting - the maximum rights of file descriptors opened relative to `fd`
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoNotcapable - if the rights include any `fd` doesn't already have
+// * wasi.ErrnoNotsup - if `fd` is standard input, output or error, which always have all rights
+//
+// Note: importFdFdstatSetRights shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: Rights are removed after "wasi_snapshot_preview1" per
+// https://github.com/WebAssembly/WASI/issues/469#issuecomment-1045251844, but are enforced here, so that a file
+// descriptor can't be used for more than it was opened for.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fd_fdstat_set_rightsfd-fd-fs_rights_base-rights-fs_rights_inheriting-rights---errno
 func (a *snapshotPreview1) FdFdstatSetRights(ctx context.Context, m api.Module, fd uint32, fsRightsBase, fsRightsInheriting uint64) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	switch fd {
+	case fdStdin, fdStdout, fdStderr:
+		return ErrnoNotsup
+	}
+
+	f, ok := fsc.OpenedFile(fd)
+	if !ok {
+		return ErrnoBadf
+	} else if !hasRights(f, fsRightsBase) || fsRightsInheriting&^inheritingRights(f) != 0 {
+		return ErrnoNotcapable
+	}
+	f.Rights = &sys.Rights{Base: fsRightsBase, Inheriting: fsRightsInheriting}
+	return ErrnoSuccess
 }
 
-// FdFilestatGet is the WASI function named functionFdFilestatGet
+// FdFilestatGet is the WASI function named functionFdFilestatGet which returns the attributes of an open file.
+//
+// * fd - the file descriptor to get the filestat attributes of
+// * resultBuf - the offset in `m.Memory` to write the result filestat
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to get its attributes
+// * wasi.ErrnoFault - if `resultBuf` contains an invalid offset due to the memory constraint
+// * wasi.ErrnoIo - if an IO related error happens during the operation
+//
+// filestat byte layout is 64 bytes, which has the following elements in order:
+// * dev 8 bytes, the device ID of the file, which is always zero
+// * ino 8 bytes, the serial number of the file, which is stable for its path
+// * filetype 1 byte, followed by 7 pad bytes
+// * nlink 8 bytes, the number of hard links, which is always one
+// * size 8 bytes, the size of the file in bytes
+// * atim 8 bytes, the last access time in epoch nanoseconds, which is the same as mtim
+// * mtim 8 bytes, the last modification time in epoch nanoseconds
+// * ctim 8 bytes, the last status change time in epoch nanoseconds, which is the same as mtim
+//
+// Note: importFdFilestatGet shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `fstat` in POSIX.
+// Note: fs.FileInfo doesn't portably include devices, access times or link counts, so they are synthesized as above.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fd_filestat_getfd-fd---errno-filestat
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-filestat-struct
+// See https://linux.die.net/man/3/fstat
 func (a *snapshotPreview1) FdFilestatGet(ctx context.Context, m api.Module, fd uint32, resultBuf uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	f, ok := fsc.OpenedFile(fd)
+	if !ok || (f.File == nil && f.FS == nil) {
+		return ErrnoBadf
+	} else if !hasRights(f, rightFdFilestatGet) {
+		return ErrnoNotcapable
+	}
+
+	var st fs.FileInfo
+	var err error
+	if f.File == nil { // pre-opened directory
+		st, err = fs.Stat(f.FS, ".")
+	} else {
+		st, err = f.File.Stat()
+	}
+	if err != nil {
+		return toErrno(err)
+	}
+	return writeFilestat(ctx, m, resultBuf, f.Dev, entryPath(f), st)
 }
 
-// FdFilestatSetSize is the WASI function named functionFdFilestatSetSize
+// FdFilestatSetSize is the WASI function named functionFdFilestatSetSize which adjusts the size of an open file,
+// truncating or extending it with zeros.
+//
+// * fd - the file descriptor of a file opened from a sys.WriteFS
+// * size - the desired size of the file
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid or not a file
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to set its size
+// * wasi.ErrnoFbig - if `size` is larger than the maximum file size
+// * wasi.ErrnoRofs - if the file system of `fd` is read-only
+// * wasi.ErrnoIo - if the file couldn't be resized
+//
+// Note: importFdFilestatSetSize shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `ftruncate` in POSIX.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fd_filestat_set_sizefd-fd-size-filesize---errno
+// See https://linux.die.net/man/3/ftruncate
 func (a *snapshotPreview1) FdFilestatSetSize(ctx context.Context, m api.Module, fd uint32, size uint64) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	f, _, errno := openedWriteFile(fsc, fd, rightFdFilestatSetSize)
+	if errno != ErrnoSuccess {
+		return errno
+	} else if size > math.MaxInt64 {
+		return ErrnoFbig
+	}
+
+	if err := sys.TruncateFile(f.File, int64(size)); err != nil {
+		return toErrno(err)
+	}
+	return ErrnoSuccess
 }
 
-// FdFilestatSetTimes is the WASI function named functionFdFilestatSetTimes
+// FdFilestatSetTimes is the WASI function named functionFdFilestatSetTimes which adjusts the times of an open file.
+//
+// * fd - the file descriptor of a file opened from a sys.WriteFS
+// * atim - the desired access time in epoch nanoseconds, used when `fstFlags` includes fstflagsAtim
+// * mtim - the desired modification time in epoch nanoseconds, used when `fstFlags` includes fstflagsMtim
+// * fstFlags - a bitmask of fstflagsAtim, fstflagsAtimNow, fstflagsMtim and fstflagsMtimNow
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid or not a file
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to set its times
+// * wasi.ErrnoInval - if `fstFlags` sets a time both to a value and to now
+// * wasi.ErrnoRofs - if the file system of `fd` is read-only
+//
+// Note: importFdFilestatSetTimes shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `futimens` in POSIX.
+// See timesToSet
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fd_filestat_set_timesfd-fd-atim-timestamp-mtim-timestamp-fst_flags-fstflags---errno
+// See https://linux.die.net/man/3/futimens
 func (a *snapshotPreview1) FdFilestatSetTimes(ctx context.Context, m api.Module, fd uint32, atim, mtim uint64, fstFlags uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	f, _, errno := openedWriteFile(fsc, fd, rightFdFilestatSetTimes)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+
+	st, err := f.File.Stat()
+	if err != nil {
+		return toErrno(err)
+	}
+	atime, mtime, errno := timesToSet(ctx, st, atim, mtim, fstFlags)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+	if err = sys.ChtimesFile(f.File, atime, mtime); err != nil {
+		return toErrno(err)
+	}
+	return ErrnoSuccess
 }
 
-// FdPread is the WASI function named functionFdPread
+// FdPread is the WASI function named functionFdPread which reads from a file descriptor at an offset, without
+// changing its offset.
+//
+// * fd - an opened file descriptor to read data from
+// * iovs - the offset in `m.Memory` to read offset, size pairs representing where to write file data.
+//   * Both offset and length are encoded as uint32le.
+// * iovsCount - the count of memory offset, size pairs to read sequentially starting at iovs.
+// * offset - the offset in the file to start reading at
+// * resultNread - the offset in `m.Memory` to write the number of bytes read
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to read
+// * wasi.ErrnoFault - if `iovs` or `resultNread` contain an invalid offset due to the memory constraint
+// * wasi.ErrnoSpipe - if the file of `fd` doesn't implement io.ReaderAt
+// * wasi.ErrnoInval - if `offset` is larger than the maximum file offset, math.MaxInt64
+// * wasi.ErrnoIo - if an IO related error happens during the operation
+//
+// The iovs and result layout are the same as FdRead.
+//
+// Note: importFdPread shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `preadv` in POSIX.
+// See FdRead
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fd_preadfd-fd-iovs-iovec_array-offset-filesize---errno-size
+// See https://linux.die.net/man/2/preadv
 func (a *snapshotPreview1) FdPread(ctx context.Context, m api.Module, fd, iovs, iovsCount uint32, offset uint64, resultNread uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	var reader io.ReaderAt
+	if f, errno := openedFileWithRights(fsc, fd, rightFdRead); errno != ErrnoSuccess {
+		return errno
+	} else if r, ok := f.File.(io.ReaderAt); !ok {
+		// fs.FS doesn't declare io.ReaderAt, but implementations such as os.File implement it.
+		return ErrnoSpipe
+	} else {
+		reader = r
+	}
+	if offset > math.MaxInt64 {
+		return ErrnoInval
+	}
+
+	var nread uint32
+	for i := uint32(0); i < iovsCount; i++ {
+		iovPtr := iovs + i*8
+		iovOffset, ok := m.Memory().ReadUint32Le(ctx, iovPtr)
+		if !ok {
+			return ErrnoFault
+		}
+		l, ok := m.Memory().ReadUint32Le(ctx, iovPtr+4)
+		if !ok {
+			return ErrnoFault
+		}
+		b, ok := m.Memory().Read(ctx, iovOffset, l)
+		if !ok {
+			return ErrnoFault
+		}
+		n, err := reader.ReadAt(b, int64(offset)+int64(nread))
+		nread += uint32(n)
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return ErrnoIo
+		}
+	}
+	if !m.Memory().WriteUint32Le(ctx, resultNread, nread) {
+		return ErrnoFault
+	}
+	return ErrnoSuccess
 }
 
 // FdPrestatDirName is the WASI function to return the path of the pre-opened directory of a file descriptor.
@@ -863,7 +1189,7 @@ func (a *snapshotPreview1) FdPread(ctx context.Context, m api.Module, fd, iovs,
 //   * This should match the uint32le FdPrestatGet writes to offset `resultPrestat`+4
 //
 // The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
-// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoBadf - if `fd` is invalid or the `fd` is not a pre-opened directory.
 // * wasi.ErrnoFault - if `path` is an invalid offset due to the memory constraint
 // * wasi.ErrnoNametoolong - if `pathLen` is longer than the actual length of the result path
 //
@@ -883,7 +1209,7 @@ func (a *snapshotPreview1) FdPrestatDirName(ctx context.Context, m api.Module, f
 	_, fsc := sysFSCtx(ctx, m)
 
 	f, ok := fsc.OpenedFile(fd)
-	if !ok {
+	if !ok || f.File != nil { // File is nil for a pre-opened directory
 		return ErrnoBadf
 	}
 
@@ -892,16 +1218,78 @@ func (a *snapshotPreview1) FdPrestatDirName(ctx context.Context, m api.Module, f
 		return ErrnoNametoolong
 	}
 
-	// TODO: FdPrestatDirName may have to return ErrnoNotdir if the type of the prestat data of `fd` is not a PrestatDir.
 	if !m.Memory().Write(ctx, pathPtr, []byte(f.Path)[:pathLen]) {
 		return ErrnoFault
 	}
 	return ErrnoSuccess
 }
 
-// FdPwrite is the WASI function named functionFdPwrite
+// FdPwrite is the WASI function named functionFdPwrite which writes to a file descriptor at an offset, without
+// changing its offset.
+//
+// * fd - an opened file descriptor to write data to
+// * iovs - the offset in `m.Memory` to read offset, size pairs representing the data to write to `fd`
+//   * Both offset and length are encoded as uint32le.
+// * iovsCount - the count of memory offset, size pairs to read sequentially starting at iovs.
+// * offset - the offset in the file to start writing at
+// * resultNwritten - the offset in `m.Memory` to write the number of bytes written
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to write
+// * wasi.ErrnoFault - if `iovs` or `resultNwritten` contain an invalid offset due to the memory constraint
+// * wasi.ErrnoSpipe - if the file of `fd` doesn't implement io.WriterAt
+// * wasi.ErrnoInval - if `offset` is larger than the maximum file offset, math.MaxInt64
+// * wasi.ErrnoIo - if an IO related error happens during the operation
+//
+// The iovs and result layout are the same as FdWrite.
+//
+// Note: importFdPwrite shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `pwritev` in POSIX.
+// See FdWrite
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fd_pwritefd-fd-iovs-ciovec_array-offset-filesize---errno-size
+// See https://linux.die.net/man/2/pwritev
 func (a *snapshotPreview1) FdPwrite(ctx context.Context, m api.Module, fd, iovs, iovsCount uint32, offset uint64, resultNwritten uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	var writer io.WriterAt
+	if f, errno := openedFileWithRights(fsc, fd, rightFdWrite); errno != ErrnoSuccess {
+		return errno
+	} else if w, ok := f.File.(io.WriterAt); !ok {
+		// fs.FS doesn't declare io.WriterAt, but implementations such as os.File implement it.
+		return ErrnoSpipe
+	} else {
+		writer = w
+	}
+	if offset > math.MaxInt64 {
+		return ErrnoInval
+	}
+
+	var nwritten uint32
+	for i := uint32(0); i < iovsCount; i++ {
+		iovPtr := iovs + i*8
+		iovOffset, ok := m.Memory().ReadUint32Le(ctx, iovPtr)
+		if !ok {
+			return ErrnoFault
+		}
+		l, ok := m.Memory().ReadUint32Le(ctx, iovPtr+4)
+		if !ok {
+			return ErrnoFault
+		}
+		b, ok := m.Memory().Read(ctx, iovOffset, l)
+		if !ok {
+			return ErrnoFault
+		}
+		n, err := writer.WriteAt(b, int64(offset)+int64(nwritten))
+		if err != nil {
+			return ErrnoIo
+		}
+		nwritten += uint32(n)
+	}
+	if !m.Memory().WriteUint32Le(ctx, resultNwritten, nwritten) {
+		return ErrnoFault
+	}
+	return ErrnoSuccess
 }
 
 // FdRead is the WASI function to read from a file descriptor.
@@ -914,7 +1302,9 @@ func (a *snapshotPreview1) FdPwrite(ctx context.Context, m api.Module, fd, iovs,
 //
 // The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
 // * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to read
 // * wasi.ErrnoFault - if `iovs` or `resultSize` contain an invalid offset due to the memory constraint
+// * wasi.ErrnoAgain - if `fd` has fdflagsNonblock and no data is available, yet
 // * wasi.ErrnoIo - if an IO related error happens during the operation
 //
 // For example, this function needs to first read `iovs` to determine where to write contents. If
@@ -951,13 +1341,21 @@ func (a *snapshotPreview1) FdRead(ctx context.Context, m api.Module, fd, iovs, i
 	sys, fsc := sysFSCtx(ctx, m)
 
 	var reader io.Reader
+	var fdflags uint16
 
 	if fd == fdStdin {
-		reader = sys.Stdin()
-	} else if f, ok := fsc.OpenedFile(fd); !ok || f.File == nil {
-		return ErrnoBadf
+		reader, fdflags = sys.Stdin(), fsc.StdioFdflags(fd)
+	} else if f, errno := openedFileWithRights(fsc, fd, rightFdRead); errno != ErrnoSuccess {
+		return errno
 	} else {
-		reader = f.File
+		reader, fdflags = f.File, f.Fdflags
+	}
+
+	// fs.File doesn't declare non-blocking reads, but implementations such as os.File and net.Conn support them.
+	if fdflags&fdflagsNonblock != 0 {
+		var restore func()
+		reader, restore = nonblockReader(reader)
+		defer restore()
 	}
 
 	var nread uint32
@@ -979,6 +1377,11 @@ func (a *snapshotPreview1) FdRead(ctx context.Context, m api.Module, fd, iovs, i
 		nread += uint32(n)
 		if errors.Is(err, io.EOF) {
 			break
+		} else if errors.Is(err, os.ErrDeadlineExceeded) { // fdflagsNonblock
+			if nread == 0 {
+				return ErrnoAgain
+			}
+			break
 		} else if err != nil {
 			return ErrnoIo
 		}
@@ -989,9 +1392,114 @@ func (a *snapshotPreview1) FdRead(ctx context.Context, m api.Module, fd, iovs, i
 	return ErrnoSuccess
 }
 
-// FdReaddir is the WASI function named functionFdReaddir
+// FdReaddir is the WASI function named functionFdReaddir which reads directory entries from a directory.
+//
+// * fd - the file descriptor of a directory to read
+// * buf - the offset in `m.Memory` to write dirent entries to
+// * bufLen - the maximum count of bytes to write to `buf`
+// * cookie - the position to start reading from: zero for the first entry, or the `d_next` of the last entry read
+// * resultBufused - the offset in `m.Memory` to write the count of bytes written to `buf`
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to read its entries
+// * wasi.ErrnoFault - if `buf` or `resultBufused` contain an invalid offset due to the memory constraint
+// * wasi.ErrnoNotdir - if `fd` is not a directory
+// * wasi.ErrnoIo - if an IO related error happens during the operation
+//
+// dirent byte layout is 24 bytes followed by the name, which isn't NUL terminated:
+// * d_next 8 bytes, the cookie of the next entry
+// * d_ino 8 bytes, the serial number of the file
+// * d_namlen 4 bytes, the length of the name
+// * d_type 1 byte, the filetype of the entry
+// * 3 pad bytes
+//
+// The first two entries are "." and "..", followed by the entries of the directory. For example, with a directory
+//    corresponding with `fd` that only includes the regular file "a" and parameters buf=1 bufLen=25 cookie=2
+//    resultBufused=26, this function writes the below to `m.Memory`:
+//
+//                    uint64le                  uint64le          uint32le   uint8  padding   name   uint32le
+//          +----------------------+  +----------------------+  +--------+  +-+  +-----+  +-+  +--------+
+//          |                      |  |                      |  |        |  | |  |     |  | |  |        |
+//   []byte{?, 3, 0, 0, 0, 0, 0, 0, 0, i, i, i, i, i, i, i, i, 1, 0, 0, 0, 4, 0, 0, 0, 'a', 25, 0, 0, 0, ?}
+//      buf --^  ^-- d_next              ^-- d_ino                ^-- d_namlen   ^-- d_type      ^-- resultBufused
+//
+// Above, `i` are the bytes of the serial number of "a", which is stable for its path.
+//
+// When `bufLen` is too small to include all remaining entries, the last entry is truncated and `resultBufused` equals
+// `bufLen`. The caller should read again from the `d_next` of the last complete entry, using a larger buffer if that
+// entry wasn't complete.
+//
+// Note: importFdReaddir shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: Entries are sorted by name, and cookies are positions in that order. Entries are read when `cookie` is zero,
+// and cached on `fd` for later calls, so entries added or removed after that aren't seen until reading restarts.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fd_readdirfd-fd-buf-pointeru8-buf_len-size-cookie-dircookie---errno-size
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-dirent-struct
 func (a *snapshotPreview1) FdReaddir(ctx context.Context, m api.Module, fd, buf, bufLen uint32, cookie uint64, resultBufused uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	dir, ok := fsc.OpenedFile(fd)
+	if !ok || dir.FS == nil {
+		return ErrnoBadf
+	} else if !hasRights(dir, rightFdReaddir) {
+		return ErrnoNotcapable
+	}
+
+	mem := m.Memory()
+	if _, ok = mem.Read(ctx, buf, bufLen); !ok {
+		return ErrnoFault
+	}
+
+	dirPath := entryPath(dir)
+	if st, err := fs.Stat(dir.FS, dirPath); err != nil {
+		return toErrno(err)
+	} else if !st.IsDir() {
+		return ErrnoNotdir
+	}
+
+	// Like rewinddir, reading from the first entry refreshes the cached entries.
+	if cookie == 0 || dir.DirEntries == nil {
+		entries, err := fs.ReadDir(dir.FS, dirPath)
+		if err != nil {
+			return toErrno(err)
+		}
+		dir.DirEntries = entries
+	}
+	entries := dir.DirEntries
+
+	var dirents []byte
+	for i := cookie; i < uint64(len(entries))+2 && uint32(len(dirents)) < bufLen; i++ {
+		switch i {
+		case 0:
+			dirents = appendDirent(dirents, i+1, inode(dirPath), ".", filetypeDirectory)
+		case 1: // The parent of a pre-opened directory is outside the file system, so it is itself.
+			dirents = appendDirent(dirents, i+1, inode(path.Dir(dirPath)), "..", filetypeDirectory)
+		default:
+			e := entries[i-2]
+			dirents = appendDirent(dirents, i+1, direntIno(dirPath, e), e.Name(), filetype(e.Type()))
+		}
+	}
+	if uint32(len(dirents)) > bufLen {
+		dirents = dirents[:bufLen] // truncate the last entry
+	}
+
+	if !mem.Write(ctx, buf, dirents) {
+		return ErrnoFault
+	}
+	if !mem.WriteUint32Le(ctx, resultBufused, uint32(len(dirents))) {
+		return ErrnoFault
+	}
+	return ErrnoSuccess
+}
+
+// appendDirent appends the dirent of a directory entry to buf.
+func appendDirent(buf []byte, next, ino uint64, name string, ft byte) []byte {
+	dirent := make([]byte, direntLen, direntLen+len(name))
+	binary.LittleEndian.PutUint64(dirent, next)
+	binary.LittleEndian.PutUint64(dirent[8:], ino)
+	binary.LittleEndian.PutUint32(dirent[16:], uint32(len(name)))
+	dirent[20] = ft
+	return append(append(buf, dirent...), name...)
 }
 
 // FdRenumber is the WASI function named functionFdRenumber
@@ -1011,6 +1519,7 @@ func (a *snapshotPreview1) FdRenumber(ctx context.Context, m api.Module, fd, to
 //
 // The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
 // * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to seek
 // * wasi.ErrnoFault - if `resultNewoffset` is an invalid offset in `m.Memory` due to the memory constraint
 // * wasi.ErrnoInval - if `whence` is an invalid value
 // * wasi.ErrnoIo - if other error happens during the operation of the underying file system
@@ -1031,15 +1540,22 @@ func (a *snapshotPreview1) FdRenumber(ctx context.Context, m api.Module, fd, to
 // See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#fd_seek
 // See https://linux.die.net/man/3/lseek
 func (a *snapshotPreview1) FdSeek(ctx context.Context, m api.Module, fd uint32, offset uint64, whence uint32, resultNewoffset uint32) Errno {
+	return seek(ctx, m, fd, offset, whence, resultNewoffset, rightFdSeek)
+}
+
+// seek implements FdSeek and FdTell, which require different rights.
+func seek(ctx context.Context, m api.Module, fd uint32, offset uint64, whence uint32, resultNewoffset uint32, rights uint64) Errno {
 	_, fsc := sysFSCtx(ctx, m)
 
 	var seeker io.Seeker
 	// Check to see if the file descriptor is available
-	if f, ok := fsc.OpenedFile(fd); !ok || f.File == nil {
-		return ErrnoBadf
+	if f, errno := openedFileWithRights(fsc, fd, rights); errno != ErrnoSuccess {
+		return errno
+	} else if s, ok := f.File.(io.Seeker); !ok {
 		// fs.FS doesn't declare io.Seeker, but implementations such as os.File implement it.
-	} else if seeker, ok = f.File.(io.Seeker); !ok {
 		return ErrnoBadf
+	} else {
+		seeker = s
 	}
 
 	if whence > io.SeekEnd /* exceeds the largest valid whence */ {
@@ -1050,21 +1566,68 @@ func (a *snapshotPreview1) FdSeek(ctx context.Context, m api.Module, fd uint32,
 		return ErrnoIo
 	}
 
-	if !m.Memory().WriteUint32Le(ctx, resultNewoffset, uint32(newOffset)) {
+	if !m.Memory().WriteUint64Le(ctx, resultNewoffset, uint64(newOffset)) {
 		return ErrnoFault
 	}
 
 	return ErrnoSuccess
 }
 
-// FdSync is the WASI function named functionFdSync
-func (a *snapshotPreview1) FdSync(ctx context.Context, m api.Module, fd uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+// FdSync is the WASI function named functionFdSync which synchronizes the data and metadata of a file to disk.
+//
+// * fd - an opened file descriptor to synchronize
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to synchronize
+// * wasi.ErrnoIo - if an IO related error happens during the operation
+//
+// Note: importFdSync shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `fsync` in POSIX.
+// Note: Files that don't implement `Sync() error`, such as those in memory, have nothing to synchronize.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fd_syncfd-fd---errno
+// See https://linux.die.net/man/2/fsync
+func (a *snapshotPreview1) FdSync(ctx context.Context, m api.Module, fd uint32) Errno {
+	return fdSync(ctx, m, fd, rightFdSync)
+}
+
+// fdSync implements FdSync and FdDatasync, which only differ in the right they require.
+func fdSync(ctx context.Context, m api.Module, fd uint32, rights uint64) Errno {
+	_, fsc := sysFSCtx(ctx, m)
+
+	f, errno := openedFileWithRights(fsc, fd, rights)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+
+	// fs.File doesn't declare Sync, but implementations such as os.File implement it.
+	if syncer, ok := f.File.(interface{ Sync() error }); ok {
+		if err := syncer.Sync(); err != nil {
+			return toErrno(err)
+		}
+	}
+	return ErrnoSuccess
 }
 
-// FdTell is the WASI function named functionFdTell
+// FdTell is the WASI function named functionFdTell which returns the current offset of a file descriptor.
+//
+// * fd - the file descriptor to get the offset of
+// * resultOffset - the offset in `m.Memory` to write the current offset to, relative to start of the file
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to tell its offset
+// * wasi.ErrnoFault - if `resultOffset` is an invalid offset in `m.Memory` due to the memory constraint
+// * wasi.ErrnoIo - if other error happens during the operation of the underying file system
+//
+// The result layout is the same as FdSeek.
+//
+// Note: importFdTell shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is the same as FdSeek with a zero offset and io.SeekCurrent, which is how `lseek` in POSIX is used.
+// See FdSeek
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fd_tellfd-fd---errno-filesize
 func (a *snapshotPreview1) FdTell(ctx context.Context, m api.Module, fd, resultOffset uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	return seek(ctx, m, fd, 0, io.SeekCurrent, resultOffset, rightFdTell)
 }
 
 // FdWrite is the WASI function to write to a file descriptor.
@@ -1077,6 +1640,7 @@ func (a *snapshotPreview1) FdTell(ctx context.Context, m api.Module, fd, resultO
 //
 // The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
 // * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to write
 // * wasi.ErrnoFault - if `iovs` or `resultSize` contain an invalid offset due to the memory constraint
 // * wasi.ErrnoIo - if an IO related error happens during the operation
 //
@@ -1120,6 +1684,7 @@ func (a *snapshotPreview1) FdWrite(ctx context.Context, m api.Module, fd, iovs,
 	sys, fsc := sysFSCtx(ctx, m)
 
 	var writer io.Writer
+	var fdflags uint16
 
 	switch fd {
 	case fdStdout:
@@ -1128,12 +1693,22 @@ func (a *snapshotPreview1) FdWrite(ctx context.Context, m api.Module, fd, iovs,
 		writer = sys.Stderr()
 	default:
 		// Check to see if the file descriptor is available
-		if f, ok := fsc.OpenedFile(fd); !ok || f.File == nil {
-			return ErrnoBadf
-			// fs.FS doesn't declare io.Writer, but implementations such as os.File implement it.
-		} else if writer, ok = f.File.(io.Writer); !ok {
+		f, errno := openedFileWithRights(fsc, fd, rightFdWrite)
+		if errno != ErrnoSuccess {
+			return errno
+		}
+		// fs.FS doesn't declare io.Writer, but implementations such as os.File implement it.
+		var ok bool
+		if writer, ok = f.File.(io.Writer); !ok {
 			return ErrnoBadf
 		}
+		fdflags = f.Fdflags
+	}
+
+	if seeker, ok := writer.(io.Seeker); ok && fdflags&fdflagsAppend != 0 {
+		if _, err := seeker.Seek(0, io.SeekEnd); err != nil {
+			return ErrnoIo
+		}
 	}
 
 	var nwritten uint32
@@ -1157,51 +1732,203 @@ func (a *snapshotPreview1) FdWrite(ctx context.Context, m api.Module, fd, iovs,
 		}
 		nwritten += uint32(n)
 	}
+
+	if syncer, ok := writer.(interface{ Sync() error }); ok && fdflags&(fdflagsDsync|fdflagsSync) != 0 {
+		if err := syncer.Sync(); err != nil {
+			return toErrno(err)
+		}
+	}
+
 	if !m.Memory().WriteUint32Le(ctx, resultSize, nwritten) {
 		return ErrnoFault
 	}
 	return ErrnoSuccess
 }
 
-// PathCreateDirectory is the WASI function named functionPathCreateDirectory
+// PathCreateDirectory is the WASI function named functionPathCreateDirectory which creates a directory.
+//
+// * fd - the file descriptor of a directory in a sys.WriteFS that `path` is relative to
+// * path - the offset in `m.Memory` to read the path string from
+// * pathLen - the length of `path`
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoFault - if `path` is an invalid offset due to the memory constraint
+// * wasi.ErrnoNotcapable - if `path` escapes the directory of `fd` or `fd` doesn't have the right to create directories
+// * wasi.ErrnoRofs - if the file system of `fd` is read-only
+// * wasi.ErrnoExist - if `path` already exists
+// * wasi.ErrnoNoent - if the parent of `path` does not exist
+//
+// Note: importPathCreateDirectory shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `mkdirat` in POSIX.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-path_create_directoryfd-fd-path-string---errno
+// See https://linux.die.net/man/2/mkdirat
 func (a *snapshotPreview1) PathCreateDirectory(ctx context.Context, m api.Module, fd, path, pathLen uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	wfs, name, errno := resolveWritePath(ctx, m, fsc, fd, path, pathLen, rightPathCreateDirectory)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+
+	if err := wfs.Mkdir(name, 0o755); err != nil {
+		return toErrno(err)
+	}
+	return ErrnoSuccess
 }
 
-// PathFilestatGet is the WASI function named functionPathFilestatGet
+// PathFilestatGet is the WASI function named functionPathFilestatGet which returns the attributes of a file or
+// directory.
+//
+// * fd - the file descriptor of a directory that `path` is relative to
+// * flags - lookupflags, where lookupflagsSymlinkFollow means a symbolic link at `path` is followed
+// * path - the offset in `m.Memory` to read the path string from
+// * pathLen - the length of `path`
+// * resultBuf - the offset in `m.Memory` to write the result filestat
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoFault - if `path` or `resultBuf` contain an invalid offset due to the memory constraint
+// * wasi.ErrnoNotcapable - if `path` escapes the directory of `fd` or `fd` doesn't have the right to get attributes of files
+// * wasi.ErrnoNoent - if `path` does not exist
+//
+// The filestat byte layout is the same as FdFilestatGet.
+//
+// Note: importPathFilestatGet shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `fstatat` in POSIX, where the absence of lookupflagsSymlinkFollow is AT_SYMLINK_NOFOLLOW.
+// Note: Symbolic links are only detected when the file system of `fd` is a sys.WriteFS, as fs.FS has no Lstat.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-path_filestat_getfd-fd-flags-lookupflags-path-string---errno-filestat
+// See https://linux.die.net/man/3/fstatat
 func (a *snapshotPreview1) PathFilestatGet(ctx context.Context, m api.Module, fd, flags, path, pathLen, resultBuf uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	dir, name, errno := resolvePath(ctx, m, fsc, fd, path, pathLen, rightPathFilestatGet)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+
+	var st fs.FileInfo
+	var err error
+	if wfs, ok := dir.FS.(sys.WriteFS); ok && flags&lookupflagsSymlinkFollow == 0 {
+		st, err = wfs.Lstat(name)
+	} else {
+		st, err = fs.Stat(dir.FS, name)
+	}
+	if err != nil {
+		return toErrno(err)
+	}
+	return writeFilestat(ctx, m, resultBuf, dir.Dev, name, st)
 }
 
-// PathFilestatSetTimes is the WASI function named functionPathFilestatSetTimes
-func (a *snapshotPreview1) PathFilestatSetTimes(ctx context.Context, m api.Module, fd, flags, path, pathLen uint32, atim, mtime uint64, fstFlags uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+// PathFilestatSetTimes is the WASI function named functionPathFilestatSetTimes which adjusts the times of a file or
+// directory.
+//
+// * fd - the file descriptor of a directory in a sys.WriteFS that `path` is relative to
+// * flags - lookupflags, which are ignored as symbolic links are always followed
+// * path - the offset in `m.Memory` to read the path string from
+// * pathLen - the length of `path`
+// * atim, mtim and fstFlags - the same as FdFilestatSetTimes
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoFault - if `path` is an invalid offset due to the memory constraint
+// * wasi.ErrnoInval - if `fstFlags` sets a time both to a value and to now
+// * wasi.ErrnoNotcapable - if `path` escapes the directory of `fd` or `fd` doesn't have the right to set times of files
+// * wasi.ErrnoRofs - if the file system of `fd` is read-only
+// * wasi.ErrnoNoent - if `path` does not exist
+//
+// Note: importPathFilestatSetTimes shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `utimensat` in POSIX.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-path_filestat_set_timesfd-fd-flags-lookupflags-path-string-atim-timestamp-mtim-timestamp-fst_flags-fstflags---errno
+// See https://linux.die.net/man/3/utimensat
+func (a *snapshotPreview1) PathFilestatSetTimes(ctx context.Context, m api.Module, fd, flags, path, pathLen uint32, atim, mtim uint64, fstFlags uint32) Errno {
+	_, fsc := sysFSCtx(ctx, m)
+
+	wfs, name, errno := resolveWritePath(ctx, m, fsc, fd, path, pathLen, rightPathFilestatSetTimes)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+
+	st, err := fs.Stat(wfs, name)
+	if err != nil {
+		return toErrno(err)
+	}
+	atime, mtime, errno := timesToSet(ctx, st, atim, mtim, fstFlags)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+	if err = wfs.Chtimes(name, atime, mtime); err != nil {
+		return toErrno(err)
+	}
+	return ErrnoSuccess
 }
 
-// PathLink is the WASI function named functionPathLink
+// PathLink is the WASI function named functionPathLink which creates a hard link.
+//
+// * oldFd - the file descriptor of a directory in a sys.WriteFS that `oldPath` is relative to
+// * oldFlags - lookupflags, which are ignored as the link source is never followed
+// * oldPath - the offset in `m.Memory` to read the existing path string from
+// * oldPathLen - the length of `oldPath`
+// * newFd - the file descriptor of a directory in the same sys.WriteFS that `newPath` is relative to
+// * newPath - the offset in `m.Memory` to read the path string of the new link from
+// * newPathLen - the length of `newPath`
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `oldFd` or `newFd` are invalid
+// * wasi.ErrnoFault - if `oldPath` or `newPath` are invalid offsets due to the memory constraint
+// * wasi.ErrnoNotcapable - if `oldPath` or `newPath` escape their directories, or their directories don't have the rights to link them
+// * wasi.ErrnoRofs - if the file system of `oldFd` is read-only
+// * wasi.ErrnoXdev - if `oldFd` and `newFd` are in different file systems
+// * wasi.ErrnoNoent - if `oldPath` does not exist
+// * wasi.ErrnoExist - if `newPath` already exists
+//
+// Note: importPathLink shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `linkat` in POSIX.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#path_link
+// See https://linux.die.net/man/2/linkat
 func (a *snapshotPreview1) PathLink(ctx context.Context, m api.Module, oldFd, oldFlags, oldPath, oldPathLen, newFd, newPath, newPathLen uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	wfs, oldName, newName, errno := resolveWritePaths(ctx, m, fsc, oldFd, oldPath, oldPathLen, newFd, newPath, newPathLen, rightPathLinkSource, rightPathLinkTarget)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+
+	if err := wfs.Link(oldName, newName); err != nil {
+		return toErrno(err)
+	}
+	return ErrnoSuccess
 }
 
 // PathOpen is the WASI function to open a file or directory. This returns ErrnoBadf if the fd is invalid.
 //
 // * fd - the file descriptor of a directory that `path` is relative to
-// * dirflags - flags to indicate how to resolve `path`
+// * dirflags - lookupflags, where the absence of lookupflagsSymlinkFollow means a symbolic link at `path` isn't
+//   opened, if the file system is a sys.WriteFS
 // * path - the offset in `m.Memory` to read the path string from
 // * pathLen - the length of `path`
-// * oFlags - the open flags to indicate the method by which to open the file
-// * fsRightsBase - the rights of the newly created file descriptor for `path`
+// * oFlags - the open flags to indicate the method by which to open the file, a combination of oflagsCreat,
+//   oflagsDirectory, oflagsExcl and oflagsTrunc
+// * fsRightsBase - the rights of the newly created file descriptor for `path`. The file is opened for writing when
+//   this includes the right to "fd_write", unless it is a directory, which is always opened for reading. This allows
+//   opening directories with all rights, as wasi-libc does by default.
 // * fsRightsInheriting - the rights of the file descriptors derived from the newly created file descriptor for `path`
-// * fdFlags - the file descriptor flags
+// * fdFlags - the file descriptor flags, see FdFdstatSetFlags
 // * resultOpenedFd - the offset in `m.Memory` to write the newly created file descriptor to.
 //     * The result FD value is guaranteed to be less than 2**31
 //
 // The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
 // * wasi.ErrnoBadf - if `fd` is invalid
 // * wasi.ErrnoFault - if `resultOpenedFd` contains an invalid offset due to the memory constraint
-// * wasi.ErrnoNoent - if `path` does not exist.
-// * wasi.ErrnoExist - if `path` exists, while `oFlags` requires that it must not.
-// * wasi.ErrnoNotdir - if `path` is not a directory, while `oFlags` requires that it must be.
+// * wasi.ErrnoInval - if `oFlags` includes undefined flags, or both oflagsCreat and oflagsDirectory
+// * wasi.ErrnoNotcapable - if `path` escapes the directory of `fd`, `fd` doesn't have the rights to open it, or
+//   `oFlags` includes oflagsTrunc without the right to write
+// * wasi.ErrnoNoent - if `path` does not exist, while `oFlags` doesn't include oflagsCreat.
+// * wasi.ErrnoExist - if `path` exists, while `oFlags` includes both oflagsCreat and oflagsExcl.
+// * wasi.ErrnoNotdir - if `path` is not a directory, while `oFlags` includes oflagsDirectory or it ends with a slash.
+// * wasi.ErrnoIsdir - if `path` is a directory, while `oFlags` includes oflagsTrunc.
+// * wasi.ErrnoLoop - if `path` is a symbolic link, while `dirflags` doesn't include lookupflagsSymlinkFollow.
+// * wasi.ErrnoRofs - if `path` would be written, but the file system of `fd` is read-only.
 // * wasi.ErrnoIo - if other error happens during the operation of the underying file system.
 //
 // For example, this function needs to first read `path` to determine the file to open.
@@ -1225,7 +1952,6 @@ func (a *snapshotPreview1) PathLink(ctx context.Context, m api.Module, oldFd, ol
 // Note: importPathOpen shows this signature in the WebAssembly 1.0 (20191205) Text Format.
 // Note: This is similar to `openat` in POSIX.
 // Note: The returned file descriptor is not guaranteed to be the lowest-numbered file
-// Note: Rights will never be implemented per https://github.com/WebAssembly/WASI/issues/469#issuecomment-1045251844
 // See https://github.com/WebAssembly/WASI/blob/main/phases/snapshot/docs.md#path_open
 // See https://linux.die.net/man/3/openat
 func (a *snapshotPreview1) PathOpen(ctx context.Context, m api.Module, fd, dirflags, pathPtr, pathLen, oflags uint32, fsRightsBase,
@@ -1237,19 +1963,56 @@ func (a *snapshotPreview1) PathOpen(ctx context.Context, m api.Module, fd, dirfl
 		return ErrnoBadf
 	}
 
+	if oflags&^(oflagsCreat|oflagsDirectory|oflagsExcl|oflagsTrunc) != 0 ||
+		oflags&(oflagsCreat|oflagsDirectory) == oflagsCreat|oflagsDirectory {
+		return ErrnoInval
+	}
+
+	// The rights to create or truncate the file are those of the directory.
+	dirRights := uint64(rightPathOpen)
+	if oflags&oflagsCreat != 0 {
+		dirRights |= rightPathCreateFile
+	}
+	if oflags&oflagsTrunc != 0 {
+		dirRights |= rightPathFilestatSetSize
+	}
+	if !hasRights(dir, dirRights) {
+		return ErrnoNotcapable
+	}
+
+	// Rights can't be escalated, so they are limited to what the directory allows.
+	rightsBase := fsRightsBase & inheritingRights(dir)
+	rightsInheriting := fsRightsInheriting & inheritingRights(dir)
+
+	// Truncating writes the file, so it can't happen on a file descriptor that can't write.
+	if oflags&oflagsTrunc != 0 && rightsBase&rightFdWrite == 0 {
+		return ErrnoNotcapable
+	}
+
 	b, ok := m.Memory().Read(ctx, pathPtr, pathLen)
 	if !ok {
 		return ErrnoFault
 	}
 
-	// TODO: Consider dirflags and oflags. Also, allow non-read-only open based on config about the mount.
-	// Ex. allow os.O_RDONLY, os.O_WRONLY, or os.O_RDWR either by config flag or pattern on filename
-	// See #390
-	entry, errno := openFileEntry(dir.FS, path.Join(dir.Path, string(b)))
+	pathName, errno := joinPath(dir, string(b))
+	if errno != ErrnoSuccess {
+		return errno
+	}
+
+	// Like POSIX, a trailing slash means the path must be a directory.
+	if strings.HasSuffix(string(b), "/") {
+		oflags |= oflagsDirectory
+	}
+
+	entry, errno := openFileEntry(dir.FS, pathName, dirflags, oflags, rightsBase)
 	if errno != ErrnoSuccess {
 		return errno
 	}
 
+	entry.Rights = &sys.Rights{Base: rightsBase, Inheriting: rightsInheriting}
+	entry.Fdflags = uint16(fdflags)
+	entry.Dev = dir.Dev
+
 	if newFD, ok := fsc.OpenFile(entry); !ok {
 		_ = entry.File.Close()
 		return ErrnoIo
@@ -1265,29 +2028,314 @@ func (a *snapshotPreview1) PathReadlink(ctx context.Context, m api.Module, fd, p
 	return ErrnoNosys // stubbed for GrainLang per #271
 }
 
-// PathRemoveDirectory is the WASI function named functionPathRemoveDirectory
+// PathRemoveDirectory is the WASI function named functionPathRemoveDirectory which removes an empty directory.
+//
+// * fd - the file descriptor of a directory in a sys.WriteFS that `path` is relative to
+// * path - the offset in `m.Memory` to read the path string from
+// * pathLen - the length of `path`
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoFault - if `path` is an invalid offset due to the memory constraint
+// * wasi.ErrnoNotcapable - if `path` escapes the directory of `fd` or `fd` doesn't have the right to remove directories
+// * wasi.ErrnoRofs - if the file system of `fd` is read-only
+// * wasi.ErrnoNoent - if `path` does not exist
+// * wasi.ErrnoNotdir - if `path` is not a directory
+// * wasi.ErrnoNotempty - if `path` is a directory that is not empty
+//
+// Note: importPathRemoveDirectory shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `unlinkat` with `AT_REMOVEDIR` in POSIX.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-path_remove_directoryfd-fd-path-string---errno
+// See https://linux.die.net/man/2/unlinkat
 func (a *snapshotPreview1) PathRemoveDirectory(ctx context.Context, m api.Module, fd, path, pathLen uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	wfs, name, errno := resolveWritePath(ctx, m, fsc, fd, path, pathLen, rightPathRemoveDirectory)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+
+	if st, err := wfs.Lstat(name); err != nil {
+		return toErrno(err)
+	} else if !st.IsDir() {
+		return ErrnoNotdir
+	}
+	if err := wfs.Remove(name); err != nil {
+		return toErrno(err)
+	}
+	return ErrnoSuccess
 }
 
-// PathRename is the WASI function named functionPathRename
+// PathRename is the WASI function named functionPathRename which renames a file or directory.
+//
+// * fd - the file descriptor of a directory in a sys.WriteFS that `oldPath` is relative to
+// * oldPath - the offset in `m.Memory` to read the existing path string from
+// * oldPathLen - the length of `oldPath`
+// * newFd - the file descriptor of a directory in the same sys.WriteFS that `newPath` is relative to
+// * newPath - the offset in `m.Memory` to read the new path string from
+// * newPathLen - the length of `newPath`
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` or `newFd` are invalid
+// * wasi.ErrnoFault - if `oldPath` or `newPath` are invalid offsets due to the memory constraint
+// * wasi.ErrnoNotcapable - if `oldPath` or `newPath` escape their directories, or their directories don't have the rights to rename them
+// * wasi.ErrnoRofs - if the file system of `fd` is read-only
+// * wasi.ErrnoXdev - if `fd` and `newFd` are in different file systems
+// * wasi.ErrnoNoent - if `oldPath` does not exist
+//
+// Note: importPathRename shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `renameat` in POSIX.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-path_renamefd-fd-old_path-string-new_fd-fd-new_path-string---errno
+// See https://linux.die.net/man/2/renameat
 func (a *snapshotPreview1) PathRename(ctx context.Context, m api.Module, fd, oldPath, oldPathLen, newFd, newPath, newPathLen uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	wfs, oldName, newName, errno := resolveWritePaths(ctx, m, fsc, fd, oldPath, oldPathLen, newFd, newPath, newPathLen, rightPathRenameSource, rightPathRenameTarget)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+
+	if err := wfs.Rename(oldName, newName); err != nil {
+		return toErrno(err)
+	}
+	return ErrnoSuccess
 }
 
-// PathSymlink is the WASI function named functionPathSymlink
+// PathSymlink is the WASI function named functionPathSymlink which creates a symbolic link.
+//
+// * oldPath - the offset in `m.Memory` to read the contents of the symbolic link from
+// * oldPathLen - the length of `oldPath`
+// * fd - the file descriptor of a directory in a sys.WriteFS that `newPath` is relative to
+// * newPath - the offset in `m.Memory` to read the path string of the symbolic link from
+// * newPathLen - the length of `newPath`
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoFault - if `oldPath` or `newPath` are invalid offsets due to the memory constraint
+// * wasi.ErrnoNotcapable - if `newPath` escapes the directory of `fd` or `fd` doesn't have the right to create symbolic links
+// * wasi.ErrnoPerm - if `oldPath` is absolute or would resolve outside the file system of `fd`
+// * wasi.ErrnoRofs - if the file system of `fd` is read-only
+// * wasi.ErrnoExist - if `newPath` already exists
+//
+// Note: importPathSymlink shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `symlinkat` in POSIX.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#path_symlink
+// See https://linux.die.net/man/2/symlinkat
 func (a *snapshotPreview1) PathSymlink(ctx context.Context, m api.Module, oldPath, oldPathLen, fd, newPath, newPathLen uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	wfs, newName, errno := resolveWritePath(ctx, m, fsc, fd, newPath, newPathLen, rightPathSymlink)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+
+	target, ok := m.Memory().Read(ctx, oldPath, oldPathLen)
+	if !ok {
+		return ErrnoFault
+	}
+
+	if err := wfs.Symlink(string(target), newName); err != nil {
+		return toErrno(err)
+	}
+	return ErrnoSuccess
 }
 
-// PathUnlinkFile is the WASI function named functionPathUnlinkFile
+// PathUnlinkFile is the WASI function named functionPathUnlinkFile which unlinks a file.
+//
+// * fd - the file descriptor of a directory in a sys.WriteFS that `path` is relative to
+// * path - the offset in `m.Memory` to read the path string from
+// * pathLen - the length of `path`
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoFault - if `path` is an invalid offset due to the memory constraint
+// * wasi.ErrnoNotcapable - if `path` escapes the directory of `fd` or `fd` doesn't have the right to unlink files
+// * wasi.ErrnoRofs - if the file system of `fd` is read-only
+// * wasi.ErrnoNoent - if `path` does not exist
+// * wasi.ErrnoIsdir - if `path` is a directory
+//
+// Note: importPathUnlinkFile shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `unlinkat` in POSIX.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-path_unlink_filefd-fd-path-string---errno
+// See https://linux.die.net/man/2/unlinkat
 func (a *snapshotPreview1) PathUnlinkFile(ctx context.Context, m api.Module, fd, path, pathLen uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	_, fsc := sysFSCtx(ctx, m)
+
+	wfs, name, errno := resolveWritePath(ctx, m, fsc, fd, path, pathLen, rightPathUnlinkFile)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+
+	if st, err := wfs.Lstat(name); err != nil {
+		return toErrno(err)
+	} else if st.IsDir() {
+		return ErrnoIsdir
+	}
+	if err := wfs.Remove(name); err != nil {
+		return toErrno(err)
+	}
+	return ErrnoSuccess
 }
 
-// PollOneoff is the WASI function named functionPollOneoff
+// PollOneoff is the WASI function named functionPollOneoff that concurrently polls for the occurrence of a set of
+// events.
+//
+// * in - the offset in `m.Memory` to read `nsubscriptions` subscriptions, each 48 bytes
+// * out - the offset in `m.Memory` to write the resulting events, each 32 bytes
+// * nsubscriptions - the count of subscriptions to read from `in`, which must be greater than zero
+// * resultNevents - the offset in `m.Memory` to write the count of events written to `out`
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoInval - if `nsubscriptions` is zero
+// * wasi.ErrnoFault - if `in`, `out` or `resultNevents` contain an invalid offset due to the memory constraint
+// * wasi.ErrnoCanceled - if `ctx` is done before any subscription triggered
+//
+// Subscriptions of type eventTypeFdRead or eventTypeFdWrite trigger immediately when the file descriptor is open, as
+// neither io.Reader nor fs.File can report readiness. When at least one of these triggered, clock subscriptions are
+// not waited on. Otherwise, this blocks until the nearest clock subscription times out, and writes an event for every
+// clock subscription that expired by then. Both relative and absolute (subclockflagsSubscriptionClockAbstime) timeouts
+// use the clock configured by experimental.WithTimeNowUnixNano, and waiting uses experimental.WithNanosleep.
+//
+// subscription byte layout is 48 bytes, which as the following elements in order
+// * userdata 8 bytes, copied to the corresponding event
+// * tag 1 byte, the eventtype of the subscription, followed by 7 pad bytes
+// * if tag is eventTypeClock:
+//   * id 4 bytes, the clock id, followed by 4 pad bytes
+//   * timeout 8 bytes, in nanoseconds
+//   * precision 8 bytes, ignored
+//   * flags 2 bytes, where subclockflagsSubscriptionClockAbstime means timeout is absolute
+// * if tag is eventTypeFdRead or eventTypeFdWrite:
+//   * file_descriptor 4 bytes
+//
+// event byte layout is 32 bytes, which as the following elements in order
+// * userdata 8 bytes, copied from the subscription
+// * error 2 bytes, the wasi.Errno of the subscription, ex. wasi.ErrnoBadf if the file descriptor is not open
+// * type 1 byte, the eventtype of the subscription, followed by 5 pad bytes
+// * nbytes 8 bytes, the bytes available, which is always zero
+// * flags 2 bytes, followed by 6 pad bytes
+//
+// Note: importPollOneoff shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `poll` in POSIX.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#poll_oneoff
+// See https://linux.die.net/man/3/poll
 func (a *snapshotPreview1) PollOneoff(ctx context.Context, m api.Module, in, out, nsubscriptions, resultNevents uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
+	// Reject counts whose byte length doesn't fit in uint32, as no memory can hold them and the multiplication
+	// below would otherwise wrap to a small length that passes the bounds check.
+	if nsubscriptions == 0 || nsubscriptions > math.MaxUint32/subscriptionLen {
+		return ErrnoInval
+	}
+
+	mem := m.Memory()
+	subs, ok := mem.Read(ctx, in, nsubscriptions*subscriptionLen)
+	if !ok {
+		return ErrnoFault
+	}
+	// Ensure the whole result range is writable before blocking.
+	if _, ok = mem.Read(ctx, out, nsubscriptions*eventLen); !ok {
+		return ErrnoFault
+	}
+
+	_, fsc := sysFSCtx(ctx, m)
+	now := timeNowUnixNanoFunc(ctx)
+
+	var events []byte
+	// Scan subscriptions, writing events for file descriptors as they trigger immediately. Clock subscriptions are
+	// converted to absolute deadlines in the configured clock.
+	start := now()
+	deadlines := make(map[uint32]uint64, nsubscriptions)
+	nearest := uint64(math.MaxUint64)
+	for i := uint32(0); i < nsubscriptions; i++ {
+		sub := subs[i*subscriptionLen : (i+1)*subscriptionLen]
+		userdata, tag := sub[0:8], sub[8]
+		switch tag {
+		case eventTypeClock:
+			id := binary.LittleEndian.Uint32(sub[16:])
+			if id != clockIDRealtime && id != clockIDMonotonic {
+				events = appendEvent(events, userdata, ErrnoInval, tag)
+				continue
+			}
+			deadline := binary.LittleEndian.Uint64(sub[24:])
+			if binary.LittleEndian.Uint16(sub[40:])&subclockflagsSubscriptionClockAbstime == 0 {
+				if deadline > math.MaxUint64-start {
+					deadline = math.MaxUint64
+				} else {
+					deadline += start
+				}
+			}
+			deadlines[i] = deadline
+			if deadline < nearest {
+				nearest = deadline
+			}
+		case eventTypeFdRead, eventTypeFdWrite:
+			fd := binary.LittleEndian.Uint32(sub[16:])
+			errno := ErrnoSuccess
+			if !fdReadyForEvent(fsc, fd, tag) {
+				errno = ErrnoBadf
+			}
+			events = appendEvent(events, userdata, errno, tag)
+		default:
+			return ErrnoInval
+		}
+	}
+
+	// Only wait on the nearest clock when nothing else triggered.
+	if len(events) == 0 && len(deadlines) > 0 {
+		if nearest > start {
+			timeout := nearest - start
+			if timeout > math.MaxInt64 {
+				timeout = math.MaxInt64
+			}
+			nanosleepFunc(ctx)(ctx, int64(timeout))
+			if ctx.Err() != nil {
+				return ErrnoCanceled
+			}
+		}
+		// The nearest subscription always triggers, even if the clock didn't advance, ex. a fixed fake clock.
+		cutoff := now()
+		if cutoff < nearest {
+			cutoff = nearest
+		}
+		for i := uint32(0); i < nsubscriptions; i++ {
+			if deadline, ok := deadlines[i]; ok && deadline <= cutoff {
+				events = appendEvent(events, subs[i*subscriptionLen:i*subscriptionLen+8], ErrnoSuccess, eventTypeClock)
+			}
+		}
+	}
+
+	if !mem.Write(ctx, out, events) {
+		return ErrnoFault
+	}
+	if !mem.WriteUint32Le(ctx, resultNevents, uint32(len(events))/eventLen) {
+		return ErrnoFault
+	}
+	return ErrnoSuccess
+}
+
+// fdReadyForEvent returns true if the file descriptor is open and can be used for the eventtype.
+func fdReadyForEvent(fsc *sys.FSContext, fd uint32, eventType byte) bool {
+	switch fd {
+	case fdStdin:
+		return eventType == eventTypeFdRead
+	case fdStdout, fdStderr:
+		return eventType == eventTypeFdWrite
+	}
+	f, ok := fsc.OpenedFile(fd)
+	if !ok || f.File == nil {
+		return false
+	}
+	if eventType == eventTypeFdWrite {
+		_, ok = f.File.(io.Writer)
+	}
+	return ok
+}
+
+// appendEvent appends a 32-byte event, leaving fd_readwrite zero as the bytes available are unknown.
+func appendEvent(events, userdata []byte, errno Errno, eventType byte) []byte {
+	event := make([]byte, eventLen)
+	copy(event, userdata)
+	binary.LittleEndian.PutUint16(event[8:], uint16(errno))
+	event[10] = eventType
+	return append(events, event...)
 }
 
 // ProcExit is the WASI function that terminates the execution of the module with an exit code.
@@ -1344,31 +2392,508 @@ func (a *snapshotPreview1) RandomGet(ctx context.Context, m api.Module, buf uint
 	return ErrnoSuccess
 }
 
-// SockRecv is the WASI function named functionSockRecv
-func (a *snapshotPreview1) SockRecv(ctx context.Context, m api.Module, fd, riData, riDataCount, riFlags, resultRoDataLen, resultRoFlags uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
-}
+// SockAccept is the WASI function named functionSockAccept which accepts a new incoming connection.
+//
+// * fd - the file descriptor of a listening socket, added with wazero.ModuleConfig WithListener
+// * flags - fdflags of the new connection, where fdflagsNonblock means "sock_recv" and "fd_read" on it return
+//   wasi.ErrnoAgain instead of waiting for data. Accepting itself always blocks.
+// * resultFd - the offset in `m.Memory` to write the file descriptor of the new connection
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoInval - if `flags` includes undefined flags
+// * wasi.ErrnoNotsup - if `flags` includes fdflags other than fdflagsNonblock, which don't apply to sockets
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to accept connections
+// * wasi.ErrnoNotsock - if `fd` is not a listening socket
+// * wasi.ErrnoFault - if `resultFd` is an invalid offset due to the memory constraint
+// * wasi.ErrnoCanceled - if `ctx` is done before a connection was accepted, and the listener supports deadlines
+// * wasi.ErrnoIo - if an IO related error happens during the operation, such as the listener was closed
+//
+// For example, if fd 3 is a listening socket and the next file descriptor is 4, and
+//    parameters fd=3 flags=0 resultFd=1, this function writes the below to `m.Memory`:
+//
+//                   uint32le
+//                  +--------+
+//                  |        |
+//        []byte{?, 4, 0, 0, 0, ?}
+//       resultFd --^
+//
+// Note: importSockAccept shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `accept` in POSIX.
+// Note: This was added to "wasi_snapshot_preview1" after the initial snapshot.
+// See https://github.com/WebAssembly/WASI/blob/main/phases/snapshot/docs.md#-sock_acceptfd-fd-flags-fdflags---resultfd-errno
+// See https://linux.die.net/man/3/accept
+func (a *snapshotPreview1) SockAccept(ctx context.Context, m api.Module, fd, flags, resultFd uint32) Errno {
+	_, fsc := sysFSCtx(ctx, m)
 
-// SockSend is the WASI function named functionSockSend
-func (a *snapshotPreview1) SockSend(ctx context.Context, m api.Module, fd, siData, siDataCount, siFlags, resultSoDataLen uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
-}
+	if flags&^(fdflagsAppend|fdflagsDsync|fdflagsNonblock|fdflagsRsync|fdflagsSync) != 0 {
+		return ErrnoInval
+	} else if flags&^fdflagsNonblock != 0 {
+		return ErrnoNotsup
+	}
 
-// SockShutdown is the WASI function named functionSockShutdown
-func (a *snapshotPreview1) SockShutdown(ctx context.Context, m api.Module, fd, how uint32) Errno {
-	return ErrnoNosys // stubbed for GrainLang per #271
-}
+	f, ok := fsc.OpenedFile(fd)
+	if !ok {
+		return ErrnoBadf
+	}
+	ln, ok := f.File.(*sys.ListenerFile)
+	if !ok {
+		return ErrnoNotsock
+	} else if !hasRights(f, rightSockAccept) {
+		return ErrnoNotcapable
+	}
 
-const (
-	fdStdin  = 0
-	fdStdout = 1
-	fdStderr = 2
+	var conn net.Conn
+	var err error
+	if d, ok := ln.Listener.(deadliner); ok {
+		stop := interruptOnDone(ctx, d.SetDeadline)
+		for {
+			conn, err = ln.Listener.Accept()
+			// Retry when another module sharing the listener was canceled.
+			if err == nil || ctx.Err() != nil || !errors.Is(err, os.ErrDeadlineExceeded) {
+				break
+			}
+		}
+		stop()
+	} else {
+		conn, err = ln.Listener.Accept()
+	}
+	if ctx.Err() != nil {
+		if conn != nil {
+			_ = conn.Close()
+		}
+		return ErrnoCanceled
+	} else if err != nil {
+		return ErrnoIo
+	}
+
+	entry := &sys.FileEntry{Path: conn.RemoteAddr().String(), File: &sys.ConnFile{Conn: conn}, Fdflags: uint16(flags)}
+	if f.Rights != nil { // The connection can't have more rights than the listener grants.
+		entry.Rights = &sys.Rights{Base: f.Rights.Inheriting, Inheriting: f.Rights.Inheriting}
+	}
+	if newFD, ok := fsc.OpenFile(entry); !ok {
+		_ = conn.Close()
+		return ErrnoNfile
+	} else if !m.Memory().WriteUint32Le(ctx, resultFd, newFD) {
+		_, _ = fsc.CloseFile(newFD)
+		return ErrnoFault
+	}
+	return ErrnoSuccess
+}
+
+// SockRecv is the WASI function named functionSockRecv which receives data from a connected socket.
+//
+// * fd - the file descriptor of a connected socket
+// * riData - the offset in `m.Memory` to read offset, size pairs representing where to write received data.
+//   * Both offset and length are encoded as uint32le.
+// * riDataCount - the count of memory offset, size pairs to read sequentially starting at riData.
+// * riFlags - riflags, where riflagsRecvWaitall means wait until all of `riData` are filled
+// * resultRoDataLen - the offset in `m.Memory` to write the number of bytes received
+// * resultRoFlags - the offset in `m.Memory` to write the uint16le roflags, which are always zero
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to read
+// * wasi.ErrnoNotsock - if `fd` is not a socket
+// * wasi.ErrnoNotconn - if `fd` is a listening socket
+// * wasi.ErrnoNotsup - if `riFlags` includes riflagsRecvPeek
+// * wasi.ErrnoFault - if `riData`, `resultRoDataLen` or `resultRoFlags` contain an invalid offset due to the memory
+//   constraint
+// * wasi.ErrnoCanceled - if `ctx` is done while waiting for data
+// * wasi.ErrnoAgain - if `fd` has fdflagsNonblock and no data is available, yet
+// * wasi.ErrnoIo - if an IO related error happens during the operation
+//
+// Unless `riFlags` includes riflagsRecvWaitall, this returns after the first read that receives data, like `recv` in
+// POSIX. Otherwise, it waits until every buffer is filled or the peer shut down its side. Zero bytes received means
+// the peer shut down its side.
+//
+// Note: importSockRecv shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `recvmsg` in POSIX.
+// See FdRead
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-sock_recvfd-fd-ri_data-iovec_array-ri_flags-riflags---errno-size-roflags
+// See https://linux.die.net/man/3/recvmsg
+func (a *snapshotPreview1) SockRecv(ctx context.Context, m api.Module, fd, riData, riDataCount, riFlags, resultRoDataLen, resultRoFlags uint32) Errno {
+	_, fsc := sysFSCtx(ctx, m)
+
+	conn, fdflags, errno := openedConn(fsc, fd, rightFdRead)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+	if riFlags&riflagsRecvPeek != 0 {
+		return ErrnoNotsup // net.Conn can't read without consuming data.
+	}
+
+	var reader io.Reader = conn
+	nonblock := fdflags&fdflagsNonblock != 0
+	if nonblock {
+		var restore func()
+		reader, restore = nonblockReader(conn)
+		defer restore()
+	} else {
+		stop := interruptOnDone(ctx, conn.SetReadDeadline)
+		defer stop()
+	}
+
+	waitall := riFlags&riflagsRecvWaitall != 0
+	var nread uint32
+	for i := uint32(0); i < riDataCount; i++ {
+		iovPtr := riData + i*8
+		offset, ok := m.Memory().ReadUint32Le(ctx, iovPtr)
+		if !ok {
+			return ErrnoFault
+		}
+		l, ok := m.Memory().ReadUint32Le(ctx, iovPtr+4)
+		if !ok {
+			return ErrnoFault
+		}
+		b, ok := m.Memory().Read(ctx, offset, l)
+		if !ok {
+			return ErrnoFault
+		}
+
+		var n int
+		var err error
+		if waitall {
+			n, err = io.ReadFull(reader, b)
+		} else {
+			n, err = reader.Read(b)
+		}
+		nread += uint32(n)
+		if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+			break
+		} else if nonblock && errors.Is(err, os.ErrDeadlineExceeded) {
+			if nread == 0 {
+				return ErrnoAgain
+			}
+			break
+		} else if err != nil {
+			if ctx.Err() != nil {
+				return ErrnoCanceled
+			}
+			return ErrnoIo
+		} else if !waitall && n > 0 {
+			break // don't block for more data
+		}
+	}
+	if !m.Memory().WriteUint32Le(ctx, resultRoDataLen, nread) {
+		return ErrnoFault
+	}
+	if !m.Memory().WriteUint16Le(ctx, resultRoFlags, 0) {
+		return ErrnoFault
+	}
+	return ErrnoSuccess
+}
+
+// SockSend is the WASI function named functionSockSend which sends data on a connected socket.
+//
+// * fd - the file descriptor of a connected socket
+// * siData - the offset in `m.Memory` to read offset, size pairs representing the data to send
+//   * Both offset and length are encoded as uint32le.
+// * siDataCount - the count of memory offset, size pairs to read sequentially starting at siData.
+// * siFlags - siflags, which has no flags defined
+// * resultSoDataLen - the offset in `m.Memory` to write the number of bytes sent
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to write
+// * wasi.ErrnoNotsock - if `fd` is not a socket
+// * wasi.ErrnoNotconn - if `fd` is a listening socket
+// * wasi.ErrnoFault - if `siData` or `resultSoDataLen` contain an invalid offset due to the memory constraint
+// * wasi.ErrnoIo - if an IO related error happens during the operation, such as the peer closed the connection
+//
+// Note: importSockSend shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `sendmsg` in POSIX.
+// See FdWrite
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-sock_sendfd-fd-si_data-ciovec_array-si_flags-siflags---errno-size
+// See https://linux.die.net/man/3/sendmsg
+func (a *snapshotPreview1) SockSend(ctx context.Context, m api.Module, fd, siData, siDataCount, siFlags, resultSoDataLen uint32) Errno {
+	_, fsc := sysFSCtx(ctx, m)
+
+	conn, _, errno := openedConn(fsc, fd, rightFdWrite)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+
+	var nwritten uint32
+	for i := uint32(0); i < siDataCount; i++ {
+		iovPtr := siData + i*8
+		offset, ok := m.Memory().ReadUint32Le(ctx, iovPtr)
+		if !ok {
+			return ErrnoFault
+		}
+		l, ok := m.Memory().ReadUint32Le(ctx, iovPtr+4)
+		if !ok {
+			return ErrnoFault
+		}
+		b, ok := m.Memory().Read(ctx, offset, l)
+		if !ok {
+			return ErrnoFault
+		}
+		n, err := conn.Write(b)
+		if err != nil {
+			return ErrnoIo
+		}
+		nwritten += uint32(n)
+	}
+	if !m.Memory().WriteUint32Le(ctx, resultSoDataLen, nwritten) {
+		return ErrnoFault
+	}
+	return ErrnoSuccess
+}
+
+// SockShutdown is the WASI function named functionSockShutdown which shuts down the receive and/or send side of a
+// connected socket.
+//
+// * fd - the file descriptor of a connected socket
+// * how - sdflags, a combination of sdflagsRd and sdflagsWr
+//
+// The wasi.Errno returned is wasi.ErrnoSuccess except the following error conditions:
+// * wasi.ErrnoBadf - if `fd` is invalid
+// * wasi.ErrnoNotcapable - if `fd` doesn't have the right to shut it down
+// * wasi.ErrnoNotsock - if `fd` is not a socket
+// * wasi.ErrnoNotconn - if `fd` is a listening socket
+// * wasi.ErrnoInval - if `how` is zero or includes undefined flags
+// * wasi.ErrnoNotsup - if only one side is shut down, but the connection doesn't support that, such as net.Pipe
+// * wasi.ErrnoIo - if an IO related error happens during the operation
+//
+// Note: importSockShutdown shows this signature in the WebAssembly 1.0 (20191205) Text Format.
+// Note: This is similar to `shutdown` in POSIX.
+// Note: When both sides are shut down and the connection doesn't support that, such as net.Pipe, it is closed. The
+// file descriptor remains open until "fd_close".
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-sock_shutdownfd-fd-how-sdflags---errno
+// See https://linux.die.net/man/3/shutdown
+func (a *snapshotPreview1) SockShutdown(ctx context.Context, m api.Module, fd, how uint32) Errno {
+	_, fsc := sysFSCtx(ctx, m)
+
+	conn, _, errno := openedConn(fsc, fd, rightSockShutdown)
+	if errno != ErrnoSuccess {
+		return errno
+	}
+	if how == 0 || how&^(sdflagsRd|sdflagsWr) != 0 {
+		return ErrnoInval
+	}
+
+	// net.Conn doesn't declare half-close, but implementations such as net.TCPConn implement it.
+	var err error
+	if hc, ok := conn.(interface {
+		CloseRead() error
+		CloseWrite() error
+	}); ok {
+		if how&sdflagsRd != 0 {
+			err = hc.CloseRead()
+		}
+		if how&sdflagsWr != 0 && err == nil {
+			err = hc.CloseWrite()
+		}
+	} else if how == sdflagsRd|sdflagsWr {
+		err = conn.Close()
+	} else {
+		return ErrnoNotsup
+	}
+
+	if err != nil {
+		return ErrnoIo
+	}
+	return ErrnoSuccess
+}
+
+// openedConn returns the connection opened as fd and its fdflags, or wasi.ErrnoNotcapable if it doesn't have the
+// rights.
+func openedConn(fsc *sys.FSContext, fd uint32, rights uint64) (net.Conn, uint16, Errno) {
+	f, ok := fsc.OpenedFile(fd)
+	if !ok {
+		return nil, 0, ErrnoBadf
+	}
+	switch file := f.File.(type) {
+	case *sys.ConnFile:
+		if !hasRights(f, rights) {
+			return nil, 0, ErrnoNotcapable
+		}
+		return file.Conn, f.Fdflags, ErrnoSuccess
+	case *sys.ListenerFile:
+		return nil, 0, ErrnoNotconn
+	}
+	return nil, 0, ErrnoNotsock
+}
+
+// riflags are used by SockRecv
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-riflags-flagsu16
+const (
+	riflagsRecvPeek = 1 << iota
+	riflagsRecvWaitall
+)
+
+// sdflags are used by SockShutdown
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-sdflags-flagsu8
+const (
+	sdflagsRd = 1 << iota
+	sdflagsWr
+)
+
+const (
+	fdStdin  = 0
+	fdStdout = 1
+	fdStderr = 2
+)
+
+// clockid values
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-clockid-enumu32
+const (
+	clockIDRealtime  = 0
+	clockIDMonotonic = 1
+)
+
+// eventtype values and sizes used by PollOneoff
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-eventtype-enumu8
+const (
+	eventTypeClock   = 0
+	eventTypeFdRead  = 1
+	eventTypeFdWrite = 2
+
+	// subscriptionLen is the size in bytes of a subscription.
+	subscriptionLen = 48
+	// eventLen is the size in bytes of an event.
+	eventLen = 32
+
+	// subclockflagsSubscriptionClockAbstime indicates the clock subscription timeout is absolute, not relative.
+	subclockflagsSubscriptionClockAbstime = 1
+)
+
+// filetype values
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-filetype-enumu8
+const (
+	filetypeUnknown = iota
+	filetypeBlockDevice
+	filetypeCharacterDevice
+	filetypeDirectory
+	filetypeRegularFile
+	filetypeSocketDgram
+	filetypeSocketStream
+	filetypeSymbolicLink
+)
+
+// filestatLen is the size in bytes of a filestat.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-filestat-struct
+const filestatLen = 64
+
+// oflags are used by PathOpen
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-oflags-flagsu16
+const (
+	oflagsCreat = 1 << iota
+	oflagsDirectory
+	oflagsExcl
+	oflagsTrunc
 )
 
+// lookupflagsSymlinkFollow indicates a symbolic link is followed, when it is the last component of a path.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-lookupflags-flagsu32
+const lookupflagsSymlinkFollow = 1
+
+// writeFilestat writes the filestat of the file at the path in the file system `dev` to offset in memory.
+func writeFilestat(ctx context.Context, m api.Module, offset uint32, dev uint64, pathName string, st fs.FileInfo) Errno {
+	filestat := make([]byte, filestatLen)
+	mtim := uint64(st.ModTime().UnixNano())
+	ino, nlink := inode(pathName), uint64(1)
+	if hostDev, hostIno, hostNlink, ok := hostStat(st); ok {
+		dev, ino, nlink = hostDev, hostIno, hostNlink
+	}
+	binary.LittleEndian.PutUint64(filestat, dev)
+	binary.LittleEndian.PutUint64(filestat[8:], ino)
+	filestat[16] = filetype(st.Mode().Type())
+	binary.LittleEndian.PutUint64(filestat[24:], nlink)
+	binary.LittleEndian.PutUint64(filestat[32:], uint64(st.Size()))
+	binary.LittleEndian.PutUint64(filestat[40:], mtim) // atim
+	binary.LittleEndian.PutUint64(filestat[48:], mtim)
+	binary.LittleEndian.PutUint64(filestat[56:], mtim) // ctim
+
+	if !m.Memory().Write(ctx, offset, filestat) {
+		return ErrnoFault
+	}
+	return ErrnoSuccess
+}
+
+// direntLen is the size in bytes of a dirent, excluding its name.
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-dirent-struct
+const direntLen = 24
+
+// filetype returns the filetype of the fs.FileMode type bits.
+func filetype(mode fs.FileMode) byte {
+	switch {
+	case mode.IsRegular():
+		return filetypeRegularFile
+	case mode.IsDir():
+		return filetypeDirectory
+	case mode&fs.ModeSymlink != 0:
+		return filetypeSymbolicLink
+	case mode&fs.ModeCharDevice != 0:
+		return filetypeCharacterDevice
+	case mode&fs.ModeDevice != 0:
+		return filetypeBlockDevice
+	case mode&fs.ModeSocket != 0:
+		return filetypeSocketStream
+	}
+	return filetypeUnknown
+}
+
+// inode returns a serial number for the path of a file in its file system, which is stable across calls. This is used
+// when hostStat can't read the serial number of the file.
+//
+// Note: fs.FileInfo doesn't portably include the inode, so this is a hash of the cleaned path. This means hard links
+// don't share a serial number.
+func inode(pathName string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(path.Clean(pathName)))
+	return h.Sum64()
+}
+
+// direntIno returns the serial number of a directory entry, consistent with the one written by writeFilestat.
+func direntIno(dirPath string, e fs.DirEntry) uint64 {
+	if st, err := e.Info(); err == nil {
+		if _, ino, _, ok := hostStat(st); ok {
+			return ino
+		}
+	}
+	return inode(path.Join(dirPath, e.Name()))
+}
+
 func timeNowUnixNano() uint64 {
 	return uint64(time.Now().UnixNano())
 }
 
+// timeNowUnixNanoFunc returns the clock overridden via experimental.WithTimeNowUnixNano or timeNowUnixNano.
+func timeNowUnixNanoFunc(ctx context.Context) func() uint64 {
+	if clockVal := ctx.Value(sys.TimeNowUnixNanoKey{}); clockVal != nil {
+		clockCtx, ok := clockVal.(func() uint64)
+		if !ok {
+			panic(fmt.Errorf("unsupported clock key: %v", clockVal))
+		}
+		return clockCtx
+	}
+	return timeNowUnixNano
+}
+
+// nanosleep blocks for ns nanoseconds or until ctx is done.
+func nanosleep(ctx context.Context, ns int64) {
+	timer := time.NewTimer(time.Duration(ns))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+	case <-timer.C:
+	}
+}
+
+// nanosleepFunc returns the function overridden via experimental.WithNanosleep or nanosleep.
+func nanosleepFunc(ctx context.Context) func(context.Context, int64) {
+	if sleepVal := ctx.Value(sys.NanosleepKey{}); sleepVal != nil {
+		sleepCtx, ok := sleepVal.(func(context.Context, int64))
+		if !ok {
+			panic(fmt.Errorf("unsupported nanosleep key: %v", sleepVal))
+		}
+		return sleepCtx
+	}
+	return nanosleep
+}
+
 func sysCtx(m api.Module) *wasm.SysContext {
 	if internal, ok := m.(*wasm.CallContext); !ok {
 		panic(fmt.Errorf("unsupported wasm.Module implementation: %v", m))
@@ -1393,25 +2918,377 @@ func sysFSCtx(ctx context.Context, m api.Module) (*wasm.SysContext, *sys.FSConte
 	}
 }
 
-func openFileEntry(rootFS fs.FS, pathName string) (*sys.FileEntry, Errno) {
-	f, err := rootFS.Open(pathName)
-	if err != nil {
-		switch {
-		case errors.Is(err, fs.ErrNotExist):
-			return nil, ErrnoNoent
-		case errors.Is(err, fs.ErrExist):
+// openFileEntry opens the path in the file system, according to the lookupflags, oflags and requested rights of
+// PathOpen.
+func openFileEntry(rootFS fs.FS, pathName string, dirflags, oflags uint32, rights uint64) (*sys.FileEntry, Errno) {
+	// Directories can't be written, so they are opened for reading, regardless of the rights to write their files.
+	if oflags&oflagsDirectory != 0 {
+		rights &^= rightFdWrite
+	} else if st, err := fs.Stat(rootFS, pathName); err == nil && st.IsDir() {
+		if oflags&(oflagsCreat|oflagsExcl) == oflagsCreat|oflagsExcl {
 			return nil, ErrnoExist
-		default:
-			return nil, ErrnoIo
+		} else if oflags&oflagsTrunc != 0 {
+			return nil, ErrnoIsdir
 		}
+		rights &^= rightFdWrite
+	}
+	writable := rights&rightFdWrite != 0
+
+	var f fs.File
+	var err error
+	if wfs, ok := rootFS.(sys.WriteFS); ok {
+		if dirflags&lookupflagsSymlinkFollow == 0 {
+			if st, err := wfs.Lstat(pathName); err == nil && st.Mode()&fs.ModeSymlink != 0 {
+				if oflags&(oflagsCreat|oflagsExcl) == oflagsCreat|oflagsExcl {
+					return nil, ErrnoExist
+				}
+				return nil, ErrnoLoop // same as O_NOFOLLOW
+			}
+		}
+		f, err = wfs.OpenFile(pathName, openFlag(oflags, rights), 0o666)
+	} else if f, err = rootFS.Open(pathName); err == nil {
+		// A read-only file system can only open existing files for reading.
+		if oflags&(oflagsCreat|oflagsExcl) == oflagsCreat|oflagsExcl {
+			_ = f.Close()
+			return nil, ErrnoExist
+		} else if writable || oflags&oflagsTrunc != 0 {
+			_ = f.Close()
+			return nil, ErrnoRofs
+		}
+	} else if oflags&oflagsCreat != 0 && errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrnoRofs
+	}
+	if err != nil {
+		return nil, toErrno(err)
 	}
 
-	// TODO: verify if oflags is a directory and fail with wasi.ErrnoNotdir if not
-	// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-oflags-flagsu16
+	if oflags&oflagsDirectory != 0 {
+		if st, err := f.Stat(); err != nil {
+			_ = f.Close()
+			return nil, toErrno(err)
+		} else if !st.IsDir() {
+			_ = f.Close()
+			return nil, ErrnoNotdir
+		}
+	}
 
 	return &sys.FileEntry{Path: pathName, FS: rootFS, File: f}, ErrnoSuccess
 }
 
+// openFlag returns the flag to pass to sys.WriteFS OpenFile for the oflags and requested rights of PathOpen.
+//
+// Note: fdflagsAppend isn't converted to os.O_APPEND, as FdFdstatSetFlags can change it after the file is open.
+func openFlag(oflags uint32, rights uint64) (flag int) {
+	switch {
+	case rights&rightFdWrite == 0:
+		flag = os.O_RDONLY
+	case rights&(rightFdRead|rightFdReaddir) == 0:
+		flag = os.O_WRONLY
+	default:
+		flag = os.O_RDWR
+	}
+	if oflags&oflagsCreat != 0 {
+		flag |= os.O_CREATE
+	}
+	if oflags&oflagsExcl != 0 {
+		flag |= os.O_EXCL
+	}
+	if oflags&oflagsTrunc != 0 {
+		flag |= os.O_TRUNC
+	}
+	return
+}
+
+// entryPath returns the path of the entry in its file system.
+func entryPath(entry *sys.FileEntry) string {
+	if entry.File == nil { // A pre-opened directory, such as "/", is the root of its file system.
+		return "."
+	}
+	return entry.Path
+}
+
+// joinPath returns the path of name in the file system of the directory entry, or ErrnoNotcapable if it escapes it.
+func joinPath(dir *sys.FileEntry, name string) (string, Errno) {
+	pathName := path.Join(entryPath(dir), name)
+	if !fs.ValidPath(pathName) {
+		return "", ErrnoNotcapable
+	}
+	return pathName, ErrnoSuccess
+}
+
+// resolvePath reads the path relative to the directory fd, and returns it with that directory. This returns
+// wasi.ErrnoNotcapable if the directory doesn't have the rights.
+func resolvePath(ctx context.Context, m api.Module, fsc *sys.FSContext, fd, pathPtr, pathLen uint32, rights uint64) (*sys.FileEntry, string, Errno) {
+	dir, ok := fsc.OpenedFile(fd)
+	if !ok || dir.FS == nil {
+		return nil, "", ErrnoBadf
+	} else if !hasRights(dir, rights) {
+		return nil, "", ErrnoNotcapable
+	}
+
+	b, ok := m.Memory().Read(ctx, pathPtr, pathLen)
+	if !ok {
+		return nil, "", ErrnoFault
+	}
+
+	pathName, errno := joinPath(dir, string(b))
+	if errno != ErrnoSuccess {
+		return nil, "", errno
+	}
+	return dir, pathName, ErrnoSuccess
+}
+
+// resolveWritePath reads the path relative to the directory fd, and returns it with the sys.WriteFS of that directory.
+func resolveWritePath(ctx context.Context, m api.Module, fsc *sys.FSContext, fd, pathPtr, pathLen uint32, rights uint64) (sys.WriteFS, string, Errno) {
+	dir, pathName, errno := resolvePath(ctx, m, fsc, fd, pathPtr, pathLen, rights)
+	if errno != ErrnoSuccess {
+		return nil, "", errno
+	}
+
+	// fs.FS doesn't declare mutations, but implementations such as sys.NewDirFS do.
+	wfs, ok := dir.FS.(sys.WriteFS)
+	if !ok {
+		return nil, "", ErrnoRofs
+	}
+	return wfs, pathName, ErrnoSuccess
+}
+
+// resolveWritePaths is like resolveWritePath, except for two paths that must be in the same file system.
+func resolveWritePaths(ctx context.Context, m api.Module, fsc *sys.FSContext, oldFd, oldPath, oldPathLen, newFd, newPath, newPathLen uint32, oldRights, newRights uint64) (sys.WriteFS, string, string, Errno) {
+	oldFS, oldName, errno := resolveWritePath(ctx, m, fsc, oldFd, oldPath, oldPathLen, oldRights)
+	if errno != ErrnoSuccess {
+		return nil, "", "", errno
+	}
+	newFS, newName, errno := resolveWritePath(ctx, m, fsc, newFd, newPath, newPathLen, newRights)
+	if errno != ErrnoSuccess {
+		return nil, "", "", errno
+	}
+	if oldFS != newFS {
+		return nil, "", "", ErrnoXdev
+	}
+	return oldFS, oldName, newName, ErrnoSuccess
+}
+
+// openedWriteFile returns the file opened as fd and its sys.WriteFS, or wasi.ErrnoNotcapable if it doesn't have the
+// rights.
+func openedWriteFile(fsc *sys.FSContext, fd uint32, rights uint64) (*sys.FileEntry, sys.WriteFS, Errno) {
+	f, errno := openedFileWithRights(fsc, fd, rights)
+	if errno != ErrnoSuccess {
+		return nil, nil, errno
+	}
+	wfs, ok := f.FS.(sys.WriteFS)
+	if !ok {
+		return nil, nil, ErrnoRofs
+	}
+	return f, wfs, ErrnoSuccess
+}
+
+// openedFileWithRights returns the file opened as fd, or wasi.ErrnoNotcapable if it doesn't have the rights.
+func openedFileWithRights(fsc *sys.FSContext, fd uint32, rights uint64) (*sys.FileEntry, Errno) {
+	f, ok := fsc.OpenedFile(fd)
+	if !ok || f.File == nil {
+		return nil, ErrnoBadf
+	} else if !hasRights(f, rights) {
+		return nil, ErrnoNotcapable
+	}
+	return f, ErrnoSuccess
+}
+
+// hasRights returns true if the base rights of the file include the rights. Files without rights have all rights.
+func hasRights(f *sys.FileEntry, rights uint64) bool {
+	return f.Rights == nil || f.Rights.Base&rights == rights
+}
+
+// inheritingRights returns the maximum rights of files opened relative to this one.
+func inheritingRights(f *sys.FileEntry) uint64 {
+	if f.Rights == nil {
+		return rightsAll
+	}
+	return f.Rights.Inheriting
+}
+
+// deadliner is implemented by listeners that can stop waiting, such as net.TCPListener.
+type deadliner interface {
+	SetDeadline(time.Time) error
+}
+
+// interruptOnDone sets a deadline in the past with setDeadline when ctx is done, so that a blocked call returns. The
+// returned function stops watching ctx, and clears the deadline if it was set, so that later calls wait as usual.
+func interruptOnDone(ctx context.Context, setDeadline func(time.Time) error) (stop func()) {
+	if ctx.Done() == nil {
+		return func() {} // never done, ex. context.Background
+	}
+	done, exited := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(exited)
+		select {
+		case <-ctx.Done():
+			_ = setDeadline(time.Unix(1, 0))
+		case <-done:
+		}
+	}()
+	return func() {
+		close(done)
+		<-exited
+		if ctx.Err() != nil {
+			_ = setDeadline(time.Time{})
+		}
+	}
+}
+
+// readDeadliner is implemented by files that support fdflagsNonblock, such as os.File pipes and net.Conn.
+type readDeadliner interface {
+	SetReadDeadline(time.Time) error
+}
+
+// nonblockDeadline is how long a read of fdflagsNonblock waits for data, when the file doesn't have a file descriptor
+// to read without waiting, such as net.Pipe. This can't be zero, as reads past their deadline don't consume data
+// that's already available.
+const nonblockDeadline = time.Millisecond
+
+// nonblockReader returns a reader of r for fdflagsNonblock, which returns os.ErrDeadlineExceeded instead of waiting
+// for data, and a function to restore blocking reads. This returns r if it doesn't support non-blocking reads.
+func nonblockReader(r io.Reader) (io.Reader, func()) {
+	if raw, ok := rawNonblockReader(r); ok {
+		return raw, func() {}
+	}
+	if d, ok := r.(readDeadliner); ok && d.SetReadDeadline(time.Now().Add(nonblockDeadline)) == nil {
+		return r, func() { _ = d.SetReadDeadline(time.Time{}) }
+	}
+	return r, func() {}
+}
+
+// fdflags are used by FdFdstatGet, FdFdstatSetFlags and PathOpen
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fdflags-flagsu16
+const (
+	fdflagsAppend = 1 << iota
+	fdflagsDsync
+	fdflagsNonblock
+	fdflagsRsync
+	fdflagsSync
+)
+
+// rights are used by FdFdstatGet, FdFdstatSetRights and PathOpen
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-rights-flagsu64
+const (
+	rightFdDatasync = 1 << iota
+	rightFdRead
+	rightFdSeek
+	rightFdFdstatSetFlags
+	rightFdSync
+	rightFdTell
+	rightFdWrite
+	rightFdAdvise
+	rightFdAllocate
+	rightPathCreateDirectory
+	rightPathCreateFile
+	rightPathLinkSource
+	rightPathLinkTarget
+	rightPathOpen
+	rightFdReaddir
+	rightPathReadlink
+	rightPathRenameSource
+	rightPathRenameTarget
+	rightPathFilestatGet
+	rightPathFilestatSetSize
+	rightPathFilestatSetTimes
+	rightFdFilestatGet
+	rightFdFilestatSetSize
+	rightFdFilestatSetTimes
+	rightPathSymlink
+	rightPathRemoveDirectory
+	rightPathUnlinkFile
+	rightPollFdReadwrite
+	rightSockShutdown
+	rightSockAccept
+
+	// rightsAll are the rights of files opened by the host, such as pre-opened directories.
+	rightsAll = rightSockAccept<<1 - 1
+)
+
+// fstflags are used by FdFilestatSetTimes and PathFilestatSetTimes
+// See https://github.com/WebAssembly/WASI/blob/snapshot-01/phases/snapshot/docs.md#-fstflags-flagsu16
+const (
+	fstflagsAtim = 1 << iota
+	fstflagsAtimNow
+	fstflagsMtim
+	fstflagsMtimNow
+)
+
+// timesToSet returns the times to set for FdFilestatSetTimes and PathFilestatSetTimes, given the current times `st`.
+// "Now" is read from the clock configured by experimental.WithTimeNowUnixNano.
+//
+// Note: Access times are not portably readable from fs.FileInfo. When only the modification time is set, the access
+// time is set to the prior modification time.
+func timesToSet(ctx context.Context, st fs.FileInfo, atim, mtim uint64, fstFlags uint32) (atime, mtime time.Time, errno Errno) {
+	if fstFlags&(fstflagsAtim|fstflagsAtimNow) == fstflagsAtim|fstflagsAtimNow ||
+		fstFlags&(fstflagsMtim|fstflagsMtimNow) == fstflagsMtim|fstflagsMtimNow {
+		return time.Time{}, time.Time{}, ErrnoInval
+	}
+
+	atime, mtime = st.ModTime(), st.ModTime()
+	now := time.Unix(0, int64(timeNowUnixNanoFunc(ctx)()))
+
+	switch {
+	case fstFlags&fstflagsAtim != 0:
+		atime = time.Unix(0, int64(atim))
+	case fstFlags&fstflagsAtimNow != 0:
+		atime = now
+	}
+	switch {
+	case fstFlags&fstflagsMtim != 0:
+		mtime = time.Unix(0, int64(mtim))
+	case fstFlags&fstflagsMtimNow != 0:
+		mtime = now
+	}
+	return atime, mtime, ErrnoSuccess
+}
+
+// toErrno maps an error from fs.FS or sys.WriteFS to the closest Errno, defaulting to ErrnoIo.
+//
+// Note: syscall.Errno are checked first as some match multiple fs errors. Ex. syscall.ENOTEMPTY is fs.ErrExist.
+func toErrno(err error) Errno {
+	var errno syscall.Errno
+	if errors.As(err, &errno) {
+		switch errno {
+		case syscall.EBADF:
+			return ErrnoBadf
+		case syscall.EBUSY:
+			return ErrnoBusy
+		case syscall.EINVAL:
+			return ErrnoInval
+		case syscall.EISDIR:
+			return ErrnoIsdir
+		case syscall.ELOOP:
+			return ErrnoLoop
+		case syscall.ENAMETOOLONG:
+			return ErrnoNametoolong
+		case syscall.ENOSPC:
+			return ErrnoNospc
+		case syscall.ENOTDIR:
+			return ErrnoNotdir
+		case syscall.ENOTEMPTY:
+			return ErrnoNotempty
+		case syscall.EROFS:
+			return ErrnoRofs
+		case syscall.EXDEV:
+			return ErrnoXdev
+		}
+	}
+
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return ErrnoNoent
+	case errors.Is(err, fs.ErrExist):
+		return ErrnoExist
+	case errors.Is(err, fs.ErrPermission):
+		return ErrnoPerm
+	case errors.Is(err, fs.ErrInvalid):
+		return ErrnoInval
+	}
+	return ErrnoIo
+}
+
 func writeOffsetsAndNullTerminatedValues(ctx context.Context, mem api.Memory, values []string, offsets, bytes uint32) Errno {
 	for _, value := range values {
 		// Write current offset and advance it.
@@ -4,17 +4,21 @@ import (
 	"bytes"
 	"context"
 	_ "embed"
+	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"io/fs"
 	"math"
 	"math/rand"
+	"net"
 	"os"
 	"path"
+	"path/filepath"
 	"testing"
 	"testing/fstest"
 	"testing/iotest"
+	"time"
 
 	"github.com/tetratelabs/wazero"
 	"github.com/tetratelabs/wazero/api"
@@ -524,22 +528,82 @@ func TestSnapshotPreview1_FdAdvise(t *testing.T) {
 	})
 }
 
-// TestSnapshotPreview1_FdAllocate only tests it is stubbed for GrainLang per #271
 func TestSnapshotPreview1_FdAllocate(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionFdAllocate, importFdAllocate, nil)
+	fileFD := uint32(4) // arbitrary fd after the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, fileFD, "file")
+
+	a, mod, fn := instantiateModule(testCtx, t, functionFdAllocate, importFdAllocate, sysCtx)
 	defer mod.Close(testCtx)
 
 	t.Run("snapshotPreview1.FdAllocate", func(t *testing.T) {
-		errno := a.FdAllocate(testCtx, mod, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+		errno := a.FdAllocate(testCtx, mod, fileFD, 0, 10)
+		require.Zero(t, errno, ErrnoName(errno))
+		requireFileSize(t, wfs, "file", 10)
+
+		// A smaller allocation doesn't truncate.
+		errno = a.FdAllocate(testCtx, mod, fileFD, 2, 3)
+		require.Zero(t, errno, ErrnoName(errno))
+		requireFileSize(t, wfs, "file", 10)
 	})
 
 	t.Run(functionFdAllocate, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0)
+		results, err := fn.Call(testCtx, uint64(fileFD), 10, 10)
 		require.NoError(t, err)
 		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+		require.Zero(t, errno, ErrnoName(errno))
+		requireFileSize(t, wfs, "file", 20)
+	})
+
+	t.Run("renamed", func(t *testing.T) {
+		// The open file is extended, even though its original name is now another file.
+		require.NoError(t, wfs.Rename("file", "renamed"))
+		f, err := wfs.OpenFile("file", os.O_RDWR|os.O_CREATE, 0o600)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		errno := a.FdAllocate(testCtx, mod, fileFD, 0, 30)
+		require.Zero(t, errno, ErrnoName(errno))
+		requireFileSize(t, wfs, "renamed", 30)
+		requireFileSize(t, wfs, "file", 0)
+	})
+
+	t.Run("overflow", func(t *testing.T) {
+		errno := a.FdAllocate(testCtx, mod, fileFD, math.MaxUint64, 1)
+		require.Equal(t, ErrnoInval, errno, ErrnoName(errno))
+
+		errno = a.FdAllocate(testCtx, mod, fileFD, math.MaxInt64, 1)
+		require.Equal(t, ErrnoFbig, errno, ErrnoName(errno))
+	})
+}
+
+func TestSnapshotPreview1_FdAllocate_Errors(t *testing.T) {
+	file, testFS := createFile(t, "file", []byte("wazero"))
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		3: {Path: ".", FS: testFS},
+		4: {Path: "file", FS: testFS, File: file},
 	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdAllocate, importFdAllocate, sysCtx)
+	defer mod.Close(testCtx)
+
+	tests := []struct {
+		name          string
+		fd            uint32
+		expectedErrno Errno
+	}{
+		{name: "invalid fd", fd: 42, expectedErrno: ErrnoBadf},
+		{name: "directory", fd: 3, expectedErrno: ErrnoBadf},
+		{name: "read-only file system", fd: 4, expectedErrno: ErrnoRofs},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			errno := a.FdAllocate(testCtx, mod, tc.fd, 0, 10)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+		})
+	}
 }
 
 func TestSnapshotPreview1_FdClose(t *testing.T) {
@@ -550,9 +614,9 @@ func TestSnapshotPreview1_FdClose(t *testing.T) {
 		// fd_close needs to close an open file descriptor. Open two files so that we can tell which is closed.
 		path1, path2 := "a", "b"
 		testFs := fstest.MapFS{path1: {Data: make([]byte, 0)}, path2: {Data: make([]byte, 0)}}
-		entry1, errno := openFileEntry(testFs, path1)
+		entry1, errno := openFileEntry(testFs, path1, 0, 0, rightFdRead)
 		require.Zero(t, errno, ErrnoName(errno))
-		entry2, errno := openFileEntry(testFs, path2)
+		entry2, errno := openFileEntry(testFs, path2, 0, 0, rightFdRead)
 		require.Zero(t, errno, ErrnoName(errno))
 
 		sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
@@ -605,162 +669,60 @@ func TestSnapshotPreview1_FdClose(t *testing.T) {
 	})
 }
 
-// TestSnapshotPreview1_FdDatasync only tests it is stubbed for GrainLang per #271
 func TestSnapshotPreview1_FdDatasync(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionFdDatasync, importFdDatasync, nil)
+	fd := uint32(4) // arbitrary fd after the pre-opened directory
+	_, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, fd, "file")
+
+	a, mod, fn := instantiateModule(testCtx, t, functionFdDatasync, importFdDatasync, sysCtx)
 	defer mod.Close(testCtx)
 
 	t.Run("snapshotPreview1.FdDatasync", func(t *testing.T) {
-		errno := a.FdDatasync(testCtx, mod, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+		errno := a.FdDatasync(testCtx, mod, fd)
+		require.Zero(t, errno, ErrnoName(errno))
 	})
 
 	t.Run(functionFdDatasync, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
-
-// TODO: TestSnapshotPreview1_FdFdstatGet TestSnapshotPreview1_FdFdstatGet_Errors
-func TestSnapshotPreview1_FdFdstatGet(t *testing.T) {
-	t.Skip("TODO")
-	_ = importFdFdstatGet // stop linter complaint until we implement this
-}
-
-// TestSnapshotPreview1_FdFdstatSetFlags only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_FdFdstatSetFlags(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionFdFdstatSetFlags, importFdFdstatSetFlags, nil)
-	defer mod.Close(testCtx)
-
-	t.Run("snapshotPreview1.FdFdstatSetFlags", func(t *testing.T) {
-		errno := a.FdFdstatSetFlags(testCtx, mod, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-
-	t.Run(functionFdFdstatSetFlags, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
-
-// TestSnapshotPreview1_FdFdstatSetRights only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_FdFdstatSetRights(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionFdFdstatSetRights, importFdFdstatSetRights, nil)
-	defer mod.Close(testCtx)
-
-	t.Run("snapshotPreview1.FdFdstatSetRights", func(t *testing.T) {
-		errno := a.FdFdstatSetRights(testCtx, mod, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-
-	t.Run(functionFdFdstatSetRights, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
-
-// TestSnapshotPreview1_FdFilestatGet only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_FdFilestatGet(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionFdFilestatGet, importFdFilestatGet, nil)
-	defer mod.Close(testCtx)
-
-	t.Run("snapshotPreview1.FdFilestatGet", func(t *testing.T) {
-		errno := a.FdFilestatGet(testCtx, mod, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-
-	t.Run(functionFdFilestatGet, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
-
-// TestSnapshotPreview1_FdFilestatSetSize only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_FdFilestatSetSize(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionFdFilestatSetSize, importFdFilestatSetSize, nil)
-	defer mod.Close(testCtx)
-
-	t.Run("snapshotPreview1.FdFilestatSetSize", func(t *testing.T) {
-		errno := a.FdFilestatSetSize(testCtx, mod, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-
-	t.Run(functionFdFilestatSetSize, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0)
+		results, err := fn.Call(testCtx, uint64(fd))
 		require.NoError(t, err)
 		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
-
-// TestSnapshotPreview1_FdFilestatSetTimes only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_FdFilestatSetTimes(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionFdFilestatSetTimes, importFdFilestatSetTimes, nil)
-	defer mod.Close(testCtx)
-
-	t.Run("snapshotPreview1.FdFilestatSetTimes", func(t *testing.T) {
-		errno := a.FdFilestatSetTimes(testCtx, mod, 0, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+		require.Zero(t, errno, ErrnoName(errno))
 	})
 
-	t.Run(functionFdFilestatSetTimes, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	t.Run("invalid fd", func(t *testing.T) {
+		errno := a.FdDatasync(testCtx, mod, 42) // arbitrary invalid fd
+		require.Equal(t, ErrnoBadf, errno, ErrnoName(errno))
 	})
 }
 
-// TestSnapshotPreview1_FdPread only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_FdPread(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionFdPread, importFdPread, nil)
-	defer mod.Close(testCtx)
-
-	t.Run("snapshotPreview1.FdPread", func(t *testing.T) {
-		errno := a.FdPread(testCtx, mod, 0, 0, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
+func TestSnapshotPreview1_FdFdstatGet(t *testing.T) {
+	dirFD, fileFD := uint32(3), uint32(4) // arbitrary fds after 0, 1, and 2, that are stdin/out/err
+	file, testFS := createFile(t, "test_path", []byte("wazero"))
 
-	t.Run(functionFdPread, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		dirFD: {Path: "/", FS: testFS},
+		fileFD: {Path: "test_path", FS: testFS, File: file, Fdflags: fdflagsAppend,
+			Rights: &internalsys.Rights{Base: rightFdRead | rightFdWrite, Inheriting: rightFdRead}},
 	})
-}
-
-func TestSnapshotPreview1_FdPrestatGet(t *testing.T) {
-	fd := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
-
-	pathName := "/tmp"
-	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{fd: {Path: pathName}})
 	require.NoError(t, err)
 
-	a, mod, fn := instantiateModule(testCtx, t, functionFdPrestatGet, importFdPrestatGet, sysCtx)
+	a, mod, fn := instantiateModule(testCtx, t, functionFdFdstatGet, importFdFdstatGet, sysCtx)
 	defer mod.Close(testCtx)
 
-	resultPrestat := uint32(1) // arbitrary offset
+	resultStat := uint32(1) // arbitrary offset
 	expectedMemory := []byte{
-		'?',     // resultPrestat after this
-		0,       // 8-bit tag indicating `prestat_dir`, the only available tag
-		0, 0, 0, // 3-byte padding
-		// the result path length field after this
-		byte(len(pathName)), 0, 0, 0, // = in little endian encoding
+		'?',                    // resultStat is after this
+		filetypeRegularFile, 0, // fs_filetype and padding
+		fdflagsAppend, 0, // fs_flags
+		0, 0, 0, 0, // padding
+		rightFdRead | rightFdWrite, 0, 0, 0, 0, 0, 0, 0, // fs_rights_base
+		rightFdRead, 0, 0, 0, 0, 0, 0, 0, // fs_rights_inheriting
 		'?',
 	}
 
-	t.Run("snapshotPreview1.FdPrestatGet", func(t *testing.T) {
+	t.Run("snapshotPreview1.FdFdstatGet", func(t *testing.T) {
 		maskMemory(t, testCtx, mod, len(expectedMemory))
 
-		errno := a.FdPrestatGet(testCtx, mod, fd, resultPrestat)
+		errno := a.FdFdstatGet(testCtx, mod, fileFD, resultStat)
 		require.Zero(t, errno, ErrnoName(errno))
 
 		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
@@ -768,10 +730,10 @@ func TestSnapshotPreview1_FdPrestatGet(t *testing.T) {
 		require.Equal(t, expectedMemory, actual)
 	})
 
-	t.Run(functionFdPrestatDirName, func(t *testing.T) {
+	t.Run(functionFdFdstatGet, func(t *testing.T) {
 		maskMemory(t, testCtx, mod, len(expectedMemory))
 
-		results, err := fn.Call(testCtx, uint64(fd), uint64(resultPrestat))
+		results, err := fn.Call(testCtx, uint64(fileFD), uint64(resultStat))
 		require.NoError(t, err)
 		errno := Errno(results[0]) // results[0] is the errno
 		require.Zero(t, errno, ErrnoName(errno))
@@ -780,257 +742,417 @@ func TestSnapshotPreview1_FdPrestatGet(t *testing.T) {
 		require.True(t, ok)
 		require.Equal(t, expectedMemory, actual)
 	})
-}
 
-func TestSnapshotPreview1_FdPrestatGet_Errors(t *testing.T) {
-	fd := uint32(3)           // fd 3 will be opened for the "/tmp" directory after 0, 1, and 2, that are stdin/out/err
-	validAddress := uint32(0) // Arbitrary valid address as arguments to fd_prestat_get. We chose 0 here.
+	t.Run("pre-opened directory", func(t *testing.T) {
+		errno := a.FdFdstatGet(testCtx, mod, dirFD, resultStat)
+		require.Zero(t, errno, ErrnoName(errno))
 
-	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{fd: {Path: "/tmp"}})
+		requireFdstat(t, mod, resultStat, filetypeDirectory, 0, rightsAll, rightsAll)
+	})
+
+	t.Run("stdout", func(t *testing.T) {
+		errno := a.FdFdstatGet(testCtx, mod, fdStdout, resultStat)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		requireFdstat(t, mod, resultStat, filetypeCharacterDevice, 0, rightsAll, rightsAll)
+	})
+}
+
+func TestSnapshotPreview1_FdFdstatGet_Errors(t *testing.T) {
+	fd := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{fd: {Path: "/", FS: fstest.MapFS{}}})
 	require.NoError(t, err)
 
-	a, mod, _ := instantiateModule(testCtx, t, functionFdPrestatGet, importFdPrestatGet, sysCtx)
+	a, mod, _ := instantiateModule(testCtx, t, functionFdFdstatGet, importFdFdstatGet, sysCtx)
 	defer mod.Close(testCtx)
 
 	memorySize := mod.Memory().Size(testCtx)
 
 	tests := []struct {
-		name          string
-		fd            uint32
-		resultPrestat uint32
-		expectedErrno Errno
+		name           string
+		fd, resultStat uint32
+		expectedErrno  Errno
 	}{
 		{
-			name:          "invalid FD",
-			fd:            42, // arbitrary invalid FD
-			resultPrestat: validAddress,
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
 			expectedErrno: ErrnoBadf,
 		},
 		{
-			name:          "out-of-memory resultPrestat",
+			name:          "resultStat exceeds the maximum valid address by 1",
 			fd:            fd,
-			resultPrestat: memorySize,
+			resultStat:    memorySize - 24 + 1,
 			expectedErrno: ErrnoFault,
 		},
-		// TODO: non pre-opened file == api.ErrnoBadf
 	}
 
 	for _, tt := range tests {
 		tc := tt
 
 		t.Run(tc.name, func(t *testing.T) {
-			errno := a.FdPrestatGet(testCtx, mod, tc.fd, tc.resultPrestat)
+			errno := a.FdFdstatGet(testCtx, mod, tc.fd, tc.resultStat)
 			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
 		})
 	}
 }
 
-func TestSnapshotPreview1_FdPrestatDirName(t *testing.T) {
-	fd := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
-
-	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{fd: {Path: "/tmp"}})
-	require.NoError(t, err)
+func TestSnapshotPreview1_FdFdstatSetFlags(t *testing.T) {
+	fileFD := uint32(4) // arbitrary fd after the pre-opened directory
+	_, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, fileFD, "file")
 
-	a, mod, fn := instantiateModule(testCtx, t, functionFdPrestatDirName, importFdPrestatDirName, sysCtx)
+	a, mod, fn := instantiateModule(testCtx, t, functionFdFdstatSetFlags, importFdFdstatSetFlags, sysCtx)
 	defer mod.Close(testCtx)
 
-	path := uint32(1)    // arbitrary offset
-	pathLen := uint32(3) // shorter than len("/tmp") to test the path is written for the length of pathLen
-	expectedMemory := []byte{
-		'?',
-		'/', 't', 'm',
-		'?', '?', '?',
-	}
-
-	t.Run("snapshotPreview1.FdPrestatDirName", func(t *testing.T) {
-		maskMemory(t, testCtx, mod, len(expectedMemory))
+	_, fsc := sysFSCtx(testCtx, mod)
 
-		errno := a.FdPrestatDirName(testCtx, mod, fd, path, pathLen)
+	t.Run("snapshotPreview1.FdFdstatSetFlags", func(t *testing.T) {
+		errno := a.FdFdstatSetFlags(testCtx, mod, fileFD, fdflagsAppend)
 		require.Zero(t, errno, ErrnoName(errno))
 
-		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		f, ok := fsc.OpenedFile(fileFD)
 		require.True(t, ok)
-		require.Equal(t, expectedMemory, actual)
+		require.Equal(t, uint16(fdflagsAppend), f.Fdflags)
 	})
 
-	t.Run(functionFdPrestatDirName, func(t *testing.T) {
-		maskMemory(t, testCtx, mod, len(expectedMemory))
-
-		results, err := fn.Call(testCtx, uint64(fd), uint64(path), uint64(pathLen))
+	t.Run(functionFdFdstatSetFlags, func(t *testing.T) {
+		results, err := fn.Call(testCtx, uint64(fileFD), fdflagsSync)
 		require.NoError(t, err)
 		errno := Errno(results[0]) // results[0] is the errno
 		require.Zero(t, errno, ErrnoName(errno))
 
-		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		f, ok := fsc.OpenedFile(fileFD)
 		require.True(t, ok)
-		require.Equal(t, expectedMemory, actual)
+		require.Equal(t, uint16(fdflagsSync), f.Fdflags)
+	})
+
+	t.Run("stdin", func(t *testing.T) {
+		errno := a.FdFdstatSetFlags(testCtx, mod, fdStdin, fdflagsNonblock)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		require.Equal(t, uint16(fdflagsNonblock), fsc.StdioFdflags(fdStdin))
 	})
 }
 
-func TestSnapshotPreview1_FdPrestatDirName_Errors(t *testing.T) {
-	fd := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
-	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{fd: {Path: "/tmp"}})
+func TestSnapshotPreview1_FdFdstatSetFlags_Errors(t *testing.T) {
+	fileFD, readOnlyFD := uint32(4), uint32(5) // arbitrary fds after the pre-opened directory
+	file, testFS := createFile(t, "test_path", []byte{})
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		fileFD:     {Path: "test_path", FS: testFS, File: file},
+		readOnlyFD: {Path: "test_path", FS: testFS, File: file, Rights: &internalsys.Rights{Base: rightFdRead}},
+	})
 	require.NoError(t, err)
 
-	a, mod, _ := instantiateModule(testCtx, t, functionFdPrestatDirName, importFdPrestatDirName, sysCtx)
+	a, mod, _ := instantiateModule(testCtx, t, functionFdFdstatSetFlags, importFdFdstatSetFlags, sysCtx)
 	defer mod.Close(testCtx)
 
-	memorySize := mod.Memory().Size(testCtx)
-	validAddress := uint32(0) // Arbitrary valid address as arguments to fd_prestat_dir_name. We chose 0 here.
-	pathLen := uint32(len("/tmp"))
-
 	tests := []struct {
 		name          string
-		fd            uint32
-		path          uint32
-		pathLen       uint32
+		fd, flags     uint32
 		expectedErrno Errno
 	}{
 		{
-			name:          "out-of-memory path",
-			fd:            fd,
-			path:          memorySize,
-			pathLen:       pathLen,
-			expectedErrno: ErrnoFault,
-		},
-		{
-			name:          "path exceeds the maximum valid address by 1",
-			fd:            fd,
-			path:          memorySize - pathLen + 1,
-			pathLen:       pathLen,
-			expectedErrno: ErrnoFault,
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
+			expectedErrno: ErrnoBadf,
 		},
 		{
-			name:          "pathLen exceeds the length of the dir name",
-			fd:            fd,
-			path:          validAddress,
-			pathLen:       pathLen + 1,
-			expectedErrno: ErrnoNametoolong,
+			name:          "undefined flags",
+			fd:            fileFD,
+			flags:         fdflagsSync << 1,
+			expectedErrno: ErrnoInval,
 		},
 		{
-			name:          "invalid fd",
-			fd:            42, // arbitrary invalid fd
-			path:          validAddress,
-			pathLen:       pathLen,
-			expectedErrno: ErrnoBadf,
+			name:          "without rights",
+			fd:            readOnlyFD,
+			flags:         fdflagsAppend,
+			expectedErrno: ErrnoNotcapable,
 		},
-		// TODO: non pre-opened file == wasi.ErrnoBadf
 	}
 
 	for _, tt := range tests {
 		tc := tt
 
 		t.Run(tc.name, func(t *testing.T) {
-			errno := a.FdPrestatDirName(testCtx, mod, tc.fd, tc.path, tc.pathLen)
+			errno := a.FdFdstatSetFlags(testCtx, mod, tc.fd, tc.flags)
 			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
 		})
 	}
 }
 
-// TestSnapshotPreview1_FdPwrite only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_FdPwrite(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionFdPwrite, importFdPwrite, nil)
+func TestSnapshotPreview1_FdFdstatSetRights(t *testing.T) {
+	fileFD := uint32(4) // arbitrary fd after the pre-opened directory
+	_, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, fileFD, "file")
+
+	a, mod, fn := instantiateModule(testCtx, t, functionFdFdstatSetRights, importFdFdstatSetRights, sysCtx)
 	defer mod.Close(testCtx)
 
-	t.Run("snapshotPreview1.FdPwrite", func(t *testing.T) {
-		errno := a.FdPwrite(testCtx, mod, 0, 0, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
+	_, fsc := sysFSCtx(testCtx, mod)
 
-	t.Run(functionFdPwrite, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	t.Run("snapshotPreview1.FdFdstatSetRights", func(t *testing.T) {
+		errno := a.FdFdstatSetRights(testCtx, mod, fileFD, rightFdRead|rightFdWrite, rightFdRead)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		f, ok := fsc.OpenedFile(fileFD)
+		require.True(t, ok)
+		require.Equal(t, &internalsys.Rights{Base: rightFdRead | rightFdWrite, Inheriting: rightFdRead}, f.Rights)
+	})
+
+	t.Run(functionFdFdstatSetRights, func(t *testing.T) {
+		results, err := fn.Call(testCtx, uint64(fileFD), rightFdRead, 0)
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+
+		f, ok := fsc.OpenedFile(fileFD)
+		require.True(t, ok)
+		require.Equal(t, &internalsys.Rights{Base: rightFdRead}, f.Rights)
+	})
+
+	t.Run("rights can't be added back", func(t *testing.T) {
+		errno := a.FdFdstatSetRights(testCtx, mod, fileFD, rightFdRead|rightFdWrite, 0)
+		require.Equal(t, ErrnoNotcapable, errno, ErrnoName(errno))
+
+		errno = a.FdFdstatSetRights(testCtx, mod, fileFD, rightFdRead, rightFdRead)
+		require.Equal(t, ErrnoNotcapable, errno, ErrnoName(errno))
 	})
 }
 
-func TestSnapshotPreview1_FdRead(t *testing.T) {
-	fd := uint32(3)   // arbitrary fd after 0, 1, and 2, that are stdin/out/err
-	iovs := uint32(1) // arbitrary offset
-	initialMemory := []byte{
-		'?',         // `iovs` is after this
-		18, 0, 0, 0, // = iovs[0].offset
-		4, 0, 0, 0, // = iovs[0].length
-		23, 0, 0, 0, // = iovs[1].offset
-		2, 0, 0, 0, // = iovs[1].length
-		'?',
-	}
-	iovsCount := uint32(2)   // The count of iovs
-	resultSize := uint32(26) // arbitrary offset
-	expectedMemory := append(
-		initialMemory,
-		'w', 'a', 'z', 'e', // iovs[0].length bytes
-		'?',      // iovs[1].offset is after this
-		'r', 'o', // iovs[1].length bytes
-		'?',        // resultSize is after this
-		6, 0, 0, 0, // sum(iovs[...].length) == length of "wazero"
-		'?',
-	)
+func TestSnapshotPreview1_FdFdstatSetRights_Errors(t *testing.T) {
+	a, mod, _ := instantiateModule(testCtx, t, functionFdFdstatSetRights, importFdFdstatSetRights, nil)
+	defer mod.Close(testCtx)
 
-	// TestSnapshotPreview1_FdRead uses a matrix because setting up test files is complicated and has to be clean each time.
-	type fdReadFn func(ctx context.Context, m api.Module, fd, iovs, iovsCount, resultSize uint32) Errno
 	tests := []struct {
-		name   string
-		fdRead func(*snapshotPreview1, api.Module, api.Function) fdReadFn
+		name          string
+		fd            uint32
+		expectedErrno Errno
 	}{
-		{"snapshotPreview1.FdRead", func(a *snapshotPreview1, _ api.Module, _ api.Function) fdReadFn {
-			return a.FdRead
-		}},
-		{functionFdRead, func(_ *snapshotPreview1, mod api.Module, fn api.Function) fdReadFn {
-			return func(ctx context.Context, m api.Module, fd, iovs, iovsCount, resultSize uint32) Errno {
-				results, err := fn.Call(testCtx, uint64(fd), uint64(iovs), uint64(iovsCount), uint64(resultSize))
-				require.NoError(t, err)
-				return Errno(results[0])
-			}
-		}},
+		{
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
+			expectedErrno: ErrnoBadf,
+		},
+		{
+			name:          "stdout",
+			fd:            fdStdout,
+			expectedErrno: ErrnoNotsup,
+		},
 	}
 
 	for _, tt := range tests {
 		tc := tt
+
 		t.Run(tc.name, func(t *testing.T) {
-			// Create a fresh file to read the contents from
-			file, testFS := createFile(t, "test_path", []byte("wazero"))
-			sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
-				fd: {Path: "test_path", FS: testFS, File: file},
-			})
-			require.NoError(t, err)
+			errno := a.FdFdstatSetRights(testCtx, mod, tc.fd, 0, 0)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+		})
+	}
+}
 
-			a, mod, fn := instantiateModule(testCtx, t, functionFdRead, importFdRead, sysCtx)
-			defer mod.Close(testCtx)
+// TestSnapshotPreview1_Rights ensures functions return wasi.ErrnoNotcapable when a file descriptor lacks the rights.
+func TestSnapshotPreview1_Rights(t *testing.T) {
+	dirFD, fd := uint32(3), uint32(4) // arbitrary fd after the pre-opened directory
+	_, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, fd, "file")
 
-			maskMemory(t, testCtx, mod, len(expectedMemory))
+	a, mod, _ := instantiateModule(testCtx, t, functionFdWrite, importFdWrite, sysCtx)
+	defer mod.Close(testCtx)
 
-			ok := mod.Memory().Write(testCtx, 0, initialMemory)
-			require.True(t, ok)
+	_, fsc := sysFSCtx(testCtx, mod)
 
-			errno := tc.fdRead(a, mod, fn)(testCtx, mod, fd, iovs, iovsCount, resultSize)
-			require.Zero(t, errno, ErrnoName(errno))
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	lnFD, ok := fsc.OpenFile(&internalsys.FileEntry{Path: ln.Addr().String(), File: &internalsys.ListenerFile{Listener: ln}})
+	require.True(t, ok)
 
-			actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+	guest, host := net.Pipe()
+	defer host.Close()
+	connFD, ok := fsc.OpenFile(&internalsys.FileEntry{Path: "pipe", File: &internalsys.ConnFile{Conn: guest}})
+	require.True(t, ok)
+
+	tests := []struct {
+		name  string
+		fd    uint32
+		right uint64
+		fn    func() Errno
+	}{
+		{"fd_read", fd, rightFdRead, func() Errno { return a.FdRead(testCtx, mod, fd, 0, 0, 0) }},
+		{"fd_pread", fd, rightFdRead, func() Errno { return a.FdPread(testCtx, mod, fd, 0, 0, 0, 0) }},
+		{"fd_write", fd, rightFdWrite, func() Errno { return a.FdWrite(testCtx, mod, fd, 0, 0, 0) }},
+		{"fd_pwrite", fd, rightFdWrite, func() Errno { return a.FdPwrite(testCtx, mod, fd, 0, 0, 0, 0) }},
+		{"fd_seek", fd, rightFdSeek, func() Errno { return a.FdSeek(testCtx, mod, fd, 0, io.SeekStart, 0) }},
+		{"fd_tell", fd, rightFdTell, func() Errno { return a.FdTell(testCtx, mod, fd, 0) }},
+		{"fd_fdstat_set_flags", fd, rightFdFdstatSetFlags, func() Errno { return a.FdFdstatSetFlags(testCtx, mod, fd, 0) }},
+		{"fd_allocate", fd, rightFdAllocate, func() Errno { return a.FdAllocate(testCtx, mod, fd, 0, 1) }},
+		{"fd_datasync", fd, rightFdDatasync, func() Errno { return a.FdDatasync(testCtx, mod, fd) }},
+		{"fd_sync", fd, rightFdSync, func() Errno { return a.FdSync(testCtx, mod, fd) }},
+		{"fd_filestat_get", fd, rightFdFilestatGet, func() Errno { return a.FdFilestatGet(testCtx, mod, fd, 0) }},
+		{"fd_filestat_set_size", fd, rightFdFilestatSetSize, func() Errno { return a.FdFilestatSetSize(testCtx, mod, fd, 0) }},
+		{"fd_filestat_set_times", fd, rightFdFilestatSetTimes, func() Errno {
+			return a.FdFilestatSetTimes(testCtx, mod, fd, 0, 0, fstflagsAtimNow)
+		}},
+		{"fd_readdir", dirFD, rightFdReaddir, func() Errno { return a.FdReaddir(testCtx, mod, dirFD, 0, 0, 0, 0) }},
+		{"path_create_directory", dirFD, rightPathCreateDirectory, func() Errno {
+			return a.PathCreateDirectory(testCtx, mod, dirFD, 0, 0)
+		}},
+		{"path_filestat_get", dirFD, rightPathFilestatGet, func() Errno {
+			return a.PathFilestatGet(testCtx, mod, dirFD, 0, 0, 0, 0)
+		}},
+		{"path_filestat_set_times", dirFD, rightPathFilestatSetTimes, func() Errno {
+			return a.PathFilestatSetTimes(testCtx, mod, dirFD, 0, 0, 0, 0, 0, fstflagsAtimNow)
+		}},
+		{"path_link source", dirFD, rightPathLinkSource, func() Errno {
+			return a.PathLink(testCtx, mod, dirFD, 0, 0, 0, dirFD, 0, 0)
+		}},
+		{"path_link target", dirFD, rightPathLinkTarget, func() Errno {
+			return a.PathLink(testCtx, mod, dirFD, 0, 0, 0, dirFD, 0, 0)
+		}},
+		{"path_open", dirFD, rightPathOpen, func() Errno { return a.PathOpen(testCtx, mod, dirFD, 0, 0, 0, 0, 0, 0, 0, 0) }},
+		{"path_remove_directory", dirFD, rightPathRemoveDirectory, func() Errno {
+			return a.PathRemoveDirectory(testCtx, mod, dirFD, 0, 0)
+		}},
+		{"path_rename source", dirFD, rightPathRenameSource, func() Errno {
+			return a.PathRename(testCtx, mod, dirFD, 0, 0, dirFD, 0, 0)
+		}},
+		{"path_rename target", dirFD, rightPathRenameTarget, func() Errno {
+			return a.PathRename(testCtx, mod, dirFD, 0, 0, dirFD, 0, 0)
+		}},
+		{"path_symlink", dirFD, rightPathSymlink, func() Errno { return a.PathSymlink(testCtx, mod, 0, 0, dirFD, 0, 0) }},
+		{"path_unlink_file", dirFD, rightPathUnlinkFile, func() Errno { return a.PathUnlinkFile(testCtx, mod, dirFD, 0, 0) }},
+		{"sock_accept", lnFD, rightSockAccept, func() Errno { return a.SockAccept(testCtx, mod, lnFD, 0, 0) }},
+		{"sock_recv", connFD, rightFdRead, func() Errno { return a.SockRecv(testCtx, mod, connFD, 0, 0, 0, 0, 0) }},
+		{"sock_send", connFD, rightFdWrite, func() Errno { return a.SockSend(testCtx, mod, connFD, 0, 0, 0, 0) }},
+		{"sock_shutdown", connFD, rightSockShutdown, func() Errno {
+			return a.SockShutdown(testCtx, mod, connFD, sdflagsRd|sdflagsWr)
+		}},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			f, ok := fsc.OpenedFile(tc.fd)
 			require.True(t, ok)
-			require.Equal(t, expectedMemory, actual)
+			// Only the right under test is missing.
+			f.Rights = &internalsys.Rights{Base: rightsAll &^ tc.right, Inheriting: rightsAll}
+			defer func() { f.Rights = nil }()
+
+			errno := tc.fn()
+			require.Equal(t, ErrnoNotcapable, errno, ErrnoName(errno))
 		})
 	}
 }
 
-func TestSnapshotPreview1_FdRead_Errors(t *testing.T) {
-	validFD := uint32(3)                                 // arbitrary valid fd after 0, 1, and 2, that are stdin/out/err
-	file, testFS := createFile(t, "test_path", []byte{}) // file with empty contents
+func TestSnapshotPreview1_SockAccept_InheritingRights(t *testing.T) {
+	lnFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer ln.Close()
 
 	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
-		validFD: {Path: "test_path", FS: testFS, File: file},
+		lnFD: {
+			Path:   ln.Addr().String(),
+			File:   &internalsys.ListenerFile{Listener: ln},
+			Rights: &internalsys.Rights{Base: rightSockAccept, Inheriting: rightFdRead},
+		},
 	})
 	require.NoError(t, err)
 
-	a, mod, _ := instantiateModule(testCtx, t, functionFdRead, importFdRead, sysCtx)
+	a, mod, _ := instantiateModule(testCtx, t, functionSockAccept, importSockAccept, sysCtx)
+	defer mod.Close(testCtx)
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	resultFd := uint32(1) // arbitrary offset
+	errno := a.SockAccept(testCtx, mod, lnFD, 0, resultFd)
+	require.Zero(t, errno, ErrnoName(errno))
+
+	connFD, ok := mod.Memory().ReadUint32Le(testCtx, resultFd)
+	require.True(t, ok)
+
+	// The connection can be read, but not written, as the listener only grants reading.
+	errno = a.SockSend(testCtx, mod, connFD, 0, 0, 0, 0)
+	require.Equal(t, ErrnoNotcapable, errno, ErrnoName(errno))
+}
+
+func TestSnapshotPreview1_FdFilestatGet(t *testing.T) {
+	dirFD, fileFD := uint32(3), uint32(4) // arbitrary fds after 0, 1, and 2, that are stdin/out/err
+	testFS := fstest.MapFS{"file": &fstest.MapFile{Data: []byte("wazero"), ModTime: time.Unix(0, int64(epochNanos))}}
+	file, err := testFS.Open("file")
+	require.NoError(t, err)
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		dirFD:  {Path: "/", FS: testFS},
+		fileFD: {Path: "file", FS: testFS, File: file, Dev: uint64(dirFD)},
+	})
+	require.NoError(t, err)
+
+	a, mod, fn := instantiateModule(testCtx, t, functionFdFilestatGet, importFdFilestatGet, sysCtx)
+	defer mod.Close(testCtx)
+
+	inodeFile := make([]byte, 8)
+	binary.LittleEndian.PutUint64(inodeFile, inode("file"))
+
+	resultBuf := uint32(1) // arbitrary offset
+	expectedMemory := append(append([]byte{
+		'?',                    // resultBuf is after this
+		3, 0, 0, 0, 0, 0, 0, 0, // dev == dirFD
+	}, inodeFile...), // ino
+		filetypeRegularFile, 0, 0, 0, 0, 0, 0, 0, // filetype and padding
+		1, 0, 0, 0, 0, 0, 0, 0, // nlink
+		6, 0, 0, 0, 0, 0, 0, 0, // size == len("wazero")
+		0x0, 0x0, 0x1f, 0xa6, 0x70, 0xfc, 0xc5, 0x16, // atim == epochNanos
+		0x0, 0x0, 0x1f, 0xa6, 0x70, 0xfc, 0xc5, 0x16, // mtim == epochNanos
+		0x0, 0x0, 0x1f, 0xa6, 0x70, 0xfc, 0xc5, 0x16, // ctim == epochNanos
+		'?',
+	)
+
+	t.Run("snapshotPreview1.FdFilestatGet", func(t *testing.T) {
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+
+		errno := a.FdFilestatGet(testCtx, mod, fileFD, resultBuf)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+	})
+
+	t.Run(functionFdFilestatGet, func(t *testing.T) {
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+
+		results, err := fn.Call(testCtx, uint64(fileFD), uint64(resultBuf))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+	})
+
+	t.Run("pre-opened directory", func(t *testing.T) {
+		errno := a.FdFilestatGet(testCtx, mod, dirFD, resultBuf)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		requireFilestat(t, mod, resultBuf, inode("."), filetypeDirectory, 0)
+	})
+}
+
+func TestSnapshotPreview1_FdFilestatGet_Errors(t *testing.T) {
+	fd := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{fd: {Path: "/", FS: fstest.MapFS{}}})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdFilestatGet, importFdFilestatGet, sysCtx)
 	defer mod.Close(testCtx)
 
+	memorySize := mod.Memory().Size(testCtx)
+
 	tests := []struct {
-		name                            string
-		fd, iovs, iovsCount, resultSize uint32
-		memory                          []byte
-		expectedErrno                   Errno
+		name          string
+		fd, resultBuf uint32
+		expectedErrno Errno
 	}{
 		{
 			name:          "invalid fd",
@@ -1038,280 +1160,2793 @@ func TestSnapshotPreview1_FdRead_Errors(t *testing.T) {
 			expectedErrno: ErrnoBadf,
 		},
 		{
-			name:          "out-of-memory reading iovs[0].offset",
-			fd:            validFD,
-			iovs:          1,
-			memory:        []byte{'?'},
-			expectedErrno: ErrnoFault,
-		},
-		{
-			name: "out-of-memory reading iovs[0].length",
-			fd:   validFD,
-			iovs: 1, iovsCount: 1,
-			memory: []byte{
-				'?',        // `iovs` is after this
-				9, 0, 0, 0, // = iovs[0].offset
-			},
-			expectedErrno: ErrnoFault,
-		},
-		{
-			name: "iovs[0].offset is outside memory",
-			fd:   validFD,
-			iovs: 1, iovsCount: 1,
-			memory: []byte{
-				'?',          // `iovs` is after this
-				0, 0, 0x1, 0, // = iovs[0].offset on the second page
-				1, 0, 0, 0, // = iovs[0].length
-			},
-			expectedErrno: ErrnoFault,
-		},
-		{
-			name: "length to read exceeds memory by 1",
-			fd:   validFD,
-			iovs: 1, iovsCount: 1,
-			memory: []byte{
-				'?',        // `iovs` is after this
-				9, 0, 0, 0, // = iovs[0].offset
-				0, 0, 0x1, 0, // = iovs[0].length on the second page
-				'?',
-			},
-			expectedErrno: ErrnoFault,
-		},
-		{
-			name: "resultSize offset is outside memory",
-			fd:   validFD,
-			iovs: 1, iovsCount: 1,
-			resultSize: 10, // 1 past memory
-			memory: []byte{
-				'?',        // `iovs` is after this
-				9, 0, 0, 0, // = iovs[0].offset
-				1, 0, 0, 0, // = iovs[0].length
-				'?',
-			},
+			name:          "resultBuf exceeds the maximum valid address by 1",
+			fd:            fd,
+			resultBuf:     memorySize - filestatLen + 1,
 			expectedErrno: ErrnoFault,
 		},
 	}
 
 	for _, tt := range tests {
 		tc := tt
-		t.Run(tc.name, func(t *testing.T) {
-			offset := uint32(wasm.MemoryPagesToBytesNum(testMemoryPageSize) - uint64(len(tc.memory)))
-
-			memoryWriteOK := mod.Memory().Write(testCtx, offset, tc.memory)
-			require.True(t, memoryWriteOK)
 
-			errno := a.FdRead(testCtx, mod, tc.fd, tc.iovs+offset, tc.iovsCount+offset, tc.resultSize+offset)
+		t.Run(tc.name, func(t *testing.T) {
+			errno := a.FdFilestatGet(testCtx, mod, tc.fd, tc.resultBuf)
 			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
 		})
 	}
-}
+}
+
+func TestSnapshotPreview1_FdFilestatSetSize(t *testing.T) {
+	fileFD := uint32(4) // arbitrary fd after the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, fileFD, "file")
+
+	a, mod, fn := instantiateModule(testCtx, t, functionFdFilestatSetSize, importFdFilestatSetSize, sysCtx)
+	defer mod.Close(testCtx)
+
+	t.Run("snapshotPreview1.FdFilestatSetSize", func(t *testing.T) {
+		errno := a.FdFilestatSetSize(testCtx, mod, fileFD, 2)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		b, err := fs.ReadFile(wfs, "file")
+		require.NoError(t, err)
+		require.Equal(t, "wa", string(b))
+	})
+
+	t.Run(functionFdFilestatSetSize, func(t *testing.T) {
+		results, err := fn.Call(testCtx, uint64(fileFD), 4)
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+
+		b, err := fs.ReadFile(wfs, "file")
+		require.NoError(t, err)
+		require.Equal(t, []byte{'w', 'a', 0, 0}, b)
+	})
+
+	t.Run("unlinked", func(t *testing.T) {
+		// The open file is resized, not a new file at its original name.
+		require.NoError(t, wfs.Remove("file"))
+		f, err := wfs.OpenFile("file", os.O_RDWR|os.O_CREATE, 0o600)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		errno := a.FdFilestatSetSize(testCtx, mod, fileFD, 8)
+		require.Zero(t, errno, ErrnoName(errno))
+		requireFileSize(t, wfs, "file", 0)
+	})
+
+	t.Run("too large", func(t *testing.T) {
+		errno := a.FdFilestatSetSize(testCtx, mod, fileFD, math.MaxInt64+1)
+		require.Equal(t, ErrnoFbig, errno, ErrnoName(errno))
+	})
+
+	t.Run("invalid fd", func(t *testing.T) {
+		errno := a.FdFilestatSetSize(testCtx, mod, 42, 0)
+		require.Equal(t, ErrnoBadf, errno, ErrnoName(errno))
+	})
+}
+
+func TestSnapshotPreview1_FdFilestatSetTimes(t *testing.T) {
+	fileFD := uint32(4) // arbitrary fd after the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, fileFD, "file")
+
+	a, mod, fn := instantiateModule(testCtx, t, functionFdFilestatSetTimes, importFdFilestatSetTimes, sysCtx)
+	defer mod.Close(testCtx)
+
+	mtim := uint64(1234567890)
+	t.Run("snapshotPreview1.FdFilestatSetTimes", func(t *testing.T) {
+		errno := a.FdFilestatSetTimes(testCtx, mod, fileFD, 0, mtim, fstflagsMtim)
+		require.Zero(t, errno, ErrnoName(errno))
+		requireModTime(t, wfs, "file", mtim)
+	})
+
+	t.Run(functionFdFilestatSetTimes, func(t *testing.T) {
+		// The current time is read from the fake clock in testCtx.
+		results, err := fn.Call(testCtx, uint64(fileFD), 0, 0, fstflagsAtimNow|fstflagsMtimNow)
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+		requireModTime(t, wfs, "file", epochNanos)
+	})
+
+	t.Run("renamed", func(t *testing.T) {
+		// The open file is changed, not a new file at its original name.
+		require.NoError(t, wfs.Rename("file", "renamed"))
+		f, err := wfs.OpenFile("file", os.O_RDWR|os.O_CREATE, 0o600)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		require.NoError(t, wfs.Chtimes("file", time.Unix(0, 0), time.Unix(0, 0)))
+
+		errno := a.FdFilestatSetTimes(testCtx, mod, fileFD, 0, mtim, fstflagsMtim)
+		require.Zero(t, errno, ErrnoName(errno))
+		requireModTime(t, wfs, "renamed", mtim)
+		requireModTime(t, wfs, "file", 0)
+	})
+
+	t.Run("both time and now", func(t *testing.T) {
+		errno := a.FdFilestatSetTimes(testCtx, mod, fileFD, 0, mtim, fstflagsMtim|fstflagsMtimNow)
+		require.Equal(t, ErrnoInval, errno, ErrnoName(errno))
+	})
+
+	t.Run("invalid fd", func(t *testing.T) {
+		errno := a.FdFilestatSetTimes(testCtx, mod, 42, 0, mtim, fstflagsMtim)
+		require.Equal(t, ErrnoBadf, errno, ErrnoName(errno))
+	})
+}
+
+func TestSnapshotPreview1_FdPread(t *testing.T) {
+	fd := uint32(4)   // arbitrary fd after the pre-opened directory
+	iovs := uint32(1) // arbitrary offset
+	initialMemory := []byte{
+		'?',         // `iovs` is after this
+		18, 0, 0, 0, // = iovs[0].offset
+		4, 0, 0, 0, // = iovs[0].length
+		23, 0, 0, 0, // = iovs[1].offset
+		2, 0, 0, 0, // = iovs[1].length
+		'?',
+	}
+	iovsCount := uint32(2)    // The count of iovs
+	resultNread := uint32(26) // arbitrary offset
+	expectedMemory := append(
+		initialMemory,
+		'w', 'a', 'z', 'e', // iovs[0].length bytes
+		'?',      // iovs[1].offset is after this
+		'r', 'o', // iovs[1].length bytes
+		'?',        // resultNread is after this
+		6, 0, 0, 0, // sum(iovs[...].length) == length of "wazero"
+		'?',
+	)
+
+	_, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "..wazero"}, fd, "file")
+	a, mod, fn := instantiateModule(testCtx, t, functionFdPread, importFdPread, sysCtx)
+	defer mod.Close(testCtx)
+
+	offset := uint64(2) // skips ".."
+
+	t.Run("snapshotPreview1.FdPread", func(t *testing.T) {
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+		require.True(t, mod.Memory().Write(testCtx, 0, initialMemory))
+
+		errno := a.FdPread(testCtx, mod, fd, iovs, iovsCount, offset, resultNread)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+	})
+
+	t.Run(functionFdPread, func(t *testing.T) {
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+		require.True(t, mod.Memory().Write(testCtx, 0, initialMemory))
+
+		results, err := fn.Call(testCtx, uint64(fd), uint64(iovs), uint64(iovsCount), offset, uint64(resultNread))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+	})
+
+	t.Run("doesn't change the offset", func(t *testing.T) {
+		_, fsc := sysFSCtx(testCtx, mod)
+		f, ok := fsc.OpenedFile(fd)
+		require.True(t, ok)
+
+		current, err := f.File.(io.Seeker).Seek(0, io.SeekCurrent)
+		require.NoError(t, err)
+		require.Zero(t, current)
+	})
+}
+
+func TestSnapshotPreview1_FdPread_Errors(t *testing.T) {
+	fileFD, dirFD := uint32(4), uint32(5) // arbitrary fds after the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero", "dir/file": ""}, fileFD, "file")
+	dir, err := wfs.Open("dir")
+	require.NoError(t, err)
+	_, ok := sysCtx.FS().OpenFile(&internalsys.FileEntry{Path: "dir", FS: wfs, File: dir})
+	require.True(t, ok)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdPread, importFdPread, sysCtx)
+	defer mod.Close(testCtx)
+
+	tests := []struct {
+		name                             string
+		fd, iovs, iovsCount, resultNread uint32
+		fileOffset                       uint64
+		memory                           []byte
+		expectedErrno                    Errno
+	}{
+		{
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
+			expectedErrno: ErrnoBadf,
+		},
+		{
+			name:          "not io.ReaderAt",
+			fd:            dirFD,
+			expectedErrno: ErrnoSpipe,
+		},
+		{
+			name:          "offset past math.MaxInt64",
+			fd:            fileFD,
+			fileOffset:    math.MaxInt64 + 1,
+			expectedErrno: ErrnoInval,
+		},
+		{
+			name:          "out-of-memory reading iovs[0].offset",
+			fd:            fileFD,
+			iovs:          1,
+			iovsCount:     1,
+			memory:        []byte{'?'},
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name: "length to read exceeds memory by 1",
+			fd:   fileFD,
+			iovs: 1, iovsCount: 1,
+			memory: []byte{
+				'?',        // `iovs` is after this
+				9, 0, 0, 0, // = iovs[0].offset
+				0, 0, 0x1, 0, // = iovs[0].length on the second page
+				'?',
+			},
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name: "resultNread offset is outside memory",
+			fd:   fileFD,
+			iovs: 1, iovsCount: 1,
+			resultNread: 10, // 1 past memory
+			memory: []byte{
+				'?',        // `iovs` is after this
+				9, 0, 0, 0, // = iovs[0].offset
+				1, 0, 0, 0, // = iovs[0].length
+				'?',
+			},
+			expectedErrno: ErrnoFault,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			offset := uint32(wasm.MemoryPagesToBytesNum(testMemoryPageSize) - uint64(len(tc.memory)))
+
+			memoryWriteOK := mod.Memory().Write(testCtx, offset, tc.memory)
+			require.True(t, memoryWriteOK)
+
+			errno := a.FdPread(testCtx, mod, tc.fd, tc.iovs+offset, tc.iovsCount, tc.fileOffset, tc.resultNread+offset)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+		})
+	}
+}
+
+func TestSnapshotPreview1_FdPrestatGet(t *testing.T) {
+	fd := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+
+	pathName := "/tmp"
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{fd: {Path: pathName}})
+	require.NoError(t, err)
+
+	a, mod, fn := instantiateModule(testCtx, t, functionFdPrestatGet, importFdPrestatGet, sysCtx)
+	defer mod.Close(testCtx)
+
+	resultPrestat := uint32(1) // arbitrary offset
+	expectedMemory := []byte{
+		'?',     // resultPrestat after this
+		0,       // 8-bit tag indicating `prestat_dir`, the only available tag
+		0, 0, 0, // 3-byte padding
+		// the result path length field after this
+		byte(len(pathName)), 0, 0, 0, // = in little endian encoding
+		'?',
+	}
+
+	t.Run("snapshotPreview1.FdPrestatGet", func(t *testing.T) {
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+
+		errno := a.FdPrestatGet(testCtx, mod, fd, resultPrestat)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+	})
+
+	t.Run(functionFdPrestatDirName, func(t *testing.T) {
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+
+		results, err := fn.Call(testCtx, uint64(fd), uint64(resultPrestat))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+	})
+}
+
+func TestSnapshotPreview1_FdPrestatGet_Errors(t *testing.T) {
+	fd := uint32(3)           // fd 3 will be opened for the "/tmp" directory after 0, 1, and 2, that are stdin/out/err
+	validAddress := uint32(0) // Arbitrary valid address as arguments to fd_prestat_get. We chose 0 here.
+
+	fileFD := uint32(4) // fd 4 will be opened for a file, which is not pre-opened
+	file, testFS := createFile(t, "test_path", []byte{})
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		fd:     {Path: "/tmp"},
+		fileFD: {Path: "test_path", FS: testFS, File: file},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdPrestatGet, importFdPrestatGet, sysCtx)
+	defer mod.Close(testCtx)
+
+	memorySize := mod.Memory().Size(testCtx)
+
+	tests := []struct {
+		name          string
+		fd            uint32
+		resultPrestat uint32
+		expectedErrno Errno
+	}{
+		{
+			name:          "invalid FD",
+			fd:            42, // arbitrary invalid FD
+			resultPrestat: validAddress,
+			expectedErrno: ErrnoBadf,
+		},
+		{
+			name:          "out-of-memory resultPrestat",
+			fd:            fd,
+			resultPrestat: memorySize,
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name:          "non pre-opened file",
+			fd:            fileFD,
+			resultPrestat: validAddress,
+			expectedErrno: ErrnoBadf,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			errno := a.FdPrestatGet(testCtx, mod, tc.fd, tc.resultPrestat)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+		})
+	}
+}
+
+func TestSnapshotPreview1_FdPrestatDirName(t *testing.T) {
+	fd := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{fd: {Path: "/tmp"}})
+	require.NoError(t, err)
+
+	a, mod, fn := instantiateModule(testCtx, t, functionFdPrestatDirName, importFdPrestatDirName, sysCtx)
+	defer mod.Close(testCtx)
+
+	path := uint32(1)    // arbitrary offset
+	pathLen := uint32(3) // shorter than len("/tmp") to test the path is written for the length of pathLen
+	expectedMemory := []byte{
+		'?',
+		'/', 't', 'm',
+		'?', '?', '?',
+	}
+
+	t.Run("snapshotPreview1.FdPrestatDirName", func(t *testing.T) {
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+
+		errno := a.FdPrestatDirName(testCtx, mod, fd, path, pathLen)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+	})
+
+	t.Run(functionFdPrestatDirName, func(t *testing.T) {
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+
+		results, err := fn.Call(testCtx, uint64(fd), uint64(path), uint64(pathLen))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+	})
+}
+
+func TestSnapshotPreview1_FdPrestatDirName_Errors(t *testing.T) {
+	fd := uint32(3)     // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	fileFD := uint32(4) // arbitrary fd for a file, which is not pre-opened
+	file, testFS := createFile(t, "test_path", []byte{})
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		fd:     {Path: "/tmp"},
+		fileFD: {Path: "test_path", FS: testFS, File: file},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdPrestatDirName, importFdPrestatDirName, sysCtx)
+	defer mod.Close(testCtx)
+
+	memorySize := mod.Memory().Size(testCtx)
+	validAddress := uint32(0) // Arbitrary valid address as arguments to fd_prestat_dir_name. We chose 0 here.
+	pathLen := uint32(len("/tmp"))
+
+	tests := []struct {
+		name          string
+		fd            uint32
+		path          uint32
+		pathLen       uint32
+		expectedErrno Errno
+	}{
+		{
+			name:          "out-of-memory path",
+			fd:            fd,
+			path:          memorySize,
+			pathLen:       pathLen,
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name:          "path exceeds the maximum valid address by 1",
+			fd:            fd,
+			path:          memorySize - pathLen + 1,
+			pathLen:       pathLen,
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name:          "pathLen exceeds the length of the dir name",
+			fd:            fd,
+			path:          validAddress,
+			pathLen:       pathLen + 1,
+			expectedErrno: ErrnoNametoolong,
+		},
+		{
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
+			path:          validAddress,
+			pathLen:       pathLen,
+			expectedErrno: ErrnoBadf,
+		},
+		{
+			name:          "non pre-opened file",
+			fd:            fileFD,
+			path:          validAddress,
+			pathLen:       pathLen,
+			expectedErrno: ErrnoBadf,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			errno := a.FdPrestatDirName(testCtx, mod, tc.fd, tc.path, tc.pathLen)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+		})
+	}
+}
+
+func TestSnapshotPreview1_FdPwrite(t *testing.T) {
+	fd := uint32(4)   // arbitrary fd after the pre-opened directory
+	iovs := uint32(1) // arbitrary offset
+	initialMemory := []byte{
+		'?',         // `iovs` is after this
+		18, 0, 0, 0, // = iovs[0].offset
+		4, 0, 0, 0, // = iovs[0].length
+		23, 0, 0, 0, // = iovs[1].offset
+		2, 0, 0, 0, // = iovs[1].length
+		'?',                // iovs[0].offset is after this
+		'w', 'a', 'z', 'e', // iovs[0].length bytes
+		'?',      // iovs[1].offset is after this
+		'r', 'o', // iovs[1].length bytes
+		'?',
+	}
+	iovsCount := uint32(2)       // The count of iovs
+	resultNwritten := uint32(26) // arbitrary offset
+	expectedMemory := append(
+		initialMemory,
+		6, 0, 0, 0, // sum(iovs[...].length) == length of "wazero"
+		'?',
+	)
+
+	// TestSnapshotPreview1_FdPwrite uses a matrix because the file has to be clean each time.
+	type fdPwriteFn func(ctx context.Context, m api.Module, fd, iovs, iovsCount uint32, offset uint64, resultNwritten uint32) Errno
+	tests := []struct {
+		name     string
+		fdPwrite func(*snapshotPreview1, api.Module, api.Function) fdPwriteFn
+	}{
+		{"snapshotPreview1.FdPwrite", func(a *snapshotPreview1, _ api.Module, _ api.Function) fdPwriteFn {
+			return a.FdPwrite
+		}},
+		{functionFdPwrite, func(_ *snapshotPreview1, mod api.Module, fn api.Function) fdPwriteFn {
+			return func(ctx context.Context, m api.Module, fd, iovs, iovsCount uint32, offset uint64, resultNwritten uint32) Errno {
+				results, err := fn.Call(testCtx, uint64(fd), uint64(iovs), uint64(iovsCount), offset, uint64(resultNwritten))
+				require.NoError(t, err)
+				return Errno(results[0])
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": ".."}, fd, "file")
+			a, mod, fn := instantiateModule(testCtx, t, functionFdPwrite, importFdPwrite, sysCtx)
+			defer mod.Close(testCtx)
+
+			maskMemory(t, testCtx, mod, len(expectedMemory))
+			require.True(t, mod.Memory().Write(testCtx, 0, initialMemory))
+
+			errno := tc.fdPwrite(a, mod, fn)(testCtx, mod, fd, iovs, iovsCount, 2, resultNwritten)
+			require.Zero(t, errno, ErrnoName(errno))
+
+			actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+			require.True(t, ok)
+			require.Equal(t, expectedMemory, actual)
+
+			// Ensure the contents were written at the offset
+			b, err := fs.ReadFile(wfs, "file")
+			require.NoError(t, err)
+			require.Equal(t, "..wazero", string(b))
+
+			// Ensure the offset of the file didn't change
+			_, fsc := sysFSCtx(testCtx, mod)
+			f, ok := fsc.OpenedFile(fd)
+			require.True(t, ok)
+			current, err := f.File.(io.Seeker).Seek(0, io.SeekCurrent)
+			require.NoError(t, err)
+			require.Zero(t, current)
+		})
+	}
+}
+
+func TestSnapshotPreview1_FdPwrite_Errors(t *testing.T) {
+	fileFD, readOnlyFD := uint32(3), uint32(4) // arbitrary fds after 0, 1, and 2, that are stdin/out/err
+	tmpDir := t.TempDir()
+	file, testFS := createWriteableFile(t, tmpDir, "test_path", []byte{})
+	readOnlyFile, readOnlyFS := createFile(t, "test_path", []byte{})
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		fileFD:     {Path: "test_path", FS: testFS, File: file},
+		readOnlyFD: {Path: "test_path", FS: readOnlyFS, File: readOnlyFile},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdPwrite, importFdPwrite, sysCtx)
+	defer mod.Close(testCtx)
+
+	tests := []struct {
+		name                                string
+		fd, iovs, iovsCount, resultNwritten uint32
+		fileOffset                          uint64
+		memory                              []byte
+		expectedErrno                       Errno
+	}{
+		{
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
+			expectedErrno: ErrnoBadf,
+		},
+		{
+			name:          "not io.WriterAt",
+			fd:            readOnlyFD,
+			expectedErrno: ErrnoSpipe,
+		},
+		{
+			name:          "offset past math.MaxInt64",
+			fd:            fileFD,
+			fileOffset:    math.MaxInt64 + 1,
+			expectedErrno: ErrnoInval,
+		},
+		{
+			name:          "out-of-memory reading iovs[0].offset",
+			fd:            fileFD,
+			iovs:          1,
+			iovsCount:     1,
+			memory:        []byte{'?'},
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name: "length to write exceeds memory by 1",
+			fd:   fileFD,
+			iovs: 1, iovsCount: 1,
+			memory: []byte{
+				'?',        // `iovs` is after this
+				9, 0, 0, 0, // = iovs[0].offset
+				0, 0, 0x1, 0, // = iovs[0].length on the second page
+				'?',
+			},
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name: "resultNwritten offset is outside memory",
+			fd:   fileFD,
+			iovs: 1, iovsCount: 1,
+			resultNwritten: 10, // 1 past memory
+			memory: []byte{
+				'?',        // `iovs` is after this
+				9, 0, 0, 0, // = iovs[0].offset
+				1, 0, 0, 0, // = iovs[0].length
+				'?',
+			},
+			expectedErrno: ErrnoFault,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			offset := uint32(wasm.MemoryPagesToBytesNum(testMemoryPageSize) - uint64(len(tc.memory)))
+
+			memoryWriteOK := mod.Memory().Write(testCtx, offset, tc.memory)
+			require.True(t, memoryWriteOK)
+
+			errno := a.FdPwrite(testCtx, mod, tc.fd, tc.iovs+offset, tc.iovsCount, tc.fileOffset, tc.resultNwritten+offset)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+		})
+	}
+}
+
+func TestSnapshotPreview1_FdRead(t *testing.T) {
+	fd := uint32(3)   // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	iovs := uint32(1) // arbitrary offset
+	initialMemory := []byte{
+		'?',         // `iovs` is after this
+		18, 0, 0, 0, // = iovs[0].offset
+		4, 0, 0, 0, // = iovs[0].length
+		23, 0, 0, 0, // = iovs[1].offset
+		2, 0, 0, 0, // = iovs[1].length
+		'?',
+	}
+	iovsCount := uint32(2)   // The count of iovs
+	resultSize := uint32(26) // arbitrary offset
+	expectedMemory := append(
+		initialMemory,
+		'w', 'a', 'z', 'e', // iovs[0].length bytes
+		'?',      // iovs[1].offset is after this
+		'r', 'o', // iovs[1].length bytes
+		'?',        // resultSize is after this
+		6, 0, 0, 0, // sum(iovs[...].length) == length of "wazero"
+		'?',
+	)
+
+	// TestSnapshotPreview1_FdRead uses a matrix because setting up test files is complicated and has to be clean each time.
+	type fdReadFn func(ctx context.Context, m api.Module, fd, iovs, iovsCount, resultSize uint32) Errno
+	tests := []struct {
+		name   string
+		fdRead func(*snapshotPreview1, api.Module, api.Function) fdReadFn
+	}{
+		{"snapshotPreview1.FdRead", func(a *snapshotPreview1, _ api.Module, _ api.Function) fdReadFn {
+			return a.FdRead
+		}},
+		{functionFdRead, func(_ *snapshotPreview1, mod api.Module, fn api.Function) fdReadFn {
+			return func(ctx context.Context, m api.Module, fd, iovs, iovsCount, resultSize uint32) Errno {
+				results, err := fn.Call(testCtx, uint64(fd), uint64(iovs), uint64(iovsCount), uint64(resultSize))
+				require.NoError(t, err)
+				return Errno(results[0])
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			// Create a fresh file to read the contents from
+			file, testFS := createFile(t, "test_path", []byte("wazero"))
+			sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+				fd: {Path: "test_path", FS: testFS, File: file},
+			})
+			require.NoError(t, err)
+
+			a, mod, fn := instantiateModule(testCtx, t, functionFdRead, importFdRead, sysCtx)
+			defer mod.Close(testCtx)
+
+			maskMemory(t, testCtx, mod, len(expectedMemory))
+
+			ok := mod.Memory().Write(testCtx, 0, initialMemory)
+			require.True(t, ok)
+
+			errno := tc.fdRead(a, mod, fn)(testCtx, mod, fd, iovs, iovsCount, resultSize)
+			require.Zero(t, errno, ErrnoName(errno))
+
+			actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+			require.True(t, ok)
+			require.Equal(t, expectedMemory, actual)
+		})
+	}
+}
+
+// TestSnapshotPreview1_FdRead_Nonblock ensures fdflagsNonblock returns wasi.ErrnoAgain instead of waiting for data.
+func TestSnapshotPreview1_FdRead_Nonblock(t *testing.T) {
+	fd := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	guest, host := net.Pipe()
+	defer host.Close()
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		fd: {Path: "pipe", File: &internalsys.ConnFile{Conn: guest}, Fdflags: fdflagsNonblock},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdRead, importFdRead, sysCtx)
+	defer mod.Close(testCtx)
+
+	iovs, resultSize := uint32(1), uint32(16) // arbitrary offsets
+	require.True(t, mod.Memory().Write(testCtx, iovs, []byte{
+		9, 0, 0, 0, // = iovs[0].offset
+		4, 0, 0, 0, // = iovs[0].length
+	}))
+
+	errno := a.FdRead(testCtx, mod, fd, iovs, 1, resultSize)
+	require.Equal(t, ErrnoAgain, errno, ErrnoName(errno))
+
+	// The deadline is cleared, so the connection can still be read when blocking.
+	errno = a.FdFdstatSetFlags(testCtx, mod, fd, 0)
+	require.Zero(t, errno, ErrnoName(errno))
+	go func() { _, _ = host.Write([]byte("wazero")) }()
+
+	errno = a.FdRead(testCtx, mod, fd, iovs, 1, resultSize)
+	require.Zero(t, errno, ErrnoName(errno))
+
+	actual, ok := mod.Memory().Read(testCtx, 9, 4)
+	require.True(t, ok)
+	require.Equal(t, []byte("waze"), actual)
+}
+
+func TestSnapshotPreview1_FdRead_Errors(t *testing.T) {
+	validFD := uint32(3)                                 // arbitrary valid fd after 0, 1, and 2, that are stdin/out/err
+	file, testFS := createFile(t, "test_path", []byte{}) // file with empty contents
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		validFD: {Path: "test_path", FS: testFS, File: file},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdRead, importFdRead, sysCtx)
+	defer mod.Close(testCtx)
+
+	tests := []struct {
+		name                            string
+		fd, iovs, iovsCount, resultSize uint32
+		memory                          []byte
+		expectedErrno                   Errno
+	}{
+		{
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
+			expectedErrno: ErrnoBadf,
+		},
+		{
+			name:          "out-of-memory reading iovs[0].offset",
+			fd:            validFD,
+			iovs:          1,
+			memory:        []byte{'?'},
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name: "out-of-memory reading iovs[0].length",
+			fd:   validFD,
+			iovs: 1, iovsCount: 1,
+			memory: []byte{
+				'?',        // `iovs` is after this
+				9, 0, 0, 0, // = iovs[0].offset
+			},
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name: "iovs[0].offset is outside memory",
+			fd:   validFD,
+			iovs: 1, iovsCount: 1,
+			memory: []byte{
+				'?',          // `iovs` is after this
+				0, 0, 0x1, 0, // = iovs[0].offset on the second page
+				1, 0, 0, 0, // = iovs[0].length
+			},
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name: "length to read exceeds memory by 1",
+			fd:   validFD,
+			iovs: 1, iovsCount: 1,
+			memory: []byte{
+				'?',        // `iovs` is after this
+				9, 0, 0, 0, // = iovs[0].offset
+				0, 0, 0x1, 0, // = iovs[0].length on the second page
+				'?',
+			},
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name: "resultSize offset is outside memory",
+			fd:   validFD,
+			iovs: 1, iovsCount: 1,
+			resultSize: 10, // 1 past memory
+			memory: []byte{
+				'?',        // `iovs` is after this
+				9, 0, 0, 0, // = iovs[0].offset
+				1, 0, 0, 0, // = iovs[0].length
+				'?',
+			},
+			expectedErrno: ErrnoFault,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			offset := uint32(wasm.MemoryPagesToBytesNum(testMemoryPageSize) - uint64(len(tc.memory)))
+
+			memoryWriteOK := mod.Memory().Write(testCtx, offset, tc.memory)
+			require.True(t, memoryWriteOK)
+
+			errno := a.FdRead(testCtx, mod, tc.fd, tc.iovs+offset, tc.iovsCount+offset, tc.resultSize+offset)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+		})
+	}
+}
+
+func TestSnapshotPreview1_FdReaddir(t *testing.T) {
+	fd := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	testFS := fstest.MapFS{
+		"a":     &fstest.MapFile{Data: []byte("a")},
+		"dir/b": &fstest.MapFile{Data: []byte("b")},
+	}
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{fd: {Path: "/", FS: testFS}})
+	require.NoError(t, err)
+
+	a, mod, fn := instantiateModule(testCtx, t, functionFdReaddir, importFdReaddir, sysCtx)
+	defer mod.Close(testCtx)
+
+	inodeA := make([]byte, 8)
+	binary.LittleEndian.PutUint64(inodeA, inode("a"))
+
+	buf, bufLen := uint32(1), uint32(direntLen+1) // arbitrary offset, and enough for the dirent of "a"
+	cookie := uint64(2)                           // after "." and ".."
+	resultBufused := buf + bufLen
+	expectedMemory := append(append(append([]byte{
+		'?',                    // buf is after this
+		3, 0, 0, 0, 0, 0, 0, 0, // d_next
+	}, inodeA...), // d_ino
+		1, 0, 0, 0, // d_namlen
+		filetypeRegularFile, // d_type
+		0, 0, 0,             // padding
+		'a', // name
+	), byte(bufLen), 0, 0, 0, // resultBufused
+		'?',
+	)
+
+	t.Run("snapshotPreview1.FdReaddir", func(t *testing.T) {
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+
+		errno := a.FdReaddir(testCtx, mod, fd, buf, bufLen, cookie, resultBufused)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+	})
+
+	t.Run(functionFdReaddir, func(t *testing.T) {
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+
+		results, err := fn.Call(testCtx, uint64(fd), uint64(buf), uint64(bufLen), cookie, uint64(resultBufused))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+	})
+}
+
+func TestSnapshotPreview1_FdReaddir_Cookie(t *testing.T) {
+	rootFD, dirFD := uint32(3), uint32(4) // arbitrary fds after 0, 1, and 2, that are stdin/out/err
+	testFS := fstest.MapFS{
+		"a":     &fstest.MapFile{Data: []byte("a")},
+		"dir/b": &fstest.MapFile{Data: []byte("b")},
+		"dir/c": &fstest.MapFile{Mode: fs.ModeDir},
+		"link":  &fstest.MapFile{Mode: fs.ModeSymlink},
+	}
+	dir, err := testFS.Open("dir")
+	require.NoError(t, err)
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		rootFD: {Path: "/", FS: testFS},
+		dirFD:  {Path: "dir", FS: testFS, File: dir},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdReaddir, importFdReaddir, sysCtx)
+	defer mod.Close(testCtx)
+
+	direntDot := appendDirent(nil, 1, inode("."), ".", filetypeDirectory)
+	direntDotDot := appendDirent(nil, 2, inode("."), "..", filetypeDirectory)
+	direntA := appendDirent(nil, 3, inode("a"), "a", filetypeRegularFile)
+	direntDir := appendDirent(nil, 4, inode("dir"), "dir", filetypeDirectory)
+	direntLink := appendDirent(nil, 5, inode("link"), "link", filetypeSymbolicLink)
+	direntDirDot := appendDirent(nil, 1, inode("dir"), ".", filetypeDirectory)
+	direntDirDotDot := appendDirent(nil, 2, inode("."), "..", filetypeDirectory)
+	direntB := appendDirent(nil, 3, inode("dir/b"), "b", filetypeRegularFile)
+	direntC := appendDirent(nil, 4, inode("dir/c"), "c", filetypeDirectory)
+	concat := func(dirents ...[]byte) (result []byte) {
+		for _, d := range dirents {
+			result = append(result, d...)
+		}
+		return
+	}
+
+	tests := []struct {
+		name            string
+		fd              uint32
+		bufLen          uint32
+		cookie          uint64
+		expectedDirents []byte
+	}{
+		{
+			name:            "all",
+			fd:              rootFD,
+			bufLen:          200,
+			expectedDirents: concat(direntDot, direntDotDot, direntA, direntDir, direntLink),
+		},
+		{
+			name:            "from cookie",
+			fd:              rootFD,
+			bufLen:          100,
+			cookie:          4,
+			expectedDirents: direntLink,
+		},
+		{
+			name:   "past the last entry",
+			fd:     rootFD,
+			bufLen: 100,
+			cookie: 5,
+		},
+		{
+			name:            "truncated",
+			fd:              rootFD,
+			bufLen:          uint32(len(direntDot) + direntLen),
+			expectedDirents: concat(direntDot, direntDotDot[:direntLen]),
+		},
+		{
+			name:            "opened directory",
+			fd:              dirFD,
+			bufLen:          200,
+			expectedDirents: concat(direntDirDot, direntDirDotDot, direntB, direntC),
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			buf, resultBufused := uint32(0), uint32(200) // arbitrary offsets that don't overlap
+			errno := a.FdReaddir(testCtx, mod, tc.fd, buf, tc.bufLen, tc.cookie, resultBufused)
+			require.Zero(t, errno, ErrnoName(errno))
+
+			bufused, ok := mod.Memory().ReadUint32Le(testCtx, resultBufused)
+			require.True(t, ok)
+			require.Equal(t, uint32(len(tc.expectedDirents)), bufused)
+
+			dirents, ok := mod.Memory().Read(testCtx, buf, bufused)
+			require.True(t, ok)
+			require.Equal(t, tc.expectedDirents, append([]byte{}, dirents...))
+		})
+	}
+}
+
+func TestSnapshotPreview1_FdReaddir_Cached(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"a": "a"}, 0, "")
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdReaddir, importFdReaddir, sysCtx)
+	defer mod.Close(testCtx)
+
+	readdirNames := func(cookie uint64) (names []string) {
+		buf, resultBufused := uint32(0), uint32(200) // arbitrary offsets that don't overlap
+		errno := a.FdReaddir(testCtx, mod, dirFD, buf, 150, cookie, resultBufused)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		bufused, ok := mod.Memory().ReadUint32Le(testCtx, resultBufused)
+		require.True(t, ok)
+		dirents, ok := mod.Memory().Read(testCtx, buf, bufused)
+		require.True(t, ok)
+		for len(dirents) > 0 {
+			namlen := binary.LittleEndian.Uint32(dirents[16:])
+			names = append(names, string(dirents[direntLen:direntLen+namlen]))
+			dirents = dirents[direntLen+namlen:]
+		}
+		return
+	}
+
+	require.Equal(t, []string{".", "..", "a"}, readdirNames(0))
+
+	// A file added after the directory was read isn't seen until reading restarts from the first entry.
+	f, err := wfs.OpenFile("b", os.O_RDWR|os.O_CREATE, 0o600)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	require.Equal(t, []string{"a"}, readdirNames(2))
+	require.Equal(t, []string{".", "..", "a", "b"}, readdirNames(0))
+}
+
+func TestSnapshotPreview1_FdReaddir_Errors(t *testing.T) {
+	dirFD, fileFD := uint32(3), uint32(4) // arbitrary fds after 0, 1, and 2, that are stdin/out/err
+	file, testFS := createFile(t, "test_path", []byte("wazero"))
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		dirFD:  {Path: "/", FS: testFS},
+		fileFD: {Path: "test_path", FS: testFS, File: file},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdReaddir, importFdReaddir, sysCtx)
+	defer mod.Close(testCtx)
+
+	memorySize := mod.Memory().Size(testCtx)
+
+	tests := []struct {
+		name                     string
+		fd, buf, bufLen, bufused uint32
+		expectedErrno            Errno
+	}{
+		{
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
+			bufLen:        100,
+			expectedErrno: ErrnoBadf,
+		},
+		{
+			name:          "not a directory",
+			fd:            fileFD,
+			bufLen:        100,
+			expectedErrno: ErrnoNotdir,
+		},
+		{
+			name:          "buf exceeds the maximum valid address by 1",
+			fd:            dirFD,
+			buf:           memorySize - 100 + 1,
+			bufLen:        100,
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name:          "out-of-memory resultBufused",
+			fd:            dirFD,
+			bufLen:        100,
+			bufused:       memorySize,
+			expectedErrno: ErrnoFault,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			errno := a.FdReaddir(testCtx, mod, tc.fd, tc.buf, tc.bufLen, 0, tc.bufused)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+		})
+	}
+}
+
+// TestSnapshotPreview1_FdRenumber only tests it is stubbed for GrainLang per #271
+func TestSnapshotPreview1_FdRenumber(t *testing.T) {
+	a, mod, fn := instantiateModule(testCtx, t, functionFdRenumber, importFdRenumber, nil)
+	defer mod.Close(testCtx)
+
+	t.Run("snapshotPreview1.FdRenumber", func(t *testing.T) {
+		errno := a.FdRenumber(testCtx, mod, 0, 0)
+		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	})
+
+	t.Run(functionFdRenumber, func(t *testing.T) {
+		results, err := fn.Call(testCtx, 0, 0)
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	})
+}
+
+func TestSnapshotPreview1_FdSeek(t *testing.T) {
+	fd := uint32(3)                                              // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	resultNewoffset := uint32(1)                                 // arbitrary offset in `ctx.Memory` for the new offset value
+	file, testFS := createFile(t, "test_path", []byte("wazero")) // arbitrary non-empty contents
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		fd: {Path: "test_path", FS: testFS, File: file},
+	})
+	require.NoError(t, err)
+
+	fsCtx := sysCtx.FS()
+
+	a, mod, fn := instantiateModule(testCtx, t, functionFdSeek, importFdSeek, sysCtx)
+	defer mod.Close(testCtx)
+
+	// TestSnapshotPreview1_FdSeek uses a matrix because setting up test files is complicated and has to be clean each time.
+	type fdSeekFn func(ctx context.Context, m api.Module, fd uint32, offset uint64, whence, resultNewOffset uint32) Errno
+	seekFns := []struct {
+		name   string
+		fdSeek func() fdSeekFn
+	}{
+		{"snapshotPreview1.FdSeek", func() fdSeekFn {
+			return a.FdSeek
+		}},
+		{functionFdSeek, func() fdSeekFn {
+			return func(ctx context.Context, m api.Module, fd uint32, offset uint64, whence, resultNewoffset uint32) Errno {
+				results, err := fn.Call(ctx, uint64(fd), offset, uint64(whence), uint64(resultNewoffset))
+				require.NoError(t, err)
+				return Errno(results[0])
+			}
+		}},
+	}
+
+	tests := []struct {
+		name           string
+		offset         int64
+		whence         int
+		expectedOffset int64
+		expectedMemory []byte
+	}{
+		{
+			name:           "SeekStart",
+			offset:         4, // arbitrary offset
+			whence:         io.SeekStart,
+			expectedOffset: 4, // = offset
+			expectedMemory: []byte{
+				'?',                    // resultNewoffset is after this
+				4, 0, 0, 0, 0, 0, 0, 0, // = expectedOffset
+				'?',
+			},
+		},
+		{
+			name:           "SeekCurrent",
+			offset:         1, // arbitrary offset
+			whence:         io.SeekCurrent,
+			expectedOffset: 2, // = 1 (the initial offset of the test file) + 1 (offset)
+			expectedMemory: []byte{
+				'?',                    // resultNewoffset is after this
+				2, 0, 0, 0, 0, 0, 0, 0, // = expectedOffset
+				'?',
+			},
+		},
+		{
+			name:           "SeekEnd",
+			offset:         -1, // arbitrary offset, note that offset can be negative
+			whence:         io.SeekEnd,
+			expectedOffset: 5, // = 6 (the size of the test file with content "wazero") + -1 (offset)
+			expectedMemory: []byte{
+				'?',                    // resultNewoffset is after this
+				5, 0, 0, 0, 0, 0, 0, 0, // = expectedOffset
+				'?',
+			},
+		},
+	}
+
+	for _, seekFn := range seekFns {
+		sf := seekFn
+		t.Run(sf.name, func(t *testing.T) {
+			for _, tt := range tests {
+				tc := tt
+				t.Run(tc.name, func(t *testing.T) {
+					maskMemory(t, testCtx, mod, len(tc.expectedMemory))
+
+					// Since we initialized this file, we know it is a seeker (because it is a MapFile)
+					f, ok := fsCtx.OpenedFile(fd)
+					require.True(t, ok)
+					seeker := f.File.(io.Seeker)
+
+					// set the initial offset of the file to 1
+					offset, err := seeker.Seek(1, io.SeekStart)
+					require.NoError(t, err)
+					require.Equal(t, int64(1), offset)
+
+					errno := sf.fdSeek()(testCtx, mod, fd, uint64(tc.offset), uint32(tc.whence), resultNewoffset)
+					require.Zero(t, errno, ErrnoName(errno))
+
+					actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(tc.expectedMemory)))
+					require.True(t, ok)
+					require.Equal(t, tc.expectedMemory, actual)
+
+					offset, err = seeker.Seek(0, io.SeekCurrent)
+					require.NoError(t, err)
+					require.Equal(t, tc.expectedOffset, offset) // test that the offset of file is actually updated.
+				})
+			}
+		})
+	}
+}
+
+func TestSnapshotPreview1_FdSeek_Errors(t *testing.T) {
+	validFD := uint32(3)                                         // arbitrary valid fd after 0, 1, and 2, that are stdin/out/err
+	file, testFS := createFile(t, "test_path", []byte("wazero")) // arbitrary valid file with non-empty contents
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		validFD: {Path: "test_path", FS: testFS, File: file},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdSeek, importFdSeek, sysCtx)
+	defer mod.Close(testCtx)
+
+	memorySize := mod.Memory().Size(testCtx)
+
+	tests := []struct {
+		name                    string
+		fd                      uint32
+		offset                  uint64
+		whence, resultNewoffset uint32
+		expectedErrno           Errno
+	}{
+		{
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
+			expectedErrno: ErrnoBadf,
+		},
+		{
+			name:          "invalid whence",
+			fd:            validFD,
+			whence:        3, // invalid whence, the largest whence io.SeekEnd(2) + 1
+			expectedErrno: ErrnoInval,
+		},
+		{
+			name:            "out-of-memory writing resultNewoffset",
+			fd:              validFD,
+			resultNewoffset: memorySize,
+			expectedErrno:   ErrnoFault,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			errno := a.FdSeek(testCtx, mod, tc.fd, tc.offset, tc.whence, tc.resultNewoffset)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+		})
+	}
+
+}
+
+func TestSnapshotPreview1_FdSync(t *testing.T) {
+	fileFD, memFD := uint32(3), uint32(4) // arbitrary fds after 0, 1, and 2, that are stdin/out/err
+	file, testFS := createWriteableFile(t, t.TempDir(), "test_path", []byte("wazero"))
+	memFS := internalsys.NewMemFS()
+	memFile, err := memFS.OpenFile("test_path", os.O_RDWR|os.O_CREATE, 0o600)
+	require.NoError(t, err)
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		fileFD: {Path: "test_path", FS: testFS, File: file},
+		memFD:  {Path: "test_path", FS: memFS, File: memFile},
+	})
+	require.NoError(t, err)
+
+	a, mod, fn := instantiateModule(testCtx, t, functionFdSync, importFdSync, sysCtx)
+	defer mod.Close(testCtx)
+
+	t.Run("snapshotPreview1.FdSync", func(t *testing.T) {
+		errno := a.FdSync(testCtx, mod, fileFD)
+		require.Zero(t, errno, ErrnoName(errno))
+	})
+
+	t.Run(functionFdSync, func(t *testing.T) {
+		results, err := fn.Call(testCtx, uint64(fileFD))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+	})
+
+	t.Run("nothing to sync", func(t *testing.T) {
+		errno := a.FdSync(testCtx, mod, memFD)
+		require.Zero(t, errno, ErrnoName(errno))
+	})
+
+	t.Run("invalid fd", func(t *testing.T) {
+		errno := a.FdSync(testCtx, mod, 42) // arbitrary invalid fd
+		require.Equal(t, ErrnoBadf, errno, ErrnoName(errno))
+	})
+}
+
+func TestSnapshotPreview1_FdTell(t *testing.T) {
+	fd := uint32(4) // arbitrary fd after the pre-opened directory
+	_, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, fd, "file")
+
+	a, mod, fn := instantiateModule(testCtx, t, functionFdTell, importFdTell, sysCtx)
+	defer mod.Close(testCtx)
+
+	// set the initial offset of the file to 3
+	_, fsc := sysFSCtx(testCtx, mod)
+	f, ok := fsc.OpenedFile(fd)
+	require.True(t, ok)
+	_, err := f.File.(io.Seeker).Seek(3, io.SeekStart)
+	require.NoError(t, err)
+
+	resultOffset := uint32(1) // arbitrary offset
+	expectedMemory := []byte{
+		'?',                    // resultOffset is after this
+		3, 0, 0, 0, 0, 0, 0, 0, // = the current offset
+		'?',
+	}
+
+	t.Run("snapshotPreview1.FdTell", func(t *testing.T) {
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+
+		errno := a.FdTell(testCtx, mod, fd, resultOffset)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+	})
+
+	t.Run(functionFdTell, func(t *testing.T) {
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+
+		results, err := fn.Call(testCtx, uint64(fd), uint64(resultOffset))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+	})
+
+	t.Run("invalid fd", func(t *testing.T) {
+		errno := a.FdTell(testCtx, mod, 42, resultOffset) // arbitrary invalid fd
+		require.Equal(t, ErrnoBadf, errno, ErrnoName(errno))
+	})
+
+	t.Run("out-of-memory resultOffset", func(t *testing.T) {
+		errno := a.FdTell(testCtx, mod, fd, mod.Memory().Size(testCtx))
+		require.Equal(t, ErrnoFault, errno, ErrnoName(errno))
+	})
+}
+
+func TestSnapshotPreview1_FdWrite(t *testing.T) {
+	fd := uint32(3)   // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	iovs := uint32(1) // arbitrary offset
+	initialMemory := []byte{
+		'?',         // `iovs` is after this
+		18, 0, 0, 0, // = iovs[0].offset
+		4, 0, 0, 0, // = iovs[0].length
+		23, 0, 0, 0, // = iovs[1].offset
+		2, 0, 0, 0, // = iovs[1].length
+		'?',                // iovs[0].offset is after this
+		'w', 'a', 'z', 'e', // iovs[0].length bytes
+		'?',      // iovs[1].offset is after this
+		'r', 'o', // iovs[1].length bytes
+		'?',
+	}
+	iovsCount := uint32(2)   // The count of iovs
+	resultSize := uint32(26) // arbitrary offset
+	expectedMemory := append(
+		initialMemory,
+		6, 0, 0, 0, // sum(iovs[...].length) == length of "wazero"
+		'?',
+	)
+
+	// TestSnapshotPreview1_FdWrite uses a matrix because setting up test files is complicated and has to be clean each time.
+	type fdWriteFn func(ctx context.Context, m api.Module, fd, iovs, iovsCount, resultSize uint32) Errno
+	tests := []struct {
+		name    string
+		fdWrite func(*snapshotPreview1, api.Module, api.Function) fdWriteFn
+	}{
+		{"snapshotPreview1.FdWrite", func(a *snapshotPreview1, _ api.Module, _ api.Function) fdWriteFn {
+			return a.FdWrite
+		}},
+		{functionFdWrite, func(_ *snapshotPreview1, mod api.Module, fn api.Function) fdWriteFn {
+			return func(ctx context.Context, m api.Module, fd, iovs, iovsCount, resultSize uint32) Errno {
+				results, err := fn.Call(ctx, uint64(fd), uint64(iovs), uint64(iovsCount), uint64(resultSize))
+				require.NoError(t, err)
+				return Errno(results[0])
+			}
+		}},
+	}
+
+	tmpDir := t.TempDir() // open before loop to ensure no locking problems.
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			// Create a fresh file to write the contents to
+			pathName := "test_path"
+			file, testFS := createWriteableFile(t, tmpDir, pathName, []byte{})
+			sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+				fd: {Path: pathName, FS: testFS, File: file},
+			})
+			require.NoError(t, err)
+
+			a, mod, fn := instantiateModule(testCtx, t, functionFdWrite, importFdWrite, sysCtx)
+			defer mod.Close(testCtx)
+
+			maskMemory(t, testCtx, mod, len(expectedMemory))
+			ok := mod.Memory().Write(testCtx, 0, initialMemory)
+			require.True(t, ok)
+
+			errno := tc.fdWrite(a, mod, fn)(testCtx, mod, fd, iovs, iovsCount, resultSize)
+			require.Zero(t, errno, ErrnoName(errno))
+
+			actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+			require.True(t, ok)
+			require.Equal(t, expectedMemory, actual)
+
+			// Since we initialized this file, we know we can read it by path
+			buf, err := os.ReadFile(path.Join(tmpDir, pathName))
+			require.NoError(t, err)
+
+			require.Equal(t, []byte("wazero"), buf) // verify the file was actually written
+		})
+	}
+}
+
+// TestSnapshotPreview1_FdWrite_Append ensures fdflagsAppend writes at the end of the file, regardless of the offset.
+func TestSnapshotPreview1_FdWrite_Append(t *testing.T) {
+	fd := uint32(4) // arbitrary fd after the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, fd, "file")
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdWrite, importFdWrite, sysCtx)
+	defer mod.Close(testCtx)
+
+	errno := a.FdFdstatSetFlags(testCtx, mod, fd, fdflagsAppend)
+	require.Zero(t, errno, ErrnoName(errno))
+
+	iovs, resultSize := uint32(1), uint32(16) // arbitrary offsets
+	require.True(t, mod.Memory().Write(testCtx, iovs, []byte{
+		9, 0, 0, 0, // = iovs[0].offset
+		2, 0, 0, 0, // = iovs[0].length
+		'!', '!', // iovs[0].length bytes
+	}))
+
+	errno = a.FdWrite(testCtx, mod, fd, iovs, 1, resultSize)
+	require.Zero(t, errno, ErrnoName(errno))
+
+	b, err := fs.ReadFile(wfs, "file")
+	require.NoError(t, err)
+	require.Equal(t, "wazero!!", string(b))
+}
+
+func TestSnapshotPreview1_FdWrite_Errors(t *testing.T) {
+	validFD := uint32(3) // arbitrary valid fd after 0, 1, and 2, that are stdin/out/err
+
+	tmpDir := t.TempDir() // open before loop to ensure no locking problems.
+	pathName := "test_path"
+	file, testFS := createWriteableFile(t, tmpDir, pathName, []byte{})
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		validFD: {Path: pathName, FS: testFS, File: file},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionFdWrite, importFdWrite, sysCtx)
+	defer mod.Close(testCtx)
+
+	// Setup valid test memory
+	iovs, iovsCount := uint32(0), uint32(1)
+	memory := []byte{
+		8, 0, 0, 0, // = iovs[0].offset (where the data "hi" begins)
+		2, 0, 0, 0, // = iovs[0].length (how many bytes are in "hi")
+		'h', 'i', // iovs[0].length bytes
+	}
+
+	tests := []struct {
+		name           string
+		fd, resultSize uint32
+		memory         []byte
+		expectedErrno  Errno
+	}{
+		{
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
+			expectedErrno: ErrnoBadf,
+		},
+		{
+			name:          "out-of-memory reading iovs[0].offset",
+			fd:            validFD,
+			memory:        []byte{},
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name:          "out-of-memory reading iovs[0].length",
+			fd:            validFD,
+			memory:        memory[0:4], // iovs[0].offset was 4 bytes and iovs[0].length next, but not enough mod.Memory()!
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name:          "iovs[0].offset is outside memory",
+			fd:            validFD,
+			memory:        memory[0:8], // iovs[0].offset (where to read "hi") is outside memory.
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name:          "length to read exceeds memory by 1",
+			fd:            validFD,
+			memory:        memory[0:9], // iovs[0].offset (where to read "hi") is in memory, but truncated.
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name:          "resultSize offset is outside memory",
+			fd:            validFD,
+			memory:        memory,
+			resultSize:    uint32(len(memory)), // read was ok, but there wasn't enough memory to write the result.
+			expectedErrno: ErrnoFault,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			mod.Memory().(*wasm.MemoryInstance).Buffer = tc.memory
+
+			errno := a.FdWrite(testCtx, mod, tc.fd, iovs, iovsCount, tc.resultSize)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+		})
+	}
+}
+
+func TestSnapshotPreview1_PathCreateDirectory(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, nil, 0, "")
+
+	a, mod, fn := instantiateModule(testCtx, t, functionPathCreateDirectory, importPathCreateDirectory, sysCtx)
+	defer mod.Close(testCtx)
+
+	path, pathLen := writePath(t, mod, 0, "dir")
+
+	t.Run("snapshotPreview1.PathCreateDirectory", func(t *testing.T) {
+		errno := a.PathCreateDirectory(testCtx, mod, dirFD, path, pathLen)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		st, err := fs.Stat(wfs, "dir")
+		require.NoError(t, err)
+		require.True(t, st.IsDir())
+	})
+
+	t.Run(functionPathCreateDirectory, func(t *testing.T) {
+		results, err := fn.Call(testCtx, uint64(dirFD), uint64(path), uint64(pathLen))
+		require.NoError(t, err)
+		errno := Errno(results[0])                            // results[0] is the errno
+		require.Equal(t, ErrnoExist, errno, ErrnoName(errno)) // created by the prior test
+	})
+}
+
+func TestSnapshotPreview1_PathCreateDirectory_Errors(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+
+	tests := []struct {
+		name          string
+		readOnly      bool
+		fd            uint32
+		pathName      string
+		pathLen       uint32
+		expectedErrno Errno
+	}{
+		{name: "invalid fd", fd: 42, pathName: "dir", expectedErrno: ErrnoBadf},
+		{name: "out-of-memory reading path", fd: dirFD, pathName: "dir", pathLen: 65537, expectedErrno: ErrnoFault},
+		{name: "escapes the directory", fd: dirFD, pathName: "../dir", expectedErrno: ErrnoNotcapable},
+		{name: "read-only file system", readOnly: true, fd: dirFD, pathName: "dir", expectedErrno: ErrnoRofs},
+		{name: "parent doesn't exist", fd: dirFD, pathName: "missing/dir", expectedErrno: ErrnoNoent},
+		{name: "parent isn't a directory", fd: dirFD, pathName: "file/dir", expectedErrno: ErrnoNotdir},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			// Each module closes its SysContext, so create one per test.
+			_, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, 0, "")
+			if tc.readOnly {
+				var err error
+				sysCtx, err = newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{dirFD: {Path: ".", FS: fstest.MapFS{}}})
+				require.NoError(t, err)
+			}
+
+			a, mod, _ := instantiateModule(testCtx, t, functionPathCreateDirectory, importPathCreateDirectory, sysCtx)
+			defer mod.Close(testCtx)
+
+			path, pathLen := writePath(t, mod, 0, tc.pathName)
+			if tc.pathLen != 0 {
+				pathLen = tc.pathLen
+			}
+			errno := a.PathCreateDirectory(testCtx, mod, tc.fd, path, pathLen)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+		})
+	}
+}
+
+func TestSnapshotPreview1_PathCreateDirectory_DirFS(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+	tmpDir := t.TempDir()
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		dirFD: {Path: "/", FS: internalsys.NewDirFS(tmpDir)},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionPathCreateDirectory, importPathCreateDirectory, sysCtx)
+	defer mod.Close(testCtx)
+
+	path, pathLen := writePath(t, mod, 0, "dir")
+	errno := a.PathCreateDirectory(testCtx, mod, dirFD, path, pathLen)
+	require.Zero(t, errno, ErrnoName(errno))
+
+	st, err := os.Stat(filepath.Join(tmpDir, "dir"))
+	require.NoError(t, err)
+	require.True(t, st.IsDir())
+}
+
+func TestSnapshotPreview1_PathFilestatGet(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero", "dir/file": "wa"}, 0, "")
+	require.NoError(t, wfs.Symlink("file", "link"))
+
+	a, mod, fn := instantiateModule(testCtx, t, functionPathFilestatGet, importPathFilestatGet, sysCtx)
+	defer mod.Close(testCtx)
+
+	t.Run("snapshotPreview1.PathFilestatGet", func(t *testing.T) {
+		pathPtr, pathLen := writePath(t, mod, 0, "file")
+		resultBuf := pathLen
+
+		errno := a.PathFilestatGet(testCtx, mod, dirFD, 0, pathPtr, pathLen, resultBuf)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		requireFilestat(t, mod, resultBuf, inode("file"), filetypeRegularFile, 6)
+	})
+
+	t.Run(functionPathFilestatGet, func(t *testing.T) {
+		pathPtr, pathLen := writePath(t, mod, 0, "dir/file")
+		resultBuf := pathLen
+
+		results, err := fn.Call(testCtx, uint64(dirFD), 0, uint64(pathPtr), uint64(pathLen), uint64(resultBuf))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+
+		requireFilestat(t, mod, resultBuf, inode("dir/file"), filetypeRegularFile, 2)
+	})
+
+	t.Run("directory", func(t *testing.T) {
+		pathPtr, pathLen := writePath(t, mod, 0, "dir")
+		resultBuf := pathLen
+
+		errno := a.PathFilestatGet(testCtx, mod, dirFD, 0, pathPtr, pathLen, resultBuf)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		st, err := fs.Stat(wfs, "dir")
+		require.NoError(t, err)
+		requireFilestat(t, mod, resultBuf, inode("dir"), filetypeDirectory, uint64(st.Size()))
+	})
+
+	t.Run("symlink not followed", func(t *testing.T) {
+		pathPtr, pathLen := writePath(t, mod, 0, "link")
+		resultBuf := pathLen
+
+		errno := a.PathFilestatGet(testCtx, mod, dirFD, 0, pathPtr, pathLen, resultBuf)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		st, err := wfs.Lstat("link")
+		require.NoError(t, err)
+		requireFilestat(t, mod, resultBuf, inode("link"), filetypeSymbolicLink, uint64(st.Size()))
+	})
+
+	t.Run("symlink followed", func(t *testing.T) {
+		pathPtr, pathLen := writePath(t, mod, 0, "link")
+		resultBuf := pathLen
+
+		errno := a.PathFilestatGet(testCtx, mod, dirFD, lookupflagsSymlinkFollow, pathPtr, pathLen, resultBuf)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		requireFilestat(t, mod, resultBuf, inode("link"), filetypeRegularFile, 6)
+	})
+}
+
+func TestSnapshotPreview1_PathFilestatGet_Dev(t *testing.T) {
+	fs1 := fstest.MapFS{"file": &fstest.MapFile{Data: []byte("wazero")}}
+	fs2 := fstest.MapFS{"file": &fstest.MapFile{Data: []byte("wazero")}}
+	hostDir := t.TempDir()
+	require.NoError(t, os.WriteFile(path.Join(hostDir, "file"), []byte("wazero"), 0o600))
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		3: {Path: "/", FS: fs1},
+		4: {Path: "/tmp", FS: fs2},
+		5: {Path: "/host", FS: internalsys.NewDirFS(hostDir)},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionPathFilestatGet, importPathFilestatGet, sysCtx)
+	defer mod.Close(testCtx)
+
+	devIno := func(fd uint32) (uint64, uint64) {
+		pathPtr, pathLen := writePath(t, mod, 0, "file")
+		resultBuf := pathLen
+		errno := a.PathFilestatGet(testCtx, mod, fd, 0, pathPtr, pathLen, resultBuf)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		filestat, ok := mod.Memory().Read(testCtx, resultBuf, filestatLen)
+		require.True(t, ok)
+		return binary.LittleEndian.Uint64(filestat), binary.LittleEndian.Uint64(filestat[8:])
+	}
+
+	// The same path in different mounts is a different file.
+	dev1, ino1 := devIno(3)
+	dev2, ino2 := devIno(4)
+	require.Equal(t, ino1, ino2)
+	require.NotEqual(t, dev1, dev2)
+
+	// The host serial number is used when available.
+	st, err := os.Stat(path.Join(hostDir, "file"))
+	require.NoError(t, err)
+	if _, ino, _, ok := hostStat(st); ok {
+		_, hostIno := devIno(5)
+		require.Equal(t, ino, hostIno)
+	}
+}
+
+func TestSnapshotPreview1_PathFilestatGet_Errors(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+	_, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, 0, "")
+
+	a, mod, _ := instantiateModule(testCtx, t, functionPathFilestatGet, importPathFilestatGet, sysCtx)
+	defer mod.Close(testCtx)
+
+	memorySize := mod.Memory().Size(testCtx)
+
+	tests := []struct {
+		name               string
+		fd                 uint32
+		pathName           string
+		pathPtr, resultBuf uint32
+		expectedErrno      Errno
+	}{
+		{
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
+			pathName:      "file",
+			expectedErrno: ErrnoBadf,
+		},
+		{
+			name:          "out-of-memory path",
+			fd:            dirFD,
+			pathName:      "file",
+			pathPtr:       memorySize,
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name:          "out-of-memory resultBuf",
+			fd:            dirFD,
+			pathName:      "file",
+			resultBuf:     memorySize,
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name:          "path escapes the directory",
+			fd:            dirFD,
+			pathName:      "../file",
+			expectedErrno: ErrnoNotcapable,
+		},
+		{
+			name:          "path doesn't exist",
+			fd:            dirFD,
+			pathName:      "missing",
+			expectedErrno: ErrnoNoent,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			pathPtr, pathLen := writePath(t, mod, 0, tc.pathName)
+			if tc.pathPtr != 0 {
+				pathPtr = tc.pathPtr
+			}
+			resultBuf := pathLen
+			if tc.resultBuf != 0 {
+				resultBuf = tc.resultBuf
+			}
+
+			errno := a.PathFilestatGet(testCtx, mod, tc.fd, 0, pathPtr, pathLen, resultBuf)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+		})
+	}
+}
+
+func TestSnapshotPreview1_PathFilestatSetTimes(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, 0, "")
+
+	a, mod, fn := instantiateModule(testCtx, t, functionPathFilestatSetTimes, importPathFilestatSetTimes, sysCtx)
+	defer mod.Close(testCtx)
+
+	path, pathLen := writePath(t, mod, 0, "file")
+	mtim := uint64(1234567890)
+
+	t.Run("snapshotPreview1.PathFilestatSetTimes", func(t *testing.T) {
+		errno := a.PathFilestatSetTimes(testCtx, mod, dirFD, 0, path, pathLen, 0, mtim, fstflagsMtim)
+		require.Zero(t, errno, ErrnoName(errno))
+		requireModTime(t, wfs, "file", mtim)
+	})
+
+	t.Run(functionPathFilestatSetTimes, func(t *testing.T) {
+		results, err := fn.Call(testCtx, uint64(dirFD), 0, uint64(path), uint64(pathLen), 0, 0, fstflagsMtimNow)
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+		requireModTime(t, wfs, "file", epochNanos)
+	})
+
+	t.Run("no such file exists", func(t *testing.T) {
+		errno := a.PathFilestatSetTimes(testCtx, mod, dirFD, 0, path, pathLen-1, 0, mtim, fstflagsMtim)
+		require.Equal(t, ErrnoNoent, errno, ErrnoName(errno))
+	})
+}
+
+func TestSnapshotPreview1_PathLink(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, 0, "")
+
+	a, mod, fn := instantiateModule(testCtx, t, functionPathLink, importPathLink, sysCtx)
+	defer mod.Close(testCtx)
+
+	oldPath, oldPathLen := writePath(t, mod, 0, "file")
+	newPath, newPathLen := writePath(t, mod, 16, "link")
+	otherPath, otherPathLen := writePath(t, mod, 32, "other")
+
+	t.Run("snapshotPreview1.PathLink", func(t *testing.T) {
+		errno := a.PathLink(testCtx, mod, dirFD, 0, oldPath, oldPathLen, dirFD, newPath, newPathLen)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		b, err := fs.ReadFile(wfs, "link")
+		require.NoError(t, err)
+		require.Equal(t, "wazero", string(b))
+	})
+
+	t.Run(functionPathLink, func(t *testing.T) {
+		results, err := fn.Call(testCtx, uint64(dirFD), 0, uint64(oldPath), uint64(oldPathLen), uint64(dirFD), uint64(otherPath), uint64(otherPathLen))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+
+		_, err = fs.Stat(wfs, "other")
+		require.NoError(t, err)
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		errno := a.PathLink(testCtx, mod, dirFD, 0, oldPath, oldPathLen, dirFD, newPath, newPathLen)
+		require.Equal(t, ErrnoExist, errno, ErrnoName(errno))
+	})
+}
+
+func TestSnapshotPreview1_PathOpen(t *testing.T) {
+	type pathOpenArgs struct {
+		fd                 uint32
+		dirflags           uint32
+		pathPtr            uint32
+		pathLen            uint32
+		oflags             uint32
+		fsRightsBase       uint64
+		fsRightsInheriting uint64
+		fdflags            uint32
+		resultOpenedFd     uint32
+	}
+
+	setup := func(workdirFD uint32, pathName string) (*snapshotPreview1, api.Module, api.Function, pathOpenArgs, []byte, uint32) {
+		// Setup the initial memory to include the path name starting at an offset.
+		initialMemory := append([]byte{'?'}, pathName...)
+
+		expectedFD := workdirFD + 1
+		expectedMemory := append(
+			initialMemory,
+			'?', // `resultOpenedFd` is after this
+			byte(expectedFD), 0, 0, 0,
+			'?',
+		)
+
+		args := pathOpenArgs{
+			fd:                 workdirFD,
+			dirflags:           0,
+			pathPtr:            1,
+			pathLen:            uint32(len(pathName)),
+			oflags:             0,
+			fsRightsBase:       rightFdRead,
+			fsRightsInheriting: rightFdRead | rightFdSeek,
+			fdflags:            0,
+			resultOpenedFd:     uint32(len(initialMemory) + 1),
+		}
+
+		testFS := fstest.MapFS{pathName: &fstest.MapFile{Mode: os.ModeDir}}
+		sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+			workdirFD: {Path: ".", FS: testFS},
+		})
+		require.NoError(t, err)
+		a, mod, fn := instantiateModule(testCtx, t, functionPathOpen, importPathOpen, sysCtx)
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+		ok := mod.Memory().Write(testCtx, 0, initialMemory)
+		require.True(t, ok)
+		return a, mod, fn, args, expectedMemory, expectedFD
+	}
+
+	verify := func(ctx context.Context, errno Errno, mod api.Module, pathName string, expectedMemory []byte, expectedFD uint32) {
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+
+		// verify the file was actually opened
+		_, fsc := sysFSCtx(ctx, mod)
+		f, ok := fsc.OpenedFile(expectedFD)
+		require.True(t, ok)
+		require.Equal(t, pathName, f.Path)
+		require.Equal(t, &internalsys.Rights{Base: rightFdRead, Inheriting: rightFdRead | rightFdSeek}, f.Rights)
+	}
+
+	t.Run("snapshotPreview1.PathOpen", func(t *testing.T) {
+		workdirFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+		pathName := "wazero"
+
+		a, mod, _, args, expectedMemory, expectedFD := setup(workdirFD, pathName)
+		errno := a.PathOpen(testCtx, mod, args.fd, args.dirflags, args.pathPtr, args.pathLen, args.oflags,
+			args.fsRightsBase, args.fsRightsInheriting, args.fdflags, args.resultOpenedFd)
+		verify(testCtx, errno, mod, pathName, expectedMemory, expectedFD)
+	})
+
+	t.Run(functionPathOpen, func(t *testing.T) {
+		workdirFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+		pathName := "wazero"
+
+		_, mod, fn, args, expectedMemory, expectedFD := setup(workdirFD, pathName)
+		results, err := fn.Call(testCtx, uint64(args.fd), uint64(args.dirflags), uint64(args.pathPtr), uint64(args.pathLen),
+			uint64(args.oflags), args.fsRightsBase, args.fsRightsInheriting, uint64(args.fdflags), uint64(args.resultOpenedFd))
+		require.NoError(t, err)
+		errno := Errno(results[0])
+		verify(testCtx, errno, mod, pathName, expectedMemory, expectedFD)
+	})
+
+	t.Run("snapshotPreview1.PathOpen.WithFS", func(t *testing.T) {
+		workdirFD := uint32(100) // dummy fd as it is not used
+		pathName := "wazero"
+
+		// The filesystem initialized in setup() is not used as it will be overridden.
+		a, mod, _, args, expectedMemory, _ := setup(workdirFD, pathName)
+
+		// Override fs.FS through context
+		workdirFD = uint32(4) // 3 is '/' and 4 is '.'
+		expectedFD := workdirFD + 1
+		expectedMemory[8] = byte(expectedFD) // replace expected memory with expected fd
+		testFS := fstest.MapFS{pathName: &fstest.MapFile{Mode: os.ModeDir}}
+		ctx, closer, err := experimental.WithFS(testCtx, testFS)
+		require.NoError(t, err)
+		defer closer.Close(ctx)
+
+		errno := a.PathOpen(ctx, mod, workdirFD, args.dirflags, args.pathPtr, args.pathLen, args.oflags,
+			args.fsRightsBase, args.fsRightsInheriting, args.fdflags, args.resultOpenedFd)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		verify(ctx, errno, mod, pathName, expectedMemory, expectedFD)
+	})
+
+	// wasi-libc requests all rights by default, so a directory must open with them, even in a read-only file system.
+	t.Run("directory with all rights", func(t *testing.T) {
+		workdirFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+		pathName := "wazero"
+
+		a, mod, _, args, _, expectedFD := setup(workdirFD, pathName)
+		errno := a.PathOpen(testCtx, mod, args.fd, args.dirflags, args.pathPtr, args.pathLen, args.oflags,
+			rightsAll, rightsAll, args.fdflags, args.resultOpenedFd)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		_, fsc := sysFSCtx(testCtx, mod)
+		f, ok := fsc.OpenedFile(expectedFD)
+		require.True(t, ok)
+		require.Equal(t, pathName, f.Path)
+	})
+}
+
+// TestSnapshotPreview1_PathOpen_Mounts ensures paths are relative to the file system of each pre-opened directory.
+func TestSnapshotPreview1_PathOpen_Mounts(t *testing.T) {
+	dataFD, tmpFD := uint32(3), uint32(4) // arbitrary fds after 0, 1, and 2, that are stdin/out/err
+	dataFS := fstest.MapFS{"input.txt": &fstest.MapFile{Data: []byte("data")}}
+	tmpFS := fstest.MapFS{"scratch.txt": &fstest.MapFile{Data: []byte("tmp")}}
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		dataFD: {Path: "/data", FS: dataFS},
+		tmpFD:  {Path: "/tmp", FS: tmpFS},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionPathOpen, importPathOpen, sysCtx)
+	defer mod.Close(testCtx)
+
+	tests := []struct {
+		name, pathName string
+		fd             uint32
+		expectedErrno  Errno
+	}{
+		{name: "/data", fd: dataFD, pathName: "input.txt"},
+		{name: "/tmp", fd: tmpFD, pathName: "scratch.txt"},
+		{name: "/tmp doesn't see /data", fd: tmpFD, pathName: "input.txt", expectedErrno: ErrnoNoent},
+		{name: "can't escape /data", fd: dataFD, pathName: "../tmp/scratch.txt", expectedErrno: ErrnoNotcapable},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			pathPtr, pathLen := writePath(t, mod, 0, tc.pathName)
+			resultOpenedFd := pathLen
+
+			errno := a.PathOpen(testCtx, mod, tc.fd, 0, pathPtr, pathLen, 0, 0, 0, 0, resultOpenedFd)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+			if errno != ErrnoSuccess {
+				return
+			}
+
+			openedFD, ok := mod.Memory().ReadUint32Le(testCtx, resultOpenedFd)
+			require.True(t, ok)
+			_, fsc := sysFSCtx(testCtx, mod)
+			f, ok := fsc.OpenedFile(openedFD)
+			require.True(t, ok)
+			require.Equal(t, tc.pathName, f.Path)
+		})
+	}
+}
+
+// TestSnapshotPreview1_PathOpen_WriteFS ensures oflags and rights open files in a sys.WriteFS like openat in POSIX.
+func TestSnapshotPreview1_PathOpen_WriteFS(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+	rw := uint64(rightFdRead | rightFdWrite)
+
+	tests := []struct {
+		name          string
+		pathName      string
+		dirflags      uint32
+		oflags        uint32
+		rights        uint64
+		expectedErrno Errno
+		expectedData  string // of "file" after writing "!!" to the opened fd
+	}{
+		{name: "read-only", pathName: "file", rights: rightFdRead},
+		{name: "read-write", pathName: "file", rights: rw, expectedData: "!!zero"},
+		{name: "trunc", pathName: "file", oflags: oflagsTrunc, rights: rw, expectedData: "!!"},
+		{name: "trunc read-only", pathName: "file", oflags: oflagsTrunc, rights: rightFdRead, expectedErrno: ErrnoNotcapable},
+		{name: "creat existing", pathName: "file", oflags: oflagsCreat, rights: rw, expectedData: "!!zero"},
+		{name: "creat", pathName: "new", oflags: oflagsCreat, rights: rw},
+		{name: "creat|excl", pathName: "new", oflags: oflagsCreat | oflagsExcl, rights: rw},
+		{name: "creat|excl existing", pathName: "file", oflags: oflagsCreat | oflagsExcl, rights: rw, expectedErrno: ErrnoExist},
+		{name: "not found", pathName: "new", rights: rw, expectedErrno: ErrnoNoent},
+		{name: "directory", pathName: "dir", oflags: oflagsDirectory, rights: rightFdReaddir},
+		{name: "directory trailing slash", pathName: "dir/", rights: rightFdReaddir},
+		{name: "directory not a directory", pathName: "file", oflags: oflagsDirectory, expectedErrno: ErrnoNotdir},
+		{name: "file trailing slash", pathName: "file/", expectedErrno: ErrnoNotdir},
+		{name: "directory with all rights", pathName: "dir", rights: rightsAll},
+		{name: "directory trunc", pathName: "dir", oflags: oflagsTrunc, rights: rw, expectedErrno: ErrnoIsdir},
+		{name: "symlink", pathName: "link", dirflags: lookupflagsSymlinkFollow, rights: rw, expectedData: "!!zero"},
+		{name: "symlink not followed", pathName: "link", rights: rightFdRead, expectedErrno: ErrnoLoop},
+		{name: "symlink creat|excl", pathName: "link", oflags: oflagsCreat | oflagsExcl, rights: rw, expectedErrno: ErrnoExist},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, 0, "")
+			require.NoError(t, wfs.Mkdir("dir", 0o700))
+			require.NoError(t, wfs.Symlink("file", "link"))
+
+			a, mod, _ := instantiateModule(testCtx, t, functionPathOpen, importPathOpen, sysCtx)
+			defer mod.Close(testCtx)
+
+			resultOpenedFd := uint32(0)
+			path, pathLen := writePath(t, mod, 4, tc.pathName)
+			errno := a.PathOpen(testCtx, mod, dirFD, tc.dirflags, path, pathLen, tc.oflags, tc.rights, 0, 0, resultOpenedFd)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+			if errno != ErrnoSuccess {
+				requireFileData(t, wfs, "file", "wazero") // unchanged
+				return
+			}
+
+			fd, ok := mod.Memory().ReadUint32Le(testCtx, resultOpenedFd)
+			require.True(t, ok)
+			_, fsc := sysFSCtx(testCtx, mod)
+			f, ok := fsc.OpenedFile(fd)
+			require.True(t, ok)
+
+			st, err := f.File.Stat()
+			require.NoError(t, err)
+			if st.IsDir() {
+				_, err = f.File.(fs.ReadDirFile).ReadDir(-1) // Directories are opened for reading, even with the right to write.
+				require.NoError(t, err)
+				return
+			} else if tc.rights&rightFdWrite == 0 {
+				return
+			}
+			_, err = f.File.(io.Writer).Write([]byte("!!"))
+			require.NoError(t, err)
+			if tc.expectedData != "" {
+				b, err := fs.ReadFile(wfs, "file")
+				require.NoError(t, err)
+				require.Equal(t, tc.expectedData, string(b))
+			}
+		})
+	}
+}
+
+// TestSnapshotPreview1_PathOpen_InheritingRights ensures files are opened with the rights the directory allows, not
+// the rights requested.
+func TestSnapshotPreview1_PathOpen_InheritingRights(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, 0, "")
+
+	a, mod, _ := instantiateModule(testCtx, t, functionPathOpen, importPathOpen, sysCtx)
+	defer mod.Close(testCtx)
+
+	_, fsc := sysFSCtx(testCtx, mod)
+	dir, ok := fsc.OpenedFile(dirFD)
+	require.True(t, ok)
+	dir.Rights = &internalsys.Rights{Base: rightsAll, Inheriting: rightFdRead}
+
+	resultOpenedFd := uint32(0)
+	path, pathLen := writePath(t, mod, 4, "file")
+
+	t.Run("write", func(t *testing.T) {
+		errno := a.PathOpen(testCtx, mod, dirFD, 0, path, pathLen, 0, rightFdRead|rightFdWrite, 0, 0, resultOpenedFd)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		fd, ok := mod.Memory().ReadUint32Le(testCtx, resultOpenedFd)
+		require.True(t, ok)
+		f, ok := fsc.OpenedFile(fd)
+		require.True(t, ok)
+		require.Equal(t, &internalsys.Rights{Base: rightFdRead}, f.Rights)
+
+		// The file wasn't opened for writing, as the directory doesn't allow it.
+		_, err := f.File.(io.Writer).Write([]byte("!!"))
+		require.Error(t, err)
+		requireFileData(t, wfs, "file", "wazero")
+	})
+
+	t.Run("trunc", func(t *testing.T) {
+		errno := a.PathOpen(testCtx, mod, dirFD, 0, path, pathLen, oflagsTrunc, rightFdRead|rightFdWrite, 0, 0, resultOpenedFd)
+		require.Equal(t, ErrnoNotcapable, errno, ErrnoName(errno))
+		requireFileData(t, wfs, "file", "wazero")
+	})
+}
+
+func TestSnapshotPreview1_PathOpen_Errors(t *testing.T) {
+	validFD := uint32(3) // arbitrary valid fd after 0, 1, and 2, that are stdin/out/err
+	pathName := "wazero"
+	testFS := fstest.MapFS{pathName: &fstest.MapFile{Mode: os.ModeDir}, "file": &fstest.MapFile{}}
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		validFD: {Path: ".", FS: testFS},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionPathOpen, importPathOpen, sysCtx)
+	defer mod.Close(testCtx)
+
+	validPath := uint32(0)    // arbitrary offset
+	validPathLen := uint32(6) // the length of "wazero"
+	mod.Memory().Write(testCtx, validPath, []byte(pathName))
+	filePath, filePathLen := writePath(t, mod, 16, "file") // arbitrary offset after validPath
+
+	tests := []struct {
+		name                                      string
+		fd, path, pathLen, oflags, resultOpenedFd uint32
+		rights                                    uint64
+		expectedErrno                             Errno
+	}{
+		{
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
+			expectedErrno: ErrnoBadf,
+		},
+		{
+			name:          "out-of-memory reading path",
+			fd:            validFD,
+			path:          mod.Memory().Size(testCtx),
+			pathLen:       validPathLen,
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name:          "out-of-memory reading pathLen",
+			fd:            validFD,
+			path:          validPath,
+			pathLen:       mod.Memory().Size(testCtx) + 1, // path is in the valid memory range, but pathLen is out-of-memory for path
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name:          "no such file exists",
+			fd:            validFD,
+			path:          validPath,
+			pathLen:       validPathLen - 1, // this make the path "wazer", which doesn't exit
+			expectedErrno: ErrnoNoent,
+		},
+		{
+			name:           "out-of-memory writing resultOpenedFd",
+			fd:             validFD,
+			path:           validPath,
+			pathLen:        validPathLen,
+			resultOpenedFd: mod.Memory().Size(testCtx), // path and pathLen correctly point to the right path, but where to write the opened FD is outside memory.
+			expectedErrno:  ErrnoFault,
+		},
+		{
+			name:          "undefined oflags",
+			fd:            validFD,
+			path:          validPath,
+			pathLen:       validPathLen,
+			oflags:        oflagsTrunc << 1,
+			expectedErrno: ErrnoInval,
+		},
+		{
+			name:          "oflags=creat|directory",
+			fd:            validFD,
+			path:          validPath,
+			pathLen:       validPathLen,
+			oflags:        oflagsCreat | oflagsDirectory,
+			expectedErrno: ErrnoInval,
+		},
+		{
+			name:          "oflags=directory, but not a directory",
+			fd:            validFD,
+			path:          filePath,
+			pathLen:       filePathLen,
+			oflags:        oflagsDirectory,
+			expectedErrno: ErrnoNotdir,
+		},
+		{
+			name:          "oflags=creat|excl, but exists",
+			fd:            validFD,
+			path:          filePath,
+			pathLen:       filePathLen,
+			oflags:        oflagsCreat | oflagsExcl,
+			expectedErrno: ErrnoExist,
+		},
+		{
+			name:          "oflags=creat, but read-only file system",
+			fd:            validFD,
+			path:          validPath,
+			pathLen:       validPathLen - 1, // this make the path "wazer", which doesn't exit
+			oflags:        oflagsCreat,
+			expectedErrno: ErrnoRofs,
+		},
+		{
+			name:          "oflags=trunc, but read-only file system",
+			fd:            validFD,
+			path:          filePath,
+			pathLen:       filePathLen,
+			oflags:        oflagsTrunc,
+			rights:        rightFdWrite,
+			expectedErrno: ErrnoRofs,
+		},
+		{
+			name:          "write rights, but read-only file system",
+			fd:            validFD,
+			path:          filePath,
+			pathLen:       filePathLen,
+			rights:        rightFdWrite,
+			expectedErrno: ErrnoRofs,
+		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			errno := a.PathOpen(testCtx, mod, tc.fd, 0, tc.path, tc.pathLen, tc.oflags, tc.rights, 0, 0, tc.resultOpenedFd)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+		})
+	}
+}
+
+// TestSnapshotPreview1_PathReadlink only tests it is stubbed for GrainLang per #271
+func TestSnapshotPreview1_PathReadlink(t *testing.T) {
+	a, mod, fn := instantiateModule(testCtx, t, functionPathReadlink, importPathReadlink, nil)
+	defer mod.Close(testCtx)
+
+	t.Run("snapshotPreview1.PathLink", func(t *testing.T) {
+		errno := a.PathReadlink(testCtx, mod, 0, 0, 0, 0, 0, 0)
+		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	})
+
+	t.Run(functionPathReadlink, func(t *testing.T) {
+		results, err := fn.Call(testCtx, 0, 0, 0, 0, 0, 0)
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	})
+}
+
+func TestSnapshotPreview1_PathRemoveDirectory(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero", "full/file": "wazero"}, 0, "")
+	require.NoError(t, wfs.Mkdir("dir1", 0o700))
+	require.NoError(t, wfs.Mkdir("dir2", 0o700))
+
+	a, mod, fn := instantiateModule(testCtx, t, functionPathRemoveDirectory, importPathRemoveDirectory, sysCtx)
+	defer mod.Close(testCtx)
+
+	t.Run("snapshotPreview1.PathRemoveDirectory", func(t *testing.T) {
+		path, pathLen := writePath(t, mod, 0, "dir1")
+		errno := a.PathRemoveDirectory(testCtx, mod, dirFD, path, pathLen)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		_, err := fs.Stat(wfs, "dir1")
+		require.True(t, errors.Is(err, fs.ErrNotExist))
+	})
+
+	t.Run(functionPathRemoveDirectory, func(t *testing.T) {
+		path, pathLen := writePath(t, mod, 0, "dir2")
+		results, err := fn.Call(testCtx, uint64(dirFD), uint64(path), uint64(pathLen))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+	})
+
+	for _, tc := range []struct {
+		pathName      string
+		expectedErrno Errno
+	}{
+		{pathName: "missing", expectedErrno: ErrnoNoent},
+		{pathName: "file", expectedErrno: ErrnoNotdir},
+		{pathName: "full", expectedErrno: ErrnoNotempty},
+	} {
+		path, pathLen := writePath(t, mod, 0, tc.pathName)
+		errno := a.PathRemoveDirectory(testCtx, mod, dirFD, path, pathLen)
+		require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+	}
+}
+
+func TestSnapshotPreview1_PathRename(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, 0, "")
+
+	a, mod, fn := instantiateModule(testCtx, t, functionPathRename, importPathRename, sysCtx)
+	defer mod.Close(testCtx)
+
+	filePath, filePathLen := writePath(t, mod, 0, "file")
+	renamedPath, renamedPathLen := writePath(t, mod, 16, "renamed")
+
+	t.Run("snapshotPreview1.PathRename", func(t *testing.T) {
+		errno := a.PathRename(testCtx, mod, dirFD, filePath, filePathLen, dirFD, renamedPath, renamedPathLen)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		b, err := fs.ReadFile(wfs, "renamed")
+		require.NoError(t, err)
+		require.Equal(t, "wazero", string(b))
+	})
+
+	t.Run(functionPathRename, func(t *testing.T) {
+		results, err := fn.Call(testCtx, uint64(dirFD), uint64(renamedPath), uint64(renamedPathLen), uint64(dirFD), uint64(filePath), uint64(filePathLen))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+
+		_, err = fs.Stat(wfs, "file")
+		require.NoError(t, err)
+	})
+
+	t.Run("different file systems", func(t *testing.T) {
+		_, fsc := sysFSCtx(testCtx, mod)
+		otherFD, ok := fsc.OpenFile(&internalsys.FileEntry{Path: "/tmp", FS: internalsys.NewMemFS()})
+		require.True(t, ok)
+		defer fsc.CloseFile(otherFD)
+
+		errno := a.PathRename(testCtx, mod, dirFD, filePath, filePathLen, otherFD, renamedPath, renamedPathLen)
+		require.Equal(t, ErrnoXdev, errno, ErrnoName(errno))
+	})
+}
+
+func TestSnapshotPreview1_PathSymlink(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file": "wazero"}, 0, "")
+
+	a, mod, fn := instantiateModule(testCtx, t, functionPathSymlink, importPathSymlink, sysCtx)
+	defer mod.Close(testCtx)
+
+	oldPath, oldPathLen := writePath(t, mod, 0, "file")
+	newPath, newPathLen := writePath(t, mod, 16, "symlink")
+	otherPath, otherPathLen := writePath(t, mod, 32, "other")
+
+	t.Run("snapshotPreview1.PathSymlink", func(t *testing.T) {
+		errno := a.PathSymlink(testCtx, mod, oldPath, oldPathLen, dirFD, newPath, newPathLen)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		st, err := wfs.Lstat("symlink")
+		require.NoError(t, err)
+		require.Equal(t, fs.ModeSymlink, st.Mode().Type())
+
+		b, err := fs.ReadFile(wfs, "symlink")
+		require.NoError(t, err)
+		require.Equal(t, "wazero", string(b))
+	})
+
+	t.Run(functionPathSymlink, func(t *testing.T) {
+		results, err := fn.Call(testCtx, uint64(oldPath), uint64(oldPathLen), uint64(dirFD), uint64(otherPath), uint64(otherPathLen))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+	})
+
+	t.Run("already exists", func(t *testing.T) {
+		errno := a.PathSymlink(testCtx, mod, oldPath, oldPathLen, dirFD, newPath, newPathLen)
+		require.Equal(t, ErrnoExist, errno, ErrnoName(errno))
+	})
+
+	for _, target := range []string{"/etc/passwd", "../file", "dir/../../file"} {
+		tc := target
+		t.Run("escape "+tc, func(t *testing.T) {
+			targetPath, targetPathLen := writePath(t, mod, 48, tc)
+			escapePath, escapePathLen := writePath(t, mod, 80, "escape")
+			errno := a.PathSymlink(testCtx, mod, targetPath, targetPathLen, dirFD, escapePath, escapePathLen)
+			require.Equal(t, ErrnoPerm, errno, ErrnoName(errno))
+
+			_, err := wfs.Lstat("escape")
+			require.True(t, errors.Is(err, fs.ErrNotExist))
+		})
+	}
+}
+
+func TestSnapshotPreview1_PathUnlinkFile(t *testing.T) {
+	dirFD := uint32(3) // the pre-opened directory
+	wfs, sysCtx := newWriteFSSysContext(t, map[string]string{"file1": "wazero", "file2": "wazero", "dir/file": "wazero"}, 0, "")
+
+	a, mod, fn := instantiateModule(testCtx, t, functionPathUnlinkFile, importPathUnlinkFile, sysCtx)
+	defer mod.Close(testCtx)
+
+	t.Run("snapshotPreview1.PathUnlinkFile", func(t *testing.T) {
+		path, pathLen := writePath(t, mod, 0, "file1")
+		errno := a.PathUnlinkFile(testCtx, mod, dirFD, path, pathLen)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		_, err := fs.Stat(wfs, "file1")
+		require.True(t, errors.Is(err, fs.ErrNotExist))
+	})
+
+	t.Run(functionPathUnlinkFile, func(t *testing.T) {
+		path, pathLen := writePath(t, mod, 0, "file2")
+		results, err := fn.Call(testCtx, uint64(dirFD), uint64(path), uint64(pathLen))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
+	})
+
+	for _, tc := range []struct {
+		pathName      string
+		expectedErrno Errno
+	}{
+		{pathName: "file1", expectedErrno: ErrnoNoent},
+		{pathName: "dir", expectedErrno: ErrnoIsdir},
+	} {
+		path, pathLen := writePath(t, mod, 0, tc.pathName)
+		errno := a.PathUnlinkFile(testCtx, mod, dirFD, path, pathLen)
+		require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+	}
+}
+
+func TestSnapshotPreview1_PollOneoff(t *testing.T) {
+	a, mod, fn := instantiateModule(testCtx, t, functionPollOneoff, importPollOneoff, nil)
+	defer mod.Close(testCtx)
+
+	mem := []byte{
+		1, 0, 0, 0, 0, 0, 0, 0, // userdata
+		eventTypeClock, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // event type and padding
+		clockIDMonotonic, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // clock id and padding
+		0x01, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // timeout (ns)
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // precision (ns)
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // flags (relative) and padding
+		'?', // stopped after encoding
+	}
+
+	expectedMem := []byte{
+		1, 0, 0, 0, 0, 0, 0, 0, // userdata
+		byte(ErrnoSuccess), 0x0, // errno is 16 bit
+		eventTypeClock, 0x0, 0x0, 0x0, 0x0, 0x0, // 1 byte for type enum, and 5 bytes padding
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // nbytes
+		0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, 0x0, // flags and padding
+		1, 0, 0, 0, // nevents
+		'?', // stopped after encoding
+	}
 
-// TestSnapshotPreview1_FdReaddir only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_FdReaddir(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionFdReaddir, importFdReaddir, nil)
-	defer mod.Close(testCtx)
+	in := uint32(0)    // past in
+	out := uint32(128) // past in
+	nsubscriptions := uint32(1)
+	resultNevents := uint32(512) // past out
 
-	t.Run("snapshotPreview1.FdReaddir", func(t *testing.T) {
-		errno := a.FdReaddir(testCtx, mod, 0, 0, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	t.Run("snapshotPreview1.PollOneoff", func(t *testing.T) {
+		maskMemory(t, testCtx, mod, 1024)
+		mod.Memory().Write(testCtx, in, mem)
+
+		errno := a.PollOneoff(testCtx, mod, in, out, nsubscriptions, resultNevents)
+		require.Zero(t, errno, ErrnoName(errno))
+
+		outMem, ok := mod.Memory().Read(testCtx, out, uint32(len(expectedMem)-5))
+		require.True(t, ok)
+		require.Equal(t, expectedMem[:len(expectedMem)-5], outMem)
+
+		nevents, ok := mod.Memory().Read(testCtx, resultNevents, 5)
+		require.True(t, ok)
+		require.Equal(t, expectedMem[len(expectedMem)-5:], nevents)
 	})
 
-	t.Run(functionFdReaddir, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0, 0, 0)
+	t.Run(functionPollOneoff, func(t *testing.T) {
+		maskMemory(t, testCtx, mod, 1024)
+		mod.Memory().Write(testCtx, in, mem)
+
+		results, err := fn.Call(testCtx, uint64(in), uint64(out), uint64(nsubscriptions), uint64(resultNevents))
 		require.NoError(t, err)
 		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+		require.Zero(t, errno, ErrnoName(errno))
+
+		nevents, ok := mod.Memory().ReadUint32Le(testCtx, resultNevents)
+		require.True(t, ok)
+		require.Equal(t, uint32(1), nevents)
 	})
 }
 
-// TestSnapshotPreview1_FdRenumber only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_FdRenumber(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionFdRenumber, importFdRenumber, nil)
+func TestSnapshotPreview1_PollOneoff_Abstime(t *testing.T) {
+	a, mod, _ := instantiateModule(testCtx, t, functionPollOneoff, importPollOneoff, nil)
 	defer mod.Close(testCtx)
 
-	t.Run("snapshotPreview1.FdRenumber", func(t *testing.T) {
-		errno := a.FdRenumber(testCtx, mod, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
+	in, out, resultNevents := uint32(0), uint32(128), uint32(512)
+	sub := make([]byte, subscriptionLen)
+	sub[0] = 7 // userdata
+	sub[8] = eventTypeClock
+	sub[16] = clockIDRealtime
+	binary.LittleEndian.PutUint64(sub[24:], epochNanos-1) // already in the past of the fake clock
+	binary.LittleEndian.PutUint16(sub[40:], subclockflagsSubscriptionClockAbstime)
+	require.True(t, mod.Memory().Write(testCtx, in, sub))
+
+	errno := a.PollOneoff(testCtx, mod, in, out, 1, resultNevents)
+	require.Zero(t, errno, ErrnoName(errno))
+
+	nevents, ok := mod.Memory().ReadUint32Le(testCtx, resultNevents)
+	require.True(t, ok)
+	require.Equal(t, uint32(1), nevents)
+	userdata, ok := mod.Memory().ReadUint64Le(testCtx, out)
+	require.True(t, ok)
+	require.Equal(t, uint64(7), userdata)
+}
 
-	t.Run(functionFdRenumber, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+func TestSnapshotPreview1_PollOneoff_ConfiguredClock(t *testing.T) {
+	a, mod, _ := instantiateModule(testCtx, t, functionPollOneoff, importPollOneoff, nil)
+	defer mod.Close(testCtx)
+
+	// A fake clock which only advances when PollOneoff sleeps.
+	clock := epochNanos
+	var slept []int64
+	ctx := experimental.WithTimeNowUnixNano(testCtx, func() uint64 { return clock })
+	ctx = experimental.WithNanosleep(ctx, func(ctx context.Context, ns int64) {
+		slept = append(slept, ns)
+		clock += uint64(ns)
 	})
-}
 
-func TestSnapshotPreview1_FdSeek(t *testing.T) {
-	fd := uint32(3)                                              // arbitrary fd after 0, 1, and 2, that are stdin/out/err
-	resultNewoffset := uint32(1)                                 // arbitrary offset in `ctx.Memory` for the new offset value
-	file, testFS := createFile(t, "test_path", []byte("wazero")) // arbitrary non-empty contents
+	in, out, resultNevents := uint32(0), uint32(256), uint32(512)
+	subs := make([]byte, 3*subscriptionLen)
+	clockSub := func(i int, userdata byte, timeout uint64, flags uint16) {
+		sub := subs[i*subscriptionLen:]
+		sub[0] = userdata
+		sub[8] = eventTypeClock
+		sub[16] = clockIDMonotonic
+		binary.LittleEndian.PutUint64(sub[24:], timeout)
+		binary.LittleEndian.PutUint16(sub[40:], flags)
+	}
+	clockSub(0, 1, 20, 0)                                                // relative, expires after the others
+	clockSub(1, 2, 10, 0)                                                // relative, the nearest
+	clockSub(2, 3, epochNanos+10, subclockflagsSubscriptionClockAbstime) // absolute, expires with the nearest
+	require.True(t, mod.Memory().Write(testCtx, in, subs))
+
+	errno := a.PollOneoff(ctx, mod, in, out, 3, resultNevents)
+	require.Zero(t, errno, ErrnoName(errno))
+	require.Equal(t, []int64{10}, slept)
+
+	nevents, ok := mod.Memory().ReadUint32Le(testCtx, resultNevents)
+	require.True(t, ok)
+	require.Equal(t, uint32(2), nevents)
+	for i, expected := range []uint64{2, 3} {
+		userdata, ok := mod.Memory().ReadUint64Le(testCtx, out+uint32(i)*eventLen)
+		require.True(t, ok)
+		require.Equal(t, expected, userdata)
+	}
+}
 
+func TestSnapshotPreview1_PollOneoff_Fd(t *testing.T) {
+	file, testFS := createFile(t, "test_path", []byte("wazero"))
 	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
-		fd: {Path: "test_path", FS: testFS, File: file},
+		3: {Path: "test_path", FS: testFS, File: file},
 	})
 	require.NoError(t, err)
 
-	fsCtx := sysCtx.FS()
-
-	a, mod, fn := instantiateModule(testCtx, t, functionFdSeek, importFdSeek, sysCtx)
+	a, mod, _ := instantiateModule(testCtx, t, functionPollOneoff, importPollOneoff, sysCtx)
 	defer mod.Close(testCtx)
 
-	// TestSnapshotPreview1_FdSeek uses a matrix because setting up test files is complicated and has to be clean each time.
-	type fdSeekFn func(ctx context.Context, m api.Module, fd uint32, offset uint64, whence, resultNewOffset uint32) Errno
-	seekFns := []struct {
-		name   string
-		fdSeek func() fdSeekFn
+	in, out, resultNevents := uint32(0), uint32(256), uint32(512)
+
+	// A clock subscription with a long timeout, which isn't waited on as the fd subscriptions trigger immediately.
+	subs := make([]byte, 4*subscriptionLen)
+	subs[0] = 1
+	subs[8] = eventTypeClock
+	binary.LittleEndian.PutUint64(subs[24:], uint64(time.Hour))
+	fdSub := func(i int, userdata byte, eventType byte, fd uint32) {
+		sub := subs[i*subscriptionLen:]
+		sub[0] = userdata
+		sub[8] = eventType
+		binary.LittleEndian.PutUint32(sub[16:], fd)
+	}
+	fdSub(1, 2, eventTypeFdRead, fdStdin)
+	fdSub(2, 3, eventTypeFdRead, 3)
+	fdSub(3, 4, eventTypeFdWrite, 42) // invalid fd
+	require.True(t, mod.Memory().Write(testCtx, in, subs))
+
+	errno := a.PollOneoff(testCtx, mod, in, out, 4, resultNevents)
+	require.Zero(t, errno, ErrnoName(errno))
+
+	nevents, ok := mod.Memory().ReadUint32Le(testCtx, resultNevents)
+	require.True(t, ok)
+	require.Equal(t, uint32(3), nevents)
+
+	for i, expected := range []struct {
+		userdata  uint64
+		errno     Errno
+		eventType byte
 	}{
-		{"snapshotPreview1.FdSeek", func() fdSeekFn {
-			return a.FdSeek
-		}},
-		{functionFdSeek, func() fdSeekFn {
-			return func(ctx context.Context, m api.Module, fd uint32, offset uint64, whence, resultNewoffset uint32) Errno {
-				results, err := fn.Call(ctx, uint64(fd), offset, uint64(whence), uint64(resultNewoffset))
-				require.NoError(t, err)
-				return Errno(results[0])
-			}
-		}},
+		{userdata: 2, errno: ErrnoSuccess, eventType: eventTypeFdRead},
+		{userdata: 3, errno: ErrnoSuccess, eventType: eventTypeFdRead},
+		{userdata: 4, errno: ErrnoBadf, eventType: eventTypeFdWrite},
+	} {
+		event, ok := mod.Memory().Read(testCtx, out+uint32(i)*eventLen, eventLen)
+		require.True(t, ok)
+		require.Equal(t, expected.userdata, binary.LittleEndian.Uint64(event))
+		require.Equal(t, expected.errno, Errno(binary.LittleEndian.Uint16(event[8:])))
+		require.Equal(t, expected.eventType, event[10])
 	}
+}
+
+func TestSnapshotPreview1_PollOneoff_Errors(t *testing.T) {
+	a, mod, _ := instantiateModule(testCtx, t, functionPollOneoff, importPollOneoff, nil)
+	defer mod.Close(testCtx)
+
+	clockSub := make([]byte, subscriptionLen)
+	clockSub[8] = eventTypeClock
+	binary.LittleEndian.PutUint64(clockSub[24:], uint64(time.Hour))
+
+	invalidSub := make([]byte, subscriptionLen)
+	invalidSub[8] = 3 // eventtype past eventTypeFdWrite
+
+	canceledCtx, cancel := context.WithCancel(testCtx)
+	cancel()
 
 	tests := []struct {
-		name           string
-		offset         int64
-		whence         int
-		expectedOffset int64
-		expectedMemory []byte
+		name                                   string
+		ctx                                    context.Context
+		mem                                    []byte
+		in, out, nsubscriptions, resultNevents uint32
+		expectedErrno                          Errno
 	}{
 		{
-			name:           "SeekStart",
-			offset:         4, // arbitrary offset
-			whence:         io.SeekStart,
-			expectedOffset: 4, // = offset
-			expectedMemory: []byte{
-				'?',        // resultNewoffset is after this
-				4, 0, 0, 0, // = expectedOffset
-				'?',
-			},
+			name:           "no subscriptions",
+			ctx:            testCtx,
+			out:            128,
+			nsubscriptions: 0,
+			expectedErrno:  ErrnoInval,
 		},
 		{
-			name:           "SeekCurrent",
-			offset:         1, // arbitrary offset
-			whence:         io.SeekCurrent,
-			expectedOffset: 2, // = 1 (the initial offset of the test file) + 1 (offset)
-			expectedMemory: []byte{
-				'?',        // resultNewoffset is after this
-				2, 0, 0, 0, // = expectedOffset
-				'?',
-			},
+			name:           "subscriptions length overflows uint32",
+			ctx:            testCtx,
+			mem:            clockSub,
+			out:            128,
+			nsubscriptions: math.MaxUint32/subscriptionLen + 1, // 48 bytes each wraps to 32
+			resultNevents:  512,
+			expectedErrno:  ErrnoInval,
 		},
 		{
-			name:           "SeekEnd",
-			offset:         -1, // arbitrary offset, note that offset can be negative
-			whence:         io.SeekEnd,
-			expectedOffset: 5, // = 6 (the size of the test file with content "wazero") + -1 (offset)
-			expectedMemory: []byte{
-				'?',        // resultNewoffset is after this
-				5, 0, 0, 0, // = expectedOffset
-				'?',
-			},
+			name:           "max nsubscriptions",
+			ctx:            testCtx,
+			out:            128,
+			nsubscriptions: math.MaxUint32,
+			expectedErrno:  ErrnoInval,
+		},
+		{
+			name:           "in out of range",
+			ctx:            testCtx,
+			in:             mod.Memory().Size(testCtx),
+			nsubscriptions: 1,
+			expectedErrno:  ErrnoFault,
+		},
+		{
+			name:           "out out of range",
+			ctx:            testCtx,
+			out:            mod.Memory().Size(testCtx),
+			nsubscriptions: 1,
+			expectedErrno:  ErrnoFault,
+		},
+		{
+			name:           "invalid eventtype",
+			ctx:            testCtx,
+			mem:            invalidSub,
+			out:            128,
+			nsubscriptions: 1,
+			expectedErrno:  ErrnoInval,
+		},
+		{
+			name:           "resultNevents out of range",
+			ctx:            testCtx,
+			mem:            make([]byte, subscriptionLen), // clock subscription with zero timeout
+			out:            128,
+			nsubscriptions: 1,
+			resultNevents:  mod.Memory().Size(testCtx),
+			expectedErrno:  ErrnoFault,
+		},
+		{
+			name:           "context canceled while waiting",
+			ctx:            canceledCtx,
+			mem:            clockSub,
+			out:            128,
+			nsubscriptions: 1,
+			resultNevents:  512,
+			expectedErrno:  ErrnoCanceled,
 		},
 	}
 
-	for _, seekFn := range seekFns {
-		sf := seekFn
-		t.Run(sf.name, func(t *testing.T) {
-			for _, tt := range tests {
-				tc := tt
-				t.Run(tc.name, func(t *testing.T) {
-					maskMemory(t, testCtx, mod, len(tc.expectedMemory))
-
-					// Since we initialized this file, we know it is a seeker (because it is a MapFile)
-					f, ok := fsCtx.OpenedFile(fd)
-					require.True(t, ok)
-					seeker := f.File.(io.Seeker)
-
-					// set the initial offset of the file to 1
-					offset, err := seeker.Seek(1, io.SeekStart)
-					require.NoError(t, err)
-					require.Equal(t, int64(1), offset)
-
-					errno := sf.fdSeek()(testCtx, mod, fd, uint64(tc.offset), uint32(tc.whence), resultNewoffset)
-					require.Zero(t, errno, ErrnoName(errno))
-
-					actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(tc.expectedMemory)))
-					require.True(t, ok)
-					require.Equal(t, tc.expectedMemory, actual)
-
-					offset, err = seeker.Seek(0, io.SeekCurrent)
-					require.NoError(t, err)
-					require.Equal(t, tc.expectedOffset, offset) // test that the offset of file is actually updated.
-				})
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			maskMemory(t, testCtx, mod, 1024)
+			if tc.mem != nil {
+				require.True(t, mod.Memory().Write(testCtx, tc.in, tc.mem))
 			}
+
+			errno := a.PollOneoff(tc.ctx, mod, tc.in, tc.out, tc.nsubscriptions, tc.resultNevents)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
 		})
 	}
 }
 
-func TestSnapshotPreview1_FdSeek_Errors(t *testing.T) {
-	validFD := uint32(3)                                         // arbitrary valid fd after 0, 1, and 2, that are stdin/out/err
-	file, testFS := createFile(t, "test_path", []byte("wazero")) // arbitrary valid file with non-empty contents
-
-	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
-		validFD: {Path: "test_path", FS: testFS, File: file},
-	})
-	require.NoError(t, err)
-
-	a, mod, _ := instantiateModule(testCtx, t, functionFdSeek, importFdSeek, sysCtx)
-	defer mod.Close(testCtx)
-
-	memorySize := mod.Memory().Size(testCtx)
-
+func TestSnapshotPreview1_ProcExit(t *testing.T) {
 	tests := []struct {
-		name                    string
-		fd                      uint32
-		offset                  uint64
-		whence, resultNewoffset uint32
-		expectedErrno           Errno
+		name     string
+		exitCode uint32
 	}{
 		{
-			name:          "invalid fd",
-			fd:            42, // arbitrary invalid fd
-			expectedErrno: ErrnoBadf,
-		},
-		{
-			name:          "invalid whence",
-			fd:            validFD,
-			whence:        3, // invalid whence, the largest whence io.SeekEnd(2) + 1
-			expectedErrno: ErrnoInval,
+			name:     "success (exitcode 0)",
+			exitCode: 0,
 		},
 		{
-			name:            "out-of-memory writing resultNewoffset",
-			fd:              validFD,
-			resultNewoffset: memorySize,
-			expectedErrno:   ErrnoFault,
+			name:     "arbitrary non-zero exitcode",
+			exitCode: 42,
 		},
 	}
 
 	for _, tt := range tests {
 		tc := tt
+
 		t.Run(tc.name, func(t *testing.T) {
-			errno := a.FdSeek(testCtx, mod, tc.fd, tc.offset, tc.whence, tc.resultNewoffset)
-			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+			// Note: Unlike most tests, this uses fn, not the 'a' result parameter. This is because currently, this function
+			// body panics, and we expect Call to unwrap the panic.
+			_, mod, fn := instantiateModule(testCtx, t, functionProcExit, importProcExit, nil)
+			defer mod.Close(testCtx)
+
+			// When ProcExit is called, store.Callfunction returns immediately, returning the exit code as the error.
+			_, err := fn.Call(testCtx, uint64(tc.exitCode))
+			require.Equal(t, tc.exitCode, err.(*sys.ExitError).ExitCode())
 		})
 	}
-
 }
 
-// TestSnapshotPreview1_FdSync only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_FdSync(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionFdSync, importFdSync, nil)
+// TestSnapshotPreview1_ProcRaise only tests it is stubbed for GrainLang per #271
+func TestSnapshotPreview1_ProcRaise(t *testing.T) {
+	a, mod, fn := instantiateModule(testCtx, t, functionProcRaise, importProcRaise, nil)
 	defer mod.Close(testCtx)
 
-	t.Run("snapshotPreview1.FdSync", func(t *testing.T) {
-		errno := a.FdSync(testCtx, mod, 0)
+	t.Run("snapshotPreview1.ProcRaise", func(t *testing.T) {
+		errno := a.ProcRaise(testCtx, mod, 0)
 		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
 	})
 
-	t.Run(functionFdSync, func(t *testing.T) {
+	t.Run(functionProcRaise, func(t *testing.T) {
 		results, err := fn.Call(testCtx, 0)
 		require.NoError(t, err)
 		errno := Errno(results[0]) // results[0] is the errno
@@ -1319,381 +3954,545 @@ func TestSnapshotPreview1_FdSync(t *testing.T) {
 	})
 }
 
-// TestSnapshotPreview1_FdTell only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_FdTell(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionFdTell, importFdTell, nil)
+// TestSnapshotPreview1_SchedYield only tests it is stubbed for GrainLang per #271
+func TestSnapshotPreview1_SchedYield(t *testing.T) {
+	a, mod, fn := instantiateModule(testCtx, t, functionSchedYield, importSchedYield, nil)
 	defer mod.Close(testCtx)
 
-	t.Run("snapshotPreview1.FdTell", func(t *testing.T) {
-		errno := a.FdTell(testCtx, mod, 0, 0)
+	t.Run("snapshotPreview1.SchedYield", func(t *testing.T) {
+		errno := a.SchedYield(mod)
 		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
 	})
 
-	t.Run(functionFdTell, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0)
+	t.Run(functionSchedYield, func(t *testing.T) {
+		results, err := fn.Call(testCtx)
 		require.NoError(t, err)
 		errno := Errno(results[0]) // results[0] is the errno
 		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
-
-func TestSnapshotPreview1_FdWrite(t *testing.T) {
-	fd := uint32(3)   // arbitrary fd after 0, 1, and 2, that are stdin/out/err
-	iovs := uint32(1) // arbitrary offset
-	initialMemory := []byte{
-		'?',         // `iovs` is after this
-		18, 0, 0, 0, // = iovs[0].offset
-		4, 0, 0, 0, // = iovs[0].length
-		23, 0, 0, 0, // = iovs[1].offset
-		2, 0, 0, 0, // = iovs[1].length
-		'?',                // iovs[0].offset is after this
-		'w', 'a', 'z', 'e', // iovs[0].length bytes
-		'?',      // iovs[1].offset is after this
-		'r', 'o', // iovs[1].length bytes
-		'?',
-	}
-	iovsCount := uint32(2)   // The count of iovs
-	resultSize := uint32(26) // arbitrary offset
-	expectedMemory := append(
-		initialMemory,
-		6, 0, 0, 0, // sum(iovs[...].length) == length of "wazero"
-		'?',
-	)
-
-	// TestSnapshotPreview1_FdWrite uses a matrix because setting up test files is complicated and has to be clean each time.
-	type fdWriteFn func(ctx context.Context, m api.Module, fd, iovs, iovsCount, resultSize uint32) Errno
-	tests := []struct {
-		name    string
-		fdWrite func(*snapshotPreview1, api.Module, api.Function) fdWriteFn
-	}{
-		{"snapshotPreview1.FdWrite", func(a *snapshotPreview1, _ api.Module, _ api.Function) fdWriteFn {
-			return a.FdWrite
-		}},
-		{functionFdWrite, func(_ *snapshotPreview1, mod api.Module, fn api.Function) fdWriteFn {
-			return func(ctx context.Context, m api.Module, fd, iovs, iovsCount, resultSize uint32) Errno {
-				results, err := fn.Call(ctx, uint64(fd), uint64(iovs), uint64(iovsCount), uint64(resultSize))
-				require.NoError(t, err)
-				return Errno(results[0])
-			}
-		}},
+	})
+}
+
+func TestSnapshotPreview1_RandomGet(t *testing.T) {
+	expectedMemory := []byte{
+		'?',                          // `offset` is after this
+		0x53, 0x8c, 0x7f, 0x96, 0xb1, // random data from seed value of 42
+		'?', // stopped after encoding
 	}
 
-	tmpDir := t.TempDir() // open before loop to ensure no locking problems.
+	length := uint32(5) // arbitrary length,
+	offset := uint32(1) // offset,
 
-	for _, tt := range tests {
-		tc := tt
-		t.Run(tc.name, func(t *testing.T) {
-			// Create a fresh file to write the contents to
-			pathName := "test_path"
-			file, testFS := createWriteableFile(t, tmpDir, pathName, []byte{})
-			sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
-				fd: {Path: pathName, FS: testFS, File: file},
-			})
-			require.NoError(t, err)
+	t.Run("snapshotPreview1.RandomGet", func(t *testing.T) {
+		source := rand.New(rand.NewSource(seed))
+		sysCtx, err := wasm.NewSysContext(math.MaxUint32, nil, nil, new(bytes.Buffer), nil, nil, source, nil)
+		require.NoError(t, err)
 
-			a, mod, fn := instantiateModule(testCtx, t, functionFdWrite, importFdWrite, sysCtx)
-			defer mod.Close(testCtx)
+		a, mod, _ := instantiateModule(testCtx, t, functionRandomGet, importRandomGet, sysCtx)
+		defer mod.Close(testCtx)
 
-			maskMemory(t, testCtx, mod, len(expectedMemory))
-			ok := mod.Memory().Write(testCtx, 0, initialMemory)
-			require.True(t, ok)
+		maskMemory(t, testCtx, mod, len(expectedMemory))
 
-			errno := tc.fdWrite(a, mod, fn)(testCtx, mod, fd, iovs, iovsCount, resultSize)
-			require.Zero(t, errno, ErrnoName(errno))
+		// Invoke RandomGet directly and check the memory side effects!
+		errno := a.RandomGet(testCtx, mod, offset, length)
+		require.Zero(t, errno, ErrnoName(errno))
 
-			actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
-			require.True(t, ok)
-			require.Equal(t, expectedMemory, actual)
+		actual, ok := mod.Memory().Read(testCtx, 0, offset+length+1)
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+	})
 
-			// Since we initialized this file, we know we can read it by path
-			buf, err := os.ReadFile(path.Join(tmpDir, pathName))
-			require.NoError(t, err)
+	t.Run(functionRandomGet, func(t *testing.T) {
+		source := rand.New(rand.NewSource(seed))
+		sysCtx, err := wasm.NewSysContext(math.MaxUint32, nil, nil, new(bytes.Buffer), nil, nil, source, nil)
+		require.NoError(t, err)
 
-			require.Equal(t, []byte("wazero"), buf) // verify the file was actually written
-		})
-	}
-}
+		_, mod, fn := instantiateModule(testCtx, t, functionRandomGet, importRandomGet, sysCtx)
+		defer mod.Close(testCtx)
 
-func TestSnapshotPreview1_FdWrite_Errors(t *testing.T) {
-	validFD := uint32(3) // arbitrary valid fd after 0, 1, and 2, that are stdin/out/err
+		maskMemory(t, testCtx, mod, len(expectedMemory))
 
-	tmpDir := t.TempDir() // open before loop to ensure no locking problems.
-	pathName := "test_path"
-	file, testFS := createWriteableFile(t, tmpDir, pathName, []byte{})
+		results, err := fn.Call(testCtx, uint64(offset), uint64(length))
+		require.NoError(t, err)
+		errno := Errno(results[0]) // results[0] is the errno
+		require.Zero(t, errno, ErrnoName(errno))
 
-	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
-		validFD: {Path: pathName, FS: testFS, File: file},
+		actual, ok := mod.Memory().Read(testCtx, 0, offset+length+1)
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
 	})
-	require.NoError(t, err)
+}
 
-	a, mod, _ := instantiateModule(testCtx, t, functionFdWrite, importFdWrite, sysCtx)
+func TestSnapshotPreview1_RandomGet_Errors(t *testing.T) {
+	validAddress := uint32(0) // arbitrary valid address
+
+	a, mod, _ := instantiateModule(testCtx, t, functionRandomGet, importRandomGet, nil)
 	defer mod.Close(testCtx)
 
-	// Setup valid test memory
-	iovs, iovsCount := uint32(0), uint32(1)
-	memory := []byte{
-		8, 0, 0, 0, // = iovs[0].offset (where the data "hi" begins)
-		2, 0, 0, 0, // = iovs[0].length (how many bytes are in "hi")
-		'h', 'i', // iovs[0].length bytes
-	}
+	memorySize := mod.Memory().Size(testCtx)
 
 	tests := []struct {
-		name           string
-		fd, resultSize uint32
-		memory         []byte
-		expectedErrno  Errno
+		name   string
+		offset uint32
+		length uint32
 	}{
 		{
-			name:          "invalid fd",
-			fd:            42, // arbitrary invalid fd
-			expectedErrno: ErrnoBadf,
-		},
-		{
-			name:          "out-of-memory reading iovs[0].offset",
-			fd:            validFD,
-			memory:        []byte{},
-			expectedErrno: ErrnoFault,
-		},
-		{
-			name:          "out-of-memory reading iovs[0].length",
-			fd:            validFD,
-			memory:        memory[0:4], // iovs[0].offset was 4 bytes and iovs[0].length next, but not enough mod.Memory()!
-			expectedErrno: ErrnoFault,
+			name:   "out-of-memory",
+			offset: memorySize,
+			length: 1,
 		},
+
 		{
-			name:          "iovs[0].offset is outside memory",
-			fd:            validFD,
-			memory:        memory[0:8], // iovs[0].offset (where to read "hi") is outside memory.
-			expectedErrno: ErrnoFault,
+			name:   "random length exceeds maximum valid address by 1",
+			offset: validAddress,
+			length: memorySize + 1,
 		},
+	}
+
+	for _, tt := range tests {
+		tc := tt
+
+		t.Run(tc.name, func(t *testing.T) {
+			errno := a.RandomGet(testCtx, mod, tc.offset, tc.length)
+			require.Equal(t, ErrnoFault, errno, ErrnoName(errno))
+		})
+	}
+}
+
+func TestSnapshotPreview1_RandomGet_SourceError(t *testing.T) {
+	tests := []struct {
+		name       string
+		randSource io.Reader
+	}{
 		{
-			name:          "length to read exceeds memory by 1",
-			fd:            validFD,
-			memory:        memory[0:9], // iovs[0].offset (where to read "hi") is in memory, but truncated.
-			expectedErrno: ErrnoFault,
+			name:       "error",
+			randSource: iotest.ErrReader(errors.New("RandSource error")),
 		},
 		{
-			name:          "resultSize offset is outside memory",
-			fd:            validFD,
-			memory:        memory,
-			resultSize:    uint32(len(memory)), // read was ok, but there wasn't enough memory to write the result.
-			expectedErrno: ErrnoFault,
+			name:       "incomplete",
+			randSource: bytes.NewReader([]byte{1, 2}),
 		},
 	}
 
 	for _, tt := range tests {
 		tc := tt
 		t.Run(tc.name, func(t *testing.T) {
-			mod.Memory().(*wasm.MemoryInstance).Buffer = tc.memory
+			var errCtx = experimental.WithTimeNowUnixNano(context.Background(), func() uint64 {
+				panic(errors.New("TimeNowUnixNano error"))
+			})
 
-			errno := a.FdWrite(testCtx, mod, tc.fd, iovs, iovsCount, tc.resultSize)
-			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
+			sysCtx, err := wasm.NewSysContext(math.MaxUint32, nil, nil, new(bytes.Buffer), nil, nil, tc.randSource, nil)
+			require.NoError(t, err)
+
+			a, mod, _ := instantiateModule(errCtx, t, functionRandomGet, importRandomGet, sysCtx)
+			defer mod.Close(errCtx)
+
+			errno := a.RandomGet(errCtx, mod, uint32(1), uint32(5)) // arbitrary offset and length
+			require.Equal(t, ErrnoIo, errno, ErrnoName(errno))
 		})
 	}
 }
 
-// TestSnapshotPreview1_PathCreateDirectory only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_PathCreateDirectory(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionPathCreateDirectory, importPathCreateDirectory, nil)
-	defer mod.Close(testCtx)
+func TestSnapshotPreview1_SockAccept(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
 
-	t.Run("snapshotPreview1.PathCreateDirectory", func(t *testing.T) {
-		errno := a.PathCreateDirectory(testCtx, mod, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	lnFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		lnFD: {Path: ln.Addr().String(), File: &internalsys.ListenerFile{Listener: ln}},
 	})
+	require.NoError(t, err)
 
-	t.Run(functionPathCreateDirectory, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0)
+	a, mod, fn := instantiateModule(testCtx, t, functionSockAccept, importSockAccept, sysCtx)
+	defer mod.Close(testCtx) // closes the listener
+
+	resultFd := uint32(1) // arbitrary offset
+
+	// requireAccepted dials the listener, and ensures the guest can read what the client wrote.
+	requireAccepted := func(t *testing.T, expectedFD byte, accept func() Errno) {
+		client, err := net.Dial("tcp", ln.Addr().String())
 		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+		defer client.Close()
+
+		_, err = client.Write([]byte("wazero"))
+		require.NoError(t, err)
+
+		expectedMemory := []byte{
+			'?',                 // resultFd is after this
+			expectedFD, 0, 0, 0, // = the next fd
+			'?',
+		}
+		maskMemory(t, testCtx, mod, len(expectedMemory))
+		errno := accept()
+		require.Zero(t, errno, ErrnoName(errno))
+
+		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+		require.True(t, ok)
+		require.Equal(t, expectedMemory, actual)
+
+		_, fsc := sysFSCtx(testCtx, mod)
+		f, ok := fsc.OpenedFile(uint32(expectedFD))
+		require.True(t, ok)
+		b := make([]byte, 6)
+		_, err = io.ReadFull(f.File, b)
+		require.NoError(t, err)
+		require.Equal(t, "wazero", string(b))
+
+		ok, err = fsc.CloseFile(uint32(expectedFD))
+		require.True(t, ok)
+		require.NoError(t, err)
+	}
+
+	t.Run("snapshotPreview1.SockAccept", func(t *testing.T) {
+		requireAccepted(t, 4, func() Errno {
+			return a.SockAccept(testCtx, mod, lnFD, 0, resultFd)
+		})
+	})
+
+	t.Run(functionSockAccept, func(t *testing.T) {
+		requireAccepted(t, 5, func() Errno { // file descriptors aren't reused
+			results, err := fn.Call(testCtx, uint64(lnFD), 0, uint64(resultFd))
+			require.NoError(t, err)
+			return Errno(results[0]) // results[0] is the errno
+		})
 	})
 }
 
-// TestSnapshotPreview1_PathFilestatGet only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_PathFilestatGet(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionPathFilestatGet, importPathFilestatGet, nil)
-	defer mod.Close(testCtx)
+func TestSnapshotPreview1_SockAccept_Nonblock(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
 
-	t.Run("snapshotPreview1.PathFilestatGet", func(t *testing.T) {
-		errno := a.PathFilestatGet(testCtx, mod, 0, 0, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	lnFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		lnFD: {Path: ln.Addr().String(), File: &internalsys.ListenerFile{Listener: ln}},
 	})
+	require.NoError(t, err)
 
-	t.Run(functionPathFilestatGet, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
+	a, mod, fn := instantiateModule(testCtx, t, functionSockAccept, importSockAccept, sysCtx)
+	defer mod.Close(testCtx) // closes the listener
+
+	client, err := net.Dial("tcp", ln.Addr().String())
+	require.NoError(t, err)
+	defer client.Close()
+
+	resultFd := uint32(1) // arbitrary offset
+	results, err := fn.Call(testCtx, uint64(lnFD), fdflagsNonblock, uint64(resultFd))
+	require.NoError(t, err)
+	errno := Errno(results[0]) // results[0] is the errno
+	require.Zero(t, errno, ErrnoName(errno))
+
+	connFD, ok := mod.Memory().ReadUint32Le(testCtx, resultFd)
+	require.True(t, ok)
+	_, fsc := sysFSCtx(testCtx, mod)
+	f, ok := fsc.OpenedFile(connFD)
+	require.True(t, ok)
+	require.Equal(t, uint16(fdflagsNonblock), f.Fdflags)
+
+	riData, buf := uint32(8), uint32(16) // arbitrary offsets
+	require.True(t, mod.Memory().WriteUint32Le(testCtx, riData, buf))
+	require.True(t, mod.Memory().WriteUint32Le(testCtx, riData+4, 3))
+	resultRoDataLen, resultRoFlags := uint32(24), uint32(28) // arbitrary offsets
+
+	// Nothing was sent, so this returns instead of waiting.
+	errno = a.SockRecv(testCtx, mod, connFD, riData, 1, riflagsRecvWaitall, resultRoDataLen, resultRoFlags)
+	require.Equal(t, ErrnoAgain, errno, ErrnoName(errno))
+
+	// Data is received once it arrives.
+	_, err = client.Write([]byte("waz"))
+	require.NoError(t, err)
+	for errno == ErrnoAgain {
+		time.Sleep(time.Millisecond)
+		errno = a.SockRecv(testCtx, mod, connFD, riData, 1, riflagsRecvWaitall, resultRoDataLen, resultRoFlags)
+	}
+	require.Zero(t, errno, ErrnoName(errno))
+	b, ok := mod.Memory().Read(testCtx, buf, 3)
+	require.True(t, ok)
+	require.Equal(t, "waz", string(b))
 }
 
-// TestSnapshotPreview1_PathFilestatSetTimes only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_PathFilestatSetTimes(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionPathFilestatSetTimes, importPathFilestatSetTimes, nil)
+func TestSnapshotPreview1_SockAccept_Errors(t *testing.T) {
+	lnFD, fileFD := uint32(3), uint32(4) // arbitrary fds after 0, 1, and 2, that are stdin/out/err
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	file, testFS := createFile(t, "test_path", []byte{})
+
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		lnFD:   {Path: ln.Addr().String(), File: &internalsys.ListenerFile{Listener: ln}},
+		fileFD: {Path: "test_path", FS: testFS, File: file},
+	})
+	require.NoError(t, err)
+
+	a, mod, _ := instantiateModule(testCtx, t, functionSockAccept, importSockAccept, sysCtx)
 	defer mod.Close(testCtx)
 
-	t.Run("snapshotPreview1.PathFilestatSetTimes", func(t *testing.T) {
-		errno := a.PathFilestatSetTimes(testCtx, mod, 0, 0, 0, 0, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	t.Run("invalid fd", func(t *testing.T) {
+		errno := a.SockAccept(testCtx, mod, 42, 0, 0) // arbitrary invalid fd
+		require.Equal(t, ErrnoBadf, errno, ErrnoName(errno))
 	})
 
-	t.Run(functionPathFilestatSetTimes, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0, 0, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	t.Run("not a socket", func(t *testing.T) {
+		errno := a.SockAccept(testCtx, mod, fileFD, 0, 0)
+		require.Equal(t, ErrnoNotsock, errno, ErrnoName(errno))
 	})
-}
 
-// TestSnapshotPreview1_PathLink only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_PathLink(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionPathLink, importPathLink, nil)
-	defer mod.Close(testCtx)
+	t.Run("undefined flags", func(t *testing.T) {
+		errno := a.SockAccept(testCtx, mod, lnFD, fdflagsSync<<1, 0)
+		require.Equal(t, ErrnoInval, errno, ErrnoName(errno))
+	})
 
-	t.Run("snapshotPreview1.PathLink", func(t *testing.T) {
-		errno := a.PathLink(testCtx, mod, 0, 0, 0, 0, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	t.Run("flags other than nonblock", func(t *testing.T) {
+		errno := a.SockAccept(testCtx, mod, lnFD, fdflagsAppend, 0)
+		require.Equal(t, ErrnoNotsup, errno, ErrnoName(errno))
 	})
 
-	t.Run(functionPathLink, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0, 0, 0, 0, 0)
+	t.Run("out-of-memory resultFd", func(t *testing.T) {
+		client, err := net.Dial("tcp", ln.Addr().String())
 		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+		defer client.Close()
+
+		errno := a.SockAccept(testCtx, mod, lnFD, 0, mod.Memory().Size(testCtx))
+		require.Equal(t, ErrnoFault, errno, ErrnoName(errno))
+
+		// Ensure the connection isn't left open
+		_, fsc := sysFSCtx(testCtx, mod)
+		_, ok := fsc.OpenedFile(5)
+		require.False(t, ok)
+	})
+
+	t.Run("context canceled while waiting", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(testCtx, 10*time.Millisecond)
+		defer cancel()
+
+		errno := a.SockAccept(ctx, mod, lnFD, 0, 0)
+		require.Equal(t, ErrnoCanceled, errno, ErrnoName(errno))
+
+		// The listener can still accept connections.
+		client, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		defer client.Close()
+		errno = a.SockAccept(testCtx, mod, lnFD, 0, 0)
+		require.Zero(t, errno, ErrnoName(errno))
+	})
+
+	t.Run("closed listener", func(t *testing.T) {
+		require.NoError(t, ln.Close())
+
+		errno := a.SockAccept(testCtx, mod, lnFD, 0, 0)
+		require.Equal(t, ErrnoIo, errno, ErrnoName(errno))
 	})
 }
 
-func TestSnapshotPreview1_PathOpen(t *testing.T) {
-	type pathOpenArgs struct {
-		fd                 uint32
-		dirflags           uint32
-		pathPtr            uint32
-		pathLen            uint32
-		oflags             uint32
-		fsRightsBase       uint64
-		fsRightsInheriting uint64
-		fdflags            uint32
-		resultOpenedFd     uint32
+func TestSnapshotPreview1_SockRecv(t *testing.T) {
+	connFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	riData := uint32(1) // arbitrary offset
+	initialMemory := []byte{
+		'?',         // `riData` is after this
+		18, 0, 0, 0, // = riData[0].offset
+		4, 0, 0, 0, // = riData[0].length
+		23, 0, 0, 0, // = riData[1].offset
+		2, 0, 0, 0, // = riData[1].length
+		'?',
+	}
+	riDataCount := uint32(2)      // The count of riData
+	resultRoDataLen := uint32(26) // arbitrary offset
+	resultRoFlags := uint32(30)   // arbitrary offset
+	expectedMemory := append(
+		initialMemory,
+		'w', 'a', 'z', 'e', // riData[0].length bytes
+		'?',      // riData[1].offset is after this
+		'r', 'o', // riData[1].length bytes
+		'?',        // resultRoDataLen is after this
+		6, 0, 0, 0, // sum(riData[...].length) == length of "wazero"
+		0, 0, // roflags
+		'?',
+	)
+
+	// TestSnapshotPreview1_SockRecv uses a matrix because each test needs a fresh connection.
+	type sockRecvFn func(ctx context.Context, m api.Module, fd, riData, riDataCount, riFlags, resultRoDataLen, resultRoFlags uint32) Errno
+	tests := []struct {
+		name     string
+		sockRecv func(*snapshotPreview1, api.Module, api.Function) sockRecvFn
+	}{
+		{"snapshotPreview1.SockRecv", func(a *snapshotPreview1, _ api.Module, _ api.Function) sockRecvFn {
+			return a.SockRecv
+		}},
+		{functionSockRecv, func(_ *snapshotPreview1, mod api.Module, fn api.Function) sockRecvFn {
+			return func(ctx context.Context, m api.Module, fd, riData, riDataCount, riFlags, resultRoDataLen, resultRoFlags uint32) Errno {
+				results, err := fn.Call(testCtx, uint64(fd), uint64(riData), uint64(riDataCount), uint64(riFlags), uint64(resultRoDataLen), uint64(resultRoFlags))
+				require.NoError(t, err)
+				return Errno(results[0])
+			}
+		}},
 	}
 
-	setup := func(workdirFD uint32, pathName string) (*snapshotPreview1, api.Module, api.Function, pathOpenArgs, []byte, uint32) {
-		// Setup the initial memory to include the path name starting at an offset.
-		initialMemory := append([]byte{'?'}, pathName...)
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			guest, host := net.Pipe()
+			defer host.Close()
 
-		expectedFD := workdirFD + 1
-		expectedMemory := append(
-			initialMemory,
-			'?', // `resultOpenedFd` is after this
-			byte(expectedFD), 0, 0, 0,
-			'?',
-		)
+			sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+				connFD: {Path: "pipe", File: &internalsys.ConnFile{Conn: guest}},
+			})
+			require.NoError(t, err)
 
-		args := pathOpenArgs{
-			fd:                 workdirFD,
-			dirflags:           0,
-			pathPtr:            1,
-			pathLen:            uint32(len(pathName)),
-			oflags:             0,
-			fsRightsBase:       1, // rights are ignored per https://github.com/WebAssembly/WASI/issues/469#issuecomment-1045251844
-			fsRightsInheriting: 2,
-			fdflags:            0,
-			resultOpenedFd:     uint32(len(initialMemory) + 1),
-		}
+			a, mod, fn := instantiateModule(testCtx, t, functionSockRecv, importSockRecv, sysCtx)
+			defer mod.Close(testCtx)
 
-		testFS := fstest.MapFS{pathName: &fstest.MapFile{Mode: os.ModeDir}}
-		sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
-			workdirFD: {Path: ".", FS: testFS},
-		})
-		require.NoError(t, err)
-		a, mod, fn := instantiateModule(testCtx, t, functionPathOpen, importPathOpen, sysCtx)
-		maskMemory(t, testCtx, mod, len(expectedMemory))
-		ok := mod.Memory().Write(testCtx, 0, initialMemory)
-		require.True(t, ok)
-		return a, mod, fn, args, expectedMemory, expectedFD
-	}
+			maskMemory(t, testCtx, mod, len(expectedMemory))
+			require.True(t, mod.Memory().Write(testCtx, 0, initialMemory))
 
-	verify := func(ctx context.Context, errno Errno, mod api.Module, pathName string, expectedMemory []byte, expectedFD uint32) {
-		require.Zero(t, errno, ErrnoName(errno))
+			// net.Pipe is synchronous, so write in the background. riflagsRecvWaitall ensures all data is received.
+			go func() {
+				_, _ = host.Write([]byte("waz"))
+				_, _ = host.Write([]byte("ero"))
+			}()
 
-		actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
-		require.True(t, ok)
-		require.Equal(t, expectedMemory, actual)
+			errno := tc.sockRecv(a, mod, fn)(testCtx, mod, connFD, riData, riDataCount, riflagsRecvWaitall, resultRoDataLen, resultRoFlags)
+			require.Zero(t, errno, ErrnoName(errno))
 
-		// verify the file was actually opened
-		_, fsc := sysFSCtx(ctx, mod)
-		f, ok := fsc.OpenedFile(expectedFD)
-		require.True(t, ok)
-		require.Equal(t, pathName, f.Path)
+			actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+			require.True(t, ok)
+			require.Equal(t, expectedMemory, actual)
+		})
 	}
+}
 
-	t.Run("snapshotPreview1.PathOpen", func(t *testing.T) {
-		workdirFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
-		pathName := "wazero"
+func TestSnapshotPreview1_SockRecv_Partial(t *testing.T) {
+	connFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	guest, host := net.Pipe()
+	defer host.Close()
 
-		a, mod, _, args, expectedMemory, expectedFD := setup(workdirFD, pathName)
-		errno := a.PathOpen(testCtx, mod, args.fd, args.dirflags, args.pathPtr, args.pathLen, args.oflags,
-			args.fsRightsBase, args.fsRightsInheriting, args.fdflags, args.resultOpenedFd)
-		verify(testCtx, errno, mod, pathName, expectedMemory, expectedFD)
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		connFD: {Path: "pipe", File: &internalsys.ConnFile{Conn: guest}},
 	})
+	require.NoError(t, err)
 
-	t.Run(functionPathOpen, func(t *testing.T) {
-		workdirFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
-		pathName := "wazero"
-
-		_, mod, fn, args, expectedMemory, expectedFD := setup(workdirFD, pathName)
-		results, err := fn.Call(testCtx, uint64(args.fd), uint64(args.dirflags), uint64(args.pathPtr), uint64(args.pathLen),
-			uint64(args.oflags), args.fsRightsBase, args.fsRightsInheriting, uint64(args.fdflags), uint64(args.resultOpenedFd))
-		require.NoError(t, err)
-		errno := Errno(results[0])
-		verify(testCtx, errno, mod, pathName, expectedMemory, expectedFD)
-	})
+	a, mod, _ := instantiateModule(testCtx, t, functionSockRecv, importSockRecv, sysCtx)
+	defer mod.Close(testCtx)
 
-	t.Run("snapshotPreview1.PathOpen.WithFS", func(t *testing.T) {
-		workdirFD := uint32(100) // dummy fd as it is not used
-		pathName := "wazero"
+	riData, buf := uint32(0), uint32(8) // arbitrary offsets
+	require.True(t, mod.Memory().WriteUint32Le(testCtx, riData, buf))
+	require.True(t, mod.Memory().WriteUint32Le(testCtx, riData+4, 10)) // larger than what's sent
+	resultRoDataLen, resultRoFlags := uint32(20), uint32(24)           // arbitrary offsets
+
+	go func() {
+		_, _ = host.Write([]byte("waz"))
+	}()
+
+	// Without riflagsRecvWaitall, this returns what was sent so far.
+	errno := a.SockRecv(testCtx, mod, connFD, riData, 1, 0, resultRoDataLen, resultRoFlags)
+	require.Zero(t, errno, ErrnoName(errno))
+
+	nread, ok := mod.Memory().ReadUint32Le(testCtx, resultRoDataLen)
+	require.True(t, ok)
+	require.Equal(t, uint32(3), nread)
+	b, ok := mod.Memory().Read(testCtx, buf, nread)
+	require.True(t, ok)
+	require.Equal(t, "waz", string(b))
+
+	// Zero bytes are received after the peer closes the connection.
+	require.NoError(t, host.Close())
+	errno = a.SockRecv(testCtx, mod, connFD, riData, 1, 0, resultRoDataLen, resultRoFlags)
+	require.Zero(t, errno, ErrnoName(errno))
+
+	nread, ok = mod.Memory().ReadUint32Le(testCtx, resultRoDataLen)
+	require.True(t, ok)
+	require.Zero(t, nread)
+}
 
-		// The filesystem initialized in setup() is not used as it will be overridden.
-		a, mod, _, args, expectedMemory, _ := setup(workdirFD, pathName)
+func TestSnapshotPreview1_SockRecv_FirstRead(t *testing.T) {
+	connFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	guest, host := net.Pipe()
+	defer host.Close()
 
-		// Override fs.FS through context
-		workdirFD = uint32(4) // 3 is '/' and 4 is '.'
-		expectedFD := workdirFD + 1
-		expectedMemory[8] = byte(expectedFD) // replace expected memory with expected fd
-		testFS := fstest.MapFS{pathName: &fstest.MapFile{Mode: os.ModeDir}}
-		ctx, closer, err := experimental.WithFS(testCtx, testFS)
-		require.NoError(t, err)
-		defer closer.Close(ctx)
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		connFD: {Path: "pipe", File: &internalsys.ConnFile{Conn: guest}},
+	})
+	require.NoError(t, err)
 
-		errno := a.PathOpen(ctx, mod, workdirFD, args.dirflags, args.pathPtr, args.pathLen, args.oflags,
-			args.fsRightsBase, args.fsRightsInheriting, args.fdflags, args.resultOpenedFd)
-		require.Zero(t, errno, ErrnoName(errno))
+	a, mod, _ := instantiateModule(testCtx, t, functionSockRecv, importSockRecv, sysCtx)
+	defer mod.Close(testCtx)
 
-		verify(ctx, errno, mod, pathName, expectedMemory, expectedFD)
-	})
+	riData := uint32(0) // arbitrary offset
+	mem := []byte{
+		16, 0, 0, 0, // = riData[0].offset
+		3, 0, 0, 0, // = riData[0].length
+		19, 0, 0, 0, // = riData[1].offset
+		10, 0, 0, 0, // = riData[1].length
+	}
+	require.True(t, mod.Memory().Write(testCtx, riData, mem))
+	resultRoDataLen, resultRoFlags := uint32(32), uint32(36) // arbitrary offsets
+
+	go func() {
+		_, _ = host.Write([]byte("wazero"))
+	}()
+
+	// Without riflagsRecvWaitall, this returns after the first read, even though more data was sent.
+	errno := a.SockRecv(testCtx, mod, connFD, riData, 2, 0, resultRoDataLen, resultRoFlags)
+	require.Zero(t, errno, ErrnoName(errno))
+
+	nread, ok := mod.Memory().ReadUint32Le(testCtx, resultRoDataLen)
+	require.True(t, ok)
+	require.Equal(t, uint32(3), nread)
+	b, ok := mod.Memory().Read(testCtx, 16, nread)
+	require.True(t, ok)
+	require.Equal(t, "waz", string(b))
+
+	// The rest is received by the next call.
+	errno = a.SockRecv(testCtx, mod, connFD, riData, 2, 0, resultRoDataLen, resultRoFlags)
+	require.Zero(t, errno, ErrnoName(errno))
+	b, ok = mod.Memory().Read(testCtx, 16, 3)
+	require.True(t, ok)
+	require.Equal(t, "ero", string(b))
 }
 
-func TestSnapshotPreview1_PathOpen_Errors(t *testing.T) {
-	validFD := uint32(3) // arbitrary valid fd after 0, 1, and 2, that are stdin/out/err
-	pathName := "wazero"
-	testFS := fstest.MapFS{pathName: &fstest.MapFile{Mode: os.ModeDir}}
+func TestSnapshotPreview1_SockRecv_Canceled(t *testing.T) {
+	connFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	guest, host := net.Pipe()
+	defer host.Close()
 
 	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
-		validFD: {Path: ".", FS: testFS},
+		connFD: {Path: "pipe", File: &internalsys.ConnFile{Conn: guest}},
 	})
 	require.NoError(t, err)
 
-	a, mod, _ := instantiateModule(testCtx, t, functionPathOpen, importPathOpen, sysCtx)
+	a, mod, _ := instantiateModule(testCtx, t, functionSockRecv, importSockRecv, sysCtx)
 	defer mod.Close(testCtx)
 
-	validPath := uint32(0)    // arbitrary offset
-	validPathLen := uint32(6) // the length of "wazero"
-	mod.Memory().Write(testCtx, validPath, []byte(pathName))
+	riData, buf := uint32(0), uint32(8) // arbitrary offsets
+	require.True(t, mod.Memory().WriteUint32Le(testCtx, riData, buf))
+	require.True(t, mod.Memory().WriteUint32Le(testCtx, riData+4, 3))
+	resultRoDataLen, resultRoFlags := uint32(20), uint32(24) // arbitrary offsets
+
+	ctx, cancel := context.WithTimeout(testCtx, 10*time.Millisecond)
+	defer cancel()
+
+	// Nothing was sent, so this waits until the context is done.
+	errno := a.SockRecv(ctx, mod, connFD, riData, 1, riflagsRecvWaitall, resultRoDataLen, resultRoFlags)
+	require.Equal(t, ErrnoCanceled, errno, ErrnoName(errno))
+
+	// The connection can still receive data.
+	go func() {
+		_, _ = host.Write([]byte("waz"))
+	}()
+	errno = a.SockRecv(testCtx, mod, connFD, riData, 1, riflagsRecvWaitall, resultRoDataLen, resultRoFlags)
+	require.Zero(t, errno, ErrnoName(errno))
+	b, ok := mod.Memory().Read(testCtx, buf, 3)
+	require.True(t, ok)
+	require.Equal(t, "waz", string(b))
+}
+
+func TestSnapshotPreview1_SockRecv_Errors(t *testing.T) {
+	a, mod, connFD, lnFD, fileFD := setupSockErrors(t, functionSockRecv, importSockRecv)
+	defer mod.Close(testCtx)
+
+	memorySize := mod.Memory().Size(testCtx)
 
 	tests := []struct {
-		name                                      string
-		fd, path, pathLen, oflags, resultOpenedFd uint32
-		expectedErrno                             Errno
+		name                             string
+		fd, riData, riDataCount, riFlags uint32
+		resultRoDataLen, resultRoFlags   uint32
+		expectedErrno                    Errno
 	}{
 		{
 			name:          "invalid fd",
@@ -1701,165 +4500,167 @@ func TestSnapshotPreview1_PathOpen_Errors(t *testing.T) {
 			expectedErrno: ErrnoBadf,
 		},
 		{
-			name:          "out-of-memory reading path",
-			fd:            validFD,
-			path:          mod.Memory().Size(testCtx),
-			pathLen:       validPathLen,
-			expectedErrno: ErrnoFault,
+			name:          "not a socket",
+			fd:            fileFD,
+			expectedErrno: ErrnoNotsock,
 		},
 		{
-			name:          "out-of-memory reading pathLen",
-			fd:            validFD,
-			path:          validPath,
-			pathLen:       mod.Memory().Size(testCtx) + 1, // path is in the valid memory range, but pathLen is out-of-memory for path
+			name:          "listening socket",
+			fd:            lnFD,
+			expectedErrno: ErrnoNotconn,
+		},
+		{
+			name:          "riflagsRecvPeek",
+			fd:            connFD,
+			riFlags:       riflagsRecvPeek,
+			expectedErrno: ErrnoNotsup,
+		},
+		{
+			name:          "out-of-memory riData",
+			fd:            connFD,
+			riData:        memorySize,
+			riDataCount:   1,
 			expectedErrno: ErrnoFault,
 		},
 		{
-			name:          "no such file exists",
-			fd:            validFD,
-			path:          validPath,
-			pathLen:       validPathLen - 1, // this make the path "wazer", which doesn't exit
-			expectedErrno: ErrnoNoent,
+			name:            "out-of-memory resultRoDataLen",
+			fd:              connFD,
+			resultRoDataLen: memorySize,
+			expectedErrno:   ErrnoFault,
 		},
 		{
-			name:           "out-of-memory writing resultOpenedFd",
-			fd:             validFD,
-			path:           validPath,
-			pathLen:        validPathLen,
-			resultOpenedFd: mod.Memory().Size(testCtx), // path and pathLen correctly point to the right path, but where to write the opened FD is outside memory.
-			expectedErrno:  ErrnoFault,
+			name:          "out-of-memory resultRoFlags",
+			fd:            connFD,
+			resultRoFlags: memorySize,
+			expectedErrno: ErrnoFault,
 		},
 	}
 
 	for _, tt := range tests {
 		tc := tt
+
 		t.Run(tc.name, func(t *testing.T) {
-			errno := a.PathOpen(testCtx, mod, tc.fd, 0, tc.path, tc.pathLen, tc.oflags, 0, 0, 0, tc.resultOpenedFd)
+			errno := a.SockRecv(testCtx, mod, tc.fd, tc.riData, tc.riDataCount, tc.riFlags, tc.resultRoDataLen, tc.resultRoFlags)
 			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
 		})
 	}
 }
 
-// TestSnapshotPreview1_PathReadlink only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_PathReadlink(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionPathReadlink, importPathReadlink, nil)
-	defer mod.Close(testCtx)
-
-	t.Run("snapshotPreview1.PathLink", func(t *testing.T) {
-		errno := a.PathReadlink(testCtx, mod, 0, 0, 0, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-
-	t.Run(functionPathReadlink, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
-
-// TestSnapshotPreview1_PathRemoveDirectory only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_PathRemoveDirectory(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionPathRemoveDirectory, importPathRemoveDirectory, nil)
-	defer mod.Close(testCtx)
-
-	t.Run("snapshotPreview1.PathRemoveDirectory", func(t *testing.T) {
-		errno := a.PathRemoveDirectory(testCtx, mod, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-
-	t.Run(functionPathRemoveDirectory, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
-
-// TestSnapshotPreview1_PathRename only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_PathRename(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionPathRename, importPathRename, nil)
-	defer mod.Close(testCtx)
-
-	t.Run("snapshotPreview1.PathRename", func(t *testing.T) {
-		errno := a.PathRename(testCtx, mod, 0, 0, 0, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-
-	t.Run(functionPathRename, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
+func TestSnapshotPreview1_SockSend(t *testing.T) {
+	connFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
+	siData := uint32(1) // arbitrary offset
+	initialMemory := []byte{
+		'?',         // `siData` is after this
+		18, 0, 0, 0, // = siData[0].offset
+		4, 0, 0, 0, // = siData[0].length
+		23, 0, 0, 0, // = siData[1].offset
+		2, 0, 0, 0, // = siData[1].length
+		'?',                // siData[0].offset is after this
+		'w', 'a', 'z', 'e', // siData[0].length bytes
+		'?',      // siData[1].offset is after this
+		'r', 'o', // siData[1].length bytes
+		'?',
+	}
+	siDataCount := uint32(2)      // The count of siData
+	resultSoDataLen := uint32(26) // arbitrary offset
+	expectedMemory := append(
+		initialMemory,
+		6, 0, 0, 0, // sum(siData[...].length) == length of "wazero"
+		'?',
+	)
 
-// TestSnapshotPreview1_PathSymlink only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_PathSymlink(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionPathSymlink, importPathSymlink, nil)
-	defer mod.Close(testCtx)
+	// TestSnapshotPreview1_SockSend uses a matrix because each test needs a fresh connection.
+	type sockSendFn func(ctx context.Context, m api.Module, fd, siData, siDataCount, siFlags, resultSoDataLen uint32) Errno
+	tests := []struct {
+		name     string
+		sockSend func(*snapshotPreview1, api.Module, api.Function) sockSendFn
+	}{
+		{"snapshotPreview1.SockSend", func(a *snapshotPreview1, _ api.Module, _ api.Function) sockSendFn {
+			return a.SockSend
+		}},
+		{functionSockSend, func(_ *snapshotPreview1, mod api.Module, fn api.Function) sockSendFn {
+			return func(ctx context.Context, m api.Module, fd, siData, siDataCount, siFlags, resultSoDataLen uint32) Errno {
+				results, err := fn.Call(testCtx, uint64(fd), uint64(siData), uint64(siDataCount), uint64(siFlags), uint64(resultSoDataLen))
+				require.NoError(t, err)
+				return Errno(results[0])
+			}
+		}},
+	}
 
-	t.Run("snapshotPreview1.PathSymlink", func(t *testing.T) {
-		errno := a.PathSymlink(testCtx, mod, 0, 0, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
+	for _, tt := range tests {
+		tc := tt
+		t.Run(tc.name, func(t *testing.T) {
+			guest, host := net.Pipe()
+			defer host.Close()
 
-	t.Run(functionPathSymlink, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
+			sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+				connFD: {Path: "pipe", File: &internalsys.ConnFile{Conn: guest}},
+			})
+			require.NoError(t, err)
 
-// TestSnapshotPreview1_PathUnlinkFile only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_PathUnlinkFile(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionPathUnlinkFile, importPathUnlinkFile, nil)
-	defer mod.Close(testCtx)
+			a, mod, fn := instantiateModule(testCtx, t, functionSockSend, importSockSend, sysCtx)
+			defer mod.Close(testCtx)
 
-	t.Run("snapshotPreview1.PathUnlinkFile", func(t *testing.T) {
-		errno := a.PathUnlinkFile(testCtx, mod, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
+			maskMemory(t, testCtx, mod, len(expectedMemory))
+			require.True(t, mod.Memory().Write(testCtx, 0, initialMemory))
 
-	t.Run(functionPathUnlinkFile, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
+			// net.Pipe is synchronous, so read in the background.
+			received := make(chan []byte)
+			go func() {
+				b := make([]byte, 6)
+				_, _ = io.ReadFull(host, b)
+				received <- b
+			}()
+
+			errno := tc.sockSend(a, mod, fn)(testCtx, mod, connFD, siData, siDataCount, 0, resultSoDataLen)
+			require.Zero(t, errno, ErrnoName(errno))
+			require.Equal(t, "wazero", string(<-received))
+
+			actual, ok := mod.Memory().Read(testCtx, 0, uint32(len(expectedMemory)))
+			require.True(t, ok)
+			require.Equal(t, expectedMemory, actual)
+		})
+	}
 }
 
-// TestSnapshotPreview1_PollOneoff only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_PollOneoff(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionPollOneoff, importPollOneoff, nil)
+func TestSnapshotPreview1_SockSend_Errors(t *testing.T) {
+	a, mod, connFD, lnFD, fileFD := setupSockErrors(t, functionSockSend, importSockSend)
 	defer mod.Close(testCtx)
 
-	t.Run("snapshotPreview1.PollOneoff", func(t *testing.T) {
-		errno := a.PollOneoff(testCtx, mod, 0, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-
-	t.Run(functionPollOneoff, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
+	memorySize := mod.Memory().Size(testCtx)
 
-func TestSnapshotPreview1_ProcExit(t *testing.T) {
 	tests := []struct {
-		name     string
-		exitCode uint32
+		name                                     string
+		fd, siData, siDataCount, resultSoDataLen uint32
+		expectedErrno                            Errno
 	}{
 		{
-			name:     "success (exitcode 0)",
-			exitCode: 0,
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
+			expectedErrno: ErrnoBadf,
 		},
 		{
-			name:     "arbitrary non-zero exitcode",
-			exitCode: 42,
+			name:          "not a socket",
+			fd:            fileFD,
+			expectedErrno: ErrnoNotsock,
+		},
+		{
+			name:          "listening socket",
+			fd:            lnFD,
+			expectedErrno: ErrnoNotconn,
+		},
+		{
+			name:          "out-of-memory siData",
+			fd:            connFD,
+			siData:        memorySize,
+			siDataCount:   1,
+			expectedErrno: ErrnoFault,
+		},
+		{
+			name:            "out-of-memory resultSoDataLen",
+			fd:              connFD,
+			resultSoDataLen: memorySize,
+			expectedErrno:   ErrnoFault,
 		},
 	}
 
@@ -1867,226 +4668,149 @@ func TestSnapshotPreview1_ProcExit(t *testing.T) {
 		tc := tt
 
 		t.Run(tc.name, func(t *testing.T) {
-			// Note: Unlike most tests, this uses fn, not the 'a' result parameter. This is because currently, this function
-			// body panics, and we expect Call to unwrap the panic.
-			_, mod, fn := instantiateModule(testCtx, t, functionProcExit, importProcExit, nil)
-			defer mod.Close(testCtx)
-
-			// When ProcExit is called, store.Callfunction returns immediately, returning the exit code as the error.
-			_, err := fn.Call(testCtx, uint64(tc.exitCode))
-			require.Equal(t, tc.exitCode, err.(*sys.ExitError).ExitCode())
+			errno := a.SockSend(testCtx, mod, tc.fd, tc.siData, tc.siDataCount, 0, tc.resultSoDataLen)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
 		})
 	}
 }
 
-// TestSnapshotPreview1_ProcRaise only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_ProcRaise(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionProcRaise, importProcRaise, nil)
-	defer mod.Close(testCtx)
-
-	t.Run("snapshotPreview1.ProcRaise", func(t *testing.T) {
-		errno := a.ProcRaise(testCtx, mod, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
+func TestSnapshotPreview1_SockShutdown(t *testing.T) {
+	connFD := uint32(3) // arbitrary fd after 0, 1, and 2, that are stdin/out/err
 
-	t.Run(functionProcRaise, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0)
+	// setup returns a guest connection accepted from a loopback listener, and the host side of it.
+	setup := func(t *testing.T) (*snapshotPreview1, api.Module, api.Function, net.Conn) {
+		ln, err := net.Listen("tcp", "127.0.0.1:0")
 		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
-
-// TestSnapshotPreview1_SchedYield only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_SchedYield(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionSchedYield, importSchedYield, nil)
-	defer mod.Close(testCtx)
+		defer ln.Close()
 
-	t.Run("snapshotPreview1.SchedYield", func(t *testing.T) {
-		errno := a.SchedYield(mod)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
+		host, err := net.Dial("tcp", ln.Addr().String())
+		require.NoError(t, err)
+		guest, err := ln.Accept()
+		require.NoError(t, err)
 
-	t.Run(functionSchedYield, func(t *testing.T) {
-		results, err := fn.Call(testCtx)
+		sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+			connFD: {Path: guest.RemoteAddr().String(), File: &internalsys.ConnFile{Conn: guest}},
+		})
 		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
 
-func TestSnapshotPreview1_RandomGet(t *testing.T) {
-	expectedMemory := []byte{
-		'?',                          // `offset` is after this
-		0x53, 0x8c, 0x7f, 0x96, 0xb1, // random data from seed value of 42
-		'?', // stopped after encoding
+		a, mod, fn := instantiateModule(testCtx, t, functionSockShutdown, importSockShutdown, sysCtx)
+		return a, mod, fn, host
 	}
 
-	length := uint32(5) // arbitrary length,
-	offset := uint32(1) // offset,
-
-	t.Run("snapshotPreview1.RandomGet", func(t *testing.T) {
-		source := rand.New(rand.NewSource(seed))
-		sysCtx, err := wasm.NewSysContext(math.MaxUint32, nil, nil, new(bytes.Buffer), nil, nil, source, nil)
-		require.NoError(t, err)
-
-		a, mod, _ := instantiateModule(testCtx, t, functionRandomGet, importRandomGet, sysCtx)
+	t.Run("snapshotPreview1.SockShutdown", func(t *testing.T) {
+		a, mod, _, host := setup(t)
 		defer mod.Close(testCtx)
+		defer host.Close()
 
-		maskMemory(t, testCtx, mod, len(expectedMemory))
-
-		// Invoke RandomGet directly and check the memory side effects!
-		errno := a.RandomGet(testCtx, mod, offset, length)
+		errno := a.SockShutdown(testCtx, mod, connFD, sdflagsWr)
 		require.Zero(t, errno, ErrnoName(errno))
 
-		actual, ok := mod.Memory().Read(testCtx, 0, offset+length+1)
-		require.True(t, ok)
-		require.Equal(t, expectedMemory, actual)
+		// The host reads EOF, as the guest won't send anymore.
+		_, err := host.Read(make([]byte, 1))
+		require.Equal(t, io.EOF, err)
 	})
 
-	t.Run(functionRandomGet, func(t *testing.T) {
-		source := rand.New(rand.NewSource(seed))
-		sysCtx, err := wasm.NewSysContext(math.MaxUint32, nil, nil, new(bytes.Buffer), nil, nil, source, nil)
-		require.NoError(t, err)
-
-		_, mod, fn := instantiateModule(testCtx, t, functionRandomGet, importRandomGet, sysCtx)
+	t.Run(functionSockShutdown, func(t *testing.T) {
+		_, mod, fn, host := setup(t)
 		defer mod.Close(testCtx)
+		defer host.Close()
 
-		maskMemory(t, testCtx, mod, len(expectedMemory))
-
-		results, err := fn.Call(testCtx, uint64(offset), uint64(length))
+		results, err := fn.Call(testCtx, uint64(connFD), sdflagsWr)
 		require.NoError(t, err)
 		errno := Errno(results[0]) // results[0] is the errno
 		require.Zero(t, errno, ErrnoName(errno))
 
-		actual, ok := mod.Memory().Read(testCtx, 0, offset+length+1)
-		require.True(t, ok)
-		require.Equal(t, expectedMemory, actual)
+		_, err = host.Read(make([]byte, 1))
+		require.Equal(t, io.EOF, err)
 	})
-}
 
-func TestSnapshotPreview1_RandomGet_Errors(t *testing.T) {
-	validAddress := uint32(0) // arbitrary valid address
+	t.Run("both", func(t *testing.T) {
+		a, mod, _, host := setup(t)
+		defer mod.Close(testCtx)
+		defer host.Close()
 
-	a, mod, _ := instantiateModule(testCtx, t, functionRandomGet, importRandomGet, nil)
-	defer mod.Close(testCtx)
+		errno := a.SockShutdown(testCtx, mod, connFD, sdflagsRd|sdflagsWr)
+		require.Zero(t, errno, ErrnoName(errno))
 
-	memorySize := mod.Memory().Size(testCtx)
+		_, err := host.Read(make([]byte, 1))
+		require.Equal(t, io.EOF, err)
+	})
+}
+
+func TestSnapshotPreview1_SockShutdown_Errors(t *testing.T) {
+	a, mod, connFD, lnFD, fileFD := setupSockErrors(t, functionSockShutdown, importSockShutdown)
+	defer mod.Close(testCtx)
 
 	tests := []struct {
-		name   string
-		offset uint32
-		length uint32
+		name          string
+		fd, how       uint32
+		expectedErrno Errno
 	}{
 		{
-			name:   "out-of-memory",
-			offset: memorySize,
-			length: 1,
+			name:          "invalid fd",
+			fd:            42, // arbitrary invalid fd
+			how:           sdflagsRd,
+			expectedErrno: ErrnoBadf,
 		},
-
 		{
-			name:   "random length exceeds maximum valid address by 1",
-			offset: validAddress,
-			length: memorySize + 1,
+			name:          "not a socket",
+			fd:            fileFD,
+			how:           sdflagsRd,
+			expectedErrno: ErrnoNotsock,
 		},
-	}
-
-	for _, tt := range tests {
-		tc := tt
-
-		t.Run(tc.name, func(t *testing.T) {
-			errno := a.RandomGet(testCtx, mod, tc.offset, tc.length)
-			require.Equal(t, ErrnoFault, errno, ErrnoName(errno))
-		})
-	}
-}
-
-func TestSnapshotPreview1_RandomGet_SourceError(t *testing.T) {
-	tests := []struct {
-		name       string
-		randSource io.Reader
-	}{
 		{
-			name:       "error",
-			randSource: iotest.ErrReader(errors.New("RandSource error")),
+			name:          "listening socket",
+			fd:            lnFD,
+			how:           sdflagsRd,
+			expectedErrno: ErrnoNotconn,
 		},
 		{
-			name:       "incomplete",
-			randSource: bytes.NewReader([]byte{1, 2}),
+			name:          "no flags",
+			fd:            connFD,
+			expectedErrno: ErrnoInval,
+		},
+		{
+			name:          "undefined flags",
+			fd:            connFD,
+			how:           sdflagsWr << 1,
+			expectedErrno: ErrnoInval,
+		},
+		{
+			name:          "half-close not supported",
+			fd:            connFD, // net.Pipe
+			how:           sdflagsRd,
+			expectedErrno: ErrnoNotsup,
 		},
 	}
 
 	for _, tt := range tests {
 		tc := tt
-		t.Run(tc.name, func(t *testing.T) {
-			var errCtx = experimental.WithTimeNowUnixNano(context.Background(), func() uint64 {
-				panic(errors.New("TimeNowUnixNano error"))
-			})
-
-			sysCtx, err := wasm.NewSysContext(math.MaxUint32, nil, nil, new(bytes.Buffer), nil, nil, tc.randSource, nil)
-			require.NoError(t, err)
-
-			a, mod, _ := instantiateModule(errCtx, t, functionRandomGet, importRandomGet, sysCtx)
-			defer mod.Close(errCtx)
 
-			errno := a.RandomGet(errCtx, mod, uint32(1), uint32(5)) // arbitrary offset and length
-			require.Equal(t, ErrnoIo, errno, ErrnoName(errno))
+		t.Run(tc.name, func(t *testing.T) {
+			errno := a.SockShutdown(testCtx, mod, tc.fd, tc.how)
+			require.Equal(t, tc.expectedErrno, errno, ErrnoName(errno))
 		})
 	}
 }
 
-// TestSnapshotPreview1_SockRecv only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_SockRecv(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionSockRecv, importSockRecv, nil)
-	defer mod.Close(testCtx)
-
-	t.Run("snapshotPreview1.SockRecv", func(t *testing.T) {
-		errno := a.SockRecv(testCtx, mod, 0, 0, 0, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-
-	t.Run(functionSockRecv, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
-
-// TestSnapshotPreview1_SockSend only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_SockSend(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionSockSend, importSockSend, nil)
-	defer mod.Close(testCtx)
-
-	t.Run("snapshotPreview1.SockSend", func(t *testing.T) {
-		errno := a.SockSend(testCtx, mod, 0, 0, 0, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-
-	t.Run(functionSockSend, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0, 0, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
-}
-
-// TestSnapshotPreview1_SockShutdown only tests it is stubbed for GrainLang per #271
-func TestSnapshotPreview1_SockShutdown(t *testing.T) {
-	a, mod, fn := instantiateModule(testCtx, t, functionSockShutdown, importSockShutdown, nil)
-	defer mod.Close(testCtx)
+// setupSockErrors instantiates the function with a net.Pipe connection, a listening socket and a regular file.
+func setupSockErrors(t *testing.T, functionName, importFunction string) (a *snapshotPreview1, mod api.Module, connFD, lnFD, fileFD uint32) {
+	connFD, lnFD, fileFD = 3, 4, 5 // arbitrary fds after 0, 1, and 2, that are stdin/out/err
+	guest, host := net.Pipe()
+	t.Cleanup(func() { _ = host.Close() })
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	file, testFS := createFile(t, "test_path", []byte{})
 
-	t.Run("snapshotPreview1.SockShutdown", func(t *testing.T) {
-		errno := a.SockShutdown(testCtx, mod, 0, 0)
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
+	sysCtx, err := newSysContext(nil, nil, map[uint32]*internalsys.FileEntry{
+		connFD: {Path: "pipe", File: &internalsys.ConnFile{Conn: guest}},
+		lnFD:   {Path: ln.Addr().String(), File: &internalsys.ListenerFile{Listener: ln}},
+		fileFD: {Path: "test_path", FS: testFS, File: file},
 	})
+	require.NoError(t, err)
 
-	t.Run(functionSockShutdown, func(t *testing.T) {
-		results, err := fn.Call(testCtx, 0, 0)
-		require.NoError(t, err)
-		errno := Errno(results[0]) // results[0] is the errno
-		require.Equal(t, ErrnoNosys, errno, ErrnoName(errno))
-	})
+	a, mod, _ = instantiateModule(testCtx, t, functionName, importFunction, sysCtx)
+	return
 }
 
 const testMemoryPageSize = 1
@@ -2154,3 +4878,80 @@ func createWriteableFile(t *testing.T, tmpDir string, pathName string, data []by
 	require.NoError(t, err)
 	return f, os.DirFS(tmpDir)
 }
+
+// newWriteFSSysContext returns a SysContext with a sys.WriteFS pre-opened as fd 3, including the files, which are
+// created along with their parent directories. If openFD is non-zero, the file at openPath is also opened as it.
+func newWriteFSSysContext(t *testing.T, files map[string]string, openFD uint32, openPath string) (internalsys.WriteFS, *wasm.SysContext) {
+	wfs := internalsys.NewMemFS()
+	for name, data := range files {
+		if dir := path.Dir(name); dir != "." {
+			require.NoError(t, wfs.Mkdir(dir, 0o700))
+		}
+		f, err := wfs.OpenFile(name, os.O_RDWR|os.O_CREATE, 0o600)
+		require.NoError(t, err)
+		_, err = f.(io.Writer).Write([]byte(data))
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+	}
+
+	openedFiles := map[uint32]*internalsys.FileEntry{3: {Path: ".", FS: wfs}}
+	if openFD != 0 {
+		f, err := wfs.OpenFile(openPath, os.O_RDWR, 0)
+		require.NoError(t, err)
+		openedFiles[openFD] = &internalsys.FileEntry{Path: openPath, FS: wfs, File: f}
+	}
+
+	sysCtx, err := newSysContext(nil, nil, openedFiles)
+	require.NoError(t, err)
+	return wfs, sysCtx
+}
+
+// writePath writes the path name to memory at the offset, returning the parameters to pass to a WASI function.
+func writePath(t *testing.T, mod api.Module, offset uint32, pathName string) (uint32, uint32) {
+	require.True(t, mod.Memory().Write(testCtx, offset, []byte(pathName)))
+	return offset, uint32(len(pathName))
+}
+
+func requireFileSize(t *testing.T, fsys fs.FS, pathName string, expected int64) {
+	st, err := fs.Stat(fsys, pathName)
+	require.NoError(t, err)
+	require.Equal(t, expected, st.Size())
+}
+
+// requireFilestat ensures the filestat at offset includes the expected ino, filetype and size.
+// requireFdstat ensures the fdstat written at the offset has the given fields.
+func requireFdstat(t *testing.T, mod api.Module, offset uint32, filetype byte, fdflags uint16, base, inheriting uint64) {
+	ft, ok := mod.Memory().ReadByte(testCtx, offset)
+	require.True(t, ok)
+	require.Equal(t, filetype, ft)
+	flags, ok := mod.Memory().ReadUint16Le(testCtx, offset+2)
+	require.True(t, ok)
+	require.Equal(t, fdflags, flags)
+	rightsBase, ok := mod.Memory().ReadUint64Le(testCtx, offset+8)
+	require.True(t, ok)
+	require.Equal(t, base, rightsBase)
+	rightsInheriting, ok := mod.Memory().ReadUint64Le(testCtx, offset+16)
+	require.True(t, ok)
+	require.Equal(t, inheriting, rightsInheriting)
+}
+
+func requireFilestat(t *testing.T, mod api.Module, offset uint32, expectedIno uint64, expectedFiletype byte, expectedSize uint64) {
+	filestat, ok := mod.Memory().Read(testCtx, offset, filestatLen)
+	require.True(t, ok)
+	require.Equal(t, expectedIno, binary.LittleEndian.Uint64(filestat[8:]))
+	require.Equal(t, expectedFiletype, filestat[16])
+	require.Equal(t, uint64(1), binary.LittleEndian.Uint64(filestat[24:]))
+	require.Equal(t, expectedSize, binary.LittleEndian.Uint64(filestat[32:]))
+}
+
+func requireModTime(t *testing.T, fsys fs.FS, pathName string, expectedNanos uint64) {
+	st, err := fs.Stat(fsys, pathName)
+	require.NoError(t, err)
+	require.Equal(t, int64(expectedNanos), st.ModTime().UnixNano())
+}
+
+func requireFileData(t *testing.T, fsys fs.FS, name, expected string) {
+	b, err := fs.ReadFile(fsys, name)
+	require.NoError(t, err)
+	require.Equal(t, expected, string(b))
+}
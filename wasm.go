@@ -127,8 +127,9 @@ func NewRuntimeWithConfig(rConfig RuntimeConfig) Runtime {
 		panic(fmt.Errorf("unsupported wazero.RuntimeConfig implementation: %#v", rConfig))
 	}
 	return &runtime{
-		store:           wasm.NewStore(config.enabledFeatures, config.newEngine(config.enabledFeatures)),
+		store:           wasm.NewStore(config.enabledFeatures, config.newEngine(config.enabledFeatures, config.stackLimits)),
 		enabledFeatures: config.enabledFeatures,
+		fuelCosts:       config.fuelCosts,
 	}
 }
 
@@ -136,6 +137,7 @@ func NewRuntimeWithConfig(rConfig RuntimeConfig) Runtime {
 type runtime struct {
 	store           *wasm.Store
 	enabledFeatures wasm.Features
+	fuelCosts       func(instruction string) uint32
 	compiledModules []*compiledCode
 }
 
@@ -184,6 +186,7 @@ func (r *runtime) CompileModule(ctx context.Context, source []byte, cConfig Comp
 	}
 
 	internal.AssignModuleID(source)
+	internal.FuelCosts = r.fuelCosts
 
 	if err = r.store.Engine.CompileModule(ctx, internal); err != nil {
 		return nil, err
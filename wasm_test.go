@@ -90,11 +90,11 @@ func TestRuntime_CompileModule(t *testing.T) {
 		code := m.(*compiledCode)
 		defer code.Close(testCtx)
 
-		require.Equal(t, &wasm.Memory{
+		require.Equal(t, []*wasm.Memory{{
 			Min: 1,
 			Cap: 2,
 			Max: 3,
-		}, code.module.MemorySection)
+		}}, code.module.MemorySection)
 	})
 
 	t.Run("WithImportReplacements", func(t *testing.T) {
@@ -192,7 +192,7 @@ func TestRuntime_CompileModule_Errors(t *testing.T) {
 		},
 		{
 			name:        "memory has too many pages binary",
-			source:      binary.EncodeModule(&wasm.Module{MemorySection: &wasm.Memory{Min: 2, Cap: 2, Max: 70000, IsMaxEncoded: true}}),
+			source:      binary.EncodeModule(&wasm.Module{MemorySection: []*wasm.Memory{{Min: 2, Cap: 2, Max: 70000, IsMaxEncoded: true}}}),
 			expectedErr: "section memory: max 70000 pages (4 Gi) over limit of 65536 pages (4 Gi)",
 		},
 	}
@@ -212,6 +212,53 @@ func TestRuntime_CompileModule_Errors(t *testing.T) {
 	}
 }
 
+// TestCompiledModule_ImportsExports only covers a couple cases to avoid duplication of internal/wasm/module_test.go
+func TestCompiledModule_ImportsExports(t *testing.T) {
+	r := NewRuntime()
+	defer r.Close(testCtx)
+
+	t.Run("module", func(t *testing.T) {
+		compiled, err := r.CompileModule(testCtx, []byte(`(module
+  (import "env" "add" (func $add (param i32 i32) (result i32)))
+  (memory 1)
+  (export "memory" (memory 0))
+  (export "add" (func $add))
+)`), NewCompileConfig().WithImportRenamer(func(externType api.ExternType, oldModule, oldName string) (string, string) {
+			return "math", oldName
+		}))
+		require.NoError(t, err)
+		defer compiled.Close(testCtx)
+
+		addType := &api.FunctionType{
+			ParamTypes:  []api.ValueType{api.ValueTypeI32, api.ValueTypeI32},
+			ResultTypes: []api.ValueType{api.ValueTypeI32},
+		}
+		require.Equal(t, []api.ImportDefinition{
+			{Module: "math", Name: "add", ExternDefinition: api.ExternDefinition{Type: api.ExternTypeFunc, Func: addType}},
+		}, compiled.Imports())
+		require.Equal(t, []api.ExportDefinition{
+			{Name: "memory", ExternDefinition: api.ExternDefinition{Type: api.ExternTypeMemory, Memory: &api.MemoryType{Min: 1}}},
+			{Name: "add", ExternDefinition: api.ExternDefinition{Type: api.ExternTypeFunc, Func: addType}},
+		}, compiled.Exports())
+	})
+
+	t.Run("host module", func(t *testing.T) {
+		compiled, err := r.NewModuleBuilder("env").
+			ExportFunction("get", func() uint64 { return 0 }).
+			Compile(testCtx, NewCompileConfig())
+		require.NoError(t, err)
+		defer compiled.Close(testCtx)
+
+		require.Equal(t, []api.ImportDefinition{}, compiled.Imports())
+		require.Equal(t, []api.ExportDefinition{
+			{Name: "get", ExternDefinition: api.ExternDefinition{
+				Type: api.ExternTypeFunc,
+				Func: &api.FunctionType{ParamTypes: []api.ValueType{}, ResultTypes: []api.ValueType{api.ValueTypeI64}},
+			}},
+		}, compiled.Exports())
+	})
+}
+
 // TestModule_Memory only covers a couple cases to avoid duplication of internal/wasm/runtime_test.go
 func TestModule_Memory(t *testing.T) {
 	tests := []struct {
@@ -585,7 +632,7 @@ func TestClose(t *testing.T) {
 func TestClose_ClosesCompiledModules(t *testing.T) {
 	engine := &mockEngine{name: "mock", cachedModules: map[*wasm.Module]struct{}{}}
 	conf := *engineLessConfig
-	conf.newEngine = func(_ wasm.Features) wasm.Engine {
+	conf.newEngine = func(wasm.Features, wasm.StackLimits) wasm.Engine {
 		return engine
 	}
 	r := NewRuntimeWithConfig(&conf)